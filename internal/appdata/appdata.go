@@ -0,0 +1,254 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package appdata 将连接配置、保存的查询与应用设置打包为加密归档，用于在机器之间迁移或分享团队配置。
+//
+// 归档格式：魔数 "BXAPPDAT"、格式版本（1 字节）、scrypt 盐（16 字节）、GCM nonce（12 字节），
+// 之后是 AES-256-GCM 加密的 gzip 压缩 JSON；头部作为附加认证数据，任何篡改都会导致解密失败。
+package appdata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/settings"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// FormatVersion 归档格式版本。
+	FormatVersion = 1
+	// MinPassphraseLen 口令最少字符数。
+	MinPassphraseLen = 8
+	// MaxArchiveBytes 导入时读取的归档大小上限。
+	MaxArchiveBytes = 64 << 20
+	// FileExtension 归档文件扩展名。
+	FileExtension = ".boxify"
+)
+
+const (
+	magic     = "BXAPPDAT"
+	saltLen   = 16
+	nonceLen  = 12
+	headerLen = len(magic) + 1 + saltLen + nonceLen
+
+	// scrypt 参数（约 32MB 内存），导出与导入各派生一次密钥
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrInvalidArchive 文件不是应用数据归档或已损坏。
+	ErrInvalidArchive = errors.New("不是有效的应用数据归档")
+	// ErrWrongPassphrase 口令错误或归档被篡改。
+	ErrWrongPassphrase = errors.New("口令错误或归档已损坏")
+)
+
+// ConnectionProfile 是前端保存的一个连接配置。
+type ConnectionProfile struct {
+	ID     string                      `json:"id"`
+	Name   string                      `json:"name"`
+	Group  string                      `json:"group,omitempty"` // 分组
+	Config connection.ConnectionConfig `json:"config"`
+}
+
+// SavedQuery 是前端保存的一个查询。
+type SavedQuery struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ConnectionID string `json:"connectionId,omitempty"` // 关联的连接 ID，对应 ConnectionProfile.ID
+	Database     string `json:"database,omitempty"`
+	SQL          string `json:"sql"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Data 是归档中保存的应用数据。
+type Data struct {
+	Version         int                 `json:"version"` // 归档格式版本
+	ExportedAt      time.Time           `json:"exportedAt"`
+	IncludesSecrets bool                `json:"includesSecrets"` // 连接配置是否包含密码等凭据
+	Connections     []ConnectionProfile `json:"connections"`
+	SavedQueries    []SavedQuery        `json:"savedQueries"`
+	Settings        *settings.Settings  `json:"settings,omitempty"` // 应用设置，未选择导出时为空
+}
+
+// ExportOptions 是导出参数。
+type ExportOptions struct {
+	Connections     []ConnectionProfile `json:"connections"`     // 要导出的连接配置
+	SavedQueries    []SavedQuery        `json:"savedQueries"`    // 要导出的查询
+	IncludeSettings bool                `json:"includeSettings"` // 是否包含应用设置
+	IncludeSecrets  bool                `json:"includeSecrets"`  // 是否包含密码等凭据；分享给团队时应关闭
+	Passphrase      string              `json:"passphrase"`      // 加密口令，导入时需要输入相同口令
+}
+
+// ValidatePassphrase 校验口令长度。
+func ValidatePassphrase(passphrase string) error {
+	if utf8.RuneCountInString(passphrase) < MinPassphraseLen {
+		return fmt.Errorf("口令至少需要 %d 个字符", MinPassphraseLen)
+	}
+	return nil
+}
+
+// Build 按导出参数组装应用数据；不包含凭据时去除密码并清理 DSN 中的凭据。不修改传入的配置。
+func Build(opts ExportOptions, current *settings.Settings, now time.Time) *Data {
+	data := &Data{
+		Version:         FormatVersion,
+		ExportedAt:      now.UTC(),
+		IncludesSecrets: opts.IncludeSecrets,
+		Connections:     make([]ConnectionProfile, 0, len(opts.Connections)),
+		SavedQueries:    append([]SavedQuery{}, opts.SavedQueries...),
+	}
+	for _, p := range opts.Connections {
+		p.Config = *p.Config.Clone()
+		if !opts.IncludeSecrets {
+			StripSecrets(&p.Config)
+		}
+		data.Connections = append(data.Connections, p)
+	}
+	if opts.IncludeSettings && current != nil {
+		s := *current
+		data.Settings = &s
+	}
+	return data
+}
+
+// dsnPasswordParam 匹配 DSN 中的 password=/pwd= 参数。
+var dsnPasswordParam = regexp.MustCompile(`(?i)\b(password|pwd)=[^;&\s]*`)
+
+// StripSecrets 去除连接配置中的数据库、SSH、代理密码与 DSN 凭据，保留用户名、主机与私钥路径。
+func StripSecrets(c *connection.ConnectionConfig) {
+	c.Password = ""
+	if c.SSH != nil {
+		c.SSH.Password = ""
+		if c.SSH.Proxy != nil {
+			c.SSH.Proxy.Password = ""
+		}
+	}
+	if c.Proxy != nil {
+		c.Proxy.Password = ""
+	}
+	c.DSN = redactDSN(c.DSN)
+}
+
+// redactDSN 去除 DSN 中的密码：URL 形式的 user:pass@、MySQL 形式的 user:pass@tcp(...) 与 password= 参数。
+func redactDSN(dsn string) string {
+	if strings.TrimSpace(dsn) == "" {
+		return dsn
+	}
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		u.User = url.User(u.User.Username())
+		dsn = u.String()
+	} else if at := strings.LastIndex(dsn, "@"); at > 0 {
+		if colon := strings.Index(dsn[:at], ":"); colon >= 0 && !strings.Contains(dsn[:at], "/") {
+			dsn = dsn[:colon] + dsn[at:]
+		}
+	}
+	return dsnPasswordParam.ReplaceAllString(dsn, "$1=")
+}
+
+// Encrypt 将应用数据序列化、压缩并用口令加密。
+func Encrypt(data *Data, passphrase string) ([]byte, error) {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(data); err != nil {
+		return nil, fmt.Errorf("序列化应用数据失败：%w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("压缩应用数据失败：%w", err)
+	}
+
+	header := make([]byte, headerLen)
+	copy(header, magic)
+	header[len(magic)] = FormatVersion
+	if _, err := rand.Read(header[len(magic)+1:]); err != nil {
+		return nil, fmt.Errorf("生成随机数失败：%w", err)
+	}
+	salt := header[len(magic)+1 : len(magic)+1+saltLen]
+	nonce := header[len(magic)+1+saltLen:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plain.Bytes(), header), nil
+}
+
+// Decrypt 校验并解密归档，返回应用数据。
+func Decrypt(archive []byte, passphrase string) (*Data, error) {
+	if len(archive) < headerLen || string(archive[:len(magic)]) != magic {
+		return nil, ErrInvalidArchive
+	}
+	if v := archive[len(magic)]; v != FormatVersion {
+		return nil, fmt.Errorf("不支持的归档格式版本 %d，请升级应用后重试", v)
+	}
+	header := archive[:headerLen]
+	salt := header[len(magic)+1 : len(magic)+1+saltLen]
+	nonce := header[len(magic)+1+saltLen:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, nonce, archive[headerLen:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer zr.Close()
+	var data Data
+	if err := json.NewDecoder(io.LimitReader(zr, MaxArchiveBytes)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("解析应用数据失败：%w", err)
+	}
+	if data.Connections == nil {
+		data.Connections = []ConnectionProfile{}
+	}
+	if data.SavedQueries == nil {
+		data.SavedQueries = []SavedQuery{}
+	}
+	return &data, nil
+}
+
+// newAEAD 由口令与盐派生 AES-256-GCM 密钥。
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败：%w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("初始化加密失败：%w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appdata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/settings"
+)
+
+func testOptions() ExportOptions {
+	return ExportOptions{
+		Connections: []ConnectionProfile{
+			{ID: "c1", Name: "prod", Config: connection.ConnectionConfig{
+				Type: connection.ConnectionTypeMySQL, Host: "db.internal", Port: 3306, User: "app", Password: "secret",
+				UseSSH: true, SSH: &connection.SSHConfig{Host: "bastion", User: "ops", Password: "ssh-secret", KeyPath: "~/.ssh/id",
+					Proxy: &connection.ProxyConfig{Type: connection.ProxyTypeHTTP, Host: "hp", Port: 8080, Password: "ssh-proxy-secret"}},
+			}},
+			{ID: "c2", Name: "custom", Config: connection.ConnectionConfig{
+				Type: connection.ConnectionTypeCustom, Driver: "mysql", DSN: "app:p@ss@tcp(db:3306)/shop?password=x&charset=utf8",
+				Proxy: &connection.ProxyConfig{Type: connection.ProxyTypeSOCKS5, Host: "sp", Port: 1080, User: "pu", Password: "proxy-secret"},
+			}},
+		},
+		SavedQueries: []SavedQuery{{ID: "q1", Name: "orders", ConnectionID: "c1", SQL: "select * from orders"}},
+		Passphrase:   "correct horse",
+	}
+}
+
+func TestBuildStripsSecretsWithoutMutatingInput(t *testing.T) {
+	opts := testOptions()
+	current := settings.Defaults()
+	data := Build(opts, &current, time.Now())
+
+	if data.IncludesSecrets || data.Settings != nil {
+		t.Fatalf("未选择时不应包含凭据与设置: %+v", data)
+	}
+	c1 := data.Connections[0].Config
+	if c1.Password != "" || c1.SSH.Password != "" || c1.User != "app" || c1.SSH.KeyPath != "~/.ssh/id" {
+		t.Fatalf("应只去除密码: %+v %+v", c1, c1.SSH)
+	}
+	if got := data.Connections[1].Config.DSN; got != "app@tcp(db:3306)/shop?password=&charset=utf8" {
+		t.Fatalf("DSN 凭据未清理: %q", got)
+	}
+	if p := data.Connections[1].Config.Proxy; p == nil || p.Password != "" || p.User != "pu" {
+		t.Fatalf("代理密码未清理: %+v", p)
+	}
+	if p := c1.SSH.Proxy; p == nil || p.Password != "" {
+		t.Fatalf("SSH 代理密码未清理: %+v", p)
+	}
+	if opts.Connections[0].Config.Password != "secret" || opts.Connections[0].Config.SSH.Password != "ssh-secret" {
+		t.Fatal("Build 不应修改传入的连接配置")
+	}
+	if opts.Connections[1].Config.Proxy.Password != "proxy-secret" || opts.Connections[0].Config.SSH.Proxy.Password != "ssh-proxy-secret" {
+		t.Fatal("Build 不应修改传入的代理配置")
+	}
+
+	opts.IncludeSecrets, opts.IncludeSettings = true, true
+	data = Build(opts, &current, time.Now())
+	if data.Connections[0].Config.Password != "secret" || data.Settings == nil {
+		t.Fatalf("选择包含凭据与设置时应原样保留: %+v", data)
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	cases := map[string]string{
+		"postgres://u:pw@host:5432/db?sslmode=disable": "postgres://u@host:5432/db?sslmode=disable",
+		"Server=h;Database=d;User Id=u;Password=pw;":    "Server=h;Database=d;User Id=u;Password=;",
+		"file:/tmp/a.db":                                "file:/tmp/a.db",
+	}
+	for in, want := range cases {
+		if got := redactDSN(in); got != want {
+			t.Errorf("redactDSN(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	opts := testOptions()
+	opts.IncludeSecrets = true
+	current := settings.Defaults()
+	current.Theme = settings.ThemeDark
+	opts.IncludeSettings = true
+	data := Build(opts, &current, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	archive, err := Encrypt(data, opts.Passphrase)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	got, err := Decrypt(archive, opts.Passphrase)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if len(got.Connections) != 2 || got.Connections[0].Config.SSH.Password != "ssh-secret" || got.SavedQueries[0].SQL != "select * from orders" {
+		t.Fatalf("解密结果不一致: %+v", got)
+	}
+	if got.Settings == nil || got.Settings.Theme != settings.ThemeDark || !got.ExportedAt.Equal(data.ExportedAt) {
+		t.Fatalf("设置或导出时间不一致: %+v", got)
+	}
+
+	if _, err := Decrypt(archive, "wrong passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("错误口令应返回 ErrWrongPassphrase, got %v", err)
+	}
+	tampered := append([]byte(nil), archive...)
+	tampered[len(magic)+2] ^= 0xff
+	if _, err := Decrypt(tampered, opts.Passphrase); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("篡改头部应导致解密失败, got %v", err)
+	}
+	if _, err := Decrypt([]byte("PK\x03\x04"), opts.Passphrase); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("非归档文件应返回 ErrInvalidArchive, got %v", err)
+	}
+	if _, err := Encrypt(data, "short"); err == nil {
+		t.Fatal("过短的口令应被拒绝")
+	}
+}
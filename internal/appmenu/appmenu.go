@@ -0,0 +1,353 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package appmenu 以声明式结构描述原生应用菜单栏与系统托盘菜单：默认定义、平台过滤、
+// 定义校验与按 ID 动态更新菜单项状态。构建 Wails 菜单由 AppMenuService 完成。
+package appmenu
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ItemType 菜单项类型，取值与上下文菜单一致。
+type ItemType string
+
+const (
+	TypeItem      ItemType = "item"      // 普通菜单项
+	TypeCheckbox  ItemType = "checkbox"  // 复选框
+	TypeRadio     ItemType = "radio"     // 单选框
+	TypeSeparator ItemType = "separator" // 分隔符
+	TypeSubmenu   ItemType = "submenu"   // 子菜单
+)
+
+// MaxRecentConnections 托盘菜单中最多显示的最近连接数。
+const MaxRecentConnections = 10
+
+// Item 是一个菜单项。设置 Role 的菜单项由系统提供行为（复制、粘贴、退出等），不发送点击事件。
+type Item struct {
+	ID        string         `json:"id,omitempty"`        // 菜单项 ID，点击事件中回传；分隔符与角色项可为空
+	Type      ItemType       `json:"type"`                // 类型，为空时视为普通菜单项
+	Label     string         `json:"label,omitempty"`     // 标签，角色项为空时使用系统默认标签
+	Role      string         `json:"role,omitempty"`      // 系统角色，见 Roles
+	Shortcut  string         `json:"shortcut,omitempty"`  // 快捷键，如 CmdOrCtrl+Enter
+	Enabled   *bool          `json:"enabled,omitempty"`   // 是否启用，nil 表示启用
+	Checked   bool           `json:"checked,omitempty"`   // 是否选中（checkbox/radio）
+	Platforms []string       `json:"platforms,omitempty"` // 仅在这些平台（darwin/windows/linux）显示，为空表示全部
+	Items     []Item         `json:"items,omitempty"`     // 子菜单项（submenu）
+	Data      map[string]any `json:"data,omitempty"`      // 菜单项数据，点击事件中回传
+}
+
+// IsEnabled 返回菜单项是否启用。
+func (it Item) IsEnabled() bool {
+	return it.Enabled == nil || *it.Enabled
+}
+
+// ItemUpdate 是对单个菜单项的动态更新，字段为 nil 表示不修改。
+type ItemUpdate struct {
+	ID      string  `json:"id"`
+	Enabled *bool   `json:"enabled,omitempty"`
+	Checked *bool   `json:"checked,omitempty"`
+	Label   *string `json:"label,omitempty"`
+}
+
+// RecentConnection 是托盘菜单中的最近连接。
+type RecentConnection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // 数据库类型，显示在名称后
+}
+
+// roles 是可用的系统角色。
+var roles = map[string]application.Role{
+	"appMenu":          application.AppMenu,
+	"editMenu":         application.EditMenu,
+	"viewMenu":         application.ViewMenu,
+	"windowMenu":       application.WindowMenu,
+	"servicesMenu":     application.ServicesMenu,
+	"about":            application.About,
+	"hide":             application.Hide,
+	"hideOthers":       application.HideOthers,
+	"unhide":           application.UnHide,
+	"quit":             application.Quit,
+	"undo":             application.Undo,
+	"redo":             application.Redo,
+	"cut":              application.Cut,
+	"copy":             application.Copy,
+	"paste":            application.Paste,
+	"selectAll":        application.SelectAll,
+	"delete":           application.Delete,
+	"closeWindow":      application.CloseWindow,
+	"minimise":         application.Minimise,
+	"zoom":             application.Zoom,
+	"bringAllToFront":  application.BringAllToFront,
+	"toggleFullscreen": application.ToggleFullscreen,
+	"reload":           application.Reload,
+	"forceReload":      application.ForceReload,
+	"openDevTools":     application.OpenDevTools,
+	"resetZoom":        application.ResetZoom,
+	"zoomIn":           application.ZoomIn,
+	"zoomOut":          application.ZoomOut,
+}
+
+// Role 返回角色名称对应的 Wails 角色。
+func Role(name string) (application.Role, bool) {
+	role, ok := roles[name]
+	return role, ok
+}
+
+// enabled 返回指向 v 的指针，用于默认定义中的 Enabled 字段。
+func enabled(v bool) *bool {
+	return &v
+}
+
+// DefaultMenubar 返回默认菜单栏定义（文件、编辑、查询、终端、窗口、帮助），dev 为 true 时追加开发者菜单项。
+// 依赖上下文的菜单项（如执行查询）默认禁用，由前端按当前焦点动态启用。
+func DefaultMenubar(dev bool) []Item {
+	menus := []Item{
+		{Type: TypeSubmenu, Role: "appMenu", Platforms: []string{"darwin"}},
+		{ID: "file", Type: TypeSubmenu, Label: "文件", Items: []Item{
+			{ID: "file.newQuery", Label: "新建查询", Shortcut: "CmdOrCtrl+N"},
+			{ID: "file.newConnection", Label: "新建连接…", Shortcut: "CmdOrCtrl+Shift+N"},
+			{ID: "file.openSql", Label: "打开 SQL 文件…", Shortcut: "CmdOrCtrl+O"},
+			{ID: "file.save", Label: "保存", Shortcut: "CmdOrCtrl+S", Enabled: enabled(false)},
+			{ID: "file.saveAs", Label: "另存为…", Shortcut: "CmdOrCtrl+Shift+S", Enabled: enabled(false)},
+			{Type: TypeSeparator},
+			{ID: "file.workspaces", Label: "工作区…"},
+			{ID: "file.importData", Label: "导入应用数据…"},
+			{ID: "file.exportData", Label: "导出应用数据…"},
+			{Type: TypeSeparator},
+			{ID: "file.settings", Label: "设置…", Shortcut: "CmdOrCtrl+,"},
+			{Type: TypeSeparator, Platforms: []string{"windows", "linux"}},
+			{Role: "closeWindow"},
+			{Role: "quit", Platforms: []string{"windows", "linux"}},
+		}},
+		{ID: "edit", Type: TypeSubmenu, Label: "编辑", Items: []Item{
+			{Role: "undo"},
+			{Role: "redo"},
+			{Type: TypeSeparator},
+			{Role: "cut"},
+			{Role: "copy"},
+			{Role: "paste"},
+			{Role: "selectAll"},
+			{Type: TypeSeparator},
+			{ID: "edit.find", Label: "查找", Shortcut: "CmdOrCtrl+F", Enabled: enabled(false)},
+			{ID: "edit.replace", Label: "替换", Shortcut: "CmdOrCtrl+Alt+F", Enabled: enabled(false)},
+			{ID: "edit.toggleComment", Label: "切换注释", Shortcut: "CmdOrCtrl+/", Enabled: enabled(false)},
+		}},
+		{ID: "query", Type: TypeSubmenu, Label: "查询", Items: []Item{
+			{ID: "query.run", Label: "执行", Shortcut: "CmdOrCtrl+Enter", Enabled: enabled(false)},
+			{ID: "query.runSelection", Label: "执行选中语句", Shortcut: "CmdOrCtrl+Shift+Enter", Enabled: enabled(false)},
+			{ID: "query.explain", Label: "查看执行计划", Shortcut: "CmdOrCtrl+E", Enabled: enabled(false)},
+			{ID: "query.cancel", Label: "取消执行", Shortcut: "CmdOrCtrl+.", Enabled: enabled(false)},
+			{Type: TypeSeparator},
+			{ID: "query.format", Label: "格式化 SQL", Shortcut: "CmdOrCtrl+Shift+F", Enabled: enabled(false)},
+			{ID: "query.history", Label: "查询历史", Shortcut: "CmdOrCtrl+Y"},
+			{ID: "query.autoCommit", Type: TypeCheckbox, Label: "自动提交", Checked: true, Enabled: enabled(false)},
+		}},
+		{ID: "terminal", Type: TypeSubmenu, Label: "终端", Items: []Item{
+			{ID: "terminal.new", Label: "新建终端", Shortcut: "Ctrl+`"},
+			{ID: "terminal.split", Label: "拆分终端", Enabled: enabled(false)},
+			{ID: "terminal.clear", Label: "清屏", Shortcut: "CmdOrCtrl+K", Enabled: enabled(false)},
+			{Type: TypeSeparator},
+			{ID: "terminal.close", Label: "关闭终端", Enabled: enabled(false)},
+		}},
+		{ID: "window", Type: TypeSubmenu, Label: "窗口", Items: []Item{
+			{Role: "minimise"},
+			{Role: "zoom"},
+			{Role: "toggleFullscreen"},
+			{Type: TypeSeparator},
+			{ID: "window.detach", Label: "在新窗口中打开", Enabled: enabled(false)},
+			{ID: "window.resetLayout", Label: "重置界面布局"},
+			{Type: TypeSeparator, Platforms: []string{"darwin"}},
+			{Role: "bringAllToFront", Platforms: []string{"darwin"}},
+		}},
+		{ID: "help", Type: TypeSubmenu, Label: "帮助", Items: []Item{
+			{ID: "help.docs", Label: "使用文档"},
+			{ID: "help.shortcuts", Label: "快捷键列表"},
+			{Type: TypeSeparator},
+			{ID: "help.reportIssue", Label: "反馈问题…"},
+			{ID: "help.diagnostics", Label: "生成诊断包…"},
+			{ID: "help.checkUpdates", Label: "检查更新…"},
+			{Role: "about", Platforms: []string{"windows", "linux"}},
+		}},
+	}
+	if dev {
+		window := Find(menus, "window")
+		window.Items = append(window.Items,
+			Item{Type: TypeSeparator},
+			Item{Role: "reload"},
+			Item{Role: "openDevTools"},
+		)
+	}
+	return menus
+}
+
+// TrayMenu 返回系统托盘菜单定义：显示主窗口、新建查询、最近连接与退出。
+func TrayMenu(recent []RecentConnection) []Item {
+	recentItems := make([]Item, 0, len(recent))
+	for _, conn := range recent {
+		if len(recentItems) == MaxRecentConnections {
+			break
+		}
+		label := conn.Name
+		if conn.Type != "" {
+			label = fmt.Sprintf("%s（%s）", conn.Name, conn.Type)
+		}
+		recentItems = append(recentItems, Item{
+			ID:    "tray.recent." + conn.ID,
+			Label: label,
+			Data:  map[string]any{"connectionId": conn.ID},
+		})
+	}
+	if len(recentItems) == 0 {
+		recentItems = append(recentItems, Item{ID: "tray.recent.none", Label: "暂无最近连接", Enabled: enabled(false)})
+	}
+	return []Item{
+		{ID: "tray.show", Label: "显示 Boxify"},
+		{Type: TypeSeparator},
+		{ID: "tray.newQuery", Label: "新建查询"},
+		{ID: "tray.recent", Type: TypeSubmenu, Label: "最近连接", Items: recentItems},
+		{Type: TypeSeparator},
+		{ID: "tray.quit", Label: "退出"},
+	}
+}
+
+// ForPlatform 返回只含 goos 平台可见菜单项的副本。
+func ForPlatform(items []Item, goos string) []Item {
+	out := make([]Item, 0, len(items))
+	for _, it := range items {
+		if len(it.Platforms) > 0 && !slices.Contains(it.Platforms, goos) {
+			continue
+		}
+		if len(it.Items) > 0 {
+			it.Items = ForPlatform(it.Items, goos)
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+// Validate 校验菜单定义：顶层只能是子菜单，非分隔符非角色项需要唯一 ID 与标签，角色名称必须有效。
+func Validate(menus []Item) error {
+	if len(menus) == 0 {
+		return errors.New("菜单定义不能为空")
+	}
+	seen := make(map[string]bool)
+	for i, menu := range menus {
+		if menu.Type != TypeSubmenu {
+			return fmt.Errorf("顶层菜单 %d 必须是子菜单", i)
+		}
+		if err := validateItem(menu, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateItem 递归校验单个菜单项，seen 记录已出现的 ID。
+func validateItem(it Item, seen map[string]bool) error {
+	if it.Role != "" {
+		if _, ok := roles[it.Role]; !ok {
+			return fmt.Errorf("未知的菜单角色: %s", it.Role)
+		}
+	}
+	switch it.Type {
+	case TypeSeparator:
+		return nil
+	case "", TypeItem, TypeCheckbox, TypeRadio:
+	case TypeSubmenu:
+		if it.Role == "" && len(it.Items) == 0 {
+			return fmt.Errorf("子菜单 %q 没有菜单项", it.Label)
+		}
+	default:
+		return fmt.Errorf("未知的菜单项类型: %s", it.Type)
+	}
+	if it.Role == "" {
+		if strings.TrimSpace(it.ID) == "" {
+			return fmt.Errorf("菜单项 %q 缺少 ID", it.Label)
+		}
+		if strings.TrimSpace(it.Label) == "" {
+			return fmt.Errorf("菜单项 %s 缺少标签", it.ID)
+		}
+	}
+	if it.ID != "" {
+		if seen[it.ID] {
+			return fmt.Errorf("菜单项 ID 重复: %s", it.ID)
+		}
+		seen[it.ID] = true
+	}
+	for _, child := range it.Items {
+		if err := validateItem(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find 按 ID 查找菜单项，返回指向 menus 内元素的指针，未找到时返回 nil。
+func Find(menus []Item, id string) *Item {
+	for i := range menus {
+		if menus[i].ID == id && id != "" {
+			return &menus[i]
+		}
+		if found := Find(menus[i].Items, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Apply 把更新写入菜单定义，返回未找到的菜单项 ID。
+func Apply(menus []Item, updates []ItemUpdate) []string {
+	var missing []string
+	for _, u := range updates {
+		it := Find(menus, u.ID)
+		if it == nil {
+			missing = append(missing, u.ID)
+			continue
+		}
+		if u.Enabled != nil {
+			it.Enabled = enabled(*u.Enabled)
+		}
+		if u.Checked != nil {
+			it.Checked = *u.Checked
+		}
+		if u.Label != nil {
+			it.Label = *u.Label
+		}
+	}
+	return missing
+}
+
+// Clone 深拷贝菜单定义（不含 Data 的内部结构）。
+func Clone(menus []Item) []Item {
+	if menus == nil {
+		return nil
+	}
+	out := make([]Item, len(menus))
+	for i, it := range menus {
+		if it.Enabled != nil {
+			it.Enabled = enabled(*it.Enabled)
+		}
+		it.Platforms = slices.Clone(it.Platforms)
+		it.Items = Clone(it.Items)
+		out[i] = it
+	}
+	return out
+}
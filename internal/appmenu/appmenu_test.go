@@ -0,0 +1,104 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appmenu
+
+import "testing"
+
+func TestDefaultMenubarValid(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		for _, goos := range []string{"darwin", "windows", "linux"} {
+			if err := Validate(ForPlatform(DefaultMenubar(dev), goos)); err != nil {
+				t.Fatalf("默认菜单栏无效 (dev=%v, %s): %v", dev, goos, err)
+			}
+		}
+	}
+	if err := Validate([]Item{{ID: "tray", Type: TypeSubmenu, Label: "托盘", Items: TrayMenu(nil)}}); err != nil {
+		t.Fatalf("默认托盘菜单无效: %v", err)
+	}
+}
+
+func TestForPlatform(t *testing.T) {
+	mac := ForPlatform(DefaultMenubar(false), "darwin")
+	win := ForPlatform(DefaultMenubar(false), "windows")
+	if mac[0].Role != "appMenu" || win[0].ID != "file" {
+		t.Fatalf("应用菜单只应出现在 macOS: mac=%+v win=%+v", mac[0], win[0])
+	}
+	hasQuit := func(menus []Item) bool {
+		for _, it := range Find(menus, "file").Items {
+			if it.Role == "quit" {
+				return true
+			}
+		}
+		return false
+	}
+	if hasQuit(mac) || !hasQuit(win) {
+		t.Fatal("文件菜单中的退出项只应出现在 Windows/Linux")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string][]Item{
+		"empty":     nil,
+		"top-level": {{ID: "a", Label: "A"}},
+		"no-id":     {{ID: "m", Type: TypeSubmenu, Label: "M", Items: []Item{{Label: "x"}}}},
+		"dup-id":    {{ID: "m", Type: TypeSubmenu, Label: "M", Items: []Item{{ID: "m", Label: "x"}}}},
+		"bad-role":  {{ID: "m", Type: TypeSubmenu, Label: "M", Items: []Item{{Role: "nope"}}}},
+		"bad-type":  {{ID: "m", Type: TypeSubmenu, Label: "M", Items: []Item{{ID: "x", Label: "x", Type: "button"}}}},
+		"empty-sub": {{ID: "m", Type: TypeSubmenu, Label: "M"}},
+	}
+	for name, menus := range cases {
+		if err := Validate(menus); err == nil {
+			t.Errorf("%s: 期望校验失败", name)
+		}
+	}
+}
+
+func TestApplyAndClone(t *testing.T) {
+	menus := DefaultMenubar(false)
+	snapshot := Clone(menus)
+	on, label := true, "执行（运行中）"
+	missing := Apply(menus, []ItemUpdate{
+		{ID: "query.run", Enabled: &on, Label: &label},
+		{ID: "query.autoCommit", Checked: new(bool)},
+		{ID: "nope", Enabled: &on},
+	})
+	if len(missing) != 1 || missing[0] != "nope" {
+		t.Fatalf("Apply() missing = %v", missing)
+	}
+	run := Find(menus, "query.run")
+	if !run.IsEnabled() || run.Label != label || Find(menus, "query.autoCommit").Checked {
+		t.Fatalf("更新未生效: %+v", run)
+	}
+	if Find(snapshot, "query.run").IsEnabled() {
+		t.Fatal("Clone 得到的副本不应受更新影响")
+	}
+}
+
+func TestTrayMenuRecent(t *testing.T) {
+	recent := make([]RecentConnection, MaxRecentConnections+2)
+	for i := range recent {
+		recent[i] = RecentConnection{ID: string(rune('a' + i)), Name: "db", Type: "mysql"}
+	}
+	sub := Find(TrayMenu(recent), "tray.recent")
+	if len(sub.Items) != MaxRecentConnections {
+		t.Fatalf("最近连接数 = %d, want %d", len(sub.Items), MaxRecentConnections)
+	}
+	if sub.Items[0].Label != "db（mysql）" || sub.Items[0].Data["connectionId"] != "a" {
+		t.Fatalf("最近连接菜单项错误: %+v", sub.Items[0])
+	}
+	if empty := Find(TrayMenu(nil), "tray.recent"); len(empty.Items) != 1 || empty.Items[0].IsEnabled() {
+		t.Fatalf("没有最近连接时应显示禁用的占位项: %+v", empty.Items)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 发起写操作的功能
+const (
+	FeatureQuery          = "Query"           // SQL 编辑器执行
+	FeatureApplyChanges   = "ApplyChanges"    // 表格编辑提交
+	FeatureUndoChanges    = "UndoChanges"     // 撤销表格编辑
+	FeatureBulkUpdate     = "BulkUpdate"      // 批量更新/删除
+	FeatureImportData     = "ImportData"      // 文件导入
+	FeatureDDL            = "DDL"             // 表结构设计
+	FeatureCreateDatabase = "CreateDatabase"  // 新建数据库
+	FeatureScheduler      = "Scheduler"       // 定时任务
+	FeatureDataTransfer   = "DataTransfer"    // 跨连接复制
+	FeatureSnapshot       = "SnapshotRestore" // 数据库快照恢复
+	FeatureProcedure      = "CallProcedure"   // 存储过程调用
+	FeatureRetention      = "Retention"       // 数据保留规则清理
+	FeatureDataSync       = "DataSync"        // 跨连接表数据同步
+	FeatureAccounts       = "Accounts"        // 账号创建与授权
+	FeatureKillProcess    = "KillProcess"     // 终止会话或取消语句
+)
+
+// maxSQLLength 单条记录保存的 SQL 最大长度，超出部分截断。
+const maxSQLLength = 64 * 1024
+
+// Entry 是一条写操作审计记录。
+type Entry struct {
+	ID           string    `json:"id"`                   // 记录 ID
+	Time         time.Time `json:"time"`                 // 执行时间
+	Feature      string    `json:"feature"`              // 发起功能
+	Connection   string    `json:"connection"`           // 连接摘要
+	Database     string    `json:"database,omitempty"`   // 数据库
+	Table        string    `json:"table,omitempty"`      // 目标表
+	User         string    `json:"user"`                 // 操作系统用户
+	SQL          string    `json:"sql,omitempty"`        // SQL 文本或操作摘要
+	AffectedRows int64     `json:"affectedRows"`         // 受影响行数，未知时为 -1
+	Success      bool      `json:"success"`              // 是否成功
+	Error        string    `json:"error,omitempty"`      // 失败原因
+	DurationMs   int64     `json:"durationMs,omitempty"` // 耗时（毫秒）
+}
+
+// Filter 是审计记录的查询条件，零值字段不参与过滤。
+type Filter struct {
+	Since      time.Time `json:"since"`      // 起始时间（含）
+	Until      time.Time `json:"until"`      // 截止时间（不含）
+	Feature    string    `json:"feature"`    // 发起功能
+	Connection string    `json:"connection"` // 连接摘要（包含匹配）
+	Keyword    string    `json:"keyword"`    // SQL、表名或错误中的关键字（不区分大小写）
+	FailedOnly bool      `json:"failedOnly"` // 仅失败记录
+	Offset     int       `json:"offset"`     // 跳过条数
+	Limit      int       `json:"limit"`      // 返回条数，<=0 时不限
+}
+
+// Match 判断记录是否满足过滤条件。
+func (f *Filter) Match(e *Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Feature != "" && !strings.EqualFold(f.Feature, e.Feature) {
+		return false
+	}
+	if f.Connection != "" && !strings.Contains(e.Connection, f.Connection) {
+		return false
+	}
+	if f.FailedOnly && e.Success {
+		return false
+	}
+	if kw := strings.ToLower(strings.TrimSpace(f.Keyword)); kw != "" {
+		text := strings.ToLower(e.SQL + "\n" + e.Table + "\n" + e.Error)
+		if !strings.Contains(text, kw) {
+			return false
+		}
+	}
+	return true
+}
+
+// Page 是一页查询结果。
+type Page struct {
+	Entries []*Entry `json:"entries"` // 记录，按时间倒序
+	Total   int      `json:"total"`   // 满足条件的总条数
+}
+
+// Log 是仅追加的本地审计日志，每条记录一行 JSON。
+type Log struct {
+	mu     sync.Mutex
+	path   string       // 日志文件路径
+	user   string       // 当前操作系统用户
+	logger *slog.Logger // 日志记录器
+}
+
+// DefaultPath 返回默认审计日志路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "audit.jsonl")
+	}
+	return filepath.Join(configDir, "Boxify", "audit", "audit.jsonl")
+}
+
+// NewLog 创建审计日志，path 为空时使用默认路径。
+func NewLog(path string, logger *slog.Logger) *Log {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Log{path: path, user: currentUser(), logger: logger.With("module", "audit")}
+}
+
+// Record 追加一条记录；ID、时间与用户为空时自动填充。
+func (l *Log) Record(e Entry) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.User == "" {
+		e.User = l.user
+	}
+	if len(e.SQL) > maxSQLLength {
+		e.SQL = e.SQL[:maxSQLLength] + "…"
+	}
+	line, err := json.Marshal(&e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("创建审计日志目录失败：%w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败：%w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入审计日志失败：%w", err)
+	}
+	return nil
+}
+
+// Query 按条件查询记录，结果按时间倒序分页。
+func (l *Log) Query(f Filter) (*Page, error) {
+	var matched []*Entry
+	err := l.scan(func(e *Entry) {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	page := &Page{Total: len(matched), Entries: []*Entry{}}
+	if f.Offset >= len(matched) {
+		return page, nil
+	}
+	matched = matched[max(f.Offset, 0):]
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	page.Entries = matched
+	return page, nil
+}
+
+// scan 按写入顺序读取全部记录，损坏的行会被跳过并记录日志。
+func (l *Log) scan(fn func(*Entry)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取审计日志失败：%w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*maxSQLLength)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			l.logger.Warn("跳过损坏的审计记录", "line", lineNo, "error", err)
+			continue
+		}
+		fn(&e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取审计日志失败：%w", err)
+	}
+	return nil
+}
+
+// currentUser 返回当前操作系统用户名。
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, key := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := NewLog(path, nil)
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: base, Feature: FeatureQuery, Connection: "root@db1:3306", SQL: "UPDATE t SET a = 1", AffectedRows: 3, Success: true},
+		{Time: base.Add(time.Minute), Feature: FeatureApplyChanges, Connection: "root@db1:3306", Table: "users", SQL: "INSERT 1, UPDATE 0, DELETE 2", AffectedRows: 3, Success: true},
+		{Time: base.Add(2 * time.Minute), Feature: FeatureQuery, Connection: "app@db2:5432", SQL: "DELETE FROM logs", AffectedRows: -1, Error: "permission denied"},
+	}
+	for _, e := range entries {
+		if err := l.Record(e); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+	}
+
+	page, err := l.Query(Filter{})
+	if err != nil || page.Total != 3 {
+		t.Fatalf("查询失败: %v %+v", err, page)
+	}
+	if page.Entries[0].SQL != "DELETE FROM logs" || page.Entries[0].ID == "" || page.Entries[0].User == "" {
+		t.Errorf("应按时间倒序并自动填充 ID 与用户: %+v", page.Entries[0])
+	}
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"功能", Filter{Feature: "query"}, 2},
+		{"连接", Filter{Connection: "db1"}, 2},
+		{"关键字", Filter{Keyword: "USERS"}, 1},
+		{"失败", Filter{FailedOnly: true}, 1},
+		{"时间", Filter{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, 1},
+	}
+	for _, c := range cases {
+		page, err := l.Query(c.filter)
+		if err != nil || page.Total != c.want {
+			t.Errorf("%s: 期望 %d 条，得到 %+v %v", c.name, c.want, page, err)
+		}
+	}
+
+	page, _ = l.Query(Filter{Offset: 1, Limit: 1})
+	if page.Total != 3 || len(page.Entries) != 1 || page.Entries[0].Feature != FeatureApplyChanges {
+		t.Errorf("分页不符: %+v", page)
+	}
+}
+
+func TestQuerySkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := NewLog(path, nil)
+	if err := l.Record(Entry{Feature: FeatureDDL, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	f.WriteString("{broken\n")
+	f.Close()
+	if err := l.Record(Entry{Feature: FeatureDDL, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	page, err := l.Query(Filter{})
+	if err != nil || page.Total != 2 {
+		t.Errorf("损坏行应被跳过: %v %+v", err, page)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	l := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"), nil)
+	l.Record(Entry{Feature: FeatureQuery, SQL: "UPDATE t SET a = 'x,y'", AffectedRows: 1, Success: true})
+	l.Record(Entry{Feature: FeatureDDL, SQL: "DROP TABLE t", Success: true})
+
+	var buf bytes.Buffer
+	n, err := l.Export(&buf, "CSV", Filter{Feature: FeatureQuery, Limit: 0})
+	if err != nil || n != 1 {
+		t.Fatalf("导出失败: %v %d", err, n)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("CSV 解析失败: %v %v", err, records)
+	}
+	if records[1][len(records[1])-1] != "UPDATE t SET a = 'x,y'" {
+		t.Errorf("SQL 列不符: %v", records[1])
+	}
+	if _, err := l.Export(&buf, "xml", Filter{}); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("不支持的格式应报错: %v", err)
+	}
+}
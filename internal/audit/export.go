@@ -0,0 +1,83 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormats 是支持的导出格式。
+var ExportFormats = map[string]bool{"csv": true, "json": true}
+
+// csvHeader 是 CSV 导出的表头。
+var csvHeader = []string{"id", "time", "feature", "user", "connection", "database", "table", "success", "affectedRows", "durationMs", "error", "sql"}
+
+// Export 将满足条件的记录按时间顺序写入 w，返回写入条数；分页参数被忽略。
+func (l *Log) Export(w io.Writer, format string, f Filter) (int, error) {
+	format = strings.ToLower(format)
+	if !ExportFormats[format] {
+		return 0, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	f.Offset, f.Limit = 0, 0
+	var entries []*Entry
+	if err := l.scan(func(e *Entry) {
+		if f.Match(e) {
+			entries = append(entries, e)
+		}
+	}); err != nil {
+		return 0, err
+	}
+
+	if format == "json" {
+		if entries == nil {
+			entries = []*Entry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return len(entries), enc.Encode(entries)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.ID,
+			e.Time.Format(time.RFC3339Nano),
+			e.Feature,
+			e.User,
+			e.Connection,
+			e.Database,
+			e.Table,
+			strconv.FormatBool(e.Success),
+			strconv.FormatInt(e.AffectedRows, 10),
+			strconv.FormatInt(e.DurationMs, 10),
+			e.Error,
+			e.SQL,
+		}
+		if err := cw.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	cw.Flush()
+	return len(entries), cw.Error()
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blobstore 暂存查询结果中的超大二进制值。
+//
+// 超过内联上限的 BLOB 在结果中以 connection.BlobRef 代替，完整内容写入临时目录，
+// 内存中只保留引用信息，前端按句柄按需读取或另存为文件。每次查询结果对应一个 Result，
+// 最近的若干个结果视为当前可见的结果，其句柄不会被淘汰或过期；总占用超过上限时
+// 按写入顺序淘汰更早结果中的值。
+package blobstore
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultInlineLimit 默认内联上限，超过该字节数的二进制值以引用返回。
+	DefaultInlineLimit = 64 << 10
+	// DefaultMaxBytes 默认暂存总字节数上限。
+	DefaultMaxBytes = 256 << 20
+	// DefaultTTL 默认暂存时长，过期的值需重新执行查询获取。
+	DefaultTTL = 30 * time.Minute
+	// DefaultPinnedResults 默认视为当前可见、不参与淘汰的最近结果数。
+	DefaultPinnedResults = 8
+)
+
+// ErrNotFound 句柄不存在、已过期或已被淘汰。
+var ErrNotFound = errors.New("值不存在或已过期，请重新执行查询")
+
+// entry 是一个暂存的二进制值。
+type entry struct {
+	ref     connection.BlobRef
+	path    string // 内容所在的临时文件
+	result  uint64 // 所属结果的序号
+	created time.Time
+}
+
+// Store 按句柄暂存二进制值，可并发使用。
+type Store struct {
+	mu         sync.Mutex
+	maxBytes   int64
+	ttl        time.Duration
+	pinned     uint64 // 不参与淘汰的最近结果数
+	dir        string // 临时目录，首次写入时创建
+	used       int64
+	lastResult uint64                   // 最近一个结果的序号
+	order      *list.List               // 按写入顺序排列，元素为 *entry
+	entries    map[string]*list.Element // 句柄 → order 中的元素
+	now        func() time.Time
+}
+
+// New 创建暂存区，maxBytes 与 ttl 非正数时使用默认值。
+func New(maxBytes int64, ttl time.Duration) *Store {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		pinned:   DefaultPinnedResults,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Result 是一次查询结果的写入端，同一结果中的值一同固定或淘汰。
+type Result struct {
+	store *Store
+	id    uint64
+}
+
+// NewResult 开始一个新的查询结果，使其成为最近的可见结果。
+func (s *Store) NewResult() *Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastResult++
+	return &Result{store: s, id: s.lastResult}
+}
+
+// Put 将 data 写入临时文件并返回其引用。
+// 超过总上限时淘汰不再可见的结果中最早写入的值，可见结果中的值始终保留。
+func (r *Result) Put(data []byte) (connection.BlobRef, error) {
+	s := r.store
+	ref := connection.BlobRef{
+		Blob:     true,
+		Handle:   uuid.New().String(),
+		Size:     int64(len(data)),
+		MimeType: http.DetectContentType(data),
+	}
+
+	dir, err := s.tempDir()
+	if err != nil {
+		return connection.BlobRef{}, err
+	}
+	f, err := os.CreateTemp(dir, "blob-*")
+	if err != nil {
+		return connection.BlobRef{}, err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return connection.BlobRef{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir != dir {
+		// 写入期间暂存区已被清空
+		os.Remove(f.Name())
+		return connection.BlobRef{}, ErrNotFound
+	}
+	now := s.now()
+	s.pruneExpired(now)
+	for el := s.order.Front(); el != nil && s.used+ref.Size > s.maxBytes; {
+		next := el.Next()
+		if !s.isPinned(el.Value.(*entry)) {
+			s.remove(el)
+		}
+		el = next
+	}
+	s.entries[ref.Handle] = s.order.PushBack(&entry{ref: ref, path: f.Name(), result: r.id, created: now})
+	s.used += ref.Size
+	return ref, nil
+}
+
+// Get 返回句柄对应的内容与引用信息。
+func (s *Store) Get(handle string) ([]byte, connection.BlobRef, error) {
+	s.mu.Lock()
+	s.pruneExpired(s.now())
+	el, ok := s.entries[handle]
+	var e entry
+	if ok {
+		e = *el.Value.(*entry)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, connection.BlobRef{}, ErrNotFound
+	}
+	data, err := os.ReadFile(e.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, connection.BlobRef{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, connection.BlobRef{}, err
+	}
+	return data, e.ref, nil
+}
+
+// Len 返回当前暂存的值个数。
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// Clear 丢弃所有暂存的值并删除临时目录。
+func (s *Store) Clear() {
+	s.mu.Lock()
+	dir := s.dir
+	s.dir = ""
+	s.order.Init()
+	s.entries = make(map[string]*list.Element)
+	s.used = 0
+	s.mu.Unlock()
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+}
+
+// tempDir 返回暂存所用的临时目录，不存在时创建。
+func (s *Store) tempDir() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		dir, err := os.MkdirTemp("", "boxify-blobs-*")
+		if err != nil {
+			return "", err
+		}
+		s.dir = dir
+	}
+	return s.dir, nil
+}
+
+// isPinned 判断值是否属于最近的可见结果，调用方需持有锁。
+func (s *Store) isPinned(e *entry) bool {
+	return e.result+s.pinned > s.lastResult
+}
+
+// pruneExpired 丢弃不可见结果中过期的值，调用方需持有锁。
+func (s *Store) pruneExpired(now time.Time) {
+	for el := s.order.Front(); el != nil; {
+		next := el.Next()
+		e := el.Value.(*entry)
+		if now.Sub(e.created) < s.ttl {
+			return
+		}
+		if !s.isPinned(e) {
+			s.remove(el)
+		}
+		el = next
+	}
+}
+
+// remove 丢弃一个值并删除其临时文件，调用方需持有锁。
+func (s *Store) remove(el *list.Element) {
+	e := s.order.Remove(el).(*entry)
+	delete(s.entries, e.ref.Handle)
+	s.used -= e.ref.Size
+	os.Remove(e.path)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// newTestStore 创建测试用暂存区，测试结束时删除临时目录。
+func newTestStore(t *testing.T, maxBytes int64, ttl time.Duration) *Store {
+	s := New(maxBytes, ttl)
+	t.Cleanup(s.Clear)
+	return s
+}
+
+// mustPut 写入 data 并返回引用，失败时终止测试。
+func mustPut(t *testing.T, r *Result, data []byte) connection.BlobRef {
+	t.Helper()
+	ref, err := r.Put(data)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	return ref
+}
+
+func TestPutGet(t *testing.T) {
+	s := newTestStore(t, 0, 0)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...)
+	ref := mustPut(t, s.NewResult(), png)
+	if !ref.Blob || ref.Handle == "" || ref.Size != int64(len(png)) {
+		t.Fatalf("引用不完整: %+v", ref)
+	}
+	if ref.MimeType != "image/png" {
+		t.Errorf("MimeType = %q, want image/png", ref.MimeType)
+	}
+
+	data, got, err := s.Get(ref.Handle)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(data, png) || got != ref {
+		t.Errorf("Get 返回 %+v，与写入不一致", got)
+	}
+	if _, _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("未知句柄应返回 ErrNotFound，得到 %v", err)
+	}
+}
+
+func TestEvictsOlderResultsWhenOverCapacity(t *testing.T) {
+	s := newTestStore(t, 10, 0)
+	s.pinned = 1
+	first := mustPut(t, s.NewResult(), make([]byte, 4))
+	second := mustPut(t, s.NewResult(), make([]byte, 4))
+	current := s.NewResult()
+	third := mustPut(t, current, make([]byte, 4))
+
+	if _, _, err := s.Get(first.Handle); !errors.Is(err, ErrNotFound) {
+		t.Errorf("更早结果中最早写入的值应被淘汰")
+	}
+	for _, ref := range []string{second.Handle, third.Handle} {
+		if _, _, err := s.Get(ref); err != nil {
+			t.Errorf("Get(%s): %v", ref, err)
+		}
+	}
+
+	// 当前结果中的值即使超过总上限也不淘汰
+	fourth := mustPut(t, current, make([]byte, 20))
+	for _, ref := range []string{third.Handle, fourth.Handle} {
+		if _, _, err := s.Get(ref); err != nil {
+			t.Errorf("当前结果的值不应被淘汰，Get(%s): %v", ref, err)
+		}
+	}
+	if _, _, err := s.Get(second.Handle); !errors.Is(err, ErrNotFound) || s.Len() != 2 {
+		t.Errorf("超过上限时应淘汰更早结果的值，Len() = %d, err = %v", s.Len(), err)
+	}
+}
+
+func TestExpiresAfterTTL(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newTestStore(t, 0, time.Minute)
+	s.pinned = 1
+	s.now = func() time.Time { return now }
+
+	ref := mustPut(t, s.NewResult(), []byte{0, 1, 2})
+	now = now.Add(time.Minute)
+	if _, _, err := s.Get(ref.Handle); err != nil {
+		t.Fatalf("可见结果中的值不应过期: %v", err)
+	}
+	s.NewResult()
+	if _, _, err := s.Get(ref.Handle); !errors.Is(err, ErrNotFound) {
+		t.Errorf("不再可见的过期值应返回 ErrNotFound，得到 %v", err)
+	}
+	if s.used != 0 {
+		t.Errorf("过期后占用应归零，得到 %d", s.used)
+	}
+}
+
+func TestClearRemovesTempFiles(t *testing.T) {
+	s := newTestStore(t, 0, 0)
+	mustPut(t, s.NewResult(), []byte("payload"))
+	dir := s.dir
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Fatalf("值应写入临时目录: %v %v", entries, err)
+	}
+	s.Clear()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Clear 后临时目录应被删除: %v", err)
+	}
+}
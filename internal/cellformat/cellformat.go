@@ -0,0 +1,115 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cellformat 识别单元格文本的格式（JSON / XML / 纯文本）并美化输出，供单元格详情查看与导出使用。
+package cellformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// 单元格内容格式
+const (
+	FormatJSON = "json"
+	FormatXML  = "xml"
+	FormatText = "text"
+)
+
+// indent 美化输出使用的缩进
+const indent = "  "
+
+// Result 是格式识别与美化的结果。
+type Result struct {
+	Format  string `json:"format"`  // 识别出的格式，见 Format* 常量
+	Content string `json:"content"` // 美化后的内容；无法解析时为原文
+}
+
+// Pretty 识别 text 的格式并美化：合法 JSON 按两空格缩进，合法 XML 逐元素缩进，其余原样返回。
+func Pretty(text string) Result {
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case trimmed == "":
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(trimmed), "", indent); err == nil {
+			return Result{Format: FormatJSON, Content: buf.String()}
+		}
+	case trimmed[0] == '<':
+		if pretty, err := indentXML(trimmed); err == nil {
+			return Result{Format: FormatXML, Content: pretty}
+		}
+	}
+	return Result{Format: FormatText, Content: text}
+}
+
+// Ext 返回格式对应的文件扩展名。
+func Ext(format string) string {
+	switch format {
+	case FormatJSON:
+		return ".json"
+	case FormatXML:
+		return ".xml"
+	default:
+		return ".txt"
+	}
+}
+
+// indentXML 逐个 token 重新编码 XML 并缩进，丢弃元素之间仅含空白的文本。
+func indentXML(text string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader(text))
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", indent)
+
+	elements := 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			elements++
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		if err := enc.EncodeToken(tok); err != nil {
+			return "", err
+		}
+		if _, ok := tok.(xml.ProcInst); ok && elements == 0 {
+			// 编码器不会在 XML 声明后换行
+			if err := enc.Flush(); err != nil {
+				return "", err
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	if elements == 0 {
+		return "", errors.New("没有 XML 元素")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cellformat
+
+import "testing"
+
+func TestPretty(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format string
+		want   string
+	}{
+		{
+			name:   "JSON 对象",
+			input:  `{"a":1,"b":[true,null]}`,
+			format: FormatJSON,
+			want:   "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}",
+		},
+		{
+			name:   "JSON 前后空白",
+			input:  "  [1,2]\n",
+			format: FormatJSON,
+			want:   "[\n  1,\n  2\n]",
+		},
+		{
+			name:   "XML 文档",
+			input:  `<?xml version="1.0"?><root><item id="1">x</item>  <item/></root>`,
+			format: FormatXML,
+			want:   "<?xml version=\"1.0\"?>\n<root>\n  <item id=\"1\">x</item>\n  <item></item>\n</root>",
+		},
+		{
+			name:   "非法 JSON 按文本返回",
+			input:  `{"a":`,
+			format: FormatText,
+			want:   `{"a":`,
+		},
+		{
+			name:   "非法 XML 按文本返回",
+			input:  "<a><b></a>",
+			format: FormatText,
+			want:   "<a><b></a>",
+		},
+		{
+			name:   "纯文本",
+			input:  "hello",
+			format: FormatText,
+			want:   "hello",
+		},
+		{
+			name:   "空字符串",
+			input:  "",
+			format: FormatText,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Pretty(tt.input)
+			if got.Format != tt.format || got.Content != tt.want {
+				t.Errorf("Pretty(%q) = %+v, want format %q content %q", tt.input, got, tt.format, tt.want)
+			}
+		})
+	}
+}
+
+func TestExt(t *testing.T) {
+	for format, want := range map[string]string{FormatJSON: ".json", FormatXML: ".xml", FormatText: ".txt", "": ".txt"} {
+		if got := Ext(format); got != want {
+			t.Errorf("Ext(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
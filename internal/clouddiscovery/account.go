@@ -0,0 +1,279 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clouddiscovery 从已配置的云账号列出托管数据库实例（AWS RDS、GCP Cloud SQL），
+// 并生成预填的连接配置（地域终端节点、端口与所需的 TLS 设置）；账号保存在用户配置目录下的 JSON 文件中。
+package clouddiscovery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 云服务商。
+const (
+	ProviderAWS = "aws" // AWS RDS / Aurora
+	ProviderGCP = "gcp" // GCP Cloud SQL
+)
+
+// ErrAccountNotFound 云账号不存在。
+var ErrAccountNotFound = errors.New("云账号不存在")
+
+// Account 是一个云账号配置；凭据留空时从环境变量（或 gcloud 命令行）读取。
+type Account struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Provider string   `json:"provider"`           // 云服务商，见 Provider*
+	Regions  []string `json:"regions,omitempty"`  // AWS 地域，为空时使用 AWS_REGION
+	Endpoint string   `json:"endpoint,omitempty"` // 自定义 API 地址（私有终端节点或兼容实现）
+
+	AccessKeyID     string `json:"accessKeyId,omitempty"`     // AWS 访问密钥 ID
+	SecretAccessKey string `json:"secretAccessKey,omitempty"` // AWS 访问密钥
+	SessionToken    string `json:"sessionToken,omitempty"`    // AWS 临时凭据令牌
+	CABundlePath    string `json:"caBundlePath,omitempty"`    // RDS CA 证书包路径，设置后生成校验证书的 TLS 配置
+
+	ProjectID   string `json:"projectId,omitempty"`   // GCP 项目 ID
+	AccessToken string `json:"accessToken,omitempty"` // GCP OAuth 访问令牌
+
+	HasSecret bool      `json:"hasSecret"` // 是否保存了密钥（列表中密钥字段会被清空）
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Store 保存云账号，可并发使用。
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	logger   *slog.Logger
+	accounts map[string]*Account
+	now      func() time.Time
+}
+
+// DefaultPath 返回默认的账号文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "cloud-accounts.json")
+	}
+	return filepath.Join(configDir, "Boxify", "cloud-accounts.json")
+}
+
+// NewStore 创建账号存储，path 为空时使用默认路径；需调用 Load 读取已保存的账号。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		path:     path,
+		logger:   logger.With("module", "clouddiscovery"),
+		accounts: make(map[string]*Account),
+		now:      time.Now,
+	}
+}
+
+// Load 读取账号文件，文件不存在时视为空。
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取云账号失败：%w", err)
+	}
+	var list []*Account
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析云账号失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = make(map[string]*Account, len(list))
+	for _, a := range list {
+		if a != nil && a.ID != "" {
+			s.accounts[a.ID] = a
+		}
+	}
+	return nil
+}
+
+// List 返回全部账号（密钥字段已清空），按名称排序。
+func (s *Store) List() []*Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Account, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		list = append(list, redact(a))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Get 按 ID 返回包含密钥的账号，仅供发现实例时使用。
+func (s *Store) Get(id string) (*Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	return clone(a), nil
+}
+
+// Save 创建或更新账号（ID 为空时创建）；更新时密钥字段留空表示保留原值。
+// 返回的账号已清空密钥字段。
+func (s *Store) Save(account *Account) (*Account, error) {
+	if account == nil {
+		return nil, errors.New("云账号不能为空")
+	}
+	saved := clone(account)
+	saved.Name = strings.TrimSpace(saved.Name)
+	saved.Provider = strings.ToLower(strings.TrimSpace(saved.Provider))
+	if err := validate(saved); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range s.accounts {
+		if a.ID != saved.ID && strings.EqualFold(a.Name, saved.Name) {
+			return nil, fmt.Errorf("云账号名称已存在: %s", saved.Name)
+		}
+	}
+
+	now := s.now()
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+	} else {
+		old, ok := s.accounts[saved.ID]
+		if !ok {
+			return nil, ErrAccountNotFound
+		}
+		saved.CreatedAt = old.CreatedAt
+		if saved.SecretAccessKey == "" {
+			saved.SecretAccessKey = old.SecretAccessKey
+		}
+		if saved.SessionToken == "" {
+			saved.SessionToken = old.SessionToken
+		}
+		if saved.AccessToken == "" {
+			saved.AccessToken = old.AccessToken
+		}
+	}
+	saved.UpdatedAt = now
+	saved.HasSecret = saved.SecretAccessKey != "" || saved.AccessToken != ""
+	s.accounts[saved.ID] = saved
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	return redact(saved), nil
+}
+
+// Delete 删除账号。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.accounts[id]; !ok {
+		return ErrAccountNotFound
+	}
+	delete(s.accounts, id)
+	return s.persistLocked()
+}
+
+// validate 校验账号字段。
+func validate(a *Account) error {
+	if a.Name == "" {
+		return errors.New("云账号名称不能为空")
+	}
+	switch a.Provider {
+	case ProviderAWS:
+		for _, r := range a.Regions {
+			if strings.TrimSpace(r) == "" || strings.ContainsAny(r, "/.: ") {
+				return fmt.Errorf("AWS 地域无效: %q", r)
+			}
+		}
+		if a.SecretAccessKey != "" && a.AccessKeyID == "" {
+			return errors.New("填写访问密钥时必须同时填写访问密钥 ID")
+		}
+	case ProviderGCP:
+		if strings.TrimSpace(a.ProjectID) == "" {
+			return errors.New("GCP 项目 ID 不能为空")
+		}
+		if strings.ContainsAny(a.ProjectID, "/?# ") {
+			return fmt.Errorf("GCP 项目 ID 无效: %q", a.ProjectID)
+		}
+	default:
+		return fmt.Errorf("不支持的云服务商: %s", a.Provider)
+	}
+	return nil
+}
+
+// clone 返回不共享切片的副本。
+func clone(a *Account) *Account {
+	c := *a
+	c.Regions = append([]string(nil), a.Regions...)
+	return &c
+}
+
+// redact 返回清空密钥字段的副本。
+func redact(a *Account) *Account {
+	c := clone(a)
+	c.SecretAccessKey = ""
+	c.SessionToken = ""
+	c.AccessToken = ""
+	return c
+}
+
+// persistLocked 将账号写入文件（权限 0600），先写临时文件再替换，调用方需持有锁。
+func (s *Store) persistLocked() error {
+	list := make([]*Account, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化云账号失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入云账号失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入云账号失败：%w", err)
+	}
+	s.logger.Debug("云账号已保存", "count", len(list))
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clouddiscovery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// rdsAPIVersion 是 RDS Query API 的版本。
+const rdsAPIVersion = "2014-10-31"
+
+// awsCredentials 是 SigV4 签名使用的凭据。
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// rdsResponse 对应 DescribeDBInstances 的 XML 响应。
+type rdsResponse struct {
+	Result struct {
+		Marker    string          `xml:"Marker"`
+		Instances []rdsDBInstance `xml:"DBInstances>DBInstance"`
+	} `xml:"DescribeDBInstancesResult"`
+}
+
+type rdsDBInstance struct {
+	Identifier     string `xml:"DBInstanceIdentifier"`
+	Engine         string `xml:"Engine"`
+	EngineVersion  string `xml:"EngineVersion"`
+	Status         string `xml:"DBInstanceStatus"`
+	MasterUsername string `xml:"MasterUsername"`
+	DBName         string `xml:"DBName"`
+	Endpoint       struct {
+		Address string `xml:"Address"`
+		Port    int    `xml:"Port"`
+	} `xml:"Endpoint"`
+	CAIdentifier string `xml:"CACertificateIdentifier"`
+	IAMAuth      bool   `xml:"IAMDatabaseAuthenticationEnabled"`
+}
+
+// rdsError 对应 RDS 的错误响应。
+type rdsError struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// discoverRDS 逐个地域调用 DescribeDBInstances。
+func (d *Discoverer) discoverRDS(ctx context.Context, account *Account) ([]*Instance, error) {
+	creds, err := awsCredentialsFor(account)
+	if err != nil {
+		return nil, err
+	}
+	regions := account.Regions
+	if len(regions) == 0 {
+		region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+		if region == "" {
+			return nil, errors.New("未配置 AWS 地域")
+		}
+		regions = []string{region}
+	}
+
+	var instances []*Instance
+	for _, region := range regions {
+		list, err := d.describeDBInstances(ctx, account, creds, region)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", region, err)
+		}
+		instances = append(instances, list...)
+	}
+	return instances, nil
+}
+
+// describeDBInstances 按 Marker 翻页列出一个地域的实例。
+func (d *Discoverer) describeDBInstances(ctx context.Context, account *Account, creds awsCredentials, region string) ([]*Instance, error) {
+	endpoint := strings.TrimRight(account.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://rds." + region + ".amazonaws.com"
+	}
+	var (
+		instances []*Instance
+		marker    string
+	)
+	for {
+		form := url.Values{"Action": {"DescribeDBInstances"}, "Version": {rdsAPIVersion}}
+		if marker != "" {
+			form.Set("Marker", marker)
+		}
+		body := form.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+		signAWSRequest(req, []byte(body), creds, region, "rds", time.Now())
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("请求 RDS API 失败：%w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取 RDS 响应失败：%w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			var apiErr rdsError
+			if xml.Unmarshal(data, &apiErr) == nil && apiErr.Error.Code != "" {
+				return nil, fmt.Errorf("RDS API 错误 %s: %s", apiErr.Error.Code, apiErr.Error.Message)
+			}
+			return nil, fmt.Errorf("RDS API 返回 %s", resp.Status)
+		}
+		var parsed rdsResponse
+		if err := xml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("解析 RDS 响应失败：%w", err)
+		}
+		for _, db := range parsed.Result.Instances {
+			instances = append(instances, rdsInstance(db, region, account.CABundlePath))
+		}
+		if parsed.Result.Marker == "" || parsed.Result.Marker == marker {
+			return instances, nil
+		}
+		marker = parsed.Result.Marker
+	}
+}
+
+// rdsInstance 将 RDS 实例转换为 Instance 并生成预填配置。
+// RDS 是否强制 TLS 由参数组决定，API 中不可见，因此默认要求加密；配置了 CA 证书包时校验证书与主机名。
+func rdsInstance(db rdsDBInstance, region, caBundle string) *Instance {
+	inst := &Instance{
+		ID:            db.Identifier,
+		Name:          db.Identifier,
+		Engine:        db.Engine,
+		EngineVersion: db.EngineVersion,
+		Region:        region,
+		Status:        db.Status,
+		Endpoint:      db.Endpoint.Address,
+		Port:          db.Endpoint.Port,
+	}
+	if db.IAMAuth {
+		inst.Notes = append(inst.Notes, "实例启用了 IAM 数据库认证，密码需使用 IAM 认证令牌")
+	}
+	if db.CAIdentifier != "" && caBundle == "" {
+		inst.Notes = append(inst.Notes, "实例使用 CA "+db.CAIdentifier+"，配置 RDS CA 证书包后可校验服务端证书")
+	}
+	typ := rdsEngineType(db.Engine)
+	if typ == "" {
+		inst.Notes = append(inst.Notes, "暂不支持该引擎: "+db.Engine)
+		return inst
+	}
+	if inst.Endpoint == "" {
+		inst.Notes = append(inst.Notes, "实例尚无连接地址")
+		return inst
+	}
+	tls := tlsFor(true)
+	if caBundle != "" {
+		tls = &connection.TLSConfig{Mode: connection.TLSModeVerifyFull, CAPath: caBundle, ServerName: inst.Endpoint}
+	}
+	inst.Config = &connection.ConnectionConfig{
+		Type:     typ,
+		Host:     inst.Endpoint,
+		Port:     inst.Port,
+		User:     db.MasterUsername,
+		Database: db.DBName,
+		TLS:      tls,
+	}
+	return inst
+}
+
+// rdsEngineType 将 RDS 引擎名称映射为连接类型，不支持时返回空。
+func rdsEngineType(engine string) connection.ConnectionType {
+	switch e := strings.ToLower(engine); {
+	case e == "mysql" || e == "aurora" || e == "aurora-mysql":
+		return connection.ConnectionTypeMySQL
+	case e == "mariadb":
+		return connection.ConnectionTypeMariaDB
+	case e == "postgres" || e == "aurora-postgresql":
+		return connection.ConnectionTypePostgreSQL
+	case strings.HasPrefix(e, "sqlserver"):
+		return connection.ConnectionTypeSQLServer
+	default:
+		return ""
+	}
+}
+
+// awsCredentialsFor 返回账号凭据，账号未填写时读取 AWS_* 环境变量。
+func awsCredentialsFor(account *Account) (awsCredentials, error) {
+	creds := awsCredentials{
+		accessKeyID:     account.AccessKeyID,
+		secretAccessKey: account.SecretAccessKey,
+		sessionToken:    account.SessionToken,
+	}
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		creds = awsCredentials{
+			accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		return awsCredentials{}, errors.New("未配置 AWS 访问密钥")
+	}
+	return creds, nil
+}
+
+// signAWSRequest 使用 AWS Signature Version 4 为请求签名。
+func signAWSRequest(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+	}
+	names := []string{"content-type", "host", "x-amz-date"}
+	if creds.sessionToken != "" {
+		headers["x-amz-security-token"] = creds.sessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+creds.secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// firstEnv 返回第一个非空的环境变量值。
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
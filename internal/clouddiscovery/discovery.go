@@ -0,0 +1,146 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clouddiscovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// defaultHTTPTimeout 是云 API 单次请求的超时时间。
+const defaultHTTPTimeout = 30 * time.Second
+
+// Instance 是发现到的托管数据库实例。
+type Instance struct {
+	Provider      string   `json:"provider"`
+	AccountID     string   `json:"accountId"`
+	ID            string   `json:"id"`                      // 实例标识（RDS 实例 ID 或 Cloud SQL 连接名）
+	Name          string   `json:"name"`                    // 实例名称
+	Engine        string   `json:"engine"`                  // 云服务商的引擎名称（如 aurora-mysql、POSTGRES_15）
+	EngineVersion string   `json:"engineVersion,omitempty"` // 引擎版本
+	Region        string   `json:"region"`                  // 地域
+	Status        string   `json:"status"`                  // 实例状态
+	Endpoint      string   `json:"endpoint,omitempty"`      // 连接地址
+	Port          int      `json:"port,omitempty"`          // 连接端口
+	TLSRequired   bool     `json:"tlsRequired"`             // 实例是否强制加密连接
+	CACertPEM     string   `json:"caCertPem,omitempty"`     // 服务端 CA 证书（Cloud SQL）
+	Notes         []string `json:"notes,omitempty"`         // 需要用户注意的事项
+
+	// Config 是预填的连接配置，不含密码；引擎不受支持或没有连接地址时为 nil。
+	Config *connection.ConnectionConfig `json:"config,omitempty"`
+}
+
+// Discoverer 调用云 API 列出实例。
+type Discoverer struct {
+	client *http.Client
+	caDir  string
+	logger *slog.Logger
+
+	// gcloudToken 获取 gcloud 命令行的访问令牌，测试中可替换。
+	gcloudToken func(ctx context.Context) (string, error)
+}
+
+// NewDiscoverer 创建实例发现器；caDir 为保存服务端 CA 证书的目录，为空时不保存，
+// 此时需要证书校验的实例只生成不校验证书的 TLS 配置。
+func NewDiscoverer(caDir string, logger *slog.Logger) *Discoverer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Discoverer{
+		client:      &http.Client{Timeout: defaultHTTPTimeout},
+		caDir:       caDir,
+		logger:      logger.With("module", "clouddiscovery"),
+		gcloudToken: gcloudAccessToken,
+	}
+}
+
+// DefaultCADir 返回默认的 CA 证书目录。
+func DefaultCADir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "cloud-ca")
+	}
+	return filepath.Join(configDir, "Boxify", "cloud-ca")
+}
+
+// Discover 列出账号下的全部实例。
+func (d *Discoverer) Discover(ctx context.Context, account *Account) ([]*Instance, error) {
+	if account == nil {
+		return nil, errors.New("云账号不能为空")
+	}
+	var (
+		instances []*Instance
+		err       error
+	)
+	switch account.Provider {
+	case ProviderAWS:
+		instances, err = d.discoverRDS(ctx, account)
+	case ProviderGCP:
+		instances, err = d.discoverCloudSQL(ctx, account)
+	default:
+		return nil, fmt.Errorf("不支持的云服务商: %s", account.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range instances {
+		inst.Provider = account.Provider
+		inst.AccountID = account.ID
+		d.applyCACert(inst)
+	}
+	d.logger.Info("云数据库实例发现完成", "account", account.Name, "provider", account.Provider, "count", len(instances))
+	return instances, nil
+}
+
+// applyCACert 将实例的服务端 CA 证书写入 caDir，并将连接配置升级为校验证书链。
+func (d *Discoverer) applyCACert(inst *Instance) {
+	if inst.CACertPEM == "" || inst.Config == nil || d.caDir == "" {
+		return
+	}
+	sum := sha256.Sum256([]byte(inst.Provider + "|" + inst.ID))
+	path := filepath.Join(d.caDir, hex.EncodeToString(sum[:8])+".pem")
+	if err := os.MkdirAll(d.caDir, 0o700); err != nil {
+		d.logger.Warn("创建 CA 证书目录失败", "error", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(inst.CACertPEM), 0o600); err != nil {
+		d.logger.Warn("保存 CA 证书失败", "instance", inst.ID, "error", err)
+		return
+	}
+	if inst.Config.TLS == nil {
+		inst.Config.TLS = &connection.TLSConfig{}
+	}
+	// 证书主题为实例名而非连接地址，只能校验证书链
+	inst.Config.TLS.Mode = connection.TLSModeVerifyCA
+	inst.Config.TLS.CAPath = path
+}
+
+// tlsFor 返回实例所需的 TLS 设置；required 为 false 时为 preferred。
+func tlsFor(required bool) *connection.TLSConfig {
+	if required {
+		return &connection.TLSConfig{Mode: connection.TLSModeRequired}
+	}
+	return &connection.TLSConfig{Mode: connection.TLSModePreferred}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clouddiscovery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestStoreKeepsSecretsOnUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	s := NewStore(path, nil)
+
+	saved, err := s.Save(&Account{Name: "prod", Provider: "AWS", Regions: []string{"us-east-1"}, AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved.SecretAccessKey != "" || !saved.HasSecret || saved.Provider != ProviderAWS {
+		t.Fatalf("返回的账号应清空密钥: %+v", saved)
+	}
+	if _, err := s.Save(&Account{ID: saved.ID, Name: "prod", Provider: ProviderAWS, AccessKeyID: "AKID2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(&Account{Name: "gcp", Provider: ProviderGCP}); err == nil {
+		t.Fatal("缺少项目 ID 应返回错误")
+	}
+
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.Get(saved.ID)
+	if err != nil || got.AccessKeyID != "AKID2" || got.SecretAccessKey != "secret" {
+		t.Fatalf("更新时留空的密钥应保留原值: %+v, %v", got, err)
+	}
+	if list := reloaded.List(); len(list) != 1 || list[0].SecretAccessKey != "" {
+		t.Fatalf("List() = %+v", list)
+	}
+	if err := reloaded.Delete("missing"); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestDiscoverRDS(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/rds/aws4_request") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := r.ParseForm(); err != nil || r.Form.Get("Action") != "DescribeDBInstances" {
+			t.Errorf("form = %v, %v", r.Form, err)
+		}
+		if r.Form.Get("Marker") == "" {
+			w.Write([]byte(`<DescribeDBInstancesResponse><DescribeDBInstancesResult><Marker>next</Marker><DBInstances>
+<DBInstance><DBInstanceIdentifier>orders</DBInstanceIdentifier><Engine>aurora-mysql</Engine><EngineVersion>8.0</EngineVersion>
+<DBInstanceStatus>available</DBInstanceStatus><MasterUsername>admin</MasterUsername><DBName>shop</DBName>
+<Endpoint><Address>orders.abc.eu-west-1.rds.amazonaws.com</Address><Port>3306</Port></Endpoint></DBInstance>
+</DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`))
+			return
+		}
+		w.Write([]byte(`<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances>
+<DBInstance><DBInstanceIdentifier>legacy</DBInstanceIdentifier><Engine>oracle-ee</Engine><DBInstanceStatus>available</DBInstanceStatus></DBInstance>
+</DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`))
+	}))
+	defer srv.Close()
+
+	d := NewDiscoverer("", nil)
+	list, err := d.Discover(context.Background(), &Account{ID: "a1", Provider: ProviderAWS, Regions: []string{"eu-west-1"}, Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if calls != 2 || len(list) != 2 {
+		t.Fatalf("应翻页获取两个实例: calls=%d list=%d", calls, len(list))
+	}
+	cfg := list[0].Config
+	if cfg == nil || cfg.Type != connection.ConnectionTypeMySQL || cfg.Host != "orders.abc.eu-west-1.rds.amazonaws.com" ||
+		cfg.Port != 3306 || cfg.User != "admin" || cfg.Database != "shop" || cfg.TLS == nil || cfg.TLS.Mode != connection.TLSModeRequired {
+		t.Fatalf("预填配置错误: %+v", cfg)
+	}
+	if list[0].Region != "eu-west-1" || list[0].AccountID != "a1" {
+		t.Fatalf("实例字段错误: %+v", list[0])
+	}
+	if list[1].Config != nil || len(list[1].Notes) == 0 {
+		t.Fatalf("不支持的引擎不应生成配置: %+v", list[1])
+	}
+}
+
+func TestDiscoverCloudSQL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" || r.URL.Path != "/v1/projects/proj-1/instances" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"message":"denied"}}`))
+			return
+		}
+		w.Write([]byte(`{"items":[{"name":"pg","connectionName":"proj-1:us-central1:pg","databaseVersion":"POSTGRES_15","region":"us-central1","state":"RUNNABLE",
+"ipAddresses":[{"type":"PRIVATE","ipAddress":"10.0.0.5"},{"type":"PRIMARY","ipAddress":"34.1.2.3"}],
+"settings":{"ipConfiguration":{"sslMode":"ENCRYPTED_ONLY"}},"serverCaCert":{"cert":"-----BEGIN CERTIFICATE-----\nAAA\n-----END CERTIFICATE-----\n"}}]}`))
+	}))
+	defer srv.Close()
+
+	caDir := t.TempDir()
+	d := NewDiscoverer(caDir, nil)
+	d.gcloudToken = func(context.Context) (string, error) { return "tok", nil }
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	list, err := d.Discover(ctx, &Account{Provider: ProviderGCP, ProjectID: "proj-1", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("len(list) = %d", len(list))
+	}
+	inst := list[0]
+	if inst.ID != "proj-1:us-central1:pg" || inst.EngineVersion != "15" || !inst.TLSRequired {
+		t.Fatalf("实例字段错误: %+v", inst)
+	}
+	cfg := inst.Config
+	if cfg == nil || cfg.Type != connection.ConnectionTypePostgreSQL || cfg.Host != "34.1.2.3" || cfg.Port != 5432 {
+		t.Fatalf("预填配置错误: %+v", cfg)
+	}
+	if cfg.TLS == nil || cfg.TLS.Mode != connection.TLSModeVerifyCA || filepath.Dir(cfg.TLS.CAPath) != caDir {
+		t.Fatalf("应保存 CA 证书并校验证书链: %+v", cfg.TLS)
+	}
+	if data, err := os.ReadFile(cfg.TLS.CAPath); err != nil || string(data) != inst.CACertPEM {
+		t.Fatalf("CA 证书内容错误: %v", err)
+	}
+
+	if _, err := d.Discover(ctx, &Account{Provider: ProviderGCP, ProjectID: "other", Endpoint: srv.URL, AccessToken: "tok"}); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("API 错误应返回服务端信息: %v", err)
+	}
+}
+
+func TestSignAWSRequestIsDeterministic(t *testing.T) {
+	creds := awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret", sessionToken: "session"}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sign := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "https://rds.us-east-1.amazonaws.com/", nil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		signAWSRequest(req, []byte("Action=DescribeDBInstances"), creds, "us-east-1", "rds", at)
+		return req
+	}
+	a, b := sign(), sign()
+	if a.Header.Get("Authorization") != b.Header.Get("Authorization") {
+		t.Fatal("相同输入的签名应一致")
+	}
+	if a.Header.Get("X-Amz-Date") != "20260102T030405Z" || a.Header.Get("X-Amz-Security-Token") != "session" ||
+		!strings.Contains(a.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token") {
+		t.Fatalf("签名头错误: %v", a.Header)
+	}
+}
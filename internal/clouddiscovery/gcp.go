@@ -0,0 +1,231 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clouddiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// sqlAdminEndpoint 是 Cloud SQL Admin API 的默认地址。
+const sqlAdminEndpoint = "https://sqladmin.googleapis.com"
+
+// cloudSQLList 对应 instances.list 的响应。
+type cloudSQLList struct {
+	Items         []cloudSQLInstance `json:"items"`
+	NextPageToken string             `json:"nextPageToken"`
+}
+
+type cloudSQLInstance struct {
+	Name            string `json:"name"`
+	ConnectionName  string `json:"connectionName"`
+	DatabaseVersion string `json:"databaseVersion"`
+	Region          string `json:"region"`
+	State           string `json:"state"`
+	DNSName         string `json:"dnsName"`
+	IPAddresses     []struct {
+		Type      string `json:"type"`
+		IPAddress string `json:"ipAddress"`
+	} `json:"ipAddresses"`
+	Settings struct {
+		IPConfiguration struct {
+			RequireSSL bool   `json:"requireSsl"`
+			SSLMode    string `json:"sslMode"`
+		} `json:"ipConfiguration"`
+	} `json:"settings"`
+	ServerCACert struct {
+		Cert string `json:"cert"`
+	} `json:"serverCaCert"`
+}
+
+// cloudSQLError 对应 Google API 的错误响应。
+type cloudSQLError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// discoverCloudSQL 按 pageToken 翻页列出项目下的实例。
+func (d *Discoverer) discoverCloudSQL(ctx context.Context, account *Account) ([]*Instance, error) {
+	token, err := d.gcpToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimRight(account.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = sqlAdminEndpoint
+	}
+	base := endpoint + "/v1/projects/" + url.PathEscape(account.ProjectID) + "/instances"
+
+	var (
+		instances []*Instance
+		pageToken string
+	)
+	for {
+		target := base
+		if pageToken != "" {
+			target += "?pageToken=" + url.QueryEscape(pageToken)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("请求 Cloud SQL API 失败：%w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取 Cloud SQL 响应失败：%w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			var apiErr cloudSQLError
+			if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+				return nil, fmt.Errorf("Cloud SQL API 错误 %d: %s", apiErr.Error.Code, apiErr.Error.Message)
+			}
+			return nil, fmt.Errorf("Cloud SQL API 返回 %s", resp.Status)
+		}
+		var parsed cloudSQLList
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("解析 Cloud SQL 响应失败：%w", err)
+		}
+		for _, item := range parsed.Items {
+			instances = append(instances, cloudSQLToInstance(item))
+		}
+		if parsed.NextPageToken == "" || parsed.NextPageToken == pageToken {
+			return instances, nil
+		}
+		pageToken = parsed.NextPageToken
+	}
+}
+
+// cloudSQLToInstance 将 Cloud SQL 实例转换为 Instance 并生成预填配置；优先使用公网地址。
+func cloudSQLToInstance(item cloudSQLInstance) *Instance {
+	inst := &Instance{
+		ID:        item.ConnectionName,
+		Name:      item.Name,
+		Engine:    item.DatabaseVersion,
+		Region:    item.Region,
+		Status:    item.State,
+		CACertPEM: item.ServerCACert.Cert,
+	}
+	if inst.ID == "" {
+		inst.ID = item.Name
+	}
+	if _, version, ok := strings.Cut(item.DatabaseVersion, "_"); ok {
+		inst.EngineVersion = strings.ReplaceAll(version, "_", ".")
+	}
+	for _, ip := range item.IPAddresses {
+		if ip.Type == "PRIMARY" {
+			inst.Endpoint = ip.IPAddress
+			break
+		}
+		if ip.Type == "PRIVATE" && inst.Endpoint == "" {
+			inst.Endpoint = ip.IPAddress
+		}
+	}
+	if inst.Endpoint == "" {
+		inst.Endpoint = item.DNSName
+	}
+
+	ipConfig := item.Settings.IPConfiguration
+	switch ipConfig.SSLMode {
+	case "ENCRYPTED_ONLY":
+		inst.TLSRequired = true
+	case "TRUSTED_CLIENT_CERTIFICATE_REQUIRED":
+		inst.TLSRequired = true
+		inst.Notes = append(inst.Notes, "实例要求客户端证书，需在连接前配置客户端证书或使用 Cloud SQL Auth Proxy")
+	case "":
+		inst.TLSRequired = ipConfig.RequireSSL
+	}
+
+	typ, port := cloudSQLEngine(item.DatabaseVersion)
+	if typ == "" {
+		inst.Notes = append(inst.Notes, "暂不支持该引擎: "+item.DatabaseVersion)
+		return inst
+	}
+	inst.Port = port
+	if inst.Endpoint == "" {
+		inst.Notes = append(inst.Notes, "实例尚无连接地址")
+		return inst
+	}
+	inst.Config = &connection.ConnectionConfig{
+		Type: typ,
+		Host: inst.Endpoint,
+		Port: port,
+		TLS:  tlsFor(inst.TLSRequired),
+	}
+	return inst
+}
+
+// cloudSQLEngine 将 databaseVersion 映射为连接类型与默认端口，不支持时返回空。
+func cloudSQLEngine(version string) (connection.ConnectionType, int) {
+	switch v := strings.ToUpper(version); {
+	case strings.HasPrefix(v, "MYSQL"):
+		return connection.ConnectionTypeMySQL, 3306
+	case strings.HasPrefix(v, "POSTGRES"):
+		return connection.ConnectionTypePostgreSQL, 5432
+	case strings.HasPrefix(v, "SQLSERVER"):
+		return connection.ConnectionTypeSQLServer, 1433
+	default:
+		return "", 0
+	}
+}
+
+// gcpToken 返回访问令牌：依次使用账号中的令牌、GOOGLE_OAUTH_ACCESS_TOKEN 环境变量与 gcloud 命令行。
+func (d *Discoverer) gcpToken(ctx context.Context, account *Account) (string, error) {
+	if account.AccessToken != "" {
+		return account.AccessToken, nil
+	}
+	if token := firstEnv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	token, err := d.gcloudToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("未配置 GCP 访问令牌，且无法通过 gcloud 获取：%w", err)
+	}
+	return token, nil
+}
+
+// gcloudAccessToken 调用 gcloud auth print-access-token 获取令牌。
+func gcloudAccessToken(ctx context.Context) (string, error) {
+	path, err := exec.LookPath("gcloud")
+	if err != nil {
+		return "", errors.New("未找到 gcloud 命令")
+	}
+	out, err := exec.CommandContext(ctx, path, "auth", "print-access-token").Output()
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("gcloud 未返回令牌")
+	}
+	return token, nil
+}
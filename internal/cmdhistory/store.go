@@ -0,0 +1,315 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cmdhistory 记录终端中通过命令块执行的命令（会话、工作目录、退出码与时间），
+// 以 JSON Lines 文件持久化，并按命令去重提供搜索与补全建议。
+package cmdhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxEntries 历史文件保留的最大记录数，超过后压缩为最近的一半。
+	DefaultMaxEntries = 20000
+	// DefaultLimit 搜索与建议默认返回的条数。
+	DefaultLimit = 50
+	// maxCommandLength 记录中保存的命令最大长度。
+	maxCommandLength = 4000
+)
+
+// Entry 是一次命令执行记录。
+type Entry struct {
+	Time      time.Time `json:"time"`               // 执行时间
+	Command   string    `json:"command"`            // 命令文本
+	SessionID string    `json:"sessionId"`          // 终端会话 ID
+	WorkPath  string    `json:"workPath,omitempty"` // 执行时的工作目录
+	Shell     string    `json:"shell,omitempty"`    // shell 类型
+	ExitCode  *int      `json:"exitCode,omitempty"` // 退出码，未知时为空
+}
+
+// Item 是按命令去重后的历史条目。
+type Item struct {
+	Command      string    `json:"command"`
+	Count        int       `json:"count"`                  // 执行次数
+	LastUsed     time.Time `json:"lastUsed"`               // 最近执行时间
+	LastExitCode *int      `json:"lastExitCode,omitempty"` // 最近一次的退出码
+	WorkPath     string    `json:"workPath,omitempty"`     // 最近一次的工作目录
+}
+
+// Query 是搜索与建议的条件。
+type Query struct {
+	Text      string // 匹配文本，忽略大小写；为空时返回最近的命令
+	Prefix    bool   // true 时按前缀匹配，否则按子串匹配
+	SessionID string // 只返回该会话执行过的命令；为空表示全部会话
+	WorkPath  string // 建议时优先该目录下执行过的命令
+	Limit     int    // 最多返回条数，<= 0 时使用 DefaultLimit
+}
+
+// Store 以 JSON Lines 文件追加保存命令历史，并在内存中保留全部记录供查询。
+type Store struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	entries    []Entry // 按时间顺序
+	loaded     bool
+	logger     *slog.Logger
+}
+
+// DefaultPath 返回默认命令历史文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "command-history.jsonl")
+	}
+	return filepath.Join(configDir, "Boxify", "command-history.jsonl")
+}
+
+// NewStore 创建命令历史存储，path 为空时使用默认路径，maxEntries<=0 时使用默认上限。
+func NewStore(path string, maxEntries int, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{path: path, maxEntries: maxEntries, logger: logger.With("module", "cmdhistory")}
+}
+
+// Record 追加一条执行记录；空命令与以空格开头的命令（shell 的 ignorespace 约定）不记录。
+func (s *Store) Record(entry Entry) error {
+	if strings.TrimSpace(entry.Command) == "" || strings.HasPrefix(entry.Command, " ") {
+		return nil
+	}
+	entry.Command = strings.TrimRight(entry.Command, "\r\n")
+	if len(entry.Command) > maxCommandLength {
+		entry.Command = entry.Command[:maxCommandLength]
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化命令历史失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建命令历史目录失败：%w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("写入命令历史失败：%w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("写入命令历史失败：%w", err)
+	}
+
+	s.entries = append(s.entries, entry)
+	if len(s.entries) > s.maxEntries {
+		return s.compactLocked()
+	}
+	return nil
+}
+
+// Search 按条件查找命令，按命令去重，最近执行的在前。
+func (s *Store) Search(q Query) ([]Item, error) {
+	items, err := s.collect(q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].LastUsed.After(items[j].LastUsed) })
+	return limit(items, q.Limit), nil
+}
+
+// Suggest 按前缀返回补全建议：同一工作目录下执行过的命令优先，其次按执行次数与最近使用时间排序；
+// 与输入完全相同的命令不作为建议。
+func (s *Store) Suggest(q Query) ([]Item, error) {
+	q.Prefix = true
+	items, err := s.collect(q)
+	if err != nil {
+		return nil, err
+	}
+	kept := items[:0]
+	for _, it := range items {
+		if it.Command != q.Text {
+			kept = append(kept, it)
+		}
+	}
+	items = kept
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if q.WorkPath != "" && (a.WorkPath == q.WorkPath) != (b.WorkPath == q.WorkPath) {
+			return a.WorkPath == q.WorkPath
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+	return limit(items, q.Limit), nil
+}
+
+// Clear 清空全部命令历史。
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("清空命令历史失败：%w", err)
+	}
+	s.entries = nil
+	s.loaded = true
+	return nil
+}
+
+// collect 按条件过滤记录并按命令聚合。
+func (s *Store) collect(q Query) ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+
+	needle := strings.ToLower(q.Text)
+	index := make(map[string]int)
+	var items []Item
+	for _, e := range s.entries {
+		if q.SessionID != "" && e.SessionID != q.SessionID {
+			continue
+		}
+		cmd := strings.ToLower(e.Command)
+		if q.Prefix && !strings.HasPrefix(cmd, needle) || !q.Prefix && !strings.Contains(cmd, needle) {
+			continue
+		}
+		i, ok := index[e.Command]
+		if !ok {
+			i = len(items)
+			index[e.Command] = i
+			items = append(items, Item{Command: e.Command})
+		}
+		it := &items[i]
+		it.Count++
+		if !e.Time.Before(it.LastUsed) {
+			it.LastUsed, it.LastExitCode, it.WorkPath = e.Time, e.ExitCode, e.WorkPath
+		}
+	}
+	return items, nil
+}
+
+// limit 截取前 n 条，n <= 0 时使用默认条数。
+func limit(items []Item, n int) []Item {
+	if n <= 0 {
+		n = DefaultLimit
+	}
+	if items == nil {
+		return []Item{}
+	}
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+// loadLocked 首次使用时读取历史文件，跳过损坏的行；调用方需持有锁。
+func (s *Store) loadLocked() error {
+	if s.loaded {
+		return nil
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.loaded = true
+			return nil
+		}
+		return fmt.Errorf("读取命令历史失败：%w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	skipped := 0
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Command == "" {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取命令历史失败：%w", err)
+	}
+	if skipped > 0 {
+		s.logger.Warn("跳过损坏的命令历史记录", "path", s.path, "count", skipped)
+	}
+	s.entries = entries
+	s.loaded = true
+	return nil
+}
+
+// compactLocked 仅保留最近一半的记录；调用方需持有锁。
+func (s *Store) compactLocked() error {
+	keep := append([]Entry(nil), s.entries[max(0, len(s.entries)-s.maxEntries/2):]...)
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("压缩命令历史失败：%w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range keep {
+		if err = enc.Encode(e); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("压缩命令历史失败：%w", err)
+	}
+	s.logger.Info("命令历史已压缩", "kept", len(keep), "dropped", len(s.entries)-len(keep))
+	s.entries = keep
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdhistory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestStoreRecordSearchPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s := NewStore(path, 0, nil)
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	records := []Entry{
+		{Time: base, Command: "git status", SessionID: "s1", WorkPath: "/a", ExitCode: intPtr(0)},
+		{Time: base.Add(time.Minute), Command: "go test ./...", SessionID: "s1", WorkPath: "/a", ExitCode: intPtr(1)},
+		{Time: base.Add(2 * time.Minute), Command: "git status", SessionID: "s2", WorkPath: "/b", ExitCode: intPtr(128)},
+		{Time: base.Add(3 * time.Minute), Command: " secret-token", SessionID: "s1"},
+		{Time: base.Add(4 * time.Minute), Command: "   ", SessionID: "s1"},
+	}
+	for _, e := range records {
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	reloaded := NewStore(path, 0, nil)
+	items, err := reloaded.Search(Query{})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Command != "git status" || items[1].Command != "go test ./..." {
+		t.Fatalf("应按命令去重并按最近使用排序: %+v", items)
+	}
+	if items[0].Count != 2 || *items[0].LastExitCode != 128 || items[0].WorkPath != "/b" {
+		t.Fatalf("去重条目应保留最近一次的信息: %+v", items[0])
+	}
+
+	items, _ = reloaded.Search(Query{Text: "GIT", SessionID: "s1"})
+	if len(items) != 1 || items[0].Count != 1 || *items[0].LastExitCode != 0 {
+		t.Fatalf("按会话过滤结果错误: %+v", items)
+	}
+}
+
+func TestStoreSuggest(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "history.jsonl"), 0, nil)
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, e := range []Entry{
+		{Command: "go build ./...", WorkPath: "/b"},
+		{Command: "go build ./...", WorkPath: "/b"},
+		{Command: "go test ./...", WorkPath: "/a"},
+		{Command: "go vet", WorkPath: "/b"},
+		{Command: "ls", WorkPath: "/a"},
+	} {
+		e.Time = base.Add(time.Duration(i) * time.Minute)
+		e.SessionID = "s1"
+		if err := s.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, err := s.Suggest(Query{Text: "go ", WorkPath: "/a"})
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	want := []string{"go test ./...", "go build ./...", "go vet"}
+	if len(items) != len(want) {
+		t.Fatalf("Suggest() = %+v, want %v", items, want)
+	}
+	for i, cmd := range want {
+		if items[i].Command != cmd {
+			t.Fatalf("Suggest()[%d] = %q, want %q", i, items[i].Command, cmd)
+		}
+	}
+
+	items, _ = s.Suggest(Query{Text: "go vet"})
+	if len(items) != 0 {
+		t.Fatalf("与输入相同的命令不应作为建议: %+v", items)
+	}
+}
+
+func TestStoreCompactAndClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s := NewStore(path, 4, nil)
+	for i := 0; i < 5; i++ {
+		if err := s.Record(Entry{Command: string(rune('a' + i)), SessionID: "s1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := NewStore(path, 4, nil)
+	items, _ := reloaded.Search(Query{})
+	if len(items) != 2 || items[0].Command != "e" || items[1].Command != "d" {
+		t.Fatalf("超过上限后应只保留最近一半: %+v", items)
+	}
+
+	if err := reloaded.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Clear 后历史文件应被删除, stat err = %v", err)
+	}
+	if items, _ := reloaded.Search(Query{}); len(items) != 0 {
+		t.Fatalf("Clear 后不应有历史: %+v", items)
+	}
+}
+
+func TestStoreSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	content := "{\"time\":\"2026-01-01T10:00:00Z\",\"command\":\"pwd\",\"sessionId\":\"s1\"}\nnot-json\n{\"command\":\"\"}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	items, err := NewStore(path, 0, nil).Search(Query{})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Command != "pwd" {
+		t.Fatalf("应跳过损坏的记录: %+v", items)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import "strings"
+
+// Clone 返回连接配置的深拷贝，修改副本（包括 SSH 与代理配置）不会影响原配置；nil 时返回 nil。
+func (c *ConnectionConfig) Clone() *ConnectionConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	if c.SSH != nil {
+		ssh := *c.SSH
+		if ssh.Proxy != nil {
+			proxy := *ssh.Proxy
+			ssh.Proxy = &proxy
+		}
+		clone.SSH = &ssh
+	}
+	if c.Proxy != nil {
+		proxy := *c.Proxy
+		clone.Proxy = &proxy
+	}
+	if c.Hosts != nil {
+		clone.Hosts = append([]HostAddress(nil), c.Hosts...)
+	}
+	if c.Auth != nil {
+		auth := *c.Auth
+		clone.Auth = &auth
+	}
+	if c.TLS != nil {
+		tls := *c.TLS
+		clone.TLS = &tls
+	}
+	return &clone
+}
+
+// Normalize 返回规范化后的深拷贝，是连接缓存与建连使用配置的唯一入口：
+// 未启用 SSH 时清空 SSH 配置，启用时清空直连代理（数据库连接经由隧道），代理类型为空视为不使用代理；PostgreSQL 未指定库名时使用默认库 postgres，
+// 没有备用主机时清空主机策略与只读路由，否则未指定策略时使用 first-available；
+// 密码认证不保留认证配置。
+// 接收者不会被修改，同一配置对象可被多个调用并发使用。
+func (c *ConnectionConfig) Normalize() *ConnectionConfig {
+	if c == nil {
+		return nil
+	}
+	n := c.Clone()
+	if !n.UseSSH {
+		n.SSH = &SSHConfig{}
+	} else {
+		n.Proxy = nil
+		if n.SSH != nil && n.SSH.Proxy != nil && strings.TrimSpace(n.SSH.Proxy.Type) == "" {
+			n.SSH.Proxy = nil
+		}
+	}
+	if n.Proxy != nil && strings.TrimSpace(n.Proxy.Type) == "" {
+		n.Proxy = nil
+	}
+	if (n.Type == "postgres" || n.Type == ConnectionTypePostgreSQL) && n.Database == "" {
+		n.Database = "postgres"
+	}
+	if n.Auth != nil {
+		n.Auth.Method = strings.ToLower(strings.TrimSpace(n.Auth.Method))
+		if n.Auth.Method == "" || n.Auth.Method == AuthMethodPassword {
+			n.Auth = nil
+		}
+	}
+	n.HostPolicy = strings.ToLower(strings.TrimSpace(n.HostPolicy))
+	n.ReadRoute = strings.ToLower(strings.TrimSpace(n.ReadRoute))
+	if len(n.Hosts) == 0 {
+		n.Hosts = nil
+		n.HostPolicy, n.ReadRoute = "", ""
+	} else if n.HostPolicy == "" {
+		n.HostPolicy = HostPolicyFirstAvailable
+	}
+	return n
+}
+
+// AuthMethod 返回规范化的认证方式，未配置时为 password。
+func (c *ConnectionConfig) AuthMethod() string {
+	if c.Auth == nil {
+		return AuthMethodPassword
+	}
+	method := strings.ToLower(strings.TrimSpace(c.Auth.Method))
+	if method == "" {
+		return AuthMethodPassword
+	}
+	return method
+}
+
+// Endpoints 返回全部主机地址：主库在前，其后为按配置顺序排列的备用主机。
+func (c *ConnectionConfig) Endpoints() []HostAddress {
+	endpoints := make([]HostAddress, 0, 1+len(c.Hosts))
+	endpoints = append(endpoints, HostAddress{Host: c.Host, Port: c.Port})
+	return append(endpoints, c.Hosts...)
+}
+
+// ForHost 返回指向单个主机的深拷贝，不再包含备用主机、主机策略与只读路由。
+func (c *ConnectionConfig) ForHost(h HostAddress) *ConnectionConfig {
+	clone := c.Clone()
+	clone.Host, clone.Port = h.Host, h.Port
+	clone.Hosts, clone.HostPolicy, clone.ReadRoute = nil, "", ""
+	return clone
+}
+
+// WithReadRoute 返回覆盖只读查询路由的深拷贝，route 为空时返回原配置的深拷贝。
+func (c *ConnectionConfig) WithReadRoute(route string) *ConnectionConfig {
+	clone := c.Clone()
+	if route = strings.TrimSpace(route); route != "" {
+		clone.ReadRoute = route
+	}
+	return clone
+}
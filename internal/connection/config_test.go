@@ -0,0 +1,74 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCloneIsDeep(t *testing.T) {
+	orig := &ConnectionConfig{Type: ConnectionTypeMySQL, UseSSH: true, SSH: &SSHConfig{Host: "bastion"}}
+	c := orig.Clone()
+	c.SSH.Host = "other"
+	c.Database = "db"
+	if orig.SSH.Host != "bastion" || orig.Database != "" {
+		t.Fatalf("Clone 修改了原配置: %+v %+v", orig, orig.SSH)
+	}
+	if (*ConnectionConfig)(nil).Clone() != nil {
+		t.Fatal("nil.Clone() 应返回 nil")
+	}
+}
+
+func TestNormalizeDoesNotMutate(t *testing.T) {
+	orig := &ConnectionConfig{Type: ConnectionTypePostgreSQL, SSH: &SSHConfig{Host: "bastion"}}
+	n := orig.Normalize()
+	if n.Database != "postgres" || n.SSH == nil || n.SSH.Host != "" {
+		t.Fatalf("规范化结果不符合预期: %+v %+v", n, n.SSH)
+	}
+	if orig.Database != "" || orig.SSH.Host != "bastion" {
+		t.Fatalf("Normalize 修改了原配置: %+v %+v", orig, orig.SSH)
+	}
+}
+
+func TestNormalizeConcurrent(t *testing.T) {
+	cfg := &ConnectionConfig{Type: ConnectionTypePostgreSQL, UseSSH: true, SSH: &SSHConfig{Host: "bastion"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := cfg.Normalize()
+			n.SSH.Port = 22
+		}()
+	}
+	wg.Wait()
+	if cfg.SSH.Port != 0 || cfg.Database != "" {
+		t.Fatalf("并发规范化修改了原配置: %+v %+v", cfg, cfg.SSH)
+	}
+}
+
+func TestNormalizeProxy(t *testing.T) {
+	proxy := &ProxyConfig{Type: ProxyTypeSOCKS5, Host: "proxy", Port: 1080}
+	viaSSH := (&ConnectionConfig{UseSSH: true, SSH: &SSHConfig{Host: "bastion", Proxy: &ProxyConfig{}}, Proxy: proxy}).Normalize()
+	if viaSSH.Proxy != nil || viaSSH.SSH.Proxy != nil {
+		t.Fatalf("启用 SSH 时应忽略直连代理与空代理: %+v %+v", viaSSH.Proxy, viaSSH.SSH.Proxy)
+	}
+	direct := &ConnectionConfig{Proxy: proxy}
+	n := direct.Normalize()
+	if n.Proxy == nil || n.Proxy == direct.Proxy {
+		t.Fatalf("直连代理应保留为副本: %+v", n.Proxy)
+	}
+}
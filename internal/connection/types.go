@@ -14,6 +14,11 @@
 
 package connection
 
+import (
+	"net"
+	"strconv"
+)
+
 // ConnectionType 数据库连接类型
 type ConnectionType string
 
@@ -35,11 +40,27 @@ const (
 // SSHConfig 是SSH连接的配置结构体
 // 包含主机、端口、用户、密码和密钥路径等信息
 type SSHConfig struct {
+	Host     string       `json:"host"`
+	Port     int          `json:"port"`
+	User     string       `json:"user"`
+	Password string       `json:"password"`
+	KeyPath  string       `json:"keyPath"`
+	Proxy    *ProxyConfig `json:"proxy,omitempty"` // 连接跳板机使用的代理，为空时直连
+}
+
+// 代理类型。
+const (
+	ProxyTypeSOCKS5 = "socks5" // SOCKS5 代理
+	ProxyTypeHTTP   = "http"   // HTTP CONNECT 代理
+)
+
+// ProxyConfig 是建立 TCP 连接时经过的代理
+type ProxyConfig struct {
+	Type     string `json:"type"` // 代理类型，见 ProxyType*
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	KeyPath  string `json:"keyPath"`
+	User     string `json:"user,omitempty"`     // 代理认证用户名，为空时不认证
+	Password string `json:"password,omitempty"` // 代理认证密码
 }
 
 // ConnectionConfig 是数据库连接的配置结构体
@@ -56,15 +77,162 @@ type ConnectionConfig struct {
 	Driver   string         `json:"driver,omitempty"`  // 用于自定义连接
 	DSN      string         `json:"dsn,omitempty"`     // 用于自定义连接
 	Timeout  int            `json:"timeout,omitempty"` // 连接超时时间，单位秒
+
+	Hosts      []HostAddress `json:"hosts,omitempty"`      // 备用主机（如只读副本），Host/Port 为主库
+	HostPolicy string        `json:"hostPolicy,omitempty"` // 多主机时的选择策略，见 HostPolicy*
+	ReadRoute  string        `json:"readRoute,omitempty"`  // 只读查询的路由，见 Route*；为空时按主机策略
+
+	Auth *AuthConfig `json:"auth,omitempty"` // 密码以外的认证方式，为空时使用用户名密码
+	TLS  *TLSConfig  `json:"tls,omitempty"`  // 传输加密设置，为空时由驱动决定
+
+	Proxy *ProxyConfig `json:"proxy,omitempty"` // 直连数据库时使用的代理，启用 SSH 时改用 SSH.Proxy
+}
+
+// TLS 模式。
+const (
+	TLSModeDisable    = "disable"     // 不加密
+	TLSModePreferred  = "preferred"   // 服务端支持时加密，不校验证书
+	TLSModeRequired   = "required"    // 必须加密，不校验证书
+	TLSModeVerifyCA   = "verify-ca"   // 必须加密并校验证书链
+	TLSModeVerifyFull = "verify-full" // 必须加密并校验证书链与主机名
+)
+
+// TLSConfig 是连接的传输加密设置。
+type TLSConfig struct {
+	Mode       string `json:"mode"`                 // TLS 模式，见 TLSMode*
+	CAPath     string `json:"caPath,omitempty"`     // CA 证书（PEM）路径，为空时使用系统根证书
+	ServerName string `json:"serverName,omitempty"` // 校验证书时使用的主机名，为空时使用连接地址
+}
+
+// 认证方式。
+const (
+	AuthMethodPassword = "password" // 用户名密码（默认）
+	AuthMethodKerberos = "kerberos" // Kerberos/GSSAPI，使用票据缓存或 keytab
+	AuthMethodLDAP     = "ldap"     // 由服务端转发到 LDAP 校验的明文密码
+	AuthMethodPAM      = "pam"      // 由服务端转发到 PAM 校验的明文密码
+)
+
+// AuthConfig 是密码以外的认证配置，Kerberos 字段为空时使用驱动与 krb5.conf 的默认值。
+type AuthConfig struct {
+	Method      string `json:"method"`                // 认证方式，见 AuthMethod*
+	Principal   string `json:"principal,omitempty"`   // Kerberos 主体，如 user@EXAMPLE.COM；为空时使用 User
+	Realm       string `json:"realm,omitempty"`       // Kerberos 域
+	ServiceName string `json:"serviceName,omitempty"` // 服务主体名称（SPN）或服务名，如 postgres、MSSQLSvc/host:1433
+	KeytabPath  string `json:"keytabPath,omitempty"`  // keytab 文件，为空时使用票据缓存
+	CachePath   string `json:"cachePath,omitempty"`   // 票据缓存文件，为空时自动探测
+	ConfigPath  string `json:"configPath,omitempty"`  // krb5.conf 路径，为空时使用系统默认
+}
+
+// 多主机连接的选择策略。
+const (
+	HostPolicyFirstAvailable = "first-available" // 按主库、备用主机的顺序使用第一个可用的主机（默认）
+	HostPolicyRoundRobin     = "round-robin"     // 写操作同 first-available，只读查询在可用主机间轮询
+)
+
+// 只读查询的路由，配置了备用主机时生效。
+const (
+	RouteAuto    = ""        // 按主机策略选择
+	RoutePrimary = "primary" // 只读查询也使用主库（主库不可达时仍会切换）
+	RouteReplica = "replica" // 只读查询优先使用备用主机，备用主机均不可达时回落到主库
+)
+
+// HostAddress 是多主机连接中的一个主机地址。
+type HostAddress struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// String 返回 host:port 形式的地址。
+func (h HostAddress) String() string {
+	return net.JoinHostPort(h.Host, strconv.Itoa(h.Port))
 }
 
 // QueryResult 是查询结果的结构体
 // 包含查询是否成功、消息、数据和字段列表等信息
 type QueryResult struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
-	Fields  []string    `json:"fields"`
+	Success   bool        `json:"success"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data"`
+	Fields    []string    `json:"fields"`
+	Truncated bool        `json:"truncated,omitempty"` // 结果行数达到上限被截断
+
+	DurationMs   int64 `json:"durationMs,omitempty"`   // 执行耗时（毫秒），仅查询执行类方法填写
+	RowsReturned int   `json:"rowsReturned,omitempty"` // 返回的行数
+	RowsAffected int64 `json:"rowsAffected,omitempty"` // 受影响的行数（写操作）
+	Retries      int   `json:"retries,omitempty"`      // 遇到瞬时错误后的重试次数
+
+	FieldMeta []FieldMeta `json:"fieldMeta,omitempty"` // 与 Fields 一一对应的列类型信息，驱动无法提供时为空
+
+	ResultSets []*ResultSet `json:"resultSets,omitempty"` // 存储过程或多语句批处理返回的全部结果集，按返回顺序排列
+}
+
+// ResultSet 是一次调用返回的多个结果集中的一个。
+type ResultSet struct {
+	Index     int                      `json:"index"` // 在全部结果集中的序号（从 0 开始）
+	Fields    []string                 `json:"fields"`
+	FieldMeta []FieldMeta              `json:"fieldMeta,omitempty"`
+	Rows      []map[string]interface{} `json:"rows"`
+	Truncated bool                     `json:"truncated,omitempty"` // 行数达到上限被截断
+}
+
+// 结果列的类型大类，供前端决定对齐方式、渲染与过滤控件
+const (
+	FieldKindNumber   = "number"
+	FieldKindString   = "string"
+	FieldKindDateTime = "datetime"
+	FieldKindBoolean  = "boolean"
+	FieldKindBinary   = "binary"
+	FieldKindJSON     = "json"
+	FieldKindOther    = "other"
+)
+
+// BlobRef 代替超大二进制值出现在查询结果中，完整内容通过 DBFetchCellValue 按需读取
+type BlobRef struct {
+	Blob     bool   `json:"$blob"`    // 恒为 true，前端据此区分引用与普通值
+	Handle   string `json:"handle"`   // 读取完整内容使用的句柄
+	Size     int64  `json:"size"`     // 原始字节数
+	MimeType string `json:"mimeType"` // 按内容嗅探的 MIME 类型
+}
+
+// FieldMeta 描述查询结果中一列的类型信息，取自驱动的 ColumnTypes；驱动未提供的项为 nil
+type FieldMeta struct {
+	Name      string `json:"name"`                // 列名
+	Type      string `json:"type"`                // 数据库类型名，如 VARCHAR、DECIMAL、TIMESTAMPTZ
+	Kind      string `json:"kind"`                // 类型大类，见 FieldKind* 常量
+	Nullable  *bool  `json:"nullable,omitempty"`  // 是否可为空
+	Length    *int64 `json:"length,omitempty"`    // 变长类型的长度
+	Precision *int64 `json:"precision,omitempty"` // 数值精度
+	Scale     *int64 `json:"scale,omitempty"`     // 数值小数位数
+	Table     string `json:"table,omitempty"`     // 来源表名，仅单表查询中直接引用的列填写
+}
+
+// ExecOptions 是单次执行的覆盖参数，零值表示沿用连接配置
+type ExecOptions struct {
+	TimeoutSeconds int    `json:"timeoutSeconds"`  // 执行超时（秒），0 表示使用连接配置的超时
+	MaxRows        int    `json:"maxRows"`         // 查询返回的最大行数，0 表示不限制
+	FetchSize      int    `json:"fetchSize"`       // 流式读取时每批获取的行数提示，0 表示驱动默认
+	Route          string `json:"route,omitempty"` // 覆盖连接配置的只读查询路由（primary/replica），写语句始终使用主库
+}
+
+// 存储过程参数方向
+const (
+	ProcedureParamIn    = "in"
+	ProcedureParamOut   = "out"
+	ProcedureParamInOut = "inout"
+)
+
+// ProcedureParam 是调用存储过程时按位置传入的参数
+type ProcedureParam struct {
+	Name  string      `json:"name"`  // 参数名，用于在结果中标识输出值
+	Mode  string      `json:"mode"`  // 参数方向（ProcedureParam*），为空按 in 处理
+	Value interface{} `json:"value"` // in/inout 参数的输入值
+}
+
+// ProcedureResult 是存储过程调用的结果：依次返回的结果集与 out/inout 参数的输出值
+type ProcedureResult struct {
+	Call       string                 `json:"call"` // 实际执行的调用语句
+	ResultSets []*ResultSet           `json:"resultSets"`
+	Outputs    map[string]interface{} `json:"outputs"`
 }
 
 // ColumnDefinition 是数据库列的定义结构体
@@ -77,6 +245,13 @@ type ColumnDefinition struct {
 	Default  *string `json:"default"`
 	Extra    string  `json:"extra"` // auto_increment
 	Comment  string  `json:"comment"`
+	// EnumValues 为 MySQL ENUM/SET 列的可选值，按定义顺序排列；SET 列允许以逗号组合多个值
+	EnumValues []string `json:"enumValues,omitempty"`
+	// IsGenerated 表示列值由表达式计算（MySQL 虚拟/存储生成列、PostgreSQL GENERATED ALWAYS AS），不可写入
+	IsGenerated          bool   `json:"isGenerated"`
+	GenerationExpression string `json:"generationExpression"`
+	// DefaultExpression 为非字面量的默认值表达式（如 CURRENT_TIMESTAMP、nextval(...)），字面量默认值时为空
+	DefaultExpression string `json:"defaultExpression"`
 }
 
 // IndexDefinition 是数据库索引的定义结构体
@@ -108,6 +283,158 @@ type TriggerDefinition struct {
 	Statement string `json:"statement"`
 }
 
+// MaterializedViewDefinition 是 PostgreSQL 物化视图的定义结构体
+// Populated 为 false 表示视图以 WITH NO DATA 创建或尚未刷新，查询前需要 REFRESH
+type MaterializedViewDefinition struct {
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	Populated  bool   `json:"populated"`
+	HasIndexes bool   `json:"hasIndexes"`
+	Definition string `json:"definition"`
+}
+
+// SequenceDefinition 是 PostgreSQL 序列的定义结构体
+// LastValue 为 nil 表示序列尚未调用过 nextval
+type SequenceDefinition struct {
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	DataType   string `json:"dataType"`
+	StartValue int64  `json:"startValue"`
+	MinValue   int64  `json:"minValue"`
+	MaxValue   int64  `json:"maxValue"`
+	Increment  int64  `json:"increment"`
+	CacheSize  int64  `json:"cacheSize"`
+	Cycle      bool   `json:"cycle"`
+	LastValue  *int64 `json:"lastValue"`
+	OwnedBy    string `json:"ownedBy"`
+}
+
+// 自定义类型种类
+const (
+	CustomTypeEnum      = "enum"
+	CustomTypeDomain    = "domain"
+	CustomTypeComposite = "composite"
+)
+
+// CustomTypeAttribute 是复合类型的字段定义
+type CustomTypeAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CustomTypeDefinition 是 PostgreSQL 自定义类型的定义结构体
+// Kind 为 enum 时 Values 为枚举标签；domain 时 BaseType、NotNull、Default、Constraints 有效；composite 时 Attributes 有效
+type CustomTypeDefinition struct {
+	Schema      string                 `json:"schema"`
+	Name        string                 `json:"name"`
+	Kind        string                 `json:"kind"`
+	Values      []string               `json:"values"`
+	BaseType    string                 `json:"baseType"`
+	NotNull     bool                   `json:"notNull"`
+	Default     *string                `json:"default"`
+	Constraints []string               `json:"constraints"`
+	Attributes  []*CustomTypeAttribute `json:"attributes"`
+	Comment     string                 `json:"comment"`
+}
+
+// DBUser 是数据库账号的定义结构体
+// Host 仅 MySQL 使用；PostgreSQL 的账号即角色，CanLogin 区分登录用户与组角色
+type DBUser struct {
+	User            string `json:"user"`
+	Host            string `json:"host"`
+	Plugin          string `json:"plugin"`
+	Locked          bool   `json:"locked"`
+	PasswordExpired bool   `json:"passwordExpired"`
+	Superuser       bool   `json:"superuser"`
+	CanLogin        bool   `json:"canLogin"`
+}
+
+// UserSpec 是创建数据库账号的参数，Host 为空时 MySQL 使用 '%'
+type UserSpec struct {
+	User     string `json:"user"`
+	Host     string `json:"host"`
+	Password string `json:"password"`
+}
+
+// GrantSpec 是授予权限的参数
+// Database 为空表示全局权限；Table 为空表示整个数据库（PostgreSQL 中 Schema 与 Table 共同定位表）
+type GrantSpec struct {
+	User            string   `json:"user"`
+	Host            string   `json:"host"`
+	Privileges      []string `json:"privileges"`
+	Database        string   `json:"database"`
+	Schema          string   `json:"schema"`
+	Table           string   `json:"table"`
+	WithGrantOption bool     `json:"withGrantOption"`
+}
+
+// ServerMetric 是服务器状态或配置变量的一项，Category 用于前端分组展示
+type ServerMetric struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Unit        string `json:"unit"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+}
+
+// ServerMetrics 是按分类整理后的服务器状态或变量，Categories 为分类的展示顺序
+type ServerMetrics struct {
+	Categories []string        `json:"categories"`
+	Items      []*ServerMetric `json:"items"`
+}
+
+// LatencyStats 是一组往返耗时的统计，单位毫秒
+type LatencyStats struct {
+	Samples  int     `json:"samples"`
+	Failures int     `json:"failures"`
+	MinMs    float64 `json:"minMs"`
+	AvgMs    float64 `json:"avgMs"`
+	P95Ms    float64 `json:"p95Ms"`
+	MaxMs    float64 `json:"maxMs"`
+}
+
+// BenchmarkReport 是连接测速结果
+// TCPConnectMs 为直连时到数据库端口的建连耗时，SSHHandshakeMs 为经跳板机时 SSH 握手与认证耗时，
+// TLSHandshakeMs 为直连且启用 TLS 时协商加密的握手耗时，未测量时为 0
+type BenchmarkReport struct {
+	TCPConnectMs     float64       `json:"tcpConnectMs,omitempty"`
+	SSHHandshakeMs   float64       `json:"sshHandshakeMs,omitempty"`
+	TLSHandshakeMs   float64       `json:"tlsHandshakeMs,omitempty"`
+	Ping             *LatencyStats `json:"ping"`
+	Query            *LatencyStats `json:"query"`
+	QueriesPerSecond float64       `json:"queriesPerSecond"`
+	Errors           []string      `json:"errors,omitempty"`
+}
+
+// ProcessInfo 是数据库服务器上的一个会话/连接
+// Self 表示该会话是读取进程列表时所用的连接
+type ProcessInfo struct {
+	ID              int64  `json:"id"`
+	User            string `json:"user"`
+	Host            string `json:"host"`
+	Database        string `json:"database"`
+	Command         string `json:"command"`
+	State           string `json:"state"`
+	DurationSeconds int64  `json:"durationSeconds"`
+	SQL             string `json:"sql"`
+	Self            bool   `json:"self"`
+}
+
+// TableStats 是表的存储统计信息
+// Rows 为估算值（MySQL 取自 information_schema，PostgreSQL 取自 pg_class.reltuples）；
+// UpdateTime 为最近一次数据变更时间，数据库未记录时为空
+type TableStats struct {
+	Name       string `json:"name"`
+	Engine     string `json:"engine"`
+	Rows       int64  `json:"rows"`
+	DataSize   int64  `json:"dataSize"`
+	IndexSize  int64  `json:"indexSize"`
+	TotalSize  int64  `json:"totalSize"`
+	UpdateTime string `json:"updateTime"`
+	Comment    string `json:"comment"`
+}
+
 // ColumnDefinitionWithTable 是包含表名的列定义结构体
 // 用于查询整个数据库的列信息时，包含所属表名以区分不同表的同名列
 type ColumnDefinitionWithTable struct {
@@ -130,3 +457,395 @@ type ChangeSet struct {
 	Updates []UpdateRow              `json:"updates"`
 	Deletes []map[string]interface{} `json:"deletes"`
 }
+
+// 变更类型，用于在部分提交报告中标识失败的变更
+const (
+	ChangeKindInsert = "insert"
+	ChangeKindUpdate = "update"
+	ChangeKindDelete = "delete"
+)
+
+// ApplyOptions 是批量应用变更的参数
+type ApplyOptions struct {
+	PartialCommit bool `json:"partialCommit"` // 以保存点逐个应用变更，提交成功的变更并报告失败项；默认任一失败整体回滚
+}
+
+// ApplyChangeError 是部分提交模式下单个变更的失败信息
+type ApplyChangeError struct {
+	Kind    string `json:"kind"`    // 变更类型：insert/update/delete
+	Index   int    `json:"index"`   // 在 ChangeSet 对应列表中的下标（从 0 开始）
+	Message string `json:"message"` // 失败原因
+}
+
+// ApplyReport 是部分提交模式的结果报告
+type ApplyReport struct {
+	Total     int                 `json:"total"`     // 变更总数
+	Applied   int                 `json:"applied"`   // 应用成功的变更数
+	Failed    int                 `json:"failed"`    // 失败的变更数
+	Committed bool                `json:"committed"` // 事务是否已提交
+	Errors    []*ApplyChangeError `json:"errors"`    // 失败明细
+}
+
+// TableDefinition 是建表定义，列与索引复用 ColumnDefinition/IndexDefinition
+type TableDefinition struct {
+	Name       string              `json:"name"`                 // 表名
+	Columns    []*ColumnDefinition `json:"columns"`              // 列定义，Key 为 PRI 的列组成主键（未指定 PrimaryKey 时）
+	PrimaryKey []string            `json:"primaryKey,omitempty"` // 主键列，按顺序
+	Indexes    []*IndexDefinition  `json:"indexes,omitempty"`    // 二级索引，同名记录按 SeqInIndex 组成复合索引
+	Comment    string              `json:"comment,omitempty"`    // 表注释
+}
+
+// ColumnRename 是列重命名
+type ColumnRename struct {
+	From string `json:"from"` // 原列名
+	To   string `json:"to"`   // 新列名
+}
+
+// TableAlteration 是修改表结构的变更集合，按删除、重命名、修改、新增的顺序生成语句
+type TableAlteration struct {
+	Table         string              `json:"table"`                   // 表名
+	AddColumns    []*ColumnDefinition `json:"addColumns,omitempty"`    // 新增列
+	ModifyColumns []*ColumnDefinition `json:"modifyColumns,omitempty"` // 修改列（按列名匹配，需给出完整定义）
+	RenameColumns []ColumnRename      `json:"renameColumns,omitempty"` // 重命名列
+	DropColumns   []string            `json:"dropColumns,omitempty"`   // 删除列
+	RenameTo      string              `json:"renameTo,omitempty"`      // 重命名表
+	Comment       *string             `json:"comment,omitempty"`       // 修改表注释
+}
+
+// 分区方式
+const (
+	PartitionMethodRange = "range"
+	PartitionMethodList  = "list"
+	PartitionMethodHash  = "hash"
+)
+
+// PartitionInfo 是单个分区的元数据
+// Rows 为估算值，来源与 TableStats 相同；MySQL 子分区以 Subpartition 区分，同一分区的每个子分区各占一条
+type PartitionInfo struct {
+	Name         string `json:"name"`                   // 分区名（PostgreSQL 为子表名）
+	Schema       string `json:"schema,omitempty"`       // 子表所在 schema，仅 PostgreSQL
+	Subpartition string `json:"subpartition,omitempty"` // 子分区名，仅 MySQL
+	Position     int    `json:"position"`               // 分区序号，从 1 开始
+	Bound        string `json:"bound"`                  // 分区边界，如 VALUES LESS THAN (2024)、FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')
+	Rows         int64  `json:"rows"`                   // 估算行数
+	DataSize     int64  `json:"dataSize"`               // 数据大小（字节）
+	IndexSize    int64  `json:"indexSize"`              // 索引大小（字节）
+	Comment      string `json:"comment,omitempty"`      // 分区注释
+}
+
+// TablePartitioning 是表的分区方案，Method 为空表示表未分区
+type TablePartitioning struct {
+	Table      string           `json:"table"`      // 表名
+	Method     string           `json:"method"`     // 分区方式：range、list、hash（MySQL 的 KEY 分区归为 hash）
+	Expression string           `json:"expression"` // 分区键表达式或列
+	Partitions []*PartitionInfo `json:"partitions"` // 分区列表，按序号排列
+}
+
+// PartitionSpec 是新增分区的定义
+// 边界值为字面量：数字、MINVALUE/MAXVALUE 与 TO_DAYS('2024-01-01') 等日期函数原样输出，其余按字符串引用
+type PartitionSpec struct {
+	Name      string   `json:"name"`                // 分区名（PostgreSQL 为新建子表名）
+	Method    string   `json:"method"`              // 分区方式，需与表的分区方式一致
+	From      []string `json:"from,omitempty"`      // range 下界，仅 PostgreSQL；多列分区键按列给出
+	To        []string `json:"to,omitempty"`        // range 上界（不含），MySQL 对应 VALUES LESS THAN
+	Values    []string `json:"values,omitempty"`    // list 分区的取值
+	Modulus   int      `json:"modulus,omitempty"`   // hash 分区的模数，仅 PostgreSQL
+	Remainder int      `json:"remainder,omitempty"` // hash 分区的余数，仅 PostgreSQL
+	Default   bool     `json:"default,omitempty"`   // 默认分区，仅 PostgreSQL
+}
+
+// TableKey 是定位表中单行所用的键
+type TableKey struct {
+	Kind    string   `json:"kind"`           // 键类型：primary（主键）、unique（非空唯一索引）、none（无可用键）
+	Name    string   `json:"name,omitempty"` // 索引名
+	Columns []string `json:"columns"`        // 键列，按索引内顺序
+}
+
+// ExportOptions 是自定义导出的参数结构体
+// 支持导出任意查询结果、选择列、附加过滤条件与行数限制，并控制 NULL 与日期的输出格式
+type ExportOptions struct {
+	Format      string   `json:"format"`                // 导出格式：csv、json、md、ndjson、parquet、xlsx
+	Query       string   `json:"query,omitempty"`       // 自定义查询语句，为空时导出 TableName 全表
+	TableName   string   `json:"tableName,omitempty"`   // 导出的表名（Query 为空时必填）
+	Columns     []string `json:"columns,omitempty"`     // 导出的列，为空表示全部列
+	Where       string   `json:"where,omitempty"`       // 附加过滤条件（不含 WHERE 关键字）
+	Limit       int      `json:"limit,omitempty"`       // 最大导出行数，<=0 表示不限制
+	NullValue   *string  `json:"nullValue,omitempty"`   // NULL 的文本表示，未设置时为 "NULL"（Excel 中留空）
+	DateFormat  string   `json:"dateFormat,omitempty"`  // 日期格式，如 yyyy-MM-dd HH:mm:ss，为空时保持原样
+	Route       string   `json:"route,omitempty"`       // 覆盖连接配置的只读查询路由（primary/replica）
+	Compression string   `json:"compression,omitempty"` // 压缩算法（ndjson：none/gzip；parquet：snappy/none/gzip/zstd），为空时使用格式默认值
+	InferSchema *bool    `json:"inferSchema,omitempty"` // 按列值推断列类型（parquet），未设置时默认开启
+}
+
+// ColumnDefault 作为导入单元格的值时表示使用列默认值，插入语句中该位置写 DEFAULT 而不绑定参数
+type ColumnDefault struct{}
+
+// ImportOptions 是数据导入的参数结构体
+type ImportOptions struct {
+	BatchSize      int  `json:"batchSize,omitempty"`      // 每条 INSERT 语句包含的行数，<=0 使用默认值
+	DryRun         bool `json:"dryRun,omitempty"`         // 试运行：在事务内执行插入后整体回滚，仅返回校验报告
+	SkipFailedRows bool `json:"skipFailedRows,omitempty"` // 跳过失败行并提交其余行；默认任一行失败即整体回滚
+	MaxErrors      int  `json:"maxErrors,omitempty"`      // 报告中最多记录的失败行数，<=0 使用默认值
+}
+
+// ImportColumnRule 是导入时单列的类型转换规则
+type ImportColumnRule struct {
+	DateFormat  string   `json:"dateFormat,omitempty"`  // 日期/时间格式，如 dd/MM/yyyy HH:mm，为空时自动识别常见格式
+	TrueValues  []string `json:"trueValues,omitempty"`  // 视为真的文本（布尔列），为空时使用内置取值
+	FalseValues []string `json:"falseValues,omitempty"` // 视为假的文本（布尔列），为空时使用内置取值
+	NullMarkers []string `json:"nullMarkers,omitempty"` // 视为 NULL 的文本，如 "", "N/A", "-"
+	Trim        bool     `json:"trim,omitempty"`        // 是否去除首尾空白
+}
+
+// ImportExecuteRequest 是按预览结果执行导入的请求
+type ImportExecuteRequest struct {
+	PreviewID string                       `json:"previewId"`       // DBImportPreview 返回的预览 ID
+	Sheet     string                       `json:"sheet,omitempty"` // Excel 工作表，为空时读取第一个工作表
+	Mapping   map[string]string            `json:"mapping"`         // 文件列 -> 表列，未映射或映射为空的列将被忽略
+	Rules     map[string]*ImportColumnRule `json:"rules,omitempty"` // 表列 -> 类型转换规则
+	Options   *ImportOptions               `json:"options,omitempty"`
+}
+
+// ImportPreview 是导入预览结果
+type ImportPreview struct {
+	PreviewID        string                   `json:"previewId"`        // 预览 ID，执行导入时回传
+	FileName         string                   `json:"fileName"`         // 文件名（不含目录）
+	Format           string                   `json:"format"`           // 文件格式
+	Sheets           []string                 `json:"sheets,omitempty"` // Excel 工作表列表
+	Sheet            string                   `json:"sheet,omitempty"`  // 当前预览的工作表
+	Headers          []string                 `json:"headers"`          // 文件列名
+	SampleRows       []map[string]interface{} `json:"sampleRows"`       // 样例行
+	TotalRows        int                      `json:"totalRows"`        // 文件总行数
+	TableColumns     []*ColumnDefinition      `json:"tableColumns"`     // 目标表列定义
+	SuggestedMapping map[string]string        `json:"suggestedMapping"` // 按列名推荐的映射
+}
+
+// ImportRowError 是导入失败行的描述
+type ImportRowError struct {
+	Row     int    `json:"row"`              // 数据行号（从 1 开始，不含表头）
+	Column  string `json:"column,omitempty"` // 出错的列（字段解析错误时填写）
+	Message string `json:"message"`          // 失败原因
+}
+
+// ImportReport 是数据导入的结果报告
+type ImportReport struct {
+	Total      int               `json:"total"`      // 待导入总行数
+	Inserted   int               `json:"inserted"`   // 在事务内插入成功的行数
+	Failed     int               `json:"failed"`     // 插入失败的行数
+	Batches    int               `json:"batches"`    // 执行的批次数
+	DryRun     bool              `json:"dryRun"`     // 是否为试运行
+	Committed  bool              `json:"committed"`  // 事务是否已提交
+	Errors     []*ImportRowError `json:"errors"`     // 失败行明细（最多 MaxErrors 条）
+	DurationMs int64             `json:"durationMs"` // 导入耗时（毫秒）
+}
+
+// CursorInfo 是结果集游标的描述
+type CursorInfo struct {
+	CursorID string   `json:"cursorId"` // 游标 ID
+	Columns  []string `json:"columns"`  // 结果集列名
+}
+
+// CursorWindow 是游标按区间读取的结果窗口
+type CursorWindow struct {
+	CursorID string                   `json:"cursorId"` // 游标 ID
+	Start    int                      `json:"start"`    // 窗口起始行号（从 0 开始）
+	Rows     []map[string]interface{} `json:"rows"`     // 窗口内的行
+	EOF      bool                     `json:"eof"`      // 是否已读到结果集末尾
+	Total    int                      `json:"total"`    // 总行数，读到末尾前为 -1
+}
+
+// TableSort 是表数据浏览的排序条件
+type TableSort struct {
+	Column string `json:"column"` // 排序列
+	Desc   bool   `json:"desc"`   // 是否降序
+}
+
+// TableFilter 是表数据浏览的过滤条件，编译为参数化 WHERE 子句
+type TableFilter struct {
+	Column string        `json:"column"`           // 过滤列
+	Path   string        `json:"path,omitempty"`   // JSON 列内的路径（如 $.address.city、$.tags[0]），为空时比较整列
+	Op     string        `json:"op"`               // 运算符：eq/ne/gt/gte/lt/lte/like/notLike/contains/startsWith/endsWith/in/notIn/between/isNull/notNull
+	Value  interface{}   `json:"value,omitempty"`  // 单值运算符的比较值
+	Values []interface{} `json:"values,omitempty"` // in/notIn/between 的比较值列表
+}
+
+// FilterPredicate 是由过滤条件生成的参数化 WHERE 条件
+type FilterPredicate struct {
+	SQL  string `json:"sql"`  // 条件表达式（不含 WHERE）
+	Args []any  `json:"args"` // 参数，顺序与占位符一致
+}
+
+// JSONKeyPath 是 JSON 列采样中出现的一个键路径
+type JSONKeyPath struct {
+	Path      string         `json:"path"`      // 路径，数组元素记为 [*]，如 $.tags[*]
+	Types     map[string]int `json:"types"`     // 各值类型出现的行数：object/array/string/number/boolean/null
+	Count     int            `json:"count"`     // 包含该路径的行数
+	Frequency float64        `json:"frequency"` // Count 占有效样本行数的比例
+}
+
+// JSONKeyReport 是 JSON 列的键路径采样结果
+type JSONKeyReport struct {
+	Column    string        `json:"column"`
+	Sampled   int           `json:"sampled"`   // 采样的非空行数
+	Invalid   int           `json:"invalid"`   // 不是合法 JSON 的行数
+	Paths     []JSONKeyPath `json:"paths"`     // 按出现次数倒序、路径正序
+	Truncated bool          `json:"truncated"` // 路径数量超过上限被截断
+}
+
+// 批量修改的操作类型
+const (
+	BulkActionUpdate = "update"
+	BulkActionDelete = "delete"
+)
+
+// BulkUpdateRequest 是按过滤条件批量更新或删除的参数，过滤条件与表数据浏览相同
+type BulkUpdateRequest struct {
+	Action   string                 `json:"action"`            // 操作类型：update/delete
+	Set      map[string]interface{} `json:"set,omitempty"`     // update 时的列与新值，nil 值写入 NULL
+	Filters  []TableFilter          `json:"filters,omitempty"` // 过滤条件
+	MatchAny bool                   `json:"matchAny"`          // 过滤条件之间使用 OR 连接（默认 AND）
+	AllowAll bool                   `json:"allowAll"`          // 允许不带过滤条件修改整表
+}
+
+// BulkUpdatePreview 是批量修改的预览：生成的语句与按相同条件统计的受影响行数
+type BulkUpdatePreview struct {
+	SQL          string `json:"sql"`          // 将执行的参数化语句
+	Args         []any  `json:"args"`         // 语句参数
+	CountSQL     string `json:"countSql"`     // 统计受影响行数的语句
+	AffectedRows int64  `json:"affectedRows"` // 预计受影响的行数
+}
+
+// TableDataOptions 是表数据浏览的分页、排序与过滤参数
+type TableDataOptions struct {
+	Page     int           `json:"page"`              // 页码（从 1 开始）
+	PageSize int           `json:"pageSize"`          // 每页行数
+	Sorts    []TableSort   `json:"sorts,omitempty"`   // 排序条件，按顺序生效
+	Filters  []TableFilter `json:"filters,omitempty"` // 过滤条件
+	MatchAny bool          `json:"matchAny"`          // 为 true 时过滤条件以 OR 组合，否则以 AND 组合
+
+	// Computed 为追加在表列之后的计算列，可用于过滤与排序（键分页时不能作为排序列）
+	Computed []ComputedColumn `json:"computed,omitempty"`
+
+	// Keyset 为 true 时使用键分页：按排序列（自动追加主键保证顺序唯一）定位，忽略 Page，
+	// After 为上一页返回的 NextAfter，为空时读取第一页
+	Keyset bool          `json:"keyset,omitempty"`
+	After  []interface{} `json:"after,omitempty"`
+}
+
+// TableDataPage 是表数据浏览的单页结果
+type TableDataPage struct {
+	Columns  []string                 `json:"columns"`  // 列名
+	Rows     []map[string]interface{} `json:"rows"`     // 当前页的行
+	Total    int64                    `json:"total"`    // 满足过滤条件的总行数，键分页时不计数，为 -1
+	Page     int                      `json:"page"`     // 页码
+	PageSize int                      `json:"pageSize"` // 每页行数
+
+	SeekColumns []string      `json:"seekColumns,omitempty"` // 键分页的定位列，与 NextAfter 一一对应
+	NextAfter   []interface{} `json:"nextAfter,omitempty"`   // 读取下一页所用的 After（原始值，可含 NULL），为空表示没有下一页
+
+	ComputedColumns []string `json:"computedColumns,omitempty"` // 结果中的计算列，不可编辑
+}
+
+// ComputedColumn 是表数据浏览中的计算列，Expression 为基于本表列的标量表达式，如 CONCAT(first, ' ', last)
+type ComputedColumn struct {
+	Name       string `json:"name"`       // 结果中的列名，不能与表列重名
+	Expression string `json:"expression"` // 表达式，只允许列引用、字面量、运算符、CASE 与常用标量函数
+}
+
+// DiffQuerySide 是结果集比较中一侧的查询，两侧可以来自不同连接
+type DiffQuerySide struct {
+	Config *ConnectionConfig `json:"config"` // 连接配置
+	DBName string            `json:"dbName"` // 数据库名（可选）
+	Query  string            `json:"query"`  // SELECT 类查询
+	Args   []any             `json:"args"`   // 查询参数
+}
+
+// FederatedSource 是联邦查询的一个输入：在指定连接上执行查询，结果以 Alias 为表名载入本地引擎
+type FederatedSource struct {
+	Alias  string            `json:"alias"`  // 本地表名，在关联查询中引用
+	Config *ConnectionConfig `json:"config"` // 连接配置
+	DBName string            `json:"dbName"` // 数据库名（可选）
+	Query  string            `json:"query"`  // SELECT 类查询
+	Args   []any             `json:"args"`   // 查询参数
+}
+
+// FederatedQueryRequest 是跨连接联邦查询的请求
+type FederatedQueryRequest struct {
+	Sources       []*FederatedSource `json:"sources"`       // 各输入结果集
+	Query         string             `json:"query"`         // 在本地引擎中执行的关联查询，按 Alias 引用各输入
+	MaxRows       int                `json:"maxRows"`       // 每个输入读取的最大行数，超过时报错而不是截断，<=0 时取默认值
+	MaxResultRows int                `json:"maxResultRows"` // 返回的最大行数，<=0 时取默认值
+}
+
+// DataSyncSide 是数据同步中一侧的表，两侧可以来自不同连接
+type DataSyncSide struct {
+	Config *ConnectionConfig `json:"config"` // 连接配置
+	DBName string            `json:"dbName"` // 数据库名（可选）
+	Table  string            `json:"table"`  // 表名，可带 schema 前缀
+}
+
+// DataSyncRequest 是按键比较两侧同名表并使目标表与源表一致的请求
+type DataSyncRequest struct {
+	Source      *DataSyncSide `json:"source"`                // 源表
+	Target      *DataSyncSide `json:"target"`                // 目标表，生成的语句在此执行
+	KeyColumns  []string      `json:"keyColumns,omitempty"`  // 匹配行的键列，为空时使用目标表的主键或非空唯一索引
+	Columns     []string      `json:"columns,omitempty"`     // 参与同步的列，为空表示两侧共有的全部列
+	MaxRows     int           `json:"maxRows,omitempty"`     // 每侧读取的最大行数，<=0 时取默认值；超出时只能比较不能同步
+	SkipInserts bool          `json:"skipInserts,omitempty"` // 不插入目标表缺少的行
+	SkipUpdates bool          `json:"skipUpdates,omitempty"` // 不更新内容不同的行
+	SkipDeletes bool          `json:"skipDeletes,omitempty"` // 不删除源表不存在的行
+}
+
+// DataSyncPlan 是数据同步的比较结果与待执行语句
+// Statements 为内联了取值的 SQL 文本，仅用于预览；实际执行使用参数化语句
+type DataSyncPlan struct {
+	KeyColumns     []string `json:"keyColumns"`     // 实际使用的键列
+	Columns        []string `json:"columns"`        // 参与同步的列
+	SourceRows     int      `json:"sourceRows"`     // 源表读取行数
+	TargetRows     int      `json:"targetRows"`     // 目标表读取行数
+	Inserts        int      `json:"inserts"`        // 待插入行数
+	Updates        int      `json:"updates"`        // 待更新行数
+	Deletes        int      `json:"deletes"`        // 待删除行数
+	Unchanged      int      `json:"unchanged"`      // 内容一致的行数
+	DuplicateKeys  int      `json:"duplicateKeys"`  // 重复键行数，存在时不能同步
+	Truncated      bool     `json:"truncated"`      // 任一侧超过读取上限，存在时不能同步
+	Statements     []string `json:"statements"`     // 预览语句，最多列出 1000 条
+	StatementsMore int      `json:"statementsMore"` // 未列出的语句条数
+	Applied        bool     `json:"applied"`        // 是否已在目标表执行
+}
+
+// ChartRequest 是图表聚合请求，数据源为 Query 或 Table 之一，在数据库端分组聚合后只返回绘图所需的数据
+type ChartRequest struct {
+	Query       string `json:"query,omitempty"`       // 数据源 SELECT 查询，与 Table 二选一
+	Table       string `json:"table,omitempty"`       // 数据源表名，与 Query 二选一
+	GroupBy     string `json:"groupBy"`               // 分组列（X 轴）
+	TimeBucket  string `json:"timeBucket,omitempty"`  // 时间分桶：minute/hour/day/week/month/year，为空时按原值分组
+	SeriesBy    string `json:"seriesBy,omitempty"`    // 拆分系列的列（可选）
+	Aggregate   string `json:"aggregate"`             // 聚合函数：count/countDistinct/sum/avg/min/max
+	ValueColumn string `json:"valueColumn,omitempty"` // 聚合列，count 时为空表示 COUNT(*)
+	SortByValue bool   `json:"sortByValue,omitempty"` // 按聚合值降序排列（仅无系列时），默认按分组值升序
+	MaxLabels   int    `json:"maxLabels,omitempty"`   // 最多返回的分组数，<=0 时取默认值
+	MaxSeries   int    `json:"maxSeries,omitempty"`   // 最多返回的系列数，<=0 时取默认值
+}
+
+// ChartSeries 是图表中的一个系列，Data 与 ChartData.Labels 一一对应，缺失的分组为 null
+type ChartSeries struct {
+	Name string     `json:"name"`
+	Data []*float64 `json:"data"`
+}
+
+// ChartData 是图表聚合结果
+type ChartData struct {
+	Labels    []string      `json:"labels"`    // 分组标签（X 轴）
+	Series    []ChartSeries `json:"series"`    // 系列，未指定 SeriesBy 时只有一个
+	Truncated bool          `json:"truncated"` // 分组或系列超过上限被截断
+}
+
+// ForeignKeyRef 是按约束聚合后的外键，复合外键的 Columns 与 RefColumns 按位置一一对应
+type ForeignKeyRef struct {
+	Name       string   `json:"name"`       // 约束名
+	Table      string   `json:"table"`      // 定义外键的子表
+	Columns    []string `json:"columns"`    // 子表中的外键列
+	RefTable   string   `json:"refTable"`   // 被引用的父表
+	RefColumns []string `json:"refColumns"` // 父表中被引用的列
+}
@@ -0,0 +1,353 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPrefetchRows 默认在可见窗口之后预取的行数。
+	DefaultPrefetchRows = 200
+	// DefaultMaxFetchRows 默认单次读取的最大行数。
+	DefaultMaxFetchRows = 1000
+	// DefaultMaxCursors 默认同时打开的游标上限（每个游标占用一个数据库连接）。
+	DefaultMaxCursors = 4
+	// DefaultIdleTimeout 默认游标空闲超时。
+	DefaultIdleTimeout = 5 * time.Minute
+)
+
+// ErrCursorNotFound 游标不存在或已关闭。
+var ErrCursorNotFound = errors.New("游标不存在或已关闭")
+
+// Source 顺序读取的行源，通常由服务端游标提供。
+type Source interface {
+	Columns() []string
+	Next() (map[string]interface{}, error) // 读完时返回 io.EOF
+	Close() error
+}
+
+// Opener 打开（或重新打开）行源；向前回滚超出缓冲区或读取中途出错时会被再次调用。
+type Opener func(ctx context.Context) (Source, error)
+
+// Options 游标管理参数。
+type Options struct {
+	PrefetchRows int           // 可见窗口之后预取的行数，同时作为向前回滚的保留行数
+	MaxFetchRows int           // 单次读取的最大行数
+	MaxCursors   int           // 同时打开的游标上限，超出时关闭最久未使用的游标
+	IdleTimeout  time.Duration // 空闲超时，超时的游标会被自动关闭
+}
+
+// DefaultOptions 返回默认游标管理参数。
+func DefaultOptions() Options {
+	return Options{
+		PrefetchRows: DefaultPrefetchRows,
+		MaxFetchRows: DefaultMaxFetchRows,
+		MaxCursors:   DefaultMaxCursors,
+		IdleTimeout:  DefaultIdleTimeout,
+	}
+}
+
+// normalize 对非法参数回退默认值。
+func (o Options) normalize() Options {
+	if o.PrefetchRows < 0 {
+		o.PrefetchRows = DefaultPrefetchRows
+	}
+	if o.MaxFetchRows <= 0 {
+		o.MaxFetchRows = DefaultMaxFetchRows
+	}
+	if o.MaxCursors <= 0 {
+		o.MaxCursors = DefaultMaxCursors
+	}
+	if o.IdleTimeout <= 0 {
+		o.IdleTimeout = DefaultIdleTimeout
+	}
+	return o
+}
+
+// Manager 管理结果集游标：OpenCursor → FetchRange → CloseCursor。
+// 每个游标只在内存中保留当前窗口及少量预取行，数据表格可借此滚动浏览超大结果集。
+type Manager struct {
+	mu      sync.Mutex
+	logger  *slog.Logger
+	opts    Options
+	cursors map[string]*Cursor
+	now     func() time.Time
+}
+
+// NewManager 创建游标管理器。
+func NewManager(logger *slog.Logger, opts Options) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{
+		logger:  logger.With("module", "cursor"),
+		opts:    opts.normalize(),
+		cursors: make(map[string]*Cursor),
+		now:     time.Now,
+	}
+}
+
+// Open 打开游标，返回游标 ID 与列名。ctx 需覆盖游标的整个生命周期。
+func (m *Manager) Open(ctx context.Context, opener Opener) (*connection.CursorInfo, error) {
+	m.evict()
+
+	src, err := opener(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cursor{
+		id:       uuid.New().String(),
+		ctx:      ctx,
+		opener:   opener,
+		opts:     m.opts,
+		src:      src,
+		columns:  src.Columns(),
+		total:    -1,
+		lastUsed: m.now(),
+	}
+
+	m.mu.Lock()
+	m.cursors[c.id] = c
+	m.mu.Unlock()
+
+	m.logger.Debug("打开游标", "cursorId", c.id, "columns", len(c.columns))
+	return &connection.CursorInfo{CursorID: c.id, Columns: c.columns}, nil
+}
+
+// FetchRange 读取 [start, start+count) 区间的行，count 超过上限时会被截断。
+func (m *Manager) FetchRange(id string, start, count int) (*connection.CursorWindow, error) {
+	if start < 0 || count <= 0 {
+		return nil, fmt.Errorf("无效的读取区间: start=%d count=%d", start, count)
+	}
+	m.mu.Lock()
+	c, ok := m.cursors[id]
+	if ok {
+		c.lastUsed = m.now()
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrCursorNotFound
+	}
+	return c.fetch(start, min(count, m.opts.MaxFetchRows))
+}
+
+// Close 关闭游标并释放连接。
+func (m *Manager) Close(id string) error {
+	m.mu.Lock()
+	c, ok := m.cursors[id]
+	delete(m.cursors, id)
+	m.mu.Unlock()
+	if !ok {
+		return ErrCursorNotFound
+	}
+	m.logger.Debug("关闭游标", "cursorId", id)
+	return c.close()
+}
+
+// CloseAll 关闭所有游标。
+func (m *Manager) CloseAll() {
+	m.mu.Lock()
+	cursors := m.cursors
+	m.cursors = make(map[string]*Cursor)
+	m.mu.Unlock()
+	for _, c := range cursors {
+		c.close()
+	}
+}
+
+// Count 返回当前打开的游标数。
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.cursors)
+}
+
+// evict 关闭空闲超时的游标，并在达到上限时关闭最久未使用的游标，为新游标腾出连接。
+func (m *Manager) evict() {
+	m.mu.Lock()
+	now := m.now()
+	var victims []*Cursor
+	for id, c := range m.cursors {
+		if now.Sub(c.lastUsed) > m.opts.IdleTimeout {
+			victims = append(victims, c)
+			delete(m.cursors, id)
+		}
+	}
+	if overflow := len(m.cursors) - m.opts.MaxCursors + 1; overflow > 0 {
+		ordered := make([]*Cursor, 0, len(m.cursors))
+		for _, c := range m.cursors {
+			ordered = append(ordered, c)
+		}
+		sort.Slice(ordered, func(i, j int) bool { return ordered[i].lastUsed.Before(ordered[j].lastUsed) })
+		for _, c := range ordered[:overflow] {
+			victims = append(victims, c)
+			delete(m.cursors, c.id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, c := range victims {
+		m.logger.Info("回收游标", "cursorId", c.id)
+		c.close()
+	}
+}
+
+// Cursor 单个结果集游标：顺序读取行源，仅缓冲 [bufStart, bufStart+len(buf)) 区间的行。
+type Cursor struct {
+	mu       sync.Mutex
+	id       string
+	ctx      context.Context
+	opener   Opener
+	opts     Options
+	src      Source
+	columns  []string
+	pos      int                      // 行源中下一行的行号
+	buf      []map[string]interface{} // 缓冲的行
+	bufStart int                      // 缓冲区首行的行号
+	eof      bool                     // 行源是否已读完
+	total    int                      // 总行数，读完前为 -1
+	lastUsed time.Time
+}
+
+// fetch 读取区间内的行：向前回滚超出缓冲区时重新打开行源并跳过前面的行。
+func (c *Cursor) fetch(start, count int) (*connection.CursorWindow, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.src == nil {
+		return nil, ErrCursorNotFound
+	}
+	if start < c.bufStart {
+		if err := c.reopen(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 丢弃窗口之前超出保留范围的行
+	keepFrom := max(start-c.opts.PrefetchRows, c.bufStart)
+	if drop := keepFrom - c.bufStart; drop > 0 {
+		if drop >= len(c.buf) {
+			c.buf = nil
+			c.bufStart = c.pos
+		} else {
+			c.buf = append([]map[string]interface{}(nil), c.buf[drop:]...)
+			c.bufStart = keepFrom
+		}
+	}
+
+	// 读到窗口末尾加预取行为止
+	target := start + count + c.opts.PrefetchRows
+	resumed := false
+	for !c.eof && c.pos < target {
+		row, err := c.src.Next()
+		if err == io.EOF {
+			c.eof = true
+			c.total = c.pos
+			break
+		}
+		if err != nil {
+			// 长时间未读取时服务端可能已断开流（如 MySQL net_write_timeout），重新打开一次并从当前位置继续
+			if resumed || c.ctx.Err() != nil {
+				return nil, err
+			}
+			resumed = true
+			if err := c.resume(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if c.pos >= keepFrom {
+			if len(c.buf) == 0 {
+				c.bufStart = c.pos
+			}
+			c.buf = append(c.buf, row)
+		}
+		c.pos++
+	}
+
+	window := &connection.CursorWindow{
+		CursorID: c.id,
+		Start:    start,
+		Rows:     make([]map[string]interface{}, 0, count),
+		EOF:      c.eof && start+count >= c.pos,
+		Total:    c.total,
+	}
+	from := start - c.bufStart
+	to := min(from+count, len(c.buf))
+	if from >= 0 && from < to {
+		window.Rows = append(window.Rows, c.buf[from:to]...)
+	}
+	return window, nil
+}
+
+// reopen 重新打开行源并重置读取状态。
+func (c *Cursor) reopen() error {
+	c.src.Close()
+	src, err := c.opener(c.ctx)
+	if err != nil {
+		c.src = nil
+		return fmt.Errorf("重新打开游标失败：%w", err)
+	}
+	c.src = src
+	c.pos = 0
+	c.buf = nil
+	c.bufStart = 0
+	c.eof = false
+	return nil
+}
+
+// resume 重新打开行源并跳过已读取的 pos 行，保留缓冲区，使读取从中断处继续。
+func (c *Cursor) resume() error {
+	c.src.Close()
+	src, err := c.opener(c.ctx)
+	if err != nil {
+		c.src = nil
+		return fmt.Errorf("重新打开游标失败：%w", err)
+	}
+	c.src = src
+	for i := 0; i < c.pos; i++ {
+		if _, err := src.Next(); err != nil {
+			if err == io.EOF {
+				err = errors.New("结果集已变化")
+			}
+			return fmt.Errorf("重新打开游标后定位到第 %d 行失败：%w", c.pos, err)
+		}
+	}
+	return nil
+}
+
+// close 关闭行源。
+func (c *Cursor) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.src == nil {
+		return nil
+	}
+	err := c.src.Close()
+	c.src = nil
+	c.buf = nil
+	return err
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeSource 生成 0..n-1 行的模拟行源，failAt > 0 时读到第 failAt 行返回错误。
+type fakeSource struct {
+	n      int
+	pos    int
+	failAt int
+	closed bool
+}
+
+func (s *fakeSource) Columns() []string { return []string{"id"} }
+
+func (s *fakeSource) Next() (map[string]interface{}, error) {
+	if s.pos >= s.n {
+		return nil, io.EOF
+	}
+	if s.failAt > 0 && s.pos == s.failAt {
+		return nil, errors.New("invalid connection")
+	}
+	row := map[string]interface{}{"id": s.pos}
+	s.pos++
+	return row, nil
+}
+
+func (s *fakeSource) Close() error { s.closed = true; return nil }
+
+// fakeOpener 记录打开次数的行源工厂。
+type fakeOpener struct {
+	n       int
+	failAt  []int // 第 i 次打开的行源在 failAt[i] 行出错
+	opens   int
+	sources []*fakeSource
+}
+
+func (o *fakeOpener) open(context.Context) (Source, error) {
+	src := &fakeSource{n: o.n}
+	if o.opens < len(o.failAt) {
+		src.failAt = o.failAt[o.opens]
+	}
+	o.opens++
+	o.sources = append(o.sources, src)
+	return src, nil
+}
+
+func TestFetchRangeStreamsWindow(t *testing.T) {
+	m := NewManager(nil, Options{PrefetchRows: 10, MaxFetchRows: 50})
+	op := &fakeOpener{n: 1000}
+	info, err := m.Open(context.Background(), op.open)
+	if err != nil {
+		t.Fatalf("打开游标失败: %v", err)
+	}
+
+	win, err := m.FetchRange(info.CursorID, 500, 20)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(win.Rows) != 20 || win.Rows[0]["id"] != 500 || win.Rows[19]["id"] != 519 {
+		t.Fatalf("窗口数据错误: %d 行", len(win.Rows))
+	}
+	if win.EOF || win.Total != -1 {
+		t.Errorf("未读到末尾时不应返回 EOF: %+v", win)
+	}
+
+	c := m.cursors[info.CursorID]
+	if len(c.buf) > 20+2*10 {
+		t.Errorf("缓冲行数应限制在窗口加预取范围内，得到 %d", len(c.buf))
+	}
+
+	// 窗口内向前小幅回滚不需要重新打开
+	if _, err := m.FetchRange(info.CursorID, 495, 10); err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if op.opens != 1 {
+		t.Errorf("保留范围内回滚不应重新打开，打开次数 %d", op.opens)
+	}
+
+	// 超出缓冲区的回滚需要重新打开
+	win, err = m.FetchRange(info.CursorID, 0, 5)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if op.opens != 2 || !op.sources[0].closed || win.Rows[0]["id"] != 0 {
+		t.Errorf("回滚后应重新打开并从头读取，打开次数 %d", op.opens)
+	}
+}
+
+func TestFetchRangeReachesEOF(t *testing.T) {
+	m := NewManager(nil, Options{PrefetchRows: 5, MaxFetchRows: 100})
+	op := &fakeOpener{n: 30}
+	info, _ := m.Open(context.Background(), op.open)
+
+	win, err := m.FetchRange(info.CursorID, 20, 50)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(win.Rows) != 10 || !win.EOF || win.Total != 30 {
+		t.Errorf("末尾窗口不正确: rows=%d eof=%v total=%d", len(win.Rows), win.EOF, win.Total)
+	}
+
+	win, _ = m.FetchRange(info.CursorID, 40, 10)
+	if len(win.Rows) != 0 || !win.EOF {
+		t.Errorf("超出末尾应返回空窗口: %+v", win)
+	}
+}
+
+func TestFetchRangeResumesAfterStreamError(t *testing.T) {
+	m := NewManager(nil, Options{PrefetchRows: 5, MaxFetchRows: 100})
+	op := &fakeOpener{n: 100, failAt: []int{30}}
+	info, _ := m.Open(context.Background(), op.open)
+
+	if _, err := m.FetchRange(info.CursorID, 0, 20); err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	win, err := m.FetchRange(info.CursorID, 20, 20)
+	if err != nil {
+		t.Fatalf("流中断后应重新打开并继续读取: %v", err)
+	}
+	if op.opens != 2 || !op.sources[0].closed {
+		t.Errorf("应重新打开一次，打开次数 %d", op.opens)
+	}
+	if len(win.Rows) != 20 || win.Rows[0]["id"] != 20 || win.Rows[19]["id"] != 39 {
+		t.Errorf("续读的窗口数据错误: %v", win.Rows)
+	}
+
+	// 重新打开后仍出错时返回错误
+	op = &fakeOpener{n: 100, failAt: []int{10, 5}}
+	info, _ = m.Open(context.Background(), op.open)
+	if _, err := m.FetchRange(info.CursorID, 0, 20); err == nil {
+		t.Error("重新打开后定位失败应返回错误")
+	}
+}
+
+func TestMaxFetchRowsAndClose(t *testing.T) {
+	m := NewManager(nil, Options{MaxFetchRows: 10})
+	op := &fakeOpener{n: 100}
+	info, _ := m.Open(context.Background(), op.open)
+
+	win, _ := m.FetchRange(info.CursorID, 0, 1000)
+	if len(win.Rows) != 10 {
+		t.Errorf("单次读取应截断到上限，得到 %d", len(win.Rows))
+	}
+
+	if err := m.Close(info.CursorID); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+	if !op.sources[0].closed {
+		t.Error("关闭游标应关闭行源")
+	}
+	if _, err := m.FetchRange(info.CursorID, 0, 1); err != ErrCursorNotFound {
+		t.Errorf("已关闭游标应返回 ErrCursorNotFound，得到 %v", err)
+	}
+}
+
+func TestEvictLeastRecentlyUsedAndIdle(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewManager(nil, Options{MaxCursors: 2, IdleTimeout: time.Minute})
+	m.now = func() time.Time { return now }
+
+	op := &fakeOpener{n: 10}
+	first, _ := m.Open(context.Background(), op.open)
+	now = now.Add(time.Second)
+	second, _ := m.Open(context.Background(), op.open)
+	now = now.Add(time.Second)
+	m.FetchRange(first.CursorID, 0, 1) // first 变为最近使用
+
+	m.Open(context.Background(), op.open)
+	if _, ok := m.cursors[second.CursorID]; ok {
+		t.Error("达到上限时应回收最久未使用的游标")
+	}
+	if m.Count() != 2 {
+		t.Errorf("期望 2 个游标，得到 %d", m.Count())
+	}
+
+	now = now.Add(2 * time.Minute)
+	m.Open(context.Background(), op.open)
+	if m.Count() != 1 {
+		t.Errorf("空闲超时的游标应被回收，剩余 %d", m.Count())
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// CompressionNone 不压缩。
+	CompressionNone = "none"
+	// CompressionGzip gzip 压缩。
+	CompressionGzip = "gzip"
+	// CompressionSnappy snappy 压缩（Parquet 列块压缩）。
+	CompressionSnappy = "snappy"
+	// CompressionZstd zstd 压缩（Parquet 列块压缩）。
+	CompressionZstd = "zstd"
+)
+
+// Options 是单次导出的格式参数，各格式只读取自身支持的项。
+type Options struct {
+	Formatter   *ValueFormatter   // 值格式化器，为 nil 时使用默认设置
+	SheetName   string            // 工作表名称（xlsx）
+	Compression string            // 压缩算法，为空时使用格式默认值，可选值见 FormatInfo.Compressions
+	InferSchema bool              // 按列值推断列类型（parquet），关闭时全部列按文本写出
+	Progress    func(written int) // 写出数据行后调用，written 为已写出的行数；为 nil 时不汇报
+}
+
+// formatter 返回导出使用的值格式化器。
+func (o *Options) formatter() *ValueFormatter {
+	if o == nil || o.Formatter == nil {
+		return NewValueFormatter(nil, "")
+	}
+	return o.Formatter
+}
+
+// report 汇报已写出的行数。
+func (o *Options) report(written int) {
+	if o != nil && o.Progress != nil {
+		o.Progress(written)
+	}
+}
+
+// compression 返回导出使用的压缩算法，未指定时取 def。
+func (o *Options) compression(def string) string {
+	if o == nil || strings.TrimSpace(o.Compression) == "" {
+		return def
+	}
+	return strings.ToLower(strings.TrimSpace(o.Compression))
+}
+
+// FormatInfo 描述一种导出格式及其可选参数，供导出对话框展示。
+type FormatInfo struct {
+	Name                string   `json:"name"`                   // 格式名，即 ExportOptions.Format 的取值
+	Label               string   `json:"label"`                  // 展示名称
+	Extension           string   `json:"extension"`              // 默认文件扩展名（不含点）
+	Compressions        []string `json:"compressions,omitempty"` // 支持的压缩算法，首项为默认值；为空表示不支持压缩
+	SupportsInferSchema bool     `json:"supportsInferSchema"`    // 是否支持推断列类型
+}
+
+// Exporter 将结果集写入一种导出格式。
+type Exporter interface {
+	// Info 返回格式描述。
+	Info() FormatInfo
+	// Write 将表头与数据行写入 w，opts 可为 nil。
+	Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error
+}
+
+var (
+	exportersMu sync.RWMutex
+	exporters   = map[string]Exporter{}
+)
+
+// Register 注册导出格式，格式名重复时覆盖已有注册。
+func Register(e Exporter) {
+	name := strings.ToLower(e.Info().Name)
+	exportersMu.Lock()
+	defer exportersMu.Unlock()
+	exporters[name] = e
+}
+
+// Lookup 按格式名（不区分大小写）查找导出格式。
+func Lookup(format string) (Exporter, bool) {
+	exportersMu.RLock()
+	defer exportersMu.RUnlock()
+	e, ok := exporters[strings.ToLower(strings.TrimSpace(format))]
+	return e, ok
+}
+
+// Supported 判断格式是否已注册。
+func Supported(format string) bool {
+	_, ok := Lookup(format)
+	return ok
+}
+
+// Formats 返回全部已注册格式的描述，按格式名排序。
+func Formats() []FormatInfo {
+	exportersMu.RLock()
+	defer exportersMu.RUnlock()
+	infos := make([]FormatInfo, 0, len(exporters))
+	for _, e := range exporters {
+		infos = append(infos, e.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// CheckOptions 校验导出参数是否适用于指定格式。
+func CheckOptions(format string, opts *Options) error {
+	e, ok := Lookup(format)
+	if !ok {
+		return fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	info := e.Info()
+	if opts == nil || strings.TrimSpace(opts.Compression) == "" {
+		return nil
+	}
+	c := opts.compression("")
+	for _, allowed := range info.Compressions {
+		if c == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s 格式不支持压缩算法: %s", info.Name, opts.Compression)
+}
+
+// fileExtensioner 由扩展名随导出参数变化的格式实现。
+type fileExtensioner interface {
+	FileExtension(opts *Options) string
+}
+
+// FileExtension 返回导出文件的扩展名（不含点），如 gzip 压缩的 NDJSON 为 ndjson.gz。
+func FileExtension(format string, opts *Options) string {
+	e, ok := Lookup(format)
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(format))
+	}
+	if fe, ok := e.(fileExtensioner); ok {
+		return fe.FileExtension(opts)
+	}
+	return e.Info().Extension
+}
+
+func init() {
+	Register(csvExporter{})
+	Register(jsonExporter{})
+	Register(markdownExporter{})
+	Register(ndjsonExporter{})
+	Register(xlsxExporter{})
+	Register(parquetExporter{})
+}
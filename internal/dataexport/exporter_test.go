@@ -0,0 +1,134 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+var sampleColumns = []string{"id", "name"}
+
+var sampleRows = []map[string]interface{}{
+	{"id": int64(1), "name": "a|b"},
+	{"id": int64(2), "name": nil},
+}
+
+func TestRegisteredFormats(t *testing.T) {
+	names := []string{}
+	for _, info := range Formats() {
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "csv,json,md,ndjson,parquet,xlsx" {
+		t.Fatalf("Formats() = %s", got)
+	}
+	if !Supported("CSV") || Supported("xml") {
+		t.Fatal("Supported() 大小写或未注册格式判断错误")
+	}
+}
+
+func TestCheckOptions(t *testing.T) {
+	tests := []struct {
+		format      string
+		compression string
+		ok          bool
+	}{
+		{"csv", "", true},
+		{"csv", "gzip", false},
+		{"ndjson", "GZIP", true},
+		{"ndjson", "zstd", false},
+		{"parquet", "zstd", true},
+		{"xml", "", false},
+	}
+	for _, tt := range tests {
+		err := CheckOptions(tt.format, &Options{Compression: tt.compression})
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckOptions(%s, %s) error = %v", tt.format, tt.compression, err)
+		}
+	}
+	if got := FileExtension("ndjson", &Options{Compression: "gzip"}); got != "ndjson.gz" {
+		t.Errorf("FileExtension(ndjson, gzip) = %s", got)
+	}
+	if got := FileExtension("parquet", &Options{Compression: "gzip"}); got != "parquet" {
+		t.Errorf("FileExtension(parquet, gzip) = %s", got)
+	}
+}
+
+func TestTextExporters(t *testing.T) {
+	empty := ""
+	opts := &Options{Formatter: NewValueFormatter(&empty, "")}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", "\ufeffid,name\n1,a|b\n2,\n"},
+		{"md", "| id | name |\n| --- | --- |\n| 1 | a\\|b |\n| 2 |  |\n"},
+		{"json", "[\n{\n    \"id\": 1,\n    \"name\": \"a|b\"\n  }\n,\n{\n    \"id\": 2,\n    \"name\": null\n  }\n]\n"},
+		{"ndjson", "{\"id\":1,\"name\":\"a|b\"}\n{\"id\":2,\"name\":null}\n"},
+	}
+	for _, tt := range tests {
+		e, _ := Lookup(tt.format)
+		var buf bytes.Buffer
+		if err := e.Write(&buf, sampleColumns, sampleRows, opts); err != nil {
+			t.Fatalf("%s Write() error = %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s Write() = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestNDJSONGzip(t *testing.T) {
+	e, _ := Lookup("ndjson")
+	var buf bytes.Buffer
+	if err := e.Write(&buf, sampleColumns, sampleRows, &Options{Compression: CompressionGzip}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("解压后行数 = %d, want 2", lines)
+	}
+}
+
+func TestExportersReportProgress(t *testing.T) {
+	for _, info := range Formats() {
+		t.Run(info.Name, func(t *testing.T) {
+			e, _ := Lookup(info.Name)
+			last := 0
+			opts := &Options{Progress: func(written int) {
+				if written <= last {
+					t.Errorf("进度应递增: %d 之后得到 %d", last, written)
+				}
+				last = written
+			}}
+			if err := e.Write(io.Discard, sampleColumns, sampleRows, opts); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if last != len(sampleRows) {
+				t.Errorf("最终进度 = %d，期望 %d", last, len(sampleRows))
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultNullValue 导出文本格式中 NULL 的默认表示。
+const DefaultNullValue = "NULL"
+
+// dateTokenReplacer 将常见日期模板转换为 Go 时间布局（长 token 优先）。
+var dateTokenReplacer = strings.NewReplacer(
+	"yyyy", "2006",
+	"yy", "06",
+	"MM", "01",
+	"dd", "02",
+	"HH", "15",
+	"hh", "03",
+	"mm", "04",
+	"ss", "05",
+	"SSS", "000",
+)
+
+// ValueFormatter 导出值格式化器，统一处理 NULL 表示与日期格式。
+type ValueFormatter struct {
+	nullValue  string // NULL 的文本表示
+	nullSet    bool   // 调用方是否显式指定了 NULL 表示
+	dateLayout string // Go 时间布局，为空时保持默认输出
+}
+
+// NewValueFormatter 创建导出值格式化器；nullValue 为 nil 时使用 DefaultNullValue。
+func NewValueFormatter(nullValue *string, dateFormat string) *ValueFormatter {
+	f := &ValueFormatter{nullValue: DefaultNullValue}
+	if nullValue != nil {
+		f.nullValue, f.nullSet = *nullValue, true
+	}
+	if strings.TrimSpace(dateFormat) != "" {
+		f.dateLayout = ConvertDateFormat(dateFormat)
+	}
+	return f
+}
+
+// NullValue 返回 NULL 的文本表示。
+func (f *ValueFormatter) NullValue() string {
+	return f.nullValue
+}
+
+// ExplicitNull 返回调用方显式指定的 NULL 表示；未指定时 ok 为 false，由格式自行决定（如 Excel 留空单元格）。
+func (f *ValueFormatter) ExplicitNull() (string, bool) {
+	return f.nullValue, f.nullSet
+}
+
+// Text 将值格式化为文本（CSV/Markdown 使用）。
+func (f *ValueFormatter) Text(v interface{}) string {
+	if v == nil {
+		return f.nullValue
+	}
+	if t, ok := v.(time.Time); ok && f.dateLayout != "" {
+		return t.Format(f.dateLayout)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Value 按日期格式转换值，保留 NULL 与其他类型（JSON 使用）。
+func (f *ValueFormatter) Value(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok && f.dateLayout != "" {
+		return t.Format(f.dateLayout)
+	}
+	return v
+}
+
+// ConvertDateFormat 将 yyyy-MM-dd HH:mm:ss 风格的模板转换为 Go 时间布局；
+// 已是 Go 布局（含 2006）时原样返回。
+func ConvertDateFormat(pattern string) string {
+	if strings.Contains(pattern, "2006") {
+		return pattern
+	}
+	return dateTokenReplacer.Replace(pattern)
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// parquetBatchRows 每批写入 Parquet 的行数。
+const parquetBatchRows = 1024
+
+// parquetKind 是推断出的 Parquet 列类型。
+type parquetKind int
+
+const (
+	parquetUnknown parquetKind = iota // 全部为 NULL，按文本写出
+	parquetInt
+	parquetFloat
+	parquetBool
+	parquetTime
+	parquetBytes
+	parquetString
+)
+
+// parquetCodecs 压缩算法名到 Parquet 编解码器的映射。
+var parquetCodecs = map[string]compress.Codec{
+	CompressionNone:   &parquet.Uncompressed,
+	CompressionSnappy: &parquet.Snappy,
+	CompressionGzip:   &parquet.Gzip,
+	CompressionZstd:   &parquet.Zstd,
+}
+
+// parquetExporter 导出 Parquet 文件，全部列均为可空列。
+// 推断列类型时整数、浮点、布尔、时间（微秒时间戳）与二进制按原类型写出，类型混杂的列退化为文本；
+// 关闭推断时全部列按文本写出并应用日期格式。
+type parquetExporter struct{}
+
+func (parquetExporter) Info() FormatInfo {
+	return FormatInfo{
+		Name:                "parquet",
+		Label:               "Parquet",
+		Extension:           "parquet",
+		Compressions:        []string{CompressionSnappy, CompressionNone, CompressionGzip, CompressionZstd},
+		SupportsInferSchema: true,
+	}
+}
+
+func (parquetExporter) Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	codec, ok := parquetCodecs[opts.compression(CompressionSnappy)]
+	if !ok {
+		return fmt.Errorf("parquet 格式不支持压缩算法: %s", opts.Compression)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("结果集没有列，无法导出 Parquet")
+	}
+	formatter := opts.formatter()
+
+	kinds := make([]parquetKind, len(columns))
+	for i := range kinds {
+		kinds[i] = parquetString
+	}
+	if opts != nil && opts.InferSchema {
+		for i, col := range columns {
+			kinds[i] = inferParquetKind(rows, col)
+		}
+	}
+
+	group := make(parquet.Group, len(columns))
+	for i, col := range columns {
+		if _, dup := group[col]; dup {
+			return fmt.Errorf("列名重复，无法导出 Parquet: %s", col)
+		}
+		group[col] = parquet.Optional(parquetNode(kinds[i]))
+	}
+	schema := parquet.NewSchema("boxify_export", group)
+	// Group 按列名排序生成叶子列，记录每个结果列对应的叶子下标
+	leaves := make([]int, len(columns))
+	for i, col := range columns {
+		leaf, ok := schema.Lookup(col)
+		if !ok {
+			return fmt.Errorf("无法定位 Parquet 列: %s", col)
+		}
+		leaves[i] = leaf.ColumnIndex
+	}
+
+	pw := parquet.NewWriter(w, schema, parquet.Compression(codec))
+	batch := make([]parquet.Row, 0, parquetBatchRows)
+	written := 0
+	for _, data := range rows {
+		row := make(parquet.Row, len(columns))
+		for i, col := range columns {
+			v := data[col]
+			if v == nil {
+				row[leaves[i]] = parquet.NullValue().Level(0, 0, leaves[i])
+				continue
+			}
+			row[leaves[i]] = parquetValue(kinds[i], v, formatter).Level(0, 1, leaves[i])
+		}
+		if batch = append(batch, row); len(batch) == parquetBatchRows {
+			if _, err := pw.WriteRows(batch); err != nil {
+				return err
+			}
+			written += len(batch)
+			opts.report(written)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if _, err := pw.WriteRows(batch); err != nil {
+			return err
+		}
+		opts.report(written + len(batch))
+	}
+	return pw.Close()
+}
+
+// parquetNode 返回列类型对应的 Parquet 叶子节点。
+func parquetNode(kind parquetKind) parquet.Node {
+	switch kind {
+	case parquetInt:
+		return parquet.Int(64)
+	case parquetFloat:
+		return parquet.Leaf(parquet.DoubleType)
+	case parquetBool:
+		return parquet.Leaf(parquet.BooleanType)
+	case parquetTime:
+		return parquet.Timestamp(parquet.Microsecond)
+	case parquetBytes:
+		return parquet.Leaf(parquet.ByteArrayType)
+	default:
+		return parquet.String()
+	}
+}
+
+// inferParquetKind 按列中非 NULL 值推断类型：整数与浮点混合时为浮点，其他混杂情况为文本。
+func inferParquetKind(rows []map[string]interface{}, col string) parquetKind {
+	kind := parquetUnknown
+	for _, row := range rows {
+		v := row[col]
+		if v == nil {
+			continue
+		}
+		k := parquetKindOf(v)
+		switch {
+		case kind == parquetUnknown || kind == k:
+			kind = k
+		case (kind == parquetInt && k == parquetFloat) || (kind == parquetFloat && k == parquetInt):
+			kind = parquetFloat
+		default:
+			return parquetString
+		}
+	}
+	if kind == parquetUnknown {
+		return parquetString
+	}
+	return kind
+}
+
+// parquetKindOf 返回单个值的 Parquet 列类型。
+func parquetKindOf(v interface{}) parquetKind {
+	switch x := v.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return parquetInt
+	case uint:
+		if uint64(x) > math.MaxInt64 {
+			return parquetString
+		}
+		return parquetInt
+	case uint64:
+		if x > math.MaxInt64 {
+			return parquetString
+		}
+		return parquetInt
+	case float32, float64:
+		return parquetFloat
+	case bool:
+		return parquetBool
+	case time.Time:
+		return parquetTime
+	case []byte:
+		return parquetBytes
+	default:
+		return parquetString
+	}
+}
+
+// parquetValue 将非 NULL 值转换为列类型对应的 Parquet 值。
+func parquetValue(kind parquetKind, v interface{}, formatter *ValueFormatter) parquet.Value {
+	switch kind {
+	case parquetInt:
+		return parquet.Int64Value(utils.ToInt64(v))
+	case parquetFloat:
+		return parquet.DoubleValue(utils.ToFloat64(v))
+	case parquetBool:
+		return parquet.BooleanValue(v.(bool))
+	case parquetTime:
+		return parquet.Int64Value(v.(time.Time).UnixMicro())
+	case parquetBytes:
+		return parquet.ByteArrayValue(v.([]byte))
+	default:
+		return parquet.ByteArrayValue([]byte(formatter.Text(v)))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// readParquet 读取 Parquet 数据，返回各列的物理类型、压缩算法与全部行。
+func readParquet(t *testing.T, data []byte) (map[string]parquet.Kind, format.CompressionCodec, []map[string]interface{}) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	kinds := map[string]parquet.Kind{}
+	for _, field := range f.Schema().Fields() {
+		kinds[field.Name()] = field.Type().Kind()
+	}
+	codec := f.Metadata().RowGroups[0].Columns[0].MetaData.Codec
+
+	reader := parquet.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+	var rows []map[string]interface{}
+	for {
+		row := map[string]interface{}{}
+		if err := reader.Read(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatalf("Read() error = %v", err)
+		}
+		rows = append(rows, row)
+	}
+	return kinds, codec, rows
+}
+
+func TestParquetExporter(t *testing.T) {
+	created := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	columns := []string{"id", "price", "active", "created", "note", "mixed"}
+	rows := []map[string]interface{}{
+		{"id": int64(1), "price": 9.5, "active": true, "created": created, "note": "x", "mixed": int64(1)},
+		{"id": int32(2), "price": int64(3), "active": nil, "created": nil, "note": nil, "mixed": "b"},
+	}
+	e, _ := Lookup("parquet")
+
+	tests := []struct {
+		name        string
+		opts        *Options
+		codec       format.CompressionCodec
+		kinds       map[string]parquet.Kind
+		wantCreated interface{}
+	}{
+		{
+			name:  "推断类型",
+			opts:  &Options{InferSchema: true, Compression: CompressionZstd},
+			codec: format.Zstd,
+			kinds: map[string]parquet.Kind{
+				"id": parquet.Int64, "price": parquet.Double, "active": parquet.Boolean,
+				"created": parquet.Int64, "note": parquet.ByteArray, "mixed": parquet.ByteArray,
+			},
+			wantCreated: created.UnixMicro(),
+		},
+		{
+			name:  "全部文本",
+			opts:  &Options{Formatter: NewValueFormatter(nil, "yyyy-MM-dd")},
+			codec: format.Snappy,
+			kinds: map[string]parquet.Kind{
+				"id": parquet.ByteArray, "price": parquet.ByteArray, "active": parquet.ByteArray,
+				"created": parquet.ByteArray, "note": parquet.ByteArray, "mixed": parquet.ByteArray,
+			},
+			wantCreated: "2026-03-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := e.Write(&buf, columns, rows, tt.opts); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			kinds, codec, got := readParquet(t, buf.Bytes())
+			if codec != tt.codec {
+				t.Errorf("codec = %v, want %v", codec, tt.codec)
+			}
+			for col, want := range tt.kinds {
+				if kinds[col] != want {
+					t.Errorf("列 %s 类型 = %v, want %v", col, kinds[col], want)
+				}
+			}
+			if len(got) != 2 {
+				t.Fatalf("行数 = %d, want 2", len(got))
+			}
+			if got[0]["created"] != tt.wantCreated {
+				t.Errorf("created = %#v, want %#v", got[0]["created"], tt.wantCreated)
+			}
+			if got[1]["note"] != nil || got[1]["active"] != nil {
+				t.Errorf("NULL 值未保留: %#v", got[1])
+			}
+		})
+	}
+}
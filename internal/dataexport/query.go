@@ -0,0 +1,75 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// exportSubqueryAlias 自定义查询包装为子查询时使用的别名。
+const exportSubqueryAlias = "boxify_export"
+
+// trailingSemicolons 匹配语句末尾的分号与空白。
+var trailingSemicolons = regexp.MustCompile(`[;\s]+$`)
+
+// BuildExportQuery 根据导出参数构造最终查询语句。
+// 自定义查询在需要选列/过滤/限制时包装为子查询，保证对任意 SELECT（含 JOIN、CTE）生效；
+// b 为当前方言的 SQL 构造器。
+func BuildExportQuery(opts *connection.ExportOptions, b db.SQLBuilder) (string, error) {
+	if opts == nil {
+		return "", fmt.Errorf("导出参数不能为空")
+	}
+
+	base := strings.TrimSpace(trailingSemicolons.ReplaceAllString(strings.TrimSpace(opts.Query), ""))
+	from := ""
+	switch {
+	case base != "":
+		if len(opts.Columns) == 0 && strings.TrimSpace(opts.Where) == "" && opts.Limit <= 0 {
+			return base, nil
+		}
+		from = fmt.Sprintf("(%s) %s", base, b.QuoteIdent(exportSubqueryAlias))
+	case strings.TrimSpace(opts.TableName) != "":
+		from = b.QuoteTable(strings.TrimSpace(opts.TableName))
+	default:
+		return "", fmt.Errorf("查询语句与表名不能同时为空")
+	}
+
+	selectList := "*"
+	if len(opts.Columns) > 0 {
+		cols := make([]string, 0, len(opts.Columns))
+		for _, col := range opts.Columns {
+			name := strings.TrimSpace(col)
+			if name == "" {
+				return "", fmt.Errorf("导出列名不能为空")
+			}
+			cols = append(cols, b.QuoteIdent(name))
+		}
+		selectList = strings.Join(cols, ", ")
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList, from)
+	if where := strings.TrimSpace(opts.Where); where != "" {
+		query += " WHERE " + where
+	}
+	if opts.Limit > 0 {
+		query += b.Limit(opts.Limit, false)
+	}
+	return query, nil
+}
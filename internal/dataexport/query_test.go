@@ -0,0 +1,102 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+func TestBuildExportQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *connection.ExportOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "自定义查询无附加条件时原样执行",
+			opts: &connection.ExportOptions{Query: "WITH t AS (SELECT 1) SELECT * FROM t;  "},
+			want: "WITH t AS (SELECT 1) SELECT * FROM t",
+		},
+		{
+			name: "自定义查询选列过滤限制",
+			opts: &connection.ExportOptions{Query: "SELECT * FROM a JOIN b ON a.id=b.id", Columns: []string{"id", "name"}, Where: "id > 10", Limit: 100},
+			want: "SELECT `id`, `name` FROM (SELECT * FROM a JOIN b ON a.id=b.id) `boxify_export` WHERE id > 10 LIMIT 100",
+		},
+		{
+			name: "表导出",
+			opts: &connection.ExportOptions{TableName: "users", Limit: 5},
+			want: "SELECT * FROM `users` LIMIT 5",
+		},
+		{
+			name:    "缺少查询与表名",
+			opts:    &connection.ExportOptions{},
+			wantErr: true,
+		},
+		{
+			name:    "空列名",
+			opts:    &connection.ExportOptions{TableName: "users", Columns: []string{" "}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildExportQuery(tt.opts, db.NewSQLBuilder(connection.ConnectionTypeMySQL))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildExportQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BuildExportQuery() = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueFormatter(t *testing.T) {
+	empty := ""
+	f := NewValueFormatter(&empty, "yyyy/MM/dd HH:mm")
+	ts := time.Date(2026, 3, 7, 9, 5, 0, 0, time.UTC)
+
+	if got := f.Text(nil); got != "" {
+		t.Errorf("自定义 NULL 表示失败: %q", got)
+	}
+	if got := f.Text(ts); got != "2026/03/07 09:05" {
+		t.Errorf("日期格式化失败: %q", got)
+	}
+	if got := f.Value(nil); got != nil {
+		t.Errorf("JSON 值应保留 nil: %v", got)
+	}
+
+	def := NewValueFormatter(nil, "")
+	if got := def.Text(nil); got != DefaultNullValue {
+		t.Errorf("默认 NULL 表示错误: %q", got)
+	}
+	if got := def.Value(ts); got != ts {
+		t.Errorf("未设置日期格式时应保持原值: %v", got)
+	}
+}
+
+func TestConvertDateFormat(t *testing.T) {
+	if got := ConvertDateFormat("yyyy-MM-dd HH:mm:ss.SSS"); got != "2006-01-02 15:04:05.000" {
+		t.Errorf("ConvertDateFormat() = %q", got)
+	}
+	if got := ConvertDateFormat(time.RFC3339); got != time.RFC3339 {
+		t.Errorf("Go 布局应原样返回: %q", got)
+	}
+}
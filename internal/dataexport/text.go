@@ -0,0 +1,174 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// utf8BOM 写在 CSV 开头，便于 Excel 正确识别 UTF-8 编码。
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// textRecord 将一行按列顺序格式化为文本字段。
+func textRecord(columns []string, row map[string]interface{}, formatter *ValueFormatter) []string {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = formatter.Text(row[col])
+	}
+	return record
+}
+
+// jsonRow 按日期格式转换一行的值，保留 NULL 与其他类型。
+func jsonRow(row map[string]interface{}, formatter *ValueFormatter) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		out[k] = formatter.Value(v)
+	}
+	return out
+}
+
+// csvExporter 导出带 UTF-8 BOM 的 CSV。
+type csvExporter struct{}
+
+func (csvExporter) Info() FormatInfo {
+	return FormatInfo{Name: "csv", Label: "CSV", Extension: "csv"}
+}
+
+func (csvExporter) Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	formatter := opts.formatter()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if err := cw.Write(textRecord(columns, row, formatter)); err != nil {
+			return err
+		}
+		opts.report(i + 1)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonExporter 导出 JSON 对象数组。
+type jsonExporter struct{}
+
+func (jsonExporter) Info() FormatInfo {
+	return FormatInfo{Name: "json", Label: "JSON", Extension: "json"}
+}
+
+func (jsonExporter) Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	formatter := opts.formatter()
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("  ", "  ")
+	for i, row := range rows {
+		if i > 0 {
+			if _, err := io.WriteString(w, ",\n"); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(jsonRow(row, formatter)); err != nil {
+			return err
+		}
+		opts.report(i + 1)
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// markdownExporter 导出 Markdown 表格，转义单元格中的竖线与换行。
+type markdownExporter struct{}
+
+func (markdownExporter) Info() FormatInfo {
+	return FormatInfo{Name: "md", Label: "Markdown", Extension: "md"}
+}
+
+func (markdownExporter) Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	formatter := opts.formatter()
+	seps := make([]string, len(columns))
+	for i := range seps {
+		seps[i] = "---"
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n| %s |\n", strings.Join(columns, " | "), strings.Join(seps, " | ")); err != nil {
+		return err
+	}
+	for n, row := range rows {
+		record := textRecord(columns, row, formatter)
+		for i, s := range record {
+			s = strings.ReplaceAll(s, "|", "\\|")
+			record[i] = strings.ReplaceAll(s, "\n", "<br>")
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(record, " | ")); err != nil {
+			return err
+		}
+		opts.report(n + 1)
+	}
+	return nil
+}
+
+// ndjsonExporter 导出每行一个 JSON 对象的 NDJSON，可整体 gzip 压缩。
+type ndjsonExporter struct{}
+
+func (ndjsonExporter) Info() FormatInfo {
+	return FormatInfo{
+		Name:         "ndjson",
+		Label:        "NDJSON",
+		Extension:    "ndjson",
+		Compressions: []string{CompressionNone, CompressionGzip},
+	}
+}
+
+func (ndjsonExporter) FileExtension(opts *Options) string {
+	if opts.compression(CompressionNone) == CompressionGzip {
+		return "ndjson.gz"
+	}
+	return "ndjson"
+}
+
+func (ndjsonExporter) Write(w io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	formatter := opts.formatter()
+	var gz *gzip.Writer
+	if opts.compression(CompressionNone) == CompressionGzip {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i, row := range rows {
+		if err := enc.Encode(jsonRow(row, formatter)); err != nil {
+			return err
+		}
+		opts.report(i + 1)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
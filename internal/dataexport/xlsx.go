@@ -0,0 +1,358 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// xlsxMaxSheetNameLen Excel 工作表名称最大长度。
+	xlsxMaxSheetNameLen = 31
+	// xlsxMinColWidth 自动列宽下限。
+	xlsxMinColWidth = 8
+	// xlsxMaxColWidth 自动列宽上限，避免超长文本撑爆列宽。
+	xlsxMaxColWidth = 60
+	// xlsxWidthSampleRows 计算列宽时采样的最大行数。
+	xlsxWidthSampleRows = 1000
+	// xlsxMaxSafeDigits 数值字符串转为数字单元格时允许的最大有效位数（超出会丢失精度）。
+	xlsxMaxSafeDigits = 15
+)
+
+// canonicalNumberPattern 匹配规范数值字符串（不含前导零，避免把编号/邮编误转为数字）。
+var canonicalNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// invalidSheetNameChars Excel 工作表名称中不允许出现的字符。
+var invalidSheetNameChars = strings.NewReplacer(
+	"[", "_", "]", "_", ":", "_", "*", "_", "?", "_", "/", "_", "\\", "_",
+)
+
+// XLSXWorkbook 基于 excelize 的 Excel 工作簿写入器，支持多工作表导出。
+type XLSXWorkbook struct {
+	file        *excelize.File
+	headerStyle int             // 表头样式 ID
+	dateStyle   int             // 日期时间样式 ID
+	sheetNames  map[string]bool // 已使用的工作表名称（小写，用于去重）
+	sheetCount  int             // 已写入工作表数量
+	formatter   *ValueFormatter // NULL 表示与日期格式，为空时 NULL 留空、日期按默认样式
+	progress    func(int)       // 每写入一行数据后调用，参数为当前工作表已写入的行数；为空时不汇报
+}
+
+// NewXLSXWorkbook 创建 Excel 工作簿并预置表头与日期样式。
+func NewXLSXWorkbook() (*XLSXWorkbook, error) {
+	f := excelize.NewFile()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "1F2937"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"E5E7EB"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "9CA3AF", Style: 1},
+		},
+	})
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("创建表头样式失败：%w", err)
+	}
+
+	dateFmt := "yyyy-mm-dd hh:mm:ss"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt})
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("创建日期样式失败：%w", err)
+	}
+
+	return &XLSXWorkbook{
+		file:        f,
+		headerStyle: headerStyle,
+		dateStyle:   dateStyle,
+		sheetNames:  make(map[string]bool),
+	}, nil
+}
+
+// SetFormatter 设置后续工作表使用的 NULL 表示与日期格式。
+// 指定日期格式时日期按该格式写为文本，未显式指定 NULL 表示时 NULL 仍留空。
+func (w *XLSXWorkbook) SetFormatter(f *ValueFormatter) {
+	w.formatter = f
+}
+
+// AddSheet 新增工作表并写入表头与数据行，返回实际使用的工作表名称。
+// 表头加粗并冻结首行，列宽按内容自动估算，单元格按值类型写入（数字/布尔/日期/文本）。
+func (w *XLSXWorkbook) AddSheet(name string, columns []string, rows []map[string]interface{}) (string, error) {
+	sheet := w.uniqueSheetName(name)
+
+	if w.sheetCount == 0 {
+		// 复用默认工作表，避免生成空白 Sheet1
+		if err := w.file.SetSheetName(w.file.GetSheetName(0), sheet); err != nil {
+			return "", fmt.Errorf("重命名工作表失败：%w", err)
+		}
+	} else if _, err := w.file.NewSheet(sheet); err != nil {
+		return "", fmt.Errorf("创建工作表失败：%w", err)
+	}
+	w.sheetCount++
+
+	sw, err := w.file.NewStreamWriter(sheet)
+	if err != nil {
+		return "", fmt.Errorf("创建工作表写入器失败：%w", err)
+	}
+
+	// 列宽必须在写入行之前设置
+	for i, width := range columnWidths(columns, rows) {
+		if err := sw.SetColWidth(i+1, i+1, width); err != nil {
+			return "", fmt.Errorf("设置列宽失败：%w", err)
+		}
+	}
+	if len(columns) > 0 {
+		if err := sw.SetPanes(&excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}); err != nil {
+			return "", fmt.Errorf("冻结表头失败：%w", err)
+		}
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = excelize.Cell{StyleID: w.headerStyle, Value: col}
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return "", fmt.Errorf("写入表头失败：%w", err)
+	}
+
+	for r, row := range rows {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = w.cellValue(row[col])
+		}
+		cell, err := excelize.CoordinatesToCellName(1, r+2)
+		if err != nil {
+			return "", err
+		}
+		if err := sw.SetRow(cell, values); err != nil {
+			return "", fmt.Errorf("写入第 %d 行失败：%w", r+1, err)
+		}
+		if w.progress != nil {
+			w.progress(r + 1)
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return "", fmt.Errorf("写入工作表失败：%w", err)
+	}
+
+	if len(columns) > 0 {
+		lastCol, _ := excelize.ColumnNumberToName(len(columns))
+		ref := fmt.Sprintf("A1:%s%d", lastCol, len(rows)+1)
+		if err := w.file.AutoFilter(sheet, ref, nil); err != nil {
+			return "", fmt.Errorf("设置筛选失败：%w", err)
+		}
+	}
+
+	return sheet, nil
+}
+
+// SaveAs 保存工作簿到指定路径。
+func (w *XLSXWorkbook) SaveAs(path string) error {
+	if w.sheetCount == 0 {
+		return fmt.Errorf("工作簿没有可保存的工作表")
+	}
+	w.file.SetActiveSheet(0)
+	return w.file.SaveAs(path)
+}
+
+// Write 将工作簿写入 out。
+func (w *XLSXWorkbook) Write(out io.Writer) error {
+	if w.sheetCount == 0 {
+		return fmt.Errorf("工作簿没有可保存的工作表")
+	}
+	w.file.SetActiveSheet(0)
+	return w.file.Write(out)
+}
+
+// Close 释放工作簿资源。
+func (w *XLSXWorkbook) Close() error {
+	return w.file.Close()
+}
+
+// xlsxExporter 导出单工作表的 Excel 工作簿，单元格按值类型写入。
+type xlsxExporter struct{}
+
+func (xlsxExporter) Info() FormatInfo {
+	return FormatInfo{Name: "xlsx", Label: "Excel", Extension: "xlsx"}
+}
+
+func (xlsxExporter) Write(out io.Writer, columns []string, rows []map[string]interface{}, opts *Options) error {
+	wb, err := NewXLSXWorkbook()
+	if err != nil {
+		return err
+	}
+	defer wb.Close()
+
+	name := ""
+	if opts != nil {
+		name = opts.SheetName
+		wb.SetFormatter(opts.Formatter)
+		wb.progress = opts.Progress
+	}
+	if _, err := wb.AddSheet(name, columns, rows); err != nil {
+		return err
+	}
+	return wb.Write(out)
+}
+
+// cellValue 将查询结果值转换为带类型的单元格值。
+func (w *XLSXWorkbook) cellValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		if w.formatter != nil {
+			if null, ok := w.formatter.ExplicitNull(); ok {
+				return null
+			}
+		}
+		return nil
+	case time.Time:
+		if w.formatter != nil && w.formatter.dateLayout != "" {
+			return val.Format(w.formatter.dateLayout)
+		}
+		return excelize.Cell{StyleID: w.dateStyle, Value: val}
+	case string:
+		if n, ok := parseCanonicalNumber(val); ok {
+			return n
+		}
+		return val
+	case []byte:
+		return string(val)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return val
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// parseCanonicalNumber 将规范数值字符串（如 DECIMAL 列）转为浮点数，超出安全精度时保持文本。
+func parseCanonicalNumber(s string) (float64, bool) {
+	if !canonicalNumberPattern.MatchString(s) {
+		return 0, false
+	}
+	digits := len(s)
+	if strings.HasPrefix(s, "-") {
+		digits--
+	}
+	if strings.Contains(s, ".") {
+		digits--
+	}
+	if digits > xlsxMaxSafeDigits {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// uniqueSheetName 清理非法字符、截断长度并去重，生成合法的工作表名称。
+func (w *XLSXWorkbook) uniqueSheetName(name string) string {
+	base := strings.TrimSpace(invalidSheetNameChars.Replace(name))
+	base = strings.Trim(base, "'")
+	if base == "" {
+		base = "Sheet"
+	}
+	base = truncateRunes(base, xlsxMaxSheetNameLen)
+
+	candidate := base
+	for i := 2; w.sheetNames[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		candidate = truncateRunes(base, xlsxMaxSheetNameLen-len(suffix)) + suffix
+	}
+	w.sheetNames[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// truncateRunes 按字符数截断字符串。
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+// columnWidths 根据表头与采样数据估算列宽（宽字符按 2 计）。
+func columnWidths(columns []string, rows []map[string]interface{}) []float64 {
+	widths := make([]float64, len(columns))
+	for i, col := range columns {
+		widths[i] = float64(displayWidth(col)) + 2
+	}
+
+	limit := len(rows)
+	if limit > xlsxWidthSampleRows {
+		limit = xlsxWidthSampleRows
+	}
+	for _, row := range rows[:limit] {
+		for i, col := range columns {
+			v := row[col]
+			if v == nil {
+				continue
+			}
+			text := ""
+			if t, ok := v.(time.Time); ok {
+				text = t.Format("2006-01-02 15:04:05")
+			} else {
+				text = fmt.Sprintf("%v", v)
+			}
+			if line, _, found := strings.Cut(text, "\n"); found {
+				text = line
+			}
+			if w := float64(displayWidth(text)) + 2; w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	for i := range widths {
+		if widths[i] < xlsxMinColWidth {
+			widths[i] = xlsxMinColWidth
+		}
+		if widths[i] > xlsxMaxColWidth {
+			widths[i] = xlsxMaxColWidth
+		}
+	}
+	return widths
+}
+
+// displayWidth 计算文本显示宽度，CJK 等宽字符按 2 个单位计算。
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x1100 && (r <= 0x115F || (r >= 0x2E80 && r <= 0xA4CF) || (r >= 0xAC00 && r <= 0xD7A3) ||
+			(r >= 0xF900 && r <= 0xFAFF) || (r >= 0xFE30 && r <= 0xFE4F) || (r >= 0xFF00 && r <= 0xFF60) ||
+			(r >= 0xFFE0 && r <= 0xFFE6)) {
+			width += 2
+			continue
+		}
+		width++
+	}
+	return width
+}
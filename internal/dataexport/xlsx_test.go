@@ -0,0 +1,147 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataexport
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseCanonicalNumber(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		ok    bool
+	}{
+		{"12.50", 12.5, true},
+		{"-3", -3, true},
+		{"0", 0, true},
+		{"00123", 0, false},
+		{"1e5", 0, false},
+		{"abc", 0, false},
+		{"1234567890123456", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCanonicalNumber(tt.input)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseCanonicalNumber(%q) = %v, %v; 期望 %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestXLSXWorkbook_UniqueSheetName(t *testing.T) {
+	w, err := NewXLSXWorkbook()
+	if err != nil {
+		t.Fatalf("NewXLSXWorkbook() error = %v", err)
+	}
+	defer w.Close()
+
+	if got := w.uniqueSheetName("a/b:c"); got != "a_b_c" {
+		t.Errorf("非法字符未替换: %s", got)
+	}
+	long := "abcdefghijklmnopqrstuvwxyz0123456789"
+	first := w.uniqueSheetName(long)
+	second := w.uniqueSheetName(long)
+	if len(first) != xlsxMaxSheetNameLen || len(second) != xlsxMaxSheetNameLen || first == second {
+		t.Errorf("长名称截断或去重失败: %s / %s", first, second)
+	}
+	if got := w.uniqueSheetName(""); got != "Sheet" {
+		t.Errorf("空名称应回退为 Sheet: %s", got)
+	}
+}
+
+func TestColumnWidths(t *testing.T) {
+	widths := columnWidths([]string{"id", "名称"}, []map[string]interface{}{
+		{"id": int64(1), "名称": "一个很长的中文名称一个很长的中文名称"},
+	})
+	if widths[0] != xlsxMinColWidth {
+		t.Errorf("短列应使用最小宽度，得到 %v", widths[0])
+	}
+	if widths[1] != 38 {
+		t.Errorf("中文列宽度应按双宽计算，得到 %v", widths[1])
+	}
+}
+
+func TestXLSXWorkbook_MultiSheetRoundTrip(t *testing.T) {
+	w, err := NewXLSXWorkbook()
+	if err != nil {
+		t.Fatalf("NewXLSXWorkbook() error = %v", err)
+	}
+	defer w.Close()
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := w.AddSheet("users", []string{"id", "name", "score", "created"}, []map[string]interface{}{
+		{"id": int64(1), "name": "alice", "score": "98.5", "created": created},
+		{"id": int64(2), "name": nil, "score": "00123", "created": nil},
+	}); err != nil {
+		t.Fatalf("AddSheet(users) error = %v", err)
+	}
+	if _, err := w.AddSheet("orders", []string{"id"}, nil); err != nil {
+		t.Fatalf("AddSheet(orders) error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.xlsx")
+	if err := w.SaveAs(path); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); len(sheets) != 2 || sheets[0] != "users" || sheets[1] != "orders" {
+		t.Fatalf("unexpected sheets: %v", sheets)
+	}
+	if typ, _ := f.GetCellType("users", "C2"); typ == excelize.CellTypeInlineString || typ == excelize.CellTypeSharedString {
+		t.Errorf("数值字符串应写为数字单元格，得到 %v", typ)
+	}
+	if v, _ := f.GetCellValue("users", "C3"); v != "00123" {
+		t.Errorf("带前导零的文本应保持原样，得到 %q", v)
+	}
+	if v, _ := f.GetCellValue("users", "A1"); v != "id" {
+		t.Errorf("表头写入错误: %q", v)
+	}
+}
+
+func TestXLSXExporterAppliesFormatter(t *testing.T) {
+	null := "(null)"
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	err := xlsxExporter{}.Write(&buf, []string{"name", "created"}, []map[string]interface{}{
+		{"name": nil, "created": created},
+	}, &Options{Formatter: NewValueFormatter(&null, "dd/MM/yyyy")})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+	if v, _ := f.GetCellValue(sheet, "A2"); v != null {
+		t.Errorf("NULL 应写为指定表示，得到 %q", v)
+	}
+	if v, _ := f.GetCellValue(sheet, "B2"); v != "02/01/2026" {
+		t.Errorf("日期应按指定格式写入，得到 %q", v)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
+)
+
+// columnKind 列值的解析类别。
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt
+	kindBool
+	kindDecimal
+	kindFloat
+	kindBit
+	kindDate
+	kindDateTime
+	kindTime
+	kindYear
+	kindJSON
+	kindEnum
+)
+
+// dateLayouts 可识别的日期格式。
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "20060102"}
+
+// dateTimeLayouts 可识别的日期时间格式。
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// timePattern MySQL TIME 取值格式（允许负数与超过 24 小时）。
+var timePattern = regexp.MustCompile(`^-?\d{1,3}:\d{2}(:\d{2}(\.\d{1,6})?)?$`)
+
+// enumValuePattern 提取 enum('a','b') 中的取值。
+var enumValuePattern = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// ColumnCoercer 根据目标表的列定义把文本字段解析为对应的 Go 类型。
+type ColumnCoercer struct {
+	def      *connection.ColumnDefinition
+	kind     columnKind
+	unsigned bool
+	bitWidth int
+	enums    map[string]bool
+	rule     *connection.ImportColumnRule // 用户指定的转换规则，可为空
+}
+
+// NewColumnCoercer 根据列定义创建字段解析器。
+func NewColumnCoercer(def *connection.ColumnDefinition) *ColumnCoercer {
+	c := &ColumnCoercer{def: def}
+	typ := strings.ToLower(strings.TrimSpace(def.Type))
+	base := typ
+	if idx := strings.IndexAny(base, "( "); idx >= 0 {
+		base = base[:idx]
+	}
+	c.unsigned = strings.Contains(typ, "unsigned")
+
+	switch base {
+	case "tinyint":
+		c.kind = kindInt
+		if strings.HasPrefix(typ, "tinyint(1)") {
+			c.kind = kindBool
+		}
+	case "smallint", "mediumint", "int", "integer", "bigint", "serial", "int2", "int4", "int8", "smallserial", "bigserial":
+		c.kind = kindInt
+	case "bool", "boolean":
+		c.kind = kindBool
+	case "decimal", "numeric", "dec", "fixed":
+		c.kind = kindDecimal
+	case "float", "double", "real", "float4", "float8":
+		c.kind = kindFloat
+	case "bit":
+		c.kind = kindBit
+		c.bitWidth = 1
+		if w, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(typ, "bit"), "() ")); err == nil {
+			c.bitWidth = w
+		}
+	case "date":
+		c.kind = kindDate
+	case "datetime", "timestamp", "timestamptz":
+		c.kind = kindDateTime
+	case "time":
+		c.kind = kindTime
+	case "year":
+		c.kind = kindYear
+	case "json", "jsonb":
+		c.kind = kindJSON
+	case "enum":
+		c.kind = kindEnum
+		c.enums = make(map[string]bool)
+		for _, m := range enumValuePattern.FindAllStringSubmatch(def.Type, -1) {
+			c.enums[strings.ToLower(strings.ReplaceAll(m[1], "''", "'"))] = true
+		}
+	default:
+		c.kind = kindString
+	}
+	return c
+}
+
+// WithRule 设置列的转换规则。
+func (c *ColumnCoercer) WithRule(rule *connection.ImportColumnRule) *ColumnCoercer {
+	c.rule = rule
+	return c
+}
+
+// Coerce 解析单个字段值：nil 原样返回；非文本列的空字符串按可空性转为 NULL，
+// 不可为空但有默认值时返回 connection.ColumnDefault，由插入语句写 DEFAULT 交给数据库取值。
+func (c *ColumnCoercer) Coerce(val interface{}) (interface{}, error) {
+	raw, ok := val.(string)
+	if !ok {
+		return val, nil
+	}
+	if c.rule != nil {
+		if c.rule.Trim {
+			raw = strings.TrimSpace(raw)
+		}
+		for _, marker := range c.rule.NullMarkers {
+			if raw == marker {
+				return nil, nil
+			}
+		}
+	}
+	if c.kind == kindString {
+		return raw, nil
+	}
+
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		switch {
+		case strings.EqualFold(c.def.Nullable, "YES"):
+			return nil, nil
+		case c.def.Default != nil || c.def.DefaultExpression != "":
+			return connection.ColumnDefault{}, nil
+		case c.kind == kindEnum:
+			return raw, nil
+		default:
+			return nil, fmt.Errorf("列不允许为空")
+		}
+	}
+
+	switch c.kind {
+	case kindInt:
+		return c.parseInt(text)
+	case kindBool:
+		return c.parseBool(text)
+	case kindDecimal:
+		if _, ok := new(big.Rat).SetString(text); !ok {
+			return nil, fmt.Errorf("无效的数值 %q", raw)
+		}
+		return text, nil // 以文本传参，避免精度损失
+	case kindFloat:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的浮点数 %q", raw)
+		}
+		return f, nil
+	case kindBit:
+		if c.bitWidth == 1 {
+			return c.parseBool(text)
+		}
+		u, err := strconv.ParseUint(text, 0, 64)
+		if err != nil || (c.bitWidth < 64 && u >= 1<<uint(c.bitWidth)) {
+			return nil, fmt.Errorf("无效的位值 %q", raw)
+		}
+		return u, nil
+	case kindDate:
+		t, err := parseTime(text, c.layouts(dateLayouts))
+		if err != nil {
+			return nil, fmt.Errorf("无效的日期 %q", raw)
+		}
+		return t.Format("2006-01-02"), nil
+	case kindDateTime:
+		t, err := parseTime(text, c.layouts(dateTimeLayouts))
+		if err != nil {
+			return nil, fmt.Errorf("无效的日期时间 %q", raw)
+		}
+		return formatDateTime(t.In(time.Local)), nil
+	case kindTime:
+		if !timePattern.MatchString(text) {
+			return nil, fmt.Errorf("无效的时间 %q", raw)
+		}
+		return text, nil
+	case kindYear:
+		y, err := strconv.Atoi(text)
+		if err != nil || (y != 0 && (y < 1901 || y > 2155)) {
+			return nil, fmt.Errorf("无效的年份 %q", raw)
+		}
+		return int64(y), nil
+	case kindJSON:
+		if !json.Valid([]byte(text)) {
+			return nil, fmt.Errorf("无效的 JSON")
+		}
+		return text, nil
+	case kindEnum:
+		if len(c.enums) > 0 && !c.enums[strings.ToLower(raw)] {
+			return nil, fmt.Errorf("%q 不在枚举取值范围内", raw)
+		}
+		return raw, nil
+	}
+	return val, nil
+}
+
+// parseInt 解析整数，按列是否 unsigned 选择范围。
+func (c *ColumnCoercer) parseInt(text string) (interface{}, error) {
+	if c.unsigned {
+		u, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的无符号整数 %q", text)
+		}
+		return u, nil
+	}
+	i, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("无效的整数 %q", text)
+	}
+	return i, nil
+}
+
+// layouts 返回日期解析格式：规则指定的格式优先，其次为内置格式。
+func (c *ColumnCoercer) layouts(defaults []string) []string {
+	if c.rule == nil || c.rule.DateFormat == "" {
+		return defaults
+	}
+	return append([]string{dataexport.ConvertDateFormat(c.rule.DateFormat)}, defaults...)
+}
+
+// parseBool 解析布尔值：规则指定了真/假取值时按规则匹配，否则使用内置取值。
+func (c *ColumnCoercer) parseBool(text string) (interface{}, error) {
+	if c.rule != nil && (len(c.rule.TrueValues) > 0 || len(c.rule.FalseValues) > 0) {
+		for _, v := range c.rule.TrueValues {
+			if strings.EqualFold(text, v) {
+				return int64(1), nil
+			}
+		}
+		for _, v := range c.rule.FalseValues {
+			if strings.EqualFold(text, v) {
+				return int64(0), nil
+			}
+		}
+		return nil, fmt.Errorf("无效的布尔值 %q", text)
+	}
+	return parseBool(text)
+}
+
+// parseBool 解析布尔值，返回 0/1 以兼容 tinyint(1) 与 bit(1)。
+func parseBool(text string) (interface{}, error) {
+	switch strings.ToLower(text) {
+	case "1", "true", "t", "yes", "y", "on":
+		return int64(1), nil
+	case "0", "false", "f", "no", "n", "off":
+		return int64(0), nil
+	}
+	return nil, fmt.Errorf("无效的布尔值 %q", text)
+}
+
+// parseTime 按候选格式依次解析时间文本。
+func parseTime(text string, layouts []string) (time.Time, error) {
+	var lastErr error
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, text, time.Local)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, lastErr
+}
+
+// formatDateTime 格式化为 DATETIME 文本，有小数秒时保留微秒。
+func formatDateTime(t time.Time) string {
+	base := t.Format("2006-01-02 15:04:05")
+	if t.Nanosecond() == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s.%06d", base, t.Nanosecond()/1000)
+}
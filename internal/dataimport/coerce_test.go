@@ -0,0 +1,125 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func col(name, typ, nullable string) *connection.ColumnDefinition {
+	return &connection.ColumnDefinition{Name: name, Type: typ, Nullable: nullable}
+}
+
+func TestColumnCoercerParsesTypes(t *testing.T) {
+	cases := []struct {
+		typ  string
+		in   string
+		want interface{}
+	}{
+		{"int(11)", "42", int64(42)},
+		{"bigint unsigned", "18446744073709551615", uint64(18446744073709551615)},
+		{"tinyint(1)", "true", int64(1)},
+		{"tinyint(4)", "-3", int64(-3)},
+		{"decimal(20,6)", "12345678901234.123456", "12345678901234.123456"},
+		{"double", "1.5e3", 1500.0},
+		{"bit(1)", "0", int64(0)},
+		{"bit(8)", "255", uint64(255)},
+		{"date", "2024/03/05", "2024-03-05"},
+		{"datetime(3)", "2024-03-05 10:11:12.5", "2024-03-05 10:11:12.500000"},
+		{"timestamp", "2024-03-05", "2024-03-05 00:00:00"},
+		{"time", "838:59:59", "838:59:59"},
+		{"year", "2024", int64(2024)},
+		{"json", `{"a":1}`, `{"a":1}`},
+		{"enum('Small','It''s')", "it's", "it's"},
+		{"varchar(20)", "  keep  ", "  keep  "},
+	}
+	for _, tc := range cases {
+		got, err := NewColumnCoercer(col("c", tc.typ, "NO")).Coerce(tc.in)
+		if err != nil {
+			t.Errorf("%s 解析 %q 失败: %v", tc.typ, tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s 解析 %q 期望 %#v，得到 %#v", tc.typ, tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestColumnCoercerRejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"int":           "12a",
+		"int unsigned":  "-1",
+		"tinyint(1)":    "maybe",
+		"decimal(10,2)": "1,5",
+		"bit(4)":        "16",
+		"date":          "2024-13-01",
+		"datetime":      "yesterday",
+		"time":          "10h",
+		"year":          "1800",
+		"json":          "{bad",
+		"enum('a','b')": "c",
+	}
+	for typ, in := range cases {
+		if _, err := NewColumnCoercer(col("c", typ, "NO")).Coerce(in); err == nil {
+			t.Errorf("%s 解析 %q 应失败", typ, in)
+		}
+	}
+}
+
+func TestColumnCoercerEmptyValues(t *testing.T) {
+	if v, err := NewColumnCoercer(col("c", "int", "YES")).Coerce(""); err != nil || v != nil {
+		t.Errorf("可空列空值应为 NULL，得到 %v %v", v, err)
+	}
+	def := "7"
+	withDefault := &connection.ColumnDefinition{Name: "c", Type: "int", Nullable: "NO", Default: &def}
+	if v, err := NewColumnCoercer(withDefault).Coerce(""); err != nil || v != (connection.ColumnDefault{}) {
+		t.Errorf("非空列空值应使用默认值，得到 %v %v", v, err)
+	}
+	if _, err := NewColumnCoercer(col("c", "int", "NO")).Coerce(""); err == nil {
+		t.Error("非空且无默认值的列空值应报错")
+	}
+	if v, _ := NewColumnCoercer(col("c", "int", "NO")).Coerce(nil); v != nil {
+		t.Errorf("nil 应原样返回，得到 %v", v)
+	}
+}
+
+func TestCoercerReportsCellErrors(t *testing.T) {
+	c := NewCoercer([]*connection.ColumnDefinition{
+		col("id", "int", "NO"),
+		col("born", "date", "YES"),
+	})
+	rows := []map[string]interface{}{
+		{"id": "1", "born": "2000-01-01"},
+		{"id": "x", "born": "bad"},
+		{"id": "3", "born": ""},
+	}
+
+	if unknown := c.UnknownColumns(append(rows, map[string]interface{}{"extra": "1"})); len(unknown) != 1 || unknown[0] != "extra" {
+		t.Errorf("未知列检测错误: %v", unknown)
+	}
+
+	parsed, rowNumbers, errs := c.CoerceRows(rows)
+	if len(parsed) != 2 || rowNumbers[0] != 1 || rowNumbers[1] != 3 {
+		t.Fatalf("期望保留第 1、3 行，得到 %v", rowNumbers)
+	}
+	if parsed[0]["id"] != int64(1) || parsed[1]["born"] != nil {
+		t.Errorf("解析结果错误: %+v", parsed)
+	}
+	if len(errs) != 2 || errs[0].Row != 2 || errs[0].Column != "born" || errs[1].Column != "id" {
+		t.Errorf("单元格错误不正确: %+v %+v", errs[0], errs[1])
+	}
+}
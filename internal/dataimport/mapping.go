@@ -0,0 +1,101 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// SuggestMapping 按列名（忽略大小写、下划线、空格与连字符）为文件列推荐目标表列。
+func SuggestMapping(headers []string, defs []*connection.ColumnDefinition) map[string]string {
+	byKey := make(map[string]string, len(defs))
+	for _, def := range defs {
+		if def != nil {
+			byKey[mappingKey(def.Name)] = def.Name
+		}
+	}
+	mapping := make(map[string]string)
+	used := make(map[string]bool)
+	for _, h := range headers {
+		if target, ok := byKey[mappingKey(h)]; ok && !used[target] {
+			mapping[h] = target
+			used[target] = true
+		}
+	}
+	return mapping
+}
+
+// ValidateMapping 校验映射：源列需存在于文件中，目标列需存在于表中且不能重复。
+func ValidateMapping(mapping map[string]string, headers []string, defs []*connection.ColumnDefinition) error {
+	if len(mapping) == 0 {
+		return fmt.Errorf("列映射不能为空")
+	}
+	headerSet := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		headerSet[h] = true
+	}
+	columnSet := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		if def != nil {
+			columnSet[strings.ToLower(def.Name)] = true
+		}
+	}
+
+	targets := make(map[string]string)
+	for source, target := range mapping {
+		if target == "" {
+			continue
+		}
+		if !headerSet[source] {
+			return fmt.Errorf("文件中不存在列: %s", source)
+		}
+		if len(columnSet) > 0 && !columnSet[strings.ToLower(target)] {
+			return fmt.Errorf("目标表中不存在列: %s", target)
+		}
+		if prev, ok := targets[strings.ToLower(target)]; ok {
+			return fmt.Errorf("列 %s 与 %s 映射到了同一目标列 %s", prev, source, target)
+		}
+		targets[strings.ToLower(target)] = source
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("至少需要映射一列")
+	}
+	return nil
+}
+
+// ApplyMapping 按映射将文件行转换为以目标列为键的行，未映射（或映射为空）的列被忽略。
+func ApplyMapping(rows []map[string]interface{}, mapping map[string]string) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		mapped := make(map[string]interface{}, len(mapping))
+		for source, target := range mapping {
+			if target == "" {
+				continue
+			}
+			mapped[target] = row[source] // 缺失的源列写入 NULL
+		}
+		out = append(out, mapped)
+	}
+	return out
+}
+
+// mappingKey 生成列名匹配键。
+func mappingKey(name string) string {
+	r := strings.NewReplacer("_", "", " ", "", "-", "")
+	return strings.ToLower(r.Replace(strings.TrimSpace(name)))
+}
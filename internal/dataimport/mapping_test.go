@@ -0,0 +1,88 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestParseCSVKeepsHeaderOrder(t *testing.T) {
+	table, err := parseCSV(strings.NewReader("b,a\n1,NULL\n2,x\n"))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if strings.Join(table.Headers, ",") != "b,a" || len(table.Rows) != 2 {
+		t.Fatalf("表头或行数错误: %v %d", table.Headers, len(table.Rows))
+	}
+	if table.Rows[0]["a"] != nil || table.Rows[1]["a"] != "x" {
+		t.Errorf("行数据错误: %+v", table.Rows)
+	}
+}
+
+func TestSuggestAndValidateMapping(t *testing.T) {
+	defs := []*connection.ColumnDefinition{{Name: "user_id"}, {Name: "created_at"}}
+	mapping := SuggestMapping([]string{"User ID", "createdAt", "note"}, defs)
+	if mapping["User ID"] != "user_id" || mapping["createdAt"] != "created_at" {
+		t.Errorf("推荐映射错误: %v", mapping)
+	}
+	if _, ok := mapping["note"]; ok {
+		t.Error("无匹配列不应出现在推荐映射中")
+	}
+
+	headers := []string{"User ID", "createdAt", "note"}
+	if err := ValidateMapping(mapping, headers, defs); err != nil {
+		t.Errorf("期望映射合法，得到 %v", err)
+	}
+	if err := ValidateMapping(map[string]string{"User ID": "user_id", "note": "USER_ID"}, headers, defs); err == nil {
+		t.Error("重复目标列应校验失败")
+	}
+	if err := ValidateMapping(map[string]string{"note": "missing"}, headers, defs); err == nil {
+		t.Error("不存在的目标列应校验失败")
+	}
+	if err := ValidateMapping(map[string]string{"nope": "user_id"}, headers, defs); err == nil {
+		t.Error("不存在的源列应校验失败")
+	}
+}
+
+func TestApplyMapping(t *testing.T) {
+	rows := []map[string]interface{}{{"User ID": "1", "note": "x"}}
+	out := ApplyMapping(rows, map[string]string{"User ID": "user_id", "note": ""})
+	if len(out[0]) != 1 || out[0]["user_id"] != "1" {
+		t.Errorf("映射结果错误: %+v", out[0])
+	}
+}
+
+func TestColumnRules(t *testing.T) {
+	flag := NewColumnCoercer(&connection.ColumnDefinition{Name: "f", Type: "tinyint(1)", Nullable: "YES"}).
+		WithRule(&connection.ImportColumnRule{TrueValues: []string{"是"}, FalseValues: []string{"否"}, NullMarkers: []string{"-"}, Trim: true})
+	if v, err := flag.Coerce(" 是 "); err != nil || v != int64(1) {
+		t.Errorf("自定义真值解析错误: %v %v", v, err)
+	}
+	if v, err := flag.Coerce("-"); err != nil || v != nil {
+		t.Errorf("NULL 标记解析错误: %v %v", v, err)
+	}
+	if _, err := flag.Coerce("true"); err == nil {
+		t.Error("指定真假取值后不应接受内置取值")
+	}
+
+	date := NewColumnCoercer(&connection.ColumnDefinition{Name: "d", Type: "date", Nullable: "NO"}).
+		WithRule(&connection.ImportColumnRule{DateFormat: "dd/MM/yyyy"})
+	if v, err := date.Coerce("05/03/2024"); err != nil || v != "2024-03-05" {
+		t.Errorf("自定义日期格式解析错误: %v %v", v, err)
+	}
+}
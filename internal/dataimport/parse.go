@@ -0,0 +1,117 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Table 从导入文件解析出的数据表。
+type Table struct {
+	Format  string                   // 文件格式：csv、json、xlsx、parquet
+	Sheet   string                   // 实际读取的工作表（仅 xlsx）
+	Headers []string                 // 列名（CSV/Excel/Parquet 保持文件顺序，JSON 按字母排序）
+	Rows    []map[string]interface{} // 数据行
+}
+
+// ParseFile 按扩展名解析导入文件；sheet 仅对 Excel 生效，为空时读取第一个工作表。
+func ParseFile(path, sheet string) (*Table, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return parseXLSX(path, sheet)
+	case ".parquet":
+		return parseParquet(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return parseJSON(f)
+	case ".csv":
+		return parseCSV(f)
+	default:
+		return nil, fmt.Errorf("不支持的文件类型")
+	}
+}
+
+// parseJSON 解析 JSON 对象数组，数字保留原文以避免大整数丢失精度。
+func parseJSON(r io.Reader) (*Table, error) {
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("Failed to parse JSON: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	headers := make([]string, 0)
+	for _, row := range rows {
+		for col := range row {
+			if !seen[col] {
+				seen[col] = true
+				headers = append(headers, col)
+			}
+		}
+	}
+	sort.Strings(headers)
+	return &Table{Format: "json", Headers: headers, Rows: rows}, nil
+}
+
+// ParseCSV 解析首行为表头的 CSV 文本（如从剪贴板粘贴的内容），"NULL" 视为空值。
+func ParseCSV(r io.Reader) (*Table, error) {
+	return parseCSV(r)
+}
+
+// parseCSV 解析首行为表头的 CSV，"NULL" 视为空值。
+func parseCSV(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse CSV: %v", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV是空的或没有头行")
+	}
+
+	headers := records[0]
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{})
+		for i, val := range record {
+			if i >= len(headers) {
+				continue
+			}
+			if val == "NULL" {
+				row[headers[i]] = nil
+			} else {
+				row[headers[i]] = val
+			}
+		}
+		rows = append(rows, row)
+	}
+	return &Table{Format: "csv", Headers: headers, Rows: rows}, nil
+}
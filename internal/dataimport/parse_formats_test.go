@@ -0,0 +1,127 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseXLSXSheetsAndDates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"id", "name", "born"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{1, "alice", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)})
+	f.SetSheetRow("Sheet1", "A4", &[]interface{}{2, "bob"})
+	f.NewSheet("Other")
+	f.SetSheetRow("Other", "A2", &[]interface{}{"code"})
+	f.SetSheetRow("Other", "A3", &[]interface{}{"x1"})
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("写入 Excel 失败: %v", err)
+	}
+
+	sheets, err := ListSheets(path)
+	if err != nil || strings.Join(sheets, ",") != "Sheet1,Other" {
+		t.Fatalf("工作表列表错误: %v %v", sheets, err)
+	}
+
+	table, err := ParseFile(path, "")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if table.Sheet != "Sheet1" || strings.Join(table.Headers, ",") != "id,name,born" || len(table.Rows) != 2 {
+		t.Fatalf("表结构错误: %+v", table)
+	}
+	if table.Rows[0]["born"] != "2024-03-05" || table.Rows[0]["id"] != "1" {
+		t.Errorf("单元格值错误: %+v", table.Rows[0])
+	}
+	if table.Rows[1]["born"] != nil {
+		t.Errorf("缺失单元格应为 NULL: %+v", table.Rows[1])
+	}
+
+	other, err := ParseFile(path, "Other")
+	if err != nil || len(other.Rows) != 1 || other.Rows[0]["code"] != "x1" {
+		t.Fatalf("指定工作表解析错误: %+v %v", other, err)
+	}
+	if _, err := ParseFile(path, "Missing"); err == nil {
+		t.Error("不存在的工作表应返回错误")
+	}
+}
+
+type parquetRecord struct {
+	ID      int64     `parquet:"id"`
+	Name    *string   `parquet:"name,optional"`
+	Created time.Time `parquet:"created,timestamp(millisecond)"`
+	Day     int32     `parquet:"day,date"`
+	Amount  int64     `parquet:"amount,decimal(2:18)"`
+}
+
+func TestParseParquetLogicalTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	name := "alice"
+	created := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	records := []parquetRecord{
+		{ID: 1, Name: &name, Created: created, Day: 19787, Amount: -1234},
+		{ID: 2, Created: created, Day: 19723, Amount: 5},
+	}
+	if err := parquet.WriteFile(path, records); err != nil {
+		t.Fatalf("写入 Parquet 失败: %v", err)
+	}
+
+	table, err := ParseFile(path, "")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if table.Format != "parquet" || len(table.Headers) != 5 || len(table.Rows) != 2 {
+		t.Fatalf("表结构错误: %+v", table)
+	}
+	row := table.Rows[0]
+	if row["name"] != "alice" || row["amount"] != "-12.34" || row["day"] != "2024-03-05" {
+		t.Errorf("值转换错误: %+v", row)
+	}
+	if row["created"] != formatDateTime(created.In(time.Local)) {
+		t.Errorf("时间戳转换错误: %v", row["created"])
+	}
+	if table.Rows[1]["name"] != nil || table.Rows[1]["amount"] != "0.05" {
+		t.Errorf("可选列或定点数错误: %+v", table.Rows[1])
+	}
+}
+
+func TestParseXLSXDuplicateHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dup.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"name", "name", "name_2", "name"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"a", "b", "c", "d"})
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("写入 Excel 失败: %v", err)
+	}
+
+	table, err := ParseFile(path, "")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if got := strings.Join(table.Headers, ","); got != "name,name_3,name_2,name_4" {
+		t.Fatalf("重复列名应追加后缀，得到 %s", got)
+	}
+	row := table.Rows[0]
+	if row["name"] != "a" || row["name_3"] != "b" || row["name_2"] != "c" || row["name_4"] != "d" {
+		t.Errorf("同名列的值不应互相覆盖: %+v", row)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// parquetConverter 将 Parquet 物理值按逻辑类型转换为可导入的值。
+type parquetConverter func(v interface{}) interface{}
+
+// parseParquet 解析 Parquet 文件：顶层字段作为列，时间戳/日期/定点数按逻辑类型转换，嵌套结构保留为对象。
+func parseParquet(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := parquet.NewReader(f)
+	defer reader.Close()
+
+	fields := reader.Schema().Fields()
+	headers := make([]string, 0, len(fields))
+	converters := make(map[string]parquetConverter, len(fields))
+	for _, field := range fields {
+		headers = append(headers, field.Name())
+		if field.Leaf() {
+			if conv := parquetConverterOf(field.Type()); conv != nil {
+				converters[field.Name()] = conv
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, reader.NumRows())
+	for {
+		row := make(map[string]interface{}, len(headers))
+		if err := reader.Read(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("读取 Parquet 数据失败：%w", err)
+		}
+		for name, conv := range converters {
+			if v, ok := row[name]; ok && v != nil {
+				row[name] = conv(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return &Table{Format: "parquet", Headers: headers, Rows: rows}, nil
+}
+
+// parquetConverterOf 根据列的逻辑类型返回值转换函数，无需转换时返回 nil。
+func parquetConverterOf(t parquet.Type) parquetConverter {
+	lt := t.LogicalType()
+	if lt == nil {
+		return nil
+	}
+	switch v := lt.Value.(type) {
+	case *format.TimestampType:
+		unit := v.Unit.Value
+		utc := v.IsAdjustedToUTC
+		return func(val interface{}) interface{} {
+			n, ok := val.(int64)
+			if !ok {
+				return val
+			}
+			var ts time.Time
+			switch unit.(type) {
+			case *format.MilliSeconds:
+				ts = time.UnixMilli(n)
+			case *format.MicroSeconds:
+				ts = time.UnixMicro(n)
+			default:
+				ts = time.Unix(0, n)
+			}
+			if utc {
+				ts = ts.In(time.Local)
+			} else {
+				ts = ts.UTC() // 本地时间语义：按字面值输出
+			}
+			return formatDateTime(ts)
+		}
+	case *format.DateType:
+		return func(val interface{}) interface{} {
+			days, ok := val.(int32)
+			if !ok {
+				return val
+			}
+			return time.Unix(int64(days)*86400, 0).UTC().Format("2006-01-02")
+		}
+	case *format.DecimalType:
+		scale := int(v.Scale)
+		return func(val interface{}) interface{} {
+			var unscaled *big.Int
+			switch n := val.(type) {
+			case float64:
+				return strconv.FormatFloat(n, 'f', scale, 64) // 读取器已按标度换算
+
+			case int32:
+				unscaled = big.NewInt(int64(n))
+			case int64:
+				unscaled = big.NewInt(n)
+			case []byte:
+				unscaled = twosComplement(n)
+			case string:
+				unscaled = twosComplement([]byte(n))
+			default:
+				return val
+			}
+			return new(big.Rat).SetFrac(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)).FloatString(scale)
+		}
+	}
+	return nil
+}
+
+// twosComplement 将大端补码字节解析为整数。
+func twosComplement(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return n
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// builtinDateNumFmts Excel 内置的日期/时间数字格式编号。
+var builtinDateNumFmts = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+	27: true, 30: true, 36: true, 45: true, 46: true, 47: true, 50: true, 57: true,
+}
+
+// ListSheets 返回 Excel 工作簿中的工作表名称。
+func ListSheets(path string) ([]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 Excel 文件失败：%w", err)
+	}
+	defer f.Close()
+	return f.GetSheetList(), nil
+}
+
+// parseXLSX 解析 Excel 工作表：首个非空行为表头，日期单元格转换为标准日期时间文本，其余单元格取原始值。
+func parseXLSX(path, sheet string) (*Table, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 Excel 文件失败：%w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("Excel 文件中没有工作表")
+	}
+	if sheet == "" {
+		sheet = sheets[0]
+	} else if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		return nil, fmt.Errorf("工作表不存在: %s", sheet)
+	}
+
+	records, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("读取工作表失败：%w", err)
+	}
+
+	headerIdx := -1
+	for i, record := range records {
+		if strings.TrimSpace(strings.Join(record, "")) != "" {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 || headerIdx == len(records)-1 {
+		return nil, fmt.Errorf("工作表是空的或没有头行")
+	}
+
+	headers := uniqueHeaders(records[headerIdx])
+	dates := newXLSXDateDetector(f, sheet)
+	rows := make([]map[string]interface{}, 0, len(records)-headerIdx-1)
+	for r := headerIdx + 1; r < len(records); r++ {
+		record := records[r]
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // 跳过空行
+		}
+		row := make(map[string]interface{}, len(headers))
+		for c, header := range headers {
+			if header == "" {
+				continue
+			}
+			if c >= len(record) || record[c] == "" {
+				row[header] = nil
+				continue
+			}
+			row[header] = dates.convert(c, r, record[c])
+		}
+		rows = append(rows, row)
+	}
+
+	return &Table{Format: "xlsx", Sheet: sheet, Headers: nonEmpty(headers), Rows: rows}, nil
+}
+
+// xlsxDateDetector 根据单元格样式识别日期单元格，并缓存样式判断结果。
+type xlsxDateDetector struct {
+	f        *excelize.File
+	sheet    string
+	date1904 bool
+	styles   map[int]bool // 样式 ID -> 是否为日期格式
+}
+
+// newXLSXDateDetector 创建日期单元格识别器。
+func newXLSXDateDetector(f *excelize.File, sheet string) *xlsxDateDetector {
+	d := &xlsxDateDetector{f: f, sheet: sheet, styles: make(map[int]bool)}
+	if props, err := f.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+		d.date1904 = *props.Date1904
+	}
+	return d
+}
+
+// convert 将日期样式的数值单元格转换为日期时间文本，其余值原样返回。
+func (d *xlsxDateDetector) convert(col, row int, raw string) interface{} {
+	serial, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+	cell, err := excelize.CoordinatesToCellName(col+1, row+1)
+	if err != nil {
+		return raw
+	}
+	styleID, err := d.f.GetCellStyle(d.sheet, cell)
+	if err != nil || !d.isDateStyle(styleID) {
+		return raw
+	}
+	t, err := excelize.ExcelDateToTime(serial, d.date1904)
+	if err != nil {
+		return raw
+	}
+	if serial == float64(int64(serial)) {
+		return t.Format("2006-01-02")
+	}
+	return formatDateTime(t)
+}
+
+// isDateStyle 判断样式的数字格式是否为日期/时间格式。
+func (d *xlsxDateDetector) isDateStyle(styleID int) bool {
+	if isDate, ok := d.styles[styleID]; ok {
+		return isDate
+	}
+	isDate := false
+	if style, err := d.f.GetStyle(styleID); err == nil && style != nil {
+		if style.CustomNumFmt != nil {
+			isDate = isDateFormatCode(*style.CustomNumFmt)
+		} else {
+			isDate = builtinDateNumFmts[style.NumFmt]
+		}
+	}
+	d.styles[styleID] = isDate
+	return isDate
+}
+
+// isDateFormatCode 判断自定义数字格式是否包含日期/时间占位符（忽略引号与方括号内的内容）。
+func isDateFormatCode(code string) bool {
+	inQuote, inBracket := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case r == 'y' || r == 'd' || r == 'h' || r == 's' || r == 'm':
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueHeaders 为重复的列名追加 _2、_3 等后缀，避免后出现的列覆盖前面同名列的值；空列名保持为空。
+func uniqueHeaders(headers []string) []string {
+	out := make([]string, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		seen[h] = true
+	}
+	used := make(map[string]bool, len(headers))
+	for i, h := range headers {
+		// 生成的名字不能与文件中其他原始列名相同
+		name := h
+		for n := 2; h != "" && (used[name] || (name != h && seen[name])); n++ {
+			name = fmt.Sprintf("%s_%d", h, n)
+		}
+		used[name] = true
+		out[i] = name
+	}
+	return out
+}
+
+// nonEmpty 过滤空列名。
+func nonEmpty(headers []string) []string {
+	out := make([]string, 0, len(headers))
+	for _, h := range headers {
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// Coercer 按目标表的列定义解析整行数据。
+type Coercer struct {
+	columns map[string]*ColumnCoercer // 小写列名 -> 字段解析器
+}
+
+// NewCoercer 根据目标表的列定义创建行解析器。
+func NewCoercer(defs []*connection.ColumnDefinition) *Coercer {
+	c := &Coercer{columns: make(map[string]*ColumnCoercer, len(defs))}
+	for _, def := range defs {
+		if def != nil {
+			c.columns[strings.ToLower(def.Name)] = NewColumnCoercer(def)
+		}
+	}
+	return c
+}
+
+// SetRules 为目标列设置转换规则（键为表列名，忽略大小写）。
+func (c *Coercer) SetRules(rules map[string]*connection.ImportColumnRule) {
+	for name, rule := range rules {
+		if coercer, ok := c.columns[strings.ToLower(name)]; ok && rule != nil {
+			coercer.WithRule(rule)
+		}
+	}
+}
+
+// UnknownColumns 返回数据中存在但目标表中没有的列（已排序）。
+func (c *Coercer) UnknownColumns(rows []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, row := range rows {
+		for col := range row {
+			if seen[col] {
+				continue
+			}
+			seen[col] = true
+			if _, ok := c.columns[strings.ToLower(col)]; !ok {
+				unknown = append(unknown, col)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// CoerceRows 逐单元格解析行数据，返回解析后的行、全部单元格通过的行号（从 1 开始）以及单元格错误。
+// 存在任一单元格错误的行不会出现在结果中。
+func (c *Coercer) CoerceRows(rows []map[string]interface{}) ([]map[string]interface{}, []int, []*connection.ImportRowError) {
+	out := make([]map[string]interface{}, 0, len(rows))
+	rowNumbers := make([]int, 0, len(rows))
+	var errs []*connection.ImportRowError
+
+	for i, row := range rows {
+		parsed := make(map[string]interface{}, len(row))
+		var rowErrs []*connection.ImportRowError
+		for _, col := range sortedKeys(row) {
+			coercer, ok := c.columns[strings.ToLower(col)]
+			if !ok {
+				parsed[col] = row[col]
+				continue
+			}
+			val, err := coercer.Coerce(row[col])
+			if err != nil {
+				rowErrs = append(rowErrs, &connection.ImportRowError{Row: i + 1, Column: col, Message: err.Error()})
+				continue
+			}
+			parsed[col] = val
+		}
+		if len(rowErrs) > 0 {
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		out = append(out, parsed)
+		rowNumbers = append(rowNumbers, i+1)
+	}
+	return out, rowNumbers, errs
+}
+
+// sortedKeys 返回按字母排序的列名，保证错误顺序稳定。
+func sortedKeys(row map[string]interface{}) []string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
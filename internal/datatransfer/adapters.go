@@ -0,0 +1,130 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// dbSource 基于数据库连接的源表。
+type dbSource struct {
+	conn     db.Database
+	streamer db.RowStreamer
+	dbType   connection.ConnectionType
+	database string
+	table    string
+}
+
+// NewDBSource 基于数据库连接创建源表，连接需支持流式读取。
+func NewDBSource(conn db.Database, dbType connection.ConnectionType, database, table string) (Source, error) {
+	streamer, ok := conn.(db.RowStreamer)
+	if !ok {
+		return nil, fmt.Errorf("源数据库不支持流式读取")
+	}
+	return &dbSource{conn: conn, streamer: streamer, dbType: dbType, database: database, table: table}, nil
+}
+
+// Describe 返回源表的列与索引定义。
+func (s *dbSource) Describe(ctx context.Context) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error) {
+	columns, err := s.conn.GetColumns(ctx, s.database, s.table)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := s.conn.GetIndexes(ctx, s.database, s.table)
+	if err != nil {
+		return nil, nil, err
+	}
+	return columns, indexes, nil
+}
+
+// CountRows 返回源表当前行数。
+func (s *dbSource) CountRows(ctx context.Context) (int64, error) {
+	stream, err := s.streamer.QueryStream(ctx, "SELECT COUNT(*) FROM "+db.QuoteTable(s.dbType, s.table))
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+	values, err := stream.NextValues()
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	_, err = fmt.Sscan(checkpointValue(values[0]), &n)
+	return n, err
+}
+
+// ReadPage 读取一页原始行数据。
+func (s *dbSource) ReadPage(ctx context.Context, columns, keyColumns []string, after []any, offset int64, limit int) ([][]any, error) {
+	query, args := db.BuildKeysetPageQuery(s.dbType, s.table, columns, keyColumns, after, offset, limit)
+	stream, err := s.streamer.QueryStream(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	rows := make([][]any, 0, limit)
+	for {
+		values, err := stream.NextValues()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, values)
+	}
+}
+
+// dbTarget 基于数据库连接的目标库。
+type dbTarget struct {
+	conn     db.Database
+	inserter db.BatchInserter
+}
+
+// NewDBTarget 基于数据库连接创建目标库，连接需支持事务内批量插入。
+func NewDBTarget(conn db.Database) (Target, error) {
+	inserter, ok := conn.(db.BatchInserter)
+	if !ok {
+		return nil, fmt.Errorf("目标数据库不支持批量写入")
+	}
+	return &dbTarget{conn: conn, inserter: inserter}, nil
+}
+
+// Exec 执行 DDL 语句。
+func (t *dbTarget) Exec(ctx context.Context, stmt string) error {
+	_, err := t.conn.Exec(stmt)
+	return err
+}
+
+// Insert 在单个事务内写入一批行，任一行失败时整批回滚并返回首个失败原因。
+func (t *dbTarget) Insert(ctx context.Context, table string, columns []string, rows [][]any) error {
+	report, err := t.inserter.InsertRows(ctx, table, columns, rows, &connection.ImportOptions{MaxErrors: 1}, nil)
+	if err != nil {
+		return err
+	}
+	if !report.Committed {
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("本批第 %d 行写入失败：%s", report.Errors[0].Row, report.Errors[0].Message)
+		}
+		return fmt.Errorf("写入未提交")
+	}
+	return nil
+}
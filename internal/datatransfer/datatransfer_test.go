@@ -0,0 +1,217 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestMapColumnType(t *testing.T) {
+	my, pg := connection.ConnectionTypeMySQL, connection.ConnectionTypePostgreSQL
+	cases := []struct {
+		from, to connection.ConnectionType
+		in, want string
+	}{
+		{my, pg, "tinyint(1)", "boolean"},
+		{my, pg, "int(10) unsigned", "bigint"},
+		{my, pg, "bigint unsigned", "numeric(20)"},
+		{my, pg, "decimal(10,2)", "numeric(10,2)"},
+		{my, pg, "datetime(3)", "timestamp(3)"},
+		{my, pg, "enum('a','b')", "text"},
+		{my, pg, "longblob", "bytea"},
+		{my, pg, "json", "jsonb"},
+		{pg, my, "character varying(64)", "varchar(64)"},
+		{pg, my, "character varying", "longtext"},
+		{pg, my, "double precision", "double"},
+		{pg, my, "timestamp with time zone", "datetime(6)"},
+		{pg, my, "timestamp(3) without time zone", "datetime(3)"},
+		{pg, my, "uuid", "char(36)"},
+		{pg, my, "integer[]", "json"},
+		{pg, my, "tsvector", "longtext"},
+		{my, connection.ConnectionTypeMariaDB, "int(11)", "int(11)"},
+	}
+	for _, c := range cases {
+		if got := MapColumnType(c.from, c.to, c.in); got != c.want {
+			t.Errorf("%s -> %s: %q 得到 %q，期望 %q", c.from, c.to, c.in, got, c.want)
+		}
+	}
+}
+
+func TestMapTableDefinition(t *testing.T) {
+	def := "0"
+	columns := []*connection.ColumnDefinition{
+		{Name: "id", Type: "bigint", Nullable: "NO", Key: "PRI", Extra: "auto_increment"},
+		{Name: "tenant", Type: "int", Nullable: "NO", Key: "PRI"},
+		{Name: "body", Type: "text", Nullable: "YES", Default: &def},
+	}
+	indexes := []*connection.IndexDefinition{
+		{Name: "PRIMARY", ColumnName: "tenant", SeqInIndex: 2},
+		{Name: "PRIMARY", ColumnName: "id", SeqInIndex: 1},
+		{Name: "ft_body", ColumnName: "body", NonUnique: 1, SeqInIndex: 1, IndexType: "FULLTEXT"},
+	}
+	got := MapTableDefinition(connection.ConnectionTypeMySQL, connection.ConnectionTypePostgreSQL, "t", columns, indexes)
+	if strings.Join(got.PrimaryKey, ",") != "id,tenant" {
+		t.Errorf("主键顺序不符: %v", got.PrimaryKey)
+	}
+	if len(got.Indexes) != 0 {
+		t.Errorf("目标方言不支持的 FULLTEXT 索引应被忽略: %+v", got.Indexes)
+	}
+	if got.Columns[0].Extra != "auto_increment" || got.Columns[2].Default != nil {
+		t.Errorf("跨方言应保留自增、丢弃默认值: %+v %+v", got.Columns[0], got.Columns[2])
+	}
+}
+
+// fakeSource 以内存行模拟按键分页的源表，第一列为整数主键。
+type fakeSource struct {
+	rows  [][]any
+	reads int
+}
+
+func (f *fakeSource) Describe(ctx context.Context) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error) {
+	return []*connection.ColumnDefinition{
+		{Name: "id", Type: "int", Nullable: "NO", Key: "PRI"},
+		{Name: "name", Type: "varchar(10)", Nullable: "YES"},
+	}, []*connection.IndexDefinition{
+		{Name: "PRIMARY", ColumnName: "id", SeqInIndex: 1},
+	}, nil
+}
+
+func (f *fakeSource) CountRows(ctx context.Context) (int64, error) {
+	return int64(len(f.rows)), nil
+}
+
+func (f *fakeSource) ReadPage(ctx context.Context, columns, keyColumns []string, after []any, offset int64, limit int) ([][]any, error) {
+	f.reads++
+	var last int64 = -1
+	if len(after) == 1 {
+		last, _ = strconv.ParseInt(after[0].(string), 10, 64)
+	}
+	var page [][]any
+	for _, row := range f.rows {
+		if row[0].(int64) > last && len(page) < limit {
+			page = append(page, append([]any(nil), row...))
+		}
+	}
+	return page, nil
+}
+
+// fakeTarget 记录写入的行，failAt 指定第几次写入失败（从 1 开始，0 表示不失败）。
+type fakeTarget struct {
+	stmts   []string
+	rows    [][]any
+	inserts int
+	failAt  int
+}
+
+func (f *fakeTarget) Exec(ctx context.Context, stmt string) error {
+	f.stmts = append(f.stmts, stmt)
+	return nil
+}
+
+func (f *fakeTarget) Insert(ctx context.Context, table string, columns []string, rows [][]any) error {
+	f.inserts++
+	if f.inserts == f.failAt {
+		return errors.New("disk full")
+	}
+	f.rows = append(f.rows, rows...)
+	return nil
+}
+
+func TestRunnerResumesFromCheckpoint(t *testing.T) {
+	store := NewStore(t.TempDir(), nil)
+	runner := NewRunner(store, nil)
+
+	src := &fakeSource{}
+	for i := int64(1); i <= 25; i++ {
+		src.rows = append(src.rows, []any{i, []byte("n" + strconv.FormatInt(i, 10))})
+	}
+	job, err := NewJob(&Request{
+		Source: &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "a", Port: 3306, User: "u"}, SourceTable: "users",
+		Target:     &connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL, Host: "b", Port: 5432, User: "u"},
+		CopySchema: true, CopyData: true, BatchSize: 10,
+	})
+	if err != nil {
+		t.Fatalf("NewJob 失败: %v", err)
+	}
+
+	dst := &fakeTarget{failAt: 2}
+	var progress []int64
+	if err := runner.Run(context.Background(), job, src, dst, func(j *Job) { progress = append(progress, j.RowsCopied) }); err == nil {
+		t.Fatal("第二批写入失败时应返回错误")
+	}
+	saved, err := store.Get(job.ID)
+	if err != nil {
+		t.Fatalf("读取任务失败: %v", err)
+	}
+	if saved.Status != StatusFailed || saved.RowsCopied != 10 || strings.Join(saved.After, ",") != "10" || !saved.SchemaCreated {
+		t.Fatalf("失败后的断点不符: %+v", saved)
+	}
+	if len(dst.stmts) != 1 || !strings.Contains(dst.stmts[0], `CREATE TABLE "users"`) {
+		t.Errorf("应按目标方言建表: %v", dst.stmts)
+	}
+	if _, ok := dst.rows[0][1].(string); !ok {
+		t.Errorf("非二进制列的字节值应转为字符串: %T", dst.rows[0][1])
+	}
+
+	dst.failAt = 0
+	if err := runner.Run(context.Background(), saved, src, dst, nil); err != nil {
+		t.Fatalf("继续任务失败: %v", err)
+	}
+	if saved.Status != StatusCompleted || saved.RowsCopied != 25 || len(dst.rows) != 25 || len(dst.stmts) != 1 {
+		t.Errorf("继续后状态不符: status=%s copied=%d written=%d stmts=%d", saved.Status, saved.RowsCopied, len(dst.rows), len(dst.stmts))
+	}
+	if len(progress) == 0 {
+		t.Error("应推送进度")
+	}
+}
+
+func TestRunnerCancelKeepsCheckpoint(t *testing.T) {
+	store := NewStore(t.TempDir(), nil)
+	src := &fakeSource{rows: [][]any{{int64(1), "a"}}}
+	job := &Job{ID: "j1", TargetTable: "t", CopyData: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewRunner(store, nil).Run(ctx, job, src, &fakeTarget{}, nil)
+	if !errors.Is(err, context.Canceled) || job.Status != StatusCancelled || !job.Resumable() {
+		t.Errorf("取消后状态不符: err=%v status=%s", err, job.Status)
+	}
+
+	jobs, _ := store.List()
+	if len(jobs) != 1 || jobs[0].ID != "j1" {
+		t.Errorf("任务应已保存: %+v", jobs)
+	}
+	if err := store.Delete("j1"); err != nil {
+		t.Errorf("删除失败: %v", err)
+	}
+	if _, err := store.Get("j1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("删除后应返回 ErrNotFound: %v", err)
+	}
+}
+
+func TestJobCloneIsIndependent(t *testing.T) {
+	job := &Job{ID: "j1", Status: StatusRunning, Columns: []string{"id"}, KeyColumns: []string{"id"}, After: []string{"10"}, RowsCopied: 10}
+	snapshot := job.Clone()
+	job.After[0], job.RowsCopied, job.Status = "20", 20, StatusCompleted
+	if snapshot.After[0] != "10" || snapshot.RowsCopied != 10 || snapshot.Status != StatusRunning {
+		t.Errorf("快照不应随任务变化: %+v", snapshot)
+	}
+}
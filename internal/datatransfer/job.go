@@ -0,0 +1,136 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/google/uuid"
+)
+
+// 任务状态。
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Job 是一次跨连接表复制任务及其断点。
+//
+// 任务文件不保存连接密码，只记录连接标识用于恢复时核对；恢复时由调用方重新提供连接配置。
+type Job struct {
+	ID             string                    `json:"id"`
+	Status         string                    `json:"status"`
+	SourceKey      string                    `json:"sourceKey"`
+	SourceType     connection.ConnectionType `json:"sourceType"`
+	SourceDatabase string                    `json:"sourceDatabase"`
+	SourceTable    string                    `json:"sourceTable"`
+	TargetKey      string                    `json:"targetKey"`
+	TargetType     connection.ConnectionType `json:"targetType"`
+	TargetDatabase string                    `json:"targetDatabase"`
+	TargetTable    string                    `json:"targetTable"`
+	CopySchema     bool                      `json:"copySchema"`
+	CopyData       bool                      `json:"copyData"`
+	BatchSize      int                       `json:"batchSize"`
+
+	Columns       []string `json:"columns,omitempty"`    // 复制的列（首次运行时确定）
+	KeyColumns    []string `json:"keyColumns,omitempty"` // 分页键，为空时按 OFFSET 分页
+	SchemaCreated bool     `json:"schemaCreated"`        // 目标表是否已由任务创建
+	After         []string `json:"after,omitempty"`      // 已提交的最后一行键值
+	Offset        int64    `json:"offset"`               // 无键表已提交的行数
+	RowsCopied    int64    `json:"rowsCopied"`
+	TotalRows     int64    `json:"totalRows"` // 开始复制时的源表行数，-1 表示未知
+
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Clone 返回任务的深拷贝，供执行期间对外返回或推送快照。
+func (j *Job) Clone() *Job {
+	clone := *j
+	clone.Columns = append([]string(nil), j.Columns...)
+	clone.KeyColumns = append([]string(nil), j.KeyColumns...)
+	clone.After = append([]string(nil), j.After...)
+	return &clone
+}
+
+// Resumable 判断任务是否可以从断点继续。
+func (j *Job) Resumable() bool {
+	return j.Status == StatusFailed || j.Status == StatusCancelled || j.Status == StatusPending
+}
+
+// Request 是创建复制任务的参数，TargetTable 为空时与源表同名。
+type Request struct {
+	Source         *connection.ConnectionConfig `json:"source"`
+	SourceDatabase string                       `json:"sourceDatabase"`
+	SourceTable    string                       `json:"sourceTable"`
+	Target         *connection.ConnectionConfig `json:"target"`
+	TargetDatabase string                       `json:"targetDatabase"`
+	TargetTable    string                       `json:"targetTable"`
+	CopySchema     bool                         `json:"copySchema"` // 在目标库创建表（按目标方言映射列类型）
+	CopyData       bool                         `json:"copyData"`   // 复制行数据
+	BatchSize      int                          `json:"batchSize"`  // 每批行数，<=0 使用 DefaultBatchSize
+}
+
+// NewJob 根据请求创建待执行的任务。
+func NewJob(req *Request) (*Job, error) {
+	if req == nil || req.Source == nil || req.Target == nil {
+		return nil, fmt.Errorf("源连接与目标连接不能为空")
+	}
+	if !req.CopySchema && !req.CopyData {
+		return nil, fmt.Errorf("至少需要复制表结构或数据中的一项")
+	}
+	targetTable := req.TargetTable
+	if targetTable == "" {
+		targetTable = req.SourceTable
+	}
+	job := &Job{
+		ID:             uuid.New().String(),
+		Status:         StatusPending,
+		SourceKey:      ConnectionKey(req.Source),
+		SourceType:     req.Source.Type,
+		SourceDatabase: req.SourceDatabase,
+		SourceTable:    req.SourceTable,
+		TargetKey:      ConnectionKey(req.Target),
+		TargetType:     req.Target.Type,
+		TargetDatabase: req.TargetDatabase,
+		TargetTable:    targetTable,
+		CopySchema:     req.CopySchema,
+		CopyData:       req.CopyData,
+		BatchSize:      req.BatchSize,
+		CreatedAt:      time.Now(),
+	}
+	if job.SourceKey == job.TargetKey && job.SourceDatabase == job.TargetDatabase && job.SourceTable == job.TargetTable {
+		return nil, fmt.Errorf("源表与目标表不能相同")
+	}
+	return job, nil
+}
+
+// ConnectionKey 返回不含凭据的连接标识，用于恢复任务时核对连接。
+func ConnectionKey(config *connection.ConnectionConfig) string {
+	if config == nil {
+		return ""
+	}
+	dbType := string(config.Type)
+	if dbType == "" {
+		dbType = string(connection.ConnectionTypeMySQL)
+	}
+	return fmt.Sprintf("%s://%s@%s:%d", dbType, config.User, config.Host, config.Port)
+}
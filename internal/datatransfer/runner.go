@@ -0,0 +1,235 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// DefaultBatchSize 是每批复制的行数。
+const DefaultBatchSize = 1000
+
+// MaxBatchSize 是允许的最大批大小。
+const MaxBatchSize = 10000
+
+// Source 是复制的源表。
+type Source interface {
+	// Describe 返回源表的列与索引定义
+	Describe(ctx context.Context) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error)
+	// CountRows 返回源表当前行数
+	CountRows(ctx context.Context) (int64, error)
+	// ReadPage 按键（或 OFFSET）读取一页原始行数据，列顺序与 columns 一致
+	ReadPage(ctx context.Context, columns, keyColumns []string, after []any, offset int64, limit int) ([][]any, error)
+}
+
+// Target 是复制的目标库。
+type Target interface {
+	// Exec 执行 DDL 语句
+	Exec(ctx context.Context, stmt string) error
+	// Insert 在单个事务内写入一批行，失败时整批回滚
+	Insert(ctx context.Context, table string, columns []string, rows [][]any) error
+}
+
+// Runner 执行复制任务，每批写入成功后保存断点，失败或取消的任务可从断点继续。
+type Runner struct {
+	store  *Store
+	logger *slog.Logger
+}
+
+// NewRunner 创建任务执行器。
+func NewRunner(store *Store, logger *slog.Logger) *Runner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Runner{store: store, logger: logger.With("module", "datatransfer")}
+}
+
+// Run 执行（或继续执行）任务；onProgress 在每次状态或进度变化后调用，可为空。
+// ctx 取消时任务以 cancelled 状态结束并保留断点。
+func (r *Runner) Run(ctx context.Context, job *Job, src Source, dst Target, onProgress func(*Job)) (err error) {
+	notify := func() {
+		if saveErr := r.store.Save(job); saveErr != nil {
+			r.logger.Error("保存复制任务断点失败", "id", job.ID, "error", saveErr)
+		}
+		if onProgress != nil {
+			onProgress(job)
+		}
+	}
+	defer func() {
+		switch {
+		case err == nil:
+			job.Status = StatusCompleted
+			job.Error = ""
+		case errors.Is(err, context.Canceled):
+			job.Status = StatusCancelled
+			job.Error = ""
+		default:
+			job.Status = StatusFailed
+			job.Error = err.Error()
+		}
+		notify()
+		r.logger.Info("复制任务结束", "id", job.ID, "status", job.Status, "rows", job.RowsCopied, "error", job.Error)
+	}()
+
+	job.Status = StatusRunning
+	job.Error = ""
+	notify()
+
+	columns, indexes, err := src.Describe(ctx)
+	if err != nil {
+		return fmt.Errorf("读取源表结构失败：%w", err)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("源表不存在或没有列: %s", job.SourceTable)
+	}
+	if len(job.Columns) == 0 {
+		for _, col := range columns {
+			job.Columns = append(job.Columns, col.Name)
+		}
+		if key := db.ResolveTableKey(indexes, columns); key.Kind != db.TableKeyNone {
+			job.KeyColumns = key.Columns
+		}
+	}
+
+	if job.CopySchema && !job.SchemaCreated {
+		if err := r.createTable(ctx, job, columns, indexes, dst); err != nil {
+			return err
+		}
+		job.SchemaCreated = true
+		notify()
+	}
+	if !job.CopyData {
+		return nil
+	}
+	return r.copyRows(ctx, job, columns, src, dst, notify)
+}
+
+// createTable 按目标方言在目标库创建表。
+func (r *Runner) createTable(ctx context.Context, job *Job, columns []*connection.ColumnDefinition, indexes []*connection.IndexDefinition, dst Target) error {
+	builder, err := db.NewDDLBuilder(job.TargetType)
+	if err != nil {
+		return err
+	}
+	def := MapTableDefinition(job.SourceType, job.TargetType, job.TargetTable, columns, indexes)
+	stmts, err := builder.CreateTable("", def)
+	if err != nil {
+		return fmt.Errorf("生成目标表结构失败：%w", err)
+	}
+	for _, stmt := range stmts {
+		if err := dst.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("创建目标表失败：%w", err)
+		}
+	}
+	return nil
+}
+
+// copyRows 分批读取并写入数据，每批提交后推进断点。
+func (r *Runner) copyRows(ctx context.Context, job *Job, columns []*connection.ColumnDefinition, src Source, dst Target, notify func()) error {
+	batchSize := job.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if job.RowsCopied == 0 {
+		if total, err := src.CountRows(ctx); err == nil {
+			job.TotalRows = total
+		} else {
+			job.TotalRows = -1
+			r.logger.Warn("统计源表行数失败", "id", job.ID, "error", err)
+		}
+	}
+
+	binary := make([]bool, len(job.Columns))
+	keyPos := make([]int, len(job.KeyColumns))
+	for i, name := range job.Columns {
+		for _, col := range columns {
+			if col.Name == name {
+				binary[i] = IsBinaryType(col.Type)
+			}
+		}
+		for k, key := range job.KeyColumns {
+			if key == name {
+				keyPos[k] = i
+			}
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		after := make([]any, len(job.After))
+		for i, v := range job.After {
+			after[i] = v
+		}
+		rows, err := src.ReadPage(ctx, job.Columns, job.KeyColumns, after, job.Offset, batchSize)
+		if err != nil {
+			return fmt.Errorf("读取源数据失败：%w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		for _, row := range rows {
+			for i, v := range row {
+				if b, ok := v.([]byte); ok && !binary[i] {
+					row[i] = string(b)
+				}
+			}
+		}
+		if err := dst.Insert(ctx, job.TargetTable, job.Columns, rows); err != nil {
+			return fmt.Errorf("写入目标数据失败（已提交 %d 行）：%w", job.RowsCopied, err)
+		}
+
+		last := rows[len(rows)-1]
+		if len(job.KeyColumns) > 0 {
+			job.After = make([]string, len(keyPos))
+			for k, pos := range keyPos {
+				job.After[k] = checkpointValue(last[pos])
+			}
+		} else {
+			job.Offset += int64(len(rows))
+		}
+		job.RowsCopied += int64(len(rows))
+		notify()
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// checkpointValue 将键值转换为可持久化的文本，恢复时作为查询参数使用。
+func checkpointValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(x)
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case time.Time:
+		return x.Format("2006-01-02 15:04:05.999999")
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 表示任务不存在。
+var ErrNotFound = errors.New("复制任务不存在")
+
+// Store 以每个任务一个 JSON 文件的形式保存复制任务。
+type Store struct {
+	mu     sync.Mutex
+	dir    string       // 存储目录
+	logger *slog.Logger // 日志记录器
+}
+
+// DefaultDir 返回默认任务存储目录。
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "transfers")
+	}
+	return filepath.Join(configDir, "Boxify", "transfers")
+}
+
+// NewStore 创建任务存储，dir 为空时使用默认目录。
+func NewStore(dir string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{dir: dir, logger: logger.With("module", "datatransfer")}
+}
+
+// Save 保存任务，先写临时文件再替换，避免写入中断损坏断点。
+func (s *Store) Save(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("创建任务目录失败：%w", err)
+	}
+	job.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化复制任务失败：%w", err)
+	}
+	path := s.path(job.ID)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入复制任务失败：%w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("写入复制任务失败：%w", err)
+	}
+	return nil
+}
+
+// Get 读取任务。
+func (s *Store) Get(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(s.path(id))
+}
+
+// List 按更新时间倒序列出全部任务，无法解析的文件会被跳过。
+func (s *Store) List() ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(paths))
+	for _, p := range paths {
+		job, err := s.read(p)
+		if err != nil {
+			s.logger.Warn("跳过无法读取的复制任务", "path", p, "error", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].UpdatedAt.After(jobs[j].UpdatedAt) })
+	return jobs, nil
+}
+
+// Delete 删除任务。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// read 读取并解析任务文件。
+func (s *Store) read(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("解析复制任务失败：%w", err)
+	}
+	return &job, nil
+}
+
+// path 返回任务文件路径，ID 中的路径分隔符会被替换以防越出存储目录。
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, strings.NewReplacer("/", "_", `\`, "_", "..", "_").Replace(id)+".json")
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatransfer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// 方言族：同一族内的数据库类型可以直接复用列类型。
+const (
+	familyMySQL    = "mysql"
+	familyPostgres = "postgres"
+)
+
+// dialectFamily 返回数据库类型所属方言族，空类型按 MySQL 处理。
+func dialectFamily(dbType connection.ConnectionType) string {
+	switch dbType {
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		return familyPostgres
+	default:
+		return familyMySQL
+	}
+}
+
+// SameDialectFamily 判断两种数据库是否属于同一方言族，同族之间迁移时列类型与默认值原样保留。
+func SameDialectFamily(a, b connection.ConnectionType) bool {
+	return dialectFamily(a) == dialectFamily(b)
+}
+
+// typePattern 拆分列类型为类型名、括号参数与修饰词（如 unsigned、with time zone）。
+var typePattern = regexp.MustCompile(`^\s*([a-z][a-z0-9_]*(?: [a-z][a-z0-9_]*)*?)\s*(\(([^)]*)\))?((?:\s+[a-z][a-z ]*)?)\s*(\[\])?\s*$`)
+
+// MapColumnType 将源库列类型转换为目标库类型；同一方言族之间原样返回，无法识别的类型转为文本类型。
+func MapColumnType(from, to connection.ConnectionType, colType string) string {
+	if dialectFamily(from) == dialectFamily(to) {
+		return colType
+	}
+	m := typePattern.FindStringSubmatch(strings.ToLower(colType))
+	if m == nil {
+		return fallbackType(to)
+	}
+	name, args, modifier, array := m[1], m[3], strings.TrimSpace(m[4]), m[5] != ""
+	// 多词类型名在无参数时会被拆成类型名与修饰词，这里重新合并
+	for _, multi := range []string{"character varying", "double precision", "bit varying"} {
+		if full := strings.TrimSpace(name + " " + modifier); strings.HasPrefix(full, multi) {
+			name, modifier = multi, strings.TrimSpace(strings.TrimPrefix(full, multi))
+		}
+	}
+	if dialectFamily(to) == familyPostgres {
+		return mysqlToPostgres(name, args, modifier)
+	}
+	if array {
+		return "json"
+	}
+	return postgresToMySQL(name, args, modifier)
+}
+
+// fallbackType 返回目标库的通用文本类型。
+func fallbackType(to connection.ConnectionType) string {
+	if dialectFamily(to) == familyPostgres {
+		return "text"
+	}
+	return "longtext"
+}
+
+// withArgs 在参数非空时追加括号参数。
+func withArgs(name, args string) string {
+	if args == "" {
+		return name
+	}
+	return name + "(" + args + ")"
+}
+
+// mysqlToPostgres 将 MySQL 列类型转换为 PostgreSQL 类型。
+func mysqlToPostgres(name, args, modifier string) string {
+	unsigned := strings.Contains(modifier, "unsigned")
+	switch name {
+	case "tinyint":
+		if args == "1" {
+			return "boolean"
+		}
+		return "smallint"
+	case "bool", "boolean":
+		return "boolean"
+	case "smallint":
+		if unsigned {
+			return "integer"
+		}
+		return "smallint"
+	case "mediumint":
+		return "integer"
+	case "int", "integer":
+		if unsigned {
+			return "bigint"
+		}
+		return "integer"
+	case "bigint":
+		if unsigned {
+			return "numeric(20)"
+		}
+		return "bigint"
+	case "decimal", "numeric", "dec", "fixed":
+		return withArgs("numeric", args)
+	case "float":
+		return "real"
+	case "double", "double precision", "real":
+		return "double precision"
+	case "bit":
+		if args == "" || args == "1" {
+			return "boolean"
+		}
+		return withArgs("bit varying", args)
+	case "char":
+		return withArgs("char", args)
+	case "varchar":
+		return withArgs("varchar", args)
+	case "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return "text"
+	case "json":
+		return "jsonb"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "bytea"
+	case "date":
+		return "date"
+	case "datetime", "timestamp":
+		return withArgs("timestamp", args)
+	case "time":
+		return withArgs("time", args)
+	case "year":
+		return "smallint"
+	default:
+		return "text"
+	}
+}
+
+// postgresToMySQL 将 PostgreSQL 列类型转换为 MySQL 类型。
+func postgresToMySQL(name, args, modifier string) string {
+	switch name {
+	case "boolean", "bool":
+		return "tinyint(1)"
+	case "smallint", "int2", "smallserial":
+		return "smallint"
+	case "integer", "int", "int4", "serial":
+		return "int"
+	case "bigint", "int8", "bigserial":
+		return "bigint"
+	case "numeric", "decimal":
+		if args == "" {
+			return "decimal(65,30)"
+		}
+		return withArgs("decimal", args)
+	case "real", "float4":
+		return "float"
+	case "double precision", "float8", "float":
+		return "double"
+	case "character varying", "varchar":
+		if args == "" {
+			return "longtext"
+		}
+		return withArgs("varchar", args)
+	case "character", "char", "bpchar":
+		return withArgs("char", args)
+	case "text", "citext", "xml":
+		return "longtext"
+	case "json", "jsonb":
+		return "json"
+	case "bytea":
+		return "longblob"
+	case "uuid":
+		return "char(36)"
+	case "date":
+		return "date"
+	case "timestamp", "timestamptz":
+		// MySQL DATETIME 不带时区，带时区的值按会话时区写入
+		if args == "" {
+			return "datetime(6)"
+		}
+		return withArgs("datetime", args)
+	case "time", "timetz":
+		return "time(6)"
+	case "interval", "inet", "cidr", "macaddr", "macaddr8":
+		return "varchar(64)"
+	default:
+		return "longtext"
+	}
+}
+
+// IsBinaryType 判断列类型是否为二进制类型，二进制列的值复制时保留字节。
+func IsBinaryType(colType string) bool {
+	t := strings.ToLower(colType)
+	for _, prefix := range []string{"binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "bytea", "bit", "geometry", "point", "linestring", "polygon"} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapTableDefinition 根据源表的列与索引生成目标库的建表定义。
+//
+// 跨方言时列默认值不复制（表达式语义不通用），自增列按目标方言重建；
+// 主键取自 PRIMARY 索引或列的 PRI 标记，目标方言不支持的索引类型会被忽略。
+func MapTableDefinition(from, to connection.ConnectionType, table string, columns []*connection.ColumnDefinition, indexes []*connection.IndexDefinition) *connection.TableDefinition {
+	sameFamily := dialectFamily(from) == dialectFamily(to)
+	def := &connection.TableDefinition{Name: table}
+
+	for _, col := range columns {
+		mapped := &connection.ColumnDefinition{
+			Name:     col.Name,
+			Type:     MapColumnType(from, to, col.Type),
+			Nullable: col.Nullable,
+			Key:      col.Key,
+			Comment:  col.Comment,
+		}
+		autoInc := strings.Contains(strings.ToLower(col.Extra), "auto_increment") ||
+			(col.Default != nil && strings.HasPrefix(strings.ToLower(*col.Default), "nextval("))
+		if autoInc {
+			mapped.Extra = "auto_increment"
+		} else if sameFamily {
+			mapped.Default = col.Default
+			mapped.Extra = col.Extra
+		}
+		def.Columns = append(def.Columns, mapped)
+	}
+
+	var primary []*connection.IndexDefinition
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Name, "PRIMARY") {
+			primary = append(primary, idx)
+			continue
+		}
+		mapped := *idx
+		if !sameFamily {
+			switch strings.ToUpper(idx.IndexType) {
+			case "BTREE", "HASH", "":
+			default:
+				continue
+			}
+		}
+		def.Indexes = append(def.Indexes, &mapped)
+	}
+	sort.SliceStable(primary, func(i, j int) bool { return primary[i].SeqInIndex < primary[j].SeqInIndex })
+	for _, idx := range primary {
+		def.PrimaryKey = append(def.PrimaryKey, idx.ColumnName)
+	}
+	return def
+}
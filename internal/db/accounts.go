@@ -0,0 +1,203 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// AccountManager 定义数据库账号与权限管理能力。
+type AccountManager interface {
+	GetUsers() ([]*connection.DBUser, error)
+	GetGrants(user, host string) ([]string, error)
+	CreateUser(spec *connection.UserSpec) error
+	GrantPrivileges(spec *connection.GrantSpec) error
+}
+
+// mysqlPrivileges 是允许授予的 MySQL 权限，权限名无法参数化，只接受白名单内的写法。
+var mysqlPrivileges = map[string]bool{
+	"ALL PRIVILEGES": true, "SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+	"CREATE": true, "DROP": true, "ALTER": true, "INDEX": true, "REFERENCES": true,
+	"CREATE VIEW": true, "SHOW VIEW": true, "TRIGGER": true, "EXECUTE": true,
+	"CREATE ROUTINE": true, "ALTER ROUTINE": true, "EVENT": true, "LOCK TABLES": true,
+	"CREATE TEMPORARY TABLES": true, "PROCESS": true, "RELOAD": true, "SHOW DATABASES": true,
+	"REPLICATION CLIENT": true, "REPLICATION SLAVE": true, "CREATE USER": true,
+}
+
+// postgresTablePrivileges 与 postgresDatabasePrivileges 分别是表级与库级可授予的 PostgreSQL 权限。
+var (
+	postgresTablePrivileges = map[string]bool{
+		"ALL PRIVILEGES": true, "SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+		"TRUNCATE": true, "REFERENCES": true, "TRIGGER": true,
+	}
+	postgresDatabasePrivileges = map[string]bool{
+		"ALL PRIVILEGES": true, "CONNECT": true, "CREATE": true, "TEMPORARY": true,
+	}
+)
+
+// normalizePrivileges 规整权限名（大写、合并空白、ALL 视为 ALL PRIVILEGES）并按白名单校验。
+func normalizePrivileges(privileges []string, allowed map[string]bool) ([]string, error) {
+	if len(privileges) == 0 {
+		return nil, fmt.Errorf("至少需要一项权限")
+	}
+	out := make([]string, 0, len(privileges))
+	for _, p := range privileges {
+		name := strings.ToUpper(strings.Join(strings.Fields(p), " "))
+		if name == "ALL" {
+			name = "ALL PRIVILEGES"
+		}
+		if !allowed[name] {
+			return nil, fmt.Errorf("不支持的权限: %s", p)
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// mysqlAccount 返回 'user'@'host' 形式的账号名。
+func mysqlAccount(user, host string) string {
+	if host == "" {
+		host = "%"
+	}
+	return quoteMySQLString(user) + "@" + quoteMySQLString(host)
+}
+
+// mysqlCreateUserStatement 生成 MySQL CREATE USER 语句，语句含明文密码，不得写入日志。
+func mysqlCreateUserStatement(spec *connection.UserSpec) (string, error) {
+	if spec == nil || strings.TrimSpace(spec.User) == "" {
+		return "", fmt.Errorf("用户名不能为空")
+	}
+	stmt := "CREATE USER " + mysqlAccount(spec.User, spec.Host)
+	if spec.Password != "" {
+		stmt += " IDENTIFIED BY " + quoteMySQLString(spec.Password)
+	}
+	return stmt, nil
+}
+
+// mysqlGrantStatement 生成 MySQL GRANT 语句。
+func mysqlGrantStatement(spec *connection.GrantSpec) (string, error) {
+	if spec == nil || strings.TrimSpace(spec.User) == "" {
+		return "", fmt.Errorf("用户名不能为空")
+	}
+	privileges, err := normalizePrivileges(spec.Privileges, mysqlPrivileges)
+	if err != nil {
+		return "", err
+	}
+	target := "*.*"
+	switch {
+	case spec.Database != "" && spec.Table != "":
+		target = quoteMySQLIdent(spec.Database) + "." + quoteMySQLIdent(spec.Table)
+	case spec.Database != "":
+		target = quoteMySQLIdent(spec.Database) + ".*"
+	case spec.Table != "":
+		return "", fmt.Errorf("授予表级权限时必须指定数据库")
+	}
+	stmt := "GRANT " + strings.Join(privileges, ", ") + " ON " + target + " TO " + mysqlAccount(spec.User, spec.Host)
+	if spec.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt, nil
+}
+
+// postgresCreateUserStatement 生成 PostgreSQL CREATE USER 语句，语句含明文密码，不得写入日志。
+func postgresCreateUserStatement(spec *connection.UserSpec) (string, error) {
+	if spec == nil || strings.TrimSpace(spec.User) == "" {
+		return "", fmt.Errorf("用户名不能为空")
+	}
+	stmt := "CREATE USER " + quotePgIdent(spec.User) + " WITH LOGIN"
+	if spec.Password != "" {
+		stmt += " PASSWORD " + quotePgString(spec.Password)
+	}
+	return stmt, nil
+}
+
+// postgresGrantStatement 生成 PostgreSQL GRANT 语句；指定表时授予表级权限，否则授予库级权限。
+func postgresGrantStatement(spec *connection.GrantSpec) (string, error) {
+	if spec == nil || strings.TrimSpace(spec.User) == "" {
+		return "", fmt.Errorf("用户名不能为空")
+	}
+	var target string
+	var privileges []string
+	var err error
+	switch {
+	case spec.Table != "":
+		privileges, err = normalizePrivileges(spec.Privileges, postgresTablePrivileges)
+		target = "TABLE " + qualified(spec.Schema, spec.Table, quotePgIdent)
+	case spec.Database != "":
+		privileges, err = normalizePrivileges(spec.Privileges, postgresDatabasePrivileges)
+		target = "DATABASE " + quotePgIdent(spec.Database)
+	default:
+		return "", fmt.Errorf("必须指定数据库或表")
+	}
+	if err != nil {
+		return "", err
+	}
+	stmt := "GRANT " + strings.Join(privileges, ", ") + " ON " + target + " TO " + quotePgIdent(spec.User)
+	if spec.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt, nil
+}
+
+const pgUsersSQL = `SELECT rolname, rolsuper, rolcanlogin, rolvaliduntil IS NOT NULL AND rolvaliduntil < now() AS expired
+FROM pg_roles
+WHERE rolname NOT LIKE 'pg\_%'
+ORDER BY rolname`
+
+const pgGrantsSQL = `SELECT table_schema, table_name, string_agg(privilege_type, ', ' ORDER BY privilege_type) AS privileges,
+       bool_or(is_grantable = 'YES') AS grantable
+FROM information_schema.role_table_grants
+WHERE grantee = $1
+GROUP BY table_schema, table_name
+ORDER BY table_schema, table_name`
+
+// queryPostgresUsers 读取 PostgreSQL 角色列表，排除 pg_ 开头的内置角色。
+func queryPostgresUsers(query queryFunc) ([]*connection.DBUser, error) {
+	rows, _, err := query(pgUsersSQL)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*connection.DBUser, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, &connection.DBUser{
+			User:            fmt.Sprint(row["rolname"]),
+			Superuser:       boolValue(row["rolsuper"]),
+			CanLogin:        boolValue(row["rolcanlogin"]),
+			PasswordExpired: boolValue(row["expired"]),
+		})
+	}
+	return users, nil
+}
+
+// queryPostgresGrants 读取角色的表级权限，并还原为 GRANT 语句。
+func queryPostgresGrants(query queryFunc, user string) ([]string, error) {
+	rows, _, err := query(pgGrantsSQL, user)
+	if err != nil {
+		return nil, err
+	}
+	grants := make([]string, 0, len(rows))
+	for _, row := range rows {
+		stmt := "GRANT " + fmt.Sprint(row["privileges"]) + " ON TABLE " +
+			qualified(fmt.Sprint(row["table_schema"]), fmt.Sprint(row["table_name"]), quotePgIdent) + " TO " + quotePgIdent(user)
+		if boolValue(row["grantable"]) {
+			stmt += " WITH GRANT OPTION"
+		}
+		grants = append(grants, stmt)
+	}
+	return grants, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// GetUsers 读取 mysql.user 中的账号列表，需要当前账号具备 mysql 库的读取权限
+func (m *MySQLDB) GetUsers() ([]*connection.DBUser, error) {
+	data, _, err := m.Query("SELECT * FROM mysql.user ORDER BY User, Host")
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*connection.DBUser, 0, len(data))
+	for _, row := range data {
+		// 不同版本的 mysql.user 列不完全一致（如 MariaDB 缺少 account_locked），缺失时按默认值处理
+		users = append(users, &connection.DBUser{
+			User:            fmt.Sprintf("%v", row["User"]),
+			Host:            fmt.Sprintf("%v", row["Host"]),
+			Plugin:          stringOrEmpty(row["plugin"]),
+			Locked:          strings.EqualFold(stringOrEmpty(row["account_locked"]), "Y"),
+			PasswordExpired: strings.EqualFold(stringOrEmpty(row["password_expired"]), "Y"),
+			Superuser:       strings.EqualFold(stringOrEmpty(row["Super_priv"]), "Y"),
+			CanLogin:        true,
+		})
+	}
+	return users, nil
+}
+
+// GetGrants 返回 SHOW GRANTS 的结果，每项为一条 GRANT 语句
+func (m *MySQLDB) GetGrants(user, host string) ([]string, error) {
+	data, columns, err := m.Query("SHOW GRANTS FOR " + mysqlAccount(user, host))
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	grants := make([]string, 0, len(data))
+	for _, row := range data {
+		grants = append(grants, fmt.Sprintf("%v", row[columns[0]]))
+	}
+	return grants, nil
+}
+
+// CreateUser 创建账号
+func (m *MySQLDB) CreateUser(spec *connection.UserSpec) error {
+	stmt, err := mysqlCreateUserStatement(spec)
+	if err != nil {
+		return err
+	}
+	_, err = m.Exec(stmt)
+	return err
+}
+
+// GrantPrivileges 为账号授予权限
+func (m *MySQLDB) GrantPrivileges(spec *connection.GrantSpec) error {
+	stmt, err := mysqlGrantStatement(spec)
+	if err != nil {
+		return err
+	}
+	_, err = m.Exec(stmt)
+	return err
+}
+
+// stringOrEmpty 将查询结果转换为字符串，nil 或缺失的列返回空字符串
+func stringOrEmpty(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// GetUsers 读取 pg_roles 中的角色列表，排除 pg_ 开头的内置角色
+func (p *PostgresDB) GetUsers() ([]*connection.DBUser, error) {
+	return queryPostgresUsers(p.Query)
+}
+
+// GetGrants 返回角色的表级权限，每项为一条 GRANT 语句；PostgreSQL 角色不区分主机，host 被忽略
+func (p *PostgresDB) GetGrants(user, host string) ([]string, error) {
+	return queryPostgresGrants(p.Query, user)
+}
+
+// CreateUser 创建可登录的角色
+func (p *PostgresDB) CreateUser(spec *connection.UserSpec) error {
+	stmt, err := postgresCreateUserStatement(spec)
+	if err != nil {
+		return err
+	}
+	_, err = p.Exec(stmt)
+	return err
+}
+
+// GrantPrivileges 为角色授予库级或表级权限
+func (p *PostgresDB) GrantPrivileges(spec *connection.GrantSpec) error {
+	stmt, err := postgresGrantStatement(spec)
+	if err != nil {
+		return err
+	}
+	_, err = p.Exec(stmt)
+	return err
+}
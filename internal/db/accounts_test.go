@@ -0,0 +1,70 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestMySQLAccountStatements(t *testing.T) {
+	stmt, err := mysqlCreateUserStatement(&connection.UserSpec{User: "app", Password: "p'w"})
+	if err != nil || stmt != `CREATE USER 'app'@'%' IDENTIFIED BY 'p''w'` {
+		t.Errorf("CREATE USER 不符: %q (err=%v)", stmt, err)
+	}
+
+	stmt, err = mysqlGrantStatement(&connection.GrantSpec{
+		User: "app", Host: "10.%", Privileges: []string{"select", " show  view ", "ALL"}, Database: "shop", WithGrantOption: true,
+	})
+	want := "GRANT SELECT, SHOW VIEW, ALL PRIVILEGES ON `shop`.* TO 'app'@'10.%' WITH GRANT OPTION"
+	if err != nil || stmt != want {
+		t.Errorf("GRANT 不符: %q (err=%v)", stmt, err)
+	}
+
+	for _, spec := range []*connection.GrantSpec{
+		{User: "app", Privileges: []string{"SELECT; DROP DATABASE x"}},
+		{User: "app", Privileges: nil},
+		{User: "app", Privileges: []string{"SELECT"}, Table: "orders"},
+	} {
+		if _, err := mysqlGrantStatement(spec); err == nil {
+			t.Errorf("应拒绝 %+v", spec)
+		}
+	}
+}
+
+func TestPostgresAccountStatements(t *testing.T) {
+	stmt, err := postgresCreateUserStatement(&connection.UserSpec{User: "App", Password: "secret"})
+	if err != nil || stmt != `CREATE USER "App" WITH LOGIN PASSWORD 'secret'` {
+		t.Errorf("CREATE USER 不符: %q (err=%v)", stmt, err)
+	}
+
+	stmt, err = postgresGrantStatement(&connection.GrantSpec{User: "app", Privileges: []string{"select", "truncate"}, Schema: "public", Table: "orders"})
+	if err != nil || stmt != `GRANT SELECT, TRUNCATE ON TABLE "public"."orders" TO "app"` {
+		t.Errorf("表级 GRANT 不符: %q (err=%v)", stmt, err)
+	}
+	if _, err := postgresGrantStatement(&connection.GrantSpec{User: "app", Privileges: []string{"TRUNCATE"}, Database: "shop"}); err == nil {
+		t.Error("库级授权应拒绝表级权限")
+	}
+
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return []map[string]interface{}{{"table_schema": "public", "table_name": "orders", "privileges": "INSERT, SELECT", "grantable": "t"}}, nil, nil
+	}
+	grants, err := queryPostgresGrants(query, "app")
+	if err != nil || len(grants) != 1 || !strings.HasSuffix(grants[0], `TO "app" WITH GRANT OPTION`) {
+		t.Errorf("权限还原不符: %v (err=%v)", grants, err)
+	}
+}
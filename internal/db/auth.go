@@ -0,0 +1,220 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// TicketCache 是 Kerberos 票据缓存的探测结果。
+type TicketCache struct {
+	Type  string `json:"type"`           // FILE、KEYRING、KCM、API、DIR 或 SSPI（Windows 登录会话）
+	Path  string `json:"path,omitempty"` // 文件类缓存的路径或缓存名称
+	Found bool   `json:"found"`          // 文件类缓存存在；非文件类缓存无法检查，视为可用
+	Note  string `json:"note,omitempty"` // 说明，如未找到时的处理建议
+}
+
+// DetectTicketCache 探测当前用户的 Kerberos 票据缓存：优先 KRB5CCNAME，Windows 使用登录会话（SSPI），
+// 其余系统检查默认的 /tmp/krb5cc_<uid>。
+func DetectTicketCache() TicketCache {
+	if name := strings.TrimSpace(os.Getenv("KRB5CCNAME")); name != "" {
+		return inspectCacheName(name)
+	}
+	if runtime.GOOS == "windows" {
+		return TicketCache{Type: "SSPI", Found: true, Note: "使用当前 Windows 登录会话的凭据"}
+	}
+	return inspectCacheName("FILE:" + filepath.Join(os.TempDir(), "krb5cc_"+strconv.Itoa(os.Getuid())))
+}
+
+// inspectCacheName 解析 TYPE:residual 形式的缓存名称，文件类缓存检查是否存在。
+func inspectCacheName(name string) TicketCache {
+	typ, residual := "FILE", name
+	if i := strings.Index(name, ":"); i > 0 && !filepath.IsAbs(name) {
+		typ, residual = strings.ToUpper(name[:i]), name[i+1:]
+	}
+	tc := TicketCache{Type: typ, Path: residual}
+	switch typ {
+	case "FILE", "DIR":
+		if _, err := os.Stat(residual); err == nil {
+			tc.Found = true
+		} else {
+			tc.Note = "未找到票据缓存，请先执行 kinit 或配置 keytab"
+		}
+	default:
+		tc.Found = true
+		tc.Note = "非文件类票据缓存，无法预先检查是否有有效票据"
+	}
+	return tc
+}
+
+// checkKerberosCredentials 确认 Kerberos 认证有可用的凭据：配置了 keytab 时检查文件，否则检查票据缓存。
+func checkKerberosCredentials(auth *connection.AuthConfig) error {
+	if auth.KeytabPath != "" {
+		if _, err := os.Stat(auth.KeytabPath); err != nil {
+			return fmt.Errorf("读取 keytab 失败：%w", err)
+		}
+		return nil
+	}
+	if auth.CachePath != "" {
+		if tc := inspectCacheName(auth.CachePath); !tc.Found {
+			return fmt.Errorf("票据缓存不存在：%s", tc.Path)
+		}
+		return nil
+	}
+	if tc := DetectTicketCache(); !tc.Found {
+		return fmt.Errorf("%s（%s）", tc.Note, tc.Path)
+	}
+	return nil
+}
+
+// applyAuthDSN 按认证方式为自定义驱动的 DSN 追加参数：Kerberos 支持 PostgreSQL（pgx、postgres）
+// 与 SQL Server（sqlserver、mssql）驱动，LDAP/PAM 由服务端校验明文密码，DSN 无需改动。
+func applyAuthDSN(driver, dsn string, config *connection.ConnectionConfig) (string, error) {
+	method := config.AuthMethod()
+	switch method {
+	case connection.AuthMethodPassword, connection.AuthMethodLDAP, connection.AuthMethodPAM:
+		return dsn, nil
+	case connection.AuthMethodKerberos:
+	default:
+		return "", fmt.Errorf("不支持的认证方式: %s", method)
+	}
+
+	auth := config.Auth
+	if err := checkKerberosCredentials(auth); err != nil {
+		return "", err
+	}
+	switch strings.ToLower(driver) {
+	case "pgx", "postgres", "postgresql":
+		// 带 / 的是完整 SPN，否则视为服务名
+		params := []dsnParam{{"krbsrvname", orDefault(auth.ServiceName, "postgres")}}
+		if strings.Contains(auth.ServiceName, "/") {
+			params[0] = dsnParam{"krbspn", auth.ServiceName}
+		}
+		return appendDSNParams(dsn, params, " "), nil
+	case "sqlserver", "mssql":
+		params := []dsnParam{{"authenticator", "krb5"}}
+		if auth.ConfigPath != "" {
+			params = append(params, dsnParam{"krb5-configfile", auth.ConfigPath})
+		}
+		if auth.Realm != "" {
+			params = append(params, dsnParam{"krb5-realm", auth.Realm})
+		}
+		if auth.KeytabPath != "" {
+			params = append(params, dsnParam{"krb5-keytabfile", auth.KeytabPath})
+		} else if auth.CachePath != "" {
+			params = append(params, dsnParam{"krb5-credcachefile", auth.CachePath})
+		}
+		if auth.ServiceName != "" {
+			params = append(params, dsnParam{"ServerSPN", auth.ServiceName})
+		}
+		return appendDSNParams(dsn, params, ";"), nil
+	default:
+		return "", fmt.Errorf("驱动 %s 不支持 Kerberos 认证", driver)
+	}
+}
+
+// dsnParam 是追加到 DSN 的一个参数。
+type dsnParam struct {
+	key, value string
+}
+
+// appendDSNParams 追加参数：URL 形式写入查询串，否则以 sep 分隔追加 key=value（已有同名参数时保留原值）。
+func appendDSNParams(dsn string, params []dsnParam, sep string) string {
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil {
+			q := u.Query()
+			for _, p := range params {
+				if !q.Has(p.key) {
+					q.Set(p.key, p.value)
+				}
+			}
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	lower := strings.ToLower(dsn)
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(dsn, sep+" "))
+	for _, p := range params {
+		if strings.Contains(lower, strings.ToLower(p.key)+"=") {
+			continue
+		}
+		value := p.value
+		if sep == " " && strings.ContainsAny(value, " '\\") {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		b.WriteString(sep)
+		b.WriteString(p.key + "=" + value)
+	}
+	return b.String()
+}
+
+// mysqlAuthParams 返回内置 MySQL 驱动认证方式需要的 DSN 参数：LDAP/PAM 需要发送明文密码，
+// 只在传输已加密时允许；Kerberos 不受支持。
+func mysqlAuthParams(config *connection.ConnectionConfig) (string, error) {
+	switch method := config.AuthMethod(); method {
+	case connection.AuthMethodPassword:
+		return "", nil
+	case connection.AuthMethodLDAP, connection.AuthMethodPAM:
+		if err := checkCleartextTransport(config); err != nil {
+			return "", err
+		}
+		return "&allowCleartextPasswords=true", nil
+	default:
+		return "", fmt.Errorf("MySQL 驱动不支持 %s 认证", method)
+	}
+}
+
+// postgresAuthCheck 检查内置 PostgreSQL 驱动能否使用配置的认证方式：LDAP/PAM 由服务端按明文密码校验，
+// 只在传输已加密时允许；Kerberos 需要 GSSAPI 实现，未链接。
+func postgresAuthCheck(config *connection.ConnectionConfig) error {
+	switch method := config.AuthMethod(); method {
+	case connection.AuthMethodPassword:
+		return nil
+	case connection.AuthMethodLDAP, connection.AuthMethodPAM:
+		return checkCleartextTransport(config)
+	default:
+		return fmt.Errorf("PostgreSQL 驱动不支持 %s 认证", method)
+	}
+}
+
+// checkCleartextTransport 确认明文密码不会以明文经过网络：TLS 模式须为 required、verify-ca 或 verify-full
+// （preferred 在服务端不支持时会退回明文），或经 SSH 隧道连接。
+func checkCleartextTransport(config *connection.ConnectionConfig) error {
+	switch tlsMode(config) {
+	case connection.TLSModeRequired, connection.TLSModeVerifyCA, connection.TLSModeVerifyFull:
+		return nil
+	}
+	if config.UseSSH {
+		return nil
+	}
+	return fmt.Errorf("%s 认证会发送明文密码，请将 TLS 模式设为 required/verify-ca/verify-full 或通过 SSH 隧道连接", config.AuthMethod())
+}
+
+// orDefault 在 s 为空时返回 def。
+func orDefault(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestApplyAuthDSNKerberos(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "krb5cc")
+	if err := os.WriteFile(cache, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Auth: &connection.AuthConfig{
+		Method: connection.AuthMethodKerberos, CachePath: cache, Realm: "EXAMPLE.COM",
+	}}
+
+	got, err := applyAuthDSN("pgx", "host=db user=alice dbname=app", cfg)
+	if err != nil || got != "host=db user=alice dbname=app krbsrvname=postgres" {
+		t.Fatalf("pgx key-value DSN = %q, %v", got, err)
+	}
+	got, err = applyAuthDSN("pgx", "postgres://alice@db/app?sslmode=require", cfg)
+	if err != nil || !strings.Contains(got, "krbsrvname=postgres") || !strings.Contains(got, "sslmode=require") {
+		t.Fatalf("pgx URL DSN = %q, %v", got, err)
+	}
+	got, err = applyAuthDSN("sqlserver", "server=db;user id=alice;", cfg)
+	if err != nil || got != "server=db;user id=alice;authenticator=krb5;krb5-realm=EXAMPLE.COM;krb5-credcachefile="+cache {
+		t.Fatalf("sqlserver DSN = %q, %v", got, err)
+	}
+	if _, err := applyAuthDSN("sqlite3", "file.db", cfg); err == nil {
+		t.Fatal("不支持 Kerberos 的驱动应返回错误")
+	}
+
+	cfg.Auth.CachePath = filepath.Join(t.TempDir(), "missing")
+	if _, err := applyAuthDSN("pgx", "host=db", cfg); err == nil {
+		t.Fatal("票据缓存不存在时应返回错误")
+	}
+}
+
+func TestMySQLAuthParams(t *testing.T) {
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL}
+	if p, err := mysqlAuthParams(cfg); err != nil || p != "" {
+		t.Fatalf("password: %q, %v", p, err)
+	}
+	cfg.Auth = &connection.AuthConfig{Method: "LDAP"}
+	if _, err := mysqlAuthParams(cfg); err == nil {
+		t.Fatal("未加密的连接不应允许 LDAP 发送明文密码")
+	}
+	cfg.TLS = &connection.TLSConfig{Mode: connection.TLSModePreferred}
+	if _, err := mysqlAuthParams(cfg); err == nil {
+		t.Fatal("preferred 可能退回明文，不应允许 LDAP")
+	}
+	for _, mode := range []string{connection.TLSModeRequired, connection.TLSModeVerifyCA, connection.TLSModeVerifyFull} {
+		cfg.TLS.Mode = mode
+		if p, err := mysqlAuthParams(cfg); err != nil || p != "&allowCleartextPasswords=true" {
+			t.Fatalf("ldap over %s: %q, %v", mode, p, err)
+		}
+	}
+	cfg.TLS, cfg.UseSSH = nil, true
+	cfg.Auth.Method = connection.AuthMethodPAM
+	if p, err := mysqlAuthParams(cfg); err != nil || p != "&allowCleartextPasswords=true" {
+		t.Fatalf("pam over ssh: %q, %v", p, err)
+	}
+	cfg.Auth.Method = connection.AuthMethodKerberos
+	if _, err := mysqlAuthParams(cfg); err == nil {
+		t.Fatal("MySQL 不支持 Kerberos，应返回错误")
+	}
+}
+
+func TestPostgresAuthCheck(t *testing.T) {
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL}
+	if err := postgresAuthCheck(cfg); err != nil {
+		t.Fatalf("password: %v", err)
+	}
+	cfg.Auth = &connection.AuthConfig{Method: connection.AuthMethodLDAP}
+	if err := postgresAuthCheck(cfg); err == nil {
+		t.Fatal("未加密的连接不应允许 LDAP 发送明文密码")
+	}
+	cfg.TLS = &connection.TLSConfig{Mode: connection.TLSModeVerifyFull}
+	if err := postgresAuthCheck(cfg); err != nil {
+		t.Fatalf("ldap over verify-full: %v", err)
+	}
+	cfg.Auth.Method = connection.AuthMethodKerberos
+	if err := postgresAuthCheck(cfg); err == nil {
+		t.Fatal("内置 PostgreSQL 驱动不支持 Kerberos，应返回错误")
+	}
+}
+
+func TestInspectCacheName(t *testing.T) {
+	if tc := inspectCacheName("KEYRING:persistent:1000"); tc.Type != "KEYRING" || !tc.Found {
+		t.Fatalf("keyring = %+v", tc)
+	}
+	if tc := inspectCacheName("FILE:/nonexistent/krb5cc"); tc.Found || tc.Path != "/nonexistent/krb5cc" {
+		t.Fatalf("missing file = %+v", tc)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+const (
+	// DefaultImportBatchSize 默认每条 INSERT 语句包含的行数。
+	DefaultImportBatchSize = 500
+	// DefaultImportMaxErrors 默认报告中最多记录的失败行数。
+	DefaultImportMaxErrors = 100
+	// importSavepoint 批次内使用的保存点名称。
+	importSavepoint = "boxify_import"
+)
+
+// sqlDialect 描述生成参数化语句所需的方言差异。
+type sqlDialect struct {
+	quoteIdent  func(string) string // 标识符引用
+	placeholder func(n int) string  // 第 n 个参数占位符（从 1 开始）
+	maxParams   int                 // 单条语句允许的最大参数个数，<=0 表示不限制
+	rowCompare  bool                // 行值比较 (a, b) > (?, ?) 可走索引，键分页优先使用
+	offsetFetch bool                // 使用 OFFSET ... FETCH 限制行数（须跟在 ORDER BY 之后），而非 LIMIT
+	json        jsonFlavor          // JSON 取值函数的写法
+	nullsLow    bool                // NULL 在升序中排在最前，键分页据此定位 NULL 所在位置
+}
+
+// mysqlDialect MySQL 方言。
+var mysqlDialect = sqlDialect{
+	quoteIdent:  quoteMySQLIdent,
+	placeholder: func(int) string { return "?" },
+	maxParams:   65535,
+	json:        jsonMySQL,
+	nullsLow:    true,
+}
+
+// buildBatchInsertSQL 构造包含 rowCount 行的参数化多行 INSERT 语句。
+func buildBatchInsertSQL(d sqlDialect, tableName string, columns []string, rowCount int) string {
+	return writeBatchInsertSQL(d, tableName, columns, rowCount, func(int, int) bool { return false })
+}
+
+// buildBatchInsertDefaultsSQL 构造 batch 的多行 INSERT 语句，值为 connection.ColumnDefault 的单元格写 DEFAULT，
+// 返回语句与其余单元格的参数。
+func buildBatchInsertDefaultsSQL(d sqlDialect, tableName string, columns []string, batch [][]any) (string, []any) {
+	isDefault := func(r, c int) bool {
+		_, ok := batch[r][c].(connection.ColumnDefault)
+		return ok
+	}
+	args := make([]any, 0, len(batch)*len(columns))
+	for r, row := range batch {
+		for c, v := range row {
+			if !isDefault(r, c) {
+				args = append(args, v)
+			}
+		}
+	}
+	return writeBatchInsertSQL(d, tableName, columns, len(batch), isDefault), args
+}
+
+// writeBatchInsertSQL 构造多行 INSERT 语句，isDefault 为 true 的单元格写 DEFAULT，其余按顺序编号占位符。
+func writeBatchInsertSQL(d sqlDialect, tableName string, columns []string, rowCount int, isDefault func(r, c int) bool) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = d.quoteIdent(col)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(d.quoteTable(tableName))
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(quoted, ", "))
+	sb.WriteString(") VALUES ")
+
+	n := 1
+	for r := 0; r < rowCount; r++ {
+		if r > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for c := range columns {
+			if c > 0 {
+				sb.WriteString(", ")
+			}
+			if isDefault(r, c) {
+				sb.WriteString("DEFAULT")
+				continue
+			}
+			sb.WriteString(d.placeholder(n))
+			n++
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+// hasColumnDefault 判断批次中是否有单元格使用列默认值。
+func hasColumnDefault(batch [][]any) bool {
+	for _, row := range batch {
+		for _, v := range row {
+			if _, ok := v.(connection.ColumnDefault); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// effectiveBatchSize 计算实际批大小，确保单条语句参数个数不超过方言上限。
+func effectiveBatchSize(d sqlDialect, requested, columnCount int) int {
+	size := requested
+	if size <= 0 {
+		size = DefaultImportBatchSize
+	}
+	if d.maxParams > 0 && columnCount > 0 && size*columnCount > d.maxParams {
+		size = d.maxParams / columnCount
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// InsertProgressFunc 在每批插入结束后调用，done 为已处理（插入成功或失败）的行数。
+type InsertProgressFunc func(done, total int)
+
+// batchInserter 在单个事务内执行批量插入，并在批次失败时逐行重试以定位失败行。
+type batchInserter struct {
+	ctx     context.Context
+	tx      *sql.Tx
+	dialect sqlDialect
+	table   string
+	columns []string
+	stmts   map[int]*sql.Stmt // 行数 -> 预处理语句
+}
+
+// batchInsertTx 在事务内以多行 INSERT 批量插入数据。
+// 默认任一行失败即整体回滚；SkipFailedRows 时提交其余行；DryRun 时始终回滚。progress 可为 nil。
+func batchInsertTx(ctx context.Context, conn sqlSession, d sqlDialect, tableName string, columns []string, rows [][]any, opts *connection.ImportOptions, progress InsertProgressFunc) (*connection.ImportReport, error) {
+	if opts == nil {
+		opts = &connection.ImportOptions{}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("导入列不能为空")
+	}
+	maxErrors := opts.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultImportMaxErrors
+	}
+
+	start := time.Now()
+	report := &connection.ImportReport{
+		Total:  len(rows),
+		DryRun: opts.DryRun,
+		Errors: make([]*connection.ImportRowError, 0),
+	}
+	defer func() {
+		report.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开启事务失败：%w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	bi := &batchInserter{ctx: ctx, tx: tx, dialect: d, table: tableName, columns: columns, stmts: make(map[int]*sql.Stmt)}
+	defer bi.close()
+
+	batchSize := effectiveBatchSize(d, opts.BatchSize, len(columns))
+	for offset := 0; offset < len(rows); offset += batchSize {
+		end := min(offset+batchSize, len(rows))
+		batch := rows[offset:end]
+		report.Batches++
+
+		execErr, err := bi.exec(batch)
+		if err != nil {
+			return nil, err
+		}
+		if execErr == nil {
+			report.Inserted += len(batch)
+			reportInsertProgress(progress, end, len(rows))
+			continue
+		}
+
+		// 批次失败：逐行重试，定位失败行
+		for i, row := range batch {
+			rowErr, err := bi.exec([][]any{row})
+			if err != nil {
+				return nil, err
+			}
+			if rowErr == nil {
+				report.Inserted++
+				continue
+			}
+			report.Failed++
+			if len(report.Errors) < maxErrors {
+				report.Errors = append(report.Errors, &connection.ImportRowError{Row: offset + i + 1, Message: rowErr.Error()})
+			}
+		}
+		reportInsertProgress(progress, end, len(rows))
+	}
+
+	if opts.DryRun || (report.Failed > 0 && !opts.SkipFailedRows) {
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("提交事务失败：%w", err)
+	}
+	committed = true
+	report.Committed = true
+	return report, nil
+}
+
+// reportInsertProgress 在 progress 非空时汇报插入进度。
+func reportInsertProgress(progress InsertProgressFunc, done, total int) {
+	if progress != nil {
+		progress(done, total)
+	}
+}
+
+// exec 在保存点保护下执行一批插入。
+// rowErr 为数据错误（已回滚到保存点，事务可继续），fatalErr 为无法继续的事务错误。
+func (b *batchInserter) exec(batch [][]any) (rowErr, fatalErr error) {
+	// 含 DEFAULT 的批次语句随单元格变化，不缓存预处理语句
+	var run func() (sql.Result, error)
+	if hasColumnDefault(batch) {
+		query, args := buildBatchInsertDefaultsSQL(b.dialect, b.table, b.columns, batch)
+		run = func() (sql.Result, error) { return b.tx.ExecContext(b.ctx, query, args...) }
+	} else {
+		stmt, err := b.stmt(len(batch))
+		if err != nil {
+			return nil, err
+		}
+		args := make([]any, 0, len(batch)*len(b.columns))
+		for _, row := range batch {
+			args = append(args, row...)
+		}
+		run = func() (sql.Result, error) { return stmt.ExecContext(b.ctx, args...) }
+	}
+
+	if _, err := b.tx.ExecContext(b.ctx, "SAVEPOINT "+importSavepoint); err != nil {
+		return nil, fmt.Errorf("创建保存点失败：%w", err)
+	}
+	if _, execErr := run(); execErr != nil {
+		if b.ctx.Err() != nil {
+			return nil, b.ctx.Err()
+		}
+		if _, err := b.tx.ExecContext(b.ctx, "ROLLBACK TO SAVEPOINT "+importSavepoint); err != nil {
+			return nil, fmt.Errorf("回滚保存点失败：%w", err)
+		}
+		return execErr, nil
+	}
+	if _, err := b.tx.ExecContext(b.ctx, "RELEASE SAVEPOINT "+importSavepoint); err != nil {
+		return nil, fmt.Errorf("释放保存点失败：%w", err)
+	}
+	return nil, nil
+}
+
+// stmt 获取（必要时预处理）包含 rowCount 行的插入语句。
+func (b *batchInserter) stmt(rowCount int) (*sql.Stmt, error) {
+	if stmt, ok := b.stmts[rowCount]; ok {
+		return stmt, nil
+	}
+	stmt, err := b.tx.PrepareContext(b.ctx, buildBatchInsertSQL(b.dialect, b.table, b.columns, rowCount))
+	if err != nil {
+		return nil, fmt.Errorf("预处理插入语句失败：%w", err)
+	}
+	b.stmts[rowCount] = stmt
+	return stmt, nil
+}
+
+// close 关闭所有预处理语句。
+func (b *batchInserter) close() {
+	for _, stmt := range b.stmts {
+		stmt.Close()
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// fakeImportDriver 模拟数据库驱动：参数中出现 "bad" 时插入失败，并记录执行语句；
+// 设置 columns 后查询返回 rows 中的行。
+type fakeImportDriver struct {
+	mu         sync.Mutex
+	execs      []string
+	committed  bool
+	rolledBack bool
+	columns    []string
+	rows       [][]driver.Value
+}
+
+func (d *fakeImportDriver) Open(string) (driver.Conn, error) { return &fakeImportConn{d: d}, nil }
+
+func (d *fakeImportDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.execs = append(d.execs, query)
+}
+
+type fakeImportConn struct{ d *fakeImportDriver }
+
+func (c *fakeImportConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeImportStmt{d: c.d, query: query}, nil
+}
+func (c *fakeImportConn) Close() error              { return nil }
+func (c *fakeImportConn) Begin() (driver.Tx, error) { return &fakeImportTx{d: c.d}, nil }
+
+type fakeImportTx struct{ d *fakeImportDriver }
+
+func (t *fakeImportTx) Commit() error   { t.d.committed = true; return nil }
+func (t *fakeImportTx) Rollback() error { t.d.rolledBack = true; return nil }
+
+type fakeImportStmt struct {
+	d     *fakeImportDriver
+	query string
+}
+
+func (s *fakeImportStmt) Close() error  { return nil }
+func (s *fakeImportStmt) NumInput() int { return -1 }
+func (s *fakeImportStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.record(s.query)
+	for _, a := range args {
+		if a == "bad" {
+			return nil, fmt.Errorf("invalid value")
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeImportStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.record(s.query)
+	if s.d.columns == nil {
+		return nil, fmt.Errorf("not supported")
+	}
+	return &fakeImportRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type fakeImportRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeImportRows) Columns() []string { return r.columns }
+func (r *fakeImportRows) Close() error      { return nil }
+func (r *fakeImportRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+var fakeDriverSeq int
+
+// openFakeImportDB 注册并打开一个独立的模拟驱动连接。
+func openFakeImportDB(t *testing.T) (*sql.DB, *fakeImportDriver) {
+	t.Helper()
+	fakeDriverSeq++
+	name := fmt.Sprintf("fake-import-%d", fakeDriverSeq)
+	drv := &fakeImportDriver{}
+	sql.Register(name, drv)
+	conn, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("打开模拟连接失败: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, drv
+}
+
+func TestBuildBatchInsertSQL(t *testing.T) {
+	got := buildBatchInsertSQL(mysqlDialect, "users", []string{"id", "na`me"}, 2)
+	want := "INSERT INTO `users` (`id`, `na``me`) VALUES (?, ?), (?, ?)"
+	if got != want {
+		t.Errorf("期望 %s，得到 %s", want, got)
+	}
+
+	pg := sqlDialect{
+		quoteIdent:  func(s string) string { return `"` + s + `"` },
+		placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	}
+	got = buildBatchInsertSQL(pg, "t", []string{"a", "b"}, 2)
+	if !strings.HasSuffix(got, "($1, $2), ($3, $4)") {
+		t.Errorf("PostgreSQL 占位符编号错误: %s", got)
+	}
+}
+
+func TestBuildBatchInsertDefaultsSQL(t *testing.T) {
+	batch := [][]any{{1, connection.ColumnDefault{}}, {connection.ColumnDefault{}, "b"}}
+	got, args := buildBatchInsertDefaultsSQL(postgresDialect, "t", []string{"a", "b"}, batch)
+	if got != `INSERT INTO "t" ("a", "b") VALUES ($1, DEFAULT), (DEFAULT, $2)` {
+		t.Errorf("DEFAULT 语句错误: %s", got)
+	}
+	if len(args) != 2 || args[0] != 1 || args[1] != "b" {
+		t.Errorf("参数不应包含 DEFAULT 单元格: %v", args)
+	}
+	if !hasColumnDefault(batch) || hasColumnDefault([][]any{{1, "a"}}) {
+		t.Error("hasColumnDefault 判断错误")
+	}
+}
+
+func TestEffectiveBatchSize(t *testing.T) {
+	if got := effectiveBatchSize(mysqlDialect, 0, 3); got != DefaultImportBatchSize {
+		t.Errorf("期望默认批大小，得到 %d", got)
+	}
+	if got := effectiveBatchSize(mysqlDialect, 10000, 100); got != 655 {
+		t.Errorf("期望受参数上限约束为 655，得到 %d", got)
+	}
+}
+
+func TestBatchInsertTxCommitsAllRows(t *testing.T) {
+	conn, drv := openFakeImportDB(t)
+	rows := [][]any{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	report, err := batchInsertTx(context.Background(), conn, mysqlDialect, "t", []string{"id", "name"}, rows, &connection.ImportOptions{BatchSize: 2}, nil)
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if report.Inserted != 3 || report.Batches != 2 || !report.Committed || !drv.committed {
+		t.Errorf("报告不正确: %+v", report)
+	}
+}
+
+func TestBatchInsertTxReportsProgress(t *testing.T) {
+	conn, _ := openFakeImportDB(t)
+	rows := [][]any{{1, "a"}, {2, "bad"}, {3, "c"}, {4, "d"}, {5, "e"}}
+
+	var got []int
+	progress := func(done, total int) {
+		if total != len(rows) {
+			t.Errorf("期望总行数 %d，得到 %d", len(rows), total)
+		}
+		got = append(got, done)
+	}
+	if _, err := batchInsertTx(context.Background(), conn, mysqlDialect, "t", []string{"id", "name"}, rows, &connection.ImportOptions{BatchSize: 2, SkipFailedRows: true}, progress); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if want := []int{2, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("进度汇报不正确: 期望 %v，得到 %v", want, got)
+	}
+}
+
+func TestBatchInsertTxReportsFailedRowsAndRollsBack(t *testing.T) {
+	conn, drv := openFakeImportDB(t)
+	rows := [][]any{{1, "a"}, {2, "bad"}, {3, "c"}, {4, "bad"}}
+
+	report, err := batchInsertTx(context.Background(), conn, mysqlDialect, "t", []string{"id", "name"}, rows, &connection.ImportOptions{BatchSize: 4}, nil)
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if report.Committed || drv.committed || !drv.rolledBack {
+		t.Errorf("存在失败行时应整体回滚: %+v", report)
+	}
+	if report.Failed != 2 || report.Inserted != 2 || len(report.Errors) != 2 {
+		t.Fatalf("报告不正确: %+v", report)
+	}
+	if report.Errors[0].Row != 2 || report.Errors[1].Row != 4 {
+		t.Errorf("失败行号不正确: %+v %+v", report.Errors[0], report.Errors[1])
+	}
+}
+
+func TestBatchInsertTxSkipFailedRowsAndDryRun(t *testing.T) {
+	conn, drv := openFakeImportDB(t)
+	rows := [][]any{{1, "a"}, {2, "bad"}}
+
+	report, err := batchInsertTx(context.Background(), conn, mysqlDialect, "t", []string{"id", "name"}, rows, &connection.ImportOptions{SkipFailedRows: true}, nil)
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if !report.Committed || !drv.committed || report.Inserted != 1 {
+		t.Errorf("跳过失败行后应提交: %+v", report)
+	}
+
+	conn, drv = openFakeImportDB(t)
+	report, err = batchInsertTx(context.Background(), conn, mysqlDialect, "t", []string{"id", "name"}, rows[:1], &connection.ImportOptions{DryRun: true}, nil)
+	if err != nil {
+		t.Fatalf("试运行失败: %v", err)
+	}
+	if report.Committed || drv.committed || !report.DryRun || report.Inserted != 1 {
+		t.Errorf("试运行不应提交: %+v", report)
+	}
+}
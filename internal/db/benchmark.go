@@ -0,0 +1,274 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
+	"github.com/chenyang-zz/boxify/internal/ssh"
+)
+
+// 连接测速的默认与最大采样次数。
+const (
+	DefaultBenchmarkPings   = 10
+	DefaultBenchmarkQueries = 50
+	MaxBenchmarkIterations  = 1000
+)
+
+// benchmarkQuery 是测速使用的最简查询，只反映往返与解析开销。
+const benchmarkQuery = "SELECT 1"
+
+// BenchmarkConnection 测量建连/握手耗时、pings 次 Ping 延迟与 queries 次简单查询的延迟和吞吐
+// 单项失败计入 Failures 并记录首个错误，不中断其他测量；ctx 取消时提前结束。
+func BenchmarkConnection(ctx context.Context, inst Database, config *connection.ConnectionConfig, pings, queries int) *connection.BenchmarkReport {
+	report := &connection.BenchmarkReport{}
+	addErr := func(stage string, err error) {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", stage, err))
+	}
+
+	switch {
+	case config.UseSSH && config.SSH != nil:
+		if d, err := ssh.MeasureHandshake(ctx, config.SSH); err != nil {
+			addErr("ssh", err)
+		} else {
+			report.SSHHandshakeMs = millis(d)
+		}
+	case config.Host != "" && config.Port > 0:
+		if d, err := measureTCPConnect(ctx, config.Host, config.Port, config.Proxy, getConnectTimeout(config)); err != nil {
+			addErr("tcp", err)
+		} else {
+			report.TCPConnectMs = millis(d)
+		}
+		if mode := tlsMode(config); mode != "" && mode != connection.TLSModeDisable && isMySQLFamily(config.Type) {
+			if d, err := measureMySQLTLSHandshake(ctx, config); err != nil {
+				addErr("tls", err)
+			} else {
+				report.TLSHandshakeMs = millis(d)
+			}
+		}
+	}
+
+	samples, failures, err := measureLatency(ctx, pings, inst.Ping)
+	report.Ping = summarizeLatency(samples, failures)
+	if err != nil {
+		addErr("ping", err)
+	}
+
+	query := func() error {
+		var err error
+		if q, ok := inst.(interface {
+			QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+		}); ok {
+			_, _, err = q.QueryContext(ctx, benchmarkQuery)
+		} else {
+			_, _, err = inst.Query(benchmarkQuery)
+		}
+		return err
+	}
+	start := time.Now()
+	samples, failures, err = measureLatency(ctx, queries, query)
+	elapsed := time.Since(start)
+	report.Query = summarizeLatency(samples, failures)
+	if err != nil {
+		addErr("query", err)
+	}
+	if len(samples) > 0 && elapsed > 0 {
+		report.QueriesPerSecond = float64(len(samples)) / elapsed.Seconds()
+	}
+	return report
+}
+
+// measureTCPConnect 连接 host:port（配置了代理时经由代理）并立即关闭，返回建连耗时。
+func measureTCPConnect(ctx context.Context, host string, port int, proxy *connection.ProxyConfig, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := netproxy.Dial(ctx, proxy, net.JoinHostPort(host, fmt.Sprint(port)))
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	_ = conn.Close()
+	return elapsed, nil
+}
+
+// MySQL 协议中与 TLS 协商相关的能力位。
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+)
+
+// isMySQLFamily 判断连接类型是否使用 MySQL 协议（空类型按 MySQL 处理）。
+func isMySQLFamily(dbType connection.ConnectionType) bool {
+	return dbType == "" || dbType == connection.ConnectionTypeMySQL
+}
+
+// measureMySQLTLSHandshake 测量 MySQL 连接升级为 TLS 的握手耗时。
+// MySQL 在明文的初始握手之后才协商加密，不能直接 tls.Dial：先读取服务端握手包，
+// 发送 SSLRequest 后在同一连接上完成 TLS 握手，只计 TLS 握手本身，随后断开而不认证。
+// preferred 模式下服务端不支持 TLS 时返回 0。
+func measureMySQLTLSHandshake(ctx context.Context, config *connection.ConnectionConfig) (time.Duration, error) {
+	tlsConfig, err := tlsClientConfig(config)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, getConnectTimeout(config))
+	defer cancel()
+	conn, err := netproxy.Dial(ctx, config.Proxy, net.JoinHostPort(config.Host, fmt.Sprint(config.Port)))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	greeting, err := readMySQLPacket(conn)
+	if err != nil {
+		return 0, fmt.Errorf("读取服务端握手包失败：%w", err)
+	}
+	caps, err := mysqlServerCapabilities(greeting)
+	if err != nil {
+		return 0, err
+	}
+	if caps&mysqlClientSSL == 0 {
+		if tlsMode(config) == connection.TLSModePreferred {
+			return 0, nil // preferred 模式下驱动会回退为明文连接，不算错误
+		}
+		return 0, fmt.Errorf("服务端不支持 TLS")
+	}
+
+	// SSLRequest：能力位、最大包长、字符集与 23 字节保留位，序号为 1
+	payload := make([]byte, 32)
+	binary.LittleEndian.PutUint32(payload[0:], mysqlClientLongPassword|mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	binary.LittleEndian.PutUint32(payload[4:], 1<<24)
+	payload[8] = 45 // utf8mb4_general_ci
+	packet := append([]byte{byte(len(payload)), 0, 0, 1}, payload...)
+	if _, err := conn.Write(packet); err != nil {
+		return 0, fmt.Errorf("发送 SSLRequest 失败：%w", err)
+	}
+
+	start := time.Now()
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return 0, fmt.Errorf("TLS 握手失败：%w", err)
+	}
+	return time.Since(start), nil
+}
+
+// readMySQLPacket 读取一个 MySQL 协议包的负载。
+func readMySQLPacket(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// mysqlServerCapabilities 解析初始握手包（协议版本 10）中的服务端能力位；服务端直接返回错误包时返回其消息。
+func mysqlServerCapabilities(greeting []byte) (uint32, error) {
+	if len(greeting) > 0 && greeting[0] == 0xff {
+		if len(greeting) > 3 {
+			return 0, fmt.Errorf("服务端拒绝连接：%s", strings.TrimPrefix(string(greeting[3:]), "#"))
+		}
+		return 0, fmt.Errorf("服务端拒绝连接")
+	}
+	if len(greeting) == 0 || greeting[0] != 10 {
+		return 0, fmt.Errorf("不支持的握手协议")
+	}
+	// 协议版本后依次为以 NUL 结尾的版本号、4 字节连接 ID、8 字节随机数与 1 字节填充，之后是能力位低 16 位
+	end := bytes.IndexByte(greeting[1:], 0)
+	if end < 0 {
+		return 0, fmt.Errorf("握手包格式错误")
+	}
+	pos := 1 + end + 1 + 4 + 8 + 1
+	if len(greeting) < pos+2 {
+		return 0, fmt.Errorf("握手包格式错误")
+	}
+	caps := uint32(binary.LittleEndian.Uint16(greeting[pos:]))
+	// 能力位高 16 位在字符集（1 字节）与状态位（2 字节）之后
+	if high := pos + 2 + 1 + 2; len(greeting) >= high+2 {
+		caps |= uint32(binary.LittleEndian.Uint16(greeting[high:])) << 16
+	}
+	return caps, nil
+}
+
+// measureLatency 顺序执行 fn n 次，返回成功调用的耗时、失败次数与首个错误。
+func measureLatency(ctx context.Context, n int, fn func() error) ([]time.Duration, int, error) {
+	samples := make([]time.Duration, 0, n)
+	failures := 0
+	var firstErr error
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			break
+		}
+		start := time.Now()
+		if err := fn(); err != nil {
+			failures++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		samples = append(samples, time.Since(start))
+	}
+	return samples, failures, firstErr
+}
+
+// summarizeLatency 计算最小、平均、P95 与最大耗时。
+func summarizeLatency(samples []time.Duration, failures int) *connection.LatencyStats {
+	stats := &connection.LatencyStats{Samples: len(samples), Failures: failures}
+	if len(samples) == 0 {
+		return stats
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	p95 := (len(sorted)*95 + 99) / 100
+	stats.MinMs = millis(sorted[0])
+	stats.MaxMs = millis(sorted[len(sorted)-1])
+	stats.AvgMs = millis(total / time.Duration(len(sorted)))
+	stats.P95Ms = millis(sorted[p95-1])
+	return stats
+}
+
+// millis 将耗时转换为保留三位小数的毫秒数。
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
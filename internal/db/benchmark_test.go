@@ -0,0 +1,181 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// benchDatabase 记录 Ping 与 Query 调用次数，第 failAt 次查询返回错误。
+type benchDatabase struct {
+	Database
+	pings   int
+	queries int
+	failAt  int
+}
+
+func (b *benchDatabase) Ping() error { b.pings++; return nil }
+
+func (b *benchDatabase) Query(query string, args ...any) ([]map[string]interface{}, []string, error) {
+	b.queries++
+	if b.queries == b.failAt {
+		return nil, nil, errors.New("boom")
+	}
+	return nil, nil, nil
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	var samples []time.Duration
+	for i := 20; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	got := summarizeLatency(samples, 2)
+	if got.Samples != 20 || got.Failures != 2 || got.MinMs != 1 || got.MaxMs != 20 || got.AvgMs != 10.5 || got.P95Ms != 19 {
+		t.Fatalf("统计错误: %+v", got)
+	}
+	if empty := summarizeLatency(nil, 3); empty.Samples != 0 || empty.Failures != 3 || empty.MaxMs != 0 {
+		t.Fatalf("无样本时应只记录失败数: %+v", empty)
+	}
+}
+
+func TestBenchmarkConnection(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听本地端口: %v", err)
+	}
+	defer ln.Close()
+	addr := ln.Addr().(*net.TCPAddr)
+
+	inst := &benchDatabase{failAt: 2}
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "127.0.0.1", Port: addr.Port}
+	report := BenchmarkConnection(context.Background(), inst, cfg, 3, 5)
+
+	if inst.pings != 3 || inst.queries != 5 {
+		t.Fatalf("调用次数错误: pings=%d queries=%d", inst.pings, inst.queries)
+	}
+	if report.Ping.Samples != 3 || report.Query.Samples != 4 || report.Query.Failures != 1 {
+		t.Fatalf("采样统计错误: %+v %+v", report.Ping, report.Query)
+	}
+	if len(report.Errors) != 1 || report.Errors[0] != "query: boom" {
+		t.Fatalf("应只记录查询错误: %v", report.Errors)
+	}
+	if report.TCPConnectMs <= 0 || report.SSHHandshakeMs != 0 || report.QueriesPerSecond <= 0 {
+		t.Fatalf("建连耗时或吞吐错误: %+v", report)
+	}
+}
+
+func TestBenchmarkConnectionCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inst := &benchDatabase{}
+	report := BenchmarkConnection(ctx, inst, &connection.ConnectionConfig{}, 5, 5)
+	if inst.pings != 0 || inst.queries != 0 || len(report.Errors) != 2 {
+		t.Fatalf("取消后不应继续测量: %+v", report)
+	}
+}
+
+// serveMySQLTLS 模拟 MySQL 服务端：发送握手包，withSSL 时声明支持 TLS 并在读取 SSLRequest 后进行 TLS 握手。
+func serveMySQLTLS(t *testing.T, ln net.Listener, cert tls.Certificate, withSSL bool) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var caps uint16 = mysqlClientProtocol41 | mysqlClientSecureConnection
+	if withSSL {
+		caps |= mysqlClientSSL
+	}
+	greeting := append([]byte{10}, "8.0.36\x00"...)
+	greeting = append(greeting, make([]byte, 4+8+1)...)
+	greeting = binary.LittleEndian.AppendUint16(greeting, caps)
+	greeting = append(greeting, 45, 2, 0, 0, 0)
+	if _, err := conn.Write(append([]byte{byte(len(greeting)), 0, 0, 0}, greeting...)); err != nil {
+		t.Errorf("写入握手包失败: %v", err)
+		return
+	}
+	if !withSSL {
+		return
+	}
+	req, err := readMySQLPacket(conn)
+	if err != nil || len(req) != 32 || binary.LittleEndian.Uint32(req)&mysqlClientSSL == 0 {
+		t.Errorf("SSLRequest 错误: %v %v", req, err)
+		return
+	}
+	// 握手结果由客户端断言，客户端拒绝证书时这里的失败是预期的
+	_ = tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+}
+
+// selfSignedCert 生成用于测试的自签名证书。
+func selfSignedCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), DNSNames: []string{"localhost"}}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestMeasureMySQLTLSHandshake(t *testing.T) {
+	cert := selfSignedCert(t)
+	for _, tt := range []struct {
+		mode    string
+		withSSL bool
+		wantErr bool
+	}{
+		{connection.TLSModeRequired, true, false},
+		{connection.TLSModeRequired, false, true},
+		{connection.TLSModePreferred, false, false},
+		{connection.TLSModeVerifyFull, true, true}, // 自签名证书不在系统根证书中
+	} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skipf("无法监听本地端口: %v", err)
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			serveMySQLTLS(t, ln, cert, tt.withSSL)
+		}()
+
+		cfg := &connection.ConnectionConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, TLS: &connection.TLSConfig{Mode: tt.mode}}
+		d, err := measureMySQLTLSHandshake(context.Background(), cfg)
+		ln.Close()
+		<-done
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s ssl=%v: err = %v", tt.mode, tt.withSSL, err)
+		}
+		if err == nil && tt.withSSL && d <= 0 {
+			t.Errorf("%s: 握手耗时应为正数", tt.mode)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// BulkStatement 是编译后的批量修改语句与计数语句，两者使用相同的过滤条件。
+type BulkStatement struct {
+	SQL       string // UPDATE/DELETE 语句
+	Args      []any  // SQL 参数（SET 值在前，过滤值在后）
+	CountSQL  string // SELECT COUNT(*) 语句
+	CountArgs []any  // CountSQL 参数
+}
+
+// BuildBulkStatement 将批量修改请求编译为参数化的 UPDATE/DELETE 与同条件的计数语句。
+// 列名必须存在于 columns 中；未指定过滤条件时需 AllowAll 显式确认修改整表。
+func BuildBulkStatement(dbType connection.ConnectionType, table string, columns []*connection.ColumnDefinition, req *connection.BulkUpdateRequest) (*BulkStatement, error) {
+	d := dialectFor(dbType)
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+	}
+	action := strings.ToLower(req.Action)
+	if action != connection.BulkActionUpdate && action != connection.BulkActionDelete {
+		return nil, fmt.Errorf("不支持的操作类型: %s", req.Action)
+	}
+	if len(req.Filters) == 0 && !req.AllowAll {
+		return nil, fmt.Errorf("未指定过滤条件，修改整表需显式确认")
+	}
+
+	where := func(n *int) (string, []any, error) {
+		clauses := make([]string, 0, len(req.Filters))
+		var args []any
+		for i, f := range req.Filters {
+			if !known[f.Column] {
+				return "", nil, fmt.Errorf("过滤条件 %d 的列不存在: %s", i+1, f.Column)
+			}
+			clause, fargs, err := compileTableFilter(d, f, n)
+			if err != nil {
+				return "", nil, fmt.Errorf("过滤条件 %d（%s）无效：%w", i+1, f.Column, err)
+			}
+			clauses = append(clauses, clause)
+			args = append(args, fargs...)
+		}
+		if len(clauses) == 0 {
+			return "", nil, nil
+		}
+		sep := " AND "
+		if req.MatchAny {
+			sep = " OR "
+		}
+		return " WHERE " + strings.Join(clauses, sep), args, nil
+	}
+
+	quotedTable := d.quoteTable(table)
+	stmt := &BulkStatement{}
+	n := 0
+	countWhere, countArgs, err := where(&n)
+	if err != nil {
+		return nil, err
+	}
+	stmt.CountSQL = "SELECT COUNT(*) FROM " + quotedTable + countWhere
+	stmt.CountArgs = countArgs
+
+	n = 0
+	if action == connection.BulkActionDelete {
+		w, args, _ := where(&n)
+		stmt.SQL, stmt.Args = "DELETE FROM "+quotedTable+w, args
+		return stmt, nil
+	}
+
+	if len(req.Set) == 0 {
+		return nil, fmt.Errorf("更新至少需要设置一列")
+	}
+	names := make([]string, 0, len(req.Set))
+	for col := range req.Set {
+		if !known[col] {
+			return nil, fmt.Errorf("更新的列不存在: %s", col)
+		}
+		names = append(names, col)
+	}
+	sort.Strings(names)
+	sets := make([]string, len(names))
+	for i, col := range names {
+		n++
+		sets[i] = d.quoteIdent(col) + " = " + d.placeholder(n)
+		stmt.Args = append(stmt.Args, req.Set[col])
+	}
+	w, args, _ := where(&n)
+	stmt.SQL = "UPDATE " + quotedTable + " SET " + strings.Join(sets, ", ") + w
+	stmt.Args = append(stmt.Args, args...)
+	return stmt, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestBuildBulkStatement(t *testing.T) {
+	columns := []*connection.ColumnDefinition{{Name: "id"}, {Name: "status"}, {Name: "note"}}
+	req := &connection.BulkUpdateRequest{
+		Action:  "UPDATE",
+		Set:     map[string]interface{}{"status": "archived", "note": nil},
+		Filters: []connection.TableFilter{{Column: "status", Op: "eq", Value: "draft"}, {Column: "id", Op: "lt", Value: 100}},
+	}
+
+	pg, err := BuildBulkStatement(connection.ConnectionTypePostgreSQL, "posts", columns, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pg.SQL != `UPDATE "posts" SET "note" = $1, "status" = $2 WHERE "status" = $3 AND "id" < $4` {
+		t.Errorf("UPDATE 语句不符: %s", pg.SQL)
+	}
+	if len(pg.Args) != 4 || pg.Args[0] != nil || pg.Args[1] != "archived" || pg.Args[2] != "draft" {
+		t.Errorf("UPDATE 参数不符: %v", pg.Args)
+	}
+	if pg.CountSQL != `SELECT COUNT(*) FROM "posts" WHERE "status" = $1 AND "id" < $2` || len(pg.CountArgs) != 2 {
+		t.Errorf("计数语句不符: %s %v", pg.CountSQL, pg.CountArgs)
+	}
+
+	del, err := BuildBulkStatement(connection.ConnectionTypeMySQL, "posts", columns, &connection.BulkUpdateRequest{
+		Action:   "delete",
+		Filters:  []connection.TableFilter{{Column: "status", Op: "in", Values: []interface{}{"a", "b"}}, {Column: "note", Op: "isNull"}},
+		MatchAny: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if del.SQL != "DELETE FROM `posts` WHERE `status` IN (?, ?) OR `note` IS NULL" || len(del.Args) != 2 {
+		t.Errorf("DELETE 语句不符: %s %v", del.SQL, del.Args)
+	}
+
+	invalid := []*connection.BulkUpdateRequest{
+		{Action: "delete"},
+		{Action: "update", AllowAll: true},
+		{Action: "update", Set: map[string]interface{}{"missing": 1}, AllowAll: true},
+		{Action: "delete", Filters: []connection.TableFilter{{Column: "missing", Op: "eq", Value: 1}}},
+		{Action: "truncate", AllowAll: true},
+	}
+	for i, r := range invalid {
+		if _, err := BuildBulkStatement(connection.ConnectionTypeMySQL, "posts", columns, r); err == nil {
+			t.Errorf("第 %d 个无效请求应报错: %+v", i, r)
+		}
+	}
+
+	all, err := BuildBulkStatement(connection.ConnectionTypeMySQL, "posts", columns, &connection.BulkUpdateRequest{Action: "delete", AllowAll: true})
+	if err != nil || all.SQL != "DELETE FROM `posts`" || all.CountSQL != "SELECT COUNT(*) FROM `posts`" {
+		t.Errorf("显式确认整表时应不带 WHERE: %v %+v", err, all)
+	}
+}
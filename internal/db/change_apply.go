@@ -0,0 +1,281 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// applySavepoint 部分提交模式下每个变更使用的保存点名称。
+const applySavepoint = "boxify_apply"
+
+// changeStatement 是变更集中单个变更对应的参数化语句。
+type changeStatement struct {
+	kind  string // 变更类型（connection.ChangeKind*）
+	index int    // 在 ChangeSet 对应列表中的下标
+	query string
+	args  []any
+}
+
+// buildMySQLChangeStatements 按删除、更新、插入的顺序生成变更语句，空变更被跳过；列按名称排序以保证语句稳定。
+// 定位条件使用 NULL 安全比较，支持无键表按全列匹配时的空值。
+func buildMySQLChangeStatements(tableName string, changes *connection.ChangeSet) ([]changeStatement, error) {
+	table := mysqlDialect.quoteTable(tableName)
+	where := mysqlMatchCondition
+
+	var stmts []changeStatement
+	for i, match := range changes.Deletes {
+		if len(match) == 0 {
+			continue
+		}
+		cond, args := where(match)
+		stmts = append(stmts, changeStatement{
+			kind:  connection.ChangeKindDelete,
+			index: i,
+			query: fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT 1", table, cond),
+			args:  args,
+		})
+	}
+	for i, update := range changes.Updates {
+		if len(update.Values) == 0 {
+			continue
+		}
+		if len(update.Keys) == 0 {
+			return nil, fmt.Errorf("更新缺少主键条件")
+		}
+		sets := make([]string, 0, len(update.Values))
+		args := make([]any, 0, len(update.Values)+len(update.Keys))
+		for _, col := range sortedKeys(update.Values) {
+			sets = append(sets, quoteMySQLIdent(col)+" = ?")
+			args = append(args, update.Values[col])
+		}
+		cond, whereArgs := where(update.Keys)
+		stmts = append(stmts, changeStatement{
+			kind:  connection.ChangeKindUpdate,
+			index: i,
+			query: fmt.Sprintf("UPDATE %s SET %s WHERE %s LIMIT 1", table, strings.Join(sets, ", "), cond),
+			args:  append(args, whereArgs...),
+		})
+	}
+	for i, row := range changes.Inserts {
+		if len(row) == 0 {
+			continue
+		}
+		cols := sortedKeys(row)
+		quoted := make([]string, len(cols))
+		args := make([]any, len(cols))
+		for j, col := range cols {
+			quoted[j] = quoteMySQLIdent(col)
+			args[j] = row[col]
+		}
+		stmts = append(stmts, changeStatement{
+			kind:  connection.ChangeKindInsert,
+			index: i,
+			query: fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")),
+			args:  args,
+		})
+	}
+	return stmts, nil
+}
+
+// mysqlMatchCondition 生成按列 NULL 安全相等的定位条件与参数，列按名称排序。
+func mysqlMatchCondition(match map[string]interface{}) (string, []any) {
+	conds := make([]string, 0, len(match))
+	args := make([]any, 0, len(match))
+	for _, col := range sortedKeys(match) {
+		conds = append(conds, quoteMySQLIdent(col)+" <=> ?")
+		args = append(args, match[col])
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// sortedKeys 返回按名称排序的列名。
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// execer 是可执行语句的事务或连接。
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execChange 执行单个变更，未影响任何行时视为失败。
+func execChange(ctx context.Context, tx execer, st changeStatement) error {
+	action, noop := "插入", "未插入任何行"
+	switch st.kind {
+	case connection.ChangeKindDelete:
+		action, noop = "删除", "未匹配到任何行"
+	case connection.ChangeKindUpdate:
+		action, noop = "更新", "未匹配到任何行"
+	}
+	res, err := tx.ExecContext(ctx, st.query, st.args...)
+	if err != nil {
+		return fmt.Errorf("%s错误：%w", action, err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("%s未生效：%s", action, noop)
+	}
+	return nil
+}
+
+// applyChangesTx 在事务内依次执行全部变更，任一变更失败时返回错误，由调用方回滚。
+func applyChangesTx(ctx context.Context, tx execer, stmts []changeStatement) (*connection.ApplyReport, error) {
+	for _, st := range stmts {
+		if err := execChange(ctx, tx, st); err != nil {
+			return nil, err
+		}
+	}
+	return &connection.ApplyReport{Total: len(stmts), Applied: len(stmts), Committed: true, Errors: []*connection.ApplyChangeError{}}, nil
+}
+
+// applyChangesPartialTx 在单个事务内逐个执行变更，每个变更由保存点保护：
+// 失败的变更回滚到保存点并记入报告，其余变更照常提交。
+func applyChangesPartialTx(ctx context.Context, session sqlSession, stmts []changeStatement) (*connection.ApplyReport, error) {
+	tx, err := session.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开启事务失败：%w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	report, err := applySavepointChanges(ctx, tx, stmts)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("提交事务失败：%w", err)
+	}
+	committed = true
+	report.Committed = true
+	return report, nil
+}
+
+// applySavepointChanges 在已开启的事务内逐个执行变更，失败的变更回滚到保存点并记入报告。
+func applySavepointChanges(ctx context.Context, tx execer, stmts []changeStatement) (*connection.ApplyReport, error) {
+	report := &connection.ApplyReport{Total: len(stmts), Errors: make([]*connection.ApplyChangeError, 0)}
+	for _, st := range stmts {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+applySavepoint); err != nil {
+			return nil, fmt.Errorf("创建保存点失败：%w", err)
+		}
+		if execErr := execChange(ctx, tx, st); execErr != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+applySavepoint); err != nil {
+				return nil, fmt.Errorf("回滚保存点失败：%w", err)
+			}
+			report.Failed++
+			report.Errors = append(report.Errors, &connection.ApplyChangeError{Kind: st.kind, Index: st.index, Message: execErr.Error()})
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+applySavepoint); err != nil {
+			return nil, fmt.Errorf("释放保存点失败：%w", err)
+		}
+		report.Applied++
+	}
+	return report, nil
+}
+
+// rowQueryer 是可执行查询的事务或连接。
+type rowQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// readMySQLChangeImages 在应用变更的事务内按定位条件锁定并读取被更新、删除行的原值。
+// 原值保留驱动返回的原始类型（二进制与文本列为 []byte），写回时不经过展示格式转换；
+// 任一行未能唯一匹配时在 Err 中返回原因，调用方不应据此生成撤销记录。
+func readMySQLChangeImages(ctx context.Context, tx rowQueryer, tableName string, changes *connection.ChangeSet) *ChangeImages {
+	images := &ChangeImages{
+		Updates: make([]map[string]interface{}, len(changes.Updates)),
+		Deletes: make([]map[string]interface{}, len(changes.Deletes)),
+	}
+	table := mysqlDialect.quoteTable(tableName)
+	read := func(match map[string]interface{}) (map[string]interface{}, error) {
+		if len(match) == 0 {
+			return map[string]interface{}{}, nil
+		}
+		cond, args := mysqlMatchCondition(match)
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT 2 FOR UPDATE", table, cond), args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		raw, err := scanRawRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) != 1 {
+			return nil, fmt.Errorf("按键匹配到 %d 行", len(raw))
+		}
+		return raw[0], nil
+	}
+	for i, update := range changes.Updates {
+		row, err := read(update.Keys)
+		if err != nil {
+			images.Err = fmt.Errorf("读取更新第 %d 行的原值失败：%w", i+1, err)
+			return images
+		}
+		images.Updates[i] = row
+	}
+	for i, del := range changes.Deletes {
+		row, err := read(del)
+		if err != nil {
+			images.Err = fmt.Errorf("读取删除第 %d 行的原值失败：%w", i+1, err)
+			return images
+		}
+		images.Deletes[i] = row
+	}
+	return images
+}
+
+// scanRawRows 读取全部结果行，列值保持驱动返回的原始值。
+func scanRawRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestBuildMySQLChangeStatements(t *testing.T) {
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{{"name": "a", "id": 3}, {}},
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"name": "b", "age": 2}}},
+		Deletes: []map[string]interface{}{{"id": 2}},
+	}
+	stmts, err := buildMySQLChangeStatements("t", changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE FROM `t` WHERE `id` <=> ? LIMIT 1",
+		"UPDATE `t` SET `age` = ?, `name` = ? WHERE `id` <=> ? LIMIT 1",
+		"INSERT INTO `t` (`id`, `name`) VALUES (?, ?)",
+	}
+	if len(stmts) != len(want) {
+		t.Fatalf("语句数量不符: %+v", stmts)
+	}
+	for i, w := range want {
+		if stmts[i].query != w {
+			t.Errorf("第 %d 条语句得到 %q，期望 %q", i, stmts[i].query, w)
+		}
+	}
+	if got := stmts[1].args; len(got) != 3 || got[0] != 2 || got[1] != "b" || got[2] != 1 {
+		t.Errorf("更新参数顺序不符: %v", got)
+	}
+
+	_, err = buildMySQLChangeStatements("t", &connection.ChangeSet{Updates: []connection.UpdateRow{{Values: map[string]interface{}{"a": 1}}}})
+	if err == nil {
+		t.Error("缺少定位条件的更新应报错")
+	}
+}
+
+func TestApplyChangesPartialTx(t *testing.T) {
+	conn, drv := openFakeImportDB(t)
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{{"name": "ok"}, {"name": "bad"}},
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"name": "bad"}}},
+		Deletes: []map[string]interface{}{{"id": 2}},
+	}
+	stmts, err := buildMySQLChangeStatements("t", changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := applyChangesPartialTx(context.Background(), conn, stmts)
+	if err != nil {
+		t.Fatalf("部分提交失败: %v", err)
+	}
+	if !report.Committed || !drv.committed || report.Total != 4 || report.Applied != 2 || report.Failed != 2 {
+		t.Fatalf("报告不符: %+v", report)
+	}
+	if e := report.Errors[0]; e.Kind != connection.ChangeKindUpdate || e.Index != 0 || !strings.Contains(e.Message, "更新错误") {
+		t.Errorf("失败明细不符: %+v", e)
+	}
+	if e := report.Errors[1]; e.Kind != connection.ChangeKindInsert || e.Index != 1 {
+		t.Errorf("失败明细不符: %+v", e)
+	}
+	rollbacks := 0
+	for _, q := range drv.execs {
+		if q == "ROLLBACK TO SAVEPOINT "+applySavepoint {
+			rollbacks++
+		}
+	}
+	if rollbacks != 2 {
+		t.Errorf("每个失败变更应回滚到保存点: %v", drv.execs)
+	}
+}
+
+func TestReadMySQLChangeImagesKeepsRawValues(t *testing.T) {
+	conn, drv := openFakeImportDB(t)
+	blob := []byte{0x00, 0xff, 0x10}
+	drv.columns = []string{"id", "data"}
+	drv.rows = [][]driver.Value{{int64(1), blob}}
+	changes := &connection.ChangeSet{
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"data": "x"}}},
+		Deletes: []map[string]interface{}{{"id": 1}},
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	images := readMySQLChangeImages(context.Background(), tx, "t", changes)
+	if images.Err != nil {
+		t.Fatalf("读取原值失败: %v", images.Err)
+	}
+	got, ok := images.Updates[0]["data"].([]byte)
+	if !ok || !bytes.Equal(got, blob) || images.Deletes[0]["id"] != int64(1) {
+		t.Errorf("原值应保留驱动原始类型: %#v %#v", images.Updates[0], images.Deletes[0])
+	}
+	if want := "SELECT * FROM `t` WHERE `id` <=> ? LIMIT 2 FOR UPDATE"; drv.execs[0] != want {
+		t.Errorf("原值查询 = %q, want %q", drv.execs[0], want)
+	}
+
+	drv.rows = append(drv.rows, drv.rows[0])
+	if images := readMySQLChangeImages(context.Background(), tx, "t", changes); images.Err == nil {
+		t.Error("匹配到多行时应返回错误")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// InvertChangeSet 根据变更前的行映像生成撤销变更集：插入变为按键删除，更新还原被修改列的原值，删除变为插入原行。
+//
+// changes 应为 NormalizeChangeSet 的结果，keyColumns 为定位行所用的列（无键表为全部列）；
+// updatePre/deletePre 依次对应 changes.Updates/changes.Deletes 的完整原行。
+// 插入行缺少键列值（如依赖自增主键）时无法定位，计入 skipped 并跳过。
+func InvertChangeSet(keyColumns []string, changes *connection.ChangeSet, updatePre, deletePre []map[string]interface{}) (inverse *connection.ChangeSet, skipped int, err error) {
+	if len(updatePre) != len(changes.Updates) || len(deletePre) != len(changes.Deletes) {
+		return nil, 0, fmt.Errorf("行映像数量与变更不一致")
+	}
+	inverse = &connection.ChangeSet{}
+
+	for _, row := range changes.Inserts {
+		match := make(map[string]interface{}, len(keyColumns))
+		for _, col := range keyColumns {
+			v, ok := row[col]
+			if !ok {
+				match = nil
+				break
+			}
+			match[col] = v
+		}
+		if len(match) == 0 {
+			skipped++
+			continue
+		}
+		inverse.Deletes = append(inverse.Deletes, match)
+	}
+
+	for i, update := range changes.Updates {
+		pre := updatePre[i]
+		keys := make(map[string]interface{}, len(keyColumns))
+		for _, col := range keyColumns {
+			if v, ok := update.Values[col]; ok {
+				keys[col] = v
+			} else {
+				keys[col] = update.Keys[col]
+			}
+		}
+		values := make(map[string]interface{}, len(update.Values))
+		for col := range update.Values {
+			v, ok := pre[col]
+			if !ok {
+				return nil, 0, fmt.Errorf("更新第 %d 行的原行缺少列 %s", i+1, col)
+			}
+			values[col] = v
+		}
+		inverse.Updates = append(inverse.Updates, connection.UpdateRow{Keys: keys, Values: values})
+	}
+
+	for _, pre := range deletePre {
+		row := make(map[string]interface{}, len(pre))
+		for col, v := range pre {
+			row[col] = v
+		}
+		inverse.Inserts = append(inverse.Inserts, row)
+	}
+	return inverse, skipped, nil
+}
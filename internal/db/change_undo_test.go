@@ -0,0 +1,64 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestInvertChangeSet(t *testing.T) {
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{
+			{"id": 10, "name": "new"},
+			{"name": "auto"}, // 自增主键未给出，无法撤销
+		},
+		Updates: []connection.UpdateRow{
+			{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"name": "b"}},
+			{Keys: map[string]interface{}{"id": 2}, Values: map[string]interface{}{"id": 3}},
+		},
+		Deletes: []map[string]interface{}{{"id": 5}},
+	}
+	updatePre := []map[string]interface{}{
+		{"id": 1, "name": "a", "age": 20},
+		{"id": 2, "name": "c", "age": 30},
+	}
+	deletePre := []map[string]interface{}{{"id": 5, "name": "gone", "age": nil}}
+
+	inverse, skipped, err := InvertChangeSet([]string{"id"}, changes, updatePre, deletePre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 {
+		t.Errorf("缺少键的插入应被跳过: %d", skipped)
+	}
+	want := &connection.ChangeSet{
+		Deletes: []map[string]interface{}{{"id": 10}},
+		Updates: []connection.UpdateRow{
+			{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"name": "a"}},
+			{Keys: map[string]interface{}{"id": 3}, Values: map[string]interface{}{"id": 2}},
+		},
+		Inserts: []map[string]interface{}{{"id": 5, "name": "gone", "age": nil}},
+	}
+	if !reflect.DeepEqual(inverse, want) {
+		t.Errorf("撤销变更集不符:\n得到 %+v\n期望 %+v", inverse, want)
+	}
+
+	if _, _, err := InvertChangeSet([]string{"id"}, changes, nil, deletePre); err == nil {
+		t.Error("行映像数量不一致应报错")
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// 图表聚合的分组与系列数量上限。
+const (
+	DefaultChartMaxLabels = 500
+	MaxChartLabels        = 5000
+	DefaultChartMaxSeries = 10
+	MaxChartSeries        = 50
+)
+
+// 图表聚合查询结果的列别名。
+const (
+	chartLabelCol  = "chart_label"
+	chartSeriesCol = "chart_series"
+	chartValueCol  = "chart_value"
+)
+
+// ChartAggregates 是支持的聚合函数。
+var ChartAggregates = []string{"count", "countDistinct", "sum", "avg", "min", "max"}
+
+// ChartTimeBuckets 是支持的时间分桶粒度。
+var ChartTimeBuckets = []string{"minute", "hour", "day", "week", "month", "year"}
+
+// ChartLimits 返回规整后的分组与系列上限。
+func ChartLimits(req *connection.ChartRequest) (maxLabels, maxSeries int) {
+	maxLabels, maxSeries = req.MaxLabels, req.MaxSeries
+	if maxLabels <= 0 {
+		maxLabels = DefaultChartMaxLabels
+	}
+	if maxLabels > MaxChartLabels {
+		maxLabels = MaxChartLabels
+	}
+	if maxSeries <= 0 {
+		maxSeries = DefaultChartMaxSeries
+	}
+	if maxSeries > MaxChartSeries {
+		maxSeries = MaxChartSeries
+	}
+	return maxLabels, maxSeries
+}
+
+// BuildChartQuery 构造图表聚合查询：按分组列（可按时间分桶）与可选的系列列分组，返回
+// chart_label、chart_series、chart_value 三列。
+//
+// 拆分系列时先在子查询中选出按标签升序的前 maxLabels+1 个标签与按聚合值降序的前 maxSeries+1 个系列，
+// 再只对这些标签与系列聚合，避免系列过多时结果行数上限截掉靠后的标签。
+func BuildChartQuery(dbType connection.ConnectionType, req *connection.ChartRequest) (string, error) {
+	d := dialectFor(dbType)
+	source := strings.TrimRight(strings.TrimSpace(req.Query), "; \t\r\n")
+	if source != "" {
+		source = "(" + source + ") chart_src"
+	} else if req.Table != "" {
+		source = d.quoteTable(req.Table)
+	} else {
+		return "", errors.New("需要指定查询或表名")
+	}
+
+	label := d.quoteIdent(req.GroupBy)
+	if req.TimeBucket != "" {
+		var err error
+		if label, err = timeBucketExpr(dbType, label, req.TimeBucket); err != nil {
+			return "", err
+		}
+	}
+	value, err := chartAggregateExpr(d, req.Aggregate, req.ValueColumn)
+	if err != nil {
+		return "", err
+	}
+
+	maxLabels, maxSeries := ChartLimits(req)
+	if req.SeriesBy == "" {
+		orders := chartLabelCol
+		if req.SortByValue {
+			orders = chartValueCol + " DESC, " + chartLabelCol
+		}
+		return fmt.Sprintf("SELECT %s AS %s, %s AS %s FROM %s GROUP BY %s ORDER BY %s",
+			label, chartLabelCol, value, chartValueCol, source, label, orders) + d.Limit(maxLabels+1, true), nil
+	}
+
+	series := d.quoteIdent(req.SeriesBy)
+	topLabels := fmt.Sprintf("SELECT %s AS top_label FROM %s GROUP BY %s ORDER BY top_label", label, source, label) +
+		d.Limit(maxLabels+1, true)
+	topSeries := fmt.Sprintf("SELECT %s AS top_series, %s AS top_value FROM %s GROUP BY %s ORDER BY top_value DESC, top_series", series, value, source, series) +
+		d.Limit(maxSeries+1, true)
+	return fmt.Sprintf("SELECT %s AS %s, %s AS %s, %s AS %s FROM %s JOIN (%s) chart_top_labels ON %s JOIN (%s) chart_top_series ON %s GROUP BY %s, %s ORDER BY %s, %s",
+		label, chartLabelCol, series, chartSeriesCol, value, chartValueCol, source,
+		topLabels, nullSafeEqual(label, "chart_top_labels.top_label"),
+		topSeries, nullSafeEqual(series, "chart_top_series.top_series"),
+		label, series, chartLabelCol, chartSeriesCol) + d.Limit((maxLabels+1)*(maxSeries+1), true), nil
+}
+
+// nullSafeEqual 返回 NULL 与 NULL 视为相等的比较表达式，各方言通用。
+func nullSafeEqual(a, b string) string {
+	return "(" + a + " = " + b + " OR (" + a + " IS NULL AND " + b + " IS NULL))"
+}
+
+// chartAggregateExpr 返回聚合表达式。
+func chartAggregateExpr(d sqlDialect, aggregate, column string) (string, error) {
+	if column == "" {
+		if !strings.EqualFold(aggregate, "count") {
+			return "", fmt.Errorf("聚合函数 %s 需要指定聚合列", aggregate)
+		}
+		return "COUNT(*)", nil
+	}
+	col := d.quoteIdent(column)
+	switch strings.ToLower(aggregate) {
+	case "count":
+		return "COUNT(" + col + ")", nil
+	case "countdistinct":
+		return "COUNT(DISTINCT " + col + ")", nil
+	case "sum", "avg", "min", "max":
+		return strings.ToUpper(aggregate) + "(" + col + ")", nil
+	default:
+		return "", fmt.Errorf("不支持的聚合函数: %s", aggregate)
+	}
+}
+
+// timeBucketExpr 返回将时间列截断到 bucket 粒度并格式化为可排序文本的表达式，周以周一为起点。
+func timeBucketExpr(dbType connection.ConnectionType, col, bucket string) (string, error) {
+	bucket = strings.ToLower(bucket)
+	switch {
+	case dbType == connection.ConnectionTypeMySQL || dbType == connection.ConnectionTypeMariaDB:
+		formats := map[string]string{
+			"minute": "%Y-%m-%d %H:%i:00", "hour": "%Y-%m-%d %H:00:00", "day": "%Y-%m-%d",
+			"week": "%Y-%m-%d", "month": "%Y-%m", "year": "%Y",
+		}
+		format, ok := formats[bucket]
+		if !ok {
+			break
+		}
+		if bucket == "week" {
+			col = "DATE_SUB(" + col + ", INTERVAL WEEKDAY(" + col + ") DAY)"
+		}
+		return "DATE_FORMAT(" + col + ", '" + format + "')", nil
+	case IsPostgresDialect(dbType):
+		formats := map[string]string{
+			"minute": "YYYY-MM-DD HH24:MI:00", "hour": "YYYY-MM-DD HH24:00:00", "day": "YYYY-MM-DD",
+			"week": "YYYY-MM-DD", "month": "YYYY-MM", "year": "YYYY",
+		}
+		format, ok := formats[bucket]
+		if !ok {
+			break
+		}
+		return "to_char(date_trunc('" + bucket + "', " + col + "), '" + format + "')", nil
+	case dbType == connection.ConnectionTypeSQLite:
+		formats := map[string]string{
+			"minute": "%Y-%m-%d %H:%M:00", "hour": "%Y-%m-%d %H:00:00", "day": "%Y-%m-%d",
+			"week": "%Y-%m-%d", "month": "%Y-%m", "year": "%Y",
+		}
+		format, ok := formats[bucket]
+		if !ok {
+			break
+		}
+		if bucket == "week" {
+			col = "date(" + col + ", '-6 days', 'weekday 1')"
+		}
+		return "strftime('" + format + "', " + col + ")", nil
+	default:
+		return "", fmt.Errorf("%s 暂不支持时间分桶", dbType)
+	}
+	return "", fmt.Errorf("不支持的时间分桶: %s", bucket)
+}
+
+// ShapeChartData 将 BuildChartQuery 的结果整理为标签与系列。未拆分系列时唯一系列命名为 seriesName；
+// 拆分系列时按聚合值绝对值之和降序保留前 maxSeries 个。
+func ShapeChartData(rows []map[string]interface{}, req *connection.ChartRequest, seriesName string) *connection.ChartData {
+	maxLabels, maxSeries := ChartLimits(req)
+	chart := &connection.ChartData{Labels: []string{}, Series: []connection.ChartSeries{}}
+	labelIndex := make(map[string]int)
+	values := make(map[string]map[int]float64)
+	totals := make(map[string]float64)
+	var seriesOrder []string
+
+	for _, row := range rows {
+		label := chartText(row[chartLabelCol])
+		idx, ok := labelIndex[label]
+		if !ok {
+			if len(chart.Labels) >= maxLabels {
+				chart.Truncated = true
+				continue
+			}
+			idx = len(chart.Labels)
+			labelIndex[label] = idx
+			chart.Labels = append(chart.Labels, label)
+		}
+		name := seriesName
+		if req.SeriesBy != "" {
+			name = chartText(row[chartSeriesCol])
+		}
+		if _, ok := values[name]; !ok {
+			values[name] = make(map[int]float64)
+			seriesOrder = append(seriesOrder, name)
+		}
+		if v, ok := chartNumber(row[chartValueCol]); ok {
+			values[name][idx] = v
+			totals[name] += math.Abs(v)
+		}
+	}
+
+	if req.SeriesBy != "" {
+		sort.SliceStable(seriesOrder, func(i, j int) bool { return totals[seriesOrder[i]] > totals[seriesOrder[j]] })
+		if len(seriesOrder) > maxSeries {
+			seriesOrder, chart.Truncated = seriesOrder[:maxSeries], true
+		}
+	} else if len(seriesOrder) == 0 {
+		seriesOrder = []string{seriesName}
+	}
+	for _, name := range seriesOrder {
+		data := make([]*float64, len(chart.Labels))
+		for idx, v := range values[name] {
+			data[idx] = &v
+		}
+		chart.Series = append(chart.Series, connection.ChartSeries{Name: name, Data: data})
+	}
+	return chart
+}
+
+// chartText 将分组值转换为标签文本。
+func chartText(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(val)
+	case time.Time:
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Nanosecond() == 0 {
+			return val.Format("2006-01-02")
+		}
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// chartNumber 将聚合值转换为浮点数，NULL 或非数值返回 false。
+func chartNumber(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int64:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case []byte:
+		f, err := strconv.ParseFloat(string(val), 64)
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestBuildChartQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType connection.ConnectionType
+		req    connection.ChartRequest
+		want   string
+	}{
+		{
+			name:   "按表计数",
+			dbType: connection.ConnectionTypeMySQL,
+			req:    connection.ChartRequest{Table: "orders", GroupBy: "status", Aggregate: "count", SortByValue: true, MaxLabels: 20},
+			want:   "SELECT `status` AS chart_label, COUNT(*) AS chart_value FROM `orders` GROUP BY `status` ORDER BY chart_value DESC, chart_label LIMIT 21",
+		},
+		{
+			name:   "查询按天分桶并拆分系列",
+			dbType: connection.ConnectionTypePostgreSQL,
+			req:    connection.ChartRequest{Query: "SELECT * FROM orders;", GroupBy: "created_at", TimeBucket: "day", SeriesBy: "region", Aggregate: "sum", ValueColumn: "amount", MaxLabels: 10, MaxSeries: 3},
+			want: `SELECT to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD') AS chart_label, "region" AS chart_series, SUM("amount") AS chart_value FROM (SELECT * FROM orders) chart_src` +
+				` JOIN (SELECT to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD') AS top_label FROM (SELECT * FROM orders) chart_src GROUP BY to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD') ORDER BY top_label LIMIT 11) chart_top_labels` +
+				` ON (to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD') = chart_top_labels.top_label OR (to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD') IS NULL AND chart_top_labels.top_label IS NULL))` +
+				` JOIN (SELECT "region" AS top_series, SUM("amount") AS top_value FROM (SELECT * FROM orders) chart_src GROUP BY "region" ORDER BY top_value DESC, top_series LIMIT 4) chart_top_series` +
+				` ON ("region" = chart_top_series.top_series OR ("region" IS NULL AND chart_top_series.top_series IS NULL))` +
+				` GROUP BY to_char(date_trunc('day', "created_at"), 'YYYY-MM-DD'), "region" ORDER BY chart_label, chart_series LIMIT 44`,
+		},
+		{
+			name:   "MySQL 按周分桶",
+			dbType: connection.ConnectionTypeMySQL,
+			req:    connection.ChartRequest{Table: "events", GroupBy: "ts", TimeBucket: "week", Aggregate: "countDistinct", ValueColumn: "user_id"},
+			want:   "SELECT DATE_FORMAT(DATE_SUB(`ts`, INTERVAL WEEKDAY(`ts`) DAY), '%Y-%m-%d') AS chart_label, COUNT(DISTINCT `user_id`) AS chart_value FROM `events` GROUP BY DATE_FORMAT(DATE_SUB(`ts`, INTERVAL WEEKDAY(`ts`) DAY), '%Y-%m-%d') ORDER BY chart_label LIMIT 501",
+		},
+		{
+			name:   "SQLite 按小时分桶",
+			dbType: connection.ConnectionTypeSQLite,
+			req:    connection.ChartRequest{Table: "logs", GroupBy: "at", TimeBucket: "hour", Aggregate: "max", ValueColumn: "latency"},
+			want:   `SELECT strftime('%Y-%m-%d %H:00:00', "at") AS chart_label, MAX("latency") AS chart_value FROM "logs" GROUP BY strftime('%Y-%m-%d %H:00:00', "at") ORDER BY chart_label LIMIT 501`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := BuildChartQuery(tt.dbType, &tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\n got  %s\n want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildChartQueryKeepsLabelsWithManySeries(t *testing.T) {
+	ctx := context.Background()
+	lite := NewSQLiteDB()
+	if err := lite.Connect(&connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: filepath.Join(t.TempDir(), "chart.db")}); err != nil {
+		t.Fatalf("打开 SQLite 数据库失败: %v", err)
+	}
+	defer lite.Close()
+	if _, err := lite.ExecContext(ctx, `CREATE TABLE sales (day TEXT, region TEXT, amount INTEGER)`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	// 第一天有 30 个小系列，第二、三天只有两个大系列
+	for i := 0; i < 30; i++ {
+		if _, err := lite.ExecContext(ctx, `INSERT INTO sales VALUES ('d1', ?, 1)`, fmt.Sprintf("r%02d", i)); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+	for _, day := range []string{"d1", "d2", "d3"} {
+		if _, err := lite.ExecContext(ctx, `INSERT INTO sales VALUES (?, 'big', 100), (?, NULL, 50)`, day, day); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+
+	req := &connection.ChartRequest{Table: "sales", GroupBy: "day", SeriesBy: "region", Aggregate: "sum", ValueColumn: "amount", MaxLabels: 3, MaxSeries: 2}
+	query, err := BuildChartQuery(connection.ConnectionTypeSQLite, req)
+	if err != nil {
+		t.Fatalf("构造查询失败: %v", err)
+	}
+	rows, _, err := lite.QueryContext(ctx, query)
+	if err != nil {
+		t.Fatalf("执行查询失败: %v\n%s", err, query)
+	}
+	chart := ShapeChartData(rows, req, "")
+	if len(chart.Labels) != 3 || chart.Labels[2] != "d3" {
+		t.Fatalf("系列过多时不应截掉标签: %+v", chart.Labels)
+	}
+	if len(chart.Series) != 2 || chart.Series[0].Name != "big" || chart.Series[1].Name != "NULL" || !chart.Truncated {
+		t.Fatalf("应保留聚合值最大的两个系列: %+v", chart.Series)
+	}
+	if v := chart.Series[1].Data[2]; v == nil || *v != 50 {
+		t.Errorf("NULL 系列的聚合值不符: %v", v)
+	}
+}
+
+func TestBuildChartQueryErrors(t *testing.T) {
+	cases := []struct {
+		dbType connection.ConnectionType
+		req    connection.ChartRequest
+		want   string
+	}{
+		{connection.ConnectionTypeMySQL, connection.ChartRequest{GroupBy: "a", Aggregate: "count"}, "查询或表名"},
+		{connection.ConnectionTypeMySQL, connection.ChartRequest{Table: "t", GroupBy: "a", Aggregate: "sum"}, "聚合列"},
+		{connection.ConnectionTypeMySQL, connection.ChartRequest{Table: "t", GroupBy: "a", Aggregate: "median", ValueColumn: "v"}, "聚合函数"},
+		{connection.ConnectionTypeMySQL, connection.ChartRequest{Table: "t", GroupBy: "a", Aggregate: "count", TimeBucket: "decade"}, "时间分桶"},
+		{connection.ConnectionTypeSQLServer, connection.ChartRequest{Table: "t", GroupBy: "a", Aggregate: "count", TimeBucket: "day"}, "暂不支持"},
+	}
+	for _, c := range cases {
+		if _, err := BuildChartQuery(c.dbType, &c.req); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v: err = %v, want contains %q", c.req, err, c.want)
+		}
+	}
+}
+
+func TestShapeChartData(t *testing.T) {
+	req := &connection.ChartRequest{SeriesBy: "region", MaxLabels: 2, MaxSeries: 2}
+	rows := []map[string]interface{}{
+		{"chart_label": []byte("2026-03-01"), "chart_series": "north", "chart_value": int64(5)},
+		{"chart_label": []byte("2026-03-01"), "chart_series": "south", "chart_value": []byte("20.5")},
+		{"chart_label": []byte("2026-03-01"), "chart_series": "west", "chart_value": int64(1)},
+		{"chart_label": []byte("2026-03-02"), "chart_series": "south", "chart_value": nil},
+		{"chart_label": []byte("2026-03-03"), "chart_series": "north", "chart_value": int64(7)},
+	}
+	chart := ShapeChartData(rows, req, "")
+	if !chart.Truncated || len(chart.Labels) != 2 || chart.Labels[1] != "2026-03-02" {
+		t.Fatalf("标签不符: %+v", chart)
+	}
+	if len(chart.Series) != 2 || chart.Series[0].Name != "south" || chart.Series[1].Name != "north" {
+		t.Fatalf("系列应按合计降序保留前两个: %+v", chart.Series)
+	}
+	south := chart.Series[0].Data
+	if *south[0] != 20.5 || south[1] != nil {
+		t.Errorf("south = %v", south)
+	}
+
+	single := ShapeChartData([]map[string]interface{}{
+		{"chart_label": time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "chart_value": float64(3)},
+		{"chart_label": nil, "chart_value": int64(1)},
+	}, &connection.ChartRequest{}, "count(*)")
+	if len(single.Series) != 1 || single.Series[0].Name != "count(*)" || single.Labels[0] != "2026-03-01" || single.Labels[1] != "NULL" {
+		t.Errorf("单系列结果不符: %+v", single)
+	}
+	empty := ShapeChartData(nil, &connection.ChartRequest{}, "count(*)")
+	if len(empty.Series) != 1 || len(empty.Labels) != 0 {
+		t.Errorf("空结果应返回一个空系列: %+v", empty)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// DefaultHealthCheckInterval 是连接健康检查的默认间隔。
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// DefaultDegradedLatency 是探活耗时达到该值即视为连接变慢的阈值。
+const DefaultDegradedLatency = time.Second
+
+// 连接健康状态。
+const (
+	HealthHealthy     = "healthy"     // 探活正常
+	HealthDegraded    = "degraded"    // 探活成功但耗时超过阈值
+	HealthLost        = "lost"        // 探活失败且重连失败
+	HealthReconnected = "reconnected" // 探活失败后重连成功，或失联后恢复
+)
+
+// HealthStatus 是一个缓存连接的健康检查结果。
+type HealthStatus struct {
+	Key       string                    `json:"key"` // 连接缓存标识（缩短）
+	Type      connection.ConnectionType `json:"type"`
+	Host      string                    `json:"host"`
+	Port      int                       `json:"port"`
+	User      string                    `json:"user"`
+	Database  string                    `json:"database"`
+	State     string                    `json:"state"`
+	LatencyMs int64                     `json:"latencyMs"`
+	Failures  int                       `json:"failures"` // 连续失败次数
+	Error     string                    `json:"error,omitempty"`
+	CheckedAt time.Time                 `json:"checkedAt"`
+}
+
+// healthRecord 记录连接的配置与最近一次健康状态；失联连接已从缓存移除，但保留记录以便后续重连。
+type healthRecord struct {
+	config connection.ConnectionConfig
+	status HealthStatus
+}
+
+// SetHealthListener 设置健康状态变化的回调，fn 为 nil 时取消。
+func (m *ConnectionManager) SetHealthListener(fn func(HealthStatus)) {
+	m.mu.Lock()
+	m.healthListener = fn
+	m.mu.Unlock()
+}
+
+// StartHealthMonitor 启动后台协程每隔 interval 检查一次缓存连接，ctx 结束时退出。
+func (m *ConnectionManager) StartHealthMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.CheckHealth()
+			}
+		}
+	}()
+}
+
+// HealthStatuses 返回全部受监控连接的最近一次健康状态。
+func (m *ConnectionManager) HealthStatuses() []HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]HealthStatus, 0, len(m.health))
+	for _, rec := range m.health {
+		list = append(list, rec.status)
+	}
+	return list
+}
+
+// CheckHealth 对缓存连接逐个探活并尝试重连失联连接，状态变化时通知监听者，返回本次检查结果。
+// 已被回收或主动断开的连接不再监控。
+func (m *ConnectionManager) CheckHealth() []HealthStatus {
+	type target struct {
+		key    string
+		config connection.ConnectionConfig
+		inst   Database
+		prev   string
+	}
+	m.mu.Lock()
+	targets := make([]target, 0, len(m.health))
+	for key, rec := range m.health {
+		entry, cached := m.cache[key]
+		if !cached && rec.status.State != HealthLost {
+			delete(m.health, key)
+			continue
+		}
+		targets = append(targets, target{key: key, config: rec.config, inst: entry.inst, prev: rec.status.State})
+	}
+	listener := m.healthListener
+	m.mu.Unlock()
+
+	results := make([]HealthStatus, 0, len(targets))
+	for _, t := range targets {
+		status := m.probe(t.key, &t.config, t.inst, t.prev)
+		m.mu.Lock()
+		rec, ok := m.health[t.key]
+		if ok {
+			if status.State == HealthLost {
+				status.Failures = rec.status.Failures + 1
+			}
+			rec.status = status
+		}
+		m.mu.Unlock()
+		results = append(results, status)
+		if listener != nil && status.State != t.prev {
+			listener(status)
+		}
+	}
+	return results
+}
+
+// probe 探活单个连接；失败（或已失联）时尝试重建连接。
+func (m *ConnectionManager) probe(key string, config *connection.ConnectionConfig, inst Database, prev string) HealthStatus {
+	status := HealthStatus{
+		Key:       shortCacheKey(key),
+		Type:      config.Type,
+		Host:      config.Host,
+		Port:      config.Port,
+		User:      config.User,
+		Database:  config.Database,
+		CheckedAt: time.Now(),
+	}
+
+	if inst != nil {
+		start := time.Now()
+		err := inst.Ping()
+		status.LatencyMs = time.Since(start).Milliseconds()
+		if err == nil {
+			m.touchPing(key, inst)
+			switch {
+			case time.Duration(status.LatencyMs)*time.Millisecond >= m.degradedLatency:
+				status.State = HealthDegraded
+			case prev == HealthLost:
+				status.State = HealthReconnected
+			default:
+				status.State = HealthHealthy
+			}
+			return status
+		}
+		m.logError("连接健康检查失败，尝试重连", "summary", FormatConnSummary(config), "key", status.Key, "error", err)
+	}
+
+	start := time.Now()
+	if _, err := m.Get(config, true); err != nil {
+		status.State = HealthLost
+		status.Error = err.Error()
+		m.markLost(key, config)
+		return status
+	}
+	status.LatencyMs = time.Since(start).Milliseconds()
+	status.State = HealthReconnected
+	return status
+}
+
+// trackHealth 登记需要健康监控的连接配置。
+func (m *ConnectionManager) trackHealth(key string, config *connection.ConnectionConfig) {
+	if _, ok := m.health[key]; ok {
+		return
+	}
+	m.health[key] = &healthRecord{config: *config, status: HealthStatus{Key: shortCacheKey(key), State: HealthHealthy}}
+}
+
+// markLost 在重连失败、缓存已被移除后保留健康记录，以便下次检查继续尝试重连。
+func (m *ConnectionManager) markLost(key string, config *connection.ConnectionConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.health[key]; !ok {
+		m.health[key] = &healthRecord{config: *config}
+	}
+}
+
+// touchPing 更新缓存连接的探活时间，不影响空闲回收使用的最近使用时间。
+func (m *ConnectionManager) touchPing(key string, inst Database) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, exists := m.cache[key]; exists && cur.inst == inst {
+		cur.lastPing = time.Now()
+		m.cache[key] = cur
+	}
+}
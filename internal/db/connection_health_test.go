@@ -0,0 +1,82 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// pingDatabase 只实现健康检查用到的方法。
+type pingDatabase struct {
+	Database
+	err error
+}
+
+func (p *pingDatabase) Ping() error  { return p.err }
+func (p *pingDatabase) Close() error { return nil }
+
+func TestConnectionManagerCheckHealth(t *testing.T) {
+	m := NewConnectionManager(nil)
+	var events []HealthStatus
+	m.SetHealthListener(func(s HealthStatus) { events = append(events, s) })
+
+	// 未配置 DSN 的自定义连接重连会立即失败，用于模拟失联
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "boxify-stub", Host: "h"}
+	key := cacheKey(cfg)
+	inst := &pingDatabase{}
+	m.cache[key] = cacheEntry{inst: inst, lastUsed: time.Now(), server: serverKey(cfg)}
+	m.trackHealth(key, cfg)
+
+	if got := m.CheckHealth(); len(got) != 1 || got[0].State != HealthHealthy || len(events) != 0 {
+		t.Fatalf("健康连接不应产生事件: %+v %+v", got, events)
+	}
+
+	m.degradedLatency = 0
+	if got := m.CheckHealth(); got[0].State != HealthDegraded || len(events) != 1 {
+		t.Fatalf("超过阈值应为 degraded: %+v", got)
+	}
+	m.degradedLatency = DefaultDegradedLatency
+
+	inst.err = errors.New("connection reset")
+	for i := 1; i <= 2; i++ {
+		got := m.CheckHealth()
+		if got[0].State != HealthLost || got[0].Failures != i || got[0].Error == "" {
+			t.Fatalf("第 %d 次检查应为 lost: %+v", i, got[0])
+		}
+	}
+	if len(events) != 2 || events[1].State != HealthLost || m.Count() != 0 {
+		t.Fatalf("失联只应通知一次且移除缓存: events=%+v count=%d", events, m.Count())
+	}
+
+	// 模拟连接恢复后再次进入缓存
+	m.cache[key] = cacheEntry{inst: &pingDatabase{}, lastUsed: time.Now(), server: serverKey(cfg)}
+	if got := m.CheckHealth(); got[0].State != HealthReconnected || got[0].Failures != 0 {
+		t.Fatalf("失联后恢复应为 reconnected: %+v", got[0])
+	}
+	if statuses := m.HealthStatuses(); len(statuses) != 1 || statuses[0].State != HealthReconnected {
+		t.Fatalf("最近状态不符: %+v", statuses)
+	}
+
+	if _, err := m.Disconnect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.CheckHealth(); len(got) != 0 || len(m.HealthStatuses()) != 0 {
+		t.Fatalf("断开后不应继续监控: %+v", got)
+	}
+}
@@ -33,18 +33,43 @@ import (
 // DefaultCachePingInterval 是缓存连接的默认探活间隔。
 const DefaultCachePingInterval = 30 * time.Second
 
+// DefaultIdleTimeout 是缓存连接的默认空闲回收时长。
+const DefaultIdleTimeout = 30 * time.Minute
+
+// reapInterval 是空闲连接回收的检查间隔。
+const reapInterval = time.Minute
+
 // cacheEntry 描述一个已缓存的数据库连接及其最近探活时间。
 type cacheEntry struct {
 	inst     Database
 	lastPing time.Time
+	lastUsed time.Time // 最近一次被获取的时间，用于空闲回收
+	server   string    // 不含库名的连接标识，同一服务器不同库的连接共享
+	schema   string    // 活动 schema，重建连接后恢复
+}
+
+// busyChecker 由能报告是否仍有查询在执行的实现提供，回收空闲连接时跳过忙碌的连接。
+type busyChecker interface {
+	Busy() bool
 }
 
-// ConnectionManager 管理数据库连接缓存、探活和重建。
+// ConnectionManager 管理数据库连接缓存、探活、重建与空闲回收。
 type ConnectionManager struct {
 	mu           sync.RWMutex
 	logger       *slog.Logger
 	pingInterval time.Duration
+	idleTimeout  time.Duration // 0 表示不回收
 	cache        map[string]cacheEntry
+
+	degradedLatency time.Duration            // 探活耗时达到该值视为变慢
+	health          map[string]*healthRecord // 健康监控记录，按缓存 key 索引
+	healthListener  func(HealthStatus)       // 健康状态变化回调
+
+	active       map[string]int       // 多主机连接当前使用的主机序号，按 serverKey 索引
+	hostDown     map[string]time.Time // 不可达主机（按缓存 key）在该时间前不再优先尝试
+	hostErrs     map[string]string    // 不可达主机最近一次的连接错误
+	readCursor   map[string]int       // round-robin 只读查询的轮询位置
+	hostListener func(HostChange)     // 活动主机变化回调
 }
 
 // NewConnectionManager 创建数据库连接管理器。
@@ -52,12 +77,32 @@ func NewConnectionManager(logger *slog.Logger) *ConnectionManager {
 	return &ConnectionManager{
 		logger:       logger,
 		pingInterval: DefaultCachePingInterval,
+		idleTimeout:  DefaultIdleTimeout,
 		cache:        make(map[string]cacheEntry),
+
+		degradedLatency: DefaultDegradedLatency,
+		health:          make(map[string]*healthRecord),
+
+		active:     make(map[string]int),
+		hostDown:   make(map[string]time.Time),
+		hostErrs:   make(map[string]string),
+		readCursor: make(map[string]int),
 	}
 }
 
 // Get 返回可用数据库连接；forcePing=true 时会强制探活。
+// 配置了备用主机时按主机策略选择可用主机，主库不可达时自动切换。
 func (m *ConnectionManager) Get(config *connection.ConnectionConfig, forcePing bool) (Database, error) {
+	// 驱动只接触规范化后的副本，调用方的配置可被并发复用
+	config = config.Normalize()
+	if len(config.Hosts) > 0 {
+		return m.getFailover(config, forcePing)
+	}
+	return m.getSingle(config, forcePing)
+}
+
+// getSingle 返回单个主机的缓存连接，不可用时重建；config 已规范化。
+func (m *ConnectionManager) getSingle(config *connection.ConnectionConfig, forcePing bool) (Database, error) {
 	key := cacheKey(config)
 	shortKey := shortCacheKey(key)
 
@@ -65,23 +110,21 @@ func (m *ConnectionManager) Get(config *connection.ConnectionConfig, forcePing b
 	entry, ok := m.cache[key]
 	m.mu.RUnlock()
 
+	schema := ""
 	if ok {
+		schema = entry.schema
 		needPing := forcePing
 		if !needPing && (entry.lastPing.IsZero() || time.Since(entry.lastPing) >= m.pingInterval) {
 			needPing = true
 		}
 
 		if !needPing {
+			m.touch(key, entry.inst, false)
 			return entry.inst, nil
 		}
 
 		if err := entry.inst.Ping(); err == nil {
-			m.mu.Lock()
-			if cur, exists := m.cache[key]; exists && cur.inst == entry.inst {
-				cur.lastPing = time.Now()
-				m.cache[key] = cur
-			}
-			m.mu.Unlock()
+			m.touch(key, entry.inst, true)
 			return entry.inst, nil
 		}
 
@@ -90,17 +133,40 @@ func (m *ConnectionManager) Get(config *connection.ConnectionConfig, forcePing b
 	}
 
 	m.logInfo("获取数据库连接", "summary", FormatConnSummary(config), "key", shortKey)
-	dbInst, err := NewDatabase(config.Type)
-	if err != nil {
-		m.logError("创建数据库驱动实例失败", "type", config.Type, "key", shortKey, "error", err)
-		return nil, err
+	var dbInst Database
+	var factoryErr error
+	retries, err := Retry(context.Background(), CurrentRetryPolicy(), func() error {
+		inst, err := NewDatabaseForConfig(config)
+		if err != nil {
+			factoryErr = err
+			return nil
+		}
+		if err := inst.Connect(config); err != nil {
+			return err
+		}
+		dbInst = inst
+		return nil
+	})
+	if factoryErr != nil {
+		m.logError("创建数据库驱动实例失败", "type", config.Type, "key", shortKey, "error", factoryErr)
+		return nil, factoryErr
 	}
-
-	if err = dbInst.Connect(config); err != nil {
+	if err != nil {
 		wrapped := wrapConnectError(config, err)
-		m.logError("建立数据库连接失败", "summary", FormatConnSummary(config), "key", shortKey, "error", wrapped)
+		m.logError("建立数据库连接失败", "summary", FormatConnSummary(config), "key", shortKey, "retries", retries, "error", wrapped)
 		return nil, wrapped
 	}
+	if retries > 0 {
+		m.logInfo("重试后建立数据库连接成功", "summary", FormatConnSummary(config), "key", shortKey, "retries", retries)
+	}
+	if schema != "" {
+		if switcher, ok := dbInst.(SchemaSwitcher); ok {
+			if err := switcher.SetActiveSchema(schema); err != nil {
+				m.logError("恢复活动 schema 失败", "schema", schema, "key", shortKey, "error", err)
+				schema = ""
+			}
+		}
+	}
 
 	now := time.Now()
 	m.mu.Lock()
@@ -109,13 +175,166 @@ func (m *ConnectionManager) Get(config *connection.ConnectionConfig, forcePing b
 		_ = dbInst.Close()
 		return existing.inst, nil
 	}
-	m.cache[key] = cacheEntry{inst: dbInst, lastPing: now}
+	m.cache[key] = cacheEntry{inst: dbInst, lastPing: now, lastUsed: now, server: serverKey(config), schema: schema}
+	m.trackHealth(key, config)
 	m.mu.Unlock()
 
 	m.logInfo("数据库连接成功并写入缓存", "summary", FormatConnSummary(config), "key", shortKey)
 	return dbInst, nil
 }
 
+// SetActiveSchema 设置缓存连接的活动 schema，连接重建后自动恢复；schema 为空时恢复默认。
+func (m *ConnectionManager) SetActiveSchema(config *connection.ConnectionConfig, schema string) error {
+	inst, err := m.Get(config, false)
+	if err != nil {
+		return err
+	}
+	switcher, ok := inst.(SchemaSwitcher)
+	if !ok {
+		return fmt.Errorf("当前数据库类型不支持切换 schema: %s", config.Type)
+	}
+	if err := switcher.SetActiveSchema(schema); err != nil {
+		return err
+	}
+
+	key := m.activeKey(config)
+	m.mu.Lock()
+	if cur, exists := m.cache[key]; exists && cur.inst == inst {
+		cur.schema = switcher.ActiveSchema()
+		m.cache[key] = cur
+	}
+	m.mu.Unlock()
+	m.logInfo("切换活动 schema", "summary", FormatConnSummary(config), "schema", schema, "key", shortCacheKey(key))
+	return nil
+}
+
+// ActiveSchema 返回缓存连接的活动 schema，未设置或连接未缓存时为空。
+func (m *ConnectionManager) ActiveSchema(config *connection.ConnectionConfig) string {
+	key := m.activeKey(config)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cache[key].schema
+}
+
+// touch 更新缓存连接的最近使用时间，pinged 为 true 时同时更新探活时间。
+func (m *ConnectionManager) touch(key string, inst Database, pinged bool) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, exists := m.cache[key]; exists && cur.inst == inst {
+		cur.lastUsed = now
+		if pinged {
+			cur.lastPing = now
+		}
+		m.cache[key] = cur
+	}
+}
+
+// SetIdleTimeout 设置空闲回收时长，d<=0 时不回收。
+func (m *ConnectionManager) SetIdleTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	m.mu.Lock()
+	m.idleTimeout = d
+	m.mu.Unlock()
+}
+
+// IdleTimeout 返回当前的空闲回收时长，0 表示不回收。
+func (m *ConnectionManager) IdleTimeout() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.idleTimeout
+}
+
+// StartReaper 启动后台协程定期回收空闲连接，ctx 结束时退出。
+func (m *ConnectionManager) StartReaper(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(reapInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.ReapIdle(now)
+			}
+		}
+	}()
+}
+
+// ReapIdle 关闭截至 now 空闲超过回收时长的连接（仍有查询在执行的除外），返回关闭的数量。
+func (m *ConnectionManager) ReapIdle(now time.Time) int {
+	m.mu.Lock()
+	idleTimeout := m.idleTimeout
+	if idleTimeout <= 0 {
+		m.mu.Unlock()
+		return 0
+	}
+	var idle []cacheEntry
+	for key, entry := range m.cache {
+		if now.Sub(entry.lastUsed) < idleTimeout {
+			continue
+		}
+		if busy, ok := entry.inst.(busyChecker); ok && busy.Busy() {
+			continue
+		}
+		idle = append(idle, entry)
+		delete(m.cache, key)
+	}
+	m.mu.Unlock()
+
+	for _, entry := range idle {
+		if err := entry.inst.Close(); err != nil {
+			m.logError("关闭空闲连接失败", "server", entry.server, "error", err)
+		}
+	}
+	if len(idle) > 0 {
+		m.logInfo("已回收空闲数据库连接", "count", len(idle), "idleTimeout", idleTimeout.String())
+	}
+	return len(idle)
+}
+
+// Disconnect 关闭与 config 指向同一服务器和用户的全部缓存连接（不区分库），返回关闭的数量。
+func (m *ConnectionManager) Disconnect(config *connection.ConnectionConfig) (int, error) {
+	servers := make(map[string]bool)
+	for _, hc := range hostConfigs(config.Normalize()) {
+		servers[serverKey(hc)] = true
+	}
+	m.mu.Lock()
+	var matched []cacheEntry
+	for key, entry := range m.cache {
+		if servers[entry.server] {
+			matched = append(matched, entry)
+			delete(m.cache, key)
+		}
+	}
+	for key, rec := range m.health {
+		if servers[serverKey(&rec.config)] {
+			delete(m.health, key)
+		}
+	}
+	delete(m.active, serverKey(config))
+	m.mu.Unlock()
+
+	var closeErr error
+	for _, entry := range matched {
+		if err := entry.inst.Close(); err != nil && closeErr == nil {
+			closeErr = err
+			m.logError("断开数据库连接失败", "summary", FormatConnSummary(config), "error", err)
+		}
+	}
+	m.logInfo("断开数据库连接", "summary", FormatConnSummary(config), "count", len(matched))
+	return len(matched), closeErr
+}
+
+// Count 返回当前缓存的连接数量。
+func (m *ConnectionManager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.cache)
+}
+
 // CloseAll 关闭并清空所有缓存连接。
 func (m *ConnectionManager) CloseAll() error {
 	m.mu.Lock()
@@ -133,6 +352,7 @@ func (m *ConnectionManager) CloseAll() error {
 		}
 		delete(m.cache, key)
 	}
+	clear(m.health)
 	return closeErr
 }
 
@@ -162,26 +382,23 @@ func (m *ConnectionManager) logError(msg string, args ...any) {
 	}
 }
 
+// cacheKey 返回规范化配置的哈希，不修改传入的配置。
 func cacheKey(config *connection.ConnectionConfig) string {
-	normalized := normalizedConfig(config)
-	b, _ := json.Marshal(normalized)
+	b, _ := json.Marshal(config.Normalize())
 	sum := sha256.Sum256(b)
 	return hex.EncodeToString(sum[:])
 }
 
-func normalizedConfig(config *connection.ConnectionConfig) *connection.ConnectionConfig {
-	runConfig := *config
-
-	if !runConfig.UseSSH {
-		runConfig.SSH = &connection.SSHConfig{}
-	}
-
-	// 保持与历史行为一致，避免同一连接生成不同缓存 key。
-	if (runConfig.Type == "postgres" || runConfig.Type == connection.ConnectionTypePostgreSQL) && runConfig.Database == "" {
-		runConfig.Database = "postgres"
-	}
+// ServerKey 返回忽略库名的连接标识，同一服务器与账号的配置得到相同的值，不含明文凭据。
+func ServerKey(config *connection.ConnectionConfig) string {
+	return serverKey(config)
+}
 
-	return &runConfig
+// serverKey 返回忽略库名的连接标识，用于按服务器断开连接。
+func serverKey(config *connection.ConnectionConfig) string {
+	runConfig := config.Clone()
+	runConfig.Database = ""
+	return cacheKey(runConfig)
 }
 
 func shortCacheKey(key string) string {
@@ -0,0 +1,91 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// fakeDatabase 只实现回收与断开用到的方法。
+type fakeDatabase struct {
+	Database
+	closed bool
+	busy   bool
+}
+
+func (f *fakeDatabase) Close() error { f.closed = true; return nil }
+func (f *fakeDatabase) Busy() bool   { return f.busy }
+
+// addFakeEntry 直接写入缓存，模拟已建立的连接。
+func addFakeEntry(m *ConnectionManager, config *connection.ConnectionConfig, lastUsed time.Time) *fakeDatabase {
+	inst := &fakeDatabase{}
+	m.cache[cacheKey(config)] = cacheEntry{inst: inst, lastPing: lastUsed, lastUsed: lastUsed, server: serverKey(config)}
+	return inst
+}
+
+func TestConnectionManagerReapIdle(t *testing.T) {
+	m := NewConnectionManager(nil)
+	now := time.Now()
+	base := connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "h", Port: 3306, User: "u"}
+
+	idleCfg, activeCfg, busyCfg := base, base, base
+	idleCfg.Database, activeCfg.Database, busyCfg.Database = "idle", "active", "busy"
+	idle := addFakeEntry(m, &idleCfg, now.Add(-2*DefaultIdleTimeout))
+	active := addFakeEntry(m, &activeCfg, now.Add(-time.Minute))
+	busy := addFakeEntry(m, &busyCfg, now.Add(-2*DefaultIdleTimeout))
+	busy.busy = true
+
+	if n := m.ReapIdle(now); n != 1 {
+		t.Fatalf("应回收 1 个连接，实际 %d", n)
+	}
+	if !idle.closed || active.closed || busy.closed {
+		t.Fatalf("回收结果不符: idle=%v active=%v busy=%v", idle.closed, active.closed, busy.closed)
+	}
+	if m.Count() != 2 {
+		t.Fatalf("剩余连接数应为 2，实际 %d", m.Count())
+	}
+
+	m.SetIdleTimeout(0)
+	busy.busy = false
+	if n := m.ReapIdle(now.Add(24 * time.Hour)); n != 0 {
+		t.Fatalf("关闭回收后不应回收连接，实际 %d", n)
+	}
+}
+
+func TestConnectionManagerDisconnect(t *testing.T) {
+	m := NewConnectionManager(nil)
+	now := time.Now()
+	cfgA := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "a", Port: 3306, User: "u", Database: "db1"}
+	cfgA2 := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "a", Port: 3306, User: "u", Database: "db2"}
+	cfgB := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "b", Port: 3306, User: "u"}
+	a1 := addFakeEntry(m, cfgA, now)
+	a2 := addFakeEntry(m, cfgA2, now)
+	b := addFakeEntry(m, cfgB, now)
+
+	n, err := m.Disconnect(&connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "a", Port: 3306, User: "u"})
+	if err != nil || n != 2 {
+		t.Fatalf("Disconnect 应关闭 2 个连接，实际 %d, err=%v", n, err)
+	}
+	if !a1.closed || !a2.closed || b.closed {
+		t.Fatalf("断开结果不符: a1=%v a2=%v b=%v", a1.closed, a2.closed, b.closed)
+	}
+
+	if err := m.CloseAll(); err != nil || !b.closed || m.Count() != 0 {
+		t.Fatalf("CloseAll 后应无缓存连接: count=%d err=%v", m.Count(), err)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+)
+
+// MaxSyncPreviewStatements 是数据同步预览最多渲染的语句条数，超出部分只计数。
+const MaxSyncPreviewStatements = 1000
+
+// SyncOptions 控制数据同步生成哪些类型的变更。
+type SyncOptions struct {
+	SkipInserts bool
+	SkipUpdates bool
+	SkipDeletes bool
+}
+
+// BuildSyncChangeSet 将以目标表为左侧、源表为右侧的比较结果转换为使目标表与源表一致的变更集。
+// 仅右侧存在的行插入目标表，仅左侧存在的行按键删除，内容不同的行按键更新差异列；
+// columns 为参与同步的列，插入时只写入这些列。result 不能被截断，否则变更集不完整。
+func BuildSyncChangeSet(result *resultdiff.Result, columns []string, opts SyncOptions) (*connection.ChangeSet, error) {
+	if result.Truncated {
+		return nil, fmt.Errorf("比较结果不完整，无法生成同步变更")
+	}
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{},
+		Updates: []connection.UpdateRow{},
+		Deletes: []map[string]interface{}{},
+	}
+	if !opts.SkipDeletes {
+		for _, row := range result.Removed {
+			key := make(map[string]interface{}, len(result.KeyColumns))
+			for _, col := range result.KeyColumns {
+				key[col] = row[col]
+			}
+			changes.Deletes = append(changes.Deletes, key)
+		}
+	}
+	if !opts.SkipUpdates {
+		for _, diff := range result.Changed {
+			values := make(map[string]interface{}, len(diff.Cells))
+			for _, cell := range diff.Cells {
+				values[cell.Column] = cell.Right
+			}
+			changes.Updates = append(changes.Updates, connection.UpdateRow{Keys: diff.Key, Values: values})
+		}
+	}
+	if !opts.SkipInserts {
+		for _, row := range result.Added {
+			insert := make(map[string]interface{}, len(columns))
+			for _, col := range columns {
+				insert[col] = row[col]
+			}
+			changes.Inserts = append(changes.Inserts, insert)
+		}
+	}
+	return changes, nil
+}
+
+// RenderChangeSetSQL 按删除、更新、插入的顺序把变更集渲染为内联取值的 SQL 文本，最多 limit 条（<=0 表示不限制）。
+// 返回的语句仅用于预览与导出，执行时应使用参数化语句；第二个返回值为未渲染的语句条数。
+func RenderChangeSetSQL(dbType connection.ConnectionType, tableName string, changes *connection.ChangeSet, limit int) ([]string, int) {
+	d := dialectFor(dbType)
+	table := d.quoteTable(tableName)
+	where := func(match map[string]interface{}) string {
+		conds := make([]string, 0, len(match))
+		for _, col := range sortedKeys(match) {
+			if match[col] == nil {
+				conds = append(conds, d.quoteIdent(col)+" IS NULL")
+				continue
+			}
+			conds = append(conds, d.quoteIdent(col)+" = "+SQLLiteral(dbType, match[col]))
+		}
+		return strings.Join(conds, " AND ")
+	}
+
+	total := len(changes.Deletes) + len(changes.Updates) + len(changes.Inserts)
+	stmts := make([]string, 0, min(total, max(limit, 0)))
+	full := func() bool { return limit > 0 && len(stmts) >= limit }
+	for _, match := range changes.Deletes {
+		if full() {
+			break
+		}
+		stmts = append(stmts, fmt.Sprintf("DELETE FROM %s WHERE %s;", table, where(match)))
+	}
+	for _, update := range changes.Updates {
+		if full() {
+			break
+		}
+		sets := make([]string, 0, len(update.Values))
+		for _, col := range sortedKeys(update.Values) {
+			sets = append(sets, d.quoteIdent(col)+" = "+SQLLiteral(dbType, update.Values[col]))
+		}
+		stmts = append(stmts, fmt.Sprintf("UPDATE %s SET %s WHERE %s;", table, strings.Join(sets, ", "), where(update.Keys)))
+	}
+	for _, row := range changes.Inserts {
+		if full() {
+			break
+		}
+		cols := sortedKeys(row)
+		quoted := make([]string, len(cols))
+		values := make([]string, len(cols))
+		for i, col := range cols {
+			quoted[i] = d.quoteIdent(col)
+			values[i] = SQLLiteral(dbType, row[col])
+		}
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", table, strings.Join(quoted, ", "), strings.Join(values, ", ")))
+	}
+	return stmts, total - len(stmts)
+}
+
+// SQLLiteral 按数据库类型把取值渲染为 SQL 字面量。
+// 字符串中的单引号成对转义，MySQL 还需转义反斜杠；非 UTF-8 的字节串按十六进制字面量输出。
+func SQLLiteral(dbType connection.ConnectionType, v interface{}) string {
+	mysqlLike := dbType == "" || dbType == connection.ConnectionTypeMySQL || dbType == connection.ConnectionTypeMariaDB || dbType == connection.ConnectionTypeTDengine
+	switch t := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if IsPostgresDialect(dbType) {
+			return strconv.FormatBool(t)
+		}
+		if t {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(t)
+	case float32:
+		return strconv.FormatFloat(float64(t), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	case time.Time:
+		return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+	case []byte:
+		if utf8.Valid(t) {
+			return stringLiteral(dbType, mysqlLike, string(t))
+		}
+		switch {
+		case IsPostgresDialect(dbType):
+			return `'\x` + hex.EncodeToString(t) + `'::bytea`
+		case dbType == connection.ConnectionTypeSQLServer:
+			return "0x" + hex.EncodeToString(t)
+		default:
+			return "X'" + hex.EncodeToString(t) + "'"
+		}
+	case string:
+		return stringLiteral(dbType, mysqlLike, t)
+	default:
+		return stringLiteral(dbType, mysqlLike, fmt.Sprint(t))
+	}
+}
+
+// stringLiteral 渲染字符串字面量；SQL Server 使用 N 前缀以保留 Unicode 字符。
+func stringLiteral(dbType connection.ConnectionType, mysqlLike bool, s string) string {
+	if mysqlLike {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	s = "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	if dbType == connection.ConnectionTypeSQLServer {
+		return "N" + s
+	}
+	return s
+}
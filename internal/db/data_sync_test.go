@@ -0,0 +1,128 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+)
+
+func syncDiff(t *testing.T) *resultdiff.Result {
+	t.Helper()
+	target := resultdiff.Input{
+		Columns: []string{"id", "name", "note"},
+		Rows: []map[string]interface{}{
+			{"id": int64(1), "name": "a", "note": "x"},
+			{"id": int64(2), "name": "b", "note": nil},
+			{"id": int64(3), "name": "gone", "note": nil},
+		},
+	}
+	source := resultdiff.Input{
+		Columns: []string{"id", "name", "note"},
+		Rows: []map[string]interface{}{
+			{"id": int64(1), "name": "a", "note": "x"},
+			{"id": int64(2), "name": "b2", "note": "y"},
+			{"id": int64(4), "name": "new", "note": nil},
+		},
+	}
+	result, err := resultdiff.Diff(target, source, []string{"id"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestBuildSyncChangeSet(t *testing.T) {
+	changes, err := BuildSyncChangeSet(syncDiff(t), []string{"id", "name"}, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{{"id": int64(4), "name": "new"}},
+		Updates: []connection.UpdateRow{{
+			Keys:   map[string]interface{}{"id": int64(2)},
+			Values: map[string]interface{}{"name": "b2", "note": "y"},
+		}},
+		Deletes: []map[string]interface{}{{"id": int64(3)}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("变更集不符: %+v", changes)
+	}
+
+	changes, _ = BuildSyncChangeSet(syncDiff(t), []string{"id", "name"}, SyncOptions{SkipInserts: true, SkipDeletes: true})
+	if len(changes.Inserts) != 0 || len(changes.Deletes) != 0 || len(changes.Updates) != 1 {
+		t.Errorf("跳过插入与删除后变更集不符: %+v", changes)
+	}
+
+	truncated := syncDiff(t)
+	truncated.Truncated = true
+	if _, err := BuildSyncChangeSet(truncated, nil, SyncOptions{}); err == nil {
+		t.Error("比较结果被截断时应返回错误")
+	}
+}
+
+func TestRenderChangeSetSQL(t *testing.T) {
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{{"id": int64(4), "name": "O'Brien"}},
+		Updates: []connection.UpdateRow{{
+			Keys:   map[string]interface{}{"id": int64(2), "tenant": nil},
+			Values: map[string]interface{}{"name": "b2", "active": true},
+		}},
+		Deletes: []map[string]interface{}{{"id": int64(3)}},
+	}
+	stmts, more := RenderChangeSetSQL(connection.ConnectionTypePostgreSQL, "app.users", changes, 0)
+	want := []string{
+		`DELETE FROM "app"."users" WHERE "id" = 3;`,
+		`UPDATE "app"."users" SET "active" = true, "name" = 'b2' WHERE "id" = 2 AND "tenant" IS NULL;`,
+		`INSERT INTO "app"."users" ("id", "name") VALUES (4, 'O''Brien');`,
+	}
+	if more != 0 || !reflect.DeepEqual(stmts, want) {
+		t.Errorf("PostgreSQL 语句不符 (%d):\n%v", more, stmts)
+	}
+
+	stmts, more = RenderChangeSetSQL(connection.ConnectionTypeMySQL, "users", changes, 2)
+	if len(stmts) != 2 || more != 1 {
+		t.Fatalf("限制条数后应渲染 2 条、剩余 1 条，实际 %d/%d", len(stmts), more)
+	}
+	if stmts[1] != "UPDATE `users` SET `active` = 1, `name` = 'b2' WHERE `id` = 2 AND `tenant` IS NULL;" {
+		t.Errorf("MySQL 更新语句不符: %s", stmts[1])
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	tests := []struct {
+		dbType connection.ConnectionType
+		value  interface{}
+		want   string
+	}{
+		{connection.ConnectionTypeMySQL, nil, "NULL"},
+		{connection.ConnectionTypeMySQL, 1.5, "1.5"},
+		{connection.ConnectionTypeMySQL, `a\'b`, `'a\\''b'`},
+		{connection.ConnectionTypePostgreSQL, `a\b`, `'a\b'`},
+		{connection.ConnectionTypeSQLServer, "名", "N'名'"},
+		{connection.ConnectionTypeMySQL, []byte("text"), "'text'"},
+		{connection.ConnectionTypeMySQL, []byte{0xff, 0x00}, "X'ff00'"},
+		{connection.ConnectionTypePostgreSQL, []byte{0xff}, `'\xff'::bytea`},
+		{connection.ConnectionTypeSQLServer, []byte{0xff}, "0xff"},
+	}
+	for _, tt := range tests {
+		if got := SQLLiteral(tt.dbType, tt.value); got != tt.want {
+			t.Errorf("SQLLiteral(%s, %#v) = %s, want %s", tt.dbType, tt.value, got, tt.want)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
@@ -27,14 +28,14 @@ type Database interface {
 	Ping() error
 	Query(query string, args ...any) ([]map[string]interface{}, []string, error)
 	Exec(query string, args ...any) (int64, error)
-	GetDatabases() ([]string, error)
-	GetTables(dbName string) ([]string, error)
-	GetCreateStatement(dbName, tableName string) (string, error)
-	GetColumns(dbName, tableName string) ([]*connection.ColumnDefinition, error)
-	GetAllColumns(dbName string) ([]*connection.ColumnDefinitionWithTable, error)
-	GetIndexes(dbName, tableName string) ([]*connection.IndexDefinition, error)
-	GetForeignKeys(dbName, tableName string) ([]*connection.ForeignKeyDefinition, error)
-	GetTriggers(dbName, tableName string) ([]*connection.TriggerDefinition, error)
+	GetDatabases(ctx context.Context) ([]string, error)
+	GetTables(ctx context.Context, dbName string) ([]string, error)
+	GetCreateStatement(ctx context.Context, dbName, tableName string) (string, error)
+	GetColumns(ctx context.Context, dbName, tableName string) ([]*connection.ColumnDefinition, error)
+	GetAllColumns(ctx context.Context, dbName string) ([]*connection.ColumnDefinitionWithTable, error)
+	GetIndexes(ctx context.Context, dbName, tableName string) ([]*connection.IndexDefinition, error)
+	GetForeignKeys(ctx context.Context, dbName, tableName string) ([]*connection.ForeignKeyDefinition, error)
+	GetTriggers(ctx context.Context, dbName, tableName string) ([]*connection.TriggerDefinition, error)
 }
 
 // BatchApplier 定义批量数据变更能力。
@@ -42,6 +43,28 @@ type BatchApplier interface {
 	ApplyChanges(tableName string, changes *connection.ChangeSet) error
 }
 
+// ChangeImages 是应用变更的事务内读取的被更新、删除行的原值，值为驱动返回的原始类型，可直接作为参数写回。
+type ChangeImages struct {
+	Updates []map[string]interface{} // 依次对应 ChangeSet.Updates
+	Deletes []map[string]interface{} // 依次对应 ChangeSet.Deletes
+	Err     error                    // 未能读取全部原值的原因，非空时原值不完整
+}
+
+// UndoApplier 定义应用变更并在同一事务内读取原行映像的能力，partial 为 true 时按保存点逐个提交。
+type UndoApplier interface {
+	ApplyChangesWithImages(ctx context.Context, tableName string, changes *connection.ChangeSet, partial bool) (*connection.ApplyReport, *ChangeImages, error)
+}
+
+// PartialApplier 定义以保存点逐个应用变更、提交成功项并报告失败项的能力。
+type PartialApplier interface {
+	ApplyChangesPartial(ctx context.Context, tableName string, changes *connection.ChangeSet) (*connection.ApplyReport, error)
+}
+
+// BatchInserter 定义事务内批量插入能力。
+type BatchInserter interface {
+	InsertRows(ctx context.Context, tableName string, columns []string, rows [][]any, opts *connection.ImportOptions, progress InsertProgressFunc) (*connection.ImportReport, error)
+}
+
 // DatabaseFactory 负责根据数据库类型创建驱动实例。
 type DatabaseFactory struct{}
 
@@ -56,9 +79,9 @@ func (f *DatabaseFactory) Create(dbType connection.ConnectionType) (Database, er
 	case connection.ConnectionTypeMySQL:
 		return &MySQLDB{}, nil
 	case connection.ConnectionTypePostgreSQL:
-		return nil, fmt.Errorf("暂不支持的数据库类型: %s", dbType)
+		return NewPostgresDB(), nil
 	case connection.ConnectionTypeSQLite:
-		return nil, fmt.Errorf("暂不支持的数据库类型: %s", dbType)
+		return NewSQLiteDB(), nil
 	default:
 		// Default to MySQL for backward compatibility if empty
 		if dbType == "" {
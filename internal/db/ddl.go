@@ -0,0 +1,173 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// DDLBuilder 根据结构化定义生成方言相关的 DDL 语句。
+//
+// schema 为空时生成不带 schema 前缀的表名；返回的语句需按顺序执行。
+type DDLBuilder interface {
+	CreateTable(schema string, def *connection.TableDefinition) ([]string, error)
+	AlterTable(schema string, alt *connection.TableAlteration) ([]string, error)
+	DropTable(schema, table string, ifExists bool) ([]string, error)
+	AddIndex(schema, table string, index []*connection.IndexDefinition) ([]string, error)
+	DropIndex(schema, table, indexName string) ([]string, error)
+	AddPartition(schema, table string, spec *connection.PartitionSpec) ([]string, error)
+	DetachPartition(schema, table, partition, target string) ([]string, error)
+}
+
+// NewDDLBuilder 返回数据库类型对应的 DDL 生成器。
+func NewDDLBuilder(dbType connection.ConnectionType) (DDLBuilder, error) {
+	switch dbType {
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypeMariaDB, "":
+		return mysqlDDL{}, nil
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		return postgresDDL{}, nil
+	default:
+		return nil, fmt.Errorf("当前数据库类型不支持结构化 DDL: %s", dbType)
+	}
+}
+
+// DDLSchema 返回生成 DDL 时使用的 schema 前缀：MySQL 的 schema 即数据库名，
+// PostgreSQL 类数据库的 dbName 是数据库而非 schema，留空以使用连接当前的 search_path。
+func DDLSchema(dbType connection.ConnectionType, dbName string) string {
+	switch dbType {
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypeMariaDB, "":
+		return dbName
+	default:
+		return ""
+	}
+}
+
+// columnTypePattern 限定列类型的写法：类型名、可选的长度/精度或枚举值列表，以及常见修饰词。
+var columnTypePattern = regexp.MustCompile(`(?i)^[a-z][a-z0-9_]*( [a-z][a-z0-9_]*)*` +
+	`(\(\s*\d+\s*(,\s*\d+\s*)?\)|\(\s*'(?:[^'\\]|''|\\.)*'(\s*,\s*'(?:[^'\\]|''|\\.)*')*\s*\))?` +
+	`( unsigned| zerofill| with time zone| without time zone)*(\[\])*$`)
+
+// validateColumn 校验列定义的必填项与类型写法，防止类型中夹带其他语句。
+func validateColumn(col *connection.ColumnDefinition) error {
+	if col == nil || strings.TrimSpace(col.Name) == "" {
+		return fmt.Errorf("列名不能为空")
+	}
+	if !columnTypePattern.MatchString(strings.TrimSpace(col.Type)) {
+		return fmt.Errorf("列 %s 的类型无效: %q", col.Name, col.Type)
+	}
+	return nil
+}
+
+// isAutoIncrement 判断列是否为自增列。
+func isAutoIncrement(col *connection.ColumnDefinition) bool {
+	return strings.Contains(strings.ToLower(col.Extra), "auto_increment")
+}
+
+// isNotNull 判断列是否声明为非空。
+func isNotNull(col *connection.ColumnDefinition) bool {
+	return strings.EqualFold(strings.TrimSpace(col.Nullable), "NO")
+}
+
+// defaultExprPattern 可直接作为表达式输出的默认值：数字、NULL、布尔与当前时间函数。
+var defaultExprPattern = regexp.MustCompile(`(?i)^(-?\d+(\.\d+)?|null|true|false|current_timestamp(\(\d?\))?|now\(\)|current_date|current_time|localtimestamp)$`)
+
+// defaultLiteral 将默认值转换为 SQL 字面量：数字与时间函数原样输出，其余按字符串引用。
+func defaultLiteral(value string, quote func(string) string) string {
+	if defaultExprPattern.MatchString(strings.TrimSpace(value)) {
+		return strings.TrimSpace(value)
+	}
+	return quote(value)
+}
+
+// primaryKeyColumns 返回表定义的主键列。
+func primaryKeyColumns(def *connection.TableDefinition) []string {
+	if len(def.PrimaryKey) > 0 {
+		return def.PrimaryKey
+	}
+	var pk []string
+	for _, col := range def.Columns {
+		if col.Key == "PRI" {
+			pk = append(pk, col.Name)
+		}
+	}
+	return pk
+}
+
+// indexSpec 是按名称合并后的索引。
+type indexSpec struct {
+	name    string
+	columns []string
+	unique  bool
+	using   string
+}
+
+// groupIndexes 将按列展开的索引定义合并为索引，保持首次出现的顺序。
+func groupIndexes(defs []*connection.IndexDefinition) ([]*indexSpec, error) {
+	byName := make(map[string][]*connection.IndexDefinition)
+	var order []string
+	for _, d := range defs {
+		if d == nil || strings.TrimSpace(d.Name) == "" || strings.TrimSpace(d.ColumnName) == "" {
+			return nil, fmt.Errorf("索引名与列名不能为空")
+		}
+		if _, ok := byName[d.Name]; !ok {
+			order = append(order, d.Name)
+		}
+		byName[d.Name] = append(byName[d.Name], d)
+	}
+
+	specs := make([]*indexSpec, 0, len(order))
+	for _, name := range order {
+		parts := byName[name]
+		sort.SliceStable(parts, func(i, j int) bool { return parts[i].SeqInIndex < parts[j].SeqInIndex })
+		spec := &indexSpec{name: name, unique: parts[0].NonUnique == 0, using: strings.ToUpper(strings.TrimSpace(parts[0].IndexType))}
+		for _, p := range parts {
+			spec.columns = append(spec.columns, p.ColumnName)
+		}
+		switch spec.using {
+		case "", "BTREE", "HASH", "GIN", "GIST", "BRIN", "SPGIST", "FULLTEXT", "SPATIAL":
+		default:
+			return nil, fmt.Errorf("索引 %s 的类型无效: %s", name, spec.using)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// quoteList 引用标识符列表并以逗号连接。
+func quoteList(names []string, quote func(string) string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quote(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// qualified 返回带 schema 前缀的已引用表名。
+func qualified(schema, table string, quote func(string) string) string {
+	if schema == "" {
+		return quote(table)
+	}
+	return quote(schema) + "." + quote(table)
+}
+
+// quotePgString 使用单引号引用 PostgreSQL 字符串字面量。
+func quotePgString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// mysqlDDL 生成 MySQL/MariaDB 的 DDL。
+type mysqlDDL struct{}
+
+// CreateTable 生成 CREATE TABLE，主键与二级索引写在表定义内。
+func (mysqlDDL) CreateTable(schema string, def *connection.TableDefinition) ([]string, error) {
+	if def == nil || strings.TrimSpace(def.Name) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+	if len(def.Columns) == 0 {
+		return nil, fmt.Errorf("表至少需要一列")
+	}
+
+	var lines []string
+	for _, col := range def.Columns {
+		line, err := mysqlColumnSQL(col)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	if pk := primaryKeyColumns(def); len(pk) > 0 {
+		lines = append(lines, "PRIMARY KEY ("+quoteList(pk, quoteMySQLIdent)+")")
+	}
+	indexes, err := groupIndexes(def.Indexes)
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		lines = append(lines, mysqlIndexPrefix(idx)+quoteMySQLIdent(idx.name)+" ("+quoteList(idx.columns, quoteMySQLIdent)+")"+mysqlIndexUsing(idx))
+	}
+
+	stmt := "CREATE TABLE " + qualified(schema, def.Name, quoteMySQLIdent) + " (\n  " + strings.Join(lines, ",\n  ") + "\n)"
+	if def.Comment != "" {
+		stmt += " COMMENT=" + quoteMySQLString(def.Comment)
+	}
+	return []string{stmt}, nil
+}
+
+// AlterTable 将全部变更合并为一条 ALTER TABLE。
+func (mysqlDDL) AlterTable(schema string, alt *connection.TableAlteration) ([]string, error) {
+	if alt == nil || strings.TrimSpace(alt.Table) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+
+	var specs []string
+	for _, name := range alt.DropColumns {
+		specs = append(specs, "DROP COLUMN "+quoteMySQLIdent(name))
+	}
+	for _, r := range alt.RenameColumns {
+		if r.From == "" || r.To == "" {
+			return nil, fmt.Errorf("重命名列的原列名与新列名不能为空")
+		}
+		specs = append(specs, "RENAME COLUMN "+quoteMySQLIdent(r.From)+" TO "+quoteMySQLIdent(r.To))
+	}
+	for _, col := range alt.ModifyColumns {
+		line, err := mysqlColumnSQL(col)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, "MODIFY COLUMN "+line)
+	}
+	for _, col := range alt.AddColumns {
+		line, err := mysqlColumnSQL(col)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, "ADD COLUMN "+line)
+	}
+	if alt.Comment != nil {
+		specs = append(specs, "COMMENT = "+quoteMySQLString(*alt.Comment))
+	}
+	if alt.RenameTo != "" {
+		specs = append(specs, "RENAME TO "+qualified(schema, alt.RenameTo, quoteMySQLIdent))
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("没有需要执行的表结构变更")
+	}
+	return []string{"ALTER TABLE " + qualified(schema, alt.Table, quoteMySQLIdent) + "\n  " + strings.Join(specs, ",\n  ")}, nil
+}
+
+// DropTable 生成 DROP TABLE。
+func (mysqlDDL) DropTable(schema, table string, ifExists bool) ([]string, error) {
+	if strings.TrimSpace(table) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+	stmt := "DROP TABLE "
+	if ifExists {
+		stmt += "IF EXISTS "
+	}
+	return []string{stmt + qualified(schema, table, quoteMySQLIdent)}, nil
+}
+
+// AddIndex 生成 CREATE INDEX；索引名为 PRIMARY 时添加主键。
+func (mysqlDDL) AddIndex(schema, table string, index []*connection.IndexDefinition) ([]string, error) {
+	specs, err := groupIndexes(index)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) != 1 {
+		return nil, fmt.Errorf("一次只能添加一个索引")
+	}
+	idx := specs[0]
+	target := qualified(schema, table, quoteMySQLIdent)
+	if strings.EqualFold(idx.name, "PRIMARY") {
+		return []string{"ALTER TABLE " + target + " ADD PRIMARY KEY (" + quoteList(idx.columns, quoteMySQLIdent) + ")"}, nil
+	}
+	prefix := "CREATE INDEX "
+	switch {
+	case idx.using == "FULLTEXT":
+		prefix = "CREATE FULLTEXT INDEX "
+	case idx.using == "SPATIAL":
+		prefix = "CREATE SPATIAL INDEX "
+	case idx.unique:
+		prefix = "CREATE UNIQUE INDEX "
+	}
+	return []string{prefix + quoteMySQLIdent(idx.name) + " ON " + target + " (" + quoteList(idx.columns, quoteMySQLIdent) + ")" + mysqlIndexUsing(idx)}, nil
+}
+
+// DropIndex 生成 DROP INDEX；索引名为 PRIMARY 时删除主键。
+func (mysqlDDL) DropIndex(schema, table, indexName string) ([]string, error) {
+	if strings.TrimSpace(indexName) == "" {
+		return nil, fmt.Errorf("索引名不能为空")
+	}
+	target := qualified(schema, table, quoteMySQLIdent)
+	if strings.EqualFold(indexName, "PRIMARY") {
+		return []string{"ALTER TABLE " + target + " DROP PRIMARY KEY"}, nil
+	}
+	return []string{"DROP INDEX " + quoteMySQLIdent(indexName) + " ON " + target}, nil
+}
+
+// mysqlColumnSQL 生成列定义子句。
+func mysqlColumnSQL(col *connection.ColumnDefinition) (string, error) {
+	if err := validateColumn(col); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString(quoteMySQLIdent(col.Name))
+	sb.WriteString(" ")
+	sb.WriteString(strings.TrimSpace(col.Type))
+	if isNotNull(col) {
+		sb.WriteString(" NOT NULL")
+	} else {
+		sb.WriteString(" NULL")
+	}
+	if col.Default != nil {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(defaultLiteral(*col.Default, quoteMySQLString))
+	}
+	if isAutoIncrement(col) {
+		sb.WriteString(" AUTO_INCREMENT")
+	}
+	if strings.Contains(strings.ToLower(col.Extra), "on update current_timestamp") {
+		sb.WriteString(" ON UPDATE CURRENT_TIMESTAMP")
+	}
+	if col.Comment != "" {
+		sb.WriteString(" COMMENT ")
+		sb.WriteString(quoteMySQLString(col.Comment))
+	}
+	return sb.String(), nil
+}
+
+// mysqlIndexPrefix 返回表定义内索引子句的前缀。
+func mysqlIndexPrefix(idx *indexSpec) string {
+	switch {
+	case idx.using == "FULLTEXT":
+		return "FULLTEXT KEY "
+	case idx.using == "SPATIAL":
+		return "SPATIAL KEY "
+	case idx.unique:
+		return "UNIQUE KEY "
+	default:
+		return "KEY "
+	}
+}
+
+// mysqlIndexUsing 返回索引方法子句，仅 BTREE/HASH 需要显式声明。
+func mysqlIndexUsing(idx *indexSpec) string {
+	if idx.using == "BTREE" || idx.using == "HASH" {
+		return " USING " + idx.using
+	}
+	return ""
+}
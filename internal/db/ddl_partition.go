@@ -0,0 +1,139 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// partitionBoundPattern 可直接作为分区边界输出的值：数字、NULL、布尔、MINVALUE/MAXVALUE 与按日期字面量计算的常用函数。
+var partitionBoundPattern = regexp.MustCompile(`(?i)^(-?\d+(\.\d+)?|null|true|false|minvalue|maxvalue|(to_days|to_seconds|unix_timestamp|year)\('[0-9:. -]*'\))$`)
+
+// partitionBoundList 将边界值转换为以逗号连接的字面量列表，不能原样输出的值按字符串引用。
+func partitionBoundList(values []string, quote func(string) string) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		if partitionBoundPattern.MatchString(strings.TrimSpace(v)) {
+			out[i] = strings.TrimSpace(v)
+		} else {
+			out[i] = quote(v)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// validatePartitionSpec 校验分区定义的必填项，range 需要上界，list 需要取值。
+func validatePartitionSpec(spec *connection.PartitionSpec) error {
+	if spec == nil || strings.TrimSpace(spec.Name) == "" {
+		return fmt.Errorf("分区名不能为空")
+	}
+	if spec.Default {
+		return nil
+	}
+	switch spec.Method {
+	case connection.PartitionMethodRange:
+		if len(spec.To) == 0 {
+			return fmt.Errorf("range 分区需要上界")
+		}
+	case connection.PartitionMethodList:
+		if len(spec.Values) == 0 {
+			return fmt.Errorf("list 分区需要取值")
+		}
+	case connection.PartitionMethodHash:
+	default:
+		return fmt.Errorf("不支持的分区方式: %s", spec.Method)
+	}
+	return nil
+}
+
+// AddPartition 生成 ALTER TABLE ... ADD PARTITION。hash/key 分区表新增一个分区并由 MySQL 重新分布数据，分区名由 MySQL 生成。
+func (mysqlDDL) AddPartition(schema, table string, spec *connection.PartitionSpec) ([]string, error) {
+	if err := validatePartitionSpec(spec); err != nil {
+		return nil, err
+	}
+	target := "ALTER TABLE " + qualified(schema, table, quoteMySQLIdent)
+	switch {
+	case spec.Default:
+		return nil, fmt.Errorf("MySQL 不支持默认分区，可使用 VALUES LESS THAN (MAXVALUE) 的 range 分区")
+	case spec.Method == connection.PartitionMethodHash:
+		return []string{target + " ADD PARTITION PARTITIONS 1"}, nil
+	case spec.Method == connection.PartitionMethodRange:
+		return []string{target + " ADD PARTITION (PARTITION " + quoteMySQLIdent(spec.Name) + " VALUES LESS THAN (" + partitionBoundList(spec.To, quoteMySQLString) + "))"}, nil
+	default:
+		return []string{target + " ADD PARTITION (PARTITION " + quoteMySQLIdent(spec.Name) + " VALUES IN (" + partitionBoundList(spec.Values, quoteMySQLString) + "))"}, nil
+	}
+}
+
+// DetachPartition 将分区数据交换到独立表 target（为空时为 表名_分区名）后删除该分区。
+// MySQL 没有 DETACH，先建同结构的非分区表再 EXCHANGE；range 分区删除后其范围并入下一个分区。
+func (mysqlDDL) DetachPartition(schema, table, partition, target string) ([]string, error) {
+	if strings.TrimSpace(table) == "" || strings.TrimSpace(partition) == "" {
+		return nil, fmt.Errorf("表名与分区名不能为空")
+	}
+	if target == "" {
+		target = table + "_" + partition
+	}
+	parent := qualified(schema, table, quoteMySQLIdent)
+	standalone := qualified(schema, target, quoteMySQLIdent)
+	return []string{
+		"CREATE TABLE " + standalone + " LIKE " + parent,
+		"ALTER TABLE " + standalone + " REMOVE PARTITIONING",
+		"ALTER TABLE " + parent + " EXCHANGE PARTITION " + quoteMySQLIdent(partition) + " WITH TABLE " + standalone,
+		"ALTER TABLE " + parent + " DROP PARTITION " + quoteMySQLIdent(partition),
+	}, nil
+}
+
+// AddPartition 生成 CREATE TABLE ... PARTITION OF，子表与父表位于同一 schema。
+func (postgresDDL) AddPartition(schema, table string, spec *connection.PartitionSpec) ([]string, error) {
+	if err := validatePartitionSpec(spec); err != nil {
+		return nil, err
+	}
+	stmt := "CREATE TABLE " + qualified(schema, spec.Name, quotePgIdent) + " PARTITION OF " + qualified(schema, table, quotePgIdent)
+	switch {
+	case spec.Default:
+		stmt += " DEFAULT"
+	case spec.Method == connection.PartitionMethodRange:
+		if len(spec.From) == 0 {
+			return nil, fmt.Errorf("range 分区需要下界")
+		}
+		stmt += " FOR VALUES FROM (" + partitionBoundList(spec.From, quotePgString) + ") TO (" + partitionBoundList(spec.To, quotePgString) + ")"
+	case spec.Method == connection.PartitionMethodList:
+		stmt += " FOR VALUES IN (" + partitionBoundList(spec.Values, quotePgString) + ")"
+	default:
+		if spec.Modulus <= 0 || spec.Remainder < 0 || spec.Remainder >= spec.Modulus {
+			return nil, fmt.Errorf("hash 分区的模数须为正数且余数小于模数")
+		}
+		stmt += " FOR VALUES WITH (MODULUS " + strconv.Itoa(spec.Modulus) + ", REMAINDER " + strconv.Itoa(spec.Remainder) + ")"
+	}
+	return []string{stmt}, nil
+}
+
+// DetachPartition 生成 ALTER TABLE ... DETACH PARTITION，子表保留为独立表；target 非空且与分区名不同时随后重命名子表。
+func (postgresDDL) DetachPartition(schema, table, partition, target string) ([]string, error) {
+	if strings.TrimSpace(table) == "" || strings.TrimSpace(partition) == "" {
+		return nil, fmt.Errorf("表名与分区名不能为空")
+	}
+	child := qualified(schema, partition, quotePgIdent)
+	stmts := []string{"ALTER TABLE " + qualified(schema, table, quotePgIdent) + " DETACH PARTITION " + child}
+	if target != "" && target != partition {
+		stmts = append(stmts, "ALTER TABLE "+child+" RENAME TO "+quotePgIdent(target))
+	}
+	return stmts, nil
+}
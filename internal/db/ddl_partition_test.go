@@ -0,0 +1,106 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestMySQLAddAndDetachPartition(t *testing.T) {
+	stmts, err := mysqlDDL{}.AddPartition("shop", "events", &connection.PartitionSpec{
+		Name: "p202402", Method: connection.PartitionMethodRange, To: []string{"TO_DAYS('2024-03-01')"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ALTER TABLE `shop`.`events` ADD PARTITION (PARTITION `p202402` VALUES LESS THAN (TO_DAYS('2024-03-01')))"
+	if len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("range 分区语句不符:\n%v", stmts)
+	}
+
+	stmts, err = mysqlDDL{}.AddPartition("", "orders", &connection.PartitionSpec{
+		Name: "p_east", Method: connection.PartitionMethodList, Values: []string{"1", "east'); DROP TABLE x; --"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stmts[0], "VALUES IN (1, 'east''); DROP TABLE x; --')") {
+		t.Errorf("list 取值应按字符串引用: %s", stmts[0])
+	}
+
+	if _, err := (mysqlDDL{}).AddPartition("", "orders", &connection.PartitionSpec{Name: "p", Method: connection.PartitionMethodRange}); err == nil {
+		t.Error("range 分区缺少上界应返回错误")
+	}
+	if _, err := (mysqlDDL{}).AddPartition("", "orders", &connection.PartitionSpec{Name: "p", Default: true}); err == nil {
+		t.Error("MySQL 默认分区应返回错误")
+	}
+
+	stmts, err = mysqlDDL{}.DetachPartition("shop", "events", "p2023", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 4 || stmts[0] != "CREATE TABLE `shop`.`events_p2023` LIKE `shop`.`events`" ||
+		stmts[2] != "ALTER TABLE `shop`.`events` EXCHANGE PARTITION `p2023` WITH TABLE `shop`.`events_p2023`" {
+		t.Errorf("分离分区语句不符:\n%s", strings.Join(stmts, "\n"))
+	}
+}
+
+func TestPostgresAddAndDetachPartition(t *testing.T) {
+	tests := []struct {
+		spec *connection.PartitionSpec
+		want string
+	}{
+		{
+			spec: &connection.PartitionSpec{Name: "events_2024_02", Method: connection.PartitionMethodRange, From: []string{"2024-02-01"}, To: []string{"2024-03-01"}},
+			want: `CREATE TABLE "events_2024_02" PARTITION OF "events" FOR VALUES FROM ('2024-02-01') TO ('2024-03-01')`,
+		},
+		{
+			spec: &connection.PartitionSpec{Name: "events_h1", Method: connection.PartitionMethodHash, Modulus: 4, Remainder: 1},
+			want: `CREATE TABLE "events_h1" PARTITION OF "events" FOR VALUES WITH (MODULUS 4, REMAINDER 1)`,
+		},
+		{
+			spec: &connection.PartitionSpec{Name: "events_other", Default: true},
+			want: `CREATE TABLE "events_other" PARTITION OF "events" DEFAULT`,
+		},
+	}
+	for _, tt := range tests {
+		stmts, err := postgresDDL{}.AddPartition("", "events", tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.spec.Name, err)
+		}
+		if len(stmts) != 1 || stmts[0] != tt.want {
+			t.Errorf("%s 语句不符:\n%v", tt.spec.Name, stmts)
+		}
+	}
+	if _, err := (postgresDDL{}).AddPartition("", "events", &connection.PartitionSpec{Name: "h", Method: connection.PartitionMethodHash, Modulus: 2, Remainder: 2}); err == nil {
+		t.Error("余数不小于模数应返回错误")
+	}
+
+	stmts, err := postgresDDL{}.DetachPartition("app", "events", "events_2023", "events_archive_2023")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`ALTER TABLE "app"."events" DETACH PARTITION "app"."events_2023"`,
+		`ALTER TABLE "app"."events_2023" RENAME TO "events_archive_2023"`,
+	}
+	if !reflect.DeepEqual(stmts, want) {
+		t.Errorf("分离分区语句不符:\n%v", stmts)
+	}
+}
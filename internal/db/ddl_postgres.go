@@ -0,0 +1,201 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// postgresDDL 生成 PostgreSQL 类数据库的 DDL，注释与索引以独立语句生成。
+type postgresDDL struct{}
+
+// CreateTable 生成 CREATE TABLE 及随后的索引、注释语句。
+func (postgresDDL) CreateTable(schema string, def *connection.TableDefinition) ([]string, error) {
+	if def == nil || strings.TrimSpace(def.Name) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+	if len(def.Columns) == 0 {
+		return nil, fmt.Errorf("表至少需要一列")
+	}
+
+	table := qualified(schema, def.Name, quotePgIdent)
+	var lines, trailing []string
+	for _, col := range def.Columns {
+		line, err := postgresColumnSQL(col)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+		if col.Comment != "" {
+			trailing = append(trailing, "COMMENT ON COLUMN "+table+"."+quotePgIdent(col.Name)+" IS "+quotePgString(col.Comment))
+		}
+	}
+	if pk := primaryKeyColumns(def); len(pk) > 0 {
+		lines = append(lines, "PRIMARY KEY ("+quoteList(pk, quotePgIdent)+")")
+	}
+
+	stmts := []string{"CREATE TABLE " + table + " (\n  " + strings.Join(lines, ",\n  ") + "\n)"}
+	indexes, err := groupIndexes(def.Indexes)
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		stmts = append(stmts, postgresCreateIndex(table, idx))
+	}
+	if def.Comment != "" {
+		stmts = append(stmts, "COMMENT ON TABLE "+table+" IS "+quotePgString(def.Comment))
+	}
+	return append(stmts, trailing...), nil
+}
+
+// AlterTable 按删除、重命名、修改、新增的顺序生成 ALTER TABLE 语句，重命名表放在最后。
+func (postgresDDL) AlterTable(schema string, alt *connection.TableAlteration) ([]string, error) {
+	if alt == nil || strings.TrimSpace(alt.Table) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+
+	table := qualified(schema, alt.Table, quotePgIdent)
+	var actions, stmts []string
+	for _, name := range alt.DropColumns {
+		actions = append(actions, "DROP COLUMN "+quotePgIdent(name))
+	}
+	for _, col := range alt.ModifyColumns {
+		if err := validateColumn(col); err != nil {
+			return nil, err
+		}
+		name := quotePgIdent(col.Name)
+		actions = append(actions, "ALTER COLUMN "+name+" TYPE "+strings.TrimSpace(col.Type))
+		if isNotNull(col) {
+			actions = append(actions, "ALTER COLUMN "+name+" SET NOT NULL")
+		} else {
+			actions = append(actions, "ALTER COLUMN "+name+" DROP NOT NULL")
+		}
+		if col.Default != nil {
+			actions = append(actions, "ALTER COLUMN "+name+" SET DEFAULT "+defaultLiteral(*col.Default, quotePgString))
+		} else if !isAutoIncrement(col) {
+			actions = append(actions, "ALTER COLUMN "+name+" DROP DEFAULT")
+		}
+	}
+	for _, col := range alt.AddColumns {
+		line, err := postgresColumnSQL(col)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, "ADD COLUMN "+line)
+	}
+
+	// RENAME COLUMN 不能与其他子句合并，单独成句且先于列修改执行
+	for _, r := range alt.RenameColumns {
+		if r.From == "" || r.To == "" {
+			return nil, fmt.Errorf("重命名列的原列名与新列名不能为空")
+		}
+		stmts = append(stmts, "ALTER TABLE "+table+" RENAME COLUMN "+quotePgIdent(r.From)+" TO "+quotePgIdent(r.To))
+	}
+	if len(actions) > 0 {
+		stmts = append(stmts, "ALTER TABLE "+table+"\n  "+strings.Join(actions, ",\n  "))
+	}
+	for _, col := range append(append([]*connection.ColumnDefinition{}, alt.ModifyColumns...), alt.AddColumns...) {
+		if col.Comment != "" {
+			stmts = append(stmts, "COMMENT ON COLUMN "+table+"."+quotePgIdent(col.Name)+" IS "+quotePgString(col.Comment))
+		}
+	}
+	if alt.Comment != nil {
+		stmts = append(stmts, "COMMENT ON TABLE "+table+" IS "+quotePgString(*alt.Comment))
+	}
+	if alt.RenameTo != "" {
+		stmts = append(stmts, "ALTER TABLE "+table+" RENAME TO "+quotePgIdent(alt.RenameTo))
+	}
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("没有需要执行的表结构变更")
+	}
+	return stmts, nil
+}
+
+// DropTable 生成 DROP TABLE。
+func (postgresDDL) DropTable(schema, table string, ifExists bool) ([]string, error) {
+	if strings.TrimSpace(table) == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+	stmt := "DROP TABLE "
+	if ifExists {
+		stmt += "IF EXISTS "
+	}
+	return []string{stmt + qualified(schema, table, quotePgIdent)}, nil
+}
+
+// AddIndex 生成 CREATE INDEX；索引名为 PRIMARY 时添加主键约束。
+func (postgresDDL) AddIndex(schema, table string, index []*connection.IndexDefinition) ([]string, error) {
+	specs, err := groupIndexes(index)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) != 1 {
+		return nil, fmt.Errorf("一次只能添加一个索引")
+	}
+	target := qualified(schema, table, quotePgIdent)
+	if strings.EqualFold(specs[0].name, "PRIMARY") {
+		return []string{"ALTER TABLE " + target + " ADD PRIMARY KEY (" + quoteList(specs[0].columns, quotePgIdent) + ")"}, nil
+	}
+	if specs[0].using == "FULLTEXT" || specs[0].using == "SPATIAL" {
+		return nil, fmt.Errorf("PostgreSQL 不支持 %s 索引", specs[0].using)
+	}
+	return []string{postgresCreateIndex(target, specs[0])}, nil
+}
+
+// DropIndex 生成 DROP INDEX，索引与表位于同一 schema。
+func (postgresDDL) DropIndex(schema, table, indexName string) ([]string, error) {
+	if strings.TrimSpace(indexName) == "" {
+		return nil, fmt.Errorf("索引名不能为空")
+	}
+	return []string{"DROP INDEX " + qualified(schema, indexName, quotePgIdent)}, nil
+}
+
+// postgresColumnSQL 生成列定义子句，自增列使用 IDENTITY。
+func postgresColumnSQL(col *connection.ColumnDefinition) (string, error) {
+	if err := validateColumn(col); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString(quotePgIdent(col.Name))
+	sb.WriteString(" ")
+	sb.WriteString(strings.TrimSpace(col.Type))
+	if isAutoIncrement(col) {
+		sb.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	}
+	if isNotNull(col) {
+		sb.WriteString(" NOT NULL")
+	}
+	if col.Default != nil && !isAutoIncrement(col) {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(defaultLiteral(*col.Default, quotePgString))
+	}
+	return sb.String(), nil
+}
+
+// postgresCreateIndex 生成 CREATE INDEX 语句。
+func postgresCreateIndex(table string, idx *indexSpec) string {
+	stmt := "CREATE INDEX "
+	if idx.unique {
+		stmt = "CREATE UNIQUE INDEX "
+	}
+	stmt += quotePgIdent(idx.name) + " ON " + table
+	if idx.using != "" {
+		stmt += " USING " + strings.ToLower(idx.using)
+	}
+	return stmt + " (" + quoteList(idx.columns, quotePgIdent) + ")"
+}
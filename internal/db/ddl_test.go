@@ -0,0 +1,167 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func ddlTestTable() *connection.TableDefinition {
+	def := "0"
+	return &connection.TableDefinition{
+		Name: "users",
+		Columns: []*connection.ColumnDefinition{
+			{Name: "id", Type: "bigint unsigned", Nullable: "NO", Key: "PRI", Extra: "auto_increment"},
+			{Name: "name", Type: "varchar(64)", Nullable: "NO", Comment: "用户名"},
+			{Name: "score", Type: "decimal(10, 2)", Nullable: "YES", Default: &def},
+		},
+		Indexes: []*connection.IndexDefinition{
+			{Name: "uk_name", ColumnName: "name", NonUnique: 0, SeqInIndex: 1},
+		},
+		Comment: "it's users",
+	}
+}
+
+func TestMySQLCreateTable(t *testing.T) {
+	stmts, err := mysqlDDL{}.CreateTable("app", ddlTestTable())
+	if err != nil {
+		t.Fatalf("CreateTable 失败: %v", err)
+	}
+	if len(stmts) != 1 {
+		t.Fatalf("应生成 1 条语句，得到 %d", len(stmts))
+	}
+	for _, want := range []string{
+		"CREATE TABLE `app`.`users`",
+		"`id` bigint unsigned NOT NULL AUTO_INCREMENT",
+		"`name` varchar(64) NOT NULL COMMENT '用户名'",
+		"`score` decimal(10, 2) NULL DEFAULT 0",
+		"PRIMARY KEY (`id`)",
+		"UNIQUE KEY `uk_name` (`name`)",
+		"COMMENT='it''s users'",
+	} {
+		if !strings.Contains(stmts[0], want) {
+			t.Errorf("语句缺少 %q:\n%s", want, stmts[0])
+		}
+	}
+}
+
+func TestPostgresCreateTable(t *testing.T) {
+	stmts, err := postgresDDL{}.CreateTable("", ddlTestTable())
+	if err != nil {
+		t.Fatalf("CreateTable 失败: %v", err)
+	}
+	want := []string{
+		`"id" bigint unsigned GENERATED BY DEFAULT AS IDENTITY NOT NULL`,
+		`CREATE UNIQUE INDEX "uk_name" ON "users" ("name")`,
+		`COMMENT ON TABLE "users" IS 'it''s users'`,
+		`COMMENT ON COLUMN "users"."name" IS '用户名'`,
+	}
+	joined := strings.Join(stmts, ";\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("语句缺少 %q:\n%s", w, joined)
+		}
+	}
+	if len(stmts) != 4 {
+		t.Errorf("应生成 4 条语句，得到 %d", len(stmts))
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	comment := "新注释"
+	alt := &connection.TableAlteration{
+		Table:         "users",
+		AddColumns:    []*connection.ColumnDefinition{{Name: "age", Type: "int", Nullable: "YES"}},
+		RenameColumns: []connection.ColumnRename{{From: "name", To: "nick"}},
+		DropColumns:   []string{"score"},
+		Comment:       &comment,
+	}
+
+	stmts, err := mysqlDDL{}.AlterTable("", alt)
+	if err != nil {
+		t.Fatalf("MySQL AlterTable 失败: %v", err)
+	}
+	want := "ALTER TABLE `users`\n  DROP COLUMN `score`,\n  RENAME COLUMN `name` TO `nick`,\n  ADD COLUMN `age` int NULL,\n  COMMENT = '新注释'"
+	if len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("MySQL ALTER 语句不符:\n%v", stmts)
+	}
+
+	stmts, err = postgresDDL{}.AlterTable("public", alt)
+	if err != nil {
+		t.Fatalf("Postgres AlterTable 失败: %v", err)
+	}
+	if len(stmts) != 3 || !strings.Contains(stmts[0], `RENAME COLUMN "name" TO "nick"`) ||
+		!strings.Contains(stmts[1], `ADD COLUMN "age" int`) || !strings.HasPrefix(stmts[2], `COMMENT ON TABLE "public"."users"`) {
+		t.Errorf("Postgres ALTER 语句不符:\n%s", strings.Join(stmts, "\n"))
+	}
+
+	if _, err := (mysqlDDL{}).AlterTable("", &connection.TableAlteration{Table: "users"}); err == nil {
+		t.Error("无变更时应返回错误")
+	}
+}
+
+func TestDDLRejectsInvalidInput(t *testing.T) {
+	bad := &connection.TableDefinition{
+		Name:    "t",
+		Columns: []*connection.ColumnDefinition{{Name: "a", Type: "int; DROP TABLE x"}},
+	}
+	for _, b := range []DDLBuilder{mysqlDDL{}, postgresDDL{}} {
+		if _, err := b.CreateTable("", bad); err == nil {
+			t.Errorf("%T 应拒绝非法列类型", b)
+		}
+	}
+
+	inject := "x'); DROP TABLE t; --"
+	stmts, err := mysqlDDL{}.CreateTable("", &connection.TableDefinition{
+		Name:    "t`x",
+		Columns: []*connection.ColumnDefinition{{Name: "a", Type: "varchar(10)", Default: &inject}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTable 失败: %v", err)
+	}
+	if !strings.Contains(stmts[0], "`t``x`") || !strings.Contains(stmts[0], `DEFAULT 'x''); DROP TABLE t; --'`) {
+		t.Errorf("标识符或默认值未正确转义:\n%s", stmts[0])
+	}
+
+	if _, err := (mysqlDDL{}).AddIndex("", "t", []*connection.IndexDefinition{{Name: "i", ColumnName: "a", IndexType: "BTREE) ; --"}}); err == nil {
+		t.Error("应拒绝非法索引类型")
+	}
+}
+
+func TestIndexStatements(t *testing.T) {
+	index := []*connection.IndexDefinition{
+		{Name: "idx_ab", ColumnName: "b", NonUnique: 1, SeqInIndex: 2},
+		{Name: "idx_ab", ColumnName: "a", NonUnique: 1, SeqInIndex: 1},
+	}
+	cases := []struct {
+		got  func() ([]string, error)
+		want string
+	}{
+		{func() ([]string, error) { return mysqlDDL{}.AddIndex("db", "t", index) }, "CREATE INDEX `idx_ab` ON `db`.`t` (`a`, `b`)"},
+		{func() ([]string, error) { return postgresDDL{}.AddIndex("", "t", index) }, `CREATE INDEX "idx_ab" ON "t" ("a", "b")`},
+		{func() ([]string, error) { return mysqlDDL{}.DropIndex("", "t", "PRIMARY") }, "ALTER TABLE `t` DROP PRIMARY KEY"},
+		{func() ([]string, error) { return postgresDDL{}.DropIndex("s", "t", "idx_ab") }, `DROP INDEX "s"."idx_ab"`},
+		{func() ([]string, error) { return mysqlDDL{}.DropTable("", "t", true) }, "DROP TABLE IF EXISTS `t`"},
+	}
+	for _, c := range cases {
+		stmts, err := c.got()
+		if err != nil || len(stmts) != 1 || stmts[0] != c.want {
+			t.Errorf("期望 %q，得到 %v (err=%v)", c.want, stmts, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// DriverCapabilities 描述驱动支持的能力，通用功能据此降级（如不支持事务时不做整体回滚）。
+type DriverCapabilities struct {
+	SupportsTransactions bool `json:"supportsTransactions"`
+	SupportsSchemas      bool `json:"supportsSchemas"`
+}
+
+// DriverInfo 是已注册的自定义驱动信息。
+type DriverInfo struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Capabilities DriverCapabilities `json:"capabilities"`
+}
+
+// DriverFactory 创建自定义驱动实例，每个连接调用一次。
+type DriverFactory func() Database
+
+// registeredDriver 是注册表中的一项。
+type registeredDriver struct {
+	info    DriverInfo
+	factory DriverFactory
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]registeredDriver)
+)
+
+// RegisterDriver 注册 "custom" 连接类型可用的驱动，通常在驱动包的 init 中调用，
+// 由宿主通过空白导入或驱动清单（见 LoadDriverPlugins）启用；重复注册同名驱动返回错误。
+func RegisterDriver(info DriverInfo, factory DriverFactory) error {
+	name := strings.TrimSpace(info.Name)
+	if name == "" || factory == nil {
+		return fmt.Errorf("驱动名称与工厂函数不能为空")
+	}
+	info.Name = name
+
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if _, exists := drivers[name]; exists {
+		return fmt.Errorf("驱动已注册: %s", name)
+	}
+	drivers[name] = registeredDriver{info: info, factory: factory}
+	return nil
+}
+
+// RegisterSQLDriver 把已通过 sql.Register 注册的 database/sql 驱动登记为自定义驱动，
+// 使用通用适配器按 DSN 连接，并声明其能力。
+func RegisterSQLDriver(name, description string, caps DriverCapabilities) error {
+	return RegisterDriver(DriverInfo{Name: name, Description: description, Capabilities: caps}, func() Database {
+		return NewGenericSQLDB(name)
+	})
+}
+
+// Drivers 返回可用于自定义连接的驱动：已注册的驱动，以及仅通过 sql.Register 注册的 database/sql 驱动（能力按最保守处理）。
+func Drivers() []DriverInfo {
+	driversMu.RLock()
+	list := make([]DriverInfo, 0, len(drivers))
+	for _, d := range drivers {
+		list = append(list, d.info)
+	}
+	driversMu.RUnlock()
+
+	for _, name := range sql.Drivers() {
+		if _, ok := lookupDriver(name); !ok && name != "mysql" {
+			list = append(list, DriverInfo{Name: name, Description: "database/sql 驱动"})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// lookupDriver 查找已注册的驱动。
+func lookupDriver(name string) (registeredDriver, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[name]
+	return d, ok
+}
+
+// isSQLDriver 判断 name 是否为 database/sql 已注册的驱动。
+func isSQLDriver(name string) bool {
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDatabaseForConfig 按连接配置创建驱动实例：自定义连接按 Driver 名称从注册表创建，
+// 未注册但 database/sql 可用的驱动使用通用适配器，其余类型委托 NewDatabase。
+func NewDatabaseForConfig(config *connection.ConnectionConfig) (Database, error) {
+	if config.Type != connection.ConnectionTypeCustom {
+		return NewDatabase(config.Type)
+	}
+	name := strings.TrimSpace(config.Driver)
+	if name == "" {
+		return nil, fmt.Errorf("自定义连接需要指定驱动")
+	}
+	if d, ok := lookupDriver(name); ok {
+		return d.factory(), nil
+	}
+	if isSQLDriver(name) {
+		return NewGenericSQLDB(name), nil
+	}
+	return nil, fmt.Errorf("未注册的驱动: %s", name)
+}
+
+// Capabilities 返回连接配置对应驱动的能力；内置 MySQL 与 PostgreSQL 支持事务与多库（schema），SQLite 支持事务，未知驱动按最保守处理。
+func Capabilities(config *connection.ConnectionConfig) DriverCapabilities {
+	switch config.Type {
+	case connection.ConnectionTypeCustom:
+		if d, ok := lookupDriver(strings.TrimSpace(config.Driver)); ok {
+			return d.info.Capabilities
+		}
+		return DriverCapabilities{}
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypePostgreSQL, "":
+		return DriverCapabilities{SupportsTransactions: true, SupportsSchemas: true}
+	case connection.ConnectionTypeSQLite:
+		return DriverCapabilities{SupportsTransactions: true}
+	default:
+		return DriverCapabilities{}
+	}
+}
+
+// DefaultDriverPluginDir 返回驱动插件的默认目录。
+func DefaultDriverPluginDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "Boxify", "drivers")
+}
+
+// DriverManifest 是驱动插件目录中的清单文件（*.json），把应用内已链接的 database/sql 驱动以新名称登记为自定义驱动，
+// 例如将 TiDB、Doris 等兼容 MySQL 协议的数据库登记为独立驱动并声明各自的能力。
+//
+// Go 插件（plugin 包）只支持 Linux/macOS 且需要 cgo，因此插件以清单描述而不加载可执行代码；
+// 引入新的驱动实现仍需空白导入后重新构建。
+type DriverManifest struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	SQLDriver    string             `json:"sqlDriver"` // 实际使用的 database/sql 驱动名，如 mysql、sqlite
+	Capabilities DriverCapabilities `json:"capabilities"`
+}
+
+// LoadDriverPlugins 读取目录中的驱动清单（*.json）并登记为自定义驱动，返回成功登记的清单路径。
+// 目录不存在时不做任何事；单个清单无效或驱动未链接不影响其余清单，错误汇总返回。
+func LoadDriverPlugins(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var loaded []string
+	var failures []string
+	for _, path := range paths {
+		if err := loadDriverManifest(path); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+			continue
+		}
+		loaded = append(loaded, path)
+	}
+	if len(failures) > 0 {
+		return loaded, fmt.Errorf("加载驱动插件失败: %s", strings.Join(failures, "; "))
+	}
+	return loaded, nil
+}
+
+// loadDriverManifest 解析单个清单并登记驱动。
+func loadDriverManifest(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var m DriverManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("解析清单失败: %w", err)
+	}
+	sqlDriver := strings.TrimSpace(m.SQLDriver)
+	if !isSQLDriver(sqlDriver) {
+		return fmt.Errorf("database/sql 驱动未链接: %s", sqlDriver)
+	}
+	return RegisterDriver(DriverInfo{Name: m.Name, Description: m.Description, Capabilities: m.Capabilities}, func() Database {
+		return NewGenericSQLDB(sqlDriver)
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// stubSQLDriver 是只用于注册名称的 database/sql 驱动。
+type stubSQLDriver struct{}
+
+func (stubSQLDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("boxify-stub", stubSQLDriver{})
+}
+
+func TestRegisterDriverAndLookup(t *testing.T) {
+	caps := DriverCapabilities{SupportsTransactions: true}
+	created := 0
+	err := RegisterDriver(DriverInfo{Name: "test-driver", Description: "测试", Capabilities: caps}, func() Database {
+		created++
+		return NewGenericSQLDB("test-driver")
+	})
+	if err != nil {
+		t.Fatalf("RegisterDriver 失败: %v", err)
+	}
+	if err := RegisterDriver(DriverInfo{Name: "test-driver"}, func() Database { return nil }); err == nil {
+		t.Fatal("重复注册应返回错误")
+	}
+	if err := RegisterDriver(DriverInfo{Name: " "}, nil); err == nil {
+		t.Fatal("空名称应返回错误")
+	}
+
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "test-driver"}
+	if _, err := NewDatabaseForConfig(cfg); err != nil || created != 1 {
+		t.Fatalf("应通过注册表创建驱动: created=%d err=%v", created, err)
+	}
+	if got := Capabilities(cfg); got != caps {
+		t.Fatalf("能力不符: %+v", got)
+	}
+
+	var names []string
+	for _, info := range Drivers() {
+		names = append(names, info.Name)
+	}
+	if !containsString(names, "test-driver") || !containsString(names, "boxify-stub") || containsString(names, "mysql") {
+		t.Fatalf("驱动列表不符: %v", names)
+	}
+}
+
+func TestNewDatabaseForConfigFallbacks(t *testing.T) {
+	inst, err := NewDatabaseForConfig(&connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "boxify-stub"})
+	if err != nil {
+		t.Fatalf("database/sql 驱动应使用通用适配器: %v", err)
+	}
+	if _, ok := inst.(*GenericSQLDB); !ok {
+		t.Fatalf("应返回 GenericSQLDB，实际 %T", inst)
+	}
+	if err := inst.Connect(&connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "boxify-stub"}); err == nil {
+		t.Fatal("缺少 DSN 时连接应失败")
+	}
+	if got := Capabilities(&connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "boxify-stub"}); got != (DriverCapabilities{}) {
+		t.Fatalf("未登记的驱动能力应按最保守处理: %+v", got)
+	}
+
+	if _, err := NewDatabaseForConfig(&connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "missing"}); err == nil {
+		t.Fatal("未注册的驱动应返回错误")
+	}
+	if _, err := NewDatabaseForConfig(&connection.ConnectionConfig{Type: connection.ConnectionTypeCustom}); err == nil {
+		t.Fatal("未指定驱动应返回错误")
+	}
+	if inst, err := NewDatabaseForConfig(&connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL}); err != nil {
+		t.Fatalf("MySQL 应委托 NewDatabase: %v", err)
+	} else if _, ok := inst.(*MySQLDB); !ok {
+		t.Fatalf("应返回 MySQLDB，实际 %T", inst)
+	}
+}
+
+func TestLoadDriverPluginsEmptyDir(t *testing.T) {
+	loaded, err := LoadDriverPlugins(t.TempDir())
+	if err != nil || len(loaded) != 0 {
+		t.Fatalf("空目录不应加载插件: %v, %v", loaded, err)
+	}
+}
+
+func TestLoadDriverPluginsManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a-alias.json":   `{"name":"manifest-alias","description":"别名","sqlDriver":"boxify-stub","capabilities":{"supportsTransactions":true}}`,
+		"b-missing.json": `{"name":"manifest-missing","sqlDriver":"not-linked"}`,
+		"c-broken.json":  `{`,
+		"d-plugin.so":    "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadDriverPlugins(dir)
+	if err == nil || !strings.Contains(err.Error(), "b-missing.json") || !strings.Contains(err.Error(), "c-broken.json") {
+		t.Fatalf("无效清单应汇总报错: %v", err)
+	}
+	if len(loaded) != 1 || filepath.Base(loaded[0]) != "a-alias.json" {
+		t.Fatalf("应只登记有效清单: %v", loaded)
+	}
+
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeCustom, Driver: "manifest-alias"}
+	inst, err := NewDatabaseForConfig(cfg)
+	if err != nil {
+		t.Fatalf("清单驱动应可创建: %v", err)
+	}
+	if g, ok := inst.(*GenericSQLDB); !ok || g.driver != "boxify-stub" {
+		t.Fatalf("清单驱动应使用引用的 database/sql 驱动: %#v", inst)
+	}
+	if got := Capabilities(cfg); !got.SupportsTransactions || got.SupportsSchemas {
+		t.Fatalf("能力应取自清单: %+v", got)
+	}
+}
+
+// containsString 判断 list 中是否包含 s。
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
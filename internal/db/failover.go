@@ -0,0 +1,218 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// hostRetryInterval 是不可达主机被跳过的时长，期满后重新按顺序优先尝试（主库恢复后自动切回）。
+const hostRetryInterval = 30 * time.Second
+
+// HostChange 是多主机连接的活动主机变化，From 为空表示首次建立连接。
+type HostChange struct {
+	Key     string    `json:"key"`              // 不含库名与凭据的连接标识
+	Type    string    `json:"type"`             // 数据库类型
+	User    string    `json:"user"`             // 登录用户
+	From    string    `json:"from,omitempty"`   // 原主机 host:port
+	To      string    `json:"to"`               // 新主机 host:port
+	Primary bool      `json:"primary"`          // 新主机是否为主库
+	Reason  string    `json:"reason,omitempty"` // 切换原因（前面主机的连接错误）
+	At      time.Time `json:"at"`
+}
+
+// SetHostListener 设置多主机连接活动主机变化的回调（在获取连接的协程中调用，不应阻塞）。
+func (m *ConnectionManager) SetHostListener(fn func(HostChange)) {
+	m.mu.Lock()
+	m.hostListener = fn
+	m.mu.Unlock()
+}
+
+// GetForRead 返回执行只读查询使用的连接，配置了备用主机时按只读路由选择：
+// replica 优先使用备用主机（均不可达时回落到主库），primary 同 Get，
+// 未指定时 round-robin 策略在全部可用主机间轮询，其余情况同 Get。
+func (m *ConnectionManager) GetForRead(config *connection.ConnectionConfig, forcePing bool) (Database, error) {
+	config = config.Normalize()
+	if len(config.Hosts) == 0 || config.ReadRoute == connection.RoutePrimary {
+		return m.Get(config, forcePing)
+	}
+	if config.ReadRoute != connection.RouteReplica && config.HostPolicy != connection.HostPolicyRoundRobin {
+		return m.Get(config, forcePing)
+	}
+
+	hosts := hostConfigs(config)
+	// replica 路由只在备用主机间选择，主库仅作为最后的回落
+	first := 0
+	if config.ReadRoute == connection.RouteReplica {
+		first = 1
+	}
+	candidates := len(hosts) - first
+	start := 0
+	if config.HostPolicy == connection.HostPolicyRoundRobin {
+		logical := serverKey(config)
+		m.mu.Lock()
+		start = m.readCursor[logical] % candidates
+		m.readCursor[logical] = start + 1
+		m.mu.Unlock()
+	}
+
+	order := make([]int, 0, len(hosts))
+	for i := 0; i < candidates; i++ {
+		order = append(order, first+(start+i)%candidates)
+	}
+	order = m.preferAvailable(hosts, order)
+	if first == 1 {
+		order = append(order, 0)
+	}
+	inst, _, err := m.tryHosts(hosts, order, forcePing)
+	return inst, err
+}
+
+// getFailover 按顺序返回第一个可用主机的连接，活动主机变化时通知监听者；config 已规范化。
+func (m *ConnectionManager) getFailover(config *connection.ConnectionConfig, forcePing bool) (Database, error) {
+	hosts := hostConfigs(config)
+	order := make([]int, len(hosts))
+	for i := range order {
+		order[i] = i
+	}
+	inst, idx, err := m.tryHosts(hosts, m.preferAvailable(hosts, order), forcePing)
+	if err != nil {
+		return nil, err
+	}
+
+	logical := serverKey(config)
+	m.mu.Lock()
+	prev, had := m.active[logical]
+	m.active[logical] = idx
+	listener := m.hostListener
+	m.mu.Unlock()
+	if had && prev == idx {
+		return inst, nil
+	}
+
+	change := HostChange{
+		Key:     logical,
+		Type:    string(config.Type),
+		User:    config.User,
+		To:      hostAddr(hosts[idx]),
+		Primary: idx == 0,
+		At:      time.Now(),
+	}
+	if had {
+		change.From = hostAddr(hosts[prev])
+	}
+	if idx > 0 {
+		change.Reason = m.hostDownReason(hosts[0])
+		m.logError("主库不可用，已切换到备用主机", "summary", FormatConnSummary(hosts[idx]), "from", change.From)
+	} else if had {
+		m.logInfo("主库已恢复，切回主库", "summary", FormatConnSummary(hosts[idx]), "from", change.From)
+	}
+	if listener != nil {
+		listener(change)
+	}
+	return inst, nil
+}
+
+// tryHosts 按 order 依次连接主机，返回第一个成功的连接及其序号；全部失败时返回首个错误。
+func (m *ConnectionManager) tryHosts(hosts []*connection.ConnectionConfig, order []int, forcePing bool) (Database, int, error) {
+	var firstErr error
+	for _, i := range order {
+		inst, err := m.getSingle(hosts[i], forcePing)
+		if err == nil {
+			m.markHostUp(hosts[i])
+			return inst, i, nil
+		}
+		m.markHostDown(hosts[i], err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, -1, fmt.Errorf("全部 %d 个主机均不可用：%w", len(hosts), firstErr)
+}
+
+// preferAvailable 保持 order 的相对顺序，把仍处于跳过期的主机移到末尾。
+func (m *ConnectionManager) preferAvailable(hosts []*connection.ConnectionConfig, order []int) []int {
+	now := time.Now()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	up := make([]int, 0, len(order))
+	var down []int
+	for _, i := range order {
+		if until, ok := m.hostDown[cacheKey(hosts[i])]; ok && now.Before(until) {
+			down = append(down, i)
+			continue
+		}
+		up = append(up, i)
+	}
+	return append(up, down...)
+}
+
+// markHostDown 记录主机不可达，在 hostRetryInterval 内不再优先尝试。
+func (m *ConnectionManager) markHostDown(config *connection.ConnectionConfig, err error) {
+	m.mu.Lock()
+	m.hostDown[cacheKey(config)] = time.Now().Add(hostRetryInterval)
+	m.hostErrs[cacheKey(config)] = err.Error()
+	m.mu.Unlock()
+}
+
+// markHostUp 清除主机的不可达记录。
+func (m *ConnectionManager) markHostUp(config *connection.ConnectionConfig) {
+	key := cacheKey(config)
+	m.mu.Lock()
+	delete(m.hostDown, key)
+	delete(m.hostErrs, key)
+	m.mu.Unlock()
+}
+
+// hostDownReason 返回主机最近一次的连接错误。
+func (m *ConnectionManager) hostDownReason(config *connection.ConnectionConfig) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hostErrs[cacheKey(config)]
+}
+
+// activeKey 返回配置当前使用的缓存 key：多主机连接取活动主机（未建立过连接时取主库）。
+func (m *ConnectionManager) activeKey(config *connection.ConnectionConfig) string {
+	config = config.Normalize()
+	if len(config.Hosts) == 0 {
+		return cacheKey(config)
+	}
+	hosts := hostConfigs(config)
+	m.mu.RLock()
+	idx := m.active[serverKey(config)]
+	m.mu.RUnlock()
+	if idx < 0 || idx >= len(hosts) {
+		idx = 0
+	}
+	return cacheKey(hosts[idx])
+}
+
+// hostConfigs 返回每个主机对应的单主机配置，主库在前。
+func hostConfigs(config *connection.ConnectionConfig) []*connection.ConnectionConfig {
+	endpoints := config.Endpoints()
+	out := make([]*connection.ConnectionConfig, len(endpoints))
+	for i, h := range endpoints {
+		out[i] = config.ForHost(h)
+	}
+	return out
+}
+
+// hostAddr 返回单主机配置的 host:port。
+func hostAddr(config *connection.ConnectionConfig) string {
+	return connection.HostAddress{Host: config.Host, Port: config.Port}.String()
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestConnectionManagerFailover(t *testing.T) {
+	defer SetRetryAttempts(CurrentRetryPolicy().Attempts)
+	SetRetryAttempts(0)
+
+	m := NewConnectionManager(nil)
+	replica := connection.HostAddress{Host: "replica", Port: 3306}
+	// 主库端口不可达，备用主机已有缓存连接
+	cfg := &connection.ConnectionConfig{
+		Type: connection.ConnectionTypeMySQL, Host: "127.0.0.1", Port: 1, User: "u", Timeout: 1,
+		Hosts: []connection.HostAddress{replica},
+	}
+	replicaInst := addFakeEntry(m, cfg.ForHost(replica), time.Now())
+
+	var changes []HostChange
+	m.SetHostListener(func(c HostChange) { changes = append(changes, c) })
+
+	inst, err := m.Get(cfg, false)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if inst != replicaInst {
+		t.Fatal("主库不可达时应切换到备用主机")
+	}
+	if len(changes) != 1 || changes[0].To != "replica:3306" || changes[0].Primary || changes[0].Reason == "" {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	// 主库处于跳过期内，再次获取直接使用备用主机且不重复通知
+	if inst, err = m.Get(cfg, false); err != nil || inst != replicaInst {
+		t.Fatalf("second Get: inst=%v err=%v", inst, err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("活动主机未变化时不应通知: %+v", changes)
+	}
+	if cfg.Host != "127.0.0.1" || len(cfg.Hosts) != 1 || cfg.HostPolicy != "" {
+		t.Fatalf("Get 修改了调用方的配置: %+v", cfg)
+	}
+
+	if n, _ := m.Disconnect(cfg); n != 1 || !replicaInst.closed {
+		t.Fatalf("Disconnect 应关闭备用主机连接: n=%d closed=%v", n, replicaInst.closed)
+	}
+}
+
+func TestConnectionManagerRoundRobinReads(t *testing.T) {
+	m := NewConnectionManager(nil)
+	replica := connection.HostAddress{Host: "replica", Port: 3306}
+	cfg := &connection.ConnectionConfig{
+		Type: connection.ConnectionTypeMySQL, Host: "primary", Port: 3306, User: "u",
+		Hosts: []connection.HostAddress{replica}, HostPolicy: connection.HostPolicyRoundRobin,
+	}
+	primaryInst := addFakeEntry(m, cfg.ForHost(cfg.Endpoints()[0]), time.Now())
+	replicaInst := addFakeEntry(m, cfg.ForHost(replica), time.Now())
+
+	var got []Database
+	for i := 0; i < 4; i++ {
+		inst, err := m.GetForRead(cfg, false)
+		if err != nil {
+			t.Fatalf("GetForRead: %v", err)
+		}
+		got = append(got, inst)
+	}
+	if got[0] != primaryInst || got[1] != replicaInst || got[2] != primaryInst || got[3] != replicaInst {
+		t.Fatalf("只读查询应在主机间轮询: %v", got)
+	}
+	if inst, _ := m.Get(cfg, false); inst != primaryInst {
+		t.Fatal("写操作应使用主库")
+	}
+}
+
+func TestConnectionManagerReplicaRoute(t *testing.T) {
+	m := NewConnectionManager(nil)
+	r1 := connection.HostAddress{Host: "r1", Port: 3306}
+	r2 := connection.HostAddress{Host: "r2", Port: 3306}
+	cfg := &connection.ConnectionConfig{
+		Type: connection.ConnectionTypeMySQL, Host: "primary", Port: 3306, User: "u",
+		Hosts: []connection.HostAddress{r1, r2}, ReadRoute: connection.RouteReplica,
+	}
+	primaryInst := addFakeEntry(m, cfg.ForHost(cfg.Endpoints()[0]), time.Now())
+	r1Inst := addFakeEntry(m, cfg.ForHost(r1), time.Now())
+	addFakeEntry(m, cfg.ForHost(r2), time.Now())
+
+	for i := 0; i < 3; i++ {
+		if inst, err := m.GetForRead(cfg, false); err != nil || inst != r1Inst {
+			t.Fatalf("replica 路由应使用第一个备用主机: inst=%v err=%v", inst, err)
+		}
+	}
+	if inst, _ := m.GetForRead(cfg.WithReadRoute(connection.RoutePrimary), false); inst != primaryInst {
+		t.Fatal("单次覆盖为 primary 时应使用主库")
+	}
+	if inst, _ := m.Get(cfg, false); inst != primaryInst {
+		t.Fatal("写操作应使用主库")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// fieldKinds 将规范化后的数据库类型名映射为类型大类，覆盖 MySQL 与 PostgreSQL 驱动返回的类型名
+var fieldKinds = map[string]string{
+	"TINYINT": connection.FieldKindNumber, "SMALLINT": connection.FieldKindNumber, "MEDIUMINT": connection.FieldKindNumber,
+	"INT": connection.FieldKindNumber, "INTEGER": connection.FieldKindNumber, "BIGINT": connection.FieldKindNumber,
+	"DECIMAL": connection.FieldKindNumber, "NUMERIC": connection.FieldKindNumber, "FLOAT": connection.FieldKindNumber,
+	"DOUBLE": connection.FieldKindNumber, "REAL": connection.FieldKindNumber, "YEAR": connection.FieldKindNumber,
+	"INT2": connection.FieldKindNumber, "INT4": connection.FieldKindNumber, "INT8": connection.FieldKindNumber,
+	"FLOAT4": connection.FieldKindNumber, "FLOAT8": connection.FieldKindNumber, "MONEY": connection.FieldKindNumber,
+	"OID": connection.FieldKindNumber,
+
+	"CHAR": connection.FieldKindString, "VARCHAR": connection.FieldKindString, "TEXT": connection.FieldKindString,
+	"TINYTEXT": connection.FieldKindString, "MEDIUMTEXT": connection.FieldKindString, "LONGTEXT": connection.FieldKindString,
+	"ENUM": connection.FieldKindString, "SET": connection.FieldKindString, "BPCHAR": connection.FieldKindString,
+	"NAME": connection.FieldKindString, "UUID": connection.FieldKindString, "CITEXT": connection.FieldKindString,
+
+	"DATE": connection.FieldKindDateTime, "TIME": connection.FieldKindDateTime, "DATETIME": connection.FieldKindDateTime,
+	"TIMESTAMP": connection.FieldKindDateTime, "TIMESTAMPTZ": connection.FieldKindDateTime, "TIMETZ": connection.FieldKindDateTime,
+	"INTERVAL": connection.FieldKindDateTime,
+
+	"BOOL": connection.FieldKindBoolean, "BOOLEAN": connection.FieldKindBoolean,
+
+	"BINARY": connection.FieldKindBinary, "VARBINARY": connection.FieldKindBinary, "BLOB": connection.FieldKindBinary,
+	"TINYBLOB": connection.FieldKindBinary, "MEDIUMBLOB": connection.FieldKindBinary, "LONGBLOB": connection.FieldKindBinary,
+	"BYTEA": connection.FieldKindBinary, "BIT": connection.FieldKindBinary, "GEOMETRY": connection.FieldKindBinary,
+
+	"JSON": connection.FieldKindJSON, "JSONB": connection.FieldKindJSON,
+}
+
+// FieldKind 返回数据库类型名对应的类型大类。
+// MySQL 驱动对无符号类型返回 "UNSIGNED INT" 形式，PostgreSQL 数组类型以下划线开头（归为 other）。
+func FieldKind(databaseTypeName string) string {
+	t := strings.ToUpper(strings.TrimSpace(databaseTypeName))
+	t = strings.TrimPrefix(t, "UNSIGNED ")
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		t = t[:i]
+	}
+	if kind, ok := fieldKinds[t]; ok {
+		return kind
+	}
+	return connection.FieldKindOther
+}
+
+// fieldMetas 根据列名与驱动提供的列类型构造 FieldMeta 列表；colTypes 为空时只填写列名。
+func fieldMetas(columns []string, colTypes []*sql.ColumnType) []connection.FieldMeta {
+	fields := make([]connection.FieldMeta, len(columns))
+	for i, name := range columns {
+		fields[i] = connection.FieldMeta{Name: name, Kind: connection.FieldKindOther}
+		if colTypes == nil || i >= len(colTypes) || colTypes[i] == nil {
+			continue
+		}
+		ct := colTypes[i]
+		fields[i].Type = ct.DatabaseTypeName()
+		fields[i].Kind = FieldKind(fields[i].Type)
+		if nullable, ok := ct.Nullable(); ok {
+			fields[i].Nullable = &nullable
+		}
+		if length, ok := ct.Length(); ok {
+			fields[i].Length = &length
+		}
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			fields[i].Precision, fields[i].Scale = &precision, &scale
+		}
+	}
+	return fields
+}
+
+// singleTableQueryPattern 匹配不含连接、子查询来源与集合运算的单表查询，捕获选择列表与表名；
+// 表后只允许别名与 WHERE / ORDER BY / LIMIT，分组、聚合等其余形式不识别。
+var singleTableQueryPattern = regexp.MustCompile("(?is)^\\s*select\\s+(?:distinct\\s+)?(.+?)\\s+from\\s+(" + identPattern + "(?:\\." + identPattern + ")?)(?:\\s+(?:as\\s+)?" + identPattern + ")?\\s*(?:(?:where|order|limit)\\b.*?)?;?\\s*$")
+
+// setOperationPattern 匹配集合运算，WHERE 之后的 UNION 等会合并其他表的行。
+var setOperationPattern = regexp.MustCompile(`(?i)\b(union|intersect|except)\b`)
+
+// selectItemPattern 匹配选择列表中对列的直接引用：[限定名.]列名 [[AS] 别名] 或 [限定名.]*。
+var selectItemPattern = regexp.MustCompile("(?is)^(?:" + identPattern + "\\.)*(\\*|" + identPattern + ")(?:\\s+(?:as\\s+)?(" + identPattern + "))?$")
+
+// identPattern 匹配裸标识符或以反引号、双引号引用的标识符。
+const identPattern = "(?:`[^`]+`|\"[^\"]+\"|[\\w$]+)"
+
+// FillOriginTable 为单表查询中直接引用的列填写来源表名。
+//
+// MySQL 与 SQLite 驱动都不通过 ColumnTypes 提供列的来源表（go-sql-driver 只能以 columnsWithAlias 改写全部列名），
+// 因此从语句本身推断：表达式、函数与别名不对应的列保持为空，无法识别的语句不做任何修改。
+func FillOriginTable(fields []connection.FieldMeta, query string) {
+	m := singleTableQueryPattern.FindStringSubmatch(query)
+	if m == nil || setOperationPattern.MatchString(query) {
+		return
+	}
+	parts := splitQualified(m[2])
+	table := parts[len(parts)-1]
+
+	direct := make(map[string]bool)  // 直接引用的列（输出名）
+	derived := make(map[string]bool) // 表达式等非直接引用的输出名
+	star := false
+	for _, item := range splitSelectList(m[1]) {
+		im := selectItemPattern.FindStringSubmatch(item)
+		switch {
+		case im == nil:
+			// 表达式没有别名时输出名即表达式原文
+			name := item
+			if i := strings.LastIndexFunc(item, unicode.IsSpace); i >= 0 {
+				name = unquoteIdent(item[i+1:])
+			}
+			derived[name], derived[item] = true, true
+		case im[1] == "*":
+			star = true
+		case im[2] != "":
+			direct[unquoteIdent(im[2])] = true
+		default:
+			direct[unquoteIdent(im[1])] = true
+		}
+	}
+	for i := range fields {
+		name := fields[i].Name
+		if direct[name] || (star && !derived[name]) {
+			fields[i].Table = table
+		}
+	}
+}
+
+// splitSelectList 按顶层逗号拆分选择列表，括号与引号内的逗号不拆分。
+func splitSelectList(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(list[start:]))
+}
+
+// splitQualified 拆分以点连接的限定名并去掉各部分的引号。
+func splitQualified(name string) []string {
+	var parts []string
+	for len(name) > 0 {
+		end := strings.IndexByte(name, '.')
+		if name[0] == '`' || name[0] == '"' {
+			if close := strings.IndexByte(name[1:], name[0]); close >= 0 {
+				end = close + 2
+				if end >= len(name) {
+					end = -1
+				}
+			}
+		}
+		if end < 0 {
+			parts = append(parts, unquoteIdent(name))
+			break
+		}
+		parts = append(parts, unquoteIdent(name[:end]))
+		name = name[end+1:]
+	}
+	return parts
+}
+
+// unquoteIdent 去掉标识符两侧的反引号或双引号。
+func unquoteIdent(name string) string {
+	if len(name) >= 2 && (name[0] == '`' || name[0] == '"') && name[len(name)-1] == name[0] {
+		return name[1 : len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestFieldKind(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{"INT", connection.FieldKindNumber},
+		{"UNSIGNED BIGINT", connection.FieldKindNumber},
+		{"decimal", connection.FieldKindNumber},
+		{"NUMERIC(10,2)", connection.FieldKindNumber},
+		{"INT4", connection.FieldKindNumber},
+		{"VARCHAR", connection.FieldKindString},
+		{"BPCHAR", connection.FieldKindString},
+		{"DATETIME", connection.FieldKindDateTime},
+		{"TIMESTAMPTZ", connection.FieldKindDateTime},
+		{"BOOL", connection.FieldKindBoolean},
+		{"BYTEA", connection.FieldKindBinary},
+		{"LONGBLOB", connection.FieldKindBinary},
+		{"JSONB", connection.FieldKindJSON},
+		{"_INT4", connection.FieldKindOther},
+		{"", connection.FieldKindOther},
+	}
+	for _, tt := range tests {
+		if got := FieldKind(tt.typeName); got != tt.want {
+			t.Errorf("FieldKind(%q) = %q, want %q", tt.typeName, got, tt.want)
+		}
+	}
+}
+
+func TestFieldMetasWithoutColumnTypes(t *testing.T) {
+	fields := fieldMetas([]string{"id", "name"}, nil)
+	if len(fields) != 2 {
+		t.Fatalf("len(fields) = %d, want 2", len(fields))
+	}
+	for i, name := range []string{"id", "name"} {
+		f := fields[i]
+		if f.Name != name || f.Type != "" || f.Kind != connection.FieldKindOther {
+			t.Errorf("fields[%d] = %+v", i, f)
+		}
+		if f.Nullable != nil || f.Length != nil || f.Precision != nil || f.Scale != nil {
+			t.Errorf("fields[%d] 不应填写驱动未提供的项: %+v", i, f)
+		}
+	}
+}
+
+func TestFillOriginTable(t *testing.T) {
+	tests := []struct {
+		query   string
+		columns []string
+		want    []string
+	}{
+		{"SELECT * FROM users WHERE id > 1", []string{"id", "name"}, []string{"users", "users"}},
+		{"select u.id, u.name AS `nick`, COUNT(*) AS n from `app`.`users` u order by id", []string{"id", "nick", "n"}, []string{"users", "users", ""}},
+		{"SELECT *, LENGTH(name) AS len FROM \"users\"", []string{"id", "name", "len"}, []string{"users", "users", ""}},
+		{"SELECT a.id FROM a JOIN b ON a.id = b.id", []string{"id"}, []string{""}},
+		{"SELECT id FROM a UNION SELECT id FROM b", []string{"id"}, []string{""}},
+		{"SELECT id FROM (SELECT 1 AS id) t", []string{"id"}, []string{""}},
+		{"SHOW TABLES", []string{"Tables_in_app"}, []string{""}},
+	}
+	for _, tt := range tests {
+		fields := fieldMetas(tt.columns, nil)
+		FillOriginTable(fields, tt.query)
+		for i, f := range fields {
+			if f.Table != tt.want[i] {
+				t.Errorf("%q: fields[%d].Table = %q, want %q", tt.query, i, f.Table, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// ReferencingKeysReader 定义读取引用指定表的外键（子表方向）的能力。
+type ReferencingKeysReader interface {
+	GetReferencingForeignKeys(dbName, tableName string) ([]*connection.ForeignKeyRef, error)
+}
+
+const mysqlReferencingKeysSQL = `SELECT TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_COLUMN_NAME
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ?
+ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION`
+
+// GetReferencingForeignKeys 读取 information_schema 中引用 tableName 的外键，按子表与约束聚合。
+func (m *MySQLDB) GetReferencingForeignKeys(dbName, tableName string) ([]*connection.ForeignKeyRef, error) {
+	data, _, err := m.Query(mysqlReferencingKeysSQL, dbName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	var refs []*connection.ForeignKeyRef
+	index := make(map[string]*connection.ForeignKeyRef)
+	for _, row := range data {
+		table := stringOrEmpty(row["TABLE_NAME"])
+		name := stringOrEmpty(row["CONSTRAINT_NAME"])
+		ref, ok := index[table+"\x00"+name]
+		if !ok {
+			ref = &connection.ForeignKeyRef{Name: name, Table: table, RefTable: tableName}
+			index[table+"\x00"+name] = ref
+			refs = append(refs, ref)
+		}
+		ref.Columns = append(ref.Columns, stringOrEmpty(row["COLUMN_NAME"]))
+		ref.RefColumns = append(ref.RefColumns, stringOrEmpty(row["REFERENCED_COLUMN_NAME"]))
+	}
+	return refs, nil
+}
+
+// GroupForeignKeys 将 GetForeignKeys 返回的逐列外键按约束聚合，table 为定义外键的表。
+func GroupForeignKeys(table string, fks []*connection.ForeignKeyDefinition) []*connection.ForeignKeyRef {
+	var refs []*connection.ForeignKeyRef
+	index := make(map[string]*connection.ForeignKeyRef)
+	for _, fk := range fks {
+		ref, ok := index[fk.Name]
+		if !ok {
+			ref = &connection.ForeignKeyRef{Name: fk.Name, Table: table, RefTable: fk.RefTableName}
+			index[fk.Name] = ref
+			refs = append(refs, ref)
+		}
+		ref.Columns = append(ref.Columns, fk.ColumnName)
+		ref.RefColumns = append(ref.RefColumns, fk.RefColumnName)
+	}
+	return refs
+}
+
+// FindForeignKey 按约束名查找外键，不存在时返回 nil。
+func FindForeignKey(refs []*connection.ForeignKeyRef, name string) *connection.ForeignKeyRef {
+	for _, ref := range refs {
+		if ref.Name == name {
+			return ref
+		}
+	}
+	return nil
+}
+
+// BuildMatchRowsQuery 构造按列等值匹配读取整行的参数化查询，columns 与 values 按位置对应，
+// limit 大于 0 时追加 LIMIT。
+func BuildMatchRowsQuery(dbType connection.ConnectionType, table string, columns []string, values []interface{}, limit int) (string, []any) {
+	d := dialectFor(dbType)
+	conds := make([]string, len(columns))
+	args := make([]any, len(values))
+	for i, col := range columns {
+		args[i] = values[i]
+		conds[i] = d.quoteIdent(col) + " = " + d.placeholder(i+1)
+	}
+	query := "SELECT * FROM " + d.quoteTable(table) + " WHERE " + strings.Join(conds, " AND ")
+	if limit > 0 {
+		query += d.Limit(limit, false)
+	}
+	return query, args
+}
+
+// BuildMatchAnyRowsQuery 构造读取 columns 取值等于 tuples 中任一组的整行的参数化查询，每组取值与 columns 按位置对应。
+// 单列时使用 IN，复合列时以 OR 连接各组的等值条件；limit 大于 0 时追加 LIMIT。
+func BuildMatchAnyRowsQuery(dbType connection.ConnectionType, table string, columns []string, tuples [][]interface{}, limit int) (string, []any) {
+	d := dialectFor(dbType)
+	args := make([]any, 0, len(columns)*len(tuples))
+	var where string
+	if len(columns) == 1 {
+		marks := make([]string, len(tuples))
+		for i, tuple := range tuples {
+			args = append(args, tuple[0])
+			marks[i] = d.placeholder(len(args))
+		}
+		where = d.quoteIdent(columns[0]) + " IN (" + strings.Join(marks, ", ") + ")"
+	} else {
+		groups := make([]string, len(tuples))
+		for i, tuple := range tuples {
+			conds := make([]string, len(columns))
+			for j, col := range columns {
+				args = append(args, tuple[j])
+				conds[j] = d.quoteIdent(col) + " = " + d.placeholder(len(args))
+			}
+			groups[i] = "(" + strings.Join(conds, " AND ") + ")"
+		}
+		where = strings.Join(groups, " OR ")
+	}
+	query := "SELECT * FROM " + d.quoteTable(table) + " WHERE " + where
+	if limit > 0 {
+		query += d.Limit(limit, false)
+	}
+	return query, args
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestGroupForeignKeys(t *testing.T) {
+	fks := []*connection.ForeignKeyDefinition{
+		{Name: "fk_order_item", ColumnName: "order_id", RefTableName: "order_items", RefColumnName: "order_id"},
+		{Name: "fk_order_item", ColumnName: "line_no", RefTableName: "order_items", RefColumnName: "line_no"},
+		{Name: "fk_user", ColumnName: "user_id", RefTableName: "users", RefColumnName: "id"},
+	}
+	refs := GroupForeignKeys("shipments", fks)
+	if len(refs) != 2 {
+		t.Fatalf("len(refs) = %d, want 2", len(refs))
+	}
+	want := &connection.ForeignKeyRef{Name: "fk_order_item", Table: "shipments", Columns: []string{"order_id", "line_no"}, RefTable: "order_items", RefColumns: []string{"order_id", "line_no"}}
+	if !reflect.DeepEqual(refs[0], want) {
+		t.Errorf("refs[0] = %+v, want %+v", refs[0], want)
+	}
+	if FindForeignKey(refs, "fk_user") != refs[1] || FindForeignKey(refs, "missing") != nil {
+		t.Error("FindForeignKey 结果不符")
+	}
+}
+
+func TestBuildMatchRowsQuery(t *testing.T) {
+	query, args := BuildMatchRowsQuery(connection.ConnectionTypeMySQL, "order_items", []string{"order_id", "line_no"}, []interface{}{int64(7), int64(2)}, 2)
+	if query != "SELECT * FROM `order_items` WHERE `order_id` = ? AND `line_no` = ? LIMIT 2" {
+		t.Errorf("query = %s", query)
+	}
+	if !reflect.DeepEqual(args, []any{int64(7), int64(2)}) {
+		t.Errorf("args = %v", args)
+	}
+
+	query, _ = BuildMatchRowsQuery(connection.ConnectionTypePostgreSQL, "users", []string{"id"}, []interface{}{1}, 0)
+	if query != `SELECT * FROM "users" WHERE "id" = $1` {
+		t.Errorf("query = %s", query)
+	}
+}
+
+func TestBuildMatchAnyRowsQuery(t *testing.T) {
+	query, args := BuildMatchAnyRowsQuery(connection.ConnectionTypePostgreSQL, "orders", []string{"user_id"}, [][]interface{}{{int64(1)}, {int64(2)}}, 11)
+	if query != `SELECT * FROM "orders" WHERE "user_id" IN ($1, $2) LIMIT 11` {
+		t.Errorf("query = %s", query)
+	}
+	if !reflect.DeepEqual(args, []any{int64(1), int64(2)}) {
+		t.Errorf("args = %v", args)
+	}
+
+	query, args = BuildMatchAnyRowsQuery(connection.ConnectionTypeMySQL, "order_items", []string{"order_id", "line_no"}, [][]interface{}{{7, 1}, {7, 2}}, 0)
+	if query != "SELECT * FROM `order_items` WHERE (`order_id` = ? AND `line_no` = ?) OR (`order_id` = ? AND `line_no` = ?)" {
+		t.Errorf("query = %s", query)
+	}
+	if !reflect.DeepEqual(args, []any{7, 1, 7, 2}) {
+		t.Errorf("args = %v", args)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// defaultFunctionKeywords 是不带括号的 SQL 标准时间函数，作为默认值时属于表达式。
+var defaultFunctionKeywords = regexp.MustCompile(`(?i)^(current_timestamp|current_date|current_time|localtimestamp|localtime|sysdate|systimestamp)\b`)
+
+// defaultExpression 判断默认值是否为表达式：含函数调用或以时间函数关键字开头时原样返回，
+// 字面量（含 PostgreSQL 的 'abc'::text 形式）返回空字符串。
+func defaultExpression(def *string) string {
+	if def == nil {
+		return ""
+	}
+	text := strings.TrimSpace(*def)
+	if text == "" {
+		return ""
+	}
+	if strings.HasPrefix(text, "'") {
+		rest := text[strings.LastIndex(text, "'")+1:]
+		if rest == "" || strings.HasPrefix(rest, "::") {
+			return ""
+		}
+	}
+	if strings.Contains(text, "(") || defaultFunctionKeywords.MatchString(text) {
+		return text
+	}
+	return ""
+}
+
+// mysqlDefaultExpression 返回 MySQL 列的默认值表达式。SHOW COLUMNS 中字符串默认值不带引号，
+// 因此只认 Extra 的 DEFAULT_GENERATED 标记（8.0.13 起）与 5.7 的 CURRENT_TIMESTAMP 系列关键字。
+func mysqlDefaultExpression(def *string, extra string) string {
+	if def == nil {
+		return ""
+	}
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") || defaultFunctionKeywords.MatchString(strings.TrimSpace(*def)) {
+		return *def
+	}
+	return ""
+}
+
+// mysqlExtraGenerated 判断 SHOW COLUMNS 的 Extra 是否标记为生成列（VIRTUAL GENERATED / STORED GENERATED）。
+// MySQL 8.0 表达式默认值的 DEFAULT_GENERATED 不属于生成列。
+func mysqlExtraGenerated(extra string) bool {
+	upper := strings.ToUpper(extra)
+	return strings.Contains(upper, "VIRTUAL GENERATED") || strings.Contains(upper, "STORED GENERATED")
+}
+
+// SkipGeneratedColumns 返回去掉生成列的新变更集：插入行与更新值中的生成列被移除，定位条件保持不变。
+// 行的数量与顺序不变，只含生成列的更新或插入会变为空变更，由语句生成阶段跳过，部分提交报告的下标仍与原变更集对应。
+func SkipGeneratedColumns(columns []*connection.ColumnDefinition, changes *connection.ChangeSet) *connection.ChangeSet {
+	generated := make(map[string]bool)
+	for _, col := range columns {
+		if col.IsGenerated {
+			generated[col.Name] = true
+		}
+	}
+	if len(generated) == 0 {
+		return changes
+	}
+	strip := func(row map[string]interface{}) map[string]interface{} {
+		out := make(map[string]interface{}, len(row))
+		for col, v := range row {
+			if !generated[col] {
+				out[col] = v
+			}
+		}
+		return out
+	}
+
+	out := &connection.ChangeSet{Deletes: changes.Deletes}
+	for _, row := range changes.Inserts {
+		out.Inserts = append(out.Inserts, strip(row))
+	}
+	for _, update := range changes.Updates {
+		out.Updates = append(out.Updates, connection.UpdateRow{Keys: update.Keys, Values: strip(update.Values)})
+	}
+	return out
+}
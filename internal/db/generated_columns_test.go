@@ -0,0 +1,97 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestDefaultExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"nextval('orders_id_seq'::regclass)", true},
+		{"now()", true},
+		{"CURRENT_TIMESTAMP", true},
+		{"'abc'::text", false},
+		{"'a(b)'::character varying", false},
+		{"0", false},
+		{"true", false},
+	}
+	for _, tt := range tests {
+		def := tt.input
+		if got := defaultExpression(&def) != ""; got != tt.want {
+			t.Errorf("defaultExpression(%q) 是否表达式 = %v, 期望 %v", tt.input, got, tt.want)
+		}
+	}
+	if defaultExpression(nil) != "" {
+		t.Error("无默认值应返回空")
+	}
+}
+
+func TestMySQLGeneratedAndDefaultExpression(t *testing.T) {
+	if !mysqlExtraGenerated("VIRTUAL GENERATED") || !mysqlExtraGenerated("STORED GENERATED") {
+		t.Error("应识别虚拟与存储生成列")
+	}
+	if mysqlExtraGenerated("DEFAULT_GENERATED") || mysqlExtraGenerated("auto_increment") {
+		t.Error("表达式默认值与自增列不是生成列")
+	}
+
+	uuid, ts, literal := "uuid()", "CURRENT_TIMESTAMP(3)", "f(x)"
+	if mysqlDefaultExpression(&uuid, "DEFAULT_GENERATED") != "uuid()" {
+		t.Error("DEFAULT_GENERATED 的默认值应为表达式")
+	}
+	if mysqlDefaultExpression(&ts, "on update CURRENT_TIMESTAMP(3)") != ts {
+		t.Error("CURRENT_TIMESTAMP 应为表达式")
+	}
+	if mysqlDefaultExpression(&literal, "") != "" {
+		t.Error("SHOW COLUMNS 中不带引号的字符串默认值不应视为表达式")
+	}
+}
+
+func TestSkipGeneratedColumns(t *testing.T) {
+	columns := []*connection.ColumnDefinition{
+		{Name: "id"},
+		{Name: "price"},
+		{Name: "total", IsGenerated: true},
+	}
+	changes := &connection.ChangeSet{
+		Inserts: []map[string]interface{}{{"id": 1, "price": 2, "total": 4}, {"total": 6}},
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1, "total": 4}, Values: map[string]interface{}{"total": 8}}},
+		Deletes: []map[string]interface{}{{"id": 2}},
+	}
+
+	out := SkipGeneratedColumns(columns, changes)
+	if len(out.Inserts) != 2 || len(out.Inserts[0]) != 2 || out.Inserts[0]["total"] != nil || len(out.Inserts[1]) != 0 {
+		t.Errorf("插入行应去掉生成列并保留行顺序: %+v", out.Inserts)
+	}
+	if len(out.Updates[0].Values) != 0 || len(out.Updates[0].Keys) != 2 {
+		t.Errorf("更新值应去掉生成列，定位条件不变: %+v", out.Updates[0])
+	}
+	if len(out.Deletes) != 1 || len(changes.Inserts[0]) != 3 {
+		t.Error("删除应保持不变且不修改原变更集")
+	}
+
+	stmts, err := buildMySQLChangeStatements("orders", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 2 {
+		t.Errorf("只含生成列的插入与更新应被跳过，实际语句数 %d", len(stmts))
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// GenericSQLDB 是基于任意 database/sql 驱动的通用适配器，用于 "custom" 连接类型。
+// 查询与执行直接透传；元数据按 ANSI information_schema 尽力读取，读取不到的结构信息返回空列表。
+type GenericSQLDB struct {
+	driver      string
+	conn        *sql.DB
+	pingTimeout time.Duration
+}
+
+// NewGenericSQLDB 创建使用指定 database/sql 驱动的适配器。
+func NewGenericSQLDB(driver string) *GenericSQLDB {
+	return &GenericSQLDB{driver: driver}
+}
+
+// Connect 使用配置中的 DSN 打开连接并探活。
+func (g *GenericSQLDB) Connect(config *connection.ConnectionConfig) error {
+	if strings.TrimSpace(config.DSN) == "" {
+		return fmt.Errorf("自定义连接需要配置 DSN")
+	}
+	if netproxy.Enabled(config.Proxy) {
+		// 自定义驱动自行拨号，无法注入代理
+		return fmt.Errorf("自定义驱动 %s 不支持代理，请改用 SSH 隧道或驱动自身的代理参数", g.driver)
+	}
+	dsn, err := applyAuthDSN(g.driver, config.DSN, config)
+	if err != nil {
+		return err
+	}
+	conn, err := sql.Open(g.driver, dsn)
+	if err != nil {
+		return fmt.Errorf("打开数据库连接失败：%w", err)
+	}
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(mysqlMaxIdleConns)
+	conn.SetConnMaxIdleTime(5 * time.Minute)
+
+	g.conn = conn
+	g.pingTimeout = getConnectTimeout(config)
+	if err := g.Ping(); err != nil {
+		_ = g.Close()
+		return fmt.Errorf("连接建立后验证失败：%w", err)
+	}
+	return nil
+}
+
+// Close 关闭连接。
+func (g *GenericSQLDB) Close() error {
+	if g.conn == nil {
+		return nil
+	}
+	return g.conn.Close()
+}
+
+// Busy 判断连接池中是否有正在执行的查询。
+func (g *GenericSQLDB) Busy() bool {
+	return g.conn != nil && g.conn.Stats().InUse > 0
+}
+
+// PoolStats 返回连接池统计
+func (g *GenericSQLDB) PoolStats() sql.DBStats {
+	if g.conn == nil {
+		return sql.DBStats{}
+	}
+	return g.conn.Stats()
+}
+
+// Ping 验证连接是否可用。
+func (g *GenericSQLDB) Ping() error {
+	if g.conn == nil {
+		return fmt.Errorf("连接没有打开")
+	}
+	timeout := g.pingTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := utils.ContextWithTimeout(timeout)
+	defer cancel()
+	return g.conn.PingContext(ctx)
+}
+
+// QueryContext 执行查询并返回结果。
+func (g *GenericSQLDB) QueryContext(ctx context.Context, query string, args ...any) ([]map[string]interface{}, []string, error) {
+	if g.conn == nil {
+		return nil, nil, fmt.Errorf("连接没有打开")
+	}
+	rows, err := g.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	return scanRows(rows)
+}
+
+// QueryMulti 执行查询并读取全部结果集，驱动不支持多结果集时只返回第一个。
+func (g *GenericSQLDB) QueryMulti(ctx context.Context, query string, maxRows int, args ...any) ([]*connection.ResultSet, error) {
+	if g.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+	rows, err := g.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanResultSets(rows, maxRows)
+}
+
+// QueryStream 执行查询并返回流式行读取器，调用方负责关闭。
+func (g *GenericSQLDB) QueryStream(ctx context.Context, query string, args ...any) (*RowStream, error) {
+	if g.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+	rows, err := g.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return newRowStream(rows)
+}
+
+// Query 执行查询并返回结果。
+func (g *GenericSQLDB) Query(query string, args ...any) ([]map[string]interface{}, []string, error) {
+	return g.QueryContext(context.Background(), query, args...)
+}
+
+// ExecContext 执行语句并返回受影响行数（驱动不支持时为 0）。
+func (g *GenericSQLDB) ExecContext(ctx context.Context, query string, args ...any) (int64, error) {
+	if g.conn == nil {
+		return 0, fmt.Errorf("连接没有打开")
+	}
+	res, err := g.conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, nil
+	}
+	return affected, nil
+}
+
+// Exec 执行语句并返回受影响行数。
+func (g *GenericSQLDB) Exec(query string, args ...any) (int64, error) {
+	return g.ExecContext(context.Background(), query, args...)
+}
+
+// GetDatabases 通过 information_schema.schemata 读取 schema 列表。
+func (g *GenericSQLDB) GetDatabases(ctx context.Context) ([]string, error) {
+	return g.queryStrings(ctx, "SELECT schema_name FROM information_schema.schemata ORDER BY schema_name")
+}
+
+// GetSchemas 通过 information_schema.schemata 读取用户 schema，排除 information_schema 与 pg_ 开头的系统 schema。
+func (g *GenericSQLDB) GetSchemas(ctx context.Context) ([]string, error) {
+	return g.queryStrings(ctx, "SELECT schema_name FROM information_schema.schemata"+
+		" WHERE schema_name <> 'information_schema' AND schema_name NOT LIKE 'pg\\_%' ORDER BY schema_name")
+}
+
+// GetTables 通过 information_schema.tables 读取表列表，dbName 为空时不按 schema 过滤。
+func (g *GenericSQLDB) GetTables(ctx context.Context, dbName string) ([]string, error) {
+	query := "SELECT table_name FROM information_schema.tables"
+	if dbName != "" {
+		query += " WHERE table_schema = " + quotePgString(dbName)
+	}
+	return g.queryStrings(ctx, query+" ORDER BY table_name")
+}
+
+// GetCreateStatement 通用适配器无法生成建表语句。
+func (g *GenericSQLDB) GetCreateStatement(ctx context.Context, dbName, tableName string) (string, error) {
+	return "", fmt.Errorf("驱动 %s 不支持查看建表语句", g.driver)
+}
+
+// GetColumns 通过 information_schema.columns 读取列信息。
+// 读取全部列以兼容各数据库的扩展字段：存在 is_generated、generation_expression（如 PostgreSQL 12+）时识别生成列。
+func (g *GenericSQLDB) GetColumns(ctx context.Context, dbName, tableName string) ([]*connection.ColumnDefinition, error) {
+	query := "SELECT * FROM information_schema.columns WHERE table_name = " + quotePgString(tableName)
+	if dbName != "" {
+		query += " AND table_schema = " + quotePgString(dbName)
+	}
+	data, _, err := g.QueryContext(ctx, query+" ORDER BY ordinal_position")
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinition, 0, len(data))
+	for _, row := range data {
+		col := &connection.ColumnDefinition{
+			Name:     stringOrEmpty(lowerKey(row, "column_name")),
+			Type:     stringOrEmpty(lowerKey(row, "data_type")),
+			Nullable: stringOrEmpty(lowerKey(row, "is_nullable")),
+		}
+		if v := lowerKey(row, "column_default"); v != nil {
+			def := fmt.Sprintf("%v", v)
+			col.Default = &def
+		}
+		col.IsGenerated = strings.EqualFold(stringOrEmpty(lowerKey(row, "is_generated")), "ALWAYS")
+		if col.IsGenerated {
+			col.GenerationExpression = stringOrEmpty(lowerKey(row, "generation_expression"))
+		}
+		col.DefaultExpression = defaultExpression(col.Default)
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// GetAllColumns 通过 information_schema.columns 读取 schema 下所有列。
+func (g *GenericSQLDB) GetAllColumns(ctx context.Context, dbName string) ([]*connection.ColumnDefinitionWithTable, error) {
+	query := "SELECT table_name, column_name, data_type FROM information_schema.columns"
+	if dbName != "" {
+		query += " WHERE table_schema = " + quotePgString(dbName)
+	}
+	data, _, err := g.QueryContext(ctx, query+" ORDER BY table_name, ordinal_position")
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinitionWithTable, 0, len(data))
+	for _, row := range data {
+		columns = append(columns, &connection.ColumnDefinitionWithTable{
+			TableName: stringOrEmpty(lowerKey(row, "table_name")),
+			Name:      stringOrEmpty(lowerKey(row, "column_name")),
+			Type:      stringOrEmpty(lowerKey(row, "data_type")),
+		})
+	}
+	return columns, nil
+}
+
+// GetIndexes 通用适配器无法读取索引，返回空列表。
+func (g *GenericSQLDB) GetIndexes(ctx context.Context, dbName, tableName string) ([]*connection.IndexDefinition, error) {
+	return []*connection.IndexDefinition{}, nil
+}
+
+// GetForeignKeys 通用适配器无法读取外键，返回空列表。
+func (g *GenericSQLDB) GetForeignKeys(ctx context.Context, dbName, tableName string) ([]*connection.ForeignKeyDefinition, error) {
+	return []*connection.ForeignKeyDefinition{}, nil
+}
+
+// GetTriggers 通用适配器无法读取触发器，返回空列表。
+func (g *GenericSQLDB) GetTriggers(ctx context.Context, dbName, tableName string) ([]*connection.TriggerDefinition, error) {
+	return []*connection.TriggerDefinition{}, nil
+}
+
+// queryStrings 执行返回单列的查询并收集为字符串列表。
+func (g *GenericSQLDB) queryStrings(ctx context.Context, query string) ([]string, error) {
+	data, columns, err := g.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return []string{}, nil
+	}
+	list := make([]string, 0, len(data))
+	for _, row := range data {
+		list = append(list, stringOrEmpty(row[columns[0]]))
+	}
+	return list, nil
+}
+
+// lowerKey 按列名读取行值，兼容驱动返回大写列名的情况。
+func lowerKey(row map[string]interface{}, key string) interface{} {
+	if v, ok := row[key]; ok {
+		return v
+	}
+	return row[strings.ToUpper(key)]
+}
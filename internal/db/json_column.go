@@ -0,0 +1,451 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// JSON 列键路径采样的行数。
+const (
+	DefaultJSONSampleRows = 200
+	MaxJSONSampleRows     = 5000
+)
+
+const (
+	maxJSONKeyPaths = 1000 // 采样报告中键路径的上限
+	maxJSONDepth    = 32   // 展开 JSON 的最大深度
+)
+
+// jsonFlavor 是方言的 JSON 取值写法。
+type jsonFlavor int
+
+const (
+	jsonMySQL     jsonFlavor = iota // JSON_EXTRACT / JSON_UNQUOTE
+	jsonPostgres                    // #>> 运算符
+	jsonSQLServer                   // JSON_VALUE
+	jsonSQLite                      // json_extract
+)
+
+// jsonPathStep 是 JSON 路径的一级：对象键或数组下标。
+type jsonPathStep struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// parseJSONPath 解析 $.a.b[0]、a.b[0] 或 $."带空格的键" 形式的路径，不支持通配符。
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	s := strings.TrimSpace(path)
+	s = strings.TrimPrefix(s, "$")
+	var steps []jsonPathStep
+	first := true
+	for s != "" {
+		switch {
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON 路径缺少 ]: %s", path)
+			}
+			idx, err := strconv.Atoi(strings.TrimSpace(s[1:end]))
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("JSON 路径的数组下标无效: %s", s[:end+1])
+			}
+			steps = append(steps, jsonPathStep{index: idx})
+			s = s[end+1:]
+		case s[0] == '.' || first:
+			if s[0] == '.' {
+				s = s[1:]
+			}
+			if strings.HasPrefix(s, `"`) {
+				key, rest, err := readQuotedKey(s)
+				if err != nil {
+					return nil, fmt.Errorf("JSON 路径无效：%w", err)
+				}
+				steps = append(steps, jsonPathStep{key: key, isKey: true})
+				s = rest
+				break
+			}
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			key := s[:end]
+			if key == "" || key == "*" || strings.ContainsFunc(key, unicode.IsSpace) || strings.ContainsAny(key, `"'`) {
+				return nil, fmt.Errorf("JSON 路径的键无效: %q（包含特殊字符的键需加双引号）", key)
+			}
+			steps = append(steps, jsonPathStep{key: key, isKey: true})
+			s = s[end:]
+		default:
+			return nil, fmt.Errorf("JSON 路径无效: %s", path)
+		}
+		first = false
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("JSON 路径不能为空")
+	}
+	return steps, nil
+}
+
+// readQuotedKey 读取以双引号开头的键，支持 \" 与 \\ 转义，返回键与剩余部分。
+func readQuotedKey(s string) (string, string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 >= len(s) {
+				return "", "", errors.New("转义不完整")
+			}
+			i++
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", errors.New("键缺少结束引号")
+}
+
+// jsonPathLiteral 返回 SQL/JSON 路径的字符串字面量，键统一加双引号，如 '$."a"[0]'。
+// MySQL 字符串字面量中反斜杠是转义符，路径里转义键名的反斜杠需按 MySQL 规则再转义一次。
+func jsonPathLiteral(d sqlDialect, steps []jsonPathStep) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, st := range steps {
+		if st.isKey {
+			b.WriteString(`."`)
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(st.key))
+			b.WriteString(`"`)
+		} else {
+			b.WriteString("[" + strconv.Itoa(st.index) + "]")
+		}
+	}
+	if d.json == jsonMySQL {
+		return quoteMySQLString(b.String())
+	}
+	return "'" + strings.ReplaceAll(b.String(), "'", "''") + "'"
+}
+
+// pgPathLiteral 返回 PostgreSQL text[] 路径字面量，如 '{"a","0"}'。
+func pgPathLiteral(steps []jsonPathStep) string {
+	parts := make([]string, len(steps))
+	for i, st := range steps {
+		elem := strconv.Itoa(st.index)
+		if st.isKey {
+			elem = st.key
+		}
+		parts[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(elem) + `"`
+	}
+	return "'{" + strings.ReplaceAll(strings.Join(parts, ","), "'", "''") + "}'"
+}
+
+// jsonValueExpr 返回从 JSON 列取出路径值的表达式；numeric 为 true 时取数值（非数字为 NULL 或不参与比较），
+// 否则取文本（字符串去掉引号）。路径不存在时为 NULL。
+func jsonValueExpr(d sqlDialect, col string, steps []jsonPathStep, numeric bool) string {
+	switch d.json {
+	case jsonPostgres:
+		path := pgPathLiteral(steps)
+		if numeric {
+			return "CASE WHEN jsonb_typeof(" + col + "::jsonb #> " + path + ") = 'number' THEN (" + col + "::jsonb #>> " + path + ")::numeric END"
+		}
+		return "(" + col + "::jsonb #>> " + path + ")"
+	case jsonSQLServer:
+		expr := "JSON_VALUE(" + col + ", " + jsonPathLiteral(d, steps) + ")"
+		if numeric {
+			return "TRY_CAST(" + expr + " AS float)"
+		}
+		return expr
+	case jsonSQLite:
+		return "json_extract(" + col + ", " + jsonPathLiteral(d, steps) + ")"
+	default:
+		// MySQL 中 JSON 数值与数值参数按数值比较，JSON 字符串与数值比较为假
+		expr := "JSON_EXTRACT(" + col + ", " + jsonPathLiteral(d, steps) + ")"
+		if numeric {
+			return expr
+		}
+		return "JSON_UNQUOTE(" + expr + ")"
+	}
+}
+
+// jsonNullExpr 返回路径不存在或值为 JSON null 的判断；not 为 true 时取反。
+func jsonNullExpr(d sqlDialect, col string, steps []jsonPathStep, not bool) string {
+	if d.json == jsonMySQL {
+		// JSON_EXTRACT 对 JSON null 返回 JSON 'null' 而非 SQL NULL，需按类型判断
+		typ := "JSON_TYPE(JSON_EXTRACT(" + col + ", " + jsonPathLiteral(d, steps) + "))"
+		if not {
+			return typ + " <> 'NULL'"
+		}
+		return "COALESCE(" + typ + ", 'NULL') = 'NULL'"
+	}
+	expr := jsonValueExpr(d, col, steps, false)
+	if d.json == jsonSQLServer {
+		// JSON_VALUE 对对象与数组返回 NULL，需同时检查 JSON_QUERY
+		expr = "COALESCE(" + expr + ", JSON_QUERY(" + col + ", " + jsonPathLiteral(d, steps) + "))"
+	}
+	if not {
+		return expr + " IS NOT NULL"
+	}
+	return expr + " IS NULL"
+}
+
+// compileJSONFilter 编译带 JSON 路径的过滤条件：数值参数按数值比较，布尔值按方言的 JSON 文本形式比较。
+func compileJSONFilter(d sqlDialect, col string, f connection.TableFilter, n *int) (string, []any, error) {
+	steps, err := parseJSONPath(f.Path)
+	if err != nil {
+		return "", nil, err
+	}
+	switch f.Op {
+	case "isNull":
+		return jsonNullExpr(d, col, steps, false), nil, nil
+	case "notNull":
+		return jsonNullExpr(d, col, steps, true), nil, nil
+	}
+
+	values := f.Values
+	if f.Op != "in" && f.Op != "notIn" && f.Op != "between" {
+		if f.Value == nil {
+			return "", nil, fmt.Errorf("缺少比较值")
+		}
+		values = []any{f.Value}
+	}
+	numeric := len(values) > 0
+	for _, v := range values {
+		numeric = numeric && isJSONNumeric(d, v)
+	}
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = jsonFilterArg(d, v, numeric)
+	}
+	expr := jsonValueExpr(d, col, steps, numeric)
+	if strings.Contains(strings.ToLower(f.Op), "like") || f.Op == "contains" || f.Op == "startsWith" || f.Op == "endsWith" {
+		expr = jsonValueExpr(d, col, steps, false)
+		for i, v := range values {
+			args[i] = fmt.Sprint(v)
+		}
+	}
+	return compileTableFilterExpr(d, expr, connection.TableFilter{Op: f.Op, Value: firstOrNil(args), Values: args}, n)
+}
+
+// isJSONNumeric 判断比较值是否按数值比较；SQLite 的 json_extract 将布尔值返回为 0/1，同样按数值比较。
+func isJSONNumeric(d sqlDialect, v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
+	case bool:
+		return d.json == jsonSQLite
+	}
+	return false
+}
+
+// jsonFilterArg 转换比较值：文本比较时布尔值转为 true/false，其他非字符串值转为文本。
+func jsonFilterArg(d sqlDialect, v any, numeric bool) any {
+	if b, ok := v.(bool); ok {
+		if d.json == jsonSQLite {
+			if b {
+				return 1
+			}
+			return 0
+		}
+		return strconv.FormatBool(b)
+	}
+	if numeric {
+		return v
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func firstOrNil(values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// BuildFilterPredicate 将过滤条件（可带 JSON 路径）编译为方言对应的参数化条件，供 SQL 编辑器插入。
+func BuildFilterPredicate(dbType connection.ConnectionType, filters []connection.TableFilter, matchAny bool) (*connection.FilterPredicate, error) {
+	if len(filters) == 0 {
+		return nil, errors.New("过滤条件不能为空")
+	}
+	d := dialectFor(dbType)
+	n := 0
+	var (
+		clauses []string
+		args    []any
+	)
+	for i, f := range filters {
+		clause, fargs, err := compileTableFilter(d, f, &n)
+		if err != nil {
+			return nil, fmt.Errorf("过滤条件 %d（%s）无效：%w", i+1, f.Column, err)
+		}
+		clauses = append(clauses, clause)
+		args = append(args, fargs...)
+	}
+	sep := " AND "
+	if matchAny {
+		sep = " OR "
+	}
+	sql := strings.Join(clauses, sep)
+	if len(clauses) > 1 {
+		sql = "(" + sql + ")"
+	}
+	if args == nil {
+		args = []any{}
+	}
+	return &connection.FilterPredicate{SQL: sql, Args: args}, nil
+}
+
+// BuildJSONSampleQuery 构造读取 JSON 列最多 limit 个非空值的采样查询。
+func BuildJSONSampleQuery(dbType connection.ConnectionType, table, column string, limit int) string {
+	d := dialectFor(dbType)
+	col := d.quoteIdent(column)
+	return "SELECT " + col + " FROM " + d.quoteTable(table) + " WHERE " + col + " IS NOT NULL" + d.Limit(limit, false)
+}
+
+// AnalyzeJSONKeys 统计采样值中各键路径的出现行数与值类型；值可为 JSON 文本或字节，非法 JSON 计入 Invalid。
+func AnalyzeJSONKeys(column string, values []any) *connection.JSONKeyReport {
+	report := &connection.JSONKeyReport{Column: column, Paths: []connection.JSONKeyPath{}}
+	paths := make(map[string]*connection.JSONKeyPath)
+	for _, v := range values {
+		var raw []byte
+		switch t := v.(type) {
+		case nil:
+			continue
+		case []byte:
+			raw = t
+		case string:
+			raw = []byte(t)
+		default:
+			report.Invalid++
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(string(raw)))
+		dec.UseNumber()
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			report.Invalid++
+			continue
+		}
+		report.Sampled++
+
+		// 同一行内重复出现的路径与类型（如数组元素）只计一次
+		type pathType struct{ path, typ string }
+		seen := make(map[pathType]bool)
+		var order []pathType
+		walkJSON(doc, "$", 0, func(path, typ string) {
+			k := pathType{path, typ}
+			if !seen[k] {
+				seen[k] = true
+				order = append(order, k)
+			}
+		})
+		counted := make(map[string]bool)
+		for _, k := range order {
+			path, typ := k.path, k.typ
+			p, ok := paths[path]
+			if !ok {
+				if len(paths) >= maxJSONKeyPaths {
+					report.Truncated = true
+					continue
+				}
+				p = &connection.JSONKeyPath{Path: path, Types: map[string]int{}}
+				paths[path] = p
+			}
+			p.Types[typ]++
+			if !counted[path] {
+				counted[path] = true
+				p.Count++
+			}
+		}
+	}
+	for _, p := range paths {
+		if report.Sampled > 0 {
+			p.Frequency = float64(p.Count) / float64(report.Sampled)
+		}
+		report.Paths = append(report.Paths, *p)
+	}
+	sort.Slice(report.Paths, func(i, j int) bool {
+		if report.Paths[i].Count != report.Paths[j].Count {
+			return report.Paths[i].Count > report.Paths[j].Count
+		}
+		return report.Paths[i].Path < report.Paths[j].Path
+	})
+	return report
+}
+
+// walkJSON 深度优先遍历 JSON 值，对根以下的每个路径回调其类型；数组元素统一记为 [*]。
+func walkJSON(v any, path string, depth int, visit func(path, typ string)) {
+	if depth > maxJSONDepth {
+		return
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			p := path + "." + jsonPathKey(k)
+			visit(p, jsonTypeName(child))
+			walkJSON(child, p, depth+1, visit)
+		}
+	case []any:
+		p := path + "[*]"
+		for _, child := range t {
+			visit(p, jsonTypeName(child))
+			walkJSON(child, p, depth+1, visit)
+		}
+	}
+}
+
+// jsonPathKey 返回路径中的键，非简单标识符加双引号。
+func jsonPathKey(k string) string {
+	simple := k != ""
+	for i, r := range k {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			simple = false
+			break
+		}
+	}
+	if simple {
+		return k
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(k) + `"`
+}
+
+// jsonTypeName 返回 JSON 值的类型名。
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
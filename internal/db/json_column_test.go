@@ -0,0 +1,136 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	steps, err := parseJSONPath(`$.address."zip code"[2].x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []jsonPathStep{{key: "address", isKey: true}, {key: "zip code", isKey: true}, {index: 2}, {key: "x", isKey: true}}
+	if !reflect.DeepEqual(steps, want) {
+		t.Fatalf("steps = %+v", steps)
+	}
+	if bare, err := parseJSONPath("a.b"); err != nil || len(bare) != 2 {
+		t.Fatalf("应支持省略 $: %+v %v", bare, err)
+	}
+	for _, bad := range []string{"", "$", "$.a[*]", "$.a b", "$.a'b", `$."open`, "$.a[-1]"} {
+		if _, err := parseJSONPath(bad); err == nil {
+			t.Errorf("%q 应解析失败", bad)
+		}
+	}
+	steps = []jsonPathStep{{key: `it's "q"`, isKey: true}, {index: 0}}
+	if got := jsonPathLiteral(ansiDialect, steps); got != `'$."it''s \"q\""[0]'` {
+		t.Fatalf("jsonPathLiteral = %s", got)
+	}
+	if got := jsonPathLiteral(mysqlDialect, steps); got != `'$."it''s \\"q\\""[0]'` {
+		t.Fatalf("MySQL jsonPathLiteral = %s", got)
+	}
+}
+
+func TestCompileJSONFilterPerDialect(t *testing.T) {
+	cases := []struct {
+		d      sqlDialect
+		filter connection.TableFilter
+		sql    string
+		args   []any
+	}{
+		{mysqlDialect, connection.TableFilter{Column: "doc", Path: "$.user.name", Op: "eq", Value: "bob"},
+			"JSON_UNQUOTE(JSON_EXTRACT(`doc`, '$.\"user\".\"name\"')) = ?", []any{"bob"}},
+		{mysqlDialect, connection.TableFilter{Column: "doc", Path: "age", Op: "gt", Value: float64(30)},
+			"JSON_EXTRACT(`doc`, '$.\"age\"') > ?", []any{float64(30)}},
+		{mysqlDialect, connection.TableFilter{Column: "doc", Path: "deleted", Op: "isNull"},
+			"COALESCE(JSON_TYPE(JSON_EXTRACT(`doc`, '$.\"deleted\"')), 'NULL') = 'NULL'", nil},
+		{postgresDialect, connection.TableFilter{Column: "doc", Path: "$.tags[0]", Op: "contains", Value: "go"},
+			`("doc"::jsonb #>> '{"tags","0"}') LIKE $1`, []any{"%go%"}},
+		{postgresDialect, connection.TableFilter{Column: "doc", Path: "$.n", Op: "between", Values: []any{1, 5}},
+			`CASE WHEN jsonb_typeof("doc"::jsonb #> '{"n"}') = 'number' THEN ("doc"::jsonb #>> '{"n"}')::numeric END BETWEEN $1 AND $2`, []any{1, 5}},
+		{sqlServerDialect, connection.TableFilter{Column: "doc", Path: "$.active", Op: "eq", Value: true},
+			`JSON_VALUE([doc], '$."active"') = @p1`, []any{"true"}},
+		{ansiDialect, connection.TableFilter{Column: "doc", Path: "$.active", Op: "in", Values: []any{true, false}},
+			`json_extract("doc", '$."active"') IN (?, ?)`, []any{1, 0}},
+	}
+	for _, tc := range cases {
+		n := 0
+		sql, args, err := compileTableFilter(tc.d, tc.filter, &n)
+		if err != nil {
+			t.Fatalf("%+v: %v", tc.filter, err)
+		}
+		if sql != tc.sql || !reflect.DeepEqual(args, tc.args) {
+			t.Errorf("%+v:\n got %s %v\nwant %s %v", tc.filter, sql, args, tc.sql, tc.args)
+		}
+	}
+}
+
+func TestBuildFilterPredicate(t *testing.T) {
+	p, err := BuildFilterPredicate(connection.ConnectionTypePostgreSQL, []connection.TableFilter{
+		{Column: "id", Op: "gt", Value: 10},
+		{Column: "doc", Path: "$.kind", Op: "eq", Value: "a"},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SQL != `("id" > $1 OR ("doc"::jsonb #>> '{"kind"}') = $2)` || len(p.Args) != 2 {
+		t.Fatalf("predicate = %+v", p)
+	}
+	if _, err := BuildFilterPredicate(connection.ConnectionTypeMySQL, []connection.TableFilter{{Column: "doc", Path: "$.a[*]", Op: "eq", Value: 1}}, false); err == nil {
+		t.Fatal("通配符路径应返回错误")
+	}
+}
+
+func TestAnalyzeJSONKeys(t *testing.T) {
+	report := AnalyzeJSONKeys("doc", []any{
+		`{"name":"a","age":3,"tags":["x","y"],"addr":{"city":"c"}}`,
+		[]byte(`{"name":"b","age":"old","tags":[],"my key":null}`),
+		"not json",
+		nil,
+	})
+	if report.Sampled != 2 || report.Invalid != 1 || report.Truncated {
+		t.Fatalf("report = %+v", report)
+	}
+	byPath := map[string]connection.JSONKeyPath{}
+	for _, p := range report.Paths {
+		byPath[p.Path] = p
+	}
+	if p := byPath["$.age"]; p.Count != 2 || p.Frequency != 1 || p.Types["number"] != 1 || p.Types["string"] != 1 {
+		t.Fatalf("$.age = %+v", p)
+	}
+	if p := byPath["$.tags[*]"]; p.Count != 1 || p.Types["string"] != 1 {
+		t.Fatalf("数组元素在同一行只计一次: %+v", p)
+	}
+	if p := byPath[`$."my key"`]; p.Count != 1 || p.Types["null"] != 1 {
+		t.Fatalf("特殊键应加引号: %+v", byPath)
+	}
+	if p := byPath["$.addr.city"]; p.Frequency != 0.5 {
+		t.Fatalf("$.addr.city = %+v", p)
+	}
+	if report.Paths[0].Count < report.Paths[len(report.Paths)-1].Count {
+		t.Fatal("应按出现次数倒序")
+	}
+}
+
+func TestBuildJSONSampleQuery(t *testing.T) {
+	got := BuildJSONSampleQuery(connection.ConnectionTypeMySQL, "shop.orders", "doc", 50)
+	if got != "SELECT `doc` FROM `shop`.`orders` WHERE `doc` IS NOT NULL LIMIT 50" {
+		t.Fatalf("query = %s", got)
+	}
+}
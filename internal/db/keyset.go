@@ -0,0 +1,124 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// postgresDialect PostgreSQL 方言。
+var postgresDialect = sqlDialect{
+	quoteIdent:  quotePgIdent,
+	placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+	maxParams:   65535,
+	rowCompare:  true,
+	json:        jsonPostgres,
+}
+
+// dialectFor 返回数据库类型对应的方言；未指定类型时按 MySQL 处理，自定义驱动等未知类型使用 ANSI 方言。
+func dialectFor(dbType connection.ConnectionType) sqlDialect {
+	switch {
+	case IsPostgresDialect(dbType):
+		return postgresDialect
+	case dbType == connection.ConnectionTypeSQLServer:
+		return sqlServerDialect
+	case dbType == "", dbType == connection.ConnectionTypeMySQL, dbType == connection.ConnectionTypeMariaDB, dbType == connection.ConnectionTypeTDengine:
+		return mysqlDialect
+	default:
+		return ansiDialect
+	}
+}
+
+// IsPostgresDialect 判断数据库类型是否使用 PostgreSQL 方言（双引号标识符、$n 占位符）。
+func IsPostgresDialect(dbType connection.ConnectionType) bool {
+	switch dbType {
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		return true
+	default:
+		return false
+	}
+}
+
+// BuildKeysetPageQuery 构造按键分页读取表数据的参数化查询。
+//
+// keyColumns 非空时按键排序，after 为上一页最后一行的键值，生成 (k1, k2) > (?, ?) 条件，
+// 分页位置不受并发写入影响；keyColumns 为空时按全部列排序并使用 OFFSET 分页。
+func BuildKeysetPageQuery(dbType connection.ConnectionType, table string, columns, keyColumns []string, after []any, offset int64, limit int) (string, []any) {
+	d := dialectFor(dbType)
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(quoteList(columns, d.quoteIdent))
+	sb.WriteString(" FROM ")
+	sb.WriteString(d.quoteTable(table))
+
+	var args []any
+	order := keyColumns
+	if len(keyColumns) > 0 && len(after) == len(keyColumns) {
+		holders := make([]string, len(after))
+		for i := range after {
+			holders[i] = d.placeholder(i + 1)
+		}
+		sb.WriteString(" WHERE (")
+		sb.WriteString(quoteList(keyColumns, d.quoteIdent))
+		sb.WriteString(") > (")
+		sb.WriteString(strings.Join(holders, ", "))
+		sb.WriteString(")")
+		args = append(args, after...)
+	}
+	if len(order) == 0 {
+		order = columns
+	}
+	sb.WriteString(" ORDER BY ")
+	sb.WriteString(quoteList(order, d.quoteIdent))
+	if len(keyColumns) == 0 && offset > 0 {
+		sb.WriteString(d.LimitOffset(limit, offset, true))
+	} else {
+		sb.WriteString(d.Limit(limit, true))
+	}
+	return sb.String(), args
+}
+
+// BuildRowLookupQuery 构造按键读取单行中一列的参数化查询，键列按名称排序以保证语句稳定。
+// 值为 nil 的键列生成 IS NULL 条件；查询取 2 行，调用方据此判断键是否唯一定位到一行。
+func BuildRowLookupQuery(dbType connection.ConnectionType, table, column string, key map[string]interface{}) (string, []any) {
+	d := dialectFor(dbType)
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conds := make([]string, len(names))
+	var args []any
+	for i, name := range names {
+		if key[name] == nil {
+			conds[i] = d.quoteIdent(name) + " IS NULL"
+			continue
+		}
+		args = append(args, key[name])
+		conds[i] = d.quoteIdent(name) + " = " + d.placeholder(len(args))
+	}
+	return "SELECT " + d.quoteIdent(column) + " FROM " + d.quoteTable(table) +
+		" WHERE " + strings.Join(conds, " AND ") + d.Limit(2, false), args
+}
+
+// QuoteIdent 按数据库类型引用标识符。
+func QuoteIdent(dbType connection.ConnectionType, name string) string {
+	return dialectFor(dbType).quoteIdent(name)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// MultiResultQuerier 定义读取一次调用返回的全部结果集的能力（存储过程、多语句批处理）。
+type MultiResultQuerier interface {
+	QueryMulti(ctx context.Context, query string, maxRows int, args ...any) ([]*connection.ResultSet, error)
+}
+
+// scanResultSets 依次读取 rows 中的全部结果集。
+// 没有列的结果（批处理中的非查询语句、存储过程末尾的状态结果）被跳过；
+// maxRows > 0 时每个结果集只保留前 maxRows 行，其余行读取后丢弃以便前进到下一个结果集。
+func scanResultSets(rows *sql.Rows, maxRows int) ([]*connection.ResultSet, error) {
+	sets := make([]*connection.ResultSet, 0, 1)
+	for {
+		columns, err := rows.Columns()
+		if err != nil {
+			return sets, err
+		}
+		if len(columns) > 0 {
+			colTypes, err := rows.ColumnTypes()
+			if err != nil || len(colTypes) != len(columns) {
+				colTypes = nil
+			}
+			set := &connection.ResultSet{
+				Index:     len(sets),
+				Fields:    columns,
+				FieldMeta: fieldMetas(columns, colTypes),
+				Rows:      make([]map[string]interface{}, 0),
+			}
+			for rows.Next() {
+				if maxRows > 0 && len(set.Rows) >= maxRows {
+					set.Truncated = true
+					continue
+				}
+				entry, err := scanEntry(rows, columns, colTypes)
+				if err != nil {
+					return sets, fmt.Errorf("读取第 %d 个结果集失败：%w", set.Index+1, err)
+				}
+				set.Rows = append(set.Rows, entry)
+			}
+			if err := rows.Err(); err != nil {
+				return sets, err
+			}
+			sets = append(sets, set)
+		}
+		if !rows.NextResultSet() {
+			return sets, rows.Err()
+		}
+	}
+}
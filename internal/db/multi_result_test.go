@@ -0,0 +1,107 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// multiResultDriver 是返回固定多个结果集的 database/sql 驱动，第二个结果集没有列（模拟非查询语句）。
+type multiResultDriver struct{}
+
+type multiResultConn struct{}
+
+type multiResultRows struct {
+	sets [][][]driver.Value
+	cols [][]string
+	set  int
+	row  int
+}
+
+func (multiResultDriver) Open(string) (driver.Conn, error) { return multiResultConn{}, nil }
+
+func (multiResultConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (multiResultConn) Close() error                        { return nil }
+func (multiResultConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (multiResultConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &multiResultRows{
+		cols: [][]string{{"id", "name"}, {}, {"total"}},
+		sets: [][][]driver.Value{
+			{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}},
+			{},
+			{{int64(42)}},
+		},
+	}, nil
+}
+
+func (r *multiResultRows) Columns() []string { return r.cols[r.set] }
+func (r *multiResultRows) Close() error      { return nil }
+
+func (r *multiResultRows) Next(dest []driver.Value) error {
+	if r.row >= len(r.sets[r.set]) {
+		return io.EOF
+	}
+	copy(dest, r.sets[r.set][r.row])
+	r.row++
+	return nil
+}
+
+func (r *multiResultRows) HasNextResultSet() bool { return r.set+1 < len(r.sets) }
+
+func (r *multiResultRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.set, r.row = r.set+1, 0
+	return nil
+}
+
+func init() {
+	sql.Register("boxify-multi-result", multiResultDriver{})
+}
+
+func TestScanResultSets(t *testing.T) {
+	conn, err := sql.Open("boxify-multi-result", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(context.Background(), "CALL report()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	sets, err := scanResultSets(rows, 2)
+	if err != nil {
+		t.Fatalf("读取结果集失败: %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("应跳过无列结果，得到 %d 个结果集", len(sets))
+	}
+	if len(sets[0].Rows) != 2 || !sets[0].Truncated || sets[0].Rows[1]["name"] != "b" {
+		t.Fatalf("第一个结果集不符: %+v", sets[0])
+	}
+	if sets[1].Index != 1 || sets[1].Fields[0] != "total" || sets[1].Rows[0]["total"] != int64(42) || sets[1].Truncated {
+		t.Fatalf("第二个结果集不符: %+v", sets[1])
+	}
+}
@@ -17,26 +17,39 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/knownhosts"
 	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
 	"github.com/chenyang-zz/boxify/internal/ssh"
 	"github.com/chenyang-zz/boxify/internal/utils"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// mysqlMaxIdleConns 连接池最大空闲连接数
+const mysqlMaxIdleConns = 5
+
 // MySQLDB Database接口的MySQL实现
 type MySQLDB struct {
 	conn        *sql.DB
-	pintTimeout time.Duration // 可配置的Ping超时
+	pintTimeout time.Duration  // 可配置的Ping超时
+	schema      *schemaSession // 活动 schema（USE 语义）
+	sshNetwork  string         // SSH 隧道网络名，关闭连接时释放隧道引用
+
+	dsn       string     // 连接池使用的 DSN（不允许多语句）
+	multiMu   sync.Mutex // 保护 multiConn 的按需创建
+	multiConn *sql.DB    // 允许多语句的专用连接池，仅供 QueryMulti 使用
 }
 
-// getDSN 构建MySQL连接字符串，考虑SSH隧道
-func (m *MySQLDB) getDSN(config *connection.ConnectionConfig) string {
+// getDSN 构建MySQL连接字符串，考虑SSH隧道与直连代理；跳板机主机密钥未受信任时返回错误
+func (m *MySQLDB) getDSN(config *connection.ConnectionConfig) (string, error) {
 	database := config.Database
 	protocol := "tcp"
 	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
@@ -44,52 +57,105 @@ func (m *MySQLDB) getDSN(config *connection.ConnectionConfig) string {
 	// 重用app.go SSH中的SSH逻辑如果全局可用或复制逻辑，则执行
 	// 目前假设RegisterSSHNetwork是全局的
 	if config.UseSSH {
-		netName, err := ssh.RegisterSSHNetwork(config.SSH)
+		// 隧道建立与连接共用同一超时，避免跳板机无响应时长时间阻塞
+		ctx, cancel := context.WithTimeout(context.Background(), getConnectTimeout(config))
+		netName, err := ssh.RegisterSSHNetworkContext(ctx, config.SSH)
+		cancel()
+		var hostKeyErr *knownhosts.HostKeyError
 		if err == nil {
 			protocol = netName
+			m.sshNetwork = netName
 			address = fmt.Sprintf("%s:%d", config.Host, config.Port)
+		} else if errors.As(err, &hostKeyErr) {
+			// 主机密钥未受信任时不能退回直连，否则用户看不到确认提示
+			return "", err
 		} else {
 			logger.Warn("注册 SSH 网络失败，将尝试直连：地址=%s:%d 用户=%s，原因：%v", config.Host, config.Port, config.User, err)
 		}
+	} else if netproxy.Enabled(config.Proxy) {
+		protocol = mysqlProxyNetwork(config.Proxy)
 	}
 
 	// 获取连接超时时间
 	timeout := getConnectTimeoutSeconds(config)
 
-	return fmt.Sprintf("%s:%s@%s(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=%ds", config.User, config.Password, protocol, address, database, timeout)
+	return fmt.Sprintf("%s:%s@%s(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=%ds", config.User, config.Password, protocol, address, database, timeout), nil
 }
 
 // Connect建立数据库连接
 func (m *MySQLDB) Connect(config *connection.ConnectionConfig) error {
-	dsn := m.getDSN(config)
+	authParams, err := mysqlAuthParams(config)
+	if err != nil {
+		return err
+	}
+	tlsParam, err := mysqlTLSParam(config)
+	if err != nil {
+		return err
+	}
+	dsn, err := m.getDSN(config)
+	if err != nil {
+		return err
+	}
+	dsn += authParams + tlsParam
+	m.dsn = dsn
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return fmt.Errorf("打开数据库连接失败：%w", err)
 	}
 
 	// 配置连接池参数，防止连接数超限
-	db.SetMaxOpenConns(10)             // 最大打开连接数
-	db.SetMaxIdleConns(5)              // 最大空闲连接数
+	db.SetMaxOpenConns(10)                  // 最大打开连接数
+	db.SetMaxIdleConns(mysqlMaxIdleConns)   // 最大空闲连接数
 	db.SetConnMaxLifetime(30 * time.Minute) // 连接最大存活时间，防止长时间占用
 	db.SetConnMaxIdleTime(5 * time.Minute)  // 空闲连接超时时间
 
 	m.conn = db
 	m.pintTimeout = getConnectTimeout(config)
+	m.schema = newSchemaSession(config.Type, config.Database)
 
 	// 尝试Ping以验证连接
 	if err := m.Ping(); err != nil {
+		_ = m.Close()
 		return fmt.Errorf("连接建立后验证失败：%w", err)
 	}
 
 	return nil
 }
 
-// Close关闭数据库连接
+// Close关闭数据库连接并释放 SSH 隧道引用
 func (m *MySQLDB) Close() error {
+	var err error
 	if m.conn != nil {
-		return m.conn.Close()
+		err = m.conn.Close()
 	}
-	return nil
+	m.multiMu.Lock()
+	if m.multiConn != nil {
+		if multiErr := m.multiConn.Close(); multiErr != nil && err == nil {
+			err = multiErr
+		}
+		m.multiConn = nil
+	}
+	m.multiMu.Unlock()
+	if m.sshNetwork != "" {
+		if sshErr := ssh.CloseSSHNetwork(m.sshNetwork); sshErr != nil && err == nil {
+			err = sshErr
+		}
+		m.sshNetwork = ""
+	}
+	return err
+}
+
+// Busy 判断连接池中是否有正在执行的查询或未关闭的结果流
+func (m *MySQLDB) Busy() bool {
+	return m.conn != nil && m.conn.Stats().InUse > 0
+}
+
+// PoolStats 返回连接池统计
+func (m *MySQLDB) PoolStats() sql.DBStats {
+	if m.conn == nil {
+		return sql.DBStats{}
+	}
+	return m.conn.Stats()
 }
 
 // Ping验证数据库连接是否可用
@@ -115,7 +181,13 @@ func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...any) (
 		return nil, nil, fmt.Errorf("连接没有打开")
 	}
 
-	rows, err := m.conn.QueryContext(ctx, query, args...)
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	rows, err := session.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -124,42 +196,122 @@ func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...any) (
 	return scanRows(rows)
 }
 
-// Query 执行查询并返回结果
-func (m *MySQLDB) Query(query string, args ...any) ([]map[string]interface{}, []string, error) {
+// QueryMulti 执行查询并读取全部结果集，用于 CALL 存储过程与分号分隔的多语句批处理。
+// 多语句只在专用连接池上开启，普通查询的连接池不接受多语句，避免只读检查被后续语句绕过。
+func (m *MySQLDB) QueryMulti(ctx context.Context, query string, maxRows int, args ...any) ([]*connection.ResultSet, error) {
 	if m.conn == nil {
-		return nil, nil, fmt.Errorf("连接没有打开")
+		return nil, fmt.Errorf("连接没有打开")
 	}
 
-	rows, err := m.conn.Query(query, args...)
+	pool, err := m.multiStatementPool()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	var session sqlSession = pool
+	release := func() {}
+	if m.schema != nil {
+		session, release, err = m.schema.acquire(ctx, pool)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := session.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
-	return scanRows(rows)
+	return scanResultSets(rows, maxRows)
 }
 
-// ExecContext 执行带有上下文的命令并返回受影响的行数
-func (m *MySQLDB) ExecContext(ctx context.Context, query string, args ...any) (int64, error) {
+// multiStatementPool 返回允许多语句的专用连接池，首次调用时创建。
+func (m *MySQLDB) multiStatementPool() (*sql.DB, error) {
+	m.multiMu.Lock()
+	defer m.multiMu.Unlock()
+	if m.multiConn != nil {
+		return m.multiConn, nil
+	}
+	pool, err := sql.Open("mysql", m.dsn+"&multiStatements=true")
+	if err != nil {
+		return nil, fmt.Errorf("打开多语句连接失败：%w", err)
+	}
+	pool.SetMaxOpenConns(2)
+	pool.SetMaxIdleConns(1)
+	pool.SetConnMaxLifetime(30 * time.Minute)
+	pool.SetConnMaxIdleTime(5 * time.Minute)
+	m.multiConn = pool
+	return pool, nil
+}
+
+// QueryStream 执行查询并返回流式行读取器，调用方负责关闭
+func (m *MySQLDB) QueryStream(ctx context.Context, query string, args ...any) (*RowStream, error) {
 	if m.conn == nil {
-		return 0, fmt.Errorf("连接没有打开")
+		return nil, fmt.Errorf("连接没有打开")
 	}
 
-	res, err := m.conn.ExecContext(ctx, query, args...)
+	session, release, err := m.session(ctx)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return res.RowsAffected()
+	rows, err := session.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	stream, err := newRowStream(rows)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	stream.release = release
+	return stream, nil
 }
 
-// Exec 执行命令并返回受影响的行数
-func (m *MySQLDB) Exec(query string, args ...any) (int64, error) {
+// SetActiveSchema 设置活动数据库：此后池中每个会话执行语句前都会 USE 该库，空字符串恢复连接配置中的默认库
+func (m *MySQLDB) SetActiveSchema(schema string) error {
+	if m.conn == nil {
+		return fmt.Errorf("连接没有打开")
+	}
+	return m.schema.set(m.conn, schema, mysqlMaxIdleConns)
+}
+
+// ActiveSchema 返回当前活动数据库，未设置时为空
+func (m *MySQLDB) ActiveSchema() string {
+	if m.schema == nil {
+		return ""
+	}
+	return m.schema.current()
+}
+
+// session 返回执行语句使用的会话，已设置活动数据库时为切换过库的独立连接
+func (m *MySQLDB) session(ctx context.Context) (sqlSession, func(), error) {
+	if m.schema == nil {
+		return m.conn, func() {}, nil
+	}
+	return m.schema.acquire(ctx, m.conn)
+}
+
+// Query 执行查询并返回结果
+func (m *MySQLDB) Query(query string, args ...any) ([]map[string]interface{}, []string, error) {
+	return m.QueryContext(context.Background(), query, args...)
+}
+
+// ExecContext 执行带有上下文的命令并返回受影响的行数
+func (m *MySQLDB) ExecContext(ctx context.Context, query string, args ...any) (int64, error) {
 	if m.conn == nil {
 		return 0, fmt.Errorf("连接没有打开")
 	}
 
-	res, err := m.conn.Exec(query, args...)
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	res, err := session.ExecContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -167,9 +319,14 @@ func (m *MySQLDB) Exec(query string, args ...any) (int64, error) {
 	return res.RowsAffected()
 }
 
+// Exec 执行命令并返回受影响的行数
+func (m *MySQLDB) Exec(query string, args ...any) (int64, error) {
+	return m.ExecContext(context.Background(), query, args...)
+}
+
 // GetDatabases 返回数据库列表
-func (m *MySQLDB) GetDatabases() ([]string, error) {
-	data, _, err := m.Query("SHOW DATABASES")
+func (m *MySQLDB) GetDatabases(ctx context.Context) ([]string, error) {
+	data, _, err := m.QueryContext(ctx, "SHOW DATABASES")
 	if err != nil {
 		return nil, err
 	}
@@ -186,15 +343,15 @@ func (m *MySQLDB) GetDatabases() ([]string, error) {
 }
 
 // GetTables 返回指定数据库的表列表，如果dbName为空，则返回当前连接数据库的表
-func (m *MySQLDB) GetTables(dbName string) ([]string, error) {
+func (m *MySQLDB) GetTables(ctx context.Context, dbName string) ([]string, error) {
 	// MySQL连接通常绑定到一个数据库，但我们可能需要查询另一个数据库或只是SHOW TABLES
 	// 如果当前conn绑定到dbName，没问题。如果不是，SHOW TABLES FROM dbName
 	query := "SHOW TABLES"
 	if dbName != "" {
-		query = fmt.Sprintf("SHOW TABLES FROM `%s`", dbName)
+		query = "SHOW TABLES FROM " + quoteMySQLIdent(dbName)
 	}
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -212,14 +369,14 @@ func (m *MySQLDB) GetTables(dbName string) ([]string, error) {
 }
 
 // GetCreateStatement 返回指定表的创建语句
-func (m *MySQLDB) GetCreateStatement(dbName, tableName string) (string, error) {
-	query := fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`", dbName, tableName)
+func (m *MySQLDB) GetCreateStatement(ctx context.Context, dbName, tableName string) (string, error) {
+	query := "SHOW CREATE TABLE " + quoteMySQLIdent(dbName) + "." + quoteMySQLIdent(tableName)
 	// 如果dbName已被选中或为空，则只使用表名
 	if dbName == "" {
-		query = fmt.Sprintf("SHOW CREATE TABLE `%s`", tableName)
+		query = "SHOW CREATE TABLE " + quoteMySQLIdent(tableName)
 	}
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return "", err
 	}
@@ -234,13 +391,13 @@ func (m *MySQLDB) GetCreateStatement(dbName, tableName string) (string, error) {
 }
 
 // GetColumns 返回指定表的列定义
-func (m *MySQLDB) GetColumns(dbName, tableName string) ([]*connection.ColumnDefinition, error) {
-	query := fmt.Sprintf("SHOW FULL COLUMNS FROM `%s`.`%s`", dbName, tableName)
+func (m *MySQLDB) GetColumns(ctx context.Context, dbName, tableName string) ([]*connection.ColumnDefinition, error) {
+	query := "SHOW FULL COLUMNS FROM " + quoteMySQLIdent(dbName) + "." + quoteMySQLIdent(tableName)
 	if dbName == "" {
-		query = fmt.Sprintf("SHOW FULL COLUMNS FROM `%s`", tableName)
+		query = "SHOW FULL COLUMNS FROM " + quoteMySQLIdent(tableName)
 	}
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -260,17 +417,97 @@ func (m *MySQLDB) GetColumns(dbName, tableName string) ([]*connection.ColumnDefi
 			d := fmt.Sprintf("%v", row["Default"])
 			col.Default = &d
 		}
+		col.EnumValues = parseMySQLEnumValues(col.Type)
+		col.IsGenerated = mysqlExtraGenerated(col.Extra)
+		col.DefaultExpression = mysqlDefaultExpression(col.Default, col.Extra)
 
 		columns = append(columns, col)
 	}
 
+	if err := m.fillGenerationExpressions(ctx, dbName, tableName, columns); err != nil {
+		return nil, err
+	}
 	return columns, nil
 }
 
+// fillGenerationExpressions 为生成列补充计算表达式。SHOW COLUMNS 不返回表达式，
+// 仅当表中存在生成列时才额外查询 information_schema，避免普通表多一次往返。
+func (m *MySQLDB) fillGenerationExpressions(ctx context.Context, dbName, tableName string, columns []*connection.ColumnDefinition) error {
+	byName := make(map[string]*connection.ColumnDefinition)
+	for _, col := range columns {
+		if col.IsGenerated {
+			byName[col.Name] = col
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	schema := "DATABASE()"
+	if dbName != "" {
+		schema = quoteMySQLString(dbName)
+	}
+	query := "SELECT COLUMN_NAME, GENERATION_EXPRESSION FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = " + schema +
+		" AND TABLE_NAME = " + quoteMySQLString(tableName)
+	data, _, err := m.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, row := range data {
+		if col, ok := byName[fmt.Sprintf("%v", row["COLUMN_NAME"])]; ok {
+			col.GenerationExpression = stringOrEmpty(row["GENERATION_EXPRESSION"])
+		}
+	}
+	return nil
+}
+
+// parseMySQLEnumValues 从 enum('a','b') 或 set('a','b') 形式的列类型中解析可选值，
+// 其他类型返回 nil。值内的单引号可以是连续两个单引号或反斜杠转义。
+func parseMySQLEnumValues(colType string) []string {
+	lower := strings.ToLower(colType)
+	var body string
+	switch {
+	case strings.HasPrefix(lower, "enum(") && strings.HasSuffix(colType, ")"):
+		body = colType[len("enum(") : len(colType)-1]
+	case strings.HasPrefix(lower, "set(") && strings.HasSuffix(colType, ")"):
+		body = colType[len("set(") : len(colType)-1]
+	default:
+		return nil
+	}
+
+	values := []string{}
+	var cur strings.Builder
+	inQuote := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if !inQuote {
+			if c == '\'' {
+				inQuote = true
+				cur.Reset()
+			}
+			continue
+		}
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			cur.WriteByte(body[i])
+		case c == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			i++
+			cur.WriteByte('\'')
+		case c == '\'':
+			inQuote = false
+			values = append(values, cur.String())
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return values
+}
+
 // GetAllColumns 返回指定数据库的所有列定义
 // 包含表名以区分不同表的同名列
-func (m *MySQLDB) GetAllColumns(dbName string) ([]*connection.ColumnDefinitionWithTable, error) {
-	query := fmt.Sprintf("SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = '%s'", dbName)
+func (m *MySQLDB) GetAllColumns(ctx context.Context, dbName string) ([]*connection.ColumnDefinitionWithTable, error) {
+	query := "SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = " + quoteMySQLString(dbName)
 	if dbName == "" {
 		// 如果dbName为空，我们可能需要使用connection
 		// 但是information_schema通常需要一个模式过滤器，否则它返回所有
@@ -279,7 +516,7 @@ func (m *MySQLDB) GetAllColumns(dbName string) ([]*connection.ColumnDefinitionWi
 		return nil, fmt.Errorf("dbName必传")
 	}
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -298,38 +535,22 @@ func (m *MySQLDB) GetAllColumns(dbName string) ([]*connection.ColumnDefinitionWi
 }
 
 // GetIndexes 返回指定表的索引定义
-func (m *MySQLDB) GetIndexes(dbName, tableName string) ([]*connection.IndexDefinition, error) {
-	query := fmt.Sprintf("SHOW INDEX FROM `%s`.`%s`", dbName, tableName)
+func (m *MySQLDB) GetIndexes(ctx context.Context, dbName, tableName string) ([]*connection.IndexDefinition, error) {
+	query := "SHOW INDEX FROM " + quoteMySQLIdent(dbName) + "." + quoteMySQLIdent(tableName)
 	if dbName == "" {
-		query = fmt.Sprintf("SHOW INDEX FROM `%s`", tableName)
+		query = "SHOW INDEX FROM " + quoteMySQLIdent(tableName)
 	}
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	var indexs []*connection.IndexDefinition
 	for _, row := range data {
-		// 需要小心处理类型 Non_unique通常是int
-		nonUnique := 0
-		if val, ok := row["Non_unique"]; ok {
-			// 处理各种数字类型（json解码可能是float64）
-			if f, ok := val.(float64); ok {
-				nonUnique = int(f)
-			} else if i, ok := val.(int64); ok {
-				nonUnique = int(i)
-			}
-		}
-
-		seq := 0
-		if val, ok := row["Seq_in_index"]; ok {
-			if f, ok := val.(float64); ok {
-				seq = int(f)
-			} else if i, ok := val.(int64); ok {
-				seq = int(i)
-			}
-		}
+		// 文本协议下 Non_unique/Seq_in_index 以字符串返回，json 解码时可能是 float64
+		nonUnique := intValue(row["Non_unique"])
+		seq := intValue(row["Seq_in_index"])
 
 		idx := &connection.IndexDefinition{
 			Name:       fmt.Sprintf("%v", row["Key_name"]),
@@ -345,12 +566,13 @@ func (m *MySQLDB) GetIndexes(dbName, tableName string) ([]*connection.IndexDefin
 }
 
 // GetForeignKeys 返回指定表的外键定义
-func (m *MySQLDB) GetForeignKeys(dbName, tableName string) ([]*connection.ForeignKeyDefinition, error) {
+func (m *MySQLDB) GetForeignKeys(ctx context.Context, dbName, tableName string) ([]*connection.ForeignKeyDefinition, error) {
 	query := fmt.Sprintf(`SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME 
 	FROM information_schema.KEY_COLUMN_USAGE 
-	WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s' AND REFERENCED_TABLE_NAME IS NOT NULL`, dbName, tableName)
+	WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s AND REFERENCED_TABLE_NAME IS NOT NULL
+	ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`, quoteMySQLString(dbName), quoteMySQLString(tableName))
 
-	data, _, err := m.Query(query)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -370,9 +592,9 @@ func (m *MySQLDB) GetForeignKeys(dbName, tableName string) ([]*connection.Foreig
 }
 
 // GetTriggers 返回指定表的触发器定义
-func (m *MySQLDB) GetTriggers(dbName, tableName string) ([]*connection.TriggerDefinition, error) {
-	query := fmt.Sprintf("SHOW TRIGGERS FROM `%s` WHERE `Table` = '%s'", dbName, tableName)
-	data, _, err := m.Query(query)
+func (m *MySQLDB) GetTriggers(ctx context.Context, dbName, tableName string) ([]*connection.TriggerDefinition, error) {
+	query := "SHOW TRIGGERS FROM " + quoteMySQLIdent(dbName) + " WHERE `Table` = " + quoteMySQLString(tableName)
+	data, _, err := m.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -391,101 +613,152 @@ func (m *MySQLDB) GetTriggers(dbName, tableName string) ([]*connection.TriggerDe
 	return triggers, nil
 }
 
-// ApplyChanges 根据提供的ChangeSet对指定表应用批量更改（插入、更新、删除）
-func (m *MySQLDB) ApplyChanges(tableName string, changes *connection.ChangeSet) error {
+// GetTableData 按页读取表数据，排序与过滤条件编译为参数化查询
+func (m *MySQLDB) GetTableData(ctx context.Context, dbName, tableName string, opts *connection.TableDataOptions) (*connection.TableDataPage, error) {
 	if m.conn == nil {
-		return fmt.Errorf("连接没有打开")
+		return nil, fmt.Errorf("连接没有打开")
 	}
 
-	tx, err := m.conn.Begin()
+	columns, err := m.GetColumns(ctx, dbName, tableName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback() // 确保在出错时回滚
-
-	// 1， 删除
-	for _, pk := range changes.Deletes {
-		// 构建DELETE语句
-		var wheres []string
-		var args []interface{}
-		for k, v := range pk {
-			wheres = append(wheres, fmt.Sprintf("`%s` = ?", k))
-			args = append(args, v)
-		}
-		if len(wheres) == 0 {
-			continue
-		}
-		query := fmt.Sprintf("DELETE FROM `%s` WHERE %s", tableName, strings.Join(wheres, " AND "))
-		res, err := tx.Exec(query, args...)
-		if err != nil {
-			return fmt.Errorf("删除错误：%w", err)
-		}
-		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
-			return fmt.Errorf("删除未生效：未匹配到任何行")
-		}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("表不存在或没有列: %s", tableName)
+	}
+	table := quoteMySQLIdent(tableName)
+	if dbName != "" {
+		table = quoteMySQLIdent(dbName) + "." + table
+	}
+	q, err := buildTableDataQuery(mysqlDialect, table, columns, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. 更新
-	for _, update := range changes.Updates {
-		var sets []string
-		var args []interface{}
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-		for k, v := range update.Values {
-			sets = append(sets, fmt.Sprintf("`%s` = ?", k))
-			args = append(args, v)
+	page := &connection.TableDataPage{Page: q.page, PageSize: q.pageSize, Total: -1}
+	if q.countSQL != "" {
+		if err := session.QueryRowContext(ctx, q.countSQL, q.args...).Scan(&page.Total); err != nil {
+			return nil, err
 		}
+	}
+	rows, err := session.QueryContext(ctx, q.selectSQL, q.selectArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var last map[string]interface{}
+	page.Rows, page.Columns, last, err = scanTableDataRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	page.SeekColumns = q.seekColumns
+	page.NextAfter = nextSeekValues(q, len(page.Rows), last)
+	page.ComputedColumns = q.computed
+	return page, nil
+}
 
-		if len(sets) == 0 {
-			continue
-		}
+// InsertRows 在事务内以参数化多行 INSERT 批量插入数据，返回导入报告；progress 在每批结束后调用，可为 nil
+func (m *MySQLDB) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]any, opts *connection.ImportOptions, progress InsertProgressFunc) (*connection.ImportReport, error) {
+	if m.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return batchInsertTx(ctx, session, mysqlDialect, tableName, columns, rows, opts, progress)
+}
 
-		var wheres []string
-		for k, v := range update.Keys {
-			wheres = append(wheres, fmt.Sprintf("`%s` = ?", k))
-			args = append(args, v)
-		}
+// ApplyChanges 根据提供的ChangeSet对指定表应用批量更改（插入、更新、删除），任一变更失败时整体回滚
+func (m *MySQLDB) ApplyChanges(tableName string, changes *connection.ChangeSet) error {
+	if m.conn == nil {
+		return fmt.Errorf("连接没有打开")
+	}
+	stmts, err := buildMySQLChangeStatements(tableName, changes)
+	if err != nil {
+		return err
+	}
 
-		if len(wheres) == 0 {
-			return fmt.Errorf("更新缺少主键条件")
-		}
+	ctx := context.Background()
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-		query := fmt.Sprintf("UPDATE `%s` SET %s WHERE %s", tableName, strings.Join(sets, ", "), strings.Join(wheres, " AND "))
-		res, err := tx.Exec(query, args...)
-		if err != nil {
-			return fmt.Errorf("更新错误：%w", err)
-		}
-		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
-			return fmt.Errorf("更新未生效：未匹配到任何行")
-		}
+	tx, err := session.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback() // 确保在出错时回滚
 
-	// 3. 插入
-	for _, row := range changes.Inserts {
-		var cols []string
-		var placeholders []string
-		var args []interface{}
+	// 依次删除、更新、插入
+	if _, err := applyChangesTx(ctx, tx, stmts); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-		for k, v := range row {
-			cols = append(cols, fmt.Sprintf("`%s`", k))
-			placeholders = append(placeholders, "?")
-			args = append(args, v)
-		}
+// ApplyChangesWithImages 在同一事务内先锁定并读取被更新、删除行的原值，再应用更改；
+// partial 为 true 时以保存点逐个应用并报告失败项。原值读取失败不影响更改本身，原因记录在 ChangeImages.Err 中。
+func (m *MySQLDB) ApplyChangesWithImages(ctx context.Context, tableName string, changes *connection.ChangeSet, partial bool) (*connection.ApplyReport, *ChangeImages, error) {
+	if m.conn == nil {
+		return nil, nil, fmt.Errorf("连接没有打开")
+	}
+	stmts, err := buildMySQLChangeStatements(tableName, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
 
-		if len(cols) == 0 {
-			continue
-		}
+	tx, err := session.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("开启事务失败：%w", err)
+	}
+	defer tx.Rollback() // 提交后回滚为空操作
 
-		query := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)", tableName, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-		res, err := tx.Exec(query, args...)
-		if err != nil {
-			return fmt.Errorf("插入错误：%w", err)
-		}
-		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
-			return fmt.Errorf("插入未生效：未插入任何行")
-		}
+	images := readMySQLChangeImages(ctx, tx, tableName, changes)
+	apply := applyChangesTx
+	if partial {
+		apply = applySavepointChanges
 	}
+	report, err := apply(ctx, tx, stmts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("提交事务失败：%w", err)
+	}
+	report.Committed = true
+	return report, images, nil
+}
 
-	return tx.Commit()
+// ApplyChangesPartial 以保存点逐个应用更改，提交成功的变更并在报告中返回失败项
+func (m *MySQLDB) ApplyChangesPartial(ctx context.Context, tableName string, changes *connection.ChangeSet) (*connection.ApplyReport, error) {
+	if m.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+	stmts, err := buildMySQLChangeStatements(tableName, changes)
+	if err != nil {
+		return nil, err
+	}
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return applyChangesPartialTx(ctx, session, stmts)
 }
 
 // normalizeMySQLDateTimeValue 处理MySQL可能返回的日期时间字符串，修复常见格式问题并尝试解析为标准格式
@@ -569,3 +842,14 @@ func formatMySQLDateTime(t time.Time) string {
 	micro := nanos / 1000
 	return fmt.Sprintf("%s.%06d", base, micro)
 }
+
+// quoteMySQLIdent 使用反引号包裹标识符，并转义其中的反引号
+func quoteMySQLIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteMySQLString 将字符串转义为 MySQL 单引号字面量，用于不支持预处理参数的 SHOW/元数据语句
+func quoteMySQLString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `''`, "\x00", `\0`)
+	return "'" + replacer.Replace(value) + "'"
+}
@@ -17,6 +17,7 @@ package db
 import (
 	"context"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -309,7 +310,7 @@ func TestMySQLDB_GetDatabases(t *testing.T) {
 		return
 	}
 
-	databases, err := db.GetDatabases()
+	databases, err := db.GetDatabases(context.Background())
 	if err != nil {
 		t.Fatalf("获取数据库列表失败: %v", err)
 	}
@@ -350,7 +351,7 @@ func TestMySQLDB_GetTables(t *testing.T) {
 			testDB = "boxify_test"
 		}
 
-		tables, err := db.GetTables(testDB)
+		tables, err := db.GetTables(context.Background(), testDB)
 		if err != nil {
 			t.Fatalf("获取表列表失败: %v", err)
 		}
@@ -383,7 +384,7 @@ func TestMySQLDB_GetCreateStatement(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	sql, err := db.GetCreateStatement(testDB, "test_users")
+	sql, err := db.GetCreateStatement(context.Background(), testDB, "test_users")
 	if err != nil {
 		t.Fatalf("获取建表语句失败: %v", err)
 	}
@@ -415,7 +416,7 @@ func TestMySQLDB_GetColumns(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	columns, err := db.GetColumns(testDB, "test_users")
+	columns, err := db.GetColumns(context.Background(), testDB, "test_users")
 	if err != nil {
 		t.Fatalf("获取列信息失败: %v", err)
 	}
@@ -462,7 +463,7 @@ func TestMySQLDB_GetIndexes(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	indexes, err := db.GetIndexes(testDB, "test_users")
+	indexes, err := db.GetIndexes(context.Background(), testDB, "test_users")
 	if err != nil {
 		t.Fatalf("获取索引信息失败: %v", err)
 	}
@@ -501,7 +502,7 @@ func TestMySQLDB_GetForeignKeys(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	fks, err := db.GetForeignKeys(testDB, "test_orders")
+	fks, err := db.GetForeignKeys(context.Background(), testDB, "test_orders")
 	if err != nil {
 		t.Fatalf("获取外键信息失败: %v", err)
 	}
@@ -530,7 +531,7 @@ func TestMySQLDB_GetTriggers(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	triggers, err := db.GetTriggers(testDB, "test_users")
+	triggers, err := db.GetTriggers(context.Background(), testDB, "test_users")
 	if err != nil {
 		t.Fatalf("获取触发器信息失败: %v", err)
 	}
@@ -566,7 +567,7 @@ func TestMySQLDB_GetAllColumns(t *testing.T) {
 		testDB = "boxify_test"
 	}
 
-	columns, err := db.GetAllColumns(testDB)
+	columns, err := db.GetAllColumns(context.Background(), testDB)
 	if err != nil {
 		t.Fatalf("获取所有列信息失败: %v", err)
 	}
@@ -826,7 +827,10 @@ func TestMySQLDB_getDSN(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			db := &MySQLDB{}
-			dsn := db.getDSN(tt.config)
+			dsn, err := db.getDSN(tt.config)
+			if err != nil {
+				t.Fatalf("getDSN() error = %v", err)
+			}
 
 			for _, substr := range tt.contains {
 				if !strings.Contains(dsn, substr) {
@@ -1251,3 +1255,37 @@ func BenchmarkExec(b *testing.B) {
 	// 清理基准测试数据
 	_, _ = db.Exec("DELETE FROM test_users WHERE username = 'bench'")
 }
+
+// TestQuoteMySQLIdentAndString 测试标识符与字符串字面量转义
+func TestQuoteMySQLIdentAndString(t *testing.T) {
+	if got := quoteMySQLIdent("a`b"); got != "`a``b`" {
+		t.Errorf("标识符转义错误，得到 %s", got)
+	}
+	if got := quoteMySQLString(`x' OR '1'='1\`); got != `'x'' OR ''1''=''1\\'` {
+		t.Errorf("字符串转义错误，得到 %s", got)
+	}
+}
+
+// Test_parseMySQLEnumValues 测试 ENUM/SET 列类型的可选值解析
+func Test_parseMySQLEnumValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "枚举", input: "enum('small','medium','large')", expected: []string{"small", "medium", "large"}},
+		{name: "集合大写", input: "SET('a','b')", expected: []string{"a", "b"}},
+		{name: "转义单引号", input: `enum('it''s','a\'b','x,y')`, expected: []string{"it's", "a'b", "x,y"}},
+		{name: "空字符串值", input: "enum('','n')", expected: []string{"", "n"}},
+		{name: "非枚举类型", input: "varchar(32)", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseMySQLEnumValues(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseMySQLEnumValues(%q) = %q, 期望 %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// PartitionReader 定义表分区方案与各分区统计的读取能力。
+type PartitionReader interface {
+	GetPartitions(dbName, tableName string) (*connection.TablePartitioning, error)
+}
+
+const mysqlPartitionsSQL = `SELECT PARTITION_NAME, SUBPARTITION_NAME, PARTITION_ORDINAL_POSITION, PARTITION_METHOD, PARTITION_EXPRESSION,
+       PARTITION_DESCRIPTION, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH, PARTITION_COMMENT
+FROM information_schema.PARTITIONS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+ORDER BY PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION`
+
+// GetPartitions 读取 information_schema.PARTITIONS；未分区的表返回 Method 为空的方案。
+func (m *MySQLDB) GetPartitions(dbName, tableName string) (*connection.TablePartitioning, error) {
+	data, _, err := m.Query(mysqlPartitionsSQL, dbName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &connection.TablePartitioning{Table: tableName, Partitions: make([]*connection.PartitionInfo, 0, len(data))}
+	for i, row := range data {
+		method, bound := mysqlPartitionBound(stringOrEmpty(row["PARTITION_METHOD"]), stringOrEmpty(row["PARTITION_DESCRIPTION"]))
+		if i == 0 {
+			result.Method = method
+			result.Expression = strings.Trim(stringOrEmpty(row["PARTITION_EXPRESSION"]), "`")
+		}
+		result.Partitions = append(result.Partitions, &connection.PartitionInfo{
+			Name:         stringOrEmpty(row["PARTITION_NAME"]),
+			Subpartition: stringOrEmpty(row["SUBPARTITION_NAME"]),
+			Position:     int(utils.ToInt64(row["PARTITION_ORDINAL_POSITION"])),
+			Bound:        bound,
+			Rows:         utils.ToInt64(row["TABLE_ROWS"]),
+			DataSize:     utils.ToInt64(row["DATA_LENGTH"]),
+			IndexSize:    utils.ToInt64(row["INDEX_LENGTH"]),
+			Comment:      stringOrEmpty(row["PARTITION_COMMENT"]),
+		})
+	}
+	return result, nil
+}
+
+// mysqlPartitionBound 将 PARTITION_METHOD（RANGE COLUMNS、LINEAR KEY 等）归一为分区方式，并按方式还原边界子句。
+func mysqlPartitionBound(method, description string) (string, string) {
+	upper := strings.ToUpper(method)
+	switch {
+	case strings.HasPrefix(upper, "RANGE"):
+		return connection.PartitionMethodRange, "VALUES LESS THAN (" + description + ")"
+	case strings.HasPrefix(upper, "LIST"):
+		return connection.PartitionMethodList, "VALUES IN (" + description + ")"
+	case strings.Contains(upper, "HASH"), strings.Contains(upper, "KEY"):
+		return connection.PartitionMethodHash, ""
+	default:
+		return strings.ToLower(method), description
+	}
+}
+
+// pgPartitionsSQL 以父表为起点左连接子表，未建子分区的分区表也返回一行分区键信息；
+// 子分区同样可能是分区表，此处只列出直接子表。
+const pgPartitionsSQL = `SELECT pg_get_partkeydef(p.oid) AS partition_key,
+       COALESCE(c.relname, '') AS partition_name, COALESCE(cn.nspname, '') AS partition_schema,
+       COALESCE(pg_get_expr(c.relpartbound, c.oid), '') AS bound,
+       COALESCE(GREATEST(c.reltuples, 0), 0)::bigint AS row_estimate,
+       COALESCE(pg_table_size(c.oid), 0) AS data_size, COALESCE(pg_indexes_size(c.oid), 0) AS index_size,
+       COALESCE(obj_description(c.oid, 'pg_class'), '') AS comment
+FROM pg_class p
+JOIN pg_namespace n ON n.oid = p.relnamespace
+JOIN pg_partitioned_table pt ON pt.partrelid = p.oid
+LEFT JOIN pg_inherits i ON i.inhparent = p.oid
+LEFT JOIN pg_class c ON c.oid = i.inhrelid
+LEFT JOIN pg_namespace cn ON cn.oid = c.relnamespace
+WHERE n.nspname::text = COALESCE(NULLIF($1::text, ''), current_schema()) AND p.relname::text = $2::text
+ORDER BY c.relname`
+
+// GetPartitions 读取分区表的分区键与直接子分区；tableName 可带 schema 前缀，否则按 dbName 确定 schema
+func (p *PostgresDB) GetPartitions(dbName, tableName string) (*connection.TablePartitioning, error) {
+	schema, table := p.schemaFor(dbName), tableName
+	if q, err := ParseQualifiedName(tableName); err == nil {
+		table = q.Name
+		if q.Schema != "" {
+			schema = q.Schema
+		}
+	}
+	return queryPostgresPartitions(p.Query, schema, table)
+}
+
+// queryPostgresPartitions 读取分区表的分区键与直接子分区；未分区的表返回 Method 为空的方案。
+func queryPostgresPartitions(query queryFunc, schema, table string) (*connection.TablePartitioning, error) {
+	rows, _, err := query(pgPartitionsSQL, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &connection.TablePartitioning{Table: table, Partitions: make([]*connection.PartitionInfo, 0, len(rows))}
+	for _, row := range rows {
+		if result.Method == "" {
+			result.Method, result.Expression = parsePgPartitionKey(textValue(row["partition_key"]))
+		}
+		name := textValue(row["partition_name"])
+		if name == "" {
+			continue
+		}
+		result.Partitions = append(result.Partitions, &connection.PartitionInfo{
+			Name:      name,
+			Schema:    textValue(row["partition_schema"]),
+			Position:  len(result.Partitions) + 1,
+			Bound:     textValue(row["bound"]),
+			Rows:      utils.ToInt64(row["row_estimate"]),
+			DataSize:  utils.ToInt64(row["data_size"]),
+			IndexSize: utils.ToInt64(row["index_size"]),
+			Comment:   textValue(row["comment"]),
+		})
+	}
+	return result, nil
+}
+
+// parsePgPartitionKey 拆分 pg_get_partkeydef 的结果，如 "RANGE (created_at)" 返回 range 与 created_at。
+func parsePgPartitionKey(def string) (string, string) {
+	method, rest, ok := strings.Cut(strings.TrimSpace(def), " ")
+	if !ok {
+		return strings.ToLower(method), ""
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		rest = rest[1 : len(rest)-1]
+	}
+	return strings.ToLower(method), rest
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestMySQLPartitionBound(t *testing.T) {
+	tests := []struct {
+		method, description string
+		wantMethod, want    string
+	}{
+		{"RANGE COLUMNS", "'2024-01-01'", connection.PartitionMethodRange, "VALUES LESS THAN ('2024-01-01')"},
+		{"RANGE", "MAXVALUE", connection.PartitionMethodRange, "VALUES LESS THAN (MAXVALUE)"},
+		{"LIST", "1,2", connection.PartitionMethodList, "VALUES IN (1,2)"},
+		{"LINEAR KEY", "", connection.PartitionMethodHash, ""},
+	}
+	for _, tt := range tests {
+		method, bound := mysqlPartitionBound(tt.method, tt.description)
+		if method != tt.wantMethod || bound != tt.want {
+			t.Errorf("mysqlPartitionBound(%q) = %q %q, 期望 %q %q", tt.method, method, bound, tt.wantMethod, tt.want)
+		}
+	}
+}
+
+func TestQueryPostgresPartitions(t *testing.T) {
+	var gotArgs []any
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		gotArgs = args
+		return []map[string]interface{}{
+			{
+				"partition_key": "RANGE (created_at)", "partition_name": "events_2024_01", "partition_schema": "public",
+				"bound": "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')", "row_estimate": int64(1200),
+				"data_size": int64(8192), "index_size": int64(4096), "comment": "",
+			},
+			{
+				"partition_key": []byte("RANGE (created_at)"), "partition_name": []byte("events_default"), "partition_schema": "public",
+				"bound": "DEFAULT", "row_estimate": "0", "data_size": int64(0), "index_size": int64(0), "comment": "",
+			},
+		}, nil, nil
+	}
+
+	result, err := queryPostgresPartitions(query, "", "events")
+	if err != nil {
+		t.Fatalf("queryPostgresPartitions 失败: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[1] != "events" {
+		t.Errorf("查询参数不符: %v", gotArgs)
+	}
+	if result.Method != connection.PartitionMethodRange || result.Expression != "created_at" || len(result.Partitions) != 2 {
+		t.Fatalf("分区方案不符: %+v", result)
+	}
+	if p := result.Partitions[1]; p.Name != "events_default" || p.Position != 2 || p.Bound != "DEFAULT" {
+		t.Errorf("默认分区解析不符: %+v", p)
+	}
+}
+
+func TestQueryPostgresPartitionsWithoutChildren(t *testing.T) {
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return []map[string]interface{}{{"partition_key": "LIST (region)", "partition_name": ""}}, nil, nil
+	}
+	result, err := queryPostgresPartitions(query, "", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Method != connection.PartitionMethodList || result.Expression != "region" || len(result.Partitions) != 0 {
+		t.Errorf("无子分区的分区表解析不符: %+v", result)
+	}
+}
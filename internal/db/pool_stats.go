@@ -0,0 +1,69 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// PoolStats 是一个缓存连接的连接池统计，不含主机与凭据，可写入诊断包。
+type PoolStats struct {
+	Key            string                    `json:"key"` // 连接缓存标识（缩短）
+	Type           connection.ConnectionType `json:"type"`
+	Pooled         bool                      `json:"pooled"` // 驱动是否提供连接池统计
+	MaxOpen        int                       `json:"maxOpen"`
+	Open           int                       `json:"open"`
+	InUse          int                       `json:"inUse"`
+	Idle           int                       `json:"idle"`
+	WaitCount      int64                     `json:"waitCount"`
+	WaitDurationMs int64                     `json:"waitDurationMs"`
+	IdleSeconds    int64                     `json:"idleSeconds"` // 距最近一次使用的秒数
+}
+
+// poolStatter 由基于 database/sql 连接池的实现提供。
+type poolStatter interface {
+	PoolStats() sql.DBStats
+}
+
+// PoolStats 返回全部缓存连接的连接池统计，按缓存标识排序。
+func (m *ConnectionManager) PoolStats() []PoolStats {
+	now := time.Now()
+	m.mu.RLock()
+	list := make([]PoolStats, 0, len(m.cache))
+	for key, entry := range m.cache {
+		stats := PoolStats{Key: shortCacheKey(key), IdleSeconds: int64(now.Sub(entry.lastUsed).Seconds())}
+		if rec, ok := m.health[key]; ok {
+			stats.Type = rec.config.Type
+		}
+		if ps, ok := entry.inst.(poolStatter); ok {
+			s := ps.PoolStats()
+			stats.Pooled = true
+			stats.MaxOpen = s.MaxOpenConnections
+			stats.Open = s.OpenConnections
+			stats.InUse = s.InUse
+			stats.Idle = s.Idle
+			stats.WaitCount = s.WaitCount
+			stats.WaitDurationMs = s.WaitDuration.Milliseconds()
+		}
+		list = append(list, stats)
+	}
+	m.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
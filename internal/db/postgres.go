@@ -0,0 +1,604 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/knownhosts"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+	"github.com/chenyang-zz/boxify/internal/ssh"
+	"github.com/chenyang-zz/boxify/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+// postgresDriverName 是 lib/pq 注册的 database/sql 驱动名。
+const postgresDriverName = "postgres"
+
+// postgresDefaultPort 是 PostgreSQL 的默认端口。
+const postgresDefaultPort = 5432
+
+// PostgresDB 是 PostgreSQL 的实现：查询与执行复用通用适配器，元数据读取 pg_catalog。
+//
+// 服务层把侧边栏选中的数据库名作为 dbName 传入元数据方法，而 PostgreSQL 的元数据按 schema 组织，
+// 因此 dbName 为空或等于连接的数据库名时使用连接当前的 schema（current_schema()），其余值视为 schema 名。
+type PostgresDB struct {
+	*GenericSQLDB
+	database   string // 连接的数据库名
+	sshNetwork string // SSH 隧道网络名，关闭连接时释放隧道引用
+}
+
+// NewPostgresDB 创建 PostgreSQL 实例。
+func NewPostgresDB() *PostgresDB {
+	return &PostgresDB{GenericSQLDB: NewGenericSQLDB(postgresDriverName)}
+}
+
+// Connect 建立连接：SSH 隧道与直连代理通过自定义拨号器接入，TLS 模式映射为 sslmode，
+// preferred（及未配置 TLS，与 libpq 默认的 prefer 一致）在服务端不支持 SSL 时退回明文连接。
+func (p *PostgresDB) Connect(config *connection.ConnectionConfig) error {
+	if err := postgresAuthCheck(config); err != nil {
+		return err
+	}
+	sslParams, fallback, err := postgresSSLParams(config)
+	if err != nil {
+		return err
+	}
+	dialer, err := p.dialer(config)
+	if err != nil {
+		return err
+	}
+	p.database = config.Database
+	p.pingTimeout = getConnectTimeout(config)
+
+	err = p.open(postgresDSN(config, sslParams), dialer)
+	if err != nil && fallback && errors.Is(err, pq.ErrSSLNotSupported) {
+		err = p.open(postgresDSN(config, []dsnParam{{"sslmode", "disable"}}), dialer)
+	}
+	if err != nil {
+		_ = p.Close()
+		return fmt.Errorf("连接建立后验证失败：%w", err)
+	}
+	return nil
+}
+
+// open 使用 DSN 与拨号器打开连接池并探活，失败时关闭连接池。
+func (p *PostgresDB) open(dsn string, dialer *pgDialer) error {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return fmt.Errorf("解析连接参数失败：%w", err)
+	}
+	connector.Dialer(dialer)
+	conn := sql.OpenDB(connector)
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(mysqlMaxIdleConns)
+	conn.SetConnMaxLifetime(30 * time.Minute)
+	conn.SetConnMaxIdleTime(5 * time.Minute)
+
+	p.conn = conn
+	if err := p.Ping(); err != nil {
+		_ = conn.Close()
+		p.conn = nil
+		return err
+	}
+	return nil
+}
+
+// dialer 按配置创建拨号器：启用 SSH 时注册（或复用）隧道，隧道建立失败时退回直连，主机密钥未受信任时返回错误。
+func (p *PostgresDB) dialer(config *connection.ConnectionConfig) (*pgDialer, error) {
+	port := config.Port
+	if port <= 0 {
+		port = postgresDefaultPort
+	}
+	d := &pgDialer{addr: net.JoinHostPort(config.Host, strconv.Itoa(port))}
+	if config.UseSSH {
+		// 隧道建立与连接共用同一超时，避免跳板机无响应时长时间阻塞
+		ctx, cancel := context.WithTimeout(context.Background(), getConnectTimeout(config))
+		netName, err := ssh.RegisterSSHNetworkContext(ctx, config.SSH)
+		cancel()
+		var hostKeyErr *knownhosts.HostKeyError
+		if err == nil {
+			d.sshNetwork = netName
+			p.sshNetwork = netName
+		} else if errors.As(err, &hostKeyErr) {
+			// 主机密钥未受信任时不能退回直连，否则用户看不到确认提示
+			return nil, err
+		} else {
+			logger.Warn("注册 SSH 网络失败，将尝试直连：地址=%s 用户=%s，原因：%v", d.addr, config.User, err)
+		}
+	} else if netproxy.Enabled(config.Proxy) {
+		proxy := *config.Proxy
+		d.proxy = &proxy
+	}
+	return d, nil
+}
+
+// Close 关闭连接并释放 SSH 隧道引用。
+func (p *PostgresDB) Close() error {
+	err := p.GenericSQLDB.Close()
+	p.conn = nil
+	if p.sshNetwork != "" {
+		if sshErr := ssh.CloseSSHNetwork(p.sshNetwork); sshErr != nil && err == nil {
+			err = sshErr
+		}
+		p.sshNetwork = ""
+	}
+	return err
+}
+
+// pgCursorName 是分批读取时在独立事务中声明的游标名。
+const pgCursorName = "boxify_stream"
+
+// QueryStream 执行查询并返回流式行读取器，调用方负责关闭。
+// ctx 携带每批行数提示且语句为不带参数的单条查询时，在独立事务中声明服务端游标，按批 FETCH 读取；
+// 截断读取时回滚事务即可结束，不必像普通查询那样在关闭时读完剩余结果。其余语句按通用方式执行。
+func (p *PostgresDB) QueryStream(ctx context.Context, query string, args ...any) (*RowStream, error) {
+	n := FetchSize(ctx)
+	if n <= 0 || len(args) > 0 || !pgCursorQuery(query) {
+		return p.GenericSQLDB.QueryStream(ctx, query, args...)
+	}
+	if p.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+
+	tx, err := p.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "DECLARE "+pgCursorName+" NO SCROLL CURSOR FOR "+query); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	fetch := func() (*sql.Rows, error) {
+		return tx.QueryContext(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", n, pgCursorName))
+	}
+	rows, err := fetch()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	stream, err := newRowStream(rows)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	stream.refill = fetch
+	stream.release = func() { tx.Rollback() }
+	return stream, nil
+}
+
+// pgCursorQuery 判断语句能否用于 DECLARE CURSOR：只有单条 SELECT、VALUES、TABLE 查询可以，
+// 公共表表达式中含数据修改语句与加锁读取已归为数据修改，按通用方式执行。
+func pgCursorQuery(query string) bool {
+	c := sqllint.Classify(query, true)
+	if c.Kind != sqllint.StatementQuery || c.Multiple {
+		return false
+	}
+	switch c.Keyword {
+	case "SELECT", "VALUES", "TABLE":
+		return true
+	}
+	return false
+}
+
+// schemaFor 把元数据方法的 dbName 转换为 schema 参数，空字符串表示连接当前的 schema。
+func (p *PostgresDB) schemaFor(dbName string) string {
+	name := strings.TrimSpace(dbName)
+	if name == p.database {
+		return ""
+	}
+	return name
+}
+
+// GetDatabases 读取允许连接的非模板数据库。
+func (p *PostgresDB) GetDatabases(ctx context.Context) ([]string, error) {
+	return p.queryStrings(ctx, "SELECT datname FROM pg_database WHERE NOT datistemplate AND datallowconn ORDER BY datname")
+}
+
+// GetSchemas 读取用户 schema，排除 information_schema 与 pg_ 开头的系统 schema。
+func (p *PostgresDB) GetSchemas(ctx context.Context) ([]string, error) {
+	return p.queryStrings(ctx, `SELECT nspname FROM pg_namespace
+WHERE nspname <> 'information_schema' AND nspname NOT LIKE 'pg\_%' ORDER BY nspname`)
+}
+
+// pgTablesSQL 读取 schema 下的表、分区父表、视图、物化视图与外部表，不含分区子表。
+const pgTablesSQL = `SELECT c.relname
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema())
+  AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+ORDER BY c.relname`
+
+// GetTables 读取 schema 下的表与视图。
+func (p *PostgresDB) GetTables(ctx context.Context, dbName string) ([]string, error) {
+	data, _, err := p.QueryContext(ctx, pgTablesSQL, p.schemaFor(dbName))
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(data))
+	for _, row := range data {
+		tables = append(tables, stringOrEmpty(row["relname"]))
+	}
+	return tables, nil
+}
+
+// pgColumnsSQL 读取表的列；attidentity 需要 PostgreSQL 10，attgenerated 需要 PostgreSQL 12。
+const pgColumnsSQL = `SELECT a.attname AS column_name, format_type(a.atttypid, a.atttypmod) AS data_type,
+       a.attnotnull AS not_null, pg_get_expr(d.adbin, d.adrelid) AS column_default,
+       a.attidentity <> '' AS is_identity, a.attgenerated <> '' AS is_generated,
+       EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY (i.indkey)) AS is_primary,
+       COALESCE(col_description(c.oid, a.attnum), '') AS column_comment
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.relname = $2
+  AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+// GetColumns 读取列信息：主键列标记为 PRI，序列或标识列标记为 auto_increment，生成列的表达式不作为默认值返回。
+func (p *PostgresDB) GetColumns(ctx context.Context, dbName, tableName string) ([]*connection.ColumnDefinition, error) {
+	return p.queryColumns(ctx, p.schemaFor(dbName), tableName)
+}
+
+// queryColumns 读取 schema（为空时为当前 schema）下表的列。
+func (p *PostgresDB) queryColumns(ctx context.Context, schema, tableName string) ([]*connection.ColumnDefinition, error) {
+	data, _, err := p.QueryContext(ctx, pgColumnsSQL, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinition, 0, len(data))
+	for _, row := range data {
+		columns = append(columns, postgresColumn(row))
+	}
+	return columns, nil
+}
+
+// postgresColumn 把 pgColumnsSQL 的一行转换为列定义。
+func postgresColumn(row map[string]interface{}) *connection.ColumnDefinition {
+	col := &connection.ColumnDefinition{
+		Name:     stringOrEmpty(row["column_name"]),
+		Type:     stringOrEmpty(row["data_type"]),
+		Nullable: "YES",
+		Comment:  stringOrEmpty(row["column_comment"]),
+	}
+	if boolValue(row["not_null"]) {
+		col.Nullable = "NO"
+	}
+	if boolValue(row["is_primary"]) {
+		col.Key = "PRI"
+	}
+	def := row["column_default"]
+	if boolValue(row["is_generated"]) {
+		col.IsGenerated = true
+		col.GenerationExpression = stringOrEmpty(def)
+		return col
+	}
+	if def != nil {
+		text := stringOrEmpty(def)
+		col.Default = &text
+		if strings.HasPrefix(text, "nextval(") {
+			col.Extra = "auto_increment"
+		}
+	}
+	if boolValue(row["is_identity"]) {
+		col.Extra = "auto_increment"
+	}
+	col.DefaultExpression = defaultExpression(col.Default)
+	return col
+}
+
+// GetAllColumns 读取 schema 下全部表与视图的列。
+func (p *PostgresDB) GetAllColumns(ctx context.Context, dbName string) ([]*connection.ColumnDefinitionWithTable, error) {
+	data, _, err := p.QueryContext(ctx, `SELECT c.relname AS table_name, a.attname AS column_name, format_type(a.atttypid, a.atttypmod) AS data_type
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema())
+  AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+  AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY c.relname, a.attnum`, p.schemaFor(dbName))
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinitionWithTable, 0, len(data))
+	for _, row := range data {
+		columns = append(columns, &connection.ColumnDefinitionWithTable{
+			TableName: stringOrEmpty(row["table_name"]),
+			Name:      stringOrEmpty(row["column_name"]),
+			Type:      stringOrEmpty(row["data_type"]),
+		})
+	}
+	return columns, nil
+}
+
+// GetIndexes 按列展开读取索引，主键索引命名为 PRIMARY；表达式索引的表达式列不返回。
+func (p *PostgresDB) GetIndexes(ctx context.Context, dbName, tableName string) ([]*connection.IndexDefinition, error) {
+	data, _, err := p.QueryContext(ctx, `SELECT ic.relname AS index_name, a.attname AS column_name, k.ord AS seq_in_index,
+       i.indisunique AS is_unique, i.indisprimary AS is_primary, upper(am.amname) AS index_type
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_class ic ON ic.oid = i.indexrelid
+JOIN pg_am am ON am.oid = ic.relam
+CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.relname = $2
+ORDER BY i.indisprimary DESC, ic.relname, k.ord`, p.schemaFor(dbName), tableName)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]*connection.IndexDefinition, 0, len(data))
+	for _, row := range data {
+		idx := &connection.IndexDefinition{
+			Name:       stringOrEmpty(row["index_name"]),
+			ColumnName: stringOrEmpty(row["column_name"]),
+			SeqInIndex: int(utils.ToInt64(row["seq_in_index"])),
+			IndexType:  stringOrEmpty(row["index_type"]),
+		}
+		if !boolValue(row["is_unique"]) {
+			idx.NonUnique = 1
+		}
+		if boolValue(row["is_primary"]) {
+			idx.Name = "PRIMARY"
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// GetForeignKeys 按列展开读取外键；被引用表不在同一 schema 时带 schema 前缀。
+func (p *PostgresDB) GetForeignKeys(ctx context.Context, dbName, tableName string) ([]*connection.ForeignKeyDefinition, error) {
+	data, _, err := p.QueryContext(ctx, `SELECT con.conname, a.attname AS column_name, rn.nspname AS ref_schema, rc.relname AS ref_table,
+       ra.attname AS ref_column, rn.nspname = n.nspname AS same_schema
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_class rc ON rc.oid = con.confrelid
+JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, ord)
+JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+WHERE con.contype = 'f' AND n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.relname = $2
+ORDER BY con.conname, k.ord`, p.schemaFor(dbName), tableName)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*connection.ForeignKeyDefinition, 0, len(data))
+	for _, row := range data {
+		name := stringOrEmpty(row["conname"])
+		refTable := stringOrEmpty(row["ref_table"])
+		if !boolValue(row["same_schema"]) {
+			refTable = QualifiedName{Schema: stringOrEmpty(row["ref_schema"]), Name: refTable}.String()
+		}
+		keys = append(keys, &connection.ForeignKeyDefinition{
+			Name:          name,
+			ColumnName:    stringOrEmpty(row["column_name"]),
+			RefTableName:  refTable,
+			RefColumnName: stringOrEmpty(row["ref_column"]),
+			ConstrainName: name,
+		})
+	}
+	return keys, nil
+}
+
+// GetTriggers 通过 information_schema.triggers 读取触发器，多事件触发器按事件展开。
+func (p *PostgresDB) GetTriggers(ctx context.Context, dbName, tableName string) ([]*connection.TriggerDefinition, error) {
+	data, _, err := p.QueryContext(ctx, `SELECT trigger_name, action_timing, event_manipulation, action_statement
+FROM information_schema.triggers
+WHERE event_object_schema = COALESCE(NULLIF($1::text, ''), current_schema()) AND event_object_table = $2
+ORDER BY trigger_name, event_manipulation`, p.schemaFor(dbName), tableName)
+	if err != nil {
+		return nil, err
+	}
+	triggers := make([]*connection.TriggerDefinition, 0, len(data))
+	for _, row := range data {
+		triggers = append(triggers, &connection.TriggerDefinition{
+			Name:      stringOrEmpty(row["trigger_name"]),
+			Timing:    stringOrEmpty(row["action_timing"]),
+			Event:     stringOrEmpty(row["event_manipulation"]),
+			Statement: stringOrEmpty(row["action_statement"]),
+		})
+	}
+	return triggers, nil
+}
+
+// pgRelationSQL 读取对象的实际 schema、类型与视图定义（非视图时为空）。
+const pgRelationSQL = `SELECT n.nspname, c.relkind::text AS relkind,
+       CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) ELSE '' END AS viewdef
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.relname = $2`
+
+// pgConstraintsSQL 读取表约束的定义，按主键、唯一、检查、外键、排他的顺序。
+const pgConstraintsSQL = `SELECT con.conname, pg_get_constraintdef(con.oid, true) AS definition
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND con.contype IN ('p', 'u', 'c', 'f', 'x')
+ORDER BY CASE con.contype WHEN 'p' THEN 0 WHEN 'u' THEN 1 WHEN 'c' THEN 2 WHEN 'f' THEN 3 ELSE 4 END, con.conname`
+
+// pgIndexDefsSQL 读取不属于约束的索引定义（约束的索引已由约束子句创建）。
+const pgIndexDefsSQL = `SELECT pg_get_indexdef(i.indexrelid) AS definition
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_class ic ON ic.oid = i.indexrelid
+WHERE n.nspname = $1 AND c.relname = $2
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = i.indexrelid)
+ORDER BY ic.relname`
+
+// GetCreateStatement 由 pg_catalog 还原建表语句（列、约束与独立索引）；视图与物化视图返回建视图语句。
+func (p *PostgresDB) GetCreateStatement(ctx context.Context, dbName, tableName string) (string, error) {
+	rel, _, err := p.QueryContext(ctx, pgRelationSQL, p.schemaFor(dbName), tableName)
+	if err != nil {
+		return "", err
+	}
+	if len(rel) == 0 {
+		return "", fmt.Errorf("表不存在: %s", tableName)
+	}
+	schema := stringOrEmpty(rel[0]["nspname"])
+	name := qualified(schema, tableName, quotePgIdent)
+	switch stringOrEmpty(rel[0]["relkind"]) {
+	case "v":
+		return "CREATE VIEW " + name + " AS\n" + strings.TrimSpace(stringOrEmpty(rel[0]["viewdef"])), nil
+	case "m":
+		return "CREATE MATERIALIZED VIEW " + name + " AS\n" + strings.TrimSpace(stringOrEmpty(rel[0]["viewdef"])), nil
+	}
+
+	columns, err := p.queryColumns(ctx, schema, tableName)
+	if err != nil {
+		return "", err
+	}
+	conRows, _, err := p.QueryContext(ctx, pgConstraintsSQL, schema, tableName)
+	if err != nil {
+		return "", err
+	}
+	constraints := make([]string, 0, len(conRows))
+	for _, row := range conRows {
+		constraints = append(constraints, "CONSTRAINT "+quotePgIdent(stringOrEmpty(row["conname"]))+" "+stringOrEmpty(row["definition"]))
+	}
+	idxRows, _, err := p.QueryContext(ctx, pgIndexDefsSQL, schema, tableName)
+	if err != nil {
+		return "", err
+	}
+	indexes := make([]string, 0, len(idxRows))
+	for _, row := range idxRows {
+		indexes = append(indexes, stringOrEmpty(row["definition"]))
+	}
+	return postgresCreateTableStatement(name, columns, constraints, indexes), nil
+}
+
+// postgresCreateTableStatement 组装建表语句，name 为已引用的表名，独立索引以单独语句附在其后。
+func postgresCreateTableStatement(name string, columns []*connection.ColumnDefinition, constraints, indexes []string) string {
+	lines := make([]string, 0, len(columns)+len(constraints))
+	for _, col := range columns {
+		line := "  " + quotePgIdent(col.Name) + " " + col.Type
+		switch {
+		case col.IsGenerated:
+			line += " GENERATED ALWAYS AS (" + col.GenerationExpression + ") STORED"
+		case col.Default != nil:
+			line += " DEFAULT " + *col.Default
+		}
+		if col.Nullable == "NO" {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+	for _, c := range constraints {
+		lines = append(lines, "  "+c)
+	}
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE " + name + " (\n" + strings.Join(lines, ",\n") + "\n);")
+	for _, idx := range indexes {
+		sb.WriteString("\n" + idx + ";")
+	}
+	return sb.String()
+}
+
+// GetMaterializedViews 读取 schema 下的物化视图。
+func (p *PostgresDB) GetMaterializedViews(schema string) ([]*connection.MaterializedViewDefinition, error) {
+	return queryMaterializedViews(p.Query, schema, "")
+}
+
+// GetSequences 读取 schema 下的序列。
+func (p *PostgresDB) GetSequences(schema string) ([]*connection.SequenceDefinition, error) {
+	return querySequences(p.Query, schema, "")
+}
+
+// GetMaterializedViewCreateStatement 返回物化视图的建视图语句。
+func (p *PostgresDB) GetMaterializedViewCreateStatement(schema, name string) (string, error) {
+	views, err := queryMaterializedViews(p.Query, schema, name)
+	if err != nil {
+		return "", err
+	}
+	if len(views) == 0 {
+		return "", fmt.Errorf("物化视图不存在: %s", name)
+	}
+	return materializedViewCreateStatement(views[0]), nil
+}
+
+// GetSequenceCreateStatement 返回序列的建序列语句。
+func (p *PostgresDB) GetSequenceCreateStatement(schema, name string) (string, error) {
+	seqs, err := querySequences(p.Query, schema, name)
+	if err != nil {
+		return "", err
+	}
+	if len(seqs) == 0 {
+		return "", fmt.Errorf("序列不存在: %s", name)
+	}
+	return sequenceCreateStatement(seqs[0]), nil
+}
+
+// postgresDSN 构建 lib/pq 的 key=value 连接串，值一律加单引号并转义。
+// 设置了证书主机名时 host 使用该名称：host 只用于证书校验与 SNI，实际地址由拨号器决定。
+func postgresDSN(config *connection.ConnectionConfig, extra []dsnParam) string {
+	host := config.Host
+	if config.TLS != nil && strings.TrimSpace(config.TLS.ServerName) != "" {
+		host = strings.TrimSpace(config.TLS.ServerName)
+	}
+	params := []dsnParam{
+		{"host", host},
+		{"user", config.User},
+		{"password", config.Password},
+		{"dbname", config.Database},
+		{"connect_timeout", strconv.Itoa(getConnectTimeoutSeconds(config))},
+	}
+	params = append(params, extra...)
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		parts = append(parts, p.key+"='"+strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(p.value)+"'")
+	}
+	return strings.Join(parts, " ")
+}
+
+// pgDialer 为 lib/pq 拨号：始终连接配置中的地址，按配置经 SSH 隧道或直连代理。
+type pgDialer struct {
+	addr       string
+	sshNetwork string
+	proxy      *connection.ProxyConfig
+}
+
+// Dial 拨号，不设超时。
+func (d *pgDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialTimeout 在 timeout 内拨号。
+func (d *pgDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.DialContext(ctx, network, address)
+}
+
+// DialContext 拨号配置中的地址，忽略驱动按 DSN 中 host 计算的地址。
+func (d *pgDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	switch {
+	case d.sshNetwork != "":
+		return ssh.DialNetwork(ctx, d.sshNetwork, d.addr)
+	case d.proxy != nil:
+		return netproxy.Dial(ctx, d.proxy, d.addr)
+	}
+	var nd net.Dialer
+	return nd.DialContext(ctx, "tcp", d.addr)
+}
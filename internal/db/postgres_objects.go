@@ -0,0 +1,143 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// PostgresObjectReader 定义 PostgreSQL 物化视图与序列的读取能力。
+//
+// schema 为空时使用连接当前的 schema（current_schema()）。
+type PostgresObjectReader interface {
+	GetMaterializedViews(schema string) ([]*connection.MaterializedViewDefinition, error)
+	GetSequences(schema string) ([]*connection.SequenceDefinition, error)
+	GetMaterializedViewCreateStatement(schema, name string) (string, error)
+	GetSequenceCreateStatement(schema, name string) (string, error)
+}
+
+// queryFunc 与 Database.Query 签名一致，便于驱动直接传入方法值。
+type queryFunc func(query string, args ...any) ([]map[string]interface{}, []string, error)
+
+const pgMaterializedViewsSQL = `SELECT schemaname, matviewname, matviewowner, ispopulated, hasindexes, definition
+FROM pg_matviews
+WHERE schemaname::text = COALESCE(NULLIF($1::text, ''), current_schema())
+  AND ($2::text = '' OR matviewname::text = $2::text)
+ORDER BY matviewname`
+
+// pgSequencesSQL 依赖 PostgreSQL 10 起提供的 pg_sequences；last_value 在未调用 nextval 时为 NULL。
+const pgSequencesSQL = `SELECT s.schemaname, s.sequencename, s.data_type::text AS data_type,
+       s.start_value, s.min_value, s.max_value, s.increment_by, s.cache_size, s.cycle, s.last_value,
+       COALESCE(o.owned_by, '') AS owned_by
+FROM pg_sequences s
+LEFT JOIN (
+    SELECT d.objid, quote_ident(n.nspname) || '.' || quote_ident(c.relname) || '.' || quote_ident(a.attname) AS owned_by
+    FROM pg_depend d
+    JOIN pg_class c ON c.oid = d.refobjid
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+    WHERE d.classid = 'pg_class'::regclass AND d.deptype IN ('a', 'i')
+) o ON o.objid = (quote_ident(s.schemaname) || '.' || quote_ident(s.sequencename))::regclass
+WHERE s.schemaname::text = COALESCE(NULLIF($1::text, ''), current_schema())
+  AND ($2::text = '' OR s.sequencename::text = $2::text)
+ORDER BY s.sequencename`
+
+// queryMaterializedViews 读取 schema 下的物化视图，name 非空时只返回同名视图。
+func queryMaterializedViews(query queryFunc, schema, name string) ([]*connection.MaterializedViewDefinition, error) {
+	rows, _, err := query(pgMaterializedViewsSQL, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*connection.MaterializedViewDefinition, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, &connection.MaterializedViewDefinition{
+			Schema:     fmt.Sprint(row["schemaname"]),
+			Name:       fmt.Sprint(row["matviewname"]),
+			Owner:      fmt.Sprint(row["matviewowner"]),
+			Populated:  boolValue(row["ispopulated"]),
+			HasIndexes: boolValue(row["hasindexes"]),
+			Definition: fmt.Sprint(row["definition"]),
+		})
+	}
+	return views, nil
+}
+
+// querySequences 读取 schema 下的序列，name 非空时只返回同名序列。
+func querySequences(query queryFunc, schema, name string) ([]*connection.SequenceDefinition, error) {
+	rows, _, err := query(pgSequencesSQL, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	seqs := make([]*connection.SequenceDefinition, 0, len(rows))
+	for _, row := range rows {
+		seq := &connection.SequenceDefinition{
+			Schema:     fmt.Sprint(row["schemaname"]),
+			Name:       fmt.Sprint(row["sequencename"]),
+			DataType:   fmt.Sprint(row["data_type"]),
+			StartValue: utils.ToInt64(row["start_value"]),
+			MinValue:   utils.ToInt64(row["min_value"]),
+			MaxValue:   utils.ToInt64(row["max_value"]),
+			Increment:  utils.ToInt64(row["increment_by"]),
+			CacheSize:  utils.ToInt64(row["cache_size"]),
+			Cycle:      boolValue(row["cycle"]),
+			OwnedBy:    fmt.Sprint(row["owned_by"]),
+		}
+		if v, ok := row["last_value"]; ok && v != nil {
+			last := utils.ToInt64(v)
+			seq.LastValue = &last
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil
+}
+
+// materializedViewCreateStatement 还原物化视图的建视图语句，未填充的视图保留 WITH NO DATA。
+func materializedViewCreateStatement(view *connection.MaterializedViewDefinition) string {
+	body := strings.TrimRight(strings.TrimSpace(view.Definition), ";")
+	stmt := "CREATE MATERIALIZED VIEW " + qualified(view.Schema, view.Name, quotePgIdent) + " AS\n" + body
+	if view.Populated {
+		return stmt + "\nWITH DATA;"
+	}
+	return stmt + "\nWITH NO DATA;"
+}
+
+// sequenceCreateStatement 还原序列的建序列语句；序列归属于表列时追加 OWNED BY。
+func sequenceCreateStatement(seq *connection.SequenceDefinition) string {
+	var sb strings.Builder
+	sb.WriteString("CREATE SEQUENCE " + qualified(seq.Schema, seq.Name, quotePgIdent))
+	if seq.DataType != "" {
+		sb.WriteString("\n  AS " + seq.DataType)
+	}
+	sb.WriteString("\n  INCREMENT BY " + strconv.FormatInt(seq.Increment, 10))
+	sb.WriteString("\n  MINVALUE " + strconv.FormatInt(seq.MinValue, 10))
+	sb.WriteString("\n  MAXVALUE " + strconv.FormatInt(seq.MaxValue, 10))
+	sb.WriteString("\n  START WITH " + strconv.FormatInt(seq.StartValue, 10))
+	sb.WriteString("\n  CACHE " + strconv.FormatInt(seq.CacheSize, 10))
+	if seq.Cycle {
+		sb.WriteString("\n  CYCLE")
+	} else {
+		sb.WriteString("\n  NO CYCLE")
+	}
+	if seq.OwnedBy != "" {
+		sb.WriteString("\n  OWNED BY " + seq.OwnedBy)
+	}
+	sb.WriteString(";")
+	return sb.String()
+}
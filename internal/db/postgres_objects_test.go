@@ -0,0 +1,72 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuerySequencesAndCreateStatement(t *testing.T) {
+	var gotArgs []any
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		gotArgs = args
+		return []map[string]interface{}{{
+			"schemaname": "public", "sequencename": "orders_id_seq", "data_type": "bigint",
+			"start_value": int64(1), "min_value": int64(1), "max_value": "9223372036854775807",
+			"increment_by": int64(1), "cache_size": int64(1), "cycle": false, "last_value": nil,
+			"owned_by": `public.orders.id`,
+		}}, nil, nil
+	}
+
+	seqs, err := querySequences(query, "", "orders_id_seq")
+	if err != nil {
+		t.Fatalf("querySequences 失败: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != "" || gotArgs[1] != "orders_id_seq" {
+		t.Errorf("查询参数不符: %v", gotArgs)
+	}
+	if len(seqs) != 1 || seqs[0].MaxValue != 9223372036854775807 || seqs[0].LastValue != nil {
+		t.Fatalf("序列解析不符: %+v", seqs[0])
+	}
+
+	stmt := sequenceCreateStatement(seqs[0])
+	for _, want := range []string{`CREATE SEQUENCE "public"."orders_id_seq"`, "AS bigint", "NO CYCLE", "OWNED BY public.orders.id;"} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("建序列语句缺少 %q:\n%s", want, stmt)
+		}
+	}
+}
+
+func TestQueryMaterializedViewsAndCreateStatement(t *testing.T) {
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return []map[string]interface{}{{
+			"schemaname": "report", "matviewname": "daily_sales", "matviewowner": "app",
+			"ispopulated": []byte("f"), "hasindexes": true, "definition": " SELECT 1 AS n;",
+		}}, nil, nil
+	}
+
+	views, err := queryMaterializedViews(query, "report", "")
+	if err != nil {
+		t.Fatalf("queryMaterializedViews 失败: %v", err)
+	}
+	if len(views) != 1 || views[0].Populated || !views[0].HasIndexes {
+		t.Fatalf("物化视图解析不符: %+v", views[0])
+	}
+	want := "CREATE MATERIALIZED VIEW \"report\".\"daily_sales\" AS\nSELECT 1 AS n\nWITH NO DATA;"
+	if got := materializedViewCreateStatement(views[0]); got != want {
+		t.Errorf("建视图语句不符:\n%s", got)
+	}
+}
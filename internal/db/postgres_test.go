@@ -0,0 +1,292 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+
+	"github.com/lib/pq"
+)
+
+// fakePostgresServer 模拟不支持 SSL 的 PostgreSQL 服务端：拒绝 SSLRequest，对启动消息回复认证失败。
+func fakePostgresServer(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				for {
+					header := make([]byte, 8)
+					if _, err := io.ReadFull(conn, header); err != nil {
+						return
+					}
+					body := make([]byte, int(binary.BigEndian.Uint32(header[:4]))-8)
+					if _, err := io.ReadFull(conn, body); err != nil {
+						return
+					}
+					if binary.BigEndian.Uint32(header[4:]) == 80877103 {
+						conn.Write([]byte{'N'})
+						continue
+					}
+					fields := "SFATAL\x00C28P01\x00Mpassword authentication failed\x00\x00"
+					msg := []byte{'E', 0, 0, 0, 0}
+					binary.BigEndian.PutUint32(msg[1:], uint32(4+len(fields)))
+					conn.Write(append(msg, fields...))
+					return
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestPostgresConnectFallsBackWhenSSLUnsupported(t *testing.T) {
+	port := fakePostgresServer(t)
+	config := &connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL, Host: "127.0.0.1", Port: port, User: "app", Database: "postgres", Timeout: 2}
+
+	err := NewPostgresDB().Connect(config)
+	if err == nil || !strings.Contains(err.Error(), "password authentication failed") {
+		t.Fatalf("未配置 TLS 时应退回明文连接并到达认证阶段: %v", err)
+	}
+
+	config.TLS = &connection.TLSConfig{Mode: connection.TLSModeRequired}
+	if err := NewPostgresDB().Connect(config); !errors.Is(err, pq.ErrSSLNotSupported) {
+		t.Fatalf("required 模式不应退回明文连接: %v", err)
+	}
+}
+
+func TestPostgresDSNQuotesValues(t *testing.T) {
+	config := &connection.ConnectionConfig{Host: "db.internal", Port: 5432, User: "app", Password: `p'a\ss word`, Database: "shop", Timeout: 7,
+		TLS: &connection.TLSConfig{Mode: connection.TLSModeVerifyFull, ServerName: "db.example.com"}}
+	got := postgresDSN(config, []dsnParam{{"sslmode", "verify-full"}})
+	want := `host='db.example.com' user='app' password='p\'a\\ss word' dbname='shop' connect_timeout='7' sslmode='verify-full'`
+	if got != want {
+		t.Errorf("DSN 不符:\n got %s\nwant %s", got, want)
+	}
+	if _, err := pq.NewConnector(got); err != nil {
+		t.Errorf("lib/pq 无法解析 DSN: %v", err)
+	}
+}
+
+func TestPostgresSchemaFor(t *testing.T) {
+	p := NewPostgresDB()
+	p.database = "shop"
+	for in, want := range map[string]string{"": "", "shop": "", " shop ": "", "sales": "sales"} {
+		if got := p.schemaFor(in); got != want {
+			t.Errorf("schemaFor(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestPostgresColumnAndCreateStatement(t *testing.T) {
+	columns := []*connection.ColumnDefinition{
+		postgresColumn(map[string]interface{}{"column_name": "id", "data_type": "bigint", "not_null": true, "is_primary": true,
+			"column_default": "nextval('orders_id_seq'::regclass)", "is_identity": false, "is_generated": false}),
+		postgresColumn(map[string]interface{}{"column_name": "total", "data_type": "numeric(10,2)", "not_null": false,
+			"column_default": "(price * qty)", "is_generated": true}),
+		postgresColumn(map[string]interface{}{"column_name": "note", "data_type": "text", "not_null": []byte("f"),
+			"column_default": nil, "is_identity": true, "column_comment": "备注"}),
+	}
+	if c := columns[0]; c.Key != "PRI" || c.Nullable != "NO" || c.Extra != "auto_increment" || c.DefaultExpression == "" {
+		t.Errorf("主键列不符: %+v", c)
+	}
+	if c := columns[1]; !c.IsGenerated || c.GenerationExpression != "(price * qty)" || c.Default != nil {
+		t.Errorf("生成列不符: %+v", c)
+	}
+	if c := columns[2]; c.Nullable != "YES" || c.Extra != "auto_increment" || c.Comment != "备注" {
+		t.Errorf("标识列不符: %+v", c)
+	}
+
+	stmt := postgresCreateTableStatement(qualified("public", "orders", quotePgIdent), columns,
+		[]string{`CONSTRAINT "orders_pkey" PRIMARY KEY (id)`},
+		[]string{`CREATE INDEX idx_orders_note ON public.orders USING btree (note)`})
+	want := `CREATE TABLE "public"."orders" (
+  "id" bigint DEFAULT nextval('orders_id_seq'::regclass) NOT NULL,
+  "total" numeric(10,2) GENERATED ALWAYS AS ((price * qty)) STORED,
+  "note" text,
+  CONSTRAINT "orders_pkey" PRIMARY KEY (id)
+);
+CREATE INDEX idx_orders_note ON public.orders USING btree (note);`
+	if stmt != want {
+		t.Errorf("建表语句不符:\n%s", stmt)
+	}
+}
+
+func TestPostgresDialerUsesConfiguredAddress(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	d, err := NewPostgresDB().dialer(&connection.ConnectionConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port})
+	if err != nil {
+		t.Fatalf("创建拨号器失败: %v", err)
+	}
+	// 驱动按 DSN 的 host（可能是证书主机名）计算的地址应被忽略
+	conn, err := d.Dial("tcp", "db.example.com:"+strconv.Itoa(postgresDefaultPort))
+	if err != nil {
+		t.Fatalf("拨号失败: %v", err)
+	}
+	conn.Close()
+}
+
+// fakePostgresDB 返回连接到模拟驱动的 PostgreSQL 实例，用于检查发送的语句。
+func fakePostgresDB(t *testing.T) (*PostgresDB, *fakeImportDriver) {
+	t.Helper()
+	conn, drv := openFakeImportDB(t)
+	p := NewPostgresDB()
+	p.conn = conn
+	return p, drv
+}
+
+func TestPostgresAccountManager(t *testing.T) {
+	p, drv := fakePostgresDB(t)
+	if err := p.CreateUser(&connection.UserSpec{User: "app", Password: "s'ecret"}); err != nil {
+		t.Fatalf("CreateUser 失败: %v", err)
+	}
+	if err := p.GrantPrivileges(&connection.GrantSpec{User: "app", Schema: "public", Table: "orders", Privileges: []string{"select"}}); err != nil {
+		t.Fatalf("GrantPrivileges 失败: %v", err)
+	}
+	want := []string{`CREATE USER "app" WITH LOGIN PASSWORD 's''ecret'`, `GRANT SELECT ON TABLE "public"."orders" TO "app"`}
+	if strings.Join(drv.execs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("执行语句不符: %q", drv.execs)
+	}
+}
+
+func TestPostgresKillProcess(t *testing.T) {
+	p, drv := fakePostgresDB(t)
+	drv.columns, drv.rows = []string{"ok"}, [][]driver.Value{{true}}
+	if err := p.KillProcess(42, true); err != nil {
+		t.Fatalf("KillProcess 失败: %v", err)
+	}
+	drv.rows = [][]driver.Value{{false}}
+	if err := p.KillProcess(43, false); err == nil {
+		t.Fatal("函数返回 false 时应报告会话不存在或无权终止")
+	}
+	if len(drv.execs) != 2 || !strings.Contains(drv.execs[0], "pg_cancel_backend") || !strings.Contains(drv.execs[1], "pg_terminate_backend") {
+		t.Errorf("执行语句不符: %q", drv.execs)
+	}
+}
+
+func TestPostgresGetPartitionsQualifiedName(t *testing.T) {
+	p, drv := fakePostgresDB(t)
+	drv.columns = []string{"partition_key", "partition_name", "partition_schema", "bound", "row_estimate", "data_size", "index_size", "comment"}
+	drv.rows = [][]driver.Value{{"RANGE (created_at)", "orders_2026", "sales", "FOR VALUES FROM ('2026-01-01') TO ('2027-01-01')", int64(10), int64(8192), int64(0), ""}}
+
+	result, err := p.GetPartitions("shop", `sales."orders"`)
+	if err != nil {
+		t.Fatalf("GetPartitions 失败: %v", err)
+	}
+	if result.Table != "orders" || result.Method != connection.PartitionMethodRange || len(result.Partitions) != 1 || result.Partitions[0].Schema != "sales" {
+		t.Errorf("分区信息不符: %+v", result)
+	}
+}
+
+func TestPostgresQueryStreamFetchesInBatches(t *testing.T) {
+	p, drv := fakePostgresDB(t)
+	drv.columns = []string{"id"}
+	drv.rows = [][]driver.Value{{int64(1)}, {int64(2)}}
+
+	stream, err := p.QueryStream(WithFetchSize(context.Background(), 2), "SELECT id FROM t")
+	if err != nil {
+		t.Fatalf("QueryStream 失败: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := stream.Next(); err != nil {
+			t.Fatalf("读取第 %d 行失败: %v", i+1, err)
+		}
+	}
+	drv.rows = nil
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("空批次后应返回 io.EOF，得到 %v", err)
+	}
+	stream.Close()
+
+	want := []string{"DECLARE boxify_stream NO SCROLL CURSOR FOR SELECT id FROM t", "FETCH FORWARD 2 FROM boxify_stream", "FETCH FORWARD 2 FROM boxify_stream"}
+	if !slices.Equal(drv.execs, want) || !drv.rolledBack {
+		t.Errorf("游标语句不符: %q，回滚 %v", drv.execs, drv.rolledBack)
+	}
+
+	drv.execs = nil
+	stream, err = p.QueryStream(WithFetchSize(context.Background(), 2), "WITH d AS (DELETE FROM t RETURNING id) SELECT id FROM d")
+	if err != nil {
+		t.Fatalf("QueryStream 失败: %v", err)
+	}
+	stream.Close()
+	if len(drv.execs) != 1 || strings.HasPrefix(drv.execs[0], "DECLARE") {
+		t.Errorf("含数据修改的语句不应使用游标: %q", drv.execs)
+	}
+}
+
+func TestPostgresFactoryAndCapabilities(t *testing.T) {
+	inst, err := NewDatabase(connection.ConnectionTypePostgreSQL)
+	if err != nil {
+		t.Fatalf("创建 PostgreSQL 实例失败: %v", err)
+	}
+	if _, ok := inst.(PostgresObjectReader); !ok {
+		t.Error("PostgreSQL 实例应支持物化视图与序列读取")
+	}
+	if _, ok := inst.(SchemaLister); !ok {
+		t.Error("PostgreSQL 实例应支持列出 schema")
+	}
+	if _, ok := inst.(AccountManager); !ok {
+		t.Error("PostgreSQL 实例应支持账号管理")
+	}
+	if _, ok := inst.(ServerInspector); !ok {
+		t.Error("PostgreSQL 实例应支持读取服务器状态与变量")
+	}
+	if _, ok := inst.(ProcessManager); !ok {
+		t.Error("PostgreSQL 实例应支持会话列表与终止会话")
+	}
+	if _, ok := inst.(TableStatsReader); !ok {
+		t.Error("PostgreSQL 实例应支持读取表统计")
+	}
+	if _, ok := inst.(PostgresTypeReader); !ok {
+		t.Error("PostgreSQL 实例应支持读取自定义类型")
+	}
+	if _, ok := inst.(PartitionReader); !ok {
+		t.Error("PostgreSQL 实例应支持读取分区")
+	}
+	caps := Capabilities(&connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL})
+	if !caps.SupportsTransactions || !caps.SupportsSchemas {
+		t.Errorf("PostgreSQL 能力不符: %+v", caps)
+	}
+}
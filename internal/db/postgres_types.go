@@ -0,0 +1,106 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// PostgresTypeReader 定义 PostgreSQL 自定义类型（枚举、域、复合类型）的读取能力。
+//
+// schema 为空时使用连接当前的 schema（current_schema()）。
+type PostgresTypeReader interface {
+	GetCustomTypes(schema string) ([]*connection.CustomTypeDefinition, error)
+}
+
+// pgCustomTypesSQL 一次性读取三类自定义类型，数组列以 JSON 文本返回以避免依赖驱动的数组解析；
+// 复合类型仅包含 CREATE TYPE 创建的独立类型（relkind = 'c'），不包含表的行类型。
+const pgCustomTypesSQL = `SELECT n.nspname AS schema_name, t.typname AS type_name,
+       CASE t.typtype WHEN 'e' THEN 'enum' WHEN 'd' THEN 'domain' ELSE 'composite' END AS kind,
+       CASE WHEN t.typtype = 'd' THEN format_type(t.typbasetype, t.typtypmod) ELSE '' END AS base_type,
+       t.typnotnull AS not_null, t.typdefault AS default_value,
+       COALESCE((SELECT json_agg(e.enumlabel ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = t.oid)::text, '[]') AS enum_values,
+       COALESCE((SELECT json_agg(pg_get_constraintdef(c.oid) ORDER BY c.conname) FROM pg_constraint c WHERE c.contypid = t.oid)::text, '[]') AS constraints,
+       COALESCE((SELECT json_agg(json_build_object('name', a.attname, 'type', format_type(a.atttypid, a.atttypmod)) ORDER BY a.attnum)
+                 FROM pg_attribute a WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped)::text, '[]') AS attributes,
+       COALESCE(obj_description(t.oid, 'pg_type'), '') AS comment
+FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+LEFT JOIN pg_class r ON r.oid = t.typrelid
+WHERE n.nspname::text = COALESCE(NULLIF($1::text, ''), current_schema())
+  AND (t.typtype IN ('e', 'd') OR (t.typtype = 'c' AND r.relkind = 'c'))
+ORDER BY t.typname`
+
+// GetCustomTypes 读取 schema 下的枚举、域与复合类型，schema 为空时使用连接当前的 schema。
+func (p *PostgresDB) GetCustomTypes(schema string) ([]*connection.CustomTypeDefinition, error) {
+	return queryCustomTypes(p.Query, schema)
+}
+
+// queryCustomTypes 读取 schema 下的枚举、域与复合类型。
+func queryCustomTypes(query queryFunc, schema string) ([]*connection.CustomTypeDefinition, error) {
+	rows, _, err := query(pgCustomTypesSQL, schema)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]*connection.CustomTypeDefinition, 0, len(rows))
+	for _, row := range rows {
+		def := &connection.CustomTypeDefinition{
+			Schema:      textValue(row["schema_name"]),
+			Name:        textValue(row["type_name"]),
+			Kind:        textValue(row["kind"]),
+			BaseType:    textValue(row["base_type"]),
+			NotNull:     boolValue(row["not_null"]),
+			Comment:     textValue(row["comment"]),
+			Values:      []string{},
+			Constraints: []string{},
+			Attributes:  []*connection.CustomTypeAttribute{},
+		}
+		if v := row["default_value"]; v != nil {
+			d := textValue(v)
+			def.Default = &d
+		}
+		if err := decodeJSONColumn(row["enum_values"], &def.Values); err != nil {
+			return nil, fmt.Errorf("解析类型 %s 的枚举值失败: %w", def.Name, err)
+		}
+		if err := decodeJSONColumn(row["constraints"], &def.Constraints); err != nil {
+			return nil, fmt.Errorf("解析类型 %s 的约束失败: %w", def.Name, err)
+		}
+		if err := decodeJSONColumn(row["attributes"], &def.Attributes); err != nil {
+			return nil, fmt.Errorf("解析类型 %s 的字段失败: %w", def.Name, err)
+		}
+		types = append(types, def)
+	}
+	return types, nil
+}
+
+// textValue 将查询结果转换为字符串，兼容驱动以字节返回的文本，nil 返回空字符串
+func textValue(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return stringOrEmpty(v)
+}
+
+// decodeJSONColumn 解析以 JSON 文本返回的列，空值保持 dst 不变
+func decodeJSONColumn(v interface{}, dst any) error {
+	text := textValue(v)
+	if text == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(text), dst)
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestQueryCustomTypes(t *testing.T) {
+	var gotArgs []any
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		gotArgs = args
+		return []map[string]interface{}{
+			{
+				"schema_name": "public", "type_name": "mood", "kind": "enum", "base_type": "",
+				"not_null": false, "default_value": nil, "enum_values": []byte(`["sad", "ok", "happy"]`),
+				"constraints": "[]", "attributes": "[]", "comment": "",
+			},
+			{
+				"schema_name": "public", "type_name": "email", "kind": "domain", "base_type": "character varying(255)",
+				"not_null": "t", "default_value": "''::character varying", "enum_values": "[]",
+				"constraints": `["CHECK (((VALUE)::text ~~ '%@%'::text))"]`, "attributes": "[]", "comment": "邮箱",
+			},
+			{
+				"schema_name": "public", "type_name": "address", "kind": "composite", "base_type": "",
+				"not_null": false, "default_value": nil, "enum_values": "[]", "constraints": "[]",
+				"attributes": `[{"name" : "city", "type" : "text"}, {"name" : "zip", "type" : "character(6)"}]`, "comment": "",
+			},
+		}, nil, nil
+	}
+
+	types, err := queryCustomTypes(query, "")
+	if err != nil {
+		t.Fatalf("queryCustomTypes 失败: %v", err)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "" {
+		t.Errorf("查询参数不符: %v", gotArgs)
+	}
+	if len(types) != 3 {
+		t.Fatalf("期望 3 个类型，实际 %d", len(types))
+	}
+
+	if !reflect.DeepEqual(types[0].Values, []string{"sad", "ok", "happy"}) || types[0].Default != nil {
+		t.Errorf("枚举解析不符: %+v", types[0])
+	}
+	domain := types[1]
+	if !domain.NotNull || domain.Default == nil || *domain.Default != "''::character varying" ||
+		len(domain.Constraints) != 1 || domain.Comment != "邮箱" {
+		t.Errorf("域解析不符: %+v", domain)
+	}
+	want := []*connection.CustomTypeAttribute{{Name: "city", Type: "text"}, {Name: "zip", Type: "character(6)"}}
+	if !reflect.DeepEqual(types[2].Attributes, want) {
+		t.Errorf("复合类型字段不符: %+v", types[2].Attributes)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// ProcedureCaller 定义按参数方向调用存储过程并取回输出参数的能力。
+type ProcedureCaller interface {
+	CallProcedure(ctx context.Context, name string, params []*connection.ProcedureParam, maxRows int) (*connection.ProcedureResult, error)
+}
+
+// procedureCall 是编译后的存储过程调用。
+type procedureCall struct {
+	setup   []string // 调用前执行的语句（为 inout 参数的会话变量赋值）
+	setArgs [][]any  // 与 setup 一一对应的参数
+	call    string
+	args    []any
+	fetch   string   // 调用后读取输出参数的查询，无输出参数时为空
+	outputs []string // 输出参数名，按 fetch 结果列 o1、o2… 的顺序排列
+}
+
+// procedureParamMode 返回规范化的参数方向，未知方向返回错误。
+func procedureParamMode(p *connection.ProcedureParam) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(p.Mode))
+	switch mode {
+	case "", connection.ProcedureParamIn:
+		return connection.ProcedureParamIn, nil
+	case connection.ProcedureParamOut, connection.ProcedureParamInOut:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("参数 %s 的方向无效: %s", p.Name, p.Mode)
+	}
+}
+
+// buildMySQLProcedureCall 编译 MySQL 存储过程调用：in 参数直接绑定，out/inout 参数通过会话变量 @boxify_out_N 传递，
+// 调用后以 SELECT 读取会话变量得到输出值。会话变量名只由参数位置生成，参数名不会拼入语句。
+func buildMySQLProcedureCall(name string, params []*connection.ProcedureParam) (*procedureCall, error) {
+	pc := &procedureCall{}
+	holders := make([]string, len(params))
+	var fetch []string
+	for i, p := range params {
+		mode, err := procedureParamMode(p)
+		if err != nil {
+			return nil, err
+		}
+		if mode == connection.ProcedureParamIn {
+			holders[i] = "?"
+			pc.args = append(pc.args, p.Value)
+			continue
+		}
+		variable := "@boxify_out_" + strconv.Itoa(i+1)
+		holders[i] = variable
+		if mode == connection.ProcedureParamInOut {
+			pc.setup = append(pc.setup, "SET "+variable+" = ?")
+			pc.setArgs = append(pc.setArgs, []any{p.Value})
+		} else {
+			pc.setup = append(pc.setup, "SET "+variable+" = NULL")
+			pc.setArgs = append(pc.setArgs, nil)
+		}
+		pc.outputs = append(pc.outputs, procedureOutputName(p, i))
+		fetch = append(fetch, variable+" AS o"+strconv.Itoa(len(pc.outputs)))
+	}
+	pc.call = "CALL " + mysqlDialect.quoteTable(name) + "(" + strings.Join(holders, ", ") + ")"
+	if len(fetch) > 0 {
+		pc.fetch = "SELECT " + strings.Join(fetch, ", ")
+	}
+	return pc, nil
+}
+
+// BuildPostgresProcedureCall 编译 PostgreSQL（11 起）存储过程调用：in/inout 参数绑定输入值，out 参数传 NULL，
+// 输出参数的值由 CALL 以单行结果集返回，列名即参数名。
+func BuildPostgresProcedureCall(name string, params []*connection.ProcedureParam) (string, []any, error) {
+	holders := make([]string, len(params))
+	var args []any
+	for i, p := range params {
+		mode, err := procedureParamMode(p)
+		if err != nil {
+			return "", nil, err
+		}
+		if mode == connection.ProcedureParamOut {
+			holders[i] = "NULL"
+			continue
+		}
+		args = append(args, p.Value)
+		holders[i] = postgresDialect.placeholder(len(args))
+	}
+	return "CALL " + postgresDialect.quoteTable(name) + "(" + strings.Join(holders, ", ") + ")", args, nil
+}
+
+// procedureOutputName 返回输出参数在结果中的名称，未命名时按位置生成。
+func procedureOutputName(p *connection.ProcedureParam, i int) string {
+	if name := strings.TrimSpace(p.Name); name != "" {
+		return name
+	}
+	return "p" + strconv.Itoa(i+1)
+}
+
+// CallProcedure 在同一会话中为输出参数赋初值、调用存储过程并读取输出参数。
+func (m *MySQLDB) CallProcedure(ctx context.Context, name string, params []*connection.ProcedureParam, maxRows int) (*connection.ProcedureResult, error) {
+	if m.conn == nil {
+		return nil, fmt.Errorf("连接没有打开")
+	}
+	pc, err := buildMySQLProcedureCall(name, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 会话变量只在单个连接内可见，未切换 schema 时也需固定一个连接
+	session, release, err := m.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, pooled := session.(*sql.DB); pooled {
+		conn, err := m.conn.Conn(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		session, release = conn, func() { conn.Close() }
+	}
+	defer release()
+
+	for i, stmt := range pc.setup {
+		if _, err := session.ExecContext(ctx, stmt, pc.setArgs[i]...); err != nil {
+			return nil, fmt.Errorf("设置输出参数失败：%w", err)
+		}
+	}
+
+	rows, err := session.QueryContext(ctx, pc.call, pc.args...)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := scanResultSets(rows, maxRows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &connection.ProcedureResult{Call: pc.call, ResultSets: sets, Outputs: map[string]interface{}{}}
+	if pc.fetch == "" {
+		return result, nil
+	}
+	outRows, err := session.QueryContext(ctx, pc.fetch)
+	if err != nil {
+		return nil, fmt.Errorf("读取输出参数失败：%w", err)
+	}
+	defer outRows.Close()
+	data, _, err := scanRows(outRows)
+	if err != nil {
+		return nil, fmt.Errorf("读取输出参数失败：%w", err)
+	}
+	if len(data) > 0 {
+		for i, out := range pc.outputs {
+			result.Outputs[out] = data[0]["o"+strconv.Itoa(i+1)]
+		}
+	}
+	return result, nil
+}
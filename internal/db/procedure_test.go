@@ -0,0 +1,61 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestBuildMySQLProcedureCall(t *testing.T) {
+	params := []*connection.ProcedureParam{
+		{Name: "id", Value: 7},
+		{Name: "total", Mode: "OUT"},
+		{Name: "counter", Mode: connection.ProcedureParamInOut, Value: 3},
+	}
+	pc, err := buildMySQLProcedureCall("shop.calc", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pc.call != "CALL `shop`.`calc`(?, @boxify_out_2, @boxify_out_3)" || !reflect.DeepEqual(pc.args, []any{7}) {
+		t.Fatalf("调用语句不符: %s %v", pc.call, pc.args)
+	}
+	if !reflect.DeepEqual(pc.setup, []string{"SET @boxify_out_2 = NULL", "SET @boxify_out_3 = ?"}) || !reflect.DeepEqual(pc.setArgs[1], []any{3}) {
+		t.Fatalf("初始化语句不符: %v %v", pc.setup, pc.setArgs)
+	}
+	if pc.fetch != "SELECT @boxify_out_2 AS o1, @boxify_out_3 AS o2" || !reflect.DeepEqual(pc.outputs, []string{"total", "counter"}) {
+		t.Fatalf("输出参数读取不符: %s %v", pc.fetch, pc.outputs)
+	}
+
+	if _, err := buildMySQLProcedureCall("p", []*connection.ProcedureParam{{Name: "x", Mode: "sideways"}}); err == nil {
+		t.Fatal("无效参数方向应返回错误")
+	}
+}
+
+func TestBuildPostgresProcedureCall(t *testing.T) {
+	call, args, err := BuildPostgresProcedureCall(`"Billing".close_month`, []*connection.ProcedureParam{
+		{Name: "month", Value: "2026-09"},
+		{Name: "closed", Mode: connection.ProcedureParamOut},
+		{Name: "note", Mode: connection.ProcedureParamInOut, Value: "x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call != `CALL "Billing"."close_month"($1, NULL, $2)` || !reflect.DeepEqual(args, []any{"2026-09", "x"}) {
+		t.Fatalf("调用语句不符: %s %v", call, args)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// ProcessManager 定义会话列表读取与终止能力。
+type ProcessManager interface {
+	GetProcessList() ([]*connection.ProcessInfo, error)
+	// KillProcess 终止会话；queryOnly 为 true 时只取消正在执行的语句，保留连接
+	KillProcess(id int64, queryOnly bool) error
+}
+
+const mysqlProcessListSQL = `SELECT ID, USER, HOST, DB, COMMAND, TIME, STATE, INFO, ID = CONNECTION_ID() AS IS_SELF
+FROM information_schema.PROCESSLIST
+ORDER BY TIME DESC, ID`
+
+// GetProcessList 读取 information_schema.PROCESSLIST，按持续时间倒序；无 PROCESS 权限时只能看到自己的会话
+func (m *MySQLDB) GetProcessList() ([]*connection.ProcessInfo, error) {
+	data, _, err := m.Query(mysqlProcessListSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]*connection.ProcessInfo, 0, len(data))
+	for _, row := range data {
+		processes = append(processes, &connection.ProcessInfo{
+			ID:              utils.ToInt64(row["ID"]),
+			User:            stringOrEmpty(row["USER"]),
+			Host:            stringOrEmpty(row["HOST"]),
+			Database:        stringOrEmpty(row["DB"]),
+			Command:         stringOrEmpty(row["COMMAND"]),
+			State:           stringOrEmpty(row["STATE"]),
+			DurationSeconds: utils.ToInt64(row["TIME"]),
+			SQL:             stringOrEmpty(row["INFO"]),
+			Self:            boolValue(row["IS_SELF"]),
+		})
+	}
+	return processes, nil
+}
+
+// KillProcess 执行 KILL [QUERY]
+func (m *MySQLDB) KillProcess(id int64, queryOnly bool) error {
+	if id <= 0 {
+		return fmt.Errorf("无效的会话 ID: %d", id)
+	}
+	stmt := "KILL "
+	if queryOnly {
+		stmt += "QUERY "
+	}
+	_, err := m.Exec(stmt + strconv.FormatInt(id, 10))
+	return err
+}
+
+// GetProcessList 读取 pg_stat_activity 中的客户端会话，按当前语句持续时间倒序
+func (p *PostgresDB) GetProcessList() ([]*connection.ProcessInfo, error) {
+	return queryPostgresProcesses(p.Query)
+}
+
+// KillProcess 调用 pg_terminate_backend，queryOnly 为 true 时调用 pg_cancel_backend 只取消当前语句
+func (p *PostgresDB) KillProcess(id int64, queryOnly bool) error {
+	return killPostgresProcess(p.Query, id, queryOnly)
+}
+
+const pgProcessListSQL = `SELECT pid, usename, COALESCE(client_addr::text, client_hostname, '') AS host, datname,
+       backend_type, state, COALESCE(extract(epoch FROM now() - COALESCE(query_start, backend_start))::bigint, 0) AS duration,
+       query, pid = pg_backend_pid() AS is_self
+FROM pg_stat_activity
+WHERE backend_type = 'client backend'
+ORDER BY duration DESC, pid`
+
+// queryPostgresProcesses 读取 pg_stat_activity 中的客户端会话，按当前语句持续时间倒序。
+func queryPostgresProcesses(query queryFunc) ([]*connection.ProcessInfo, error) {
+	rows, _, err := query(pgProcessListSQL)
+	if err != nil {
+		return nil, err
+	}
+	processes := make([]*connection.ProcessInfo, 0, len(rows))
+	for _, row := range rows {
+		processes = append(processes, &connection.ProcessInfo{
+			ID:              utils.ToInt64(row["pid"]),
+			User:            stringOrEmpty(row["usename"]),
+			Host:            stringOrEmpty(row["host"]),
+			Database:        stringOrEmpty(row["datname"]),
+			Command:         stringOrEmpty(row["backend_type"]),
+			State:           stringOrEmpty(row["state"]),
+			DurationSeconds: utils.ToInt64(row["duration"]),
+			SQL:             stringOrEmpty(row["query"]),
+			Self:            boolValue(row["is_self"]),
+		})
+	}
+	return processes, nil
+}
+
+// killPostgresProcess 调用 pg_cancel_backend / pg_terminate_backend；函数返回 false 表示会话不存在或无权终止。
+func killPostgresProcess(query queryFunc, id int64, queryOnly bool) error {
+	if id <= 0 {
+		return fmt.Errorf("无效的会话 ID: %d", id)
+	}
+	fn := "pg_terminate_backend"
+	if queryOnly {
+		fn = "pg_cancel_backend"
+	}
+	rows, _, err := query("SELECT "+fn+"($1) AS ok", id)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 || !boolValue(rows[0]["ok"]) {
+		return fmt.Errorf("会话 %d 不存在或无权终止", id)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryPostgresProcesses(t *testing.T) {
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return []map[string]interface{}{
+			{"pid": int64(4242), "usename": "app", "host": "10.0.0.8/32", "datname": "shop", "backend_type": "client backend",
+				"state": "active", "duration": "125", "query": "UPDATE orders SET x = 1", "is_self": false},
+			{"pid": int64(7), "usename": "admin", "host": "", "datname": nil, "backend_type": "client backend",
+				"state": "active", "duration": int64(0), "query": "SELECT 1", "is_self": "t"},
+		}, nil, nil
+	}
+	processes, err := queryPostgresProcesses(query)
+	if err != nil {
+		t.Fatalf("queryPostgresProcesses 失败: %v", err)
+	}
+	if len(processes) != 2 || processes[0].ID != 4242 || processes[0].DurationSeconds != 125 || processes[0].Self {
+		t.Errorf("会话解析不符: %+v", processes[0])
+	}
+	if processes[1].Database != "" || !processes[1].Self {
+		t.Errorf("空库名或自身会话解析不符: %+v", processes[1])
+	}
+}
+
+func TestKillPostgresProcess(t *testing.T) {
+	var gotQuery string
+	var gotArgs []any
+	ok := true
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		gotQuery, gotArgs = q, args
+		return []map[string]interface{}{{"ok": ok}}, []string{"ok"}, nil
+	}
+
+	if err := killPostgresProcess(query, 99, true); err != nil || !strings.Contains(gotQuery, "pg_cancel_backend") || gotArgs[0] != int64(99) {
+		t.Errorf("取消语句不符: %s %v (err=%v)", gotQuery, gotArgs, err)
+	}
+	if err := killPostgresProcess(query, 99, false); err != nil || !strings.Contains(gotQuery, "pg_terminate_backend") {
+		t.Errorf("终止会话不符: %s (err=%v)", gotQuery, err)
+	}
+	ok = false
+	if err := killPostgresProcess(query, 99, false); err == nil {
+		t.Error("函数返回 false 时应报错")
+	}
+	if err := killPostgresProcess(query, 0, false); err == nil {
+		t.Error("应拒绝非正数 ID")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlProxyNetwork 为直连代理注册 MySQL 驱动的自定义网络并返回网络名；
+// 网络名由代理配置摘要得到，相同代理重复注册时覆盖为等价的拨号函数。
+func mysqlProxyNetwork(proxy *connection.ProxyConfig) string {
+	p := *proxy
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s\x00%s", p.Type, p.Host, p.Port, p.User, p.Password)))
+	name := "proxy-" + hex.EncodeToString(sum[:8])
+	mysql.RegisterDialContext(name, func(ctx context.Context, addr string) (net.Conn, error) {
+		return netproxy.Dial(ctx, &p, addr)
+	})
+	return name
+}
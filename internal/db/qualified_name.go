@@ -0,0 +1,112 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// SchemaLister 定义列出数据库下 schema（命名空间）的能力。
+type SchemaLister interface {
+	GetSchemas(ctx context.Context) ([]string, error)
+}
+
+// QualifiedName 是可带 schema 前缀的对象名，Schema 为空表示未限定。
+type QualifiedName struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+}
+
+// ParseQualifiedName 解析 "schema.table" 形式的对象名。
+//
+// 各部分可用双引号或反引号包裹（引号内连续两个引号表示引号本身），引号内的点不作为分隔符；
+// 名称按原样保留大小写，不做 PostgreSQL 的小写折叠，因为传入的名称通常直接来自目录查询结果。
+func ParseQualifiedName(raw string) (QualifiedName, error) {
+	raw = strings.TrimSpace(raw)
+	var parts []string
+	var cur strings.Builder
+	var quote rune
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				cur.WriteRune(r)
+				i++
+			} else {
+				quote = 0
+			}
+		case quote != 0:
+			cur.WriteRune(r)
+		case r == '"' || r == '`':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return QualifiedName{}, fmt.Errorf("对象名引号未闭合: %s", raw)
+	}
+	parts = append(parts, strings.TrimSpace(cur.String()))
+	for _, p := range parts {
+		if p == "" {
+			return QualifiedName{}, fmt.Errorf("对象名不完整: %s", raw)
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return QualifiedName{Name: parts[0]}, nil
+	case 2:
+		return QualifiedName{Schema: parts[0], Name: parts[1]}, nil
+	default:
+		return QualifiedName{}, fmt.Errorf("对象名最多包含 schema 与名称两级: %s", raw)
+	}
+}
+
+// String 返回可被 ParseQualifiedName 还原的文本形式，包含点、引号或首尾空白的部分使用双引号包裹。
+func (q QualifiedName) String() string {
+	part := func(s string) string {
+		if strings.ContainsAny(s, ".\"`") || strings.TrimSpace(s) != s {
+			return quotePgIdent(s)
+		}
+		return s
+	}
+	if q.Schema == "" {
+		return part(q.Name)
+	}
+	return part(q.Schema) + "." + part(q.Name)
+}
+
+// quoteTable 引用可带 schema 前缀的表名；无法解析时把整个名称作为单个标识符引用。
+func (d sqlDialect) quoteTable(name string) string {
+	q, err := ParseQualifiedName(name)
+	if err != nil {
+		return d.quoteIdent(name)
+	}
+	return qualified(q.Schema, q.Name, d.quoteIdent)
+}
+
+// QuoteTable 按数据库类型引用可带 schema 前缀的表名，如 "Sales"."Orders"。
+func QuoteTable(dbType connection.ConnectionType, name string) string {
+	return dialectFor(dbType).quoteTable(name)
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestParseQualifiedName(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want QualifiedName
+	}{
+		{"orders", QualifiedName{Name: "orders"}},
+		{"sales.orders", QualifiedName{Schema: "sales", Name: "orders"}},
+		{`"Sales"."Order Items"`, QualifiedName{Schema: "Sales", Name: "Order Items"}},
+		{`"a.b"."c""d"`, QualifiedName{Schema: "a.b", Name: `c"d`}},
+		{"`shop`.`t.1`", QualifiedName{Schema: "shop", Name: "t.1"}},
+		{" MixedCase . Tbl ", QualifiedName{Schema: "MixedCase", Name: "Tbl"}},
+	}
+	for _, c := range cases {
+		got, err := ParseQualifiedName(c.raw)
+		if err != nil || got != c.want {
+			t.Errorf("%q: 期望 %+v，得到 %+v (%v)", c.raw, c.want, got, err)
+			continue
+		}
+		back, err := ParseQualifiedName(got.String())
+		if err != nil || back != got {
+			t.Errorf("%q: String() %q 无法还原 (%+v, %v)", c.raw, got.String(), back, err)
+		}
+	}
+
+	for _, raw := range []string{"", "a.", `"open`, "a.b.c"} {
+		if _, err := ParseQualifiedName(raw); err == nil {
+			t.Errorf("%q: 期望解析失败", raw)
+		}
+	}
+}
+
+func TestQuoteTable(t *testing.T) {
+	if got := QuoteTable(connection.ConnectionTypePostgreSQL, `"Sales".orders`); got != `"Sales"."orders"` {
+		t.Errorf("PostgreSQL 引用结果不符: %s", got)
+	}
+	if got := QuoteTable(connection.ConnectionTypeMySQL, "shop.orders"); got != "`shop`.`orders`" {
+		t.Errorf("MySQL 引用结果不符: %s", got)
+	}
+	if got := QuoteTable(connection.ConnectionTypeMySQL, `"open`); got != "`\"open`" {
+		t.Errorf("无法解析的名称应整体引用: %s", got)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// RetentionStatements 是数据保留规则编译后的语句，两条语句都只有一个参数：截止时间。
+type RetentionStatements struct {
+	CountSQL  string // 统计早于截止时间的行数
+	DeleteSQL string // 删除至多 batchSize 行早于截止时间的数据
+}
+
+// BuildRetentionStatements 生成按时间列分批删除旧数据的语句。
+// 各数据库限制单条 DELETE 行数的写法不同：MySQL 使用 LIMIT，SQL Server 使用 TOP，
+// PostgreSQL 与 SQLite 没有 DELETE ... LIMIT，改为按 ctid/rowid 子查询；其他数据库不支持分批删除。
+func BuildRetentionStatements(dbType connection.ConnectionType, table, column string, batchSize int) (*RetentionStatements, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("每批行数必须大于 0")
+	}
+	d := dialectFor(dbType)
+	quotedTable := d.quoteTable(table)
+	cond := d.quoteIdent(column) + " < " + d.placeholder(1)
+	limit := strconv.Itoa(batchSize)
+
+	stmts := &RetentionStatements{CountSQL: "SELECT COUNT(*) FROM " + quotedTable + " WHERE " + cond}
+	switch {
+	case IsPostgresDialect(dbType):
+		stmts.DeleteSQL = "DELETE FROM " + quotedTable + " WHERE ctid IN (SELECT ctid FROM " + quotedTable + " WHERE " + cond + " LIMIT " + limit + ")"
+	case dbType == connection.ConnectionTypeSQLServer:
+		stmts.DeleteSQL = "DELETE TOP (" + limit + ") FROM " + quotedTable + " WHERE " + cond
+	case dbType == connection.ConnectionTypeSQLite:
+		stmts.DeleteSQL = "DELETE FROM " + quotedTable + " WHERE rowid IN (SELECT rowid FROM " + quotedTable + " WHERE " + cond + " LIMIT " + limit + ")"
+	case dbType == "", dbType == connection.ConnectionTypeMySQL, dbType == connection.ConnectionTypeMariaDB:
+		stmts.DeleteSQL = "DELETE FROM " + quotedTable + " WHERE " + cond + " LIMIT " + limit
+	default:
+		return nil, fmt.Errorf("当前数据库类型不支持分批删除: %s", dbType)
+	}
+	return stmts, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestBuildRetentionStatements(t *testing.T) {
+	tests := []struct {
+		dbType connection.ConnectionType
+		table  string
+		want   string
+	}{
+		{connection.ConnectionTypeMySQL, "events", "DELETE FROM `events` WHERE `created_at` < ? LIMIT 500"},
+		{connection.ConnectionTypePostgreSQL, "app.events", `DELETE FROM "app"."events" WHERE ctid IN (SELECT ctid FROM "app"."events" WHERE "created_at" < $1 LIMIT 500)`},
+		{connection.ConnectionTypeSQLServer, "dbo.events", "DELETE TOP (500) FROM [dbo].[events] WHERE [created_at] < @p1"},
+		{connection.ConnectionTypeSQLite, "events", `DELETE FROM "events" WHERE rowid IN (SELECT rowid FROM "events" WHERE "created_at" < ? LIMIT 500)`},
+	}
+	for _, tt := range tests {
+		stmts, err := BuildRetentionStatements(tt.dbType, tt.table, "created_at", 500)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dbType, err)
+		}
+		if stmts.DeleteSQL != tt.want {
+			t.Errorf("%s 删除语句不符:\n%s", tt.dbType, stmts.DeleteSQL)
+		}
+	}
+
+	stmts, _ := BuildRetentionStatements(connection.ConnectionTypeMySQL, "events", "created_at", 500)
+	if stmts.CountSQL != "SELECT COUNT(*) FROM `events` WHERE `created_at` < ?" {
+		t.Errorf("计数语句不符: %s", stmts.CountSQL)
+	}
+	if _, err := BuildRetentionStatements(connection.ConnectionTypeDameng, "events", "created_at", 500); err == nil {
+		t.Error("不支持分批删除的数据库应返回错误")
+	}
+	if _, err := BuildRetentionStatements(connection.ConnectionTypeMySQL, "events", "created_at", 0); err == nil {
+		t.Error("每批行数为 0 应返回错误")
+	}
+}
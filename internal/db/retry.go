@@ -0,0 +1,172 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// DefaultRetryAttempts 是瞬时错误默认的重试次数（不含首次执行）。
+	DefaultRetryAttempts = 2
+	// MaxRetryAttempts 是允许配置的最大重试次数。
+	MaxRetryAttempts = 10
+	// DefaultRetryBaseDelay 是第一次重试前的基础等待时间，之后每次翻倍。
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	// DefaultRetryMaxDelay 是单次重试等待时间的上限。
+	DefaultRetryMaxDelay = 5 * time.Second
+)
+
+// RetryPolicy 是建连与只读查询遇到瞬时错误时的重试策略，Attempts 为 0 时不重试。
+type RetryPolicy struct {
+	Attempts  int           // 首次执行之后的最多重试次数
+	BaseDelay time.Duration // 第一次重试前的基础等待时间
+	MaxDelay  time.Duration // 单次等待时间上限
+}
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy = RetryPolicy{Attempts: DefaultRetryAttempts, BaseDelay: DefaultRetryBaseDelay, MaxDelay: DefaultRetryMaxDelay}
+)
+
+// CurrentRetryPolicy 返回应用当前的重试策略。
+func CurrentRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// SetRetryAttempts 修改应用的重试次数，超出 [0, MaxRetryAttempts] 时截断。
+func SetRetryAttempts(attempts int) {
+	if attempts < 0 {
+		attempts = 0
+	}
+	if attempts > MaxRetryAttempts {
+		attempts = MaxRetryAttempts
+	}
+	retryMu.Lock()
+	retryPolicy.Attempts = attempts
+	retryMu.Unlock()
+}
+
+// backoff 返回第 n 次重试（从 1 开始）前的等待时间：基础时间按指数增长并封顶，
+// 再在 [d/2, d] 内随机抖动，避免多个请求同时重连。
+func (p RetryPolicy) backoff(n int) time.Duration {
+	base, limit := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if limit <= 0 {
+		limit = DefaultRetryMaxDelay
+	}
+	d := base
+	for i := 1; i < n && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		d = limit
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// Retry 执行 fn，遇到瞬时错误时按策略等待后重试，返回实际重试的次数与最后一次的错误。
+// 非瞬时错误立即返回；ctx 结束时停止等待并返回最后一次的错误。
+func Retry(ctx context.Context, p RetryPolicy, fn func() error) (int, error) {
+	err := fn()
+	retries := 0
+	for err != nil && retries < p.Attempts && IsTransient(err) {
+		timer := time.NewTimer(p.backoff(retries + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retries, err
+		case <-timer.C:
+		}
+		retries++
+		err = fn()
+	}
+	return retries, err
+}
+
+// mysqlTransientErrors 是可重试的 MySQL 错误码：死锁、锁等待超时、服务关闭、连接丢失与只读切换等。
+var mysqlTransientErrors = map[uint16]bool{
+	1040: true, // Too many connections
+	1053: true, // Server shutdown in progress
+	1205: true, // Lock wait timeout exceeded
+	1213: true, // Deadlock found when trying to get lock
+	1290: true, // --read-only（主从切换期间）
+	1927: true, // Connection was killed
+	2006: true, // MySQL server has gone away
+	2013: true, // Lost connection to MySQL server during query
+}
+
+// transientMessages 是无法按类型识别时用于匹配错误文本的片段（小写），覆盖 PostgreSQL SQLSTATE、
+// SQL Server 死锁牺牲者与常见网络错误。
+var transientMessages = []string{
+	"connection reset",
+	"broken pipe",
+	"connection refused",
+	"bad connection",
+	"deadlock",
+	"could not serialize access", // PostgreSQL 40001
+	"sqlstate 40001",
+	"sqlstate 40p01",
+	"sqlstate 57p01", // admin_shutdown
+	"sqlstate 57p03", // cannot_connect_now（故障切换或恢复中）
+	"the database system is starting up",
+	"the database system is shutting down",
+	"the database system is in recovery mode",
+	"chosen as the deadlock victim", // SQL Server 1205
+	"failover",
+	"server has gone away",
+	"lost connection",
+	"too many connections",
+}
+
+// IsTransient 判断错误是否为重试可能成功的瞬时错误（连接中断、死锁牺牲者、故障切换进行中等）。
+// 上下文取消与超时不视为瞬时错误。
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		return mysqlTransientErrors[me.Number]
+	}
+	msg := strings.ToLower(err.Error())
+	for _, frag := range transientMessages {
+		if strings.Contains(msg, frag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{driver.ErrBadConn, true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{errors.New("ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)"), true},
+		{errors.New("Transaction (Process ID 52) was deadlocked on lock resources with another process and has been chosen as the deadlock victim"), true},
+		{errors.New("syntax error at or near \"SELEC\""), false},
+		{context.DeadlineExceeded, false},
+	}
+	for _, c := range cases {
+		if got := IsTransient(c.err); got != c.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryTransient(t *testing.T) {
+	p := RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	calls := 0
+	retries, err := Retry(context.Background(), p, func() error {
+		calls++
+		if calls < 3 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	if err != nil || retries != 2 || calls != 3 {
+		t.Fatalf("retries=%d calls=%d err=%v", retries, calls, err)
+	}
+
+	calls = 0
+	permanent := errors.New("syntax error")
+	retries, err = Retry(context.Background(), p, func() error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || retries != 0 || calls != 1 {
+		t.Fatalf("非瞬时错误不应重试: retries=%d calls=%d err=%v", retries, calls, err)
+	}
+
+	calls = 0
+	retries, err = Retry(context.Background(), p, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	if !errors.Is(err, driver.ErrBadConn) || retries != 3 || calls != 4 {
+		t.Fatalf("重试次数应受上限约束: retries=%d calls=%d err=%v", retries, calls, err)
+	}
+}
+
+func TestRetryBackoffBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(n); d < max/2 || d > max {
+				t.Fatalf("backoff(%d) = %v, want [%v, %v]", n, d, max/2, max)
+			}
+		}
+	}
+}
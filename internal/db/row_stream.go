@@ -0,0 +1,188 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// RowStreamer 定义流式读取查询结果的能力（服务端游标）。
+//
+// MySQL 驱动按行从网络读取结果，天然为流式；PostgreSQL 类驱动应基于 DECLARE CURSOR / FETCH 实现。
+type RowStreamer interface {
+	QueryStream(ctx context.Context, query string, args ...any) (*RowStream, error)
+}
+
+// fetchSizeKey 是 context 中每批读取行数提示的键。
+type fetchSizeKey struct{}
+
+// WithFetchSize 在 ctx 中携带每批读取的行数提示，供基于 DECLARE CURSOR / FETCH 的流式实现使用。
+// PostgreSQL 驱动据此用服务端游标分批读取；MySQL 驱动按行从网络读取，忽略该提示。
+func WithFetchSize(ctx context.Context, n int) context.Context {
+	if n <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fetchSizeKey{}, n)
+}
+
+// FetchSize 返回 ctx 中的每批读取行数提示，未设置时返回 0。
+func FetchSize(ctx context.Context) int {
+	n, _ := ctx.Value(fetchSizeKey{}).(int)
+	return n
+}
+
+// BlobSink 保存超过内联上限的二进制值，返回代替其出现在结果中的引用。
+type BlobSink interface {
+	Put(data []byte) (connection.BlobRef, error)
+}
+
+// RowStream 逐行读取查询结果，不在内存中缓存整个结果集。
+// 流在关闭前会占用连接池中的一个连接。
+type RowStream struct {
+	rows        *sql.Rows
+	columns     []string
+	colTypes    []*sql.ColumnType
+	release     func()                    // 归还独立会话（可为空）
+	refill      func() (*sql.Rows, error) // 读取下一批结果（可为空），某批为空时结束
+	batchRows   int                       // 当前批已读取行数
+	blobs       BlobSink
+	inlineLimit int
+}
+
+// newRowStream 基于 sql.Rows 创建行流。
+func newRowStream(rows *sql.Rows) (*RowStream, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	colTypes, err := rows.ColumnTypes()
+	if err != nil || len(colTypes) != len(columns) {
+		colTypes = nil // 如果无法获取列类型，继续但不使用类型信息
+	}
+	return &RowStream{rows: rows, columns: columns, colTypes: colTypes}, nil
+}
+
+// Columns 返回结果集列名。
+func (s *RowStream) Columns() []string {
+	return s.columns
+}
+
+// ColumnTypes 返回各列的数据库类型名，无法获取时对应项为空。
+func (s *RowStream) ColumnTypes() []string {
+	types := make([]string, len(s.columns))
+	for i := range types {
+		if s.colTypes != nil && s.colTypes[i] != nil {
+			types[i] = s.colTypes[i].DatabaseTypeName()
+		}
+	}
+	return types
+}
+
+// Fields 返回各列的类型信息（名称、类型、可空、长度、精度），驱动未提供的项为空。
+func (s *RowStream) Fields() []connection.FieldMeta {
+	return fieldMetas(s.columns, s.colTypes)
+}
+
+// SetBlobSink 使 Next 将超过 inlineLimit 字节的二进制值交给 sink 保存，并在结果中以引用代替。
+// 文本内容不受影响；sink 为 nil 或保存失败时内联返回。
+func (s *RowStream) SetBlobSink(sink BlobSink, inlineLimit int) {
+	s.blobs, s.inlineLimit = sink, inlineLimit
+}
+
+// Next 读取下一行，读完时返回 io.EOF。
+func (s *RowStream) Next() (map[string]interface{}, error) {
+	if err := s.advance(); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(s.columns))
+	valuePtrs := make([]interface{}, len(s.columns))
+	for i := range s.columns {
+		valuePtrs[i] = &values[i]
+	}
+	if err := s.rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+
+	entry := make(map[string]interface{}, len(s.columns))
+	for i, col := range s.columns {
+		dbTypeName := ""
+		if s.colTypes != nil && s.colTypes[i] != nil {
+			dbTypeName = s.colTypes[i].DatabaseTypeName()
+		}
+		if b, ok := values[i].([]byte); ok && s.blobs != nil && len(b) > s.inlineLimit && isBinaryValue(b, dbTypeName) {
+			if ref, err := s.blobs.Put(b); err == nil {
+				entry[col] = ref
+				continue
+			}
+		}
+		entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
+	}
+	return entry, nil
+}
+
+// NextValues 读取下一行的原始值（按列顺序，不做显示用的转换），用于数据复制等需要保真的场景；读完时返回 io.EOF。
+func (s *RowStream) NextValues() ([]interface{}, error) {
+	if err := s.advance(); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(s.columns))
+	valuePtrs := make([]interface{}, len(s.columns))
+	for i := range s.columns {
+		valuePtrs[i] = &values[i]
+	}
+	if err := s.rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// advance 移动到下一行；当前批读完且设置了 refill 时读取下一批，读完时返回 io.EOF。
+func (s *RowStream) advance() error {
+	for {
+		if s.rows.Next() {
+			s.batchRows++
+			return nil
+		}
+		if err := s.rows.Err(); err != nil {
+			return err
+		}
+		if s.refill == nil || s.batchRows == 0 {
+			return io.EOF
+		}
+		s.rows.Close()
+		rows, err := s.refill()
+		if err != nil {
+			return err
+		}
+		s.rows, s.batchRows = rows, 0
+	}
+}
+
+// Close 关闭行流并归还连接。
+func (s *RowStream) Close() error {
+	err := s.rows.Close()
+	if s.release != nil {
+		s.release()
+		s.release = nil
+	}
+	return err
+}
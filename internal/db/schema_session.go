@@ -0,0 +1,140 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// SchemaSwitcher 定义切换活动 schema 的能力。
+//
+// 活动 schema 作用于整个缓存连接：连接池中的每个会话在执行语句前都会切换到该 schema，
+// 因此无需按 schema 拆分连接池，编辑器中未限定 schema 的语句即可解析到侧边栏当前选中的 schema。
+type SchemaSwitcher interface {
+	SetActiveSchema(schema string) error
+	ActiveSchema() string
+}
+
+// SchemaStatement 返回把会话默认 schema 切换为 schema 的语句：MySQL 使用 USE，PostgreSQL 设置 search_path。
+// schema 为空时 PostgreSQL 恢复默认 search_path；MySQL 无法取消已选择的数据库，返回错误。
+func SchemaStatement(dbType connection.ConnectionType, schema string) (string, error) {
+	switch dbType {
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		if schema == "" {
+			return "RESET search_path", nil
+		}
+		return "SET search_path TO " + quotePgIdent(schema) + ", public", nil
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypeMariaDB, "":
+		if schema == "" {
+			return "", fmt.Errorf("MySQL 无法取消已选择的数据库")
+		}
+		return "USE " + quoteMySQLIdent(schema), nil
+	default:
+		return "", fmt.Errorf("当前数据库类型不支持切换 schema: %s", dbType)
+	}
+}
+
+// quotePgIdent 使用双引号引用 PostgreSQL 标识符，并转义其中的双引号。
+func quotePgIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlSession 是 *sql.DB 与 *sql.Conn 共有的执行能力。
+type sqlSession interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// schemaSession 维护连接池的活动 schema，并在执行语句前把取出的会话切换到目标 schema。
+type schemaSession struct {
+	mu       sync.RWMutex
+	dbType   connection.ConnectionType
+	fallback string // 连接配置中的默认库，清空活动 schema 时恢复
+	active   string
+	switched bool // 是否需要逐会话切换 schema
+}
+
+// newSchemaSession 创建活动 schema 管理器。
+func newSchemaSession(dbType connection.ConnectionType, fallback string) *schemaSession {
+	return &schemaSession{dbType: dbType, fallback: fallback}
+}
+
+// set 设置活动 schema；清空时若没有默认库，则关闭空闲连接以丢弃已切换的会话。
+func (s *schemaSession) set(pool *sql.DB, schema string, maxIdle int) error {
+	schema = strings.TrimSpace(schema)
+	if schema != "" {
+		if _, err := SchemaStatement(s.dbType, schema); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = schema
+	if schema == "" && s.fallback == "" {
+		if s.switched {
+			// 空闲会话仍停留在之前的 schema，重置空闲上限以关闭它们
+			pool.SetMaxIdleConns(0)
+			pool.SetMaxIdleConns(maxIdle)
+		}
+		s.switched = false
+		return nil
+	}
+	s.switched = schema != "" || s.switched
+	return nil
+}
+
+// current 返回活动 schema（未设置时为空）。
+func (s *schemaSession) current() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// acquire 返回用于执行语句的会话；设置过活动 schema 时取出独立连接并切换 schema，调用方必须调用 release。
+func (s *schemaSession) acquire(ctx context.Context, pool *sql.DB) (sqlSession, func(), error) {
+	s.mu.RLock()
+	switched, target := s.switched, s.active
+	if target == "" {
+		target = s.fallback
+	}
+	s.mu.RUnlock()
+
+	if !switched {
+		return pool, func() {}, nil
+	}
+
+	stmt, err := SchemaStatement(s.dbType, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, stmt); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("切换 schema %s 失败：%w", target, err)
+	}
+	return conn, func() { conn.Close() }, nil
+}
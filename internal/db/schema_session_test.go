@@ -0,0 +1,89 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestSchemaStatement(t *testing.T) {
+	cases := []struct {
+		dbType connection.ConnectionType
+		schema string
+		want   string
+	}{
+		{connection.ConnectionTypeMySQL, "shop`1", "USE `shop``1`"},
+		{connection.ConnectionTypePostgreSQL, `ten"ant`, `SET search_path TO "ten""ant", public`},
+		{connection.ConnectionTypePostgreSQL, "", "RESET search_path"},
+	}
+	for _, c := range cases {
+		got, err := SchemaStatement(c.dbType, c.schema)
+		if err != nil || got != c.want {
+			t.Errorf("%s/%s: 期望 %q，得到 %q (%v)", c.dbType, c.schema, c.want, got, err)
+		}
+	}
+	if _, err := SchemaStatement(connection.ConnectionTypeMySQL, ""); err == nil {
+		t.Error("MySQL 清空数据库应返回错误")
+	}
+}
+
+func TestSchemaSessionSwitchesEachSession(t *testing.T) {
+	pool, drv := openFakeImportDB(t)
+	s := newSchemaSession(connection.ConnectionTypeMySQL, "app")
+	ctx := context.Background()
+
+	session, release, err := s.acquire(ctx, pool)
+	if err != nil || session != pool {
+		t.Fatalf("未切换时应直接使用连接池: %v", err)
+	}
+	release()
+
+	if err := s.set(pool, "tenant_a", mysqlMaxIdleConns); err != nil {
+		t.Fatalf("设置 schema 失败: %v", err)
+	}
+	if s.current() != "tenant_a" {
+		t.Fatalf("活动 schema 错误: %s", s.current())
+	}
+	for i := 0; i < 2; i++ {
+		_, release, err := s.acquire(ctx, pool)
+		if err != nil {
+			t.Fatalf("获取会话失败: %v", err)
+		}
+		release()
+	}
+
+	// 清空后恢复连接配置中的默认库
+	if err := s.set(pool, "", mysqlMaxIdleConns); err != nil {
+		t.Fatalf("清空 schema 失败: %v", err)
+	}
+	if _, release, err := s.acquire(ctx, pool); err != nil {
+		t.Fatalf("获取会话失败: %v", err)
+	} else {
+		release()
+	}
+
+	want := []string{"USE `tenant_a`", "USE `tenant_a`", "USE `app`"}
+	if len(drv.execs) != len(want) {
+		t.Fatalf("期望执行 %v，得到 %v", want, drv.execs)
+	}
+	for i := range want {
+		if drv.execs[i] != want[i] {
+			t.Errorf("第 %d 条语句期望 %s，得到 %s", i, want[i], drv.execs[i])
+		}
+	}
+}
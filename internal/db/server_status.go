@@ -0,0 +1,305 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// ServerInspector 定义服务器运行状态与配置变量的读取能力。
+type ServerInspector interface {
+	GetServerStatus() (*connection.ServerMetrics, error)
+	GetServerVariables() (*connection.ServerMetrics, error)
+}
+
+// 服务器指标的通用分类，MySQL 与 PostgreSQL 的原始名称都映射到这些分类。
+const (
+	MetricCategoryServer      = "server"
+	MetricCategoryConnections = "connections"
+	MetricCategoryQueries     = "queries"
+	MetricCategoryTraffic     = "traffic"
+	MetricCategoryStorage     = "storage"
+	MetricCategoryCache       = "cache"
+	MetricCategoryLocks       = "locks"
+	MetricCategoryReplication = "replication"
+	MetricCategoryLogging     = "logging"
+	MetricCategoryCharset     = "charset"
+	MetricCategorySecurity    = "security"
+	MetricCategoryOther       = "other"
+)
+
+// metricCategoryOrder 是分类的展示顺序。
+var metricCategoryOrder = []string{
+	MetricCategoryServer, MetricCategoryConnections, MetricCategoryQueries, MetricCategoryTraffic,
+	MetricCategoryStorage, MetricCategoryCache, MetricCategoryLocks, MetricCategoryReplication,
+	MetricCategoryLogging, MetricCategoryCharset, MetricCategorySecurity, MetricCategoryOther,
+}
+
+// categoryRule 按名称前缀（小写）归类，规则按顺序匹配，先命中者生效。
+type categoryRule struct {
+	prefix   string
+	category string
+}
+
+var mysqlStatusRules = []categoryRule{
+	{"uptime", MetricCategoryServer},
+	{"threads_", MetricCategoryConnections},
+	{"connections", MetricCategoryConnections},
+	{"max_used_connections", MetricCategoryConnections},
+	{"aborted_", MetricCategoryConnections},
+	{"connection_errors_", MetricCategoryConnections},
+	{"com_", MetricCategoryQueries},
+	{"questions", MetricCategoryQueries},
+	{"queries", MetricCategoryQueries},
+	{"slow_queries", MetricCategoryQueries},
+	{"select_", MetricCategoryQueries},
+	{"sort_", MetricCategoryQueries},
+	{"handler_", MetricCategoryQueries},
+	{"bytes_", MetricCategoryTraffic},
+	{"innodb_buffer_pool_", MetricCategoryCache},
+	{"innodb_row_lock_", MetricCategoryLocks},
+	{"innodb_", MetricCategoryStorage},
+	{"created_tmp_", MetricCategoryStorage},
+	{"open", MetricCategoryCache},
+	{"table_open_cache_", MetricCategoryCache},
+	{"qcache_", MetricCategoryCache},
+	{"key_", MetricCategoryCache},
+	{"table_locks_", MetricCategoryLocks},
+	{"binlog_", MetricCategoryReplication},
+	{"slave_", MetricCategoryReplication},
+	{"replica_", MetricCategoryReplication},
+	{"rpl_", MetricCategoryReplication},
+	{"ssl_", MetricCategorySecurity},
+}
+
+var mysqlVariableRules = []categoryRule{
+	{"version", MetricCategoryServer},
+	{"hostname", MetricCategoryServer},
+	{"port", MetricCategoryServer},
+	{"datadir", MetricCategoryServer},
+	{"server_id", MetricCategoryReplication},
+	{"max_connections", MetricCategoryConnections},
+	{"max_user_connections", MetricCategoryConnections},
+	{"wait_timeout", MetricCategoryConnections},
+	{"interactive_timeout", MetricCategoryConnections},
+	{"connect_timeout", MetricCategoryConnections},
+	{"thread_", MetricCategoryConnections},
+	{"max_allowed_packet", MetricCategoryTraffic},
+	{"net_", MetricCategoryTraffic},
+	{"innodb_buffer_pool_", MetricCategoryCache},
+	{"innodb_lock_", MetricCategoryLocks},
+	{"innodb_", MetricCategoryStorage},
+	{"query_cache_", MetricCategoryCache},
+	{"table_open_cache", MetricCategoryCache},
+	{"key_buffer_", MetricCategoryCache},
+	{"tmp_table_size", MetricCategoryStorage},
+	{"max_heap_table_size", MetricCategoryStorage},
+	{"lock_wait_timeout", MetricCategoryLocks},
+	{"transaction_isolation", MetricCategoryLocks},
+	{"tx_isolation", MetricCategoryLocks},
+	{"log_bin", MetricCategoryReplication},
+	{"binlog_", MetricCategoryReplication},
+	{"gtid_", MetricCategoryReplication},
+	{"relay_log", MetricCategoryReplication},
+	{"sync_binlog", MetricCategoryReplication},
+	{"slow_query_log", MetricCategoryLogging},
+	{"long_query_time", MetricCategoryLogging},
+	{"general_log", MetricCategoryLogging},
+	{"log_", MetricCategoryLogging},
+	{"character_set_", MetricCategoryCharset},
+	{"collation_", MetricCategoryCharset},
+	{"ssl_", MetricCategorySecurity},
+	{"tls_", MetricCategorySecurity},
+	{"require_secure_transport", MetricCategorySecurity},
+	{"default_authentication_plugin", MetricCategorySecurity},
+	{"sql_mode", MetricCategoryQueries},
+	{"max_execution_time", MetricCategoryQueries},
+	{"optimizer_", MetricCategoryQueries},
+	{"sort_buffer_size", MetricCategoryQueries},
+	{"join_buffer_size", MetricCategoryQueries},
+}
+
+// categorize 按规则返回名称所属分类，未命中时归为 other。
+func categorize(name string, rules []categoryRule) string {
+	lower := strings.ToLower(name)
+	for _, r := range rules {
+		if strings.HasPrefix(lower, r.prefix) {
+			return r.category
+		}
+	}
+	return MetricCategoryOther
+}
+
+// newServerMetrics 按分类顺序与名称排序整理指标，并只保留出现过的分类。
+func newServerMetrics(items []*connection.ServerMetric) *connection.ServerMetrics {
+	rank := make(map[string]int, len(metricCategoryOrder))
+	for i, c := range metricCategoryOrder {
+		rank[c] = i
+	}
+	seen := make(map[string]bool)
+	var extra []string
+	for _, item := range items {
+		if _, ok := rank[item.Category]; !ok && !seen[item.Category] {
+			extra = append(extra, item.Category)
+		}
+		seen[item.Category] = true
+	}
+	sort.Strings(extra)
+	for i, c := range extra {
+		rank[c] = len(metricCategoryOrder) + i
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if rank[items[i].Category] != rank[items[j].Category] {
+			return rank[items[i].Category] < rank[items[j].Category]
+		}
+		return items[i].Name < items[j].Name
+	})
+	categories := make([]string, 0, len(seen))
+	for _, c := range append(append([]string{}, metricCategoryOrder...), extra...) {
+		if seen[c] {
+			categories = append(categories, c)
+		}
+	}
+	return &connection.ServerMetrics{Categories: categories, Items: items}
+}
+
+// mysqlServerMetrics 将 SHOW GLOBAL STATUS/VARIABLES 的结果转换为指标。
+func mysqlServerMetrics(rows []map[string]interface{}, rules []categoryRule) *connection.ServerMetrics {
+	items := make([]*connection.ServerMetric, 0, len(rows))
+	for _, row := range rows {
+		name := fmt.Sprintf("%v", row["Variable_name"])
+		items = append(items, &connection.ServerMetric{
+			Name:     name,
+			Value:    stringOrEmpty(row["Value"]),
+			Category: categorize(name, rules),
+		})
+	}
+	return newServerMetrics(items)
+}
+
+// GetServerStatus 读取 SHOW GLOBAL STATUS 并按分类整理
+func (m *MySQLDB) GetServerStatus() (*connection.ServerMetrics, error) {
+	data, _, err := m.Query("SHOW GLOBAL STATUS")
+	if err != nil {
+		return nil, err
+	}
+	return mysqlServerMetrics(data, mysqlStatusRules), nil
+}
+
+// GetServerVariables 读取 SHOW GLOBAL VARIABLES 并按分类整理
+func (m *MySQLDB) GetServerVariables() (*connection.ServerMetrics, error) {
+	data, _, err := m.Query("SHOW GLOBAL VARIABLES")
+	if err != nil {
+		return nil, err
+	}
+	return mysqlServerMetrics(data, mysqlVariableRules), nil
+}
+
+// GetServerStatus 汇总 pg_stat_activity、pg_stat_database 等统计视图
+func (p *PostgresDB) GetServerStatus() (*connection.ServerMetrics, error) {
+	return queryPostgresStatus(p.Query)
+}
+
+// GetServerVariables 读取 pg_settings 并按分类整理
+func (p *PostgresDB) GetServerVariables() (*connection.ServerMetrics, error) {
+	return queryPostgresVariables(p.Query)
+}
+
+// pgStatusQueries 是 PostgreSQL 状态来源：每行的各列展开为指标，分类由来源决定。
+var pgStatusQueries = []struct {
+	category string
+	query    string
+}{
+	{MetricCategoryServer, `SELECT extract(epoch FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds, version() AS version`},
+	{MetricCategoryConnections, `SELECT count(*) AS total, count(*) FILTER (WHERE state = 'active') AS active,
+       count(*) FILTER (WHERE state = 'idle') AS idle, count(*) FILTER (WHERE state LIKE 'idle in transaction%') AS idle_in_transaction,
+       count(*) FILTER (WHERE wait_event_type = 'Lock') AS waiting_on_lock,
+       current_setting('max_connections')::int AS max_connections
+FROM pg_stat_activity WHERE backend_type = 'client backend'`},
+	{MetricCategoryQueries, `SELECT xact_commit, xact_rollback, tup_returned, tup_fetched, tup_inserted, tup_updated, tup_deleted,
+       conflicts, deadlocks, temp_files, temp_bytes
+FROM pg_stat_database WHERE datname = current_database()`},
+	{MetricCategoryCache, `SELECT blks_read, blks_hit,
+       CASE WHEN blks_read + blks_hit = 0 THEN NULL ELSE round(blks_hit * 100.0 / (blks_read + blks_hit), 2) END AS hit_ratio_percent
+FROM pg_stat_database WHERE datname = current_database()`},
+	{MetricCategoryStorage, `SELECT pg_database_size(current_database()) AS database_size_bytes`},
+	{MetricCategoryLocks, `SELECT count(*) AS locks_held, count(*) FILTER (WHERE NOT granted) AS locks_waiting FROM pg_locks`},
+}
+
+// queryPostgresStatus 汇总 pg_stat_activity、pg_stat_database 等视图为指标；单个来源失败（如权限不足）时跳过该来源。
+func queryPostgresStatus(query queryFunc) (*connection.ServerMetrics, error) {
+	var items []*connection.ServerMetric
+	var firstErr error
+	for _, src := range pgStatusQueries {
+		rows, columns, err := query(src.query)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, row := range rows {
+			for _, col := range columns {
+				items = append(items, &connection.ServerMetric{Name: col, Value: stringOrEmpty(row[col]), Category: src.category})
+			}
+		}
+	}
+	if len(items) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return newServerMetrics(items), nil
+}
+
+// pgCategoryRules 将 pg_settings.category 的首段映射为通用分类。
+var pgCategoryRules = []categoryRule{
+	{"connections and authentication / authentication", MetricCategorySecurity},
+	{"connections and authentication / ssl", MetricCategorySecurity},
+	{"connections and authentication", MetricCategoryConnections},
+	{"resource usage / memory", MetricCategoryCache},
+	{"resource usage", MetricCategoryStorage},
+	{"write-ahead log", MetricCategoryStorage},
+	{"replication", MetricCategoryReplication},
+	{"query tuning", MetricCategoryQueries},
+	{"statistics", MetricCategoryQueries},
+	{"reporting and logging", MetricCategoryLogging},
+	{"lock management", MetricCategoryLocks},
+	{"client connection defaults / locale", MetricCategoryCharset},
+	{"file locations", MetricCategoryServer},
+	{"preset options", MetricCategoryServer},
+}
+
+// queryPostgresVariables 读取 pg_settings，并将其自带的分类映射为通用分类。
+func queryPostgresVariables(query queryFunc) (*connection.ServerMetrics, error) {
+	rows, _, err := query(`SELECT name, setting, COALESCE(unit, '') AS unit, category, short_desc FROM pg_settings`)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*connection.ServerMetric, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, &connection.ServerMetric{
+			Name:        fmt.Sprintf("%v", row["name"]),
+			Value:       stringOrEmpty(row["setting"]),
+			Unit:        stringOrEmpty(row["unit"]),
+			Category:    categorize(stringOrEmpty(row["category"]), pgCategoryRules),
+			Description: stringOrEmpty(row["short_desc"]),
+		})
+	}
+	return newServerMetrics(items), nil
+}
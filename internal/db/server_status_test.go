@@ -0,0 +1,116 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMySQLServerMetricsCategorized(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"Variable_name": "Com_select", "Value": "42"},
+		{"Variable_name": "Uptime", "Value": "3600"},
+		{"Variable_name": "Threads_connected", "Value": "5"},
+		{"Variable_name": "Innodb_buffer_pool_reads", "Value": "7"},
+		{"Variable_name": "Innodb_rows_read", "Value": "9"},
+		{"Variable_name": "Mystery_counter", "Value": nil},
+	}
+	metrics := mysqlServerMetrics(rows, mysqlStatusRules)
+
+	if got := strings.Join(metrics.Categories, ","); got != "server,connections,queries,storage,cache,other" {
+		t.Errorf("分类顺序不符: %s", got)
+	}
+	want := map[string]string{
+		"Com_select": MetricCategoryQueries, "Uptime": MetricCategoryServer, "Threads_connected": MetricCategoryConnections,
+		"Innodb_buffer_pool_reads": MetricCategoryCache, "Innodb_rows_read": MetricCategoryStorage, "Mystery_counter": MetricCategoryOther,
+	}
+	for _, item := range metrics.Items {
+		if want[item.Name] != item.Category {
+			t.Errorf("%s 应归为 %s，得到 %s", item.Name, want[item.Name], item.Category)
+		}
+	}
+	if metrics.Items[0].Name != "Uptime" || metrics.Items[len(metrics.Items)-1].Value != "" {
+		t.Errorf("排序或空值处理不符: %+v", metrics.Items)
+	}
+}
+
+func TestQueryPostgresStatusSkipsFailedSources(t *testing.T) {
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		if strings.Contains(q, "pg_locks") {
+			return nil, nil, errors.New("permission denied")
+		}
+		return []map[string]interface{}{{"n": int64(1)}}, []string{"n"}, nil
+	}
+	metrics, err := queryPostgresStatus(query)
+	if err != nil {
+		t.Fatalf("queryPostgresStatus 失败: %v", err)
+	}
+	for _, c := range metrics.Categories {
+		if c == MetricCategoryLocks {
+			t.Error("失败的来源不应出现在分类中")
+		}
+	}
+	if len(metrics.Items) != len(pgStatusQueries)-1 {
+		t.Errorf("指标数不符: %d", len(metrics.Items))
+	}
+
+	failing := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return nil, nil, errors.New("down")
+	}
+	if _, err := queryPostgresStatus(failing); err == nil {
+		t.Error("全部来源失败时应返回错误")
+	}
+}
+
+func TestQueryPostgresVariablesMapsCategory(t *testing.T) {
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		return []map[string]interface{}{
+			{"name": "shared_buffers", "setting": "16384", "unit": "8kB", "category": "Resource Usage / Memory", "short_desc": "x"},
+			{"name": "ssl", "setting": "on", "unit": "", "category": "Connections and Authentication / SSL", "short_desc": "y"},
+		}, nil, nil
+	}
+	metrics, err := queryPostgresVariables(query)
+	if err != nil {
+		t.Fatalf("queryPostgresVariables 失败: %v", err)
+	}
+	if metrics.Items[0].Name != "shared_buffers" || metrics.Items[0].Category != MetricCategoryCache || metrics.Items[0].Unit != "8kB" {
+		t.Errorf("shared_buffers 映射不符: %+v", metrics.Items[0])
+	}
+	if metrics.Items[1].Category != MetricCategorySecurity {
+		t.Errorf("ssl 应归为 security: %+v", metrics.Items[1])
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+		ok   bool
+	}{
+		{"8.0.36-log", "8.0.36", 0, true},
+		{"5.7.44", "8.0.1", -1, true},
+		{"16.2 (Debian 16.2-1.pgdg120+2)", "15.6", 1, true},
+		{"10.11.6-MariaDB", "10.11", 1, true},
+		{"", "8.0", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := CompareVersions(c.a, c.b)
+		if got != c.want || ok != c.ok {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v", c.a, c.b, got, ok)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// versionPattern 匹配版本字符串中的首个数字版本号，如 "8.0.36-log"、"PostgreSQL 16.2 on x86_64"。
+var versionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// ServerVersionQuery 返回读取服务器版本的语句，结果只有一列 version；不支持的类型返回空字符串。
+func ServerVersionQuery(dbType connection.ConnectionType) string {
+	switch dbType {
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypeMariaDB, "":
+		return "SELECT VERSION() AS version"
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		return "SELECT current_setting('server_version') AS version"
+	case connection.ConnectionTypeSQLServer:
+		return "SELECT CAST(SERVERPROPERTY('ProductVersion') AS NVARCHAR(128)) AS version"
+	case connection.ConnectionTypeSQLite:
+		return "SELECT sqlite_version() AS version"
+	case connection.ConnectionTypeTDengine:
+		return "SELECT SERVER_VERSION() AS version"
+	default:
+		return ""
+	}
+}
+
+// ServerVersion 读取服务器版本字符串。
+func ServerVersion(conn Database, dbType connection.ConnectionType) (string, error) {
+	query := ServerVersionQuery(dbType)
+	if query == "" {
+		return "", fmt.Errorf("不支持读取 %s 的服务器版本", dbType)
+	}
+	rows, columns, err := conn.Query(query)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 || len(columns) == 0 {
+		return "", fmt.Errorf("未读取到服务器版本")
+	}
+	switch v := rows[0][columns[0]].(type) {
+	case []byte:
+		return string(v), nil
+	default:
+		return stringOrEmpty(v), nil
+	}
+}
+
+// CompareVersions 按主版本、次版本、修订号比较两个版本字符串，返回 -1、0 或 1；
+// ok 为 false 表示其中一个无法解析出版本号。
+func CompareVersions(a, b string) (cmp int, ok bool) {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, true
+		case va[i] > vb[i]:
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// parseVersion 提取版本字符串中的主版本、次版本与修订号，缺失部分为 0。
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return v, false
+	}
+	for i := range v {
+		if m[i+1] != "" {
+			v[i], _ = strconv.Atoi(m[i+1])
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// SQLBuilder 按数据库方言生成 SQL 片段，供服务层与各导入导出模块拼装语句时统一引用标识符与限制行数。
+type SQLBuilder interface {
+	// QuoteIdent 引用单个标识符，转义其中的引号。
+	QuoteIdent(name string) string
+	// QuoteTable 引用可带 schema 前缀的表名。
+	QuoteTable(name string) string
+	// Placeholder 返回第 n 个参数占位符（从 1 开始）。
+	Placeholder(n int) string
+	// Limit 返回限制结果行数的子句（含前导空格），ordered 表示语句已有 ORDER BY。
+	Limit(n int, ordered bool) string
+	// LimitOffset 返回跳过 offset 行后限制行数的子句（含前导空格）。
+	LimitOffset(n int, offset int64, ordered bool) string
+}
+
+// sqlServerDialect SQL Server 方言。
+var sqlServerDialect = sqlDialect{
+	quoteIdent:  quoteSQLServerIdent,
+	placeholder: func(n int) string { return "@p" + strconv.Itoa(n) },
+	maxParams:   2100,
+	offsetFetch: true,
+	json:        jsonSQLServer,
+	nullsLow:    true,
+}
+
+// ansiDialect 双引号标识符与 ? 占位符的通用方言，用于 SQLite、达梦与自定义驱动。
+var ansiDialect = sqlDialect{
+	quoteIdent:  quotePgIdent,
+	placeholder: func(int) string { return "?" },
+	maxParams:   999,
+	json:        jsonSQLite,
+	nullsLow:    true,
+}
+
+// NewSQLBuilder 返回数据库类型对应的 SQL 构造器。
+func NewSQLBuilder(dbType connection.ConnectionType) SQLBuilder {
+	return dialectFor(dbType)
+}
+
+// QuoteIdent 引用单个标识符。
+func (d sqlDialect) QuoteIdent(name string) string {
+	return d.quoteIdent(name)
+}
+
+// QuoteTable 引用可带 schema 前缀的表名。
+func (d sqlDialect) QuoteTable(name string) string {
+	return d.quoteTable(name)
+}
+
+// Placeholder 返回第 n 个参数占位符。
+func (d sqlDialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+// Limit 返回限制行数的子句：多数方言为 LIMIT，SQL Server 为 OFFSET 0 ROWS FETCH NEXT，
+// 语句没有 ORDER BY 时补充 ORDER BY (SELECT NULL)。
+func (d sqlDialect) Limit(n int, ordered bool) string {
+	if !d.offsetFetch {
+		return " LIMIT " + strconv.Itoa(n)
+	}
+	return d.LimitOffset(n, 0, ordered)
+}
+
+// LimitOffset 返回跳过 offset 行后限制行数的子句。
+func (d sqlDialect) LimitOffset(n int, offset int64, ordered bool) string {
+	if !d.offsetFetch {
+		return " LIMIT " + strconv.Itoa(n) + " OFFSET " + strconv.FormatInt(offset, 10)
+	}
+	clause := " OFFSET " + strconv.FormatInt(offset, 10) + " ROWS FETCH NEXT " + strconv.Itoa(n) + " ROWS ONLY"
+	if !ordered {
+		return " ORDER BY (SELECT NULL)" + clause
+	}
+	return clause
+}
+
+// quoteSQLServerIdent 使用方括号引用 SQL Server 标识符，并转义其中的右方括号。
+func quoteSQLServerIdent(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
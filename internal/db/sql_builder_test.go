@@ -0,0 +1,61 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestSQLBuilder(t *testing.T) {
+	cases := []struct {
+		dbType    connection.ConnectionType
+		ident     string
+		limit     string
+		unordered string
+		page      string
+	}{
+		{connection.ConnectionTypeMySQL, "`order list`", " LIMIT 10", " LIMIT 10", " LIMIT 10 OFFSET 20"},
+		{connection.ConnectionTypePostgreSQL, `"order list"`, " LIMIT 10", " LIMIT 10", " LIMIT 10 OFFSET 20"},
+		{connection.ConnectionTypeSQLite, `"order list"`, " LIMIT 10", " LIMIT 10", " LIMIT 10 OFFSET 20"},
+		{connection.ConnectionTypeSQLServer, "[order list]",
+			" OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+			" ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+			" OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+	}
+	for _, c := range cases {
+		b := NewSQLBuilder(c.dbType)
+		if got := b.QuoteIdent("order list"); got != c.ident {
+			t.Errorf("%s: QuoteIdent 得到 %s", c.dbType, got)
+		}
+		if got := b.Limit(10, true); got != c.limit {
+			t.Errorf("%s: Limit 得到 %q", c.dbType, got)
+		}
+		if got := b.Limit(10, false); got != c.unordered {
+			t.Errorf("%s: 无排序 Limit 得到 %q", c.dbType, got)
+		}
+		if got := b.LimitOffset(10, 20, true); got != c.page {
+			t.Errorf("%s: LimitOffset 得到 %q", c.dbType, got)
+		}
+	}
+
+	if got := NewSQLBuilder(connection.ConnectionTypeSQLServer).QuoteIdent("a]b"); got != "[a]]b]" {
+		t.Errorf("SQL Server 右方括号未转义: %s", got)
+	}
+	if got := NewSQLBuilder(connection.ConnectionTypeSQLServer).Placeholder(3); got != "@p3" {
+		t.Errorf("SQL Server 占位符不符: %s", got)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteDriverName 是 modernc.org/sqlite（纯 Go 实现，无需 cgo）注册的驱动名。
+const sqliteDriverName = "sqlite"
+
+// SQLiteDB 是 SQLite 本地文件数据库的实现：查询与执行复用通用适配器，元数据读取 sqlite_master 与 pragma 表值函数。
+type SQLiteDB struct {
+	*GenericSQLDB
+}
+
+// NewSQLiteDB 创建 SQLite 实例。
+func NewSQLiteDB() *SQLiteDB {
+	return &SQLiteDB{GenericSQLDB: NewGenericSQLDB(sqliteDriverName)}
+}
+
+// Connect 打开数据库文件：配置了 DSN 时使用 DSN，否则以 Database 作为文件路径。
+func (s *SQLiteDB) Connect(config *connection.ConnectionConfig) error {
+	local := config.Clone()
+	if strings.TrimSpace(local.DSN) == "" {
+		local.DSN = local.Database
+	}
+	if strings.TrimSpace(local.DSN) == "" {
+		return fmt.Errorf("SQLite 连接需要指定数据库文件路径")
+	}
+	// 本地文件无需代理
+	local.Proxy = nil
+	return s.GenericSQLDB.Connect(local)
+}
+
+// GetDatabases SQLite 只有主库 main。
+func (s *SQLiteDB) GetDatabases(ctx context.Context) ([]string, error) {
+	return []string{"main"}, nil
+}
+
+// GetTables 读取 sqlite_master 中的用户表，排除 sqlite_ 开头的内部表。
+func (s *SQLiteDB) GetTables(ctx context.Context, dbName string) ([]string, error) {
+	return s.queryStrings(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY name`)
+}
+
+// GetCreateStatement 返回 sqlite_master 中保存的建表语句。
+func (s *SQLiteDB) GetCreateStatement(ctx context.Context, dbName, tableName string) (string, error) {
+	data, _, err := s.QueryContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName)
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("表不存在: %s", tableName)
+	}
+	return stringOrEmpty(data[0]["sql"]), nil
+}
+
+// GetColumns 通过 pragma_table_info 读取列信息。
+func (s *SQLiteDB) GetColumns(ctx context.Context, dbName, tableName string) ([]*connection.ColumnDefinition, error) {
+	data, _, err := s.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinition, 0, len(data))
+	for _, row := range data {
+		col := &connection.ColumnDefinition{
+			Name:     stringOrEmpty(row["name"]),
+			Type:     strings.ToLower(stringOrEmpty(row["type"])),
+			Nullable: "YES",
+		}
+		if sqliteInt(row["notnull"]) != 0 {
+			col.Nullable = "NO"
+		}
+		if sqliteInt(row["pk"]) > 0 {
+			col.Key = "PRI"
+		}
+		if v := row["dflt_value"]; v != nil {
+			def := stringOrEmpty(v)
+			col.Default = &def
+		}
+		col.DefaultExpression = defaultExpression(col.Default)
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// GetAllColumns 读取全部用户表的列。
+func (s *SQLiteDB) GetAllColumns(ctx context.Context, dbName string) ([]*connection.ColumnDefinitionWithTable, error) {
+	data, _, err := s.QueryContext(ctx, `SELECT m.name AS table_name, p.name AS column_name, p.type AS data_type
+FROM sqlite_master m JOIN pragma_table_info(m.name) p
+WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite\_%' ESCAPE '\'
+ORDER BY m.name, p.cid`)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*connection.ColumnDefinitionWithTable, 0, len(data))
+	for _, row := range data {
+		columns = append(columns, &connection.ColumnDefinitionWithTable{
+			TableName: stringOrEmpty(row["table_name"]),
+			Name:      stringOrEmpty(row["column_name"]),
+			Type:      strings.ToLower(stringOrEmpty(row["data_type"])),
+		})
+	}
+	return columns, nil
+}
+
+// GetIndexes 通过 pragma_index_list 与 pragma_index_info 读取索引，主键索引命名为 PRIMARY。
+func (s *SQLiteDB) GetIndexes(ctx context.Context, dbName, tableName string) ([]*connection.IndexDefinition, error) {
+	data, _, err := s.QueryContext(ctx, `SELECT il.name AS index_name, il."unique" AS is_unique, il.origin, ii.seqno, ii.name AS column_name
+FROM pragma_index_list(?) il JOIN pragma_index_info(il.name) ii
+ORDER BY il.name, ii.seqno`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]*connection.IndexDefinition, 0, len(data))
+	for _, row := range data {
+		idx := &connection.IndexDefinition{
+			Name:       stringOrEmpty(row["index_name"]),
+			ColumnName: stringOrEmpty(row["column_name"]),
+			SeqInIndex: int(sqliteInt(row["seqno"])) + 1,
+			IndexType:  "BTREE",
+		}
+		if sqliteInt(row["is_unique"]) == 0 {
+			idx.NonUnique = 1
+		}
+		if stringOrEmpty(row["origin"]) == "pk" {
+			idx.Name = "PRIMARY"
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// GetForeignKeys 通过 pragma_foreign_key_list 读取外键，约束以 "fk_<表名>_<id>" 命名。
+func (s *SQLiteDB) GetForeignKeys(ctx context.Context, dbName, tableName string) ([]*connection.ForeignKeyDefinition, error) {
+	data, _, err := s.QueryContext(ctx, `SELECT id, "table" AS ref_table, "from" AS column_name, "to" AS ref_column FROM pragma_foreign_key_list(?) ORDER BY id, seq`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*connection.ForeignKeyDefinition, 0, len(data))
+	for _, row := range data {
+		name := fmt.Sprintf("fk_%s_%d", tableName, sqliteInt(row["id"]))
+		keys = append(keys, &connection.ForeignKeyDefinition{
+			Name:          name,
+			ColumnName:    stringOrEmpty(row["column_name"]),
+			RefTableName:  stringOrEmpty(row["ref_table"]),
+			RefColumnName: stringOrEmpty(row["ref_column"]),
+			ConstrainName: name,
+		})
+	}
+	return keys, nil
+}
+
+// sqliteInt 将 pragma 返回的整数值转换为 int64，无法解析时返回 0。
+func sqliteInt(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case []byte:
+		i, _ := strconv.ParseInt(string(n), 10, 64)
+		return i
+	default:
+		i, _ := strconv.ParseInt(stringOrEmpty(v), 10, 64)
+		return i
+	}
+}
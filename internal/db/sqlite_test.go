@@ -0,0 +1,74 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestSQLiteOpensFileDatabase(t *testing.T) {
+	ctx := context.Background()
+	lite := NewSQLiteDB()
+	path := filepath.Join(t.TempDir(), "scratch.db")
+	if err := lite.Connect(&connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: path}); err != nil {
+		t.Fatalf("打开 SQLite 数据库失败: %v", err)
+	}
+	defer lite.Close()
+
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'anon')`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id))`,
+		`CREATE UNIQUE INDEX idx_users_name ON users(name)`,
+	} {
+		if _, err := lite.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("执行 %q 失败: %v", stmt, err)
+		}
+	}
+	if n, err := lite.ExecContext(ctx, "INSERT INTO users (id, name) VALUES (?, ?), (?, ?)", 1, "a", 2, "b"); err != nil || n != 2 {
+		t.Fatalf("插入失败: %v，影响行数 %d", err, n)
+	}
+
+	tables, err := lite.GetTables(ctx, "main")
+	if err != nil || len(tables) != 2 || tables[0] != "orders" || tables[1] != "users" {
+		t.Fatalf("GetTables() = %v, %v", tables, err)
+	}
+
+	columns, err := lite.GetColumns(ctx, "main", "users")
+	if err != nil || len(columns) != 2 {
+		t.Fatalf("GetColumns() = %v, %v", columns, err)
+	}
+	if columns[0].Key != "PRI" || columns[1].Nullable != "NO" || columns[1].Default == nil || *columns[1].Default != "'anon'" {
+		t.Errorf("列信息不符: %+v %+v", columns[0], columns[1])
+	}
+
+	indexes, err := lite.GetIndexes(ctx, "main", "users")
+	if err != nil || len(indexes) != 1 || indexes[0].Name != "idx_users_name" || indexes[0].NonUnique != 0 {
+		t.Fatalf("GetIndexes() = %v, %v", indexes, err)
+	}
+
+	keys, err := lite.GetForeignKeys(ctx, "main", "orders")
+	if err != nil || len(keys) != 1 || keys[0].RefTableName != "users" || keys[0].ColumnName != "user_id" {
+		t.Fatalf("GetForeignKeys() = %v, %v", keys, err)
+	}
+
+	rows, _, err := lite.QueryContext(ctx, "SELECT name FROM users ORDER BY id")
+	if err != nil || len(rows) != 2 || stringOrEmpty(rows[1]["name"]) != "b" {
+		t.Fatalf("QueryContext() = %v, %v", rows, err)
+	}
+}
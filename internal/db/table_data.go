@@ -0,0 +1,505 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+)
+
+const (
+	// DefaultTableDataPageSize 表数据浏览的默认每页行数。
+	DefaultTableDataPageSize = 100
+	// MaxTableDataPageSize 表数据浏览的每页行数上限。
+	MaxTableDataPageSize = 1000
+)
+
+// TableDataReader 定义按页读取表数据（服务端排序与过滤）的能力。
+type TableDataReader interface {
+	GetTableData(ctx context.Context, dbName, tableName string, opts *connection.TableDataOptions) (*connection.TableDataPage, error)
+}
+
+// tableDataQuery 是编译后的分页查询与计数查询，两者共享过滤参数。
+type tableDataQuery struct {
+	selectSQL   string
+	countSQL    string // 键分页时为空，不计数
+	args        []any  // 计数查询参数（仅过滤条件）
+	selectArgs  []any  // 分页查询参数（过滤条件与键分页条件）
+	page        int
+	pageSize    int
+	seekColumns []string // 键分页定位列，非键分页时为空
+	seekBinary  []bool   // 定位列是否为二进制类型，与 seekColumns 一一对应
+	computed    []string // 计算列名，按追加顺序
+}
+
+// buildTableDataQuery 将分页、排序与过滤参数编译为参数化查询。
+// 列名必须存在于 columns 中，未指定排序时按主键排序以保证分页稳定；
+// 计算列的表达式在拼入语句前按 columns 校验，过滤与排序时直接使用表达式。
+func buildTableDataQuery(d sqlDialect, table string, columns []*connection.ColumnDefinition, opts *connection.TableDataOptions) (*tableDataQuery, error) {
+	if opts == nil {
+		opts = &connection.TableDataOptions{}
+	}
+	known := make(map[string]bool, len(columns))
+	defs := make(map[string]*connection.ColumnDefinition, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+		defs[col.Name] = col
+	}
+
+	q := &tableDataQuery{page: max(opts.Page, 1), pageSize: opts.PageSize}
+	computed, err := compileComputedColumns(d, columns, opts.Computed)
+	if err != nil {
+		return nil, err
+	}
+	selectList := "*"
+	for _, c := range opts.Computed {
+		selectList += ", " + computed[c.Name] + " AS " + d.quoteIdent(c.Name)
+		q.computed = append(q.computed, c.Name)
+	}
+	if q.pageSize <= 0 {
+		q.pageSize = DefaultTableDataPageSize
+	}
+	q.pageSize = min(q.pageSize, MaxTableDataPageSize)
+
+	var where []string
+	n := 0
+	for i, f := range opts.Filters {
+		var (
+			clause string
+			args   []any
+			err    error
+		)
+		if expr, ok := computed[f.Column]; ok {
+			if strings.TrimSpace(f.Path) != "" {
+				return nil, fmt.Errorf("过滤条件 %d：计算列 %s 不支持 JSON 路径", i+1, f.Column)
+			}
+			clause, args, err = compileTableFilterExpr(d, expr, f, &n)
+		} else if !known[f.Column] {
+			return nil, fmt.Errorf("过滤条件 %d 的列不存在: %s", i+1, f.Column)
+		} else {
+			clause, args, err = compileTableFilter(d, f, &n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("过滤条件 %d（%s）无效：%w", i+1, f.Column, err)
+		}
+		where = append(where, clause)
+		q.args = append(q.args, args...)
+	}
+
+	sorts := make([]connection.TableSort, 0, len(opts.Sorts))
+	for _, s := range opts.Sorts {
+		if _, ok := computed[s.Column]; ok && opts.Keyset {
+			return nil, fmt.Errorf("键分页不能按计算列 %s 排序", s.Column)
+		}
+		if _, ok := computed[s.Column]; !ok && !known[s.Column] {
+			return nil, fmt.Errorf("排序列不存在: %s", s.Column)
+		}
+		sorts = append(sorts, s)
+	}
+	if len(sorts) == 0 || opts.Keyset {
+		sorts = appendPrimaryKeySorts(sorts, columns)
+	}
+
+	var orderBy []string
+	for _, s := range sorts {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		ref := d.quoteIdent(s.Column)
+		if expr, ok := computed[s.Column]; ok {
+			ref = expr
+		}
+		orderBy = append(orderBy, ref+" "+dir)
+	}
+
+	filter := ""
+	if len(where) > 0 {
+		sep := " AND "
+		if opts.MatchAny {
+			sep = " OR "
+		}
+		filter = strings.Join(where, sep)
+	}
+	from := " FROM " + table
+	if filter != "" {
+		from += " WHERE " + filter
+	}
+	q.selectArgs = q.args
+
+	// 键分页只向后翻页，不执行代价随表大小增长的 COUNT(*)
+	if !opts.Keyset {
+		q.countSQL = "SELECT COUNT(*)" + from
+	} else {
+		if !hasPrimaryKey(columns) {
+			return nil, fmt.Errorf("表没有主键，无法使用键分页")
+		}
+		for _, s := range sorts {
+			q.seekColumns = append(q.seekColumns, s.Column)
+			q.seekBinary = append(q.seekBinary, isBinaryColumn(defs[s.Column]))
+		}
+		if len(opts.After) > 0 {
+			if len(opts.After) != len(sorts) {
+				return nil, fmt.Errorf("after 应包含 %d 个值（%s），实际为 %d 个", len(sorts), strings.Join(q.seekColumns, ", "), len(opts.After))
+			}
+			after, err := decodeSeekValues(q, opts.After)
+			if err != nil {
+				return nil, err
+			}
+			seek, args, err := compileSeek(d, sorts, defs, after, &n)
+			if err != nil {
+				return nil, err
+			}
+			if filter == "" {
+				from = " FROM " + table + " WHERE " + seek
+			} else {
+				from = " FROM " + table + " WHERE (" + filter + ") AND " + seek
+			}
+			q.selectArgs = append(append([]any{}, q.args...), args...)
+		}
+	}
+
+	q.selectSQL = "SELECT " + selectList + from
+	if len(orderBy) > 0 {
+		q.selectSQL += " ORDER BY " + strings.Join(orderBy, ", ")
+	}
+	if opts.Keyset {
+		q.selectSQL += d.Limit(q.pageSize, len(orderBy) > 0)
+	} else {
+		q.selectSQL += d.LimitOffset(q.pageSize, int64(q.page-1)*int64(q.pageSize), len(orderBy) > 0)
+	}
+	return q, nil
+}
+
+// compileComputedColumns 校验计算列并返回列名到带括号表达式的映射：
+// 列名不能为空、重复或与表列同名（忽略大小写），表达式只能引用 columns 中的列。
+func compileComputedColumns(d sqlDialect, columns []*connection.ColumnDefinition, computed []connection.ComputedColumn) (map[string]string, error) {
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[strings.ToLower(col.Name)] = true
+	}
+	column := func(name string) bool { return known[strings.ToLower(name)] }
+
+	exprs := make(map[string]string, len(computed))
+	names := make(map[string]bool, len(computed))
+	for i, c := range computed {
+		name := strings.ToLower(c.Name)
+		switch {
+		case strings.TrimSpace(c.Name) == "":
+			return nil, fmt.Errorf("计算列 %d 缺少列名", i+1)
+		case known[name]:
+			return nil, fmt.Errorf("计算列 %s 与表列重名", c.Name)
+		case names[name]:
+			return nil, fmt.Errorf("计算列 %s 重复", c.Name)
+		}
+		names[name] = true
+		if _, err := sqllint.CheckExpression(c.Expression, d.json == jsonPostgres, column); err != nil {
+			return nil, fmt.Errorf("计算列 %s 无效：%w", c.Name, err)
+		}
+		exprs[c.Name] = "(" + c.Expression + ")"
+	}
+	return exprs, nil
+}
+
+// appendPrimaryKeySorts 在排序条件后追加尚未出现的主键列（升序），使排序结果唯一。
+func appendPrimaryKeySorts(sorts []connection.TableSort, columns []*connection.ColumnDefinition) []connection.TableSort {
+	for _, col := range columns {
+		if col.Key != "PRI" {
+			continue
+		}
+		present := false
+		for _, s := range sorts {
+			if s.Column == col.Name {
+				present = true
+				break
+			}
+		}
+		if !present {
+			sorts = append(sorts, connection.TableSort{Column: col.Name})
+		}
+	}
+	return sorts
+}
+
+// hasPrimaryKey 判断列定义中是否包含主键列。
+func hasPrimaryKey(columns []*connection.ColumnDefinition) bool {
+	for _, col := range columns {
+		if col.Key == "PRI" {
+			return true
+		}
+	}
+	return false
+}
+
+// compileSeek 将上一页末行的排序列值编译为键分页条件，n 为已使用的参数个数。
+//
+// 排序方向一致、定位列均不可为空且方言支持行值比较时生成 (a, b) > (?, ?)，可直接走复合索引；
+// 否则展开为 a > ? OR (a = ? AND b > ?)，每列按各自方向比较。可为空的列按方言的 NULL 排序位置
+// 补充 IS NULL / IS NOT NULL 条件，末行值为 NULL 时以 IS NULL 代替等值比较。
+func compileSeek(d sqlDialect, sorts []connection.TableSort, defs map[string]*connection.ColumnDefinition, after []any, n *int) (string, []any, error) {
+	nullable := make([]bool, len(sorts))
+	for i, s := range sorts {
+		nullable[i] = defs[s.Column] != nil && strings.EqualFold(defs[s.Column].Nullable, "YES")
+		if after[i] == nil && !nullable[i] {
+			return "", nil, fmt.Errorf("键分页的排序列 %s 的值不能为 NULL", s.Column)
+		}
+	}
+	next := func() string {
+		*n++
+		return d.placeholder(*n)
+	}
+	op := func(s connection.TableSort) string {
+		if s.Desc {
+			return " < "
+		}
+		return " > "
+	}
+
+	uniform := true
+	for i, s := range sorts {
+		uniform = uniform && s.Desc == sorts[0].Desc && !nullable[i]
+	}
+	if d.rowCompare && uniform && len(sorts) > 1 {
+		cols := make([]string, len(sorts))
+		marks := make([]string, len(sorts))
+		for i, s := range sorts {
+			cols[i] = d.quoteIdent(s.Column)
+			marks[i] = next()
+		}
+		return "(" + strings.Join(cols, ", ") + ")" + op(sorts[0]) + "(" + strings.Join(marks, ", ") + ")", after, nil
+	}
+
+	var (
+		branches []string
+		args     []any
+	)
+	for i, s := range sorts {
+		// NULL 排在本列最前时，NULL 之后是全部非空值；排在最后时 NULL 之后没有更大的值，本分支为空
+		nullsFirst := d.nullsLow != s.Desc
+		if after[i] == nil && !nullsFirst {
+			continue
+		}
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			col := d.quoteIdent(sorts[j].Column)
+			if after[j] == nil {
+				conds = append(conds, col+" IS NULL")
+				continue
+			}
+			conds = append(conds, col+" = "+next())
+			args = append(args, after[j])
+		}
+		col := d.quoteIdent(s.Column)
+		switch {
+		case after[i] == nil:
+			conds = append(conds, col+" IS NOT NULL")
+		case nullable[i] && !nullsFirst:
+			conds = append(conds, "("+col+op(s)+next()+" OR "+col+" IS NULL)")
+			args = append(args, after[i])
+		default:
+			conds = append(conds, col+op(s)+next())
+			args = append(args, after[i])
+		}
+		branches = append(branches, strings.Join(conds, " AND "))
+	}
+	if len(branches) == 1 {
+		return branches[0], args, nil
+	}
+	return "((" + strings.Join(branches, ") OR (") + "))", args, nil
+}
+
+// isBinaryColumn 判断列是否为二进制类型，其定位值以十六进制文本往返前端。
+func isBinaryColumn(col *connection.ColumnDefinition) bool {
+	if col == nil {
+		return false
+	}
+	t := strings.ToLower(col.Type)
+	return strings.Contains(t, "binary") || strings.Contains(t, "blob") || strings.HasPrefix(t, "bit")
+}
+
+// nextSeekValues 返回读取下一页所用的 After：本页读满时取末行定位列的原始值，否则返回 nil。
+// last 为末行的驱动原始值，二进制列编码为 0x 开头的十六进制文本，时间按数据库可比较的文本格式输出，
+// 保证经 JSON 往返后仍能精确定位。
+func nextSeekValues(q *tableDataQuery, rowCount int, last map[string]interface{}) []interface{} {
+	if len(q.seekColumns) == 0 || rowCount < q.pageSize || last == nil {
+		return nil
+	}
+	values := make([]interface{}, len(q.seekColumns))
+	for i, col := range q.seekColumns {
+		switch v := last[col].(type) {
+		case []byte:
+			if q.seekBinary[i] {
+				values[i] = "0x" + hex.EncodeToString(v)
+			} else {
+				values[i] = string(v)
+			}
+		case time.Time:
+			values[i] = v.Format("2006-01-02 15:04:05.999999")
+		default:
+			values[i] = v
+		}
+	}
+	return values
+}
+
+// decodeSeekValues 将前端传回的 After 还原为可绑定的参数，二进制列的十六进制文本解码为字节。
+func decodeSeekValues(q *tableDataQuery, after []interface{}) ([]any, error) {
+	values := make([]any, len(after))
+	for i, v := range after {
+		values[i] = v
+		text, ok := v.(string)
+		if !ok || !q.seekBinary[i] {
+			continue
+		}
+		b, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+		if err != nil || !strings.HasPrefix(text, "0x") {
+			return nil, fmt.Errorf("键分页的排序列 %s 的值应为 0x 开头的十六进制文本", q.seekColumns[i])
+		}
+		values[i] = b
+	}
+	return values, nil
+}
+
+// scanTableDataRows 读取全部行并转换为显示值，同时返回末行的驱动原始值用于键分页定位。
+func scanTableDataRows(rows *sql.Rows) ([]map[string]interface{}, []string, map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	colTypes, err := rows.ColumnTypes()
+	if err != nil || len(colTypes) != len(columns) {
+		colTypes = nil
+	}
+
+	data := make([]map[string]interface{}, 0)
+	var last map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, nil, err
+		}
+		entry := make(map[string]interface{}, len(columns))
+		last = make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			dbTypeName := ""
+			if colTypes != nil && colTypes[i] != nil {
+				dbTypeName = colTypes[i].DatabaseTypeName()
+			}
+			entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
+			last[col] = values[i]
+		}
+		data = append(data, entry)
+	}
+	return data, columns, last, rows.Err()
+}
+
+// compileTableFilter 将单个过滤条件编译为带占位符的表达式，n 为已使用的参数个数；
+// 指定 JSON 路径时比较列内路径上的值。
+func compileTableFilter(d sqlDialect, f connection.TableFilter, n *int) (string, []any, error) {
+	col := d.quoteIdent(f.Column)
+	if strings.TrimSpace(f.Path) != "" {
+		return compileJSONFilter(d, col, f, n)
+	}
+	return compileTableFilterExpr(d, col, f, n)
+}
+
+// compileTableFilterExpr 将过滤条件作用于表达式 col（已引用的列名或取值表达式）。
+func compileTableFilterExpr(d sqlDialect, col string, f connection.TableFilter, n *int) (string, []any, error) {
+	next := func() string {
+		*n++
+		return d.placeholder(*n)
+	}
+	binary := func(op string) (string, []any, error) {
+		if f.Value == nil {
+			return "", nil, fmt.Errorf("缺少比较值")
+		}
+		return col + " " + op + " " + next(), []any{f.Value}, nil
+	}
+	like := func(op, prefix, suffix string) (string, []any, error) {
+		if f.Value == nil {
+			return "", nil, fmt.Errorf("缺少比较值")
+		}
+		text := fmt.Sprint(f.Value)
+		if prefix != "" || suffix != "" {
+			text = prefix + escapeLikePattern(text) + suffix
+		}
+		return col + " " + op + " " + next(), []any{text}, nil
+	}
+
+	switch f.Op {
+	case "eq", "":
+		return binary("=")
+	case "ne":
+		return binary("<>")
+	case "gt":
+		return binary(">")
+	case "gte":
+		return binary(">=")
+	case "lt":
+		return binary("<")
+	case "lte":
+		return binary("<=")
+	case "like":
+		return like("LIKE", "", "")
+	case "notLike":
+		return like("NOT LIKE", "", "")
+	case "contains":
+		return like("LIKE", "%", "%")
+	case "startsWith":
+		return like("LIKE", "", "%")
+	case "endsWith":
+		return like("LIKE", "%", "")
+	case "in", "notIn":
+		if len(f.Values) == 0 {
+			return "", nil, fmt.Errorf("值列表不能为空")
+		}
+		marks := make([]string, len(f.Values))
+		for i := range f.Values {
+			marks[i] = next()
+		}
+		op := "IN"
+		if f.Op == "notIn" {
+			op = "NOT IN"
+		}
+		return col + " " + op + " (" + strings.Join(marks, ", ") + ")", f.Values, nil
+	case "between":
+		if len(f.Values) != 2 {
+			return "", nil, fmt.Errorf("between 需要两个值")
+		}
+		return col + " BETWEEN " + next() + " AND " + next(), f.Values, nil
+	case "isNull":
+		return col + " IS NULL", nil, nil
+	case "notNull":
+		return col + " IS NOT NULL", nil, nil
+	default:
+		return "", nil, fmt.Errorf("不支持的运算符: %s", f.Op)
+	}
+}
+
+// escapeLikePattern 转义 LIKE 通配符，使用户输入按字面匹配。
+func escapeLikePattern(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
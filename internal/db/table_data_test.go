@@ -0,0 +1,321 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+var tableDataColumns = []*connection.ColumnDefinition{
+	{Name: "id", Key: "PRI"},
+	{Name: "name"},
+	{Name: "age"},
+}
+
+func TestBuildTableDataQueryDefaults(t *testing.T) {
+	q, err := buildTableDataQuery(mysqlDialect, "`shop`.`users`", tableDataColumns, nil)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	if q.selectSQL != "SELECT * FROM `shop`.`users` ORDER BY `id` ASC LIMIT 100 OFFSET 0" {
+		t.Errorf("查询语句错误: %s", q.selectSQL)
+	}
+	if q.countSQL != "SELECT COUNT(*) FROM `shop`.`users`" || len(q.args) != 0 {
+		t.Errorf("计数语句错误: %s %v", q.countSQL, q.args)
+	}
+}
+
+func TestBuildTableDataQueryFiltersAndSorts(t *testing.T) {
+	opts := &connection.TableDataOptions{
+		Page:     3,
+		PageSize: 20,
+		Sorts:    []connection.TableSort{{Column: "age", Desc: true}, {Column: "name"}},
+		Filters: []connection.TableFilter{
+			{Column: "name", Op: "contains", Value: "50%_off"},
+			{Column: "age", Op: "between", Values: []interface{}{18, 30}},
+			{Column: "id", Op: "in", Values: []interface{}{1, 2}},
+			{Column: "name", Op: "notNull"},
+		},
+	}
+	q, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	wantWhere := " FROM `users` WHERE `name` LIKE ? AND `age` BETWEEN ? AND ? AND `id` IN (?, ?) AND `name` IS NOT NULL"
+	if q.countSQL != "SELECT COUNT(*)"+wantWhere {
+		t.Errorf("计数语句错误: %s", q.countSQL)
+	}
+	if q.selectSQL != "SELECT *"+wantWhere+" ORDER BY `age` DESC, `name` ASC LIMIT 20 OFFSET 40" {
+		t.Errorf("查询语句错误: %s", q.selectSQL)
+	}
+	wantArgs := []any{`%50\%\_off%`, 18, 30, 1, 2}
+	if !reflect.DeepEqual(q.args, wantArgs) {
+		t.Errorf("参数错误: %v", q.args)
+	}
+}
+
+func TestBuildTableDataQueryNumberedPlaceholdersAndMatchAny(t *testing.T) {
+	pg := sqlDialect{
+		quoteIdent:  quotePgIdent,
+		placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	}
+	opts := &connection.TableDataOptions{
+		PageSize: 5000,
+		MatchAny: true,
+		Filters: []connection.TableFilter{
+			{Column: "age", Op: "gte", Value: 18},
+			{Column: "name", Op: "startsWith", Value: "a"},
+		},
+	}
+	q, err := buildTableDataQuery(pg, `"users"`, tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want := `SELECT * FROM "users" WHERE "age" >= $1 OR "name" LIKE $2 ORDER BY "id" ASC LIMIT 1000 OFFSET 0`
+	if q.selectSQL != want {
+		t.Errorf("查询语句错误: %s", q.selectSQL)
+	}
+}
+
+func TestBuildTableDataQueryRejectsUnknownInput(t *testing.T) {
+	cases := []*connection.TableDataOptions{
+		{Sorts: []connection.TableSort{{Column: "id; DROP TABLE users"}}},
+		{Filters: []connection.TableFilter{{Column: "missing", Op: "eq", Value: 1}}},
+		{Filters: []connection.TableFilter{{Column: "age", Op: "regexp", Value: 1}}},
+		{Filters: []connection.TableFilter{{Column: "age", Op: "eq"}}},
+		{Filters: []connection.TableFilter{{Column: "age", Op: "in"}}},
+	}
+	for i, opts := range cases {
+		if _, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts); err == nil {
+			t.Errorf("用例 %d 应返回错误", i)
+		}
+	}
+}
+
+func TestBuildTableDataQueryComputedColumns(t *testing.T) {
+	opts := &connection.TableDataOptions{
+		Computed: []connection.ComputedColumn{{Name: "label", Expression: "CONCAT(name, ' (', age, ')')"}},
+		Sorts:    []connection.TableSort{{Column: "label", Desc: true}},
+		Filters:  []connection.TableFilter{{Column: "label", Op: "startsWith", Value: "a"}},
+	}
+	q, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want := "SELECT *, (CONCAT(name, ' (', age, ')')) AS `label` FROM `users` WHERE (CONCAT(name, ' (', age, ')')) LIKE ? ORDER BY (CONCAT(name, ' (', age, ')')) DESC LIMIT 100 OFFSET 0"
+	if q.selectSQL != want {
+		t.Errorf("查询语句错误:\n%s", q.selectSQL)
+	}
+	if q.countSQL != "SELECT COUNT(*) FROM `users` WHERE (CONCAT(name, ' (', age, ')')) LIKE ?" {
+		t.Errorf("计数语句错误: %s", q.countSQL)
+	}
+	if !reflect.DeepEqual(q.computed, []string{"label"}) {
+		t.Errorf("计算列错误: %v", q.computed)
+	}
+
+	cases := []*connection.TableDataOptions{
+		{Computed: []connection.ComputedColumn{{Name: "x", Expression: "missing + 1"}}},
+		{Computed: []connection.ComputedColumn{{Name: "x", Expression: "age) FROM users; --"}}},
+		{Computed: []connection.ComputedColumn{{Name: "Name", Expression: "age + 1"}}},
+		{Computed: []connection.ComputedColumn{{Name: "x", Expression: "age"}, {Name: "X", Expression: "age"}}},
+		{Computed: []connection.ComputedColumn{{Name: "x", Expression: "age"}}, Keyset: true, Sorts: []connection.TableSort{{Column: "x"}}},
+		{Computed: []connection.ComputedColumn{{Name: "x", Expression: "age"}}, Filters: []connection.TableFilter{{Column: "x", Path: "$.a", Op: "eq", Value: 1}}},
+	}
+	for i, opts := range cases {
+		if _, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts); err == nil {
+			t.Errorf("用例 %d 应返回错误", i)
+		}
+	}
+}
+
+func TestBuildTableDataQueryKeyset(t *testing.T) {
+	opts := &connection.TableDataOptions{
+		PageSize: 50,
+		Keyset:   true,
+		Sorts:    []connection.TableSort{{Column: "age", Desc: true}},
+		Filters: []connection.TableFilter{
+			{Column: "name", Op: "eq", Value: "a"},
+			{Column: "name", Op: "eq", Value: "b"},
+		},
+		MatchAny: true,
+	}
+	q, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	if q.selectSQL != "SELECT * FROM `users` WHERE `name` = ? OR `name` = ? ORDER BY `age` DESC, `id` ASC LIMIT 50" {
+		t.Errorf("首页查询错误: %s", q.selectSQL)
+	}
+	if !reflect.DeepEqual(q.seekColumns, []string{"age", "id"}) {
+		t.Errorf("定位列错误: %v", q.seekColumns)
+	}
+
+	opts.After = []interface{}{30, 7}
+	q, err = buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want := "SELECT * FROM `users` WHERE (`name` = ? OR `name` = ?) AND ((`age` < ?) OR (`age` = ? AND `id` > ?)) ORDER BY `age` DESC, `id` ASC LIMIT 50"
+	if q.selectSQL != want {
+		t.Errorf("后续页查询错误:\n%s", q.selectSQL)
+	}
+	if q.countSQL != "" {
+		t.Errorf("键分页不应计数: %s", q.countSQL)
+	}
+	if !reflect.DeepEqual(q.selectArgs, []any{"a", "b", 30, 30, 7}) {
+		t.Errorf("参数错误: %v", q.selectArgs)
+	}
+}
+
+func TestBuildTableDataQueryKeysetRowCompare(t *testing.T) {
+	opts := &connection.TableDataOptions{
+		PageSize: 10,
+		Keyset:   true,
+		Sorts:    []connection.TableSort{{Column: "age"}},
+		Filters:  []connection.TableFilter{{Column: "name", Op: "notNull"}},
+		After:    []interface{}{20, 3},
+	}
+	q, err := buildTableDataQuery(postgresDialect, `"users"`, tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want := `SELECT * FROM "users" WHERE ("name" IS NOT NULL) AND ("age", "id") > ($1, $2) ORDER BY "age" ASC, "id" ASC LIMIT 10`
+	if q.selectSQL != want {
+		t.Errorf("行值比较查询错误:\n%s", q.selectSQL)
+	}
+
+	opts.Sorts = []connection.TableSort{{Column: "id"}}
+	opts.Filters = nil
+	opts.After = []interface{}{3}
+	q, err = buildTableDataQuery(postgresDialect, `"users"`, tableDataColumns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	if q.selectSQL != `SELECT * FROM "users" WHERE "id" > $1 ORDER BY "id" ASC LIMIT 10` {
+		t.Errorf("单列键分页查询错误: %s", q.selectSQL)
+	}
+}
+
+func TestBuildTableDataQueryKeysetErrors(t *testing.T) {
+	noKey := []*connection.ColumnDefinition{{Name: "name"}}
+	if _, err := buildTableDataQuery(mysqlDialect, "`t`", noKey, &connection.TableDataOptions{Keyset: true}); err == nil {
+		t.Error("无主键表应拒绝键分页")
+	}
+	cases := []*connection.TableDataOptions{
+		{Keyset: true, After: []interface{}{1, 2}},
+		{Keyset: true, Sorts: []connection.TableSort{{Column: "age"}}, After: []interface{}{nil, 1}},
+	}
+	for i, opts := range cases {
+		if _, err := buildTableDataQuery(mysqlDialect, "`users`", tableDataColumns, opts); err == nil {
+			t.Errorf("用例 %d 应返回错误", i)
+		}
+	}
+}
+
+func TestBuildTableDataQueryKeysetNulls(t *testing.T) {
+	columns := []*connection.ColumnDefinition{
+		{Name: "id", Key: "PRI"},
+		{Name: "score", Nullable: "YES"},
+	}
+	opts := &connection.TableDataOptions{
+		Keyset: true,
+		Sorts:  []connection.TableSort{{Column: "score"}},
+		After:  []interface{}{nil, 7},
+	}
+	q, err := buildTableDataQuery(mysqlDialect, "`t`", columns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want := "SELECT * FROM `t` WHERE ((`score` IS NOT NULL) OR (`score` IS NULL AND `id` > ?)) ORDER BY `score` ASC, `id` ASC LIMIT 100"
+	if q.selectSQL != want || !reflect.DeepEqual(q.selectArgs, []any{7}) {
+		t.Errorf("升序 NULL 定位错误:\n%s %v", q.selectSQL, q.selectArgs)
+	}
+
+	opts.Sorts = []connection.TableSort{{Column: "score", Desc: true}}
+	q, err = buildTableDataQuery(mysqlDialect, "`t`", columns, opts)
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	if q.selectSQL != "SELECT * FROM `t` WHERE `score` IS NULL AND `id` > ? ORDER BY `score` DESC, `id` ASC LIMIT 100" {
+		t.Errorf("降序 NULL 定位错误:\n%s", q.selectSQL)
+	}
+
+	opts.After = []interface{}{50, 7}
+	q, err = buildTableDataQuery(postgresDialect, `"t"`, columns, &connection.TableDataOptions{Keyset: true, Sorts: []connection.TableSort{{Column: "score"}}, After: opts.After})
+	if err != nil {
+		t.Fatalf("编译失败: %v", err)
+	}
+	want = `SELECT * FROM "t" WHERE ((("score" > $1 OR "score" IS NULL)) OR ("score" = $2 AND "id" > $3)) ORDER BY "score" ASC, "id" ASC LIMIT 100`
+	if q.selectSQL != want || !reflect.DeepEqual(q.selectArgs, []any{50, 50, 7}) {
+		t.Errorf("NULL 排在最后时的定位错误:\n%s %v", q.selectSQL, q.selectArgs)
+	}
+}
+
+func TestNextSeekValues(t *testing.T) {
+	q := &tableDataQuery{pageSize: 2, seekColumns: []string{"at", "code", "id"}, seekBinary: []bool{false, true, false}}
+	at := time.Date(2024, 5, 1, 8, 30, 0, 123000000, time.Local)
+	last := map[string]interface{}{"id": []byte("2"), "code": []byte{0x00, 0xff}, "at": at, "name": "b"}
+	got := nextSeekValues(q, 2, last)
+	if !reflect.DeepEqual(got, []interface{}{"2024-05-01 08:30:00.123", "0x00ff", "2"}) {
+		t.Errorf("下一页定位值错误: %v", got)
+	}
+	if got := nextSeekValues(q, 1, last); got != nil {
+		t.Errorf("未读满一页时不应有下一页: %v", got)
+	}
+
+	values, err := decodeSeekValues(q, got)
+	if err != nil || !reflect.DeepEqual(values[1], []byte{0x00, 0xff}) {
+		t.Errorf("二进制定位值解码错误: %v %v", values, err)
+	}
+	if _, err := decodeSeekValues(q, []interface{}{"x", "00ff", "2"}); err == nil {
+		t.Error("缺少 0x 前缀的二进制定位值应返回错误")
+	}
+}
+
+func TestBuildKeysetPageQuery(t *testing.T) {
+	q, args := BuildKeysetPageQuery(connection.ConnectionTypeMySQL, "orders", []string{"id", "sku", "qty"}, []string{"id", "sku"}, []any{"5", "x"}, 0, 100)
+	want := "SELECT `id`, `sku`, `qty` FROM `orders` WHERE (`id`, `sku`) > (?, ?) ORDER BY `id`, `sku` LIMIT 100"
+	if q != want || len(args) != 2 {
+		t.Errorf("MySQL 键分页查询不符:\n%s\n%v", q, args)
+	}
+
+	q, args = BuildKeysetPageQuery(connection.ConnectionTypePostgreSQL, "orders", []string{"id"}, []string{"id"}, nil, 0, 10)
+	if q != `SELECT "id" FROM "orders" ORDER BY "id" LIMIT 10` || len(args) != 0 {
+		t.Errorf("首页查询不符: %s", q)
+	}
+	q, _ = BuildKeysetPageQuery(connection.ConnectionTypePostgreSQL, "logs", []string{"a", "b"}, nil, nil, 20, 10)
+	if q != `SELECT "a", "b" FROM "logs" ORDER BY "a", "b" LIMIT 10 OFFSET 20` {
+		t.Errorf("无键表分页查询不符: %s", q)
+	}
+}
+
+func TestBuildRowLookupQuery(t *testing.T) {
+	q, args := BuildRowLookupQuery(connection.ConnectionTypeMySQL, "orders", "payload", map[string]interface{}{"sku": "x", "id": 5})
+	want := "SELECT `payload` FROM `orders` WHERE `id` = ? AND `sku` = ? LIMIT 2"
+	if q != want || len(args) != 2 || args[0] != 5 || args[1] != "x" {
+		t.Errorf("MySQL 单行查询不符:\n%s\n%v", q, args)
+	}
+
+	q, args = BuildRowLookupQuery(connection.ConnectionTypePostgreSQL, "orders", "payload", map[string]interface{}{"a": nil, "b": 1})
+	want = `SELECT "payload" FROM "orders" WHERE "a" IS NULL AND "b" = $1 LIMIT 2`
+	if q != want || len(args) != 1 {
+		t.Errorf("PostgreSQL 单行查询不符:\n%s\n%v", q, args)
+	}
+}
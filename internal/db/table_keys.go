@@ -0,0 +1,148 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+const (
+	// TableKeyPrimary 主键。
+	TableKeyPrimary = "primary"
+	// TableKeyUnique 全部列非空的唯一索引。
+	TableKeyUnique = "unique"
+	// TableKeyNone 没有可用于定位行的键。
+	TableKeyNone = "none"
+)
+
+// ResolveTableKey 根据索引选出定位行所用的键：优先主键，其次列数最少且全部列非空的唯一索引。
+// 含可空列的唯一索引允许多行同为 NULL，不能唯一定位行，因此不作为候选。
+func ResolveTableKey(indexes []*connection.IndexDefinition, columns []*connection.ColumnDefinition) *connection.TableKey {
+	nullable := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		nullable[col.Name] = !strings.EqualFold(col.Nullable, "NO")
+	}
+
+	grouped := make(map[string][]*connection.IndexDefinition)
+	var names []string
+	for _, idx := range indexes {
+		if idx.NonUnique != 0 {
+			continue
+		}
+		if _, ok := grouped[idx.Name]; !ok {
+			names = append(names, idx.Name)
+		}
+		grouped[idx.Name] = append(grouped[idx.Name], idx)
+	}
+
+	var best *connection.TableKey
+	for _, name := range names {
+		parts := grouped[name]
+		sort.Slice(parts, func(i, j int) bool { return parts[i].SeqInIndex < parts[j].SeqInIndex })
+		key := &connection.TableKey{Kind: TableKeyUnique, Name: name}
+		usable := true
+		for _, p := range parts {
+			key.Columns = append(key.Columns, p.ColumnName)
+			usable = usable && !nullable[p.ColumnName]
+		}
+		if strings.EqualFold(name, "PRIMARY") {
+			key.Kind = TableKeyPrimary
+			return key
+		}
+		if usable && (best == nil || len(key.Columns) < len(best.Columns)) {
+			best = key
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	// 索引信息缺失时回退到列定义中的主键标记
+	key := &connection.TableKey{Kind: TableKeyPrimary}
+	for _, col := range columns {
+		if col.Key == "PRI" {
+			key.Columns = append(key.Columns, col.Name)
+		}
+	}
+	if len(key.Columns) > 0 {
+		return key
+	}
+	return &connection.TableKey{Kind: TableKeyNone, Columns: []string{}}
+}
+
+// NormalizeChangeSet 校验更新与删除的定位条件，返回只保留键列条件的新变更集。
+// 表没有可用键时退化为全列匹配：定位条件必须包含全部列，并返回提示信息。
+// 插入与更新中的生成列会被移除（见 SkipGeneratedColumns），避免数据库以难以理解的错误拒绝写入。
+func NormalizeChangeSet(key *connection.TableKey, columns []*connection.ColumnDefinition, changes *connection.ChangeSet) (*connection.ChangeSet, string, error) {
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+	}
+
+	matchCols := key.Columns
+	warning := ""
+	if key.Kind == TableKeyNone {
+		matchCols = make([]string, 0, len(columns))
+		for _, col := range columns {
+			matchCols = append(matchCols, col.Name)
+		}
+		if len(changes.Updates) > 0 || len(changes.Deletes) > 0 {
+			warning = "表没有主键或非空唯一索引，已按全部列匹配行，每次仅修改一行"
+		}
+	}
+
+	narrow := func(kind string, i int, match map[string]interface{}) (map[string]interface{}, error) {
+		for col := range match {
+			if !known[col] {
+				return nil, fmt.Errorf("%s第 %d 行的定位列不存在: %s", kind, i+1, col)
+			}
+		}
+		out := make(map[string]interface{}, len(matchCols))
+		for _, col := range matchCols {
+			v, ok := match[col]
+			if !ok {
+				return nil, fmt.Errorf("%s第 %d 行缺少定位列 %s", kind, i+1, col)
+			}
+			out[col] = v
+		}
+		return out, nil
+	}
+
+	out := &connection.ChangeSet{Inserts: changes.Inserts}
+	for i, del := range changes.Deletes {
+		match, err := narrow("删除", i, del)
+		if err != nil {
+			return nil, "", err
+		}
+		out.Deletes = append(out.Deletes, match)
+	}
+	for i, update := range changes.Updates {
+		for col := range update.Values {
+			if !known[col] {
+				return nil, "", fmt.Errorf("更新第 %d 行的列不存在: %s", i+1, col)
+			}
+		}
+		match, err := narrow("更新", i, update.Keys)
+		if err != nil {
+			return nil, "", err
+		}
+		out.Updates = append(out.Updates, connection.UpdateRow{Keys: match, Values: update.Values})
+	}
+	return SkipGeneratedColumns(columns, out), warning, nil
+}
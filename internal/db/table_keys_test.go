@@ -0,0 +1,104 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+var keyTestColumns = []*connection.ColumnDefinition{
+	{Name: "id", Nullable: "NO"},
+	{Name: "tenant", Nullable: "NO"},
+	{Name: "email", Nullable: "YES"},
+	{Name: "code", Nullable: "NO"},
+}
+
+func TestResolveTableKeyPrefersPrimary(t *testing.T) {
+	indexes := []*connection.IndexDefinition{
+		{Name: "uk_code", ColumnName: "code", SeqInIndex: 1},
+		{Name: "PRIMARY", ColumnName: "tenant", SeqInIndex: 2},
+		{Name: "PRIMARY", ColumnName: "id", SeqInIndex: 1},
+	}
+	key := ResolveTableKey(indexes, keyTestColumns)
+	if key.Kind != TableKeyPrimary || strings.Join(key.Columns, ",") != "id,tenant" {
+		t.Errorf("应选中复合主键，得到 %+v", key)
+	}
+}
+
+func TestResolveTableKeyBestUnique(t *testing.T) {
+	indexes := []*connection.IndexDefinition{
+		{Name: "uk_email", ColumnName: "email", SeqInIndex: 1},
+		{Name: "uk_tenant_code", ColumnName: "tenant", SeqInIndex: 1},
+		{Name: "uk_tenant_code", ColumnName: "code", SeqInIndex: 2},
+		{Name: "uk_code", ColumnName: "code", SeqInIndex: 1},
+		{Name: "idx_tenant", ColumnName: "tenant", SeqInIndex: 1, NonUnique: 1},
+	}
+	key := ResolveTableKey(indexes, keyTestColumns)
+	if key.Kind != TableKeyUnique || key.Name != "uk_code" {
+		t.Errorf("应选中列数最少的非空唯一索引，得到 %+v", key)
+	}
+
+	key = ResolveTableKey(indexes[:1], keyTestColumns)
+	if key.Kind != TableKeyNone {
+		t.Errorf("可空唯一索引不能作为键，得到 %+v", key)
+	}
+}
+
+func TestNormalizeChangeSetWithKey(t *testing.T) {
+	key := &connection.TableKey{Kind: TableKeyPrimary, Columns: []string{"id"}}
+	changes := &connection.ChangeSet{
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1, "email": "a@x"}, Values: map[string]interface{}{"code": "c"}}},
+		Deletes: []map[string]interface{}{{"id": 2, "code": "d"}},
+	}
+	out, warning, err := NormalizeChangeSet(key, keyTestColumns, changes)
+	if err != nil || warning != "" {
+		t.Fatalf("不应失败: %v %s", err, warning)
+	}
+	if len(out.Updates[0].Keys) != 1 || out.Updates[0].Keys["id"] != 1 || len(out.Deletes[0]) != 1 {
+		t.Errorf("定位条件应只保留键列: %+v", out)
+	}
+
+	_, _, err = NormalizeChangeSet(key, keyTestColumns, &connection.ChangeSet{
+		Deletes: []map[string]interface{}{{"code": "d"}},
+	})
+	if err == nil {
+		t.Error("缺少键列应返回错误")
+	}
+	_, _, err = NormalizeChangeSet(key, keyTestColumns, &connection.ChangeSet{
+		Updates: []connection.UpdateRow{{Keys: map[string]interface{}{"id": 1}, Values: map[string]interface{}{"missing": 1}}},
+	})
+	if err == nil {
+		t.Error("未知列应返回错误")
+	}
+}
+
+func TestNormalizeChangeSetKeylessFallback(t *testing.T) {
+	key := &connection.TableKey{Kind: TableKeyNone}
+	full := map[string]interface{}{"id": 1, "tenant": "t", "email": nil, "code": "c"}
+	out, warning, err := NormalizeChangeSet(key, keyTestColumns, &connection.ChangeSet{Deletes: []map[string]interface{}{full}})
+	if err != nil || warning == "" || len(out.Deletes[0]) != 4 {
+		t.Fatalf("无键表应按全列匹配并给出提示: %+v %q %v", out, warning, err)
+	}
+
+	_, _, err = NormalizeChangeSet(key, keyTestColumns, &connection.ChangeSet{
+		Deletes: []map[string]interface{}{{"id": 1}},
+	})
+	if err == nil {
+		t.Error("无键表缺少列时应返回错误")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"sort"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// TableStatsReader 定义表存储统计的读取能力。
+type TableStatsReader interface {
+	GetTableStats(dbName string) ([]*connection.TableStats, error)
+}
+
+const mysqlTableStatsSQL = `SELECT TABLE_NAME, ENGINE, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH, UPDATE_TIME, TABLE_COMMENT
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'`
+
+// GetTableStats 读取 information_schema.TABLES 中的行数估算与数据、索引大小，按总大小倒序
+func (m *MySQLDB) GetTableStats(dbName string) ([]*connection.TableStats, error) {
+	data, _, err := m.Query(mysqlTableStatsSQL, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*connection.TableStats, 0, len(data))
+	for _, row := range data {
+		updateTime := stringOrEmpty(row["UPDATE_TIME"])
+		if t, ok := row["UPDATE_TIME"].(time.Time); ok {
+			updateTime = formatMySQLDateTime(t)
+		}
+		stats = append(stats, &connection.TableStats{
+			Name:       stringOrEmpty(row["TABLE_NAME"]),
+			Engine:     stringOrEmpty(row["ENGINE"]),
+			Rows:       utils.ToInt64(row["TABLE_ROWS"]),
+			DataSize:   utils.ToInt64(row["DATA_LENGTH"]),
+			IndexSize:  utils.ToInt64(row["INDEX_LENGTH"]),
+			UpdateTime: updateTime,
+			Comment:    stringOrEmpty(row["TABLE_COMMENT"]),
+		})
+	}
+	return sortTableStats(stats), nil
+}
+
+// pgTableStatsSQL 的 UpdateTime 取最近一次 vacuum/analyze 时间：PostgreSQL 不记录数据变更时间，这是最接近的近似。
+const pgTableStatsSQL = `SELECT c.relname AS name, c.relkind::text AS kind, GREATEST(c.reltuples, 0)::bigint AS row_estimate,
+       pg_table_size(c.oid) AS data_size, pg_indexes_size(c.oid) AS index_size,
+       COALESCE(to_char(GREATEST(s.last_vacuum, s.last_autovacuum, s.last_analyze, s.last_autoanalyze), 'YYYY-MM-DD HH24:MI:SS'), '') AS update_time,
+       COALESCE(obj_description(c.oid, 'pg_class'), '') AS comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND c.relkind IN ('r', 'p', 'm')`
+
+// GetTableStats 读取 schema 下各表的估算行数与数据、索引大小，按总大小倒序；dbName 为连接的数据库时使用当前 schema
+func (p *PostgresDB) GetTableStats(dbName string) ([]*connection.TableStats, error) {
+	return queryPostgresTableStats(p.Query, p.schemaFor(dbName))
+}
+
+// queryPostgresTableStats 读取 schema 下普通表、分区表与物化视图的统计；Engine 标注对象类型。
+func queryPostgresTableStats(query queryFunc, schema string) ([]*connection.TableStats, error) {
+	rows, _, err := query(pgTableStatsSQL, schema)
+	if err != nil {
+		return nil, err
+	}
+	kinds := map[string]string{"r": "table", "p": "partitioned", "m": "materialized view"}
+	stats := make([]*connection.TableStats, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, &connection.TableStats{
+			Name:       stringOrEmpty(row["name"]),
+			Engine:     kinds[stringOrEmpty(row["kind"])],
+			Rows:       utils.ToInt64(row["row_estimate"]),
+			DataSize:   utils.ToInt64(row["data_size"]),
+			IndexSize:  utils.ToInt64(row["index_size"]),
+			UpdateTime: stringOrEmpty(row["update_time"]),
+			Comment:    stringOrEmpty(row["comment"]),
+		})
+	}
+	return sortTableStats(stats), nil
+}
+
+// sortTableStats 计算总大小并按总大小倒序、表名正序排列。
+func sortTableStats(stats []*connection.TableStats) []*connection.TableStats {
+	for _, s := range stats {
+		s.TotalSize = s.DataSize + s.IndexSize
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].TotalSize != stats[j].TotalSize {
+			return stats[i].TotalSize > stats[j].TotalSize
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import "testing"
+
+func TestQueryPostgresTableStatsSortsBySize(t *testing.T) {
+	var gotArgs []any
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		gotArgs = args
+		return []map[string]interface{}{
+			{"name": "small", "kind": "r", "row_estimate": int64(10), "data_size": int64(8192), "index_size": int64(0), "update_time": "", "comment": ""},
+			{"name": "big", "kind": "p", "row_estimate": "1000000", "data_size": int64(1 << 30), "index_size": int64(1 << 20), "update_time": "2024-05-01 10:00:00", "comment": "事件"},
+			{"name": "mv", "kind": "m", "row_estimate": int64(5), "data_size": int64(8192), "index_size": int64(0), "update_time": nil, "comment": nil},
+		}, nil, nil
+	}
+
+	stats, err := queryPostgresTableStats(query, "app")
+	if err != nil {
+		t.Fatalf("queryPostgresTableStats 失败: %v", err)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "app" {
+		t.Errorf("查询参数不符: %v", gotArgs)
+	}
+	if len(stats) != 3 || stats[0].Name != "big" || stats[1].Name != "mv" || stats[2].Name != "small" {
+		t.Fatalf("排序不符: %+v %+v %+v", stats[0], stats[1], stats[2])
+	}
+	if stats[0].TotalSize != 1<<30+1<<20 || stats[0].Rows != 1000000 || stats[0].Engine != "partitioned" {
+		t.Errorf("统计解析不符: %+v", stats[0])
+	}
+	if stats[1].Engine != "materialized view" || stats[1].UpdateTime != "" {
+		t.Errorf("物化视图解析不符: %+v", stats[1])
+	}
+}
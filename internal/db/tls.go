@@ -0,0 +1,141 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlTLSParam 返回内置 MySQL 驱动的 tls 参数（含前导 &），未配置 TLS 时为空。
+// 需要校验证书时按 CA 与主机名注册驱动的自定义 TLS 配置。
+func mysqlTLSParam(config *connection.ConnectionConfig) (string, error) {
+	mode := tlsMode(config)
+	switch mode {
+	case "", connection.TLSModeDisable:
+		return "", nil
+	case connection.TLSModePreferred:
+		return "&tls=preferred", nil
+	case connection.TLSModeRequired:
+		return "&tls=skip-verify", nil
+	}
+	cfg, err := tlsClientConfig(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(mode + "|" + config.TLS.CAPath + "|" + cfg.ServerName))
+	name := "boxify-" + hex.EncodeToString(sum[:8])
+	if err := mysql.RegisterTLSConfig(name, cfg); err != nil {
+		return "", fmt.Errorf("注册 TLS 配置失败：%w", err)
+	}
+	return "&tls=" + name, nil
+}
+
+// postgresSSLParams 返回内置 PostgreSQL 驱动的 sslmode 及证书参数。lib/pq 不支持 prefer，
+// preferred 与未配置 TLS（libpq 默认 prefer）按 require 连接，fallback 为 true 表示服务端不支持 SSL 时应退回 disable。
+func postgresSSLParams(config *connection.ConnectionConfig) (params []dsnParam, fallback bool, err error) {
+	switch mode := tlsMode(config); mode {
+	case connection.TLSModeDisable:
+		return []dsnParam{{"sslmode", "disable"}}, false, nil
+	case "", connection.TLSModePreferred:
+		return []dsnParam{{"sslmode", "require"}}, true, nil
+	case connection.TLSModeRequired:
+		return []dsnParam{{"sslmode", "require"}}, false, nil
+	case connection.TLSModeVerifyCA, connection.TLSModeVerifyFull:
+		params = []dsnParam{{"sslmode", mode}}
+		if config.TLS.CAPath != "" {
+			params = append(params, dsnParam{"sslrootcert", config.TLS.CAPath})
+		}
+		return params, false, nil
+	default:
+		return nil, false, fmt.Errorf("不支持的 TLS 模式: %s", config.TLS.Mode)
+	}
+}
+
+// tlsMode 返回规范化的 TLS 模式，未配置 TLS 时为空。
+func tlsMode(config *connection.ConnectionConfig) string {
+	if config.TLS == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(config.TLS.Mode))
+}
+
+// tlsClientConfig 按 TLS 模式构造客户端配置：preferred 与 required 不校验证书，
+// verify-ca 只校验证书链，verify-full 同时校验主机名。未启用 TLS 时返回错误。
+func tlsClientConfig(config *connection.ConnectionConfig) (*tls.Config, error) {
+	mode := tlsMode(config)
+	switch mode {
+	case connection.TLSModePreferred, connection.TLSModeRequired:
+		return &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}, nil
+	case connection.TLSModeVerifyCA, connection.TLSModeVerifyFull:
+	case "", connection.TLSModeDisable:
+		return nil, fmt.Errorf("未启用 TLS")
+	default:
+		return nil, fmt.Errorf("不支持的 TLS 模式: %s", config.TLS.Mode)
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: config.TLS.ServerName}
+	if cfg.ServerName == "" {
+		cfg.ServerName = config.Host
+	}
+	if config.TLS.CAPath != "" {
+		pem, err := os.ReadFile(config.TLS.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败：%w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书不是有效的 PEM：%s", config.TLS.CAPath)
+		}
+		cfg.RootCAs = pool
+	}
+	if mode == connection.TLSModeVerifyCA {
+		// 只校验证书链，不校验主机名
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = verifyChainOnly(cfg.RootCAs)
+	}
+	return cfg, nil
+}
+
+// verifyChainOnly 返回只校验证书链（不校验主机名）的回调，roots 为 nil 时使用系统根证书。
+func verifyChainOnly(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("服务端未提供证书")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
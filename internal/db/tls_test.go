@@ -0,0 +1,86 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestMySQLTLSParam(t *testing.T) {
+	cases := []struct {
+		mode string
+		want string
+	}{
+		{"", ""},
+		{connection.TLSModeDisable, ""},
+		{connection.TLSModePreferred, "&tls=preferred"},
+		{connection.TLSModeRequired, "&tls=skip-verify"},
+	}
+	for _, tc := range cases {
+		got, err := mysqlTLSParam(&connection.ConnectionConfig{TLS: &connection.TLSConfig{Mode: tc.mode}})
+		if err != nil || got != tc.want {
+			t.Errorf("mode %q: got %q, %v; want %q", tc.mode, got, err, tc.want)
+		}
+	}
+
+	got, err := mysqlTLSParam(&connection.ConnectionConfig{Host: "db.example.com", TLS: &connection.TLSConfig{Mode: connection.TLSModeVerifyFull}})
+	if err != nil || !strings.HasPrefix(got, "&tls=boxify-") {
+		t.Fatalf("verify-full 应注册自定义 TLS 配置: %q, %v", got, err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bad, []byte("not a cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mysqlTLSParam(&connection.ConnectionConfig{TLS: &connection.TLSConfig{Mode: connection.TLSModeVerifyCA, CAPath: bad}}); err == nil {
+		t.Fatal("无效的 CA 证书应返回错误")
+	}
+	if _, err := mysqlTLSParam(&connection.ConnectionConfig{TLS: &connection.TLSConfig{Mode: "bogus"}}); err == nil {
+		t.Fatal("未知模式应返回错误")
+	}
+}
+
+func TestPostgresSSLParams(t *testing.T) {
+	cases := []struct {
+		tls      *connection.TLSConfig
+		want     string
+		fallback bool
+	}{
+		{nil, "sslmode=require", true},
+		{&connection.TLSConfig{Mode: connection.TLSModePreferred}, "sslmode=require", true},
+		{&connection.TLSConfig{Mode: connection.TLSModeDisable}, "sslmode=disable", false},
+		{&connection.TLSConfig{Mode: connection.TLSModeRequired}, "sslmode=require", false},
+		{&connection.TLSConfig{Mode: connection.TLSModeVerifyCA}, "sslmode=verify-ca", false},
+		{&connection.TLSConfig{Mode: connection.TLSModeVerifyFull, CAPath: "/etc/ca.pem"}, "sslmode=verify-full sslrootcert=/etc/ca.pem", false},
+	}
+	for _, tc := range cases {
+		params, fallback, err := postgresSSLParams(&connection.ConnectionConfig{TLS: tc.tls})
+		parts := make([]string, 0, len(params))
+		for _, p := range params {
+			parts = append(parts, p.key+"="+p.value)
+		}
+		if got := strings.Join(parts, " "); err != nil || got != tc.want || fallback != tc.fallback {
+			t.Errorf("%+v: got %q fallback=%v, %v; want %q fallback=%v", tc.tls, got, fallback, err, tc.want, tc.fallback)
+		}
+	}
+	if _, _, err := postgresSSLParams(&connection.ConnectionConfig{TLS: &connection.TLSConfig{Mode: "bogus"}}); err == nil {
+		t.Fatal("未知模式应返回错误")
+	}
+}
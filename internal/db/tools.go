@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -59,24 +60,10 @@ func scanRows(rows *sql.Rows) ([]map[string]interface{}, []string, error) {
 	resultData := make([]map[string]interface{}, 0)
 
 	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range columns {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err = rows.Scan(valuePtrs...); err != nil {
+		entry, err := scanEntry(rows, columns, colTypes)
+		if err != nil {
 			continue
 		}
-
-		entry := make(map[string]interface{}, len(columns))
-		for i, col := range columns {
-			dbTypeName := ""
-			if colTypes != nil && i < len(colTypes) && colTypes[i] != nil {
-				dbTypeName = colTypes[i].DatabaseTypeName()
-			}
-			entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
-		}
 		resultData = append(resultData, entry)
 	}
 
@@ -87,6 +74,28 @@ func scanRows(rows *sql.Rows) ([]map[string]interface{}, []string, error) {
 	return resultData, columns, nil
 }
 
+// scanEntry 读取当前行并按列名组装为显示用的值。
+func scanEntry(rows *sql.Rows, columns []string, colTypes []*sql.ColumnType) (map[string]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+
+	entry := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		dbTypeName := ""
+		if colTypes != nil && i < len(colTypes) && colTypes[i] != nil {
+			dbTypeName = colTypes[i].DatabaseTypeName()
+		}
+		entry[col] = normalizeQueryValueWithDBType(values[i], dbTypeName)
+	}
+	return entry, nil
+}
+
 // normalizeQueryValueWithDBType 根据数据库类型对查询结果中的值进行规范化处理
 func normalizeQueryValueWithDBType(v interface{}, databaseTypeName string) interface{} {
 	if b, ok := v.([]byte); ok {
@@ -95,6 +104,39 @@ func normalizeQueryValueWithDBType(v interface{}, databaseTypeName string) inter
 	return v
 }
 
+// intValue 将查询结果中的数值（整数、浮点数或数字文本）转换为 int，无法转换时返回 0
+func intValue(v interface{}) int {
+	switch n := v.(type) {
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(n))
+		return i
+	default:
+		return 0
+	}
+}
+
+// boolValue 将查询结果中的布尔值转换为 bool，兼容 t/f、1/0 等文本形式
+func boolValue(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case int64:
+		return b != 0
+	case []byte:
+		return boolValue(string(b))
+	case string:
+		switch strings.ToLower(strings.TrimSpace(b)) {
+		case "t", "true", "1", "yes", "on":
+			return true
+		}
+	}
+	return false
+}
+
 // bytesToDisplayValue 将字节数组转换为适合显示的值，考虑数据库类型和内容
 func bytesToDisplayValue(b []byte, databaseTypeName string) interface{} {
 	if b == nil {
@@ -132,6 +174,14 @@ func bytesToDisplayValue(b []byte, databaseTypeName string) interface{} {
 	return bytesToReadableString(b)
 }
 
+// isBinaryValue 判断字节数组是否按二进制内容显示（即 bytesToDisplayValue 会将其转为十六进制）
+func isBinaryValue(b []byte, databaseTypeName string) bool {
+	if isBitLikeDBType(strings.ToUpper(strings.TrimSpace(databaseTypeName))) {
+		return false
+	}
+	return !utf8.Valid(b) || !isMostlyPrintable(string(b))
+}
+
 // bytesToReadableString 将字节数组转换为可读字符串，非UTF-8内容以十六进制表示
 func bytesToReadableString(b []byte) interface{} {
 	if b == nil {
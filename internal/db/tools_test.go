@@ -199,6 +199,29 @@ func TestIsBitLikeDBType(t *testing.T) {
 	}
 }
 
+// TestIsBinaryValue 测试二进制内容判断
+func TestIsBinaryValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		typeName string
+		expected bool
+	}{
+		{name: "可打印文本", input: []byte("hello world"), typeName: "BLOB", expected: false},
+		{name: "非 UTF-8 内容", input: []byte{0xff, 0xfe, 0x00, 0x01}, typeName: "BLOB", expected: true},
+		{name: "大量控制字符", input: []byte{0x00, 0x01, 0x02, 0x03, 0x04}, typeName: "", expected: true},
+		{name: "BIT 类型不视为二进制", input: []byte{0xff, 0xfe}, typeName: "bit(16)", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinaryValue(tt.input, tt.typeName); got != tt.expected {
+				t.Errorf("isBinaryValue(%v, %q) = %v, 期望 %v", tt.input, tt.typeName, got, tt.expected)
+			}
+		})
+	}
+}
+
 // TestBytesToUint64 测试字节数组转 uint64
 func TestBytesToUint64(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,325 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbconsole 实现内置的 SQL 交互控制台：本机未安装 mysql/psql 或需经 SSH 隧道连接时，
+// 终端以应用自身的 db-console 子命令启动该控制台，使用应用自带的驱动执行语句。
+package dbconsole
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+	"github.com/rivo/uniseg"
+)
+
+// Subcommand 是启动内置控制台的命令行子命令
+const Subcommand = "db-console"
+
+// ConfigFlag 是传递连接配置文件路径的命令行参数。配置文件为仅当前用户可读的 JSON，
+// 控制台读取后立即删除，凭据不经过环境变量，不会被终端中后续执行的命令继承
+const ConfigFlag = "--config-file"
+
+// DefaultMaxRows 单条查询最多显示的行数
+const DefaultMaxRows = 1000
+
+const (
+	promptFirst    = "boxify> "
+	promptContinue = "     -> "
+)
+
+// Console 是一个绑定到数据库连接的 SQL 交互控制台
+type Console struct {
+	db       db.Database
+	postgres bool
+	out      io.Writer
+	maxRows  int
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // 正在执行语句的取消函数
+}
+
+// New 创建控制台，输出写入 out
+func New(inst db.Database, dbType connection.ConnectionType, out io.Writer) *Console {
+	return &Console{
+		db:       inst,
+		postgres: db.IsPostgresDialect(dbType),
+		out:      out,
+		maxRows:  DefaultMaxRows,
+	}
+}
+
+// EncodeConfig 将连接配置编码为配置文件内容
+func EncodeConfig(config *connection.ConnectionConfig) ([]byte, error) {
+	return json.Marshal(config)
+}
+
+// DecodeConfig 解析配置文件内容
+func DecodeConfig(data []byte) (*connection.ConnectionConfig, error) {
+	var config connection.ConnectionConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("连接配置解析失败: %w", err)
+	}
+	return &config, nil
+}
+
+// ReadConfigFile 读取配置文件并立即删除
+func ReadConfigFile(path string) (*connection.ConnectionConfig, error) {
+	data, err := os.ReadFile(path)
+	_ = os.Remove(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取连接配置失败: %w", err)
+	}
+	return DecodeConfig(data)
+}
+
+// Main 是 db-console 子命令入口：args 为子命令之后的参数，从 ConfigFlag 指定的文件读取连接配置，
+// 连接后在标准输入输出上运行控制台，返回进程退出码
+func Main(args []string) int {
+	if len(args) != 2 || args[0] != ConfigFlag {
+		fmt.Fprintf(os.Stderr, "用法: %s %s <file>\n", Subcommand, ConfigFlag)
+		return 2
+	}
+	config, err := ReadConfigFile(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	inst, err := db.NewDatabaseForConfig(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := inst.Connect(config); err != nil {
+		fmt.Fprintf(os.Stderr, "连接失败: %v\n", err)
+		return 1
+	}
+	defer inst.Close()
+
+	console := New(inst, config.Type, os.Stdout)
+	fmt.Fprintf(os.Stdout, "已连接 %s，语句以 ; 结束，输入 \\q 退出\n", db.FormatConnSummary(config))
+
+	// Ctrl+C 只取消正在执行的语句，不退出控制台
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		for range interrupts {
+			console.Interrupt()
+		}
+	}()
+
+	if err := console.Run(context.Background(), os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// Interrupt 取消正在执行的语句
+func (c *Console) Interrupt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// Run 逐行读取输入，遇到顶层分号即执行已输入的语句，直到输入结束或收到 \q / quit / exit
+func (c *Console) Run(ctx context.Context, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var pending strings.Builder
+	fmt.Fprint(c.out, promptFirst)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pending.Len() == 0 {
+			switch strings.TrimSpace(line) {
+			case `\q`, "quit", "exit":
+				return nil
+			}
+		}
+		if strings.TrimSpace(line) == `\c` {
+			pending.Reset()
+			fmt.Fprint(c.out, promptFirst)
+			continue
+		}
+
+		pending.WriteString(line)
+		pending.WriteByte('\n')
+		stmts, rest := sqllint.SplitStatements(pending.String(), c.postgres)
+		for _, stmt := range stmts {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			c.Execute(ctx, stmt)
+		}
+		pending.Reset()
+		if rest != "" {
+			pending.WriteString(rest)
+			pending.WriteByte('\n')
+			fmt.Fprint(c.out, promptContinue)
+		} else {
+			fmt.Fprint(c.out, promptFirst)
+		}
+	}
+	fmt.Fprintln(c.out)
+	return scanner.Err()
+}
+
+// Execute 执行单条语句并输出结果；返回结果集的语句以表格显示，其余显示影响行数
+func (c *Console) Execute(ctx context.Context, stmt string) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.cancel = cancel
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.cancel = nil
+		c.mu.Unlock()
+		cancel()
+	}()
+
+	start := time.Now()
+	class := sqllint.Classify(stmt, c.postgres)
+	if class.Kind == sqllint.StatementDML && !class.ReturnsRows || class.Kind == sqllint.StatementDDL {
+		affected, err := c.exec(ctx, stmt)
+		if err != nil {
+			fmt.Fprintf(c.out, "ERROR: %v\n", err)
+			return
+		}
+		fmt.Fprintf(c.out, "Query OK, %d rows affected (%s)\n\n", affected, elapsed(start))
+		return
+	}
+
+	rows, columns, err := c.query(ctx, stmt)
+	if err != nil {
+		fmt.Fprintf(c.out, "ERROR: %v\n", err)
+		return
+	}
+	if len(columns) == 0 {
+		fmt.Fprintf(c.out, "Query OK (%s)\n\n", elapsed(start))
+		return
+	}
+	total := len(rows)
+	if total > c.maxRows {
+		rows = rows[:c.maxRows]
+	}
+	writeTable(c.out, columns, rows)
+	if total > len(rows) {
+		fmt.Fprintf(c.out, "%d rows in set, showing first %d (%s)\n\n", total, len(rows), elapsed(start))
+		return
+	}
+	fmt.Fprintf(c.out, "%d rows in set (%s)\n\n", total, elapsed(start))
+}
+
+// query 执行返回结果集的语句，驱动支持时使用带上下文的版本以便中断
+func (c *Console) query(ctx context.Context, stmt string) ([]map[string]interface{}, []string, error) {
+	if q, ok := c.db.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		return q.QueryContext(ctx, stmt)
+	}
+	return c.db.Query(stmt)
+}
+
+// exec 执行数据修改或结构变更语句
+func (c *Console) exec(ctx context.Context, stmt string) (int64, error) {
+	if e, ok := c.db.(interface {
+		ExecContext(context.Context, string, ...any) (int64, error)
+	}); ok {
+		return e.ExecContext(ctx, stmt)
+	}
+	return c.db.Exec(stmt)
+}
+
+// writeTable 以 ASCII 边框表格输出结果集，列宽按终端显示宽度计算（中文等宽字符占两列）
+func writeTable(out io.Writer, columns []string, rows []map[string]interface{}) {
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = uniseg.StringWidth(col)
+	}
+	for r, row := range rows {
+		cells[r] = make([]string, len(columns))
+		for i, col := range columns {
+			text := formatValue(row[col])
+			cells[r][i] = text
+			if n := uniseg.StringWidth(text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sep strings.Builder
+	sep.WriteByte('+')
+	for _, w := range widths {
+		sep.WriteString(strings.Repeat("-", w+2))
+		sep.WriteByte('+')
+	}
+	border := sep.String()
+
+	writeRow := func(values []string) {
+		var b strings.Builder
+		b.WriteByte('|')
+		for i, v := range values {
+			b.WriteByte(' ')
+			b.WriteString(v)
+			b.WriteString(strings.Repeat(" ", widths[i]-uniseg.StringWidth(v)+1))
+			b.WriteByte('|')
+		}
+		fmt.Fprintln(out, b.String())
+	}
+
+	fmt.Fprintln(out, border)
+	writeRow(columns)
+	fmt.Fprintln(out, border)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	fmt.Fprintln(out, border)
+}
+
+// formatValue 将单元格值转换为单行文本，NULL 显示为 NULL，换行转义为 \n
+func formatValue(v interface{}) string {
+	var text string
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		text = string(val)
+	case time.Time:
+		text = val.Format("2006-01-02 15:04:05")
+	default:
+		text = fmt.Sprintf("%v", val)
+	}
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`, "\t", `\t`).Replace(text)
+}
+
+// elapsed 返回形如 0.012 sec 的耗时
+func elapsed(start time.Time) string {
+	return fmt.Sprintf("%.3f sec", time.Since(start).Seconds())
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbconsole
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// fakeDatabase 记录执行过的语句，查询固定返回两行。
+type fakeDatabase struct {
+	db.Database
+	queries []string
+	execs   []string
+}
+
+func (f *fakeDatabase) Query(query string, args ...any) ([]map[string]interface{}, []string, error) {
+	f.queries = append(f.queries, query)
+	if strings.HasPrefix(query, "SET") {
+		return nil, nil, nil
+	}
+	return []map[string]interface{}{
+		{"id": int64(1), "name": []byte("张三")},
+		{"id": int64(2), "name": nil},
+	}, []string{"id", "name"}, nil
+}
+
+func (f *fakeDatabase) Exec(query string, args ...any) (int64, error) {
+	f.execs = append(f.execs, query)
+	return 3, nil
+}
+
+func TestConsoleRun(t *testing.T) {
+	fake := &fakeDatabase{}
+	var out bytes.Buffer
+	c := New(fake, connection.ConnectionTypeMySQL, &out)
+
+	input := "SELECT id,\n name FROM t; UPDATE t SET a = ';'\n;\n\\c\nSET x = 1;\nSELECT 'unfinished\n\\q\n"
+	if err := c.Run(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(fake.queries) != 2 || fake.queries[0] != "SELECT id,\n name FROM t" || fake.queries[1] != "SET x = 1" {
+		t.Fatalf("queries = %q", fake.queries)
+	}
+	if len(fake.execs) != 1 || fake.execs[0] != "UPDATE t SET a = ';'" {
+		t.Fatalf("execs = %q", fake.execs)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"+----+------+\n| id | name |\n+----+------+\n| 1  | 张三 |\n| 2  | NULL |\n+----+------+\n2 rows in set",
+		"Query OK, 3 rows affected",
+		"Query OK (",
+		promptContinue,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("输出缺少 %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestConsoleMaxRows(t *testing.T) {
+	var out bytes.Buffer
+	c := New(&fakeDatabase{}, connection.ConnectionTypeMySQL, &out)
+	c.maxRows = 1
+	c.Execute(context.Background(), "SELECT * FROM t")
+	if !strings.Contains(out.String(), "2 rows in set, showing first 1") || strings.Contains(out.String(), "NULL") {
+		t.Fatalf("应只显示第一行:\n%s", out.String())
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL, Host: "h", Port: 5432, User: "u", Password: "p@ss"}
+	value, err := EncodeConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "console.json")
+	if err := os.WriteFile(path, value, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadConfigFile(path)
+	if err != nil || !reflect.DeepEqual(got, cfg) {
+		t.Fatalf("ReadConfigFile() = %+v, %v", got, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("读取后应删除配置文件: %v", err)
+	}
+	if _, err := DecodeConfig([]byte("not json")); err == nil {
+		t.Fatal("无效输入应返回错误")
+	}
+}
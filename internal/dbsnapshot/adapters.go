@@ -0,0 +1,82 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// dbSource 基于数据库连接的快照来源。
+type dbSource struct {
+	conn     db.Database
+	streamer db.RowStreamer
+	dbType   connection.ConnectionType
+	database string
+}
+
+// NewDBSource 基于数据库连接创建快照来源，连接需支持流式读取。
+func NewDBSource(conn db.Database, dbType connection.ConnectionType, database string) (Source, error) {
+	streamer, ok := conn.(db.RowStreamer)
+	if !ok {
+		return nil, fmt.Errorf("数据库不支持流式读取")
+	}
+	return &dbSource{conn: conn, streamer: streamer, dbType: dbType, database: database}, nil
+}
+
+// Describe 返回表的列与索引定义。
+func (s *dbSource) Describe(ctx context.Context, table string) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error) {
+	columns, err := s.conn.GetColumns(ctx, s.database, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := s.conn.GetIndexes(ctx, s.database, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	return columns, indexes, nil
+}
+
+// Scan 以服务端游标逐行读取表的原始值。
+func (s *dbSource) Scan(ctx context.Context, table string, columns []string, fn func(values []any) error) error {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = db.QuoteIdent(s.dbType, col)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoted, ", "), db.QuoteTable(s.dbType, table))
+	stream, err := s.streamer.QueryStream(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	for {
+		values, err := stream.NextValues()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+)
+
+// 归档中的特殊值标记：二进制内容按 base64 保存，时间按 RFC3339Nano 保存。
+const (
+	bytesKey = "$b64"
+	timeKey  = "$t"
+)
+
+// encodeValue 将驱动返回的原始值转换为可 JSON 序列化的形式。
+// 二进制列或非 UTF-8 的字节保存为 {"$b64": ...}，其余字节按字符串保存。
+func encodeValue(v any, binary bool) any {
+	switch val := v.(type) {
+	case []byte:
+		if binary || !utf8.Valid(val) {
+			return map[string]string{bytesKey: base64.StdEncoding.EncodeToString(val)}
+		}
+		return string(val)
+	case time.Time:
+		return map[string]string{timeKey: val.Format(time.RFC3339Nano)}
+	default:
+		return v
+	}
+}
+
+// decodeValue 将归档中（UseNumber 解码）的值还原为写入数据库用的值。
+func decodeValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		if len(val) != 1 {
+			return v
+		}
+		if s, ok := val[bytesKey].(string); ok {
+			if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+				return b
+			}
+		}
+		if s, ok := val[timeKey].(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// CheckLevel 是恢复前检查项的结果级别。
+type CheckLevel string
+
+const (
+	CheckPassed  CheckLevel = "passed"  // 通过
+	CheckWarning CheckLevel = "warning" // 可以恢复，但需要留意
+	CheckFailed  CheckLevel = "failed"  // 无法恢复
+)
+
+// 恢复前检查项名称
+const (
+	CheckDatabase = "database" // 目标数据库是否存在
+	CheckDialect  = "dialect"  // 目标方言是否支持建表
+	CheckVersion  = "version"  // 服务器版本兼容性
+	CheckTables   = "tables"   // 待恢复的表与目标库现有表
+)
+
+// Check 是一项恢复前检查的结果。
+type Check struct {
+	Name    string     `json:"name"`    // 检查项，见 Check* 常量
+	Level   CheckLevel `json:"level"`   // 结果级别
+	Message string     `json:"message"` // 说明
+}
+
+// TargetInfo 描述恢复目标的现状，由调用方从目标连接读取。
+type TargetInfo struct {
+	Type           connection.ConnectionType // 目标数据库类型
+	Version        string                    // 目标服务器版本，未知时为空
+	Database       string                    // 目标数据库
+	DatabaseExists bool                      // 目标数据库是否已存在
+	CreateDatabase bool                      // 目标数据库不存在时是否新建
+	Tables         []string                  // 目标数据库中已有的表
+}
+
+// PreflightReport 是恢复前检查报告；OK 为 false 时不应开始恢复。
+type PreflightReport struct {
+	SnapshotID     string                    `json:"snapshotId"`              // 快照 ID
+	SnapshotName   string                    `json:"snapshotName"`            // 快照名称
+	SourceType     connection.ConnectionType `json:"sourceType"`              // 来源数据库类型
+	SourceVersion  string                    `json:"sourceVersion,omitempty"` // 来源服务器版本
+	TargetType     connection.ConnectionType `json:"targetType"`              // 目标数据库类型
+	TargetVersion  string                    `json:"targetVersion,omitempty"` // 目标服务器版本
+	Database       string                    `json:"database"`                // 目标数据库
+	Tables         []string                  `json:"tables"`                  // 将恢复的表
+	Rows           int64                     `json:"rows"`                    // 将写入的总行数
+	CreateDatabase bool                      `json:"createDatabase"`          // 恢复前需要新建目标数据库
+	Checks         []*Check                  `json:"checks"`                  // 各项检查结果
+	OK             bool                      `json:"ok"`                      // 没有失败的检查项
+}
+
+// Preflight 在恢复前检查快照与目标是否匹配：目标库是否存在、方言与版本是否兼容、待恢复的表与目标现有表的关系。
+func Preflight(m *Manifest, target TargetInfo, opts RestoreOptions) *PreflightReport {
+	if opts.Mode == "" {
+		opts.Mode = RestoreReplace
+	}
+	r := &PreflightReport{
+		SnapshotID:    m.ID,
+		SnapshotName:  m.Name,
+		SourceType:    m.SourceType,
+		SourceVersion: m.ServerVersion,
+		TargetType:    target.Type,
+		TargetVersion: target.Version,
+		Database:      target.Database,
+		Tables:        []string{},
+	}
+	add := func(name string, level CheckLevel, format string, args ...any) {
+		r.Checks = append(r.Checks, &Check{Name: name, Level: level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case target.DatabaseExists:
+		add(CheckDatabase, CheckPassed, "目标数据库 %s 已存在", target.Database)
+	case !target.CreateDatabase:
+		add(CheckDatabase, CheckFailed, "目标数据库 %s 不存在", target.Database)
+	case opts.Mode == RestoreDataOnly:
+		add(CheckDatabase, CheckFailed, "目标数据库 %s 不存在，仅恢复数据要求目标表已存在", target.Database)
+	default:
+		r.CreateDatabase = true
+		add(CheckDatabase, CheckPassed, "目标数据库 %s 不存在，将在恢复前创建", target.Database)
+	}
+
+	if opts.Mode == RestoreReplace {
+		if _, err := db.NewDDLBuilder(target.Type); err != nil {
+			add(CheckDialect, CheckFailed, "目标数据库不支持重建表结构：%v", err)
+		} else if !datatransfer.SameDialectFamily(m.SourceType, target.Type) {
+			add(CheckDialect, CheckWarning, "跨方言恢复（%s → %s），列类型将自动转换，默认值不会保留", m.SourceType, target.Type)
+		} else {
+			add(CheckDialect, CheckPassed, "目标方言与来源兼容")
+		}
+	}
+
+	checkVersion(m, target, add)
+
+	tables, err := selectTables(m, opts.Tables)
+	if err != nil {
+		add(CheckTables, CheckFailed, "%v", err)
+	} else {
+		existing := make(map[string]bool, len(target.Tables))
+		for _, name := range target.Tables {
+			existing[strings.ToLower(name)] = true
+		}
+		var overlap, missing []string
+		for _, t := range tables {
+			r.Tables = append(r.Tables, t.Name)
+			r.Rows += t.RowCount
+			if existing[strings.ToLower(t.Name)] {
+				overlap = append(overlap, t.Name)
+			} else {
+				missing = append(missing, t.Name)
+			}
+		}
+		switch {
+		case opts.Mode == RestoreDataOnly && len(missing) > 0:
+			add(CheckTables, CheckFailed, "目标数据库缺少表: %s", strings.Join(missing, ", "))
+		case opts.Mode == RestoreDataOnly:
+			add(CheckTables, CheckWarning, "将清空并重新写入 %d 张表的数据", len(overlap))
+		case len(overlap) > 0:
+			add(CheckTables, CheckWarning, "将删除并重建已有的表: %s", strings.Join(overlap, ", "))
+		default:
+			add(CheckTables, CheckPassed, "将新建 %d 张表", len(tables))
+		}
+	}
+
+	r.OK = true
+	for _, c := range r.Checks {
+		if c.Level == CheckFailed {
+			r.OK = false
+		}
+	}
+	return r
+}
+
+// checkVersion 比较同类型数据库的服务器版本：目标版本低于来源时给出警告，跨类型时不比较。
+func checkVersion(m *Manifest, target TargetInfo, add func(string, CheckLevel, string, ...any)) {
+	if m.SourceType != target.Type {
+		return
+	}
+	if m.ServerVersion == "" || target.Version == "" {
+		add(CheckVersion, CheckWarning, "无法确定来源或目标的服务器版本，跳过版本检查")
+		return
+	}
+	cmp, ok := db.CompareVersions(target.Version, m.ServerVersion)
+	switch {
+	case !ok:
+		add(CheckVersion, CheckWarning, "无法解析服务器版本（来源 %s，目标 %s），跳过版本检查", m.ServerVersion, target.Version)
+	case cmp < 0:
+		add(CheckVersion, CheckWarning, "目标版本 %s 低于来源版本 %s，部分列类型、默认值或排序规则可能不受支持", target.Version, m.ServerVersion)
+	default:
+		add(CheckVersion, CheckPassed, "目标版本 %s 不低于来源版本 %s", target.Version, m.ServerVersion)
+	}
+}
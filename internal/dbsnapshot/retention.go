@@ -0,0 +1,90 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import "fmt"
+
+// Expired 按保留策略返回 list 中应删除的快照；list 须按创建时间倒序排列。
+//
+// 保留最近的 keepLast 份，另外为最近 keepWeekly 个有备份的自然周（ISO 周）各保留该周最新的一份。
+// 两者均 <= 0 时不删除任何快照。
+func Expired(list []*Manifest, keepLast, keepWeekly int) []*Manifest {
+	if keepLast <= 0 && keepWeekly <= 0 {
+		return nil
+	}
+	keep := make(map[string]bool, len(list))
+	for i, m := range list {
+		if i >= keepLast {
+			break
+		}
+		keep[m.ID] = true
+	}
+	weeks := make(map[[2]int]bool)
+	for _, m := range list {
+		if len(weeks) >= keepWeekly {
+			break
+		}
+		year, week := m.CreatedAt.ISOWeek()
+		if weeks[[2]int{year, week}] {
+			continue
+		}
+		weeks[[2]int{year, week}] = true
+		keep[m.ID] = true
+	}
+
+	var expired []*Manifest
+	for _, m := range list {
+		if !keep[m.ID] {
+			expired = append(expired, m)
+		}
+	}
+	return expired
+}
+
+// Prune 按保留策略删除定时备份任务 taskID 创建的过期快照，返回已删除的快照。
+// 单个快照删除失败时继续处理其余快照，并返回第一个错误。
+func (s *Store) Prune(taskID string, keepLast, keepWeekly int) ([]*Manifest, error) {
+	if taskID == "" {
+		return nil, fmt.Errorf("任务 ID 不能为空")
+	}
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]*Manifest, 0, len(list))
+	for _, m := range list {
+		if m.TaskID == taskID {
+			owned = append(owned, m)
+		}
+	}
+
+	var (
+		deleted  []*Manifest
+		firstErr error
+	)
+	for _, m := range Expired(owned, keepLast, keepWeekly) {
+		if err := s.Delete(m.ID); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		deleted = append(deleted, m)
+	}
+	if len(deleted) > 0 {
+		s.logger.Info("清理过期备份", "task", taskID, "deleted", len(deleted))
+	}
+	return deleted, firstErr
+}
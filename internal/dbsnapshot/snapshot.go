@@ -0,0 +1,352 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/google/uuid"
+)
+
+// RestoreMode 是恢复方式。
+type RestoreMode string
+
+const (
+	// RestoreReplace 删除并按快照结构重建目标表后写入数据
+	RestoreReplace RestoreMode = "replace"
+	// RestoreDataOnly 保留目标表结构，清空现有数据后写入
+	RestoreDataOnly RestoreMode = "data-only"
+)
+
+// Source 是快照的来源数据库。
+type Source interface {
+	// Describe 返回表的列与索引定义
+	Describe(ctx context.Context, table string) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error)
+	// Scan 按 columns 顺序逐行读取表的原始值
+	Scan(ctx context.Context, table string, columns []string, fn func(values []any) error) error
+}
+
+// Progress 汇报进度，done/total 为已处理与总行数（总数未知时为 0）。
+type Progress func(done, total int64, message string)
+
+// CaptureOptions 是创建快照的参数。
+type CaptureOptions struct {
+	Name          string                    // 快照名称
+	SourceType    connection.ConnectionType // 来源数据库类型
+	ServerVersion string                    // 来源服务器版本，用于恢复前的兼容性检查
+	Source        string                    // 来源连接摘要
+	Database      string                    // 来源数据库
+	Tables        []string                  // 待快照的表
+	TaskID        string                    // 定时备份任务 ID，用于按任务清理过期备份
+}
+
+// RestoreOptions 是恢复快照的参数。
+type RestoreOptions struct {
+	TargetType connection.ConnectionType // 目标数据库类型
+	Tables     []string                  // 待恢复的表，为空时恢复全部
+	Mode       RestoreMode               // 恢复方式
+	BatchSize  int                       // 每批写入行数，<=0 时使用默认值
+}
+
+// Capture 读取各表结构与全部数据写入新的快照归档；失败或取消时不留下半成品文件。
+func (s *Store) Capture(ctx context.Context, src Source, opts CaptureOptions, progress Progress) (_ *Manifest, err error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return nil, fmt.Errorf("快照名称不能为空")
+	}
+	if len(opts.Tables) == 0 {
+		return nil, fmt.Errorf("至少选择一张表")
+	}
+	if progress == nil {
+		progress = func(int64, int64, string) {}
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("创建数据库快照目录失败：%w", err)
+	}
+
+	m := &Manifest{
+		ID:            uuid.New().String(),
+		Name:          strings.TrimSpace(opts.Name),
+		SourceType:    opts.SourceType,
+		ServerVersion: opts.ServerVersion,
+		Source:        opts.Source,
+		Database:      opts.Database,
+		TaskID:        opts.TaskID,
+		CreatedAt:     time.Now(),
+	}
+	f, err := os.CreateTemp(s.dir, m.ID+"-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("创建数据库快照文件失败：%w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	var done int64
+	for i, table := range opts.Tables {
+		t, err := captureTable(ctx, zw, src, table, fmt.Sprintf("data/%04d.jsonl", i+1), func(n int64) {
+			progress(done+n, 0, table)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("快照表 %s 失败：%w", table, err)
+		}
+		done += t.RowCount
+		m.Tables = append(m.Tables, t)
+	}
+	if err := writeManifest(zw, m); err != nil {
+		return nil, fmt.Errorf("写入快照清单失败：%w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("写入数据库快照失败：%w", err)
+	}
+	if info, statErr := f.Stat(); statErr == nil {
+		m.SizeBytes = info.Size()
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("写入数据库快照失败：%w", err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, m.ID+".zip")); err != nil {
+		return nil, fmt.Errorf("保存数据库快照失败：%w", err)
+	}
+	s.logger.Info("创建数据库快照", "id", m.ID, "name", m.Name, "tables", len(m.Tables), "rows", done, "bytes", m.SizeBytes)
+	return m, nil
+}
+
+// captureTable 读取单表结构并将数据逐行写入归档内的数据文件。
+func captureTable(ctx context.Context, zw *zip.Writer, src Source, table, dataFile string, onRows func(int64)) (*Table, error) {
+	columns, indexes, err := src.Describe(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("读取表结构失败：%w", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("表不存在或没有列")
+	}
+	t := &Table{Name: table, Columns: columns, Indexes: indexes, DataFile: dataFile}
+	names := make([]string, len(columns))
+	binary := make([]bool, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+		binary[i] = datatransfer.IsBinaryType(col.Type)
+	}
+
+	w, err := zw.Create(dataFile)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(w)
+	row := make([]any, len(columns))
+	err = src.Scan(ctx, table, names, func(values []any) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i := range row {
+			row[i] = nil
+			if i < len(values) {
+				row[i] = encodeValue(values[i], binary[i])
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		t.RowCount++
+		if t.RowCount%1000 == 0 {
+			onRows(t.RowCount)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	onRows(t.RowCount)
+	return t, nil
+}
+
+// Restore 将快照中的表写入目标库，返回写入的总行数。
+//
+// replace 模式先按快照逆序删除已有表，再按顺序建表写入，便于被引用表先于引用表创建；
+// data-only 模式要求目标表已存在且包含快照中的列。每批写入在单个事务内提交。
+func (s *Store) Restore(ctx context.Context, id string, dst datatransfer.Target, opts RestoreOptions, progress Progress) (_ int64, err error) {
+	if opts.Mode == "" {
+		opts.Mode = RestoreReplace
+	}
+	if opts.Mode != RestoreReplace && opts.Mode != RestoreDataOnly {
+		return 0, fmt.Errorf("不支持的恢复方式: %s", opts.Mode)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = datatransfer.DefaultBatchSize
+	}
+	if progress == nil {
+		progress = func(int64, int64, string) {}
+	}
+
+	zr, err := s.open(id)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+	m, err := readManifest(&zr.Reader)
+	if err != nil {
+		return 0, err
+	}
+	tables, err := selectTables(m, opts.Tables)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, t := range tables {
+		total += t.RowCount
+	}
+	if opts.Mode == RestoreReplace {
+		if err := recreateTables(ctx, dst, m.SourceType, opts.TargetType, tables); err != nil {
+			return 0, err
+		}
+	}
+
+	var done int64
+	for _, t := range tables {
+		if opts.Mode == RestoreDataOnly {
+			if err := dst.Exec(ctx, "DELETE FROM "+db.QuoteTable(opts.TargetType, t.Name)); err != nil {
+				return done, fmt.Errorf("清空表 %s 失败：%w", t.Name, err)
+			}
+		}
+		progress(done, total, t.Name)
+		n, err := restoreRows(ctx, &zr.Reader, dst, t, opts.BatchSize, func(n int64) {
+			progress(done+n, total, t.Name)
+		})
+		done += n
+		if err != nil {
+			return done, fmt.Errorf("恢复表 %s 失败（已写入 %d 行）：%w", t.Name, n, err)
+		}
+	}
+	s.logger.Info("恢复数据库快照", "id", id, "mode", opts.Mode, "tables", len(tables), "rows", done)
+	return done, nil
+}
+
+// selectTables 按名称选择待恢复的表，names 为空时返回全部。
+func selectTables(m *Manifest, names []string) ([]*Table, error) {
+	if len(names) == 0 {
+		return m.Tables, nil
+	}
+	tables := make([]*Table, 0, len(names))
+	for _, name := range names {
+		t := m.FindTable(name)
+		if t == nil {
+			return nil, fmt.Errorf("快照中不存在表: %s", name)
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+// recreateTables 逆序删除目标表后按目标方言顺序重建。
+func recreateTables(ctx context.Context, dst datatransfer.Target, from, to connection.ConnectionType, tables []*Table) error {
+	builder, err := db.NewDDLBuilder(to)
+	if err != nil {
+		return err
+	}
+	for i := len(tables) - 1; i >= 0; i-- {
+		stmts, err := builder.DropTable("", tables[i].Name, true)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range stmts {
+			if err := dst.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("删除表 %s 失败：%w", tables[i].Name, err)
+			}
+		}
+	}
+	for _, t := range tables {
+		def := datatransfer.MapTableDefinition(from, to, t.Name, t.Columns, t.Indexes)
+		stmts, err := builder.CreateTable("", def)
+		if err != nil {
+			return fmt.Errorf("生成表 %s 结构失败：%w", t.Name, err)
+		}
+		for _, stmt := range stmts {
+			if err := dst.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("创建表 %s 失败：%w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// restoreRows 逐行读取数据文件并分批写入目标表。
+func restoreRows(ctx context.Context, zr *zip.Reader, dst datatransfer.Target, t *Table, batchSize int, onRows func(int64)) (n int64, err error) {
+	f, err := zr.Open(t.DataFile)
+	if err != nil {
+		return 0, fmt.Errorf("快照归档缺少数据文件：%w", err)
+	}
+	defer closeErr(f, &err)
+
+	columns := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		columns[i] = col.Name
+	}
+	flush := func(batch [][]any) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := dst.Insert(ctx, t.Name, columns, batch); err != nil {
+			return err
+		}
+		n += int64(len(batch))
+		onRows(n)
+		return nil
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	dec.UseNumber()
+	batch := make([][]any, 0, batchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		var row []any
+		if err := dec.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return n, fmt.Errorf("解析快照数据失败：%w", err)
+		}
+		for i, v := range row {
+			row[i] = decodeValue(v)
+		}
+		batch = append(batch, row)
+		if len(batch) >= batchSize {
+			if err := flush(batch); err != nil {
+				return n, err
+			}
+			batch = make([][]any, 0, batchSize)
+		}
+	}
+	return n, flush(batch)
+}
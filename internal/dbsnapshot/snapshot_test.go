@@ -0,0 +1,275 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// fakeSource 以内存表模拟快照来源。
+type fakeSource struct {
+	tables  map[string][][]any
+	indexes []*connection.IndexDefinition // 附加的二级索引
+}
+
+func (f *fakeSource) Describe(ctx context.Context, table string) ([]*connection.ColumnDefinition, []*connection.IndexDefinition, error) {
+	if _, ok := f.tables[table]; !ok {
+		return nil, nil, nil
+	}
+	return []*connection.ColumnDefinition{
+		{Name: "id", Type: "int", Nullable: "NO", Key: "PRI"},
+		{Name: "name", Type: "varchar(10)", Nullable: "YES"},
+		{Name: "data", Type: "blob", Nullable: "YES"},
+		{Name: "at", Type: "datetime", Nullable: "YES"},
+	}, append([]*connection.IndexDefinition{{Name: "PRIMARY", ColumnName: "id", SeqInIndex: 1}}, f.indexes...), nil
+}
+
+func (f *fakeSource) Scan(ctx context.Context, table string, columns []string, fn func(values []any) error) error {
+	for _, row := range f.tables[table] {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeTarget 记录执行的语句与写入的行。
+type fakeTarget struct {
+	stmts []string
+	rows  map[string][][]any
+}
+
+func (f *fakeTarget) Exec(ctx context.Context, stmt string) error {
+	f.stmts = append(f.stmts, stmt)
+	return nil
+}
+
+func (f *fakeTarget) Insert(ctx context.Context, table string, columns []string, rows [][]any) error {
+	if f.rows == nil {
+		f.rows = map[string][][]any{}
+	}
+	f.rows[table] = append(f.rows[table], rows...)
+	return nil
+}
+
+func TestCaptureRestoreRoundTrip(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC)
+	src := &fakeSource{tables: map[string][][]any{
+		"users": {
+			{int64(1), []byte("张三"), []byte{0x00, 0xff}, at},
+			{int64(2), nil, nil, nil},
+		},
+		"orders": {{int64(10), "x", []byte("raw"), nil}},
+	}}
+	store := NewStore(t.TempDir(), nil)
+
+	m, err := store.Capture(context.Background(), src, CaptureOptions{
+		Name:       "实验前",
+		SourceType: connection.ConnectionTypeMySQL,
+		Tables:     []string{"users", "orders"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建快照失败: %v", err)
+	}
+	if len(m.Tables) != 2 || m.Tables[0].RowCount != 2 || m.SizeBytes == 0 {
+		t.Fatalf("清单不符: %+v", m)
+	}
+	list, err := store.List()
+	if err != nil || len(list) != 1 || list[0].ID != m.ID {
+		t.Fatalf("列表不符: %v %+v", err, list)
+	}
+
+	dst := &fakeTarget{}
+	n, err := store.Restore(context.Background(), m.ID, dst, RestoreOptions{TargetType: connection.ConnectionTypeMySQL, BatchSize: 1}, nil)
+	if err != nil || n != 3 {
+		t.Fatalf("恢复失败: %v，行数 %d", err, n)
+	}
+	// 逆序删除、顺序重建
+	if !strings.Contains(dst.stmts[0], "`orders`") || !strings.Contains(dst.stmts[1], "`users`") || !strings.HasPrefix(dst.stmts[2], "CREATE TABLE `users`") {
+		t.Errorf("DDL 顺序不符: %v", dst.stmts)
+	}
+	got := dst.rows["users"][0]
+	if got[0] != int64(1) || got[1] != "张三" || !bytes.Equal(got[2].([]byte), []byte{0x00, 0xff}) || !got[3].(time.Time).Equal(at) {
+		t.Errorf("值还原不符: %#v", got)
+	}
+	if row := dst.rows["users"][1]; row[1] != nil || row[3] != nil {
+		t.Errorf("NULL 应保持为 nil: %#v", row)
+	}
+	if b, ok := dst.rows["orders"][0][2].([]byte); !ok || string(b) != "raw" {
+		t.Errorf("二进制列应保留字节: %#v", dst.rows["orders"][0][2])
+	}
+}
+
+func TestRestoreDataOnlySelectedTables(t *testing.T) {
+	src := &fakeSource{tables: map[string][][]any{
+		"a": {{int64(1), "x", nil, nil}},
+		"b": {{int64(2), "y", nil, nil}},
+	}}
+	store := NewStore(t.TempDir(), nil)
+	m, err := store.Capture(context.Background(), src, CaptureOptions{Name: "s", SourceType: connection.ConnectionTypeMySQL, Tables: []string{"a", "b"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &fakeTarget{}
+	if _, err := store.Restore(context.Background(), m.ID, dst, RestoreOptions{TargetType: connection.ConnectionTypePostgreSQL, Tables: []string{"b"}, Mode: RestoreDataOnly}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.stmts) != 1 || dst.stmts[0] != `DELETE FROM "b"` || len(dst.rows["a"]) != 0 || len(dst.rows["b"]) != 1 {
+		t.Errorf("仅数据恢复不符: %v %v", dst.stmts, dst.rows)
+	}
+	if _, err := store.Restore(context.Background(), m.ID, dst, RestoreOptions{Tables: []string{"missing"}}, nil); err == nil {
+		t.Error("恢复不存在的表应报错")
+	}
+}
+
+func TestCaptureFailureLeavesNoFile(t *testing.T) {
+	store := NewStore(t.TempDir(), nil)
+	_, err := store.Capture(context.Background(), &fakeSource{}, CaptureOptions{Name: "s", Tables: []string{"missing"}}, nil)
+	if err == nil {
+		t.Fatal("快照不存在的表应报错")
+	}
+	list, _ := store.List()
+	if len(list) != 0 {
+		t.Errorf("失败的快照不应保留: %+v", list)
+	}
+	if err := store.Delete("../x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("非法 ID 应返回 ErrNotFound: %v", err)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	day := func(d int) *Manifest {
+		at := time.Date(2026, 3, d, 2, 0, 0, 0, time.UTC)
+		return &Manifest{ID: at.Format("0102"), CreatedAt: at}
+	}
+	// 2026-03-16 为周一；倒序排列
+	list := []*Manifest{day(18), day(17), day(16), day(15), day(14), day(8), day(1)}
+	ids := func(ms []*Manifest) string {
+		var out []string
+		for _, m := range ms {
+			out = append(out, m.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(Expired(list, 2, 0)); got != "0316,0315,0314,0308,0301" {
+		t.Errorf("keepLast=2: %s", got)
+	}
+	// 最近 3 周各保留最新一份：0318（第 12 周）、0315（第 11 周）、0308（第 10 周）
+	if got := ids(Expired(list, 1, 3)); got != "0317,0316,0314,0301" {
+		t.Errorf("keepLast=1 keepWeekly=3: %s", got)
+	}
+	if got := Expired(list, 0, 0); got != nil {
+		t.Errorf("未设置保留策略时不应删除: %v", ids(got))
+	}
+}
+
+func TestPruneOnlyTouchesTaskBackups(t *testing.T) {
+	src := &fakeSource{tables: map[string][][]any{"a": {{int64(1), "x", nil, nil}}}}
+	store := NewStore(t.TempDir(), nil)
+	var backups []*Manifest
+	for i := 0; i < 3; i++ {
+		m, err := store.Capture(context.Background(), src, CaptureOptions{Name: "b", Tables: []string{"a"}, TaskID: "task"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, m)
+		time.Sleep(2 * time.Millisecond)
+	}
+	manual, err := store.Capture(context.Background(), src, CaptureOptions{Name: "manual", Tables: []string{"a"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := store.Prune("task", 1, 0)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("应删除 2 份过期备份: %v %d", err, len(deleted))
+	}
+	list, _ := store.List()
+	if len(list) != 2 || list[0].ID != manual.ID || list[1].ID != backups[2].ID {
+		t.Errorf("应保留最新备份与手动快照: %+v", list)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	src := &fakeSource{tables: map[string][][]any{"users": {{int64(1), "x", nil, nil}, {int64(2), nil, nil, nil}}}}
+	src.indexes = []*connection.IndexDefinition{{Name: "idx_name", ColumnName: "name", NonUnique: 1, SeqInIndex: 1}}
+	store := NewStore(t.TempDir(), nil)
+	m, err := store.Capture(context.Background(), src, CaptureOptions{Name: "b", SourceType: connection.ConnectionTypeMySQL, Tables: []string{"users"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := store.Verify(context.Background(), m.ID, nil, ""); err != nil || n != 2 {
+		t.Fatalf("仅校验归档失败: %v，行数 %d", err, n)
+	}
+
+	dst := &fakeTarget{}
+	if _, err := store.Verify(context.Background(), m.ID, dst, connection.ConnectionTypePostgreSQL); err != nil {
+		t.Fatalf("试恢复结构失败: %v", err)
+	}
+	all := strings.Join(dst.stmts, "\n")
+	if !strings.HasPrefix(dst.stmts[0], `CREATE TABLE "_verify_`) || strings.Contains(all, `"users"`) || strings.Contains(all, `"idx_name"`) {
+		t.Errorf("试恢复应只使用临时表名与索引名: %v", dst.stmts)
+	}
+	if last := dst.stmts[len(dst.stmts)-1]; !strings.HasPrefix(last, "DROP TABLE") {
+		t.Errorf("试恢复结束后应删除临时表: %v", dst.stmts)
+	}
+	if len(dst.rows) != 0 {
+		t.Errorf("试恢复不应写入数据: %v", dst.rows)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	m := &Manifest{
+		ID: "s", SourceType: connection.ConnectionTypeMySQL, ServerVersion: "8.0.36",
+		Tables: []*Table{{Name: "users", RowCount: 2}, {Name: "orders", RowCount: 3}},
+	}
+	levels := func(r *PreflightReport) string {
+		var out []string
+		for _, c := range r.Checks {
+			out = append(out, c.Name+"="+string(c.Level))
+		}
+		return strings.Join(out, ",")
+	}
+
+	r := Preflight(m, TargetInfo{Type: connection.ConnectionTypeMySQL, Version: "8.4.0", Database: "app", DatabaseExists: true, Tables: []string{"Users"}}, RestoreOptions{})
+	if !r.OK || r.Rows != 5 || levels(r) != "database=passed,dialect=passed,version=passed,tables=warning" {
+		t.Errorf("覆盖已有表应警告但可恢复: %s %+v", levels(r), r)
+	}
+
+	r = Preflight(m, TargetInfo{Type: connection.ConnectionTypeMySQL, Version: "5.7.44", Database: "new", CreateDatabase: true}, RestoreOptions{Tables: []string{"orders"}})
+	if !r.OK || r.Rows != 3 || !r.CreateDatabase || levels(r) != "database=passed,dialect=passed,version=warning,tables=passed" {
+		t.Errorf("新建库且目标版本较低应警告: %s", levels(r))
+	}
+
+	r = Preflight(m, TargetInfo{Type: connection.ConnectionTypePostgreSQL, Database: "missing"}, RestoreOptions{})
+	if r.OK || levels(r) != "database=failed,dialect=warning,tables=passed" {
+		t.Errorf("目标库不存在应失败，跨方言应警告且不比较版本: %s", levels(r))
+	}
+
+	r = Preflight(m, TargetInfo{Type: connection.ConnectionTypeMySQL, Database: "app", DatabaseExists: true, Tables: []string{"users"}}, RestoreOptions{Mode: RestoreDataOnly})
+	if r.OK || levels(r) != "database=passed,version=warning,tables=failed" {
+		t.Errorf("仅数据恢复缺少目标表应失败: %s", levels(r))
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// ErrNotFound 表示数据库快照不存在。
+var ErrNotFound = errors.New("数据库快照不存在")
+
+// manifestName 是归档内清单文件的名称。
+const manifestName = "manifest.json"
+
+// Table 描述快照中的一张表。
+type Table struct {
+	Name     string                         `json:"name"`              // 表名
+	Columns  []*connection.ColumnDefinition `json:"columns"`           // 列定义
+	Indexes  []*connection.IndexDefinition  `json:"indexes,omitempty"` // 索引定义
+	RowCount int64                          `json:"rowCount"`          // 行数
+	DataFile string                         `json:"dataFile"`          // 归档内的数据文件
+}
+
+// Manifest 是快照归档的清单，描述来源与各表结构。
+type Manifest struct {
+	ID            string                    `json:"id"`                      // 快照 ID
+	Name          string                    `json:"name"`                    // 快照名称
+	SourceType    connection.ConnectionType `json:"sourceType"`              // 来源数据库类型
+	ServerVersion string                    `json:"serverVersion,omitempty"` // 来源服务器版本，未知时为空
+	Source        string                    `json:"source,omitempty"`        // 来源连接摘要
+	Database      string                    `json:"database,omitempty"`      // 来源数据库
+	TaskID        string                    `json:"taskId,omitempty"`        // 创建该快照的定时备份任务，手动快照为空
+	Tables        []*Table                  `json:"tables"`                  // 表列表
+	SizeBytes     int64                     `json:"sizeBytes"`               // 归档文件大小
+	CreatedAt     time.Time                 `json:"createdAt"`               // 创建时间
+}
+
+// FindTable 按名称查找表，不存在时返回 nil。
+func (m *Manifest) FindTable(name string) *Table {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Store 将数据库快照保存为本地 zip 归档：manifest.json 保存结构，每张表的数据按 JSON Lines 存放。
+type Store struct {
+	dir    string       // 存储目录
+	logger *slog.Logger // 日志记录器
+}
+
+// DefaultDir 返回默认数据库快照存储目录。
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "db-snapshots")
+	}
+	return filepath.Join(configDir, "Boxify", "db-snapshots")
+}
+
+// NewStore 创建数据库快照存储，dir 为空时使用默认目录。
+func NewStore(dir string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{dir: dir, logger: logger.With("module", "dbsnapshot")}
+}
+
+// List 返回所有快照清单，按创建时间倒序；损坏的归档会被跳过并记录日志。
+func (s *Store) List() ([]*Manifest, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取数据库快照目录失败：%w", err)
+	}
+	list := make([]*Manifest, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".zip" {
+			continue
+		}
+		m, err := s.Get(strings.TrimSuffix(entry.Name(), ".zip"))
+		if err != nil {
+			s.logger.Warn("跳过无法读取的数据库快照", "file", entry.Name(), "error", err)
+			continue
+		}
+		list = append(list, m)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+// Get 读取快照清单。
+func (s *Store) Get(id string) (*Manifest, error) {
+	zr, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readManifest(&zr.Reader)
+}
+
+// Delete 删除快照归档。
+func (s *Store) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("删除数据库快照失败：%w", err)
+	}
+	s.logger.Info("删除数据库快照", "id", id)
+	return nil
+}
+
+// path 返回快照归档路径，拒绝包含路径分隔符的 ID。
+func (s *Store) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, id+".zip"), nil
+}
+
+// open 打开快照归档。
+func (s *Store) open(id string) (*zip.ReadCloser, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("打开数据库快照失败：%w", err)
+	}
+	return zr, nil
+}
+
+// readManifest 读取归档内的清单。
+func readManifest(zr *zip.Reader) (*Manifest, error) {
+	f, err := zr.Open(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("快照归档缺少清单：%w", err)
+	}
+	defer f.Close()
+	var m Manifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("解析快照清单失败：%w", err)
+	}
+	return &m, nil
+}
+
+// writeManifest 将清单写入归档。
+func writeManifest(zw *zip.Writer, m *Manifest) error {
+	w, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// closeErr 关闭 c，err 为空时记录关闭错误。
+func closeErr(c io.Closer, err *error) {
+	if cerr := c.Close(); cerr != nil && *err == nil {
+		*err = cerr
+	}
+}
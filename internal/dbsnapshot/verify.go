@@ -0,0 +1,148 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbsnapshot
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/google/uuid"
+)
+
+// maxScratchNameLen 是临时表与索引名的最大长度，取 PostgreSQL 的标识符上限。
+const maxScratchNameLen = 63
+
+// Verify 校验快照归档：逐行读取每张表的数据文件，确认可解析、列数与行数和清单一致，返回校验的总行数。
+//
+// dst 不为空时还会在目标库中试恢复表结构：以临时表名按目标方言建表，结束后（无论成败）删除这些临时表。
+func (s *Store) Verify(ctx context.Context, id string, dst datatransfer.Target, targetType connection.ConnectionType) (_ int64, err error) {
+	zr, err := s.open(id)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+	m, err := readManifest(&zr.Reader)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, t := range m.Tables {
+		n, err := verifyRows(ctx, &zr.Reader, t)
+		if err != nil {
+			return total, fmt.Errorf("校验表 %s 失败：%w", t.Name, err)
+		}
+		total += n
+	}
+	if dst != nil {
+		if err := verifySchema(ctx, dst, m.SourceType, targetType, m.Tables); err != nil {
+			return total, err
+		}
+	}
+	s.logger.Info("校验数据库快照", "id", id, "tables", len(m.Tables), "rows", total, "schema", dst != nil)
+	return total, nil
+}
+
+// verifyRows 读取单表数据文件，校验每行列数与总行数。
+func verifyRows(ctx context.Context, zr *zip.Reader, t *Table) (n int64, err error) {
+	f, err := zr.Open(t.DataFile)
+	if err != nil {
+		return 0, fmt.Errorf("快照归档缺少数据文件：%w", err)
+	}
+	defer closeErr(f, &err)
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	for {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		var row []any
+		if err := dec.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return n, fmt.Errorf("第 %d 行解析失败：%w", n+1, err)
+		}
+		if len(row) != len(t.Columns) {
+			return n, fmt.Errorf("第 %d 行有 %d 列，清单记录 %d 列", n+1, len(row), len(t.Columns))
+		}
+		n++
+	}
+	if n != t.RowCount {
+		return n, fmt.Errorf("数据文件有 %d 行，清单记录 %d 行", n, t.RowCount)
+	}
+	return n, nil
+}
+
+// verifySchema 以临时表名在目标库中创建快照中的各表，随后删除。
+func verifySchema(ctx context.Context, dst datatransfer.Target, from, to connection.ConnectionType, tables []*Table) (err error) {
+	builder, err := db.NewDDLBuilder(to)
+	if err != nil {
+		return err
+	}
+	prefix := "_verify_" + strings.ReplaceAll(uuid.New().String(), "-", "")[:8] + "_"
+	var created []string
+	defer func() {
+		for i := len(created) - 1; i >= 0; i-- {
+			stmts, dropErr := builder.DropTable("", created[i], true)
+			for j := 0; dropErr == nil && j < len(stmts); j++ {
+				dropErr = dst.Exec(context.WithoutCancel(ctx), stmts[j])
+			}
+			if dropErr != nil && err == nil {
+				err = fmt.Errorf("删除试恢复临时表 %s 失败：%w", created[i], dropErr)
+			}
+		}
+	}()
+
+	for _, t := range tables {
+		name := scratchName(prefix, t.Name)
+		def := datatransfer.MapTableDefinition(from, to, name, t.Columns, t.Indexes)
+		for _, idx := range def.Indexes {
+			idx.Name = scratchName(prefix, idx.Name)
+		}
+		stmts, err := builder.CreateTable("", def)
+		if err != nil {
+			return fmt.Errorf("生成表 %s 结构失败：%w", t.Name, err)
+		}
+		for i, stmt := range stmts {
+			if err := dst.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("试恢复表 %s 结构失败：%w", t.Name, err)
+			}
+			if i == 0 {
+				created = append(created, name)
+			}
+		}
+	}
+	return nil
+}
+
+// scratchName 返回带前缀的临时名称，超长时按字符截断。
+func scratchName(prefix, name string) string {
+	s := prefix + name
+	for len(s) > maxScratchNameLen {
+		r := []rune(s)
+		s = string(r[:len(r)-1])
+	}
+	return s
+}
@@ -0,0 +1,404 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drafts 持久化编辑器中未保存的内容（草稿），应用或系统崩溃后据此恢复。
+//
+// 每个标签页一个草稿文件，写入经过短暂合并后落盘（临时文件 + fsync + 重命名）。会话开始时写入会话标记，
+// 正常退出时删除；启动时标记仍存在说明上次异常退出，上次会话遗留的草稿可供恢复。
+package drafts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxContentBytes 单个草稿内容的大小上限。
+	MaxContentBytes = 4 << 20
+	// DefaultFlushDelay 默认的写入合并时长，期间同一标签页的多次更新只落盘一次。
+	DefaultFlushDelay = 500 * time.Millisecond
+
+	sessionFile = "session.json"
+)
+
+// ErrDraftNotFound 草稿不存在。
+var ErrDraftNotFound = errors.New("草稿不存在")
+
+// tabIDPattern 限制标签页 ID 的字符，ID 直接用作文件名。
+var tabIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// Draft 是一个标签页的未保存内容。
+type Draft struct {
+	TabID        string    `json:"tabId"`
+	Title        string    `json:"title"`
+	ConnectionID string    `json:"connectionId,omitempty"` // 关联的连接 ID
+	Database     string    `json:"database,omitempty"`     // 关联的数据库
+	FilePath     string    `json:"filePath,omitempty"`     // 关联的本地文件，为空表示未保存过的新查询
+	Content      string    `json:"content"`
+	SessionID    string    `json:"sessionId"` // 写入草稿的会话
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Summary 是恢复列表中的草稿摘要，不含内容。
+type Summary struct {
+	TabID        string    `json:"tabId"`
+	Title        string    `json:"title"`
+	ConnectionID string    `json:"connectionId,omitempty"`
+	Database     string    `json:"database,omitempty"`
+	FilePath     string    `json:"filePath,omitempty"`
+	Size         int       `json:"size"`    // 内容字节数
+	Preview      string    `json:"preview"` // 内容开头，最多 200 个字符
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Recovery 是启动时可恢复草稿的信息。
+type Recovery struct {
+	Crashed         bool      `json:"crashed"`                   // 上次会话未正常退出
+	PreviousStarted time.Time `json:"previousStarted,omitempty"` // 上次会话开始时间，正常退出时为零值
+	Drafts          []Summary `json:"drafts"`                    // 上次及更早会话遗留的草稿，最近更新的在前
+}
+
+// session 是会话标记文件内容。
+type session struct {
+	ID        string    `json:"id"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// Store 保存草稿，可并发使用。
+type Store struct {
+	mu         sync.Mutex
+	dir        string
+	logger     *slog.Logger
+	flushDelay time.Duration
+	now        func() time.Time
+
+	sessionID string
+	previous  *session          // 上次未正常结束的会话，Begin 时读取
+	pending   map[string]*Draft // 等待落盘的草稿
+	timer     *time.Timer
+}
+
+// DefaultDir 返回默认的草稿目录。
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "drafts")
+	}
+	return filepath.Join(configDir, "Boxify", "drafts")
+}
+
+// NewStore 创建草稿存储，dir 为空时使用默认目录，flushDelay <= 0 时每次更新立即落盘。
+func NewStore(dir string, flushDelay time.Duration, logger *slog.Logger) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		dir:        dir,
+		logger:     logger.With("module", "drafts"),
+		flushDelay: flushDelay,
+		now:        time.Now,
+		sessionID:  uuid.New().String(),
+		pending:    make(map[string]*Draft),
+	}
+}
+
+// SessionID 返回当前会话 ID。
+func (s *Store) SessionID() string {
+	return s.sessionID
+}
+
+// Begin 开始会话：读取上次的会话标记判断是否异常退出，并写入当前会话标记。
+func (s *Store) Begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := filepath.Join(s.dir, sessionFile)
+	if data, err := os.ReadFile(path); err == nil {
+		var prev session
+		if err := json.Unmarshal(data, &prev); err == nil && prev.ID != "" {
+			s.previous = &prev
+			s.logger.Warn("检测到上次会话未正常退出", "sessionId", prev.ID, "startedAt", prev.StartedAt)
+		}
+	}
+	cur := session{ID: s.sessionID, PID: os.Getpid(), StartedAt: s.now()}
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return fmt.Errorf("序列化会话标记失败：%w", err)
+	}
+	return writeFileSync(s.dir, path, data)
+}
+
+// End 正常结束会话：写入全部待落盘的草稿并删除会话标记。
+func (s *Store) End() error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(filepath.Join(s.dir, sessionFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除会话标记失败：%w", err)
+	}
+	return nil
+}
+
+// Save 更新标签页草稿，在合并时长结束后落盘。
+func (s *Store) Save(d Draft) error {
+	if err := ValidTabID(d.TabID); err != nil {
+		return err
+	}
+	if len(d.Content) > MaxContentBytes {
+		return fmt.Errorf("草稿内容超过 %d 字节上限", MaxContentBytes)
+	}
+	d.SessionID = s.sessionID
+	d.UpdatedAt = s.now()
+
+	s.mu.Lock()
+	s.pending[d.TabID] = &d
+	if s.flushDelay > 0 {
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.flushDelay, func() {
+				if err := s.Flush(); err != nil {
+					s.logger.Error("写入草稿失败", "error", err)
+				}
+			})
+		}
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+	return s.Flush()
+}
+
+// Flush 立即写入全部待落盘的草稿，返回遇到的第一个错误。
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	var firstErr error
+	for tabID, d := range s.pending {
+		data, err := json.Marshal(d)
+		if err == nil {
+			err = writeFileSync(s.dir, s.pathFor(tabID), data)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("写入草稿 %s 失败：%w", tabID, err)
+			}
+			continue
+		}
+		delete(s.pending, tabID)
+	}
+	return firstErr
+}
+
+// Discard 删除标签页草稿（内容已保存或标签页已关闭）。
+func (s *Store) Discard(tabID string) error {
+	if err := ValidTabID(tabID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, tabID)
+	if err := os.Remove(s.pathFor(tabID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除草稿失败：%w", err)
+	}
+	return nil
+}
+
+// Recovery 返回上次及更早会话遗留的草稿，当前会话写入的草稿不在其中。
+func (s *Store) Recovery() (*Recovery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	rec := &Recovery{Crashed: s.previous != nil, Drafts: []Summary{}}
+	if s.previous != nil {
+		rec.PreviousStarted = s.previous.StartedAt
+	}
+	for _, d := range all {
+		if d.SessionID == s.sessionID {
+			continue
+		}
+		rec.Drafts = append(rec.Drafts, summarize(d))
+	}
+	sort.Slice(rec.Drafts, func(i, j int) bool { return rec.Drafts[i].UpdatedAt.After(rec.Drafts[j].UpdatedAt) })
+	return rec, nil
+}
+
+// Recover 读取遗留草稿的完整内容并归入当前会话，之后由当前会话继续更新或删除。
+func (s *Store) Recover(tabID string) (*Draft, error) {
+	if err := ValidTabID(tabID); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.pending[tabID]; ok {
+		c := *d
+		return &c, nil
+	}
+	d, err := readDraft(s.pathFor(tabID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrDraftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.SessionID != s.sessionID {
+		d.SessionID = s.sessionID
+		data, err := json.Marshal(d)
+		if err != nil {
+			return nil, fmt.Errorf("序列化草稿失败：%w", err)
+		}
+		if err := writeFileSync(s.dir, s.pathFor(tabID), data); err != nil {
+			return nil, fmt.Errorf("写入草稿失败：%w", err)
+		}
+	}
+	return d, nil
+}
+
+// DiscardRecovery 删除全部遗留草稿，返回删除的数量。
+func (s *Store) DiscardRecovery() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.readAllLocked()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, d := range all {
+		if d.SessionID == s.sessionID {
+			continue
+		}
+		if err := os.Remove(s.pathFor(d.TabID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("删除草稿失败：%w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ValidTabID 校验标签页 ID：1-128 个字母、数字、下划线或连字符。
+func ValidTabID(tabID string) error {
+	if !tabIDPattern.MatchString(tabID) {
+		return fmt.Errorf("标签页 ID 无效: %q", tabID)
+	}
+	return nil
+}
+
+// readAllLocked 读取目录下全部草稿，跳过无法解析的文件；调用方需持有锁。
+func (s *Store) readAllLocked() ([]*Draft, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取草稿目录失败：%w", err)
+	}
+	var list []*Draft
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == sessionFile || filepath.Ext(name) != ".json" {
+			continue
+		}
+		d, err := readDraft(filepath.Join(s.dir, name))
+		if err != nil {
+			s.logger.Warn("跳过无法读取的草稿文件", "file", name, "error", err)
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// pathFor 返回草稿文件路径，tabID 需已校验。
+func (s *Store) pathFor(tabID string) string {
+	return filepath.Join(s.dir, tabID+".json")
+}
+
+// readDraft 读取并解析单个草稿文件。
+func readDraft(path string) (*Draft, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d Draft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("解析草稿失败：%w", err)
+	}
+	if ValidTabID(d.TabID) != nil {
+		return nil, errors.New("草稿文件缺少有效的标签页 ID")
+	}
+	return &d, nil
+}
+
+// summarize 生成草稿摘要。
+func summarize(d *Draft) Summary {
+	preview := []rune(strings.TrimSpace(d.Content))
+	if len(preview) > 200 {
+		preview = preview[:200]
+	}
+	return Summary{
+		TabID:        d.TabID,
+		Title:        d.Title,
+		ConnectionID: d.ConnectionID,
+		Database:     d.Database,
+		FilePath:     d.FilePath,
+		Size:         len(d.Content),
+		Preview:      string(preview),
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
+
+// writeFileSync 写入临时文件并同步到磁盘后替换目标文件，保证崩溃时不会留下半个文件。
+func writeFileSync(dir, path string, data []byte) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("创建草稿目录失败：%w", err)
+	}
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drafts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrashRecovery(t *testing.T) {
+	dir := t.TempDir()
+
+	// 第一次会话写入草稿后未调用 End，模拟崩溃
+	first := NewStore(dir, 0, nil)
+	if err := first.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := first.Save(Draft{TabID: "tab-1", Title: "查询 1", Content: "SELECT 1;"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := first.Save(Draft{TabID: "tab-2", Title: "查询 2", Content: strings.Repeat("x", 300)}); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewStore(dir, 0, nil)
+	if err := second.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	second.Save(Draft{TabID: "tab-3", Title: "新会话", Content: "SELECT 3;"})
+	rec, err := second.Recovery()
+	if err != nil {
+		t.Fatalf("Recovery() error = %v", err)
+	}
+	if !rec.Crashed || rec.PreviousStarted.IsZero() {
+		t.Fatalf("应检测到上次异常退出: %+v", rec)
+	}
+	if len(rec.Drafts) != 2 {
+		t.Fatalf("应只列出上次会话的草稿: %+v", rec.Drafts)
+	}
+	for _, d := range rec.Drafts {
+		if d.TabID == "tab-2" && (len([]rune(d.Preview)) != 200 || d.Size != 300) {
+			t.Fatalf("摘要预览应截断为 200 个字符: %+v", d)
+		}
+	}
+
+	d, err := second.Recover("tab-1")
+	if err != nil || d.Content != "SELECT 1;" {
+		t.Fatalf("Recover() = %+v, %v", d, err)
+	}
+	if rec, _ := second.Recovery(); len(rec.Drafts) != 1 {
+		t.Fatalf("恢复后的草稿应归入当前会话: %+v", rec.Drafts)
+	}
+	if n, err := second.DiscardRecovery(); err != nil || n != 1 {
+		t.Fatalf("DiscardRecovery() = %d, %v", n, err)
+	}
+	if _, err := second.Recover("tab-2"); err != ErrDraftNotFound {
+		t.Fatalf("丢弃后的草稿不应存在, got %v", err)
+	}
+
+	// 正常退出后不再视为崩溃
+	if err := second.End(); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	third := NewStore(dir, 0, nil)
+	third.Begin()
+	rec, _ = third.Recovery()
+	if rec.Crashed || len(rec.Drafts) != 2 {
+		t.Fatalf("正常退出后遗留草稿仍可恢复但不标记崩溃: %+v", rec)
+	}
+}
+
+func TestSaveCoalescesWrites(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir, time.Hour, nil)
+	for _, content := range []string{"S", "SE", "SEL"} {
+		if err := s.Save(Draft{TabID: "tab", Content: content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tab.json")); !os.IsNotExist(err) {
+		t.Fatalf("合并时长内不应落盘, stat err = %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	d, err := readDraft(filepath.Join(dir, "tab.json"))
+	if err != nil || d.Content != "SEL" || d.SessionID != s.SessionID() {
+		t.Fatalf("落盘内容应为最后一次更新: %+v, %v", d, err)
+	}
+
+	if err := s.Discard("tab"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tab.json")); !os.IsNotExist(err) {
+		t.Fatalf("Discard 后草稿文件应被删除, stat err = %v", err)
+	}
+}
+
+func TestSaveValidation(t *testing.T) {
+	s := NewStore(t.TempDir(), 0, nil)
+	for _, id := range []string{"", "../etc", "a/b", strings.Repeat("a", 129)} {
+		if err := s.Save(Draft{TabID: id}); err == nil {
+			t.Errorf("Save(tabID=%q) 应返回错误", id)
+		}
+	}
+	if err := s.Save(Draft{TabID: "big", Content: strings.Repeat("x", MaxContentBytes+1)}); err == nil {
+		t.Error("超过大小上限的草稿应返回错误")
+	}
+}
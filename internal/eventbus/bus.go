@@ -0,0 +1,304 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+)
+
+const (
+	// DefaultMaxBufferedPerEvent 默认每个事件最多缓冲的条数。
+	DefaultMaxBufferedPerEvent = 256
+	// DefaultBufferTTL 默认缓冲事件的存活时间。
+	DefaultBufferTTL = 30 * time.Second
+	// maxDeadLetters 保留的死信记录条数。
+	maxDeadLetters = 100
+)
+
+// 死信原因。
+const (
+	DeadLetterOverflow = "overflow"  // 缓冲区已满，最早的事件被丢弃
+	DeadLetterExpired  = "expired"   // 缓冲事件超过存活时间
+	DeadLetterNoBuffer = "no_buffer" // 无订阅者且未启用缓冲
+)
+
+// Emitter 事件发射接口（解耦 Wails 依赖）。
+type Emitter interface {
+	Emit(event string, data interface{})
+}
+
+// Options 事件总线参数。
+type Options struct {
+	BufferUnsubscribed  bool          // 已跟踪事件暂无订阅者时是否缓冲，订阅恢复后补发
+	MaxBufferedPerEvent int           // 每个事件最多缓冲的条数
+	BufferTTL           time.Duration // 缓冲事件存活时间
+}
+
+// DefaultOptions 返回默认事件总线参数。
+func DefaultOptions() Options {
+	return Options{
+		BufferUnsubscribed:  true,
+		MaxBufferedPerEvent: DefaultMaxBufferedPerEvent,
+		BufferTTL:           DefaultBufferTTL,
+	}
+}
+
+// normalize 对非法参数回退默认值。
+func (o Options) normalize() Options {
+	if o.MaxBufferedPerEvent <= 0 {
+		o.MaxBufferedPerEvent = DefaultMaxBufferedPerEvent
+	}
+	if o.BufferTTL <= 0 {
+		o.BufferTTL = DefaultBufferTTL
+	}
+	return o
+}
+
+// bufferedEvent 等待订阅者的缓冲事件。
+type bufferedEvent struct {
+	data any
+	at   time.Time
+}
+
+// eventState 单个事件的订阅、缓冲与计数状态。
+type eventState struct {
+	subscribers map[string]time.Time // 窗口名 -> 订阅时间
+	tracked     bool                 // 是否曾被订阅（仅跟踪过的事件才缓冲）
+	buffer      []bufferedEvent
+	emitted     int64
+	delivered   int64
+	buffered    int64
+	replayed    int64
+	dropped     int64
+	lastEmitAt  time.Time
+}
+
+// Bus 包装应用事件总线：记录各窗口的事件订阅，
+// 在窗口重载等订阅空窗期缓冲事件，订阅恢复后按序补发，避免事件静默丢失。
+//
+// 从未被订阅过的事件直接透传，以兼容未接入订阅上报的前端代码。
+type Bus struct {
+	mu          sync.Mutex
+	emitter     Emitter
+	logger      *slog.Logger
+	opts        Options
+	events      map[string]*eventState
+	deadLetters []*types.EventBusDeadLetter
+	now         func() time.Time
+}
+
+// NewBus 创建事件总线。
+func NewBus(emitter Emitter, logger *slog.Logger, opts Options) *Bus {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Bus{
+		emitter: emitter,
+		logger:  logger.With("module", "eventbus"),
+		opts:    opts.normalize(),
+		events:  make(map[string]*eventState),
+		now:     time.Now,
+	}
+}
+
+// Options 返回当前生效的总线参数。
+func (b *Bus) Options() Options {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.opts
+}
+
+// SetOptions 更新总线参数；关闭缓冲时已缓冲的事件转为死信。
+func (b *Bus) SetOptions(opts Options) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.opts = opts.normalize()
+	if b.opts.BufferUnsubscribed {
+		return
+	}
+	for name, st := range b.events {
+		for range st.buffer {
+			b.addDeadLetterLocked(name, DeadLetterNoBuffer)
+		}
+		st.dropped += int64(len(st.buffer))
+		st.buffer = nil
+	}
+}
+
+// Emit 发送事件：有订阅者或未被跟踪时直接发送，否则按配置缓冲。
+func (b *Bus) Emit(event string, data interface{}) {
+	b.mu.Lock()
+	st := b.stateLocked(event)
+	now := b.now()
+	st.emitted++
+	st.lastEmitAt = now
+
+	if !st.tracked || len(st.subscribers) > 0 {
+		st.delivered++
+		b.mu.Unlock()
+		b.emitter.Emit(event, data)
+		return
+	}
+
+	if !b.opts.BufferUnsubscribed {
+		st.dropped++
+		b.addDeadLetterLocked(event, DeadLetterNoBuffer)
+		b.mu.Unlock()
+		// 未启用缓冲时仍透传，由前端自行决定是否处理
+		b.emitter.Emit(event, data)
+		return
+	}
+
+	b.expireLocked(event, st, now)
+	if len(st.buffer) >= b.opts.MaxBufferedPerEvent {
+		st.buffer = st.buffer[1:]
+		st.dropped++
+		b.addDeadLetterLocked(event, DeadLetterOverflow)
+	}
+	st.buffer = append(st.buffer, bufferedEvent{data: data, at: now})
+	st.buffered++
+	b.mu.Unlock()
+}
+
+// Subscribe 记录窗口订阅事件，并补发订阅空窗期内缓冲的事件，返回补发条数。
+func (b *Bus) Subscribe(window, event string) int {
+	b.mu.Lock()
+	st := b.stateLocked(event)
+	st.tracked = true
+	if _, exists := st.subscribers[window]; !exists {
+		st.subscribers[window] = b.now()
+	}
+	b.expireLocked(event, st, b.now())
+	pending := st.buffer
+	st.buffer = nil
+	st.replayed += int64(len(pending))
+	st.delivered += int64(len(pending))
+	b.mu.Unlock()
+
+	for _, item := range pending {
+		b.emitter.Emit(event, item.data)
+	}
+	if len(pending) > 0 {
+		b.logger.Info("补发缓冲事件", "event", event, "window", window, "count", len(pending))
+	}
+	return len(pending)
+}
+
+// Unsubscribe 移除窗口对事件的订阅。
+func (b *Bus) Unsubscribe(window, event string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if st, ok := b.events[event]; ok {
+		delete(st.subscribers, window)
+	}
+}
+
+// RemoveWindow 移除窗口的全部订阅（窗口关闭或重载时调用），返回移除的订阅数。
+func (b *Bus) RemoveWindow(window string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	removed := 0
+	for _, st := range b.events {
+		if _, ok := st.subscribers[window]; ok {
+			delete(st.subscribers, window)
+			removed++
+		}
+	}
+	if removed > 0 {
+		b.logger.Debug("移除窗口事件订阅", "window", window, "count", removed)
+	}
+	return removed
+}
+
+// Stats 返回事件总线状态快照。
+func (b *Bus) Stats() *types.EventBusStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	stats := &types.EventBusStats{
+		BufferEnabled:       b.opts.BufferUnsubscribed,
+		MaxBufferedPerEvent: b.opts.MaxBufferedPerEvent,
+		BufferTTLMs:         b.opts.BufferTTL.Milliseconds(),
+		Events:              make([]*types.EventBusEventStat, 0, len(b.events)),
+		DeadLetters:         append([]*types.EventBusDeadLetter(nil), b.deadLetters...),
+	}
+	for name, st := range b.events {
+		b.expireLocked(name, st, now)
+		subs := make([]*types.EventSubscription, 0, len(st.subscribers))
+		for window, since := range st.subscribers {
+			subs = append(subs, &types.EventSubscription{Window: window, SinceMs: since.UnixMilli()})
+		}
+		sort.Slice(subs, func(i, j int) bool { return subs[i].Window < subs[j].Window })
+		stat := &types.EventBusEventStat{
+			Event:       name,
+			Tracked:     st.tracked,
+			Subscribers: subs,
+			Pending:     len(st.buffer),
+			Emitted:     st.emitted,
+			Delivered:   st.delivered,
+			Buffered:    st.buffered,
+			Replayed:    st.replayed,
+			Dropped:     st.dropped,
+		}
+		if !st.lastEmitAt.IsZero() {
+			stat.LastEmitAtMs = st.lastEmitAt.UnixMilli()
+		}
+		stats.Events = append(stats.Events, stat)
+	}
+	sort.Slice(stats.Events, func(i, j int) bool { return stats.Events[i].Event < stats.Events[j].Event })
+	return stats
+}
+
+// stateLocked 获取或创建事件状态，调用方需持有锁。
+func (b *Bus) stateLocked(event string) *eventState {
+	st, ok := b.events[event]
+	if !ok {
+		st = &eventState{subscribers: make(map[string]time.Time)}
+		b.events[event] = st
+	}
+	return st
+}
+
+// expireLocked 丢弃超过存活时间的缓冲事件，调用方需持有锁。
+func (b *Bus) expireLocked(event string, st *eventState, now time.Time) {
+	idx := 0
+	for idx < len(st.buffer) && now.Sub(st.buffer[idx].at) > b.opts.BufferTTL {
+		b.addDeadLetterLocked(event, DeadLetterExpired)
+		idx++
+	}
+	if idx > 0 {
+		st.dropped += int64(idx)
+		st.buffer = st.buffer[idx:]
+		b.logger.Warn("缓冲事件已过期丢弃", "event", event, "count", idx)
+	}
+}
+
+// addDeadLetterLocked 记录死信，仅保留最近 maxDeadLetters 条，调用方需持有锁。
+func (b *Bus) addDeadLetterLocked(event, reason string) {
+	b.deadLetters = append(b.deadLetters, &types.EventBusDeadLetter{
+		Event:  event,
+		Reason: reason,
+		AtMs:   b.now().UnixMilli(),
+	})
+	if overflow := len(b.deadLetters) - maxDeadLetters; overflow > 0 {
+		b.deadLetters = b.deadLetters[overflow:]
+	}
+}
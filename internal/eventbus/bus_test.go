@@ -0,0 +1,132 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordEmitter 记录发送事件的测试发射器。
+type recordEmitter struct {
+	mu     sync.Mutex
+	events []string
+	data   []interface{}
+}
+
+func (r *recordEmitter) Emit(event string, data interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	r.data = append(r.data, data)
+}
+
+func (r *recordEmitter) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestUntrackedEventsPassThrough(t *testing.T) {
+	rec := &recordEmitter{}
+	bus := NewBus(rec, nil, DefaultOptions())
+
+	bus.Emit("menu:clicked", 1)
+	if rec.count() != 1 {
+		t.Fatalf("未跟踪事件应直接透传，得到 %d 条", rec.count())
+	}
+}
+
+func TestBufferAndReplayOnResubscribe(t *testing.T) {
+	rec := &recordEmitter{}
+	bus := NewBus(rec, nil, DefaultOptions())
+
+	bus.Subscribe("main", "terminal:output")
+	bus.Emit("terminal:output", "a")
+	bus.RemoveWindow("main") // 模拟窗口重载
+	bus.Emit("terminal:output", "b")
+	bus.Emit("terminal:output", "c")
+	if rec.count() != 1 {
+		t.Fatalf("无订阅者期间应缓冲，已发送 %d 条", rec.count())
+	}
+
+	if n := bus.Subscribe("main", "terminal:output"); n != 2 {
+		t.Fatalf("期望补发 2 条，得到 %d", n)
+	}
+	if rec.count() != 3 || rec.data[1] != "b" || rec.data[2] != "c" {
+		t.Fatalf("补发顺序错误: %v", rec.data)
+	}
+
+	stat := bus.Stats().Events[0]
+	if stat.Emitted != 3 || stat.Buffered != 2 || stat.Replayed != 2 || stat.Delivered != 3 || stat.Pending != 0 {
+		t.Errorf("统计不正确: %+v", stat)
+	}
+	if len(stat.Subscribers) != 1 || stat.Subscribers[0].Window != "main" {
+		t.Errorf("订阅者不正确: %+v", stat.Subscribers)
+	}
+}
+
+func TestBufferOverflowAndExpiry(t *testing.T) {
+	rec := &recordEmitter{}
+	now := time.Unix(1000, 0)
+	bus := NewBus(rec, nil, Options{BufferUnsubscribed: true, MaxBufferedPerEvent: 2, BufferTTL: time.Second})
+	bus.now = func() time.Time { return now }
+
+	bus.Subscribe("main", "sync")
+	bus.Unsubscribe("main", "sync")
+	bus.Emit("sync", 1)
+	bus.Emit("sync", 2)
+	bus.Emit("sync", 3) // 溢出，丢弃 1
+
+	now = now.Add(500 * time.Millisecond)
+	bus.Emit("sync", 4) // 溢出，丢弃 2
+
+	now = now.Add(700 * time.Millisecond) // 3 已过期
+	if n := bus.Subscribe("main", "sync"); n != 1 {
+		t.Fatalf("期望补发 1 条，得到 %d", n)
+	}
+	if rec.data[0] != 4 {
+		t.Errorf("期望补发最新事件，得到 %v", rec.data[0])
+	}
+
+	stats := bus.Stats()
+	if stats.Events[0].Dropped != 3 {
+		t.Errorf("期望丢弃 3 条，得到 %d", stats.Events[0].Dropped)
+	}
+	reasons := map[string]int{}
+	for _, dl := range stats.DeadLetters {
+		reasons[dl.Reason]++
+	}
+	if reasons[DeadLetterOverflow] != 2 || reasons[DeadLetterExpired] != 1 {
+		t.Errorf("死信原因统计不正确: %v", reasons)
+	}
+}
+
+func TestBufferDisabled(t *testing.T) {
+	rec := &recordEmitter{}
+	bus := NewBus(rec, nil, Options{BufferUnsubscribed: false})
+
+	bus.Subscribe("main", "sync")
+	bus.RemoveWindow("main")
+	bus.Emit("sync", 1)
+	if rec.count() != 1 {
+		t.Fatalf("未启用缓冲时应透传，得到 %d 条", rec.count())
+	}
+	stats := bus.Stats()
+	if len(stats.DeadLetters) != 1 || stats.DeadLetters[0].Reason != DeadLetterNoBuffer {
+		t.Errorf("期望记录 no_buffer 死信: %+v", stats.DeadLetters)
+	}
+}
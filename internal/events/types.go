@@ -19,5 +19,26 @@ type EventType string
 const (
 	EventTypeGitStatusChanged               EventType = "git:status-changed"
 	EventTypeTerminalInteractionModeChanged EventType = "terminal:interaction_mode_change"
+	EventTypeTerminalClosed                 EventType = "terminal:closed"
+	EventTypeTerminalIdleWarning            EventType = "terminal:idle_warning"
+	EventTypeTerminalOutputTruncated        EventType = "terminal:output_truncated"
 	EventTypeClawChatEvent                  EventType = "claw:chat-event"
+	EventTypeTransferEnd                    EventType = "transfer:end"
+	EventTypeInitialDataChunk               EventType = "initial-data:chunk"
+	EventTypeReportPreviewChunk             EventType = "report-preview:chunk"
+	EventTypeDataTransferProgress           EventType = "data-transfer:progress"
+	EventTypeSSHTunnelStatus                EventType = "ssh:tunnel-status"
+	EventTypeJobProgress                    EventType = "job:progress"
+	EventTypeJobCompleted                   EventType = "job:completed"
+	EventTypeJobFailed                      EventType = "job:failed"
+	EventTypeJobCancelled                   EventType = "job:cancelled"
+	EventTypeSchedulerRun                   EventType = "scheduler:run"
+	EventTypeNotificationClicked            EventType = "notification:clicked"
+	EventTypeQueryWatchUpdate               EventType = "query-watch:update"
+	EventTypeConnectionState                EventType = "connection:state"
+	EventTypeSettingsChanged                EventType = "settings:changed"
+	EventTypeRecentsChanged                 EventType = "recents:changed"
+	EventTypeQueryQueue                     EventType = "query:queue"
+	EventTypeConnectionHostChanged          EventType = "connection:host-changed"
+	EventTypeSSHHostKeyPrompt               EventType = "ssh:host-key-prompt"
 )
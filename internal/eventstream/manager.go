@@ -0,0 +1,203 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventstream
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultChunkSize 默认单个分块最大字节数（原始字节，编码前）。
+	DefaultChunkSize = 32 * 1024
+	// DefaultMaxInFlight 默认在途未确认分块上限。
+	DefaultMaxInFlight = 8
+	// DefaultAckTimeout 默认等待确认超时时间。
+	DefaultAckTimeout = 3 * time.Second
+)
+
+// Emitter 事件发射接口（解耦 Wails 依赖）。
+type Emitter interface {
+	Emit(event string, data interface{})
+}
+
+// Options 分块传输参数。
+type Options struct {
+	ChunkSize   int           // 单个分块最大字节数
+	MaxInFlight int           // 在途未确认分块上限
+	AckTimeout  time.Duration // 等待确认超时，超时后该流降级为无流控
+}
+
+// DefaultOptions 返回默认分块传输参数。
+func DefaultOptions() Options {
+	return Options{
+		ChunkSize:   DefaultChunkSize,
+		MaxInFlight: DefaultMaxInFlight,
+		AckTimeout:  DefaultAckTimeout,
+	}
+}
+
+// normalize 对非法参数回退默认值。
+func (o Options) normalize() Options {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultChunkSize
+	}
+	if o.MaxInFlight <= 0 {
+		o.MaxInFlight = DefaultMaxInFlight
+	}
+	if o.AckTimeout <= 0 {
+		o.AckTimeout = DefaultAckTimeout
+	}
+	return o
+}
+
+// Manager 管理所有分块传输流，负责确认分发与状态统计。
+type Manager struct {
+	mu      sync.RWMutex
+	emitter Emitter
+	logger  *slog.Logger
+	opts    Options
+	streams map[string]*Stream // streamID -> 传输流
+}
+
+// NewManager 创建分块传输管理器。
+func NewManager(emitter Emitter, logger *slog.Logger, opts Options) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{
+		emitter: emitter,
+		logger:  logger.With("module", "eventstream"),
+		opts:    opts.normalize(),
+		streams: make(map[string]*Stream),
+	}
+}
+
+// Options 返回当前生效的传输参数。
+func (m *Manager) Options() Options {
+	return m.opts
+}
+
+// ShouldChunk 判断负载是否超过单个分块大小，需要走分块通道。
+func (m *Manager) ShouldChunk(size int) bool {
+	return size > m.opts.ChunkSize
+}
+
+// Open 打开一个分块传输流，meta 会合并进每个分块事件负载。
+func (m *Manager) Open(event string, meta map[string]interface{}) *Stream {
+	s := newStream(m, uuid.New().String(), event, meta)
+
+	m.mu.Lock()
+	m.streams[s.id] = s
+	m.mu.Unlock()
+
+	m.logger.Debug("打开分块传输流", "streamId", s.id, "event", event)
+	return s
+}
+
+// Send 一次性分块发送完整负载，发送结束后自动关闭传输流。
+func (m *Manager) Send(ctx context.Context, event string, meta map[string]interface{}, payload []byte) (string, error) {
+	s := m.Open(event, meta)
+	err := s.Write(ctx, payload, nil)
+	s.Close()
+	if err != nil {
+		m.logger.Warn("分块发送失败", "streamId", s.id, "event", event, "size", len(payload), "error", err)
+		return s.id, err
+	}
+	m.logger.Info("分块发送完成", "streamId", s.id, "event", event, "size", len(payload))
+	return s.id, nil
+}
+
+// Ack 处理消费端累计确认：seq 及之前的分块均视为已接收。
+func (m *Manager) Ack(streamID string, seq int64) error {
+	s, ok := m.get(streamID)
+	if !ok {
+		return fmt.Errorf("传输流不存在: %s", streamID)
+	}
+	return s.ack(seq)
+}
+
+// Cancel 取消传输流，阻塞中的写入会立即返回。
+func (m *Manager) Cancel(streamID string) error {
+	s, ok := m.get(streamID)
+	if !ok {
+		return fmt.Errorf("传输流不存在: %s", streamID)
+	}
+	s.cancel()
+	m.logger.Info("分块传输流已取消", "streamId", streamID)
+	return nil
+}
+
+// Stats 返回所有活跃传输流的状态快照（按创建时间排序）。
+func (m *Manager) Stats() []*types.TransferStreamStat {
+	m.mu.RLock()
+	list := make([]*Stream, 0, len(m.streams))
+	for _, s := range m.streams {
+		list = append(list, s)
+	}
+	m.mu.RUnlock()
+
+	stats := make([]*types.TransferStreamStat, 0, len(list))
+	for _, s := range list {
+		stats = append(stats, s.stat())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].CreatedAtMs < stats[j].CreatedAtMs
+	})
+	return stats
+}
+
+// CloseAll 取消并关闭所有传输流，用于应用退出。
+func (m *Manager) CloseAll() {
+	m.mu.RLock()
+	list := make([]*Stream, 0, len(m.streams))
+	for _, s := range m.streams {
+		list = append(list, s)
+	}
+	m.mu.RUnlock()
+
+	for _, s := range list {
+		s.cancel()
+		s.Close()
+	}
+}
+
+// get 按 ID 查找传输流。
+func (m *Manager) get(streamID string) (*Stream, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.streams[streamID]
+	return s, ok
+}
+
+// remove 从管理器中移除传输流并发送结束事件。
+func (m *Manager) remove(s *Stream, end types.TransferEndEvent) {
+	m.mu.Lock()
+	delete(m.streams, s.id)
+	m.mu.Unlock()
+
+	if m.emitter != nil {
+		m.emitter.Emit(string(events.EventTypeTransferEnd), end)
+	}
+	m.logger.Debug("关闭分块传输流", "streamId", s.id, "chunks", end.TotalChunks, "bytes", end.TotalBytes)
+}
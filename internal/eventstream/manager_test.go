@@ -0,0 +1,238 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventstream
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/types"
+)
+
+// recordEmitter 记录发送事件的测试发射器。
+type recordEmitter struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+type recordedEvent struct {
+	name string
+	data interface{}
+}
+
+func (r *recordEmitter) Emit(event string, data interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, recordedEvent{name: event, data: data})
+}
+
+func (r *recordEmitter) chunks(event string) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []map[string]interface{}
+	for _, e := range r.events {
+		if e.name == event {
+			out = append(out, e.data.(map[string]interface{}))
+		}
+	}
+	return out
+}
+
+func (r *recordEmitter) last(event string) interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].name == event {
+			return r.events[i].data
+		}
+	}
+	return nil
+}
+
+func TestManager_SendSplitsPayload(t *testing.T) {
+	emitter := &recordEmitter{}
+	m := NewManager(emitter, nil, Options{ChunkSize: 4, MaxInFlight: 2, AckTimeout: time.Second})
+
+	id, err := m.Send(context.Background(), "test:chunk", map[string]interface{}{"k": "v"}, []byte("0123456789"))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	chunks := emitter.chunks("test:chunk")
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	var joined []byte
+	for i, c := range chunks {
+		if c["streamId"] != id || c["k"] != "v" || c["seq"] != int64(i+1) {
+			t.Fatalf("unexpected chunk payload: %#v", c)
+		}
+		b, _ := base64.StdEncoding.DecodeString(c["data"].(string))
+		joined = append(joined, b...)
+	}
+	if string(joined) != "0123456789" {
+		t.Fatalf("reassembled payload = %q", joined)
+	}
+	end, ok := emitter.last(string(events.EventTypeTransferEnd)).(types.TransferEndEvent)
+	if !ok || end.StreamID != id || end.TotalChunks != 3 || end.TotalBytes != 10 {
+		t.Fatalf("unexpected end event: %#v", end)
+	}
+	if len(m.Stats()) != 0 {
+		t.Fatal("stream should be removed after Send")
+	}
+}
+
+func TestStream_BlocksUntilAck(t *testing.T) {
+	emitter := &recordEmitter{}
+	m := NewManager(emitter, nil, Options{ChunkSize: 1, MaxInFlight: 2, AckTimeout: 5 * time.Second})
+	s := m.Open("test:chunk", nil)
+	defer s.Close()
+
+	// 首个确认开启流控
+	if err := s.Write(context.Background(), []byte("ab"), nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := m.Ack(s.ID(), 1); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Write(context.Background(), []byte("cd"), nil)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("write should block while window is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := m.Ack(s.ID(), 3); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("write should resume after ack")
+	}
+
+	if got := len(emitter.chunks("test:chunk")); got != 4 {
+		t.Fatalf("expected 4 chunks, got %d", got)
+	}
+}
+
+func TestStream_DegradesOnAckTimeout(t *testing.T) {
+	m := NewManager(&recordEmitter{}, nil, Options{ChunkSize: 1, MaxInFlight: 1, AckTimeout: 20 * time.Millisecond})
+	s := m.Open("test:chunk", nil)
+	defer s.Close()
+
+	_ = s.Write(context.Background(), []byte("a"), nil)
+	_ = m.Ack(s.ID(), 1)
+
+	if err := s.Write(context.Background(), []byte("bcd"), nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	stats := m.Stats()
+	if len(stats) != 1 || !stats[0].Degraded {
+		t.Fatalf("stream should be degraded, stats=%+v", stats)
+	}
+}
+
+func TestStream_CancelUnblocksWriter(t *testing.T) {
+	m := NewManager(&recordEmitter{}, nil, Options{ChunkSize: 1, MaxInFlight: 1, AckTimeout: 5 * time.Second})
+	s := m.Open("test:chunk", nil)
+	defer s.Close()
+
+	_ = s.Write(context.Background(), []byte("a"), nil)
+	_ = m.Ack(s.ID(), 1)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Write(context.Background(), []byte("bc"), nil)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if err := m.Cancel(s.ID()); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != ErrStreamClosed {
+			t.Fatalf("expected ErrStreamClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancel should unblock writer")
+	}
+}
+
+func TestStream_WaitReady(t *testing.T) {
+	m := NewManager(&recordEmitter{}, nil, Options{ChunkSize: 1, MaxInFlight: 1, AckTimeout: 5 * time.Second})
+	s := m.Open("test:chunk", nil)
+	defer s.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WaitReady(context.Background())
+	}()
+	select {
+	case <-done:
+		t.Fatal("WaitReady should block until the consumer acks")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 确认序号 0 表示消费端已就绪
+	if err := m.Ack(s.ID(), 0); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WaitReady() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitReady should return after ack")
+	}
+}
+
+func TestStream_WaitReadyTimesOut(t *testing.T) {
+	m := NewManager(&recordEmitter{}, nil, Options{ChunkSize: 1, MaxInFlight: 1, AckTimeout: 20 * time.Millisecond})
+	s := m.Open("test:chunk", nil)
+	defer s.Close()
+
+	if err := s.WaitReady(context.Background()); err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
+	if err := s.Write(context.Background(), []byte("abc"), nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	s.Close()
+	if err := s.WaitReady(context.Background()); err != ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed after Close, got %v", err)
+	}
+}
+
+func TestManager_AckUnknownStream(t *testing.T) {
+	m := NewManager(nil, nil, DefaultOptions())
+	if err := m.Ack("missing", 1); err == nil {
+		t.Fatal("expected error for unknown stream")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventstream
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+)
+
+// ErrStreamClosed 表示传输流已关闭或已取消。
+var ErrStreamClosed = errors.New("传输流已关闭")
+
+// Stream 单个分块传输流。
+// 消费端通过累计确认（Ack）推进窗口；在收到首个确认前视为不支持流控，
+// 直接发送不等待，以兼容尚未实现确认逻辑的前端。
+type Stream struct {
+	id    string
+	event string
+	meta  map[string]interface{}
+	m     *Manager
+
+	mu          sync.Mutex
+	notify      chan struct{} // 确认/取消信号（容量 1，合并多次通知）
+	done        chan struct{} // 取消信号，关闭后所有等待立即返回
+	nextSeq     int64         // 下一个分块序号（从 1 开始）
+	ackedSeq    int64         // 已累计确认的最大序号
+	sentBytes   int64         // 已发送原始字节数
+	flowControl bool          // 是否已收到过确认（消费端支持流控）
+	degraded    bool          // 是否因确认超时降级
+	cancelled   bool          // 是否已取消
+	closed      bool          // 是否已关闭
+	createdAt   time.Time     // 创建时间
+	lastAckAt   time.Time     // 最近确认时间
+	waitCount   int64         // 窗口等待次数
+	waitTotal   time.Duration // 累计等待时长
+}
+
+// newStream 创建传输流。
+func newStream(m *Manager, id, event string, meta map[string]interface{}) *Stream {
+	return &Stream{
+		id:        id,
+		event:     event,
+		meta:      meta,
+		m:         m,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		nextSeq:   1,
+		createdAt: time.Now(),
+	}
+}
+
+// ID 返回传输流 ID。
+func (s *Stream) ID() string {
+	return s.id
+}
+
+// Write 将数据切分为分块并逐个发送；窗口已满时阻塞等待确认。
+// extra 会与打开时的 meta 一起合并进本次写入的每个分块负载。
+func (s *Stream) Write(ctx context.Context, data []byte, extra map[string]interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	chunkSize := s.m.opts.ChunkSize
+	for offset := 0; offset < len(data) || (offset == 0 && len(data) == 0); {
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := s.waitWindow(ctx); err != nil {
+			return err
+		}
+		s.emitChunk(data[offset:end], extra)
+		if end == len(data) {
+			break
+		}
+		offset = end
+	}
+	return nil
+}
+
+// WaitReady 等待消费端发出首个确认（确认序号 0 表示已就绪、尚未收到分块）。
+// 用于流 ID 随调用返回值下发的场景，避免分块先于返回值到达；超时后返回 nil，按无流控继续发送。
+func (s *Stream) WaitReady(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(s.m.opts.AckTimeout)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		ready, cancelled := s.flowControl, s.cancelled || s.closed
+		s.mu.Unlock()
+		if cancelled {
+			return ErrStreamClosed
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.done:
+			return ErrStreamClosed
+		case <-s.notify:
+		case <-timer.C:
+			s.m.logger.Warn("等待消费端就绪超时，按无流控发送", "streamId", s.id, "event", s.event)
+			return nil
+		}
+	}
+}
+
+// Close 关闭传输流并发送结束事件，重复调用无副作用。
+func (s *Stream) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	end := types.TransferEndEvent{
+		StreamID:    s.id,
+		Event:       s.event,
+		TotalChunks: s.nextSeq - 1,
+		TotalBytes:  s.sentBytes,
+		Cancelled:   s.cancelled,
+	}
+	s.mu.Unlock()
+
+	s.m.remove(s, end)
+}
+
+// waitWindow 在窗口已满时等待确认；超时后降级为无流控，避免前端异常导致写入方永久阻塞。
+func (s *Stream) waitWindow(ctx context.Context) error {
+	var started time.Time
+	for {
+		s.mu.Lock()
+		if s.cancelled || s.closed {
+			s.mu.Unlock()
+			return ErrStreamClosed
+		}
+		inFlight := s.nextSeq - 1 - s.ackedSeq
+		if !s.flowControl || s.degraded || inFlight < int64(s.m.opts.MaxInFlight) {
+			if !started.IsZero() {
+				s.waitTotal += time.Since(started)
+			}
+			s.mu.Unlock()
+			return nil
+		}
+		if started.IsZero() {
+			started = time.Now()
+			s.waitCount++
+		}
+		s.mu.Unlock()
+
+		timer := time.NewTimer(s.m.opts.AckTimeout)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-s.done:
+			timer.Stop()
+			return ErrStreamClosed
+		case <-s.notify:
+			timer.Stop()
+		case <-timer.C:
+			s.mu.Lock()
+			s.degraded = true
+			s.waitTotal += time.Since(started)
+			s.mu.Unlock()
+			s.m.logger.Warn("等待分块确认超时，传输流降级为无流控", "streamId", s.id, "event", s.event, "inFlight", inFlight)
+			return nil
+		}
+	}
+}
+
+// emitChunk 发送单个分块事件。
+func (s *Stream) emitChunk(chunk []byte, extra map[string]interface{}) {
+	s.mu.Lock()
+	seq := s.nextSeq
+	s.nextSeq++
+	s.sentBytes += int64(len(chunk))
+	s.mu.Unlock()
+
+	if s.m.emitter == nil {
+		return
+	}
+
+	payload := make(map[string]interface{}, len(s.meta)+len(extra)+3)
+	for k, v := range s.meta {
+		payload[k] = v
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	payload["streamId"] = s.id
+	payload["seq"] = seq
+	payload["data"] = base64.StdEncoding.EncodeToString(chunk)
+	s.m.emitter.Emit(s.event, payload)
+}
+
+// ack 处理累计确认并唤醒等待中的写入方。
+func (s *Stream) ack(seq int64) error {
+	s.mu.Lock()
+	if seq >= s.nextSeq {
+		s.mu.Unlock()
+		return errors.New("确认序号超出已发送范围")
+	}
+	if seq > s.ackedSeq {
+		s.ackedSeq = seq
+	}
+	s.flowControl = true
+	s.degraded = false
+	s.lastAckAt = time.Now()
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// cancel 取消传输流，唤醒所有等待。
+func (s *Stream) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelled {
+		return
+	}
+	s.cancelled = true
+	close(s.done)
+}
+
+// stat 返回传输流状态快照。
+func (s *Stream) stat() *types.TransferStreamStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat := &types.TransferStreamStat{
+		StreamID:    s.id,
+		Event:       s.event,
+		SentChunks:  s.nextSeq - 1,
+		AckedChunks: s.ackedSeq,
+		InFlight:    s.nextSeq - 1 - s.ackedSeq,
+		SentBytes:   s.sentBytes,
+		FlowControl: s.flowControl,
+		Degraded:    s.degraded,
+		CreatedAtMs: s.createdAt.UnixMilli(),
+		WaitCount:   s.waitCount,
+		WaitTotalMs: s.waitTotal.Milliseconds(),
+	}
+	if !s.lastAckAt.IsZero() {
+		stat.LastAckAtMs = s.lastAckAt.UnixMilli()
+	}
+	return stat
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package execqueue 限制每个连接同时执行的语句数，超出的请求按到达顺序排队，
+// 排队等待受各自的上下文约束，并通过监听者报告排队位置，避免一次操作触发的大量元数据查询耗尽服务端连接。
+package execqueue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLimit 是每个连接默认允许同时执行的语句数。
+	DefaultLimit = 4
+	// MaxLimit 是每个连接允许配置的最大并发数。
+	MaxLimit = 64
+)
+
+// 排队状态。
+const (
+	StateQueued   = "queued"   // 正在排队，Position 为前面等待的请求数加一
+	StateStarted  = "started"  // 已获得执行槽
+	StateCanceled = "canceled" // 排队期间超时或被取消
+)
+
+// Event 是一个请求的排队状态变化。
+type Event struct {
+	Key      string    `json:"key"`      // 连接标识
+	Ticket   uint64    `json:"ticket"`   // 请求编号，在进程内唯一
+	Label    string    `json:"label"`    // 请求说明，如方法名
+	State    string    `json:"state"`    // queued / started / canceled
+	Position int       `json:"position"` // 排队位置（从 1 开始），started/canceled 时为 0
+	Running  int       `json:"running"`  // 该连接正在执行的请求数
+	Waiting  int       `json:"waiting"`  // 该连接正在排队的请求数
+	At       time.Time `json:"at"`
+}
+
+// Stats 是一个连接队列的当前状态。
+type Stats struct {
+	Key     string `json:"key"`
+	Running int    `json:"running"`
+	Waiting int    `json:"waiting"`
+}
+
+// Listener 接收排队状态变化，在调用方协程中同步调用，不应阻塞。
+type Listener func(Event)
+
+// waiter 是一个排队中的请求。
+type waiter struct {
+	ticket uint64
+	label  string
+	ready  chan struct{} // 获得执行槽时关闭
+}
+
+// queue 是单个连接的执行槽与等待队列。
+type queue struct {
+	running int
+	waiting []*waiter
+}
+
+// Scheduler 按连接标识分配执行槽，可并发使用；零值不可用，请使用 New 创建。
+type Scheduler struct {
+	mu       sync.Mutex
+	limit    int
+	queues   map[string]*queue
+	listener Listener
+	next     uint64
+	now      func() time.Time
+}
+
+// Default 是应用共享的调度器，并发上限由应用设置维护。
+var Default = New(DefaultLimit)
+
+// SetLimit 修改共享调度器的每连接并发上限。
+func SetLimit(limit int) {
+	Default.SetLimit(limit)
+}
+
+// New 创建调度器，limit<=0 时使用 DefaultLimit。
+func New(limit int) *Scheduler {
+	return &Scheduler{limit: clampLimit(limit), queues: make(map[string]*queue), now: time.Now}
+}
+
+// clampLimit 将并发数限制在 [1, MaxLimit]，非正数使用默认值。
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// SetListener 设置排队状态变化的回调，nil 表示不再通知。
+func (s *Scheduler) SetListener(fn Listener) {
+	s.mu.Lock()
+	s.listener = fn
+	s.mu.Unlock()
+}
+
+// Limit 返回每个连接的并发上限。
+func (s *Scheduler) Limit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit
+}
+
+// SetLimit 修改每个连接的并发上限；调大时立即唤醒排队的请求，调小时已在执行的请求不受影响。
+func (s *Scheduler) SetLimit(limit int) {
+	s.mu.Lock()
+	s.limit = clampLimit(limit)
+	var events []Event
+	for key, q := range s.queues {
+		events = append(events, s.dispatchLocked(key, q)...)
+	}
+	listener := s.listener
+	s.mu.Unlock()
+	emit(listener, events)
+}
+
+// Acquire 为 key 对应的连接获取一个执行槽，槽位已满时排队等待直到轮到或 ctx 结束。
+// 成功时返回的 release 必须且只需调用一次；ctx 结束时返回 ctx.Err()。
+func (s *Scheduler) Acquire(ctx context.Context, key, label string) (release func(), err error) {
+	s.mu.Lock()
+	s.next++
+	w := &waiter{ticket: s.next, label: label, ready: make(chan struct{})}
+	q := s.queues[key]
+	if q == nil {
+		q = &queue{}
+		s.queues[key] = q
+	}
+	listener := s.listener
+
+	if len(q.waiting) == 0 && q.running < s.limit {
+		q.running++
+		ev := s.eventLocked(key, q, w, StateStarted, 0)
+		s.mu.Unlock()
+		emit(listener, []Event{ev})
+		return s.releaser(key), nil
+	}
+
+	q.waiting = append(q.waiting, w)
+	ev := s.eventLocked(key, q, w, StateQueued, len(q.waiting))
+	s.mu.Unlock()
+	emit(listener, []Event{ev})
+
+	select {
+	case <-w.ready:
+		return s.releaser(key), nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	select {
+	case <-w.ready:
+		// 取消与分配同时发生时以分配为准，归还执行槽后再返回错误
+		s.mu.Unlock()
+		s.releaser(key)()
+		return nil, ctx.Err()
+	default:
+	}
+	var events []Event
+	for i, cand := range q.waiting {
+		if cand == w {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			events = append(events, s.eventLocked(key, q, w, StateCanceled, 0))
+			// 后面的请求位置前移
+			for j := i; j < len(q.waiting); j++ {
+				events = append(events, s.eventLocked(key, q, q.waiting[j], StateQueued, j+1))
+			}
+			break
+		}
+	}
+	s.dropIfIdleLocked(key, q)
+	listener = s.listener
+	s.mu.Unlock()
+	emit(listener, events)
+	return nil, ctx.Err()
+}
+
+// releaser 返回归还 key 执行槽的函数，重复调用只生效一次。
+func (s *Scheduler) releaser(key string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			q := s.queues[key]
+			if q == nil {
+				s.mu.Unlock()
+				return
+			}
+			q.running--
+			events := s.dispatchLocked(key, q)
+			s.dropIfIdleLocked(key, q)
+			listener := s.listener
+			s.mu.Unlock()
+			emit(listener, events)
+		})
+	}
+}
+
+// dispatchLocked 在有空闲槽时按顺序唤醒排队的请求，返回需要通知的事件；调用方持有 s.mu。
+func (s *Scheduler) dispatchLocked(key string, q *queue) []Event {
+	var events []Event
+	started := 0
+	for len(q.waiting) > 0 && q.running < s.limit {
+		w := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		q.running++
+		close(w.ready)
+		events = append(events, s.eventLocked(key, q, w, StateStarted, 0))
+		started++
+	}
+	if started > 0 {
+		for i, w := range q.waiting {
+			events = append(events, s.eventLocked(key, q, w, StateQueued, i+1))
+		}
+	}
+	return events
+}
+
+// dropIfIdleLocked 删除已无执行与排队请求的连接队列；调用方持有 s.mu。
+func (s *Scheduler) dropIfIdleLocked(key string, q *queue) {
+	if q.running == 0 && len(q.waiting) == 0 {
+		delete(s.queues, key)
+	}
+}
+
+// eventLocked 构造事件；调用方持有 s.mu。
+func (s *Scheduler) eventLocked(key string, q *queue, w *waiter, state string, position int) Event {
+	return Event{
+		Key:      key,
+		Ticket:   w.ticket,
+		Label:    w.label,
+		State:    state,
+		Position: position,
+		Running:  q.running,
+		Waiting:  len(q.waiting),
+		At:       s.now(),
+	}
+}
+
+// Stats 返回有请求执行或排队的连接队列状态，按 key 排序。
+func (s *Scheduler) Stats() []Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Stats, 0, len(s.queues))
+	for key, q := range s.queues {
+		out = append(out, Stats{Key: key, Running: q.running, Waiting: len(q.waiting)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// emit 在锁外依次通知监听者。
+func emit(listener Listener, events []Event) {
+	if listener == nil {
+		return
+	}
+	for _, ev := range events {
+		listener(ev)
+	}
+}
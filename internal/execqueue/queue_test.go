@@ -0,0 +1,134 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireQueuesBeyondLimit(t *testing.T) {
+	s := New(2)
+	var mu sync.Mutex
+	var events []Event
+	s.SetListener(func(ev Event) {
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+	})
+
+	r1, _ := s.Acquire(context.Background(), "a", "q1")
+	r2, _ := s.Acquire(context.Background(), "a", "q2")
+	// 其他连接不受影响
+	rb, err := s.Acquire(context.Background(), "b", "q")
+	if err != nil {
+		t.Fatalf("other key: %v", err)
+	}
+	rb()
+
+	got := make(chan func(), 1)
+	go func() {
+		r, err := s.Acquire(context.Background(), "a", "q3")
+		if err != nil {
+			t.Errorf("q3: %v", err)
+		}
+		got <- r
+	}()
+
+	waitFor(t, func() bool { st := s.Stats(); return len(st) == 1 && st[0].Waiting == 1 })
+	select {
+	case <-got:
+		t.Fatal("超过并发上限的请求不应立即执行")
+	default:
+	}
+
+	r1()
+	r1() // 重复调用无副作用
+	r3 := <-got
+	if st := s.Stats(); st[0].Running != 2 || st[0].Waiting != 0 {
+		t.Fatalf("stats = %+v", st)
+	}
+	r2()
+	r3()
+	if st := s.Stats(); len(st) != 0 {
+		t.Fatalf("空闲队列应被删除: %+v", st)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var queued bool
+	for _, ev := range events {
+		if ev.Label == "q3" && ev.State == StateQueued && ev.Position == 1 {
+			queued = true
+		}
+	}
+	if !queued {
+		t.Fatalf("缺少排队事件: %+v", events)
+	}
+}
+
+func TestAcquireTimeoutWhileQueued(t *testing.T) {
+	s := New(1)
+	release, _ := s.Acquire(context.Background(), "a", "busy")
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, "a", "late"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if st := s.Stats(); st[0].Waiting != 0 {
+		t.Fatalf("超时请求应移出队列: %+v", st)
+	}
+}
+
+func TestSetLimitWakesWaiters(t *testing.T) {
+	s := New(1)
+	release, _ := s.Acquire(context.Background(), "a", "busy")
+	defer release()
+
+	done := make(chan struct{})
+	go func() {
+		r, err := s.Acquire(context.Background(), "a", "next")
+		if err == nil {
+			r()
+		}
+		close(done)
+	}()
+	waitFor(t, func() bool { st := s.Stats(); return len(st) == 1 && st[0].Waiting == 1 })
+	s.SetLimit(2)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("调大并发上限后排队请求应被唤醒")
+	}
+	if s.Limit() != 2 {
+		t.Fatalf("Limit() = %d", s.Limit())
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("等待条件超时")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
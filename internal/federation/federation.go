@@ -0,0 +1,135 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package federation 实现轻量的跨连接联邦查询：将各连接的查询结果载入内嵌的 SQLite 引擎，
+// 在本地执行关联查询，并以行数与单元格数上限防止一次载入过多数据。
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataimport"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/scratch"
+)
+
+// 规模上限
+const (
+	MaxSources           = 8         // 单次查询的输入数上限
+	DefaultMaxRows       = 10000     // 每个输入默认读取的最大行数
+	MaxRowsLimit         = 100000    // 每个输入可设置的最大行数
+	DefaultMaxResultRows = 1000      // 默认返回的最大行数
+	MaxResultRowsLimit   = 10000     // 可设置的最大返回行数
+	MaxTotalCells        = 5_000_000 // 全部输入载入本地引擎的单元格总数上限
+)
+
+// EngineSQLite 是内嵌引擎名称
+const EngineSQLite = "sqlite"
+
+// ErrTooLarge 表示输入结果集超出规模上限。
+var ErrTooLarge = errors.New("联邦查询的输入超出规模上限")
+
+// Input 是一个已读取的输入结果集。
+type Input struct {
+	Alias   string
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// Engine 是执行本地关联查询的内嵌引擎。
+type Engine interface {
+	scratch.Execer
+	QueryContext(ctx context.Context, query string, args ...any) ([]map[string]interface{}, []string, error)
+}
+
+// Result 是联邦查询的结果。
+type Result struct {
+	Engine    string                   `json:"engine"`    // 使用的内嵌引擎
+	Columns   []string                 `json:"columns"`   // 结果列
+	Rows      []map[string]interface{} `json:"rows"`      // 结果行
+	Truncated bool                     `json:"truncated"` // 结果超过返回上限被截断
+	InputRows map[string]int           `json:"inputRows"` // 各输入载入的行数
+}
+
+// CheckSize 校验输入的单元格总数不超过 MaxTotalCells。
+func CheckSize(inputs []*Input) error {
+	var cells int
+	for _, in := range inputs {
+		cells += len(in.Rows) * len(in.Columns)
+	}
+	if cells > MaxTotalCells {
+		return fmt.Errorf("%w：共 %d 个单元格，上限 %d，请在来源查询中过滤或聚合", ErrTooLarge, cells, MaxTotalCells)
+	}
+	return nil
+}
+
+// Run 将各输入以 Alias 为表名载入 engine 后执行 query，最多返回 maxResultRows 行。
+func Run(ctx context.Context, engine Engine, engineName string, inputs []*Input, query string, maxResultRows int) (*Result, error) {
+	if err := CheckSize(inputs); err != nil {
+		return nil, err
+	}
+	if maxResultRows <= 0 {
+		maxResultRows = DefaultMaxResultRows
+	}
+	result := &Result{Engine: engineName, InputRows: make(map[string]int, len(inputs))}
+	for _, in := range inputs {
+		table := &dataimport.Table{Headers: in.Columns, Rows: in.Rows}
+		if _, err := scratch.LoadTable(ctx, engine, in.Alias, table, true); err != nil {
+			return nil, fmt.Errorf("载入 %s 失败：%w", in.Alias, err)
+		}
+		result.InputRows[in.Alias] = len(in.Rows)
+	}
+
+	// 多取一行用于判断是否截断
+	wrapped := fmt.Sprintf("SELECT * FROM (%s) AS federated_result LIMIT %d", strings.TrimRight(strings.TrimSpace(query), "; \t\n"), maxResultRows+1)
+	rows, columns, err := engine.QueryContext(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("执行关联查询失败：%w", err)
+	}
+	if len(rows) > maxResultRows {
+		rows, result.Truncated = rows[:maxResultRows], true
+	}
+	result.Columns, result.Rows = columns, rows
+	return result, nil
+}
+
+// OpenEngine 打开一个临时的内嵌引擎（临时 SQLite 文件）。
+// 返回的 cleanup 关闭引擎并删除临时文件。
+func OpenEngine() (Engine, string, func(), error) {
+	lite := db.NewSQLiteDB()
+	f, err := os.CreateTemp("", "boxify-federation-*.db")
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("创建临时数据库失败：%w", err)
+	}
+	path := f.Name()
+	_ = f.Close()
+	remove := func() {
+		for _, p := range []string{path, path + "-journal", path + "-wal", path + "-shm"} {
+			_ = os.Remove(p)
+		}
+	}
+	if err := lite.Connect(&connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: path}); err != nil {
+		remove()
+		return nil, "", nil, fmt.Errorf("打开临时 SQLite 数据库失败：%w", err)
+	}
+	return lite, EngineSQLite, func() {
+		_ = lite.Close()
+		remove()
+	}, nil
+}
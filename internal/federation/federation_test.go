@@ -0,0 +1,107 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeEngine 记录执行的语句，查询时返回预设的行。
+type fakeEngine struct {
+	stmts []string
+	query string
+	rows  []map[string]interface{}
+}
+
+func (f *fakeEngine) ExecContext(ctx context.Context, query string, args ...any) (int64, error) {
+	f.stmts = append(f.stmts, query)
+	return 0, nil
+}
+
+func (f *fakeEngine) QueryContext(ctx context.Context, query string, args ...any) ([]map[string]interface{}, []string, error) {
+	f.query = query
+	return f.rows, []string{"id"}, nil
+}
+
+func TestRunLoadsInputsAndTruncates(t *testing.T) {
+	engine := &fakeEngine{rows: []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}}}
+	inputs := []*Input{
+		{Alias: "prod", Columns: []string{"id", "name"}, Rows: []map[string]interface{}{{"id": int64(1), "name": "a"}}},
+		{Alias: "stage", Columns: []string{"id"}, Rows: []map[string]interface{}{{"id": "1"}, {"id": "2"}}},
+	}
+	result, err := Run(context.Background(), engine, EngineSQLite, inputs, "SELECT p.id FROM prod p JOIN stage s ON s.id = p.id;", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := strings.Join(engine.stmts, "\n")
+	if !strings.Contains(all, `CREATE TABLE "prod" ("id" INTEGER, "name" TEXT)`) || !strings.Contains(all, `CREATE TABLE "stage" ("id" INTEGER)`) {
+		t.Errorf("应以别名建表: %v", engine.stmts)
+	}
+	if engine.query != "SELECT * FROM (SELECT p.id FROM prod p JOIN stage s ON s.id = p.id) AS federated_result LIMIT 3" {
+		t.Errorf("关联查询包装不符: %s", engine.query)
+	}
+	if !result.Truncated || len(result.Rows) != 2 || result.InputRows["stage"] != 2 {
+		t.Errorf("结果应截断到 2 行: %+v", result)
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	wide := &Input{Alias: "a", Columns: make([]string, 1000), Rows: make([]map[string]interface{}, MaxTotalCells/1000+1)}
+	if err := CheckSize([]*Input{wide}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("超出单元格上限应返回 ErrTooLarge: %v", err)
+	}
+	if _, err := Run(context.Background(), &fakeEngine{}, EngineSQLite, []*Input{wide}, "SELECT 1", 0); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Run 应在载入前检查规模: %v", err)
+	}
+}
+
+func TestOpenEngineJoinsInputs(t *testing.T) {
+	// 临时数据库写入独立目录，便于检查 cleanup
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("TMP", tmp)
+
+	engine, name, cleanup, err := OpenEngine()
+	if err != nil {
+		t.Fatalf("OpenEngine() error = %v", err)
+	}
+	if name != EngineSQLite {
+		t.Fatalf("引擎 = %s, want %s", name, EngineSQLite)
+	}
+	inputs := []*Input{
+		{Alias: "prod", Columns: []string{"id", "name"}, Rows: []map[string]interface{}{{"id": int64(1), "name": "a"}, {"id": int64(2), "name": "b"}}},
+		{Alias: "stage", Columns: []string{"id", "name"}, Rows: []map[string]interface{}{{"id": "2", "name": "b2"}, {"id": "3", "name": "c"}}},
+	}
+	result, err := Run(context.Background(), engine, name, inputs, "SELECT p.id, p.name, s.name AS stage_name FROM prod p JOIN stage s ON s.id = p.id", 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Rows) != 1 || fmt.Sprint(result.Rows[0]["id"]) != "2" || fmt.Sprint(result.Rows[0]["stage_name"]) != "b2" {
+		t.Errorf("关联结果不符: %v", result.Rows)
+	}
+
+	if files, _ := os.ReadDir(tmp); len(files) == 0 {
+		t.Fatal("应在临时目录创建数据库文件")
+	}
+	cleanup()
+	if files, _ := os.ReadDir(tmp); len(files) != 0 {
+		t.Errorf("cleanup 应删除临时数据库文件，剩余 %d 个", len(files))
+	}
+}
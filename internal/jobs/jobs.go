@@ -0,0 +1,323 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jobs 以后台任务的形式执行耗时操作（导出、导入、结构扫描等）。
+//
+// 每个任务有唯一 ID，执行函数通过 Reporter 汇报进度；进度、完成、失败与取消以 Event 通知监听者，
+// 调用方可随时取消任务。已结束的任务在内存中保留最近若干条，供前端查看。
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMaxRecent 默认保留的已结束任务条数。
+	DefaultMaxRecent = 50
+	// DefaultProgressInterval 默认的进度事件最小间隔，避免高频汇报刷屏。
+	DefaultProgressInterval = 200 * time.Millisecond
+)
+
+// ErrJobNotFound 任务不存在或已被清理。
+var ErrJobNotFound = errors.New("任务不存在或已被清理")
+
+// Status 是任务状态。
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Job 是任务状态快照。
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`                 // 任务类型，如 export、import、schema-scan
+	Title      string     `json:"title"`                // 展示用标题
+	Status     Status     `json:"status"`               // 任务状态
+	Done       int64      `json:"done"`                 // 已完成的工作量
+	Total      int64      `json:"total"`                // 总工作量，0 表示未知
+	Message    string     `json:"message,omitempty"`    // 最近一次进度说明
+	Error      string     `json:"error,omitempty"`      // 失败原因
+	Result     any        `json:"result,omitempty"`     // 成功时的结果
+	StartedAt  time.Time  `json:"startedAt"`            // 开始时间
+	FinishedAt *time.Time `json:"finishedAt,omitempty"` // 结束时间，执行中为空
+}
+
+// Reporter 供任务函数汇报进度。
+type Reporter interface {
+	// Report 更新进度；total 为 0 表示总量未知。
+	Report(done, total int64, message string)
+}
+
+// Func 是任务函数，应在 ctx 取消后尽快返回。
+type Func func(ctx context.Context, r Reporter) (any, error)
+
+// EventKind 是任务事件类型。
+type EventKind string
+
+const (
+	EventProgress  EventKind = "progress"
+	EventCompleted EventKind = "completed"
+	EventFailed    EventKind = "failed"
+	EventCancelled EventKind = "cancelled"
+)
+
+// Event 是任务状态变化通知。
+type Event struct {
+	Kind EventKind `json:"kind"`
+	Job  Job       `json:"job"`
+}
+
+// Listener 接收任务事件，在任务所在的 goroutine 中同步调用。
+type Listener func(Event)
+
+// Options 任务管理参数。
+type Options struct {
+	MaxRecent        int           // 保留的已结束任务条数
+	ProgressInterval time.Duration // 进度事件最小间隔
+}
+
+// DefaultOptions 返回默认任务管理参数。
+func DefaultOptions() Options {
+	return Options{MaxRecent: DefaultMaxRecent, ProgressInterval: DefaultProgressInterval}
+}
+
+// entry 是一个任务的内部状态。
+type entry struct {
+	job          Job
+	cancel       context.CancelFunc
+	lastProgress time.Time
+}
+
+// Manager 管理后台任务，可并发使用。
+type Manager struct {
+	mu       sync.Mutex
+	logger   *slog.Logger
+	opts     Options
+	entries  map[string]*entry
+	listener Listener
+	wg       sync.WaitGroup
+	now      func() time.Time
+}
+
+// NewManager 创建任务管理器，参数非法时回退默认值。
+func NewManager(logger *slog.Logger, opts Options) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if opts.MaxRecent <= 0 {
+		opts.MaxRecent = DefaultMaxRecent
+	}
+	if opts.ProgressInterval < 0 {
+		opts.ProgressInterval = DefaultProgressInterval
+	}
+	return &Manager{
+		logger:  logger.With("module", "jobs"),
+		opts:    opts,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// SetListener 设置任务事件监听者，传入 nil 取消监听。
+func (m *Manager) SetListener(l Listener) {
+	m.mu.Lock()
+	m.listener = l
+	m.mu.Unlock()
+}
+
+// Submit 在后台启动任务并返回初始快照。
+func (m *Manager) Submit(kind, title string, fn Func) Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &entry{
+		job: Job{
+			ID:        uuid.New().String(),
+			Kind:      kind,
+			Title:     title,
+			Status:    StatusRunning,
+			StartedAt: m.now(),
+		},
+		cancel: cancel,
+	}
+
+	m.mu.Lock()
+	m.entries[e.job.ID] = e
+	snapshot := e.job
+	m.mu.Unlock()
+
+	m.logger.Info("任务开始", "jobId", snapshot.ID, "kind", kind, "title", title)
+	m.wg.Add(1)
+	go m.run(ctx, e, fn)
+	return snapshot
+}
+
+// Get 返回任务快照。
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return e.job, nil
+}
+
+// List 返回全部任务快照，执行中的任务在前，其余按开始时间倒序。
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	jobs := make([]Job, 0, len(m.entries))
+	for _, e := range m.entries {
+		jobs = append(jobs, e.job)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		ri, rj := jobs[i].Status == StatusRunning, jobs[j].Status == StatusRunning
+		if ri != rj {
+			return ri
+		}
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
+
+// Cancel 请求取消执行中的任务，任务函数返回后状态变为 cancelled。
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if e.job.Status != StatusRunning {
+		return fmt.Errorf("任务已结束，状态为 %s", e.job.Status)
+	}
+	e.cancel()
+	return nil
+}
+
+// Shutdown 取消所有执行中的任务并等待其返回。
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	for _, e := range m.entries {
+		if e.job.Status == StatusRunning {
+			e.cancel()
+		}
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
+
+// run 执行任务函数并记录结果。
+func (m *Manager) run(ctx context.Context, e *entry, fn Func) {
+	defer m.wg.Done()
+	defer e.cancel()
+
+	result, err := m.call(ctx, e, fn)
+
+	m.mu.Lock()
+	finished := m.now()
+	e.job.FinishedAt = &finished
+	kind := EventCompleted
+	switch {
+	case err != nil && ctx.Err() != nil:
+		e.job.Status, e.job.Error, kind = StatusCancelled, "任务已取消", EventCancelled
+	case err != nil:
+		e.job.Status, e.job.Error, kind = StatusFailed, err.Error(), EventFailed
+	default:
+		e.job.Status, e.job.Result = StatusSucceeded, result
+		if e.job.Total > 0 {
+			e.job.Done = e.job.Total
+		}
+	}
+	snapshot := e.job
+	listener := m.listener
+	m.pruneLocked()
+	m.mu.Unlock()
+
+	m.logger.Info("任务结束", "jobId", snapshot.ID, "kind", snapshot.Kind, "status", snapshot.Status,
+		"durationMs", finished.Sub(snapshot.StartedAt).Milliseconds(), "error", snapshot.Error)
+	if listener != nil {
+		listener(Event{Kind: kind, Job: snapshot})
+	}
+}
+
+// call 调用任务函数，将 panic 转为错误，避免拖垮整个应用。
+func (m *Manager) call(ctx context.Context, e *entry, fn Func) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("任务异常", "jobId", e.job.ID, "panic", r)
+			err = fmt.Errorf("任务异常: %v", r)
+		}
+	}()
+	return fn(ctx, &reporter{m: m, e: e})
+}
+
+// pruneLocked 清理超出保留条数的已结束任务（最早结束的优先），调用方需持有锁。
+func (m *Manager) pruneLocked() {
+	var finished []*entry
+	for _, e := range m.entries {
+		if e.job.FinishedAt != nil {
+			finished = append(finished, e)
+		}
+	}
+	if len(finished) <= m.opts.MaxRecent {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].job.FinishedAt.Before(*finished[j].job.FinishedAt)
+	})
+	for _, e := range finished[:len(finished)-m.opts.MaxRecent] {
+		delete(m.entries, e.job.ID)
+	}
+}
+
+// reporter 将进度写回任务状态，并按间隔发送进度事件。
+type reporter struct {
+	m *Manager
+	e *entry
+}
+
+// Report 更新进度；达到总量的汇报总会发送事件。
+func (r *reporter) Report(done, total int64, message string) {
+	r.m.mu.Lock()
+	if r.e.job.Status != StatusRunning {
+		r.m.mu.Unlock()
+		return
+	}
+	r.e.job.Done, r.e.job.Total, r.e.job.Message = done, total, message
+	now := r.m.now()
+	emit := now.Sub(r.e.lastProgress) >= r.m.opts.ProgressInterval || (total > 0 && done >= total)
+	if emit {
+		r.e.lastProgress = now
+	}
+	snapshot := r.e.job
+	listener := r.m.listener
+	r.m.mu.Unlock()
+
+	if emit && listener != nil {
+		listener(Event{Kind: EventProgress, Job: snapshot})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder 记录收到的任务事件。
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) listen(e Event) {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+}
+
+func (r *recorder) kinds() []EventKind {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kinds := make([]EventKind, len(r.events))
+	for i, e := range r.events {
+		kinds[i] = e.Kind
+	}
+	return kinds
+}
+
+func TestSubmitCompletesWithProgress(t *testing.T) {
+	m := NewManager(nil, Options{ProgressInterval: 0})
+	rec := &recorder{}
+	m.SetListener(rec.listen)
+
+	job := m.Submit("export", "导出 users", func(ctx context.Context, r Reporter) (any, error) {
+		r.Report(1, 2, "第 1 批")
+		r.Report(2, 2, "第 2 批")
+		return "ok", nil
+	})
+	if job.Status != StatusRunning || job.ID == "" {
+		t.Fatalf("初始快照错误: %+v", job)
+	}
+	m.wg.Wait()
+
+	got, err := m.Get(job.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != StatusSucceeded || got.Result != "ok" || got.Done != 2 || got.FinishedAt == nil {
+		t.Errorf("完成状态错误: %+v", got)
+	}
+	kinds := rec.kinds()
+	want := []EventKind{EventProgress, EventProgress, EventCompleted}
+	if len(kinds) != len(want) {
+		t.Fatalf("事件序列 = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("事件序列 = %v, want %v", kinds, want)
+		}
+	}
+}
+
+func TestProgressThrottled(t *testing.T) {
+	m := NewManager(nil, Options{ProgressInterval: time.Hour})
+	rec := &recorder{}
+	m.SetListener(rec.listen)
+
+	m.Submit("scan", "扫描", func(ctx context.Context, r Reporter) (any, error) {
+		for i := int64(1); i <= 10; i++ {
+			r.Report(i, 10, "")
+		}
+		return nil, nil
+	})
+	m.wg.Wait()
+
+	// 第一次汇报与达到总量的汇报会发送，其余被节流
+	kinds := rec.kinds()
+	if len(kinds) != 3 || kinds[2] != EventCompleted {
+		t.Errorf("事件序列 = %v", kinds)
+	}
+}
+
+func TestFailureAndPanic(t *testing.T) {
+	m := NewManager(nil, DefaultOptions())
+	failed := m.Submit("import", "导入", func(ctx context.Context, r Reporter) (any, error) {
+		return nil, errors.New("boom")
+	})
+	panicked := m.Submit("import", "导入", func(ctx context.Context, r Reporter) (any, error) {
+		panic("bad")
+	})
+	m.wg.Wait()
+
+	if got, _ := m.Get(failed.ID); got.Status != StatusFailed || got.Error != "boom" {
+		t.Errorf("失败任务状态错误: %+v", got)
+	}
+	if got, _ := m.Get(panicked.ID); got.Status != StatusFailed || got.Error == "" {
+		t.Errorf("panic 任务应标记为失败: %+v", got)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	m := NewManager(nil, DefaultOptions())
+	rec := &recorder{}
+	m.SetListener(rec.listen)
+
+	started := make(chan struct{})
+	job := m.Submit("export", "导出", func(ctx context.Context, r Reporter) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+	if err := m.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	m.Shutdown()
+
+	got, _ := m.Get(job.ID)
+	if got.Status != StatusCancelled {
+		t.Errorf("状态 = %s, want cancelled", got.Status)
+	}
+	if kinds := rec.kinds(); len(kinds) != 1 || kinds[0] != EventCancelled {
+		t.Errorf("事件序列 = %v", kinds)
+	}
+	if err := m.Cancel(job.ID); err == nil {
+		t.Error("已结束的任务不应再次取消")
+	}
+	if err := m.Cancel("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("未知任务应返回 ErrJobNotFound，得到 %v", err)
+	}
+}
+
+func TestListOrderAndPrune(t *testing.T) {
+	m := NewManager(nil, Options{MaxRecent: 2})
+	base := time.Unix(1700000000, 0)
+	var mu sync.Mutex
+	tick := 0
+	m.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		job := m.Submit("scan", "扫描", func(ctx context.Context, r Reporter) (any, error) { return nil, nil })
+		m.wg.Wait()
+		ids = append(ids, job.ID)
+	}
+	release := make(chan struct{})
+	running := m.Submit("scan", "执行中", func(ctx context.Context, r Reporter) (any, error) {
+		<-release
+		return nil, nil
+	})
+
+	jobs := m.List()
+	if len(jobs) != 3 {
+		t.Fatalf("应保留 1 个执行中与 2 个最近结束的任务，得到 %d 个", len(jobs))
+	}
+	if jobs[0].ID != running.ID || jobs[1].ID != ids[2] || jobs[2].ID != ids[1] {
+		t.Errorf("列表顺序错误: %v", []string{jobs[0].ID, jobs[1].ID, jobs[2].ID})
+	}
+	if _, err := m.Get(ids[0]); !errors.Is(err, ErrJobNotFound) {
+		t.Error("最早结束的任务应被清理")
+	}
+	close(release)
+	m.Shutdown()
+}
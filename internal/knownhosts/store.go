@@ -0,0 +1,235 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package knownhosts 校验 SSH 跳板机的主机密钥：首次连接时需用户确认指纹（TOFU），
+// 确认后的密钥保存在用户配置目录下的 JSON 文件中，同时兼容 ~/.ssh/known_hosts 中已有的记录。
+package knownhosts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrHostNotFound 受信任的主机不存在。
+var ErrHostNotFound = errors.New("受信任的主机不存在")
+
+// Entry 是一条受信任的主机密钥。
+type Entry struct {
+	Host        string    `json:"host"`        // 主机地址，host:port
+	KeyType     string    `json:"keyType"`     // 密钥类型，如 ssh-ed25519
+	Fingerprint string    `json:"fingerprint"` // SHA256 指纹
+	PublicKey   string    `json:"publicKey"`   // authorized_keys 格式的公钥
+	AddedAt     time.Time `json:"addedAt"`
+	LastSeen    time.Time `json:"lastSeen"` // 最近一次校验通过的时间
+}
+
+// Status 是主机密钥的校验结果。
+type Status int
+
+const (
+	StatusUnknown Status = iota // 主机没有受信任的密钥
+	StatusTrusted               // 密钥与受信任的记录一致
+	StatusChanged               // 主机有受信任的密钥，但与本次不同
+)
+
+// Store 保存受信任的主机密钥，可并发使用。
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	logger  *slog.Logger
+	entries map[string]*Entry // host + "\x00" + keyType
+	now     func() time.Time
+}
+
+// DefaultPath 返回默认的主机密钥文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "known-hosts.json")
+	}
+	return filepath.Join(configDir, "Boxify", "known-hosts.json")
+}
+
+// NewStore 创建主机密钥存储，path 为空时使用默认路径；需调用 Load 读取已保存的记录。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		path:    path,
+		logger:  logger.With("module", "knownhosts"),
+		entries: make(map[string]*Entry),
+		now:     time.Now,
+	}
+}
+
+// Load 读取主机密钥文件，文件不存在时视为空。
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取受信任主机失败：%w", err)
+	}
+	var list []*Entry
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析受信任主机失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]*Entry, len(list))
+	for _, e := range list {
+		if e != nil && e.Host != "" && e.KeyType != "" {
+			s.entries[entryKey(e.Host, e.KeyType)] = e
+		}
+	}
+	return nil
+}
+
+// List 返回全部受信任的主机密钥，按主机与密钥类型排序。
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Host != list[j].Host {
+			return list[i].Host < list[j].Host
+		}
+		return list[i].KeyType < list[j].KeyType
+	})
+	return list
+}
+
+// Check 校验主机密钥，一致时更新最近校验时间；返回主机已有记录的指纹，供密钥变化时提示。
+func (s *Store) Check(host string, key ssh.PublicKey) (Status, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var known []string
+	for _, e := range s.entries {
+		if e.Host != host {
+			continue
+		}
+		if e.KeyType == key.Type() && e.Fingerprint == ssh.FingerprintSHA256(key) {
+			e.LastSeen = s.now()
+			if err := s.persistLocked(); err != nil {
+				s.logger.Warn("更新主机最近校验时间失败", "host", host, "error", err)
+			}
+			return StatusTrusted, nil
+		}
+		known = append(known, e.Fingerprint)
+	}
+	if len(known) == 0 {
+		return StatusUnknown, nil
+	}
+	sort.Strings(known)
+	return StatusChanged, known
+}
+
+// Trust 信任主机密钥；同一主机的其他密钥被替换，避免旧密钥继续有效。
+func (s *Store) Trust(host string, key ssh.PublicKey) (*Entry, error) {
+	if strings.TrimSpace(host) == "" || key == nil {
+		return nil, errors.New("主机与密钥不能为空")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if e.Host == host {
+			delete(s.entries, k)
+		}
+	}
+	now := s.now()
+	e := &Entry{
+		Host:        host,
+		KeyType:     key.Type(),
+		Fingerprint: ssh.FingerprintSHA256(key),
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))),
+		AddedAt:     now,
+		LastSeen:    now,
+	}
+	s.entries[entryKey(host, e.KeyType)] = e
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	s.logger.Info("已信任主机密钥", "host", host, "fingerprint", e.Fingerprint)
+	c := *e
+	return &c, nil
+}
+
+// Remove 删除主机的受信任密钥，keyType 为空时删除该主机的全部密钥。
+func (s *Store) Remove(host, keyType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for k, e := range s.entries {
+		if e.Host == host && (keyType == "" || e.KeyType == keyType) {
+			delete(s.entries, k)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return ErrHostNotFound
+	}
+	return s.persistLocked()
+}
+
+func entryKey(host, keyType string) string {
+	return host + "\x00" + keyType
+}
+
+// persistLocked 将记录写入文件，先写临时文件再替换，调用方需持有锁。
+func (s *Store) persistLocked() error {
+	list := make([]*Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Host != list[j].Host {
+			return list[i].Host < list[j].Host
+		}
+		return list[i].KeyType < list[j].KeyType
+	})
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化受信任主机失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入受信任主机失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入受信任主机失败：%w", err)
+	}
+	return nil
+}
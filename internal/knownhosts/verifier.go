@@ -0,0 +1,188 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knownhosts
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	xknownhosts "golang.org/x/crypto/ssh/knownhosts"
+)
+
+// pendingTTL 是待确认密钥的保留时间，超时后需重新连接以获取密钥。
+const pendingTTL = 10 * time.Minute
+
+// ErrPromptExpired 待确认的主机密钥不存在或已过期。
+var ErrPromptExpired = errors.New("待确认的主机密钥不存在或已过期，请重新连接")
+
+// Prompt 是需要用户确认的主机密钥。
+type Prompt struct {
+	Host        string   `json:"host"`            // 主机地址，host:port
+	KeyType     string   `json:"keyType"`         // 密钥类型
+	Fingerprint string   `json:"fingerprint"`     // 本次收到的密钥指纹
+	Changed     bool     `json:"changed"`         // 主机已有受信任的其他密钥，可能存在中间人攻击
+	Known       []string `json:"known,omitempty"` // 已受信任的指纹
+}
+
+// HostKeyError 表示主机密钥未受信任，连接已中止；用户确认后需重新连接。
+type HostKeyError struct {
+	Prompt
+}
+
+func (e *HostKeyError) Error() string {
+	if e.Changed {
+		return fmt.Sprintf("SSH 主机 %s 的密钥已变化（%s %s），可能存在中间人攻击，确认无误后才可重新信任", e.Host, e.KeyType, e.Fingerprint)
+	}
+	return fmt.Sprintf("SSH 主机 %s 的密钥尚未受信任（%s %s），确认指纹后重试", e.Host, e.KeyType, e.Fingerprint)
+}
+
+// pendingKey 是等待用户确认的密钥。
+type pendingKey struct {
+	key ssh.PublicKey
+	at  time.Time
+}
+
+// Verifier 按 TOFU 规则校验主机密钥：已信任的密钥直接通过，~/.ssh/known_hosts 中的记录同样认可；
+// 未知或变化的密钥中止连接并通知监听方，用户通过 Accept 确认后重新连接。
+//
+// 不在握手中等待用户确认：握手受连接超时约束，后台重连也无人应答。
+type Verifier struct {
+	store       *Store
+	systemFiles []string
+
+	mu       sync.Mutex
+	pending  map[string]pendingKey // host + "\x00" + fingerprint
+	listener func(Prompt)
+	now      func() time.Time
+}
+
+// DefaultSystemFiles 返回用户的 OpenSSH known_hosts 文件路径。
+func DefaultSystemFiles() []string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return nil
+	}
+	return []string{filepath.Join(home, ".ssh", "known_hosts")}
+}
+
+// NewVerifier 创建校验器，systemFiles 为只读的 OpenSSH known_hosts 文件，不存在的文件被忽略。
+func NewVerifier(store *Store, systemFiles []string) *Verifier {
+	return &Verifier{
+		store:       store,
+		systemFiles: systemFiles,
+		pending:     make(map[string]pendingKey),
+		now:         time.Now,
+	}
+}
+
+// SetPromptListener 设置需要用户确认密钥时的回调，传入 nil 取消。
+func (v *Verifier) SetPromptListener(fn func(Prompt)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.listener = fn
+}
+
+// Verify 校验 host（host:port）的密钥，remote 为实际连接的地址；签名与 ssh.HostKeyCallback 相同。
+func (v *Verifier) Verify(host string, remote net.Addr, key ssh.PublicKey) error {
+	status, known := v.store.Check(host, key)
+	switch status {
+	case StatusTrusted:
+		return nil
+	case StatusUnknown:
+		switch v.checkSystem(host, remote, key) {
+		case StatusTrusted:
+			return nil
+		case StatusChanged:
+			status = StatusChanged
+		}
+	}
+
+	prompt := Prompt{
+		Host:        host,
+		KeyType:     key.Type(),
+		Fingerprint: ssh.FingerprintSHA256(key),
+		Changed:     status == StatusChanged,
+		Known:       known,
+	}
+	v.mu.Lock()
+	v.prunePendingLocked()
+	v.pending[host+"\x00"+prompt.Fingerprint] = pendingKey{key: key, at: v.now()}
+	listener := v.listener
+	v.mu.Unlock()
+	if listener != nil {
+		listener(prompt)
+	}
+	return &HostKeyError{Prompt: prompt}
+}
+
+// Accept 信任最近一次连接时收到的密钥，fingerprint 必须与提示中的指纹一致。
+func (v *Verifier) Accept(host, fingerprint string) (*Entry, error) {
+	v.mu.Lock()
+	v.prunePendingLocked()
+	k := host + "\x00" + fingerprint
+	p, ok := v.pending[k]
+	delete(v.pending, k)
+	v.mu.Unlock()
+	if !ok {
+		return nil, ErrPromptExpired
+	}
+	return v.store.Trust(host, p.key)
+}
+
+// checkSystem 在 OpenSSH known_hosts 中查找主机；文件缺失或无法解析时视为未知。
+func (v *Verifier) checkSystem(host string, remote net.Addr, key ssh.PublicKey) Status {
+	var files []string
+	for _, f := range v.systemFiles {
+		if _, err := os.Stat(f); err == nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return StatusUnknown
+	}
+	cb, err := xknownhosts.New(files...)
+	if err != nil {
+		return StatusUnknown
+	}
+	if remote == nil {
+		remote = &net.TCPAddr{}
+	}
+	err = cb(host, remote, key)
+	if err == nil {
+		return StatusTrusted
+	}
+	var keyErr *xknownhosts.KeyError
+	var revoked *xknownhosts.RevokedError
+	if errors.As(err, &revoked) || (errors.As(err, &keyErr) && len(keyErr.Want) > 0) {
+		return StatusChanged
+	}
+	return StatusUnknown
+}
+
+// prunePendingLocked 清理过期的待确认密钥，调用方需持有锁。
+func (v *Verifier) prunePendingLocked() {
+	now := v.now()
+	for k, p := range v.pending {
+		if now.Sub(p.at) > pendingTTL {
+			delete(v.pending, k)
+		}
+	}
+}
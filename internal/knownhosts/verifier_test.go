@@ -0,0 +1,113 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knownhosts
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	xknownhosts "golang.org/x/crypto/ssh/knownhosts"
+)
+
+func newKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestVerifierTrustOnFirstUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known-hosts.json")
+	v := NewVerifier(NewStore(path, nil), nil)
+	var prompts []Prompt
+	v.SetPromptListener(func(p Prompt) { prompts = append(prompts, p) })
+	key := newKey(t)
+
+	err := v.Verify("bastion:22", nil, key)
+	var hkErr *HostKeyError
+	if !errors.As(err, &hkErr) || hkErr.Changed || hkErr.Fingerprint != ssh.FingerprintSHA256(key) {
+		t.Fatalf("首次连接应要求确认: %v", err)
+	}
+	if len(prompts) != 1 || prompts[0].Host != "bastion:22" {
+		t.Fatalf("应通知前端确认: %+v", prompts)
+	}
+	if _, err := v.Accept("bastion:22", "SHA256:other"); !errors.Is(err, ErrPromptExpired) {
+		t.Fatalf("指纹不一致时不应信任: %v", err)
+	}
+	if _, err := v.Accept("bastion:22", hkErr.Fingerprint); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if err := v.Verify("bastion:22", nil, key); err != nil {
+		t.Fatalf("已信任的密钥应通过: %v", err)
+	}
+
+	// 重新加载后仍受信任，换了密钥则提示变化
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	v2 := NewVerifier(reloaded, nil)
+	if err := v2.Verify("bastion:22", nil, key); err != nil {
+		t.Fatalf("持久化的密钥应通过: %v", err)
+	}
+	other := newKey(t)
+	err = v2.Verify("bastion:22", nil, other)
+	if !errors.As(err, &hkErr) || !hkErr.Changed || len(hkErr.Known) != 1 {
+		t.Fatalf("密钥变化应提示: %v", err)
+	}
+	if _, err := v2.Accept("bastion:22", ssh.FingerprintSHA256(other)); err != nil {
+		t.Fatal(err)
+	}
+	if list := reloaded.List(); len(list) != 1 || list[0].Fingerprint != ssh.FingerprintSHA256(other) {
+		t.Fatalf("重新信任应替换旧密钥: %+v", list)
+	}
+	if err := reloaded.Remove("bastion:22", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Remove("bastion:22", ""); !errors.Is(err, ErrHostNotFound) {
+		t.Fatalf("Remove() error = %v", err)
+	}
+}
+
+func TestVerifierUsesSystemKnownHosts(t *testing.T) {
+	dir := t.TempDir()
+	key := newKey(t)
+	system := filepath.Join(dir, "known_hosts")
+	line := xknownhosts.Line([]string{xknownhosts.Normalize("bastion:2222")}, key)
+	if err := os.WriteFile(system, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(NewStore(filepath.Join(dir, "known-hosts.json"), nil), []string{system, filepath.Join(dir, "missing")})
+	remote := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 2222}
+
+	if err := v.Verify("bastion:2222", remote, key); err != nil {
+		t.Fatalf("known_hosts 中的密钥应通过: %v", err)
+	}
+	var hkErr *HostKeyError
+	if err := v.Verify("bastion:2222", remote, newKey(t)); !errors.As(err, &hkErr) || !hkErr.Changed {
+		t.Fatalf("与 known_hosts 不一致应视为变化: %v", err)
+	}
+}
@@ -28,8 +28,20 @@ import (
 var (
 	logger *slog.Logger
 	mu     sync.RWMutex
+	// level 运行时可调整的日志级别，传给 Init 后由设置服务修改
+	level = new(slog.LevelVar)
 )
 
+// Level 返回运行时可调整的日志级别
+func Level() *slog.LevelVar {
+	return level
+}
+
+// SetLevel 修改日志级别，对使用 Level() 初始化的 logger 立即生效
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
 func Init(level slog.Leveler) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -37,7 +49,10 @@ func Init(level slog.Leveler) {
 		return
 	}
 
-	logger = DefaultLogger(level)
+	out.mu.Lock()
+	out.global = level
+	out.mu.Unlock()
+	logger = slog.New(newFilterHandler(out))
 }
 
 func DefaultLogger(level slog.Leveler) *slog.Logger {
@@ -57,7 +72,7 @@ func GetDefaultLogger() *slog.Logger {
 	mu.RUnlock()
 
 	// 需要初始化
-	Init(slog.LevelInfo)
+	Init(level)
 
 	mu.RLock()
 	defer mu.RUnlock()
@@ -0,0 +1,336 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"github.com/mattn/go-isatty"
+)
+
+// 日志子系统，通过 For 取得的 logger 带 subsystem 属性，可单独设置级别
+const (
+	SubsystemKey      = "subsystem"
+	SubsystemDB       = "db"
+	SubsystemTerminal = "terminal"
+	SubsystemWindow   = "window"
+	SubsystemSync     = "sync"
+)
+
+// minLevel 输出端接受的最低级别，级别过滤统一由 filterHandler 完成
+const minLevel = slog.Level(-8)
+
+// Subsystems 返回可单独设置级别的子系统
+func Subsystems() []string {
+	return []string{SubsystemDB, SubsystemTerminal, SubsystemWindow, SubsystemSync}
+}
+
+// ParseLevel 解析 debug、info、warn、error 形式的日志级别
+func ParseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("未知的日志级别: %s", name)
+	}
+	return l, nil
+}
+
+// LevelName 返回日志级别的小写名称
+func LevelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
+
+// output 保存当前的输出配置：全局与子系统级别、输出格式和日志文件。
+// 配置变化时 gen 递增，已派生的 handler 在下次写日志时按新配置重建。
+type output struct {
+	mu     sync.RWMutex
+	gen    uint64
+	global slog.Leveler
+	levels map[string]slog.Level // 子系统级别覆盖
+	json   bool
+	stderr io.Writer
+	color  bool
+	file   *rotatingFile
+	rec    *recorder
+	sink   slog.Handler
+}
+
+// out 是全局 logger 使用的输出配置
+var out = newOutput(os.Stderr, level, recent)
+
+// newOutput 创建输出配置，输出到 w 并把日志记入 rec
+func newOutput(w io.Writer, global slog.Leveler, rec *recorder) *output {
+	o := &output{global: global, levels: make(map[string]slog.Level), stderr: w, rec: rec}
+	if f, ok := w.(*os.File); ok {
+		o.color = isatty.IsTerminal(f.Fd())
+	}
+	o.rebuildLocked()
+	return o
+}
+
+// rebuildLocked 按当前配置构建输出 handler；调用方需持有写锁
+func (o *output) rebuildLocked() {
+	handlers := []slog.Handler{o.formatHandler(o.stderr, o.color)}
+	if o.file != nil {
+		handlers = append(handlers, o.formatHandler(o.file, false))
+	}
+	var next slog.Handler = fanout(handlers)
+	if len(handlers) == 1 {
+		next = handlers[0]
+	}
+	o.sink = newRecordingHandler(next, o.rec)
+	o.gen++
+}
+
+// formatHandler 按输出格式创建写入 w 的 handler
+func (o *output) formatHandler(w io.Writer, color bool) slog.Handler {
+	if o.json {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: minLevel})
+	}
+	if !color {
+		return slog.NewTextHandler(w, &slog.HandlerOptions{Level: minLevel})
+	}
+	return tint.NewHandler(w, &tint.Options{TimeFormat: time.Kitchen, Level: minLevel})
+}
+
+// current 返回当前输出 handler 与配置代数
+func (o *output) current() (slog.Handler, uint64) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.sink, o.gen
+}
+
+// enabled 判断子系统的日志级别是否输出，子系统未覆盖时使用全局级别
+func (o *output) enabled(subsystem string, l slog.Level) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if min, ok := o.levels[subsystem]; ok && subsystem != "" {
+		return l >= min
+	}
+	return l >= o.global.Level()
+}
+
+// filterHandler 按子系统级别过滤日志，再交给当前输出 handler。
+// WithAttrs/WithGroup 记录为操作序列，输出配置变化后据此重建派生 handler。
+type filterHandler struct {
+	out       *output
+	subsystem string
+	groups    int
+	ops       []func(slog.Handler) slog.Handler
+	cache     *handlerCache
+}
+
+// handlerCache 缓存按当前配置重建的派生 handler
+type handlerCache struct {
+	mu      sync.Mutex
+	gen     uint64
+	handler slog.Handler
+}
+
+// newFilterHandler 创建写入 o 的 handler
+func newFilterHandler(o *output) *filterHandler {
+	return &filterHandler{out: o, cache: &handlerCache{}}
+}
+
+func (h *filterHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.out.enabled(h.subsystem, l)
+}
+
+func (h *filterHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.resolve().Handle(ctx, r)
+}
+
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	next := h.derive(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+	if h.groups == 0 {
+		for _, a := range attrs {
+			if a.Key == SubsystemKey {
+				next.subsystem = a.Value.String()
+			}
+		}
+	}
+	return next
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := h.derive(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+	next.groups++
+	return next
+}
+
+// derive 追加一个派生操作
+func (h *filterHandler) derive(op func(slog.Handler) slog.Handler) *filterHandler {
+	ops := append(slices.Clip(h.ops), op)
+	return &filterHandler{out: h.out, subsystem: h.subsystem, groups: h.groups, ops: ops, cache: &handlerCache{}}
+}
+
+// resolve 返回按当前配置派生的 handler，配置未变化时使用缓存
+func (h *filterHandler) resolve() slog.Handler {
+	sink, gen := h.out.current()
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+	if h.cache.handler == nil || h.cache.gen != gen {
+		for _, op := range h.ops {
+			sink = op(sink)
+		}
+		h.cache.handler, h.cache.gen = sink, gen
+	}
+	return h.cache.handler
+}
+
+// fanout 把日志同时写入多个 handler
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			if err := h.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
+
+// For 返回带子系统属性的 logger，其级别可通过 SetSubsystemLevel 单独调整
+func For(subsystem string) *slog.Logger {
+	return GetDefaultLogger().With(SubsystemKey, subsystem)
+}
+
+// SetSubsystemLevel 设置子系统的日志级别，覆盖全局级别
+func SetSubsystemLevel(subsystem string, l slog.Level) error {
+	if !slices.Contains(Subsystems(), subsystem) {
+		return fmt.Errorf("未知的日志子系统: %s", subsystem)
+	}
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	out.levels[subsystem] = l
+	return nil
+}
+
+// ClearSubsystemLevel 取消子系统的级别覆盖，恢复使用全局级别
+func ClearSubsystemLevel(subsystem string) {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	delete(out.levels, subsystem)
+}
+
+// SubsystemLevels 返回已覆盖级别的子系统
+func SubsystemLevels() map[string]slog.Level {
+	out.mu.RLock()
+	defer out.mu.RUnlock()
+	levels := make(map[string]slog.Level, len(out.levels))
+	for k, v := range out.levels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// SetJSON 切换 JSON 输出格式，同时作用于标准错误与日志文件
+func SetJSON(enabled bool) {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if out.json != enabled {
+		out.json = enabled
+		out.rebuildLocked()
+	}
+}
+
+// JSONEnabled 返回是否使用 JSON 输出格式
+func JSONEnabled() bool {
+	out.mu.RLock()
+	defer out.mu.RUnlock()
+	return out.json
+}
+
+// EnableFile 开启日志文件输出，path 为空时使用 DefaultFilePath；文件超过大小上限时自动轮转
+func EnableFile(path string) error {
+	if path == "" {
+		path = DefaultFilePath()
+	}
+	f, err := openRotatingFile(path, DefaultMaxFileBytes, DefaultMaxBackups)
+	if err != nil {
+		return err
+	}
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if out.file != nil {
+		out.file.Close()
+	}
+	out.file = f
+	out.rebuildLocked()
+	return nil
+}
+
+// FilePath 返回当前日志文件路径，未开启文件输出时为空
+func FilePath() string {
+	out.mu.RLock()
+	defer out.mu.RUnlock()
+	if out.file == nil {
+		return ""
+	}
+	return out.file.path
+}
+
+// Rotate 立即轮转日志文件，返回轮转后的旧日志路径
+func Rotate() (string, error) {
+	out.mu.RLock()
+	defer out.mu.RUnlock()
+	if out.file == nil {
+		return "", errors.New("未开启日志文件输出")
+	}
+	return out.file.Rotate()
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilterHandlerSubsystemLevels(t *testing.T) {
+	var buf bytes.Buffer
+	global := new(slog.LevelVar)
+	global.Set(slog.LevelInfo)
+	o := newOutput(&buf, global, newRecorder(16))
+	root := slog.New(newFilterHandler(o))
+	db := root.With(SubsystemKey, SubsystemDB)
+
+	o.levels[SubsystemDB] = slog.LevelDebug
+	db.Debug("db-debug")
+	root.Debug("root-debug")
+	root.WithGroup("g").With(SubsystemKey, SubsystemDB).Debug("grouped-debug")
+
+	o.levels[SubsystemTerminal] = slog.LevelError
+	root.With(SubsystemKey, SubsystemTerminal).Warn("terminal-warn")
+
+	got := buf.String()
+	if !strings.Contains(got, "db-debug") {
+		t.Fatalf("子系统级别应覆盖全局级别: %s", got)
+	}
+	for _, msg := range []string{"root-debug", "grouped-debug", "terminal-warn"} {
+		if strings.Contains(got, msg) {
+			t.Fatalf("不应输出 %s: %s", msg, got)
+		}
+	}
+	if recs := o.rec.snapshot(slog.LevelDebug, SubsystemDB, 0); len(recs) != 1 || recs[0].Message != "db-debug" {
+		t.Fatalf("按子系统读取最近日志错误: %+v", recs)
+	}
+}
+
+func TestFilterHandlerSwitchesToJSON(t *testing.T) {
+	var buf bytes.Buffer
+	o := newOutput(&buf, slog.LevelInfo, newRecorder(16))
+	log := slog.New(newFilterHandler(o)).With("module", "test")
+	log.Info("text")
+
+	o.mu.Lock()
+	o.json = true
+	o.rebuildLocked()
+	o.mu.Unlock()
+	buf.Reset()
+	log.Info("json", "n", 1)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("切换后应输出 JSON: %q, %v", buf.String(), err)
+	}
+	if entry["msg"] != "json" || entry["module"] != "test" {
+		t.Fatalf("派生 logger 的属性应在重建后保留: %v", entry)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boxify.log")
+	r, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	read := func(p string) string {
+		data, _ := os.ReadFile(p)
+		return string(data)
+	}
+	if read(path) != "dddddddd\n" || read(path+".1") != "cccccccc\n" || read(path+".2") != "bbbbbbbb\n" {
+		t.Fatalf("超过上限应自动轮转: %q %q %q", read(path), read(path+".1"), read(path+".2"))
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatal("超出保留数的旧日志应被删除")
+	}
+
+	rotated, err := r.Rotate()
+	if err != nil || rotated != path+".1" || read(rotated) != "dddddddd\n" || read(path) != "" {
+		t.Fatalf("Rotate() = %q, %v", rotated, err)
+	}
+}
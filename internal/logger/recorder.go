@@ -0,0 +1,164 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRecentCapacity 内存中保留的最近日志条数。
+const DefaultRecentCapacity = 2000
+
+// Record 是内存中保留的一条日志。
+type Record struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// sensitiveKeys 属性名包含这些片段时不保留原值。
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "credential", "privatekey"}
+
+// recorder 以环形缓冲保留最近的日志，供诊断与问题反馈使用。
+type recorder struct {
+	mu   sync.Mutex
+	buf  []Record
+	next int
+	full bool
+}
+
+var recent = newRecorder(DefaultRecentCapacity)
+
+func newRecorder(capacity int) *recorder {
+	return &recorder{buf: make([]Record, capacity)}
+}
+
+func (r *recorder) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf[r.next] = rec
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot 按时间顺序返回级别不低于 minLevel 的最近 limit 条日志（limit<=0 表示全部），
+// subsystem 不为空时只返回该子系统的日志。
+func (r *recorder) snapshot(minLevel slog.Level, subsystem string, limit int) []Record {
+	r.mu.Lock()
+	ordered := make([]Record, 0, len(r.buf))
+	if r.full {
+		ordered = append(ordered, r.buf[r.next:]...)
+	}
+	ordered = append(ordered, r.buf[:r.next]...)
+	r.mu.Unlock()
+
+	out := make([]Record, 0, len(ordered))
+	for _, rec := range ordered {
+		var level slog.Level
+		if subsystem != "" && rec.Attrs[SubsystemKey] != subsystem {
+			continue
+		}
+		if err := level.UnmarshalText([]byte(rec.Level)); err == nil && level >= minLevel {
+			out = append(out, rec)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+// Recent 返回内存中级别不低于 minLevel 的最近 limit 条日志，敏感属性已脱敏。
+func Recent(minLevel slog.Level, limit int) []Record {
+	return recent.snapshot(minLevel, "", limit)
+}
+
+// RecentFor 返回内存中指定子系统级别不低于 minLevel 的最近 limit 条日志，subsystem 为空时不按子系统过滤。
+func RecentFor(subsystem string, minLevel slog.Level, limit int) []Record {
+	return recent.snapshot(minLevel, subsystem, limit)
+}
+
+// recordingHandler 在转发日志的同时写入内存环形缓冲。
+type recordingHandler struct {
+	next   slog.Handler
+	rec    *recorder
+	attrs  []slog.Attr
+	prefix string
+}
+
+func newRecordingHandler(next slog.Handler, rec *recorder) *recordingHandler {
+	return &recordingHandler{next: next, rec: rec}
+}
+
+func (h *recordingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, r slog.Record) error {
+	rec := Record{Time: r.Time, Level: r.Level.String(), Message: r.Message}
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		rec.Attrs = make(map[string]string, len(h.attrs)+r.NumAttrs())
+		for _, a := range h.attrs {
+			addAttr(rec.Attrs, "", a)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			addAttr(rec.Attrs, h.prefix, a)
+			return true
+		})
+	}
+	h.rec.add(rec)
+	return h.next.Handle(ctx, r)
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	qualified := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	qualified = append(qualified, h.attrs...)
+	for _, a := range attrs {
+		qualified = append(qualified, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &recordingHandler{next: h.next.WithAttrs(attrs), rec: h.rec, attrs: qualified, prefix: h.prefix}
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return &recordingHandler{next: h.next.WithGroup(name), rec: h.rec, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// addAttr 展开分组属性并对敏感字段脱敏。
+func addAttr(dst map[string]string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	key := prefix + a.Key
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			addAttr(dst, key+".", ga)
+		}
+		return
+	}
+	lower := strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(lower, s) {
+			dst[key] = "[REDACTED]"
+			return
+		}
+	}
+	dst[key] = fmt.Sprint(a.Value.Any())
+}
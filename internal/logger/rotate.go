@@ -0,0 +1,129 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// DefaultMaxFileBytes 日志文件自动轮转的大小上限
+	DefaultMaxFileBytes = 10 << 20
+	// DefaultMaxBackups 轮转后保留的旧日志文件数
+	DefaultMaxBackups = 5
+)
+
+// DefaultFilePath 返回默认的日志文件路径
+func DefaultFilePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "logs", "boxify.log")
+	}
+	return filepath.Join(configDir, "Boxify", "logs", "boxify.log")
+}
+
+// rotatingFile 按大小轮转的日志文件：boxify.log 轮转为 boxify.log.1，旧文件依次后移，超出保留数的删除
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// openRotatingFile 以追加方式打开日志文件
+func openRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := r.openLocked(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write 写入日志，写入后超过上限时先轮转
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if _, err := r.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate 立即轮转，返回旧日志的路径
+func (r *rotatingFile) Rotate() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotateLocked()
+}
+
+// Close 关闭日志文件
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// openLocked 打开日志文件；调用方需持有锁
+func (r *rotatingFile) openLocked() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return fmt.Errorf("创建日志目录失败：%w", err)
+	}
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败：%w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("读取日志文件失败：%w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// rotateLocked 关闭当前文件、后移旧文件并重新打开；调用方需持有锁
+func (r *rotatingFile) rotateLocked() (string, error) {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+	backups := max(r.maxBackups, 1)
+	os.Remove(fmt.Sprintf("%s.%d", r.path, backups))
+	for i := backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	rotated := r.path + ".1"
+	if err := os.Rename(r.path, rotated); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("轮转日志文件失败：%w", err)
+	}
+	return rotated, r.openLocked()
+}
@@ -0,0 +1,338 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics 提供进程内的计数器与直方图，用于量化查询耗时、导出吞吐与终端输出速率。
+//
+// 指标默认关闭，由用户在设置中开启；关闭时记录操作直接返回，不产生开销。
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 内置指标名称。
+const (
+	QueryTotal           = "db.query.total"          // 执行的查询数
+	QueryErrors          = "db.query.errors"         // 失败的查询数
+	QueryDurationMs      = "db.query.duration_ms"    // 查询耗时（毫秒）
+	ExportTotal          = "export.total"            // 完成的导出数
+	ExportRows           = "export.rows"             // 导出的行数
+	ExportBytes          = "export.bytes"            // 写入导出文件的字节数
+	ExportDurationMs     = "export.duration_ms"      // 单次导出耗时（毫秒）
+	ExportRowsPerSecond  = "export.rows_per_sec"     // 单次导出吞吐（行/秒）
+	TerminalOutputBytes  = "terminal.output.bytes"   // 从 PTY 读取的字节数
+	TerminalOutputEvents = "terminal.output.events"  // 发送给前端的输出事件数
+	TerminalDroppedBytes = "terminal.output.dropped" // 超过速率上限被丢弃的字节数
+)
+
+// DefaultBuckets 是毫秒耗时直方图的默认桶上界。
+var DefaultBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// ThroughputBuckets 是每秒行数直方图的桶上界。
+var ThroughputBuckets = []float64{100, 500, 1000, 5000, 10000, 50000, 100000, 500000, 1000000}
+
+// Registry 管理一组指标。
+type Registry struct {
+	enabled atomic.Bool
+
+	mu         sync.Mutex
+	since      time.Time
+	counters   map[string]*Counter
+	histograms map[string]*Histogram
+}
+
+// NewRegistry 创建指标注册表，初始为关闭状态
+func NewRegistry() *Registry {
+	return &Registry{
+		since:      time.Now(),
+		counters:   make(map[string]*Counter),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// SetEnabled 开启或关闭指标记录
+func (r *Registry) SetEnabled(enabled bool) {
+	r.enabled.Store(enabled)
+}
+
+// Enabled 返回是否记录指标
+func (r *Registry) Enabled() bool {
+	return r.enabled.Load()
+}
+
+// Counter 返回名为 name 的计数器，不存在时创建
+func (r *Registry) Counter(name string) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.counters[name]
+	if !ok {
+		c = &Counter{reg: r}
+		r.counters[name] = c
+	}
+	return c
+}
+
+// Histogram 返回名为 name 的直方图，不存在时按 buckets 创建；buckets 为空时使用 DefaultBuckets
+func (r *Registry) Histogram(name string, buckets []float64) *Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.histograms[name]
+	if !ok {
+		if len(buckets) == 0 {
+			buckets = DefaultBuckets
+		}
+		bounds := append([]float64(nil), buckets...)
+		sort.Float64s(bounds)
+		h = &Histogram{reg: r, bounds: bounds, counts: make([]uint64, len(bounds)+1), min: math.Inf(1), max: math.Inf(-1)}
+		r.histograms[name] = h
+	}
+	return h
+}
+
+// Reset 清空全部指标并重新开始计时
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	counters := make([]*Counter, 0, len(r.counters))
+	for _, c := range r.counters {
+		counters = append(counters, c)
+	}
+	histograms := make([]*Histogram, 0, len(r.histograms))
+	for _, h := range r.histograms {
+		histograms = append(histograms, h)
+	}
+	r.since = time.Now()
+	r.mu.Unlock()
+
+	for _, c := range counters {
+		c.value.Store(0)
+	}
+	for _, h := range histograms {
+		h.reset()
+	}
+}
+
+// Counter 是单调递增的计数器。
+type Counter struct {
+	reg   *Registry
+	value atomic.Int64
+}
+
+// Add 累加 n，指标关闭时忽略
+func (c *Counter) Add(n int64) {
+	if c.reg.Enabled() {
+		c.value.Add(n)
+	}
+}
+
+// Value 返回当前值
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+// Histogram 按桶统计观测值的分布。
+type Histogram struct {
+	reg    *Registry
+	bounds []float64
+
+	mu     sync.Mutex
+	counts []uint64 // 最后一个桶统计超过全部上界的观测值
+	count  uint64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+// Observe 记录一次观测值，指标关闭时忽略
+func (h *Histogram) Observe(v float64) {
+	if !h.reg.Enabled() {
+		return
+	}
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.mu.Lock()
+	h.counts[i]++
+	h.count++
+	h.sum += v
+	h.min = math.Min(h.min, v)
+	h.max = math.Max(h.max, v)
+	h.mu.Unlock()
+}
+
+// ObserveDuration 以毫秒记录耗时
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(float64(d) / float64(time.Millisecond))
+}
+
+// reset 清空观测值
+func (h *Histogram) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clear(h.counts)
+	h.count, h.sum = 0, 0
+	h.min, h.max = math.Inf(1), math.Inf(-1)
+}
+
+// snapshot 返回直方图快照
+func (h *Histogram) snapshot(name string) HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HistogramSnapshot{Name: name, Count: h.count, Sum: h.sum, Buckets: make([]Bucket, 0, len(h.bounds))}
+	var cumulative uint64
+	for i, b := range h.bounds {
+		cumulative += h.counts[i]
+		s.Buckets = append(s.Buckets, Bucket{UpperBound: b, Count: cumulative})
+	}
+	if h.count == 0 {
+		return s
+	}
+	s.Min, s.Max, s.Mean = h.min, h.max, h.sum/float64(h.count)
+	s.P50 = h.quantileLocked(0.5)
+	s.P95 = h.quantileLocked(0.95)
+	s.P99 = h.quantileLocked(0.99)
+	return s
+}
+
+// quantileLocked 按桶内线性插值估算分位数；调用方需持有锁
+func (h *Histogram) quantileLocked(q float64) float64 {
+	rank := q * float64(h.count)
+	var cumulative float64
+	for i, n := range h.counts {
+		if n == 0 {
+			continue
+		}
+		if cumulative+float64(n) >= rank {
+			lower := h.min
+			if i > 0 {
+				lower = math.Max(h.bounds[i-1], h.min)
+			}
+			upper := h.max
+			if i < len(h.bounds) {
+				upper = math.Min(h.bounds[i], h.max)
+			}
+			return lower + (upper-lower)*(rank-cumulative)/float64(n)
+		}
+		cumulative += float64(n)
+	}
+	return h.max
+}
+
+// Bucket 是直方图的累计桶：观测值不超过 UpperBound 的次数。
+type Bucket struct {
+	UpperBound float64 `json:"upperBound"`
+	Count      uint64  `json:"count"`
+}
+
+// CounterSnapshot 是计数器快照。
+type CounterSnapshot struct {
+	Name  string  `json:"name"`
+	Value int64   `json:"value"`
+	Rate  float64 `json:"rate"` // 自开始计时以来的平均每秒增量
+}
+
+// HistogramSnapshot 是直方图快照，分位数由桶内插值估算。
+type HistogramSnapshot struct {
+	Name    string   `json:"name"`
+	Count   uint64   `json:"count"`
+	Sum     float64  `json:"sum"`
+	Min     float64  `json:"min"`
+	Max     float64  `json:"max"`
+	Mean    float64  `json:"mean"`
+	P50     float64  `json:"p50"`
+	P95     float64  `json:"p95"`
+	P99     float64  `json:"p99"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Snapshot 是全部指标的快照，供本地指标面板展示。
+type Snapshot struct {
+	Enabled    bool                `json:"enabled"`
+	Since      time.Time           `json:"since"` // 开始计时的时间
+	Uptime     float64             `json:"uptimeSeconds"`
+	Counters   []CounterSnapshot   `json:"counters"`
+	Histograms []HistogramSnapshot `json:"histograms"`
+}
+
+// Snapshot 返回按名称排序的指标快照
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	since := r.since
+	counters := make(map[string]*Counter, len(r.counters))
+	for name, c := range r.counters {
+		counters[name] = c
+	}
+	histograms := make(map[string]*Histogram, len(r.histograms))
+	for name, h := range r.histograms {
+		histograms[name] = h
+	}
+	r.mu.Unlock()
+
+	uptime := time.Since(since).Seconds()
+	s := Snapshot{
+		Enabled:    r.Enabled(),
+		Since:      since,
+		Uptime:     uptime,
+		Counters:   make([]CounterSnapshot, 0, len(counters)),
+		Histograms: make([]HistogramSnapshot, 0, len(histograms)),
+	}
+	for name, c := range counters {
+		cs := CounterSnapshot{Name: name, Value: c.Value()}
+		if uptime > 0 {
+			cs.Rate = float64(cs.Value) / uptime
+		}
+		s.Counters = append(s.Counters, cs)
+	}
+	for name, h := range histograms {
+		s.Histograms = append(s.Histograms, h.snapshot(name))
+	}
+	sort.Slice(s.Counters, func(i, j int) bool { return s.Counters[i].Name < s.Counters[j].Name })
+	sort.Slice(s.Histograms, func(i, j int) bool { return s.Histograms[i].Name < s.Histograms[j].Name })
+	return s
+}
+
+// Default 是应用使用的全局指标注册表。
+var Default = NewRegistry()
+
+// SetEnabled 开启或关闭全局指标记录
+func SetEnabled(enabled bool) {
+	Default.SetEnabled(enabled)
+}
+
+// Enabled 返回是否记录全局指标
+func Enabled() bool {
+	return Default.Enabled()
+}
+
+// Add 累加全局计数器
+func Add(name string, n int64) {
+	if Default.Enabled() {
+		Default.Counter(name).Add(n)
+	}
+}
+
+// Observe 向全局直方图记录观测值
+func Observe(name string, v float64) {
+	if Default.Enabled() {
+		Default.Histogram(name, nil).Observe(v)
+	}
+}
+
+// ObserveSince 以毫秒向全局直方图记录自 start 以来的耗时
+func ObserveSince(name string, start time.Time) {
+	if Default.Enabled() {
+		Default.Histogram(name, nil).ObserveDuration(time.Since(start))
+	}
+}
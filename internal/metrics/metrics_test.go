@@ -0,0 +1,70 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRegistryDisabledIgnoresRecords(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("c").Add(3)
+	r.Histogram("h", nil).Observe(10)
+
+	s := r.Snapshot()
+	if s.Enabled || s.Counters[0].Value != 0 || s.Histograms[0].Count != 0 {
+		t.Fatalf("关闭时不应记录指标: %+v", s)
+	}
+}
+
+func TestHistogramSnapshot(t *testing.T) {
+	r := NewRegistry()
+	r.SetEnabled(true)
+	h := r.Histogram("h", []float64{10, 100})
+	for _, v := range []float64{1, 2, 3, 50, 500} {
+		h.Observe(v)
+	}
+	h.ObserveDuration(20 * time.Millisecond)
+
+	s := r.Snapshot().Histograms[0]
+	if s.Count != 6 || s.Min != 1 || s.Max != 500 || s.Sum != 576 {
+		t.Fatalf("直方图统计错误: %+v", s)
+	}
+	if len(s.Buckets) != 2 || s.Buckets[0].Count != 3 || s.Buckets[1].Count != 5 {
+		t.Fatalf("累计桶错误: %+v", s.Buckets)
+	}
+	if s.P50 < 1 || s.P50 > 10 || s.P99 <= 100 || s.P99 > 500 {
+		t.Fatalf("分位数估算错误: p50=%v p99=%v", s.P50, s.P99)
+	}
+}
+
+func TestRegistryReset(t *testing.T) {
+	r := NewRegistry()
+	r.SetEnabled(true)
+	r.Counter("c").Add(5)
+	r.Histogram("h", nil).Observe(1)
+	r.Reset()
+
+	s := r.Snapshot()
+	if s.Counters[0].Value != 0 || s.Histograms[0].Count != 0 || !math.IsInf(r.Histogram("h", nil).min, 1) {
+		t.Fatalf("重置后应清空指标: %+v", s)
+	}
+	r.Counter("c").Add(2)
+	if got := r.Snapshot().Counters[0]; got.Value != 2 || got.Rate <= 0 {
+		t.Fatalf("重置后应继续计数: %+v", got)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nestedfetch
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// dbSource 基于数据库连接的数据来源。
+type dbSource struct {
+	conn     db.Database
+	dbType   connection.ConnectionType
+	database string
+}
+
+// NewDBSource 基于数据库连接创建数据来源，database 为表所在的库（或 schema）。
+func NewDBSource(conn db.Database, dbType connection.ConnectionType, database string) Source {
+	return &dbSource{conn: conn, dbType: dbType, database: database}
+}
+
+// ForeignKeys 读取 table 上的外键并按约束聚合。
+func (s *dbSource) ForeignKeys(ctx context.Context, table string) ([]*connection.ForeignKeyRef, error) {
+	fks, err := s.conn.GetForeignKeys(ctx, s.database, table)
+	if err != nil {
+		return nil, err
+	}
+	return db.GroupForeignKeys(table, fks), nil
+}
+
+// Match 读取匹配任一组取值的行，连接支持时随 ctx 取消。
+func (s *dbSource) Match(ctx context.Context, table string, columns []string, tuples [][]interface{}, limit int) ([]map[string]interface{}, []string, error) {
+	query, args := db.BuildMatchAnyRowsQuery(s.dbType, table, columns, tuples, limit)
+	if q, ok := s.conn.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		return q.QueryContext(ctx, query, args...)
+	}
+	return s.conn.Query(query, args...)
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nestedfetch 按声明的关联规格沿外键读取一行及其关联行，组装为嵌套结构，
+// 每个关联在每一层只查询一次（按上一层全部行批量匹配），避免详情页逐行往返。
+package nestedfetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+)
+
+// 关联深度与每个关联读取行数的默认值与上限。
+const (
+	DefaultMaxDepth = 3
+	MaxDepth        = 5
+	DefaultLimit    = 100
+	MaxLimit        = 1000
+)
+
+// maxTuplesPerQuery 是单条匹配语句包含的最大取值组数，避免超出驱动的参数个数限制。
+const maxTuplesPerQuery = 200
+
+// ErrRowNotFound 表示根行定位条件没有匹配到记录。
+var ErrRowNotFound = errors.New("未找到记录")
+
+// Source 是嵌套读取的数据来源。
+type Source interface {
+	// ForeignKeys 返回定义在 table 上的外键，按约束聚合
+	ForeignKeys(ctx context.Context, table string) ([]*connection.ForeignKeyRef, error)
+	// Match 读取 table 中 columns 取值等于 tuples 任一组的行，limit 大于 0 时至多读取 limit 行
+	Match(ctx context.Context, table string, columns []string, tuples [][]interface{}, limit int) ([]map[string]interface{}, []string, error)
+}
+
+// Spec 是一条关联的声明。默认读取引用当前行的子表记录，Parent 为 true 时读取当前行引用的父表记录。
+type Spec struct {
+	Name       string  `json:"name,omitempty"`       // 结果中的关联名，默认为关联表名
+	Table      string  `json:"table"`                // 关联表
+	Constraint string  `json:"constraint,omitempty"` // 外键约束名，为空时要求两表之间只有一个该方向的外键
+	Parent     bool    `json:"parent,omitempty"`     // 读取父表记录
+	Limit      int     `json:"limit,omitempty"`      // 该关联在本层最多读取的行数（所有上层行合计），<=0 时取默认值
+	Relations  []*Spec `json:"relations,omitempty"`  // 下一层关联
+}
+
+// Request 是嵌套读取请求。
+type Request struct {
+	Table     string                 `json:"table"`              // 根表
+	Key       map[string]interface{} `json:"key"`                // 根行定位条件（列名到取值），通常为主键，须唯一匹配一行
+	Relations []*Spec                `json:"relations"`          // 关联规格
+	MaxDepth  int                    `json:"maxDepth,omitempty"` // 关联深度上限，<=0 时取默认值
+}
+
+// Row 是结果中的一行及其关联。
+type Row struct {
+	Values    map[string]interface{} `json:"values"`
+	Relations map[string]*Relation   `json:"relations,omitempty"`
+}
+
+// Relation 是某一行在一条关联下的记录。
+type Relation struct {
+	Table      string   `json:"table"`
+	Constraint string   `json:"constraint"`
+	Parent     bool     `json:"parent"`
+	Columns    []string `json:"columns"`
+	Rows       []*Row   `json:"rows"`
+	Truncated  bool     `json:"truncated"` // 该关联在本层超过行数上限，部分行的记录可能不完整
+}
+
+// Result 是嵌套读取结果。
+type Result struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	Row     *Row     `json:"row"`
+	Queries int      `json:"queries"` // 执行的查询条数，不含读取外键元数据
+}
+
+// Fetch 读取 req.Key 定位的根行，再按 req.Relations 逐层批量读取关联行。
+func Fetch(ctx context.Context, src Source, req *Request) (*Result, error) {
+	maxDepth := req.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxDepth > MaxDepth {
+		return nil, fmt.Errorf("关联深度不能超过 %d", MaxDepth)
+	}
+	if err := checkSpecs(req.Relations, 1, maxDepth); err != nil {
+		return nil, err
+	}
+	if len(req.Key) == 0 {
+		return nil, fmt.Errorf("缺少根行定位条件")
+	}
+
+	keyColumns := make([]string, 0, len(req.Key))
+	for col := range req.Key {
+		keyColumns = append(keyColumns, col)
+	}
+	sort.Strings(keyColumns)
+	tuple := make([]interface{}, len(keyColumns))
+	for i, col := range keyColumns {
+		tuple[i] = req.Key[col]
+	}
+	rows, columns, err := src.Match(ctx, req.Table, keyColumns, [][]interface{}{tuple}, 2)
+	if err != nil {
+		return nil, err
+	}
+	switch len(rows) {
+	case 0:
+		return nil, ErrRowNotFound
+	case 1:
+	default:
+		return nil, fmt.Errorf("定位条件匹配到多行，请使用主键或唯一列")
+	}
+
+	f := &fetcher{src: src, queries: 1}
+	root := &Row{Values: rows[0]}
+	if err := f.expand(ctx, req.Table, []*Row{root}, req.Relations); err != nil {
+		return nil, err
+	}
+	return &Result{Table: req.Table, Columns: columns, Row: root, Queries: f.queries}, nil
+}
+
+// checkSpecs 校验关联规格：表名必填、同层关联名不重复、深度与行数在上限内。
+func checkSpecs(specs []*Spec, depth, maxDepth int) error {
+	if len(specs) > 0 && depth > maxDepth {
+		return fmt.Errorf("关联深度超过上限 %d", maxDepth)
+	}
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec == nil || spec.Table == "" {
+			return fmt.Errorf("关联缺少表名")
+		}
+		if spec.Limit > MaxLimit {
+			return fmt.Errorf("关联 %s 的行数上限不能超过 %d", spec.name(), MaxLimit)
+		}
+		if names[spec.name()] {
+			return fmt.Errorf("同一层存在重复的关联名 %s", spec.name())
+		}
+		names[spec.name()] = true
+		if err := checkSpecs(spec.Relations, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name 返回关联在结果中的名称。
+func (s *Spec) name() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Table
+}
+
+// fetcher 保存一次嵌套读取的状态。
+type fetcher struct {
+	src     Source
+	queries int
+}
+
+// expand 为 table 中的 rows 读取 specs 声明的关联，并递归读取下一层。
+func (f *fetcher) expand(ctx context.Context, table string, rows []*Row, specs []*Spec) error {
+	for _, spec := range specs {
+		fk, err := f.resolve(ctx, table, spec)
+		if err != nil {
+			return err
+		}
+		// from 为当前行中参与匹配的列，to 为关联表中对应的列
+		from, to := fk.RefColumns, fk.Columns
+		if spec.Parent {
+			from, to = fk.Columns, fk.RefColumns
+		}
+
+		var tuples [][]interface{}
+		seen := make(map[string]bool)
+		for _, row := range rows {
+			tuple, ok := matchTuple(row.Values, from)
+			if !ok {
+				continue
+			}
+			key := resultdiff.RowKey(row.Values, from)
+			if !seen[key] {
+				seen[key] = true
+				tuples = append(tuples, tuple)
+			}
+		}
+
+		limit := spec.Limit
+		if limit <= 0 {
+			limit = DefaultLimit
+		}
+		related, columns, truncated, err := f.match(ctx, spec.Table, to, tuples, limit)
+		if err != nil {
+			return fmt.Errorf("读取关联 %s 失败: %w", spec.name(), err)
+		}
+
+		children := make([]*Row, len(related))
+		index := make(map[string][]*Row, len(related))
+		for i, values := range related {
+			children[i] = &Row{Values: values}
+			key := resultdiff.RowKey(values, to)
+			index[key] = append(index[key], children[i])
+		}
+		for _, row := range rows {
+			rel := &Relation{Table: spec.Table, Constraint: fk.Name, Parent: spec.Parent, Columns: columns, Rows: []*Row{}, Truncated: truncated}
+			if _, ok := matchTuple(row.Values, from); ok {
+				if matched := index[resultdiff.RowKey(row.Values, from)]; matched != nil {
+					rel.Rows = matched
+				}
+			}
+			if row.Relations == nil {
+				row.Relations = make(map[string]*Relation, len(specs))
+			}
+			row.Relations[spec.name()] = rel
+		}
+
+		if len(children) > 0 {
+			if err := f.expand(ctx, spec.Table, children, spec.Relations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve 查找 spec 对应的外键：子表方向为 spec.Table 上引用 table 的外键，父表方向为 table 上引用 spec.Table 的外键。
+func (f *fetcher) resolve(ctx context.Context, table string, spec *Spec) (*connection.ForeignKeyRef, error) {
+	owner, referenced := spec.Table, table
+	if spec.Parent {
+		owner, referenced = table, spec.Table
+	}
+	refs, err := f.src.ForeignKeys(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("读取表 %s 的外键失败: %w", owner, err)
+	}
+	var candidates []*connection.ForeignKeyRef
+	for _, ref := range refs {
+		if ref.RefTable != referenced {
+			continue
+		}
+		if spec.Constraint != "" && ref.Name != spec.Constraint {
+			continue
+		}
+		candidates = append(candidates, ref)
+	}
+	switch len(candidates) {
+	case 0:
+		if spec.Constraint != "" {
+			return nil, fmt.Errorf("表 %s 上不存在引用 %s 的外键 %s", owner, referenced, spec.Constraint)
+		}
+		return nil, fmt.Errorf("表 %s 上没有引用 %s 的外键", owner, referenced)
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, fmt.Errorf("表 %s 上有多个引用 %s 的外键，请为关联 %s 指定约束名", owner, referenced, spec.name())
+	}
+}
+
+// match 分批读取关联行，合计至多 limit 行；超出时截断并返回 true。
+func (f *fetcher) match(ctx context.Context, table string, columns []string, tuples [][]interface{}, limit int) ([]map[string]interface{}, []string, bool, error) {
+	var rows []map[string]interface{}
+	var fields []string
+	for start := 0; start < len(tuples) && len(rows) <= limit; start += maxTuplesPerQuery {
+		end := min(start+maxTuplesPerQuery, len(tuples))
+		batch, batchFields, err := f.src.Match(ctx, table, columns, tuples[start:end], limit+1-len(rows))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		f.queries++
+		if fields == nil {
+			fields = batchFields
+		}
+		rows = append(rows, batch...)
+	}
+	if fields == nil {
+		fields = []string{}
+	}
+	if len(rows) > limit {
+		return rows[:limit], fields, true, nil
+	}
+	return rows, fields, false, nil
+}
+
+// matchTuple 按 columns 顺序取出行中的值，缺少列或存在 NULL 时没有关联记录。
+func matchTuple(values map[string]interface{}, columns []string) ([]interface{}, bool) {
+	tuple := make([]interface{}, len(columns))
+	for i, col := range columns {
+		v, ok := values[col]
+		if !ok || v == nil {
+			return nil, false
+		}
+		tuple[i] = v
+	}
+	return tuple, true
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nestedfetch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+)
+
+// fakeSource 是内存中的表与外键。
+type fakeSource struct {
+	tables  map[string][]map[string]interface{}
+	fks     map[string][]*connection.ForeignKeyRef
+	queries int
+}
+
+func (s *fakeSource) ForeignKeys(ctx context.Context, table string) ([]*connection.ForeignKeyRef, error) {
+	return s.fks[table], nil
+}
+
+func (s *fakeSource) Match(ctx context.Context, table string, columns []string, tuples [][]interface{}, limit int) ([]map[string]interface{}, []string, error) {
+	s.queries++
+	var out []map[string]interface{}
+	for _, row := range s.tables[table] {
+		for _, tuple := range tuples {
+			match := true
+			for i, col := range columns {
+				if !resultdiff.Equal(row[col], tuple[i]) {
+					match = false
+					break
+				}
+			}
+			if match {
+				out = append(out, row)
+				break
+			}
+		}
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, []string{"id"}, nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		tables: map[string][]map[string]interface{}{
+			"users": {{"id": int64(1), "name": "ann"}, {"id": int64(2), "name": "bob"}},
+			"orders": {
+				{"id": int64(10), "user_id": int64(1)},
+				{"id": int64(11), "user_id": int64(1)},
+				{"id": int64(12), "user_id": int64(2)},
+			},
+			"order_items": {
+				{"order_id": int64(10), "product_id": "p1"},
+				{"order_id": int64(10), "product_id": "p2"},
+				{"order_id": int64(11), "product_id": "p1"},
+				{"order_id": int64(11), "product_id": nil},
+			},
+			"products": {{"id": "p1"}, {"id": "p2"}},
+		},
+		fks: map[string][]*connection.ForeignKeyRef{
+			"orders":      {{Name: "fk_orders_user", Table: "orders", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}}},
+			"order_items": {{Name: "fk_items_order", Table: "order_items", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}}, {Name: "fk_items_product", Table: "order_items", Columns: []string{"product_id"}, RefTable: "products", RefColumns: []string{"id"}}},
+		},
+	}
+}
+
+func TestFetch(t *testing.T) {
+	src := newFakeSource()
+	result, err := Fetch(context.Background(), src, &Request{
+		Table: "users",
+		Key:   map[string]interface{}{"id": 1},
+		Relations: []*Spec{{
+			Table: "orders",
+			Relations: []*Spec{{
+				Name:      "items",
+				Table:     "order_items",
+				Relations: []*Spec{{Table: "products", Parent: true}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := result.Row.Relations["orders"]
+	if orders == nil || len(orders.Rows) != 2 || orders.Constraint != "fk_orders_user" {
+		t.Fatalf("orders 关联不符: %+v", orders)
+	}
+	items := orders.Rows[0].Relations["items"]
+	if len(items.Rows) != 2 || len(orders.Rows[1].Relations["items"].Rows) != 2 {
+		t.Fatalf("items 关联不符: %+v", items)
+	}
+	product := items.Rows[1].Relations["products"]
+	if !product.Parent || len(product.Rows) != 1 || product.Rows[0].Values["id"] != "p2" {
+		t.Errorf("products 关联不符: %+v", product)
+	}
+	if nullItem := orders.Rows[1].Relations["items"].Rows[1]; len(nullItem.Relations["products"].Rows) != 0 {
+		t.Error("外键为 NULL 的行不应关联父表记录")
+	}
+	// 根行、每个关联各一次
+	if result.Queries != 4 || src.queries != 4 {
+		t.Errorf("查询次数 = %d/%d, want 4", result.Queries, src.queries)
+	}
+}
+
+func TestFetchLimit(t *testing.T) {
+	result, err := Fetch(context.Background(), newFakeSource(), &Request{
+		Table:     "users",
+		Key:       map[string]interface{}{"id": 1},
+		Relations: []*Spec{{Table: "orders", Limit: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := result.Row.Relations["orders"]
+	if len(orders.Rows) != 1 || !orders.Truncated {
+		t.Errorf("超过上限时应截断: %+v", orders)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	src := newFakeSource()
+	if _, err := Fetch(context.Background(), src, &Request{Table: "users", Key: map[string]interface{}{"id": 9}}); !errors.Is(err, ErrRowNotFound) {
+		t.Errorf("根行不存在时 err = %v", err)
+	}
+	deep := &Request{
+		Table:     "users",
+		Key:       map[string]interface{}{"id": 1},
+		MaxDepth:  1,
+		Relations: []*Spec{{Table: "orders", Relations: []*Spec{{Table: "order_items"}}}},
+	}
+	if _, err := Fetch(context.Background(), src, deep); err == nil {
+		t.Error("超过深度上限应返回错误")
+	}
+	noFK := &Request{Table: "users", Key: map[string]interface{}{"id": 1}, Relations: []*Spec{{Table: "products"}}}
+	if _, err := Fetch(context.Background(), src, noFK); err == nil {
+		t.Error("没有外键的关联应返回错误")
+	}
+	dup := &Request{Table: "users", Key: map[string]interface{}{"id": 1}, Relations: []*Spec{{Table: "orders"}, {Table: "orders"}}}
+	if _, err := Fetch(context.Background(), src, dup); err == nil {
+		t.Error("重复的关联名应返回错误")
+	}
+}
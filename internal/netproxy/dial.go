@@ -0,0 +1,250 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netproxy 通过 SOCKS5 或 HTTP CONNECT 代理建立 TCP 连接，
+// 供 SSH 跳板机拨号与数据库直连共用。
+package netproxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// Enabled 判断代理配置是否启用。
+func Enabled(proxy *connection.ProxyConfig) bool {
+	return proxy != nil && strings.TrimSpace(proxy.Type) != ""
+}
+
+// Address 返回代理服务器地址。
+func Address(proxy *connection.ProxyConfig) string {
+	return net.JoinHostPort(proxy.Host, strconv.Itoa(proxy.Port))
+}
+
+// Dial 建立到 addr 的 TCP 连接，proxy 未启用时直连。
+// 代理握手受 ctx 约束：握手期间使用 ctx 的截止时间，ctx 取消时关闭连接。
+func Dial(ctx context.Context, proxy *connection.ProxyConfig, addr string) (net.Conn, error) {
+	var d net.Dialer
+	if !Enabled(proxy) {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	typ := strings.ToLower(strings.TrimSpace(proxy.Type))
+	if typ != connection.ProxyTypeSOCKS5 && typ != connection.ProxyTypeHTTP {
+		return nil, fmt.Errorf("不支持的代理类型: %s", proxy.Type)
+	}
+	conn, err := d.DialContext(ctx, "tcp", Address(proxy))
+	if err != nil {
+		return nil, fmt.Errorf("连接代理 %s 失败：%w", Address(proxy), err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	if typ == connection.ProxyTypeSOCKS5 {
+		err = socks5Connect(conn, proxy, addr)
+	} else {
+		err = httpConnect(conn, proxy, addr)
+	}
+	close(done)
+	if err != nil {
+		_ = conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("通过代理 %s 连接 %s 失败：%w", Address(proxy), addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// SOCKS5 协议常量（RFC 1928 / RFC 1929）。
+const (
+	socksVersion        = 0x05
+	socksAuthNone       = 0x00
+	socksAuthPassword   = 0x02
+	socksAuthNoAccept   = 0xff
+	socksCmdConnect     = 0x01
+	socksAddrIPv4       = 0x01
+	socksAddrDomain     = 0x03
+	socksAddrIPv6       = 0x04
+	socksPasswordVer    = 0x01
+	socksReplySucceeded = 0x00
+)
+
+// socksReplyErrors 是 SOCKS5 应答码对应的说明。
+var socksReplyErrors = map[byte]string{
+	0x01: "代理服务器故障",
+	0x02: "代理规则不允许该连接",
+	0x03: "网络不可达",
+	0x04: "主机不可达",
+	0x05: "连接被拒绝",
+	0x06: "TTL 已过期",
+	0x07: "不支持的命令",
+	0x08: "不支持的地址类型",
+}
+
+// socks5Connect 在已建立的代理连接上完成认证与 CONNECT 请求。
+func socks5Connect(conn net.Conn, proxy *connection.ProxyConfig, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 || port > 65535 {
+		return fmt.Errorf("端口无效: %s", portStr)
+	}
+
+	methods := []byte{socksAuthNone}
+	if proxy.User != "" {
+		methods = []byte{socksAuthNone, socksAuthPassword}
+	}
+	if _, err := conn.Write(append([]byte{socksVersion, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != socksVersion {
+		return errors.New("代理不是 SOCKS5 服务")
+	}
+	switch reply[1] {
+	case socksAuthNone:
+	case socksAuthPassword:
+		if proxy.User == "" {
+			return errors.New("SOCKS5 代理要求用户名密码认证")
+		}
+		if len(proxy.User) > 255 || len(proxy.Password) > 255 {
+			return errors.New("SOCKS5 用户名或密码过长")
+		}
+		req := []byte{socksPasswordVer, byte(len(proxy.User))}
+		req = append(req, proxy.User...)
+		req = append(req, byte(len(proxy.Password)))
+		req = append(req, proxy.Password...)
+		if _, err := conn.Write(req); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return err
+		}
+		if reply[1] != 0x00 {
+			return errors.New("SOCKS5 代理认证失败")
+		}
+	case socksAuthNoAccept:
+		return errors.New("SOCKS5 代理不接受任何可用的认证方式")
+	default:
+		return fmt.Errorf("SOCKS5 代理选择了不支持的认证方式 %d", reply[1])
+	}
+
+	req := []byte{socksVersion, socksCmdConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(req, socksAddrIPv4)
+			req = append(req, ip4...)
+		} else {
+			req = append(req, socksAddrIPv6)
+			req = append(req, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return errors.New("主机名过长")
+		}
+		req = append(req, socksAddrDomain, byte(len(host)))
+		req = append(req, host...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return err
+	}
+	if head[1] != socksReplySucceeded {
+		if msg, ok := socksReplyErrors[head[1]]; ok {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("SOCKS5 代理返回错误 %d", head[1])
+	}
+	// 跳过绑定地址与端口
+	var skip int
+	switch head[3] {
+	case socksAddrIPv4:
+		skip = net.IPv4len + 2
+	case socksAddrIPv6:
+		skip = net.IPv6len + 2
+	case socksAddrDomain:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return err
+		}
+		skip = int(l[0]) + 2
+	default:
+		return fmt.Errorf("SOCKS5 代理返回未知地址类型 %d", head[3])
+	}
+	_, err = io.CopyN(io.Discard, conn, int64(skip))
+	return err
+}
+
+// httpConnect 在已建立的代理连接上发送 CONNECT 请求；代理在应答后提前发送的数据会导致失败，
+// 因为读取应答使用的缓冲无法交还给调用方。
+func httpConnect(conn net.Conn, proxy *connection.ProxyConfig, addr string) error {
+	var b strings.Builder
+	b.WriteString("CONNECT " + addr + " HTTP/1.1\r\nHost: " + addr + "\r\n")
+	if proxy.User != "" {
+		cred := base64.StdEncoding.EncodeToString([]byte(proxy.User + ":" + proxy.Password))
+		b.WriteString("Proxy-Authorization: Basic " + cred + "\r\n")
+	}
+	b.WriteString("\r\n")
+	if _, err := io.WriteString(conn, b.String()); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return fmt.Errorf("读取代理应答失败：%w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		return errors.New("HTTP 代理要求认证")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP 代理返回 %s", resp.Status)
+	}
+	if br.Buffered() > 0 {
+		return errors.New("HTTP 代理在隧道建立前发送了多余数据")
+	}
+	return nil
+}
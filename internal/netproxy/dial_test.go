@@ -0,0 +1,196 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netproxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// startEcho 启动回显服务并返回地址。
+func startEcho(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() { defer c.Close(); io.Copy(c, c) }()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// startProxy 启动只处理一个连接的代理，handshake 返回目标地址。
+func startProxy(t *testing.T, handshake func(net.Conn) (string, error)) *connection.ProxyConfig {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		target, err := handshake(c)
+		if err != nil {
+			return
+		}
+		up, err := net.Dial("tcp", target)
+		if err != nil {
+			return
+		}
+		defer up.Close()
+		go io.Copy(up, c)
+		io.Copy(c, up)
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return &connection.ProxyConfig{Host: host, Port: p}
+}
+
+func roundTrip(t *testing.T, conn net.Conn) {
+	t.Helper()
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("回显 = %q, %v", buf, err)
+	}
+}
+
+func TestDialSOCKS5WithAuth(t *testing.T) {
+	echo := startEcho(t)
+	proxy := startProxy(t, func(c net.Conn) (string, error) {
+		head := make([]byte, 2)
+		io.ReadFull(c, head)
+		methods := make([]byte, head[1])
+		io.ReadFull(c, methods)
+		c.Write([]byte{5, socksAuthPassword})
+		ver := make([]byte, 2)
+		io.ReadFull(c, ver)
+		user := make([]byte, ver[1])
+		io.ReadFull(c, user)
+		l := make([]byte, 1)
+		io.ReadFull(c, l)
+		pass := make([]byte, l[0])
+		io.ReadFull(c, pass)
+		if string(user) != "alice" || string(pass) != "pw" {
+			c.Write([]byte{1, 1})
+			return "", io.EOF
+		}
+		c.Write([]byte{1, 0})
+		req := make([]byte, 4)
+		io.ReadFull(c, req)
+		ip := make([]byte, 4)
+		io.ReadFull(c, ip)
+		port := make([]byte, 2)
+		io.ReadFull(c, port)
+		c.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		return net.JoinHostPort(net.IP(ip).String(), strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+	})
+	proxy.Type = connection.ProxyTypeSOCKS5
+	proxy.User, proxy.Password = "alice", "pw"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, proxy, echo)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	roundTrip(t, conn)
+}
+
+func TestDialHTTPConnect(t *testing.T) {
+	echo := startEcho(t)
+	var auth string
+	proxy := startProxy(t, func(c net.Conn) (string, error) {
+		req, err := http.ReadRequest(bufio.NewReader(c))
+		if err != nil {
+			return "", err
+		}
+		auth = req.Header.Get("Proxy-Authorization")
+		c.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		return req.Host, nil
+	})
+	proxy.Type = connection.ProxyTypeHTTP
+	proxy.User, proxy.Password = "bob", "secret"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, proxy, echo)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	roundTrip(t, conn)
+	if !strings.HasPrefix(auth, "Basic ") {
+		t.Fatalf("应发送代理认证头: %q", auth)
+	}
+}
+
+func TestDialHTTPConnectRejected(t *testing.T) {
+	proxy := startProxy(t, func(c net.Conn) (string, error) {
+		http.ReadRequest(bufio.NewReader(c))
+		c.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\nContent-Length: 0\r\n\r\n"))
+		return "", io.EOF
+	})
+	proxy.Type = connection.ProxyTypeHTTP
+
+	_, err := Dial(context.Background(), proxy, "db.internal:3306")
+	if err == nil || !strings.Contains(err.Error(), "要求认证") {
+		t.Fatalf("407 应返回认证错误: %v", err)
+	}
+}
+
+func TestDialHonorsContext(t *testing.T) {
+	// 代理接受连接后不应答，握手应在 ctx 截止时失败
+	proxy := startProxy(t, func(c net.Conn) (string, error) {
+		time.Sleep(time.Second)
+		return "", io.EOF
+	})
+	proxy.Type = connection.ProxyTypeSOCKS5
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := Dial(ctx, proxy, "db.internal:3306"); err == nil {
+		t.Fatal("握手超时应返回错误")
+	}
+	if elapsed := time.Since(start); elapsed > 800*time.Millisecond {
+		t.Fatalf("握手未受 ctx 约束: %v", elapsed)
+	}
+}
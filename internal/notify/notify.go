@@ -0,0 +1,145 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify 负责系统通知的筛选与分发：按用户设置过滤分类、窗口在前台时静默，并合并短时间内的重复通知。
+//
+// 实际发送由注入的 Sender 完成（应用中为 Wails 通知服务），各服务只需调用 Center.Notify。
+package notify
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Category 是通知分类，可在设置中单独开关。
+type Category string
+
+const (
+	CategoryExport     Category = "export"     // 导出完成或失败
+	CategoryImport     Category = "import"     // 导入完成或失败
+	CategoryScheduler  Category = "scheduler"  // 定时任务执行失败
+	CategoryConnection Category = "connection" // 连接中断
+)
+
+// DefaultDedupeWindow 内内容相同的通知只发送一次，避免重连等场景刷屏。
+const DefaultDedupeWindow = 30 * time.Second
+
+// Notification 是一条待发送的通知。
+type Notification struct {
+	Category Category               `json:"category"`
+	Title    string                 `json:"title"`
+	Body     string                 `json:"body,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"` // 点击通知时回传给前端的附加数据
+}
+
+// Sender 将通知发送到操作系统。
+type Sender func(n Notification) error
+
+// Center 按设置筛选通知并交给 Sender 发送，可并发使用。
+type Center struct {
+	mu         sync.Mutex
+	logger     *slog.Logger
+	settings   Settings
+	sender     Sender
+	foreground func() bool          // 应用窗口是否在前台
+	recent     map[string]time.Time // 最近发送的通知，用于去重
+	now        func() time.Time
+}
+
+// NewCenter 创建通知中心，初始使用默认设置，未设置 Sender 前通知会被丢弃。
+func NewCenter(logger *slog.Logger) *Center {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Center{
+		logger:   logger.With("module", "notify"),
+		settings: DefaultSettings(),
+		recent:   make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// SetSender 设置通知发送函数，传 nil 停止发送。
+func (c *Center) SetSender(sender Sender) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sender = sender
+}
+
+// SetForegroundCheck 设置判断应用是否在前台的函数，用于 OnlyInBackground 设置。
+func (c *Center) SetForegroundCheck(fn func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.foreground = fn
+}
+
+// Settings 返回当前设置的副本。
+func (c *Center) Settings() Settings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.settings.clone()
+}
+
+// SetSettings 替换当前设置。
+func (c *Center) SetSettings(settings Settings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings = settings.clone()
+}
+
+// Notify 按设置发送通知，返回是否实际发送；发送失败只记录日志。
+func (c *Center) Notify(n Notification) bool {
+	n.Title = strings.TrimSpace(n.Title)
+	n.Body = strings.TrimSpace(n.Body)
+	if n.Title == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	sender, foreground := c.sender, c.foreground
+	if sender == nil || !c.settings.Allows(n.Category) {
+		c.mu.Unlock()
+		return false
+	}
+	onlyInBackground := c.settings.OnlyInBackground
+	now := c.now()
+	key := string(n.Category) + "\x00" + n.Title + "\x00" + n.Body
+	if last, ok := c.recent[key]; ok && now.Sub(last) < DefaultDedupeWindow {
+		c.mu.Unlock()
+		return false
+	}
+	c.mu.Unlock()
+
+	// 前台判断可能需要同步调用界面线程，不能持锁执行
+	if onlyInBackground && foreground != nil && foreground() {
+		return false
+	}
+
+	c.mu.Lock()
+	for k, t := range c.recent {
+		if now.Sub(t) >= DefaultDedupeWindow {
+			delete(c.recent, k)
+		}
+	}
+	c.recent[key] = now
+	c.mu.Unlock()
+
+	if err := sender(n); err != nil {
+		c.logger.Warn("发送系统通知失败", "category", n.Category, "title", n.Title, "error", err)
+		return false
+	}
+	return true
+}
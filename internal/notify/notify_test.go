@@ -0,0 +1,133 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestCenter() (*Center, *[]Notification) {
+	var sent []Notification
+	c := NewCenter(nil)
+	c.SetSender(func(n Notification) error {
+		sent = append(sent, n)
+		return nil
+	})
+	return c, &sent
+}
+
+func TestNotifyFiltersBySettings(t *testing.T) {
+	c, sent := newTestCenter()
+	c.SetSettings(Settings{Enabled: true, Categories: map[Category]bool{CategoryConnection: false}})
+
+	if !c.Notify(Notification{Category: CategoryExport, Title: "导出完成"}) {
+		t.Error("未列出的分类应默认开启")
+	}
+	if c.Notify(Notification{Category: CategoryConnection, Title: "连接中断"}) {
+		t.Error("关闭的分类不应发送")
+	}
+	if c.Notify(Notification{Category: CategoryExport, Title: " "}) {
+		t.Error("空标题不应发送")
+	}
+
+	c.SetSettings(Settings{Enabled: false})
+	if c.Notify(Notification{Category: CategoryImport, Title: "导入完成"}) {
+		t.Error("总开关关闭时不应发送")
+	}
+	if len(*sent) != 1 {
+		t.Errorf("sent = %d, want 1", len(*sent))
+	}
+}
+
+func TestNotifyOnlyInBackground(t *testing.T) {
+	c, sent := newTestCenter()
+	focused := true
+	c.SetForegroundCheck(func() bool { return focused })
+
+	if c.Notify(Notification{Category: CategoryExport, Title: "导出完成"}) {
+		t.Error("窗口在前台时不应发送")
+	}
+	focused = false
+	if !c.Notify(Notification{Category: CategoryExport, Title: "导出完成"}) {
+		t.Error("窗口在后台时应发送")
+	}
+
+	c.SetSettings(Settings{Enabled: true, OnlyInBackground: false})
+	focused = true
+	if !c.Notify(Notification{Category: CategoryImport, Title: "导入完成"}) {
+		t.Error("关闭 OnlyInBackground 后前台也应发送")
+	}
+	if len(*sent) != 2 {
+		t.Errorf("sent = %d, want 2", len(*sent))
+	}
+}
+
+func TestNotifyDedupe(t *testing.T) {
+	c, sent := newTestCenter()
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	n := Notification{Category: CategoryConnection, Title: "SSH 隧道中断", Body: "db.example.com"}
+	c.Notify(n)
+	now = now.Add(10 * time.Second)
+	if c.Notify(n) {
+		t.Error("去重窗口内的重复通知不应发送")
+	}
+	if !c.Notify(Notification{Category: CategoryConnection, Title: "SSH 隧道中断", Body: "other"}) {
+		t.Error("内容不同的通知应发送")
+	}
+	now = now.Add(DefaultDedupeWindow)
+	if !c.Notify(n) {
+		t.Error("超过去重窗口后应再次发送")
+	}
+	if len(*sent) != 3 {
+		t.Errorf("sent = %d, want 3", len(*sent))
+	}
+}
+
+func TestNotifySenderError(t *testing.T) {
+	c := NewCenter(nil)
+	if c.Notify(Notification{Category: CategoryExport, Title: "导出完成"}) {
+		t.Error("未设置 Sender 时不应发送")
+	}
+	c.SetSender(func(Notification) error { return errors.New("denied") })
+	if c.Notify(Notification{Category: CategoryExport, Title: "导出完成"}) {
+		t.Error("发送失败应返回 false")
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	settings, err := LoadSettings(path)
+	if err != nil || !settings.Enabled || !settings.OnlyInBackground {
+		t.Fatalf("缺少文件时应返回默认设置: %+v, %v", settings, err)
+	}
+
+	settings.OnlyInBackground = false
+	settings.Categories[CategoryScheduler] = false
+	if err := SaveSettings(path, settings); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if loaded.OnlyInBackground || loaded.Allows(CategoryScheduler) || !loaded.Allows(CategoryExport) {
+		t.Errorf("加载的设置不符: %+v", loaded)
+	}
+}
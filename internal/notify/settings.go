@@ -0,0 +1,100 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings 是通知的用户设置。
+type Settings struct {
+	Enabled          bool              `json:"enabled"`          // 总开关
+	OnlyInBackground bool              `json:"onlyInBackground"` // 仅在应用窗口不在前台时通知
+	Categories       map[Category]bool `json:"categories"`       // 分类开关，未列出的分类默认开启
+}
+
+// DefaultSettings 返回默认设置：全部开启，窗口在前台时由界面内提示代替系统通知。
+func DefaultSettings() Settings {
+	return Settings{Enabled: true, OnlyInBackground: true, Categories: map[Category]bool{}}
+}
+
+// Allows 判断分类是否允许发送。
+func (s Settings) Allows(category Category) bool {
+	if !s.Enabled {
+		return false
+	}
+	enabled, ok := s.Categories[category]
+	return !ok || enabled
+}
+
+// clone 返回不共享分类表的副本。
+func (s Settings) clone() Settings {
+	c := s
+	c.Categories = make(map[Category]bool, len(s.Categories))
+	for k, v := range s.Categories {
+		c.Categories[k] = v
+	}
+	return c
+}
+
+// DefaultSettingsPath 返回默认设置文件路径。
+func DefaultSettingsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "notifications.json")
+	}
+	return filepath.Join(configDir, "Boxify", "notifications.json")
+}
+
+// LoadSettings 读取设置文件，文件不存在时返回默认设置。
+func LoadSettings(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return DefaultSettings(), fmt.Errorf("读取通知设置失败：%w", err)
+	}
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("解析通知设置失败：%w", err)
+	}
+	if settings.Categories == nil {
+		settings.Categories = map[Category]bool{}
+	}
+	return settings, nil
+}
+
+// SaveSettings 写入设置文件，先写临时文件再替换。
+func SaveSettings(path string, settings Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化通知设置失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入通知设置失败：%w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("写入通知设置失败：%w", err)
+	}
+	return nil
+}
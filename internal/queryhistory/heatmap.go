@@ -0,0 +1,164 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryhistory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 热力图时间粒度。
+const (
+	BucketHour = "hour"
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// HeatmapOptions 是表使用热力图的统计参数。
+type HeatmapOptions struct {
+	Connection string    // 仅统计该连接，为空表示全部连接
+	Since      time.Time // 统计起始时间
+	Until      time.Time // 统计结束时间，零值表示当前时间
+	Bucket     string    // 时间粒度：hour/day/week
+	Limit      int       // 最多返回的表数量，<=0 表示不限制
+}
+
+// UsageCell 是单个时间段内的读写次数。
+type UsageCell struct {
+	Reads  int `json:"reads"`
+	Writes int `json:"writes"`
+}
+
+// TableUsage 是单个表在统计区间内的读写频率。
+type TableUsage struct {
+	Table  string      `json:"table"`  // 表名（database.table）
+	Reads  int         `json:"reads"`  // 读次数合计
+	Writes int         `json:"writes"` // 写次数合计
+	Cells  []UsageCell `json:"cells"`  // 按时间段的读写次数，与 Heatmap.Buckets 对齐
+}
+
+// Heatmap 是按表与时间段统计的使用热力图。
+type Heatmap struct {
+	Bucket  string        `json:"bucket"`  // 时间粒度
+	Buckets []time.Time   `json:"buckets"` // 各时间段的起始时间
+	Tables  []*TableUsage `json:"tables"`  // 按读写总次数降序排列
+	Queries int           `json:"queries"` // 参与统计的语句数
+}
+
+// BuildHeatmap 统计成功执行的语句中各表在每个时间段的读写次数。
+func BuildHeatmap(entries []Entry, opts HeatmapOptions) (*Heatmap, error) {
+	if opts.Bucket == "" {
+		opts.Bucket = BucketDay
+	}
+	if opts.Bucket != BucketHour && opts.Bucket != BucketDay && opts.Bucket != BucketWeek {
+		return nil, fmt.Errorf("不支持的时间粒度: %s", opts.Bucket)
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	hm := &Heatmap{Bucket: opts.Bucket, Tables: []*TableUsage{}}
+	for t := truncateBucket(opts.Since, opts.Bucket); !t.After(until); t = nextBucket(t, opts.Bucket) {
+		hm.Buckets = append(hm.Buckets, t)
+	}
+	if len(hm.Buckets) == 0 {
+		return hm, nil
+	}
+	index := make(map[time.Time]int, len(hm.Buckets))
+	for i, t := range hm.Buckets {
+		index[t] = i
+	}
+
+	usage := make(map[string]*TableUsage)
+	for _, e := range entries {
+		if !e.Success || e.Time.Before(opts.Since) || e.Time.After(until) {
+			continue
+		}
+		if opts.Connection != "" && e.Connection != opts.Connection {
+			continue
+		}
+		pos, ok := index[truncateBucket(e.Time, opts.Bucket)]
+		if !ok || len(e.Tables) == 0 {
+			continue
+		}
+		hm.Queries++
+		for _, ref := range e.Tables {
+			name := qualifyTable(e.Database, ref.Name)
+			u := usage[name]
+			if u == nil {
+				u = &TableUsage{Table: name, Cells: make([]UsageCell, len(hm.Buckets))}
+				usage[name] = u
+				hm.Tables = append(hm.Tables, u)
+			}
+			if ref.Write {
+				u.Writes++
+				u.Cells[pos].Writes++
+			} else {
+				u.Reads++
+				u.Cells[pos].Reads++
+			}
+		}
+	}
+
+	sort.SliceStable(hm.Tables, func(i, j int) bool {
+		ti, tj := hm.Tables[i].Reads+hm.Tables[i].Writes, hm.Tables[j].Reads+hm.Tables[j].Writes
+		if ti != tj {
+			return ti > tj
+		}
+		return hm.Tables[i].Table < hm.Tables[j].Table
+	})
+	if opts.Limit > 0 && len(hm.Tables) > opts.Limit {
+		hm.Tables = hm.Tables[:opts.Limit]
+	}
+	return hm, nil
+}
+
+// qualifyTable 为未带 schema 的表名补全执行时的数据库名。
+func qualifyTable(database, table string) string {
+	if database == "" || strings.Contains(table, ".") {
+		return table
+	}
+	return database + "." + table
+}
+
+// truncateBucket 返回 t 所在时间段的起始时间（本地时区，周以周一为起点）。
+func truncateBucket(t time.Time, bucket string) time.Time {
+	t = t.In(time.Local)
+	switch bucket {
+	case BucketHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+	case BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+}
+
+// nextBucket 返回下一个时间段的起始时间。
+func nextBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketHour:
+		return t.Add(time.Hour)
+	case BucketWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
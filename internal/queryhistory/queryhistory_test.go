@@ -0,0 +1,103 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryhistory
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractTables(t *testing.T) {
+	cases := []struct {
+		query string
+		want  []TableRef
+	}{
+		{"SELECT * FROM users u JOIN `shop`.`orders` o ON o.uid = u.id", []TableRef{{Name: "users"}, {Name: "shop.orders"}}},
+		{"select a from t1, t2 as x where y in (select id from t3)", []TableRef{{Name: "t1"}, {Name: "t2"}, {Name: "t3"}}},
+		{"INSERT INTO logs (msg) SELECT msg FROM staging", []TableRef{{Name: "logs", Write: true}, {Name: "staging"}}},
+		{"UPDATE users SET name = 'from x' WHERE id = 1", []TableRef{{Name: "users", Write: true}}},
+		{"DELETE FROM sessions WHERE expired -- from comment", []TableRef{{Name: "sessions", Write: true}}},
+		{`DROP TABLE IF EXISTS "old""tbl"`, []TableRef{{Name: `old"tbl`, Write: true}}},
+		{"SELECT 1", nil},
+	}
+	for _, c := range cases {
+		if got := ExtractTables(c.query); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: 期望 %+v，得到 %+v", c.query, c.want, got)
+		}
+	}
+}
+
+func TestStoreRecordAndCompact(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"), 4, nil)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		if err := store.Record(Entry{Time: base.Add(time.Duration(i) * time.Minute), Query: "SELECT * FROM t", Success: true}); err != nil {
+			t.Fatalf("记录失败: %v", err)
+		}
+	}
+	entries, err := store.Entries(time.Time{})
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(entries) != 2 || !entries[1].Time.Equal(base.Add(4*time.Minute)) {
+		t.Fatalf("超过上限后应只保留最近一半: %+v", entries)
+	}
+	if len(entries[0].Tables) != 1 || entries[0].Tables[0].Name != "t" {
+		t.Errorf("记录时应识别表: %+v", entries[0].Tables)
+	}
+
+	recent, _ := store.Entries(base.Add(4 * time.Minute))
+	if len(recent) != 1 {
+		t.Errorf("按时间过滤错误: %d", len(recent))
+	}
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	day := time.Date(2026, 5, 4, 10, 0, 0, 0, time.Local) // 周一
+	entries := []Entry{
+		{Time: day, Connection: "a", Database: "shop", Success: true, Tables: []TableRef{{Name: "users"}}},
+		{Time: day.Add(time.Hour), Connection: "a", Database: "shop", Success: true, Tables: []TableRef{{Name: "users", Write: true}, {Name: "crm.leads"}}},
+		{Time: day.AddDate(0, 0, 1), Connection: "a", Database: "shop", Success: true, Tables: []TableRef{{Name: "users"}}},
+		{Time: day.AddDate(0, 0, 1), Connection: "a", Database: "shop", Success: false, Tables: []TableRef{{Name: "users"}}},
+		{Time: day, Connection: "b", Success: true, Tables: []TableRef{{Name: "other"}}},
+	}
+	hm, err := BuildHeatmap(entries, HeatmapOptions{Connection: "a", Since: day, Until: day.AddDate(0, 0, 2), Bucket: BucketDay})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if len(hm.Buckets) != 3 || hm.Queries != 3 || len(hm.Tables) != 2 {
+		t.Fatalf("热力图结构错误: %+v", hm)
+	}
+	users := hm.Tables[0]
+	if users.Table != "shop.users" || users.Reads != 2 || users.Writes != 1 {
+		t.Errorf("表统计错误: %+v", users)
+	}
+	if users.Cells[0] != (UsageCell{Reads: 1, Writes: 1}) || users.Cells[1] != (UsageCell{Reads: 1}) {
+		t.Errorf("时间段统计错误: %+v", users.Cells)
+	}
+	if hm.Tables[1].Table != "crm.leads" {
+		t.Errorf("带 schema 的表名不应补全: %s", hm.Tables[1].Table)
+	}
+
+	week, _ := BuildHeatmap(entries, HeatmapOptions{Since: day.AddDate(0, 0, 2), Until: day.AddDate(0, 0, 2), Bucket: BucketWeek})
+	if len(week.Buckets) != 1 || !week.Buckets[0].Equal(time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("周粒度应以周一为起点: %v", week.Buckets)
+	}
+	if _, err := BuildHeatmap(nil, HeatmapOptions{Bucket: "minute"}); err == nil {
+		t.Error("不支持的粒度应返回错误")
+	}
+}
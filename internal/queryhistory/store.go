@@ -0,0 +1,231 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+const (
+	// DefaultMaxEntries 历史文件保留的最大记录数，超过后压缩为最近的一半。
+	DefaultMaxEntries = 50000
+	// maxQueryLength 记录中保存的语句最大长度。
+	maxQueryLength = 2000
+)
+
+// Entry 是一条查询历史记录。
+type Entry struct {
+	Time       time.Time  `json:"time"`               // 执行时间
+	Connection string     `json:"connection"`         // 连接标识（不含凭据）
+	Database   string     `json:"database,omitempty"` // 执行时的数据库
+	Query      string     `json:"query"`              // 语句（超长时截断）
+	Tables     []TableRef `json:"tables,omitempty"`   // 语句读写的表
+	Success    bool       `json:"success"`            // 是否执行成功
+	DurationMs int64      `json:"durationMs"`         // 耗时（毫秒）
+}
+
+// ConnectionKey 返回用于归类历史记录的连接标识，不包含密码等凭据。
+func ConnectionKey(config *connection.ConnectionConfig) string {
+	if config == nil {
+		return ""
+	}
+	dbType := string(config.Type)
+	if dbType == "" {
+		dbType = string(connection.ConnectionTypeMySQL)
+	}
+	return fmt.Sprintf("%s://%s@%s:%d", dbType, config.User, config.Host, config.Port)
+}
+
+// Store 以 JSON Lines 文件追加保存查询历史。
+type Store struct {
+	mu         sync.Mutex
+	path       string       // 历史文件路径
+	maxEntries int          // 最大记录数
+	count      int          // 当前记录数，-1 表示尚未统计
+	logger     *slog.Logger // 日志记录器
+}
+
+// DefaultPath 返回默认查询历史文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "query-history.jsonl")
+	}
+	return filepath.Join(configDir, "Boxify", "query-history.jsonl")
+}
+
+// NewStore 创建查询历史存储，path 为空时使用默认路径，maxEntries<=0 时使用默认上限。
+func NewStore(path string, maxEntries int, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{path: path, maxEntries: maxEntries, count: -1, logger: logger.With("module", "queryhistory")}
+}
+
+// Record 追加一条历史记录，自动识别语句涉及的表。
+func (s *Store) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Tables == nil {
+		entry.Tables = ExtractTables(entry.Query)
+	}
+	if len(entry.Query) > maxQueryLength {
+		entry.Query = entry.Query[:maxQueryLength]
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化查询历史失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建查询历史目录失败：%w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("写入查询历史失败：%w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("写入查询历史失败：%w", err)
+	}
+
+	if s.count < 0 {
+		entries, err := s.readLocked()
+		if err != nil {
+			return err
+		}
+		s.count = len(entries)
+	} else {
+		s.count++
+	}
+	if s.count > s.maxEntries {
+		return s.compactLocked()
+	}
+	return nil
+}
+
+// Entries 返回 since 之后的历史记录（since 为零值时返回全部），按时间顺序。
+func (s *Store) Entries(since time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	if since.IsZero() {
+		return entries, nil
+	}
+	out := entries[:0]
+	for _, e := range entries {
+		if !e.Time.Before(since) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// readLocked 读取全部记录，跳过损坏的行；调用方需持有锁。
+func (s *Store) readLocked() ([]Entry, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取查询历史失败：%w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	skipped := 0
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取查询历史失败：%w", err)
+	}
+	if skipped > 0 {
+		s.logger.Warn("跳过损坏的查询历史记录", "path", s.path, "count", skipped)
+	}
+	return entries, nil
+}
+
+// compactLocked 仅保留最近一半的记录；调用方需持有锁。
+func (s *Store) compactLocked() error {
+	entries, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	keep := entries[max(0, len(entries)-s.maxEntries/2):]
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("压缩查询历史失败：%w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range keep {
+		if err = enc.Encode(e); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("压缩查询历史失败：%w", err)
+	}
+	s.count = len(keep)
+	s.logger.Info("查询历史已压缩", "kept", len(keep), "dropped", len(entries)-len(keep))
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryhistory
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+)
+
+// TableRef 是从 SQL 中识别出的表引用。
+type TableRef struct {
+	Name  string `json:"name"`  // 表名，带 schema 时为 schema.table
+	Write bool   `json:"write"` // 是否为写操作（INSERT/UPDATE/DELETE/REPLACE/MERGE/DDL 目标表）
+}
+
+// ExtractTables 以轻量词法分析识别语句读写的表：FROM/JOIN 之后为读，INTO/UPDATE/DDL 之后为写。
+// 字符串、注释与子查询括号会被正确跳过，结果按首次出现顺序去重。
+func ExtractTables(query string) []TableRef {
+	tokens := tokenizeSQL(query)
+	var refs []TableRef
+	seen := make(map[TableRef]bool)
+	add := func(name string, write bool) {
+		ref := TableRef{Name: name, Write: write}
+		if name == "" || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+
+	statementWrite := false // 当前语句为 DELETE 时，FROM 之后的表为写目标
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind != tokenWord {
+			if tok.text == ";" {
+				statementWrite = false
+			}
+			continue
+		}
+
+		switch strings.ToUpper(tok.text) {
+		case "DELETE":
+			statementWrite = true
+		case "FROM", "JOIN":
+			write := statementWrite && strings.EqualFold(tok.text, "FROM")
+			statementWrite = false
+			for j := i + 1; j < len(tokens); {
+				name, next := readTableName(tokens, j)
+				if name == "" {
+					break
+				}
+				add(name, write)
+				// FROM a, b 形式的多表列表：跳过别名后继续读取逗号后的表
+				next = skipAlias(tokens, next)
+				if next < len(tokens) && tokens[next].text == "," {
+					j = next + 1
+					continue
+				}
+				break
+			}
+		case "INTO", "UPDATE":
+			if name, _ := readTableName(tokens, i+1); name != "" {
+				add(name, true)
+			}
+		case "TABLE":
+			// CREATE/ALTER/DROP/TRUNCATE TABLE [IF [NOT] EXISTS] name
+			j := i + 1
+			for j < len(tokens) && tokens[j].kind == tokenWord && isKeyword(tokens[j].text, "IF", "NOT", "EXISTS") {
+				j++
+			}
+			if name, _ := readTableName(tokens, j); name != "" {
+				add(name, true)
+			}
+		}
+	}
+	return refs
+}
+
+// readTableName 从 tokens[i] 开始读取可能带 schema 前缀的表名，返回表名与下一个位置。
+func readTableName(tokens []sqlToken, i int) (string, int) {
+	var parts []string
+	for i < len(tokens) {
+		tok := tokens[i]
+		if tok.kind != tokenWord && tok.kind != tokenQuoted {
+			break
+		}
+		if tok.kind == tokenWord && isReservedAfterTable(tok.text) {
+			break
+		}
+		parts = append(parts, tok.text)
+		i++
+		if i < len(tokens) && tokens[i].text == "." {
+			i++
+			continue
+		}
+		break
+	}
+	return strings.Join(parts, "."), i
+}
+
+// skipAlias 跳过表名后的 [AS] alias。
+func skipAlias(tokens []sqlToken, i int) int {
+	if i < len(tokens) && tokens[i].kind == tokenWord && strings.EqualFold(tokens[i].text, "AS") {
+		i++
+	}
+	if i < len(tokens) && (tokens[i].kind == tokenQuoted || (tokens[i].kind == tokenWord && !isReservedAfterTable(tokens[i].text))) {
+		i++
+	}
+	return i
+}
+
+// isReservedAfterTable 判断单词是否为可能紧跟在表名位置的关键字（此时不是表名或别名）。
+func isReservedAfterTable(word string) bool {
+	return isKeyword(word, "SELECT", "WHERE", "SET", "VALUES", "VALUE", "ON", "USING", "GROUP", "ORDER", "LIMIT",
+		"HAVING", "UNION", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "OUTER", "CROSS", "NATURAL", "WITH", "AS",
+		"FOR", "INTO", "DEFAULT", "PARTITION", "WINDOW", "OFFSET", "FETCH", "RETURNING", "LATERAL", "STRAIGHT_JOIN")
+}
+
+// isKeyword 判断单词是否为给定关键字之一（忽略大小写）。
+func isKeyword(word string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(word, k) {
+			return true
+		}
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // 未引用的标识符或关键字
+	tokenQuoted                  // 引用的标识符（反引号、双引号或方括号）
+	tokenSymbol                  // 标点与运算符
+)
+
+// sqlToken 是 SQL 词法单元，字符串字面量与注释不产生 token。
+type sqlToken struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizeSQL 将 SQL 切分为词法单元，忽略字符串、数字与注释。
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '\'':
+			i = sqllint.SkipQuoted(runes, i, '\'', true)
+		case r == '`' || r == '"' || r == '[':
+			closer := r
+			if r == '[' {
+				closer = ']'
+			}
+			end := sqllint.SkipQuoted(runes, i, closer, false)
+			text := string(runes[i+1 : max(i+1, end-1)])
+			text = strings.ReplaceAll(text, string(closer)+string(closer), string(closer))
+			tokens = append(tokens, sqlToken{kind: tokenQuoted, text: text})
+			i = end
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenWord, text: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+		default:
+			tokens = append(tokens, sqlToken{kind: tokenSymbol, text: string(r)})
+			i++
+		}
+	}
+	return tokens
+}
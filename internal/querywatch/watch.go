@@ -0,0 +1,330 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querywatch 按固定间隔重复执行查询，与上一次结果比较后只推送变化的行，用于监控队列表、任务状态等。
+package querywatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+	"github.com/google/uuid"
+)
+
+const (
+	// MinInterval 是允许的最小刷新间隔。
+	MinInterval = time.Second
+	// MaxInterval 是允许的最大刷新间隔。
+	MaxInterval = time.Hour
+	// DefaultMaxWatches 默认同时运行的监视上限（每个监视周期性占用一个数据库连接）。
+	DefaultMaxWatches = 16
+	// DefaultMaxFailures 连续失败达到该次数后自动停止监视。
+	DefaultMaxFailures = 5
+)
+
+var (
+	// ErrWatchNotFound 监视不存在或已停止。
+	ErrWatchNotFound = errors.New("监视不存在或已停止")
+	// ErrTooManyWatches 同时运行的监视数量已达上限。
+	ErrTooManyWatches = errors.New("同时运行的监视数量已达上限")
+)
+
+// UpdateKind 是推送的更新类型。
+type UpdateKind string
+
+const (
+	UpdateSnapshot UpdateKind = "snapshot" // 首次执行的完整结果
+	UpdateChanges  UpdateKind = "changes"  // 与上一次结果相比的变化
+	UpdateError    UpdateKind = "error"    // 本次执行失败，保留上一次结果
+	UpdateStopped  UpdateKind = "stopped"  // 监视已停止
+)
+
+// Fetcher 执行一次查询并返回结果集，truncated 表示结果超过读取上限。
+type Fetcher func(ctx context.Context) (input resultdiff.Input, truncated bool, err error)
+
+// Options 是单个监视的参数。
+type Options struct {
+	Label      string        // 显示名称，通常为 SQL 片段
+	Interval   time.Duration // 刷新间隔
+	KeyColumns []string      // 用于匹配行的键列；为空时按整行内容匹配，变化表现为删除旧行并新增新行
+}
+
+// Info 是监视的当前状态。
+type Info struct {
+	ID         string    `json:"id"`
+	Label      string    `json:"label"`
+	IntervalMs int64     `json:"intervalMs"`
+	KeyColumns []string  `json:"keyColumns"`
+	StartedAt  time.Time `json:"startedAt"`
+	LastRunAt  time.Time `json:"lastRunAt,omitempty"`
+	Runs       int       `json:"runs"`     // 已执行次数
+	Failures   int       `json:"failures"` // 连续失败次数
+}
+
+// Update 是推送给监听者的一次更新。
+type Update struct {
+	WatchID   string                   `json:"watchId"`
+	Seq       int                      `json:"seq"` // 执行序号，从 1 开始
+	Kind      UpdateKind               `json:"kind"`
+	At        time.Time                `json:"at"`
+	Columns   []string                 `json:"columns,omitempty"`
+	Rows      []map[string]interface{} `json:"rows,omitempty"`      // 仅 snapshot
+	Diff      *resultdiff.Result       `json:"diff,omitempty"`      // 仅 changes
+	Truncated bool                     `json:"truncated,omitempty"` // 结果超过读取上限，只比较了前部分行
+	Error     string                   `json:"error,omitempty"`     // error / stopped 的原因
+}
+
+// Listener 接收监视更新。
+type Listener func(Update)
+
+// watch 是一个运行中的监视。
+type watch struct {
+	info    Info
+	fetch   Fetcher
+	cancel  context.CancelFunc
+	last    *resultdiff.Input
+	stopped bool
+}
+
+// Manager 管理运行中的监视，可并发使用。
+type Manager struct {
+	mu          sync.Mutex
+	logger      *slog.Logger
+	watches     map[string]*watch
+	listener    Listener
+	maxWatches  int
+	maxFailures int
+	wg          sync.WaitGroup
+	now         func() time.Time
+}
+
+// NewManager 创建监视管理器。
+func NewManager(logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{
+		logger:      logger.With("module", "querywatch"),
+		watches:     make(map[string]*watch),
+		maxWatches:  DefaultMaxWatches,
+		maxFailures: DefaultMaxFailures,
+		now:         time.Now,
+	}
+}
+
+// SetListener 设置更新监听者。
+func (m *Manager) SetListener(l Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listener = l
+}
+
+// Start 启动监视：立即执行一次并推送 snapshot，之后按间隔执行并只在结果变化时推送 changes。
+func (m *Manager) Start(fetch Fetcher, opts Options) (Info, error) {
+	if opts.Interval < MinInterval || opts.Interval > MaxInterval {
+		return Info{}, fmt.Errorf("刷新间隔需在 %s 到 %s 之间", MinInterval, MaxInterval)
+	}
+
+	m.mu.Lock()
+	if len(m.watches) >= m.maxWatches {
+		m.mu.Unlock()
+		return Info{}, ErrTooManyWatches
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &watch{
+		info: Info{
+			ID:         uuid.NewString(),
+			Label:      opts.Label,
+			IntervalMs: opts.Interval.Milliseconds(),
+			KeyColumns: append([]string(nil), opts.KeyColumns...),
+			StartedAt:  m.now(),
+		},
+		fetch:  fetch,
+		cancel: cancel,
+	}
+	m.watches[w.info.ID] = w
+	info := w.info
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go m.loop(ctx, w, opts.Interval)
+	m.logger.Info("开始监视查询", "watchId", info.ID, "label", info.Label, "interval", opts.Interval)
+	return info, nil
+}
+
+// Stop 停止监视，执行中的查询会被取消。
+func (m *Manager) Stop(id string) error {
+	m.mu.Lock()
+	w, ok := m.watches[id]
+	m.mu.Unlock()
+	if !ok {
+		return ErrWatchNotFound
+	}
+	m.stop(w, "")
+	return nil
+}
+
+// StopAll 停止全部监视并等待执行中的查询返回。
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	all := make([]*watch, 0, len(m.watches))
+	for _, w := range m.watches {
+		all = append(all, w)
+	}
+	m.mu.Unlock()
+	for _, w := range all {
+		m.stop(w, "")
+	}
+	m.wg.Wait()
+}
+
+// List 返回运行中的监视，按启动时间排序。
+func (m *Manager) List() []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Info, 0, len(m.watches))
+	for _, w := range m.watches {
+		list = append(list, w.info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].StartedAt.Before(list[j].StartedAt) })
+	return list
+}
+
+// loop 按间隔执行查询直到监视停止。
+func (m *Manager) loop(ctx context.Context, w *watch, interval time.Duration) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.runOnce(ctx, w)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 执行一次查询，与上一次结果比较并推送更新。
+func (m *Manager) runOnce(ctx context.Context, w *watch) {
+	input, truncated, err := w.fetch(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	if w.stopped {
+		m.mu.Unlock()
+		return
+	}
+	w.info.Runs++
+	w.info.LastRunAt = m.now()
+	update := Update{WatchID: w.info.ID, Seq: w.info.Runs, At: w.info.LastRunAt, Truncated: truncated}
+	var diff *resultdiff.Result
+	if err == nil && w.last == nil {
+		if missing := missingColumn(w.info.KeyColumns, input.Columns); missing != "" {
+			m.mu.Unlock()
+			m.stop(w, fmt.Sprintf("结果集缺少键列 %s，已停止监视", missing))
+			return
+		}
+	}
+	if err == nil && w.last != nil {
+		diff, err = resultdiff.Diff(*w.last, input, diffKeys(w.info.KeyColumns, input.Columns), 0)
+	}
+	if err != nil {
+		w.info.Failures++
+		failures := w.info.Failures
+		m.mu.Unlock()
+
+		m.logger.Warn("监视查询执行失败", "watchId", w.info.ID, "failures", failures, "error", err)
+		update.Kind, update.Error = UpdateError, err.Error()
+		m.emit(update)
+		if failures >= m.maxFailures {
+			m.stop(w, fmt.Sprintf("连续失败 %d 次，已停止监视：%v", failures, err))
+		}
+		return
+	}
+	w.info.Failures = 0
+	first := w.last == nil
+	w.last = &input
+	m.mu.Unlock()
+
+	switch {
+	case first:
+		update.Kind, update.Columns, update.Rows = UpdateSnapshot, input.Columns, input.Rows
+	case diff.AddedCount+diff.RemovedCount+diff.ChangedCount > 0:
+		update.Kind, update.Columns, update.Diff = UpdateChanges, input.Columns, diff
+	default:
+		return
+	}
+	m.emit(update)
+}
+
+// stop 取消监视并推送 stopped，reason 为空表示主动停止。
+func (m *Manager) stop(w *watch, reason string) {
+	m.mu.Lock()
+	if w.stopped {
+		m.mu.Unlock()
+		return
+	}
+	w.stopped = true
+	delete(m.watches, w.info.ID)
+	update := Update{WatchID: w.info.ID, Seq: w.info.Runs, Kind: UpdateStopped, At: m.now(), Error: reason}
+	m.mu.Unlock()
+
+	w.cancel()
+	m.logger.Info("停止监视查询", "watchId", w.info.ID, "reason", reason)
+	m.emit(update)
+}
+
+// emit 将更新交给监听者。
+func (m *Manager) emit(u Update) {
+	m.mu.Lock()
+	l := m.listener
+	m.mu.Unlock()
+	if l != nil {
+		l(u)
+	}
+}
+
+// diffKeys 返回比较使用的键列，未指定时使用全部列。
+func diffKeys(keyColumns, columns []string) []string {
+	if len(keyColumns) > 0 {
+		return keyColumns
+	}
+	return columns
+}
+
+// missingColumn 返回 columns 中不存在的第一个键列，全部存在时返回空字符串。
+func missingColumn(keyColumns, columns []string) string {
+	for _, key := range keyColumns {
+		found := false
+		for _, col := range columns {
+			if col == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querywatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+)
+
+// recorder 收集推送的更新。
+type recorder struct {
+	mu      sync.Mutex
+	updates []Update
+}
+
+func (r *recorder) listen(u Update) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates = append(r.updates, u)
+}
+
+func (r *recorder) snapshot() []Update {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Update(nil), r.updates...)
+}
+
+// sequence 依次返回预设的结果，用尽后重复最后一个。
+func sequence(results ...func() (resultdiff.Input, error)) Fetcher {
+	var mu sync.Mutex
+	i := 0
+	return func(ctx context.Context) (resultdiff.Input, bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn := results[i]
+		if i < len(results)-1 {
+			i++
+		}
+		in, err := fn()
+		return in, false, err
+	}
+}
+
+func rows(statuses ...string) func() (resultdiff.Input, error) {
+	return func() (resultdiff.Input, error) {
+		in := resultdiff.Input{Columns: []string{"id", "status"}}
+		for i, s := range statuses {
+			in.Rows = append(in.Rows, map[string]interface{}{"id": int64(i + 1), "status": s})
+		}
+		return in, nil
+	}
+}
+
+func newTestManager() (*Manager, *recorder) {
+	m := NewManager(nil)
+	r := &recorder{}
+	m.SetListener(r.listen)
+	return m, r
+}
+
+func TestRunOnceEmitsSnapshotThenOnlyChanges(t *testing.T) {
+	m, r := newTestManager()
+	w := &watch{
+		info:   Info{ID: "w1", KeyColumns: []string{"id"}},
+		fetch:  sequence(rows("queued", "queued"), rows("queued", "queued"), rows("done", "queued", "queued")),
+		cancel: func() {},
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		m.runOnce(ctx, w)
+	}
+
+	updates := r.snapshot()
+	if len(updates) != 2 {
+		t.Fatalf("应推送 snapshot 与一次 changes，实际 %d 条: %+v", len(updates), updates)
+	}
+	if updates[0].Kind != UpdateSnapshot || len(updates[0].Rows) != 2 {
+		t.Errorf("首次推送不符: %+v", updates[0])
+	}
+	diff := updates[1].Diff
+	if updates[1].Kind != UpdateChanges || updates[1].Seq != 3 || diff.ChangedCount != 1 || diff.AddedCount != 1 {
+		t.Errorf("变化推送不符: %+v", updates[1])
+	}
+}
+
+func TestRunOnceWithoutKeysMatchesWholeRows(t *testing.T) {
+	m, r := newTestManager()
+	w := &watch{info: Info{ID: "w1"}, fetch: sequence(rows("queued"), rows("done")), cancel: func() {}}
+	m.runOnce(context.Background(), w)
+	m.runOnce(context.Background(), w)
+
+	updates := r.snapshot()
+	if len(updates) != 2 || updates[1].Diff.AddedCount != 1 || updates[1].Diff.RemovedCount != 1 {
+		t.Errorf("未指定键列时应表现为删除旧行并新增新行: %+v", updates)
+	}
+}
+
+func TestRunOnceStopsAfterRepeatedFailures(t *testing.T) {
+	m, r := newTestManager()
+	m.maxFailures = 2
+	info, err := m.Start(sequence(func() (resultdiff.Input, error) { return resultdiff.Input{}, errors.New("boom") }), Options{Interval: MinInterval})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.List()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	m.wg.Wait()
+	updates := r.snapshot()
+	if len(updates) != 3 || updates[0].Kind != UpdateError || updates[2].Kind != UpdateStopped || updates[2].Error == "" {
+		t.Fatalf("应推送两次 error 后 stopped: %+v", updates)
+	}
+	if err := m.Stop(info.ID); !errors.Is(err, ErrWatchNotFound) {
+		t.Errorf("已停止的监视应返回 ErrWatchNotFound，得到 %v", err)
+	}
+}
+
+func TestStartValidatesAndStops(t *testing.T) {
+	m, r := newTestManager()
+	if _, err := m.Start(sequence(rows("a")), Options{Interval: time.Millisecond}); err == nil {
+		t.Error("过短的间隔应返回错误")
+	}
+
+	info, err := m.Start(sequence(rows("a")), Options{Interval: time.Minute, KeyColumns: []string{"missing"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	m.wg.Wait()
+	updates := r.snapshot()
+	if len(updates) != 1 || updates[0].WatchID != info.ID || updates[0].Kind != UpdateStopped {
+		t.Fatalf("缺少键列时应直接停止: %+v", updates)
+	}
+
+	m.maxWatches = 1
+	if _, err := m.Start(sequence(rows("a")), Options{Interval: time.Minute}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := m.Start(sequence(rows("a")), Options{Interval: time.Minute}); !errors.Is(err, ErrTooManyWatches) {
+		t.Errorf("超过上限应返回 ErrTooManyWatches，得到 %v", err)
+	}
+	m.StopAll()
+	if len(m.List()) != 0 {
+		t.Error("StopAll 后不应有运行中的监视")
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recents 记录最近使用的连接、打开的数据表与执行过的 SQL 文件（含使用时间、次数与置顶），
+// 为启动页的“继续上次工作”提供数据；记录保存在用户配置目录下的 JSON 文件中。
+package recents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind 最近使用记录的类型。
+type Kind string
+
+const (
+	KindConnection Kind = "connection" // 连接
+	KindTable      Kind = "table"      // 数据表
+	KindFile       Kind = "file"       // 执行过的 SQL 文件
+)
+
+// MaxPerKind 每种类型保留的未置顶记录上限，超出时丢弃最久未使用的记录。
+const MaxPerKind = 50
+
+// ErrItemNotFound 记录不存在。
+var ErrItemNotFound = errors.New("最近使用记录不存在")
+
+// Item 是一条最近使用记录，Key 由类型相关字段生成，同一对象重复使用时合并为一条。
+type Item struct {
+	Kind         Kind      `json:"kind"`
+	Key          string    `json:"key"`
+	Title        string    `json:"title"`                  // 显示名称，如连接名、表名、文件名
+	ConnectionID string    `json:"connectionId,omitempty"` // 前端保存的连接 ID
+	DBType       string    `json:"dbType,omitempty"`       // 数据库类型
+	Database     string    `json:"database,omitempty"`
+	Schema       string    `json:"schema,omitempty"`
+	Table        string    `json:"table,omitempty"`
+	Path         string    `json:"path,omitempty"` // SQL 文件路径
+	Pinned       bool      `json:"pinned"`
+	UseCount     int       `json:"useCount"`
+	LastUsedAt   time.Time `json:"lastUsedAt"`
+}
+
+// Overview 是启动页使用的最近记录汇总，每类按置顶优先、最近使用排序。
+type Overview struct {
+	Connections []Item `json:"connections"`
+	Tables      []Item `json:"tables"`
+	Files       []Item `json:"files"`
+}
+
+// Store 保存最近使用记录，可并发使用。
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	logger   *slog.Logger
+	items    map[string]*Item // storeKey -> 记录
+	now      func() time.Time
+	onChange []func(kind Kind)
+}
+
+// DefaultPath 返回默认的记录文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "recents.json")
+	}
+	return filepath.Join(configDir, "Boxify", "recents.json")
+}
+
+// NewStore 创建记录存储，path 为空时使用默认路径；需调用 Load 读取已保存的记录。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		path:   path,
+		logger: logger.With("module", "recents"),
+		items:  make(map[string]*Item),
+		now:    time.Now,
+	}
+}
+
+// OnChange 注册记录变化回调，回调在锁外同步执行。
+func (s *Store) OnChange(fn func(kind Kind)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Load 读取记录文件，文件不存在时视为空。
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取最近使用记录失败：%w", err)
+	}
+	var list []*Item
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析最近使用记录失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = make(map[string]*Item, len(list))
+	for _, it := range list {
+		if it == nil || ValidKind(it.Kind) != nil || it.Key == "" {
+			continue
+		}
+		s.items[storeKey(it.Kind, it.Key)] = it
+	}
+	return nil
+}
+
+// Touch 记录一次使用：已有记录时累加次数并更新显示信息，否则新建；返回更新后的记录。
+func (s *Store) Touch(item Item) (*Item, error) {
+	key, err := KeyOf(item)
+	if err != nil {
+		return nil, err
+	}
+	item.Key = key
+	if item.Kind == KindFile {
+		item.Path = filepath.Clean(item.Path)
+		if strings.TrimSpace(item.Title) == "" {
+			item.Title = filepath.Base(item.Path)
+		}
+	}
+	if strings.TrimSpace(item.Title) == "" {
+		return nil, errors.New("记录标题不能为空")
+	}
+
+	s.mu.Lock()
+	sk := storeKey(item.Kind, key)
+	if old, ok := s.items[sk]; ok {
+		item.Pinned = old.Pinned
+		item.UseCount = old.UseCount
+	}
+	item.UseCount++
+	item.LastUsedAt = s.now()
+	saved := item
+	s.items[sk] = &saved
+	s.trimLocked(item.Kind)
+	err = s.persistLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.notify(item.Kind)
+	return &saved, nil
+}
+
+// Pin 置顶或取消置顶记录，置顶的记录不会因超出上限被丢弃。
+func (s *Store) Pin(kind Kind, key string, pinned bool) (*Item, error) {
+	s.mu.Lock()
+	it, ok := s.items[storeKey(kind, key)]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrItemNotFound
+	}
+	it.Pinned = pinned
+	if !pinned {
+		s.trimLocked(kind)
+	}
+	saved := *it
+	err := s.persistLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.notify(kind)
+	return &saved, nil
+}
+
+// Remove 删除一条记录。
+func (s *Store) Remove(kind Kind, key string) error {
+	s.mu.Lock()
+	sk := storeKey(kind, key)
+	if _, ok := s.items[sk]; !ok {
+		s.mu.Unlock()
+		return ErrItemNotFound
+	}
+	delete(s.items, sk)
+	err := s.persistLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notify(kind)
+	return nil
+}
+
+// RemoveConnection 删除与连接相关的全部记录（连接被删除时调用），返回删除的数量。
+func (s *Store) RemoveConnection(connectionID string) (int, error) {
+	if strings.TrimSpace(connectionID) == "" {
+		return 0, nil
+	}
+	s.mu.Lock()
+	removed := 0
+	changed := make(map[Kind]bool)
+	for sk, it := range s.items {
+		if it.ConnectionID == connectionID {
+			delete(s.items, sk)
+			changed[it.Kind] = true
+			removed++
+		}
+	}
+	var err error
+	if len(changed) > 0 {
+		err = s.persistLocked()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	for _, kind := range []Kind{KindConnection, KindTable, KindFile} {
+		if changed[kind] {
+			s.notify(kind)
+		}
+	}
+	return removed, nil
+}
+
+// Clear 清除指定类型（为空时全部类型）的未置顶记录。
+func (s *Store) Clear(kind Kind) error {
+	s.mu.Lock()
+	for sk, it := range s.items {
+		if (kind == "" || it.Kind == kind) && !it.Pinned {
+			delete(s.items, sk)
+		}
+	}
+	err := s.persistLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notify(kind)
+	return nil
+}
+
+// List 返回指定类型的记录，置顶的在前，其余按最近使用排序；limit <= 0 表示不限制。
+func (s *Store) List(kind Kind, limit int) []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listLocked(kind, limit)
+}
+
+// Overview 返回启动页使用的汇总，每类最多 limit 条（<= 0 表示不限制）。
+func (s *Store) Overview(limit int) Overview {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Overview{
+		Connections: s.listLocked(KindConnection, limit),
+		Tables:      s.listLocked(KindTable, limit),
+		Files:       s.listLocked(KindFile, limit),
+	}
+}
+
+// ValidKind 校验记录类型。
+func ValidKind(kind Kind) error {
+	switch kind {
+	case KindConnection, KindTable, KindFile:
+		return nil
+	}
+	return fmt.Errorf("未知的记录类型: %s", kind)
+}
+
+// KeyOf 生成记录的唯一键：连接按连接 ID，数据表按连接/库/模式/表，文件按清理后的路径。
+func KeyOf(item Item) (string, error) {
+	if err := ValidKind(item.Kind); err != nil {
+		return "", err
+	}
+	switch item.Kind {
+	case KindConnection:
+		if strings.TrimSpace(item.ConnectionID) == "" {
+			return "", errors.New("连接记录缺少 connectionId")
+		}
+		return item.ConnectionID, nil
+	case KindTable:
+		if strings.TrimSpace(item.ConnectionID) == "" || strings.TrimSpace(item.Table) == "" {
+			return "", errors.New("数据表记录缺少 connectionId 或 table")
+		}
+		return strings.Join([]string{item.ConnectionID, item.Database, item.Schema, item.Table}, "/"), nil
+	default:
+		if strings.TrimSpace(item.Path) == "" {
+			return "", errors.New("文件记录缺少 path")
+		}
+		return filepath.Clean(item.Path), nil
+	}
+}
+
+// listLocked 返回排序后的记录副本；调用方需持有锁。
+func (s *Store) listLocked(kind Kind, limit int) []Item {
+	list := make([]Item, 0)
+	for _, it := range s.items {
+		if it.Kind == kind {
+			list = append(list, *it)
+		}
+	}
+	sortItems(list)
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// trimLocked 丢弃超出上限的最久未使用的未置顶记录；调用方需持有锁。
+func (s *Store) trimLocked(kind Kind) {
+	var unpinned []*Item
+	for _, it := range s.items {
+		if it.Kind == kind && !it.Pinned {
+			unpinned = append(unpinned, it)
+		}
+	}
+	if len(unpinned) <= MaxPerKind {
+		return
+	}
+	sort.Slice(unpinned, func(i, j int) bool { return unpinned[i].LastUsedAt.After(unpinned[j].LastUsedAt) })
+	for _, it := range unpinned[MaxPerKind:] {
+		delete(s.items, storeKey(it.Kind, it.Key))
+	}
+}
+
+// persistLocked 将记录写入文件，先写临时文件再替换，调用方需持有锁。
+func (s *Store) persistLocked() error {
+	list := make([]Item, 0, len(s.items))
+	for _, it := range s.items {
+		list = append(list, *it)
+	}
+	sortItems(list)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化最近使用记录失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入最近使用记录失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入最近使用记录失败：%w", err)
+	}
+	return nil
+}
+
+// notify 调用变化回调。
+func (s *Store) notify(kind Kind) {
+	s.mu.Lock()
+	callbacks := slices.Clone(s.onChange)
+	s.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(kind)
+	}
+}
+
+// sortItems 按置顶优先、最近使用、标题排序。
+func sortItems(list []Item) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Pinned != list[j].Pinned {
+			return list[i].Pinned
+		}
+		if !list[i].LastUsedAt.Equal(list[j].LastUsedAt) {
+			return list[i].LastUsedAt.After(list[j].LastUsedAt)
+		}
+		return list[i].Title < list[j].Title
+	})
+}
+
+// storeKey 返回记录在存储中的键。
+func storeKey(kind Kind, key string) string {
+	return string(kind) + "\x00" + key
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recents
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestStore 创建使用临时文件与可控时钟的存储，每次调用 now 前进一秒。
+func newTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recents.json")
+	s := NewStore(path, nil)
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	tick := 0
+	s.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+	return s, path
+}
+
+func TestTouchMergesAndPersists(t *testing.T) {
+	s, path := newTestStore(t)
+	if _, err := s.Touch(Item{Kind: KindConnection, ConnectionID: "c1", Title: "本地 MySQL", DBType: "mysql"}); err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+	if _, err := s.Touch(Item{Kind: KindTable, ConnectionID: "c1", Database: "shop", Table: "orders", Title: "orders"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Touch(Item{Kind: KindFile, Path: "/tmp/sql/../report.sql"}); err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.Touch(Item{Kind: KindConnection, ConnectionID: "c1", Title: "MySQL（重命名）"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.UseCount != 2 || again.Title != "MySQL（重命名）" {
+		t.Fatalf("重复使用应合并并更新标题: %+v", again)
+	}
+
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ov := reloaded.Overview(0)
+	if len(ov.Connections) != 1 || len(ov.Tables) != 1 || len(ov.Files) != 1 {
+		t.Fatalf("Overview() = %+v", ov)
+	}
+	if ov.Tables[0].Key != "c1/shop//orders" {
+		t.Fatalf("数据表记录键错误: %q", ov.Tables[0].Key)
+	}
+	if ov.Files[0].Path != "/tmp/report.sql" || ov.Files[0].Title != "report.sql" {
+		t.Fatalf("文件记录应清理路径并以文件名为标题: %+v", ov.Files[0])
+	}
+}
+
+func TestTouchRejectsInvalid(t *testing.T) {
+	s, _ := newTestStore(t)
+	for _, it := range []Item{
+		{Kind: "query", Title: "x"},
+		{Kind: KindConnection, Title: "x"},
+		{Kind: KindTable, ConnectionID: "c1", Title: "x"},
+		{Kind: KindFile},
+		{Kind: KindConnection, ConnectionID: "c1"},
+	} {
+		if _, err := s.Touch(it); err == nil {
+			t.Errorf("Touch(%+v) 应返回错误", it)
+		}
+	}
+}
+
+func TestPinOrderAndTrim(t *testing.T) {
+	s, _ := newTestStore(t)
+	for i := 0; i < MaxPerKind+5; i++ {
+		id := fmt.Sprintf("c%d", i)
+		if _, err := s.Touch(Item{Kind: KindConnection, ConnectionID: id, Title: id}); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if _, err := s.Pin(KindConnection, id, true); err != nil {
+				t.Fatalf("Pin() error = %v", err)
+			}
+		}
+	}
+	list := s.List(KindConnection, 0)
+	if len(list) != MaxPerKind+1 {
+		t.Fatalf("应保留 %d 条未置顶记录与置顶记录, got %d", MaxPerKind, len(list))
+	}
+	if list[0].Key != "c0" || !list[0].Pinned {
+		t.Fatalf("置顶记录应排在最前且不被丢弃: %+v", list[0])
+	}
+	if list[1].Key != fmt.Sprintf("c%d", MaxPerKind+4) {
+		t.Fatalf("其余记录应按最近使用排序: %+v", list[1])
+	}
+	if got := s.List(KindConnection, 3); len(got) != 3 {
+		t.Fatalf("List(limit=3) 返回 %d 条", len(got))
+	}
+	if _, err := s.Pin(KindConnection, "nope", true); err != ErrItemNotFound {
+		t.Fatalf("Pin 不存在的记录应返回 ErrItemNotFound, got %v", err)
+	}
+
+	if err := s.Clear(KindConnection); err != nil {
+		t.Fatal(err)
+	}
+	if list := s.List(KindConnection, 0); len(list) != 1 || list[0].Key != "c0" {
+		t.Fatalf("Clear 应保留置顶记录: %+v", list)
+	}
+}
+
+func TestRemoveConnectionAndOnChange(t *testing.T) {
+	s, _ := newTestStore(t)
+	var changed []Kind
+	s.OnChange(func(kind Kind) { changed = append(changed, kind) })
+	s.Touch(Item{Kind: KindConnection, ConnectionID: "c1", Title: "c1"})
+	s.Touch(Item{Kind: KindTable, ConnectionID: "c1", Table: "t", Title: "t"})
+	s.Touch(Item{Kind: KindTable, ConnectionID: "c2", Table: "t", Title: "t"})
+
+	removed, err := s.RemoveConnection("c1")
+	if err != nil || removed != 2 {
+		t.Fatalf("RemoveConnection() = %d, %v", removed, err)
+	}
+	if ov := s.Overview(0); len(ov.Connections) != 0 || len(ov.Tables) != 1 {
+		t.Fatalf("应只删除连接 c1 的记录: %+v", ov)
+	}
+	if err := s.Remove(KindTable, "c2///t"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if len(changed) != 6 {
+		t.Fatalf("变化回调次数 = %d (%v)", len(changed), changed)
+	}
+}
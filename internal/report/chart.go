@@ -0,0 +1,221 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 图表类型
+const (
+	ChartBar  = "bar"
+	ChartLine = "line"
+)
+
+// 图表尺寸与规模上限
+const (
+	MaxChartPoints = 50 // 图表最多展示的标签数，超出部分截断
+	MaxChartSeries = 8  // 图表最多展示的系列数
+
+	chartWidth   = 720
+	chartHeight  = 320
+	chartPadLeft = 64
+	chartPadTop  = 24
+	chartPadEnd  = 16
+	chartPadBot  = 72
+	chartTicks   = 4
+)
+
+// chartPalette 系列配色。
+var chartPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7"}
+
+// ChartSpec 是一节的简单图表设置：以 Label 列为横轴标签，Values 中的每列为一个数值系列。
+type ChartSpec struct {
+	Type   string   `json:"type"`   // 图表类型：bar 或 line
+	Label  string   `json:"label"`  // 横轴标签列
+	Values []string `json:"values"` // 数值系列列
+}
+
+// validate 校验图表设置。
+func (c *ChartSpec) validate() error {
+	if c.Type != ChartBar && c.Type != ChartLine {
+		return fmt.Errorf("不支持的图表类型: %s", c.Type)
+	}
+	if strings.TrimSpace(c.Label) == "" {
+		return fmt.Errorf("图表需要指定标签列")
+	}
+	if len(c.Values) == 0 || len(c.Values) > MaxChartSeries {
+		return fmt.Errorf("图表的数值列需要 1 到 %d 个", MaxChartSeries)
+	}
+	return nil
+}
+
+// RenderChart 按图表设置将结果行绘制为 SVG。数值列中无法解析为数字的值按 0 绘制，
+// 超过 MaxChartPoints 的行被截断。
+func RenderChart(spec *ChartSpec, columns []string, rows []map[string]interface{}) ([]byte, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col] = true
+	}
+	for _, col := range append([]string{spec.Label}, spec.Values...) {
+		if !known[col] {
+			return nil, fmt.Errorf("图表列不存在: %s", col)
+		}
+	}
+	if len(rows) > MaxChartPoints {
+		rows = rows[:MaxChartPoints]
+	}
+
+	labels := make([]string, len(rows))
+	series := make([][]float64, len(spec.Values))
+	maxValue, minValue := 0.0, 0.0
+	for i, row := range rows {
+		labels[i] = fmt.Sprint(row[spec.Label])
+		if row[spec.Label] == nil {
+			labels[i] = "NULL"
+		}
+		for s, col := range spec.Values {
+			v := chartNumber(row[col])
+			series[s] = append(series[s], v)
+			maxValue = math.Max(maxValue, v)
+			minValue = math.Min(minValue, v)
+		}
+	}
+	if maxValue == minValue {
+		maxValue = minValue + 1
+	}
+
+	plotW := float64(chartWidth - chartPadLeft - chartPadEnd)
+	plotH := float64(chartHeight - chartPadTop - chartPadBot)
+	y := func(v float64) float64 {
+		return chartPadTop + plotH*(maxValue-v)/(maxValue-minValue)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	b.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>`)
+
+	// 网格线与纵轴刻度
+	for i := 0; i <= chartTicks; i++ {
+		v := minValue + (maxValue-minValue)*float64(i)/chartTicks
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e5e5e5"/>`, chartPadLeft, y(v), chartWidth-chartPadEnd, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="#666">%s</text>`, chartPadLeft-6, y(v)+4, formatTick(v))
+	}
+
+	if n := len(labels); n > 0 {
+		step := plotW / float64(n)
+		for i, label := range labels {
+			x := chartPadLeft + step*(float64(i)+0.5)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="end" fill="#444" transform="rotate(-40 %.1f %d)">%s</text>`,
+				x, chartHeight-chartPadBot+14, x, chartHeight-chartPadBot+14, html.EscapeString(truncateLabel(label)))
+		}
+		for s, values := range series {
+			color := chartPalette[s%len(chartPalette)]
+			if spec.Type == ChartBar {
+				barW := step * 0.8 / float64(len(series))
+				for i, v := range values {
+					x := chartPadLeft + step*float64(i) + step*0.1 + barW*float64(s)
+					top, bottom := y(math.Max(v, 0)), y(math.Min(v, 0))
+					fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`, x, top, barW, bottom-top, color)
+				}
+				continue
+			}
+			points := make([]string, len(values))
+			for i, v := range values {
+				points[i] = fmt.Sprintf("%.1f,%.1f", chartPadLeft+step*(float64(i)+0.5), y(v))
+			}
+			fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), color)
+		}
+	}
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#999"/>`, chartPadLeft, y(0), chartWidth-chartPadEnd, y(0))
+
+	// 图例
+	for s, name := range spec.Values {
+		x := chartPadLeft + s*110
+		fmt.Fprintf(&b, `<rect x="%d" y="6" width="10" height="10" fill="%s"/>`, x, chartPalette[s%len(chartPalette)])
+		fmt.Fprintf(&b, `<text x="%d" y="15" fill="#333">%s</text>`, x+14, html.EscapeString(truncateLabel(name)))
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String()), nil
+}
+
+// ChartDataURI 将 SVG 编码为可嵌入 Markdown 或 HTML 的 data URI。
+func ChartDataURI(svg []byte) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg)
+}
+
+// chartNumber 将单元格值转换为数字，数值字符串（如 DECIMAL 列）按数字解析，其他值为 0。
+func chartNumber(v interface{}) float64 {
+	switch x := v.(type) {
+	case int:
+		return float64(x)
+	case int8:
+		return float64(x)
+	case int16:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint8:
+		return float64(x)
+	case uint16:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case float32:
+		return float64(x)
+	case float64:
+		return x
+	case []byte:
+		return chartNumber(string(x))
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+	return 0
+}
+
+// formatTick 格式化纵轴刻度，整数不带小数位。
+func formatTick(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// truncateLabel 截断过长的标签文本。
+func truncateLabel(s string) string {
+	const maxRunes = 16
+	r := []rune(s)
+	if len(r) <= maxRunes {
+		return s
+	}
+	return string(r[:maxRunes-1]) + "…"
+}
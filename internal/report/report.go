@@ -0,0 +1,245 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report 将一个或多个保存的查询结果渲染为 Markdown 或 HTML 报表：
+// 报表包含标题、说明、各查询的结果表格与以内嵌 SVG 图片呈现的简单图表，可使用自定义模板。
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/appdata"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
+)
+
+// 报表格式
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// 规模上限
+const (
+	MaxSections     = 20   // 单个报表的节数上限
+	DefaultMaxRows  = 100  // 每节默认展示的最大行数
+	MaxRowsLimit    = 1000 // 每节可设置的最大行数
+	generatedLayout = "2006-01-02 15:04:05"
+)
+
+// Definition 是报表定义。查询与连接配置由前端保存，生成报表时作为参数传入。
+type Definition struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Format      string     `json:"format"`               // 报表格式：md 或 html
+	Template    string     `json:"template,omitempty"`   // 自定义模板（Go template 语法，数据结构见 View），为空时使用内置模板
+	MaxRows     int        `json:"maxRows,omitempty"`    // 每节展示的最大行数，<=0 使用 DefaultMaxRows
+	DateFormat  string     `json:"dateFormat,omitempty"` // 日期格式，如 yyyy-MM-dd HH:mm:ss，为空时保持原样
+	OutputPath  string     `json:"outputPath,omitempty"` // 输出文件路径，支持 {date} 与 {datetime} 占位符；为空时弹出保存对话框
+	Sections    []*Section `json:"sections"`
+}
+
+// Section 是报表中的一节，对应一个保存的查询。
+type Section struct {
+	Title       string                       `json:"title,omitempty"`       // 节标题，为空时使用查询名称
+	Description string                       `json:"description,omitempty"` // 节说明，为空时使用查询说明
+	Query       appdata.SavedQuery           `json:"query"`
+	Connection  *connection.ConnectionConfig `json:"connection"` // 查询关联的连接配置
+	Chart       *ChartSpec                   `json:"chart,omitempty"`
+	HideTable   bool                         `json:"hideTable,omitempty"` // 只展示图表，不展示结果表格
+}
+
+// SectionResult 是一节查询的执行结果。
+type SectionResult struct {
+	Columns   []string
+	Rows      []map[string]interface{}
+	Truncated bool  // 结果超过 MaxRows 被截断
+	Err       error // 查询失败的原因，失败的节在报表中展示错误信息
+}
+
+// View 是渲染模板使用的数据。
+type View struct {
+	Title       string
+	Description string
+	GeneratedAt string
+	Sections    []*SectionView
+}
+
+// SectionView 是模板中一节的数据，单元格已按日期格式转换为文本（NULL 为 "NULL"）。
+type SectionView struct {
+	Title       string
+	Description string
+	SQL         string
+	Columns     []string
+	Rows        [][]string
+	RowCount    int
+	Truncated   bool
+	HideTable   bool
+	Chart       htmltemplate.URL // 图表的 SVG data URI，未设置图表时为空
+	ChartError  string
+	Error       string
+}
+
+// Validate 校验报表定义（不含连接配置）。
+func (d *Definition) Validate() error {
+	if strings.TrimSpace(d.Title) == "" {
+		return errors.New("报表标题不能为空")
+	}
+	if d.Format != FormatMarkdown && d.Format != FormatHTML {
+		return fmt.Errorf("不支持的报表格式: %s", d.Format)
+	}
+	if len(d.Sections) == 0 || len(d.Sections) > MaxSections {
+		return fmt.Errorf("报表需要包含 1 到 %d 个查询", MaxSections)
+	}
+	if d.MaxRows > MaxRowsLimit {
+		return fmt.Errorf("每节展示的最大行数不能超过 %d", MaxRowsLimit)
+	}
+	for i, s := range d.Sections {
+		if s == nil || strings.TrimSpace(s.Query.SQL) == "" {
+			return fmt.Errorf("第 %d 个查询的 SQL 不能为空", i+1)
+		}
+		if s.Chart != nil {
+			if err := s.Chart.validate(); err != nil {
+				return fmt.Errorf("第 %d 个查询：%w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RowLimit 返回每节展示的最大行数。
+func (d *Definition) RowLimit() int {
+	if d.MaxRows <= 0 {
+		return DefaultMaxRows
+	}
+	return d.MaxRows
+}
+
+// Extension 返回报表文件扩展名（不含点）。
+func (d *Definition) Extension() string {
+	return d.Format
+}
+
+// Render 按报表定义与各节查询结果渲染报表，results 与 d.Sections 一一对应。
+func Render(d *Definition, results []*SectionResult, now time.Time) ([]byte, error) {
+	if len(results) != len(d.Sections) {
+		return nil, fmt.Errorf("查询结果数量 %d 与报表节数 %d 不一致", len(results), len(d.Sections))
+	}
+	view := buildView(d, results, now)
+
+	text := d.Template
+	var buf bytes.Buffer
+	if d.Format == FormatHTML {
+		if strings.TrimSpace(text) == "" {
+			text = defaultHTMLTemplate
+		}
+		tmpl, err := htmltemplate.New("report").Funcs(htmltemplate.FuncMap{"cell": htmlCell}).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("解析报表模板失败：%w", err)
+		}
+		if err := tmpl.Execute(&buf, view); err != nil {
+			return nil, fmt.Errorf("渲染报表失败：%w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	if strings.TrimSpace(text) == "" {
+		text = defaultMarkdownTemplate
+	}
+	tmpl, err := template.New("report").Funcs(template.FuncMap{"cell": markdownCell}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析报表模板失败：%w", err)
+	}
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return nil, fmt.Errorf("渲染报表失败：%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// buildView 组装模板数据：格式化单元格并为设置了图表的节生成 SVG。
+func buildView(d *Definition, results []*SectionResult, now time.Time) *View {
+	formatter := dataexport.NewValueFormatter(nil, d.DateFormat)
+	view := &View{
+		Title:       d.Title,
+		Description: d.Description,
+		GeneratedAt: now.Format(generatedLayout),
+		Sections:    make([]*SectionView, 0, len(d.Sections)),
+	}
+	for i, s := range d.Sections {
+		res := results[i]
+		sv := &SectionView{
+			Title:       firstNonEmpty(s.Title, s.Query.Name, fmt.Sprintf("查询 %d", i+1)),
+			Description: firstNonEmpty(s.Description, s.Query.Description),
+			SQL:         s.Query.SQL,
+			HideTable:   s.HideTable,
+		}
+		view.Sections = append(view.Sections, sv)
+		if res == nil || res.Err != nil {
+			sv.Error = "未执行"
+			if res != nil {
+				sv.Error = res.Err.Error()
+			}
+			continue
+		}
+
+		sv.Columns = res.Columns
+		sv.RowCount = len(res.Rows)
+		sv.Truncated = res.Truncated
+		sv.Rows = make([][]string, len(res.Rows))
+		for r, row := range res.Rows {
+			cells := make([]string, len(res.Columns))
+			for c, col := range res.Columns {
+				cells[c] = formatter.Text(row[col])
+			}
+			sv.Rows[r] = cells
+		}
+		if s.Chart != nil {
+			svg, err := RenderChart(s.Chart, res.Columns, res.Rows)
+			if err != nil {
+				sv.ChartError = err.Error()
+			} else {
+				sv.Chart = htmltemplate.URL(ChartDataURI(svg))
+			}
+		}
+	}
+	return view
+}
+
+// markdownCell 转义 Markdown 表格单元格中的竖线与换行。
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// htmlCell 原样返回单元格文本，由 html/template 负责转义；使自定义模板可在两种格式间通用。
+func htmlCell(s string) string {
+	return s
+}
+
+// firstNonEmpty 返回第一个非空白的字符串。
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/appdata"
+)
+
+var reportTime = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+// sampleDefinition 返回含一个成功查询（带图表）与一个失败查询的报表定义及结果。
+func sampleDefinition(format string) (*Definition, []*SectionResult) {
+	def := &Definition{
+		Title:       "周报 <W41>",
+		Description: "每周订单汇总",
+		Format:      format,
+		Sections: []*Section{
+			{
+				Query: appdata.SavedQuery{Name: "每日订单", SQL: "SELECT day, orders FROM daily"},
+				Chart: &ChartSpec{Type: ChartBar, Label: "day", Values: []string{"orders"}},
+			},
+			{Title: "退款", Query: appdata.SavedQuery{SQL: "SELECT * FROM refunds"}},
+		},
+	}
+	results := []*SectionResult{
+		{
+			Columns:   []string{"day", "orders"},
+			Rows:      []map[string]interface{}{{"day": "Mon|1", "orders": int64(3)}, {"day": "Tue", "orders": nil}},
+			Truncated: true,
+		},
+		{Err: errors.New("table refunds does not exist")},
+	}
+	return def, results
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	valid, _ := sampleDefinition(FormatMarkdown)
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(d *Definition)
+	}{
+		{"标题为空", func(d *Definition) { d.Title = " " }},
+		{"格式不支持", func(d *Definition) { d.Format = "pdf" }},
+		{"没有查询", func(d *Definition) { d.Sections = nil }},
+		{"SQL 为空", func(d *Definition) { d.Sections[1].Query.SQL = "" }},
+		{"行数超限", func(d *Definition) { d.MaxRows = MaxRowsLimit + 1 }},
+		{"图表类型", func(d *Definition) { d.Sections[0].Chart.Type = "pie" }},
+		{"图表无数值列", func(d *Definition) { d.Sections[0].Chart.Values = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := sampleDefinition(FormatMarkdown)
+			tt.mutate(d)
+			if err := d.Validate(); err == nil {
+				t.Error("Validate() 应返回错误")
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	def, results := sampleDefinition(FormatMarkdown)
+	out, err := Render(def, results, reportTime)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"# 周报 <W41>\n",
+		"_生成时间：2026-10-12 09:00:00_",
+		"## 每日订单\n",
+		"![每日订单](data:image/svg+xml;base64,",
+		"| day | orders |\n| --- | --- |\n| Mon\\|1 | 3 |\n| Tue | NULL |\n",
+		"_共 2 行，结果已截断_",
+		"## 退款\n",
+		"> 查询失败：table refunds does not exist",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("报表缺少 %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	def, results := sampleDefinition(FormatHTML)
+	def.Sections[0].HideTable = true
+	out, err := Render(def, results, reportTime)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"<title>周报 &lt;W41&gt;</title>",
+		`<img src="data:image/svg`,
+		`<p class="error">查询失败：table refunds does not exist</p>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("报表缺少 %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<th>day</th>") {
+		t.Error("HideTable 的节不应输出表格")
+	}
+}
+
+func TestRenderCustomTemplate(t *testing.T) {
+	def, results := sampleDefinition(FormatMarkdown)
+	def.Template = "{{.Title}}:{{range .Sections}} {{.Title}}={{.RowCount}}{{end}}"
+	out, err := Render(def, results, reportTime)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := string(out); got != "周报 <W41>: 每日订单=2 退款=0" {
+		t.Errorf("Render() = %q", got)
+	}
+
+	def.Template = "{{.Missing"
+	if _, err := Render(def, results, reportTime); err == nil {
+		t.Error("模板语法错误时应返回错误")
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"d": "a", "x": int64(2), "y": "1.5"},
+		{"d": "b", "x": int64(-1), "y": nil},
+	}
+	svg, err := RenderChart(&ChartSpec{Type: ChartBar, Label: "d", Values: []string{"x", "y"}}, []string{"d", "x", "y"}, rows)
+	if err != nil {
+		t.Fatalf("RenderChart() error = %v", err)
+	}
+	// 背景、图例 2 个、柱 4 个
+	if n := strings.Count(string(svg), "<rect "); n != 7 {
+		t.Errorf("rect 数量 = %d, want 7", n)
+	}
+
+	svg, err = RenderChart(&ChartSpec{Type: ChartLine, Label: "d", Values: []string{"x"}}, []string{"d", "x", "y"}, rows)
+	if err != nil || strings.Count(string(svg), "<polyline ") != 1 {
+		t.Errorf("折线图生成错误: err=%v", err)
+	}
+
+	if _, err := RenderChart(&ChartSpec{Type: ChartBar, Label: "d", Values: []string{"z"}}, []string{"d", "x"}, rows); err == nil {
+		t.Error("图表列不存在时应返回错误")
+	}
+}
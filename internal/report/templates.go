@@ -0,0 +1,79 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+// defaultMarkdownTemplate 内置 Markdown 报表模板。
+const defaultMarkdownTemplate = `# {{.Title}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+_生成时间：{{.GeneratedAt}}_
+{{range .Sections}}
+## {{.Title}}
+{{if .Description}}
+{{.Description}}
+{{end}}{{if .Error}}
+> 查询失败：{{.Error}}
+{{else}}{{if .Chart}}
+![{{.Title}}]({{.Chart}})
+{{else if .ChartError}}
+> 图表生成失败：{{.ChartError}}
+{{end}}{{if not .HideTable}}{{if .Columns}}
+|{{range .Columns}} {{cell .}} |{{end}}
+|{{range .Columns}} --- |{{end}}
+{{range .Rows}}|{{range .}} {{cell .}} |{{end}}
+{{end}}{{end}}
+_共 {{.RowCount}} 行{{if .Truncated}}，结果已截断{{end}}_
+{{end}}{{end}}{{end}}`
+
+// defaultHTMLTemplate 内置 HTML 报表模板。
+const defaultHTMLTemplate = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", sans-serif; margin: 32px auto; max-width: 960px; color: #222; }
+h1 { margin-bottom: 4px; }
+.meta { color: #888; font-size: 13px; }
+section { margin-top: 32px; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; white-space: pre-wrap; }
+th { background: #f5f5f5; }
+.error { color: #c0392b; }
+img { max-width: 100%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<p class="meta">生成时间：{{.GeneratedAt}}</p>
+{{range .Sections}}<section>
+<h2>{{.Title}}</h2>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}{{if .Error}}<p class="error">查询失败：{{.Error}}</p>
+{{else}}{{if .Chart}}<img src="{{.Chart}}" alt="{{.Title}}">
+{{else if .ChartError}}<p class="error">图表生成失败：{{.ChartError}}</p>
+{{end}}{{if not .HideTable}}{{if .Columns}}<table>
+<thead><tr>{{range .Columns}}<th>{{cell .}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{cell .}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{end}}{{end}}<p class="meta">共 {{.RowCount}} 行{{if .Truncated}}，结果已截断{{end}}</p>
+{{end}}</section>
+{{end}}</body>
+</html>
+`
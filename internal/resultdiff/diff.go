@@ -0,0 +1,296 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resultdiff 按键列比较两个查询结果集，输出新增、删除与变更行及单元格差异。
+package resultdiff
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxListed 是每类差异默认列出的最大行数，超出部分只计数。
+const DefaultMaxListed = 1000
+
+// ErrNoKeyColumns 表示未指定用于匹配行的键列。
+var ErrNoKeyColumns = errors.New("至少需要指定一个键列")
+
+// Input 是参与比较的一侧结果集。
+type Input struct {
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// CellDiff 是同一行中某一列的取值差异。
+type CellDiff struct {
+	Column string      `json:"column"`
+	Left   interface{} `json:"left"`
+	Right  interface{} `json:"right"`
+}
+
+// RowDiff 是键相同但内容不同的一行。
+type RowDiff struct {
+	Key   map[string]interface{} `json:"key"`
+	Cells []CellDiff             `json:"cells"`
+}
+
+// Result 是结果集比较结果。Added 为仅右侧存在的行，Removed 为仅左侧存在的行。
+type Result struct {
+	KeyColumns       []string                 `json:"keyColumns"`
+	Columns          []string                 `json:"columns"`
+	LeftOnlyColumns  []string                 `json:"leftOnlyColumns"`
+	RightOnlyColumns []string                 `json:"rightOnlyColumns"`
+	LeftRows         int                      `json:"leftRows"`
+	RightRows        int                      `json:"rightRows"`
+	AddedCount       int                      `json:"addedCount"`
+	RemovedCount     int                      `json:"removedCount"`
+	ChangedCount     int                      `json:"changedCount"`
+	UnchangedCount   int                      `json:"unchangedCount"`
+	DuplicateKeys    int                      `json:"duplicateKeys"`
+	Added            []map[string]interface{} `json:"added"`
+	Removed          []map[string]interface{} `json:"removed"`
+	Changed          []*RowDiff               `json:"changed"`
+	Truncated        bool                     `json:"truncated"`
+}
+
+// Diff 以 keyColumns 匹配左右两侧的行，比较两侧共有的非键列。
+// 同一侧出现重复键时只取第一行参与比较，并计入 DuplicateKeys；每类差异最多列出 maxListed 行（<=0 时取默认值）。
+func Diff(left, right Input, keyColumns []string, maxListed int) (*Result, error) {
+	if len(keyColumns) == 0 {
+		return nil, ErrNoKeyColumns
+	}
+	if maxListed <= 0 {
+		maxListed = DefaultMaxListed
+	}
+	for _, key := range keyColumns {
+		if !contains(left.Columns, key) {
+			return nil, fmt.Errorf("左侧结果集缺少键列 %s", key)
+		}
+		if !contains(right.Columns, key) {
+			return nil, fmt.Errorf("右侧结果集缺少键列 %s", key)
+		}
+	}
+
+	result := &Result{
+		KeyColumns:       keyColumns,
+		Columns:          []string{},
+		LeftOnlyColumns:  []string{},
+		RightOnlyColumns: []string{},
+		LeftRows:         len(left.Rows),
+		RightRows:        len(right.Rows),
+		Added:            []map[string]interface{}{},
+		Removed:          []map[string]interface{}{},
+		Changed:          []*RowDiff{},
+	}
+	var compared []string
+	for _, col := range left.Columns {
+		if contains(right.Columns, col) {
+			result.Columns = append(result.Columns, col)
+			if !contains(keyColumns, col) {
+				compared = append(compared, col)
+			}
+		} else {
+			result.LeftOnlyColumns = append(result.LeftOnlyColumns, col)
+		}
+	}
+	for _, col := range right.Columns {
+		if !contains(left.Columns, col) {
+			result.RightOnlyColumns = append(result.RightOnlyColumns, col)
+		}
+	}
+
+	rightIndex := make(map[string]map[string]interface{}, len(right.Rows))
+	rightOrder := make([]string, 0, len(right.Rows))
+	for _, row := range right.Rows {
+		key := RowKey(row, keyColumns)
+		if _, exists := rightIndex[key]; exists {
+			result.DuplicateKeys++
+			continue
+		}
+		rightIndex[key] = row
+		rightOrder = append(rightOrder, key)
+	}
+
+	matched := make(map[string]bool, len(left.Rows))
+	for _, leftRow := range left.Rows {
+		key := RowKey(leftRow, keyColumns)
+		if matched[key] {
+			result.DuplicateKeys++
+			continue
+		}
+		matched[key] = true
+
+		rightRow, ok := rightIndex[key]
+		if !ok {
+			result.RemovedCount++
+			if len(result.Removed) < maxListed {
+				result.Removed = append(result.Removed, leftRow)
+			} else {
+				result.Truncated = true
+			}
+			continue
+		}
+
+		var cells []CellDiff
+		for _, col := range compared {
+			if !Equal(leftRow[col], rightRow[col]) {
+				cells = append(cells, CellDiff{Column: col, Left: leftRow[col], Right: rightRow[col]})
+			}
+		}
+		if len(cells) == 0 {
+			result.UnchangedCount++
+			continue
+		}
+		result.ChangedCount++
+		if len(result.Changed) < maxListed {
+			rowKeyValues := make(map[string]interface{}, len(keyColumns))
+			for _, k := range keyColumns {
+				rowKeyValues[k] = leftRow[k]
+			}
+			result.Changed = append(result.Changed, &RowDiff{Key: rowKeyValues, Cells: cells})
+		} else {
+			result.Truncated = true
+		}
+	}
+
+	for _, key := range rightOrder {
+		if matched[key] {
+			continue
+		}
+		result.AddedCount++
+		if len(result.Added) < maxListed {
+			result.Added = append(result.Added, rightIndex[key])
+		} else {
+			result.Truncated = true
+		}
+	}
+	return result, nil
+}
+
+// Equal 判断两个单元格取值是否相同。
+// 数值类型与数字文本按数值比较（不同数据库对 DECIMAL/整数的返回类型不同），时间统一按 UTC 比较，其余按文本比较。
+func Equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if isNumeric(a) || isNumeric(b) {
+		ra, okA := toRat(a)
+		rb, okB := toRat(b)
+		if okA && okB {
+			return ra.Cmp(rb) == 0
+		}
+	}
+	return canonical(a) == canonical(b)
+}
+
+// RowKey 将键列取值编码为可作为 map 键的字符串；NULL 与空串可区分，Equal 视为相等的数值得到相同的键。
+func RowKey(row map[string]interface{}, keyColumns []string) string {
+	var sb strings.Builder
+	for i, col := range keyColumns {
+		if i > 0 {
+			sb.WriteByte(0x1f)
+		}
+		v := row[col]
+		if v == nil {
+			sb.WriteString("\x00")
+			continue
+		}
+		if r, ok := toRat(v); ok && isNumeric(v) {
+			sb.WriteString(r.RatString())
+			continue
+		}
+		sb.WriteString(canonical(v))
+	}
+	return sb.String()
+}
+
+// canonical 返回取值的规范化文本形式。
+func canonical(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+	case bool:
+		if t {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// isNumeric 判断取值是否为 Go 数值类型。
+func isNumeric(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// toRat 将数值或数字文本转换为有理数，用于跨类型精确比较。
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	case float32:
+		return toRat(strconv.FormatFloat(float64(n), 'g', -1, 32))
+	case float64:
+		return toRat(strconv.FormatFloat(n, 'g', -1, 64))
+	case []byte:
+		return toRat(string(n))
+	case string:
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(n))
+		return r, ok
+	default:
+		return nil, false
+	}
+}
+
+// contains 判断 list 中是否包含 s。
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultdiff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiffAddedRemovedChanged(t *testing.T) {
+	left := Input{
+		Columns: []string{"id", "name", "price", "legacy"},
+		Rows: []map[string]interface{}{
+			{"id": int64(1), "name": "a", "price": "10.50", "legacy": "x"},
+			{"id": int64(2), "name": "b", "price": "3", "legacy": "y"},
+			{"id": int64(3), "name": "c", "price": nil, "legacy": "z"},
+		},
+	}
+	right := Input{
+		Columns: []string{"id", "name", "price", "extra"},
+		Rows: []map[string]interface{}{
+			{"id": "1", "name": "a", "price": 10.5, "extra": 1},
+			{"id": "2", "name": "B", "price": "3", "extra": 1},
+			{"id": "4", "name": "d", "price": "1", "extra": 1},
+		},
+	}
+
+	result, err := Diff(left, right, []string{"id"}, 0)
+	if err != nil {
+		t.Fatalf("Diff 返回错误: %v", err)
+	}
+	if result.UnchangedCount != 1 || result.ChangedCount != 1 || result.AddedCount != 1 || result.RemovedCount != 1 {
+		t.Fatalf("计数不符: %+v", result)
+	}
+	if len(result.Changed) != 1 || len(result.Changed[0].Cells) != 1 || result.Changed[0].Cells[0].Column != "name" {
+		t.Fatalf("变更行不符: %+v", result.Changed)
+	}
+	if result.Removed[0]["id"] != int64(3) || result.Added[0]["id"] != "4" {
+		t.Fatalf("新增/删除行不符: removed=%v added=%v", result.Removed, result.Added)
+	}
+	if len(result.LeftOnlyColumns) != 1 || result.LeftOnlyColumns[0] != "legacy" {
+		t.Fatalf("左侧独有列不符: %v", result.LeftOnlyColumns)
+	}
+	if len(result.RightOnlyColumns) != 1 || result.RightOnlyColumns[0] != "extra" {
+		t.Fatalf("右侧独有列不符: %v", result.RightOnlyColumns)
+	}
+}
+
+func TestDiffCompositeKeyDuplicatesAndTruncation(t *testing.T) {
+	left := Input{Columns: []string{"a", "b", "v"}}
+	right := Input{Columns: []string{"a", "b", "v"}}
+	for i := 0; i < 5; i++ {
+		left.Rows = append(left.Rows, map[string]interface{}{"a": int64(i), "b": "x", "v": int64(i)})
+		right.Rows = append(right.Rows, map[string]interface{}{"a": int64(i), "b": "x", "v": int64(i + 1)})
+	}
+	right.Rows = append(right.Rows, map[string]interface{}{"a": int64(0), "b": "x", "v": int64(0)})
+
+	result, err := Diff(left, right, []string{"a", "b"}, 2)
+	if err != nil {
+		t.Fatalf("Diff 返回错误: %v", err)
+	}
+	if result.ChangedCount != 5 || len(result.Changed) != 2 || !result.Truncated {
+		t.Fatalf("截断结果不符: count=%d listed=%d truncated=%v", result.ChangedCount, len(result.Changed), result.Truncated)
+	}
+	if result.DuplicateKeys != 1 {
+		t.Fatalf("重复键计数不符: %d", result.DuplicateKeys)
+	}
+}
+
+func TestDiffMissingKeyColumn(t *testing.T) {
+	in := Input{Columns: []string{"id"}}
+	if _, err := Diff(in, in, nil, 0); !errors.Is(err, ErrNoKeyColumns) {
+		t.Fatalf("期望 ErrNoKeyColumns，得到 %v", err)
+	}
+	if _, err := Diff(in, Input{Columns: []string{"name"}}, []string{"id"}, 0); err == nil {
+		t.Fatal("右侧缺少键列时应返回错误")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, "", false},
+		{int64(1), "1.00", true},
+		{float64(0.1), "0.1", true},
+		{"001", "1", false},
+		{[]byte("abc"), "abc", true},
+		{ts, ts.In(time.FixedZone("CST", 8*3600)), true},
+		{true, int64(1), true},
+	}
+	for _, c := range cases {
+		if got := Equal(c.a, c.b); got != c.want {
+			t.Errorf("Equal(%v, %v) = %v，期望 %v", c.a, c.b, got, c.want)
+		}
+	}
+}
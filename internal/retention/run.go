@@ -0,0 +1,58 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+// BatchFunc 执行一批删除并返回删除的行数。
+type BatchFunc func(ctx context.Context) (int64, error)
+
+// ProgressFunc 在每批完成后回调累计删除行数与批次数。
+type ProgressFunc func(deleted int64, batches int)
+
+// Execute 循环执行 batch 直到某批删除行数少于 batchSize，批次之间等待 pause；
+// ctx 取消时在批次之间停止。返回的 Run 不含开始、结束时间与截止时间，由调用方填写。
+func Execute(ctx context.Context, batch BatchFunc, batchSize int, pause time.Duration, progress ProgressFunc) (*Run, error) {
+	run := &Run{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return run, err
+		}
+		n, err := batch(ctx)
+		if err != nil {
+			return run, err
+		}
+		run.Deleted += n
+		run.Batches++
+		if progress != nil {
+			progress(run.Deleted, run.Batches)
+		}
+		if n < int64(batchSize) {
+			return run, nil
+		}
+		if pause > 0 {
+			timer := time.NewTimer(pause)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return run, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteStopsOnShortBatch(t *testing.T) {
+	remaining := int64(2500)
+	batch := func(ctx context.Context) (int64, error) {
+		n := min(remaining, 1000)
+		remaining -= n
+		return n, nil
+	}
+	var progress []int64
+	run, err := Execute(context.Background(), batch, 1000, 0, func(deleted int64, batches int) {
+		progress = append(progress, deleted)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Deleted != 2500 || run.Batches != 3 || len(progress) != 3 || progress[2] != 2500 {
+		t.Errorf("执行结果不符: %+v %v", run, progress)
+	}
+}
+
+func TestExecuteCancelDuringPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := 0
+	batch := func(ctx context.Context) (int64, error) {
+		batches++
+		cancel()
+		return 10, nil
+	}
+	run, err := Execute(ctx, batch, 10, time.Minute, nil)
+	if !errors.Is(err, context.Canceled) || batches != 1 || run.Deleted != 10 {
+		t.Errorf("批次间取消应立即停止并保留已删除行数: %+v %v", run, err)
+	}
+}
+
+func TestExecuteBatchError(t *testing.T) {
+	boom := errors.New("lock wait timeout")
+	run, err := Execute(context.Background(), func(ctx context.Context) (int64, error) { return 0, boom }, 10, 0, nil)
+	if !errors.Is(err, boom) || run.Batches != 0 {
+		t.Errorf("批次失败应返回错误: %+v %v", run, err)
+	}
+}
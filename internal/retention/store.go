@@ -0,0 +1,303 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention 管理数据保留规则：按时间列保留最近 N 天的数据，更早的行分批删除，
+// 用于替代手写的清理脚本；规则保存在用户配置目录下的 JSON 文件中，执行由调用方提交为后台任务。
+package retention
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultBatchSize 未指定时每批删除的行数。
+	DefaultBatchSize = 1000
+	// MaxBatchSize 每批删除行数的上限，过大的批次会长时间持有锁并产生大事务。
+	MaxBatchSize = 100000
+	// MaxPause 批次间隔的上限。
+	MaxPause = time.Minute
+)
+
+// ErrRuleNotFound 保留规则不存在。
+var ErrRuleNotFound = errors.New("数据保留规则不存在")
+
+// Rule 是一条数据保留规则：删除 Table 中 TimestampColumn 早于 KeepDays 天前的行。
+type Rule struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	ConnectionID    string    `json:"connectionId,omitempty"` // 关联的连接 ID，规则只能在该连接上预览与执行
+	Database        string    `json:"database,omitempty"`     // 数据库名
+	Table           string    `json:"table"`                  // 表名，可带 schema 前缀
+	TimestampColumn string    `json:"timestampColumn"`        // 日期时间列
+	KeepDays        int       `json:"keepDays"`               // 保留天数，至少 1 天
+	BatchSize       int       `json:"batchSize"`              // 每批删除行数，0 表示 DefaultBatchSize
+	PauseMs         int       `json:"pauseMs"`                // 批次间隔（毫秒），用于降低对线上库的压力
+	LastRun         *Run      `json:"lastRun,omitempty"`      // 最近一次执行
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Run 是一次执行的结果。
+type Run struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Cutoff     time.Time `json:"cutoff"`          // 删除早于该时间的行
+	Deleted    int64     `json:"deleted"`         // 已删除行数
+	Batches    int       `json:"batches"`         // 已执行批次数
+	Error      string    `json:"error,omitempty"` // 失败或取消原因，成功时为空
+}
+
+// Cutoff 返回规则在 now 时刻对应的截止时间，早于该时间的行将被删除。
+func (r *Rule) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -r.KeepDays)
+}
+
+// ErrConnectionMismatch 规则未关联连接或与执行时的连接不一致。
+var ErrConnectionMismatch = errors.New("数据保留规则不属于当前连接")
+
+// CheckConnection 校验规则关联的连接与 connectionID 一致；未关联连接的规则需重新保存后才能执行。
+func (r *Rule) CheckConnection(connectionID string) error {
+	if r.ConnectionID == "" {
+		return fmt.Errorf("%w：规则未关联连接，请编辑规则选择连接后重新保存", ErrConnectionMismatch)
+	}
+	if connectionID != r.ConnectionID {
+		return ErrConnectionMismatch
+	}
+	return nil
+}
+
+// EffectiveBatchSize 返回实际使用的每批行数。
+func (r *Rule) EffectiveBatchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return r.BatchSize
+}
+
+// Pause 返回批次间隔。
+func (r *Rule) Pause() time.Duration {
+	return time.Duration(r.PauseMs) * time.Millisecond
+}
+
+// Store 保存数据保留规则，可并发使用。
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	logger *slog.Logger
+	rules  map[string]*Rule
+	now    func() time.Time
+}
+
+// DefaultPath 返回默认的规则文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "retention-rules.json")
+	}
+	return filepath.Join(configDir, "Boxify", "retention-rules.json")
+}
+
+// NewStore 创建规则存储，path 为空时使用默认路径；需调用 Load 读取已保存的规则。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		path:   path,
+		logger: logger.With("module", "retention"),
+		rules:  make(map[string]*Rule),
+		now:    time.Now,
+	}
+}
+
+// Load 读取规则文件，文件不存在时视为空。
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取数据保留规则失败：%w", err)
+	}
+	var list []*Rule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析数据保留规则失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = make(map[string]*Rule, len(list))
+	for _, r := range list {
+		if r != nil && r.ID != "" {
+			s.rules[r.ID] = r
+		}
+	}
+	return nil
+}
+
+// List 返回全部规则，按名称排序。
+func (s *Store) List() []*Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Rule, 0, len(s.rules))
+	for _, r := range s.rules {
+		list = append(list, clone(r))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Get 按 ID 查找规则。
+func (s *Store) Get(id string) (*Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.rules[id]
+	if !ok {
+		return nil, ErrRuleNotFound
+	}
+	return clone(r), nil
+}
+
+// Save 创建或更新规则（ID 为空时创建），更新时保留最近一次执行记录。
+func (s *Store) Save(rule *Rule) (*Rule, error) {
+	if rule == nil {
+		return nil, errors.New("数据保留规则不能为空")
+	}
+	saved := clone(rule)
+	saved.Name = strings.TrimSpace(saved.Name)
+	saved.Table = strings.TrimSpace(saved.Table)
+	saved.TimestampColumn = strings.TrimSpace(saved.TimestampColumn)
+	if err := validate(saved); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+		saved.LastRun = nil
+	} else {
+		old, ok := s.rules[saved.ID]
+		if !ok {
+			return nil, ErrRuleNotFound
+		}
+		saved.CreatedAt = old.CreatedAt
+		saved.LastRun = old.LastRun
+	}
+	saved.UpdatedAt = now
+	s.rules[saved.ID] = saved
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	return clone(saved), nil
+}
+
+// Delete 删除规则。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+	delete(s.rules, id)
+	return s.persistLocked()
+}
+
+// RecordRun 记录规则最近一次执行的结果；规则已被删除时忽略。
+func (s *Store) RecordRun(id string, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.rules[id]
+	if !ok {
+		return nil
+	}
+	c := *run
+	r.LastRun = &c
+	return s.persistLocked()
+}
+
+// validate 校验规则字段。
+func validate(r *Rule) error {
+	switch {
+	case r.Name == "":
+		return errors.New("数据保留规则名称不能为空")
+	case r.Table == "":
+		return errors.New("表名不能为空")
+	case r.TimestampColumn == "":
+		return errors.New("时间列不能为空")
+	case r.KeepDays < 1:
+		return errors.New("保留天数至少为 1 天")
+	case r.BatchSize < 0 || r.BatchSize > MaxBatchSize:
+		return fmt.Errorf("每批行数须在 1 到 %d 之间", MaxBatchSize)
+	case r.PauseMs < 0 || time.Duration(r.PauseMs)*time.Millisecond > MaxPause:
+		return fmt.Errorf("批次间隔须在 0 到 %d 毫秒之间", MaxPause.Milliseconds())
+	}
+	return nil
+}
+
+// clone 返回不共享执行记录的副本。
+func clone(r *Rule) *Rule {
+	c := *r
+	if r.LastRun != nil {
+		run := *r.LastRun
+		c.LastRun = &run
+	}
+	return &c
+}
+
+// persistLocked 将规则写入文件，先写临时文件再替换，调用方需持有锁。
+func (s *Store) persistLocked() error {
+	list := make([]*Rule, 0, len(s.rules))
+	for _, r := range s.rules {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化数据保留规则失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入数据保留规则失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入数据保留规则失败：%w", err)
+	}
+	s.logger.Debug("数据保留规则已保存", "count", len(list))
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreSaveLoadDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	s := NewStore(path, nil)
+
+	r, err := s.Save(&Rule{Name: " 清理审计日志 ", Database: "app", Table: "audit_log", TimestampColumn: "created_at", KeepDays: 90})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if r.ID == "" || r.Name != "清理审计日志" || r.EffectiveBatchSize() != DefaultBatchSize {
+		t.Fatalf("新建规则字段错误: %+v", r)
+	}
+	for _, bad := range []*Rule{
+		{Name: "x", Table: "t", TimestampColumn: "c", KeepDays: 0},
+		{Name: "x", Table: "t", TimestampColumn: "c", KeepDays: 1, BatchSize: MaxBatchSize + 1},
+		{Name: "x", Table: "t", KeepDays: 1},
+		{Name: "x", Table: "t", TimestampColumn: "c", KeepDays: 1, PauseMs: -1},
+	} {
+		if _, err := s.Save(bad); err == nil {
+			t.Errorf("无效规则应返回错误: %+v", bad)
+		}
+	}
+	if _, err := s.Save(&Rule{ID: "missing", Name: "x", Table: "t", TimestampColumn: "c", KeepDays: 1}); !errors.Is(err, ErrRuleNotFound) {
+		t.Fatalf("更新不存在的规则应返回 ErrRuleNotFound, got %v", err)
+	}
+
+	run := &Run{Deleted: 1500, Batches: 2}
+	if err := s.RecordRun(r.ID, run); err != nil {
+		t.Fatal(err)
+	}
+	r.KeepDays = 30
+	updated, err := s.Save(r)
+	if err != nil || updated.LastRun == nil || updated.LastRun.Deleted != 1500 {
+		t.Fatalf("更新规则应保留执行记录: %+v, %v", updated, err)
+	}
+
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := reloaded.Get(r.ID)
+	if err != nil || got.KeepDays != 30 || got.LastRun == nil {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	if err := reloaded.Delete(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Fatal("删除后列表应为空")
+	}
+	if err := reloaded.Delete(r.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Fatalf("重复删除应返回 ErrRuleNotFound, got %v", err)
+	}
+}
+
+func TestRuleCheckConnection(t *testing.T) {
+	r := &Rule{ConnectionID: "conn-1"}
+	if err := r.CheckConnection("conn-1"); err != nil {
+		t.Errorf("同一连接应允许执行: %v", err)
+	}
+	if err := r.CheckConnection("conn-2"); !errors.Is(err, ErrConnectionMismatch) {
+		t.Errorf("其他连接应被拒绝: %v", err)
+	}
+	if err := (&Rule{}).CheckConnection(""); !errors.Is(err, ErrConnectionMismatch) {
+		t.Errorf("未关联连接的规则应被拒绝: %v", err)
+	}
+}
+
+func TestRuleCutoff(t *testing.T) {
+	now := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	r := &Rule{KeepDays: 30}
+	if got := r.Cutoff(now); !got.Equal(time.Date(2024, 2, 9, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Cutoff() = %v", got)
+	}
+}
@@ -0,0 +1,469 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler 在应用运行期间按 cron 表达式定时执行保存的查询或导出任务。
+//
+// 任务定义与执行历史保存在一个 JSON 文件中；连接密码只保存在内存中，应用重启后需重新提供。
+// 具体执行逻辑由调用方以 Runner 注入，每次执行结束后通过 Listener 通知（用于事件推送与失败提醒）。
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultCheckInterval 默认检查到期任务的间隔。
+	DefaultCheckInterval = 20 * time.Second
+	// DefaultRunTimeout 默认单次执行超时。
+	DefaultRunTimeout = time.Hour
+	// maxHistory 保留的执行记录条数（全部任务合计）。
+	maxHistory = 500
+)
+
+// ErrTaskNotFound 任务不存在。
+var ErrTaskNotFound = errors.New("定时任务不存在")
+
+// Runner 执行任务，config 为包含密码的完整连接配置。
+type Runner func(ctx context.Context, task *Task, config *connection.ConnectionConfig) (*RunResult, error)
+
+// Listener 在每次执行结束后调用。
+type Listener func(task Task, run Run)
+
+// state 是持久化文件的内容。
+type state struct {
+	Tasks   []*Task `json:"tasks"`
+	History []Run   `json:"history"`
+}
+
+// Scheduler 管理定时任务，可并发使用。
+type Scheduler struct {
+	mu       sync.Mutex
+	path     string
+	logger   *slog.Logger
+	runner   Runner
+	listener Listener
+	tasks    map[string]*Task
+	history  []Run
+	secrets  map[string]*connection.ConnectionConfig // 任务 ID → 含密码的连接配置（仅内存）
+	running  map[string]bool
+	ctx      context.Context
+	wg       sync.WaitGroup
+	now      func() time.Time
+}
+
+// DefaultPath 返回默认的任务文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "schedules.json")
+	}
+	return filepath.Join(configDir, "Boxify", "schedules.json")
+}
+
+// New 创建调度器，path 为空时使用默认路径；需调用 Load 读取已保存的任务。
+func New(path string, runner Runner, logger *slog.Logger) *Scheduler {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{
+		path:    path,
+		logger:  logger.With("module", "scheduler"),
+		runner:  runner,
+		tasks:   make(map[string]*Task),
+		secrets: make(map[string]*connection.ConnectionConfig),
+		running: make(map[string]bool),
+		ctx:     context.Background(),
+		now:     time.Now,
+	}
+}
+
+// SetListener 设置执行结束监听者。
+func (s *Scheduler) SetListener(l Listener) {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+}
+
+// Load 读取任务文件，文件不存在时视为空；启用任务的下一次触发时间从当前时间重新计算，错过的触发不补跑。
+func (s *Scheduler) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取定时任务失败：%w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("解析定时任务失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.tasks = make(map[string]*Task, len(st.Tasks))
+	for _, t := range st.Tasks {
+		s.scheduleLocked(t, now)
+		s.tasks[t.ID] = t
+	}
+	s.history = st.History
+	return nil
+}
+
+// Start 启动后台检查循环，ctx 取消时停止并取消执行中的任务。
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.tick(s.now())
+			}
+		}
+	}()
+}
+
+// Wait 等待检查循环与执行中的任务返回。
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Save 创建或更新任务（ID 为空时创建）。task.Connection 可包含密码，密码只保存在内存中。
+func (s *Scheduler) Save(task *Task) (*Task, error) {
+	if task == nil {
+		return nil, errors.New("任务不能为空")
+	}
+	if err := task.validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	saved := *task
+	saved.Kind = strings.ToLower(saved.Kind)
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+	} else {
+		old, ok := s.tasks[saved.ID]
+		if !ok {
+			return nil, ErrTaskNotFound
+		}
+		saved.CreatedAt, saved.LastRun = old.CreatedAt, old.LastRun
+	}
+	saved.UpdatedAt = now
+	if saved.Kind != KindExport {
+		saved.Export = nil
+	}
+	if saved.Kind != KindBackup {
+		saved.Backup = nil
+	}
+
+	stripped, hadSecret := stripSecrets(task.Connection)
+	saved.Connection = stripped
+	// 编辑时未重新填写密码但仍指向同一服务器，沿用内存中的密码
+	old := s.tasks[saved.ID]
+	keep := !hadSecret && old != nil && old.NeedsCredentials && sameServer(old.Connection, stripped)
+	switch {
+	case hadSecret:
+		s.secrets[saved.ID] = task.Connection.Clone()
+	case keep:
+		if prev, ok := s.secrets[saved.ID]; ok {
+			s.secrets[saved.ID] = withSecretsFrom(stripped, prev)
+		}
+	default:
+		delete(s.secrets, saved.ID)
+	}
+	saved.NeedsCredentials = hadSecret || keep
+
+	s.scheduleLocked(&saved, now)
+	s.tasks[saved.ID] = &saved
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	return s.copyLocked(&saved), nil
+}
+
+// SetCredentials 为任务重新提供含密码的连接配置，需与任务记录指向同一服务器与账号。
+func (s *Scheduler) SetCredentials(id string, config *connection.ConnectionConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if config == nil || !sameServer(t.Connection, config) {
+		return errors.New("连接配置与任务记录不一致")
+	}
+	s.secrets[id] = config.Clone()
+	return nil
+}
+
+// Delete 删除任务及其执行记录。
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[id]; !ok {
+		return ErrTaskNotFound
+	}
+	delete(s.tasks, id)
+	delete(s.secrets, id)
+	kept := s.history[:0]
+	for _, r := range s.history {
+		if r.TaskID != id {
+			kept = append(kept, r)
+		}
+	}
+	s.history = kept
+	return s.persistLocked()
+}
+
+// SetEnabled 启用或停用任务。
+func (s *Scheduler) SetEnabled(id string, enabled bool) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	t.Enabled = enabled
+	t.UpdatedAt = s.now()
+	s.scheduleLocked(t, t.UpdatedAt)
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	return s.copyLocked(t), nil
+}
+
+// List 返回全部任务，按名称排序。
+func (s *Scheduler) List() []*Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]*Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, s.copyLocked(t))
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Name != tasks[j].Name {
+			return tasks[i].Name < tasks[j].Name
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks
+}
+
+// History 返回执行记录（最新在前）；id 为空时返回全部任务的记录，limit <= 0 表示不限制。
+func (s *Scheduler) History(id string, limit int) []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make([]Run, 0)
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if id != "" && s.history[i].TaskID != id {
+			continue
+		}
+		runs = append(runs, s.history[i])
+		if limit > 0 && len(runs) >= limit {
+			break
+		}
+	}
+	return runs
+}
+
+// RunNow 立即在后台执行一次任务（不影响计划时间）。
+func (s *Scheduler) RunNow(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if s.running[id] {
+		return errors.New("任务正在执行")
+	}
+	s.launchLocked(t, TriggerManual)
+	return nil
+}
+
+// tick 启动所有到期的启用任务，并计算其下一次触发时间。
+func (s *Scheduler) tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tasks {
+		if !t.Enabled || t.NextRun == nil || t.NextRun.After(now) {
+			continue
+		}
+		s.scheduleLocked(t, now)
+		if s.running[t.ID] {
+			s.logger.Warn("上一次执行尚未结束，跳过本次触发", "taskId", t.ID, "name", t.Name)
+			continue
+		}
+		s.launchLocked(t, TriggerSchedule)
+	}
+}
+
+// launchLocked 在后台执行任务，调用方需持有锁。
+func (s *Scheduler) launchLocked(t *Task, trigger string) {
+	task := s.copyLocked(t)
+	config, ok := s.secrets[t.ID]
+	if !ok && !t.NeedsCredentials {
+		config = task.Connection
+	}
+	s.running[t.ID] = true
+	ctx := s.ctx
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		run := Run{TaskID: task.ID, TaskName: task.Name, Trigger: trigger, StartedAt: s.now()}
+		var (
+			result *RunResult
+			err    error
+		)
+		if config == nil {
+			err = ErrCredentialsRequired
+		} else {
+			runCtx, cancel := context.WithTimeout(ctx, DefaultRunTimeout)
+			result, err = s.call(runCtx, task, config)
+			cancel()
+		}
+		run.FinishedAt = s.now()
+		if err != nil {
+			run.Status, run.Message = RunFailed, err.Error()
+		} else {
+			run.Status = RunSucceeded
+			if result != nil {
+				run.Rows, run.File, run.Message = result.Rows, result.File, result.Message
+			}
+		}
+		s.finish(task, run)
+	}()
+}
+
+// call 调用执行函数，将 panic 转为错误。
+func (s *Scheduler) call(ctx context.Context, task *Task, config *connection.ConnectionConfig) (result *RunResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("定时任务异常", "taskId", task.ID, "panic", r)
+			err = fmt.Errorf("任务异常: %v", r)
+		}
+	}()
+	return s.runner(ctx, task, config)
+}
+
+// finish 记录执行结果并通知监听者。
+func (s *Scheduler) finish(task *Task, run Run) {
+	s.mu.Lock()
+	delete(s.running, task.ID)
+	s.history = append(s.history, run)
+	if len(s.history) > maxHistory {
+		s.history = append([]Run(nil), s.history[len(s.history)-maxHistory:]...)
+	}
+	if t, ok := s.tasks[task.ID]; ok {
+		r := run
+		t.LastRun = &r
+	}
+	if err := s.persistLocked(); err != nil {
+		s.logger.Warn("保存执行记录失败", "taskId", task.ID, "error", err)
+	}
+	listener := s.listener
+	s.mu.Unlock()
+
+	s.logger.Info("定时任务执行结束", "taskId", task.ID, "name", task.Name, "trigger", run.Trigger,
+		"status", run.Status, "durationMs", run.FinishedAt.Sub(run.StartedAt).Milliseconds(), "message", run.Message)
+	if listener != nil {
+		listener(*task, run)
+	}
+}
+
+// scheduleLocked 根据启用状态计算下一次触发时间，调用方需持有锁。
+func (s *Scheduler) scheduleLocked(t *Task, now time.Time) {
+	t.NextRun = nil
+	if !t.Enabled {
+		return
+	}
+	sched, err := ParseSchedule(t.Cron)
+	if err != nil {
+		s.logger.Warn("定时任务的 cron 表达式无效", "taskId", t.ID, "cron", t.Cron, "error", err)
+		return
+	}
+	if next := sched.Next(now); !next.IsZero() {
+		t.NextRun = &next
+	}
+}
+
+// copyLocked 返回任务副本，避免调用方修改内部状态，调用方需持有锁。
+func (s *Scheduler) copyLocked(t *Task) *Task {
+	c := *t
+	if t.Connection != nil {
+		c.Connection = t.Connection.Clone()
+	}
+	if t.Export != nil {
+		export := *t.Export
+		c.Export = &export
+	}
+	return &c
+}
+
+// persistLocked 将任务与执行记录写入文件，先写临时文件再替换，调用方需持有锁。
+func (s *Scheduler) persistLocked() error {
+	st := state{Tasks: make([]*Task, 0, len(s.tasks)), History: s.history}
+	for _, t := range s.tasks {
+		st.Tasks = append(st.Tasks, t)
+	}
+	sort.Slice(st.Tasks, func(i, j int) bool { return st.Tasks[i].CreatedAt.Before(st.Tasks[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化定时任务失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建定时任务目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入定时任务失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入定时任务失败：%w", err)
+	}
+	return nil
+}
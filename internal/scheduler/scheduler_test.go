@@ -0,0 +1,284 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// fakeRunner 记录执行时收到的连接配置。
+type fakeRunner struct {
+	mu      sync.Mutex
+	configs []*connection.ConnectionConfig
+	err     error
+}
+
+func (f *fakeRunner) run(ctx context.Context, task *Task, config *connection.ConnectionConfig) (*RunResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs = append(f.configs, config)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &RunResult{Rows: 3, Message: "ok"}, nil
+}
+
+func newTestTask() *Task {
+	return &Task{
+		Name:       "nightly",
+		Kind:       KindExport,
+		Cron:       "0 2 * * *",
+		Enabled:    true,
+		Connection: &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "db", Port: 3306, User: "root", Password: "secret"},
+		Query:      "SELECT * FROM report",
+		Export:     &ExportTarget{Format: "csv", Path: "/tmp/report_{date}.csv"},
+	}
+}
+
+func TestSaveStripsPasswordAndSchedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	s := New(path, (&fakeRunner{}).run, nil)
+	s.now = func() time.Time { return time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local) }
+
+	saved, err := s.Save(newTestTask())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved.ID == "" || saved.Connection.Password != "" || !saved.NeedsCredentials {
+		t.Errorf("保存的任务不应包含密码: %+v", saved.Connection)
+	}
+	if saved.NextRun == nil || !saved.NextRun.Equal(time.Date(2026, 3, 15, 2, 0, 0, 0, time.Local)) {
+		t.Errorf("NextRun = %v", saved.NextRun)
+	}
+
+	// 重新加载后密码不存在，执行需重新提供凭据
+	reloaded := New(path, (&fakeRunner{}).run, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tasks := reloaded.List()
+	if len(tasks) != 1 || tasks[0].Connection.Password != "" || !tasks[0].NeedsCredentials {
+		t.Fatalf("重新加载的任务不符: %+v", tasks)
+	}
+	if err := reloaded.SetCredentials(saved.ID, &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "other", Port: 3306, User: "root"}); err == nil {
+		t.Error("指向其他服务器的凭据应被拒绝")
+	}
+}
+
+func TestTickRunsDueTasksAndRecordsHistory(t *testing.T) {
+	runner := &fakeRunner{}
+	s := New(filepath.Join(t.TempDir(), "schedules.json"), runner.run, nil)
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+
+	var runs []Run
+	s.SetListener(func(task Task, run Run) { runs = append(runs, run) })
+
+	saved, err := s.Save(newTestTask())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	s.tick(now)
+	s.Wait()
+	if len(runs) != 0 {
+		t.Fatalf("未到期的任务不应执行")
+	}
+
+	now = time.Date(2026, 3, 15, 2, 0, 30, 0, time.Local)
+	s.tick(now)
+	s.Wait()
+	if len(runs) != 1 || runs[0].Status != RunSucceeded || runs[0].Rows != 3 || runs[0].Trigger != TriggerSchedule {
+		t.Fatalf("执行记录不符: %+v", runs)
+	}
+	if runner.configs[0].Password != "secret" {
+		t.Error("执行时应使用内存中的密码")
+	}
+	task := s.List()[0]
+	if task.LastRun == nil || !task.NextRun.Equal(time.Date(2026, 3, 16, 2, 0, 0, 0, time.Local)) {
+		t.Errorf("执行后状态不符: lastRun=%v nextRun=%v", task.LastRun, task.NextRun)
+	}
+
+	// 停用后不再触发
+	if _, err := s.SetEnabled(saved.ID, false); err != nil {
+		t.Fatalf("SetEnabled: %v", err)
+	}
+	now = time.Date(2026, 3, 16, 2, 0, 0, 0, time.Local)
+	s.tick(now)
+	s.Wait()
+	if len(runs) != 1 {
+		t.Errorf("停用的任务不应执行")
+	}
+	if h := s.History(saved.ID, 0); len(h) != 1 {
+		t.Errorf("History = %d 条, want 1", len(h))
+	}
+}
+
+func TestRunFailuresAndMissingCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	runner := &fakeRunner{err: errors.New("boom")}
+	s := New(path, runner.run, nil)
+	var runs []Run
+	s.SetListener(func(task Task, run Run) { runs = append(runs, run) })
+
+	saved, err := s.Save(newTestTask())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.RunNow(saved.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	s.Wait()
+	if len(runs) != 1 || runs[0].Status != RunFailed || runs[0].Message != "boom" || runs[0].Trigger != TriggerManual {
+		t.Fatalf("失败记录不符: %+v", runs)
+	}
+
+	reloaded := New(path, runner.run, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	reloaded.SetListener(func(task Task, run Run) { runs = append(runs, run) })
+	if err := reloaded.RunNow(saved.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	reloaded.Wait()
+	if len(runs) != 2 || runs[1].Message != ErrCredentialsRequired.Error() {
+		t.Errorf("缺少凭据时应失败: %+v", runs)
+	}
+	if len(runner.configs) != 1 {
+		t.Errorf("缺少凭据时不应调用执行函数")
+	}
+	if h := reloaded.History("", 0); len(h) != 2 || h[0].Message != ErrCredentialsRequired.Error() {
+		t.Errorf("执行记录应持久化且最新在前: %+v", h)
+	}
+}
+
+func TestSaveKeepsCredentialsWhenEditing(t *testing.T) {
+	runner := &fakeRunner{}
+	s := New(filepath.Join(t.TempDir(), "schedules.json"), runner.run, nil)
+	saved, err := s.Save(newTestTask())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	edit := *saved
+	edit.Name = "renamed"
+	if _, err := s.Save(&edit); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.RunNow(saved.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	s.Wait()
+	if len(runner.configs) != 1 || runner.configs[0].Password != "secret" {
+		t.Errorf("未修改服务器的编辑应沿用内存中的密码")
+	}
+}
+
+func TestSaveStripsAndRestoresProxyPasswords(t *testing.T) {
+	runner := &fakeRunner{}
+	s := New(filepath.Join(t.TempDir(), "schedules.json"), runner.run, nil)
+	task := newTestTask()
+	task.Connection.Proxy = &connection.ProxyConfig{Type: connection.ProxyTypeSOCKS5, Host: "proxy", Port: 1080, User: "p", Password: "proxy-secret"}
+	task.Connection.SSH = &connection.SSHConfig{Host: "jump", Proxy: &connection.ProxyConfig{Type: connection.ProxyTypeHTTP, Host: "hp", Port: 8080, Password: "ssh-proxy-secret"}}
+	saved, err := s.Save(task)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved.Connection.Proxy.Password != "" || saved.Connection.SSH.Proxy.Password != "" {
+		t.Fatalf("保存的任务不应包含代理密码: %+v %+v", saved.Connection.Proxy, saved.Connection.SSH.Proxy)
+	}
+	if task.Connection.Proxy.Password != "proxy-secret" {
+		t.Fatal("Save 不应修改传入的代理配置")
+	}
+
+	edit := *saved
+	edit.Name = "renamed"
+	if _, err := s.Save(&edit); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.RunNow(saved.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	s.Wait()
+	got := runner.configs[0]
+	if got.Proxy.Password != "proxy-secret" || got.SSH.Proxy.Password != "ssh-proxy-secret" {
+		t.Errorf("编辑后执行应沿用内存中的代理密码: %+v %+v", got.Proxy, got.SSH.Proxy)
+	}
+}
+
+func TestSaveValidation(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "schedules.json"), (&fakeRunner{}).run, nil)
+	cases := []func(*Task){
+		func(t *Task) { t.Name = "" },
+		func(t *Task) { t.Cron = "bad" },
+		func(t *Task) { t.Query = " " },
+		func(t *Task) { t.Kind = "dump" },
+		func(t *Task) { t.Export = nil },
+		func(t *Task) { t.Export.Format = "pdf" },
+		func(t *Task) { t.Connection = nil },
+	}
+	for i, mutate := range cases {
+		task := newTestTask()
+		mutate(task)
+		if _, err := s.Save(task); err == nil {
+			t.Errorf("用例 %d 应返回错误", i)
+		}
+	}
+	if _, err := s.SetEnabled("missing", true); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("未知任务应返回 ErrTaskNotFound，得到 %v", err)
+	}
+}
+
+func TestSaveBackupTask(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "schedules.json"), (&fakeRunner{}).run, nil)
+	task := newTestTask()
+	task.Kind, task.Query = KindBackup, ""
+	task.Backup = &BackupSettings{KeepLast: 7, KeepWeekly: 4, Verify: true}
+	saved, err := s.Save(task)
+	if err != nil {
+		t.Fatalf("备份任务不需要 SQL: %v", err)
+	}
+	if saved.Export != nil || saved.Backup == nil || saved.Backup.KeepLast != 7 {
+		t.Errorf("备份任务应只保留备份设置: %+v", saved)
+	}
+
+	for i, mutate := range []func(*Task){
+		func(t *Task) { t.Backup = nil },
+		func(t *Task) { t.Backup.KeepLast = -1 },
+		func(t *Task) { t.Backup.KeepWeekly = -1 },
+	} {
+		bad := newTestTask()
+		bad.Kind = KindBackup
+		bad.Backup = &BackupSettings{}
+		mutate(bad)
+		if _, err := s.Save(bad); err == nil {
+			t.Errorf("用例 %d 应返回错误", i)
+		}
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	at := time.Date(2026, 3, 14, 2, 5, 9, 0, time.Local)
+	if got := ExpandPath("/out/report_{date}_{datetime}.csv", at); got != "/out/report_20260314_20260314-020509.csv" {
+		t.Errorf("ExpandPath = %q", got)
+	}
+}
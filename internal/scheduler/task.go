@@ -0,0 +1,192 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
+)
+
+// 定时任务类型
+const (
+	KindQuery  = "query"  // 执行保存的 SQL
+	KindExport = "export" // 执行查询并导出到文件
+	KindBackup = "backup" // 将数据库备份为本地快照归档
+)
+
+// 触发方式
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// RunStatus 是单次执行的结果状态。
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// ErrCredentialsRequired 任务的连接密码只保存在内存中，应用重启后需重新提供。
+var ErrCredentialsRequired = errors.New("应用重启后需要重新提供连接密码")
+
+// ExportTarget 是导出任务的输出设置。
+type ExportTarget struct {
+	Format      string  `json:"format"`                // 导出格式，见 dataexport.Formats
+	Path        string  `json:"path"`                  // 输出文件路径，支持 {date}（20060102）与 {datetime}（20060102-150405）占位符
+	NullValue   *string `json:"nullValue,omitempty"`   // NULL 的文本表示，未设置时为 "NULL"
+	DateFormat  string  `json:"dateFormat,omitempty"`  // 日期时间格式
+	Compression string  `json:"compression,omitempty"` // 压缩算法，为空时使用格式默认值
+	InferSchema *bool   `json:"inferSchema,omitempty"` // 按列值推断列类型（parquet），未设置时默认开启
+}
+
+// BackupSettings 是备份任务的设置。KeepLast 与 KeepWeekly 均 <= 0 时保留全部备份。
+type BackupSettings struct {
+	Tables         []string `json:"tables,omitempty"`         // 待备份的表，为空时备份数据库中的全部表
+	KeepLast       int      `json:"keepLast"`                 // 保留最近的 N 份备份
+	KeepWeekly     int      `json:"keepWeekly"`               // 另外为最近 N 个自然周各保留一份（该周最新的备份）
+	Verify         bool     `json:"verify"`                   // 备份后校验归档，指定校验数据库时还试恢复表结构
+	VerifyDatabase string   `json:"verifyDatabase,omitempty"` // 试恢复表结构的独立数据库，不能是被备份的数据库；为空时只校验归档。试恢复使用临时表名并在结束后删除
+}
+
+// Task 是一个定时任务。连接配置持久化时不含密码。
+type Task struct {
+	ID               string                       `json:"id"`
+	Name             string                       `json:"name"`
+	Kind             string                       `json:"kind"`               // 任务类型，见 Kind* 常量
+	Cron             string                       `json:"cron"`               // cron 表达式（分 时 日 月 周）
+	Enabled          bool                         `json:"enabled"`            // 是否启用
+	Connection       *connection.ConnectionConfig `json:"connection"`         // 连接配置（不含密码）
+	NeedsCredentials bool                         `json:"needsCredentials"`   // 连接需要密码，重启后需重新提供
+	Database         string                       `json:"database,omitempty"` // 数据库名
+	Query            string                       `json:"query"`              // 要执行的 SQL
+	Export           *ExportTarget                `json:"export,omitempty"`   // 导出设置，仅 export 任务
+	Backup           *BackupSettings              `json:"backup,omitempty"`   // 备份设置，仅 backup 任务
+	NextRun          *time.Time                   `json:"nextRun,omitempty"`  // 下一次触发时间，停用时为空
+	LastRun          *Run                         `json:"lastRun,omitempty"`  // 最近一次执行
+	CreatedAt        time.Time                    `json:"createdAt"`          // 创建时间
+	UpdatedAt        time.Time                    `json:"updatedAt"`          // 更新时间
+}
+
+// Run 是一次执行记录。
+type Run struct {
+	TaskID     string    `json:"taskId"`
+	TaskName   string    `json:"taskName"`
+	Trigger    string    `json:"trigger"`        // 触发方式，见 Trigger* 常量
+	Status     RunStatus `json:"status"`         // 执行结果
+	StartedAt  time.Time `json:"startedAt"`      // 开始时间
+	FinishedAt time.Time `json:"finishedAt"`     // 结束时间
+	Message    string    `json:"message"`        // 结果说明或失败原因
+	Rows       int64     `json:"rows"`           // 返回或影响的行数
+	File       string    `json:"file,omitempty"` // 导出文件路径，备份任务为快照 ID
+}
+
+// RunResult 是执行函数返回的结果。
+type RunResult struct {
+	Rows    int64
+	File    string
+	Message string
+}
+
+// validate 校验任务定义。
+func (t *Task) validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("任务名称不能为空")
+	}
+	if _, err := ParseSchedule(t.Cron); err != nil {
+		return err
+	}
+	if t.Connection == nil {
+		return errors.New("连接配置不能为空")
+	}
+	if t.Kind != KindBackup && strings.TrimSpace(t.Query) == "" {
+		return errors.New("SQL 不能为空")
+	}
+	switch t.Kind {
+	case KindQuery:
+	case KindExport:
+		if t.Export == nil || strings.TrimSpace(t.Export.Path) == "" {
+			return errors.New("导出任务需要指定输出文件路径")
+		}
+		if err := dataexport.CheckOptions(t.Export.Format, &dataexport.Options{Compression: t.Export.Compression}); err != nil {
+			return err
+		}
+	case KindBackup:
+		if t.Backup == nil {
+			return errors.New("备份任务需要指定备份设置")
+		}
+		if t.Backup.KeepLast < 0 || t.Backup.KeepWeekly < 0 {
+			return errors.New("备份保留数量不能为负数")
+		}
+	default:
+		return fmt.Errorf("不支持的任务类型: %s", t.Kind)
+	}
+	return nil
+}
+
+// ExpandPath 将输出路径中的日期占位符替换为 at 对应的时间。
+func ExpandPath(path string, at time.Time) string {
+	return strings.NewReplacer(
+		"{date}", at.Format("20060102"),
+		"{datetime}", at.Format("20060102-150405"),
+	).Replace(path)
+}
+
+// stripSecrets 返回去掉数据库、SSH 与代理密码的连接配置副本，以及原配置是否包含密码。
+func stripSecrets(config *connection.ConnectionConfig) (*connection.ConnectionConfig, bool) {
+	stripped := config.Clone()
+	hadSecret := stripped.Password != ""
+	stripped.Password = ""
+	if stripped.SSH != nil {
+		hadSecret = hadSecret || stripped.SSH.Password != ""
+		stripped.SSH.Password = ""
+		if stripped.SSH.Proxy != nil {
+			hadSecret = hadSecret || stripped.SSH.Proxy.Password != ""
+			stripped.SSH.Proxy.Password = ""
+		}
+	}
+	if stripped.Proxy != nil {
+		hadSecret = hadSecret || stripped.Proxy.Password != ""
+		stripped.Proxy.Password = ""
+	}
+	return stripped, hadSecret
+}
+
+// withSecretsFrom 返回 config 的副本，数据库、SSH 与代理密码取自 secrets。
+func withSecretsFrom(config, secrets *connection.ConnectionConfig) *connection.ConnectionConfig {
+	merged := config.Clone()
+	merged.Password = secrets.Password
+	if merged.SSH != nil && secrets.SSH != nil {
+		merged.SSH.Password = secrets.SSH.Password
+		if merged.SSH.Proxy != nil && secrets.SSH.Proxy != nil {
+			merged.SSH.Proxy.Password = secrets.SSH.Proxy.Password
+		}
+	}
+	if merged.Proxy != nil && secrets.Proxy != nil {
+		merged.Proxy.Password = secrets.Proxy.Password
+	}
+	return merged
+}
+
+// sameServer 判断两份连接配置是否指向同一服务器与账号。
+func sameServer(a, b *connection.ConnectionConfig) bool {
+	return a.Type == b.Type && a.Host == b.Host && a.Port == b.Port && a.User == b.User
+}
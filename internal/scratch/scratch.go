@@ -0,0 +1,225 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scratch 管理本地内嵌的 SQLite 草稿库：始终可用，无需服务器连接，
+// 用于粘贴 CSV、加载导出文件并与其他数据做关联查询或试写 SQL。
+package scratch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataimport"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// maxParams 是单条语句的参数上限，取旧版 SQLite 的 SQLITE_MAX_VARIABLE_NUMBER。
+const maxParams = 999
+
+// SQLite 列类型亲和性
+const (
+	typeInteger = "INTEGER"
+	typeReal    = "REAL"
+	typeText    = "TEXT"
+)
+
+// Execer 是写入草稿库所需的执行能力。
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (int64, error)
+}
+
+// DefaultPath 返回草稿库文件的默认路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "scratch.db")
+	}
+	return filepath.Join(configDir, "Boxify", "scratch.db")
+}
+
+// Config 返回草稿库的连接配置，并确保数据库文件所在目录存在；path 为空时使用默认路径。
+func Config(path string) (*connection.ConnectionConfig, error) {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("创建草稿库目录失败：%w", err)
+	}
+	return &connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: path}, nil
+}
+
+// LoadTable 将解析出的数据写入草稿库的 name 表，返回写入行数。
+//
+// 列类型按数据推断（全部为整数时为 INTEGER，全部为数字时为 REAL，否则为 TEXT），空字符串在数字列中视为 NULL。
+// replace 为 true 时先删除同名表，否则表已存在时报错。
+func LoadTable(ctx context.Context, exec Execer, name string, t *dataimport.Table, replace bool) (int64, error) {
+	if len(t.Headers) == 0 {
+		return 0, fmt.Errorf("数据没有列")
+	}
+	types := make([]string, len(t.Headers))
+	for i, h := range t.Headers {
+		types[i] = inferType(t.Rows, h)
+	}
+	table := db.QuoteIdent(connection.ConnectionTypeSQLite, name)
+	if replace {
+		if _, err := exec.ExecContext(ctx, "DROP TABLE IF EXISTS "+table); err != nil {
+			return 0, fmt.Errorf("删除已有表失败：%w", err)
+		}
+	}
+	if _, err := exec.ExecContext(ctx, createTableSQL(table, t.Headers, types)); err != nil {
+		return 0, fmt.Errorf("创建表失败：%w", err)
+	}
+
+	quoted := make([]string, len(t.Headers))
+	for i, h := range t.Headers {
+		quoted[i] = db.QuoteIdent(connection.ConnectionTypeSQLite, h)
+	}
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(t.Headers)), ", ") + ")"
+	batch := max(1, maxParams/len(t.Headers))
+	var written int64
+	for start := 0; start < len(t.Rows); start += batch {
+		end := min(start+batch, len(t.Rows))
+		groups := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*len(t.Headers))
+		for _, row := range t.Rows[start:end] {
+			groups = append(groups, placeholder)
+			for i, h := range t.Headers {
+				args = append(args, convertValue(row[h], types[i]))
+			}
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(quoted, ", "), strings.Join(groups, ", "))
+		if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+			return written, fmt.Errorf("写入第 %d 行起的数据失败：%w", start+1, err)
+		}
+		written += int64(end - start)
+	}
+	return written, nil
+}
+
+// createTableSQL 构造建表语句，重复的列名由数据库报错。
+func createTableSQL(table string, headers, types []string) string {
+	defs := make([]string, len(headers))
+	for i, h := range headers {
+		defs[i] = db.QuoteIdent(connection.ConnectionTypeSQLite, h) + " " + types[i]
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", table, strings.Join(defs, ", "))
+}
+
+// inferType 按列中的非空值推断 SQLite 类型，没有非空值时为 TEXT。
+func inferType(rows []map[string]interface{}, header string) string {
+	result := ""
+	for _, row := range rows {
+		kind := valueType(row[header])
+		switch {
+		case kind == "":
+			continue
+		case kind == typeText:
+			return typeText
+		case result == "" || kind == typeReal:
+			result = kind
+		}
+	}
+	if result == "" {
+		return typeText
+	}
+	return result
+}
+
+// valueType 返回单个值适合的类型，空值与空字符串返回空字符串。
+func valueType(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return textType(x)
+	case json.Number:
+		return textType(x.String())
+	case bool:
+		return typeInteger
+	case time.Time, []byte:
+		return typeText
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typeInteger
+	case reflect.Float32, reflect.Float64:
+		return typeReal
+	default:
+		return typeText
+	}
+}
+
+// textType 判断文本值是整数、小数还是普通文本。
+func textType(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return typeInteger
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return typeReal
+	}
+	return typeText
+}
+
+// convertValue 将值转换为列类型对应的 Go 值，数字列中的文本按数字解析。
+func convertValue(v interface{}, colType string) interface{} {
+	var text string
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case string:
+		text = x
+	case json.Number:
+		text = x.String()
+	case bool:
+		if x {
+			return int64(1)
+		}
+		return int64(0)
+	case time.Time:
+		return x.Format("2006-01-02 15:04:05")
+	default:
+		return v
+	}
+	trimmed := strings.TrimSpace(text)
+	switch colType {
+	case typeInteger:
+		if trimmed == "" {
+			return nil
+		}
+		if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return n
+		}
+	case typeReal:
+		if trimmed == "" {
+			return nil
+		}
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
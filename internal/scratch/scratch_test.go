@@ -0,0 +1,109 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scratch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/dataimport"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// fakeExec 记录执行的语句与参数。
+type fakeExec struct {
+	stmts []string
+	args  [][]any
+}
+
+func (f *fakeExec) ExecContext(ctx context.Context, query string, args ...any) (int64, error) {
+	f.stmts = append(f.stmts, query)
+	f.args = append(f.args, args)
+	return 0, nil
+}
+
+func TestLoadTableInfersTypes(t *testing.T) {
+	table, err := dataimport.ParseCSV(strings.NewReader("id,price,name,empty\n1,9.5,a,\n2,,b c,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &fakeExec{}
+	n, err := LoadTable(context.Background(), exec, "orders", table, true)
+	if err != nil || n != 2 {
+		t.Fatalf("写入失败: %v，行数 %d", err, n)
+	}
+	if exec.stmts[0] != `DROP TABLE IF EXISTS "orders"` {
+		t.Errorf("replace 应先删除同名表: %v", exec.stmts)
+	}
+	if want := `CREATE TABLE "orders" ("id" INTEGER, "price" REAL, "name" TEXT, "empty" TEXT)`; exec.stmts[1] != want {
+		t.Errorf("建表语句 = %s", exec.stmts[1])
+	}
+	args := exec.args[2]
+	if len(args) != 8 || args[0] != int64(1) || args[1] != 9.5 || args[2] != "a" || args[3] != "" || args[5] != nil {
+		t.Errorf("参数转换不符: %#v", args)
+	}
+}
+
+func TestLoadTableBatchesByParamLimit(t *testing.T) {
+	table := &dataimport.Table{Headers: make([]string, 500)}
+	for i := range table.Headers {
+		table.Headers[i] = fmt.Sprintf("c%d", i)
+	}
+	for i := 0; i < 5; i++ {
+		table.Rows = append(table.Rows, map[string]interface{}{})
+	}
+	exec := &fakeExec{}
+	if _, err := LoadTable(context.Background(), exec, "wide", table, false); err != nil {
+		t.Fatal(err)
+	}
+	// 500 列每批最多 1 行
+	if len(exec.stmts) != 6 || len(exec.args[1]) != 500 {
+		t.Errorf("应按参数上限分批写入: %d 条语句", len(exec.stmts))
+	}
+}
+
+func TestLoadTableIntoSQLite(t *testing.T) {
+	config, err := Config(filepath.Join(t.TempDir(), "nested", "scratch.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lite := db.NewSQLiteDB()
+	if err := lite.Connect(config); err != nil {
+		t.Fatalf("打开草稿库失败: %v", err)
+	}
+	defer lite.Close()
+
+	table, err := dataimport.ParseCSV(strings.NewReader("id,price,name\n1,9.5,a\n2,,b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if n, err := LoadTable(ctx, lite, "orders", table, false); err != nil || n != 2 {
+		t.Fatalf("写入失败: %v，行数 %d", err, n)
+	}
+	rows, _, err := lite.QueryContext(ctx, "SELECT typeof(id) AS t, SUM(price) AS total, COUNT(price) AS priced FROM orders GROUP BY 1")
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("查询失败: %v %v", rows, err)
+	}
+	if fmt.Sprint(rows[0]["t"]) != "integer" || fmt.Sprint(rows[0]["total"]) != "9.5" || fmt.Sprint(rows[0]["priced"]) != "1" {
+		t.Errorf("类型推断或空值处理不符: %v", rows[0])
+	}
+	if _, err := LoadTable(ctx, lite, "orders", table, false); err == nil {
+		t.Error("表已存在且 replace 为 false 时应报错")
+	}
+}
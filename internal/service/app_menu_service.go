@@ -0,0 +1,334 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/appmenu"
+	"github.com/chenyang-zz/boxify/internal/recents"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/icons"
+)
+
+const (
+	// AppMenuID 原生菜单栏点击事件中的菜单 ID
+	AppMenuID = "app-menu"
+	// TrayMenuID 系统托盘菜单点击事件中的菜单 ID
+	TrayMenuID = "tray"
+)
+
+// AppMenuService 管理原生应用菜单栏与系统托盘，菜单定义与状态更新逻辑在 internal/appmenu。
+//
+// 菜单项点击通过 menu:clicked 事件发送到前端（与上下文菜单相同），前端按焦点调用 UpdateAppMenuItems 启用或禁用菜单项。
+// 托盘菜单的最近连接跟随最近使用记录自动更新。
+type AppMenuService struct {
+	BaseService
+	mu      sync.Mutex
+	menubar []appmenu.Item                   // 当前菜单栏定义（已按平台过滤，含动态状态）
+	live    map[string]*application.MenuItem // 菜单栏中可更新的菜单项：ID -> Wails 菜单项
+	tray    *application.SystemTray
+	recent  []appmenu.RecentConnection
+	recents *recents.Store // 最近使用记录，为 nil 时托盘只显示 SetRecentConnections 设置的连接
+}
+
+// NewAppMenuService 创建应用菜单服务
+func NewAppMenuService(deps *ServiceDeps) *AppMenuService {
+	s := &AppMenuService{
+		BaseService: NewBaseService(deps),
+		live:        make(map[string]*application.MenuItem),
+	}
+	s.recents = deps.Recents()
+	return s
+}
+
+// ServiceStartup 服务启动，安装默认菜单栏并创建系统托盘
+func (s *AppMenuService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if err := s.DefaultServiceStartup(ctx, options); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.installMenubarLocked(s.defaultMenubar())
+	if s.recents != nil {
+		s.recent = recentConnections(s.recents)
+		s.recents.OnChange(func(kind recents.Kind) {
+			if kind == "" || kind == recents.KindConnection {
+				s.SetRecentConnections(recentConnections(s.recents))
+			}
+		})
+	}
+	s.createTrayLocked()
+	return nil
+}
+
+// ServiceShutdown 服务关闭
+func (s *AppMenuService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// GetApplicationMenu 获取当前菜单栏定义，包含动态启用状态与选中状态。
+func (s *AppMenuService) GetApplicationMenu() *types.AppMenuResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	menus := appmenu.Clone(s.menubar)
+	for id, item := range s.live {
+		if item.IsCheckbox() || item.IsRadio() {
+			appmenu.Find(menus, id).Checked = item.Checked()
+		}
+	}
+	return &types.AppMenuResult{BaseResult: types.BaseResult{Success: true, Message: "获取应用菜单成功"}, Data: menus}
+}
+
+// SetApplicationMenu 用自定义定义替换菜单栏，菜单项的 platforms 按当前平台过滤。
+func (s *AppMenuService) SetApplicationMenu(menus []appmenu.Item) *types.BaseResult {
+	menus = appmenu.ForPlatform(menus, runtime.GOOS)
+	if err := appmenu.Validate(menus); err != nil {
+		return &types.BaseResult{Success: false, Message: fmt.Sprintf("菜单定义无效: %v", err)}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.installMenubarLocked(menus)
+	return &types.BaseResult{Success: true, Message: "应用菜单已更新"}
+}
+
+// ResetApplicationMenu 恢复默认菜单栏。
+func (s *AppMenuService) ResetApplicationMenu() *types.BaseResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.installMenubarLocked(s.defaultMenubar())
+	return &types.BaseResult{Success: true, Message: "应用菜单已恢复默认"}
+}
+
+// UpdateAppMenuItems 按 ID 批量更新菜单项的启用状态、选中状态与标签，未知 ID 会在结果中列出。
+func (s *AppMenuService) UpdateAppMenuItems(updates []appmenu.ItemUpdate) *types.BaseResult {
+	v := validate.New()
+	for i, u := range updates {
+		v.Required(fmt.Sprintf("updates[%d].id", i), u.ID)
+	}
+	if err := v.Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	missing := appmenu.Apply(s.menubar, updates)
+	rebuild := false
+	for _, u := range updates {
+		item, ok := s.live[u.ID]
+		if !ok {
+			// 子菜单没有可直接更新的菜单项，需要重建菜单栏
+			rebuild = rebuild || appmenu.Find(s.menubar, u.ID) != nil
+			continue
+		}
+		if u.Enabled != nil {
+			item.SetEnabled(*u.Enabled)
+		}
+		if u.Checked != nil {
+			item.SetChecked(*u.Checked)
+		}
+		if u.Label != nil {
+			item.SetLabel(*u.Label)
+		}
+	}
+	if rebuild {
+		s.installMenubarLocked(s.menubar)
+	}
+	if len(missing) > 0 {
+		return &types.BaseResult{Success: false, Message: fmt.Sprintf("菜单项不存在: %s", strings.Join(missing, ", "))}
+	}
+	return &types.BaseResult{Success: true, Message: "菜单项已更新"}
+}
+
+// SetRecentConnections 更新托盘菜单中的最近连接（按最近使用排序，最多显示 appmenu.MaxRecentConnections 个）。
+func (s *AppMenuService) SetRecentConnections(connections []appmenu.RecentConnection) *types.BaseResult {
+	v := validate.New()
+	for i, conn := range connections {
+		v.Required(fmt.Sprintf("connections[%d].id", i), conn.ID)
+		v.Required(fmt.Sprintf("connections[%d].name", i), conn.Name)
+	}
+	if err := v.Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent = append([]appmenu.RecentConnection(nil), connections...)
+	if s.tray != nil {
+		s.tray.SetMenu(s.buildMenu(appmenu.TrayMenu(s.recent), TrayMenuID, nil))
+	}
+	return &types.BaseResult{Success: true, Message: "最近连接已更新"}
+}
+
+// recentConnections 从最近使用记录中取托盘菜单显示的连接。
+func recentConnections(store *recents.Store) []appmenu.RecentConnection {
+	items := store.List(recents.KindConnection, appmenu.MaxRecentConnections)
+	list := make([]appmenu.RecentConnection, 0, len(items))
+	for _, it := range items {
+		list = append(list, appmenu.RecentConnection{ID: it.ConnectionID, Name: it.Title, Type: it.DBType})
+	}
+	return list
+}
+
+// defaultMenubar 返回当前平台的默认菜单栏定义，开发构建追加开发者菜单项。
+func (s *AppMenuService) defaultMenubar() []appmenu.Item {
+	dev := s.App() != nil && s.App().Env.Info().Debug
+	return appmenu.ForPlatform(appmenu.DefaultMenubar(dev), runtime.GOOS)
+}
+
+// installMenubarLocked 按定义构建并设置原生菜单栏；调用方需持有锁。
+func (s *AppMenuService) installMenubarLocked(menus []appmenu.Item) {
+	s.menubar = menus
+	s.live = make(map[string]*application.MenuItem)
+	if s.App() == nil {
+		return
+	}
+	s.App().Menu.SetApplicationMenu(s.buildMenu(menus, AppMenuID, s.live))
+	s.Logger().Info("应用菜单已安装", "menus", len(menus))
+}
+
+// createTrayLocked 创建系统托盘：单击显示主窗口，菜单提供快捷操作；调用方需持有锁。
+func (s *AppMenuService) createTrayLocked() {
+	if s.App() == nil || s.tray != nil {
+		return
+	}
+	tray := s.App().SystemTray.New()
+	if runtime.GOOS == "darwin" {
+		tray.SetTemplateIcon(icons.SystrayMacTemplate)
+	} else {
+		tray.SetIcon(icons.SystrayLight)
+		tray.SetDarkModeIcon(icons.SystrayDark)
+	}
+	tray.SetTooltip("Boxify")
+	tray.SetMenu(s.buildMenu(appmenu.TrayMenu(s.recent), TrayMenuID, nil))
+	tray.OnClick(func() {
+		s.showMainWindow()
+	})
+	s.tray = tray
+}
+
+// buildMenu 按定义构建 Wails 菜单，live 不为 nil 时记录带 ID 的菜单项。
+func (s *AppMenuService) buildMenu(items []appmenu.Item, menuID string, live map[string]*application.MenuItem) *application.Menu {
+	menu := application.NewMenu()
+	s.buildItems(menu, items, menuID, live)
+	return menu
+}
+
+// buildItems 递归构建菜单项。
+func (s *AppMenuService) buildItems(menu *application.Menu, items []appmenu.Item, menuID string, live map[string]*application.MenuItem) {
+	for _, def := range items {
+		var item *application.MenuItem
+		switch {
+		case def.Role != "":
+			role, _ := appmenu.Role(def.Role)
+			item = application.NewRole(role)
+			if item == nil {
+				continue
+			}
+			if def.Label != "" {
+				item.SetLabel(def.Label)
+			}
+			menu.Append(application.NewMenuFromItems(item))
+		case def.Type == appmenu.TypeSeparator:
+			menu.AddSeparator()
+			continue
+		case def.Type == appmenu.TypeSubmenu:
+			s.buildItems(menu.AddSubmenu(def.Label), def.Items, menuID, live)
+			continue
+		case def.Type == appmenu.TypeCheckbox:
+			item = menu.AddCheckbox(def.Label, def.Checked)
+		case def.Type == appmenu.TypeRadio:
+			item = menu.AddRadio(def.Label, def.Checked)
+		default:
+			item = menu.Add(def.Label)
+		}
+
+		if def.Shortcut != "" {
+			item.SetAccelerator(def.Shortcut)
+		}
+		if !def.IsEnabled() {
+			item.SetEnabled(false)
+		}
+		if def.Role == "" {
+			item.OnClick(func(ctx *application.Context) {
+				s.handleClick(menuID, def, ctx.IsChecked())
+			})
+		}
+		if live != nil && def.ID != "" {
+			live[def.ID] = item
+		}
+	}
+}
+
+// handleClick 处理菜单项点击：托盘的显示与退出在后端完成，其余操作发送 menu:clicked 事件交给前端。
+func (s *AppMenuService) handleClick(menuID string, def appmenu.Item, checked bool) {
+	window := ""
+	switch {
+	case menuID == TrayMenuID && def.ID == "tray.quit":
+		s.App().Quit()
+		return
+	case menuID == TrayMenuID:
+		window = s.showMainWindow()
+		if def.ID == "tray.show" || window == "" {
+			return
+		}
+	default:
+		if current := s.App().Window.Current(); current != nil {
+			window = current.Name()
+		}
+	}
+
+	s.App().Event.Emit("menu:clicked", MenuClickEvent{
+		MenuID:    menuID,
+		ItemID:    def.ID,
+		Type:      MenuItemType(def.Type),
+		Label:     def.Label,
+		Checked:   checked,
+		ItemData:  def.Data,
+		Timestamp: time.Now().Unix(),
+		Window:    window,
+	})
+	s.Logger().Debug("应用菜单点击事件已发送", "menuId", menuID, "itemId", def.ID, "window", window)
+}
+
+// showMainWindow 显示并聚焦主窗口，返回窗口名称；主窗口未创建（如未登录）时返回空字符串。
+func (s *AppMenuService) showMainWindow() string {
+	am := s.AppManager()
+	if am == nil {
+		return ""
+	}
+	mainConfig := am.GetPageConfig().GetMainPageConfig()
+	if mainConfig == nil || mainConfig.Window == nil {
+		return ""
+	}
+	w := am.GetWindow(mainConfig.Window.Name)
+	if w == nil {
+		s.Logger().Info("主窗口未打开，忽略托盘操作")
+		return ""
+	}
+	if w.IsMinimised() {
+		w.UnMinimise()
+	}
+	w.Show()
+	w.Focus()
+	return mainConfig.Window.Name
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/appdata"
+	"github.com/chenyang-zz/boxify/internal/settings"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// AppDataService 导出与导入应用数据（连接配置、保存的查询与应用设置），核心逻辑在 internal/appdata。
+//
+// 连接配置与查询由前端保存，导出时作为参数传入，导入时返回给前端合并；
+// 应用设置直接读写设置存储，导入时按需覆盖并广播 settings:changed。
+type AppDataService struct {
+	BaseService
+	settings *settings.Store
+}
+
+// NewAppDataService 创建应用数据导入导出服务
+func NewAppDataService(deps *ServiceDeps) *AppDataService {
+	return &AppDataService{
+		BaseService: NewBaseService(deps),
+		settings:    deps.Settings(),
+	}
+}
+
+// ServiceStartup 服务启动
+func (s *AppDataService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *AppDataService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// ExportAppData 选择保存位置，将连接配置、查询与（可选的）应用设置用口令加密导出。
+// 未选择包含凭据时去除密码与 DSN 中的凭据，适合分享给团队。
+func (s *AppDataService) ExportAppData(opts *appdata.ExportOptions) *types.AppDataExportResult {
+	v := validate.New().Check(opts != nil, "options", validate.CodeRequired, "options 不能为空")
+	if opts != nil {
+		if err := appdata.ValidatePassphrase(opts.Passphrase); err != nil {
+			v.Check(false, "passphrase", validate.CodeInvalid, err.Error())
+		}
+	}
+	if err := v.Err(); err != nil {
+		return &types.AppDataExportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	var current *settings.Settings
+	if s.settings != nil {
+		value := s.settings.Get()
+		current = &value
+	}
+	now := time.Now()
+	data := appdata.Build(*opts, current, now)
+	archive, err := appdata.Encrypt(data, opts.Passphrase)
+	if err != nil {
+		return &types.AppDataExportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	filename, err := runtime.SaveFileDialog(s.Context(), runtime.SaveDialogOptions{
+		Title:           "导出应用数据",
+		DefaultFilename: fmt.Sprintf("boxify-%s%s", now.Format("20060102-150405"), appdata.FileExtension),
+	})
+	if err != nil || filename == "" {
+		return &types.AppDataExportResult{BaseResult: types.BaseResult{Success: false, Message: "Cancelled"}}
+	}
+	if err := os.WriteFile(filename, archive, 0o600); err != nil {
+		s.Logger().Error("写入应用数据归档失败", "path", filename, "error", err)
+		return &types.AppDataExportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	s.Logger().Info("应用数据已导出", "path", filename, "connections", len(data.Connections),
+		"savedQueries", len(data.SavedQueries), "settings", data.Settings != nil, "secrets", data.IncludesSecrets)
+	return &types.AppDataExportResult{
+		BaseResult: types.BaseResult{Success: true, Message: "应用数据已导出"},
+		Data: &types.AppDataExportFile{
+			Path:            filename,
+			Size:            int64(len(archive)),
+			Connections:     len(data.Connections),
+			SavedQueries:    len(data.SavedQueries),
+			IncludesSecrets: data.IncludesSecrets,
+		},
+	}
+}
+
+// ImportAppData 选择归档文件并用口令解密，返回其中的连接配置与查询供前端合并；
+// applySettings 为 true 且归档包含应用设置时覆盖当前设置。
+func (s *AppDataService) ImportAppData(passphrase string, applySettings bool) *types.AppDataImportResult {
+	if err := validate.New().Required("passphrase", passphrase).Err(); err != nil {
+		return &types.AppDataImportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	filename, err := runtime.OpenFileDialog(s.Context(), runtime.OpenDialogOptions{
+		Title: "导入应用数据",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Boxify Data", Pattern: "*" + appdata.FileExtension},
+		},
+	})
+	if err != nil || filename == "" {
+		return &types.AppDataImportResult{BaseResult: types.BaseResult{Success: false, Message: "Cancelled"}}
+	}
+	archive, err := readAppDataArchive(filename)
+	if err != nil {
+		return &types.AppDataImportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	data, err := appdata.Decrypt(archive, passphrase)
+	if err != nil {
+		s.Logger().Warn("解密应用数据归档失败", "path", filename, "error", err)
+		return &types.AppDataImportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	result := &types.AppDataImportResult{BaseResult: types.BaseResult{Success: true, Message: "应用数据已读取"}, Data: data, Path: filename}
+	if applySettings && data.Settings != nil && s.settings != nil {
+		old, saved, err := s.settings.Update(*data.Settings)
+		if err != nil {
+			s.Logger().Warn("导入应用设置失败", "path", filename, "error", err)
+			result.Message = "应用数据已读取，但应用设置无效未导入：" + err.Error()
+			return result
+		}
+		applySettingsChange(&s.BaseService, old, saved)
+		result.SettingsApplied = true
+	}
+
+	s.Logger().Info("应用数据已导入", "path", filename, "connections", len(data.Connections),
+		"savedQueries", len(data.SavedQueries), "settingsApplied", result.SettingsApplied)
+	return result
+}
+
+// readAppDataArchive 读取归档文件，超过大小上限时报错
+func readAppDataArchive(filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, appdata.MaxArchiveBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > appdata.MaxArchiveBytes {
+		return nil, fmt.Errorf("归档文件超过 %d MB 上限", appdata.MaxArchiveBytes>>20)
+	}
+	return data, nil
+}
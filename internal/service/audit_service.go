@@ -0,0 +1,125 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// AuditService 提供写操作审计日志的查询与导出。
+//
+// 各服务通过共享的 audit.Log（BaseService.Audit）记录经由 Boxify 执行的数据修改操作，
+// 日志仅追加写入本地文件，用于合规审查。
+type AuditService struct {
+	BaseService
+}
+
+// NewAuditService 创建审计日志服务
+func NewAuditService(deps *ServiceDeps) *AuditService {
+	return &AuditService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动
+func (s *AuditService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *AuditService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// QueryAuditLog 按条件分页查询审计记录（按时间倒序）。
+func (s *AuditService) QueryAuditLog(filter *audit.Filter) *types.AuditLogResult {
+	l := s.AuditLog()
+	if l == nil {
+		return &types.AuditLogResult{BaseResult: types.BaseResult{Success: false, Message: "审计日志未初始化"}}
+	}
+	if filter == nil {
+		filter = &audit.Filter{}
+	}
+	page, err := l.Query(*filter)
+	if err != nil {
+		s.Logger().Error("查询审计日志失败", "error", err)
+		return &types.AuditLogResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.AuditLogResult{BaseResult: types.BaseResult{Success: true, Message: "查询审计日志成功"}, Data: page}
+}
+
+// ExportAuditLog 将满足条件的审计记录导出为 CSV 或 JSON 文件，文件路径由用户选择。
+func (s *AuditService) ExportAuditLog(filter *audit.Filter, format string) *types.AuditExportResult {
+	l := s.AuditLog()
+	if l == nil {
+		return &types.AuditExportResult{BaseResult: types.BaseResult{Success: false, Message: "审计日志未初始化"}}
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if !audit.ExportFormats[format] {
+		return &types.AuditExportResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("不支持的导出格式: %s", format)}}
+	}
+	if filter == nil {
+		filter = &audit.Filter{}
+	}
+
+	filename, err := runtime.SaveFileDialog(s.Context(), runtime.SaveDialogOptions{
+		Title:           "导出审计日志",
+		DefaultFilename: fmt.Sprintf("boxify-audit-%s.%s", time.Now().Format("20060102"), format),
+	})
+	if err != nil || filename == "" {
+		return &types.AuditExportResult{BaseResult: types.BaseResult{Success: false, Message: "Cancelled"}}
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return &types.AuditExportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	count, err := l.Export(f, format, *filter)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		s.Logger().Error("导出审计日志失败", "file", filename, "error", err)
+		return &types.AuditExportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.AuditExportResult{BaseResult: types.BaseResult{Success: true, Message: fmt.Sprintf("已导出 %d 条审计记录", count)}, File: filename, Count: count}
+}
+
+// newAuditEntry 以连接信息创建写操作审计记录，耗时从 start 起算；err 非空时记为失败。
+// 受影响行数默认未知（-1），由调用方按需填写。
+func newAuditEntry(feature string, config *connection.ConnectionConfig, dbName string, start time.Time, err error) audit.Entry {
+	e := audit.Entry{
+		Time:         start,
+		Feature:      feature,
+		Connection:   db.FormatConnSummary(config),
+		Database:     dbName,
+		AffectedRows: -1,
+		Success:      err == nil,
+		DurationMs:   time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
@@ -20,6 +20,12 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/eventbus"
+	"github.com/chenyang-zz/boxify/internal/eventstream"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/notify"
 	"github.com/chenyang-zz/boxify/internal/window"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
@@ -32,6 +38,11 @@ type BaseService struct {
 	mu         sync.RWMutex
 	appManager *window.AppManager
 	registry   *window.WindowRegistry
+	bus        *eventbus.Bus
+	streams    *eventstream.Manager
+	jobs       *jobs.Manager
+	notifier   *notify.Center
+	auditLog   *audit.Log
 }
 
 // NewBaseService 使用依赖注入创建基础服务
@@ -42,6 +53,11 @@ func NewBaseService(deps *ServiceDeps) BaseService {
 		logger:     deps.app.Logger,
 		appManager: deps.appManager,
 		registry:   deps.registry,
+		bus:        deps.bus,
+		streams:    deps.streams,
+		jobs:       deps.jobs,
+		notifier:   deps.notifier,
+		auditLog:   deps.auditLog,
 	}
 }
 
@@ -76,6 +92,13 @@ func (b *BaseService) Logger() *slog.Logger {
 	return b.logger
 }
 
+// setSubsystem 为服务日志附加子系统属性，子系统级别可通过 LogService 单独调整
+func (b *BaseService) setSubsystem(subsystem string) {
+	if b.logger != nil {
+		b.logger = b.logger.With(logger.SubsystemKey, subsystem)
+	}
+}
+
 // AppManager 获取窗口管理器
 func (b *BaseService) AppManager() *window.AppManager {
 	return b.appManager
@@ -100,6 +123,57 @@ func (b *BaseService) SetRegistry(registry *window.WindowRegistry) {
 	b.registry = registry
 }
 
+// EventBus 获取事件总线（可能为 nil）
+func (b *BaseService) EventBus() *eventbus.Bus {
+	return b.bus
+}
+
+// EmitEvent 通过事件总线发送事件，未注入总线时直接使用应用事件。
+func (b *BaseService) EmitEvent(event string, data interface{}) {
+	if b.bus != nil {
+		b.bus.Emit(event, data)
+		return
+	}
+	b.app.Event.Emit(event, data)
+}
+
+// Streams 获取分块传输管理器（可能为 nil）
+func (b *BaseService) Streams() *eventstream.Manager {
+	return b.streams
+}
+
+// Jobs 获取后台任务管理器（可能为 nil）
+func (b *BaseService) Jobs() *jobs.Manager {
+	return b.jobs
+}
+
+// Notifier 获取系统通知中心（可能为 nil）
+func (b *BaseService) Notifier() *notify.Center {
+	return b.notifier
+}
+
+// Notify 发送系统通知，未注入通知中心时忽略。
+func (b *BaseService) Notify(n notify.Notification) {
+	if b.notifier != nil {
+		b.notifier.Notify(n)
+	}
+}
+
+// AuditLog 获取写操作审计日志（可能为 nil）
+func (b *BaseService) AuditLog() *audit.Log {
+	return b.auditLog
+}
+
+// Audit 记录一次写操作，未注入审计日志时忽略；写入失败只记录日志，不影响操作结果。
+func (b *BaseService) Audit(e audit.Entry) {
+	if b.auditLog == nil {
+		return
+	}
+	if err := b.auditLog.Record(e); err != nil {
+		b.logger.Warn("写入审计日志失败", "feature", e.Feature, "error", err)
+	}
+}
+
 // DefaultServiceStartup 默认启动实现
 func (b *BaseService) DefaultServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	b.SetContext(ctx)
@@ -0,0 +1,111 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/clouddiscovery"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// discoverTimeout 是一次实例发现（含翻页与多个地域）的总超时时间。
+const discoverTimeout = 2 * time.Minute
+
+// CloudDiscoveryService 管理云账号并从中发现托管数据库实例（AWS RDS、GCP Cloud SQL），核心逻辑在 internal/clouddiscovery。
+//
+// 发现结果携带预填的 ConnectionConfig（地域终端节点、端口与 TLS 设置，不含密码），
+// 前端据此打开新建连接表单。
+type CloudDiscoveryService struct {
+	BaseService
+	accounts   *clouddiscovery.Store
+	discoverer *clouddiscovery.Discoverer
+}
+
+// NewCloudDiscoveryService 创建云数据库发现服务
+func NewCloudDiscoveryService(deps *ServiceDeps) *CloudDiscoveryService {
+	s := &CloudDiscoveryService{BaseService: NewBaseService(deps)}
+	s.accounts = clouddiscovery.NewStore("", s.Logger())
+	s.discoverer = clouddiscovery.NewDiscoverer(clouddiscovery.DefaultCADir(), s.Logger())
+	return s
+}
+
+// ServiceStartup 服务启动，读取已保存的云账号
+func (s *CloudDiscoveryService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if err := s.accounts.Load(); err != nil {
+		s.Logger().Warn("加载云账号失败", "error", err)
+	}
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *CloudDiscoveryService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// ListCloudAccounts 列出全部云账号，密钥字段不会返回。
+func (s *CloudDiscoveryService) ListCloudAccounts() *types.CloudAccountListResult {
+	return &types.CloudAccountListResult{BaseResult: types.BaseResult{Success: true, Message: "获取云账号成功"}, Data: s.accounts.List()}
+}
+
+// SaveCloudAccount 新建或更新云账号（ID 为空时新建）；更新时密钥字段留空表示保留原值。
+func (s *CloudDiscoveryService) SaveCloudAccount(account *clouddiscovery.Account) *types.CloudAccountResult {
+	if err := validate.New().Check(account != nil, "account", validate.CodeRequired, "account 不能为空").Err(); err != nil {
+		return &types.CloudAccountResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	saved, err := s.accounts.Save(account)
+	if err != nil {
+		s.Logger().Warn("保存云账号失败", "name", account.Name, "error", err)
+		return &types.CloudAccountResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.CloudAccountResult{BaseResult: types.BaseResult{Success: true, Message: "保存云账号成功"}, Data: saved}
+}
+
+// DeleteCloudAccount 删除云账号。
+func (s *CloudDiscoveryService) DeleteCloudAccount(id string) *types.BaseResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.accounts.Delete(id); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "删除云账号成功"}
+}
+
+// DiscoverCloudInstances 列出云账号下的数据库实例及预填的连接配置。
+func (s *CloudDiscoveryService) DiscoverCloudInstances(accountID string) *types.CloudInstanceListResult {
+	if err := validate.New().Required("accountId", accountID).Err(); err != nil {
+		return &types.CloudInstanceListResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	account, err := s.accounts.Get(accountID)
+	if err != nil {
+		return &types.CloudInstanceListResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	parent := s.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, discoverTimeout)
+	defer cancel()
+	instances, err := s.discoverer.Discover(ctx, account)
+	if err != nil {
+		s.Logger().Warn("发现云数据库实例失败", "account", account.Name, "error", err)
+		return &types.CloudInstanceListResult{BaseResult: types.BaseResult{Success: false, Message: "发现云数据库实例失败：" + err.Error()}}
+	}
+	return &types.CloudInstanceListResult{BaseResult: types.BaseResult{Success: true, Message: "发现云数据库实例成功"}, Data: instances}
+}
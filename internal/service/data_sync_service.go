@@ -17,9 +17,14 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/syncstate"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -32,14 +37,21 @@ type DataSyncEvent struct {
 	Data      map[string]interface{} `json:"data"`      // 实际数据
 	Timestamp int64                  `json:"timestamp"` // 时间戳
 	ID        string                 `json:"id"`        // 唯一消息ID
+
+	// 以下字段仅由 PublishState/DeleteState 发送的版本化状态事件填写
+	Key      string `json:"key,omitempty"`      // 共享状态键
+	Version  uint64 `json:"version,omitempty"`  // 写入后的版本，窗口可忽略不高于已知版本的事件
+	Conflict bool   `json:"conflict,omitempty"` // 写入基于旧版本，Data 可能是合并后的值
+	Deleted  bool   `json:"deleted,omitempty"`  // 键已删除
 }
 
 // 预定义的数据频道
 const (
-	ChannelConfig     = "config"     // 配置同步
-	ChannelConnection = "connection" // 连接配置
-	ChannelSettings   = "settings"   // 应用设置
-	ChannelCustom     = "custom"     // 自定义频道
+	ChannelConfig        = "config"         // 配置同步
+	ChannelConnection    = "connection"     // 连接配置
+	ChannelSettings      = "settings"       // 应用设置
+	ChannelWindowContext = "window-context" // 窗口间移交的标签页上下文
+	ChannelCustom        = "custom"         // 自定义频道
 )
 
 // 预定义的数据类型
@@ -51,20 +63,29 @@ const (
 	DataTypeSettingsUpdate   = "settings:update"
 	DataTypeThemeChanged     = "theme:changed"
 	DataTypeConnectionState  = "connection:state"
+	DataTypeContextTransfer  = "context:transfer" // 上下文移交到其他窗口
+	DataTypeContextRelease   = "context:release"  // 上下文对应的标签页已关闭
 )
 
 // DataSyncService 数据同步服务
+//
+// Broadcast/SendTo 发送一次性消息；PublishState 写入带版本号的共享状态（核心逻辑在 internal/syncstate）并广播，
+// 晚打开的窗口通过 GetSyncState/ListSyncState 获取当前值。共享状态键约定为 "频道:名称"，如 "connection:<id>"。
 type DataSyncService struct {
 	BaseService
 	lastEventTime map[string]time.Time // 消息去重
+	state         *syncstate.Store     // 版本化共享状态（与窗口服务共享）
 }
 
 // NewDataSyncService 创建数据同步服务
 func NewDataSyncService(deps *ServiceDeps) *DataSyncService {
-	return &DataSyncService{
+	ds := &DataSyncService{
 		BaseService:   NewBaseService(deps),
 		lastEventTime: make(map[string]time.Time),
+		state:         deps.SyncState(),
 	}
+	ds.setSubsystem(logger.SubsystemSync)
+	return ds
 }
 
 // Startup 是在应用程序启动时调用的函数
@@ -127,7 +148,12 @@ func (ds *DataSyncService) Emit(event DataSyncEvent) error {
 	}
 
 	ds.lastEventTime[key] = time.Now()
+	ds.sendLocked(event)
+	return nil
+}
 
+// sendLocked 生成消息ID并发送事件；调用方需持有锁
+func (ds *DataSyncService) sendLocked(event DataSyncEvent) {
 	// 生成唯一消息ID
 	event.ID = ds.generateMessageID()
 
@@ -135,7 +161,7 @@ func (ds *DataSyncService) Emit(event DataSyncEvent) error {
 	eventName := ds.getEventName(event.Target)
 
 	// 发送事件
-	ds.App().Event.Emit(eventName, event)
+	ds.EmitEvent(eventName, event)
 
 	ds.Logger().Info("数据同步事件已发送",
 		"source", event.Source,
@@ -143,8 +169,76 @@ func (ds *DataSyncService) Emit(event DataSyncEvent) error {
 		"channel", event.Channel,
 		"dataType", event.DataType,
 	)
+}
 
-	return nil
+// PublishState 写入共享状态并广播。baseVersion 为窗口所见的版本（0 表示不检查），
+// 落后于当前版本时按频道登记的合并函数合并，未登记时后写者覆盖；不参与一秒去重。
+func (ds *DataSyncService) PublishState(key, dataType string, data map[string]interface{}, baseVersion uint64, source string) *types.SyncStateUpdateResult {
+	if err := validate.New().Required("key", key).Err(); err != nil {
+		return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	update, err := ds.state.Set(key, data, baseVersion, source)
+	if err != nil {
+		ds.Logger().Warn("写入共享状态失败", "key", key, "error", err)
+		return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if update.Conflict {
+		ds.Logger().Info("共享状态写入冲突", "key", key, "baseVersion", baseVersion, "version", update.Version, "resolution", update.Resolution, "source", source)
+	}
+	ds.emitState(update, dataType)
+	return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: true, Message: "写入共享状态成功"}, Data: &update}
+}
+
+// DeleteState 删除共享状态并广播删除事件。
+func (ds *DataSyncService) DeleteState(key, dataType, source string) *types.SyncStateUpdateResult {
+	if err := validate.New().Required("key", key).Err(); err != nil {
+		return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	update, ok := ds.state.Delete(key, source)
+	if !ok {
+		return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: false, Message: "共享状态不存在: " + key}}
+	}
+	ds.emitState(update, dataType)
+	return &types.SyncStateUpdateResult{BaseResult: types.BaseResult{Success: true, Message: "删除共享状态成功"}, Data: &update}
+}
+
+// GetSyncState 获取共享状态的当前值与版本，供晚打开的窗口初始化；键不存在时 Data 为空。
+func (ds *DataSyncService) GetSyncState(key string) *types.SyncStateResult {
+	if err := validate.New().Required("key", key).Err(); err != nil {
+		return &types.SyncStateResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	entry, ok := ds.state.Get(key)
+	if !ok {
+		return &types.SyncStateResult{BaseResult: types.BaseResult{Success: true, Message: "共享状态不存在"}}
+	}
+	return &types.SyncStateResult{BaseResult: types.BaseResult{Success: true, Message: "获取共享状态成功"}, Data: &entry}
+}
+
+// ListSyncState 获取键前缀匹配的全部共享状态（不含已删除的键），prefix 为空时返回全部。
+func (ds *DataSyncService) ListSyncState(prefix string) *types.SyncStateListResult {
+	return &types.SyncStateListResult{BaseResult: types.BaseResult{Success: true, Message: "获取共享状态成功"}, Data: ds.state.List(prefix)}
+}
+
+// emitState 广播共享状态变更
+func (ds *DataSyncService) emitState(update syncstate.Update, dataType string) {
+	broadcastSyncState(&ds.BaseService, update, dataType)
+}
+
+// broadcastSyncState 以 data-sync:broadcast 事件广播共享状态变更，频道取键中第一个冒号之前的部分；不参与一秒去重
+func broadcastSyncState(b *BaseService, update syncstate.Update, dataType string) {
+	channel, _, _ := strings.Cut(update.Key, ":")
+	b.EmitEvent("data-sync:broadcast", DataSyncEvent{
+		Source:    update.Source,
+		Channel:   channel,
+		DataType:  dataType,
+		Data:      update.Value,
+		Timestamp: update.UpdatedAt / 1000,
+		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
+		Key:       update.Key,
+		Version:   update.Version,
+		Conflict:  update.Conflict,
+		Deleted:   update.Deleted,
+	})
 }
 
 // getEventName 根据目标获取事件名称
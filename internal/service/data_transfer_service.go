@@ -0,0 +1,264 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// DataTransferService 在两个连接之间复制表结构与数据，核心逻辑在 internal/datatransfer。
+//
+// 任务在后台执行，进度通过 data-transfer:progress 事件推送；每批提交后保存断点，
+// 失败或取消的任务可通过 ResumeDataTransfer 从断点继续。
+type DataTransferService struct {
+	BaseService
+	manager *db.ConnectionManager
+	store   *datatransfer.Store
+	runner  *datatransfer.Runner
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // 执行中的任务
+	wg      sync.WaitGroup
+}
+
+// NewDataTransferService 创建跨连接复制服务
+func NewDataTransferService(deps *ServiceDeps) *DataTransferService {
+	store := datatransfer.NewStore("", deps.app.Logger)
+	return &DataTransferService{
+		BaseService: NewBaseService(deps),
+		manager:     db.NewConnectionManager(deps.app.Logger),
+		store:       store,
+		runner:      datatransfer.NewRunner(store, deps.app.Logger),
+		running:     make(map[string]context.CancelFunc),
+	}
+}
+
+// ServiceStartup 服务启动，将上次退出时仍在执行的任务标记为失败以便继续
+func (s *DataTransferService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	if jobs, err := s.store.List(); err == nil {
+		for _, job := range jobs {
+			if job.Status == datatransfer.StatusRunning {
+				job.Status = datatransfer.StatusFailed
+				job.Error = "应用退出时任务未完成"
+				if err := s.store.Save(job); err != nil {
+					s.Logger().Warn("更新中断的复制任务失败", "id", job.ID, "error", err)
+				}
+			}
+		}
+	}
+	s.Logger().Info("服务启动", "service", "DataTransferService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭，取消执行中的任务（保留断点）并释放连接
+func (s *DataTransferService) ServiceShutdown() error {
+	s.Logger().Info("服务开始关闭，准备释放资源", "service", "DataTransferService")
+	s.mu.Lock()
+	for _, cancel := range s.running {
+		cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	if err := s.manager.CloseAll(); err != nil {
+		s.Logger().Error("关闭数据库连接失败", "error", err)
+	}
+	s.Logger().Info("服务关闭", "service", "DataTransferService")
+	return nil
+}
+
+// StartDataTransfer 创建并在后台启动复制任务，返回任务初始状态。
+func (s *DataTransferService) StartDataTransfer(req *datatransfer.Request) *types.DataTransferJobResult {
+	v := validate.New().Check(req != nil, "req", validate.CodeRequired, "req 不能为空")
+	if req != nil {
+		v.ConnectionConfig("req.source", req.Source).
+			ConnectionConfig("req.target", req.Target).
+			OptionalIdentifier("req.sourceDatabase", req.SourceDatabase).
+			Identifier("req.sourceTable", req.SourceTable).
+			OptionalIdentifier("req.targetDatabase", req.TargetDatabase).
+			OptionalIdentifier("req.targetTable", req.TargetTable).
+			Range("req.batchSize", req.BatchSize, 0, datatransfer.MaxBatchSize)
+	}
+	if err := v.Err(); err != nil {
+		s.Logger().Warn("StartDataTransfer 参数校验失败", "error", err)
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	// 未链接驱动的数据库类型在建任务前拒绝，避免留下无法执行的任务记录
+	for _, config := range []*connection.ConnectionConfig{req.Source, req.Target} {
+		if _, err := db.NewDatabase(config.Type); err != nil {
+			return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+		}
+	}
+
+	job, err := datatransfer.NewJob(req)
+	if err != nil {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	snapshot, err := s.launch(job, req.Source, req.Target)
+	if err != nil {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: true, Message: "复制任务已启动"}, Data: snapshot}
+}
+
+// ResumeDataTransfer 从断点继续失败或已取消的任务；任务不保存密码，需重新提供源与目标连接配置。
+func (s *DataTransferService) ResumeDataTransfer(jobID string, source, target *connection.ConnectionConfig) *types.DataTransferJobResult {
+	if err := validate.New().Required("jobId", jobID).
+		ConnectionConfig("source", source).
+		ConnectionConfig("target", target).Err(); err != nil {
+		s.Logger().Warn("ResumeDataTransfer 参数校验失败", "error", err)
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	job, err := s.store.Get(jobID)
+	if err != nil {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if !job.Resumable() {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("任务当前状态为 %s，无法继续", job.Status)}}
+	}
+	if datatransfer.ConnectionKey(source) != job.SourceKey || datatransfer.ConnectionKey(target) != job.TargetKey {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: "连接配置与任务记录不一致"}}
+	}
+	snapshot, err := s.launch(job, source, target)
+	if err != nil {
+		return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.DataTransferJobResult{BaseResult: types.BaseResult{Success: true, Message: "复制任务已继续"}, Data: snapshot}
+}
+
+// CancelDataTransfer 取消执行中的任务，已提交的数据与断点保留。
+func (s *DataTransferService) CancelDataTransfer(jobID string) *types.BaseResult {
+	s.mu.Lock()
+	cancel, ok := s.running[jobID]
+	s.mu.Unlock()
+	if !ok {
+		return &types.BaseResult{Success: false, Message: "任务未在执行"}
+	}
+	cancel()
+	return &types.BaseResult{Success: true, Message: "已请求取消任务"}
+}
+
+// ListDataTransfers 列出全部复制任务。
+func (s *DataTransferService) ListDataTransfers() *types.DataTransferJobListResult {
+	jobs, err := s.store.List()
+	if err != nil {
+		return &types.DataTransferJobListResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.DataTransferJobListResult{BaseResult: types.BaseResult{Success: true, Message: "获取复制任务成功"}, Data: jobs}
+}
+
+// DeleteDataTransfer 删除任务记录，执行中的任务需先取消。
+func (s *DataTransferService) DeleteDataTransfer(jobID string) *types.BaseResult {
+	if s.isRunning(jobID) {
+		return &types.BaseResult{Success: false, Message: "任务正在执行，请先取消"}
+	}
+	if err := s.store.Delete(jobID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "删除复制任务成功"}
+}
+
+// launch 登记任务后打开源与目标连接并在后台执行，返回启动时的任务快照；同一任务已在执行时返回错误。
+// 执行中的 job 由后台协程独占修改，调用方只能使用返回的快照。
+func (s *DataTransferService) launch(job *datatransfer.Job, source, target *connection.ConnectionConfig) (*datatransfer.Job, error) {
+	// 检查与登记在同一临界区内完成，避免同一任务被并发启动两次
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	if _, ok := s.running[job.ID]; ok {
+		s.mu.Unlock()
+		cancel()
+		return nil, fmt.Errorf("任务正在执行")
+	}
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+	started := false
+	defer func() {
+		if !started {
+			s.mu.Lock()
+			delete(s.running, job.ID)
+			s.mu.Unlock()
+			cancel()
+		}
+	}()
+
+	srcConn, err := s.manager.Get(normalizeRunConfig(source, job.SourceDatabase), false)
+	if err != nil {
+		return nil, fmt.Errorf("连接源数据库失败：%w", err)
+	}
+	dstConn, err := s.manager.Get(normalizeRunConfig(target, job.TargetDatabase), false)
+	if err != nil {
+		return nil, fmt.Errorf("连接目标数据库失败：%w", err)
+	}
+	src, err := datatransfer.NewDBSource(srcConn, job.SourceType, job.SourceDatabase, job.SourceTable)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := datatransfer.NewDBTarget(dstConn)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Save(job); err != nil {
+		return nil, err
+	}
+	snapshot := job.Clone()
+
+	started = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, job.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+		start, copiedBefore := time.Now(), job.RowsCopied
+		err := s.runner.Run(ctx, job, src, dst, func(j *datatransfer.Job) {
+			s.EmitEvent(string(events.EventTypeDataTransferProgress), j.Clone())
+		})
+		entry := newAuditEntry(audit.FeatureDataTransfer, target, job.TargetDatabase, start, err)
+		entry.Table = job.TargetTable
+		entry.SQL = fmt.Sprintf("COPY %s.%s FROM %s %s.%s", job.TargetDatabase, job.TargetTable, db.FormatConnSummary(source), job.SourceDatabase, job.SourceTable)
+		entry.AffectedRows = job.RowsCopied - copiedBefore
+		s.Audit(entry)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.Logger().Error("复制任务失败", "id", job.ID, "error", err,
+				"source", db.FormatConnSummary(source), "target", db.FormatConnSummary(target))
+		}
+	}()
+	return snapshot, nil
+}
+
+// isRunning 判断任务是否正在执行。
+func (s *DataTransferService) isRunning(jobID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.running[jobID]
+	return ok
+}
@@ -16,24 +16,67 @@ package service
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/chenyang-zz/boxify/internal/blobstore"
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/cursor"
 	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/dbsnapshot"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/execqueue"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/notify"
+	"github.com/chenyang-zz/boxify/internal/queryhistory"
+	"github.com/chenyang-zz/boxify/internal/querywatch"
+	"github.com/chenyang-zz/boxify/internal/retention"
+	"github.com/chenyang-zz/boxify/internal/snapshot"
+	"github.com/chenyang-zz/boxify/internal/ssh"
+	"github.com/chenyang-zz/boxify/internal/supportbundle"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 // DatabaseService 负责前端服务编排，连接管理由 db.ConnectionManager 承担。
 type DatabaseService struct {
 	BaseService
-	manager *db.ConnectionManager
+	manager     *db.ConnectionManager
+	cursors     *cursor.Manager      // 结果集游标（按区间滚动读取）
+	blobs       *blobstore.Store     // 查询结果中超大二进制值的暂存
+	snapshots   *snapshot.Store      // 工作区查询结果快照
+	dbSnapshots *dbsnapshot.Store    // 表结构与数据的快照归档
+	history     *queryhistory.Store  // 查询历史（表使用统计）
+	retention   *retention.Store     // 数据保留规则
+	watches     *querywatch.Manager  // 定时刷新的查询监视
+	slots       *execqueue.Scheduler // 每连接的并发执行槽
+
+	importPreviews importPreviewStore // 导入预览文件登记
+	schemas        schemaCache        // SQL 分析使用的列信息缓存
+	changeUndo     changeUndoLog      // ApplyChanges 的撤销记录
 }
 
 // NewDatabaseService 创建 DatabaseService（使用依赖注入）。
 func NewDatabaseService(deps *ServiceDeps) *DatabaseService {
-	return &DatabaseService{
-		BaseService: NewBaseService(deps),
-		manager:     db.NewConnectionManager(deps.app.Logger),
-	}
+	s := &DatabaseService{BaseService: NewBaseService(deps), blobs: blobstore.New(0, 0)}
+	s.setSubsystem(logger.SubsystemDB)
+	log := s.Logger()
+	s.manager = db.NewConnectionManager(log)
+	s.cursors = cursor.NewManager(log, cursor.DefaultOptions())
+	s.snapshots = snapshot.NewStore("", log)
+	s.dbSnapshots = dbsnapshot.NewStore("", log)
+	s.history = queryhistory.NewStore("", 0, log)
+	s.retention = retention.NewStore("", log)
+	s.watches = querywatch.NewManager(log)
+	s.slots = execqueue.Default
+	deps.Probes().Register(func(stats *supportbundle.RuntimeStats) {
+		stats.Pools = append(stats.Pools, s.manager.PoolStats()...)
+		stats.Sessions["dbConnections"] = s.manager.Count()
+		stats.Sessions["cursors"] = s.cursors.Count()
+		stats.Sessions["queryWatches"] = len(s.watches.List())
+		for _, q := range s.slots.Stats() {
+			stats.Sessions["queuedStatements"] += q.Waiting
+		}
+	})
+	return s
 }
 
 // ServiceStartup 在应用启动时初始化数据库服务状态。
@@ -42,6 +85,52 @@ func (a *DatabaseService) ServiceStartup(ctx context.Context, options applicatio
 	if a.manager == nil {
 		a.manager = db.NewConnectionManager(a.Logger())
 	}
+	a.manager.StartReaper(ctx)
+	a.manager.SetHealthListener(func(status db.HealthStatus) {
+		a.EmitEvent(string(events.EventTypeConnectionState), status)
+		if status.State == db.HealthLost {
+			a.Notify(notify.Notification{
+				Category: notify.CategoryConnection,
+				Title:    "数据库连接已断开",
+				Body:     fmt.Sprintf("%s@%s:%d %s", status.User, status.Host, status.Port, status.Error),
+			})
+		}
+	})
+	a.manager.SetHostListener(func(change db.HostChange) {
+		a.EmitEvent(string(events.EventTypeConnectionHostChanged), change)
+		if !change.Primary {
+			a.Notify(notify.Notification{
+				Category: notify.CategoryConnection,
+				Title:    "主库不可用，已切换到备用主机",
+				Body:     fmt.Sprintf("%s@%s %s", change.User, change.To, change.Reason),
+			})
+		}
+	})
+	a.manager.StartHealthMonitor(ctx, db.DefaultHealthCheckInterval)
+	if err := a.retentionStore().Load(); err != nil {
+		a.Logger().Warn("加载数据保留规则失败", "error", err)
+	}
+	if loaded, err := db.LoadDriverPlugins(db.DefaultDriverPluginDir()); err != nil {
+		a.Logger().Warn("加载驱动插件失败", "error", err)
+	} else if len(loaded) > 0 {
+		a.Logger().Info("已加载驱动插件", "plugins", loaded)
+	}
+	ssh.SetTunnelStatusListener(func(status ssh.TunnelStatus) {
+		a.EmitEvent(string(events.EventTypeSSHTunnelStatus), status)
+		if status.State == ssh.TunnelReconnecting {
+			a.Notify(notify.Notification{
+				Category: notify.CategoryConnection,
+				Title:    "SSH 隧道连接中断，正在重连",
+				Body:     fmt.Sprintf("%s@%s:%d %s", status.User, status.Host, status.Port, status.LastError),
+			})
+		}
+	})
+	a.watchManager().SetListener(func(u querywatch.Update) {
+		a.EmitEvent(string(events.EventTypeQueryWatchUpdate), u)
+	})
+	a.execSlots().SetListener(func(ev execqueue.Event) {
+		a.EmitEvent(string(events.EventTypeQueryQueue), ev)
+	})
 	a.Logger().Info("服务启动", "service", "DatabaseService")
 	return nil
 }
@@ -49,6 +138,21 @@ func (a *DatabaseService) ServiceStartup(ctx context.Context, options applicatio
 // ServiceShutdown 在应用关闭时释放数据库连接资源。
 func (a *DatabaseService) ServiceShutdown() error {
 	a.Logger().Info("服务开始关闭，准备释放资源", "service", "DatabaseService")
+	ssh.SetTunnelStatusListener(nil)
+	a.execSlots().SetListener(nil)
+	if a.manager != nil {
+		a.manager.SetHealthListener(nil)
+		a.manager.SetHostListener(nil)
+	}
+	if a.watches != nil {
+		a.watches.StopAll()
+	}
+	if a.cursors != nil {
+		a.cursors.CloseAll()
+	}
+	if a.blobs != nil {
+		a.blobs.Clear()
+	}
 	if a.manager != nil {
 		if err := a.manager.CloseAll(); err != nil {
 			a.Logger().Error("关闭数据库连接失败", "error", err)
@@ -68,6 +172,14 @@ func (a *DatabaseService) getDatabase(config *connection.ConnectionConfig) (db.D
 	return a.getDatabaseWithPing(config, false)
 }
 
+// getReadDatabase 返回执行只读查询使用的连接，多主机 round-robin 策略下在可用主机间轮询。
+func (a *DatabaseService) getReadDatabase(config *connection.ConnectionConfig) (db.Database, error) {
+	if a.manager == nil {
+		a.manager = db.NewConnectionManager(a.Logger())
+	}
+	return a.manager.GetForRead(config, false)
+}
+
 // getDatabaseWithPing 按需探活并返回数据库连接。
 func (a *DatabaseService) getDatabaseWithPing(config *connection.ConnectionConfig, forcePing bool) (db.Database, error) {
 	if a.manager == nil {
@@ -15,19 +15,81 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/execqueue"
 )
 
+// defaultMetadataTimeout 是连接未配置超时时元数据查询的超时。
+const defaultMetadataTimeout = 30 * time.Second
+
+// metadataContext 返回元数据查询（库、表、列、索引等）使用的上下文，超时取连接配置的 timeout，
+// 避免服务端无响应时界面一直等待。
+func metadataContext(parent context.Context, config *connection.ConnectionConfig) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	timeout := defaultMetadataTimeout
+	if config != nil && config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// execSlots 返回每连接的执行槽调度器。
+func (a *DatabaseService) execSlots() *execqueue.Scheduler {
+	if a.slots == nil {
+		a.slots = execqueue.Default
+	}
+	return a.slots
+}
+
+// acquireSlot 在连接的执行队列中获取一个执行槽，槽位已满时排队，等待时间受 ctx 约束。
+func (a *DatabaseService) acquireSlot(ctx context.Context, config *connection.ConnectionConfig, label string) (func(), error) {
+	release, err := a.execSlots().Acquire(ctx, db.ServerKey(config), label)
+	if err != nil {
+		a.Logger().Warn("等待执行槽失败", "label", label, "error", err, "summary", db.FormatConnSummary(config))
+		return nil, fmt.Errorf("排队等待执行超时：%w", err)
+	}
+	return release, nil
+}
+
+// metadataCall 返回元数据查询使用的上下文并占用一个执行槽，排队与执行共用同一超时；
+// 成功时调用方必须调用 done 归还执行槽。
+func (a *DatabaseService) metadataCall(config *connection.ConnectionConfig, label string) (context.Context, func(), error) {
+	ctx, cancel := metadataContext(a.Context(), config)
+	release, err := a.acquireSlot(ctx, config, label)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return ctx, func() {
+		release()
+		cancel()
+	}, nil
+}
+
+// getTableColumns 在元数据超时内读取表的列定义，tableName 可带 schema 前缀。
+func getTableColumns(ctx context.Context, dbInst db.Database, config *connection.ConnectionConfig, dbName, tableName string) ([]*connection.ColumnDefinition, error) {
+	ctx, cancel := metadataContext(ctx, config)
+	defer cancel()
+	schema, table := splitTableName(dbName, tableName)
+	return dbInst.GetColumns(ctx, schema, table)
+}
+
 // normalizeRunConfig 根据连接配置和用户输入的 dbName 生成最终的运行配置
 // 对于大多数数据库类型，dbName 被视为要连接的数据库名称
 // 并覆盖连接配置中的 Database 字段。
 func normalizeRunConfig(config *connection.ConnectionConfig, dbName string) *connection.ConnectionConfig {
-	runConfig := *config
+	runConfig := config.Clone()
 	name := strings.TrimSpace(dbName)
 	if name == "" {
-		return &runConfig
+		return runConfig
 	}
 
 	switch config.Type {
@@ -43,34 +105,42 @@ func normalizeRunConfig(config *connection.ConnectionConfig, dbName string) *con
 		// custom: 语义不明确，避免污染缓存 key
 	}
 
-	return &runConfig
+	return runConfig
+}
+
+// splitTableName 拆分可带 schema 前缀的表名：带前缀（支持引号包裹的大小写混合或含点名称）时返回该 schema，
+// 否则沿用 dbName；名称无法解析时原样返回。
+func splitTableName(dbName, tableName string) (string, string) {
+	q, err := db.ParseQualifiedName(tableName)
+	if err != nil {
+		return strings.TrimSpace(dbName), strings.TrimSpace(tableName)
+	}
+	if q.Schema != "" {
+		return q.Schema, q.Name
+	}
+	return strings.TrimSpace(dbName), q.Name
 }
 
 // normalizeSchemaAndTable 根据数据库类型和用户输入的 dbName/tableName 规范化 schema 和 table 名称
 func normalizeSchemaAndTable(config *connection.ConnectionConfig, dbName string, tableName string) (string, string) {
-	rawTable := strings.TrimSpace(tableName)
 	rawDB := strings.TrimSpace(dbName)
-	if rawTable == "" {
-		return rawDB, rawTable
+	q, err := db.ParseQualifiedName(tableName)
+	if err != nil {
+		return rawDB, strings.TrimSpace(tableName)
 	}
-
-	if parts := strings.SplitN(rawTable, ".", 2); len(parts) == 2 {
-		schema := strings.TrimSpace(parts[0])
-		table := strings.TrimSpace(parts[1])
-		if schema != "" && table != "" {
-			return schema, table
-		}
+	if q.Schema != "" {
+		return q.Schema, q.Name
 	}
 
 	switch config.Type {
 	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
 		// PG/金仓/瀚高/海量：dbName 在 UI 里是"数据库"，schema 需从 tableName 或使用默认 public。
-		return "public", rawTable
+		return "public", q.Name
 	case connection.ConnectionTypeSQLServer:
 		// SQL Server：dbName 表示数据库，schema 默认 dbo
-		return "dbo", rawTable
+		return "dbo", q.Name
 	default:
 		// MySQL：dbName 表示数据库；Oracle/达梦：dbName 表示 schema/owner。
-		return rawDB, rawTable
+		return rawDB, q.Name
 	}
 }
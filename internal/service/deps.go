@@ -15,6 +15,17 @@
 package service
 
 import (
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/cmdhistory"
+	"github.com/chenyang-zz/boxify/internal/eventbus"
+	"github.com/chenyang-zz/boxify/internal/eventstream"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/notify"
+	"github.com/chenyang-zz/boxify/internal/recents"
+	"github.com/chenyang-zz/boxify/internal/settings"
+	"github.com/chenyang-zz/boxify/internal/supportbundle"
+	"github.com/chenyang-zz/boxify/internal/syncstate"
+	"github.com/chenyang-zz/boxify/internal/termprofile"
 	"github.com/chenyang-zz/boxify/internal/window"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
@@ -24,6 +35,17 @@ type ServiceDeps struct {
 	app        *application.App
 	appManager *window.AppManager
 	registry   *window.WindowRegistry
+	bus        *eventbus.Bus         // 带订阅跟踪与缓冲的事件总线
+	streams    *eventstream.Manager  // 大负载分块传输通道
+	jobs       *jobs.Manager         // 后台任务（各服务共享）
+	notifier   *notify.Center        // 系统通知（各服务共享，由 NotificationService 注入发送端）
+	auditLog   *audit.Log            // 写操作审计日志（各服务共享）
+	profiles   *termprofile.Store    // 终端配置方案（终端服务与方案服务共享）
+	cmdHistory *cmdhistory.Store     // 终端命令历史（全部终端会话共享）
+	settings   *settings.Store       // 应用设置（设置服务维护，其他服务读取）
+	recents    *recents.Store        // 最近使用记录（最近使用服务维护，托盘菜单读取）
+	syncState  *syncstate.Store      // 多窗口共享状态（数据同步服务与窗口服务共享）
+	probes     *supportbundle.Probes // 诊断包运行时状态采集（各服务注册，诊断服务读取）
 }
 
 // NewServiceDeps 创建依赖容器
@@ -35,6 +57,31 @@ func NewServiceDeps(app *application.App, am *window.AppManager) *ServiceDeps {
 	if am != nil {
 		deps.registry = am.GetRegistry()
 	}
+	// config 频道的冲突写入按顶层字段合并；窗口上下文的归属不能合并，基于旧版本的移交直接拒绝
+	deps.syncState = syncstate.NewStore()
+	deps.probes = supportbundle.NewProbes()
+	deps.syncState.RegisterMerge(ChannelConfig, syncstate.ShallowMerge)
+	deps.syncState.RegisterMerge(ChannelWindowContext+":", rejectStaleWindowContext)
+	if app != nil {
+		deps.bus = eventbus.NewBus(&appEventEmitter{app: app}, app.Logger, eventbus.DefaultOptions())
+		deps.streams = eventstream.NewManager(deps.bus, app.Logger, eventstream.DefaultOptions())
+		deps.jobs = jobs.NewManager(app.Logger, jobs.DefaultOptions())
+		deps.notifier = notify.NewCenter(app.Logger)
+		deps.auditLog = audit.NewLog("", app.Logger)
+		deps.profiles = termprofile.NewStore("", app.Logger)
+		if err := deps.profiles.Load(); err != nil {
+			app.Logger.Warn("加载终端配置方案失败", "error", err)
+		}
+		deps.cmdHistory = cmdhistory.NewStore("", 0, app.Logger)
+		deps.settings = settings.NewStore("", app.Logger)
+		if err := deps.settings.Load(); err != nil {
+			app.Logger.Warn("加载应用设置失败，使用默认设置", "error", err)
+		}
+		deps.recents = recents.NewStore("", app.Logger)
+		if err := deps.recents.Load(); err != nil {
+			app.Logger.Warn("加载最近使用记录失败", "error", err)
+		}
+	}
 	return deps
 }
 
@@ -52,3 +99,68 @@ func (d *ServiceDeps) AppManager() *window.AppManager {
 func (d *ServiceDeps) Registry() *window.WindowRegistry {
 	return d.registry
 }
+
+// EventBus 获取事件总线
+func (d *ServiceDeps) EventBus() *eventbus.Bus {
+	return d.bus
+}
+
+// Streams 获取分块传输管理器
+func (d *ServiceDeps) Streams() *eventstream.Manager {
+	return d.streams
+}
+
+// Jobs 获取后台任务管理器
+func (d *ServiceDeps) Jobs() *jobs.Manager {
+	return d.jobs
+}
+
+// Notifier 获取系统通知中心
+func (d *ServiceDeps) Notifier() *notify.Center {
+	return d.notifier
+}
+
+// AuditLog 获取写操作审计日志
+func (d *ServiceDeps) AuditLog() *audit.Log {
+	return d.auditLog
+}
+
+// TerminalProfiles 获取终端配置方案存储
+func (d *ServiceDeps) TerminalProfiles() *termprofile.Store {
+	return d.profiles
+}
+
+// CommandHistory 获取终端命令历史存储
+func (d *ServiceDeps) CommandHistory() *cmdhistory.Store {
+	return d.cmdHistory
+}
+
+// SyncState 获取多窗口共享状态存储
+func (d *ServiceDeps) SyncState() *syncstate.Store {
+	return d.syncState
+}
+
+// Settings 获取应用设置存储
+func (d *ServiceDeps) Settings() *settings.Store {
+	return d.settings
+}
+
+// Recents 获取最近使用记录存储
+func (d *ServiceDeps) Recents() *recents.Store {
+	return d.recents
+}
+
+// Probes 获取诊断包运行时状态采集注册表
+func (d *ServiceDeps) Probes() *supportbundle.Probes {
+	return d.probes
+}
+
+// appEventEmitter 将 Wails 事件总线适配为 eventbus.Emitter。
+type appEventEmitter struct {
+	app *application.App
+}
+
+// Emit 通过应用事件总线发送事件。
+func (e *appEventEmitter) Emit(event string, data interface{}) {
+	e.app.Event.Emit(event, data)
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/drafts"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// DraftService 持久化编辑器未保存的内容，应用或系统崩溃后可恢复，核心逻辑在 internal/drafts。
+//
+// 前端在编辑时（防抖后）调用 SaveDraft，保存或关闭标签页后调用 DiscardDraft；
+// 启动时通过 GetRecoverableDrafts 询问用户是否恢复上次遗留的草稿。
+type DraftService struct {
+	BaseService
+	store *drafts.Store
+}
+
+// NewDraftService 创建草稿服务
+func NewDraftService(deps *ServiceDeps) *DraftService {
+	s := &DraftService{BaseService: NewBaseService(deps)}
+	s.store = drafts.NewStore("", drafts.DefaultFlushDelay, s.Logger())
+	return s
+}
+
+// ServiceStartup 服务启动，写入会话标记
+func (s *DraftService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if err := s.store.Begin(); err != nil {
+		s.Logger().Warn("写入草稿会话标记失败，本次无法检测异常退出", "error", err)
+	}
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭，写入剩余草稿并标记正常退出
+func (s *DraftService) ServiceShutdown() error {
+	if err := s.store.End(); err != nil {
+		s.Logger().Error("结束草稿会话失败", "error", err)
+	}
+	return s.DefaultServiceShutdown()
+}
+
+// SaveDraft 保存标签页草稿，短时间内的多次调用合并为一次写入。
+func (s *DraftService) SaveDraft(draft *drafts.Draft) *types.BaseResult {
+	v := validate.New().Check(draft != nil, "draft", validate.CodeRequired, "draft 不能为空")
+	if draft != nil {
+		v.Check(drafts.ValidTabID(draft.TabID) == nil, "tabId", validate.CodeInvalid, "tabId 只能包含字母、数字、下划线或连字符")
+	}
+	if err := v.Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.store.Save(*draft); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "草稿已保存"}
+}
+
+// FlushDrafts 立即写入全部待落盘的草稿，用于窗口关闭等时机。
+func (s *DraftService) FlushDrafts() *types.BaseResult {
+	if err := s.store.Flush(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "草稿已写入"}
+}
+
+// DiscardDraft 删除标签页草稿（内容已保存或标签页已关闭）。
+func (s *DraftService) DiscardDraft(tabID string) *types.BaseResult {
+	if err := validateTabID(tabID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.store.Discard(tabID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "草稿已删除"}
+}
+
+// GetRecoverableDrafts 获取上次及更早会话遗留的草稿，Crashed 表示上次未正常退出。
+func (s *DraftService) GetRecoverableDrafts() *types.DraftRecoveryResult {
+	rec, err := s.store.Recovery()
+	if err != nil {
+		return &types.DraftRecoveryResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.DraftRecoveryResult{
+		BaseResult: types.BaseResult{Success: true, Message: fmt.Sprintf("找到 %d 个可恢复的草稿", len(rec.Drafts))},
+		Data:       rec,
+	}
+}
+
+// RecoverDraft 取得遗留草稿的完整内容，草稿归入当前会话，之后按当前标签页继续保存或删除。
+func (s *DraftService) RecoverDraft(tabID string) *types.DraftResult {
+	if err := validateTabID(tabID); err != nil {
+		return &types.DraftResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	d, err := s.store.Recover(tabID)
+	if err != nil {
+		if errors.Is(err, drafts.ErrDraftNotFound) {
+			return &types.DraftResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+		}
+		s.Logger().Error("恢复草稿失败", "tabId", tabID, "error", err)
+		return &types.DraftResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("恢复草稿失败: %v", err)}}
+	}
+	return &types.DraftResult{BaseResult: types.BaseResult{Success: true, Message: "草稿已恢复"}, Data: d}
+}
+
+// DiscardRecoverableDrafts 删除全部遗留草稿（用户选择不恢复）。
+func (s *DraftService) DiscardRecoverableDrafts() *types.BaseResult {
+	n, err := s.store.DiscardRecovery()
+	if err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: fmt.Sprintf("已删除 %d 个草稿", n)}
+}
+
+// validateTabID 校验标签页 ID。
+func validateTabID(tabID string) error {
+	return validate.New().
+		Check(drafts.ValidTabID(tabID) == nil, "tabId", validate.CodeInvalid, "tabId 只能包含字母、数字、下划线或连字符").
+		Err()
+}
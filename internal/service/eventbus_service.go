@@ -0,0 +1,114 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/eventbus"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// EventBusService 向前端暴露事件订阅上报与事件总线状态查询接口，核心逻辑在 internal/eventbus。
+//
+// 前端在注册事件监听后调用 SubscribeEvent，页面卸载（重载）前调用 UnsubscribeWindow，
+// 订阅空窗期内发送的事件会被缓冲并在重新订阅时补发。
+type EventBusService struct {
+	BaseService
+}
+
+// NewEventBusService 创建事件总线服务
+func NewEventBusService(deps *ServiceDeps) *EventBusService {
+	return &EventBusService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动
+func (s *EventBusService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	s.Logger().Info("服务启动", "service", "EventBusService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭
+func (s *EventBusService) ServiceShutdown() error {
+	s.Logger().Info("服务关闭", "service", "EventBusService")
+	return nil
+}
+
+// SubscribeEvent 上报窗口订阅事件，返回补发的缓冲事件条数。
+func (s *EventBusService) SubscribeEvent(windowName, eventName string) *types.EventSubscribeResult {
+	bus := s.EventBus()
+	if bus == nil {
+		return &types.EventSubscribeResult{BaseResult: types.BaseResult{Success: false, Message: "事件总线未初始化"}}
+	}
+	if err := validate.New().Required("windowName", windowName).Required("eventName", eventName).Err(); err != nil {
+		return &types.EventSubscribeResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	replayed := bus.Subscribe(windowName, eventName)
+	return &types.EventSubscribeResult{
+		BaseResult: types.BaseResult{Success: true, Message: "订阅成功"},
+		Data:       &types.EventSubscribeData{Replayed: replayed},
+	}
+}
+
+// UnsubscribeEvent 上报窗口取消订阅事件。
+func (s *EventBusService) UnsubscribeEvent(windowName, eventName string) *types.BaseResult {
+	bus := s.EventBus()
+	if bus == nil {
+		return &types.BaseResult{Success: false, Message: "事件总线未初始化"}
+	}
+	bus.Unsubscribe(windowName, eventName)
+	return &types.BaseResult{Success: true, Message: "取消订阅成功"}
+}
+
+// UnsubscribeWindow 移除窗口的全部订阅，在页面卸载或重载前调用。
+func (s *EventBusService) UnsubscribeWindow(windowName string) *types.BaseResult {
+	bus := s.EventBus()
+	if bus == nil {
+		return &types.BaseResult{Success: false, Message: "事件总线未初始化"}
+	}
+	bus.RemoveWindow(windowName)
+	return &types.BaseResult{Success: true, Message: "已移除窗口订阅"}
+}
+
+// SetEventBuffering 配置无订阅者时的事件缓冲：是否启用、每个事件最大缓冲条数与存活时间（毫秒）。
+func (s *EventBusService) SetEventBuffering(enabled bool, maxPerEvent int, ttlMs int) *types.BaseResult {
+	bus := s.EventBus()
+	if bus == nil {
+		return &types.BaseResult{Success: false, Message: "事件总线未初始化"}
+	}
+	bus.SetOptions(eventbus.Options{
+		BufferUnsubscribed:  enabled,
+		MaxBufferedPerEvent: maxPerEvent,
+		BufferTTL:           time.Duration(ttlMs) * time.Millisecond,
+	})
+	s.Logger().Info("事件缓冲配置已更新", "enabled", enabled, "maxPerEvent", maxPerEvent, "ttlMs", ttlMs)
+	return &types.BaseResult{Success: true, Message: "配置已更新"}
+}
+
+// GetEventBusStats 获取事件订阅、缓冲与死信统计。
+func (s *EventBusService) GetEventBusStats() *types.EventBusStatsResult {
+	bus := s.EventBus()
+	if bus == nil {
+		return &types.EventBusStatsResult{BaseResult: types.BaseResult{Success: false, Message: "事件总线未初始化"}}
+	}
+	return &types.EventBusStatsResult{
+		BaseResult: types.BaseResult{Success: true, Message: "获取事件总线状态成功"},
+		Data:       bus.Stats(),
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -60,6 +61,15 @@ func (s *FilesystemService) ListDirectories(path string) *types.ListDirectoryRes
 		},
 	}
 
+	if path != "" {
+		if err := validate.New().SafePath("path", path).Err(); err != nil {
+			result.Success = false
+			result.Message = err.Error()
+			s.Logger().Warn("ListDirectories 参数校验失败", "error", err)
+			return result
+		}
+	}
+
 	// 如果路径为空，使用当前工作目录
 	if path == "" {
 		wd, err := os.Getwd()
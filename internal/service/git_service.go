@@ -40,7 +40,7 @@ func (g *GitService) ServiceStartup(ctx context.Context, options application.Ser
 	g.SetContext(ctx)
 	g.manager = gitcore.NewManager(ctx, g.Logger(), func(event boxtypes.GitStatusChangedEvent) {
 		g.Logger().Info("Git 状态变化事件", "repoKey", event.RepoKey, "status", event.Status, "timestamp", event.Timestamp)
-		g.App().Event.Emit(string(events.EventTypeGitStatusChanged), boxtypes.GitStatusChangedEvent{
+		g.EmitEvent(string(events.EventTypeGitStatusChanged), boxtypes.GitStatusChangedEvent{
 			RepoKey:   event.RepoKey,
 			Status:    event.Status,
 			Timestamp: event.Timestamp,
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/events"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -31,11 +32,12 @@ const (
 
 // InitialDataEntry 初始数据条目
 type InitialDataEntry struct {
-	WindowName string                 `json:"windowName"` // 目标窗口名称
-	Source     string                 `json:"source"`     // 源窗口名称
-	Data       map[string]interface{} `json:"data"`       // 实际数据
-	Timestamp  int64                  `json:"timestamp"`  // 创建时间戳
-	ExpiresAt  int64                  `json:"expiresAt"`  // 过期时间戳
+	WindowName string                 `json:"windowName"`           // 目标窗口名称
+	Source     string                 `json:"source"`               // 源窗口名称
+	Data       map[string]interface{} `json:"data"`                 // 实际数据
+	Timestamp  int64                  `json:"timestamp"`            // 创建时间戳
+	ExpiresAt  int64                  `json:"expiresAt"`            // 过期时间戳
+	TransferID string                 `json:"transferId,omitempty"` // 分块传输流 ID（数据过大时 Data 通过 initial-data:chunk 分块下发）
 }
 
 // InitialDataService 初始数据服务
@@ -261,6 +263,34 @@ func (ids *InitialDataService) cleanupExpiredData() {
 }
 
 // emitWindowEvent 发送窗口事件
+// 数据超过单个分块大小时，事件本身只携带元信息，数据通过分块传输流异步下发，避免一次性推送大负载。
 func (ids *InitialDataService) emitWindowInitialData(entry *InitialDataEntry) {
-	application.Get().Event.Emit("initial-data:received", *entry)
+	streams := ids.Streams()
+	if streams == nil {
+		ids.EmitEvent("initial-data:received", *entry)
+		return
+	}
+
+	payload, err := json.Marshal(entry.Data)
+	if err != nil || !streams.ShouldChunk(len(payload)) {
+		ids.EmitEvent("initial-data:received", *entry)
+		return
+	}
+
+	stream := streams.Open(string(events.EventTypeInitialDataChunk), map[string]interface{}{
+		"windowName": entry.WindowName,
+	})
+	header := *entry
+	header.Data = nil
+	header.TransferID = stream.ID()
+	ids.EmitEvent("initial-data:received", header)
+
+	go func() {
+		defer stream.Close()
+		if err := stream.Write(ids.Context(), payload, nil); err != nil {
+			ids.Logger().Warn("分块下发初始数据失败", "target", entry.WindowName, "size", len(payload), "error", err)
+			return
+		}
+		ids.Logger().Info("初始数据分块下发完成", "target", entry.WindowName, "size", len(payload), "streamId", stream.ID())
+	}()
 }
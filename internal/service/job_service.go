@@ -0,0 +1,109 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// jobEventTypes 将任务事件映射为前端事件名。
+var jobEventTypes = map[jobs.EventKind]events.EventType{
+	jobs.EventProgress:  events.EventTypeJobProgress,
+	jobs.EventCompleted: events.EventTypeJobCompleted,
+	jobs.EventFailed:    events.EventTypeJobFailed,
+	jobs.EventCancelled: events.EventTypeJobCancelled,
+}
+
+// JobService 向前端暴露后台任务的查询与取消接口，核心逻辑在 internal/jobs。
+//
+// 任务由各服务通过共享的 jobs.Manager 提交（如 DatabaseService 的 DBStart*Job），
+// 进度与结束状态通过 job:progress / job:completed / job:failed / job:cancelled 事件推送。
+type JobService struct {
+	BaseService
+}
+
+// NewJobService 创建后台任务服务
+func NewJobService(deps *ServiceDeps) *JobService {
+	return &JobService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动，将任务事件转发到前端
+func (s *JobService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	if m := s.Jobs(); m != nil {
+		m.SetListener(func(e jobs.Event) {
+			s.EmitEvent(string(jobEventTypes[e.Kind]), e.Job)
+		})
+	}
+	s.Logger().Info("服务启动", "service", "JobService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭，取消执行中的任务并等待其返回
+func (s *JobService) ServiceShutdown() error {
+	s.Logger().Info("服务开始关闭，准备释放资源", "service", "JobService")
+	if m := s.Jobs(); m != nil {
+		m.SetListener(nil)
+		m.Shutdown()
+	}
+	s.Logger().Info("服务关闭", "service", "JobService")
+	return nil
+}
+
+// ListJobs 列出执行中与最近结束的任务。
+func (s *JobService) ListJobs() *types.JobListResult {
+	m := s.Jobs()
+	if m == nil {
+		return &types.JobListResult{BaseResult: types.BaseResult{Success: false, Message: "任务管理器未初始化"}}
+	}
+	return &types.JobListResult{BaseResult: types.BaseResult{Success: true, Message: "获取任务列表成功"}, Data: m.List()}
+}
+
+// GetJob 获取单个任务的当前状态。
+func (s *JobService) GetJob(jobID string) *types.JobResult {
+	if err := validate.New().Required("jobId", jobID).Err(); err != nil {
+		return &types.JobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	m := s.Jobs()
+	if m == nil {
+		return &types.JobResult{BaseResult: types.BaseResult{Success: false, Message: "任务管理器未初始化"}}
+	}
+	job, err := m.Get(jobID)
+	if err != nil {
+		return &types.JobResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.JobResult{BaseResult: types.BaseResult{Success: true, Message: "获取任务成功"}, Data: &job}
+}
+
+// CancelJob 请求取消执行中的任务，任务结束后推送 job:cancelled 事件。
+func (s *JobService) CancelJob(jobID string) *types.BaseResult {
+	if err := validate.New().Required("jobId", jobID).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	m := s.Jobs()
+	if m == nil {
+		return &types.BaseResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	if err := m.Cancel(jobID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "已请求取消任务"}
+}
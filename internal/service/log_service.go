@@ -0,0 +1,126 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// LogService 在运行时调整日志输出并提供日志查看面板的数据，核心逻辑在 internal/logger。
+//
+// 全局日志级别由 SettingsService 管理；这里可为 db、terminal、window、sync 子系统单独设置级别，
+// 切换 JSON 输出格式，手动轮转日志文件，并按子系统读取内存中的最近日志。
+type LogService struct {
+	BaseService
+}
+
+// NewLogService 创建日志服务
+func NewLogService(deps *ServiceDeps) *LogService {
+	return &LogService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动
+func (s *LogService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *LogService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// GetLogConfig 获取当前日志配置。
+func (s *LogService) GetLogConfig() *types.LogConfigResult {
+	levels := make(map[string]string)
+	for subsystem, l := range logger.SubsystemLevels() {
+		levels[subsystem] = logger.LevelName(l)
+	}
+	return &types.LogConfigResult{
+		BaseResult: types.BaseResult{Success: true, Message: "获取日志配置成功"},
+		Data: &types.LogConfig{
+			Level:           logger.LevelName(logger.Level().Level()),
+			SubsystemLevels: levels,
+			Subsystems:      logger.Subsystems(),
+			JSON:            logger.JSONEnabled(),
+			FilePath:        logger.FilePath(),
+		},
+	}
+}
+
+// SetSubsystemLogLevel 设置子系统的日志级别，level 为空时恢复使用全局级别。
+func (s *LogService) SetSubsystemLogLevel(subsystem, level string) *types.LogConfigResult {
+	if err := validate.New().OneOf("subsystem", subsystem, logger.Subsystems()...).Err(); err != nil {
+		return &types.LogConfigResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if level == "" {
+		logger.ClearSubsystemLevel(subsystem)
+		s.Logger().Info("子系统日志级别已恢复为全局级别", "target", subsystem)
+		return s.GetLogConfig()
+	}
+	l, err := logger.ParseLevel(level)
+	if err != nil {
+		return &types.LogConfigResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if err := logger.SetSubsystemLevel(subsystem, l); err != nil {
+		return &types.LogConfigResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	s.Logger().Info("子系统日志级别已更新", "target", subsystem, "level", logger.LevelName(l))
+	return s.GetLogConfig()
+}
+
+// SetJSONLogging 切换 JSON 输出格式，同时作用于标准错误与日志文件。
+func (s *LogService) SetJSONLogging(enabled bool) *types.LogConfigResult {
+	logger.SetJSON(enabled)
+	s.Logger().Info("日志输出格式已更新", "json", enabled)
+	return s.GetLogConfig()
+}
+
+// RotateLog 立即轮转日志文件，返回轮转后的旧日志路径。
+func (s *LogService) RotateLog() *types.LogRotateResult {
+	rotated, err := logger.Rotate()
+	if err != nil {
+		s.Logger().Warn("轮转日志文件失败", "error", err)
+		return &types.LogRotateResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	s.Logger().Info("日志文件已轮转", "rotated", rotated)
+	return &types.LogRotateResult{BaseResult: types.BaseResult{Success: true, Message: "日志文件已轮转"}, Data: rotated}
+}
+
+// GetRecentLogs 获取内存中最近 limit 条日志，subsystem 为空时不按子系统过滤，level 为空时返回全部级别。
+func (s *LogService) GetRecentLogs(subsystem, level string, limit int) *types.LogEntriesResult {
+	if subsystem != "" {
+		if err := validate.New().OneOf("subsystem", subsystem, logger.Subsystems()...).Err(); err != nil {
+			return &types.LogEntriesResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+		}
+	}
+	minLevel := slog.LevelDebug
+	if level != "" {
+		l, err := logger.ParseLevel(level)
+		if err != nil {
+			return &types.LogEntriesResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+		}
+		minLevel = l
+	}
+	return &types.LogEntriesResult{
+		BaseResult: types.BaseResult{Success: true, Message: "获取最近日志成功"},
+		Data:       logger.RecentFor(subsystem, minLevel, limit),
+	}
+}
@@ -15,17 +15,23 @@
 package service
 
 import (
-	"fmt"
-	"strings"
+	"time"
 
+	"github.com/chenyang-zz/boxify/internal/audit"
 	"github.com/chenyang-zz/boxify/internal/connection"
 	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/ssh"
+	"github.com/chenyang-zz/boxify/internal/validate"
 )
 
 // 通用数据库方法
 
 // DBConnect 连接数据库，成功则返回成功消息，失败则返回错误信息
 func (a *DatabaseService) DBConnect(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBConnect", err)
+	}
+
 	// 连接测试需要强制 ping，避免缓存命中但连接已失效时误判成功
 	_, err := a.getDatabaseForcePing(config)
 	if err != nil {
@@ -43,8 +49,109 @@ func (a *DatabaseService) DBConnect(config *connection.ConnectionConfig) *connec
 	}
 }
 
+// DBDisconnect 断开与该服务器和用户的全部缓存连接（含各库连接及其 SSH 隧道）
+func (a *DatabaseService) DBDisconnect(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBDisconnect", err)
+	}
+	if a.manager == nil {
+		return &connection.QueryResult{Success: true, Message: "连接已断开", Data: map[string]int{"closed": 0}}
+	}
+
+	closed, err := a.manager.Disconnect(config)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "连接已断开", Data: map[string]int{"closed": closed}}
+}
+
+// DBDisconnectAll 关闭所有游标与缓存连接
+func (a *DatabaseService) DBDisconnectAll() *connection.QueryResult {
+	if a.cursors != nil {
+		a.cursors.CloseAll()
+	}
+	if a.manager == nil {
+		return &connection.QueryResult{Success: true, Message: "已断开所有连接", Data: map[string]int{"closed": 0}}
+	}
+
+	closed := a.manager.Count()
+	if err := a.manager.CloseAll(); err != nil {
+		a.Logger().Error("DBDisconnectAll 关闭连接失败", "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBDisconnectAll 已断开所有连接", "count", closed)
+	return &connection.QueryResult{Success: true, Message: "已断开所有连接", Data: map[string]int{"closed": closed}}
+}
+
+// DBGetConnectionHealth 返回各缓存连接最近一次健康检查的状态，状态变化时另以 connection:state 事件推送
+func (a *DatabaseService) DBGetConnectionHealth() *connection.QueryResult {
+	statuses := []db.HealthStatus{}
+	if a.manager != nil {
+		statuses = a.manager.HealthStatuses()
+	}
+	return &connection.QueryResult{Success: true, Message: "获取连接状态成功", Data: statuses}
+}
+
+// DBCheckConnectionHealth 立即检查全部缓存连接并尝试重连失联连接，返回本次检查结果
+func (a *DatabaseService) DBCheckConnectionHealth() *connection.QueryResult {
+	statuses := []db.HealthStatus{}
+	if a.manager != nil {
+		statuses = a.manager.CheckHealth()
+	}
+	return &connection.QueryResult{Success: true, Message: "连接检查完成", Data: statuses}
+}
+
+// DBSetIdleTimeout 设置缓存连接的空闲回收时长（分钟），0 表示不自动回收
+func (a *DatabaseService) DBSetIdleTimeout(minutes int) *connection.QueryResult {
+	if err := validate.New().Range("minutes", minutes, 0, 24*60).Err(); err != nil {
+		return a.invalidArgs("DBSetIdleTimeout", err)
+	}
+	if a.manager == nil {
+		a.manager = db.NewConnectionManager(a.Logger())
+	}
+	a.manager.SetIdleTimeout(time.Duration(minutes) * time.Minute)
+	a.Logger().Info("设置连接空闲回收时长", "minutes", minutes)
+	return &connection.QueryResult{Success: true, Message: "设置成功"}
+}
+
+// DBGetIdleTimeout 返回缓存连接的空闲回收时长（分钟），0 表示不自动回收
+func (a *DatabaseService) DBGetIdleTimeout() *connection.QueryResult {
+	minutes := int(db.DefaultIdleTimeout / time.Minute)
+	if a.manager != nil {
+		minutes = int(a.manager.IdleTimeout() / time.Minute)
+	}
+	return &connection.QueryResult{Success: true, Message: "获取成功", Data: minutes}
+}
+
+// DBGetSSHTunnels 返回 SSH 隧道的状态（跳板机、引用数、重连次数与最近错误）
+func (a *DatabaseService) DBGetSSHTunnels() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取成功", Data: ssh.TunnelStatuses()}
+}
+
+// DBListDrivers 返回 "custom" 连接类型可用的驱动及其能力
+func (a *DatabaseService) DBListDrivers() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取成功", Data: db.Drivers()}
+}
+
+// DBDetectKerberosTicket 探测当前用户的 Kerberos 票据缓存，供配置 Kerberos 认证时提示是否需要先执行 kinit
+func (a *DatabaseService) DBDetectKerberosTicket() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取成功", Data: db.DetectTicketCache()}
+}
+
+// DBGetCapabilities 返回连接对应驱动的能力（事务、多 schema），前端据此隐藏不可用的功能
+func (a *DatabaseService) DBGetCapabilities(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBGetCapabilities", err)
+	}
+	return &connection.QueryResult{Success: true, Message: "获取成功", Data: db.Capabilities(config)}
+}
+
 // TestConnection 测试数据库连接，成功则返回成功消息，失败则返回错误信息
 func (a *DatabaseService) TestConnection(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("TestConnection", err)
+	}
+
 	_, err := a.getDatabaseForcePing(config)
 	if err != nil {
 		a.Logger().Error("TestConnection 连接失败", "summary", db.FormatConnSummary(config), "error", err)
@@ -63,10 +170,14 @@ func (a *DatabaseService) TestConnection(config *connection.ConnectionConfig) *c
 
 // CreateDatabase 创建一个新的数据库
 func (a *DatabaseService) CreateDatabase(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validate.New().ConnectionConfig("config", config).Identifier("dbName", dbName).Err(); err != nil {
+		return a.invalidArgs("CreateDatabase", err)
+	}
+
+	runConfig := config.Clone()
 	runConfig.Database = ""
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{
 			Success: false,
@@ -74,19 +185,13 @@ func (a *DatabaseService) CreateDatabase(config *connection.ConnectionConfig, db
 		}
 	}
 
-	escapedDbName := strings.ReplaceAll(dbName, "`", "``") // MySQL中使用反引号包裹数据库名，并对其中的反引号进行转义
-	query := fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", escapedDbName)
-	dbType := config.Type
-	if dbType == connection.ConnectionTypePostgreSQL || dbType == connection.ConnectionTypeKingbase || dbType == connection.ConnectionTypeHighGo || dbType == connection.ConnectionTypeVastBase {
-		escapedDbName = strings.ReplaceAll(dbName, `"`, `""`) // PostgreSQL及其衍生数据库中使用双引号包裹数据库名，并对其中的双引号进行转义
-		query = fmt.Sprintf("CREATE DATABASE \"%s\"", escapedDbName)
-	} else if dbType == connection.ConnectionTypeTDengine {
-		query = fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", quoteIdentByType(dbType, dbName))
-	} else if dbType == connection.ConnectionTypeMariaDB {
-		// MariaDB 支持 MYSQL 语法
-	}
+	query := buildCreateDatabaseSQL(config.Type, dbName)
 
+	start := time.Now()
 	_, err = dbInst.Exec(query)
+	entry := newAuditEntry(audit.FeatureCreateDatabase, runConfig, dbName, start, err)
+	entry.SQL = query
+	a.Audit(entry)
 	if err != nil {
 		return &connection.QueryResult{
 			Success: false,
@@ -99,3 +204,16 @@ func (a *DatabaseService) CreateDatabase(config *connection.ConnectionConfig, db
 		Message: "数据库创建成功",
 	}
 }
+
+// buildCreateDatabaseSQL 按数据库类型构造建库语句：MySQL 系默认 utf8mb4，TDengine 已存在时跳过。
+func buildCreateDatabaseSQL(dbType connection.ConnectionType, dbName string) string {
+	quoted := db.QuoteIdent(dbType, dbName)
+	switch dbType {
+	case connection.ConnectionTypeMySQL, connection.ConnectionTypeMariaDB, "":
+		return "CREATE DATABASE " + quoted + " CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+	case connection.ConnectionTypeTDengine:
+		return "CREATE DATABASE IF NOT EXISTS " + quoted
+	default:
+		return "CREATE DATABASE " + quoted
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetUsers 获取数据库账号列表。
+func (a *DatabaseService) DBGetUsers(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBGetUsers", err)
+	}
+	manager, res := a.accountManager("DBGetUsers", config)
+	if res != nil {
+		return res
+	}
+
+	users, err := manager.GetUsers()
+	if err != nil {
+		a.Logger().Error("DBGetUsers 获取账号列表失败", "error", err, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取账号列表成功", Data: users}
+}
+
+// DBGetGrants 获取账号已有的权限，每项为一条 GRANT 语句；host 仅 MySQL 使用。
+func (a *DatabaseService) DBGetGrants(config *connection.ConnectionConfig, user, host string) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Identifier("user", user).OptionalIdentifier("host", host).Err(); err != nil {
+		return a.invalidArgs("DBGetGrants", err)
+	}
+	manager, res := a.accountManager("DBGetGrants", config)
+	if res != nil {
+		return res
+	}
+
+	grants, err := manager.GetGrants(user, host)
+	if err != nil {
+		a.Logger().Error("DBGetGrants 获取权限失败", "error", err, "user", user, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取权限成功", Data: grants}
+}
+
+// DBCreateUser 创建数据库账号。
+func (a *DatabaseService) DBCreateUser(config *connection.ConnectionConfig, spec *connection.UserSpec) *connection.QueryResult {
+	v := validate.New().ConnectionConfig("config", config).
+		Check(spec != nil, "spec", validate.CodeRequired, "spec 不能为空")
+	if spec != nil {
+		v.Identifier("spec.user", spec.User).OptionalIdentifier("spec.host", spec.Host)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBCreateUser", err)
+	}
+	manager, res := a.accountManager("DBCreateUser", config)
+	if res != nil {
+		return res
+	}
+
+	// 语句中包含明文密码，日志与审计只记录账号
+	start := time.Now()
+	err := manager.CreateUser(spec)
+	entry := newAuditEntry(audit.FeatureAccounts, config, "", start, err)
+	entry.SQL = "CREATE USER " + accountSummary(spec.User, spec.Host)
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DBCreateUser 创建账号失败", "error", err, "user", spec.User, "host", spec.Host, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBCreateUser 创建账号成功", "user", spec.User, "host", spec.Host, "summary", db.FormatConnSummary(config))
+	return &connection.QueryResult{Success: true, Message: "创建账号成功"}
+}
+
+// DBGrantPrivileges 为账号授予权限，权限名只接受白名单内的写法。
+func (a *DatabaseService) DBGrantPrivileges(config *connection.ConnectionConfig, spec *connection.GrantSpec) *connection.QueryResult {
+	v := validate.New().ConnectionConfig("config", config).
+		Check(spec != nil, "spec", validate.CodeRequired, "spec 不能为空")
+	if spec != nil {
+		v.Identifier("spec.user", spec.User).
+			OptionalIdentifier("spec.host", spec.Host).
+			OptionalIdentifier("spec.database", spec.Database).
+			OptionalIdentifier("spec.schema", spec.Schema).
+			OptionalIdentifier("spec.table", spec.Table).
+			Check(len(spec.Privileges) > 0, "spec.privileges", validate.CodeRequired, "spec.privileges 不能为空")
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBGrantPrivileges", err)
+	}
+	manager, res := a.accountManager("DBGrantPrivileges", config)
+	if res != nil {
+		return res
+	}
+
+	start := time.Now()
+	err := manager.GrantPrivileges(spec)
+	entry := newAuditEntry(audit.FeatureAccounts, config, spec.Database, start, err)
+	entry.Table = spec.Table
+	entry.SQL = grantSummary(spec)
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DBGrantPrivileges 授权失败", "error", err, "user", spec.User, "privileges", spec.Privileges, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBGrantPrivileges 授权成功", "user", spec.User, "privileges", spec.Privileges, "database", spec.Database, "table", spec.Table)
+	return &connection.QueryResult{Success: true, Message: "授权成功"}
+}
+
+// accountManager 获取连接并断言其支持账号管理；失败时返回可直接响应的结果。
+func (a *DatabaseService) accountManager(method string, config *connection.ConnectionConfig) (db.AccountManager, *connection.QueryResult) {
+	dbInst, err := a.getDatabase(config)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(config))
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	manager, ok := dbInst.(db.AccountManager)
+	if !ok {
+		return nil, &connection.QueryResult{Success: false, Message: "当前数据库不支持账号管理"}
+	}
+	return manager, nil
+}
+
+// accountSummary 返回审计记录中的账号，指定主机时为 user@host。
+func accountSummary(user, host string) string {
+	if host == "" {
+		return user
+	}
+	return user + "@" + host
+}
+
+// grantSummary 返回授权操作的审计摘要，作用范围按库、schema、表依次限定，均为空时为全局。
+func grantSummary(spec *connection.GrantSpec) string {
+	var scope []string
+	for _, part := range []string{spec.Database, spec.Schema, spec.Table} {
+		if part != "" {
+			scope = append(scope, part)
+		}
+	}
+	target := "*"
+	if len(scope) > 0 {
+		target = strings.Join(scope, ".")
+	}
+	summary := "GRANT " + strings.Join(spec.Privileges, ", ") + " ON " + target + " TO " + accountSummary(spec.User, spec.Host)
+	if spec.WithGrantOption {
+		summary += " WITH GRANT OPTION"
+	}
+	return summary
+}
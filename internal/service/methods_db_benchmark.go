@@ -0,0 +1,55 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBBenchmarkConnection 测量连接的建连/SSH 握手耗时、pings 次 Ping 延迟与 queries 次 SELECT 1 的延迟和吞吐，
+// 用于排查连接慢的原因；次数为 0 时使用默认值，整体受连接超时限制。
+func (a *DatabaseService) DBBenchmarkConnection(config *connection.ConnectionConfig, pings, queries int) *connection.QueryResult {
+	if err := validate.New().
+		ConnectionConfig("config", config).
+		Range("pings", pings, 0, db.MaxBenchmarkIterations).
+		Range("queries", queries, 0, db.MaxBenchmarkIterations).
+		Err(); err != nil {
+		return a.invalidArgs("DBBenchmarkConnection", err)
+	}
+	if pings == 0 {
+		pings = db.DefaultBenchmarkPings
+	}
+	if queries == 0 {
+		queries = db.DefaultBenchmarkQueries
+	}
+
+	runConfig := normalizeRunConfig(config, config.Database)
+	dbInst, err := a.getDatabaseForcePing(runConfig)
+	if err != nil {
+		a.Logger().Error("DBBenchmarkConnection 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, cancel := utils.ContextWithTimeout(execTimeout(runConfig, &connection.ExecOptions{}))
+	defer cancel()
+
+	report := db.BenchmarkConnection(ctx, dbInst, runConfig, pings, queries)
+	a.Logger().Info("DBBenchmarkConnection 测速完成", "summary", db.FormatConnSummary(runConfig),
+		"pingAvgMs", report.Ping.AvgMs, "queryAvgMs", report.Query.AvgMs, "qps", report.QueriesPerSecond)
+	return &connection.QueryResult{Success: true, Message: "连接测速完成", Data: report}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"mime"
+	"os"
+
+	"github.com/chenyang-zz/boxify/internal/blobstore"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// maxInlineCellBytes 是 DBFetchCellValue 直接返回内容的上限，更大的值需另存为文件。
+const maxInlineCellBytes = 16 << 20
+
+// DBFetchCellValue 按句柄读取查询结果中以引用返回的二进制值。
+// saveToFile 为 false 时在 Data.content 中返回完整内容（base64）；为 true 时弹出保存对话框并写入文件。
+func (a *DatabaseService) DBFetchCellValue(handle string, saveToFile bool) *connection.QueryResult {
+	if err := validate.New().Required("handle", handle).Err(); err != nil {
+		return a.invalidArgs("DBFetchCellValue", err)
+	}
+
+	data, ref, err := a.blobStore().Get(handle)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	if !saveToFile {
+		if ref.Size > maxInlineCellBytes {
+			return &connection.QueryResult{Success: false, Message: fmt.Sprintf("内容过大（%d 字节），请另存为文件", ref.Size)}
+		}
+		return &connection.QueryResult{
+			Success: true,
+			Message: "读取成功",
+			Data:    map[string]interface{}{"handle": ref.Handle, "size": ref.Size, "mimeType": ref.MimeType, "content": data},
+		}
+	}
+
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "保存单元格内容",
+		DefaultFilename: "cell" + cellFileExt(ref.MimeType),
+	})
+	if err != nil || filename == "" {
+		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		a.Logger().Error("DBFetchCellValue 写入文件失败", "file", filename, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBFetchCellValue 已保存单元格内容", "file", filename, "size", ref.Size)
+	return &connection.QueryResult{
+		Success: true,
+		Message: "保存成功",
+		Data:    map[string]interface{}{"handle": ref.Handle, "size": ref.Size, "mimeType": ref.MimeType, "file": filename},
+	}
+}
+
+// blobStore 返回二进制值暂存区，未初始化时懒加载。
+func (a *DatabaseService) blobStore() *blobstore.Store {
+	if a.blobs == nil {
+		a.blobs = blobstore.New(0, 0)
+	}
+	return a.blobs
+}
+
+// cellFileExt 返回 MIME 类型对应的文件扩展名，未知类型使用 .bin。
+func cellFileExt(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err == nil && mediaType != "application/octet-stream" {
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			return exts[0]
+		}
+	}
+	return ".bin"
+}
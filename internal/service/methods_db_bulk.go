@@ -0,0 +1,153 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBBulkUpdatePreview 按过滤条件生成批量 UPDATE/DELETE 语句，并以相同条件的 SELECT COUNT 统计将受影响的行数，不修改数据。
+func (a *DatabaseService) DBBulkUpdatePreview(config *connection.ConnectionConfig, dbName, tableName string, req *connection.BulkUpdateRequest) *connection.QueryResult {
+	if err := validateBulkArgs(config, dbName, tableName, req).Err(); err != nil {
+		return a.invalidArgs("DBBulkUpdatePreview", err)
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, stmt, res := a.prepareBulkStatement("DBBulkUpdatePreview", runConfig, dbName, tableName, req)
+	if res != nil {
+		return res
+	}
+	count, err := a.countBulkRows(dbInst, runConfig, stmt)
+	if err != nil {
+		a.Logger().Error("DBBulkUpdatePreview 统计行数失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("预计影响 %d 行", count),
+		Data:    &connection.BulkUpdatePreview{SQL: stmt.SQL, Args: stmt.Args, CountSQL: stmt.CountSQL, AffectedRows: count},
+	}
+}
+
+// DBBulkUpdateExecute 执行批量 UPDATE/DELETE；expectedRows 为预览得到的行数，
+// 执行前按相同条件重新统计，行数与预览不一致时拒绝执行，需重新预览确认。
+func (a *DatabaseService) DBBulkUpdateExecute(config *connection.ConnectionConfig, dbName, tableName string, req *connection.BulkUpdateRequest, expectedRows int64) *connection.QueryResult {
+	if err := validateBulkArgs(config, dbName, tableName, req).
+		Check(expectedRows >= 0, "expectedRows", validate.CodeOutOfRange, "expectedRows 不能为负数").Err(); err != nil {
+		return a.invalidArgs("DBBulkUpdateExecute", err)
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, stmt, res := a.prepareBulkStatement("DBBulkUpdateExecute", runConfig, dbName, tableName, req)
+	if res != nil {
+		return res
+	}
+	count, err := a.countBulkRows(dbInst, runConfig, stmt)
+	if err != nil {
+		a.Logger().Error("DBBulkUpdateExecute 统计行数失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if count != expectedRows {
+		return &connection.QueryResult{
+			Success: false,
+			Message: fmt.Sprintf("受影响行数已变化（预览 %d 行，当前 %d 行），请重新预览", expectedRows, count),
+			Data:    &connection.BulkUpdatePreview{SQL: stmt.SQL, Args: stmt.Args, CountSQL: stmt.CountSQL, AffectedRows: count},
+		}
+	}
+
+	start := time.Now()
+	affected, err := dbInst.Exec(stmt.SQL, stmt.Args...)
+	entry := newAuditEntry(audit.FeatureBulkUpdate, runConfig, dbName, start, err)
+	entry.Table = tableName
+	entry.SQL = stmt.SQL
+	if err == nil {
+		entry.AffectedRows = affected
+	}
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DBBulkUpdateExecute 执行失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(stmt.SQL))
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: time.Since(start).Milliseconds()}
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      fmt.Sprintf("执行成功，受影响的行数: %d", affected),
+		Data:         map[string]int64{"affectedRows": affected},
+		DurationMs:   time.Since(start).Milliseconds(),
+		RowsAffected: affected,
+	}
+}
+
+// validateBulkArgs 校验批量修改的公共参数。
+func validateBulkArgs(config *connection.ConnectionConfig, dbName, tableName string, req *connection.BulkUpdateRequest) *validate.Validator {
+	v := validateTableArgs(config, dbName, tableName).Check(req != nil, "request", validate.CodeRequired, "批量修改请求不能为空")
+	if req != nil {
+		v.OneOf("action", req.Action, connection.BulkActionUpdate, connection.BulkActionDelete)
+	}
+	return v
+}
+
+// prepareBulkStatement 读取表结构并编译批量修改语句，失败时返回错误结果。
+func (a *DatabaseService) prepareBulkStatement(method string, runConfig *connection.ConnectionConfig, dbName, tableName string, req *connection.BulkUpdateRequest) (db.Database, *db.BulkStatement, *connection.QueryResult) {
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	columns, err := getTableColumns(a.Context(), dbInst, runConfig, dbName, tableName)
+	if err != nil {
+		return nil, nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if len(columns) == 0 {
+		return nil, nil, &connection.QueryResult{Success: false, Message: fmt.Sprintf("表不存在或没有列: %s", tableName)}
+	}
+	stmt, err := db.BuildBulkStatement(runConfig.Type, tableName, columns, req)
+	if err != nil {
+		return nil, nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return dbInst, stmt, nil
+}
+
+// countBulkRows 执行计数语句并返回行数。
+func (a *DatabaseService) countBulkRows(dbInst db.Database, runConfig *connection.ConnectionConfig, stmt *db.BulkStatement) (int64, error) {
+	rows, columns, err := a.queryReferenceRows(dbInst, runConfig, stmt.CountSQL, stmt.CountArgs)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) != 1 || len(columns) != 1 {
+		return 0, fmt.Errorf("计数查询返回了意外的结果")
+	}
+	return parseCountValue(rows[0][columns[0]])
+}
+
+// parseCountValue 将驱动返回的 COUNT(*) 值转换为整数。
+func parseCountValue(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case []byte:
+		v = string(n)
+	}
+	count, err := strconv.ParseInt(strings.TrimSpace(fmt.Sprint(v)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析计数结果 %v", v)
+	}
+	return count, nil
+}
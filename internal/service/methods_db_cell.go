@@ -0,0 +1,116 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/cellformat"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DBGetCellDetail 按键读取单个单元格的完整内容，识别 JSON / XML 并美化。
+// key 为定位行的列名与值（通常取自 DBGetTableKeys）；saveToFile 为 true 时弹出保存对话框并写入美化后的内容。
+func (a *DatabaseService) DBGetCellDetail(config *connection.ConnectionConfig, dbName, tableName, column string, key map[string]interface{}, saveToFile bool) *connection.QueryResult {
+	keyColumns := make([]string, 0, len(key))
+	for name := range key {
+		keyColumns = append(keyColumns, name)
+	}
+	if err := validateTableArgs(config, dbName, tableName).
+		Identifier("column", column).
+		Check(len(key) > 0, "key", validate.CodeRequired, "key 不能为空").
+		Identifiers("key", keyColumns).
+		Err(); err != nil {
+		return a.invalidArgs("DBGetCellDetail", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetCellDetail 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	query, args := db.BuildRowLookupQuery(runConfig.Type, tableName, column, key)
+	var rows []map[string]interface{}
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		rows, _, err = q.QueryContext(ctx, query, args...)
+	} else {
+		rows, _, err = dbInst.Query(query, args...)
+	}
+	if err != nil {
+		a.Logger().Error("DBGetCellDetail 查询失败", "error", err, "table", tableName, "column", column)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	switch len(rows) {
+	case 0:
+		return &connection.QueryResult{Success: false, Message: "未找到对应的行，数据可能已被修改或删除"}
+	case 1:
+	default:
+		return &connection.QueryResult{Success: false, Message: "键匹配到多行，无法定位单元格"}
+	}
+
+	value := rows[0][column]
+	if value == nil {
+		return &connection.QueryResult{Success: true, Message: "单元格为 NULL", Data: map[string]interface{}{"isNull": true, "format": cellformat.FormatText, "content": ""}}
+	}
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	detail := cellformat.Pretty(text)
+
+	if !saveToFile {
+		return &connection.QueryResult{
+			Success: true,
+			Message: "读取成功",
+			Data:    map[string]interface{}{"isNull": false, "format": detail.Format, "content": detail.Content, "size": len(text)},
+		}
+	}
+
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           fmt.Sprintf("导出 %s.%s", tableName, column),
+		DefaultFilename: fmt.Sprintf("%s_%s%s", tableName, column, cellformat.Ext(detail.Format)),
+	})
+	if err != nil || filename == "" {
+		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+	if err := os.WriteFile(filename, []byte(detail.Content), 0o644); err != nil {
+		a.Logger().Error("DBGetCellDetail 写入文件失败", "file", filename, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBGetCellDetail 已导出单元格内容", "file", filename, "format", detail.Format, "size", len(detail.Content))
+	return &connection.QueryResult{
+		Success: true,
+		Message: "导出成功",
+		Data:    map[string]interface{}{"isNull": false, "format": detail.Format, "file": filename, "size": len(detail.Content)},
+	}
+}
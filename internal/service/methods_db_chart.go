@@ -0,0 +1,101 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBAggregateForChart 在数据库端按分组列（可按时间分桶）与可选的系列列聚合，返回标签与系列，
+// 避免将大量原始行传给前端绘图。数据源为 req.Query 或 req.Table 之一。
+func (a *DatabaseService) DBAggregateForChart(config *connection.ConnectionConfig, dbName string, req *connection.ChartRequest) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).Check(req != nil, "request", validate.CodeRequired, "图表请求不能为空")
+	if req != nil {
+		v.Check((req.Query == "") != (req.Table == ""), "request", validate.CodeInvalid, "query 与 table 需且只能指定一个").
+			Check(req.Query == "" || isCursorQuery(req.Query), "request.query", validate.CodeNotAllowed, "图表数据源仅支持 SELECT 类查询").
+			OptionalIdentifier("request.table", req.Table).
+			Identifier("request.groupBy", req.GroupBy).
+			OptionalIdentifier("request.seriesBy", req.SeriesBy).
+			OptionalIdentifier("request.valueColumn", req.ValueColumn).
+			OneOf("request.aggregate", req.Aggregate, db.ChartAggregates...).
+			Range("request.maxLabels", req.MaxLabels, 0, db.MaxChartLabels).
+			Range("request.maxSeries", req.MaxSeries, 0, db.MaxChartSeries)
+		if req.TimeBucket != "" {
+			v.OneOf("request.timeBucket", req.TimeBucket, db.ChartTimeBuckets...)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBAggregateForChart", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	query, err := db.BuildChartQuery(runConfig.Type, req)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBAggregateForChart 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	start := time.Now()
+	var data []map[string]interface{}
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, _, err = q.QueryContext(ctx, query)
+	} else {
+		data, _, err = dbInst.Query(query)
+	}
+	elapsed := time.Since(start)
+	if err != nil {
+		a.Logger().Error("DBAggregateForChart 查询失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds()}
+	}
+
+	seriesName := fmt.Sprintf("%s(%s)", req.Aggregate, req.ValueColumn)
+	if req.ValueColumn == "" {
+		seriesName = req.Aggregate + "(*)"
+	}
+	chart := db.ShapeChartData(data, req, seriesName)
+	message := "聚合成功"
+	if chart.Truncated {
+		message = "聚合成功，分组或系列超过上限已截断"
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      message,
+		Data:         chart,
+		Truncated:    chart.Truncated,
+		DurationMs:   elapsed.Milliseconds(),
+		RowsReturned: len(data),
+	}
+}
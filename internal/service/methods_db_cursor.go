@@ -0,0 +1,105 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/blobstore"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/cursor"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// OpenCursor 为查询打开服务端游标，供数据表格按区间滚动读取超大结果集。
+func (a *DatabaseService) OpenCursor(config *connection.ConnectionConfig, dbName, query string, args []any) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).
+		Required("query", query).
+		Check(isCursorQuery(query), "query", validate.CodeNotAllowed, "游标仅支持 SELECT 类查询").
+		Err(); err != nil {
+		return a.invalidArgs("OpenCursor", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("OpenCursor 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	streamer, ok := dbInst.(db.RowStreamer)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持游标读取"}
+	}
+
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	blobs := a.blobStore().NewResult()
+	info, err := a.cursorManager().Open(a.ctx, func(ctx context.Context) (cursor.Source, error) {
+		stream, err := streamer.QueryStream(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		stream.SetBlobSink(blobs, blobstore.DefaultInlineLimit)
+		return stream, nil
+	})
+	if err != nil {
+		a.Logger().Error("OpenCursor 打开游标失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "游标已打开", Data: info, Fields: info.Columns}
+}
+
+// FetchRange 读取游标 [start, start+count) 区间的行。
+func (a *DatabaseService) FetchRange(cursorID string, start, count int) *connection.QueryResult {
+	if err := validate.New().
+		Required("cursorId", cursorID).
+		Check(start >= 0, "start", validate.CodeOutOfRange, "start 不能为负数").
+		PageSize("count", count, cursor.DefaultMaxFetchRows).
+		Err(); err != nil {
+		return a.invalidArgs("FetchRange", err)
+	}
+
+	window, err := a.cursorManager().FetchRange(cursorID, start, count)
+	if err != nil {
+		a.Logger().Warn("FetchRange 读取失败", "cursorId", cursorID, "start", start, "count", count, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "读取成功", Data: window}
+}
+
+// CloseCursor 关闭游标并释放其占用的连接。
+func (a *DatabaseService) CloseCursor(cursorID string) *connection.QueryResult {
+	if err := a.cursorManager().Close(cursorID); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "游标已关闭"}
+}
+
+// cursorManager 返回游标管理器，未初始化时懒加载。
+func (a *DatabaseService) cursorManager() *cursor.Manager {
+	if a.cursors == nil {
+		a.cursors = cursor.NewManager(a.Logger(), cursor.DefaultOptions())
+	}
+	return a.cursors
+}
+
+// isCursorQuery 判断语句是否为可用游标读取的只读查询。
+// EXPLAIN ANALYZE 会实际执行被分析的语句，因此 EXPLAIN 不视为只读查询；
+// 多语句输入中后续语句可能修改数据，同样不视为只读查询。
+func isCursorQuery(query string) bool {
+	c := sqllint.Classify(query, false)
+	return c.Kind == sqllint.StatementQuery && c.ReturnsRows && !c.Multiple && c.Keyword != "EXPLAIN"
+}
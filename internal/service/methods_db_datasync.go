@@ -0,0 +1,226 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// dataSyncState 是一次数据同步比较的结果及执行所需的目标连接。
+type dataSyncState struct {
+	plan      *connection.DataSyncPlan
+	changes   *connection.ChangeSet // 比较结果不完整时为 nil
+	runConfig *connection.ConnectionConfig
+	dbInst    db.Database
+	key       *connection.TableKey
+	columns   []*connection.ColumnDefinition
+}
+
+// DataSyncCompare 按键比较源表与目标表的行，返回使目标表与源表一致所需的插入、更新、删除数量及预览语句，不修改数据。
+// 两侧可以来自不同连接；任一侧超过读取上限时只返回计数，不生成语句。
+func (a *DatabaseService) DataSyncCompare(req *connection.DataSyncRequest) *connection.QueryResult {
+	state, res := a.prepareDataSync("DataSyncCompare", req)
+	if res != nil {
+		return res
+	}
+	plan := state.plan
+	message := fmt.Sprintf("比较完成：插入 %d 行，更新 %d 行，删除 %d 行", plan.Inserts, plan.Updates, plan.Deletes)
+	if plan.Truncated {
+		message = fmt.Sprintf("比较完成（超过 %d 行，仅比较前 %d 行，无法生成同步语句）", req.MaxRows, req.MaxRows)
+	}
+	return &connection.QueryResult{Success: true, Message: message, Data: plan}
+}
+
+// DataSyncApply 重新比较两侧数据后在目标表执行同步变更，全部变更在一个事务内提交，任一失败整体回滚。
+// dryRun 为 true 时只返回比较结果与预览语句；比较结果不完整或存在重复键时拒绝执行。
+func (a *DatabaseService) DataSyncApply(req *connection.DataSyncRequest, dryRun bool) *connection.QueryResult {
+	state, res := a.prepareDataSync("DataSyncApply", req)
+	if res != nil {
+		return res
+	}
+	plan := state.plan
+	switch {
+	case plan.Truncated:
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("数据超过 %d 行，比较结果不完整，无法同步", req.MaxRows), Data: plan}
+	case plan.DuplicateKeys > 0:
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("存在 %d 行重复键，无法按键同步", plan.DuplicateKeys), Data: plan}
+	}
+	if dryRun {
+		return &connection.QueryResult{Success: true, Message: "预览同步语句", Data: plan}
+	}
+	total := len(state.changes.Inserts) + len(state.changes.Updates) + len(state.changes.Deletes)
+	if total == 0 {
+		return &connection.QueryResult{Success: true, Message: "目标表已与源表一致", Data: plan}
+	}
+
+	applier, ok := state.dbInst.(db.BatchApplier)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持数据同步"}
+	}
+	if !db.Capabilities(state.runConfig).SupportsTransactions {
+		return &connection.QueryResult{Success: false, Message: "当前驱动不支持事务，无法保证同步整体回滚", Data: plan}
+	}
+	normalized, _, err := db.NormalizeChangeSet(state.key, state.columns, state.changes)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	start := time.Now()
+	err = applier.ApplyChanges(req.Target.Table, normalized)
+	entry := newAuditEntry(audit.FeatureDataSync, state.runConfig, req.Target.DBName, start, err)
+	entry.Table = req.Target.Table
+	entry.SQL = fmt.Sprintf("INSERT %d, UPDATE %d, DELETE %d", len(normalized.Inserts), len(normalized.Updates), len(normalized.Deletes))
+	if err == nil {
+		entry.AffectedRows = int64(total)
+	}
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DataSyncApply 执行失败", "error", err, "table", req.Target.Table, "summary", db.FormatConnSummary(state.runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error(), Data: plan}
+	}
+	plan.Applied = true
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("同步完成：插入 %d 行，更新 %d 行，删除 %d 行", plan.Inserts, plan.Updates, plan.Deletes),
+		Data:    plan,
+	}
+}
+
+// prepareDataSync 校验请求、确定键列并读取两侧数据进行比较，失败时返回错误结果。
+// 键列默认取目标表的主键或非空唯一索引，因为生成的语句在目标表上按键定位。
+func (a *DatabaseService) prepareDataSync(method string, req *connection.DataSyncRequest) (*dataSyncState, *connection.QueryResult) {
+	v := validate.New().Check(req != nil, "req", validate.CodeRequired, "req 不能为空")
+	if req != nil {
+		v.Check(req.Source != nil, "req.source", validate.CodeRequired, "source 不能为空").
+			Check(req.Target != nil, "req.target", validate.CodeRequired, "target 不能为空").
+			Identifiers("req.keyColumns", req.KeyColumns).
+			Identifiers("req.columns", req.Columns).
+			Range("req.maxRows", req.MaxRows, 0, maxDiffMaxRows)
+		for _, side := range []struct {
+			name string
+			s    *connection.DataSyncSide
+		}{{"req.source", req.Source}, {"req.target", req.Target}} {
+			if side.s == nil {
+				continue
+			}
+			v.ConnectionConfig(side.name+".config", side.s.Config).
+				OptionalIdentifier(side.name+".dbName", side.s.DBName).
+				Identifier(side.name+".table", side.s.Table)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, a.invalidArgs(method, err)
+	}
+	if req.MaxRows <= 0 {
+		req.MaxRows = defaultDiffMaxRows
+	}
+
+	runConfig := normalizeRunConfig(req.Target.Config, req.Target.DBName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	key, columns, err := resolveTableKey(a.Context(), dbInst, runConfig, req.Target.DBName, req.Target.Table)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if len(req.KeyColumns) > 0 {
+		key = &connection.TableKey{Kind: db.TableKeyUnique, Columns: req.KeyColumns}
+	} else if key.Kind == db.TableKeyNone {
+		return nil, &connection.QueryResult{Success: false, Message: "目标表没有主键或非空唯一索引，请指定键列"}
+	}
+
+	ctx := context.Background()
+	targetData, err := a.readQueryInput(ctx, method, dataSyncQuerySide(req.Target, key.Columns, req.Columns), req.MaxRows)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: fmt.Sprintf("读取目标表失败: %v", err)}
+	}
+	sourceData, err := a.readQueryInput(ctx, method, dataSyncQuerySide(req.Source, key.Columns, req.Columns), req.MaxRows)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: fmt.Sprintf("读取源表失败: %v", err)}
+	}
+	result, err := resultdiff.Diff(targetData.input, sourceData.input, key.Columns, req.MaxRows)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	plan := &connection.DataSyncPlan{
+		KeyColumns:    key.Columns,
+		Columns:       result.Columns,
+		SourceRows:    result.RightRows,
+		TargetRows:    result.LeftRows,
+		Unchanged:     result.UnchangedCount,
+		DuplicateKeys: result.DuplicateKeys,
+		Truncated:     targetData.truncated || sourceData.truncated || result.Truncated,
+		Statements:    []string{},
+	}
+	if !req.SkipInserts {
+		plan.Inserts = result.AddedCount
+	}
+	if !req.SkipUpdates {
+		plan.Updates = result.ChangedCount
+	}
+	if !req.SkipDeletes {
+		plan.Deletes = result.RemovedCount
+	}
+	state := &dataSyncState{plan: plan, runConfig: runConfig, dbInst: dbInst, key: key, columns: columns}
+	if plan.Truncated {
+		return state, nil
+	}
+
+	state.changes, err = db.BuildSyncChangeSet(result, result.Columns, db.SyncOptions{
+		SkipInserts: req.SkipInserts,
+		SkipUpdates: req.SkipUpdates,
+		SkipDeletes: req.SkipDeletes,
+	})
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	plan.Statements, plan.StatementsMore = db.RenderChangeSetSQL(runConfig.Type, req.Target.Table, db.SkipGeneratedColumns(columns, state.changes), db.MaxSyncPreviewStatements)
+	return state, nil
+}
+
+// dataSyncQuerySide 构造读取一侧表数据的查询；指定了同步列时只读取键列与这些列。
+func dataSyncQuerySide(side *connection.DataSyncSide, keyColumns, columns []string) *connection.DiffQuerySide {
+	selectList := "*"
+	if len(columns) > 0 {
+		b := db.NewSQLBuilder(side.Config.Type)
+		quoted := make([]string, 0, len(keyColumns)+len(columns))
+		seen := make(map[string]bool, len(keyColumns)+len(columns))
+		for _, col := range append(append([]string{}, keyColumns...), columns...) {
+			if seen[col] {
+				continue
+			}
+			seen[col] = true
+			quoted = append(quoted, b.QuoteIdent(col))
+		}
+		selectList = strings.Join(quoted, ", ")
+	}
+	return &connection.DiffQuerySide{
+		Config: side.Config,
+		DBName: side.DBName,
+		Query:  "SELECT " + selectList + " FROM " + db.QuoteTable(side.Config.Type, side.Table),
+	}
+}
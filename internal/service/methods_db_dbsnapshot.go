@@ -0,0 +1,303 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/dbsnapshot"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBCreateDatabaseSnapshot 在后台任务中将选定表的结构与数据保存为本地快照归档，立即返回任务快照。
+func (a *DatabaseService) DBCreateDatabaseSnapshot(config *connection.ConnectionConfig, dbName, name string, tables []string) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).
+		Required("name", name).
+		Check(len(tables) > 0, "tables", validate.CodeRequired, "至少选择一张表").
+		Identifiers("tables", tables)
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBCreateDatabaseSnapshot", err)
+	}
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	job := m.Submit(jobKindDBSnapshot, fmt.Sprintf("快照 %s", name), func(ctx context.Context, r jobs.Reporter) (any, error) {
+		dbInst, err := a.getDatabase(runConfig)
+		if err != nil {
+			a.Logger().Error("DBCreateDatabaseSnapshot 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+			return nil, err
+		}
+		src, err := dbsnapshot.NewDBSource(dbInst, runConfig.Type, dbName)
+		if err != nil {
+			return nil, err
+		}
+		version, err := db.ServerVersion(dbInst, runConfig.Type)
+		if err != nil {
+			a.Logger().Warn("DBCreateDatabaseSnapshot 读取服务器版本失败", "error", err)
+		}
+		return a.dbSnapshotStore().Capture(ctx, src, dbsnapshot.CaptureOptions{
+			Name:          name,
+			SourceType:    runConfig.Type,
+			ServerVersion: version,
+			Source:        db.FormatConnSummary(runConfig),
+			Database:      dbName,
+			Tables:        tables,
+		}, r.Report)
+	})
+	return &connection.QueryResult{Success: true, Message: "快照任务已启动", Data: job}
+}
+
+// DBRestoreDatabaseSnapshot 在后台任务中将快照恢复到指定连接（可与来源不同），立即返回任务快照。
+// tables 为空时恢复全部表；mode 为 replace（重建表）或 data-only（仅替换数据）。
+func (a *DatabaseService) DBRestoreDatabaseSnapshot(id string, config *connection.ConnectionConfig, dbName string, tables []string, mode string) *connection.QueryResult {
+	v := validate.New().
+		Required("id", id).
+		ConnectionConfig("config", config).
+		OptionalIdentifier("dbName", dbName).
+		Identifiers("tables", tables)
+	if mode != "" {
+		v.OneOf("mode", mode, string(dbsnapshot.RestoreReplace), string(dbsnapshot.RestoreDataOnly))
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBRestoreDatabaseSnapshot", err)
+	}
+	manifest, err := a.dbSnapshotStore().Get(id)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	restoreMode := dbsnapshot.RestoreMode(strings.ToLower(strings.TrimSpace(mode)))
+	if restoreMode == "" {
+		restoreMode = dbsnapshot.RestoreReplace
+	}
+	job := a.submitRestore(m, fmt.Sprintf("恢复快照 %s", manifest.Name), restoreRequest{
+		caller:    "DBRestoreDatabaseSnapshot",
+		id:        id,
+		runConfig: normalizeRunConfig(config, dbName),
+		dbName:    dbName,
+		opts:      dbsnapshot.RestoreOptions{TargetType: config.Type, Tables: tables, Mode: restoreMode},
+		auditSQL:  fmt.Sprintf("RESTORE SNAPSHOT %s (%s) MODE %s", manifest.Name, id, restoreMode),
+		tables:    tables,
+	})
+	return &connection.QueryResult{Success: true, Message: "恢复任务已启动", Data: job}
+}
+
+// DBRestoreBackupPreflight 检查快照（手动快照或定时备份）能否恢复到目标连接的 dbName 数据库，返回各项检查结果。
+// dbName 可以尚不存在，createDatabase 为 true 时视为将在恢复前创建；tables 与 mode 同 DBRestoreDatabaseSnapshot。
+func (a *DatabaseService) DBRestoreBackupPreflight(id string, config *connection.ConnectionConfig, dbName string, tables []string, mode string, createDatabase bool) *connection.QueryResult {
+	if err := validateRestoreBackupArgs(id, config, dbName, tables, mode); err != nil {
+		return a.invalidArgs("DBRestoreBackupPreflight", err)
+	}
+	ctx, cancel := metadataContext(a.Context(), config)
+	defer cancel()
+	report, _, err := a.restoreBackupPreflight(ctx, id, config, dbName, tables, mode, createDatabase)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "恢复前检查完成", Data: report}
+}
+
+// DBRestoreBackup 先执行恢复前检查，通过后在后台任务中恢复快照：目标库不存在且 createDatabase 为 true 时先建库。
+// 检查未通过时返回失败并附带检查报告；恢复进度通过任务进度事件推送，立即返回任务快照。
+func (a *DatabaseService) DBRestoreBackup(id string, config *connection.ConnectionConfig, dbName string, tables []string, mode string, createDatabase bool) *connection.QueryResult {
+	if err := validateRestoreBackupArgs(id, config, dbName, tables, mode); err != nil {
+		return a.invalidArgs("DBRestoreBackup", err)
+	}
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	ctx, cancel := metadataContext(a.Context(), config)
+	report, opts, err := a.restoreBackupPreflight(ctx, id, config, dbName, tables, mode, createDatabase)
+	cancel()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if !report.OK {
+		return &connection.QueryResult{Success: false, Message: "恢复前检查未通过", Data: report}
+	}
+
+	job := a.submitRestore(m, fmt.Sprintf("恢复备份 %s 到 %s", report.SnapshotName, dbName), restoreRequest{
+		caller:    "DBRestoreBackup",
+		id:        id,
+		runConfig: normalizeRunConfig(config, dbName),
+		dbName:    dbName,
+		opts:      opts,
+		auditSQL:  fmt.Sprintf("RESTORE BACKUP %s (%s) MODE %s", report.SnapshotName, id, opts.Mode),
+		tables:    report.Tables,
+		prepare: func(r jobs.Reporter) error {
+			if !report.CreateDatabase {
+				return nil
+			}
+			r.Report(0, report.Rows, "创建数据库 "+dbName)
+			if res := a.CreateDatabase(config, dbName); !res.Success {
+				return fmt.Errorf("创建数据库失败：%s", res.Message)
+			}
+			return nil
+		},
+		result: map[string]interface{}{"preflight": report},
+	})
+	return &connection.QueryResult{Success: true, Message: "恢复任务已启动", Data: job}
+}
+
+// restoreRequest 描述一次后台恢复任务：目标连接、恢复选项与审计内容。
+type restoreRequest struct {
+	caller    string                       // 日志中的调用方名称
+	id        string                       // 快照 ID
+	runConfig *connection.ConnectionConfig // 已切换到目标库的连接配置
+	dbName    string
+	opts      dbsnapshot.RestoreOptions
+	auditSQL  string                      // 审计记录的操作描述
+	tables    []string                    // 审计记录的表列表
+	prepare   func(r jobs.Reporter) error // 恢复前的准备步骤（如建库），可为空
+	result    map[string]interface{}      // 附加到任务结果中的字段，可为空
+}
+
+// submitRestore 提交后台恢复任务：获取目标连接、恢复快照并写入审计记录，
+// 快照恢复与备份恢复共用这一流程。
+func (a *DatabaseService) submitRestore(m *jobs.Manager, title string, req restoreRequest) jobs.Job {
+	return m.Submit(jobKindDBRestore, title, func(ctx context.Context, r jobs.Reporter) (any, error) {
+		if req.prepare != nil {
+			if err := req.prepare(r); err != nil {
+				return nil, err
+			}
+		}
+		dbInst, err := a.getDatabase(req.runConfig)
+		if err != nil {
+			a.Logger().Error(req.caller+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(req.runConfig))
+			return nil, err
+		}
+		dst, err := datatransfer.NewDBTarget(dbInst)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		rows, err := a.dbSnapshotStore().Restore(ctx, req.id, dst, req.opts, r.Report)
+		entry := newAuditEntry(audit.FeatureSnapshot, req.runConfig, req.dbName, start, err)
+		entry.Table = strings.Join(req.tables, ",")
+		entry.SQL = req.auditSQL
+		entry.AffectedRows = rows
+		a.Audit(entry)
+		if err != nil {
+			return nil, err
+		}
+		result := map[string]interface{}{"rows": rows}
+		for k, v := range req.result {
+			result[k] = v
+		}
+		return result, nil
+	})
+}
+
+// validateRestoreBackupArgs 校验恢复备份的参数，目标数据库必须指定。
+func validateRestoreBackupArgs(id string, config *connection.ConnectionConfig, dbName string, tables []string, mode string) error {
+	v := validate.New().
+		Required("id", id).
+		ConnectionConfig("config", config).
+		Identifier("dbName", dbName).
+		Identifiers("tables", tables)
+	if mode != "" {
+		v.OneOf("mode", mode, string(dbsnapshot.RestoreReplace), string(dbsnapshot.RestoreDataOnly))
+	}
+	return v.Err()
+}
+
+// restoreBackupPreflight 读取快照清单与目标现状（库是否存在、服务器版本、已有表）并执行恢复前检查。
+func (a *DatabaseService) restoreBackupPreflight(ctx context.Context, id string, config *connection.ConnectionConfig, dbName string, tables []string, mode string, createDatabase bool) (*dbsnapshot.PreflightReport, dbsnapshot.RestoreOptions, error) {
+	opts := dbsnapshot.RestoreOptions{
+		TargetType: config.Type,
+		Tables:     tables,
+		Mode:       dbsnapshot.RestoreMode(strings.ToLower(strings.TrimSpace(mode))),
+	}
+	if opts.Mode == "" {
+		opts.Mode = dbsnapshot.RestoreReplace
+	}
+	manifest, err := a.dbSnapshotStore().Get(id)
+	if err != nil {
+		return nil, opts, err
+	}
+
+	serverConfig := config.Clone()
+	serverConfig.Database = ""
+	serverInst, err := a.getDatabase(serverConfig)
+	if err != nil {
+		return nil, opts, fmt.Errorf("连接目标服务器失败：%w", err)
+	}
+	target := dbsnapshot.TargetInfo{Type: config.Type, Database: dbName, CreateDatabase: createDatabase}
+	if target.Version, err = db.ServerVersion(serverInst, config.Type); err != nil {
+		a.Logger().Warn("恢复前检查读取服务器版本失败", "error", err, "summary", db.FormatConnSummary(serverConfig))
+	}
+	databases, err := serverInst.GetDatabases(ctx)
+	if err != nil {
+		return nil, opts, fmt.Errorf("读取数据库列表失败：%w", err)
+	}
+	for _, name := range databases {
+		if strings.EqualFold(name, dbName) {
+			target.DatabaseExists = true
+			break
+		}
+	}
+	if target.DatabaseExists {
+		dbInst, err := a.getDatabase(normalizeRunConfig(config, dbName))
+		if err != nil {
+			return nil, opts, fmt.Errorf("连接目标数据库失败：%w", err)
+		}
+		if target.Tables, err = dbInst.GetTables(ctx, dbName); err != nil {
+			return nil, opts, fmt.Errorf("读取目标表列表失败：%w", err)
+		}
+	}
+	return dbsnapshot.Preflight(manifest, target, opts), opts, nil
+}
+
+// ListDatabaseSnapshots 列出本地保存的数据库快照清单。
+func (a *DatabaseService) ListDatabaseSnapshots() *connection.QueryResult {
+	list, err := a.dbSnapshotStore().List()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取数据库快照列表成功", Data: list}
+}
+
+// DeleteDatabaseSnapshot 删除数据库快照归档。
+func (a *DatabaseService) DeleteDatabaseSnapshot(id string) *connection.QueryResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return a.invalidArgs("DeleteDatabaseSnapshot", err)
+	}
+	if err := a.dbSnapshotStore().Delete(id); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "数据库快照已删除"}
+}
+
+// dbSnapshotStore 返回数据库快照存储，未初始化时使用默认目录。
+func (a *DatabaseService) dbSnapshotStore() *dbsnapshot.Store {
+	if a.dbSnapshots == nil {
+		a.dbSnapshots = dbsnapshot.NewStore("", a.Logger())
+	}
+	return a.dbSnapshots
+}
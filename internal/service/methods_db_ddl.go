@@ -0,0 +1,147 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBCreateTable 根据结构化表定义生成并执行 CREATE TABLE；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBCreateTable(config *connection.ConnectionConfig, dbName string, def *connection.TableDefinition, dryRun bool) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).Check(def != nil, "definition", validate.CodeRequired, "definition 不能为空")
+	if def != nil {
+		v.Identifier("definition.name", def.Name)
+		for i, col := range def.Columns {
+			if col != nil {
+				v.Identifier(fmt.Sprintf("definition.columns[%d].name", i), col.Name)
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBCreateTable", err)
+	}
+	return a.runDDL("DBCreateTable", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.CreateTable(schema, def)
+	})
+}
+
+// DBAlterTable 根据结构化变更生成并执行 ALTER TABLE；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBAlterTable(config *connection.ConnectionConfig, dbName string, alt *connection.TableAlteration, dryRun bool) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).Check(alt != nil, "changes", validate.CodeRequired, "changes 不能为空")
+	if alt != nil {
+		v.Identifier("changes.table", alt.Table).
+			OptionalIdentifier("changes.renameTo", alt.RenameTo).
+			Identifiers("changes.dropColumns", alt.DropColumns)
+		for i, r := range alt.RenameColumns {
+			v.Identifier(fmt.Sprintf("changes.renameColumns[%d].from", i), r.From).
+				Identifier(fmt.Sprintf("changes.renameColumns[%d].to", i), r.To)
+		}
+		for i, col := range append(append([]*connection.ColumnDefinition{}, alt.AddColumns...), alt.ModifyColumns...) {
+			if col != nil {
+				v.Identifier(fmt.Sprintf("changes.columns[%d].name", i), col.Name)
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBAlterTable", err)
+	}
+	return a.runDDL("DBAlterTable", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.AlterTable(schema, alt)
+	})
+}
+
+// DBDropTable 生成并执行 DROP TABLE；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBDropTable(config *connection.ConnectionConfig, dbName, tableName string, ifExists, dryRun bool) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBDropTable", err)
+	}
+	return a.runDDL("DBDropTable", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.DropTable(schema, tableName, ifExists)
+	})
+}
+
+// DBAddIndex 为表添加索引，index 为同一索引按列展开的定义；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBAddIndex(config *connection.ConnectionConfig, dbName, tableName string, index []*connection.IndexDefinition, dryRun bool) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName).Check(len(index) > 0, "index", validate.CodeRequired, "index 不能为空")
+	for i, part := range index {
+		if part != nil {
+			v.Identifier(fmt.Sprintf("index[%d].name", i), part.Name).
+				Identifier(fmt.Sprintf("index[%d].columnName", i), part.ColumnName)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBAddIndex", err)
+	}
+	return a.runDDL("DBAddIndex", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.AddIndex(schema, tableName, index)
+	})
+}
+
+// DBDropIndex 删除表上的索引；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBDropIndex(config *connection.ConnectionConfig, dbName, tableName, indexName string, dryRun bool) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Identifier("indexName", indexName).Err(); err != nil {
+		return a.invalidArgs("DBDropIndex", err)
+	}
+	return a.runDDL("DBDropIndex", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.DropIndex(schema, tableName, indexName)
+	})
+}
+
+// runDDL 生成 DDL 并按顺序执行；执行失败时返回已执行条数与失败的语句，便于用户判断当前表结构状态。
+func (a *DatabaseService) runDDL(method string, config *connection.ConnectionConfig, dbName string, dryRun bool, build func(db.DDLBuilder, string) ([]string, error)) *connection.QueryResult {
+	runConfig := normalizeRunConfig(config, dbName)
+	builder, err := db.NewDDLBuilder(runConfig.Type)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	statements, err := build(builder, db.DDLSchema(runConfig.Type, dbName))
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if dryRun {
+		return &connection.QueryResult{Success: true, Message: "已生成 DDL", Data: map[string]any{"statements": statements}}
+	}
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	for i, stmt := range statements {
+		start := time.Now()
+		_, err := dbInst.Exec(stmt)
+		entry := newAuditEntry(audit.FeatureDDL, runConfig, dbName, start, err)
+		entry.SQL = stmt
+		a.Audit(entry)
+		if err != nil {
+			a.Logger().Error(method+" 执行 DDL 失败", "error", err, "executed", i, "summary", db.FormatConnSummary(runConfig))
+			return &connection.QueryResult{
+				Success: false,
+				Message: fmt.Sprintf("第 %d 条语句执行失败: %v", i+1, err),
+				Data:    map[string]any{"statements": statements, "executed": i, "failed": stmt},
+			}
+		}
+	}
+	a.schemas.invalidate(schemaCacheKey(runConfig, dbName))
+	a.Logger().Info(method+" 执行 DDL 成功", "count", len(statements), "summary", db.FormatConnSummary(runConfig))
+	return &connection.QueryResult{Success: true, Message: "执行 DDL 成功", Data: map[string]any{"statements": statements, "executed": len(statements)}}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// 结果集比较时每侧读取的行数上限。
+const (
+	defaultDiffMaxRows = 10000
+	maxDiffMaxRows     = 100000
+)
+
+// diffSideData 是一侧查询读取到的结果。
+type diffSideData struct {
+	input     resultdiff.Input
+	truncated bool
+}
+
+// DBDiffQueryResults 分别执行两侧查询，按 keyColumns 匹配行并返回新增、删除、变更行及单元格差异。
+// 两侧可以是不同连接（如生产与预发），用于核对迁移结果；maxRows 为每侧读取的最大行数，<=0 时取默认值。
+func (a *DatabaseService) DBDiffQueryResults(left, right *connection.DiffQuerySide, keyColumns []string, maxRows int) *connection.QueryResult {
+	v := validate.New().
+		Check(left != nil, "left", validate.CodeRequired, "left 不能为空").
+		Check(right != nil, "right", validate.CodeRequired, "right 不能为空").
+		Check(len(keyColumns) > 0, "keyColumns", validate.CodeRequired, "keyColumns 不能为空").
+		Identifiers("keyColumns", keyColumns).
+		Range("maxRows", maxRows, 0, maxDiffMaxRows)
+	for _, side := range []struct {
+		name string
+		q    *connection.DiffQuerySide
+	}{{"left", left}, {"right", right}} {
+		if side.q == nil {
+			continue
+		}
+		v.ConnectionConfig(side.name+".config", side.q.Config).
+			OptionalIdentifier(side.name+".dbName", side.q.DBName).
+			Required(side.name+".query", side.q.Query).
+			Check(isCursorQuery(side.q.Query), side.name+".query", validate.CodeNotAllowed, "结果集比较仅支持 SELECT 类查询")
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBDiffQueryResults", err)
+	}
+	if maxRows <= 0 {
+		maxRows = defaultDiffMaxRows
+	}
+
+	leftData, err := a.readQueryInput(context.Background(), "DBDiffQueryResults", left, maxRows)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("左侧查询失败: %v", err)}
+	}
+	rightData, err := a.readQueryInput(context.Background(), "DBDiffQueryResults", right, maxRows)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("右侧查询失败: %v", err)}
+	}
+
+	result, err := resultdiff.Diff(leftData.input, rightData.input, keyColumns, 0)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	message := "比较完成"
+	if leftData.truncated || rightData.truncated {
+		message = fmt.Sprintf("比较完成（结果超过 %d 行，仅比较前 %d 行）", maxRows, maxRows)
+	}
+	return &connection.QueryResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"diff":           result,
+			"leftTruncated":  leftData.truncated,
+			"rightTruncated": rightData.truncated,
+		},
+		Fields: result.Columns,
+	}
+}
+
+// readQueryInput 以流式方式执行一侧查询，最多读取 maxRows 行；op 为日志中的调用方名称。
+func (a *DatabaseService) readQueryInput(ctx context.Context, op string, side *connection.DiffQuerySide, maxRows int) (*diffSideData, error) {
+	runConfig := normalizeRunConfig(side.Config, side.DBName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(op+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, err
+	}
+	streamer, ok := dbInst.(db.RowStreamer)
+	if !ok {
+		return nil, errors.New("当前数据库不支持结果集比较")
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	query := sanitizeSQLForPgLike(runConfig.Type, side.Query)
+	stream, err := streamer.QueryStream(ctx, query, side.Args...)
+	if err != nil {
+		a.Logger().Error(op+" 查询失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
+		return nil, err
+	}
+	defer stream.Close()
+
+	data := &diffSideData{input: resultdiff.Input{Columns: stream.Columns()}}
+	for {
+		row, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			a.Logger().Error(op+" 读取结果失败", "error", err, "snippet", sqlSnippet(query))
+			return nil, err
+		}
+		if len(data.input.Rows) >= maxRows {
+			data.truncated = true
+			break
+		}
+		data.input.Rows = append(data.input.Rows, row)
+	}
+	return data, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/metrics"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// exportPlan 是已校验并选定输出文件的导出任务。
+type exportPlan struct {
+	runConfig   *connection.ConnectionConfig
+	query       string
+	format      string
+	defaultName string
+	filename    string
+	opts        *connection.ExportOptions
+}
+
+// DBExportQuery 按导出参数导出任意查询结果或表数据，支持选列、过滤、行数限制与格式控制。
+func (a *DatabaseService) DBExportQuery(config *connection.ConnectionConfig, dbName string, opts *connection.ExportOptions) *connection.QueryResult {
+	plan, res := a.planExport(config, dbName, opts)
+	if res != nil {
+		return res
+	}
+	rows, err := a.runExport(a.ctx, plan, nil)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("导出成功，共 %d 行", rows),
+		Data:    map[string]interface{}{"file": plan.filename, "rows": rows},
+	}
+}
+
+// DBExportFormats 返回已注册的导出格式及其可选参数（压缩算法、列类型推断），供导出对话框展示。
+func (a *DatabaseService) DBExportFormats() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取导出格式成功", Data: dataexport.Formats()}
+}
+
+// planExport 校验导出参数、编译查询并弹出保存对话框；失败或取消时返回错误结果。
+func (a *DatabaseService) planExport(config *connection.ConnectionConfig, dbName string, opts *connection.ExportOptions) (*exportPlan, *connection.QueryResult) {
+	if opts == nil {
+		return nil, &connection.QueryResult{Success: false, Message: "导出参数不能为空"}
+	}
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	formatOpts := exportFormatOptions("", opts.NullValue, opts.DateFormat, opts.Compression, opts.InferSchema)
+	optsErr := dataexport.CheckOptions(format, formatOpts)
+	if err := validateDatabaseArgs(config, dbName).
+		Check(dataexport.Supported(format), "format", validate.CodeNotAllowed, fmt.Sprintf("不支持的导出格式: %s", opts.Format)).
+		Check(!dataexport.Supported(format) || optsErr == nil, "compression", validate.CodeNotAllowed, fmt.Sprint(optsErr)).
+		OptionalIdentifier("tableName", opts.TableName).
+		Check(opts.TableName != "" || strings.TrimSpace(opts.Query) != "", "query", validate.CodeRequired, "表名与查询语句不能同时为空").
+		Identifiers("columns", opts.Columns).
+		Route("route", opts.Route).
+		Err(); err != nil {
+		return nil, a.invalidArgs("DBExportQuery", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	query, err := dataexport.BuildExportQuery(opts, db.NewSQLBuilder(runConfig.Type))
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	defaultName := strings.TrimSpace(opts.TableName)
+	if defaultName == "" {
+		defaultName = "query_result"
+	}
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           fmt.Sprintf("导出 %s", defaultName),
+		DefaultFilename: fmt.Sprintf("%s.%s", defaultName, dataexport.FileExtension(format, formatOpts)),
+	})
+	if err != nil || filename == "" {
+		return nil, &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+	return &exportPlan{
+		runConfig:   runConfig,
+		query:       query,
+		format:      format,
+		defaultName: defaultName,
+		filename:    filename,
+		opts:        opts,
+	}, nil
+}
+
+// runExport 执行导出查询并写入文件，返回导出的行数；r 非空时按已写出的行数汇报进度。
+func (a *DatabaseService) runExport(ctx context.Context, plan *exportPlan, r jobs.Reporter) (int, error) {
+	// 导出只读取数据，按只读路由选择主机，避免大批量导出压到主库
+	dbInst, err := a.getReadDatabase(plan.runConfig.WithReadRoute(plan.opts.Route))
+	if err != nil {
+		a.Logger().Error("DBExportQuery 获取连接失败", "error", err, "summary", db.FormatConnSummary(plan.runConfig))
+		return 0, err
+	}
+
+	a.Logger().Info("DBExportQuery 开始导出", "format", plan.format, "snippet", sqlSnippet(plan.query), "file", plan.filename)
+	start := time.Now()
+	query := sanitizeSQLForPgLike(plan.runConfig.Type, plan.query)
+	var (
+		data    []map[string]interface{}
+		columns []string
+	)
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, columns, err = q.QueryContext(ctx, query)
+	} else {
+		data, columns, err = dbInst.Query(query)
+	}
+	if err != nil {
+		a.Logger().Error("DBExportQuery 查询失败", "error", err, "snippet", sqlSnippet(plan.query))
+		return 0, err
+	}
+
+	formatOpts := exportFormatOptions(plan.defaultName, plan.opts.NullValue, plan.opts.DateFormat, plan.opts.Compression, plan.opts.InferSchema)
+	if r != nil {
+		total := int64(len(data))
+		r.Report(0, total, "正在写入文件")
+		formatOpts.Progress = func(written int) { r.Report(int64(written), total, "正在写入文件") }
+	}
+	if err := writeExportFile(plan.filename, plan.format, columns, data, formatOpts); err != nil {
+		a.Logger().Error("DBExportQuery 写入文件失败", "file", plan.filename, "error", err)
+		return 0, err
+	}
+
+	recordExportMetrics(plan.filename, len(data), time.Since(start))
+	a.Logger().Info("DBExportQuery 导出完成", "rows", len(data), "file", plan.filename)
+	return len(data), nil
+}
+
+// recordExportMetrics 记录一次导出的行数、文件大小、耗时与吞吐
+func recordExportMetrics(filename string, rows int, elapsed time.Duration) {
+	if !metrics.Enabled() {
+		return
+	}
+	metrics.Add(metrics.ExportTotal, 1)
+	metrics.Add(metrics.ExportRows, int64(rows))
+	if info, err := os.Stat(filename); err == nil {
+		metrics.Add(metrics.ExportBytes, info.Size())
+	}
+	metrics.Observe(metrics.ExportDurationMs, float64(elapsed)/float64(time.Millisecond))
+	if elapsed > 0 {
+		metrics.Default.Histogram(metrics.ExportRowsPerSecond, metrics.ThroughputBuckets).Observe(float64(rows) / elapsed.Seconds())
+	}
+}
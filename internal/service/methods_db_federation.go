@@ -0,0 +1,103 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/federation"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// federatedQueryTimeout 是载入输入并执行本地关联查询的超时，不含读取各输入的时间。
+const federatedQueryTimeout = 5 * time.Minute
+
+// DBFederatedQuery 分别在各连接上执行来源查询，将结果以别名为表名载入内嵌的 SQLite 引擎后执行关联查询，
+// 用于跨系统核对数据而无需手动导出。任一输入超过 maxRows 行时报错而不是截断，避免得到不完整的关联结果。
+func (a *DatabaseService) DBFederatedQuery(req *connection.FederatedQueryRequest) *connection.QueryResult {
+	v := validate.New().Check(req != nil, "req", validate.CodeRequired, "req 不能为空")
+	if req != nil {
+		v.Check(len(req.Sources) > 0, "req.sources", validate.CodeRequired, "至少需要一个输入").
+			Check(len(req.Sources) <= federation.MaxSources, "req.sources", validate.CodeOutOfRange, fmt.Sprintf("输入不能超过 %d 个", federation.MaxSources)).
+			Required("req.query", req.Query).
+			Check(isCursorQuery(req.Query), "req.query", validate.CodeNotAllowed, "关联查询仅支持 SELECT 类查询").
+			Range("req.maxRows", req.MaxRows, 0, federation.MaxRowsLimit).
+			Range("req.maxResultRows", req.MaxResultRows, 0, federation.MaxResultRowsLimit)
+		aliases := make(map[string]bool, len(req.Sources))
+		for i, src := range req.Sources {
+			field := fmt.Sprintf("req.sources[%d]", i)
+			if src == nil {
+				v.Check(false, field, validate.CodeRequired, field+" 不能为空")
+				continue
+			}
+			v.Identifier(field+".alias", src.Alias).
+				Check(!aliases[strings.ToLower(src.Alias)], field+".alias", validate.CodeInvalid, "输入别名重复: "+src.Alias).
+				ConnectionConfig(field+".config", src.Config).
+				OptionalIdentifier(field+".dbName", src.DBName).
+				Required(field+".query", src.Query).
+				Check(isCursorQuery(src.Query), field+".query", validate.CodeNotAllowed, "来源查询仅支持 SELECT 类查询")
+			aliases[strings.ToLower(src.Alias)] = true
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBFederatedQuery", err)
+	}
+	maxRows := req.MaxRows
+	if maxRows <= 0 {
+		maxRows = federation.DefaultMaxRows
+	}
+
+	inputs := make([]*federation.Input, 0, len(req.Sources))
+	for _, src := range req.Sources {
+		data, err := a.readQueryInput(context.Background(), "DBFederatedQuery", &connection.DiffQuerySide{
+			Config: src.Config,
+			DBName: src.DBName,
+			Query:  src.Query,
+			Args:   src.Args,
+		}, maxRows)
+		if err != nil {
+			return &connection.QueryResult{Success: false, Message: fmt.Sprintf("输入 %s 查询失败: %v", src.Alias, err)}
+		}
+		if data.truncated {
+			return &connection.QueryResult{Success: false, Message: fmt.Sprintf("输入 %s 超过 %d 行，请在来源查询中过滤或聚合", src.Alias, maxRows)}
+		}
+		inputs = append(inputs, &federation.Input{Alias: src.Alias, Columns: data.input.Columns, Rows: data.input.Rows})
+	}
+	if err := federation.CheckSize(inputs); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	engine, engineName, cleanup, err := federation.OpenEngine()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), federatedQueryTimeout)
+	defer cancel()
+	result, err := federation.Run(ctx, engine, engineName, inputs, req.Query, req.MaxResultRows)
+	if err != nil {
+		a.Logger().Warn("DBFederatedQuery 执行失败", "error", err, "engine", engineName, "snippet", sqlSnippet(req.Query))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	message := fmt.Sprintf("查询完成，返回 %d 行", len(result.Rows))
+	if result.Truncated {
+		message = fmt.Sprintf("查询完成（结果超过 %d 行，仅返回前 %d 行）", len(result.Rows), len(result.Rows))
+	}
+	return &connection.QueryResult{Success: true, Message: message, Data: result, Fields: result.Columns}
+}
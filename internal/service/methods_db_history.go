@@ -0,0 +1,99 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/metrics"
+	"github.com/chenyang-zz/boxify/internal/queryhistory"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+const (
+	// maxHeatmapDays 热力图最多统计的天数。
+	maxHeatmapDays = 365
+	// maxHourlyHeatmapDays 按小时统计时最多的天数。
+	maxHourlyHeatmapDays = 31
+)
+
+// GetTableUsageHeatmap 根据查询历史统计各表最近 days 天的读写频率；config 为空时统计全部连接。
+func (a *DatabaseService) GetTableUsageHeatmap(config *connection.ConnectionConfig, days int, bucket string, limit int) *connection.QueryResult {
+	maxDays := maxHeatmapDays
+	if bucket == queryhistory.BucketHour {
+		maxDays = maxHourlyHeatmapDays
+	}
+	v := validate.New().Range("days", days, 1, maxDays)
+	if bucket != "" {
+		v.OneOf("bucket", bucket, queryhistory.BucketHour, queryhistory.BucketDay, queryhistory.BucketWeek)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("GetTableUsageHeatmap", err)
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	entries, err := a.queryHistory().Entries(since)
+	if err != nil {
+		a.Logger().Error("GetTableUsageHeatmap 读取查询历史失败", "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	heatmap, err := queryhistory.BuildHeatmap(entries, queryhistory.HeatmapOptions{
+		Connection: queryhistory.ConnectionKey(config),
+		Since:      since,
+		Bucket:     bucket,
+		Limit:      limit,
+	})
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取表使用热力图成功", Data: heatmap}
+}
+
+// recordQueryHistory 记录一次语句执行，失败只记录日志不影响查询结果。
+func (a *DatabaseService) recordQueryHistory(config *connection.ConnectionConfig, dbName, query string, success bool, start time.Time) {
+	metrics.Add(metrics.QueryTotal, 1)
+	if !success {
+		metrics.Add(metrics.QueryErrors, 1)
+	}
+	metrics.ObserveSince(metrics.QueryDurationMs, start)
+	database := dbName
+	if database == "" && a.manager != nil {
+		database = a.manager.ActiveSchema(config)
+	}
+	if database == "" {
+		database = config.Database
+	}
+	err := a.queryHistory().Record(queryhistory.Entry{
+		Time:       start,
+		Connection: queryhistory.ConnectionKey(config),
+		Database:   database,
+		Query:      query,
+		Success:    success,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		a.Logger().Warn("记录查询历史失败", "error", err)
+	}
+}
+
+// queryHistory 返回查询历史存储，未初始化时使用默认路径。
+func (a *DatabaseService) queryHistory() *queryhistory.Store {
+	if a.history == nil {
+		a.history = queryhistory.NewStore("", 0, a.Logger())
+	}
+	return a.history
+}
@@ -0,0 +1,371 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataimport"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// importPreviewSampleRows 导入预览返回的样例行数。
+	importPreviewSampleRows = 20
+	// maxImportPreviews 保留的导入预览数量，超出时淘汰最早的预览。
+	maxImportPreviews = 16
+)
+
+// importPreviewStore 记录预览 ID 对应的导入文件，执行导入时只接受预览过的文件，避免前端传入任意路径。
+type importPreviewStore struct {
+	mu    sync.Mutex
+	order []string
+	files map[string]string // previewID -> 文件路径
+}
+
+// put 保存预览文件并返回预览 ID。
+func (s *importPreviewStore) put(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		s.files = make(map[string]string)
+	}
+	id := uuid.New().String()
+	s.files[id] = path
+	s.order = append(s.order, id)
+	if len(s.order) > maxImportPreviews {
+		delete(s.files, s.order[0])
+		s.order = s.order[1:]
+	}
+	return id
+}
+
+// get 获取预览 ID 对应的文件路径。
+func (s *importPreviewStore) get(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, ok := s.files[id]
+	return path, ok
+}
+
+// ImportData 选择 CSV/JSON/Excel/Parquet 文件并在事务内批量导入到目标表，任一行失败则整体回滚。
+func (a *DatabaseService) ImportData(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	return a.ImportDataWithOptions(config, dbName, tableName, nil)
+}
+
+// ImportDataWithOptions 按导入参数（批大小、试运行、跳过失败行）批量导入文件（Excel 读取第一个工作表），Data 返回导入报告。
+func (a *DatabaseService) ImportDataWithOptions(config *connection.ConnectionConfig, dbName, tableName string, opts *connection.ImportOptions) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("ImportData", err)
+	}
+
+	selection, err := selectImportDataFile(a.ctx, tableName)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if selection == "" {
+		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+
+	table, err := dataimport.ParseFile(selection, "")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return a.runImport(a.ctx, config, dbName, tableName, table.Rows, nil, opts, selection, nil)
+}
+
+// DBImportPreview 选择导入文件并返回表头、样例行、目标表列定义与推荐的列映射。
+func (a *DatabaseService) DBImportPreview(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBImportPreview", err)
+	}
+
+	selection, err := selectImportDataFile(a.ctx, tableName)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if selection == "" {
+		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+	return a.buildImportPreview(config, dbName, tableName, a.importPreviews.put(selection), selection, "")
+}
+
+// DBImportPreviewSheet 切换 Excel 工作表后重新预览已选择的文件。
+func (a *DatabaseService) DBImportPreviewSheet(config *connection.ConnectionConfig, dbName, tableName, previewID, sheet string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Required("previewId", previewID).Err(); err != nil {
+		return a.invalidArgs("DBImportPreviewSheet", err)
+	}
+	selection, ok := a.importPreviews.get(previewID)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "导入预览已失效，请重新选择文件"}
+	}
+	return a.buildImportPreview(config, dbName, tableName, previewID, selection, sheet)
+}
+
+// buildImportPreview 解析文件并组装预览结果。
+func (a *DatabaseService) buildImportPreview(config *connection.ConnectionConfig, dbName, tableName, previewID, selection, sheet string) *connection.QueryResult {
+	table, err := dataimport.ParseFile(selection, sheet)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defs, err := getTableColumns(a.Context(), dbInst, runConfig, dbName, tableName)
+	if err != nil {
+		a.Logger().Error("DBImportPreview 获取列定义失败", "table", tableName, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	preview := &connection.ImportPreview{
+		PreviewID:        previewID,
+		FileName:         filepath.Base(selection),
+		Format:           table.Format,
+		Sheet:            table.Sheet,
+		Headers:          table.Headers,
+		SampleRows:       table.Rows[:min(len(table.Rows), importPreviewSampleRows)],
+		TotalRows:        len(table.Rows),
+		TableColumns:     defs,
+		SuggestedMapping: dataimport.SuggestMapping(table.Headers, defs),
+	}
+	if table.Format == "xlsx" {
+		if preview.Sheets, err = dataimport.ListSheets(selection); err != nil {
+			return &connection.QueryResult{Success: false, Message: err.Error()}
+		}
+	}
+	return &connection.QueryResult{Success: true, Message: "预览成功", Data: preview, Fields: table.Headers}
+}
+
+// DBImportExecute 按列映射与列转换规则导入预览过的文件，失败行按 Options.SkipFailedRows 跳过或整体中止。
+func (a *DatabaseService) DBImportExecute(config *connection.ConnectionConfig, dbName, tableName string, req *connection.ImportExecuteRequest) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName).Check(req != nil, "request", validate.CodeRequired, "导入请求不能为空")
+	if req != nil {
+		v.Required("previewId", req.PreviewID)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBImportExecute", err)
+	}
+	return a.importExecute(a.ctx, config, dbName, tableName, req, nil)
+}
+
+// importExecute 解析预览过的文件并导入，ctx 取消时中止插入；参数需已校验，r 可为 nil。
+func (a *DatabaseService) importExecute(ctx context.Context, config *connection.ConnectionConfig, dbName, tableName string, req *connection.ImportExecuteRequest, r jobs.Reporter) *connection.QueryResult {
+	selection, ok := a.importPreviews.get(req.PreviewID)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "导入预览已失效，请重新选择文件"}
+	}
+	table, err := dataimport.ParseFile(selection, req.Sheet)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defs, err := getTableColumns(ctx, dbInst, runConfig, dbName, tableName)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if err := dataimport.ValidateMapping(req.Mapping, table.Headers, defs); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	rows := dataimport.ApplyMapping(table.Rows, req.Mapping)
+	return a.runImport(ctx, config, dbName, tableName, rows, req.Rules, req.Options, selection, r)
+}
+
+// runImport 按目标表列类型（及可选的列规则）解析字段，并在事务内批量插入，Data 返回导入报告。
+// 驱动不支持事务时不能试运行（试运行依赖回滚），失败时已插入的行不会回滚。r 非空时每批插入后按已处理行数汇报进度。
+func (a *DatabaseService) runImport(ctx context.Context, config *connection.ConnectionConfig, dbName, tableName string, rows []map[string]interface{}, rules map[string]*connection.ImportColumnRule, opts *connection.ImportOptions, source string, r jobs.Reporter) *connection.QueryResult {
+	if opts == nil {
+		opts = &connection.ImportOptions{}
+	}
+	if len(rows) == 0 {
+		return &connection.QueryResult{Success: true, Message: "没有数据可导入"}
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	inserter, ok := dbInst.(db.BatchInserter)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持批量导入"}
+	}
+	transactional := db.Capabilities(runConfig).SupportsTransactions
+	if opts.DryRun && !transactional {
+		return &connection.QueryResult{Success: false, Message: "当前驱动不支持事务，无法试运行导入"}
+	}
+
+	// 按目标表列类型解析字段，单元格错误精确到行列
+	total := len(rows)
+	rowNumbers := make([]int, total)
+	for i := range rowNumbers {
+		rowNumbers[i] = i + 1
+	}
+	var cellErrs []*connection.ImportRowError
+	if defs, err := getTableColumns(ctx, dbInst, runConfig, dbName, tableName); err != nil {
+		a.Logger().Warn("ImportData 获取列定义失败，按原始文本导入", "table", tableName, "error", err)
+	} else if len(defs) > 0 {
+		coercer := dataimport.NewCoercer(defs)
+		coercer.SetRules(rules)
+		if unknown := coercer.UnknownColumns(rows); len(unknown) > 0 {
+			return &connection.QueryResult{Success: false, Message: fmt.Sprintf("表 %s 中不存在列: %s", tableName, strings.Join(unknown, ", "))}
+		}
+		rows, rowNumbers, cellErrs = coercer.CoerceRows(rows)
+	}
+
+	report := &connection.ImportReport{DryRun: opts.DryRun, Errors: make([]*connection.ImportRowError, 0)}
+	if len(rows) > 0 && (len(cellErrs) == 0 || opts.SkipFailedRows || opts.DryRun) {
+		columns, values := importRowValues(rows)
+		var progress db.InsertProgressFunc
+		if r != nil {
+			progress = func(done, total int) { r.Report(int64(done), int64(total), "正在导入") }
+		}
+		start := time.Now()
+		report, err = inserter.InsertRows(ctx, tableName, columns, values, opts, progress)
+		if !opts.DryRun {
+			entry := newAuditEntry(audit.FeatureImportData, runConfig, dbName, start, err)
+			entry.Table = tableName
+			entry.SQL = fmt.Sprintf("IMPORT %d ROWS FROM %s", len(values), source)
+			if err == nil && report.Committed {
+				entry.AffectedRows = int64(report.Inserted)
+			} else if err == nil {
+				entry.AffectedRows, entry.Success, entry.Error = 0, false, "导入失败，事务已回滚"
+			}
+			a.Audit(entry)
+		}
+		if err != nil {
+			a.Logger().Error("ImportData 导入失败", "table", tableName, "file", source, "error", err)
+			return &connection.QueryResult{Success: false, Message: rollbackNote(transactional, err)}
+		}
+	}
+	mergeImportReport(report, total, rowNumbers, cellErrs, opts.MaxErrors)
+
+	a.Logger().Info("ImportData 导入结束", "table", tableName, "total", report.Total, "inserted", report.Inserted,
+		"failed", report.Failed, "committed", report.Committed, "dryRun", report.DryRun, "durationMs", report.DurationMs)
+	return &connection.QueryResult{Success: report.Committed || report.DryRun, Message: importReportMessage(report), Data: report}
+}
+
+// selectImportDataFile 弹出导入文件选择窗口。
+func selectImportDataFile(ctx context.Context, tableName string) (string, error) {
+	return runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
+		Title: fmt.Sprintf("Import into %s", tableName),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Data Files", Pattern: "*.csv;*.json;*.xlsx;*.parquet"},
+		},
+	})
+}
+
+// importRowValues 汇总所有行出现过的列（按列名排序），并将行数据转换为按列对齐的参数列表，缺失列填 NULL。
+func importRowValues(rows []map[string]interface{}) ([]string, [][]any) {
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	for _, row := range rows {
+		for col := range row {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	values := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		vals := make([]any, len(columns))
+		for i, col := range columns {
+			vals[i] = importArg(row[col])
+		}
+		values = append(values, vals)
+	}
+	return columns, values
+}
+
+// importArg 将 JSON 数字按原文、嵌套对象/数组序列化为字符串传参，其余值原样作为参数。
+func importArg(val interface{}) any {
+	switch v := val.(type) {
+	case json.Number:
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	default:
+		return v
+	}
+}
+
+// mergeImportReport 将插入报告中的行号映射回原始数据行号，并合并字段解析阶段的单元格错误。
+func mergeImportReport(report *connection.ImportReport, total int, rowNumbers []int, cellErrs []*connection.ImportRowError, maxErrors int) {
+	if maxErrors <= 0 {
+		maxErrors = db.DefaultImportMaxErrors
+	}
+	for _, e := range report.Errors {
+		if e.Row >= 1 && e.Row <= len(rowNumbers) {
+			e.Row = rowNumbers[e.Row-1]
+		}
+	}
+
+	failedRows := make(map[int]bool)
+	for _, e := range cellErrs {
+		failedRows[e.Row] = true
+	}
+
+	errs := make([]*connection.ImportRowError, 0, len(cellErrs)+len(report.Errors))
+	errs = append(errs, cellErrs...)
+	errs = append(errs, report.Errors...)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+	if len(errs) > maxErrors {
+		errs = errs[:maxErrors]
+	}
+	report.Errors = errs
+	report.Failed += len(failedRows)
+	report.Total = total
+}
+
+// importReportMessage 根据导入报告生成提示信息。
+func importReportMessage(report *connection.ImportReport) string {
+	switch {
+	case report.DryRun:
+		return fmt.Sprintf("试运行完成，可导入: %d, 失败: %d（未写入数据）", report.Inserted, report.Failed)
+	case report.Committed:
+		return fmt.Sprintf("导入完成，成功: %d, 失败: %d", report.Inserted, report.Failed)
+	default:
+		return fmt.Sprintf("导入失败，%d 行出错，未写入任何数据", report.Failed)
+	}
+}
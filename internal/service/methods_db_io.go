@@ -16,25 +16,19 @@ package service
 
 import (
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/chenyang-zz/boxify/internal/audit"
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
 	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// exportWriterContext 封装导出场景中的写入器状态。
-type exportWriterContext struct {
-	format         string
-	csvWriter      *csv.Writer
-	jsonEncoder    *json.Encoder
-	isJSONFirstRow bool
-}
-
 // OpenSQLFile 选择 SQL 文件并返回内容。
 func (a *DatabaseService) OpenSQLFile() *connection.QueryResult {
 	selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
@@ -58,63 +52,178 @@ func (a *DatabaseService) OpenSQLFile() *connection.QueryResult {
 	return &connection.QueryResult{Success: true, Message: "SQL文件加载成功", Data: string(content)}
 }
 
-// ImportData 选择 CSV/JSON 文件并导入到目标表。
-func (a *DatabaseService) ImportData(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
-	selection, err := selectImportDataFile(a.ctx, tableName)
+// ApplyChanges 将更改集应用到数据库表中，任一变更失败时整体回滚。
+func (a *DatabaseService) ApplyChanges(config *connection.ConnectionConfig, dbName, tableName string, changes *connection.ChangeSet) *connection.QueryResult {
+	return a.ApplyChangesWithOptions(config, dbName, tableName, changes, nil)
+}
+
+// ApplyChangesWithOptions 按应用参数将更改集应用到数据库表中。
+// PartialCommit 时每个变更由保存点保护，成功的变更照常提交，失败项在 Data.report 中按类型与下标返回。
+// 驱动不支持事务时不能部分提交，也不记录撤销（原行需在同一事务内读取），失败时出错前的变更不会回滚。
+func (a *DatabaseService) ApplyChangesWithOptions(config *connection.ConnectionConfig, dbName, tableName string, changes *connection.ChangeSet, opts *connection.ApplyOptions) *connection.QueryResult {
+	if opts == nil {
+		opts = &connection.ApplyOptions{}
+	}
+	if err := validateTableArgs(config, dbName, tableName).
+		Check(changes != nil, "changes", validate.CodeRequired, "changes 不能为空").Err(); err != nil {
+		return a.invalidArgs("ApplyChanges", err)
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	if selection == "" {
-		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+
+	applier, ok := dbInst.(db.BatchApplier)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持批量更改"}
+	}
+	partial, ok := dbInst.(db.PartialApplier)
+	if opts.PartialCommit && !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持部分提交"}
+	}
+	transactional := db.Capabilities(runConfig).SupportsTransactions
+	if opts.PartialCommit && !transactional {
+		return &connection.QueryResult{Success: false, Message: "当前驱动不支持事务，无法部分提交"}
 	}
 
-	rows, err := parseImportRows(selection)
+	key, columns, err := resolveTableKey(a.Context(), dbInst, runConfig, dbName, tableName)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	normalized, warning, err := db.NormalizeChangeSet(key, columns, changes)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	if len(rows) == 0 {
-		return &connection.QueryResult{Success: true, Message: "没有数据可导入"}
+	if warning != "" {
+		a.Logger().Warn("ApplyChanges 表无可用键，按全列匹配", "table", tableName)
 	}
 
-	runConfig := cloneConfigWithDatabase(config, dbName)
-	dbInst, err := a.getDatabase(runConfig)
+	// 撤销所需的原行在应用更改的同一事务内读取，保留驱动原始值（二进制列不经展示格式转换）
+	capture := a.newUndoCapture(key, columns, normalized)
+	undoApplier, canCapture := dbInst.(db.UndoApplier)
+	if capture != nil && (!canCapture || !transactional) {
+		capture = nil
+	}
+
+	total := len(normalized.Inserts) + len(normalized.Updates) + len(normalized.Deletes)
+	report := &connection.ApplyReport{Total: total, Applied: total, Committed: true, Errors: []*connection.ApplyChangeError{}}
+	start := time.Now()
+	switch {
+	case capture != nil:
+		var images *db.ChangeImages
+		report, images, err = undoApplier.ApplyChangesWithImages(context.Background(), tableName, normalized, opts.PartialCommit)
+		if err == nil {
+			if undoErr := capture.setImages(images); undoErr != nil {
+				a.Logger().Warn("ApplyChanges 读取原行失败，本次更改不可撤销", "table", tableName, "error", undoErr)
+				capture = nil
+			}
+		}
+	case opts.PartialCommit:
+		report, err = partial.ApplyChangesPartial(context.Background(), tableName, normalized)
+	default:
+		err = applier.ApplyChanges(tableName, normalized)
+	}
+	entry := newAuditEntry(audit.FeatureApplyChanges, runConfig, dbName, start, err)
+	entry.Table = tableName
+	entry.SQL = changeSetSummary(normalized)
+	if err == nil {
+		entry.AffectedRows = int64(report.Applied)
+		if report.Failed > 0 {
+			entry.Error = fmt.Sprintf("部分提交：%d 个变更失败", report.Failed)
+		}
+	}
+	a.Audit(entry)
 	if err != nil {
-		return &connection.QueryResult{Success: false, Message: err.Error()}
+		return &connection.QueryResult{Success: false, Message: rollbackNote(transactional, err)}
+	}
+
+	message := "批量更改应用成功"
+	if report.Failed > 0 {
+		message = fmt.Sprintf("已应用 %d 个变更，%d 个失败", report.Applied, report.Failed)
+	}
+	if warning != "" {
+		message += "（" + warning + "）"
+	}
+	data := map[string]interface{}{"key": key, "warning": warning, "report": report}
+	if capture != nil && report.Applied > 0 {
+		undo, err := capture.entry(runConfig, tableName, report.Errors)
+		if err != nil {
+			a.Logger().Warn("ApplyChanges 生成撤销记录失败", "table", tableName, "error", err)
+		} else if undo != nil {
+			undo.AppliedAt = start
+			a.changeUndo.push(undo)
+			data["undo"] = undo
+		}
 	}
+	return &connection.QueryResult{Success: report.Applied > 0 || report.Failed == 0, Message: message, Data: data}
+}
 
-	successCount, errCount := applyImportRows(dbInst, runConfig.Type, tableName, rows)
-	return &connection.QueryResult{Success: true, Message: fmt.Sprintf("导入完成，成功: %d, 失败: %d", successCount, errCount)}
+// changeSetSummary 生成变更集的审计摘要：各类操作行数及变更内容。
+func changeSetSummary(changes *connection.ChangeSet) string {
+	summary := fmt.Sprintf("INSERT %d, UPDATE %d, DELETE %d", len(changes.Inserts), len(changes.Updates), len(changes.Deletes))
+	if detail, err := json.Marshal(changes); err == nil {
+		summary += "\n" + string(detail)
+	}
+	return summary
 }
 
-// ApplyChanges 将更改集应用到数据库表中。
-func (a *DatabaseService) ApplyChanges(config *connection.ConnectionConfig, dbName, tableName string, changes *connection.ChangeSet) *connection.QueryResult {
+// DBGetTableKeys 获取定位行所用的键：主键，或列数最少且非空的唯一索引；均不存在时 kind 为 none。
+func (a *DatabaseService) DBGetTableKeys(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetTableKeys", err)
+	}
+
 	runConfig := cloneConfigWithDatabase(config, dbName)
 	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
+	key, _, err := resolveTableKey(a.Context(), dbInst, runConfig, dbName, tableName)
+	if err != nil {
+		a.Logger().Error("DBGetTableKeys 获取键信息失败", "table", tableName, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取表键信息成功", Data: key, Fields: key.Columns}
+}
 
-	if applier, ok := dbInst.(db.BatchApplier); ok {
-		if err := applier.ApplyChanges(tableName, changes); err != nil {
-			return &connection.QueryResult{Success: false, Message: err.Error()}
-		}
-		return &connection.QueryResult{Success: true, Message: "批量更改应用成功"}
+// resolveTableKey 在元数据超时内读取表的列与索引定义并选出定位行所用的键。
+func resolveTableKey(ctx context.Context, dbInst db.Database, config *connection.ConnectionConfig, dbName, tableName string) (*connection.TableKey, []*connection.ColumnDefinition, error) {
+	ctx, cancel := metadataContext(ctx, config)
+	defer cancel()
+	dbName, tableName = splitTableName(dbName, tableName)
+	columns, err := dbInst.GetColumns(ctx, dbName, tableName)
+	if err != nil {
+		return nil, nil, err
 	}
-	return &connection.QueryResult{Success: false, Message: "数据库不支持批量更改"}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("表不存在或没有列: %s", tableName)
+	}
+	indexes, err := dbInst.GetIndexes(ctx, dbName, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.ResolveTableKey(indexes, columns), columns, nil
 }
 
-// ExportTable 导出表数据到 CSV、JSON 或 Markdown 文件。
+// ExportTable 按默认格式参数导出表数据，format 为任一已注册的导出格式。
 func (a *DatabaseService) ExportTable(config *connection.ConnectionConfig, dbName, tableName string, format string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).
+		Check(dataexport.Supported(format), "format", validate.CodeNotAllowed, fmt.Sprintf("不支持的导出格式: %s", format)).Err(); err != nil {
+		return a.invalidArgs("ExportTable", err)
+	}
+
 	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
 		Title:           fmt.Sprintf("导出 %s", tableName),
-		DefaultFilename: fmt.Sprintf("%s.%s", tableName, format),
+		DefaultFilename: fmt.Sprintf("%s.%s", tableName, dataexport.FileExtension(format, nil)),
 	})
 	if err != nil || filename == "" {
 		return &connection.QueryResult{Success: false, Message: "Cancelled"}
 	}
 
 	runConfig := cloneConfigWithDatabase(config, dbName)
-	dbInst, err := a.getDatabase(runConfig)
+	dbInst, err := a.getReadDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
@@ -125,27 +234,57 @@ func (a *DatabaseService) ExportTable(config *connection.ConnectionConfig, dbNam
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	f, err := os.Create(filename)
-	if err != nil {
+	if err := writeExportFile(filename, format, columns, data, exportFormatOptions(tableName, nil, "", "", nil)); err != nil {
+		a.Logger().Error("ExportTable 写入文件失败", "table", tableName, "file", filename, "error", err)
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	defer f.Close()
 
-	writerCtx, err := initExportWriter(f, strings.ToLower(format), columns)
+	return &connection.QueryResult{Success: true, Message: "导出成功"}
+}
+
+// ExportTables 将多张表导出到同一个 Excel 工作簿，每张表一个工作表。
+func (a *DatabaseService) ExportTables(config *connection.ConnectionConfig, dbName string, tableNames []string) *connection.QueryResult {
+	if len(tableNames) == 0 {
+		return &connection.QueryResult{Success: false, Message: "未选择要导出的表"}
+	}
+	if err := validateDatabaseArgs(config, dbName).Identifiers("tableNames", tableNames).Err(); err != nil {
+		return a.invalidArgs("ExportTables", err)
+	}
+
+	defaultName := tableNames[0]
+	if len(tableNames) > 1 && dbName != "" {
+		defaultName = dbName
+	}
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           fmt.Sprintf("导出 %d 张表", len(tableNames)),
+		DefaultFilename: fmt.Sprintf("%s.xlsx", defaultName),
+	})
+	if err != nil || filename == "" {
+		return &connection.QueryResult{Success: false, Message: "Cancelled"}
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	dbInst, err := a.getReadDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	if writerCtx.csvWriter != nil {
-		defer writerCtx.csvWriter.Flush()
+
+	sheets := make([]xlsxSheetData, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		data, columns, err := dbInst.Query(buildExportSelectQuery(runConfig.Type, tableName))
+		if err != nil {
+			a.Logger().Error("ExportTables 查询表数据失败", "table", tableName, "error", err)
+			return &connection.QueryResult{Success: false, Message: fmt.Sprintf("查询表 %s 失败：%s", tableName, err.Error())}
+		}
+		sheets = append(sheets, xlsxSheetData{name: tableName, columns: columns, rows: data})
 	}
 
-	if err := writeExportRows(f, writerCtx, columns, data); err != nil {
+	if err := writeXLSXWorkbook(filename, sheets); err != nil {
+		a.Logger().Error("ExportTables 写入 Excel 失败", "file", filename, "error", err)
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
-	if writerCtx.format == "json" {
-		f.WriteString("]\n")
-	}
 
+	a.Logger().Info("ExportTables 导出完成", "tables", len(tableNames), "file", filename)
 	return &connection.QueryResult{Success: true, Message: "导出成功"}
 }
 
@@ -156,223 +295,72 @@ func (a *DatabaseService) TypeOnly_ColumnDefinition() *connection.ColumnDefiniti
 
 // cloneConfigWithDatabase 复制连接配置并按需覆盖数据库名。
 func cloneConfigWithDatabase(config *connection.ConnectionConfig, dbName string) *connection.ConnectionConfig {
-	runConfig := *config
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
-	return &runConfig
-}
-
-// selectImportDataFile 弹出导入文件选择窗口。
-func selectImportDataFile(ctx context.Context, tableName string) (string, error) {
-	return runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
-		Title: fmt.Sprintf("Import into %s", tableName),
-		Filters: []runtime.FileFilter{
-			{DisplayName: "Data Files", Pattern: "*csv;*.json"},
-		},
-	})
-}
-
-// parseImportRows 从 CSV/JSON 文件解析出待导入数据行。
-func parseImportRows(selection string) ([]map[string]interface{}, error) {
-	f, err := os.Open(selection)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	if strings.HasSuffix(strings.ToLower(selection), ".json") {
-		var rows []map[string]interface{}
-		decoder := json.NewDecoder(f)
-		if err := decoder.Decode(&rows); err != nil {
-			return nil, fmt.Errorf("Failed to parse JSON: %v", err)
-		}
-		return rows, nil
-	}
-
-	if strings.HasSuffix(strings.ToLower(selection), ".csv") {
-		return parseCSVRows(f)
-	}
-
-	return nil, fmt.Errorf("不支持的文件类型")
-}
-
-// parseCSVRows 将 CSV 内容转换为行对象。
-func parseCSVRows(f *os.File) ([]map[string]interface{}, error) {
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to parse CSV: %v", err)
-	}
-	if len(records) < 2 {
-		return nil, fmt.Errorf("CSV是空的或没有头行")
-	}
-
-	headers := records[0]
-	rows := make([]map[string]interface{}, 0, len(records)-1)
-	for _, record := range records[1:] {
-		row := make(map[string]interface{})
-		for i, val := range record {
-			if i >= len(headers) {
-				continue
-			}
-			if val == "NULL" {
-				row[headers[i]] = nil
-			} else {
-				row[headers[i]] = val
-			}
-		}
-		rows = append(rows, row)
-	}
-
-	return rows, nil
+	return runConfig
 }
 
-// applyImportRows 执行逐行导入并返回成功/失败统计。
-func applyImportRows(dbInst db.Database, dbType connection.ConnectionType, tableName string, rows []map[string]interface{}) (int, int) {
-	successCount := 0
-	errCount := 0
-	cols := extractColumnOrder(rows[0])
-
-	for _, row := range rows {
-		query := buildImportInsertQuery(dbType, tableName, cols, row)
-		if _, err := dbInst.Exec(query); err != nil {
-			errCount++
-			fmt.Printf("导入错误: %v\n", err)
-		} else {
-			successCount++
-		}
+// rollbackNote 返回错误信息；驱动不支持事务时补充说明出错前已执行的语句不会回滚。
+func rollbackNote(transactional bool, err error) string {
+	if transactional {
+		return err.Error()
 	}
-
-	return successCount, errCount
+	return err.Error() + "（当前驱动不支持事务，出错前已执行的变更未回滚）"
 }
 
-// extractColumnOrder 从首行提取列顺序。
-func extractColumnOrder(firstRow map[string]interface{}) []string {
-	cols := make([]string, 0, len(firstRow))
-	for k := range firstRow {
-		cols = append(cols, k)
-	}
-	return cols
+// xlsxSheetData 描述写入 Excel 的单个工作表数据。
+type xlsxSheetData struct {
+	name    string
+	columns []string
+	rows    []map[string]interface{}
 }
 
-// buildImportInsertQuery 按数据库类型构造插入 SQL。
-func buildImportInsertQuery(dbType connection.ConnectionType, tableName string, cols []string, row map[string]interface{}) string {
-	values := buildImportValueTokens(cols, row)
-	query := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)", tableName, strings.Join(cols, ", "), strings.Join(values, ", "))
-
-	if dbType == "postgres" {
-		pgCols := make([]string, len(cols))
-		for i, c := range cols {
-			pgCols[i] = fmt.Sprintf(`"%s"`, c)
-		}
-		query = fmt.Sprintf(`INSERT INTO "%s" (%s) VALUES (%s)`, tableName, strings.Join(pgCols, ", "), strings.Join(values, ", "))
+// writeXLSXWorkbook 将一个或多个结果集写入 Excel 工作簿。
+func writeXLSXWorkbook(filename string, sheets []xlsxSheetData) error {
+	wb, err := dataexport.NewXLSXWorkbook()
+	if err != nil {
+		return err
 	}
+	defer wb.Close()
 
-	return query
-}
-
-// buildImportValueTokens 将行数据转换为 SQL values token 列表。
-func buildImportValueTokens(cols []string, row map[string]interface{}) []string {
-	values := make([]string, 0, len(cols))
-	for _, col := range cols {
-		val := row[col]
-		if val == nil {
-			values = append(values, "NULL")
-			continue
+	for _, sheet := range sheets {
+		if _, err := wb.AddSheet(sheet.name, sheet.columns, sheet.rows); err != nil {
+			return err
 		}
-		vStr := fmt.Sprintf("%v", val)
-		vStr = strings.ReplaceAll(vStr, "'", "''")
-		values = append(values, fmt.Sprintf("'%s'", vStr))
 	}
-	return values
+	return wb.SaveAs(filename)
 }
 
-// buildExportSelectQuery 构造导出使用的查询语句。
+// buildExportSelectQuery 构造导出使用的查询语句，表名可带 schema 前缀。
 func buildExportSelectQuery(dbType connection.ConnectionType, tableName string) string {
-	if dbType == "postgres" {
-		return fmt.Sprintf(`SELECT * FROM "%s"`, tableName)
-	}
-	return fmt.Sprintf("SELECT * FROM `%s`", tableName)
+	return "SELECT * FROM " + db.QuoteTable(dbType, tableName)
 }
 
-// initExportWriter 初始化导出写入器并写入头信息。
-func initExportWriter(f *os.File, format string, columns []string) (*exportWriterContext, error) {
-	ctx := &exportWriterContext{format: format, isJSONFirstRow: true}
-
-	switch format {
-	case "csv", "xlsx":
-		f.Write([]byte{0xEF, 0xBB, 0xBF})
-		ctx.csvWriter = csv.NewWriter(f)
-		if err := ctx.csvWriter.Write(columns); err != nil {
-			return nil, err
-		}
-	case "json":
-		f.WriteString("[\n")
-		ctx.jsonEncoder = json.NewEncoder(f)
-		ctx.jsonEncoder.SetIndent("  ", "  ")
-	case "md":
-		fmt.Fprintf(f, "| %s |\n", strings.Join(columns, " | "))
-		seps := make([]string, len(columns))
-		for i := range seps {
-			seps[i] = "---"
-		}
-		fmt.Fprintf(f, "| %s |\n", strings.Join(seps, " | "))
-	default:
-		return nil, fmt.Errorf("不支持的导出格式")
+// writeExportFile 按注册的导出格式将结果集写入文件。
+func writeExportFile(filename, format string, columns []string, data []map[string]interface{}, opts *dataexport.Options) error {
+	exporter, ok := dataexport.Lookup(format)
+	if !ok {
+		return fmt.Errorf("不支持的导出格式: %s", format)
 	}
-
-	return ctx, nil
-}
-
-// writeExportRows 逐行写入导出结果。
-func writeExportRows(f *os.File, writerCtx *exportWriterContext, columns []string, data []map[string]interface{}) error {
-	for _, rowMap := range data {
-		record := buildExportRecord(columns, rowMap, writerCtx.format)
-		if err := writeExportRow(f, writerCtx, record, rowMap); err != nil {
-			return err
-		}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
 	}
-	return nil
-}
-
-// buildExportRecord 按导出格式将单行转为文本字段。
-func buildExportRecord(columns []string, rowMap map[string]interface{}, format string) []string {
-	record := make([]string, len(columns))
-	for i, col := range columns {
-		val := rowMap[col]
-		if val == nil {
-			record[i] = "NULL"
-			continue
-		}
-		s := fmt.Sprintf("%v", val)
-		if format == "md" {
-			s = strings.ReplaceAll(s, "|", "\\|")
-			s = strings.ReplaceAll(s, "\n", "<br>")
-		}
-		record[i] = s
+	if err := exporter.Write(f, columns, data, opts); err != nil {
+		f.Close()
+		return err
 	}
-	return record
+	return f.Close()
 }
 
-// writeExportRow 根据目标格式写入一行数据。
-func writeExportRow(f *os.File, writerCtx *exportWriterContext, record []string, rowMap map[string]interface{}) error {
-	switch writerCtx.format {
-	case "csv", "xlsx":
-		return writerCtx.csvWriter.Write(record)
-	case "json":
-		if !writerCtx.isJSONFirstRow {
-			f.WriteString(",\n")
-		}
-		if err := writerCtx.jsonEncoder.Encode(rowMap); err != nil {
-			return err
-		}
-		writerCtx.isJSONFirstRow = false
-		return nil
-	case "md":
-		_, err := fmt.Fprintf(f, "| %s |\n", strings.Join(record, " | "))
-		return err
-	default:
-		return fmt.Errorf("不支持的导出格式")
+// exportFormatOptions 构造导出格式参数；inferSchema 未设置时默认推断列类型。
+func exportFormatOptions(sheetName string, nullValue *string, dateFormat, compression string, inferSchema *bool) *dataexport.Options {
+	return &dataexport.Options{
+		Formatter:   dataexport.NewValueFormatter(nullValue, dateFormat),
+		SheetName:   sheetName,
+		Compression: compression,
+		InferSchema: inferSchema == nil || *inferSchema,
 	}
 }
@@ -0,0 +1,152 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/notify"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// 数据库服务提交的后台任务类型
+const (
+	jobKindExport     = "export"
+	jobKindImport     = "import"
+	jobKindSchemaScan = "schema-scan"
+	jobKindDBSnapshot = "db-snapshot"
+	jobKindDBRestore  = "db-restore"
+	jobKindRetention  = "retention"
+)
+
+// DBStartExportJob 与 DBExportQuery 相同，但在选定文件后转入后台任务执行，立即返回任务快照；写入文件时按行汇报进度。
+func (a *DatabaseService) DBStartExportJob(config *connection.ConnectionConfig, dbName string, opts *connection.ExportOptions) *connection.QueryResult {
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	plan, res := a.planExport(config, dbName, opts)
+	if res != nil {
+		return res
+	}
+	job := m.Submit(jobKindExport, fmt.Sprintf("导出 %s", plan.defaultName), func(ctx context.Context, r jobs.Reporter) (any, error) {
+		r.Report(0, 0, "正在查询")
+		rows, err := a.runExport(ctx, plan, r)
+		a.notifyJobResult(ctx, notify.CategoryExport, "导出 "+plan.defaultName, fmt.Sprintf("已导出 %d 行到 %s", rows, plan.filename), err)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"file": plan.filename, "rows": rows}, nil
+	})
+	return &connection.QueryResult{Success: true, Message: "导出任务已启动", Data: job}
+}
+
+// DBStartImportJob 与 DBImportExecute 相同，但在后台任务中解析文件并导入，立即返回任务快照；每批插入后按行汇报进度。
+func (a *DatabaseService) DBStartImportJob(config *connection.ConnectionConfig, dbName, tableName string, req *connection.ImportExecuteRequest) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName).Check(req != nil, "request", validate.CodeRequired, "导入请求不能为空")
+	if req != nil {
+		v.Required("previewId", req.PreviewID)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBStartImportJob", err)
+	}
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	job := m.Submit(jobKindImport, fmt.Sprintf("导入 %s", tableName), func(ctx context.Context, r jobs.Reporter) (any, error) {
+		r.Report(0, 0, "正在解析文件")
+		res := a.importExecute(ctx, config, dbName, tableName, req, r)
+		if !res.Success {
+			a.notifyJobResult(ctx, notify.CategoryImport, "导入 "+tableName, "", errors.New(res.Message))
+			return nil, errors.New(res.Message)
+		}
+		a.notifyJobResult(ctx, notify.CategoryImport, "导入 "+tableName, res.Message, nil)
+		return res.Data, nil
+	})
+	return &connection.QueryResult{Success: true, Message: "导入任务已启动", Data: job}
+}
+
+// DBStartSchemaScanJob 在后台逐表读取列定义并刷新 SQL 分析使用的列信息缓存，按表汇报进度。
+func (a *DatabaseService) DBStartSchemaScanJob(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).Err(); err != nil {
+		return a.invalidArgs("DBStartSchemaScanJob", err)
+	}
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	title := "扫描表结构"
+	if dbName != "" {
+		title = fmt.Sprintf("扫描 %s 表结构", dbName)
+	}
+	job := m.Submit(jobKindSchemaScan, title, func(ctx context.Context, r jobs.Reporter) (any, error) {
+		return a.scanSchema(ctx, runConfig, dbName, r)
+	})
+	return &connection.QueryResult{Success: true, Message: "表结构扫描任务已启动", Data: job}
+}
+
+// scanSchema 逐表读取列定义，完成后写入列信息缓存；ctx 取消时在表之间中止。
+func (a *DatabaseService) scanSchema(ctx context.Context, runConfig *connection.ConnectionConfig, dbName string, r jobs.Reporter) (any, error) {
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("scanSchema 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, err
+	}
+	listCtx, cancel := metadataContext(ctx, runConfig)
+	tables, err := dbInst.GetTables(listCtx, dbName)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	total := int64(len(tables))
+	var columns []*connection.ColumnDefinitionWithTable
+	for i, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tableCtx, cancel := metadataContext(ctx, runConfig)
+		defs, err := dbInst.GetColumns(tableCtx, dbName, table)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 的列失败：%w", table, err)
+		}
+		for _, def := range defs {
+			columns = append(columns, &connection.ColumnDefinitionWithTable{TableName: table, Name: def.Name, Type: def.Type})
+		}
+		r.Report(int64(i+1), total, table)
+	}
+	a.schemas.put(schemaCacheKey(runConfig, dbName), columns)
+	return map[string]interface{}{"tables": len(tables), "columns": len(columns)}, nil
+}
+
+// notifyJobResult 在导出/导入任务结束时发送系统通知，已取消的任务不通知。
+func (a *DatabaseService) notifyJobResult(ctx context.Context, category notify.Category, title, body string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	n := notify.Notification{Category: category, Title: title + " 已完成", Body: body}
+	if err != nil {
+		n.Title, n.Body = title+" 失败", err.Error()
+	}
+	a.Notify(n)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetJSONKeys 采样 JSON 列的最多 sampleRows 个非空值（<= 0 时使用默认值），
+// 返回出现过的键路径及其值类型与出现频率，供过滤器与查询编辑器提示路径。
+func (a *DatabaseService) DBGetJSONKeys(config *connection.ConnectionConfig, dbName, tableName, column string, sampleRows int) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName).
+		Identifier("column", column).
+		Range("sampleRows", sampleRows, 0, db.MaxJSONSampleRows)
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBGetJSONKeys", err)
+	}
+	if sampleRows <= 0 {
+		sampleRows = db.DefaultJSONSampleRows
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getReadDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetJSONKeys 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	query := sanitizeSQLForPgLike(runConfig.Type, db.BuildJSONSampleQuery(runConfig.Type, tableName, column, sampleRows))
+	start := time.Now()
+	var (
+		data   []map[string]interface{}
+		fields []string
+	)
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, fields, err = q.QueryContext(ctx, query)
+	} else {
+		data, fields, err = dbInst.Query(query)
+	}
+	elapsed := time.Since(start)
+	if err != nil {
+		a.Logger().Error("DBGetJSONKeys 采样失败", "error", err, "table", tableName, "column", column, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds()}
+	}
+
+	key := column
+	if len(fields) > 0 {
+		key = fields[0]
+	}
+	values := make([]any, 0, len(data))
+	for _, row := range data {
+		values = append(values, row[key])
+	}
+	report := db.AnalyzeJSONKeys(column, values)
+	message := "获取 JSON 键路径成功"
+	if report.Sampled == 0 && report.Invalid > 0 {
+		message = "采样值都不是合法的 JSON"
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      message,
+		Data:         report,
+		Truncated:    report.Truncated,
+		DurationMs:   elapsed.Milliseconds(),
+		RowsReturned: len(data),
+	}
+}
+
+// DBBuildFilterPredicate 将前端过滤条件（可带 JSON 路径）翻译为当前数据库方言的参数化条件，
+// JSON 路径按方言生成 JSON_EXTRACT、#>>、JSON_VALUE 或 json_extract 取值。
+func (a *DatabaseService) DBBuildFilterPredicate(config *connection.ConnectionConfig, filters []connection.TableFilter, matchAny bool) *connection.QueryResult {
+	v := validate.New().ConnectionConfig("config", config).
+		Check(len(filters) > 0, "filters", validate.CodeRequired, "过滤条件不能为空")
+	for _, f := range filters {
+		v.Identifier("filters.column", f.Column)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBBuildFilterPredicate", err)
+	}
+	predicate, err := db.BuildFilterPredicate(config.Type, filters, matchAny)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "生成过滤条件成功", Data: predicate}
+}
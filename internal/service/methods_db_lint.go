@@ -0,0 +1,140 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// schemaCacheTTL 是列信息缓存的有效期，过期后下次分析时重新加载。
+const schemaCacheTTL = 5 * time.Minute
+
+// schemaCacheEntry 是一个库的列信息快照。
+type schemaCacheEntry struct {
+	schema   sqllint.Schema
+	loadedAt time.Time
+}
+
+// schemaCache 按连接与库缓存列信息，供 SQL 分析检查列名与类型；DDL 执行后失效。
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]schemaCacheEntry
+}
+
+// get 返回未过期的缓存。
+func (c *schemaCache) get(key string) (sqllint.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.loadedAt) > schemaCacheTTL {
+		return nil, false
+	}
+	return entry.schema, true
+}
+
+// put 由列信息构建并保存缓存。
+func (c *schemaCache) put(key string, columns []*connection.ColumnDefinitionWithTable) sqllint.Schema {
+	schema := sqllint.Schema{}
+	for _, col := range columns {
+		schema.Add(col.TableName, col.Name, col.Type)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]schemaCacheEntry)
+	}
+	c.entries[key] = schemaCacheEntry{schema: schema, loadedAt: time.Now()}
+	return schema
+}
+
+// invalidate 删除缓存。
+func (c *schemaCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// schemaCacheKey 返回不含凭据的连接与库标识。
+func schemaCacheKey(config *connection.ConnectionConfig, dbName string) string {
+	if dbName == "" {
+		dbName = config.Database
+	}
+	return fmt.Sprintf("%s://%s@%s:%d/%s", config.Type, config.User, config.Host, config.Port, dbName)
+}
+
+// AnalyzeSQL 对 SQL 做静态分析，返回带行列位置的告警（SELECT *、无 WHERE 的写操作、隐式类型转换、
+// 无法使用索引的谓词、表结构中不存在的列）。config 为空时只做不依赖表结构的检查；
+// 否则使用缓存的列信息，缓存缺失时加载一次。
+func (a *DatabaseService) AnalyzeSQL(config *connection.ConnectionConfig, dbName, sql string) *connection.QueryResult {
+	v := validate.New().Required("sql", sql)
+	if config != nil {
+		v.ConnectionConfig("config", config).OptionalIdentifier("dbName", dbName)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("AnalyzeSQL", err)
+	}
+
+	opts := sqllint.Options{}
+	if config != nil {
+		runConfig := normalizeRunConfig(config, dbName)
+		opts.Postgres = db.IsPostgresDialect(runConfig.Type)
+		opts.Schema = a.lintSchema(runConfig, dbName)
+	}
+
+	warnings := sqllint.Analyze(sql, opts)
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("分析完成，共 %d 条告警", len(warnings)),
+		Data: map[string]interface{}{
+			"warnings":      warnings,
+			"schemaChecked": opts.Schema != nil,
+		},
+	}
+}
+
+// lintSchema 返回分析使用的列信息，加载失败时返回 nil（跳过依赖表结构的规则）。
+func (a *DatabaseService) lintSchema(runConfig *connection.ConnectionConfig, dbName string) sqllint.Schema {
+	key := schemaCacheKey(runConfig, dbName)
+	if schema, ok := a.schemas.get(key); ok {
+		return schema
+	}
+	if dbName == "" {
+		dbName = runConfig.Database
+	}
+	if dbName == "" {
+		return nil
+	}
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Warn("AnalyzeSQL 获取连接失败，跳过表结构检查", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil
+	}
+	ctx, cancel := metadataContext(a.Context(), runConfig)
+	defer cancel()
+	columns, err := dbInst.GetAllColumns(ctx, dbName)
+	if err != nil {
+		a.Logger().Warn("AnalyzeSQL 加载列信息失败，跳过表结构检查", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil
+	}
+	return a.schemas.put(key, columns)
+}
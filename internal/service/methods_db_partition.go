@@ -0,0 +1,72 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetPartitions 获取表的分区方式、分区键与各分区的边界、估算行数和大小；未分区的表返回 method 为空。
+func (a *DatabaseService) DBGetPartitions(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetPartitions", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetPartitions 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.PartitionReader)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持读取分区"}
+	}
+
+	partitions, err := reader.GetPartitions(dbName, tableName)
+	if err != nil {
+		a.Logger().Error("DBGetPartitions 读取分区失败", "error", err, "table", tableName, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取分区成功", Data: partitions}
+}
+
+// DBAddPartition 为分区表新增分区；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBAddPartition(config *connection.ConnectionConfig, dbName, tableName string, spec *connection.PartitionSpec, dryRun bool) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName).Check(spec != nil, "partition", validate.CodeRequired, "partition 不能为空")
+	if spec != nil {
+		v.Identifier("partition.name", spec.Name)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBAddPartition", err)
+	}
+	return a.runDDL("DBAddPartition", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.AddPartition(schema, tableName, spec)
+	})
+}
+
+// DBDetachPartition 将分区从表中分离为独立表 targetTable（为空时 PostgreSQL 保留子表名，MySQL 使用 表名_分区名）；dryRun 为 true 时只返回语句。
+func (a *DatabaseService) DBDetachPartition(config *connection.ConnectionConfig, dbName, tableName, partitionName, targetTable string, dryRun bool) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).
+		Identifier("partitionName", partitionName).
+		OptionalIdentifier("targetTable", targetTable).Err(); err != nil {
+		return a.invalidArgs("DBDetachPartition", err)
+	}
+	return a.runDDL("DBDetachPartition", config, dbName, dryRun, func(b db.DDLBuilder, schema string) ([]string, error) {
+		return b.DetachPartition(schema, tableName, partitionName, targetTable)
+	})
+}
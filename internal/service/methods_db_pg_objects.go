@@ -0,0 +1,131 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// DBGetMaterializedViews 获取 schema 下的物化视图及其填充状态，schema 为空时使用连接当前 schema。
+func (a *DatabaseService) DBGetMaterializedViews(config *connection.ConnectionConfig, dbName, schema string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).OptionalIdentifier("schema", schema).Err(); err != nil {
+		return a.invalidArgs("DBGetMaterializedViews", err)
+	}
+	reader, runConfig, res := a.postgresObjectReader("DBGetMaterializedViews", config, dbName)
+	if res != nil {
+		return res
+	}
+
+	views, err := reader.GetMaterializedViews(schema)
+	if err != nil {
+		a.Logger().Error("DBGetMaterializedViews 获取物化视图失败", "error", err, "schema", schema, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取物化视图成功", Data: views}
+}
+
+// DBGetSequences 获取 schema 下的序列及其当前值，schema 为空时使用连接当前 schema。
+func (a *DatabaseService) DBGetSequences(config *connection.ConnectionConfig, dbName, schema string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).OptionalIdentifier("schema", schema).Err(); err != nil {
+		return a.invalidArgs("DBGetSequences", err)
+	}
+	reader, runConfig, res := a.postgresObjectReader("DBGetSequences", config, dbName)
+	if res != nil {
+		return res
+	}
+
+	seqs, err := reader.GetSequences(schema)
+	if err != nil {
+		a.Logger().Error("DBGetSequences 获取序列失败", "error", err, "schema", schema, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取序列成功", Data: seqs}
+}
+
+// DBShowCreateMaterializedView 获取物化视图的建视图语句。
+func (a *DatabaseService) DBShowCreateMaterializedView(config *connection.ConnectionConfig, dbName, schema, viewName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).OptionalIdentifier("schema", schema).Identifier("viewName", viewName).Err(); err != nil {
+		return a.invalidArgs("DBShowCreateMaterializedView", err)
+	}
+	reader, runConfig, res := a.postgresObjectReader("DBShowCreateMaterializedView", config, dbName)
+	if res != nil {
+		return res
+	}
+
+	sqlStr, err := reader.GetMaterializedViewCreateStatement(schema, viewName)
+	if err != nil {
+		a.Logger().Error("DBShowCreateMaterializedView 获取建视图语句失败", "error", err, "view", viewName, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取建视图语句成功", Data: sqlStr}
+}
+
+// DBShowCreateSequence 获取序列的建序列语句。
+func (a *DatabaseService) DBShowCreateSequence(config *connection.ConnectionConfig, dbName, schema, sequenceName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).OptionalIdentifier("schema", schema).Identifier("sequenceName", sequenceName).Err(); err != nil {
+		return a.invalidArgs("DBShowCreateSequence", err)
+	}
+	reader, runConfig, res := a.postgresObjectReader("DBShowCreateSequence", config, dbName)
+	if res != nil {
+		return res
+	}
+
+	sqlStr, err := reader.GetSequenceCreateStatement(schema, sequenceName)
+	if err != nil {
+		a.Logger().Error("DBShowCreateSequence 获取建序列语句失败", "error", err, "sequence", sequenceName, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取建序列语句成功", Data: sqlStr}
+}
+
+// postgresObjectReader 获取连接并断言其支持物化视图与序列读取；失败时返回可直接响应的结果。
+func (a *DatabaseService) postgresObjectReader(method string, config *connection.ConnectionConfig, dbName string) (db.PostgresObjectReader, *connection.ConnectionConfig, *connection.QueryResult) {
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, runConfig, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.PostgresObjectReader)
+	if !ok {
+		return nil, runConfig, &connection.QueryResult{Success: false, Message: "当前数据库不支持物化视图与序列"}
+	}
+	return reader, runConfig, nil
+}
+
+// DBGetCustomTypes 获取 schema 下的枚举、域与复合类型，供表格编辑器为枚举列提供下拉选项，schema 为空时使用连接当前 schema。
+func (a *DatabaseService) DBGetCustomTypes(config *connection.ConnectionConfig, dbName, schema string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).OptionalIdentifier("schema", schema).Err(); err != nil {
+		return a.invalidArgs("DBGetCustomTypes", err)
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetCustomTypes 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.PostgresTypeReader)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持自定义类型"}
+	}
+
+	types, err := reader.GetCustomTypes(schema)
+	if err != nil {
+		a.Logger().Error("DBGetCustomTypes 获取自定义类型失败", "error", err, "schema", schema, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取自定义类型成功", Data: types}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// maxProcedureParams 单次调用允许的最大参数个数。
+const maxProcedureParams = 256
+
+// DBCallProcedure 按数据库方言生成 CALL 语句调用存储过程，params 按位置对应过程参数，
+// Data 返回依次产生的结果集与 out/inout 参数的输出值。name 可带 schema 前缀。
+func (a *DatabaseService) DBCallProcedure(config *connection.ConnectionConfig, dbName, name string, params []*connection.ProcedureParam) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).
+		Identifier("name", name).
+		Check(len(params) <= maxProcedureParams, "params", validate.CodeTooLong, fmt.Sprintf("参数个数不能超过 %d", maxProcedureParams))
+	for i, p := range params {
+		if p == nil {
+			v.Check(false, fmt.Sprintf("params[%d]", i), validate.CodeRequired, "参数不能为空")
+			continue
+		}
+		mode := strings.ToLower(strings.TrimSpace(p.Mode))
+		v.Check(mode == "" || mode == connection.ProcedureParamIn || mode == connection.ProcedureParamOut || mode == connection.ProcedureParamInOut,
+			fmt.Sprintf("params[%d].mode", i), validate.CodeNotAllowed, fmt.Sprintf("参数方向无效: %s", p.Mode))
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBCallProcedure", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBCallProcedure 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, cancel := utils.ContextWithTimeout(execTimeout(runConfig, &connection.ExecOptions{}))
+	defer cancel()
+
+	start := time.Now()
+	var result *connection.ProcedureResult
+	switch inst := dbInst.(type) {
+	case db.ProcedureCaller:
+		result, err = inst.CallProcedure(ctx, name, params, maxExecRows)
+	case db.MultiResultQuerier:
+		if !db.IsPostgresDialect(runConfig.Type) {
+			return &connection.QueryResult{Success: false, Message: "当前驱动不支持调用存储过程"}
+		}
+		result, err = callPostgresProcedure(ctx, inst, name, params)
+	default:
+		return &connection.QueryResult{Success: false, Message: "当前驱动不支持调用存储过程"}
+	}
+	elapsed := time.Since(start)
+
+	call := "CALL " + name
+	if result != nil {
+		call = result.Call
+	}
+	a.recordQueryHistory(runConfig, dbName, call, err == nil, start)
+	entry := newAuditEntry(audit.FeatureProcedure, runConfig, dbName, start, err)
+	entry.SQL = call
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DBCallProcedure 调用失败", "error", err, "procedure", name, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds()}
+	}
+
+	rowsReturned := 0
+	for _, set := range result.ResultSets {
+		rowsReturned += len(set.Rows)
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      fmt.Sprintf("调用成功，返回 %d 个结果集、%d 个输出参数", len(result.ResultSets), len(result.Outputs)),
+		Data:         result,
+		ResultSets:   result.ResultSets,
+		DurationMs:   elapsed.Milliseconds(),
+		RowsReturned: rowsReturned,
+	}
+}
+
+// callPostgresProcedure 以 CALL 调用 PostgreSQL 存储过程，输出参数由 CALL 返回的单行结果集按参数名读取。
+func callPostgresProcedure(ctx context.Context, querier db.MultiResultQuerier, name string, params []*connection.ProcedureParam) (*connection.ProcedureResult, error) {
+	call, args, err := db.BuildPostgresProcedureCall(name, params)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := querier.QueryMulti(ctx, call, maxExecRows, args...)
+	if err != nil {
+		return nil, err
+	}
+	result := &connection.ProcedureResult{Call: call, ResultSets: sets, Outputs: map[string]interface{}{}}
+	if len(sets) == 0 || len(sets[0].Rows) == 0 {
+		return result, nil
+	}
+	row := sets[0].Rows[0]
+	for _, p := range params {
+		if mode := strings.ToLower(strings.TrimSpace(p.Mode)); mode == connection.ProcedureParamOut || mode == connection.ProcedureParamInOut {
+			result.Outputs[p.Name] = row[p.Name]
+		}
+	}
+	return result, nil
+}
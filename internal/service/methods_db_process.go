@@ -0,0 +1,84 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetProcessList 获取服务器上的活动会话及其正在执行的语句。
+func (a *DatabaseService) DBGetProcessList(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBGetProcessList", err)
+	}
+	manager, res := a.processManager("DBGetProcessList", config)
+	if res != nil {
+		return res
+	}
+
+	processes, err := manager.GetProcessList()
+	if err != nil {
+		a.Logger().Error("DBGetProcessList 获取会话列表失败", "error", err, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取会话列表成功", Data: processes}
+}
+
+// DBKillProcess 终止会话；queryOnly 为 true 时只取消正在执行的语句。
+func (a *DatabaseService) DBKillProcess(config *connection.ConnectionConfig, id int64, queryOnly bool) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).
+		Check(id > 0, "id", validate.CodeOutOfRange, "id 必须为正数").Err(); err != nil {
+		return a.invalidArgs("DBKillProcess", err)
+	}
+	manager, res := a.processManager("DBKillProcess", config)
+	if res != nil {
+		return res
+	}
+
+	start := time.Now()
+	err := manager.KillProcess(id, queryOnly)
+	entry := newAuditEntry(audit.FeatureKillProcess, config, "", start, err)
+	entry.SQL = fmt.Sprintf("KILL %d", id)
+	if queryOnly {
+		entry.SQL = fmt.Sprintf("KILL QUERY %d", id)
+	}
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("DBKillProcess 终止会话失败", "error", err, "id", id, "queryOnly", queryOnly, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info("DBKillProcess 终止会话成功", "id", id, "queryOnly", queryOnly, "summary", db.FormatConnSummary(config))
+	return &connection.QueryResult{Success: true, Message: "终止会话成功"}
+}
+
+// processManager 获取连接并断言其支持会话管理；失败时返回可直接响应的结果。
+func (a *DatabaseService) processManager(method string, config *connection.ConnectionConfig) (db.ProcessManager, *connection.QueryResult) {
+	dbInst, err := a.getDatabase(config)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(config))
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	manager, ok := dbInst.(db.ProcessManager)
+	if !ok {
+		return nil, &connection.QueryResult{Success: false, Message: "当前数据库不支持会话管理"}
+	}
+	return manager, nil
+}
@@ -16,50 +16,131 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/blobstore"
 	"github.com/chenyang-zz/boxify/internal/connection"
 	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
 	"github.com/chenyang-zz/boxify/internal/utils"
 )
 
+// 单次执行参数的取值上限。
+const (
+	maxExecTimeoutSeconds = 24 * 60 * 60
+	maxExecRows           = 1000000
+	maxExecFetchSize      = 100000
+)
+
 // DBQuery 执行 SQL 并返回查询结果或受影响行数。
 func (a *DatabaseService) DBQuery(config *connection.ConnectionConfig, dbName, query string, args []any) *connection.QueryResult {
+	return a.DBQueryWithOptions(config, dbName, query, args, nil)
+}
+
+// DBQueryWithOptions 按单次执行参数（超时、最大行数、每批读取行数）执行 SQL，不修改连接配置。
+// 查询结果超过 maxRows 时只返回前 maxRows 行并设置 Truncated。
+func (a *DatabaseService) DBQueryWithOptions(config *connection.ConnectionConfig, dbName, query string, args []any, opts *connection.ExecOptions) *connection.QueryResult {
+	if opts == nil {
+		opts = &connection.ExecOptions{}
+	}
+	if err := validateDatabaseArgs(config, dbName).
+		Required("query", query).
+		Range("timeoutSeconds", opts.TimeoutSeconds, 0, maxExecTimeoutSeconds).
+		Range("maxRows", opts.MaxRows, 0, maxExecRows).
+		Range("fetchSize", opts.FetchSize, 0, maxExecFetchSize).
+		Route("route", opts.Route).
+		Err(); err != nil {
+		return a.invalidArgs("DBQuery", err)
+	}
+
 	runConfig := normalizeRunConfig(config, dbName)
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	class := sqllint.Classify(query, db.IsPostgresDialect(runConfig.Type))
 
-	dbInst, err := a.getDatabase(runConfig)
+	var dbInst db.Database
+	var err error
+	if class.Kind == sqllint.StatementQuery && !class.Multiple {
+		dbInst, err = a.getReadDatabase(runConfig.WithReadRoute(opts.Route))
+	} else {
+		dbInst, err = a.getDatabase(runConfig)
+	}
 	if err != nil {
 		a.Logger().Error("DBQuery 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	query = sanitizeSQLForPgLike(runConfig.Type, query)
-	timeoutSeconds := runConfig.Timeout
-	if timeoutSeconds <= 0 {
-		timeoutSeconds = 30
-	}
-	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	ctx, cancel := utils.ContextWithTimeout(execTimeout(runConfig, opts))
 	defer cancel()
+	ctx = db.WithFetchSize(ctx, opts.FetchSize)
+	release, err := a.acquireSlot(ctx, runConfig, "DBQuery")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer release()
 
-	lowerQuery := strings.TrimSpace(strings.ToLower(query))
-	if strings.HasPrefix(lowerQuery, "select") || strings.HasPrefix(lowerQuery, "show") || strings.HasPrefix(lowerQuery, "describe") || strings.HasPrefix(lowerQuery, "explain") {
+	start := time.Now()
+	if class.ReturnsRows {
 		var data []map[string]interface{}
 		var columns []string
+		var fields []connection.FieldMeta
+		truncated := false
 
-		if q, ok := dbInst.(interface {
-			QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
-		}); ok {
-			data, columns, err = q.QueryContext(ctx, query, args...)
-		} else {
-			data, columns, err = dbInst.Query(query, args...)
+		// 只读查询遇到连接中断、死锁等瞬时错误时按策略重试，带 RETURNING 的数据修改只执行一次
+		policy := db.CurrentRetryPolicy()
+		if class.Kind != sqllint.StatementQuery || class.Multiple {
+			policy.Attempts = 0
+		}
+		retries, err := db.Retry(ctx, policy, func() error {
+			var err error
+			if streamer, ok := dbInst.(db.RowStreamer); ok {
+				data, fields, truncated, err = readStreamRows(ctx, streamer, query, args, opts.MaxRows, a.blobStore())
+				columns = fieldNames(fields)
+			} else if q, ok := dbInst.(interface {
+				QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+			}); ok {
+				data, columns, err = q.QueryContext(ctx, query, args...)
+			} else {
+				data, columns, err = dbInst.Query(query, args...)
+			}
+			return err
+		})
+		elapsed := time.Since(start)
+		a.recordQueryHistory(runConfig, dbName, query, err == nil, start)
+		if class.Kind != sqllint.StatementQuery || class.Multiple {
+			// 按分类而非执行路径审计：带 RETURNING 的数据修改、加锁读取同样返回结果集；
+			// 结果可能被 maxRows 截断，受影响行数记为未知
+			entry := newAuditEntry(audit.FeatureQuery, runConfig, dbName, start, err)
+			entry.SQL = query
+			a.Audit(entry)
 		}
 		if err != nil {
 			a.Logger().Error("DBQuery 查询失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
-			return &connection.QueryResult{Success: false, Message: err.Error()}
+			return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds(), Retries: retries}
+		}
+		if opts.MaxRows > 0 && len(data) > opts.MaxRows {
+			data, truncated = data[:opts.MaxRows], true
+		}
+		db.FillOriginTable(fields, query)
+		message := "查询成功"
+		if truncated {
+			message = fmt.Sprintf("查询成功，结果已截断为前 %d 行", opts.MaxRows)
+		}
+		return &connection.QueryResult{
+			Success:      true,
+			Message:      message,
+			Data:         data,
+			Fields:       columns,
+			FieldMeta:    fields,
+			Truncated:    truncated,
+			DurationMs:   elapsed.Milliseconds(),
+			RowsReturned: len(data),
+			Retries:      retries,
 		}
-		return &connection.QueryResult{Success: true, Message: "查询成功", Data: data, Fields: columns}
 	}
 
 	var affected int64
@@ -70,16 +151,142 @@ func (a *DatabaseService) DBQuery(config *connection.ConnectionConfig, dbName, q
 	} else {
 		affected, err = dbInst.Exec(query)
 	}
+	elapsed := time.Since(start)
+	a.recordQueryHistory(runConfig, dbName, query, err == nil, start)
+	entry := newAuditEntry(audit.FeatureQuery, runConfig, dbName, start, err)
+	entry.SQL = query
+	if err == nil {
+		entry.AffectedRows = affected
+	}
+	a.Audit(entry)
 	if err != nil {
 		a.Logger().Error("DBQuery 执行失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
-		return &connection.QueryResult{Success: false, Message: err.Error()}
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds()}
 	}
 
 	return &connection.QueryResult{
-		Success: true,
-		Message: fmt.Sprintf("执行成功，受影响的行数: %d", affected),
-		Data:    map[string]int64{"affectedRows": affected},
+		Success:      true,
+		Message:      fmt.Sprintf("执行成功，受影响的行数: %d", affected),
+		Data:         map[string]int64{"affectedRows": affected},
+		DurationMs:   elapsed.Milliseconds(),
+		RowsAffected: affected,
+	}
+}
+
+// DBQueryMulti 执行 CALL 存储过程或分号分隔的多语句批处理，按返回顺序读取全部结果集。
+// maxRows 作用于每个结果集；Data 与 Fields 为第一个结果集，便于只展示单个结果的调用方。
+func (a *DatabaseService) DBQueryMulti(config *connection.ConnectionConfig, dbName, query string, args []any, opts *connection.ExecOptions) *connection.QueryResult {
+	if opts == nil {
+		opts = &connection.ExecOptions{}
+	}
+	if err := validateDatabaseArgs(config, dbName).
+		Required("query", query).
+		Range("timeoutSeconds", opts.TimeoutSeconds, 0, maxExecTimeoutSeconds).
+		Range("maxRows", opts.MaxRows, 0, maxExecRows).
+		Err(); err != nil {
+		return a.invalidArgs("DBQueryMulti", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBQueryMulti 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	querier, ok := dbInst.(db.MultiResultQuerier)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前驱动不支持读取多个结果集"}
+	}
+
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	ctx, cancel := utils.ContextWithTimeout(execTimeout(runConfig, opts))
+	defer cancel()
+	release, err := a.acquireSlot(ctx, runConfig, "DBQueryMulti")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer release()
+
+	start := time.Now()
+	sets, err := querier.QueryMulti(ctx, query, opts.MaxRows, args...)
+	elapsed := time.Since(start)
+	a.recordQueryHistory(runConfig, dbName, query, err == nil, start)
+	if class := sqllint.Classify(query, db.IsPostgresDialect(runConfig.Type)); class.Kind != sqllint.StatementQuery || class.Multiple {
+		entry := newAuditEntry(audit.FeatureQuery, runConfig, dbName, start, err)
+		entry.SQL = query
+		a.Audit(entry)
+	}
+	if err != nil {
+		a.Logger().Error("DBQueryMulti 执行失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
+		return &connection.QueryResult{Success: false, Message: err.Error(), DurationMs: elapsed.Milliseconds(), ResultSets: sets}
+	}
+
+	result := &connection.QueryResult{
+		Success:    true,
+		Message:    fmt.Sprintf("执行成功，共返回 %d 个结果集", len(sets)),
+		ResultSets: sets,
+		DurationMs: elapsed.Milliseconds(),
+	}
+	for _, set := range sets {
+		result.RowsReturned += len(set.Rows)
+		result.Truncated = result.Truncated || set.Truncated
+	}
+	if len(sets) > 0 {
+		result.Data, result.Fields, result.FieldMeta = sets[0].Rows, sets[0].Fields, sets[0].FieldMeta
+	}
+	return result
+}
+
+// execTimeout 返回单次执行的超时：优先使用执行参数，其次连接配置，默认 30 秒。
+func execTimeout(runConfig *connection.ConnectionConfig, opts *connection.ExecOptions) time.Duration {
+	timeoutSeconds := opts.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = runConfig.Timeout
+	}
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	return time.Duration(timeoutSeconds) * time.Second
+}
+
+// readStreamRows 以流式方式读取查询结果并返回列类型信息；maxRows > 0 时读到 maxRows 行后停止，不在内存中缓存其余结果。
+// 超过内联上限的二进制值作为一个新结果保存到 blobs 并以引用返回。
+func readStreamRows(ctx context.Context, streamer db.RowStreamer, query string, args []any, maxRows int, blobs *blobstore.Store) ([]map[string]interface{}, []connection.FieldMeta, bool, error) {
+	stream, err := streamer.QueryStream(ctx, query, args...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer stream.Close()
+	stream.SetBlobSink(blobs.NewResult(), blobstore.DefaultInlineLimit)
+
+	capacity := 1024
+	if maxRows > 0 {
+		capacity = min(maxRows, capacity)
+	}
+	data := make([]map[string]interface{}, 0, capacity)
+	for {
+		row, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return data, stream.Fields(), false, nil
+		}
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if maxRows > 0 && len(data) >= maxRows {
+			return data, stream.Fields(), true, nil
+		}
+		data = append(data, row)
+	}
+}
+
+// fieldNames 返回列类型信息中的列名，顺序与 fields 一致。
+func fieldNames(fields []connection.FieldMeta) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
 	}
+	return names
 }
 
 // sqlSnippet 返回SQL查询的简短片段，用于日志输出，限制长度以避免过长。
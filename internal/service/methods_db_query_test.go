@@ -0,0 +1,107 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/queryhistory"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestDBQueryWithOptionsMetrics(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewDatabaseService(NewServiceDeps(application.New(application.Options{}), nil))
+	svc.history = queryhistory.NewStore(filepath.Join(dir, "history.jsonl"), 0, nil)
+	config := &connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: filepath.Join(dir, "metrics.db")}
+
+	if res := svc.DBQuery(config, "", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)", nil); !res.Success {
+		t.Fatalf("create table: %s", res.Message)
+	}
+	res := svc.DBQuery(config, "", "INSERT INTO items (name) VALUES ('a'), ('b'), ('c'), ('d'), ('e')", nil)
+	if !res.Success {
+		t.Fatalf("insert: %s", res.Message)
+	}
+	if res.RowsAffected != 5 || res.RowsReturned != 0 || res.DurationMs < 0 {
+		t.Fatalf("insert metrics = affected %d, returned %d, duration %d", res.RowsAffected, res.RowsReturned, res.DurationMs)
+	}
+
+	tests := []struct {
+		maxRows       int
+		wantReturned  int
+		wantTruncated bool
+	}{
+		{0, 5, false},
+		{5, 5, false},
+		{3, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("maxRows=%d", tt.maxRows), func(t *testing.T) {
+			res := svc.DBQueryWithOptions(config, "", "SELECT id, name FROM items ORDER BY id", nil, &connection.ExecOptions{MaxRows: tt.maxRows})
+			if !res.Success {
+				t.Fatalf("select: %s", res.Message)
+			}
+			if res.RowsReturned != tt.wantReturned || res.Truncated != tt.wantTruncated {
+				t.Fatalf("metrics = returned %d, truncated %v; want %d, %v", res.RowsReturned, res.Truncated, tt.wantReturned, tt.wantTruncated)
+			}
+			if rows, ok := res.Data.([]map[string]interface{}); !ok || len(rows) != res.RowsReturned {
+				t.Fatalf("RowsReturned %d does not match data %#v", res.RowsReturned, res.Data)
+			}
+			if res.RowsAffected != 0 || res.DurationMs < 0 {
+				t.Fatalf("metrics = affected %d, duration %d", res.RowsAffected, res.DurationMs)
+			}
+		})
+	}
+
+	res = svc.DBQuery(config, "", "SELECT missing FROM items", nil)
+	if res.Success || res.RowsReturned != 0 || res.DurationMs < 0 {
+		t.Fatalf("failed query = %+v", res)
+	}
+}
+
+func TestDBQueryAuditsRowReturningDML(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewDatabaseService(NewServiceDeps(application.New(application.Options{}), nil))
+	svc.history = queryhistory.NewStore(filepath.Join(dir, "history.jsonl"), 0, nil)
+	svc.auditLog = audit.NewLog(filepath.Join(dir, "audit.jsonl"), nil)
+	config := &connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite, Database: filepath.Join(dir, "audit.db")}
+
+	if res := svc.DBQuery(config, "", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)", nil); !res.Success {
+		t.Fatalf("create table: %s", res.Message)
+	}
+	res := svc.DBQuery(config, "", "INSERT INTO items (name) VALUES ('a') RETURNING id", nil)
+	if !res.Success || res.RowsReturned != 1 {
+		t.Fatalf("insert returning: %+v", res)
+	}
+	if res := svc.DBQuery(config, "", "SELECT id, name FROM items", nil); !res.Success {
+		t.Fatalf("select: %s", res.Message)
+	}
+
+	page, err := svc.auditLog.Query(audit.Filter{Feature: audit.FeatureQuery})
+	if err != nil {
+		t.Fatalf("query audit log: %v", err)
+	}
+	var sqls []string
+	for _, e := range page.Entries {
+		sqls = append(sqls, e.SQL)
+	}
+	if len(sqls) != 2 || sqls[0] != "INSERT INTO items (name) VALUES ('a') RETURNING id" {
+		t.Fatalf("audited statements = %q; want the CREATE and the INSERT ... RETURNING only", sqls)
+	}
+}
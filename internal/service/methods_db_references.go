@@ -0,0 +1,245 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/nestedfetch"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// 读取子表引用行的默认与最大行数。
+const (
+	defaultReferencingRows = 100
+	maxReferencingRows     = 1000
+)
+
+// DBGetReferencingForeignKeys 获取引用指定表的外键（子表方向），按约束聚合。
+func (a *DatabaseService) DBGetReferencingForeignKeys(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetReferencingForeignKeys", err)
+	}
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetReferencingForeignKeys 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.ReferencingKeysReader)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持读取引用关系"}
+	}
+	refs, err := reader.GetReferencingForeignKeys(dbName, tableName)
+	if err != nil {
+		a.Logger().Error("DBGetReferencingForeignKeys 读取引用关系失败", "table", tableName, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if refs == nil {
+		refs = []*connection.ForeignKeyRef{}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取引用关系成功", Data: refs}
+}
+
+// DBGetReferencedRow 按 tableName 上名为 constraintName 的外键，读取 row 所引用的父表记录。
+// row 为当前行的列值，至少包含外键列；外键列存在 NULL 时没有引用记录。
+func (a *DatabaseService) DBGetReferencedRow(config *connection.ConnectionConfig, dbName, tableName, constraintName string, row map[string]interface{}) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).
+		Required("constraintName", constraintName).
+		Check(len(row) > 0, "row", validate.CodeRequired, "row 不能为空").
+		Err(); err != nil {
+		return a.invalidArgs("DBGetReferencedRow", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetReferencedRow 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	ctx, cancel := metadataContext(a.Context(), runConfig)
+	defer cancel()
+	fks, err := dbInst.GetForeignKeys(ctx, dbName, tableName)
+	if err != nil {
+		a.Logger().Error("DBGetReferencedRow 获取外键失败", "table", tableName, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	fk := db.FindForeignKey(db.GroupForeignKeys(tableName, fks), constraintName)
+	if fk == nil {
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("表 %s 上不存在外键 %s", tableName, constraintName)}
+	}
+	values, ok, msg := referenceValues(row, fk.Columns)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: msg}
+	}
+
+	query, args := db.BuildMatchRowsQuery(runConfig.Type, fk.RefTable, fk.RefColumns, values, 2)
+	rows, columns, err := a.queryReferenceRows(dbInst, runConfig, query, args)
+	if err != nil {
+		a.Logger().Error("DBGetReferencedRow 查询失败", "error", err, "table", fk.RefTable, "constraint", constraintName)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	switch len(rows) {
+	case 0:
+		return &connection.QueryResult{Success: false, Message: "未找到被引用的记录，数据可能已被删除"}
+	case 1:
+	default:
+		return &connection.QueryResult{Success: false, Message: "被引用的列不唯一，匹配到多行"}
+	}
+	return &connection.QueryResult{
+		Success: true,
+		Message: "获取被引用记录成功",
+		Data:    map[string]interface{}{"foreignKey": fk, "table": fk.RefTable, "row": rows[0]},
+		Fields:  columns,
+	}
+}
+
+// DBGetReferencingRows 读取子表 childTable 中通过外键 constraintName 引用 tableName 当前行的记录。
+// row 为当前行的列值，至少包含被引用列；limit <= 0 时取默认值，超出时 Truncated 为 true。
+func (a *DatabaseService) DBGetReferencingRows(config *connection.ConnectionConfig, dbName, tableName, childTable, constraintName string, row map[string]interface{}, limit int) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).
+		Identifier("childTable", childTable).
+		Required("constraintName", constraintName).
+		Check(len(row) > 0, "row", validate.CodeRequired, "row 不能为空").
+		Range("limit", limit, 0, maxReferencingRows).
+		Err(); err != nil {
+		return a.invalidArgs("DBGetReferencingRows", err)
+	}
+	if limit <= 0 {
+		limit = defaultReferencingRows
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetReferencingRows 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	ctx, cancel := metadataContext(a.Context(), runConfig)
+	defer cancel()
+	fks, err := dbInst.GetForeignKeys(ctx, dbName, childTable)
+	if err != nil {
+		a.Logger().Error("DBGetReferencingRows 获取外键失败", "table", childTable, "error", err)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	fk := db.FindForeignKey(db.GroupForeignKeys(childTable, fks), constraintName)
+	if fk == nil || fk.RefTable != tableName {
+		return &connection.QueryResult{Success: false, Message: fmt.Sprintf("表 %s 上不存在引用 %s 的外键 %s", childTable, tableName, constraintName)}
+	}
+	values, ok, msg := referenceValues(row, fk.RefColumns)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: msg}
+	}
+
+	query, args := db.BuildMatchRowsQuery(runConfig.Type, childTable, fk.Columns, values, limit+1)
+	rows, columns, err := a.queryReferenceRows(dbInst, runConfig, query, args)
+	if err != nil {
+		a.Logger().Error("DBGetReferencingRows 查询失败", "error", err, "table", childTable, "constraint", constraintName)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	truncated := len(rows) > limit
+	if truncated {
+		rows = rows[:limit]
+	}
+	message := fmt.Sprintf("找到 %d 条引用记录", len(rows))
+	if truncated {
+		message = fmt.Sprintf("引用记录超过 %d 条，仅返回前 %d 条", limit, limit)
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      message,
+		Data:         map[string]interface{}{"foreignKey": fk, "table": childTable, "rows": rows},
+		Fields:       columns,
+		Truncated:    truncated,
+		RowsReturned: len(rows),
+	}
+}
+
+// DBFetchNested 读取 req.Key 定位的一行，并按 req.Relations 沿外键读取子表或父表记录，返回嵌套结构。
+// 每个关联在每一层只执行一次批量查询，详情页无需逐行往返；整个读取共用一次连接超时。
+func (a *DatabaseService) DBFetchNested(config *connection.ConnectionConfig, dbName string, req *nestedfetch.Request) *connection.QueryResult {
+	v := validateDatabaseArgs(config, dbName).Check(req != nil, "req", validate.CodeRequired, "req 不能为空")
+	if req != nil {
+		v.Identifier("req.table", req.Table).
+			Check(len(req.Key) > 0, "req.key", validate.CodeRequired, "req.key 不能为空").
+			Range("req.maxDepth", req.MaxDepth, 0, nestedfetch.MaxDepth)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBFetchNested", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBFetchNested 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	result, err := nestedfetch.Fetch(ctx, nestedfetch.NewDBSource(dbInst, runConfig.Type, dbName), req)
+	if err != nil {
+		a.Logger().Error("DBFetchNested 读取失败", "error", err, "table", req.Table)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("读取完成，共执行 %d 次查询", result.Queries),
+		Data:    result,
+		Fields:  result.Columns,
+	}
+}
+
+// queryReferenceRows 在连接超时内执行引用记录查询。
+func (a *DatabaseService) queryReferenceRows(dbInst db.Database, runConfig *connection.ConnectionConfig, query string, args []any) ([]map[string]interface{}, []string, error) {
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		return q.QueryContext(ctx, query, args...)
+	}
+	return dbInst.Query(query, args...)
+}
+
+// referenceValues 按 columns 顺序取出 row 中的值；缺少列或存在 NULL 时返回 false 与原因。
+func referenceValues(row map[string]interface{}, columns []string) ([]interface{}, bool, string) {
+	values := make([]interface{}, len(columns))
+	for i, col := range columns {
+		v, ok := row[col]
+		if !ok {
+			return nil, false, fmt.Sprintf("当前行缺少列 %s", col)
+		}
+		if v == nil {
+			return nil, false, fmt.Sprintf("列 %s 为 NULL，没有关联的记录", col)
+		}
+		values[i] = v
+	}
+	return values, true, ""
+}
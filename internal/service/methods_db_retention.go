@@ -0,0 +1,230 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/jobs"
+	"github.com/chenyang-zz/boxify/internal/retention"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// RetentionPreview 是数据保留规则的预览结果。
+type RetentionPreview struct {
+	RuleID           string    `json:"ruleId"`
+	Cutoff           time.Time `json:"cutoff"`           // 删除早于该时间的行
+	AffectedRows     int64     `json:"affectedRows"`     // 当前早于截止时间的行数
+	BatchSize        int       `json:"batchSize"`        // 每批删除行数
+	EstimatedBatches int64     `json:"estimatedBatches"` // 预计批次数
+	CountSQL         string    `json:"countSql"`
+	DeleteSQL        string    `json:"deleteSql"`
+}
+
+// retentionPlan 是执行或预览规则所需的连接与语句。
+type retentionPlan struct {
+	rule      *retention.Rule
+	runConfig *connection.ConnectionConfig
+	dbInst    db.Database
+	stmts     *db.RetentionStatements
+}
+
+// DBListRetentionRules 获取全部数据保留规则，按名称排序。
+func (a *DatabaseService) DBListRetentionRules() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取数据保留规则成功", Data: a.retentionStore().List()}
+}
+
+// DBSaveRetentionRule 创建或更新数据保留规则（ID 为空时创建）。
+func (a *DatabaseService) DBSaveRetentionRule(rule *retention.Rule) *connection.QueryResult {
+	v := validate.New().Check(rule != nil, "rule", validate.CodeRequired, "rule 不能为空")
+	if rule != nil {
+		v.Required("rule.connectionId", rule.ConnectionID).
+			OptionalIdentifier("rule.database", rule.Database).
+			Identifier("rule.timestampColumn", rule.TimestampColumn)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBSaveRetentionRule", err)
+	}
+	saved, err := a.retentionStore().Save(rule)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "数据保留规则已保存", Data: saved}
+}
+
+// DBDeleteRetentionRule 删除数据保留规则。
+func (a *DatabaseService) DBDeleteRetentionRule(id string) *connection.QueryResult {
+	if err := a.retentionStore().Delete(id); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "数据保留规则已删除"}
+}
+
+// DBPreviewRetention 统计规则当前将删除的行数并返回生成的语句，不修改数据；connectionID 为 config 对应的前端连接 ID，须与规则关联的连接一致。
+func (a *DatabaseService) DBPreviewRetention(config *connection.ConnectionConfig, connectionID, ruleID string) *connection.QueryResult {
+	plan, res := a.prepareRetention("DBPreviewRetention", config, connectionID, ruleID)
+	if res != nil {
+		return res
+	}
+	cutoff := plan.rule.Cutoff(time.Now())
+	count, err := a.countBulkRows(plan.dbInst, plan.runConfig, &db.BulkStatement{CountSQL: plan.stmts.CountSQL, CountArgs: []any{cutoff}})
+	if err != nil {
+		a.Logger().Error("DBPreviewRetention 统计行数失败", "error", err, "summary", db.FormatConnSummary(plan.runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	batchSize := plan.rule.EffectiveBatchSize()
+	return &connection.QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("预计删除 %d 行", count),
+		Data: &RetentionPreview{
+			RuleID:           plan.rule.ID,
+			Cutoff:           cutoff,
+			AffectedRows:     count,
+			BatchSize:        batchSize,
+			EstimatedBatches: (count + int64(batchSize) - 1) / int64(batchSize),
+			CountSQL:         plan.stmts.CountSQL,
+			DeleteSQL:        plan.stmts.DeleteSQL,
+		},
+	}
+}
+
+// DBStartRetentionJob 在后台任务中按规则分批删除旧数据，批次间按规则设置的间隔暂停，立即返回任务快照。
+// 截止时间在任务开始时确定，执行期间新变旧的行留待下次执行；取消任务时已删除的批次不会回滚。
+// connectionID 为 config 对应的前端连接 ID，与规则关联的连接不一致时拒绝执行。
+func (a *DatabaseService) DBStartRetentionJob(config *connection.ConnectionConfig, connectionID, ruleID string) *connection.QueryResult {
+	m := a.Jobs()
+	if m == nil {
+		return &connection.QueryResult{Success: false, Message: "任务管理器未初始化"}
+	}
+	plan, res := a.prepareRetention("DBStartRetentionJob", config, connectionID, ruleID)
+	if res != nil {
+		return res
+	}
+	title := fmt.Sprintf("清理 %s 中 %d 天前的数据", plan.rule.Table, plan.rule.KeepDays)
+	job := m.Submit(jobKindRetention, title, func(ctx context.Context, r jobs.Reporter) (any, error) {
+		return a.runRetention(ctx, plan, r)
+	})
+	return &connection.QueryResult{Success: true, Message: "数据清理任务已启动", Data: job}
+}
+
+// runRetention 统计待删除行数后循环执行删除，记录执行结果与审计日志。
+func (a *DatabaseService) runRetention(ctx context.Context, plan *retentionPlan, r jobs.Reporter) (*retention.Run, error) {
+	start := time.Now()
+	cutoff := plan.rule.Cutoff(start)
+	r.Report(0, 0, "正在统计待删除行数")
+	total, err := a.countBulkRows(plan.dbInst, plan.runConfig, &db.BulkStatement{CountSQL: plan.stmts.CountSQL, CountArgs: []any{cutoff}})
+	if err != nil {
+		total = 0
+		a.Logger().Warn("runRetention 统计行数失败，进度不显示总数", "error", err, "summary", db.FormatConnSummary(plan.runConfig))
+	}
+
+	batch := func(ctx context.Context) (int64, error) {
+		if e, ok := plan.dbInst.(interface {
+			ExecContext(context.Context, string, ...any) (int64, error)
+		}); ok {
+			return e.ExecContext(ctx, plan.stmts.DeleteSQL, cutoff)
+		}
+		return plan.dbInst.Exec(plan.stmts.DeleteSQL, cutoff)
+	}
+	run, err := retention.Execute(ctx, batch, plan.rule.EffectiveBatchSize(), plan.rule.Pause(), func(deleted int64, batches int) {
+		r.Report(deleted, max(total, deleted), fmt.Sprintf("已删除 %d 行（第 %d 批）", deleted, batches))
+	})
+	run.StartedAt, run.FinishedAt, run.Cutoff = start, time.Now(), cutoff
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if recErr := a.retentionStore().RecordRun(plan.rule.ID, run); recErr != nil {
+		a.Logger().Warn("runRetention 保存执行记录失败", "error", recErr, "rule", plan.rule.ID)
+	}
+
+	entry := newAuditEntry(audit.FeatureRetention, plan.runConfig, plan.rule.Database, start, err)
+	entry.Table = plan.rule.Table
+	entry.SQL = plan.stmts.DeleteSQL
+	entry.AffectedRows = run.Deleted
+	a.Audit(entry)
+	if err != nil {
+		a.Logger().Error("runRetention 清理中止", "error", err, "deleted", run.Deleted, "rule", plan.rule.ID, "summary", db.FormatConnSummary(plan.runConfig))
+	}
+	return run, err
+}
+
+// prepareRetention 读取规则、校验连接与时间列并生成语句，失败时返回错误结果。
+func (a *DatabaseService) prepareRetention(method string, config *connection.ConnectionConfig, connectionID, ruleID string) (*retentionPlan, *connection.QueryResult) {
+	if err := validate.New().ConnectionConfig("config", config).
+		Required("connectionId", connectionID).
+		Required("ruleId", ruleID).Err(); err != nil {
+		return nil, a.invalidArgs(method, err)
+	}
+	rule, err := a.retentionStore().Get(ruleID)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if err := rule.CheckConnection(connectionID); err != nil {
+		a.Logger().Warn(method+" 连接与规则不一致", "rule", rule.ID, "connectionId", connectionID)
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	runConfig := normalizeRunConfig(config, rule.Database)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	columns, err := getTableColumns(a.Context(), dbInst, runConfig, rule.Database, rule.Table)
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	if err := checkRetentionColumn(columns, rule.TimestampColumn); err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	stmts, err := db.BuildRetentionStatements(runConfig.Type, rule.Table, rule.TimestampColumn, rule.EffectiveBatchSize())
+	if err != nil {
+		return nil, &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &retentionPlan{rule: rule, runConfig: runConfig, dbInst: dbInst, stmts: stmts}, nil
+}
+
+// checkRetentionColumn 校验时间列存在且为日期时间类型；驱动报告的类型无法识别时不拦截。
+func checkRetentionColumn(columns []*connection.ColumnDefinition, name string) error {
+	for _, col := range columns {
+		if col.Name != name {
+			continue
+		}
+		switch db.FieldKind(col.Type) {
+		case connection.FieldKindDateTime, connection.FieldKindOther:
+			return nil
+		default:
+			return fmt.Errorf("列 %s 的类型 %s 不是日期时间类型", name, col.Type)
+		}
+	}
+	if len(columns) == 0 {
+		return errors.New("表不存在或没有列")
+	}
+	return fmt.Errorf("时间列不存在: %s", name)
+}
+
+// retentionStore 返回数据保留规则存储，未初始化时按默认路径创建。
+func (a *DatabaseService) retentionStore() *retention.Store {
+	if a.retention == nil {
+		a.retention = retention.NewStore("", a.Logger())
+	}
+	return a.retention
+}
@@ -17,17 +17,27 @@ package service
 import (
 	"github.com/chenyang-zz/boxify/internal/connection"
 	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
 )
 
 // DBGetDatabases 获取数据库列表。
 func (a *DatabaseService) DBGetDatabases(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("DBGetDatabases", err)
+	}
+
 	dbInst, err := a.getDatabase(config)
 	if err != nil {
 		a.Logger().Error("DBGetDatabases 获取连接失败", "error", err, "summary", db.FormatConnSummary(config))
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	dbs, err := dbInst.GetDatabases()
+	ctx, done, err := a.metadataCall(config, "DBGetDatabases")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	dbs, err := dbInst.GetDatabases(ctx)
 	if err != nil {
 		a.Logger().Error("DBGetDatabases 获取数据库列表失败", "error", err, "summary", db.FormatConnSummary(config))
 		return &connection.QueryResult{Success: false, Message: err.Error()}
@@ -43,6 +53,10 @@ func (a *DatabaseService) DBGetDatabases(config *connection.ConnectionConfig) *c
 
 // DBGetTables 获取表列表。
 func (a *DatabaseService) DBGetTables(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).Err(); err != nil {
+		return a.invalidArgs("DBGetTables", err)
+	}
+
 	runConfig := normalizeRunConfig(config, dbName)
 
 	dbInst, err := a.getDatabase(runConfig)
@@ -51,7 +65,12 @@ func (a *DatabaseService) DBGetTables(config *connection.ConnectionConfig, dbNam
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	tables, err := dbInst.GetTables(dbName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetTables")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	tables, err := dbInst.GetTables(ctx, dbName)
 	if err != nil {
 		a.Logger().Error("DBGetTables 获取表列表失败", "error", err, "summary", db.FormatConnSummary(runConfig))
 		return &connection.QueryResult{Success: false, Message: err.Error()}
@@ -65,19 +84,104 @@ func (a *DatabaseService) DBGetTables(config *connection.ConnectionConfig, dbNam
 	return &connection.QueryResult{Success: true, Message: "获取表列表成功", Data: resData}
 }
 
+// DBGetSchemas 获取数据库下的 schema 列表；驱动不区分 schema 时（如 MySQL，schema 即数据库）返回数据库列表。
+func (a *DatabaseService) DBGetSchemas(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).Err(); err != nil {
+		return a.invalidArgs("DBGetSchemas", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetSchemas 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, done, err := a.metadataCall(runConfig, "DBGetSchemas")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	var schemas []string
+	if lister, ok := dbInst.(db.SchemaLister); ok {
+		schemas, err = lister.GetSchemas(ctx)
+	} else {
+		schemas, err = dbInst.GetDatabases(ctx)
+	}
+	if err != nil {
+		a.Logger().Error("DBGetSchemas 获取 schema 列表失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	resData := make([]map[string]string, 0, len(schemas))
+	for _, name := range schemas {
+		resData = append(resData, map[string]string{"Schema": name})
+	}
+
+	return &connection.QueryResult{Success: true, Message: "获取 schema 列表成功", Data: resData}
+}
+
+// DBGetSchemaTables 获取指定 schema 下的表列表，QualifiedName 为带 schema 前缀的表名，
+// 可直接作为 tableName 传给 DBGetColumns、ApplyChanges 与导出等方法，大小写混合或含点的名称会加引号。
+func (a *DatabaseService) DBGetSchemaTables(config *connection.ConnectionConfig, dbName, schema string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).Identifier("schema", schema).Err(); err != nil {
+		return a.invalidArgs("DBGetSchemaTables", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetSchemaTables 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	ctx, done, err := a.metadataCall(runConfig, "DBGetSchemaTables")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	tables, err := dbInst.GetTables(ctx, schema)
+	if err != nil {
+		a.Logger().Error("DBGetSchemaTables 获取表列表失败", "error", err, "schema", schema, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	resData := make([]map[string]string, 0, len(tables))
+	for _, name := range tables {
+		resData = append(resData, map[string]string{
+			"Schema":        schema,
+			"Table":         name,
+			"QualifiedName": db.QualifiedName{Schema: schema, Name: name}.String(),
+		})
+	}
+
+	return &connection.QueryResult{Success: true, Message: "获取表列表成功", Data: resData}
+}
+
 // DBShowCreateTable 获取建表语句。
 func (a *DatabaseService) DBShowCreateTable(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBShowCreateTable", err)
+	}
+
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	sqlStr, err := dbInst.GetCreateStatement(dbName, tableName)
+	ctx, done, err := a.metadataCall(runConfig, "DBShowCreateTable")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	sqlStr, err := dbInst.GetCreateStatement(ctx, dbName, tableName)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
@@ -87,6 +191,10 @@ func (a *DatabaseService) DBShowCreateTable(config *connection.ConnectionConfig,
 
 // DBGetColumns 获取列信息。
 func (a *DatabaseService) DBGetColumns(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetColumns", err)
+	}
+
 	runConfig := normalizeRunConfig(config, dbName)
 
 	dbInst, err := a.getDatabase(runConfig)
@@ -96,7 +204,12 @@ func (a *DatabaseService) DBGetColumns(config *connection.ConnectionConfig, dbNa
 	}
 
 	schemaName, pureTableName := normalizeSchemaAndTable(config, dbName, tableName)
-	columns, err := dbInst.GetColumns(schemaName, pureTableName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetColumns")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	columns, err := dbInst.GetColumns(ctx, schemaName, pureTableName)
 	if err != nil {
 		a.Logger().Error("DBGetColumns 获取列信息失败", "error", err, "summary", db.FormatConnSummary(runConfig), "schema", schemaName, "table", pureTableName)
 		return &connection.QueryResult{Success: false, Message: err.Error()}
@@ -107,17 +220,26 @@ func (a *DatabaseService) DBGetColumns(config *connection.ConnectionConfig, dbNa
 
 // DBGetIndexes 获取索引信息。
 func (a *DatabaseService) DBGetIndexes(config *connection.ConnectionConfig, dbName string, tableName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetIndexes", err)
+	}
+
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	indexes, err := dbInst.GetIndexes(dbName, tableName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetIndexes")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	indexes, err := dbInst.GetIndexes(ctx, dbName, tableName)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
@@ -127,17 +249,26 @@ func (a *DatabaseService) DBGetIndexes(config *connection.ConnectionConfig, dbNa
 
 // DBGetForeignKeys 获取外键信息。
 func (a *DatabaseService) DBGetForeignKeys(config *connection.ConnectionConfig, dbName string, tableName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetForeignKeys", err)
+	}
+
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	fks, err := dbInst.GetForeignKeys(dbName, tableName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetForeignKeys")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	fks, err := dbInst.GetForeignKeys(ctx, dbName, tableName)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
@@ -147,17 +278,26 @@ func (a *DatabaseService) DBGetForeignKeys(config *connection.ConnectionConfig,
 
 // DBGetTriggers 获取触发器信息。
 func (a *DatabaseService) DBGetTriggers(config *connection.ConnectionConfig, dbName string, tableName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("DBGetTriggers", err)
+	}
+
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	triggers, err := dbInst.GetTriggers(dbName, tableName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetTriggers")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	triggers, err := dbInst.GetTriggers(ctx, dbName, tableName)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
@@ -167,20 +307,58 @@ func (a *DatabaseService) DBGetTriggers(config *connection.ConnectionConfig, dbN
 
 // DBGetAllColumns 获取所有列信息（包含系统表）。
 func (a *DatabaseService) DBGetAllColumns(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
-	runConfig := *config
+	if err := validateDatabaseArgs(config, dbName).Err(); err != nil {
+		return a.invalidArgs("DBGetAllColumns", err)
+	}
+
+	runConfig := config.Clone()
 	if dbName != "" {
 		runConfig.Database = dbName
 	}
 
-	dbInst, err := a.getDatabase(&runConfig)
+	dbInst, err := a.getDatabase(runConfig)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
 
-	columns, err := dbInst.GetAllColumns(dbName)
+	ctx, done, err := a.metadataCall(runConfig, "DBGetAllColumns")
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer done()
+	columns, err := dbInst.GetAllColumns(ctx, dbName)
 	if err != nil {
 		return &connection.QueryResult{Success: false, Message: err.Error()}
 	}
+	a.schemas.put(schemaCacheKey(runConfig, dbName), columns)
 
 	return &connection.QueryResult{Success: true, Message: "获取所有列信息成功", Data: columns}
 }
+
+// SetActiveSchema 设置连接的活动 schema（MySQL 为 USE，PostgreSQL 为 search_path），
+// 之后 dbName 为空的查询中未限定的对象名都解析到该 schema；schema 为空时恢复连接默认库。
+func (a *DatabaseService) SetActiveSchema(config *connection.ConnectionConfig, schema string) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).OptionalIdentifier("schema", schema).Err(); err != nil {
+		return a.invalidArgs("SetActiveSchema", err)
+	}
+	if a.manager == nil {
+		a.manager = db.NewConnectionManager(a.Logger())
+	}
+
+	if err := a.manager.SetActiveSchema(config, schema); err != nil {
+		a.Logger().Error("SetActiveSchema 切换 schema 失败", "error", err, "schema", schema, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "切换 schema 成功", Data: map[string]string{"schema": schema}}
+}
+
+// GetActiveSchema 获取连接当前的活动 schema，未设置时为空。
+func (a *DatabaseService) GetActiveSchema(config *connection.ConnectionConfig) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs("GetActiveSchema", err)
+	}
+	if a.manager == nil {
+		return &connection.QueryResult{Success: true, Message: "获取活动 schema 成功", Data: map[string]string{"schema": ""}}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取活动 schema 成功", Data: map[string]string{"schema": a.manager.ActiveSchema(config)}}
+}
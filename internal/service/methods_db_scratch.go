@@ -0,0 +1,97 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataimport"
+	"github.com/chenyang-zz/boxify/internal/scratch"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// scratchImportTimeout 是写入草稿库的超时。
+const scratchImportTimeout = 5 * time.Minute
+
+// GetScratchConnection 返回本地草稿库（SQLite 文件）的连接配置，前端将其作为始终存在的连接展示，
+// 查询、建表等操作与普通连接使用相同的接口。
+func (a *DatabaseService) GetScratchConnection() *connection.QueryResult {
+	config, err := a.scratchDatabase()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取草稿库成功", Data: config}
+}
+
+// ScratchImportText 将粘贴的 CSV 文本（首行为表头）写入草稿库的 table 表；replace 为 true 时替换同名表。
+func (a *DatabaseService) ScratchImportText(table, content string, replace bool) *connection.QueryResult {
+	v := validate.New().Identifier("table", table).Required("content", content)
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("ScratchImportText", err)
+	}
+	parsed, err := dataimport.ParseCSV(strings.NewReader(content))
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return a.scratchLoad("ScratchImportText", table, parsed, replace)
+}
+
+// ScratchImportFile 将导出或下载的数据文件（CSV、JSON、Excel、Parquet）写入草稿库的 table 表；
+// sheet 仅对 Excel 生效，replace 为 true 时替换同名表。
+func (a *DatabaseService) ScratchImportFile(table, filePath, sheet string, replace bool) *connection.QueryResult {
+	v := validate.New().Identifier("table", table).Required("filePath", filePath)
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("ScratchImportFile", err)
+	}
+	parsed, err := dataimport.ParseFile(filePath, sheet)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return a.scratchLoad("ScratchImportFile", table, parsed, replace)
+}
+
+// scratchLoad 将解析出的数据写入草稿库。
+func (a *DatabaseService) scratchLoad(method, table string, parsed *dataimport.Table, replace bool) *connection.QueryResult {
+	config, err := a.scratchDatabase()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	dbInst, err := a.getDatabase(config)
+	if err != nil {
+		a.Logger().Error(method+" 打开草稿库失败", "error", err, "path", config.Database)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	exec, ok := dbInst.(scratch.Execer)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "草稿库连接不支持写入"}
+	}
+	ctx, cancel := utils.ContextWithTimeout(scratchImportTimeout)
+	defer cancel()
+	rows, err := scratch.LoadTable(ctx, exec, table, parsed, replace)
+	if err != nil {
+		a.Logger().Warn(method+" 写入草稿库失败", "error", err, "table", table, "rows", rows)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	a.Logger().Info(method+" 写入草稿库", "table", table, "rows", rows)
+	return &connection.QueryResult{Success: true, Message: "已写入草稿库", Data: map[string]interface{}{"table": table, "rows": rows}}
+}
+
+// scratchDatabase 返回草稿库连接配置。
+func (a *DatabaseService) scratchDatabase() (*connection.ConnectionConfig, error) {
+	return scratch.Config("")
+}
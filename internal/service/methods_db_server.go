@@ -0,0 +1,55 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetServerStatus 获取服务器运行状态（MySQL 的 SHOW GLOBAL STATUS、PostgreSQL 的 pg_stat_*），按分类整理。
+func (a *DatabaseService) DBGetServerStatus(config *connection.ConnectionConfig) *connection.QueryResult {
+	return a.serverMetrics("DBGetServerStatus", config, "获取服务器状态成功", db.ServerInspector.GetServerStatus)
+}
+
+// DBGetServerVariables 获取服务器配置变量（MySQL 的 SHOW GLOBAL VARIABLES、PostgreSQL 的 pg_settings），按分类整理。
+func (a *DatabaseService) DBGetServerVariables(config *connection.ConnectionConfig) *connection.QueryResult {
+	return a.serverMetrics("DBGetServerVariables", config, "获取服务器变量成功", db.ServerInspector.GetServerVariables)
+}
+
+// serverMetrics 校验参数、获取连接并读取指标。
+func (a *DatabaseService) serverMetrics(method string, config *connection.ConnectionConfig, okMessage string, read func(db.ServerInspector) (*connection.ServerMetrics, error)) *connection.QueryResult {
+	if err := validate.New().ConnectionConfig("config", config).Err(); err != nil {
+		return a.invalidArgs(method, err)
+	}
+
+	dbInst, err := a.getDatabase(config)
+	if err != nil {
+		a.Logger().Error(method+" 获取连接失败", "error", err, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	inspector, ok := dbInst.(db.ServerInspector)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持服务器状态查看"}
+	}
+
+	metrics, err := read(inspector)
+	if err != nil {
+		a.Logger().Error(method+" 读取失败", "error", err, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: okMessage, Data: metrics}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"os"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/slowquery"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// slowQueryServerRows 是从服务端统计视图读取的最大行数，聚合后再取前 N。
+const slowQueryServerRows = 1000
+
+// slowQuerySortOptions 是允许的排序方式。
+var slowQuerySortOptions = []string{slowquery.SortByTotalTime, slowquery.SortByMeanTime, slowquery.SortByCalls, slowquery.SortByRows}
+
+// AnalyzeSlowQueryLog 解析 MySQL 慢查询日志文件并按指纹聚合；path 为空时弹出文件选择框。
+func (a *DatabaseService) AnalyzeSlowQueryLog(path, sortBy string, limit int) *connection.QueryResult {
+	v := validate.New().Range("limit", limit, 0, 1000)
+	if sortBy != "" {
+		v.OneOf("sortBy", sortBy, slowQuerySortOptions...)
+	}
+	if path != "" {
+		v.SafePath("path", path)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("AnalyzeSlowQueryLog", err)
+	}
+
+	if path == "" {
+		selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: "Select Slow Query Log",
+			Filters: []runtime.FileFilter{
+				{DisplayName: "Log Files (*.log)", Pattern: "*.log"},
+				{DisplayName: "All Files (*.*)", Pattern: "*.*"},
+			},
+		})
+		if err != nil {
+			return &connection.QueryResult{Success: false, Message: err.Error()}
+		}
+		if selection == "" {
+			return &connection.QueryResult{Success: false, Message: "Cancelled"}
+		}
+		path = selection
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer f.Close()
+
+	agg := slowquery.NewAggregator()
+	if err := slowquery.ParseMySQLSlowLog(f, agg.Add); err != nil {
+		a.Logger().Error("AnalyzeSlowQueryLog 解析慢日志失败", "error", err, "path", path)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	report, err := agg.Report(slowquery.SourceSlowLog, sortBy, limit)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "慢查询分析完成", Data: report}
+}
+
+// DBGetSlowQueries 从服务端统计视图读取语句耗时并按指纹聚合：
+// MySQL 使用 performance_schema 语句摘要，PostgreSQL 使用 pg_stat_statements 扩展。
+func (a *DatabaseService) DBGetSlowQueries(config *connection.ConnectionConfig, sortBy string, limit int) *connection.QueryResult {
+	v := validate.New().ConnectionConfig("config", config).Range("limit", limit, 0, 1000)
+	if sortBy != "" {
+		v.OneOf("sortBy", sortBy, slowQuerySortOptions...)
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBGetSlowQueries", err)
+	}
+
+	dbInst, err := a.getDatabase(config)
+	if err != nil {
+		a.Logger().Error("DBGetSlowQueries 获取连接失败", "error", err, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	agg := slowquery.NewAggregator()
+	source := slowquery.SourcePerformanceSchema
+	switch config.Type {
+	case connection.ConnectionTypePostgreSQL, connection.ConnectionTypeKingbase, connection.ConnectionTypeHighGo, connection.ConnectionTypeVastBase:
+		source = slowquery.SourcePgStatStatements
+		err = slowquery.FromPgStatStatements(dbInst.Query, slowQueryServerRows, agg)
+	default:
+		err = slowquery.FromPerformanceSchema(dbInst.Query, slowQueryServerRows, agg)
+	}
+	if err != nil {
+		a.Logger().Error("DBGetSlowQueries 读取语句统计失败", "error", err, "source", source, "summary", db.FormatConnSummary(config))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+
+	report, err := agg.Report(source, sortBy, limit)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取慢查询统计成功", Data: report}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/snapshot"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBSaveSnapshot 执行查询并把结果保存为工作区中的只读快照，之后无需连接即可查看。
+func (a *DatabaseService) DBSaveSnapshot(config *connection.ConnectionConfig, dbName, name, query string, args []any) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).
+		Required("name", name).
+		Required("query", query).
+		Check(isCursorQuery(query), "query", validate.CodeNotAllowed, "快照仅支持 SELECT 类查询").
+		Err(); err != nil {
+		return a.invalidArgs("DBSaveSnapshot", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBSaveSnapshot 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	streamer, ok := dbInst.(db.RowStreamer)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持保存快照"}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	stream, err := streamer.QueryStream(ctx, query, args...)
+	if err != nil {
+		a.Logger().Error("DBSaveSnapshot 查询失败", "error", err, "summary", db.FormatConnSummary(runConfig), "snippet", sqlSnippet(query))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	defer stream.Close()
+
+	snap := &snapshot.Snapshot{Meta: snapshot.Meta{
+		Name:     name,
+		Source:   db.FormatConnSummary(runConfig),
+		Database: dbName,
+		Query:    query,
+	}}
+	types := stream.ColumnTypes()
+	for i, col := range stream.Columns() {
+		snap.Columns = append(snap.Columns, snapshot.Column{Name: col, Type: types[i]})
+	}
+	for {
+		row, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			a.Logger().Error("DBSaveSnapshot 读取结果失败", "error", err, "snippet", sqlSnippet(query))
+			return &connection.QueryResult{Success: false, Message: err.Error()}
+		}
+		if len(snap.Rows) >= snapshot.DefaultMaxRows {
+			snap.Truncated = true
+			break
+		}
+		snap.AppendRecord(row)
+	}
+
+	meta, err := a.snapshotStore().Save(snap)
+	if err != nil {
+		a.Logger().Error("DBSaveSnapshot 保存快照失败", "error", err, "name", name)
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "快照已保存", Data: meta}
+}
+
+// ListSnapshots 列出工作区中的快照（仅元信息）。
+func (a *DatabaseService) ListSnapshots() *connection.QueryResult {
+	metas, err := a.snapshotStore().List()
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取快照列表成功", Data: metas}
+}
+
+// GetSnapshot 读取快照的列结构与行数据，不需要数据库连接。
+func (a *DatabaseService) GetSnapshot(id string) *connection.QueryResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return a.invalidArgs("GetSnapshot", err)
+	}
+	snap, err := a.snapshotStore().Get(id)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取快照成功", Data: snap, Fields: snap.ColumnNames()}
+}
+
+// RenameSnapshot 修改快照名称。
+func (a *DatabaseService) RenameSnapshot(id, name string) *connection.QueryResult {
+	if err := validate.New().Required("id", id).Required("name", name).Err(); err != nil {
+		return a.invalidArgs("RenameSnapshot", err)
+	}
+	meta, err := a.snapshotStore().Rename(id, name)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "快照已重命名", Data: meta}
+}
+
+// DeleteSnapshot 删除快照。
+func (a *DatabaseService) DeleteSnapshot(id string) *connection.QueryResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return a.invalidArgs("DeleteSnapshot", err)
+	}
+	if err := a.snapshotStore().Delete(id); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "快照已删除"}
+}
+
+// snapshotStore 返回快照存储，未初始化时使用默认目录。
+func (a *DatabaseService) snapshotStore() *snapshot.Store {
+	if a.snapshots == nil {
+		a.snapshots = snapshot.NewStore("", a.Logger())
+	}
+	return a.snapshots
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// DBGetTableData 按页浏览表数据，排序与过滤在服务端编译为参数化查询，前端无需拼接 SQL。
+// options.Keyset 为 true 时使用键分页，大表翻页不随页码变慢；下一页传入返回的 NextAfter。
+func (a *DatabaseService) DBGetTableData(config *connection.ConnectionConfig, dbName, tableName string, options *connection.TableDataOptions) *connection.QueryResult {
+	v := validateTableArgs(config, dbName, tableName)
+	if options != nil {
+		v.Check(options.Page >= 0, "options.page", validate.CodeOutOfRange, "页码不能为负数")
+		if options.PageSize != 0 {
+			v.PageSize("options.pageSize", options.PageSize, db.MaxTableDataPageSize)
+		}
+		for i, c := range options.Computed {
+			v.Identifier(fmt.Sprintf("options.computed[%d].name", i), c.Name).
+				Required(fmt.Sprintf("options.computed[%d].expression", i), c.Expression)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return a.invalidArgs("DBGetTableData", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetTableData 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.TableDataReader)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持表数据浏览"}
+	}
+	if options != nil && len(options.Computed) > 0 {
+		if err := a.checkComputedColumns(runConfig, dbName, tableName, options.Computed); err != nil {
+			return &connection.QueryResult{Success: false, Message: err.Error()}
+		}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	start := time.Now()
+	page, err := reader.GetTableData(ctx, dbName, tableName, options)
+	if err != nil {
+		a.Logger().Error("DBGetTableData 读取表数据失败", "error", err, "table", tableName, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{
+		Success:      true,
+		Message:      "获取表数据成功",
+		Data:         page,
+		Fields:       page.Columns,
+		DurationMs:   time.Since(start).Milliseconds(),
+		RowsReturned: len(page.Rows),
+	}
+}
+
+// checkComputedColumns 按缓存的列信息预先校验计算列表达式，无需访问数据库即可拒绝引用不存在列的表达式。
+// 缓存不可用或没有该表时跳过，读取表数据时仍会按实时列定义再次校验。
+func (a *DatabaseService) checkComputedColumns(runConfig *connection.ConnectionConfig, dbName, tableName string, computed []connection.ComputedColumn) error {
+	schema := a.lintSchema(runConfig, dbName)
+	if schema == nil {
+		return nil
+	}
+	columns, ok := schema.Columns(tableName)
+	if !ok {
+		return nil
+	}
+	postgres := db.IsPostgresDialect(runConfig.Type)
+	for _, c := range computed {
+		if _, err := sqllint.CheckExpression(c.Expression, postgres, func(name string) bool {
+			_, ok := columns[strings.ToLower(name)]
+			return ok
+		}); err != nil {
+			return fmt.Errorf("计算列 %s 无效：%w", c.Name, err)
+		}
+	}
+	return nil
+}
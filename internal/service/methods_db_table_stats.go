@@ -0,0 +1,45 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// DBGetTableStats 获取库内各表的估算行数、数据与索引大小及最近更新时间，按总大小倒序。
+func (a *DatabaseService) DBGetTableStats(config *connection.ConnectionConfig, dbName string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).Err(); err != nil {
+		return a.invalidArgs("DBGetTableStats", err)
+	}
+
+	runConfig := normalizeRunConfig(config, dbName)
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		a.Logger().Error("DBGetTableStats 获取连接失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	reader, ok := dbInst.(db.TableStatsReader)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "当前数据库不支持表统计"}
+	}
+
+	stats, err := reader.GetTableStats(dbName)
+	if err != nil {
+		a.Logger().Error("DBGetTableStats 读取表统计失败", "error", err, "summary", db.FormatConnSummary(runConfig))
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "获取表统计成功", Data: stats}
+}
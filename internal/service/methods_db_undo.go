@@ -0,0 +1,282 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/google/uuid"
+)
+
+// 撤销记录的保留策略。
+const (
+	defaultUndoWindow    = 10 * time.Minute
+	maxUndoWindowSeconds = 24 * 60 * 60
+	maxUndoEntries       = 50
+)
+
+// changeUndoEntry 是一次 ApplyChanges 的撤销记录。
+type changeUndoEntry struct {
+	ID        string                `json:"id"`        // 撤销记录 ID
+	Table     string                `json:"table"`     // 表名
+	Inserts   int                   `json:"inserts"`   // 撤销时插入的行数（原删除）
+	Updates   int                   `json:"updates"`   // 撤销时还原的行数（原更新）
+	Deletes   int                   `json:"deletes"`   // 撤销时删除的行数（原插入）
+	Skipped   int                   `json:"skipped"`   // 无法撤销的插入行数
+	AppliedAt time.Time             `json:"appliedAt"` // 原更改的应用时间
+	ExpiresAt time.Time             `json:"expiresAt"` // 撤销截止时间
+	key       string                // 连接、数据库与表的组合键
+	inverse   *connection.ChangeSet // 撤销变更集
+}
+
+// changeUndoLog 按表保存最近的撤销记录，超过撤销时限的记录不再可用。
+type changeUndoLog struct {
+	mu       sync.Mutex
+	window   time.Duration // 撤销时限，0 表示默认值
+	disabled bool          // 是否关闭撤销
+	entries  []*changeUndoEntry
+}
+
+// enabled 判断是否记录撤销信息。
+func (l *changeUndoLog) enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.disabled
+}
+
+// windowLocked 返回当前撤销时限，调用方需持有锁。
+func (l *changeUndoLog) windowLocked() time.Duration {
+	if l.window <= 0 {
+		return defaultUndoWindow
+	}
+	return l.window
+}
+
+// setWindow 设置撤销时限（秒），0 表示关闭撤销并清空已有记录。
+func (l *changeUndoLog) setWindow(seconds int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.disabled = seconds == 0
+	l.window = time.Duration(seconds) * time.Second
+	if l.disabled {
+		l.entries = nil
+	}
+}
+
+// windowSeconds 返回当前撤销时限（秒），已关闭时为 0。
+func (l *changeUndoLog) windowSeconds() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.disabled {
+		return 0
+	}
+	return int(l.windowLocked() / time.Second)
+}
+
+// push 保存撤销记录并清理过期记录，超过上限时丢弃最早的记录。
+func (l *changeUndoLog) push(e *changeUndoEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.ExpiresAt = e.AppliedAt.Add(l.windowLocked())
+	kept := l.entries[:0]
+	for _, old := range l.entries {
+		if time.Now().Before(old.ExpiresAt) {
+			kept = append(kept, old)
+		}
+	}
+	l.entries = append(kept, e)
+	if len(l.entries) > maxUndoEntries {
+		l.entries = l.entries[len(l.entries)-maxUndoEntries:]
+	}
+}
+
+// latest 返回指定表最近一条未过期的撤销记录。
+func (l *changeUndoLog) latest(key string) *changeUndoEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if e := l.entries[i]; e.key == key {
+			if now.Before(e.ExpiresAt) {
+				return e
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// remove 删除撤销记录。
+func (l *changeUndoLog) remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.entries {
+		if e.ID == id {
+			l.entries = append(l.entries[:i], l.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// undoKey 返回撤销记录的表级组合键。
+func undoKey(config *connection.ConnectionConfig, dbName, tableName string) string {
+	return schemaCacheKey(config, dbName) + "/" + tableName
+}
+
+// undoCapture 是生成撤销记录所需的键与原行映像，原行在应用更改的事务内读取。
+type undoCapture struct {
+	keyColumns []string
+	columns    []*connection.ColumnDefinition
+	changes    *connection.ChangeSet
+	updatePre  []map[string]interface{}
+	deletePre  []map[string]interface{}
+}
+
+// newUndoCapture 准备撤销记录的定位列；关闭撤销时返回 nil。无键表以全部列定位。
+func (a *DatabaseService) newUndoCapture(key *connection.TableKey, columns []*connection.ColumnDefinition, changes *connection.ChangeSet) *undoCapture {
+	if !a.changeUndo.enabled() {
+		return nil
+	}
+	keyColumns := key.Columns
+	if key.Kind == db.TableKeyNone {
+		keyColumns = make([]string, len(columns))
+		for i, col := range columns {
+			keyColumns[i] = col.Name
+		}
+	}
+	return &undoCapture{keyColumns: keyColumns, columns: columns, changes: changes}
+}
+
+// setImages 记录事务内读取的原行映像，原值不完整时返回原因。
+func (c *undoCapture) setImages(images *db.ChangeImages) error {
+	if images == nil {
+		return fmt.Errorf("未读取原行")
+	}
+	if images.Err != nil {
+		return images.Err
+	}
+	c.updatePre, c.deletePre = images.Updates, images.Deletes
+	return nil
+}
+
+// entry 生成撤销记录，failed 中的变更（部分提交时未生效）不参与撤销；没有可撤销的变更时返回 nil。
+func (c *undoCapture) entry(runConfig *connection.ConnectionConfig, tableName string, failed []*connection.ApplyChangeError) (*changeUndoEntry, error) {
+	skip := make(map[string]bool, len(failed))
+	for _, f := range failed {
+		skip[fmt.Sprintf("%s/%d", f.Kind, f.Index)] = true
+	}
+	applied := &connection.ChangeSet{}
+	var updatePre, deletePre []map[string]interface{}
+	for i, row := range c.changes.Inserts {
+		if !skip[fmt.Sprintf("%s/%d", connection.ChangeKindInsert, i)] {
+			applied.Inserts = append(applied.Inserts, row)
+		}
+	}
+	for i, update := range c.changes.Updates {
+		if !skip[fmt.Sprintf("%s/%d", connection.ChangeKindUpdate, i)] {
+			applied.Updates = append(applied.Updates, update)
+			updatePre = append(updatePre, c.updatePre[i])
+		}
+	}
+	for i, del := range c.changes.Deletes {
+		if !skip[fmt.Sprintf("%s/%d", connection.ChangeKindDelete, i)] {
+			applied.Deletes = append(applied.Deletes, del)
+			deletePre = append(deletePre, c.deletePre[i])
+		}
+	}
+
+	inverse, skipped, err := db.InvertChangeSet(c.keyColumns, applied, updatePre, deletePre)
+	if err != nil {
+		return nil, err
+	}
+	// 删除的逆操作按原行重新插入，原行映像包含生成列的值，写回前需去掉
+	inverse = db.SkipGeneratedColumns(c.columns, inverse)
+	if len(inverse.Inserts)+len(inverse.Updates)+len(inverse.Deletes) == 0 {
+		return nil, nil
+	}
+	return &changeUndoEntry{
+		ID:      uuid.New().String(),
+		Table:   tableName,
+		Inserts: len(inverse.Inserts),
+		Updates: len(inverse.Updates),
+		Deletes: len(inverse.Deletes),
+		Skipped: skipped,
+		key:     undoKey(runConfig, runConfig.Database, tableName),
+		inverse: inverse,
+	}, nil
+}
+
+// UndoLastChange 撤销指定表最近一次通过 ApplyChanges 提交且仍在撤销时限内的更改。
+// 撤销按键定位行，行已被其他操作删除或修改键时整体回滚并返回失败。
+func (a *DatabaseService) UndoLastChange(config *connection.ConnectionConfig, dbName, tableName string) *connection.QueryResult {
+	if err := validateTableArgs(config, dbName, tableName).Err(); err != nil {
+		return a.invalidArgs("UndoLastChange", err)
+	}
+
+	runConfig := cloneConfigWithDatabase(config, dbName)
+	entry := a.changeUndo.latest(undoKey(runConfig, runConfig.Database, tableName))
+	if entry == nil {
+		return &connection.QueryResult{Success: false, Message: "没有可撤销的更改（已撤销或超过撤销时限）"}
+	}
+	dbInst, err := a.getDatabase(runConfig)
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	applier, ok := dbInst.(db.BatchApplier)
+	if !ok {
+		return &connection.QueryResult{Success: false, Message: "数据库不支持批量更改"}
+	}
+
+	start := time.Now()
+	err = applier.ApplyChanges(tableName, entry.inverse)
+	record := newAuditEntry(audit.FeatureUndoChanges, runConfig, dbName, start, err)
+	record.Table = tableName
+	record.SQL = changeSetSummary(entry.inverse)
+	if err == nil {
+		record.AffectedRows = int64(entry.Inserts + entry.Updates + entry.Deletes)
+	}
+	a.Audit(record)
+	if err != nil {
+		a.Logger().Error("UndoLastChange 撤销失败", "table", tableName, "error", err)
+		return &connection.QueryResult{Success: false, Message: "撤销失败，数据可能已被修改：" + err.Error()}
+	}
+	a.changeUndo.remove(entry.ID)
+
+	message := "已撤销最近一次更改"
+	if entry.Skipped > 0 {
+		message += fmt.Sprintf("（%d 行插入缺少键值，未能撤销）", entry.Skipped)
+	}
+	return &connection.QueryResult{Success: true, Message: message, Data: entry}
+}
+
+// SetChangeUndoWindow 设置 ApplyChanges 的撤销时限（秒），0 表示关闭撤销并清空已有撤销记录。
+func (a *DatabaseService) SetChangeUndoWindow(seconds int) *connection.QueryResult {
+	if err := validate.New().Range("seconds", seconds, 0, maxUndoWindowSeconds).Err(); err != nil {
+		return a.invalidArgs("SetChangeUndoWindow", err)
+	}
+	a.changeUndo.setWindow(seconds)
+	return &connection.QueryResult{Success: true, Message: "撤销时限已更新", Data: map[string]int{"windowSeconds": a.changeUndo.windowSeconds()}}
+}
+
+// GetChangeUndoWindow 获取 ApplyChanges 的撤销时限（秒），0 表示已关闭撤销。
+func (a *DatabaseService) GetChangeUndoWindow() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取撤销时限成功", Data: map[string]int{"windowSeconds": a.changeUndo.windowSeconds()}}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/querywatch"
+	"github.com/chenyang-zz/boxify/internal/resultdiff"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// 监视查询每次读取的行数上限。
+const watchMaxRows = 10000
+
+// DBWatchQuery 按 intervalSeconds 间隔重复执行查询，首次推送完整结果，之后只在结果变化时通过
+// query-watch:update 事件推送新增、删除与变更的行。keyColumns 用于匹配行，为空时按整行内容匹配。
+// 连续失败多次后监视自动停止；返回的 Data 为监视信息，其 id 用于 DBStopWatchQuery。
+func (a *DatabaseService) DBWatchQuery(config *connection.ConnectionConfig, dbName, query string, intervalSeconds int, keyColumns []string) *connection.QueryResult {
+	if err := validateDatabaseArgs(config, dbName).
+		Required("query", query).
+		Check(isCursorQuery(query), "query", validate.CodeNotAllowed, "监视仅支持 SELECT 类查询").
+		Range("intervalSeconds", intervalSeconds, int(querywatch.MinInterval/time.Second), int(querywatch.MaxInterval/time.Second)).
+		Identifiers("keyColumns", keyColumns).Err(); err != nil {
+		return a.invalidArgs("DBWatchQuery", err)
+	}
+
+	side := &connection.DiffQuerySide{Config: config, DBName: dbName, Query: query}
+	info, err := a.watchManager().Start(func(ctx context.Context) (resultdiff.Input, bool, error) {
+		data, err := a.readQueryInput(ctx, "DBWatchQuery", side, watchMaxRows)
+		if err != nil {
+			return resultdiff.Input{}, false, err
+		}
+		return data.input, data.truncated, nil
+	}, querywatch.Options{
+		Label:      sqlSnippet(query),
+		Interval:   time.Duration(intervalSeconds) * time.Second,
+		KeyColumns: keyColumns,
+	})
+	if err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "已开始监视查询", Data: info}
+}
+
+// DBStopWatchQuery 停止监视，执行中的查询会被取消。
+func (a *DatabaseService) DBStopWatchQuery(watchID string) *connection.QueryResult {
+	if err := validate.New().Required("watchId", watchID).Err(); err != nil {
+		return a.invalidArgs("DBStopWatchQuery", err)
+	}
+	if err := a.watchManager().Stop(watchID); err != nil {
+		return &connection.QueryResult{Success: false, Message: err.Error()}
+	}
+	return &connection.QueryResult{Success: true, Message: "已停止监视"}
+}
+
+// DBListWatchQueries 列出运行中的监视。
+func (a *DatabaseService) DBListWatchQueries() *connection.QueryResult {
+	return &connection.QueryResult{Success: true, Message: "获取监视列表成功", Data: a.watchManager().List()}
+}
+
+// watchManager 返回查询监视管理器，未初始化时懒加载。
+func (a *DatabaseService) watchManager() *querywatch.Manager {
+	if a.watches == nil {
+		a.watches = querywatch.NewManager(a.Logger())
+	}
+	return a.watches
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/metrics"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// MetricsService 为本地指标面板提供查询耗时、导出吞吐与终端输出速率等指标，核心逻辑在 internal/metrics。
+//
+// 指标默认关闭，由设置中的 metrics 开关控制，数据只保留在进程内，不会上传。
+type MetricsService struct {
+	BaseService
+}
+
+// NewMetricsService 创建指标服务
+func NewMetricsService(deps *ServiceDeps) *MetricsService {
+	return &MetricsService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动
+func (s *MetricsService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *MetricsService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// GetMetrics 获取全部指标的快照；指标关闭时返回关闭前已采集的数据。
+func (s *MetricsService) GetMetrics() *types.MetricsResult {
+	snapshot := metrics.Default.Snapshot()
+	return &types.MetricsResult{BaseResult: types.BaseResult{Success: true, Message: "获取指标成功"}, Data: &snapshot}
+}
+
+// ResetMetrics 清空已采集的指标并重新开始计时。
+func (s *MetricsService) ResetMetrics() *types.BaseResult {
+	metrics.Default.Reset()
+	s.Logger().Info("指标已重置")
+	return &types.BaseResult{Success: true, Message: "指标已重置"}
+}
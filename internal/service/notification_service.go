@@ -0,0 +1,173 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/notify"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+// NotificationService 封装 Wails 通知服务，将导出/导入完成、定时任务失败、连接中断等事件发送为系统通知。
+//
+// 各服务通过共享的 notify.Center（BaseService.Notify）提交通知，本服务负责注入发送端、
+// 加载用户设置，并在用户点击通知时聚焦窗口、通过 notification:clicked 事件回传附加数据。
+type NotificationService struct {
+	BaseService
+	native       *notifications.NotificationService
+	settingsPath string
+}
+
+// NewNotificationService 创建系统通知服务
+func NewNotificationService(deps *ServiceDeps) *NotificationService {
+	return &NotificationService{
+		BaseService:  NewBaseService(deps),
+		native:       notifications.New(),
+		settingsPath: notify.DefaultSettingsPath(),
+	}
+}
+
+// ServiceStartup 服务启动，初始化系统通知并加载设置；系统通知不可用时仅记录日志，不影响应用启动
+func (s *NotificationService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	center := s.Notifier()
+	if center == nil {
+		return nil
+	}
+	settings, err := notify.LoadSettings(s.settingsPath)
+	if err != nil {
+		s.Logger().Warn("加载通知设置失败，使用默认设置", "error", err)
+	}
+	center.SetSettings(settings)
+
+	if err := s.native.ServiceStartup(ctx, options); err != nil {
+		s.Logger().Warn("系统通知初始化失败", "error", err)
+		return nil
+	}
+	s.native.OnNotificationResponse(s.onResponse)
+	center.SetForegroundCheck(s.appFocused)
+	center.SetSender(func(n notify.Notification) error {
+		data := map[string]interface{}{"category": string(n.Category)}
+		for k, v := range n.Data {
+			data[k] = v
+		}
+		return s.native.SendNotification(notifications.NotificationOptions{
+			ID:    uuid.NewString(),
+			Title: n.Title,
+			Body:  n.Body,
+			Data:  data,
+		})
+	})
+	s.Logger().Info("服务启动", "service", "NotificationService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭，停止发送通知
+func (s *NotificationService) ServiceShutdown() error {
+	s.Logger().Info("服务开始关闭，准备释放资源", "service", "NotificationService")
+	if center := s.Notifier(); center != nil {
+		center.SetSender(nil)
+		center.SetForegroundCheck(nil)
+	}
+	if err := s.native.ServiceShutdown(); err != nil {
+		s.Logger().Warn("关闭系统通知失败", "error", err)
+	}
+	s.Logger().Info("服务关闭", "service", "NotificationService")
+	return nil
+}
+
+// GetNotificationSettings 获取通知设置。
+func (s *NotificationService) GetNotificationSettings() *types.NotificationSettingsResult {
+	center := s.Notifier()
+	if center == nil {
+		return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: false, Message: "通知中心未初始化"}}
+	}
+	settings := center.Settings()
+	return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: true, Message: "获取通知设置成功"}, Data: &settings}
+}
+
+// SaveNotificationSettings 保存通知设置并立即生效。
+func (s *NotificationService) SaveNotificationSettings(settings *notify.Settings) *types.NotificationSettingsResult {
+	center := s.Notifier()
+	if center == nil {
+		return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: false, Message: "通知中心未初始化"}}
+	}
+	if settings == nil {
+		return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: false, Message: "settings 不能为空"}}
+	}
+	if err := notify.SaveSettings(s.settingsPath, *settings); err != nil {
+		s.Logger().Error("保存通知设置失败", "error", err)
+		return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	center.SetSettings(*settings)
+	saved := center.Settings()
+	return &types.NotificationSettingsResult{BaseResult: types.BaseResult{Success: true, Message: "保存通知设置成功"}, Data: &saved}
+}
+
+// RequestNotificationPermission 请求系统通知授权（macOS 首次使用时弹出授权提示），返回是否已授权。
+func (s *NotificationService) RequestNotificationPermission() *types.BaseResult {
+	granted, err := s.native.RequestNotificationAuthorization()
+	if err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if !granted {
+		return &types.BaseResult{Success: false, Message: "系统通知未授权"}
+	}
+	return &types.BaseResult{Success: true, Message: "系统通知已授权"}
+}
+
+// SendTestNotification 忽略设置直接发送一条测试通知，用于确认系统通知可用。
+func (s *NotificationService) SendTestNotification() *types.BaseResult {
+	err := s.native.SendNotification(notifications.NotificationOptions{
+		ID:    uuid.NewString(),
+		Title: "Boxify",
+		Body:  "系统通知已启用",
+	})
+	if err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "测试通知已发送"}
+}
+
+// appFocused 判断是否有应用窗口处于焦点。
+func (s *NotificationService) appFocused() bool {
+	for _, w := range s.App().Window.GetAll() {
+		if w.IsFocused() {
+			return true
+		}
+	}
+	return false
+}
+
+// onResponse 用户点击通知后聚焦窗口，并将通知附加数据推送给前端。
+func (s *NotificationService) onResponse(result notifications.NotificationResult) {
+	if result.Error != nil {
+		s.Logger().Warn("处理通知响应失败", "error", result.Error)
+		return
+	}
+	if w := s.App().Window.Current(); w != nil {
+		w.Show()
+		w.Focus()
+	} else if all := s.App().Window.GetAll(); len(all) > 0 {
+		all[0].Show()
+		all[0].Focus()
+	}
+	s.EmitEvent(string(events.EventTypeNotificationClicked), result.Response.UserInfo)
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/recents"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// RecentsService 管理最近使用的连接、数据表与 SQL 文件，核心逻辑在 internal/recents。
+//
+// 前端在连接、打开表或执行文件时调用 RecordRecent，启动页通过 GetRecents 一次取得全部分类；
+// 记录变化后广播 recents:changed 事件。
+type RecentsService struct {
+	BaseService
+	store *recents.Store
+}
+
+// NewRecentsService 创建最近使用服务
+func NewRecentsService(deps *ServiceDeps) *RecentsService {
+	s := &RecentsService{BaseService: NewBaseService(deps)}
+	s.store = deps.Recents()
+	if s.store != nil {
+		s.store.OnChange(func(kind recents.Kind) {
+			s.EmitEvent(string(events.EventTypeRecentsChanged), types.RecentsChangedEvent{Kind: kind})
+		})
+	}
+	return s
+}
+
+// ServiceStartup 服务启动
+func (s *RecentsService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *RecentsService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// GetRecents 获取启动页使用的最近连接、数据表与文件，每类最多 limit 条（<= 0 表示不限制）。
+func (s *RecentsService) GetRecents(limit int) *types.RecentsResult {
+	if s.store == nil {
+		return &types.RecentsResult{BaseResult: types.BaseResult{Success: false, Message: "最近使用记录未初始化"}}
+	}
+	overview := s.store.Overview(limit)
+	return &types.RecentsResult{BaseResult: types.BaseResult{Success: true, Message: "获取最近使用记录成功"}, Data: &overview}
+}
+
+// ListRecents 获取单类最近使用记录，置顶的在前。
+func (s *RecentsService) ListRecents(kind recents.Kind, limit int) *types.RecentListResult {
+	if err := validate.New().Check(recents.ValidKind(kind) == nil, "kind", validate.CodeInvalid, "kind 只能是 connection、table 或 file").Err(); err != nil {
+		return &types.RecentListResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.store == nil {
+		return &types.RecentListResult{BaseResult: types.BaseResult{Success: false, Message: "最近使用记录未初始化"}}
+	}
+	return &types.RecentListResult{BaseResult: types.BaseResult{Success: true, Message: "获取最近使用记录成功"}, Data: s.store.List(kind, limit)}
+}
+
+// RecordRecent 记录一次使用，同一连接、数据表或文件重复使用时累加次数。
+func (s *RecentsService) RecordRecent(item *recents.Item) *types.RecentItemResult {
+	if err := validate.New().Check(item != nil, "item", validate.CodeRequired, "item 不能为空").Err(); err != nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.store == nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: "最近使用记录未初始化"}}
+	}
+	saved, err := s.store.Touch(*item)
+	if err != nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.RecentItemResult{BaseResult: types.BaseResult{Success: true, Message: "已记录"}, Data: saved}
+}
+
+// PinRecent 置顶或取消置顶记录。
+func (s *RecentsService) PinRecent(kind recents.Kind, key string, pinned bool) *types.RecentItemResult {
+	if err := validateRecentKey(kind, key); err != nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.store == nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: "最近使用记录未初始化"}}
+	}
+	saved, err := s.store.Pin(kind, key, pinned)
+	if err != nil {
+		return &types.RecentItemResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.RecentItemResult{BaseResult: types.BaseResult{Success: true, Message: "置顶状态已更新"}, Data: saved}
+}
+
+// RemoveRecent 删除一条记录。
+func (s *RecentsService) RemoveRecent(kind recents.Kind, key string) *types.BaseResult {
+	if err := validateRecentKey(kind, key); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if s.store == nil {
+		return &types.BaseResult{Success: false, Message: "最近使用记录未初始化"}
+	}
+	if err := s.store.Remove(kind, key); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "记录已删除"}
+}
+
+// ForgetConnection 删除与连接相关的全部记录，前端删除连接后调用。
+func (s *RecentsService) ForgetConnection(connectionID string) *types.BaseResult {
+	if err := validate.New().Required("connectionId", connectionID).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if s.store == nil {
+		return &types.BaseResult{Success: false, Message: "最近使用记录未初始化"}
+	}
+	if _, err := s.store.RemoveConnection(connectionID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "连接相关记录已删除"}
+}
+
+// ClearRecents 清除指定类型（为空时全部类型）的未置顶记录。
+func (s *RecentsService) ClearRecents(kind recents.Kind) *types.BaseResult {
+	if kind != "" {
+		if err := validate.New().Check(recents.ValidKind(kind) == nil, "kind", validate.CodeInvalid, "kind 只能是 connection、table 或 file").Err(); err != nil {
+			return &types.BaseResult{Success: false, Message: err.Error()}
+		}
+	}
+	if s.store == nil {
+		return &types.BaseResult{Success: false, Message: "最近使用记录未初始化"}
+	}
+	if err := s.store.Clear(kind); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "最近使用记录已清除"}
+}
+
+// validateRecentKey 校验记录类型与键。
+func validateRecentKey(kind recents.Kind, key string) error {
+	return validate.New().
+		Check(recents.ValidKind(kind) == nil, "kind", validate.CodeInvalid, "kind 只能是 connection、table 或 file").
+		Required("key", key).
+		Err()
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dataexport"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/report"
+	"github.com/chenyang-zz/boxify/internal/scheduler"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/utils"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ReportService 将一个或多个保存的查询渲染为 Markdown 或 HTML 报表，核心逻辑在 internal/report。
+//
+// 查询与连接配置由前端保存，生成时随报表定义传入；各查询按只读路由依次执行，
+// 失败的查询在报表中展示错误信息而不中断生成。
+type ReportService struct {
+	BaseService
+	manager *db.ConnectionManager
+}
+
+// NewReportService 创建报表服务
+func NewReportService(deps *ServiceDeps) *ReportService {
+	return &ReportService{
+		BaseService: NewBaseService(deps),
+		manager:     db.NewConnectionManager(deps.app.Logger),
+	}
+}
+
+// ServiceStartup 服务启动
+func (s *ReportService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭，释放数据库连接
+func (s *ReportService) ServiceShutdown() error {
+	if err := s.manager.CloseAll(); err != nil {
+		s.Logger().Error("关闭数据库连接失败", "error", err)
+	}
+	return s.DefaultServiceShutdown()
+}
+
+// PreviewReport 执行报表中的查询并返回渲染后的内容，不写入文件。
+// 内容超过单个分块大小时（如内嵌大量图表）Content 为空，内容通过 TransferID 对应的分块传输流下发。
+func (s *ReportService) PreviewReport(def *report.Definition) *types.ReportResult {
+	file, content, res := s.render("PreviewReport", def)
+	if res != nil {
+		return res
+	}
+	s.attachPreviewContent(file, content)
+	return &types.ReportResult{BaseResult: types.BaseResult{Success: true, Message: reportMessage("报表已生成", file)}, Data: file}
+}
+
+// attachPreviewContent 将预览内容放入返回值，过大时改为打开分块传输流异步下发。
+// 流 ID 随返回值到达前端，分块须等前端以 AckTransfer(transferId, 0) 表示就绪后再发送，避免先于返回值到达。
+func (s *ReportService) attachPreviewContent(file *types.ReportFile, content []byte) {
+	streams := s.Streams()
+	if streams == nil || !streams.ShouldChunk(len(content)) {
+		file.Content = string(content)
+		return
+	}
+
+	stream := streams.Open(string(events.EventTypeReportPreviewChunk), map[string]interface{}{"format": file.Format})
+	file.TransferID = stream.ID()
+	go func() {
+		defer stream.Close()
+		err := stream.WaitReady(s.Context())
+		if err == nil {
+			err = stream.Write(s.Context(), content, nil)
+		}
+		if err != nil {
+			s.Logger().Warn("分块下发报表预览失败", "size", len(content), "streamId", stream.ID(), "error", err)
+		}
+	}()
+}
+
+// GenerateReport 执行报表中的查询并将报表写入 OutputPath；未设置输出路径时弹出保存对话框。
+func (s *ReportService) GenerateReport(def *report.Definition) *types.ReportResult {
+	file, content, res := s.render("GenerateReport", def)
+	if res != nil {
+		return res
+	}
+
+	now := time.Now()
+	filename := scheduler.ExpandPath(def.OutputPath, now)
+	if filename == "" {
+		var err error
+		filename, err = runtime.SaveFileDialog(s.Context(), runtime.SaveDialogOptions{
+			Title:           "保存报表",
+			DefaultFilename: fmt.Sprintf("report-%s.%s", now.Format("20060102"), def.Extension()),
+		})
+		if err != nil || filename == "" {
+			return &types.ReportResult{BaseResult: types.BaseResult{Success: false, Message: "Cancelled"}}
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return &types.ReportResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("创建报表目录失败：%s", err.Error())}}
+	}
+	if err := os.WriteFile(filename, content, 0o644); err != nil {
+		s.Logger().Error("写入报表失败", "path", filename, "error", err)
+		return &types.ReportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	file.Path = filename
+	s.Logger().Info("报表已生成", "path", filename, "sections", file.Sections, "failed", file.FailedSections)
+	return &types.ReportResult{BaseResult: types.BaseResult{Success: true, Message: reportMessage("报表已生成", file)}, Data: file}
+}
+
+// render 校验报表定义、依次执行各节查询并渲染报表；失败时返回错误结果。
+func (s *ReportService) render(method string, def *report.Definition) (*types.ReportFile, []byte, *types.ReportResult) {
+	if err := validateReportDefinition(def); err != nil {
+		s.Logger().Warn(method+" 参数校验失败", "error", err)
+		return nil, nil, &types.ReportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	results := make([]*report.SectionResult, len(def.Sections))
+	failed := 0
+	for i, section := range def.Sections {
+		results[i] = s.runSection(section, def.RowLimit())
+		if results[i].Err != nil {
+			failed++
+			s.Logger().Warn(method+" 查询失败", "section", i+1, "error", results[i].Err, "snippet", sqlSnippet(section.Query.SQL))
+		}
+	}
+
+	content, err := report.Render(def, results, time.Now())
+	if err != nil {
+		return nil, nil, &types.ReportResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.ReportFile{
+		Format:         def.Format,
+		Size:           int64(len(content)),
+		Sections:       len(def.Sections),
+		FailedSections: failed,
+	}, content, nil
+}
+
+// runSection 执行一节的查询，读取至多 maxRows 行，多出的行用于判断结果是否被截断。
+func (s *ReportService) runSection(section *report.Section, maxRows int) *report.SectionResult {
+	runConfig := normalizeRunConfig(section.Connection, section.Query.Database)
+	query, err := dataexport.BuildExportQuery(&connection.ExportOptions{Query: section.Query.SQL, Limit: maxRows + 1}, db.NewSQLBuilder(runConfig.Type))
+	if err != nil {
+		return &report.SectionResult{Err: err}
+	}
+	dbInst, err := s.manager.GetForRead(runConfig, false)
+	if err != nil {
+		return &report.SectionResult{Err: fmt.Errorf("连接数据库失败：%w", err)}
+	}
+
+	timeoutSeconds := runConfig.Timeout
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 30
+	}
+	ctx, cancel := utils.ContextWithTimeout(time.Duration(timeoutSeconds) * time.Second)
+	defer cancel()
+
+	query = sanitizeSQLForPgLike(runConfig.Type, query)
+	var (
+		data    []map[string]interface{}
+		columns []string
+	)
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, columns, err = q.QueryContext(ctx, query)
+	} else {
+		data, columns, err = dbInst.Query(query)
+	}
+	if err != nil {
+		return &report.SectionResult{Err: err}
+	}
+
+	result := &report.SectionResult{Columns: columns, Rows: data}
+	if len(data) > maxRows {
+		result.Rows = data[:maxRows]
+		result.Truncated = true
+	}
+	return result
+}
+
+// validateReportDefinition 校验报表定义与各节的连接配置；报表只执行 SELECT 类查询。
+func validateReportDefinition(def *report.Definition) error {
+	v := validate.New().Check(def != nil, "definition", validate.CodeRequired, "报表定义不能为空")
+	if def == nil {
+		return v.Err()
+	}
+	if err := def.Validate(); err != nil {
+		return v.Check(false, "definition", validate.CodeInvalid, err.Error()).Err()
+	}
+	for i, section := range def.Sections {
+		field := fmt.Sprintf("definition.sections[%d]", i)
+		v.ConnectionConfig(field+".connection", section.Connection).
+			OptionalIdentifier(field+".query.database", section.Query.Database).
+			Check(isCursorQuery(section.Query.SQL), field+".query.sql", validate.CodeNotAllowed, "报表仅支持 SELECT 类查询")
+	}
+	return v.Err()
+}
+
+// reportMessage 在消息后附加失败查询数。
+func reportMessage(message string, file *types.ReportFile) string {
+	if file.FailedSections > 0 {
+		return fmt.Sprintf("%s，%d 个查询执行失败", message, file.FailedSections)
+	}
+	return message
+}
@@ -0,0 +1,330 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/audit"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/datatransfer"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/dbsnapshot"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/notify"
+	"github.com/chenyang-zz/boxify/internal/scheduler"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// SchedulerService 在应用运行期间按 cron 表达式执行保存的查询、导出或备份，核心逻辑在 internal/scheduler。
+//
+// 每次执行结束通过 scheduler:run 事件推送执行记录，失败时额外发送系统通知。
+// 备份任务将数据库保存为本地快照归档（与手动快照共用存储），并按任务的保留策略清理过期备份。
+// 任务不持久化密码，应用重启后需通过 SetScheduledTaskCredentials 重新提供。
+type SchedulerService struct {
+	BaseService
+	manager   *db.ConnectionManager
+	scheduler *scheduler.Scheduler
+	snapshots *dbsnapshot.Store
+	cancel    context.CancelFunc
+}
+
+// NewSchedulerService 创建定时任务服务
+func NewSchedulerService(deps *ServiceDeps) *SchedulerService {
+	s := &SchedulerService{
+		BaseService: NewBaseService(deps),
+		manager:     db.NewConnectionManager(deps.app.Logger),
+		snapshots:   dbsnapshot.NewStore("", deps.app.Logger),
+	}
+	s.scheduler = scheduler.New(scheduler.DefaultPath(), s.run, deps.app.Logger)
+	return s
+}
+
+// ServiceStartup 服务启动，加载任务并开始调度
+func (s *SchedulerService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	if err := s.scheduler.Load(); err != nil {
+		s.Logger().Warn("加载定时任务失败", "error", err)
+	}
+	s.scheduler.SetListener(s.onRun)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.scheduler.Start(runCtx, 0)
+	s.Logger().Info("服务启动", "service", "SchedulerService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭，停止调度并等待执行中的任务返回
+func (s *SchedulerService) ServiceShutdown() error {
+	s.Logger().Info("服务开始关闭，准备释放资源", "service", "SchedulerService")
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.scheduler.Wait()
+	if err := s.manager.CloseAll(); err != nil {
+		s.Logger().Error("关闭数据库连接失败", "error", err)
+	}
+	s.Logger().Info("服务关闭", "service", "SchedulerService")
+	return nil
+}
+
+// ListScheduledTasks 列出全部定时任务。
+func (s *SchedulerService) ListScheduledTasks() *types.ScheduledTaskListResult {
+	return &types.ScheduledTaskListResult{BaseResult: types.BaseResult{Success: true, Message: "获取定时任务成功"}, Data: s.scheduler.List()}
+}
+
+// SaveScheduledTask 新建或更新定时任务；连接密码只保存在内存中。
+func (s *SchedulerService) SaveScheduledTask(task *scheduler.Task) *types.ScheduledTaskResult {
+	v := validate.New().Check(task != nil, "task", validate.CodeRequired, "task 不能为空")
+	if task != nil {
+		v.ConnectionConfig("task.connection", task.Connection).
+			OptionalIdentifier("task.database", task.Database)
+		if task.Backup != nil {
+			v.Identifiers("task.backup.tables", task.Backup.Tables).
+				OptionalIdentifier("task.backup.verifyDatabase", task.Backup.VerifyDatabase).
+				Check(task.Backup.VerifyDatabase == "" || task.Backup.VerifyDatabase != task.Database,
+					"task.backup.verifyDatabase", validate.CodeNotAllowed, "校验数据库不能是被备份的数据库")
+		}
+	}
+	if err := v.Err(); err != nil {
+		s.Logger().Warn("SaveScheduledTask 参数校验失败", "error", err)
+		return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	saved, err := s.scheduler.Save(task)
+	if err != nil {
+		return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: true, Message: "保存定时任务成功"}, Data: saved}
+}
+
+// DeleteScheduledTask 删除定时任务。
+func (s *SchedulerService) DeleteScheduledTask(id string) *types.BaseResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.scheduler.Delete(id); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "删除定时任务成功"}
+}
+
+// SetScheduledTaskEnabled 启用或停用定时任务。
+func (s *SchedulerService) SetScheduledTaskEnabled(id string, enabled bool) *types.ScheduledTaskResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	task, err := s.scheduler.SetEnabled(id, enabled)
+	if err != nil {
+		return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.ScheduledTaskResult{BaseResult: types.BaseResult{Success: true, Message: "更新定时任务成功"}, Data: task}
+}
+
+// SetScheduledTaskCredentials 为任务提供连接密码，仅保存在内存中；配置须指向任务记录的同一服务器。
+func (s *SchedulerService) SetScheduledTaskCredentials(id string, config *connection.ConnectionConfig) *types.BaseResult {
+	if err := validate.New().Required("id", id).ConnectionConfig("config", config).Err(); err != nil {
+		s.Logger().Warn("SetScheduledTaskCredentials 参数校验失败", "error", err)
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.scheduler.SetCredentials(id, config); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "已设置连接凭据"}
+}
+
+// RunScheduledTaskNow 立即在后台执行一次任务，结果通过 scheduler:run 事件推送。
+func (s *SchedulerService) RunScheduledTaskNow(id string) *types.BaseResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.scheduler.RunNow(id); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "任务已开始执行"}
+}
+
+// GetScheduledTaskHistory 返回执行记录（最新在前）；id 为空时返回全部任务的记录，limit <= 0 表示不限制。
+func (s *SchedulerService) GetScheduledTaskHistory(id string, limit int) *types.ScheduledRunListResult {
+	return &types.ScheduledRunListResult{BaseResult: types.BaseResult{Success: true, Message: "获取执行记录成功"}, Data: s.scheduler.History(id, limit)}
+}
+
+// onRun 推送执行记录，失败时发送系统通知。
+func (s *SchedulerService) onRun(task scheduler.Task, run scheduler.Run) {
+	s.EmitEvent(string(events.EventTypeSchedulerRun), run)
+	if run.Status != scheduler.RunFailed {
+		return
+	}
+	s.Notify(notify.Notification{
+		Category: notify.CategoryScheduler,
+		Title:    "定时任务执行失败：" + task.Name,
+		Body:     run.Message,
+		Data:     map[string]interface{}{"taskId": task.ID},
+	})
+}
+
+// run 执行单个定时任务：查询任务执行 SQL，导出任务将查询结果写入文件，备份任务创建快照归档。
+func (s *SchedulerService) run(ctx context.Context, task *scheduler.Task, config *connection.ConnectionConfig) (*scheduler.RunResult, error) {
+	runConfig := normalizeRunConfig(config, task.Database)
+	dbInst, err := s.manager.Get(runConfig, false)
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败：%w", err)
+	}
+	if task.Kind == scheduler.KindBackup {
+		return s.runBackup(ctx, task, config, runConfig, dbInst)
+	}
+	query := sanitizeSQLForPgLike(runConfig.Type, task.Query)
+
+	if task.Kind == scheduler.KindQuery && !isCursorQuery(query) {
+		start := time.Now()
+		var affected int64
+		if e, ok := dbInst.(interface {
+			ExecContext(context.Context, string) (int64, error)
+		}); ok {
+			affected, err = e.ExecContext(ctx, query)
+		} else {
+			affected, err = dbInst.Exec(query)
+		}
+		entry := newAuditEntry(audit.FeatureScheduler, runConfig, task.Database, start, err)
+		entry.SQL = query
+		if err == nil {
+			entry.AffectedRows = affected
+		}
+		s.Audit(entry)
+		if err != nil {
+			return nil, err
+		}
+		return &scheduler.RunResult{Rows: affected, Message: fmt.Sprintf("执行成功，受影响的行数: %d", affected)}, nil
+	}
+
+	var (
+		data    []map[string]interface{}
+		columns []string
+	)
+	if q, ok := dbInst.(interface {
+		QueryContext(context.Context, string, ...any) ([]map[string]interface{}, []string, error)
+	}); ok {
+		data, columns, err = q.QueryContext(ctx, query)
+	} else {
+		data, columns, err = dbInst.Query(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if task.Kind == scheduler.KindQuery {
+		return &scheduler.RunResult{Rows: int64(len(data)), Message: fmt.Sprintf("查询成功，返回 %d 行", len(data))}, nil
+	}
+
+	filename := scheduler.ExpandPath(task.Export.Path, time.Now())
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return nil, fmt.Errorf("创建导出目录失败：%w", err)
+	}
+	exp := task.Export
+	formatOpts := exportFormatOptions(task.Name, exp.NullValue, exp.DateFormat, exp.Compression, exp.InferSchema)
+	if err := writeExportFile(filename, exp.Format, columns, data, formatOpts); err != nil {
+		return nil, fmt.Errorf("写入导出文件失败：%w", err)
+	}
+	return &scheduler.RunResult{Rows: int64(len(data)), File: filename, Message: fmt.Sprintf("导出完成，共 %d 行", len(data))}, nil
+}
+
+// runBackup 将数据库备份为快照归档，按设置校验归档并试恢复表结构，最后清理过期备份。
+// 校验或清理失败时返回错误，由 onRun 发送失败通知；此时备份归档仍会保留。
+func (s *SchedulerService) runBackup(ctx context.Context, task *scheduler.Task, config, runConfig *connection.ConnectionConfig, dbInst db.Database) (*scheduler.RunResult, error) {
+	settings := task.Backup
+	tables := settings.Tables
+	if len(tables) == 0 {
+		var err error
+		if tables, err = dbInst.GetTables(ctx, task.Database); err != nil {
+			return nil, fmt.Errorf("读取表列表失败：%w", err)
+		}
+		if len(tables) == 0 {
+			return nil, fmt.Errorf("数据库中没有可备份的表")
+		}
+	}
+	src, err := dbsnapshot.NewDBSource(dbInst, runConfig.Type, task.Database)
+	if err != nil {
+		return nil, err
+	}
+	version, err := db.ServerVersion(dbInst, runConfig.Type)
+	if err != nil {
+		s.Logger().Warn("备份任务读取服务器版本失败", "taskId", task.ID, "error", err)
+	}
+	m, err := s.snapshots.Capture(ctx, src, dbsnapshot.CaptureOptions{
+		Name:          fmt.Sprintf("%s %s", task.Name, time.Now().Format("2006-01-02 15:04")),
+		SourceType:    runConfig.Type,
+		ServerVersion: version,
+		Source:        db.FormatConnSummary(runConfig),
+		Database:      task.Database,
+		Tables:        tables,
+		TaskID:        task.ID,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("备份失败：%w", err)
+	}
+	var rows int64
+	for _, t := range m.Tables {
+		rows += t.RowCount
+	}
+	result := &scheduler.RunResult{Rows: rows, File: m.ID, Message: fmt.Sprintf("备份完成，共 %d 张表 %d 行", len(m.Tables), rows)}
+
+	if settings.Verify {
+		// 只在独立的校验数据库中试恢复表结构，避免在刚备份的数据库中建删临时表；未指定时只校验归档
+		var dst datatransfer.Target
+		var verifyConfig *connection.ConnectionConfig
+		var verifyType connection.ConnectionType
+		if settings.VerifyDatabase != "" && settings.VerifyDatabase != task.Database {
+			verifyConfig = normalizeRunConfig(config, settings.VerifyDatabase)
+			verifyInst, err := s.manager.Get(verifyConfig, false)
+			if err != nil {
+				return nil, fmt.Errorf("备份 %s 已保存，但连接校验数据库失败：%w", m.ID, err)
+			}
+			if dst, err = datatransfer.NewDBTarget(verifyInst); err != nil {
+				return nil, fmt.Errorf("备份 %s 已保存，但无法试恢复表结构：%w", m.ID, err)
+			}
+			verifyType = verifyConfig.Type
+		}
+		start := time.Now()
+		_, err := s.snapshots.Verify(ctx, m.ID, dst, verifyType)
+		if dst != nil {
+			entry := newAuditEntry(audit.FeatureScheduler, verifyConfig, settings.VerifyDatabase, start, err)
+			entry.SQL = fmt.Sprintf("VERIFY BACKUP %s (CREATE/DROP _verify_* TABLES)", m.ID)
+			s.Audit(entry)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("备份 %s 已保存，但校验失败：%w", m.ID, err)
+		}
+		if dst != nil {
+			result.Message += "，校验通过"
+		} else {
+			result.Message += "，归档校验通过（未指定校验数据库，跳过表结构试恢复）"
+		}
+	}
+
+	deleted, err := s.snapshots.Prune(task.ID, settings.KeepLast, settings.KeepWeekly)
+	if err != nil {
+		return nil, fmt.Errorf("备份 %s 已保存，但清理过期备份失败：%w", m.ID, err)
+	}
+	if len(deleted) > 0 {
+		result.Message += fmt.Sprintf("，已清理 %d 份过期备份", len(deleted))
+	}
+	return result, nil
+}
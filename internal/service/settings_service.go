@@ -0,0 +1,138 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/execqueue"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/metrics"
+	"github.com/chenyang-zz/boxify/internal/settings"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// SettingsService 管理应用设置（编辑器偏好、默认行数、日志级别、主题与操作确认），核心逻辑在 internal/settings。
+//
+// 设置变更后通过 settings:changed 事件广播到全部窗口，日志级别立即生效。
+type SettingsService struct {
+	BaseService
+	store *settings.Store
+}
+
+// NewSettingsService 创建应用设置服务
+func NewSettingsService(deps *ServiceDeps) *SettingsService {
+	return &SettingsService{
+		BaseService: NewBaseService(deps),
+		store:       deps.Settings(),
+	}
+}
+
+// ServiceStartup 服务启动，应用已保存的日志级别、指标开关、每连接并发上限与重试次数
+func (s *SettingsService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if s.store != nil {
+		current := s.store.Get()
+		logger.SetLevel(current.SlogLevel())
+		metrics.SetEnabled(current.Metrics)
+		execqueue.SetLimit(current.Query.MaxConcurrency)
+		db.SetRetryAttempts(current.Query.RetryAttempts)
+	}
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *SettingsService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// GetSettings 获取当前应用设置。
+func (s *SettingsService) GetSettings() *types.SettingsResult {
+	if s.store == nil {
+		return &types.SettingsResult{BaseResult: types.BaseResult{Success: false, Message: "应用设置未初始化"}}
+	}
+	current := s.store.Get()
+	return &types.SettingsResult{BaseResult: types.BaseResult{Success: true, Message: "获取应用设置成功"}, Data: &current}
+}
+
+// UpdateSettings 校验并保存应用设置，有变化时广播 settings:changed 事件。
+func (s *SettingsService) UpdateSettings(next *settings.Settings) *types.SettingsResult {
+	if err := validate.New().Check(next != nil, "settings", validate.CodeRequired, "settings 不能为空").Err(); err != nil {
+		return &types.SettingsResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.store == nil {
+		return &types.SettingsResult{BaseResult: types.BaseResult{Success: false, Message: "应用设置未初始化"}}
+	}
+	old, saved, err := s.store.Update(*next)
+	if err != nil {
+		return s.settingsFailed("保存应用设置失败", err)
+	}
+	s.applyChange(old, saved)
+	return &types.SettingsResult{BaseResult: types.BaseResult{Success: true, Message: "保存应用设置成功"}, Data: &saved}
+}
+
+// ResetSettings 恢复默认设置，有变化时广播 settings:changed 事件。
+func (s *SettingsService) ResetSettings() *types.SettingsResult {
+	if s.store == nil {
+		return &types.SettingsResult{BaseResult: types.BaseResult{Success: false, Message: "应用设置未初始化"}}
+	}
+	old, saved, err := s.store.Reset()
+	if err != nil {
+		return s.settingsFailed("恢复默认设置失败", err)
+	}
+	s.applyChange(old, saved)
+	return &types.SettingsResult{BaseResult: types.BaseResult{Success: true, Message: "已恢复默认设置"}, Data: &saved}
+}
+
+// applyChange 应用日志级别并广播变更
+func (s *SettingsService) applyChange(old, next settings.Settings) {
+	applySettingsChange(&s.BaseService, old, next)
+}
+
+// applySettingsChange 应用日志级别、指标开关、每连接并发上限与重试次数，并向全部窗口广播设置变更，设置未变化时不做任何事
+func applySettingsChange(b *BaseService, old, next settings.Settings) {
+	changed := settings.ChangedSections(old, next)
+	if len(changed) == 0 {
+		return
+	}
+	if old.LogLevel != next.LogLevel {
+		logger.SetLevel(next.SlogLevel())
+	}
+	if old.Metrics != next.Metrics {
+		metrics.SetEnabled(next.Metrics)
+	}
+	if old.Query.MaxConcurrency != next.Query.MaxConcurrency {
+		execqueue.SetLimit(next.Query.MaxConcurrency)
+	}
+	if old.Query.RetryAttempts != next.Query.RetryAttempts {
+		db.SetRetryAttempts(next.Query.RetryAttempts)
+	}
+	b.Logger().Info("应用设置已更新", "changed", changed)
+	b.EmitEvent(string(events.EventTypeSettingsChanged), types.SettingsChangedEvent{Settings: next, Changed: changed})
+}
+
+// settingsFailed 构造失败结果，校验错误附带字段明细
+func (s *SettingsService) settingsFailed(msg string, err error) *types.SettingsResult {
+	result := &types.SettingsResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	if ve, ok := validate.AsError(err); ok {
+		result.ValidationErrors = ve.Fields
+	} else {
+		s.Logger().Warn(msg, "error", err)
+	}
+	return result
+}
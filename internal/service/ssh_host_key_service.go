@@ -0,0 +1,92 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/knownhosts"
+	"github.com/chenyang-zz/boxify/internal/ssh"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// SSHHostKeyService 校验 SSH 跳板机主机密钥并管理受信任的主机，核心逻辑在 internal/knownhosts。
+//
+// 启动后全部 SSH 连接都会校验主机密钥：未知或变化的密钥使连接失败并广播 ssh:host-key-prompt 事件，
+// 前端展示指纹，用户确认后调用 AcceptSSHHostKey 再重新连接。
+type SSHHostKeyService struct {
+	BaseService
+	store    *knownhosts.Store
+	verifier *knownhosts.Verifier
+}
+
+// NewSSHHostKeyService 创建 SSH 主机密钥服务
+func NewSSHHostKeyService(deps *ServiceDeps) *SSHHostKeyService {
+	s := &SSHHostKeyService{BaseService: NewBaseService(deps)}
+	s.store = knownhosts.NewStore("", s.Logger())
+	s.verifier = knownhosts.NewVerifier(s.store, knownhosts.DefaultSystemFiles())
+	return s
+}
+
+// ServiceStartup 服务启动，读取受信任主机并启用主机密钥校验
+func (s *SSHHostKeyService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if err := s.store.Load(); err != nil {
+		s.Logger().Warn("加载受信任主机失败", "error", err)
+	}
+	s.verifier.SetPromptListener(func(p knownhosts.Prompt) {
+		s.Logger().Warn("SSH 主机密钥需要确认", "host", p.Host, "fingerprint", p.Fingerprint, "changed", p.Changed)
+		s.EmitEvent(string(events.EventTypeSSHHostKeyPrompt), p)
+	})
+	ssh.SetHostKeyVerifier(s.verifier.Verify)
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *SSHHostKeyService) ServiceShutdown() error {
+	ssh.SetHostKeyVerifier(nil)
+	s.verifier.SetPromptListener(nil)
+	return s.DefaultServiceShutdown()
+}
+
+// ListSSHKnownHosts 列出受信任的主机密钥（不含 ~/.ssh/known_hosts 中的记录）。
+func (s *SSHHostKeyService) ListSSHKnownHosts() *types.KnownHostListResult {
+	return &types.KnownHostListResult{BaseResult: types.BaseResult{Success: true, Message: "获取受信任主机成功"}, Data: s.store.List()}
+}
+
+// AcceptSSHHostKey 信任最近一次连接提示中的主机密钥，fingerprint 需与提示一致；确认后重新连接即可。
+func (s *SSHHostKeyService) AcceptSSHHostKey(host, fingerprint string) *types.KnownHostResult {
+	if err := validate.New().Required("host", host).Required("fingerprint", fingerprint).Err(); err != nil {
+		return &types.KnownHostResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	entry, err := s.verifier.Accept(host, fingerprint)
+	if err != nil {
+		return &types.KnownHostResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.KnownHostResult{BaseResult: types.BaseResult{Success: true, Message: "已信任主机密钥"}, Data: entry}
+}
+
+// RemoveSSHKnownHost 删除主机的受信任密钥，keyType 为空时删除该主机的全部密钥；下次连接需重新确认。
+func (s *SSHHostKeyService) RemoveSSHKnownHost(host, keyType string) *types.BaseResult {
+	if err := validate.New().Required("host", host).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.store.Remove(host, keyType); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "已删除受信任主机"}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/supportbundle"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// supportBundleTTL 预览后等待用户确认的有效期。
+const supportBundleTTL = 10 * time.Minute
+
+// SupportService 生成用于问题反馈的诊断包，核心逻辑在 internal/supportbundle。
+// 帮助菜单的“生成诊断包…”（help.diagnostics）由前端响应并调用这里的接口。
+//
+// 生成分两步：PrepareSupportBundle 收集内容并返回完整清单供用户逐项查看，
+// 用户确认后调用 GenerateSupportBundle，写入的 zip 与预览内容完全一致。
+type SupportService struct {
+	BaseService
+	mu      sync.Mutex
+	pending map[string]*supportbundle.Bundle // 等待确认的诊断包
+	probes  *supportbundle.Probes            // 连接池与会话数等运行时状态
+}
+
+// NewSupportService 创建诊断包服务
+func NewSupportService(deps *ServiceDeps) *SupportService {
+	return &SupportService{
+		BaseService: NewBaseService(deps),
+		pending:     make(map[string]*supportbundle.Bundle),
+		probes:      deps.Probes(),
+	}
+}
+
+// ServiceStartup 服务启动
+func (s *SupportService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	s.Logger().Info("服务启动", "service", "SupportService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭
+func (s *SupportService) ServiceShutdown() error {
+	s.Logger().Info("服务关闭", "service", "SupportService")
+	return nil
+}
+
+// PrepareSupportBundle 收集诊断信息并返回将写入诊断包的全部内容，connections 为前端保存的连接配置。
+func (s *SupportService) PrepareSupportBundle(connections []*connection.ConnectionConfig) *types.SupportBundlePreviewResult {
+	bundle, err := supportbundle.Collect(supportbundle.Options{AppName: "Boxify", Connections: connections, Runtime: s.probes.Collect()})
+	if err != nil {
+		s.Logger().Error("收集诊断信息失败", "error", err)
+		return &types.SupportBundlePreviewResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	s.mu.Lock()
+	for id, b := range s.pending {
+		if time.Since(b.CreatedAt) > supportBundleTTL {
+			delete(s.pending, id)
+		}
+	}
+	s.pending[bundle.ID] = bundle
+	s.mu.Unlock()
+
+	return &types.SupportBundlePreviewResult{
+		BaseResult: types.BaseResult{Success: true, Message: "请确认诊断包内容"},
+		Data:       bundle,
+	}
+}
+
+// GenerateSupportBundle 用户确认预览内容后，选择保存位置并写入诊断包 zip。
+func (s *SupportService) GenerateSupportBundle(bundleID string) *types.SupportBundleFileResult {
+	if err := validate.New().Required("bundleId", bundleID).Err(); err != nil {
+		return &types.SupportBundleFileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	s.mu.Lock()
+	bundle, ok := s.pending[bundleID]
+	s.mu.Unlock()
+	if !ok || time.Since(bundle.CreatedAt) > supportBundleTTL {
+		return &types.SupportBundleFileResult{BaseResult: types.BaseResult{Success: false, Message: "诊断包预览已失效，请重新生成预览"}}
+	}
+
+	filename, err := runtime.SaveFileDialog(s.Context(), runtime.SaveDialogOptions{
+		Title:           "保存诊断包",
+		DefaultFilename: fmt.Sprintf("boxify-support-%s.zip", bundle.CreatedAt.Format("20060102-150405")),
+	})
+	if err != nil || filename == "" {
+		return &types.SupportBundleFileResult{BaseResult: types.BaseResult{Success: false, Message: "Cancelled"}}
+	}
+
+	size, err := writeSupportBundle(filename, bundle)
+	if err != nil {
+		s.Logger().Error("写入诊断包失败", "path", filename, "error", err)
+		return &types.SupportBundleFileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	s.mu.Lock()
+	delete(s.pending, bundleID)
+	s.mu.Unlock()
+
+	s.Logger().Info("诊断包已生成", "path", filename, "size", size)
+	return &types.SupportBundleFileResult{
+		BaseResult: types.BaseResult{Success: true, Message: "诊断包已生成"},
+		Data:       &types.SupportBundleFile{Path: filename, Size: size},
+	}
+}
+
+// writeSupportBundle 写入诊断包文件并返回文件大小。
+func writeSupportBundle(filename string, bundle *supportbundle.Bundle) (int64, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	err = supportbundle.WriteZip(f, bundle)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
+		return 0, err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return 0, nil
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/termprofile"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// TerminalProfileService 管理终端配置方案（shell、启动参数、环境变量与工作目录），核心逻辑在 internal/termprofile。
+//
+// 方案与 TerminalService 共享同一存储：创建终端时通过 TerminalConfig.Profile 指定方案，
+// 未指定时使用标记为默认的方案。
+type TerminalProfileService struct {
+	BaseService
+	profiles *termprofile.Store
+}
+
+// NewTerminalProfileService 创建终端配置方案服务
+func NewTerminalProfileService(deps *ServiceDeps) *TerminalProfileService {
+	return &TerminalProfileService{
+		BaseService: NewBaseService(deps),
+		profiles:    deps.TerminalProfiles(),
+	}
+}
+
+// ServiceStartup 服务启动
+func (s *TerminalProfileService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *TerminalProfileService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// ListTerminalProfiles 列出全部终端配置方案。
+func (s *TerminalProfileService) ListTerminalProfiles() *types.TerminalProfileListResult {
+	if s.profiles == nil {
+		return &types.TerminalProfileListResult{BaseResult: types.BaseResult{Success: false, Message: "终端配置方案未初始化"}}
+	}
+	return &types.TerminalProfileListResult{BaseResult: types.BaseResult{Success: true, Message: "获取终端配置方案成功"}, Data: s.profiles.List()}
+}
+
+// GetTerminalProfile 按 ID 或名称获取终端配置方案。
+func (s *TerminalProfileService) GetTerminalProfile(idOrName string) *types.TerminalProfileResult {
+	if err := validate.New().Required("idOrName", idOrName).Err(); err != nil {
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.profiles == nil {
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: "终端配置方案未初始化"}}
+	}
+	profile, err := s.profiles.Get(idOrName)
+	if err != nil {
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: true, Message: "获取终端配置方案成功"}, Data: profile}
+}
+
+// SaveTerminalProfile 新建或更新终端配置方案（ID 为空时新建）；设为默认时取消其他方案的默认标记。
+func (s *TerminalProfileService) SaveTerminalProfile(profile *termprofile.Profile) *types.TerminalProfileResult {
+	if err := validate.New().Check(profile != nil, "profile", validate.CodeRequired, "profile 不能为空").Err(); err != nil {
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if s.profiles == nil {
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: "终端配置方案未初始化"}}
+	}
+	saved, err := s.profiles.Save(profile)
+	if err != nil {
+		s.Logger().Warn("保存终端配置方案失败", "name", profile.Name, "error", err)
+		return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.TerminalProfileResult{BaseResult: types.BaseResult{Success: true, Message: "保存终端配置方案成功"}, Data: saved}
+}
+
+// DeleteTerminalProfile 删除终端配置方案。
+func (s *TerminalProfileService) DeleteTerminalProfile(id string) *types.BaseResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if s.profiles == nil {
+		return &types.BaseResult{Success: false, Message: "终端配置方案未初始化"}
+	}
+	if err := s.profiles.Delete(id); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "删除终端配置方案成功"}
+}
@@ -18,10 +18,23 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"os"
+	"os/exec"
+	"sort"
 	"strings"
-
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/cmdhistory"
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/supportbundle"
 	"github.com/chenyang-zz/boxify/internal/terminal"
+	"github.com/chenyang-zz/boxify/internal/termprofile"
 	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
 	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
@@ -36,21 +49,40 @@ type TerminalService struct {
 	shellDetector   *terminal.ShellDetector
 	pathScanner     *terminal.PathCommandScanner
 	configGenerator *terminal.ShellConfigGenerator
+	profiles        *termprofile.Store
+	history         *cmdhistory.Store
+
+	// 已写入但尚未结束的命令，命令结束时带退出码写入历史；key 为 sessionID/blockID
+	historyMu      sync.Mutex
+	pendingHistory map[string]cmdhistory.Entry
+
+	// 空闲会话自动关闭，idleTimeout 为 0 时不检查
+	idleMu      sync.RWMutex
+	idleTimeout time.Duration
 }
 
+// idleCheckInterval 空闲会话检查间隔
+const idleCheckInterval = 15 * time.Second
+
 // NewTerminalService 创建终端服务
 func NewTerminalService(deps *ServiceDeps) *TerminalService {
 	shellDetector := terminal.NewShellDetector()
-	configGenerator := terminal.NewShellConfigGenerator(deps.app.Logger)
-
-	return &TerminalService{
-		BaseService:     NewBaseService(deps),
-		sessionManager:  terminal.NewSessionManager(),
-		shellDetector:   shellDetector,
-		pathScanner:     terminal.NewPathCommandScanner(deps.app.Logger, shellDetector),
-		configGenerator: configGenerator,
-		validator:       terminal.NewValidator(shellDetector),
+	ts := &TerminalService{
+		BaseService:    NewBaseService(deps),
+		sessionManager: terminal.NewSessionManager(),
+		shellDetector:  shellDetector,
+		validator:      terminal.NewValidator(shellDetector),
+		profiles:       deps.TerminalProfiles(),
+		history:        deps.CommandHistory(),
+		pendingHistory: make(map[string]cmdhistory.Entry),
 	}
+	ts.setSubsystem(logger.SubsystemTerminal)
+	ts.configGenerator = terminal.NewShellConfigGenerator(ts.Logger())
+	ts.pathScanner = terminal.NewPathCommandScanner(ts.Logger(), shellDetector)
+	deps.Probes().Register(func(stats *supportbundle.RuntimeStats) {
+		stats.Sessions["terminal"] = ts.sessionManager.Count()
+	})
+	return ts
 }
 
 // ServiceStartup 服务启动
@@ -59,10 +91,14 @@ func (ts *TerminalService) ServiceStartup(ctx context.Context, options applicati
 
 	// 创建输出处理器（实现 EventEmitter 接口）
 	ts.outputHandler = terminal.NewOutputHandler(ts, ts.Logger())
+	ts.outputHandler.SetStreamManager(ts.Streams())
+	ts.outputHandler.SetCommandEndHook(ts.finishCommandHistory)
 
 	// 更新 processManager
 	ts.processManager = terminal.NewProcessManager(ts.configGenerator, ts.Logger())
 
+	go ts.idleLoop(ctx)
+
 	ts.Logger().Info("服务启动", "service", "TerminalService")
 	return nil
 }
@@ -70,14 +106,17 @@ func (ts *TerminalService) ServiceStartup(ctx context.Context, options applicati
 // ServiceShutdown 服务关闭
 func (ts *TerminalService) ServiceShutdown() error {
 	ts.Logger().Info("服务开始关闭，准备释放资源", "service", "TerminalService")
-	ts.sessionManager.CloseAll(ts.configGenerator)
+	for sessionID, report := range ts.sessionManager.CloseAll(ts.configGenerator) {
+		ts.logCloseReport(sessionID, report)
+	}
+	ts.flushCommandHistory("")
 	ts.Logger().Info("服务关闭", "service", "TerminalService")
 	return nil
 }
 
 // Emit 实现 EventEmitter 接口
 func (ts *TerminalService) Emit(event string, data interface{}) {
-	ts.App().Event.Emit(event, data)
+	ts.EmitEvent(event, data)
 }
 
 // formatCommandPayload 根据会话模式包装命令并补齐换行。
@@ -95,6 +134,18 @@ func formatCommandPayload(session *terminal.Session, command string) string {
 
 // Create 创建新的终端会话
 func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.TerminalCreateResult {
+	// 合并终端配置方案：指定方案或默认方案中的值作为未填写字段的默认值
+	profile, err := ts.resolveProfile(config.Profile)
+	if err != nil {
+		return &types.TerminalCreateResult{
+			BaseResult: types.BaseResult{
+				Success: false,
+				Message: err.Error(),
+			},
+		}
+	}
+	config = terminal.ApplyProfile(config, profile)
+
 	// 验证基本配置
 	validationResult := ts.validator.ValidateBasicConfig(config)
 	if !validationResult.Valid {
@@ -121,6 +172,22 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 	// 规范化终端尺寸
 	rows, cols := ts.validator.NormalizeSize(config.Rows, config.Cols)
 
+	// 数据库控制台：选择客户端并准备启动命令，凭据写入私有临时目录中的 0600 文件，不进入 shell 环境
+	var consoleLaunch *terminal.DBConsoleLaunch
+	var secretDir string
+	if config.DBConsole != nil {
+		launch, dir, err := ts.buildDBConsoleLaunch(config.DBConsole, validationResult.ShellType)
+		if err != nil {
+			return &types.TerminalCreateResult{
+				BaseResult: types.BaseResult{
+					Success: false,
+					Message: err.Error(),
+				},
+			}
+		}
+		consoleLaunch, secretDir = launch, dir
+	}
+
 	// 创建 PTY 进程
 	process, err := ts.processManager.CreateProcess(&terminal.ProcessOptions{
 		ShellPath: validationResult.ShellPath,
@@ -129,8 +196,13 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 		SessionID: config.ID,
 		Rows:      rows,
 		Cols:      cols,
+		Args:      config.Args,
+		Env:       termprofile.EnvList(config.Env),
 	})
 	if err != nil {
+		if secretDir != "" {
+			_ = os.RemoveAll(secretDir)
+		}
 		ts.Logger().Error("创建 PTY 失败", "shell", validationResult.ShellPath, "error", err)
 		return &types.TerminalCreateResult{
 			BaseResult: types.BaseResult{
@@ -143,11 +215,15 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 	// 创建会话
 	session := terminal.NewSession(ts.Context(), config.ID, process.Pty, process.Cmd, validationResult.ShellType, process.UseHooks, ts.Logger())
 	session.SetConfigPath(process.ConfigPath)
+	session.SetSecretDir(secretDir)
 	session.SetWorkPath(validationResult.WorkPath)
 	session.SetLogger(ts.Logger())
 
 	ts.sessionManager.Add(session)
 
+	// shell 自行退出（如输入 exit）时释放会话并通知前端
+	ts.sessionManager.WatchExit(session, ts.configGenerator, ts.onSessionExit)
+
 	// 启动输出读取 goroutine
 	go ts.outputHandler.StartOutputLoop(session)
 
@@ -162,6 +238,17 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 		}
 	}
 
+	// 启动数据库控制台（作为普通命令块执行，初始命令完成后才写入）
+	var consoleInfo *types.TerminalDBConsoleInfo
+	if consoleLaunch != nil {
+		consoleInfo = &types.TerminalDBConsoleInfo{Client: consoleLaunch.Client, BlockID: uuid.New().String()}
+		go func(sessionID, command, blockID string) {
+			if _, err := ts.writeCommandInternal(sessionID, command, blockID); err != nil {
+				ts.Logger().Warn("启动数据库控制台失败", "sessionId", sessionID, "error", err)
+			}
+		}(config.ID, consoleLaunch.Command, consoleInfo.BlockID)
+	}
+
 	ts.Logger().Info("终端会话创建",
 		"sessionId", config.ID,
 		"shell", validationResult.ShellPath,
@@ -172,6 +259,7 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 
 	// 获取环境信息
 	envInfo := terminal.GetEnvironmentInfo(validationResult.WorkPath)
+	envInfo.DBConsole = consoleInfo
 
 	return &types.TerminalCreateResult{
 		BaseResult: types.BaseResult{
@@ -182,6 +270,51 @@ func (ts *TerminalService) Create(config terminal.TerminalConfig) *types.Termina
 	}
 }
 
+// resolveProfile 按 ID 或名称查找终端配置方案，未指定时返回默认方案（可能为 nil）
+func (ts *TerminalService) resolveProfile(idOrName string) (*termprofile.Profile, error) {
+	if ts.profiles == nil {
+		if strings.TrimSpace(idOrName) != "" {
+			return nil, termprofile.ErrProfileNotFound
+		}
+		return nil, nil
+	}
+	if strings.TrimSpace(idOrName) == "" {
+		return ts.profiles.Default(), nil
+	}
+	profile, err := ts.profiles.Get(idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, idOrName)
+	}
+	return profile, nil
+}
+
+// buildDBConsoleLaunch 校验连接配置并生成数据库控制台启动命令，本机没有 mysql/psql 时回退到应用内置控制台
+// 凭据文件写入新建的私有临时目录，返回该目录供会话关闭时删除
+func (ts *TerminalService) buildDBConsoleLaunch(config *connection.ConnectionConfig, shellType terminal.ShellType) (*terminal.DBConsoleLaunch, string, error) {
+	if err := validate.New().ConnectionConfig("dbConsole", config).Err(); err != nil {
+		return nil, "", err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		ts.Logger().Warn("获取应用可执行文件路径失败，内置控制台不可用", "error", err)
+		executable = ""
+	}
+	secretDir, err := terminal.NewDBConsoleSecretDir()
+	if err != nil {
+		return nil, "", fmt.Errorf("创建控制台凭据目录失败: %w", err)
+	}
+	launch, err := terminal.BuildDBConsoleLaunch(config, shellType, exec.LookPath, executable, secretDir)
+	if err == nil {
+		err = launch.WriteSecret()
+	}
+	if err != nil {
+		_ = os.RemoveAll(secretDir)
+		return nil, "", err
+	}
+	ts.Logger().Info("准备启动数据库控制台", "client", launch.Client, "summary", db.FormatConnSummary(config))
+	return launch, secretDir, nil
+}
+
 // Write 向终端写入用户输入
 func (ts *TerminalService) Write(sessionID, data string) error {
 	decoded, err := base64.StdEncoding.DecodeString(data)
@@ -194,6 +327,7 @@ func (ts *TerminalService) Write(sessionID, data string) error {
 	if !ok {
 		return fmt.Errorf("会话不存在: %s", sessionID)
 	}
+	session.Touch()
 
 	_, err = session.Pty.Write(decoded)
 	if err != nil {
@@ -224,7 +358,9 @@ func (ts *TerminalService) writeCommandInternal(sessionID, command, preferredBlo
 	}
 
 	// 设置当前 block
+	session.Touch()
 	session.SetCurrentBlock(blockID)
+	session.Blocks().Begin(blockID, command)
 
 	// 根据模式决定是否包装命令
 	cmd := formatCommandPayload(session, command)
@@ -243,13 +379,42 @@ func (ts *TerminalService) writeCommandInternal(sessionID, command, preferredBlo
 // WriteCommand 写入命令并返回 block ID
 // 用于追踪命令输出，实现 block 关联（兼容旧调用，不要求前端提供 block ID）。
 func (ts *TerminalService) WriteCommand(sessionID, command string) (string, error) {
-	return ts.writeCommandInternal(sessionID, command, "")
+	blockID, err := ts.writeCommandInternal(sessionID, command, "")
+	if err == nil {
+		ts.beginCommandHistory(sessionID, blockID, command)
+	}
+	return blockID, err
 }
 
 // WriteCommandWithBlock 写入命令并复用前端提供的 block ID。
 // 该方法用于保证命令流式输出与前端 block 在首包输出前就能完成绑定。
 func (ts *TerminalService) WriteCommandWithBlock(sessionID, blockID, command string) (string, error) {
-	return ts.writeCommandInternal(sessionID, command, blockID)
+	blockID, err := ts.writeCommandInternal(sessionID, command, blockID)
+	if err == nil {
+		ts.beginCommandHistory(sessionID, blockID, command)
+	}
+	return blockID, err
+}
+
+// SendSignal 向终端前台进程组发送信号（SIGINT、SIGTSTP、SIGCONT、SIGQUIT、SIGTERM、SIGKILL），
+// 用于工具栏的中断与强制结束按钮；命令忽略 Ctrl+C 字符时也能生效。
+func (ts *TerminalService) SendSignal(sessionID, signal string) *types.BaseResult {
+	if err := validate.New().Required("sessionId", sessionID).Required("signal", signal).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	session, ok := ts.sessionManager.Get(sessionID)
+	if !ok {
+		return &types.BaseResult{Success: false, Message: fmt.Sprintf("会话不存在: %s", sessionID)}
+	}
+
+	pgid, err := session.SendSignal(signal)
+	if err != nil {
+		ts.Logger().Warn("发送终端信号失败", "sessionId", sessionID, "signal", signal, "pgid", pgid, "error", err)
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	session.Touch()
+	ts.Logger().Info("已发送终端信号", "sessionId", sessionID, "signal", signal, "pgid", pgid)
+	return &types.BaseResult{Success: true, Message: "信号已发送"}
 }
 
 // Resize 调整终端大小
@@ -271,15 +436,305 @@ func (ts *TerminalService) Resize(sessionID string, rows, cols uint16) error {
 
 // Close 关闭终端会话
 func (ts *TerminalService) Close(sessionID string) error {
-	err := ts.sessionManager.CloseSession(sessionID, ts.configGenerator)
+	return ts.closeSession(sessionID, types.TerminalCloseReasonClosed)
+}
+
+// closeSession 关闭会话并发送 terminal:closed 事件
+func (ts *TerminalService) closeSession(sessionID, reason string) error {
+	report, err := ts.sessionManager.CloseSession(sessionID, ts.configGenerator)
 	if err != nil {
 		return err
 	}
+	if report == nil {
+		return nil
+	}
 
-	ts.Logger().Info("终端会话已关闭", "sessionId", sessionID)
+	ts.flushCommandHistory(sessionID)
+	ts.logCloseReport(sessionID, report)
+	ts.emitClosed(sessionID, reason, nil, report)
 	return nil
 }
 
+// onSessionExit shell 自行退出后的清理回调（会话已从管理器移除）
+func (ts *TerminalService) onSessionExit(session *terminal.Session, report *terminal.CloseReport) {
+	ts.flushCommandHistory(session.ID)
+	var exitCode *int
+	if code, ok := session.ExitCode(); ok {
+		exitCode = &code
+	}
+	ts.Logger().Info("终端进程已退出，会话已清理", "sessionId", session.ID, "exitCode", exitCode, "orphanedChildren", report.OrphanedChildren)
+	ts.emitClosed(session.ID, types.TerminalCloseReasonExit, exitCode, report)
+}
+
+// emitClosed 发送终端会话关闭事件
+func (ts *TerminalService) emitClosed(sessionID, reason string, exitCode *int, report *terminal.CloseReport) {
+	ts.Emit(string(events.EventTypeTerminalClosed), types.TerminalClosedEvent{
+		SessionID:        sessionID,
+		Reason:           reason,
+		ExitCode:         exitCode,
+		OrphanedChildren: report != nil && report.OrphanedChildren,
+		ClosedAtUnix:     time.Now().UnixMilli(),
+	})
+}
+
+// SetIdleTimeout 设置空闲会话自动关闭时间（分钟），0 表示不自动关闭。
+// 会话在关闭前一分钟（超时不足两分钟时为一半时间）收到 terminal:idle_warning 事件，期间有输入或输出即重新计时。
+func (ts *TerminalService) SetIdleTimeout(minutes int) *types.BaseResult {
+	if err := validate.New().Range("minutes", minutes, 0, 7*24*60).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	ts.idleMu.Lock()
+	ts.idleTimeout = time.Duration(minutes) * time.Minute
+	ts.idleMu.Unlock()
+	ts.Logger().Info("终端空闲超时已更新", "minutes", minutes)
+	return &types.BaseResult{Success: true, Message: "设置空闲超时成功"}
+}
+
+// idleLoop 定期检查空闲会话，直到服务上下文结束
+func (ts *TerminalService) idleLoop(ctx context.Context) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ts.checkIdleSessions(now)
+		}
+	}
+}
+
+// checkIdleSessions 向即将超时的会话发送警告，关闭已超时的会话
+func (ts *TerminalService) checkIdleSessions(now time.Time) {
+	ts.idleMu.RLock()
+	timeout := ts.idleTimeout
+	ts.idleMu.RUnlock()
+	if timeout <= 0 {
+		return
+	}
+	warnBefore := min(terminal.DefaultIdleWarnBefore, timeout/2)
+
+	var expired []string
+	ts.sessionManager.ForEach(func(session *terminal.Session) {
+		switch session.CheckIdle(now, timeout, warnBefore) {
+		case terminal.IdleWarn:
+			idle := now.Sub(session.LastActivity())
+			ts.Emit(string(events.EventTypeTerminalIdleWarning), types.TerminalIdleWarningEvent{
+				SessionID:      session.ID,
+				IdleSeconds:    int64(idle.Seconds()),
+				CloseInSeconds: int64((timeout - idle).Seconds()),
+			})
+		case terminal.IdleExpire:
+			expired = append(expired, session.ID)
+		}
+	})
+
+	for _, sessionID := range expired {
+		ts.Logger().Info("终端会话空闲超时，自动关闭", "sessionId", sessionID, "timeout", timeout)
+		if err := ts.closeSession(sessionID, types.TerminalCloseReasonIdle); err != nil {
+			ts.Logger().Warn("关闭空闲终端会话失败", "sessionId", sessionID, "error", err)
+		}
+	}
+}
+
+// logCloseReport 记录会话关闭结果，shell 退出后仍有子进程存活时输出警告
+func (ts *TerminalService) logCloseReport(sessionID string, report *terminal.CloseReport) {
+	if report == nil {
+		return
+	}
+	if report.OrphanedChildren {
+		ts.Logger().Warn("终端会话已关闭，但仍有子进程存活", "sessionId", sessionID, "killed", report.Killed, "elapsed", report.Elapsed)
+		return
+	}
+	ts.Logger().Info("终端会话已关闭", "sessionId", sessionID, "graceful", report.Graceful, "killed", report.Killed, "elapsed", report.Elapsed)
+}
+
+// SearchCommandHistory 按子串搜索终端命令历史（忽略大小写），按命令去重，最近执行的在前；
+// sessionID 为空时搜索全部会话，text 为空时返回最近执行的命令。
+func (ts *TerminalService) SearchCommandHistory(text, sessionID string, limit int) *types.TerminalCommandHistoryResult {
+	if ts.history == nil {
+		return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: false, Message: "命令历史未初始化"}}
+	}
+	items, err := ts.history.Search(cmdhistory.Query{Text: text, SessionID: sessionID, Limit: limit})
+	if err != nil {
+		ts.Logger().Error("搜索命令历史失败", "error", err)
+		return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: true, Message: "搜索命令历史成功"}, Data: items}
+}
+
+// SuggestCommands 按输入前缀返回命令补全建议，供命令面板使用；
+// 当前工作目录下执行过的命令优先，其次按执行次数与最近使用时间排序。
+func (ts *TerminalService) SuggestCommands(prefix, sessionID string, limit int) *types.TerminalCommandHistoryResult {
+	if ts.history == nil {
+		return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: false, Message: "命令历史未初始化"}}
+	}
+	q := cmdhistory.Query{Text: prefix, Limit: limit}
+	if session, ok := ts.sessionManager.Get(sessionID); ok {
+		q.WorkPath = session.WorkPath()
+	}
+	items, err := ts.history.Suggest(q)
+	if err != nil {
+		ts.Logger().Error("获取命令建议失败", "error", err)
+		return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.TerminalCommandHistoryResult{BaseResult: types.BaseResult{Success: true, Message: "获取命令建议成功"}, Data: items}
+}
+
+// ClearCommandHistory 清空终端命令历史
+func (ts *TerminalService) ClearCommandHistory() *types.BaseResult {
+	if ts.history == nil {
+		return &types.BaseResult{Success: false, Message: "命令历史未初始化"}
+	}
+	if err := ts.history.Clear(); err != nil {
+		ts.Logger().Error("清空命令历史失败", "error", err)
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "命令历史已清空"}
+}
+
+// beginCommandHistory 登记已写入的命令，等待命令结束时写入历史
+func (ts *TerminalService) beginCommandHistory(sessionID, blockID, command string) {
+	if ts.history == nil {
+		return
+	}
+	session, ok := ts.sessionManager.Get(sessionID)
+	if !ok {
+		return
+	}
+	ts.historyMu.Lock()
+	ts.pendingHistory[sessionID+"/"+blockID] = cmdhistory.Entry{
+		Time:      time.Now(),
+		Command:   command,
+		SessionID: sessionID,
+		WorkPath:  session.WorkPath(),
+		Shell:     string(session.ShellType()),
+	}
+	ts.historyMu.Unlock()
+}
+
+// finishCommandHistory 命令结束时带退出码写入历史（由输出处理器回调）
+func (ts *TerminalService) finishCommandHistory(session *terminal.Session, blockID string, exitCode int) {
+	key := session.ID + "/" + blockID
+	ts.historyMu.Lock()
+	entry, ok := ts.pendingHistory[key]
+	delete(ts.pendingHistory, key)
+	ts.historyMu.Unlock()
+	if !ok {
+		return
+	}
+	entry.ExitCode = &exitCode
+	ts.recordCommandHistory(entry)
+}
+
+// flushCommandHistory 将未结束的命令以未知退出码写入历史，sessionID 为空时处理全部会话
+func (ts *TerminalService) flushCommandHistory(sessionID string) {
+	ts.historyMu.Lock()
+	var entries []cmdhistory.Entry
+	for key, entry := range ts.pendingHistory {
+		if sessionID == "" || entry.SessionID == sessionID {
+			entries = append(entries, entry)
+			delete(ts.pendingHistory, key)
+		}
+	}
+	ts.historyMu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	for _, entry := range entries {
+		ts.recordCommandHistory(entry)
+	}
+}
+
+// recordCommandHistory 写入命令历史，失败只记录日志
+func (ts *TerminalService) recordCommandHistory(entry cmdhistory.Entry) {
+	if err := ts.history.Record(entry); err != nil {
+		ts.Logger().Warn("记录命令历史失败", "sessionId", entry.SessionID, "error", err)
+	}
+}
+
+// SearchScrollback 在会话的回滚缓冲区中搜索，regex=false 时按字面匹配
+// 缓冲区由后端维护，前端无需保留完整历史即可实现终端内查找
+func (ts *TerminalService) SearchScrollback(sessionID, pattern string, regex bool) *types.TerminalScrollbackSearchResult {
+	session, ok := ts.sessionManager.Get(sessionID)
+	if !ok {
+		return &types.TerminalScrollbackSearchResult{
+			BaseResult: types.BaseResult{
+				Success: false,
+				Message: fmt.Sprintf("会话不存在: %s", sessionID),
+			},
+		}
+	}
+
+	matches, truncated, err := session.Scrollback().Search(pattern, regex)
+	if err != nil {
+		return &types.TerminalScrollbackSearchResult{
+			BaseResult: types.BaseResult{
+				Success: false,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	return &types.TerminalScrollbackSearchResult{
+		BaseResult: types.BaseResult{
+			Success: true,
+			Message: "搜索完成",
+		},
+		Data: &types.TerminalScrollbackSearchData{
+			Matches:   matches,
+			Truncated: truncated,
+		},
+	}
+}
+
+// GetBlockOutput 获取命令块保存的输出，用于事后重新渲染或复制；单块与会话总量超限时较早的输出会被丢弃
+func (ts *TerminalService) GetBlockOutput(sessionID, blockID string) *types.TerminalBlockOutputResult {
+	output, err := ts.blockOutput(sessionID, blockID)
+	if err != nil {
+		return &types.TerminalBlockOutputResult{
+			BaseResult: types.BaseResult{
+				Success: false,
+				Message: err.Error(),
+			},
+		}
+	}
+	return &types.TerminalBlockOutputResult{
+		BaseResult: types.BaseResult{
+			Success: true,
+			Message: "获取命令输出成功",
+		},
+		Data: output,
+	}
+}
+
+// ExportBlockOutput 将命令块输出的纯文本保存到 path
+func (ts *TerminalService) ExportBlockOutput(sessionID, blockID, path string) *types.BaseResult {
+	if err := validate.New().SafePath("path", path).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	output, err := ts.blockOutput(sessionID, blockID)
+	if err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := os.WriteFile(path, []byte(output.Text), 0o644); err != nil {
+		ts.Logger().Error("保存命令输出失败", "sessionId", sessionID, "blockId", blockID, "path", path, "error", err)
+		return &types.BaseResult{Success: false, Message: fmt.Sprintf("保存命令输出失败: %v", err)}
+	}
+	return &types.BaseResult{Success: true, Message: "命令输出已保存"}
+}
+
+// blockOutput 查找会话中命令块保存的输出
+func (ts *TerminalService) blockOutput(sessionID, blockID string) (*types.TerminalBlockOutput, error) {
+	session, ok := ts.sessionManager.Get(sessionID)
+	if !ok {
+		return nil, fmt.Errorf("会话不存在: %s", sessionID)
+	}
+	output, ok := session.Blocks().Get(blockID)
+	if !ok {
+		return nil, fmt.Errorf("命令输出不存在或已被淘汰: %s", blockID)
+	}
+	return output, nil
+}
+
 // TestConfig 测试终端配置参数是否有效
 func (ts *TerminalService) TestConfig(config terminal.TerminalConfig) *types.TerminalTestConfigResult {
 	result := &types.TerminalTestConfigResult{
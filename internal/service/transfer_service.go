@@ -0,0 +1,88 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// TransferService 向前端暴露分块传输的确认、取消与状态查询接口，核心逻辑在 internal/eventstream。
+type TransferService struct {
+	BaseService
+}
+
+// NewTransferService 创建分块传输服务
+func NewTransferService(deps *ServiceDeps) *TransferService {
+	return &TransferService{BaseService: NewBaseService(deps)}
+}
+
+// ServiceStartup 服务启动
+func (s *TransferService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.SetContext(ctx)
+	s.Logger().Info("服务启动", "service", "TransferService")
+	return nil
+}
+
+// ServiceShutdown 服务关闭，取消所有未完成的传输流
+func (s *TransferService) ServiceShutdown() error {
+	s.Logger().Info("服务开始关闭，准备释放资源", "service", "TransferService")
+	if streams := s.Streams(); streams != nil {
+		streams.CloseAll()
+	}
+	s.Logger().Info("服务关闭", "service", "TransferService")
+	return nil
+}
+
+// AckTransfer 累计确认分块：seq 及之前的分块均已被前端消费。
+func (s *TransferService) AckTransfer(streamID string, seq int64) *types.BaseResult {
+	streams := s.Streams()
+	if streams == nil {
+		return &types.BaseResult{Success: false, Message: "分块传输通道未初始化"}
+	}
+	if err := streams.Ack(streamID, seq); err != nil {
+		s.Logger().Debug("分块确认失败", "streamId", streamID, "seq", seq, "error", err)
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "确认成功"}
+}
+
+// CancelTransfer 取消指定传输流。
+func (s *TransferService) CancelTransfer(streamID string) *types.BaseResult {
+	streams := s.Streams()
+	if streams == nil {
+		return &types.BaseResult{Success: false, Message: "分块传输通道未初始化"}
+	}
+	if err := streams.Cancel(streamID); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "传输已取消"}
+}
+
+// GetTransferStats 获取所有活跃传输流的状态。
+func (s *TransferService) GetTransferStats() *types.TransferStatsResult {
+	streams := s.Streams()
+	if streams == nil {
+		return &types.TransferStatsResult{
+			BaseResult: types.BaseResult{Success: false, Message: "分块传输通道未初始化"},
+		}
+	}
+	return &types.TransferStatsResult{
+		BaseResult: types.BaseResult{Success: true, Message: "获取传输状态成功"},
+		Data:       streams.Stats(),
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// invalidArgs 记录参数校验失败并返回统一的失败结果，Data 携带字段级错误明细供前端定位。
+func (a *DatabaseService) invalidArgs(method string, err error) *connection.QueryResult {
+	a.Logger().Warn(method+" 参数校验失败", "error", err)
+	result := &connection.QueryResult{Success: false, Message: err.Error()}
+	if ve, ok := validate.AsError(err); ok {
+		result.Data = map[string]any{"validationErrors": ve.Fields}
+	}
+	return result
+}
+
+// validateDatabaseArgs 校验连接配置与可选数据库名。
+func validateDatabaseArgs(config *connection.ConnectionConfig, dbName string) *validate.Validator {
+	return validate.New().
+		ConnectionConfig("config", config).
+		OptionalIdentifier("dbName", dbName)
+}
+
+// validateTableArgs 校验连接配置、可选数据库名与必填表名。
+func validateTableArgs(config *connection.ConnectionConfig, dbName, tableName string) *validate.Validator {
+	return validateDatabaseArgs(config, dbName).Identifier("tableName", tableName)
+}
@@ -16,27 +16,46 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/syncstate"
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/chenyang-zz/boxify/internal/window"
+	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 // WindowService 窗口管理服务
+//
+// 分离窗口：OpenDetachedWindow 打开可多开的窗口并把标签页上下文移交给它，上下文的归属保存在
+// 数据同步层的共享状态中（键 window-context:<id>），移交与释放以 data-sync:broadcast 事件通知全部窗口。
 type WindowService struct {
 	BaseService
+	syncState *syncstate.Store // 与数据同步服务共享
 }
 
 // NewWindowService 创建 WindowService
 func NewWindowService(deps *ServiceDeps) *WindowService {
-	return &WindowService{
+	ws := &WindowService{
 		BaseService: NewBaseService(deps),
+		syncState:   deps.SyncState(),
 	}
+	ws.setSubsystem(logger.SubsystemWindow)
+	return ws
 }
 
 // Startup 是在应用程序启动时调用的函数
 func (ws *WindowService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	ws.SetContext(ctx)
+	if registry := ws.Registry(); registry != nil {
+		registry.OnDetachedClosed(ws.reclaimWindowContexts)
+	}
 	ws.Logger().Info("服务启动", "service", "WindowService")
 	return nil
 }
@@ -146,3 +165,173 @@ func (ws *WindowService) GetWindowNameByPageID(pageId string) *connection.QueryR
 		Data:    pageConfig.Window.Name,
 	}
 }
+
+// windowContextKey 返回窗口上下文在共享状态中的键
+func windowContextKey(id string) string {
+	return ChannelWindowContext + ":" + id
+}
+
+// rejectStaleWindowContext 拒绝基于旧版本的窗口上下文写入，避免两个窗口同时认领同一上下文
+func rejectStaleWindowContext(current, incoming map[string]interface{}) (map[string]interface{}, error) {
+	return nil, errors.New("上下文已被其他窗口修改，请刷新后重试")
+}
+
+// OpenDetachedWindow 以页面为模板打开分离窗口，并将上下文（连接中的数据表或终端会话）移交给新窗口。
+// 新窗口 URL 带 windowContext 参数，加载后调用 GetWindowContext 获取上下文；source 为发起拖出的窗口名称。
+func (ws *WindowService) OpenDetachedWindow(pageId string, wctx *types.WindowContext, source string) *types.WindowContextResult {
+	v := validate.New().Required("pageId", pageId).Check(wctx != nil, "context", validate.CodeRequired, "context 不能为空")
+	if wctx != nil {
+		v.OneOf("context.kind", wctx.Kind, types.WindowContextTable, types.WindowContextTerminal)
+		switch wctx.Kind {
+		case types.WindowContextTable:
+			v.Required("context.connectionId", wctx.ConnectionID).
+				OptionalIdentifier("context.database", wctx.Database).
+				OptionalIdentifier("context.schema", wctx.Schema).
+				Identifier("context.table", wctx.Table)
+		case types.WindowContextTerminal:
+			v.Required("context.sessionId", wctx.SessionID)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	am := ws.AppManager()
+	if am == nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: "AppManager 未初始化"}}
+	}
+
+	name := window.NewDetachedWindowName()
+	opened := *wctx
+	opened.ID = uuid.NewString()
+	opened.Owner = name
+	update, err := ws.putWindowContext(&opened, 0, source)
+	if err != nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+
+	if err := am.OpenDetachedPage(pageId, name, opened.Title, url.Values{"windowContext": {opened.ID}}); err != nil {
+		ws.syncState.Delete(windowContextKey(opened.ID), source)
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("打开分离窗口失败: %s", err.Error())}}
+	}
+	broadcastSyncState(&ws.BaseService, update, DataTypeContextTransfer)
+
+	ws.Logger().Info("分离窗口已打开", "window", name, "contextId", opened.ID, "kind", opened.Kind, "source", source)
+	return &types.WindowContextResult{BaseResult: types.BaseResult{Success: true, Message: "分离窗口已打开"}, Data: windowContextFromEntry(update.Entry)}
+}
+
+// GetWindowContext 获取窗口上下文，分离窗口加载后据此恢复标签页。
+func (ws *WindowService) GetWindowContext(contextID string) *types.WindowContextResult {
+	if err := validate.New().Required("contextId", contextID).Err(); err != nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	entry, ok := ws.syncState.Get(windowContextKey(contextID))
+	if !ok || entry.Deleted {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: "上下文不存在或已关闭"}}
+	}
+	return &types.WindowContextResult{BaseResult: types.BaseResult{Success: true, Message: "获取上下文成功"}, Data: windowContextFromEntry(entry)}
+}
+
+// ListWindowContexts 列出窗口持有的上下文，owner 为空时列出全部。
+func (ws *WindowService) ListWindowContexts(owner string) *types.WindowContextListResult {
+	list := make([]*types.WindowContext, 0)
+	for _, entry := range ws.syncState.List(ChannelWindowContext + ":") {
+		if wctx := windowContextFromEntry(entry); owner == "" || wctx.Owner == owner {
+			list = append(list, wctx)
+		}
+	}
+	return &types.WindowContextListResult{BaseResult: types.BaseResult{Success: true, Message: "获取上下文成功"}, Data: list}
+}
+
+// TransferWindowContext 将上下文移交给目标窗口（如将分离的标签页拖回主窗口）。
+// baseVersion 为发起方所见的上下文版本，期间被其他窗口移交过时拒绝。
+func (ws *WindowService) TransferWindowContext(contextID, targetWindow string, baseVersion uint64, source string) *types.WindowContextResult {
+	if err := validate.New().Required("contextId", contextID).Required("targetWindow", targetWindow).Err(); err != nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if registry := ws.Registry(); registry == nil || registry.Get(targetWindow) == nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: fmt.Sprintf("目标窗口不存在: %s", targetWindow)}}
+	}
+	entry, ok := ws.syncState.Get(windowContextKey(contextID))
+	if !ok || entry.Deleted {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: "上下文不存在或已关闭"}}
+	}
+
+	wctx := windowContextFromEntry(entry)
+	from := wctx.Owner
+	wctx.Owner = targetWindow
+	if baseVersion == 0 {
+		baseVersion = entry.Version
+	}
+	update, err := ws.putWindowContext(wctx, baseVersion, source)
+	if err != nil {
+		return &types.WindowContextResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	broadcastSyncState(&ws.BaseService, update, DataTypeContextTransfer)
+	ws.Logger().Info("上下文已移交", "contextId", contextID, "from", from, "to", targetWindow)
+	return &types.WindowContextResult{BaseResult: types.BaseResult{Success: true, Message: "上下文已移交"}, Data: windowContextFromEntry(update.Entry)}
+}
+
+// ReleaseWindowContext 标签页关闭后释放上下文。
+func (ws *WindowService) ReleaseWindowContext(contextID, source string) *types.BaseResult {
+	if err := validate.New().Required("contextId", contextID).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	update, ok := ws.syncState.Delete(windowContextKey(contextID), source)
+	if !ok {
+		return &types.BaseResult{Success: false, Message: "上下文不存在或已关闭"}
+	}
+	broadcastSyncState(&ws.BaseService, update, DataTypeContextRelease)
+	return &types.BaseResult{Success: true, Message: "上下文已释放"}
+}
+
+// reclaimWindowContexts 分离窗口关闭后将其持有的上下文交还主窗口，避免终端会话等资源无人持有
+func (ws *WindowService) reclaimWindowContexts(closed string) {
+	am := ws.AppManager()
+	if am == nil {
+		return
+	}
+	mainPage := am.GetPageConfig().GetMainPageConfig()
+	if mainPage == nil || mainPage.Window == nil {
+		return
+	}
+	for _, entry := range ws.syncState.List(ChannelWindowContext + ":") {
+		wctx := windowContextFromEntry(entry)
+		if wctx.Owner != closed {
+			continue
+		}
+		wctx.Owner = mainPage.Window.Name
+		update, err := ws.putWindowContext(wctx, entry.Version, closed)
+		if err != nil {
+			ws.Logger().Warn("交还上下文失败", "contextId", wctx.ID, "window", closed, "error", err)
+			continue
+		}
+		broadcastSyncState(&ws.BaseService, update, DataTypeContextTransfer)
+		ws.Logger().Info("分离窗口已关闭，上下文交还主窗口", "contextId", wctx.ID, "window", closed)
+	}
+}
+
+// putWindowContext 写入窗口上下文（不含版本字段）
+func (ws *WindowService) putWindowContext(wctx *types.WindowContext, baseVersion uint64, source string) (syncstate.Update, error) {
+	stored := *wctx
+	stored.Version = 0
+	raw, err := json.Marshal(&stored)
+	if err != nil {
+		return syncstate.Update{}, err
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return syncstate.Update{}, err
+	}
+	delete(value, "version")
+	return ws.syncState.Set(windowContextKey(wctx.ID), value, baseVersion, source)
+}
+
+// windowContextFromEntry 由共享状态还原窗口上下文
+func windowContextFromEntry(entry syncstate.Entry) *types.WindowContext {
+	wctx := &types.WindowContext{}
+	if raw, err := json.Marshal(entry.Value); err == nil {
+		_ = json.Unmarshal(raw, wctx)
+	}
+	wctx.Version = entry.Version
+	return wctx
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestWindowContextOwnership(t *testing.T) {
+	app := application.New(application.Options{LogLevel: slog.LevelInfo})
+	ws := NewWindowService(NewServiceDeps(app, nil))
+
+	wctx := &types.WindowContext{ID: "ctx-1", Kind: types.WindowContextTable, Owner: "detached-1",
+		ConnectionID: "c1", Database: "shop", Table: "orders", Data: map[string]interface{}{"sort": "id desc"}}
+	first, err := ws.putWindowContext(wctx, 0, "main")
+	if err != nil {
+		t.Fatalf("putWindowContext() error = %v", err)
+	}
+
+	got := ws.GetWindowContext("ctx-1")
+	if !got.Success || got.Data.Owner != "detached-1" || got.Data.Table != "orders" || got.Data.Data["sort"] != "id desc" || got.Data.Version != first.Version {
+		t.Fatalf("GetWindowContext() = %+v", got.Data)
+	}
+	if list := ws.ListWindowContexts("detached-1"); len(list.Data) != 1 || list.Data[0].ID != "ctx-1" {
+		t.Fatalf("ListWindowContexts() = %+v", list.Data)
+	}
+
+	moved := *got.Data
+	moved.Owner = "main"
+	if _, err := ws.putWindowContext(&moved, first.Version, "detached-1"); err != nil {
+		t.Fatalf("基于当前版本的移交应成功: %v", err)
+	}
+	moved.Owner = "detached-2"
+	if _, err := ws.putWindowContext(&moved, first.Version, "detached-2"); err == nil {
+		t.Fatal("基于旧版本的移交应被拒绝")
+	}
+	if got := ws.GetWindowContext("ctx-1"); got.Data.Owner != "main" {
+		t.Fatalf("被拒绝的移交不应修改归属: %+v", got.Data)
+	}
+
+	if res := ws.TransferWindowContext("ctx-1", "missing", 0, "main"); res.Success {
+		t.Fatal("目标窗口不存在时应失败")
+	}
+	if res := ws.ReleaseWindowContext("ctx-1", "main"); !res.Success {
+		t.Fatalf("ReleaseWindowContext() = %+v", res)
+	}
+	if got := ws.GetWindowContext("ctx-1"); got.Success {
+		t.Fatal("释放后不应再能获取上下文")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+	"github.com/chenyang-zz/boxify/internal/validate"
+	"github.com/chenyang-zz/boxify/internal/workspace"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// WorkspaceService 管理命名工作区（连接、打开的库表、编辑器标签页、终端会话与布局），核心逻辑在 internal/workspace。
+//
+// 前端在退出或切换工作区前调用 SaveWorkspace，启动时通过 GetLastWorkspace 恢复上次打开的工作区。
+type WorkspaceService struct {
+	BaseService
+	store *workspace.Store
+}
+
+// NewWorkspaceService 创建工作区服务
+func NewWorkspaceService(deps *ServiceDeps) *WorkspaceService {
+	s := &WorkspaceService{BaseService: NewBaseService(deps)}
+	s.store = workspace.NewStore("", s.Logger())
+	return s
+}
+
+// ServiceStartup 服务启动
+func (s *WorkspaceService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return s.DefaultServiceStartup(ctx, options)
+}
+
+// ServiceShutdown 服务关闭
+func (s *WorkspaceService) ServiceShutdown() error {
+	return s.DefaultServiceShutdown()
+}
+
+// ListWorkspaces 列出全部工作区摘要，最近使用的在前。
+func (s *WorkspaceService) ListWorkspaces() *types.WorkspaceListResult {
+	list, err := s.store.List()
+	if err != nil {
+		return &types.WorkspaceListResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.WorkspaceListResult{BaseResult: types.BaseResult{Success: true, Message: "获取工作区列表成功"}, Data: list}
+}
+
+// SaveWorkspace 新建或更新工作区（ID 为空时新建），返回保存后的工作区。
+func (s *WorkspaceService) SaveWorkspace(ws *workspace.Workspace) *types.WorkspaceResult {
+	v := validate.New().Check(ws != nil, "workspace", validate.CodeRequired, "workspace 不能为空")
+	if ws != nil {
+		v.Required("name", ws.Name)
+	}
+	if err := v.Err(); err != nil {
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	saved, err := s.store.Save(ws)
+	if err != nil {
+		s.Logger().Warn("保存工作区失败", "name", ws.Name, "error", err)
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: true, Message: "保存工作区成功"}, Data: saved}
+}
+
+// LoadWorkspace 按 ID 或名称加载工作区，并记为最近打开的工作区。
+func (s *WorkspaceService) LoadWorkspace(idOrName string) *types.WorkspaceResult {
+	if err := validate.New().Required("idOrName", idOrName).Err(); err != nil {
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	ws, err := s.store.Open(idOrName)
+	if err != nil {
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: true, Message: "加载工作区成功"}, Data: ws}
+}
+
+// GetLastWorkspace 返回最近打开的工作区，用于启动时恢复；没有时 Data 为空。
+func (s *WorkspaceService) GetLastWorkspace() *types.WorkspaceResult {
+	ws, err := s.store.Last()
+	if err != nil {
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: false, Message: err.Error()}}
+	}
+	if ws == nil {
+		return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: true, Message: "没有可恢复的工作区"}}
+	}
+	return &types.WorkspaceResult{BaseResult: types.BaseResult{Success: true, Message: "获取工作区成功"}, Data: ws}
+}
+
+// DeleteWorkspace 删除工作区。
+func (s *WorkspaceService) DeleteWorkspace(id string) *types.BaseResult {
+	if err := validate.New().Required("id", id).Err(); err != nil {
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	if err := s.store.Delete(id); err != nil {
+		if !errors.Is(err, workspace.ErrWorkspaceNotFound) {
+			s.Logger().Warn("删除工作区失败", "id", id, "error", err)
+		}
+		return &types.BaseResult{Success: false, Message: err.Error()}
+	}
+	return &types.BaseResult{Success: true, Message: "删除工作区成功"}
+}
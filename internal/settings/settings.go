@@ -0,0 +1,209 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package settings 保存应用设置（编辑器偏好、默认行数、日志级别、主题与操作确认），
+// 以带版本号的 JSON 文件持久化，加载时按版本依次迁移到当前结构。
+package settings
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// 主题取值。
+const (
+	ThemeSystem = "system" // 跟随系统
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// 日志级别取值。
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// 取值范围。
+const (
+	MinFontSize        = 8
+	MaxFontSize        = 48
+	MinTabSize         = 1
+	MaxTabSize         = 16
+	MaxDefaultRowLimit = 100000
+	MaxFontFamilyLen   = 256
+	MaxConcurrency     = 64
+	MaxRetryAttempts   = 10
+)
+
+// EditorSettings 是 SQL 编辑器偏好。
+type EditorSettings struct {
+	FontFamily   string `json:"fontFamily"`   // 字体，为空时使用前端默认字体
+	FontSize     int    `json:"fontSize"`     // 字号
+	TabSize      int    `json:"tabSize"`      // 缩进宽度
+	InsertSpaces bool   `json:"insertSpaces"` // 使用空格缩进
+	WordWrap     bool   `json:"wordWrap"`     // 自动换行
+	LineNumbers  bool   `json:"lineNumbers"`  // 显示行号
+	AutoComplete bool   `json:"autoComplete"` // 自动补全
+}
+
+// QuerySettings 是查询与数据浏览设置。
+type QuerySettings struct {
+	DefaultRowLimit int `json:"defaultRowLimit"` // 浏览表数据与执行查询时默认返回的行数
+	MaxConcurrency  int `json:"maxConcurrency"`  // 每个连接同时执行的语句数，超出的请求排队等待
+	RetryAttempts   int `json:"retryAttempts"`   // 建连与只读查询遇到瞬时错误时的重试次数，0 表示不重试
+}
+
+// ConfirmSettings 控制哪些操作执行前需要用户确认。
+type ConfirmSettings struct {
+	DangerousSQL         bool `json:"dangerousSql"`         // 执行无 WHERE 的 UPDATE/DELETE、DROP、TRUNCATE 等语句
+	DeleteRows           bool `json:"deleteRows"`           // 在表格中删除行
+	DropObjects          bool `json:"dropObjects"`          // 删除库、表、视图等对象
+	CloseUnsavedTab      bool `json:"closeUnsavedTab"`      // 关闭有未保存内容的标签页
+	CloseRunningTerminal bool `json:"closeRunningTerminal"` // 关闭仍有命令在运行的终端
+}
+
+// Settings 是应用设置，SchemaVersion 由存储维护。
+type Settings struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Theme         string          `json:"theme"`    // 主题：system、light、dark
+	LogLevel      string          `json:"logLevel"` // 日志级别：debug、info、warn、error
+	Editor        EditorSettings  `json:"editor"`
+	Query         QuerySettings   `json:"query"`
+	Confirm       ConfirmSettings `json:"confirm"`
+	Metrics       bool            `json:"metrics"` // 采集本地性能指标（默认关闭）
+}
+
+// Defaults 返回默认设置：危险操作全部需要确认。
+func Defaults() Settings {
+	return Settings{
+		SchemaVersion: CurrentVersion,
+		Theme:         ThemeSystem,
+		LogLevel:      LogLevelInfo,
+		Editor: EditorSettings{
+			FontSize:     14,
+			TabSize:      2,
+			InsertSpaces: true,
+			LineNumbers:  true,
+			AutoComplete: true,
+		},
+		Query: QuerySettings{DefaultRowLimit: 500, MaxConcurrency: 4, RetryAttempts: 2},
+		Confirm: ConfirmSettings{
+			DangerousSQL:         true,
+			DeleteRows:           true,
+			DropObjects:          true,
+			CloseUnsavedTab:      true,
+			CloseRunningTerminal: true,
+		},
+	}
+}
+
+// Validate 校验设置取值，返回字段级错误（*validate.Error）。
+func (s Settings) Validate() error {
+	return s.validator().Err()
+}
+
+// validator 收集全部字段错误。
+func (s Settings) validator() *validate.Validator {
+	return validate.New().
+		OneOf("theme", s.Theme, ThemeSystem, ThemeLight, ThemeDark).
+		OneOf("logLevel", s.LogLevel, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError).
+		Check(len(s.Editor.FontFamily) <= MaxFontFamilyLen, "editor.fontFamily", validate.CodeTooLong, "editor.fontFamily 过长").
+		Range("editor.fontSize", s.Editor.FontSize, MinFontSize, MaxFontSize).
+		Range("editor.tabSize", s.Editor.TabSize, MinTabSize, MaxTabSize).
+		Range("query.defaultRowLimit", s.Query.DefaultRowLimit, 1, MaxDefaultRowLimit).
+		Range("query.maxConcurrency", s.Query.MaxConcurrency, 1, MaxConcurrency).
+		Range("query.retryAttempts", s.Query.RetryAttempts, 0, MaxRetryAttempts)
+}
+
+// normalize 统一枚举值的大小写与空白。
+func (s *Settings) normalize() {
+	s.Theme = strings.ToLower(strings.TrimSpace(s.Theme))
+	s.LogLevel = strings.ToLower(strings.TrimSpace(s.LogLevel))
+	s.Editor.FontFamily = strings.TrimSpace(s.Editor.FontFamily)
+}
+
+// sanitize 将不合法的字段替换为默认值，返回被替换的字段名；用于加载手工编辑或损坏的设置文件。
+func (s *Settings) sanitize() []string {
+	s.normalize()
+	ve, ok := validate.AsError(s.Validate())
+	if !ok {
+		return nil
+	}
+	def := Defaults()
+	fields := make([]string, 0, len(ve.Fields))
+	for _, f := range ve.Fields {
+		switch f.Field {
+		case "theme":
+			s.Theme = def.Theme
+		case "logLevel":
+			s.LogLevel = def.LogLevel
+		case "editor.fontFamily":
+			s.Editor.FontFamily = def.Editor.FontFamily
+		case "editor.fontSize":
+			s.Editor.FontSize = def.Editor.FontSize
+		case "editor.tabSize":
+			s.Editor.TabSize = def.Editor.TabSize
+		case "query.defaultRowLimit":
+			s.Query.DefaultRowLimit = def.Query.DefaultRowLimit
+		case "query.maxConcurrency":
+			s.Query.MaxConcurrency = def.Query.MaxConcurrency
+		case "query.retryAttempts":
+			s.Query.RetryAttempts = def.Query.RetryAttempts
+		}
+		fields = append(fields, f.Field)
+	}
+	return fields
+}
+
+// SlogLevel 返回日志级别对应的 slog.Level，无法识别时返回 Info。
+func (s Settings) SlogLevel() slog.Level {
+	switch strings.ToLower(s.LogLevel) {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// ChangedSections 返回两份设置之间发生变化的顶层字段（theme、logLevel、editor、query、confirm）。
+func ChangedSections(old, next Settings) []string {
+	var changed []string
+	if old.Theme != next.Theme {
+		changed = append(changed, "theme")
+	}
+	if old.LogLevel != next.LogLevel {
+		changed = append(changed, "logLevel")
+	}
+	if old.Editor != next.Editor {
+		changed = append(changed, "editor")
+	}
+	if old.Query != next.Query {
+		changed = append(changed, "query")
+	}
+	if old.Confirm != next.Confirm {
+		changed = append(changed, "confirm")
+	}
+	if old.Metrics != next.Metrics {
+		changed = append(changed, "metrics")
+	}
+	return changed
+}
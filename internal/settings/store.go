@@ -0,0 +1,196 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CurrentVersion 当前设置结构版本。修改结构时递增版本并在 migrations 中登记上一版本的迁移函数。
+const CurrentVersion = 1
+
+// migration 将 from 版本的原始设置改写为 from+1 版本。
+type migration func(raw map[string]any) error
+
+// migrations 按起始版本登记的迁移函数。
+//
+// 版本 0 是未写入 schemaVersion 的设置文件，字段布局与版本 1 相同，无需改写。
+var migrations = map[int]migration{
+	0: func(map[string]any) error { return nil },
+}
+
+// Store 保存应用设置，可并发使用。
+type Store struct {
+	mu       sync.RWMutex
+	path     string
+	logger   *slog.Logger
+	settings Settings
+}
+
+// DefaultPath 返回默认的设置文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "settings.json")
+	}
+	return filepath.Join(configDir, "Boxify", "settings.json")
+}
+
+// NewStore 创建设置存储，path 为空时使用默认路径；调用 Load 前返回默认设置。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{path: path, logger: logger.With("module", "settings"), settings: Defaults()}
+}
+
+// Path 返回设置文件路径。
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load 读取设置文件：旧版本按顺序迁移并写回（原文件备份为 settings.json.v<版本>.bak），
+// 不合法的字段替换为默认值。文件不存在时使用默认设置；读取失败时保留默认设置并返回错误。
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.settings = Defaults()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取设置失败：%w", err)
+	}
+
+	loaded, version, err := decode(data)
+	if err != nil {
+		return err
+	}
+	if fields := loaded.sanitize(); len(fields) > 0 {
+		s.logger.Warn("设置中的部分字段不合法，已使用默认值", "fields", fields)
+	}
+	s.settings = loaded
+
+	if version > CurrentVersion {
+		s.logger.Warn("设置文件来自更新版本的应用，未识别的字段将被忽略", "version", version)
+	}
+	if version < CurrentVersion {
+		backup := fmt.Sprintf("%s.v%d.bak", s.path, version)
+		if err := os.WriteFile(backup, data, 0o600); err != nil {
+			return fmt.Errorf("备份旧版本设置失败：%w", err)
+		}
+		if err := s.writeLocked(loaded); err != nil {
+			return err
+		}
+		s.logger.Info("设置已迁移", "from", version, "to", CurrentVersion, "backup", backup)
+	}
+	return nil
+}
+
+// Get 返回当前设置。
+func (s *Store) Get() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings
+}
+
+// Update 校验并保存设置，返回保存前后的设置。
+func (s *Store) Update(next Settings) (old, saved Settings, err error) {
+	next.normalize()
+	next.SchemaVersion = CurrentVersion
+	if err := next.Validate(); err != nil {
+		return Settings{}, Settings{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.writeLocked(next); err != nil {
+		return Settings{}, Settings{}, err
+	}
+	old = s.settings
+	s.settings = next
+	return old, next, nil
+}
+
+// Reset 恢复默认设置并保存，返回保存前后的设置。
+func (s *Store) Reset() (old, saved Settings, err error) {
+	return s.Update(Defaults())
+}
+
+// writeLocked 写入设置文件，先写临时文件再替换；调用方需持有锁。
+func (s *Store) writeLocked(settings Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化设置失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入设置失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入设置失败：%w", err)
+	}
+	return nil
+}
+
+// decode 解析设置文件并迁移到当前版本，返回设置与文件原始版本。
+// 文件中缺失的字段使用默认值；版本高于当前版本时（由更新的应用写入）按当前结构尽量读取。
+func decode(data []byte) (Settings, int, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Defaults(), 0, fmt.Errorf("解析设置失败：%w", err)
+	}
+	if raw == nil {
+		raw = map[string]any{}
+	}
+
+	version := 0
+	if v, ok := raw["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	for v := version; v < CurrentVersion; v++ {
+		migrate, ok := migrations[v]
+		if !ok {
+			return Defaults(), version, fmt.Errorf("不支持从版本 %d 迁移设置", v)
+		}
+		if err := migrate(raw); err != nil {
+			return Defaults(), version, fmt.Errorf("迁移设置（版本 %d）失败：%w", v, err)
+		}
+	}
+	raw["schemaVersion"] = CurrentVersion
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return Defaults(), version, fmt.Errorf("序列化设置失败：%w", err)
+	}
+	settings := Defaults()
+	if err := json.Unmarshal(migrated, &settings); err != nil {
+		return Defaults(), version, fmt.Errorf("解析设置失败：%w", err)
+	}
+	return settings, version, nil
+}
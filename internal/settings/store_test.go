@@ -0,0 +1,124 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+func TestStoreDefaultsAndUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	s := NewStore(path, nil)
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(s.Get(), Defaults()) {
+		t.Fatalf("文件不存在时应使用默认设置: %+v", s.Get())
+	}
+
+	next := s.Get()
+	next.Theme = " Dark "
+	next.Query.DefaultRowLimit = 1000
+	next.Confirm.DeleteRows = false
+	old, saved, err := s.Update(next)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if saved.Theme != ThemeDark || old.Theme != ThemeSystem {
+		t.Fatalf("Update() old=%q saved=%q", old.Theme, saved.Theme)
+	}
+	if got := ChangedSections(old, saved); !reflect.DeepEqual(got, []string{"theme", "query", "confirm"}) {
+		t.Fatalf("ChangedSections() = %v", got)
+	}
+
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.Get(), saved) {
+		t.Fatalf("重新加载后设置不一致: %+v", reloaded.Get())
+	}
+}
+
+func TestStoreUpdateRejectsInvalid(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "settings.json"), nil)
+	next := Defaults()
+	next.LogLevel = "verbose"
+	next.Editor.FontSize = 2
+	_, _, err := s.Update(next)
+	ve, ok := validate.AsError(err)
+	if !ok || len(ve.Fields) != 2 || ve.Fields[0].Field != "logLevel" || ve.Fields[1].Field != "editor.fontSize" {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if s.Get() != Defaults() {
+		t.Fatalf("校验失败时不应修改设置: %+v", s.Get())
+	}
+}
+
+func TestStoreLoadMigratesUnversionedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	legacy := `{"theme":"light","editor":{"fontSize":200},"query":{"defaultRowLimit":50}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(path, nil)
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := s.Get()
+	if got.SchemaVersion != CurrentVersion || got.Theme != ThemeLight || got.Query.DefaultRowLimit != 50 {
+		t.Fatalf("迁移结果错误: %+v", got)
+	}
+	if got.Editor.FontSize != Defaults().Editor.FontSize || got.Editor.TabSize != Defaults().Editor.TabSize || !got.Confirm.DangerousSQL {
+		t.Fatalf("不合法与缺失的字段应使用默认值: %+v", got)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != legacy {
+		t.Fatalf("应备份迁移前的文件: %q, %v", backup, err)
+	}
+	data, _ := os.ReadFile(path)
+	var written Settings
+	if err := json.Unmarshal(data, &written); err != nil || written != got {
+		t.Fatalf("迁移后应写回当前版本: %s", data)
+	}
+}
+
+func TestStoreLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"corrupt": "{not-json",
+		"version": `{"schemaVersion":-1}`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		s := NewStore(path, nil)
+		if err := s.Load(); err == nil {
+			t.Fatalf("%s: Load() 应返回错误", name)
+		}
+		if s.Get() != Defaults() {
+			t.Fatalf("%s: 加载失败时应保留默认设置", name)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowquery
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 排序方式。
+const (
+	SortByTotalTime = "totalTime"
+	SortByMeanTime  = "meanTime"
+	SortByCalls     = "calls"
+	SortByRows      = "rows"
+)
+
+// DefaultTopN 是未指定条数时返回的指纹数量。
+const DefaultTopN = 50
+
+// maxExampleLen 是示例语句保留的最大长度。
+const maxExampleLen = 4096
+
+// Sample 是一次慢查询记录，时间单位为毫秒。
+type Sample struct {
+	Query        string    `json:"query"`
+	Database     string    `json:"database"`
+	User         string    `json:"user"`
+	Host         string    `json:"host"`
+	Time         time.Time `json:"time"`
+	QueryTimeMs  float64   `json:"queryTimeMs"`
+	LockTimeMs   float64   `json:"lockTimeMs"`
+	RowsSent     int64     `json:"rowsSent"`
+	RowsExamined int64     `json:"rowsExamined"`
+}
+
+// Stat 是同一指纹的汇总统计，时间单位为毫秒。
+type Stat struct {
+	Fingerprint  string    `json:"fingerprint"`
+	Example      string    `json:"example"`
+	Database     string    `json:"database"`
+	Calls        int64     `json:"calls"`
+	TotalTimeMs  float64   `json:"totalTimeMs"`
+	MeanTimeMs   float64   `json:"meanTimeMs"`
+	MaxTimeMs    float64   `json:"maxTimeMs"`
+	LockTimeMs   float64   `json:"lockTimeMs"`
+	Rows         int64     `json:"rows"`
+	RowsExamined int64     `json:"rowsExamined"`
+	FirstSeen    time.Time `json:"firstSeen,omitempty"`
+	LastSeen     time.Time `json:"lastSeen,omitempty"`
+}
+
+// Report 是慢查询分析结果。
+type Report struct {
+	Source       string  `json:"source"`
+	SortBy       string  `json:"sortBy"`
+	Fingerprints int     `json:"fingerprints"`
+	Calls        int64   `json:"calls"`
+	TotalTimeMs  float64 `json:"totalTimeMs"`
+	Top          []*Stat `json:"top"`
+}
+
+// Aggregator 按指纹聚合慢查询。零值不可用，使用 NewAggregator 创建。
+type Aggregator struct {
+	stats map[string]*Stat
+}
+
+// NewAggregator 创建聚合器。
+func NewAggregator() *Aggregator {
+	return &Aggregator{stats: make(map[string]*Stat)}
+}
+
+// Add 累加一条慢查询记录。
+func (a *Aggregator) Add(s Sample) {
+	st := a.stat(s.Query, s.Database)
+	st.Calls++
+	st.TotalTimeMs += s.QueryTimeMs
+	st.LockTimeMs += s.LockTimeMs
+	st.Rows += s.RowsSent
+	st.RowsExamined += s.RowsExamined
+	st.MaxTimeMs = max(st.MaxTimeMs, s.QueryTimeMs)
+	if !s.Time.IsZero() {
+		if st.FirstSeen.IsZero() || s.Time.Before(st.FirstSeen) {
+			st.FirstSeen = s.Time
+		}
+		if s.Time.After(st.LastSeen) {
+			st.LastSeen = s.Time
+		}
+	}
+}
+
+// AddStat 合并已聚合的统计（如 performance_schema 摘要或 pg_stat_statements），不同来源的同一指纹会合并为一项。
+func (a *Aggregator) AddStat(in Stat) {
+	st := a.stat(in.Example, in.Database)
+	st.Calls += in.Calls
+	st.TotalTimeMs += in.TotalTimeMs
+	st.LockTimeMs += in.LockTimeMs
+	st.Rows += in.Rows
+	st.RowsExamined += in.RowsExamined
+	st.MaxTimeMs = max(st.MaxTimeMs, in.MaxTimeMs)
+}
+
+// stat 返回指纹对应的统计项，不存在时以 query 作为示例创建。
+func (a *Aggregator) stat(query, database string) *Stat {
+	fp := Fingerprint(query)
+	key := database + "\x00" + fp
+	st, ok := a.stats[key]
+	if !ok {
+		example := query
+		if len(example) > maxExampleLen {
+			example = example[:maxExampleLen]
+		}
+		st = &Stat{Fingerprint: fp, Example: example, Database: database}
+		a.stats[key] = st
+	}
+	return st
+}
+
+// Report 按 sortBy 返回前 limit 个指纹；limit <= 0 时使用 DefaultTopN。
+func (a *Aggregator) Report(source, sortBy string, limit int) (*Report, error) {
+	if sortBy == "" {
+		sortBy = SortByTotalTime
+	}
+	key, err := sortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultTopN
+	}
+
+	report := &Report{Source: source, SortBy: sortBy, Fingerprints: len(a.stats)}
+	all := make([]*Stat, 0, len(a.stats))
+	for _, st := range a.stats {
+		if st.Calls > 0 {
+			st.MeanTimeMs = st.TotalTimeMs / float64(st.Calls)
+		}
+		report.Calls += st.Calls
+		report.TotalTimeMs += st.TotalTimeMs
+		all = append(all, st)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		ki, kj := key(all[i]), key(all[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return all[i].Fingerprint < all[j].Fingerprint
+	})
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	report.Top = all
+	return report, nil
+}
+
+// sortKey 返回排序字段的取值函数。
+func sortKey(sortBy string) (func(*Stat) float64, error) {
+	switch sortBy {
+	case SortByTotalTime:
+		return func(s *Stat) float64 { return s.TotalTimeMs }, nil
+	case SortByMeanTime:
+		return func(s *Stat) float64 { return s.MeanTimeMs }, nil
+	case SortByCalls:
+		return func(s *Stat) float64 { return float64(s.Calls) }, nil
+	case SortByRows:
+		return func(s *Stat) float64 { return float64(s.Rows) }, nil
+	default:
+		return nil, fmt.Errorf("不支持的排序方式: %s", sortBy)
+	}
+}
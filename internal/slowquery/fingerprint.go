@@ -0,0 +1,103 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowquery
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/chenyang-zz/boxify/internal/sqllint"
+)
+
+var (
+	fpCommaPattern  = regexp.MustCompile(`\s*,\s*`)
+	fpOpenPattern   = regexp.MustCompile(`\(\s+`)
+	fpClosePattern  = regexp.MustCompile(`\s+\)`)
+	fpListPattern   = regexp.MustCompile(`\(\?(, \?)+\)`)
+	fpValuesPattern = regexp.MustCompile(`(\((\?|\?\+)\))(, \((\?|\?\+)\))+`)
+)
+
+// Fingerprint 将 SQL 归一化为指纹：去掉注释，字符串、数字与占位参数替换为 ?，
+// 未引用的关键字与标识符转为小写，空白合并，IN 列表与多行 VALUES 折叠为 (?+)。
+// 仅字面量不同的语句得到相同指纹。
+func Fingerprint(query string) string {
+	runes := []rune(query)
+	var sb strings.Builder
+	sb.Grow(len(query))
+	space := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), " ") {
+			sb.WriteByte(' ')
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			space()
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space()
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			space()
+		case r == '\'':
+			i = sqllint.SkipQuoted(runes, i, '\'', true)
+			sb.WriteByte('?')
+		case r == '`' || r == '"':
+			end := sqllint.SkipQuoted(runes, i, r, false)
+			sb.WriteString(string(runes[i:min(end, len(runes))]))
+			i = end
+		case r == '$' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			sb.WriteByte('?')
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			sb.WriteString(strings.ToLower(string(runes[start:i])))
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			// 0x 十六进制与科学计数法一并视为数字
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			sb.WriteByte('?')
+		default:
+			sb.WriteRune(r)
+			i++
+		}
+	}
+
+	fp := strings.TrimSpace(sb.String())
+	fp = strings.TrimSpace(strings.TrimRight(fp, "; "))
+	fp = fpCommaPattern.ReplaceAllString(fp, ", ")
+	fp = fpOpenPattern.ReplaceAllString(fp, "(")
+	fp = fpClosePattern.ReplaceAllString(fp, ")")
+	fp = fpListPattern.ReplaceAllString(fp, "(?+)")
+	fp = fpValuesPattern.ReplaceAllString(fp, "$1")
+	return fp
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowquery
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLineSize 是慢日志单行的最大长度，超长的批量 INSERT 会被截断。
+const maxLineSize = 16 * 1024 * 1024
+
+// ParseMySQLSlowLog 解析 MySQL/MariaDB 慢查询日志，每解析出一条记录调用一次 fn。
+//
+// 记录由 "# Time"、"# User@Host"、"# Query_time" 注释行开头，随后是 "use db;"、
+// "SET timestamp=...;" 与语句本身；服务启动时写入的文件头会被跳过。
+func ParseMySQLSlowLog(r io.Reader, fn func(Sample)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var cur Sample
+	var query strings.Builder
+	hasHeader := false
+	database := ""
+
+	flush := func() {
+		q := strings.TrimSpace(query.String())
+		if hasHeader && q != "" {
+			cur.Query = q
+			cur.Database = database
+			fn(cur)
+		}
+		query.Reset()
+		cur = Sample{}
+		hasHeader = false
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# Time:"):
+			flush()
+			cur.Time = parseSlowLogTime(strings.TrimSpace(strings.TrimPrefix(trimmed, "# Time:")))
+		case strings.HasPrefix(trimmed, "# User@Host:"):
+			if query.Len() > 0 {
+				t := cur.Time
+				flush()
+				cur.Time = t
+			}
+			cur.User, cur.Host = parseUserHost(strings.TrimPrefix(trimmed, "# User@Host:"))
+		case strings.HasPrefix(trimmed, "# Query_time:"):
+			parseQueryStats(trimmed[1:], &cur)
+			hasHeader = true
+		case strings.HasPrefix(trimmed, "#"):
+			// MariaDB 的 Thread_id、Full_scan 等扩展行
+		case isSlowLogPreamble(trimmed):
+			flush()
+		case query.Len() == 0 && strings.HasPrefix(strings.ToLower(trimmed), "use ") && strings.HasSuffix(trimmed, ";"):
+			database = strings.Trim(strings.TrimSuffix(trimmed[4:], ";"), " `")
+		case query.Len() == 0 && strings.HasPrefix(strings.ToLower(trimmed), "set timestamp="):
+			ts, err := strconv.ParseInt(strings.TrimSuffix(trimmed[len("set timestamp="):], ";"), 10, 64)
+			if err == nil && cur.Time.IsZero() {
+				cur.Time = time.Unix(ts, 0)
+			}
+		default:
+			if query.Len() > 0 {
+				query.WriteByte('\n')
+			}
+			query.WriteString(line)
+		}
+	}
+	flush()
+	return scanner.Err()
+}
+
+// isSlowLogPreamble 判断是否为服务启动时写入的文件头行。
+func isSlowLogPreamble(line string) bool {
+	return strings.Contains(line, ", Version: ") && strings.Contains(line, "started with") ||
+		strings.HasPrefix(line, "Tcp port:") ||
+		strings.HasPrefix(line, "Time ") && strings.Contains(line, "Id Command")
+}
+
+// parseSlowLogTime 解析 MySQL 5.7+ 的 RFC3339 时间或 5.6 及 MariaDB 的 "yymmdd hh:mm:ss" 时间。
+func parseSlowLogTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "060102 15:04:05", "060102  15:04:05", "060102 1:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseUserHost 解析 "root[root] @ localhost [127.0.0.1]  Id: 8" 形式的账号信息。
+func parseUserHost(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, "Id:"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	userPart, hostPart, _ := strings.Cut(value, "@")
+	user := strings.TrimSpace(userPart)
+	if idx := strings.Index(user, "["); idx >= 0 {
+		user = user[:idx]
+	}
+	host := strings.TrimSpace(hostPart)
+	if name, ip, ok := strings.Cut(host, "["); ok {
+		host = strings.TrimSpace(name)
+		if host == "" {
+			host = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(ip), "]"))
+		}
+	}
+	return user, host
+}
+
+// parseQueryStats 解析 "Query_time: 2.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0" 行。
+func parseQueryStats(line string, s *Sample) {
+	fields := strings.Fields(line)
+	for i := 0; i+1 < len(fields); i += 2 {
+		value := fields[i+1]
+		switch strings.TrimSuffix(fields[i], ":") {
+		case "Query_time":
+			f, _ := strconv.ParseFloat(value, 64)
+			s.QueryTimeMs = f * 1000
+		case "Lock_time":
+			f, _ := strconv.ParseFloat(value, 64)
+			s.LockTimeMs = f * 1000
+		case "Rows_sent":
+			s.RowsSent, _ = strconv.ParseInt(value, 10, 64)
+		case "Rows_examined":
+			s.RowsExamined, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+}
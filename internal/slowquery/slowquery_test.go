@@ -0,0 +1,123 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	cases := map[string]string{
+		"SELECT * FROM `Users` WHERE id = 42 AND name = 'bob''s'":      "select * from `Users` where id = ? and name = ?",
+		"select *   from `Users`\n where ID=7 and NAME = \"x\" -- c\n": "select * from `Users` where id=? and name = \"x\"",
+		"SELECT a FROM t WHERE id IN (1, 2,3) /* hint */;":             "select a from t where id in (?+)",
+		"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), (3, 'z')":     "insert into t (a, b) values (?+)",
+		"UPDATE t SET v = $1 WHERE id = $2":                            "update t set v = ? where id = ?",
+		"select -1.5e3, 0xFF":                                          "select -?, ?",
+	}
+	for in, want := range cases {
+		if got := Fingerprint(in); got != want {
+			t.Errorf("Fingerprint(%q)\n得到 %q\n期望 %q", in, got, want)
+		}
+	}
+}
+
+const sampleSlowLog = `/usr/sbin/mysqld, Version: 8.0.36 (MySQL Community Server - GPL). started with:
+Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+Time                 Id Command    Argument
+# Time: 2024-05-01T10:00:00.123456Z
+# User@Host: app[app] @ web-1 [10.0.0.5]  Id:    12
+# Query_time: 2.500000  Lock_time: 0.000100 Rows_sent: 10  Rows_examined: 5000
+use shop;
+SET timestamp=1714557600;
+SELECT *
+FROM orders WHERE customer_id = 17;
+# Time: 2024-05-01T10:05:00Z
+# User@Host: app[app] @  [10.0.0.6]  Id:    13
+# Query_time: 0.500000  Lock_time: 0.000000 Rows_sent: 2  Rows_examined: 800
+SET timestamp=1714557900;
+SELECT * FROM orders WHERE customer_id = 99;
+# User@Host: batch[batch] @ localhost []  Id:    14
+# Query_time: 9.000000  Lock_time: 1.000000 Rows_sent: 0  Rows_examined: 100000
+SET timestamp=1714557900;
+DELETE FROM audit WHERE created_at < '2023-01-01';
+`
+
+func TestParseMySQLSlowLogAndAggregate(t *testing.T) {
+	var samples []Sample
+	if err := ParseMySQLSlowLog(strings.NewReader(sampleSlowLog), func(s Sample) { samples = append(samples, s) }); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("应解析出 3 条记录，得到 %d: %+v", len(samples), samples)
+	}
+	first := samples[0]
+	if first.User != "app" || first.Host != "web-1" || first.Database != "shop" || first.QueryTimeMs != 2500 || first.RowsExamined != 5000 {
+		t.Errorf("首条记录解析不符: %+v", first)
+	}
+	if samples[1].Host != "10.0.0.6" || samples[2].User != "batch" || samples[2].Time.IsZero() {
+		t.Errorf("账号或时间解析不符: %+v / %+v", samples[1], samples[2])
+	}
+
+	agg := NewAggregator()
+	for _, s := range samples {
+		agg.Add(s)
+	}
+	report, err := agg.Report(SourceSlowLog, SortByCalls, 1)
+	if err != nil {
+		t.Fatalf("Report 失败: %v", err)
+	}
+	if report.Fingerprints != 2 || report.Calls != 3 || len(report.Top) != 1 {
+		t.Fatalf("汇总不符: %+v", report)
+	}
+	top := report.Top[0]
+	if top.Calls != 2 || top.TotalTimeMs != 3000 || top.MeanTimeMs != 1500 || top.MaxTimeMs != 2500 || top.Rows != 12 {
+		t.Errorf("按调用次数排序的首项不符: %+v", top)
+	}
+	if top.LastSeen.Before(top.FirstSeen) || top.FirstSeen.IsZero() {
+		t.Errorf("首末出现时间不符: %v %v", top.FirstSeen, top.LastSeen)
+	}
+
+	report, _ = agg.Report(SourceSlowLog, "", 0)
+	if report.Top[0].Fingerprint != "delete from audit where created_at < ?" {
+		t.Errorf("默认应按总耗时排序: %+v", report.Top[0])
+	}
+	if _, err := agg.Report(SourceSlowLog, "bogus", 0); err == nil {
+		t.Error("应拒绝未知排序方式")
+	}
+}
+
+func TestFromPgStatStatementsFallsBackToLegacyColumns(t *testing.T) {
+	var queries []string
+	query := func(q string, args ...any) ([]map[string]interface{}, []string, error) {
+		queries = append(queries, q)
+		if strings.Contains(q, "total_exec_time") {
+			return nil, nil, errors.New(`column "total_exec_time" does not exist`)
+		}
+		return []map[string]interface{}{
+			{"datname": "shop", "query": "SELECT * FROM t WHERE id = $1", "calls": int64(10), "total_time": 55.5, "max_time": "20", "rows": int64(10)},
+		}, nil, nil
+	}
+	agg := NewAggregator()
+	if err := FromPgStatStatements(query, 100, agg); err != nil {
+		t.Fatalf("FromPgStatStatements 失败: %v", err)
+	}
+	report, _ := agg.Report(SourcePgStatStatements, SortByTotalTime, 10)
+	if len(queries) != 2 || len(report.Top) != 1 || report.Top[0].Calls != 10 || report.Top[0].MaxTimeMs != 20 {
+		t.Errorf("回退或解析不符: %v %+v", queries, report.Top)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowquery
+
+import (
+	"fmt"
+
+	"github.com/chenyang-zz/boxify/internal/utils"
+)
+
+// 慢查询来源。
+const (
+	SourceSlowLog           = "slowLog"
+	SourcePerformanceSchema = "performanceSchema"
+	SourcePgStatStatements  = "pgStatStatements"
+)
+
+// QueryFunc 与 db.Database.Query 签名一致。
+type QueryFunc func(query string, args ...any) ([]map[string]interface{}, []string, error)
+
+// performanceSchemaSQL 读取语句摘要；计时器单位为皮秒。
+const performanceSchemaSQL = `SELECT SCHEMA_NAME, DIGEST_TEXT, COUNT_STAR, SUM_TIMER_WAIT, MAX_TIMER_WAIT, SUM_LOCK_TIME,
+       SUM_ROWS_SENT, SUM_ROWS_EXAMINED
+FROM performance_schema.events_statements_summary_by_digest
+WHERE DIGEST_TEXT IS NOT NULL
+ORDER BY SUM_TIMER_WAIT DESC
+LIMIT ?`
+
+// pgStatStatementsSQL 适用于 PostgreSQL 13 起的列名；更早的版本回退到 pgStatStatementsLegacySQL。
+const (
+	pgStatStatementsSQL = `SELECT d.datname, s.query, s.calls, s.total_exec_time AS total_time, s.max_exec_time AS max_time, s.rows
+FROM pg_stat_statements s LEFT JOIN pg_database d ON d.oid = s.dbid
+ORDER BY s.total_exec_time DESC
+LIMIT $1`
+	pgStatStatementsLegacySQL = `SELECT d.datname, s.query, s.calls, s.total_time, s.max_time, s.rows
+FROM pg_stat_statements s LEFT JOIN pg_database d ON d.oid = s.dbid
+ORDER BY s.total_time DESC
+LIMIT $1`
+)
+
+// FromPerformanceSchema 从 performance_schema 语句摘要读取至多 maxRows 条并合并到聚合器。
+func FromPerformanceSchema(query QueryFunc, maxRows int, agg *Aggregator) error {
+	rows, _, err := query(performanceSchemaSQL, maxRows)
+	if err != nil {
+		return fmt.Errorf("读取 performance_schema 失败（需开启 performance_schema 并具备读取权限）: %w", err)
+	}
+	const picosPerMs = 1e9
+	for _, row := range rows {
+		agg.AddStat(Stat{
+			Example:      toString(row["DIGEST_TEXT"]),
+			Database:     toString(row["SCHEMA_NAME"]),
+			Calls:        utils.ToInt64(row["COUNT_STAR"]),
+			TotalTimeMs:  utils.ToFloat64(row["SUM_TIMER_WAIT"]) / picosPerMs,
+			MaxTimeMs:    utils.ToFloat64(row["MAX_TIMER_WAIT"]) / picosPerMs,
+			LockTimeMs:   utils.ToFloat64(row["SUM_LOCK_TIME"]) / picosPerMs,
+			Rows:         utils.ToInt64(row["SUM_ROWS_SENT"]),
+			RowsExamined: utils.ToInt64(row["SUM_ROWS_EXAMINED"]),
+		})
+	}
+	return nil
+}
+
+// FromPgStatStatements 从 pg_stat_statements 读取至多 maxRows 条并合并到聚合器，时间列单位已是毫秒。
+func FromPgStatStatements(query QueryFunc, maxRows int, agg *Aggregator) error {
+	rows, _, err := query(pgStatStatementsSQL, maxRows)
+	if err != nil {
+		var legacyErr error
+		rows, _, legacyErr = query(pgStatStatementsLegacySQL, maxRows)
+		if legacyErr != nil {
+			return fmt.Errorf("读取 pg_stat_statements 失败（需安装该扩展并具备读取权限）: %w", err)
+		}
+	}
+	for _, row := range rows {
+		agg.AddStat(Stat{
+			Example:     toString(row["query"]),
+			Database:    toString(row["datname"]),
+			Calls:       utils.ToInt64(row["calls"]),
+			TotalTimeMs: utils.ToFloat64(row["total_time"]),
+			MaxTimeMs:   utils.ToFloat64(row["max_time"]),
+			Rows:        utils.ToInt64(row["rows"]),
+		})
+	}
+	return nil
+}
+
+// toString 将查询结果转换为字符串，nil 返回空字符串。
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return fmt.Sprint(s)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxRows 单个快照最多保存的行数。
+const DefaultMaxRows = 100000
+
+// ErrNotFound 表示快照不存在。
+var ErrNotFound = errors.New("快照不存在")
+
+// Column 描述快照中的一列。
+type Column struct {
+	Name string `json:"name"`           // 列名
+	Type string `json:"type,omitempty"` // 数据库类型名
+}
+
+// Meta 描述快照的元信息，列表展示时无需读取行数据。
+type Meta struct {
+	ID        string    `json:"id"`                 // 快照 ID
+	Name      string    `json:"name"`               // 快照名称
+	Source    string    `json:"source,omitempty"`   // 来源连接摘要
+	Database  string    `json:"database,omitempty"` // 来源数据库
+	Query     string    `json:"query,omitempty"`    // 生成快照的查询
+	Columns   []Column  `json:"columns"`            // 列结构
+	RowCount  int       `json:"rowCount"`           // 行数
+	Truncated bool      `json:"truncated"`          // 是否因行数上限被截断
+	SizeBytes int64     `json:"sizeBytes"`          // 压缩后的数据文件大小
+	CreatedAt time.Time `json:"createdAt"`          // 创建时间
+}
+
+// Snapshot 是只读的静态数据集，行以按列顺序排列的数组紧凑保存。
+type Snapshot struct {
+	Meta
+	Rows [][]interface{} `json:"rows"` // 行数据，顺序与 Columns 一致
+}
+
+// ColumnNames 返回列名列表。
+func (s *Snapshot) ColumnNames() []string {
+	names := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// AppendRecord 按列顺序追加一行 map 形式的记录。
+func (s *Snapshot) AppendRecord(record map[string]interface{}) {
+	row := make([]interface{}, len(s.Columns))
+	for i, c := range s.Columns {
+		row[i] = record[c.Name]
+	}
+	s.Rows = append(s.Rows, row)
+}
+
+// Store 将快照保存在本地工作区目录：index.json 保存元信息，行数据按快照 gzip 压缩存放。
+type Store struct {
+	mu     sync.Mutex
+	dir    string       // 存储目录
+	logger *slog.Logger // 日志记录器
+}
+
+// DefaultDir 返回默认快照存储目录。
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "snapshots")
+	}
+	return filepath.Join(configDir, "Boxify", "snapshots")
+}
+
+// NewStore 创建快照存储，dir 为空时使用默认目录。
+func NewStore(dir string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{dir: dir, logger: logger.With("module", "snapshot")}
+}
+
+// Save 保存快照并返回元信息；ID 与创建时间由存储生成。
+func (s *Store) Save(snap *Snapshot) (*Meta, error) {
+	if strings.TrimSpace(snap.Name) == "" {
+		return nil, fmt.Errorf("快照名称不能为空")
+	}
+	if len(snap.Columns) == 0 {
+		return nil, fmt.Errorf("快照没有列")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	snap.ID = uuid.New().String()
+	snap.CreatedAt = time.Now()
+	snap.RowCount = len(snap.Rows)
+	size, err := s.writeData(snap)
+	if err != nil {
+		return nil, err
+	}
+	snap.SizeBytes = size
+
+	meta := snap.Meta
+	index = append(index, &meta)
+	if err := s.writeIndex(index); err != nil {
+		os.Remove(s.dataPath(snap.ID))
+		return nil, err
+	}
+	s.logger.Info("保存快照", "id", snap.ID, "name", snap.Name, "rows", snap.RowCount, "bytes", size)
+	return &meta, nil
+}
+
+// List 返回所有快照元信息，按创建时间倒序。
+func (s *Store) List() ([]*Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		return index[i].CreatedAt.After(index[j].CreatedAt)
+	})
+	return index, nil
+}
+
+// Get 读取快照元信息与全部行数据。
+func (s *Store) Get(id string) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	pos := findMeta(index, id)
+	if pos < 0 {
+		return nil, ErrNotFound
+	}
+
+	f, err := os.Open(s.dataPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("读取快照数据失败：%w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("解压快照数据失败：%w", err)
+	}
+	defer zr.Close()
+
+	snap := &Snapshot{Meta: *index[pos]}
+	dec := json.NewDecoder(zr)
+	dec.UseNumber()
+	if err := dec.Decode(&snap.Rows); err != nil {
+		return nil, fmt.Errorf("解析快照数据失败：%w", err)
+	}
+	return snap, nil
+}
+
+// Rename 修改快照名称。
+func (s *Store) Rename(id, name string) (*Meta, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("快照名称不能为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	pos := findMeta(index, id)
+	if pos < 0 {
+		return nil, ErrNotFound
+	}
+	index[pos].Name = name
+	if err := s.writeIndex(index); err != nil {
+		return nil, err
+	}
+	return index[pos], nil
+}
+
+// Delete 删除快照及其数据文件。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	pos := findMeta(index, id)
+	if pos < 0 {
+		return ErrNotFound
+	}
+	if err := s.writeIndex(append(index[:pos], index[pos+1:]...)); err != nil {
+		return err
+	}
+	if err := os.Remove(s.dataPath(id)); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("删除快照数据文件失败", "id", id, "error", err)
+	}
+	return nil
+}
+
+// indexPath 返回索引文件路径。
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "index.json")
+}
+
+// dataPath 返回快照数据文件路径。
+func (s *Store) dataPath(id string) string {
+	return filepath.Join(s.dir, id+".json.gz")
+}
+
+// readIndex 读取索引，文件不存在时返回空列表。
+func (s *Store) readIndex() ([]*Meta, error) {
+	data, err := os.ReadFile(s.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Meta{}, nil
+		}
+		return nil, fmt.Errorf("读取快照索引失败：%w", err)
+	}
+	var index []*Meta
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("解析快照索引失败：%w", err)
+	}
+	return index, nil
+}
+
+// writeIndex 先写临时文件再替换，避免写入中断损坏索引。
+func (s *Store) writeIndex(index []*Meta) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("创建快照目录失败：%w", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化快照索引失败：%w", err)
+	}
+	tmp := s.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("写入快照索引失败：%w", err)
+	}
+	if err := os.Rename(tmp, s.indexPath()); err != nil {
+		return fmt.Errorf("写入快照索引失败：%w", err)
+	}
+	return nil
+}
+
+// writeData 将行数据以 gzip 压缩的 JSON 数组写入，返回文件大小。
+func (s *Store) writeData(snap *Snapshot) (int64, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return 0, fmt.Errorf("创建快照目录失败：%w", err)
+	}
+	path := s.dataPath(snap.ID)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("写入快照数据失败：%w", err)
+	}
+
+	zw := gzip.NewWriter(f)
+	rows := snap.Rows
+	if rows == nil {
+		rows = [][]interface{}{}
+	}
+	err = json.NewEncoder(zw).Encode(rows)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, fmt.Errorf("写入快照数据失败：%w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, nil
+	}
+	return info.Size(), nil
+}
+
+// findMeta 返回指定 ID 在索引中的位置，不存在时返回 -1。
+func findMeta(index []*Meta, id string) int {
+	for i, m := range index {
+		if m.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestStoreSaveGetListDelete(t *testing.T) {
+	store := NewStore(t.TempDir(), nil)
+
+	snap := &Snapshot{Meta: Meta{
+		Name:    "活跃用户",
+		Query:   "SELECT id, name FROM users",
+		Columns: []Column{{Name: "id", Type: "INT"}, {Name: "name", Type: "VARCHAR"}},
+	}}
+	snap.AppendRecord(map[string]interface{}{"id": 1, "name": "alice"})
+	snap.AppendRecord(map[string]interface{}{"id": 2})
+
+	meta, err := store.Save(snap)
+	if err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	if meta.ID == "" || meta.RowCount != 2 || meta.SizeBytes <= 0 {
+		t.Fatalf("元信息错误: %+v", meta)
+	}
+
+	// 新建存储实例模拟重启后读取
+	reopened := NewStore(store.dir, nil)
+	got, err := reopened.Get(meta.ID)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(got.Rows) != 2 || got.Rows[0][1] != "alice" || got.Rows[1][1] != nil {
+		t.Errorf("行数据错误: %+v", got.Rows)
+	}
+	if got.Rows[0][0] != json.Number("1") {
+		t.Errorf("数值应保持精度: %#v", got.Rows[0][0])
+	}
+	if names := got.ColumnNames(); len(names) != 2 || names[1] != "name" {
+		t.Errorf("列名错误: %v", names)
+	}
+
+	if _, err := reopened.Rename(meta.ID, "用户快照"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	list, err := reopened.List()
+	if err != nil || len(list) != 1 || list[0].Name != "用户快照" {
+		t.Fatalf("列表错误: %+v %v", list, err)
+	}
+
+	if err := reopened.Delete(meta.ID); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if _, err := reopened.Get(meta.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("删除后应返回 ErrNotFound，得到 %v", err)
+	}
+}
+
+func TestStoreSaveRequiresNameAndColumns(t *testing.T) {
+	store := NewStore(t.TempDir(), nil)
+	if _, err := store.Save(&Snapshot{Meta: Meta{Columns: []Column{{Name: "a"}}}}); err == nil {
+		t.Error("缺少名称应返回错误")
+	}
+	if _, err := store.Save(&Snapshot{Meta: Meta{Name: "x"}}); err == nil {
+		t.Error("缺少列应返回错误")
+	}
+}
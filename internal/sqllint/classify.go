@@ -0,0 +1,219 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import "strings"
+
+// 语句类别。
+const (
+	StatementQuery = "query" // 只读查询（SELECT、SHOW、EXPLAIN 等）
+	StatementDML   = "dml"   // 数据修改（INSERT、UPDATE、DELETE 等）
+	StatementDDL   = "ddl"   // 结构变更与授权
+	StatementOther = "other" // 会话设置、事务控制、过程调用等
+	StatementEmpty = "empty" // 只有空白或注释
+)
+
+// queryKeywords 是返回结果集的只读语句首关键字。
+var queryKeywords = map[string]bool{
+	"SELECT": true, "SHOW": true, "DESCRIBE": true, "DESC": true, "EXPLAIN": true,
+	"VALUES": true, "TABLE": true, "PRAGMA": true,
+}
+
+// dmlKeywords 是数据修改语句首关键字。
+var dmlKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "REPLACE": true, "UPSERT": true,
+}
+
+// ddlKeywords 是结构变更与授权语句首关键字。
+var ddlKeywords = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"COMMENT": true, "GRANT": true, "REVOKE": true,
+}
+
+// Classification 是语句分类结果。
+type Classification struct {
+	Kind        string `json:"kind"`        // 语句类别（Statement*）
+	Keyword     string `json:"keyword"`     // 决定类别的关键字（大写），WITH 语句为主语句的关键字
+	ReturnsRows bool   `json:"returnsRows"` // 是否返回结果集（查询或带 RETURNING 的数据修改）
+	Multiple    bool   `json:"multiple"`    // 第一条语句之后还有其他语句；只读判断不能只看第一条
+}
+
+// Classify 判断 sql 中第一条语句的类别及是否返回结果集，并标记其后是否还有其他语句。
+//
+// 跳过注释、前导括号与 WITH 公共表表达式，按主语句关键字分类；
+// INSERT/UPDATE/DELETE ... RETURNING 视为返回结果集。以下 SELECT 不视为只读查询：
+// 公共表表达式中含数据修改语句时归为数据修改；FOR UPDATE/FOR SHARE 等加锁读取需要行锁，归为数据修改；
+// SELECT ... INTO 不返回结果集，PostgreSQL 中用于建表，归为结构变更，MySQL 中写入变量或文件，归为其他。
+func Classify(sql string, postgres bool) Classification {
+	tokens := tokenize([]rune(sql), postgres)
+	for len(tokens) > 0 && tokens[0].text == ";" {
+		tokens = tokens[1:]
+	}
+	multiple := false
+	for i, t := range tokens {
+		if t.text == ";" {
+			for _, next := range tokens[i+1:] {
+				if next.text != ";" {
+					multiple = true
+					break
+				}
+			}
+			tokens = tokens[:i]
+			break
+		}
+	}
+	c := classifyStatement(tokens, postgres)
+	c.Multiple = multiple
+	return c
+}
+
+// classifyStatement 按主语句关键字对单条语句的词法单元分类。
+func classifyStatement(tokens []token, postgres bool) Classification {
+	i := 0
+	for i < len(tokens) && tokens[i].text == "(" {
+		i++
+	}
+	if i >= len(tokens) {
+		return Classification{Kind: StatementEmpty}
+	}
+	modifying := false
+	if tokens[i].is("WITH") {
+		i, modifying = skipCTEs(tokens, i+1, postgres)
+		if i >= len(tokens) {
+			kind := StatementQuery
+			if modifying {
+				kind = StatementDML
+			}
+			return Classification{Kind: kind, Keyword: "WITH", ReturnsRows: true}
+		}
+	}
+
+	head := tokens[i]
+	keyword := strings.ToUpper(head.text)
+	if head.kind != tokenWord {
+		return Classification{Kind: StatementOther, Keyword: keyword}
+	}
+	rest := tokens[i+1:]
+	switch {
+	case queryKeywords[keyword]:
+		c := Classification{Kind: StatementQuery, Keyword: keyword, ReturnsRows: true}
+		if keyword == "SELECT" {
+			switch {
+			case hasTopLevel(rest, "INTO"):
+				c.ReturnsRows = false
+				c.Kind = StatementOther
+				if postgres {
+					c.Kind = StatementDDL
+				}
+			case isLockingRead(rest):
+				c.Kind = StatementDML
+			}
+		}
+		if modifying && c.Kind == StatementQuery {
+			c.Kind = StatementDML
+		}
+		return c
+	case dmlKeywords[keyword]:
+		return Classification{Kind: StatementDML, Keyword: keyword, ReturnsRows: hasTopLevel(rest, "RETURNING")}
+	case ddlKeywords[keyword]:
+		return Classification{Kind: StatementDDL, Keyword: keyword}
+	default:
+		return Classification{Kind: StatementOther, Keyword: keyword}
+	}
+}
+
+// skipCTEs 跳过 WITH [RECURSIVE] name [(cols)] AS [NOT] [MATERIALIZED] (...), ... 部分，返回主语句起始位置，
+// 并报告是否有公共表表达式的主体为数据修改语句（如 PostgreSQL 的 WITH d AS (DELETE ... RETURNING *)）。
+func skipCTEs(tokens []token, i int, postgres bool) (int, bool) {
+	modifying := false
+	if i < len(tokens) && tokens[i].is("RECURSIVE") {
+		i++
+	}
+	for i < len(tokens) {
+		// 名称与可选列清单
+		i++
+		if i < len(tokens) && tokens[i].text == "(" {
+			i = skipParens(tokens, i)
+		}
+		if i < len(tokens) && tokens[i].is("AS") {
+			i++
+		}
+		for i < len(tokens) && tokens[i].is("NOT", "MATERIALIZED") {
+			i++
+		}
+		if i < len(tokens) && tokens[i].text == "(" {
+			end := skipParens(tokens, i)
+			body := tokens[i+1 : max(end-1, i+1)]
+			if classifyStatement(body, postgres).Kind == StatementDML {
+				modifying = true
+			}
+			i = end
+		}
+		if i < len(tokens) && tokens[i].text == "," {
+			i++
+			continue
+		}
+		return i, modifying
+	}
+	return i, modifying
+}
+
+// isLockingRead 判断 SELECT（含子查询）是否以 FOR UPDATE、FOR NO KEY UPDATE、FOR SHARE、FOR KEY SHARE
+// 或 MySQL 的 LOCK IN SHARE MODE 加锁读取。
+func isLockingRead(tokens []token) bool {
+	for i := 0; i+1 < len(tokens); i++ {
+		t, next := tokens[i], tokens[i+1]
+		if t.is("FOR") && next.is("UPDATE", "SHARE", "NO", "KEY") {
+			return true
+		}
+		if t.is("LOCK") && next.is("IN") && i+2 < len(tokens) && tokens[i+2].is("SHARE") {
+			return true
+		}
+	}
+	return false
+}
+
+// skipParens 从左括号位置跳到与之匹配的右括号之后。
+func skipParens(tokens []token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// hasTopLevel 判断括号外是否出现关键字 word。
+func hasTopLevel(tokens []token, word string) bool {
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth == 0 && t.is(word):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exprFunctions 是计算列表达式允许调用的标量函数（小写），不含聚合、窗口与有副作用的函数。
+var exprFunctions = map[string]bool{
+	"concat": true, "concat_ws": true, "coalesce": true, "ifnull": true, "nullif": true, "if": true,
+	"upper": true, "lower": true, "trim": true, "ltrim": true, "rtrim": true, "substring": true, "substr": true,
+	"left": true, "right": true, "length": true, "char_length": true, "character_length": true,
+	"replace": true, "lpad": true, "rpad": true, "reverse": true, "format": true, "to_char": true,
+	"round": true, "floor": true, "ceil": true, "ceiling": true, "abs": true, "mod": true, "sign": true,
+	"power": true, "sqrt": true, "greatest": true, "least": true,
+	"date": true, "year": true, "month": true, "day": true, "hour": true, "minute": true, "second": true,
+	"date_format": true, "datediff": true, "cast": true,
+	"json_extract": true, "json_unquote": true, "json_length": true,
+}
+
+// exprKeywords 是计算列表达式允许出现的关键字（大写）。AS 只能用于 CAST 中的目标类型。
+var exprKeywords = map[string]bool{
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+	"AND": true, "OR": true, "NOT": true, "NULL": true, "IS": true, "IN": true,
+	"LIKE": true, "BETWEEN": true, "TRUE": true, "FALSE": true, "AS": true,
+}
+
+// exprForbidden 是不允许出现在计算列表达式中的关键字，未引用时即使与列同名也拒绝。
+var exprForbidden = map[string]bool{
+	"FROM": true, "WHERE": true, "UNION": true, "INTO": true, "LIMIT": true, "OFFSET": true,
+	"ORDER": true, "GROUP": true, "HAVING": true, "JOIN": true, "EXISTS": true, "WITH": true, "OVER": true,
+}
+
+// exprSymbols 是计算列表达式允许的运算符与标点。
+var exprSymbols = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "(": true, ")": true, ",": true, "||": true,
+	"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true, "::": true,
+}
+
+// CheckExpression 校验 expr 是否为可以安全拼入 SELECT 列表的标量表达式，返回其引用的列名。
+//
+// 只允许列引用、字面量、运算符、CASE 与白名单中的标量函数；子查询、注释、占位符、限定名与多条语句均被拒绝，
+// 字符串与引用标识符必须闭合，括号必须配对。column 判断列是否存在，为 nil 时不检查列名。
+func CheckExpression(expr string, postgres bool, column func(name string) bool) ([]string, error) {
+	runes := []rune(expr)
+	tokens := tokenize(runes, postgres)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("表达式不能为空")
+	}
+
+	var (
+		columns  []string
+		parens   []string // 每层括号所属的函数名（小写），普通括号为空
+		typeAt   = -1     // CAST ... AS 之后类型名所在的括号层数
+		prevEnd  = 0
+		castType = false // 上一个 token 为 ::，下一个单词是类型名
+	)
+	for i, t := range tokens {
+		if strings.TrimSpace(string(runes[prevEnd:t.start])) != "" {
+			return nil, fmt.Errorf("表达式不能包含注释")
+		}
+		prevEnd = t.end
+		if t.kind == tokenString && strings.HasPrefix(t.text, "$") {
+			return nil, fmt.Errorf("表达式不支持美元符号字符串")
+		}
+		if (t.kind == tokenString || t.kind == tokenQuoted) && !closedQuote(runes, t, postgres) {
+			return nil, fmt.Errorf("引号未闭合")
+		}
+
+		switch t.kind {
+		case tokenParam:
+			return nil, fmt.Errorf("表达式不能包含参数占位符: %s", t.text)
+		case tokenString:
+		case tokenNumber:
+		case tokenQuoted:
+			if column != nil && !column(t.text) {
+				return nil, fmt.Errorf("列不存在: %s", t.text)
+			}
+			columns = append(columns, t.text)
+		case tokenWord:
+			upper := strings.ToUpper(t.text)
+			next := i+1 < len(tokens) && tokens[i+1].is("(")
+			switch {
+			case exprForbidden[upper] || queryKeywords[upper] || dmlKeywords[upper] || ddlKeywords[upper]:
+				return nil, fmt.Errorf("表达式不能包含 %s", upper)
+			case castType || typeAt == len(parens):
+				castType = false
+			case upper == "AS":
+				if len(parens) == 0 || parens[len(parens)-1] != "cast" {
+					return nil, fmt.Errorf("AS 只能用于 CAST 的目标类型")
+				}
+				typeAt = len(parens)
+			case exprKeywords[upper]:
+			case next:
+				if !exprFunctions[strings.ToLower(t.text)] {
+					return nil, fmt.Errorf("不支持的函数: %s", t.text)
+				}
+			default:
+				if column != nil && !column(t.text) {
+					return nil, fmt.Errorf("列不存在: %s", t.text)
+				}
+				columns = append(columns, t.text)
+			}
+		case tokenSymbol:
+			if !exprSymbols[t.text] {
+				return nil, fmt.Errorf("表达式不能包含 %s", t.text)
+			}
+			switch t.text {
+			case "(":
+				fn := ""
+				if i > 0 && tokens[i-1].kind == tokenWord {
+					fn = strings.ToLower(tokens[i-1].text)
+				}
+				parens = append(parens, fn)
+			case ")":
+				if len(parens) == 0 {
+					return nil, fmt.Errorf("括号不匹配")
+				}
+				if typeAt == len(parens) {
+					typeAt = -1
+				}
+				parens = parens[:len(parens)-1]
+			case "::":
+				if !postgres {
+					return nil, fmt.Errorf("表达式不能包含 ::")
+				}
+				castType = true
+			}
+		}
+	}
+	if strings.TrimSpace(string(runes[prevEnd:])) != "" {
+		return nil, fmt.Errorf("表达式不能包含注释")
+	}
+	if len(parens) > 0 {
+		return nil, fmt.Errorf("括号不匹配")
+	}
+	return columns, nil
+}
+
+// closedQuote 判断字符串或引用标识符是否以匹配的引号结束：在末尾追加空白后重新扫描，未闭合时会越过原文末尾。
+func closedQuote(runes []rune, t token, postgres bool) bool {
+	if t.end < len(runes) {
+		return true
+	}
+	padded := append(append([]rune{}, runes...), ' ')
+	return SkipQuoted(padded, t.start, runes[t.start], t.kind == tokenString && !postgres) <= len(runes)
+}
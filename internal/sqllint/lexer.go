@@ -0,0 +1,180 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // 未引用的标识符或关键字
+	tokenQuoted                  // 引用的标识符（反引号，PostgreSQL 下含双引号）
+	tokenString                  // 字符串字面量
+	tokenNumber                  // 数字字面量
+	tokenParam                   // 占位符（? / $1 / :name / @var）
+	tokenSymbol                  // 标点与运算符
+)
+
+// token 是带位置的词法单元，start/end 为按字符（rune）计的偏移，end 不含。
+type token struct {
+	kind  tokenKind
+	text  string // 标识符为去除引号后的名称，其余为原文
+	start int
+	end   int
+}
+
+// isIdent 判断 token 是否可能为标识符。
+func (t token) isIdent() bool {
+	return t.kind == tokenWord || t.kind == tokenQuoted
+}
+
+// is 判断 token 是否为给定关键字或符号之一（关键字忽略大小写，引用标识符不视为关键字）。
+func (t token) is(words ...string) bool {
+	if t.kind != tokenWord && t.kind != tokenSymbol {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// multiSymbols 是需要合并为单个 token 的多字符运算符，长的在前。
+var multiSymbols = []string{"->>", "<=>", "<>", "!=", "<=", ">=", "||", "::", ":=", "->"}
+
+// tokenize 将 SQL 切分为带位置的词法单元，跳过空白与注释。
+// postgres 为 true 时双引号为标识符、支持 $tag$ 字符串，否则双引号为字符串且反斜杠转义。
+func tokenize(runes []rune, postgres bool) []token {
+	var tokens []token
+	emit := func(kind tokenKind, text string, start, end int) {
+		tokens = append(tokens, token{kind: kind, text: text, start: start, end: end})
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#' && !postgres:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i = min(i+2, len(runes))
+		case r == '\'' || (r == '"' && !postgres):
+			end := SkipQuoted(runes, i, r, !postgres)
+			emit(tokenString, string(runes[i:end]), i, end)
+			i = end
+		case r == '`' || r == '"':
+			end := SkipQuoted(runes, i, r, false)
+			text := string(runes[i+1 : max(i+1, end-1)])
+			text = strings.ReplaceAll(text, string(r)+string(r), string(r))
+			emit(tokenQuoted, text, i, end)
+			i = end
+		case r == '$' && postgres:
+			start := i
+			j := i + 1
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			if j > i+1 {
+				emit(tokenParam, string(runes[start:j]), start, j)
+				i = j
+				continue
+			}
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			if j < len(runes) && runes[j] == '$' {
+				tag := string(runes[start : j+1])
+				n := j + 1 - start
+				k := j + 1
+				for k+n <= len(runes) && string(runes[k:k+n]) != tag {
+					k++
+				}
+				i = min(k+n, len(runes))
+				emit(tokenString, string(runes[start:i]), start, i)
+				continue
+			}
+			emit(tokenSymbol, "$", start, start+1)
+			i++
+		case r == '?':
+			emit(tokenParam, "?", i, i+1)
+			i++
+		case (r == '@' || r == ':') && i+1 < len(runes) && (runes[i+1] == '_' || runes[i+1] == '@' || unicode.IsLetter(runes[i+1])) && !(r == ':' && i > 0 && runes[i-1] == ':'):
+			start := i
+			i++
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '@' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			emit(tokenParam, string(runes[start:i]), start, i)
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			emit(tokenWord, string(runes[start:i]), start, i)
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.' ||
+				((runes[i] == '+' || runes[i] == '-') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			emit(tokenNumber, string(runes[start:i]), start, i)
+		default:
+			text := string(r)
+			for _, sym := range multiSymbols {
+				n := len([]rune(sym))
+				if i+n <= len(runes) && string(runes[i:i+n]) == sym {
+					text = sym
+					break
+				}
+			}
+			n := len([]rune(text))
+			emit(tokenSymbol, text, i, i+n)
+			i += n
+		}
+	}
+	return tokens
+}
+
+// SkipQuoted 跳过从 runes[i] 开始、以 closer 结束的字符串或引用标识符（成对的 closer 视为转义，
+// backslash 为 true 时同时跳过反斜杠转义），返回结束后的位置；未闭合时返回 len(runes)。
+func SkipQuoted(runes []rune, i int, closer rune, backslash bool) int {
+	i++
+	for i < len(runes) {
+		if backslash && runes[i] == '\\' {
+			i += 2
+			continue
+		}
+		if runes[i] == closer {
+			if i+1 < len(runes) && runes[i+1] == closer {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return len(runes)
+}
@@ -0,0 +1,667 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqllint 对 SQL 做轻量静态分析，返回带位置的告警供编辑器标注。
+package sqllint
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// 规则编号。
+const (
+	RuleSelectStar         = "select-star"
+	RuleMissingWhere       = "missing-where"
+	RuleImplicitConversion = "implicit-conversion"
+	RuleNonSargable        = "non-sargable"
+	RuleUnknownColumn      = "unknown-column"
+)
+
+// 告警级别。
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+)
+
+// Options 是分析选项。
+type Options struct {
+	Postgres bool   // 按 PostgreSQL 方言解析（双引号为标识符）
+	Schema   Schema // 表结构快照，为空时跳过依赖表结构的规则
+}
+
+// Warning 是一条分析告警。行列从 1 开始、按字符计，EndColumn 不含。
+type Warning struct {
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Statement   int    `json:"statement"` // 语句序号（从 0 开始）
+	StartLine   int    `json:"startLine"`
+	StartColumn int    `json:"startColumn"`
+	EndLine     int    `json:"endLine"`
+	EndColumn   int    `json:"endColumn"`
+	Offset      int    `json:"offset"` // 起始字符偏移
+	Length      int    `json:"length"` // 字符长度
+}
+
+// Analyze 分析 sql 中的全部语句并按出现位置返回告警。
+func Analyze(sql string, opts Options) []Warning {
+	runes := []rune(sql)
+	tokens := tokenize(runes, opts.Postgres)
+	lines := lineStarts(runes)
+
+	warnings := []Warning{}
+	index := 0
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && tokens[i].text != ";" {
+			continue
+		}
+		if i > start {
+			s := newStatement(tokens[start:i], opts.Schema)
+			for _, w := range s.analyze() {
+				w.Statement = index
+				w.StartLine, w.StartColumn = position(lines, w.Offset)
+				w.EndLine, w.EndColumn = position(lines, w.Offset+w.Length)
+				warnings = append(warnings, w)
+			}
+			index++
+		}
+		start = i + 1
+	}
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Offset < warnings[j].Offset })
+	return warnings
+}
+
+// lineStarts 返回每一行起始的字符偏移。
+func lineStarts(runes []rune) []int {
+	starts := []int{0}
+	for i, r := range runes {
+		if r == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position 将字符偏移转换为从 1 开始的行列号。
+func position(lines []int, offset int) (int, int) {
+	line := sort.Search(len(lines), func(i int) bool { return lines[i] > offset }) - 1
+	return line + 1, offset - lines[line] + 1
+}
+
+// tableRef 是语句中引用的表，name 为空表示派生表。
+type tableRef struct {
+	name    string
+	columns map[string]string // 表结构中登记的列，未登记时为 nil
+}
+
+// statement 是单条语句的分析上下文。
+type statement struct {
+	tokens  []token
+	schema  Schema
+	match   []int    // 括号对应位置，非括号为 -1
+	parent  []int    // 外层左括号位置，顶层为 -1
+	clause  []string // 所属子句关键字（大写）
+	verb    string   // 语句主动词（大写）
+	tables  []tableRef
+	aliases map[string]int // 小写别名或表名 → tables 下标
+	// allKnown 表示语句引用的表全部在表结构中登记且无派生表/CTE，此时才检查未限定的列名
+	allKnown bool
+}
+
+// clauseKeywords 是切换子句的关键字。
+var clauseKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "SET": true, "ON": true, "USING": true, "HAVING": true,
+	"GROUP": true, "ORDER": true, "LIMIT": true, "OFFSET": true, "VALUES": true, "VALUE": true, "INTO": true,
+	"UPDATE": true, "DELETE": true, "INSERT": true, "REPLACE": true, "RETURNING": true, "WINDOW": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "JOIN": true, "WITH": true,
+}
+
+// newStatement 预处理语句 token：括号配对、子句归属、表引用与别名。
+func newStatement(tokens []token, schema Schema) *statement {
+	s := &statement{
+		tokens:  tokens,
+		schema:  schema,
+		match:   make([]int, len(tokens)),
+		parent:  make([]int, len(tokens)),
+		clause:  make([]string, len(tokens)),
+		aliases: make(map[string]int),
+	}
+	var opens []int
+	clauses := []string{""}
+	for i, t := range tokens {
+		s.match[i] = -1
+		s.parent[i] = -1
+		if len(opens) > 0 {
+			s.parent[i] = opens[len(opens)-1]
+		}
+		switch {
+		case t.is("("):
+			s.clause[i] = clauses[len(clauses)-1]
+			opens = append(opens, i)
+			clauses = append(clauses, clauses[len(clauses)-1])
+			continue
+		case t.is(")") && len(opens) > 0:
+			open := opens[len(opens)-1]
+			opens = opens[:len(opens)-1]
+			clauses = clauses[:len(clauses)-1]
+			s.match[open], s.match[i] = i, open
+			s.parent[i] = s.parent[open]
+		case t.kind == tokenWord && clauseKeywords[strings.ToUpper(t.text)]:
+			clauses[len(clauses)-1] = strings.ToUpper(t.text)
+			if len(opens) == 0 && s.verb == "" && isVerb(t.text) {
+				s.verb = strings.ToUpper(t.text)
+			}
+		}
+		s.clause[i] = clauses[len(clauses)-1]
+	}
+	if s.verb == "" && len(tokens) > 0 && tokens[0].kind == tokenWord {
+		s.verb = strings.ToUpper(tokens[0].text)
+	}
+	s.collectTables()
+	return s
+}
+
+// isVerb 判断关键字是否为 DML 主动词。
+func isVerb(word string) bool {
+	switch strings.ToUpper(word) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
+// collectTables 识别 FROM/JOIN/UPDATE/INTO 之后的表及其别名。
+func (s *statement) collectTables() {
+	derived := false
+	for i, t := range s.tokens {
+		if t.kind != tokenWord {
+			continue
+		}
+		switch strings.ToUpper(t.text) {
+		case "WITH":
+			if i == 0 {
+				derived = true
+			}
+		case "FROM", "JOIN", "UPDATE", "INTO":
+			if t.is("UPDATE") && i > 0 && s.tokens[i-1].is("KEY", "FOR") {
+				continue // ON DUPLICATE KEY UPDATE / FOR UPDATE
+			}
+			for j := i + 1; j < len(s.tokens); {
+				var ref tableRef
+				next := j
+				if s.tokens[j].is("(") && s.match[j] > j {
+					derived = true
+					next = s.match[j] + 1
+				} else {
+					name, end := readName(s.tokens, j)
+					if name == "" {
+						break
+					}
+					ref.name = name
+					if s.schema != nil {
+						ref.columns, _ = s.schema.Columns(name)
+					}
+					next = end
+				}
+				s.tables = append(s.tables, ref)
+				stored := len(s.tables) - 1
+				if ref.name != "" {
+					s.aliases[strings.ToLower(ref.name)] = stored
+					if idx := strings.LastIndex(ref.name, "."); idx >= 0 {
+						s.aliases[strings.ToLower(ref.name[idx+1:])] = stored
+					}
+				}
+				if alias, end := readAlias(s.tokens, next); alias != "" {
+					s.aliases[strings.ToLower(alias)] = stored
+					next = end
+				}
+				if t.is("FROM") && next < len(s.tokens) && s.tokens[next].is(",") {
+					j = next + 1
+					continue
+				}
+				break
+			}
+		}
+	}
+
+	s.allKnown = s.schema != nil && len(s.tables) > 0 && !derived
+	for _, ref := range s.tables {
+		if ref.columns == nil {
+			s.allKnown = false
+		}
+	}
+}
+
+// readName 从 tokens[i] 开始读取可能带 schema 前缀的对象名，返回名称与下一个位置。
+func readName(tokens []token, i int) (string, int) {
+	var parts []string
+	for i < len(tokens) && tokens[i].isIdent() {
+		if tokens[i].kind == tokenWord && reserved[strings.ToUpper(tokens[i].text)] {
+			break
+		}
+		parts = append(parts, tokens[i].text)
+		i++
+		if i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].isIdent() {
+			i++
+			continue
+		}
+		break
+	}
+	return strings.Join(parts, "."), i
+}
+
+// readAlias 读取表名后的 [AS] alias。
+func readAlias(tokens []token, i int) (string, int) {
+	if i < len(tokens) && tokens[i].is("AS") {
+		i++
+	}
+	if i < len(tokens) && tokens[i].isIdent() && !(tokens[i].kind == tokenWord && reserved[strings.ToUpper(tokens[i].text)]) {
+		return tokens[i].text, i + 1
+	}
+	return "", i
+}
+
+// reserved 是不会作为列名、表名或别名出现的关键字。
+var reserved = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "SET": true, "VALUES": true, "VALUE": true, "ON": true, "USING": true,
+	"GROUP": true, "ORDER": true, "BY": true, "LIMIT": true, "OFFSET": true, "HAVING": true, "UNION": true, "ALL": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true, "NATURAL": true,
+	"WITH": true, "AS": true, "FOR": true, "INTO": true, "DEFAULT": true, "PARTITION": true, "WINDOW": true, "FETCH": true,
+	"RETURNING": true, "LATERAL": true, "STRAIGHT_JOIN": true, "AND": true, "OR": true, "XOR": true, "NOT": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "UNKNOWN": true, "IS": true, "IN": true, "LIKE": true, "ILIKE": true,
+	"REGEXP": true, "RLIKE": true, "BETWEEN": true, "EXISTS": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "INTERVAL": true, "DISTINCT": true, "ANY": true, "SOME": true, "ESCAPE": true,
+	"COLLATE": true, "BINARY": true, "ASC": true, "DESC": true, "INTERSECT": true, "EXCEPT": true, "DUPLICATE": true,
+	"KEY": true, "UPDATE": true, "DELETE": true, "INSERT": true, "REPLACE": true, "IGNORE": true, "LOW_PRIORITY": true,
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "LOCALTIME": true,
+	"LOCALTIMESTAMP": true, "MICROSECOND": true, "SECOND": true, "MINUTE": true, "HOUR": true, "DAY": true,
+	"WEEK": true, "MONTH": true, "QUARTER": true, "YEAR": true, "SIMILAR": true, "TO": true, "ROW": true,
+}
+
+// analyze 依次执行各条规则。
+func (s *statement) analyze() []Warning {
+	var warnings []Warning
+	warnings = append(warnings, s.checkSelectStar()...)
+	warnings = append(warnings, s.checkMissingWhere()...)
+	warnings = append(warnings, s.checkPredicates()...)
+	warnings = append(warnings, s.checkUnknownColumns()...)
+	return warnings
+}
+
+// warning 以 tokens[from..to] 的范围创建告警。
+func (s *statement) warning(rule, severity string, from, to int, format string, args ...any) Warning {
+	return Warning{
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Offset:   s.tokens[from].start,
+		Length:   s.tokens[to].end - s.tokens[from].start,
+	}
+}
+
+// checkSelectStar 标记 SELECT 列表中的 * 与 t.*（EXISTS 子查询除外）。
+func (s *statement) checkSelectStar() []Warning {
+	var warnings []Warning
+	for i, t := range s.tokens {
+		if !t.is("*") || s.clause[i] != "SELECT" || i == 0 {
+			continue
+		}
+		prev := s.tokens[i-1]
+		from := i
+		if prev.is(".") && i >= 2 {
+			from = i - 2
+		} else if !prev.is("SELECT", "DISTINCT", "ALL", ",") {
+			continue
+		}
+		if p := s.parent[i]; p > 0 && s.tokens[p-1].is("EXISTS") {
+			continue
+		}
+		warnings = append(warnings, s.warning(RuleSelectStar, SeverityInfo, from, i,
+			"避免使用 SELECT *：列出所需列可减少传输量，并避免表结构变化影响结果"))
+	}
+	return warnings
+}
+
+// checkMissingWhere 标记没有 WHERE 条件的 UPDATE/DELETE。
+func (s *statement) checkMissingWhere() []Warning {
+	if s.verb != "UPDATE" && s.verb != "DELETE" {
+		return nil
+	}
+	verbAt := -1
+	for i, t := range s.tokens {
+		if s.parent[i] != -1 {
+			continue
+		}
+		if t.is("WHERE") {
+			return nil
+		}
+		if verbAt < 0 && t.is(s.verb) {
+			verbAt = i
+		}
+	}
+	if verbAt < 0 {
+		return nil
+	}
+	return []Warning{s.warning(RuleMissingWhere, SeverityWarning, verbAt, verbAt,
+		"%s 语句没有 WHERE 条件，将影响表中所有行", s.verb)}
+}
+
+// comparisonOps 是参与索引与类型检查的比较运算符。
+var comparisonOps = map[string]bool{"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true, "<=>": true}
+
+// checkPredicates 检查 WHERE/ON 中的比较：隐式类型转换、对列施加函数或运算、前导通配符 LIKE。
+func (s *statement) checkPredicates() []Warning {
+	var warnings []Warning
+	for i, t := range s.tokens {
+		if s.clause[i] != "WHERE" && s.clause[i] != "ON" {
+			continue
+		}
+		switch {
+		case t.kind == tokenSymbol && comparisonOps[t.text]:
+			lFrom, lTo := s.leftOperand(i)
+			rFrom, rTo := s.rightOperand(i)
+			if lFrom > lTo || rFrom > rTo {
+				continue
+			}
+			if w, ok := s.implicitConversion(lFrom, lTo, rFrom, rTo); ok {
+				warnings = append(warnings, w)
+			} else if w, ok := s.implicitConversion(rFrom, rTo, lFrom, lTo); ok {
+				warnings = append(warnings, w)
+			}
+			if w, ok := s.nonSargable(lFrom, lTo, rFrom, rTo); ok {
+				warnings = append(warnings, w)
+			} else if w, ok := s.nonSargable(rFrom, rTo, lFrom, lTo); ok {
+				warnings = append(warnings, w)
+			}
+		case t.is("LIKE", "ILIKE") && i+1 < len(s.tokens):
+			lFrom, lTo := s.leftOperand(i)
+			lit := s.tokens[i+1]
+			if lFrom > lTo || !s.hasColumn(lFrom, lTo) || lit.kind != tokenString {
+				continue
+			}
+			if value := unquoteString(lit.text); strings.HasPrefix(value, "%") || strings.HasPrefix(value, "_") {
+				warnings = append(warnings, s.warning(RuleNonSargable, SeverityWarning, i+1, i+1,
+					"%s 模式以通配符开头，无法使用索引", strings.ToUpper(t.text)))
+			}
+		}
+	}
+	return warnings
+}
+
+// operandBoundary 是表达式操作数的边界关键字。
+var operandBoundary = map[string]bool{
+	"AND": true, "OR": true, "XOR": true, "NOT": true, "WHERE": true, "ON": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "CASE": true, "HAVING": true, "SET": true, "SELECT": true, "BETWEEN": true,
+	"LIKE": true, "ILIKE": true, "IN": true, "IS": true, "EXISTS": true, "ORDER": true, "GROUP": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true,
+	"FULL": true, "CROSS": true, "RETURNING": true, "FOR": true, "ESCAPE": true, "USING": true,
+}
+
+// isBoundary 判断 token 是否结束一个操作数。
+func isBoundary(t token) bool {
+	if t.kind == tokenSymbol {
+		return t.text == "," || t.text == ";" || comparisonOps[t.text]
+	}
+	return t.kind == tokenWord && operandBoundary[strings.ToUpper(t.text)]
+}
+
+// leftOperand 返回运算符左侧操作数的 token 区间（闭区间），为空时 from > to。
+func (s *statement) leftOperand(op int) (int, int) {
+	i := op - 1
+	for i >= 0 {
+		t := s.tokens[i]
+		if t.is(")") && s.match[i] >= 0 {
+			i = s.match[i] - 1
+			continue
+		}
+		if t.is("(") || isBoundary(t) {
+			break
+		}
+		i--
+	}
+	return i + 1, op - 1
+}
+
+// rightOperand 返回运算符右侧操作数的 token 区间（闭区间），为空时 from > to。
+func (s *statement) rightOperand(op int) (int, int) {
+	i := op + 1
+	for i < len(s.tokens) {
+		t := s.tokens[i]
+		if t.is("(") && s.match[i] > i {
+			i = s.match[i] + 1
+			continue
+		}
+		if t.is(")") || isBoundary(t) {
+			break
+		}
+		i++
+	}
+	return op + 1, i - 1
+}
+
+// columnRef 判断区间是否恰好为一个列引用（col、t.col 或 db.t.col），返回限定名与列名。
+func (s *statement) columnRef(from, to int) (string, string, bool) {
+	n := to - from + 1
+	if n != 1 && n != 3 && n != 5 {
+		return "", "", false
+	}
+	var parts []string
+	for i := from; i <= to; i++ {
+		t := s.tokens[i]
+		if (i-from)%2 == 1 {
+			if !t.is(".") {
+				return "", "", false
+			}
+			continue
+		}
+		if !s.isColumnToken(i) {
+			return "", "", false
+		}
+		parts = append(parts, t.text)
+	}
+	return strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1], true
+}
+
+// isColumnToken 判断 tokens[i] 是否可能是列名（而非关键字、函数名或类型名）。
+func (s *statement) isColumnToken(i int) bool {
+	t := s.tokens[i]
+	if !t.isIdent() {
+		return false
+	}
+	if t.kind == tokenWord && reserved[strings.ToUpper(t.text)] {
+		return false
+	}
+	if i+1 < len(s.tokens) {
+		next := s.tokens[i+1]
+		if next.is("(") || (next.kind == tokenString && next.start == t.end) {
+			return false // 函数调用或 E'..' / _utf8'..' 等字面量前缀
+		}
+	}
+	if i > 0 && s.tokens[i-1].is("::", "AS", "COLLATE") {
+		return false
+	}
+	return true
+}
+
+// hasColumn 判断区间内是否引用了列。
+func (s *statement) hasColumn(from, to int) bool {
+	for i := from; i <= to; i++ {
+		if s.isColumnToken(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// literal 判断区间是否为单个字符串或数字字面量（允许正负号）。
+func (s *statement) literal(from, to int) (token, bool) {
+	if to-from == 1 && s.tokens[from].is("-", "+") {
+		from++
+	}
+	if from != to {
+		return token{}, false
+	}
+	t := s.tokens[from]
+	return t, t.kind == tokenString || t.kind == tokenNumber
+}
+
+// resolve 查找列所属表的列类型：tableKnown 表示能确定列所在表的结构，found 表示列存在。
+func (s *statement) resolve(qualifier, name string) (colType string, tableKnown, found bool) {
+	name = strings.ToLower(name)
+	if qualifier != "" {
+		var cols map[string]string
+		if idx, ok := s.aliases[strings.ToLower(qualifier)]; ok {
+			cols = s.tables[idx].columns
+		} else if s.schema != nil {
+			cols, _ = s.schema.Columns(qualifier)
+		}
+		if cols == nil {
+			return "", false, false
+		}
+		colType, found = cols[name]
+		return colType, true, found
+	}
+	for _, ref := range s.tables {
+		if colType, ok := ref.columns[name]; ok {
+			return colType, true, true
+		}
+	}
+	return "", s.allKnown, false
+}
+
+// implicitConversion 检查 col op literal 形式的比较是否因类型不一致而逐行转换。
+func (s *statement) implicitConversion(colFrom, colTo, litFrom, litTo int) (Warning, bool) {
+	qualifier, name, ok := s.columnRef(colFrom, colTo)
+	if !ok {
+		return Warning{}, false
+	}
+	lit, ok := s.literal(litFrom, litTo)
+	if !ok {
+		return Warning{}, false
+	}
+	colType, _, found := s.resolve(qualifier, name)
+	if !found {
+		return Warning{}, false
+	}
+	switch classifyType(colType) {
+	case classString:
+		if lit.kind == tokenNumber {
+			return s.warning(RuleImplicitConversion, SeverityWarning, colFrom, litTo,
+				"字符串列 %s（%s）与数字 %s 比较会逐行转换类型，无法使用索引，应改为字符串字面量", name, colType, lit.text), true
+		}
+	case classNumeric:
+		if lit.kind == tokenString {
+			if _, ok := new(big.Rat).SetString(strings.TrimSpace(unquoteString(lit.text))); !ok {
+				return s.warning(RuleImplicitConversion, SeverityWarning, colFrom, litTo,
+					"数值列 %s（%s）与非数字字符串 %s 比较会发生隐式类型转换", name, colType, lit.text), true
+			}
+		}
+	}
+	return Warning{}, false
+}
+
+// nonSargable 检查对列施加函数、运算或类型转换后再与常量比较的情况。
+func (s *statement) nonSargable(exprFrom, exprTo, otherFrom, otherTo int) (Warning, bool) {
+	if _, _, ok := s.columnRef(exprFrom, exprTo); ok {
+		return Warning{}, false
+	}
+	if !s.hasColumn(exprFrom, exprTo) || s.hasColumn(otherFrom, otherTo) {
+		return Warning{}, false
+	}
+	first := s.tokens[exprFrom]
+	if first.kind == tokenWord && exprFrom+1 <= exprTo && s.tokens[exprFrom+1].is("(") && s.match[exprFrom+1] == exprTo {
+		return s.warning(RuleNonSargable, SeverityWarning, exprFrom, exprTo,
+			"对列使用函数 %s() 后比较无法使用索引，可改写为对常量一侧计算", strings.ToUpper(first.text)), true
+	}
+	return s.warning(RuleNonSargable, SeverityWarning, exprFrom, exprTo,
+		"对列进行运算或类型转换后比较无法使用索引，可改写为对常量一侧计算"), true
+}
+
+// checkUnknownColumns 标记表结构中不存在的列：限定列名在任意子句中检查，
+// 未限定列名仅在 WHERE/ON/SET 与 INSERT 列清单中且所有表结构已知时检查。
+func (s *statement) checkUnknownColumns() []Warning {
+	if s.schema == nil || !isVerb(s.verb) {
+		return nil
+	}
+	var warnings []Warning
+	for i := 0; i < len(s.tokens); i++ {
+		if !s.isColumnToken(i) || (i > 0 && s.tokens[i-1].is(".")) || s.isTableName(i) {
+			continue
+		}
+		end := i
+		for end+2 < len(s.tokens) && s.tokens[end+1].is(".") && s.tokens[end+2].isIdent() && end-i < 4 {
+			end += 2
+		}
+		if end+1 < len(s.tokens) && s.tokens[end+1].is(".") {
+			i = end + 1 // t.* 或不完整的引用
+			continue
+		}
+		qualifier, name, ok := s.columnRef(i, end)
+		if !ok {
+			i = end
+			continue
+		}
+		if qualifier == "" {
+			switch {
+			case s.clause[i] == "WHERE" || s.clause[i] == "ON" || s.clause[i] == "SET":
+			case s.clause[i] == "INTO" && s.parent[i] >= 0:
+				// INSERT INTO t (a, b) 的列清单
+			default:
+				continue
+			}
+		}
+		if _, tableKnown, found := s.resolve(qualifier, name); tableKnown && !found {
+			target := qualifier
+			if target == "" {
+				target = s.tables[0].name
+				if len(s.tables) > 1 {
+					target = "引用的表"
+				}
+			}
+			warnings = append(warnings, s.warning(RuleUnknownColumn, SeverityWarning, i, end,
+				"列 %s 不存在于 %s 中", name, target))
+		}
+		i = end
+	}
+	return warnings
+}
+
+// isTableName 判断 tokens[i] 是否位于 FROM/JOIN/UPDATE/INTO 的表名位置。
+func (s *statement) isTableName(i int) bool {
+	switch s.clause[i] {
+	case "FROM", "JOIN", "UPDATE", "DELETE":
+		return true
+	case "INTO":
+		return s.parent[i] < 0
+	}
+	return false
+}
+
+// unquoteString 去掉字符串字面量两侧的引号（含 $tag$ 形式）。
+func unquoteString(text string) string {
+	if strings.HasPrefix(text, "$") {
+		if idx := strings.Index(text[1:], "$"); idx >= 0 {
+			tag := text[:idx+2]
+			return strings.TrimSuffix(strings.TrimPrefix(text, tag), tag)
+		}
+	}
+	if len(text) >= 2 {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import "strings"
+
+// Schema 是用于列检查的表结构快照：小写表名 → 小写列名 → 列类型。
+// 表名可带 schema 前缀，查找时先匹配完整名称再匹配最后一段。
+type Schema map[string]map[string]string
+
+// Add 登记表的一列及其类型。
+func (s Schema) Add(table, column, dataType string) {
+	table = strings.ToLower(table)
+	cols, ok := s[table]
+	if !ok {
+		cols = make(map[string]string)
+		s[table] = cols
+	}
+	cols[strings.ToLower(column)] = strings.ToLower(dataType)
+}
+
+// Columns 返回表的列集合（小写列名 → 列类型），未登记时返回 false。
+func (s Schema) Columns(table string) (map[string]string, bool) {
+	table = strings.ToLower(table)
+	if cols, ok := s[table]; ok {
+		return cols, true
+	}
+	if idx := strings.LastIndex(table, "."); idx >= 0 {
+		cols, ok := s[table[idx+1:]]
+		return cols, ok
+	}
+	return nil, false
+}
+
+// typeClass 是列类型的大类，用于判断比较时是否发生隐式转换。
+type typeClass int
+
+const (
+	classOther typeClass = iota
+	classNumeric
+	classString
+)
+
+// numericTypes 与 stringTypes 是按类型名首个单词归类的 MySQL/PostgreSQL 类型。
+var (
+	numericTypes = map[string]bool{
+		"tinyint": true, "smallint": true, "mediumint": true, "int": true, "integer": true, "bigint": true,
+		"int2": true, "int4": true, "int8": true, "decimal": true, "numeric": true, "float": true, "float4": true,
+		"float8": true, "double": true, "real": true, "serial": true, "bigserial": true, "smallserial": true, "money": true,
+	}
+	stringTypes = map[string]bool{
+		"char": true, "varchar": true, "character": true, "nchar": true, "nvarchar": true, "bpchar": true,
+		"tinytext": true, "text": true, "mediumtext": true, "longtext": true, "enum": true, "set": true, "citext": true,
+	}
+)
+
+// classifyType 按类型名首个单词（如 int(11) unsigned 中的 int）判断列类型大类。
+func classifyType(dataType string) typeClass {
+	t := strings.TrimSpace(strings.ToLower(dataType))
+	end := strings.IndexFunc(t, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	if end >= 0 {
+		t = t[:end]
+	}
+	switch {
+	case numericTypes[t]:
+		return classNumeric
+	case stringTypes[t]:
+		return classString
+	default:
+		return classOther
+	}
+}
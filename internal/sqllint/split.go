@@ -0,0 +1,38 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import "strings"
+
+// SplitStatements 按顶层分号切分 sql，返回以分号结尾的完整语句（不含分号、已去除首尾空白）与末尾未结束的文本。
+// 字符串、引用标识符与注释中的分号不参与切分；只有注释或空白的语句会被跳过。
+func SplitStatements(sql string, postgres bool) ([]string, string) {
+	runes := []rune(sql)
+	var stmts []string
+	start := 0
+	empty := true
+	for _, t := range tokenize(runes, postgres) {
+		if t.kind != tokenSymbol || t.text != ";" {
+			empty = false
+			continue
+		}
+		if !empty {
+			stmts = append(stmts, strings.TrimSpace(string(runes[start:t.start])))
+		}
+		start = t.end
+		empty = true
+	}
+	return stmts, strings.TrimSpace(string(runes[start:]))
+}
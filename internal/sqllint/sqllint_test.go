@@ -0,0 +1,267 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqllint
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSchema() Schema {
+	s := Schema{}
+	s.Add("users", "id", "bigint(20) unsigned")
+	s.Add("users", "name", "varchar(64)")
+	s.Add("users", "phone", "varchar(20)")
+	s.Add("users", "created_at", "datetime")
+	s.Add("orders", "id", "int")
+	s.Add("orders", "user_id", "bigint")
+	s.Add("orders", "amount", "decimal(10,2)")
+	return s
+}
+
+// rules 返回告警的规则编号列表，便于断言。
+func rules(warnings []Warning) []string {
+	out := make([]string, len(warnings))
+	for i, w := range warnings {
+		out[i] = w.Rule
+	}
+	return out
+}
+
+func TestAnalyzeSelectStarAndMissingWhere(t *testing.T) {
+	sql := "SELECT * FROM users;\nSELECT COUNT(*), o.* FROM orders o WHERE EXISTS (SELECT * FROM users u WHERE u.id = o.user_id);\nDELETE FROM orders;\nUPDATE users SET name = 'x' WHERE id = 1"
+	warnings := Analyze(sql, Options{})
+	got := strings.Join(rules(warnings), ",")
+	want := "select-star,select-star,missing-where"
+	if got != want {
+		t.Fatalf("规则不符: %s，期望 %s (%+v)", got, want, warnings)
+	}
+
+	star := warnings[1]
+	if star.Statement != 1 || star.StartLine != 2 || star.StartColumn != 18 || star.EndColumn != 21 {
+		t.Fatalf("o.* 位置不符: %+v", star)
+	}
+	del := warnings[2]
+	if del.StartLine != 3 || del.StartColumn != 1 || del.Length != len("DELETE") {
+		t.Fatalf("DELETE 位置不符: %+v", del)
+	}
+}
+
+func TestAnalyzeNonSargable(t *testing.T) {
+	cases := map[string]bool{
+		"SELECT id FROM users WHERE DATE(created_at) = '2026-01-01'":      true,
+		"SELECT id FROM users WHERE id + 1 = 10":                          true,
+		"SELECT id FROM users WHERE name LIKE '%bob'":                     true,
+		"SELECT id FROM users WHERE created_at::date = $1":                true,
+		"SELECT id FROM users WHERE created_at >= NOW() - INTERVAL 1 DAY": false,
+		"SELECT id FROM users WHERE name LIKE 'bob%'":                     false,
+		"SELECT u.id FROM users u JOIN orders o ON o.user_id = u.id":      false,
+		"SELECT id FROM users WHERE LOWER(name) = LOWER(phone)":           false,
+		"SELECT id FROM users WHERE (id = 1 OR name = 'a') AND phone = ?": false,
+	}
+	for sql, want := range cases {
+		warnings := Analyze(sql, Options{Postgres: strings.Contains(sql, "::")})
+		got := false
+		for _, w := range warnings {
+			if w.Rule == RuleNonSargable {
+				got = true
+			}
+		}
+		if got != want {
+			t.Errorf("%s: 期望 non-sargable=%v，得到 %+v", sql, want, warnings)
+		}
+	}
+}
+
+func TestAnalyzeImplicitConversion(t *testing.T) {
+	opts := Options{Schema: testSchema()}
+	warnings := Analyze("SELECT id FROM users u WHERE u.phone = 13800000000 AND id = '12' AND 'abc' = id", opts)
+	if got := strings.Join(rules(warnings), ","); got != "implicit-conversion,implicit-conversion" {
+		t.Fatalf("规则不符: %s (%+v)", got, warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "phone") || !strings.Contains(warnings[1].Message, "'abc'") {
+		t.Fatalf("告警内容不符: %+v", warnings)
+	}
+
+	if warnings := Analyze("SELECT id FROM users WHERE phone = 138", Options{}); len(warnings) != 0 {
+		t.Fatalf("无表结构时不应报告类型转换: %+v", warnings)
+	}
+}
+
+func TestAnalyzeUnknownColumn(t *testing.T) {
+	opts := Options{Schema: testSchema()}
+	sql := "SELECT u.nme, o.amount FROM users u JOIN orders o ON o.usr_id = u.id WHERE u.id = 1"
+	warnings := Analyze(sql, opts)
+	if got := strings.Join(rules(warnings), ","); got != "unknown-column,unknown-column" {
+		t.Fatalf("规则不符: %s (%+v)", got, warnings)
+	}
+	if warnings[0].Offset != strings.Index(sql, "u.nme") || warnings[0].Length != len("u.nme") {
+		t.Fatalf("u.nme 位置不符: %+v", warnings[0])
+	}
+
+	warnings = Analyze("UPDATE users SET nickname = 'a' WHERE id = 1; INSERT INTO orders (id, total) VALUES (1, 2)", opts)
+	if got := strings.Join(rules(warnings), ","); got != "unknown-column,unknown-column" {
+		t.Fatalf("规则不符: %s (%+v)", got, warnings)
+	}
+
+	lenient := []string{
+		"SELECT id FROM users WHERE missing = 1 AND id IN (SELECT user_id FROM audit)",
+		"WITH t AS (SELECT id AS uid FROM users) SELECT uid FROM t WHERE uid = 1",
+		"SELECT d.x FROM (SELECT id AS x FROM users) d WHERE d.x = 1",
+		"SELECT id FROM users WHERE name = E'a' AND created_at > CURRENT_DATE",
+	}
+	for _, sql := range lenient {
+		if warnings := Analyze(sql, Options{Schema: testSchema(), Postgres: true}); len(warnings) != 0 {
+			t.Errorf("%s: 不应报告告警，得到 %+v", sql, warnings)
+		}
+	}
+}
+
+func TestTokenizeLiteralsAndComments(t *testing.T) {
+	sql := "SELECT 'a;b', \"x\" -- c;\n/* ; */ FROM t WHERE v = $tag$ ; $tag$ AND w = $1"
+	tokens := tokenize([]rune(sql), true)
+	for _, tok := range tokens {
+		if tok.text == ";" {
+			t.Fatalf("字符串或注释中的分号不应切分: %+v", tokens)
+		}
+	}
+	if warnings := Analyze(sql, Options{Postgres: true}); len(warnings) != 0 {
+		t.Fatalf("不应报告告警: %+v", warnings)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		sql      string
+		postgres bool
+		kind     string
+		keyword  string
+		rows     bool
+	}{
+		{"  -- 注释\n/* 块注释 */ select 1", false, StatementQuery, "SELECT", true},
+		{"WITH t AS (SELECT 1) SELECT * FROM t", false, StatementQuery, "SELECT", true},
+		{"WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM t) SELECT n FROM t", true, StatementQuery, "SELECT", true},
+		{"with d as materialized (delete from a returning *) insert into b select * from d", true, StatementDML, "INSERT", false},
+		{"INSERT INTO t (a) VALUES (1) RETURNING id", true, StatementDML, "INSERT", true},
+		{"UPDATE t SET a = (SELECT 1) WHERE id = 2", false, StatementDML, "UPDATE", false},
+		{"WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d", true, StatementDML, "SELECT", true},
+		{"WITH a AS (SELECT 1), b AS (UPDATE t SET x = 1 RETURNING id) SELECT * FROM a, b", true, StatementDML, "SELECT", true},
+		{"WITH a AS (WITH b AS (INSERT INTO t VALUES (1) RETURNING id) SELECT * FROM b) SELECT * FROM a", true, StatementDML, "SELECT", true},
+		{"SELECT * FROM t FOR UPDATE", false, StatementDML, "SELECT", true},
+		{"SELECT * FROM t WHERE id = 1 FOR NO KEY UPDATE SKIP LOCKED", true, StatementDML, "SELECT", true},
+		{"select * from t for share", true, StatementDML, "SELECT", true},
+		{"SELECT * FROM t LOCK IN SHARE MODE", false, StatementDML, "SELECT", true},
+		{"SELECT * FROM (SELECT * FROM t FOR UPDATE) s", false, StatementDML, "SELECT", true},
+		{"SELECT * FROM t WHERE lock IN (1, 2)", false, StatementQuery, "SELECT", true},
+		{"SELECT a INTO @v FROM t", false, StatementOther, "SELECT", false},
+		{"SELECT * INTO archive FROM t", true, StatementDDL, "SELECT", false},
+		{"SELECT (SELECT 1 INTO x) AS y", false, StatementQuery, "SELECT", true},
+		{"(SELECT 1) UNION (SELECT 2)", false, StatementQuery, "SELECT", true},
+		{"# mysql 注释\nSHOW TABLES", false, StatementQuery, "SHOW", true},
+		{"CREATE TABLE t (id int)", false, StatementDDL, "CREATE", false},
+		{"SET NAMES utf8mb4; SELECT 1", false, StatementOther, "SET", false},
+		{" ; -- 仅注释", false, StatementEmpty, "", false},
+	}
+	for _, c := range cases {
+		got := Classify(c.sql, c.postgres)
+		if got.Kind != c.kind || got.Keyword != c.keyword || got.ReturnsRows != c.rows {
+			t.Errorf("%q: 得到 %+v，期望 kind=%s keyword=%s rows=%v", c.sql, got, c.kind, c.keyword, c.rows)
+		}
+	}
+
+	multiple := map[string]bool{
+		"SELECT 1":                    false,
+		"SELECT 1;  ;\n-- 注释":         false,
+		"SELECT ';'":                  false,
+		"SELECT 1; DELETE FROM t":     true,
+		"/* x */ SELECT 1;\nSELECT 2": true,
+	}
+	for sql, want := range multiple {
+		if got := Classify(sql, false).Multiple; got != want {
+			t.Errorf("%q: Multiple = %v，期望 %v", sql, got, want)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts, rest := SplitStatements("SELECT ';' AS a; -- c;\n;\nUPDATE t SET x = 1 /* ; */;\nSELECT 'open;", false)
+	if len(stmts) != 2 || stmts[0] != "SELECT ';' AS a" || stmts[1] != "UPDATE t SET x = 1 /* ; */" {
+		t.Fatalf("stmts = %q", stmts)
+	}
+	if rest != "SELECT 'open;" {
+		t.Fatalf("rest = %q", rest)
+	}
+
+	stmts, rest = SplitStatements(`SELECT $$a;b$$, "x;y";`, true)
+	if len(stmts) != 1 || stmts[0] != `SELECT $$a;b$$, "x;y"` || rest != "" {
+		t.Fatalf("postgres stmts = %q rest = %q", stmts, rest)
+	}
+}
+
+func TestCheckExpression(t *testing.T) {
+	known := map[string]bool{"first": true, "last": true, "price": true, "qty": true, "created_at": true, "select": true}
+	column := func(name string) bool { return known[strings.ToLower(name)] }
+
+	valid := []string{
+		"CONCAT(first, ' ', last)",
+		"price * qty",
+		"CASE WHEN qty > 10 THEN 'bulk' ELSE 'single' END",
+		"CAST(price AS DECIMAL(10, 2))",
+		"COALESCE(`last`, 'n/a')",
+		"DATE_FORMAT(created_at, '%Y-%m')",
+		"qty IN (1, 2) AND NOT price IS NULL",
+		"`select` + 1",
+	}
+	for _, expr := range valid {
+		if _, err := CheckExpression(expr, false, column); err != nil {
+			t.Errorf("CheckExpression(%q) 应通过: %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"price; DROP TABLE t",
+		"(SELECT 1)",
+		"price -- comment",
+		"price /* x */ + 1",
+		"missing + 1",
+		"SLEEP(10)",
+		"COUNT(*)",
+		"t.price",
+		"price + ?",
+		"'unterminated",
+		"CONCAT(first, 'it''s",
+		"(price",
+		"price)",
+		"price AS x",
+		"select + 1",
+	}
+	for _, expr := range invalid {
+		if _, err := CheckExpression(expr, false, column); err == nil {
+			t.Errorf("CheckExpression(%q) 应被拒绝", expr)
+		}
+	}
+
+	cols, err := CheckExpression(`price::text || "last"`, true, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(cols, ",") != "price,last" {
+		t.Errorf("引用列 = %v", cols)
+	}
+	if _, err := CheckExpression("price::text", false, column); err == nil {
+		t.Error("非 PostgreSQL 方言应拒绝 ::")
+	}
+}
@@ -19,12 +19,13 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/connection"
 	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
 
-	"github.com/go-sql-driver/mysql"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -38,27 +39,88 @@ func (d *ViaSSHDialer) Dial(ctx context.Context, addr string) (net.Conn, error)
 	return dialContext(ctx, d.sshClient, "tcp", addr)
 }
 
-// RegisterSSHNetwork为指定的SSH隧道注册一个唯一的网络名
-// 返回在DSN中使用的网络名
-func RegisterSSHNetwork(sshConfig *connection.SSHConfig) (string, error) {
-	client, err := connectSSH(sshConfig)
-	if err != nil {
-		return "", err
+// defaultTunnels 是数据库连接共用的隧道管理器
+var defaultTunnels = NewTunnelManager(DefaultKeepaliveInterval)
+
+// defaultDialTimeout 是调用方未给出截止时间时 SSH 建连、握手与认证的总超时
+const defaultDialTimeout = 5 * time.Second
+
+// HostKeyVerifier 校验跳板机主机密钥，host 为 host:port，返回错误时中止连接
+type HostKeyVerifier func(host string, remote net.Addr, key ssh.PublicKey) error
+
+var (
+	hostKeyMu       sync.RWMutex
+	hostKeyVerifier HostKeyVerifier
+)
+
+// SetHostKeyVerifier 设置全部 SSH 连接（含隧道重连）使用的主机密钥校验函数，传入 nil 表示不校验
+func SetHostKeyVerifier(fn HostKeyVerifier) {
+	hostKeyMu.Lock()
+	defer hostKeyMu.Unlock()
+	hostKeyVerifier = fn
+}
+
+// hostKeyCallback 返回握手使用的主机密钥回调，未设置校验函数时接受任意密钥
+func hostKeyCallback() ssh.HostKeyCallback {
+	hostKeyMu.RLock()
+	fn := hostKeyVerifier
+	hostKeyMu.RUnlock()
+	if fn == nil {
+		logger.Warn("未启用 SSH 主机密钥校验，将接受任意主机密钥")
+		return ssh.InsecureIgnoreHostKey()
 	}
+	return ssh.HostKeyCallback(fn)
+}
 
-	// 生产唯一的网络名
-	netName := fmt.Sprintf("ssh_%s_%d", sshConfig.Host, time.Now().UnixNano())
-	logger.Info("注册 SSH 网络：%s（地址=%s:%d 用户=%s）", netName, sshConfig.Host, sshConfig.Port, sshConfig.User)
+// RegisterSSHNetwork为指定的SSH隧道注册一个网络名，同一跳板机的连接共享隧道
+// 返回在DSN中使用的网络名，使用完毕后需调用 CloseSSHNetwork 释放
+func RegisterSSHNetwork(sshConfig *connection.SSHConfig) (string, error) {
+	return RegisterSSHNetworkContext(context.Background(), sshConfig)
+}
+
+// RegisterSSHNetworkContext 与 RegisterSSHNetwork 相同，新建隧道时的拨号与握手受 ctx 约束
+func RegisterSSHNetworkContext(ctx context.Context, sshConfig *connection.SSHConfig) (string, error) {
+	return defaultTunnels.AcquireContext(ctx, sshConfig)
+}
+
+// CloseSSHNetwork 释放网络名对应的隧道引用，最后一个引用释放时关闭 SSH 客户端；网络名未注册时忽略
+func CloseSSHNetwork(netName string) error {
+	return defaultTunnels.Release(netName)
+}
 
-	mysql.RegisterDialContext(netName, func(ctx context.Context, addr string) (net.Conn, error) {
-		return dialContext(ctx, client, "tcp", addr)
-	})
+// DialNetwork 通过已注册网络名对应的隧道拨号 addr，供 PostgreSQL 等以拨号器接入的驱动使用
+func DialNetwork(ctx context.Context, netName, addr string) (net.Conn, error) {
+	return defaultTunnels.Dial(ctx, netName, addr)
+}
+
+// TunnelStatuses 返回所有 SSH 隧道的状态
+func TunnelStatuses() []TunnelStatus {
+	return defaultTunnels.Statuses()
+}
 
-	return netName, nil
+// SetTunnelStatusListener 设置 SSH 隧道状态变化的回调
+func SetTunnelStatusListener(fn func(TunnelStatus)) {
+	defaultTunnels.SetStatusListener(fn)
+}
+
+// MeasureHandshake 新建一条独立于共享隧道的 SSH 连接并立即关闭，返回 TCP 建连、握手与认证的总耗时
+func MeasureHandshake(ctx context.Context, config *connection.SSHConfig) (time.Duration, error) {
+	if config == nil {
+		return 0, fmt.Errorf("SSH 配置为空")
+	}
+	start := time.Now()
+	client, err := connectSSH(ctx, config)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	_ = client.Close()
+	return elapsed, nil
 }
 
 // connectSSH建立一个SSH连接并返回一个Dialer
-func connectSSH(config *connection.SSHConfig) (*ssh.Client, error) {
+// TCP 建连、握手与认证整体受 ctx 约束，ctx 没有截止时间时使用 defaultDialTimeout
+func connectSSH(ctx context.Context, config *connection.SSHConfig) (*ssh.Client, error) {
 	logger.Info("开始建立ssh连接，地址=%s:%d 用户=%s", config.Host, config.Port, config.User)
 	authMethods := []ssh.AuthMethod{}
 
@@ -87,12 +149,14 @@ func connectSSH(config *connection.SSHConfig) (*ssh.Client, error) {
 	sshConfig := &ssh.ClientConfig{
 		User:            config.User,
 		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // 在生产中使用严格的检查！
-		Timeout:         5 * time.Second,
+		HostKeyCallback: hostKeyCallback(),
 	}
 
 	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	client, err := ssh.Dial("tcp", addr, sshConfig)
+	if netproxy.Enabled(config.Proxy) {
+		logger.Info("通过 %s 代理连接跳板机：代理=%s", config.Proxy.Type, netproxy.Address(config.Proxy))
+	}
+	client, err := dialSSH(ctx, addr, config.Proxy, sshConfig)
 	if err != nil {
 		logger.Error("SSH 连接建立失败：地址=%s 用户=%s, err: %w", addr, config.User, err)
 		return nil, err
@@ -101,12 +165,53 @@ func connectSSH(config *connection.SSHConfig) (*ssh.Client, error) {
 	return client, nil
 }
 
+// dialSSH 在 ctx 的截止时间内完成 TCP 建连（proxy 不为空时经由代理）与 SSH 握手，ctx 取消时立即中断握手
+func dialSSH(ctx context.Context, addr string, proxy *connection.ProxyConfig, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultDialTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	conn, err := netproxy.Dial(ctx, proxy, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 握手阶段没有 ctx 参数，借助连接截止时间与取消时关闭连接来约束
+	_ = conn.SetDeadline(deadline)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	close(done)
+	if err != nil {
+		_ = conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
 // dialContext 是一个辅助函数，用于在SSH连接上拨号，并支持上下文取消
 func dialContext(ctx context.Context, client *ssh.Client, network, addr string) (net.Conn, error) {
 	if client == nil {
 		return nil, fmt.Errorf("SSH 客户端为 nil")
 	}
+	return dialClient(ctx, client, network, addr)
+}
 
+// dialClient 在隧道客户端上拨号，ctx 取消时放弃等待并关闭迟到的连接
+func dialClient(ctx context.Context, client tunnelClient, network, addr string) (net.Conn, error) {
 	type result struct {
 		conn net.Conn
 		err  error
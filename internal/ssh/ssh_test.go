@@ -16,7 +16,10 @@ package ssh
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -81,7 +84,7 @@ func TestConnectSSH_InvalidConfig(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := connectSSH(tt.config)
+			client, err := connectSSH(context.Background(), tt.config)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("connectSSH() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,7 +114,7 @@ func TestConnectSSH_InvalidKeyFile(t *testing.T) {
 		KeyPath: invalidKeyFile,
 	}
 
-	client, err := connectSSH(config)
+	client, err := connectSSH(context.Background(), config)
 	if err == nil {
 		client.Close()
 		t.Error("期望使用无效密钥文件时出错，但没有错误")
@@ -465,3 +468,68 @@ func validateSSHConfig(config *connection.SSHConfig) bool {
 	}
 	return true
 }
+
+// TestConnectSSH_HostKeyVerifier 测试主机密钥校验失败时中止连接，且错误可被调用方识别
+func TestConnectSSH_HostKeyVerifier(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(signer)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_, chans, reqs, err := ssh.NewServerConn(c, serverConfig)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					ch.Reject(ssh.Prohibited, "")
+				}
+			}()
+		}
+	}()
+
+	rejected := errors.New("rejected")
+	var seenHost string
+	var seenKey ssh.PublicKey
+	SetHostKeyVerifier(func(host string, remote net.Addr, key ssh.PublicKey) error {
+		seenHost, seenKey = host, key
+		return rejected
+	})
+	defer SetHostKeyVerifier(nil)
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	cfg := &connection.SSHConfig{Host: "127.0.0.1", Port: port, User: "u", Password: "p"}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := connectSSH(ctx, cfg); !errors.Is(err, rejected) {
+		t.Fatalf("校验失败应中止连接并保留错误: %v", err)
+	}
+	if seenHost != fmt.Sprintf("127.0.0.1:%d", port) || seenKey == nil || string(seenKey.Marshal()) != string(signer.PublicKey().Marshal()) {
+		t.Fatalf("校验函数收到的参数错误: %s %v", seenHost, seenKey)
+	}
+
+	SetHostKeyVerifier(func(string, net.Addr, ssh.PublicKey) error { return nil })
+	client, err := connectSSH(ctx, cfg)
+	if err != nil {
+		t.Fatalf("校验通过时应建立连接: %v", err)
+	}
+	client.Close()
+}
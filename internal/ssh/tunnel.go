@@ -0,0 +1,407 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/chenyang-zz/boxify/internal/netproxy"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// 隧道状态
+const (
+	TunnelConnected    = "connected"
+	TunnelReconnecting = "reconnecting"
+	TunnelClosed       = "closed"
+)
+
+// DefaultKeepaliveInterval 是隧道保活与断线检查的默认间隔
+const DefaultKeepaliveInterval = 30 * time.Second
+
+// keepaliveTimeout 是单次保活请求等待响应的最长时间
+const keepaliveTimeout = 10 * time.Second
+
+// TunnelStatus 是隧道的状态快照
+type TunnelStatus struct {
+	Network       string    `json:"network"`       // 注册给驱动的网络名
+	Host          string    `json:"host"`          // 跳板机地址
+	Port          int       `json:"port"`          // 跳板机端口
+	User          string    `json:"user"`          // 跳板机用户
+	State         string    `json:"state"`         // connected / reconnecting / closed
+	Refs          int       `json:"refs"`          // 使用该隧道的数据库连接数
+	Reconnects    int       `json:"reconnects"`    // 自动重连成功次数
+	LastError     string    `json:"lastError"`     // 最近一次保活或重连错误
+	ConnectedAt   time.Time `json:"connectedAt"`   // 最近一次建立连接的时间
+	LastKeepalive time.Time `json:"lastKeepalive"` // 最近一次保活成功的时间
+}
+
+// tunnelClient 是隧道使用的 SSH 客户端能力，*ssh.Client 满足该接口
+type tunnelClient interface {
+	Dial(network, addr string) (net.Conn, error)
+	SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error)
+	Close() error
+}
+
+// TunnelManager 管理 SSH 隧道：同一跳板机（地址、用户与凭据相同）的数据库连接共享一个客户端，
+// 按引用计数释放，后台定期保活并在断线后自动重连，状态变化时通知监听者
+type TunnelManager struct {
+	mu                sync.Mutex
+	tunnels           map[string]*tunnel // 跳板机标识 -> 隧道
+	byNetwork         map[string]*tunnel // 网络名 -> 隧道
+	keepaliveInterval time.Duration
+	connect           func(context.Context, *connection.SSHConfig) (tunnelClient, error)
+	register          func(netName string, dial mysql.DialContextFunc)
+	listener          func(TunnelStatus)
+}
+
+// NewTunnelManager 创建隧道管理器，keepaliveInterval<=0 时使用默认间隔
+func NewTunnelManager(keepaliveInterval time.Duration) *TunnelManager {
+	if keepaliveInterval <= 0 {
+		keepaliveInterval = DefaultKeepaliveInterval
+	}
+	return &TunnelManager{
+		tunnels:           make(map[string]*tunnel),
+		byNetwork:         make(map[string]*tunnel),
+		keepaliveInterval: keepaliveInterval,
+		connect: func(ctx context.Context, config *connection.SSHConfig) (tunnelClient, error) {
+			return connectSSH(ctx, config)
+		},
+		register: mysql.RegisterDialContext,
+	}
+}
+
+// SetStatusListener 设置隧道状态变化的回调（在后台协程中调用，不应阻塞）
+func (m *TunnelManager) SetStatusListener(fn func(TunnelStatus)) {
+	m.mu.Lock()
+	m.listener = fn
+	m.mu.Unlock()
+}
+
+// Acquire 返回可用于 DSN 的网络名：已有同一跳板机的隧道时复用并增加引用，否则新建
+func (m *TunnelManager) Acquire(config *connection.SSHConfig) (string, error) {
+	return m.AcquireContext(context.Background(), config)
+}
+
+// AcquireContext 与 Acquire 相同，新建隧道时的拨号与握手受 ctx 约束
+func (m *TunnelManager) AcquireContext(ctx context.Context, config *connection.SSHConfig) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("SSH 配置为空")
+	}
+	key := tunnelKey(config)
+
+	m.mu.Lock()
+	if t, ok := m.tunnels[key]; ok {
+		t.mu.Lock()
+		t.status.Refs++
+		t.mu.Unlock()
+		m.mu.Unlock()
+		m.notify(t)
+		return t.status.Network, nil
+	}
+	m.mu.Unlock()
+
+	client, err := m.connect(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	if t, ok := m.tunnels[key]; ok {
+		// 并发建立时以先完成的为准
+		t.mu.Lock()
+		t.status.Refs++
+		t.mu.Unlock()
+		m.mu.Unlock()
+		_ = client.Close()
+		m.notify(t)
+		return t.status.Network, nil
+	}
+	now := time.Now()
+	t := &tunnel{
+		mgr:    m,
+		key:    key,
+		config: *config,
+		client: client,
+		stop:   make(chan struct{}),
+		kick:   make(chan struct{}, 1),
+		status: TunnelStatus{
+			Network:     fmt.Sprintf("ssh_%s_%d", config.Host, now.UnixNano()),
+			Host:        config.Host,
+			Port:        config.Port,
+			User:        config.User,
+			State:       TunnelConnected,
+			Refs:        1,
+			ConnectedAt: now,
+		},
+	}
+	m.tunnels[key] = t
+	m.byNetwork[t.status.Network] = t
+	m.mu.Unlock()
+
+	m.register(t.status.Network, t.dial)
+	logger.Info("注册 SSH 网络：%s（地址=%s:%d 用户=%s）", t.status.Network, config.Host, config.Port, config.User)
+	go t.keepalive(m.keepaliveInterval)
+	m.notify(t)
+	return t.status.Network, nil
+}
+
+// Release 减少网络名对应隧道的引用，引用归零时关闭客户端；网络名未注册时忽略
+func (m *TunnelManager) Release(netName string) error {
+	m.mu.Lock()
+	t, ok := m.byNetwork[netName]
+	if !ok {
+		m.mu.Unlock()
+		return nil
+	}
+	t.mu.Lock()
+	t.status.Refs--
+	last := t.status.Refs <= 0
+	t.mu.Unlock()
+	if last {
+		delete(m.byNetwork, netName)
+		delete(m.tunnels, t.key)
+	}
+	m.mu.Unlock()
+
+	if !last {
+		m.notify(t)
+		return nil
+	}
+	err := t.close()
+	logger.Info("关闭 SSH 网络：%s", netName)
+	m.notify(t)
+	return err
+}
+
+// Dial 通过网络名对应的隧道拨号，供无法注册自定义网络、只能传入拨号器的驱动使用；网络名未注册时返回错误
+func (m *TunnelManager) Dial(ctx context.Context, netName, addr string) (net.Conn, error) {
+	m.mu.Lock()
+	t, ok := m.byNetwork[netName]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("SSH 网络未注册: %s", netName)
+	}
+	return t.dial(ctx, addr)
+}
+
+// Statuses 返回所有隧道的状态，按网络名排序
+func (m *TunnelManager) Statuses() []TunnelStatus {
+	m.mu.Lock()
+	list := make([]*tunnel, 0, len(m.tunnels))
+	for _, t := range m.tunnels {
+		list = append(list, t)
+	}
+	m.mu.Unlock()
+
+	statuses := make([]TunnelStatus, 0, len(list))
+	for _, t := range list {
+		statuses = append(statuses, t.snapshot())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Network < statuses[j].Network })
+	return statuses
+}
+
+// notify 把隧道当前状态推送给监听者
+func (m *TunnelManager) notify(t *tunnel) {
+	m.mu.Lock()
+	fn := m.listener
+	m.mu.Unlock()
+	if fn != nil {
+		fn(t.snapshot())
+	}
+}
+
+// tunnelKey 返回跳板机标识；凭据与代理以摘要参与，避免不同凭据或不同代理共用客户端
+func tunnelKey(config *connection.SSHConfig) string {
+	material := config.Password + "\x00" + config.KeyPath
+	if p := config.Proxy; netproxy.Enabled(p) {
+		material += fmt.Sprintf("\x00%s://%s@%s:%d\x00%s", p.Type, p.User, p.Host, p.Port, p.Password)
+	}
+	sum := sha256.Sum256([]byte(material))
+	return fmt.Sprintf("%s@%s:%d/%s", config.User, config.Host, config.Port, hex.EncodeToString(sum[:8]))
+}
+
+// tunnel 是一个共享的 SSH 客户端及其保活状态
+type tunnel struct {
+	mgr    *TunnelManager
+	key    string
+	config connection.SSHConfig
+	stop   chan struct{}
+	kick   chan struct{} // 拨号失败时唤醒保活协程立即检查
+
+	mu     sync.Mutex
+	client tunnelClient
+	status TunnelStatus
+	closed bool
+}
+
+// snapshot 返回状态副本
+func (t *tunnel) snapshot() TunnelStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// dial 通过当前客户端拨号，失败时触发一次健康检查
+func (t *tunnel) dial(ctx context.Context, addr string) (net.Conn, error) {
+	t.mu.Lock()
+	client := t.client
+	state := t.status.State
+	t.mu.Unlock()
+	if client == nil || state != TunnelConnected {
+		t.wake()
+		return nil, fmt.Errorf("SSH 隧道不可用（%s）", state)
+	}
+	conn, err := dialClient(ctx, client, "tcp", addr)
+	if err != nil && ctx.Err() == nil {
+		t.wake()
+	}
+	return conn, err
+}
+
+// wake 非阻塞地唤醒保活协程
+func (t *tunnel) wake() {
+	select {
+	case t.kick <- struct{}{}:
+	default:
+	}
+}
+
+// keepalive 定期发送保活请求，失败时重连直到成功或隧道关闭
+func (t *tunnel) keepalive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		case <-t.kick:
+		}
+		t.check()
+	}
+}
+
+// check 执行一次保活，失败时尝试重连
+func (t *tunnel) check() {
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+
+	err := sendKeepalive(client)
+	if err == nil {
+		t.mu.Lock()
+		t.status.LastKeepalive = time.Now()
+		t.mu.Unlock()
+		return
+	}
+
+	logger.Warn("SSH 隧道保活失败，准备重连：网络=%s，原因：%v", t.status.Network, err)
+	if !t.setState(TunnelReconnecting, err) {
+		return
+	}
+	t.mgr.notify(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDialTimeout)
+	newClient, err := t.mgr.connect(ctx, &t.config)
+	cancel()
+	if err != nil {
+		t.setState(TunnelReconnecting, err)
+		t.mgr.notify(t)
+		return
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		_ = newClient.Close()
+		return
+	}
+	old := t.client
+	t.client = newClient
+	t.status.State = TunnelConnected
+	t.status.Reconnects++
+	t.status.LastError = ""
+	t.status.ConnectedAt = time.Now()
+	t.mu.Unlock()
+	if old != nil {
+		_ = old.Close()
+	}
+	logger.Info("SSH 隧道已重连：网络=%s", t.status.Network)
+	t.mgr.notify(t)
+}
+
+// setState 更新状态与错误，隧道已关闭时返回 false
+func (t *tunnel) setState(state string, err error) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.status.State = state
+	if err != nil {
+		t.status.LastError = err.Error()
+	}
+	return true
+}
+
+// close 停止保活并关闭客户端
+func (t *tunnel) close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.status.State = TunnelClosed
+	client := t.client
+	t.client = nil
+	t.mu.Unlock()
+
+	close(t.stop)
+	if client != nil {
+		return client.Close()
+	}
+	return nil
+}
+
+// sendKeepalive 发送 OpenSSH 保活请求，超时视为失败
+func sendKeepalive(client tunnelClient) error {
+	if client == nil {
+		return fmt.Errorf("SSH 客户端为 nil")
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(keepaliveTimeout):
+		return fmt.Errorf("保活请求超时")
+	}
+}
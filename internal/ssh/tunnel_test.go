@@ -0,0 +1,183 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// fakeClient 模拟 SSH 客户端，alive 为 false 时保活与拨号失败
+type fakeClient struct {
+	mu     sync.Mutex
+	alive  bool
+	closed bool
+}
+
+func (c *fakeClient) Dial(network, addr string) (net.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.alive {
+		return nil, errors.New("connection lost")
+	}
+	server, client := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func (c *fakeClient) SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.alive {
+		return false, nil, errors.New("connection lost")
+	}
+	return true, nil, nil
+}
+
+func (c *fakeClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed, c.alive = true, false
+	return nil
+}
+
+// newTestManager 创建使用假客户端、不注册驱动网络的隧道管理器
+func newTestManager(t *testing.T) (*TunnelManager, *[]*fakeClient, map[string]mysql.DialContextFunc) {
+	t.Helper()
+	m := NewTunnelManager(time.Hour)
+	var clients []*fakeClient
+	var mu sync.Mutex
+	dialers := make(map[string]mysql.DialContextFunc)
+	m.connect = func(_ context.Context, config *connection.SSHConfig) (tunnelClient, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		c := &fakeClient{alive: true}
+		clients = append(clients, c)
+		return c, nil
+	}
+	m.register = func(netName string, dial mysql.DialContextFunc) { dialers[netName] = dial }
+	return m, &clients, dialers
+}
+
+func TestTunnelManagerSharesAndReleases(t *testing.T) {
+	m, clients, _ := newTestManager(t)
+	cfg := &connection.SSHConfig{Host: "bastion", Port: 22, User: "ops", Password: "p"}
+
+	net1, err := m.Acquire(cfg)
+	if err != nil {
+		t.Fatalf("Acquire 失败: %v", err)
+	}
+	net2, _ := m.Acquire(cfg)
+	other, _ := m.Acquire(&connection.SSHConfig{Host: "bastion", Port: 22, User: "ops", Password: "other"})
+	if net1 != net2 || net1 == other || len(*clients) != 2 {
+		t.Fatalf("同一跳板机应共享隧道: net1=%s net2=%s other=%s clients=%d", net1, net2, other, len(*clients))
+	}
+	if statuses := m.Statuses(); len(statuses) != 2 {
+		t.Fatalf("应有 2 条隧道，实际 %d", len(statuses))
+	}
+
+	_ = m.Release(net1)
+	if (*clients)[0].closed {
+		t.Fatal("仍有引用时不应关闭客户端")
+	}
+	_ = m.Release(net2)
+	if !(*clients)[0].closed {
+		t.Fatal("引用归零后应关闭客户端")
+	}
+	if err := m.Release("unknown"); err != nil {
+		t.Fatalf("释放未注册网络名应忽略: %v", err)
+	}
+	if statuses := m.Statuses(); len(statuses) != 1 || statuses[0].Network != other {
+		t.Fatalf("剩余隧道不符: %+v", statuses)
+	}
+}
+
+func TestTunnelManagerDialByNetwork(t *testing.T) {
+	m, _, _ := newTestManager(t)
+	netName, err := m.Acquire(&connection.SSHConfig{Host: "bastion", Port: 22, User: "ops"})
+	if err != nil {
+		t.Fatalf("Acquire 失败: %v", err)
+	}
+	conn, err := m.Dial(context.Background(), netName, "db:5432")
+	if err != nil {
+		t.Fatalf("按网络名拨号失败: %v", err)
+	}
+	_ = conn.Close()
+
+	_ = m.Release(netName)
+	if _, err := m.Dial(context.Background(), netName, "db:5432"); err == nil {
+		t.Fatal("隧道释放后按网络名拨号应失败")
+	}
+}
+
+func TestTunnelManagerReconnect(t *testing.T) {
+	m, clients, dialers := newTestManager(t)
+	var mu sync.Mutex
+	var states []string
+	m.SetStatusListener(func(s TunnelStatus) {
+		mu.Lock()
+		states = append(states, s.State)
+		mu.Unlock()
+	})
+
+	netName, err := m.Acquire(&connection.SSHConfig{Host: "bastion", Port: 22, User: "ops"})
+	if err != nil {
+		t.Fatalf("Acquire 失败: %v", err)
+	}
+	defer m.Release(netName)
+
+	(*clients)[0].Close()
+	if _, err := dialers[netName](context.Background(), "db:3306"); err == nil {
+		t.Fatal("客户端断开时拨号应失败")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		statuses := m.Statuses()
+		if len(statuses) == 1 && statuses[0].State == TunnelConnected && statuses[0].Reconnects == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("未在期限内重连: %+v", statuses)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	conn, err := dialers[netName](context.Background(), "db:3306")
+	if err != nil {
+		t.Fatalf("重连后拨号失败: %v", err)
+	}
+	_ = conn.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	sawReconnecting := false
+	for _, s := range states {
+		if s == TunnelReconnecting {
+			sawReconnecting = true
+		}
+	}
+	if !sawReconnecting || states[len(states)-1] != TunnelConnected {
+		t.Fatalf("状态事件不符: %v", states)
+	}
+}
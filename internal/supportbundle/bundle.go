@@ -0,0 +1,241 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLogLimit 诊断包中包含的最近日志条数。
+	DefaultLogLimit = 1000
+	// DefaultErrorLimit 诊断包中包含的最近错误条数。
+	DefaultErrorLimit = 200
+)
+
+// hashedLogKeys 是日志中可能带有 SQL 文本、连接地址、库名或用户名的属性，写入诊断包时以哈希代替原值。
+var hashedLogKeys = []string{"summary", "snippet", "host"}
+
+// Item 是诊断包中的一个文件，Content 即写入 zip 的完整内容。
+type Item struct {
+	Name        string `json:"name"`        // zip 内文件名
+	Description string `json:"description"` // 内容说明
+	Content     string `json:"content"`     // 文件内容（供用户确认）
+	Size        int    `json:"size"`        // 内容字节数
+}
+
+// Bundle 是待用户确认的诊断包内容清单。
+type Bundle struct {
+	ID        string    `json:"id"`        // 诊断包 ID，确认生成时使用
+	CreatedAt time.Time `json:"createdAt"` // 收集时间
+	Items     []*Item   `json:"items"`     // 包含的文件
+}
+
+// Options 是收集诊断信息的参数。
+type Options struct {
+	AppName     string                         // 应用名称
+	Connections []*connection.ConnectionConfig // 前端保存的连接配置，写入前会匿名化
+	LogLimit    int                            // 最近日志条数，<=0 时使用默认值
+	ErrorLimit  int                            // 最近错误条数，<=0 时使用默认值
+	Runtime     *RuntimeStats                  // 运行时状态，为空时只采集进程状态
+}
+
+// SystemInfo 是应用与系统环境信息。
+type SystemInfo struct {
+	App       string `json:"app"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	NumCPU    int    `json:"numCpu"`
+	Hostname  string `json:"hostnameHash"` // 主机名哈希
+	Generated string `json:"generatedAt"`
+}
+
+// AnonymizedConnection 是去除凭据并哈希主机后的连接配置。
+type AnonymizedConnection struct {
+	Type        string `json:"type"`
+	HostHash    string `json:"hostHash"`
+	Port        int    `json:"port"`
+	HasUser     bool   `json:"hasUser"`
+	HasPassword bool   `json:"hasPassword"`
+	HasDatabase bool   `json:"hasDatabase"`
+	UseSSH      bool   `json:"useSSH"`
+	SSHHostHash string `json:"sshHostHash,omitempty"`
+	SSHAuth     string `json:"sshAuth,omitempty"` // password / key / none
+	Driver      string `json:"driver,omitempty"`
+	HasDSN      bool   `json:"hasDsn"`
+	Timeout     int    `json:"timeout,omitempty"`
+}
+
+// Collect 收集诊断信息并生成内容清单，调用方应先展示给用户确认后再写入文件。
+func Collect(opts Options) (*Bundle, error) {
+	if opts.LogLimit <= 0 {
+		opts.LogLimit = DefaultLogLimit
+	}
+	if opts.ErrorLimit <= 0 {
+		opts.ErrorLimit = DefaultErrorLimit
+	}
+
+	now := time.Now()
+	bundle := &Bundle{ID: uuid.New().String(), CreatedAt: now}
+	add := func(name, description string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化 %s 失败：%w", name, err)
+		}
+		bundle.Items = append(bundle.Items, &Item{Name: name, Description: description, Content: string(data), Size: len(data)})
+		return nil
+	}
+
+	if err := add("system.json", "应用版本与操作系统信息（主机名已哈希）", collectSystemInfo(opts.AppName, now)); err != nil {
+		return nil, err
+	}
+	connections := make([]*AnonymizedConnection, 0, len(opts.Connections))
+	for _, c := range opts.Connections {
+		if c != nil {
+			connections = append(connections, AnonymizeConnection(c))
+		}
+	}
+	if err := add("connections.json", "连接配置（主机已哈希，不含用户名、密码、私钥与 DSN）", connections); err != nil {
+		return nil, err
+	}
+	stats := opts.Runtime
+	if stats == nil {
+		stats = (*Probes)(nil).Collect()
+	}
+	if err := add("runtime.json", "运行时状态（协程与内存、数据库连接池统计、活动会话数）", stats); err != nil {
+		return nil, err
+	}
+	if err := add("logs.json", fmt.Sprintf("最近 %d 条应用日志（敏感字段已脱敏，SQL 片段与连接摘要已哈希）", opts.LogLimit), hashLogAttrs(logger.Recent(slog.LevelDebug, opts.LogLimit))); err != nil {
+		return nil, err
+	}
+	if err := add("errors.json", fmt.Sprintf("最近 %d 条错误日志（SQL 片段与连接摘要已哈希）", opts.ErrorLimit), hashLogAttrs(logger.Recent(slog.LevelError, opts.ErrorLimit))); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// WriteZip 将清单中的文件原样写入 zip。
+func WriteZip(w io.Writer, bundle *Bundle) error {
+	zw := zip.NewWriter(w)
+	for _, item := range bundle.Items {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: item.Name, Method: zip.Deflate, Modified: bundle.CreatedAt})
+		if err != nil {
+			return fmt.Errorf("写入 %s 失败：%w", item.Name, err)
+		}
+		if _, err := io.WriteString(f, item.Content); err != nil {
+			return fmt.Errorf("写入 %s 失败：%w", item.Name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// AnonymizeConnection 去除连接配置中的凭据，主机名以哈希代替。
+func AnonymizeConnection(c *connection.ConnectionConfig) *AnonymizedConnection {
+	out := &AnonymizedConnection{
+		Type:        string(c.Type),
+		HostHash:    hashValue(c.Host),
+		Port:        c.Port,
+		HasUser:     c.User != "",
+		HasPassword: c.Password != "",
+		HasDatabase: c.Database != "",
+		UseSSH:      c.UseSSH,
+		Driver:      c.Driver,
+		HasDSN:      strings.TrimSpace(c.DSN) != "",
+		Timeout:     c.Timeout,
+	}
+	if c.UseSSH && c.SSH != nil {
+		out.SSHHostHash = hashValue(c.SSH.Host)
+		switch {
+		case c.SSH.KeyPath != "":
+			out.SSHAuth = "key"
+		case c.SSH.Password != "":
+			out.SSHAuth = "password"
+		default:
+			out.SSHAuth = "none"
+		}
+	}
+	return out
+}
+
+// hashLogAttrs 返回日志副本，hashedLogKeys 中的属性（含分组下的同名属性）替换为原值的短哈希，
+// 相同语句或连接仍可在日志间对应，但不会泄露原文。
+func hashLogAttrs(records []logger.Record) []logger.Record {
+	out := make([]logger.Record, len(records))
+	for i, rec := range records {
+		out[i] = rec
+		if len(rec.Attrs) == 0 {
+			continue
+		}
+		attrs := make(map[string]string, len(rec.Attrs))
+		for k, v := range rec.Attrs {
+			name := k[strings.LastIndex(k, ".")+1:]
+			for _, key := range hashedLogKeys {
+				if name == key && v != "" {
+					v = "sha256:" + hashValue(v)
+					break
+				}
+			}
+			attrs[k] = v
+		}
+		out[i].Attrs = attrs
+	}
+	return out
+}
+
+// hashValue 返回值的短哈希，用于在不泄露原值的前提下区分不同主机。
+func hashValue(v string) string {
+	if v == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(v))))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// collectSystemInfo 收集应用与系统信息。
+func collectSystemInfo(appName string, now time.Time) *SystemInfo {
+	info := &SystemInfo{
+		App:       appName,
+		Version:   "unknown",
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Generated: now.Format(time.RFC3339),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" {
+		info.Version = bi.Main.Version
+	}
+	if host, err := os.Hostname(); err == nil {
+		info.Hostname = hashValue(host)
+	}
+	return info
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/logger"
+)
+
+func TestCollectAnonymizesConnections(t *testing.T) {
+	logger.Error("测试错误", "password", "hunter2", "table", "users",
+		"summary", "类型=mysql 地址=db.internal.example.com:3306 用户=admin", "snippet", "SELECT card_no FROM payments")
+
+	bundle, err := Collect(Options{AppName: "Boxify", Connections: []*connection.ConnectionConfig{{
+		Type:     connection.ConnectionTypeMySQL,
+		Host:     "db.internal.example.com",
+		Port:     3306,
+		User:     "admin",
+		Password: "s3cret",
+		DSN:      "admin:s3cret@tcp(db.internal.example.com)/app",
+		UseSSH:   true,
+		SSH:      &connection.SSHConfig{Host: "bastion.example.com", Password: "sshpass"},
+	}}})
+	if err != nil {
+		t.Fatalf("收集失败: %v", err)
+	}
+
+	names := make([]string, 0, len(bundle.Items))
+	for _, item := range bundle.Items {
+		names = append(names, item.Name)
+		for _, secret := range []string{"s3cret", "sshpass", "hunter2", "admin", "db.internal.example.com", "bastion.example.com", "card_no"} {
+			if strings.Contains(item.Content, secret) {
+				t.Errorf("%s 泄露了敏感信息 %q", item.Name, secret)
+			}
+		}
+	}
+	if strings.Join(names, ",") != "system.json,connections.json,runtime.json,logs.json,errors.json" {
+		t.Errorf("清单文件错误: %v", names)
+	}
+	if !strings.Contains(bundle.Items[4].Content, "测试错误") {
+		t.Error("错误日志应包含最近的错误")
+	}
+	if want := `"snippet": "sha256:` + hashValue("SELECT card_no FROM payments"); !strings.Contains(bundle.Items[4].Content, want) {
+		t.Errorf("SQL 片段应以哈希代替: %s", bundle.Items[4].Content)
+	}
+}
+
+func TestCollectIncludesRuntimeProbes(t *testing.T) {
+	probes := NewProbes()
+	probes.Register(func(stats *RuntimeStats) {
+		stats.Sessions["terminal"] = 2
+		stats.Pools = append(stats.Pools, db.PoolStats{Key: "abc", Pooled: true, Open: 3, InUse: 1})
+	})
+
+	bundle, err := Collect(Options{AppName: "Boxify", Runtime: probes.Collect()})
+	if err != nil {
+		t.Fatalf("收集失败: %v", err)
+	}
+	var stats RuntimeStats
+	if err := json.Unmarshal([]byte(bundle.Items[2].Content), &stats); err != nil {
+		t.Fatalf("解析 runtime.json 失败: %v", err)
+	}
+	if stats.Goroutines == 0 || stats.Sessions["terminal"] != 2 || len(stats.Pools) != 1 || stats.Pools[0].InUse != 1 {
+		t.Errorf("运行时状态错误: %+v", stats)
+	}
+}
+
+func TestWriteZipMatchesPreview(t *testing.T) {
+	bundle := &Bundle{Items: []*Item{{Name: "a.json", Content: `{"a":1}`}, {Name: "b.json", Content: "[]"}}}
+	var buf bytes.Buffer
+	if err := WriteZip(&buf, bundle); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || len(zr.File) != 2 {
+		t.Fatalf("读取 zip 失败: %v", err)
+	}
+	for i, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if f.Name != bundle.Items[i].Name || string(data) != bundle.Items[i].Content {
+			t.Errorf("zip 内容与预览不一致: %s %s", f.Name, data)
+		}
+	}
+}
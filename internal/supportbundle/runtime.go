@@ -0,0 +1,78 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/chenyang-zz/boxify/internal/db"
+)
+
+// RuntimeStats 是收集诊断包时的运行时状态。
+type RuntimeStats struct {
+	Goroutines     int            `json:"goroutines"`
+	HeapAllocBytes uint64         `json:"heapAllocBytes"`
+	SysBytes       uint64         `json:"sysBytes"`
+	Pools          []db.PoolStats `json:"pools"`    // 数据库连接池统计
+	Sessions       map[string]int `json:"sessions"` // 各类活动会话数，如终端会话、游标
+}
+
+// Probe 向运行时状态中写入一部分数据，由持有相应资源的服务注册。
+type Probe func(stats *RuntimeStats)
+
+// Probes 管理已注册的运行时状态采集函数。
+type Probes struct {
+	mu     sync.Mutex
+	probes []Probe
+}
+
+// NewProbes 创建运行时状态采集注册表
+func NewProbes() *Probes {
+	return &Probes{}
+}
+
+// Register 注册采集函数
+func (p *Probes) Register(probe Probe) {
+	if p == nil || probe == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probes = append(p.probes, probe)
+}
+
+// Collect 采集进程状态并依次调用已注册的采集函数。
+func (p *Probes) Collect() *RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := &RuntimeStats{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		SysBytes:       mem.Sys,
+		Pools:          []db.PoolStats{},
+		Sessions:       make(map[string]int),
+	}
+	if p == nil {
+		return stats
+	}
+	p.mu.Lock()
+	probes := append([]Probe(nil), p.probes...)
+	p.mu.Unlock()
+	for _, probe := range probes {
+		probe(stats)
+	}
+	return stats
+}
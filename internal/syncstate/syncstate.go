@@ -0,0 +1,198 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syncstate 保存多窗口共享状态的权威值：每个键带单调递增的版本号与更新时间，
+// 写入时以窗口所见版本检测冲突，冲突按键前缀登记的合并函数合并，未登记时后写者覆盖。
+// 晚打开的窗口据此获取当前值，无需等待下一次广播。
+package syncstate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxKeys 最多保存的键数量。
+const MaxKeys = 4096
+
+// ErrTooManyKeys 键数量达到上限。
+var ErrTooManyKeys = fmt.Errorf("共享状态键数量超过 %d 上限", MaxKeys)
+
+// 冲突处理方式。
+const (
+	ResolutionNone           = ""                 // 无冲突
+	ResolutionLastWriterWins = "last-writer-wins" // 基于旧版本写入，直接覆盖当前值
+	ResolutionMerged         = "merged"           // 基于旧版本写入，由合并函数合并
+)
+
+// Entry 是一个键的当前状态。
+type Entry struct {
+	Key       string                 `json:"key"`
+	Value     map[string]interface{} `json:"value"`
+	Version   uint64                 `json:"version"`   // 每次写入递增，从 1 开始
+	UpdatedAt int64                  `json:"updatedAt"` // 最近写入时间（Unix 毫秒）
+	Source    string                 `json:"source"`    // 最近写入的窗口
+	Deleted   bool                   `json:"deleted,omitempty"`
+}
+
+// Update 是一次写入的结果。
+type Update struct {
+	Entry
+	Conflict   bool   `json:"conflict"`             // 写入基于的版本落后于当前版本
+	Resolution string `json:"resolution,omitempty"` // 冲突处理方式
+}
+
+// MergeFunc 合并冲突写入：current 为当前值，incoming 为基于旧版本的写入值，返回合并后的值。
+type MergeFunc func(current, incoming map[string]interface{}) (map[string]interface{}, error)
+
+// ShallowMerge 按顶层字段合并：incoming 中的字段覆盖当前值，其余字段保留。
+func ShallowMerge(current, incoming map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(current)+len(incoming))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range incoming {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// Store 保存全部键的状态，可并发使用。
+type Store struct {
+	mu      sync.RWMutex
+	entries map[string]*Entry
+	merges  map[string]MergeFunc // 按键前缀登记
+	now     func() time.Time
+}
+
+// NewStore 创建共享状态存储。
+func NewStore() *Store {
+	return &Store{
+		entries: make(map[string]*Entry),
+		merges:  make(map[string]MergeFunc),
+		now:     time.Now,
+	}
+}
+
+// RegisterMerge 为键前缀登记冲突合并函数，多个前缀匹配时使用最长的前缀；fn 为 nil 时取消登记。
+func (s *Store) RegisterMerge(prefix string, fn MergeFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fn == nil {
+		delete(s.merges, prefix)
+		return
+	}
+	s.merges[prefix] = fn
+}
+
+// Get 返回键的当前状态（含已删除的键），不存在时第二个返回值为 false。
+func (s *Store) Get(key string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// List 返回前缀匹配且未删除的全部状态，按键排序；prefix 为空时返回全部。
+func (s *Store) List(prefix string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Entry, 0)
+	for key, e := range s.entries {
+		if !e.Deleted && strings.HasPrefix(key, prefix) {
+			list = append(list, *e)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// Set 写入键的值。baseVersion 为写入方所见的版本（0 表示不检查）；
+// 落后于当前版本时视为冲突，按登记的合并函数合并，未登记时后写者覆盖。
+func (s *Store) Set(key string, value map[string]interface{}, baseVersion uint64, source string) (Update, error) {
+	if strings.TrimSpace(key) == "" {
+		return Update{}, errors.New("key 不能为空")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.entries[key]
+	if !exists && len(s.entries) >= MaxKeys {
+		return Update{}, ErrTooManyKeys
+	}
+
+	update := Update{}
+	if exists && baseVersion > 0 && baseVersion < current.Version {
+		update.Conflict = true
+		update.Resolution = ResolutionLastWriterWins
+		if merge := s.mergeForLocked(key); merge != nil && !current.Deleted {
+			merged, err := merge(current.Value, value)
+			if err != nil {
+				return Update{}, fmt.Errorf("合并共享状态 %s 失败：%w", key, err)
+			}
+			value = merged
+			update.Resolution = ResolutionMerged
+		}
+	}
+
+	update.Entry = s.writeLocked(key, current, value, source, false)
+	return update, nil
+}
+
+// Delete 删除键，保留带新版本号的删除标记，使基于旧版本的写入可以被识别为冲突。
+func (s *Store) Delete(key, source string) (Update, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.entries[key]
+	if !ok || current.Deleted {
+		return Update{}, false
+	}
+	return Update{Entry: s.writeLocked(key, current, nil, source, true)}, true
+}
+
+// writeLocked 写入新版本并返回副本；调用方需持有锁。
+func (s *Store) writeLocked(key string, current *Entry, value map[string]interface{}, source string, deleted bool) Entry {
+	var version uint64 = 1
+	updatedAt := s.now().UnixMilli()
+	if current != nil {
+		version = current.Version + 1
+		// 时钟回拨时保证更新时间单调
+		if updatedAt < current.UpdatedAt {
+			updatedAt = current.UpdatedAt
+		}
+	}
+	e := &Entry{Key: key, Value: value, Version: version, UpdatedAt: updatedAt, Source: source, Deleted: deleted}
+	s.entries[key] = e
+	return *e
+}
+
+// mergeForLocked 返回最长前缀匹配的合并函数；调用方需持有锁。
+func (s *Store) mergeForLocked(key string) MergeFunc {
+	var (
+		best   MergeFunc
+		bestLn = -1
+	)
+	for prefix, fn := range s.merges {
+		if strings.HasPrefix(key, prefix) && len(prefix) > bestLn {
+			best, bestLn = fn, len(prefix)
+		}
+	}
+	return best
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncstate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStoreVersionsAndLastWriterWins(t *testing.T) {
+	s := NewStore()
+	first, err := s.Set("connection:c1", map[string]interface{}{"name": "a"}, 0, "main")
+	if err != nil || first.Version != 1 || first.Conflict {
+		t.Fatalf("Set() = %+v, %v", first, err)
+	}
+	second, _ := s.Set("connection:c1", map[string]interface{}{"name": "b"}, 1, "main")
+	if second.Version != 2 || second.Conflict {
+		t.Fatalf("基于当前版本的写入不应冲突: %+v", second)
+	}
+
+	stale, _ := s.Set("connection:c1", map[string]interface{}{"name": "c"}, 1, "editor")
+	if !stale.Conflict || stale.Resolution != ResolutionLastWriterWins || stale.Version != 3 {
+		t.Fatalf("基于旧版本的写入应按后写者覆盖: %+v", stale)
+	}
+	got, ok := s.Get("connection:c1")
+	if !ok || got.Value["name"] != "c" || got.Source != "editor" {
+		t.Fatalf("Get() = %+v", got)
+	}
+}
+
+func TestStoreMergeByLongestPrefix(t *testing.T) {
+	s := NewStore()
+	s.RegisterMerge("config", ShallowMerge)
+	s.RegisterMerge("config:locked", func(current, incoming map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("locked")
+	})
+
+	s.Set("config:ui", map[string]interface{}{"sidebar": 200, "theme": "dark"}, 0, "main")
+	s.Set("config:ui", map[string]interface{}{"sidebar": 240, "theme": "dark"}, 1, "main")
+	merged, err := s.Set("config:ui", map[string]interface{}{"theme": "light"}, 1, "editor")
+	if err != nil || merged.Resolution != ResolutionMerged {
+		t.Fatalf("Set() = %+v, %v", merged, err)
+	}
+	if merged.Value["sidebar"] != 240 || merged.Value["theme"] != "light" {
+		t.Fatalf("应保留其他窗口的修改: %+v", merged.Value)
+	}
+
+	s.Set("config:locked", map[string]interface{}{"v": 1}, 0, "main")
+	s.Set("config:locked", map[string]interface{}{"v": 2}, 1, "main")
+	if _, err := s.Set("config:locked", map[string]interface{}{"v": 3}, 1, "editor"); err == nil {
+		t.Fatal("合并失败时应返回错误")
+	}
+	if got, _ := s.Get("config:locked"); got.Version != 2 {
+		t.Fatalf("合并失败时不应写入: %+v", got)
+	}
+}
+
+func TestStoreDeleteKeepsTombstone(t *testing.T) {
+	s := NewStore()
+	clock := time.UnixMilli(1000)
+	s.now = func() time.Time { return clock }
+	s.Set("connection:c1", map[string]interface{}{"name": "a"}, 0, "main")
+	s.Set("connection:c2", map[string]interface{}{"name": "b"}, 0, "main")
+
+	clock = time.UnixMilli(500) // 时钟回拨
+	deleted, ok := s.Delete("connection:c1", "main")
+	if !ok || !deleted.Deleted || deleted.Version != 2 || deleted.UpdatedAt != 1000 {
+		t.Fatalf("Delete() = %+v, %v", deleted, ok)
+	}
+	if _, ok := s.Delete("connection:c1", "main"); ok {
+		t.Fatal("重复删除应返回 false")
+	}
+	if list := s.List("connection:"); len(list) != 1 || list[0].Key != "connection:c2" {
+		t.Fatalf("List() 不应包含已删除的键: %+v", list)
+	}
+
+	revived, _ := s.Set("connection:c1", map[string]interface{}{"name": "z"}, 1, "editor")
+	if !revived.Conflict || revived.Version != 3 || revived.Deleted {
+		t.Fatalf("基于删除前版本的写入应报告冲突: %+v", revived)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+)
+
+// 命令块输出的保留上限
+const (
+	DefaultBlockOutputLimit = 256 << 10 // 单个命令块保留的输出字节数，超出时保留末尾
+	DefaultBlockTotalLimit  = 8 << 20   // 单个会话全部命令块的输出字节数，超出时淘汰最早的命令块
+)
+
+// blockOutput 单个命令块的输出记录
+type blockOutput struct {
+	command   string
+	data      []byte
+	truncated bool
+	exitCode  *int
+	startedAt time.Time
+	endedAt   time.Time
+}
+
+// BlockOutputs 按 block ID 保存命令块的原始输出（含 ANSI 序列），供界面事后重新渲染、复制或保存
+type BlockOutputs struct {
+	mu         sync.RWMutex
+	blockLimit int
+	totalLimit int
+	used       int
+	blocks     map[string]*blockOutput
+	order      []string // 按开始顺序排列的 block ID，用于淘汰
+}
+
+// NewBlockOutputs 创建命令块输出存储，上限 <= 0 时使用默认值
+func NewBlockOutputs(blockLimit, totalLimit int) *BlockOutputs {
+	if blockLimit <= 0 {
+		blockLimit = DefaultBlockOutputLimit
+	}
+	if totalLimit <= 0 {
+		totalLimit = DefaultBlockTotalLimit
+	}
+	return &BlockOutputs{
+		blockLimit: blockLimit,
+		totalLimit: totalLimit,
+		blocks:     make(map[string]*blockOutput),
+	}
+}
+
+// Begin 登记一个新命令块；同一 block ID 重复登记时清空旧输出
+func (b *BlockOutputs) Begin(blockID, command string) {
+	if blockID == "" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if old, ok := b.blocks[blockID]; ok {
+		b.used -= len(old.data)
+		b.removeOrderLocked(blockID)
+	}
+	b.blocks[blockID] = &blockOutput{command: command, startedAt: time.Now()}
+	b.order = append(b.order, blockID)
+}
+
+// Append 追加命令块输出；未登记的 block ID 自动登记
+func (b *BlockOutputs) Append(blockID string, p []byte) {
+	if blockID == "" || len(p) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	block, ok := b.blocks[blockID]
+	if !ok {
+		block = &blockOutput{startedAt: time.Now()}
+		b.blocks[blockID] = block
+		b.order = append(b.order, blockID)
+	}
+
+	before := len(block.data)
+	block.data = append(block.data, p...)
+	if over := len(block.data) - b.blockLimit; over > 0 {
+		block.data = append(block.data[:0:0], block.data[over:]...)
+		block.truncated = true
+	}
+	b.used += len(block.data) - before
+	b.evictLocked(blockID)
+}
+
+// End 记录命令块结束与退出码
+func (b *BlockOutputs) End(blockID string, exitCode int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if block, ok := b.blocks[blockID]; ok {
+		block.exitCode = &exitCode
+		block.endedAt = time.Now()
+	}
+}
+
+// Get 返回命令块输出，Output 为 base64 编码的原始输出，Text 为去除控制序列后的纯文本
+func (b *BlockOutputs) Get(blockID string) (*types.TerminalBlockOutput, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	block, ok := b.blocks[blockID]
+	if !ok {
+		return nil, false
+	}
+	var plain ansiStripper
+	out := &types.TerminalBlockOutput{
+		BlockID:   blockID,
+		Command:   block.command,
+		Output:    base64.StdEncoding.EncodeToString(block.data),
+		Text:      string(plain.appendPlain(nil, block.data)),
+		Truncated: block.truncated,
+		StartedAt: block.startedAt,
+	}
+	if block.exitCode != nil {
+		code := *block.exitCode
+		out.ExitCode = &code
+		out.EndedAt = &block.endedAt
+	}
+	return out, true
+}
+
+// evictLocked 总量超限时从最早的命令块开始淘汰，keep 为正在写入的命令块，不会被淘汰
+func (b *BlockOutputs) evictLocked(keep string) {
+	for b.used > b.totalLimit && len(b.order) > 0 {
+		id := b.order[0]
+		if id == keep {
+			if len(b.order) == 1 {
+				return
+			}
+			b.order = append(b.order[1:], id)
+			continue
+		}
+		b.used -= len(b.blocks[id].data)
+		delete(b.blocks, id)
+		b.order = b.order[1:]
+	}
+}
+
+// removeOrderLocked 从淘汰顺序中移除 block ID
+func (b *BlockOutputs) removeOrderLocked(blockID string) {
+	for i, id := range b.order {
+		if id == blockID {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestBlockOutputsCaptureAndEnd(t *testing.T) {
+	b := NewBlockOutputs(0, 0)
+	b.Begin("blk-1", "ls -la")
+	b.Append("blk-1", []byte("\x1b[32mok\x1b[0m\r\n"))
+	b.Append("blk-1", []byte("done\n"))
+
+	out, ok := b.Get("blk-1")
+	if !ok || out.Command != "ls -la" || out.Text != "ok\ndone\n" || out.ExitCode != nil || out.EndedAt != nil {
+		t.Fatalf("Get() = %+v, %v", out, ok)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(out.Output)
+	if string(raw) != "\x1b[32mok\x1b[0m\r\ndone\n" {
+		t.Fatalf("原始输出应保留控制序列: %q", raw)
+	}
+
+	b.End("blk-1", 2)
+	if out, _ := b.Get("blk-1"); out.ExitCode == nil || *out.ExitCode != 2 || out.EndedAt == nil {
+		t.Fatalf("结束后应记录退出码: %+v", out)
+	}
+	if _, ok := b.Get("missing"); ok {
+		t.Fatal("未知 block 应返回 false")
+	}
+}
+
+func TestBlockOutputsLimits(t *testing.T) {
+	b := NewBlockOutputs(4, 10)
+	b.Append("a", []byte("123456"))
+	if out, _ := b.Get("a"); out.Text != "3456" || !out.Truncated {
+		t.Fatalf("单块超限应保留末尾: %+v", out)
+	}
+
+	b.Append("b", []byte("abcd"))
+	b.Append("c", []byte("wxyz"))
+	if _, ok := b.Get("a"); ok {
+		t.Fatal("总量超限应淘汰最早的块")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := b.Get(id); !ok {
+			t.Fatalf("块 %s 不应被淘汰", id)
+		}
+	}
+
+	// 重新登记同一 block 时清空旧输出
+	b.Begin("b", "again")
+	b.Append("b", []byte("x"))
+	if out, _ := b.Get("b"); out.Text != "x" || out.Command != "again" {
+		t.Fatalf("重新登记后应只有新输出: %+v", out)
+	}
+	if b.used != 5 {
+		t.Fatalf("used = %d, want 5", b.used)
+	}
+}
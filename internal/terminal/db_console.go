@@ -0,0 +1,184 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/db"
+	"github.com/chenyang-zz/boxify/internal/dbconsole"
+)
+
+// 数据库控制台使用的客户端
+const (
+	DBConsoleClientMySQL    = "mysql"
+	DBConsoleClientMariaDB  = "mariadb"
+	DBConsoleClientPsql     = "psql"
+	DBConsoleClientInternal = "internal" // 应用内置控制台（db-console 子命令）
+)
+
+// DBConsoleLaunch 启动数据库控制台所需的命令与凭据文件
+// 凭据写入 SecretPath 指向的仅当前用户可读的文件（0600），命令只引用文件路径；
+// 凭据不出现在命令行、命令块与 shell 环境变量中，终端里后续执行的命令不会继承
+type DBConsoleLaunch struct {
+	Client     string
+	Command    string
+	SecretPath string // 凭据文件路径，为空表示不需要凭据文件
+	Secret     []byte // 凭据文件内容，由调用方在执行 Command 前写入 SecretPath
+}
+
+// BuildDBConsoleLaunch 根据连接配置选择控制台客户端并生成启动命令，secretDir 为存放凭据文件的私有目录
+// MySQL/MariaDB 与 PostgreSQL 系数据库在 lookPath 能找到 mysql/mariadb/psql 时使用原生客户端；
+// 经 SSH 隧道的连接、其他数据库类型或本机没有客户端时，使用 executable 的 db-console 子命令启动内置控制台。
+func BuildDBConsoleLaunch(config *connection.ConnectionConfig, shellType ShellType, lookPath func(string) (string, error), executable, secretDir string) (*DBConsoleLaunch, error) {
+	if config == nil {
+		return nil, fmt.Errorf("连接配置不能为空")
+	}
+
+	if !config.UseSSH {
+		switch {
+		case config.Type == "" || config.Type == connection.ConnectionTypeMySQL || config.Type == connection.ConnectionTypeMariaDB:
+			clients := []string{DBConsoleClientMySQL}
+			if config.Type == connection.ConnectionTypeMariaDB {
+				clients = []string{DBConsoleClientMariaDB, DBConsoleClientMySQL}
+			}
+			for _, client := range clients {
+				if path, err := lookPath(client); err == nil {
+					return mysqlConsoleLaunch(config, shellType, client, path, secretDir), nil
+				}
+			}
+		case db.IsPostgresDialect(config.Type):
+			if path, err := lookPath(DBConsoleClientPsql); err == nil {
+				return psqlConsoleLaunch(config, shellType, path, secretDir), nil
+			}
+		}
+	}
+
+	if executable == "" {
+		return nil, fmt.Errorf("未找到可用的数据库客户端")
+	}
+	encoded, err := dbconsole.EncodeConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("编码连接配置失败: %w", err)
+	}
+	secretPath := filepath.Join(secretDir, "console.json")
+	return &DBConsoleLaunch{
+		Client:     DBConsoleClientInternal,
+		Command:    shellCommand(shellType, executable, dbconsole.Subcommand, dbconsole.ConfigFlag, secretPath),
+		SecretPath: secretPath,
+		Secret:     encoded,
+	}, nil
+}
+
+// NewDBConsoleSecretDir 创建存放控制台凭据文件的私有临时目录（仅当前用户可访问），会话关闭时删除
+func NewDBConsoleSecretDir() (string, error) {
+	return os.MkdirTemp("", "boxify-dbconsole-*")
+}
+
+// WriteSecret 将凭据以 0600 权限写入 SecretPath，不需要凭据文件时直接返回
+func (l *DBConsoleLaunch) WriteSecret() error {
+	if l.SecretPath == "" {
+		return nil
+	}
+	if err := os.WriteFile(l.SecretPath, l.Secret, 0o600); err != nil {
+		return fmt.Errorf("写入控制台凭据文件失败: %w", err)
+	}
+	return nil
+}
+
+// mysqlConsoleLaunch 生成 mysql/mariadb 客户端命令，密码写入 --defaults-extra-file 指定的选项文件
+func mysqlConsoleLaunch(config *connection.ConnectionConfig, shellType ShellType, client, path, secretDir string) *DBConsoleLaunch {
+	launch := &DBConsoleLaunch{Client: client}
+	var args []string
+	if config.Password != "" {
+		// --defaults-extra-file 必须是第一个参数
+		launch.SecretPath = filepath.Join(secretDir, "client.cnf")
+		launch.Secret = []byte("[client]\npassword=\"" + mysqlOptionEscaper.Replace(config.Password) + "\"\n")
+		args = append(args, "--defaults-extra-file="+launch.SecretPath)
+	}
+	args = append(args, "-h", config.Host, "-P", strconv.Itoa(config.Port), "-u", config.User)
+	if config.Database != "" {
+		args = append(args, "--database", config.Database)
+	}
+	launch.Command = shellCommand(shellType, path, args...)
+	return launch
+}
+
+// psqlConsoleLaunch 生成 psql 客户端命令，密码写入 passfile 连接参数指定的密码文件
+func psqlConsoleLaunch(config *connection.ConnectionConfig, shellType ShellType, path, secretDir string) *DBConsoleLaunch {
+	launch := &DBConsoleLaunch{Client: DBConsoleClientPsql}
+	args := []string{"-h", config.Host, "-p", strconv.Itoa(config.Port), "-U", config.User}
+	var conninfo []string
+	if config.Database != "" {
+		conninfo = append(conninfo, "dbname="+conninfoValue(config.Database))
+	}
+	if config.Password != "" {
+		launch.SecretPath = filepath.Join(secretDir, "pgpass")
+		launch.Secret = []byte(fmt.Sprintf("%s:%d:*:%s:%s\n", pgpassEscaper.Replace(config.Host), config.Port, pgpassEscaper.Replace(config.User), pgpassEscaper.Replace(config.Password)))
+		conninfo = append(conninfo, "passfile="+conninfoValue(launch.SecretPath))
+	}
+	if len(conninfo) > 0 {
+		args = append(args, "-d", strings.Join(conninfo, " "))
+	}
+	launch.Command = shellCommand(shellType, path, args...)
+	return launch
+}
+
+// mysqlOptionEscaper 转义 MySQL 选项文件中双引号包裹的值
+var mysqlOptionEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// pgpassEscaper 转义 .pgpass 文件字段中的反斜杠与冒号
+var pgpassEscaper = strings.NewReplacer(`\`, `\\`, `:`, `\:`)
+
+// conninfoValue 按 libpq 连接串语法为值加单引号并转义
+func conninfoValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// shellCommand 按 shell 类型引用程序路径与参数并拼接为命令行
+func shellCommand(shellType ShellType, program string, args ...string) string {
+	parts := make([]string, 0, len(args)+2)
+	if shellType == ShellTypePowershell || shellType == ShellTypePwsh {
+		// PowerShell 中引号包裹的路径需要调用运算符才会被当作命令执行
+		parts = append(parts, "&")
+	}
+	parts = append(parts, quoteShellArg(shellType, program))
+	for _, arg := range args {
+		parts = append(parts, quoteShellArg(shellType, arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteShellArg 在参数含空白或特殊字符时按 shell 类型加引号
+func quoteShellArg(shellType ShellType, arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@,+", r))
+	}) < 0 {
+		return arg
+	}
+	switch shellType {
+	case ShellTypeCmd:
+		return `"` + strings.ReplaceAll(arg, `"`, `""`) + `"`
+	case ShellTypePowershell, ShellTypePwsh:
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/dbconsole"
+)
+
+// fakeLookPath 只认识 available 中列出的客户端
+func fakeLookPath(available ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestBuildDBConsoleLaunch(t *testing.T) {
+	mysqlCfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "db.local", Port: 3306, User: "root", Password: `s3"c\ret`, Database: "my app"}
+	pgCfg := &connection.ConnectionConfig{Type: connection.ConnectionTypePostgreSQL, Host: "pg", Port: 5432, User: "postgres", Password: "p:w", Database: "app"}
+	mariaCfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMariaDB, Host: "m", Port: 3306, User: "u"}
+
+	tests := []struct {
+		name       string
+		config     *connection.ConnectionConfig
+		shell      ShellType
+		available  []string
+		wantClient string
+		wantCmd    string
+		wantSecret string
+	}{
+		{"mysql", mysqlCfg, ShellTypeBash, []string{"mysql"}, DBConsoleClientMySQL,
+			"/usr/bin/mysql --defaults-extra-file=/s/client.cnf -h db.local -P 3306 -u root --database 'my app'",
+			"[client]\npassword=\"s3\\\"c\\\\ret\"\n"},
+		{"mysql powershell", mysqlCfg, ShellTypePwsh, []string{"mysql"}, DBConsoleClientMySQL,
+			"& /usr/bin/mysql --defaults-extra-file=/s/client.cnf -h db.local -P 3306 -u root --database 'my app'",
+			"[client]\npassword=\"s3\\\"c\\\\ret\"\n"},
+		{"mariadb prefers mariadb", mariaCfg, ShellTypeZsh, []string{"mysql", "mariadb"}, DBConsoleClientMariaDB,
+			"/usr/bin/mariadb -h m -P 3306 -u u", ""},
+		{"mariadb falls back to mysql", mariaCfg, ShellTypeZsh, []string{"mysql"}, DBConsoleClientMySQL,
+			"/usr/bin/mysql -h m -P 3306 -u u", ""},
+		{"psql", pgCfg, ShellTypeBash, []string{"psql"}, DBConsoleClientPsql,
+			`/usr/bin/psql -h pg -p 5432 -U postgres -d 'dbname='\''app'\'' passfile='\''/s/pgpass'\'''`,
+			"pg:5432:*:postgres:p\\:w\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launch, err := BuildDBConsoleLaunch(tt.config, tt.shell, fakeLookPath(tt.available...), "/opt/Box App/boxify", "/s")
+			if err != nil {
+				t.Fatalf("BuildDBConsoleLaunch() error = %v", err)
+			}
+			if launch.Client != tt.wantClient || launch.Command != tt.wantCmd {
+				t.Fatalf("got %s %q, want %s %q", launch.Client, launch.Command, tt.wantClient, tt.wantCmd)
+			}
+			if string(launch.Secret) != tt.wantSecret {
+				t.Fatalf("Secret = %q, want %q", launch.Secret, tt.wantSecret)
+			}
+			if (launch.SecretPath == "") != (tt.wantSecret == "") {
+				t.Fatalf("SecretPath = %q", launch.SecretPath)
+			}
+			if tt.config.Password != "" && strings.Contains(launch.Command, tt.config.Password) {
+				t.Fatalf("密码不应出现在命令行: %q", launch.Command)
+			}
+		})
+	}
+}
+
+func TestBuildDBConsoleLaunchInternal(t *testing.T) {
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "h", Port: 3306, Password: "pw", UseSSH: true, SSH: &connection.SSHConfig{Host: "jump"}}
+	dir := t.TempDir()
+	launch, err := BuildDBConsoleLaunch(cfg, ShellTypeBash, fakeLookPath("mysql"), "/usr/bin/boxify", dir)
+	if err != nil || launch.Client != DBConsoleClientInternal {
+		t.Fatalf("SSH 连接应使用内置控制台: %+v %v", launch, err)
+	}
+	wantCmd := shellCommand(ShellTypeBash, "/usr/bin/boxify", dbconsole.Subcommand, dbconsole.ConfigFlag, launch.SecretPath)
+	if launch.Command != wantCmd || strings.Contains(launch.Command, "pw") {
+		t.Fatalf("Command = %q, want %q", launch.Command, wantCmd)
+	}
+	if err := launch.WriteSecret(); err != nil {
+		t.Fatalf("WriteSecret() error = %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(launch.SecretPath)
+		if err != nil || info.Mode().Perm() != 0o600 {
+			t.Fatalf("凭据文件权限应为 0600: %v %v", info, err)
+		}
+	}
+	decoded, err := dbconsole.ReadConfigFile(launch.SecretPath)
+	if err != nil || decoded.SSH == nil || decoded.SSH.Host != "jump" || decoded.Password != "pw" {
+		t.Fatalf("凭据文件应携带完整连接配置: %+v %v", decoded, err)
+	}
+
+	if _, err := BuildDBConsoleLaunch(cfg, ShellTypeBash, fakeLookPath(), "", dir); err == nil {
+		t.Fatal("没有客户端也没有可执行文件时应返回错误")
+	}
+}
+
+func TestQuoteShellArg(t *testing.T) {
+	tests := []struct {
+		shell ShellType
+		arg   string
+		want  string
+	}{
+		{ShellTypeBash, "plain-arg_1.0", "plain-arg_1.0"},
+		{ShellTypeBash, "it's", `'it'\''s'`},
+		{ShellTypeBash, "", "''"},
+		{ShellTypePowershell, "it's", "'it''s'"},
+		{ShellTypeCmd, `C:\Program Files\x "y"`, `"C:\Program Files\x ""y"""`},
+	}
+	for _, tt := range tests {
+		if got := quoteShellArg(tt.shell, tt.arg); got != tt.want {
+			t.Errorf("quoteShellArg(%s, %q) = %q, want %q", tt.shell, tt.arg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultCloseGracePeriod 关闭会话时等待 shell 自行退出的默认时长
+const DefaultCloseGracePeriod = 3 * time.Second
+
+// errGracefulUnsupported 当前平台不支持向 shell 发送退出信号
+var errGracefulUnsupported = errors.New("当前平台不支持优雅关闭")
+
+// CloseReport 会话关闭结果
+type CloseReport struct {
+	Graceful         bool          // shell 在宽限期内自行退出
+	Killed           bool          // 宽限期结束后被强制终止
+	OrphanedChildren bool          // shell 退出后会话中仍有存活的子进程（如 nohup/disown 的后台任务）
+	Elapsed          time.Duration // 从发送信号到进程退出的耗时
+}
+
+// Terminate 优雅结束会话进程：先发送 SIGHUP/SIGTERM，让 shell 通知作业并保存历史；
+// grace 内未退出时发送 SIGKILL。grace <= 0 时直接强制终止。
+func (s *Session) Terminate(grace time.Duration) *CloseReport {
+	report := &CloseReport{}
+	if s.Cmd == nil || s.Cmd.Process == nil {
+		report.Graceful = true
+		return report
+	}
+
+	start := time.Now()
+	exited := s.exitedChan()
+	sid := s.Cmd.Process.Pid
+
+	if grace > 0 {
+		select {
+		case <-exited:
+			report.Graceful = true
+		default:
+			if err := hangupProcess(s.Cmd.Process); err != nil && !errors.Is(err, errGracefulUnsupported) {
+				s.logger.Debug("发送退出信号失败", "sessionId", s.ID, "error", err)
+			}
+			timer := time.NewTimer(grace)
+			select {
+			case <-exited:
+				report.Graceful = true
+			case <-timer.C:
+			}
+			timer.Stop()
+		}
+	}
+
+	if !report.Graceful {
+		select {
+		case <-exited:
+			report.Graceful = true
+		default:
+			if err := s.Cmd.Process.Kill(); err != nil {
+				s.logger.Debug("强制终止进程失败", "sessionId", s.ID, "error", err)
+			}
+			<-exited
+			report.Killed = true
+		}
+	}
+
+	report.Elapsed = time.Since(start)
+	report.OrphanedChildren = sessionHasProcesses(sid)
+	return report
+}
+
+// exitedChan 返回进程退出通知通道，首次调用时启动唯一的 Wait 协程
+func (s *Session) exitedChan() <-chan struct{} {
+	s.waitOnce.Do(func() {
+		s.exited = make(chan struct{})
+		go func() {
+			s.waitErr = s.Cmd.Wait()
+			close(s.exited)
+		}()
+	})
+	return s.exited
+}
+
+// Exited 返回进程退出通知通道，没有进程时返回 nil
+func (s *Session) Exited() <-chan struct{} {
+	if s.Cmd == nil || s.Cmd.Process == nil {
+		return nil
+	}
+	return s.exitedChan()
+}
+
+// ExitCode 返回进程退出码；进程未退出或被信号终止时第二个返回值为 false
+func (s *Session) ExitCode() (int, bool) {
+	exited := s.Exited()
+	if exited == nil {
+		return 0, false
+	}
+	select {
+	case <-exited:
+	default:
+		return 0, false
+	}
+	code := s.Cmd.ProcessState.ExitCode()
+	return code, code >= 0
+}
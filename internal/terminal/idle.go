@@ -0,0 +1,55 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import "time"
+
+// DefaultIdleWarnBefore 空闲会话关闭前提前警告的时长
+const DefaultIdleWarnBefore = time.Minute
+
+// IdleAction 空闲检查结果
+type IdleAction int
+
+const (
+	IdleNone   IdleAction = iota // 未达到警告时间，或本轮已警告过
+	IdleWarn                     // 即将因空闲被关闭，需要警告
+	IdleExpire                   // 已达到空闲超时，应关闭会话
+)
+
+// Touch 记录一次输入或输出活动，并重置空闲警告
+func (s *Session) Touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+	s.idleWarned.Store(false)
+}
+
+// LastActivity 返回最近一次输入或输出的时间
+func (s *Session) LastActivity() time.Time {
+	return time.Unix(0, s.lastActivity.Load())
+}
+
+// CheckIdle 按空闲超时判断会话状态；超时前 warnBefore 内返回一次 IdleWarn，timeout <= 0 表示不检查
+func (s *Session) CheckIdle(now time.Time, timeout, warnBefore time.Duration) IdleAction {
+	if timeout <= 0 {
+		return IdleNone
+	}
+	idle := now.Sub(s.LastActivity())
+	if idle >= timeout {
+		return IdleExpire
+	}
+	if warnBefore > 0 && idle >= timeout-warnBefore && s.idleWarned.CompareAndSwap(false, true) {
+		return IdleWarn
+	}
+	return IdleNone
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSession_CheckIdle(t *testing.T) {
+	session := NewSession(context.Background(), "idle", nil, nil, ShellTypeBash, false, testLogger)
+	start := session.LastActivity()
+
+	if got := session.CheckIdle(start.Add(time.Hour), 0, time.Minute); got != IdleNone {
+		t.Errorf("timeout 0 should disable idle check, got %v", got)
+	}
+	if got := session.CheckIdle(start.Add(3*time.Minute), 5*time.Minute, time.Minute); got != IdleNone {
+		t.Errorf("3m idle = %v, want IdleNone", got)
+	}
+	if got := session.CheckIdle(start.Add(4*time.Minute), 5*time.Minute, time.Minute); got != IdleWarn {
+		t.Errorf("4m idle = %v, want IdleWarn", got)
+	}
+	if got := session.CheckIdle(start.Add(4*time.Minute+30*time.Second), 5*time.Minute, time.Minute); got != IdleNone {
+		t.Errorf("warning should only be sent once, got %v", got)
+	}
+	if got := session.CheckIdle(start.Add(5*time.Minute), 5*time.Minute, time.Minute); got != IdleExpire {
+		t.Errorf("5m idle = %v, want IdleExpire", got)
+	}
+
+	session.Touch()
+	now := session.LastActivity()
+	if got := session.CheckIdle(now.Add(4*time.Minute), 5*time.Minute, time.Minute); got != IdleWarn {
+		t.Errorf("Touch should reset warning, got %v", got)
+	}
+}
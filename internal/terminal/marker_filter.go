@@ -29,9 +29,11 @@ import (
 type MarkerFilter struct {
 	mu               sync.Mutex
 	buffer           bytes.Buffer
-	inCommandOutput  bool   // 是否在命令输出区域内
-	inInteractive    bool   // 是否处于 alternate screen 交互模式
-	currentPwd       string // 当前工作路径
+	inCommandOutput  bool             // 是否在命令输出区域内
+	inInteractive    bool             // 是否处于 alternate screen 交互模式
+	currentPwd       string           // 当前工作路径
+	commandStartedAt time.Time        // 最近一次 OSC 133;A 的时间，命令结束后清零
+	now              func() time.Time // 时间来源（测试可替换）
 	startMarkerRegex *regexp.Regexp
 	endMarkerRegex   *regexp.Regexp
 	pwdMarkerRegex   *regexp.Regexp // OSC 1337;Pwd 序列
@@ -67,18 +69,27 @@ func NewMarkerFilter(logger *slog.Logger) *MarkerFilter {
 		createdAt:        time.Now(),
 		fallbackDelay:    3 * time.Second, // 3秒后如果没检测到标记，进入降级模式
 		activeModes:      make(map[string]struct{}),
+		now:              time.Now,
 	}
 }
 
 // ProcessResult 处理结果
 type ProcessResult struct {
-	Output                 []byte // 过滤后的输出
-	CommandEnded           bool   // 命令是否结束
-	ExitCode               int    // 命令退出码（仅在 CommandEnded 为 true 时有效）
-	PwdChanged             bool   // 工作路径是否变化
-	Pwd                    string // 新的工作路径（仅在 PwdChanged 为 true 时有效）
-	InteractionModeChanged bool   // 交互模式是否切换
-	InInteractive          bool   // 当前是否处于交互模式
+	Output                 []byte        // 过滤后的输出
+	CommandEnded           bool          // 命令是否结束
+	ExitCode               int           // 命令退出码（仅在 CommandEnded 为 true 时有效）
+	Timing                 CommandTiming // 命令耗时与工作路径（仅在 CommandEnded 为 true 时有效）
+	PwdChanged             bool          // 工作路径是否变化
+	Pwd                    string        // 新的工作路径（仅在 PwdChanged 为 true 时有效）
+	InteractionModeChanged bool          // 交互模式是否切换
+	InInteractive          bool          // 当前是否处于交互模式
+}
+
+// CommandTiming 命令执行耗时，由 OSC 133;A 与 OSC 133;D 的到达时间计算
+type CommandTiming struct {
+	StartedAt time.Time     // 命令开始时间，未检测到开始标记时为零值
+	Duration  time.Duration // 命令耗时，未检测到开始标记时为 0
+	Cwd       string        // 命令结束时的工作路径（来自 OSC 1337;Pwd），未知时为空
 }
 
 // Process 处理输出数据
@@ -139,6 +150,7 @@ func (f *MarkerFilter) Process(data []byte) ProcessResult {
 	var result bytes.Buffer
 	var commandEnded bool
 	var exitCode int
+	var timing CommandTiming
 	var pwdChanged bool
 	var pwd string
 
@@ -195,6 +207,11 @@ func (f *MarkerFilter) Process(data []byte) ProcessResult {
 				exitCodeStr := content[endMatch[2]:endMatch[3]]
 				exitCode = parseInt(exitCodeStr)
 			}
+			timing = CommandTiming{StartedAt: f.commandStartedAt, Cwd: f.currentPwd}
+			if !f.commandStartedAt.IsZero() {
+				timing.Duration = f.now().Sub(f.commandStartedAt)
+			}
+			f.commandStartedAt = time.Time{}
 			f.inCommandOutput = false
 			commandEnded = true
 		case 3:
@@ -215,6 +232,7 @@ func (f *MarkerFilter) Process(data []byte) ProcessResult {
 		case 1:
 			// 开始标记
 			f.inCommandOutput = true
+			f.commandStartedAt = f.now()
 		}
 
 		// 移除已处理的内容
@@ -256,6 +274,7 @@ func (f *MarkerFilter) Process(data []byte) ProcessResult {
 		Output:                 result.Bytes(),
 		CommandEnded:           commandEnded,
 		ExitCode:               exitCode,
+		Timing:                 timing,
 		PwdChanged:             pwdChanged,
 		Pwd:                    pwd,
 		InteractionModeChanged: interactionModeChanged,
@@ -366,6 +385,7 @@ func (f *MarkerFilter) Reset() {
 	defer f.mu.Unlock()
 	f.buffer.Reset()
 	f.inCommandOutput = false
+	f.commandStartedAt = time.Time{}
 	f.inInteractive = false
 	f.privateModeTail = ""
 	f.activeModes = make(map[string]struct{})
@@ -207,6 +207,33 @@ func TestMarkerFilter_Process_MultipleCommands(t *testing.T) {
 	}
 }
 
+func TestMarkerFilter_Process_CommandTiming(t *testing.T) {
+	filter := NewMarkerFilter(testLogger)
+	clock := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	filter.now = func() time.Time { return clock }
+
+	filter.Process([]byte("\x1b]133;A\x1b\\hello\n"))
+	clock = clock.Add(1500 * time.Millisecond)
+	pwd := base64.StdEncoding.EncodeToString([]byte("/tmp/work"))
+	result := filter.Process([]byte("\x1b]1337;Pwd;" + pwd + "\x07\x1b]133;D;0\x1b\\"))
+
+	if !result.CommandEnded {
+		t.Fatal("command should be ended")
+	}
+	if !result.Timing.StartedAt.Equal(clock.Add(-1500*time.Millisecond)) || result.Timing.Duration != 1500*time.Millisecond {
+		t.Errorf("timing = %+v, want 1.5s from start marker", result.Timing)
+	}
+	if result.Timing.Cwd != "/tmp/work" {
+		t.Errorf("cwd = %q, want /tmp/work", result.Timing.Cwd)
+	}
+
+	// 缺少开始标记时不计算耗时
+	result = filter.Process([]byte("\x1b]133;D;1\x1b\\"))
+	if !result.CommandEnded || !result.Timing.StartedAt.IsZero() || result.Timing.Duration != 0 {
+		t.Errorf("timing without start marker = %+v, want zero", result.Timing)
+	}
+}
+
 func TestMarkerFilter_Reset(t *testing.T) {
 	filter := NewMarkerFilter(testLogger)
 
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/events"
+	"github.com/chenyang-zz/boxify/internal/eventstream"
+	"github.com/chenyang-zz/boxify/internal/metrics"
 	boxtypes "github.com/chenyang-zz/boxify/internal/types"
 
 	"log/slog"
@@ -34,6 +36,10 @@ type EventEmitter interface {
 type OutputHandler struct {
 	emitter EventEmitter
 	logger  *slog.Logger
+	streams *eventstream.Manager // 分块传输通道（为 nil 时直接发送事件）
+	limits  OutputLimits         // 输出合并与限速参数
+	// 命令块结束回调（可选），在发送 command_end 事件后调用
+	onCommandEnd func(session *Session, blockID string, exitCode int)
 	// 初始命令结束后保留一段静默窗口，吸收尾部输出，避免串到首条用户命令。
 	initialCommandDrainDelay time.Duration
 }
@@ -43,13 +49,50 @@ func NewOutputHandler(emitter EventEmitter, logger *slog.Logger) *OutputHandler
 	return &OutputHandler{
 		emitter:                  emitter,
 		logger:                   logger,
+		limits:                   DefaultOutputLimits(),
 		initialCommandDrainDelay: 200 * time.Millisecond,
 	}
 }
 
+// SetOutputLimits 设置输出合并与限速参数，对之后启动的会话生效。
+func (h *OutputHandler) SetOutputLimits(limits OutputLimits) {
+	h.limits = limits
+}
+
+// SetStreamManager 设置分块传输通道，启用前端确认驱动的输出背压。
+func (h *OutputHandler) SetStreamManager(streams *eventstream.Manager) {
+	h.streams = streams
+}
+
+// SetCommandEndHook 设置命令块结束回调，初始命令结束时不会调用。
+func (h *OutputHandler) SetCommandEndHook(fn func(session *Session, blockID string, exitCode int)) {
+	h.onCommandEnd = fn
+}
+
 // StartOutputLoop 启动输出读取循环
 func (h *OutputHandler) StartOutputLoop(session *Session) {
-	buf := make([]byte, 1024)
+	buf := make([]byte, 32<<10)
+
+	// 每个会话一个长生命周期传输流；前端确认滞后时写入阻塞，从而暂停读取 PTY。
+	var stream *eventstream.Stream
+	if h.streams != nil {
+		stream = h.streams.Open("terminal:output", map[string]interface{}{"sessionId": session.ID})
+		defer stream.Close()
+	}
+
+	// 合并短时间内的连续输出，并按速率上限丢弃多余部分，避免 `yes`、大文件 cat 等场景淹没事件总线。
+	coalescer := newOutputCoalescer(h.limits, func(blockID string, data []byte) {
+		metrics.Add(metrics.TerminalOutputEvents, 1)
+		if stream != nil {
+			h.emitOutputStream(session, stream, blockID, data)
+		} else {
+			h.emitOutput(session.ID, blockID, data)
+		}
+	}, func(blockID string, dropped int64) {
+		metrics.Add(metrics.TerminalDroppedBytes, dropped)
+		h.emitOutputTruncated(session.ID, blockID, dropped)
+	})
+	defer coalescer.Close()
 
 	for {
 		select {
@@ -67,6 +110,9 @@ func (h *OutputHandler) StartOutputLoop(session *Session) {
 				return
 			}
 
+			session.Touch()
+			metrics.Add(metrics.TerminalOutputBytes, int64(n))
+
 			// 使用过滤器处理输出
 			result := session.Filter().Process(buf[:n])
 
@@ -77,10 +123,17 @@ func (h *OutputHandler) StartOutputLoop(session *Session) {
 			if len(result.Output) > 0 {
 				if !session.IsInitialCommandBlock(blockID) {
 					h.logger.Info("提取过滤后终端输出", "text", string(result.Output))
-					h.emitOutput(session.ID, blockID, result.Output)
+					session.Scrollback().Write(result.Output)
+					session.Blocks().Append(blockID, result.Output)
+					coalescer.Write(blockID, result.Output)
 				}
 			}
 
+			// 其他事件发送前先发出已合并的输出，保持事件顺序
+			if result.PwdChanged || result.InteractionModeChanged || result.CommandEnded {
+				coalescer.Flush()
+			}
+
 			// 工作路径变化时发送事件
 			if result.PwdChanged {
 				h.emitPwdUpdate(session.ID, result.Pwd)
@@ -107,7 +160,11 @@ func (h *OutputHandler) StartOutputLoop(session *Session) {
 					}(session)
 					continue
 				}
-				h.emitCommandEnd(session.ID, blockID, result.ExitCode)
+				session.Blocks().End(blockID, result.ExitCode)
+				h.emitCommandEnd(session.ID, blockID, result.ExitCode, result.Timing)
+				if h.onCommandEnd != nil {
+					h.onCommandEnd(session, blockID, result.ExitCode)
+				}
 			}
 		}
 	}
@@ -127,6 +184,15 @@ func (h *OutputHandler) emitOutput(sessionID, blockID string, output []byte) {
 	})
 }
 
+// emitOutputStream 通过分块传输流发送输出事件，负载字段与 emitOutput 保持兼容。
+func (h *OutputHandler) emitOutputStream(session *Session, stream *eventstream.Stream, blockID string, output []byte) {
+	err := stream.Write(session.Context(), output, map[string]interface{}{"blockId": blockID})
+	if err != nil && session.Context().Err() == nil {
+		h.logger.Warn("分块发送终端输出失败，回退直接发送", "sessionId", session.ID, "error", err)
+		h.emitOutput(session.ID, blockID, output)
+	}
+}
+
 // emitError 发送错误事件
 func (h *OutputHandler) emitError(sessionID, message string) {
 	if h.emitter == nil {
@@ -140,16 +206,41 @@ func (h *OutputHandler) emitError(sessionID, message string) {
 }
 
 // emitCommandEnd 发送命令结束事件
-func (h *OutputHandler) emitCommandEnd(sessionID, blockID string, exitCode int) {
+// 检测到开始标记时附带 startedAt（Unix 毫秒）与 durationMs，已知工作路径时附带 cwd
+func (h *OutputHandler) emitCommandEnd(sessionID, blockID string, exitCode int, timing CommandTiming) {
 	if h.emitter == nil {
 		return
 	}
 
-	h.emitter.Emit("terminal:command_end", map[string]interface{}{
+	payload := map[string]interface{}{
 		"sessionId": sessionID,
 		"blockId":   blockID,
 		"exitCode":  exitCode,
-	})
+	}
+	if !timing.StartedAt.IsZero() {
+		payload["startedAt"] = timing.StartedAt.UnixMilli()
+		payload["durationMs"] = timing.Duration.Milliseconds()
+	}
+	if timing.Cwd != "" {
+		payload["cwd"] = timing.Cwd
+	}
+	h.emitter.Emit("terminal:command_end", payload)
+}
+
+// emitOutputTruncated 发送输出截断事件：超过速率上限的输出未发送给前端，
+// 完整输出仍可通过命令块输出与回滚缓冲区获取
+func (h *OutputHandler) emitOutputTruncated(sessionID, blockID string, droppedBytes int64) {
+	if h.emitter == nil {
+		return
+	}
+	h.emitter.Emit(
+		string(events.EventTypeTerminalOutputTruncated),
+		boxtypes.TerminalOutputTruncatedEvent{
+			SessionID:    sessionID,
+			BlockID:      blockID,
+			DroppedBytes: droppedBytes,
+		},
+	)
 }
 
 // emitPwdUpdate 发送工作路径更新事件
@@ -0,0 +1,168 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"sync"
+	"time"
+)
+
+// OutputLimits 终端输出合并与限速参数
+type OutputLimits struct {
+	FlushInterval     time.Duration // 合并窗口，窗口内的输出合并为一个事件；<= 0 时每次读取立即发送
+	MaxBatchBytes     int           // 单个事件的最大字节数，达到后立即发送
+	MaxBytesPerSecond int           // 每秒发送给前端的最大字节数，超出部分丢弃并发送截断事件；<= 0 表示不限速
+}
+
+// DefaultOutputLimits 返回默认的输出合并与限速参数
+func DefaultOutputLimits() OutputLimits {
+	return OutputLimits{
+		FlushInterval:     16 * time.Millisecond,
+		MaxBatchBytes:     64 << 10,
+		MaxBytesPerSecond: 2 << 20,
+	}
+}
+
+// outputCoalescer 合并同一命令块的连续输出并按速率上限丢弃多余输出。
+// 只影响发送给前端的事件，回滚缓冲区与命令块输出仍保存完整数据。
+type outputCoalescer struct {
+	mu     sync.Mutex
+	limits OutputLimits
+	emit   func(blockID string, data []byte)        // 发送合并后的输出
+	report func(blockID string, droppedBytes int64) // 发送截断信号
+	now    func() time.Time
+
+	blockID     string
+	pending     []byte
+	windowStart time.Time // 当前限速窗口的开始时间
+	windowBytes int       // 当前限速窗口内已接收的字节数
+	dropped     int64     // 尚未上报的丢弃字节数
+	droppedID   string    // 丢弃输出所属的命令块
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newOutputCoalescer 创建输出合并器，FlushInterval > 0 时启动定时发送协程
+func newOutputCoalescer(limits OutputLimits, emit func(string, []byte), report func(string, int64)) *outputCoalescer {
+	c := &outputCoalescer{
+		limits: limits,
+		emit:   emit,
+		report: report,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if limits.FlushInterval > 0 {
+		go c.run()
+	} else {
+		close(c.done)
+	}
+	return c
+}
+
+// Write 追加命令块输出；命令块切换或达到批次上限时先发送已合并的输出
+func (c *outputCoalescer) Write(blockID string, p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if blockID != c.blockID {
+		c.flushLocked(true)
+		c.blockID = blockID
+	}
+	p = c.limitLocked(blockID, p)
+	if len(p) == 0 {
+		return
+	}
+	c.pending = append(c.pending, p...)
+	if c.limits.FlushInterval <= 0 || (c.limits.MaxBatchBytes > 0 && len(c.pending) >= c.limits.MaxBatchBytes) {
+		c.flushLocked(false)
+	}
+}
+
+// Flush 立即发送已合并的输出并上报截断，用于在命令结束等事件前保持顺序
+func (c *outputCoalescer) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked(true)
+}
+
+// Close 停止定时发送并发送剩余输出
+func (c *outputCoalescer) Close() {
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	<-c.done
+	c.Flush()
+}
+
+// run 按合并窗口定时发送输出
+func (c *outputCoalescer) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.limits.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			c.flushLocked(false)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// limitLocked 按每秒字节上限截取可发送的部分，其余计入丢弃字节数；调用方需持有锁
+func (c *outputCoalescer) limitLocked(blockID string, p []byte) []byte {
+	if c.limits.MaxBytesPerSecond <= 0 {
+		return p
+	}
+	now := c.now()
+	if now.Sub(c.windowStart) >= time.Second {
+		// 新窗口开始前上报上一窗口的截断
+		c.flushLocked(true)
+		c.windowStart = now
+		c.windowBytes = 0
+	}
+	allowed := min(len(p), max(0, c.limits.MaxBytesPerSecond-c.windowBytes))
+	c.windowBytes += len(p)
+	if allowed < len(p) {
+		c.dropped += int64(len(p) - allowed)
+		c.droppedID = blockID
+	}
+	return p[:allowed]
+}
+
+// flushLocked 发送已合并的输出；持续丢弃时每个限速窗口只上报一次截断，force 为 true 时立即上报。
+// 调用方需持有锁
+func (c *outputCoalescer) flushLocked(force bool) {
+	if len(c.pending) > 0 {
+		data := c.pending
+		c.pending = nil
+		c.emit(c.blockID, data)
+	}
+	if c.dropped > 0 && (force || c.now().Sub(c.windowStart) >= time.Second) {
+		if c.report != nil {
+			c.report(c.droppedID, c.dropped)
+		}
+		c.dropped = 0
+	}
+}
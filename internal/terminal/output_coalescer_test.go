@@ -0,0 +1,104 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// coalescerRecorder 记录合并器发出的输出与截断
+type coalescerRecorder struct {
+	outputs   []string
+	truncated []int64
+}
+
+func (r *coalescerRecorder) emit(blockID string, data []byte) {
+	r.outputs = append(r.outputs, blockID+":"+string(data))
+}
+
+func (r *coalescerRecorder) report(_ string, dropped int64) {
+	r.truncated = append(r.truncated, dropped)
+}
+
+func TestOutputCoalescer_MergesWithinInterval(t *testing.T) {
+	rec := &coalescerRecorder{}
+	c := newOutputCoalescer(OutputLimits{FlushInterval: time.Hour, MaxBatchBytes: 8}, rec.emit, rec.report)
+	defer c.Close()
+
+	c.Write("b1", []byte("ab"))
+	c.Write("b1", []byte("cd"))
+	c.Write("b2", []byte("ef"))
+	if len(rec.outputs) != 1 || rec.outputs[0] != "b1:abcd" {
+		t.Fatalf("block switch should flush previous block, got %v", rec.outputs)
+	}
+
+	c.Write("b2", []byte("ghijkl"))
+	if len(rec.outputs) != 2 || rec.outputs[1] != "b2:efghijkl" {
+		t.Fatalf("MaxBatchBytes should flush immediately, got %v", rec.outputs)
+	}
+
+	c.Write("b2", []byte("z"))
+	c.Flush()
+	if len(rec.outputs) != 3 || rec.outputs[2] != "b2:z" {
+		t.Fatalf("Flush should emit pending output, got %v", rec.outputs)
+	}
+}
+
+func TestOutputCoalescer_ImmediateWithoutInterval(t *testing.T) {
+	rec := &coalescerRecorder{}
+	c := newOutputCoalescer(OutputLimits{}, rec.emit, rec.report)
+	defer c.Close()
+
+	c.Write("b1", []byte("a"))
+	c.Write("b1", []byte("b"))
+	if strings.Join(rec.outputs, ",") != "b1:a,b1:b" {
+		t.Fatalf("outputs = %v, want one event per write", rec.outputs)
+	}
+}
+
+func TestOutputCoalescer_RateLimit(t *testing.T) {
+	rec := &coalescerRecorder{}
+	c := newOutputCoalescer(OutputLimits{MaxBytesPerSecond: 4}, rec.emit, rec.report)
+	defer c.Close()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return clock }
+
+	c.Write("b1", []byte("abc"))
+	c.Write("b1", []byte("defg"))
+	c.Write("b1", []byte("hij"))
+	if strings.Join(rec.outputs, ",") != "b1:abc,b1:d" {
+		t.Fatalf("outputs = %v, want only 4 bytes in the first second", rec.outputs)
+	}
+	if len(rec.truncated) != 0 {
+		t.Fatalf("truncation should be reported once per window, got %v", rec.truncated)
+	}
+
+	clock = clock.Add(time.Second)
+	c.Write("b1", []byte("kl"))
+	if strings.Join(rec.outputs, ",") != "b1:abc,b1:d,b1:kl" {
+		t.Fatalf("new window should allow output again, got %v", rec.outputs)
+	}
+	if len(rec.truncated) != 1 || rec.truncated[0] != 6 {
+		t.Fatalf("truncated = %v, want [6]", rec.truncated)
+	}
+
+	c.Write("b1", []byte("mnop"))
+	c.Flush()
+	if len(rec.truncated) != 2 || rec.truncated[1] != 2 {
+		t.Fatalf("Flush should report pending truncation, got %v", rec.truncated)
+	}
+}
@@ -156,7 +156,7 @@ func TestOutputHandler_emitCommandEnd(t *testing.T) {
 	emitter := &mockEventEmitter{}
 	handler := NewOutputHandler(emitter, testLogger)
 
-	handler.emitCommandEnd("session-1", "block-1", 0)
+	handler.emitCommandEnd("session-1", "block-1", 0, CommandTiming{})
 
 	if len(emitter.events) != 1 {
 		t.Fatalf("expected 1 event, got %d", len(emitter.events))
@@ -183,7 +183,7 @@ func TestOutputHandler_emitCommandEnd(t *testing.T) {
 
 	// 测试非零退出码
 	emitter.events = nil
-	handler.emitCommandEnd("session-2", "block-2", 1)
+	handler.emitCommandEnd("session-2", "block-2", 1, CommandTiming{})
 
 	payload2, ok := emitter.events[0].data.(map[string]interface{})
 	if !ok {
@@ -194,11 +194,37 @@ func TestOutputHandler_emitCommandEnd(t *testing.T) {
 	}
 }
 
+func TestOutputHandler_emitCommandEnd_Timing(t *testing.T) {
+	emitter := &mockEventEmitter{}
+	handler := NewOutputHandler(emitter, testLogger)
+	startedAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	handler.emitCommandEnd("session-1", "block-1", 0, CommandTiming{StartedAt: startedAt, Duration: 2500 * time.Millisecond, Cwd: "/tmp"})
+
+	payload := emitter.events[0].data.(map[string]interface{})
+	if payload["durationMs"] != int64(2500) {
+		t.Errorf("durationMs = %v, want 2500", payload["durationMs"])
+	}
+	if payload["startedAt"] != startedAt.UnixMilli() {
+		t.Errorf("startedAt = %v, want %d", payload["startedAt"], startedAt.UnixMilli())
+	}
+	if payload["cwd"] != "/tmp" {
+		t.Errorf("cwd = %v, want /tmp", payload["cwd"])
+	}
+
+	emitter.events = nil
+	handler.emitCommandEnd("session-1", "block-2", 0, CommandTiming{})
+	payload = emitter.events[0].data.(map[string]interface{})
+	if _, ok := payload["durationMs"]; ok {
+		t.Error("durationMs should be omitted without start marker")
+	}
+}
+
 func TestOutputHandler_emitCommandEnd_NilEmitter(t *testing.T) {
 	handler := NewOutputHandler(nil, testLogger)
 
 	// 不应该 panic
-	handler.emitCommandEnd("session-1", "block-1", 0)
+	handler.emitCommandEnd("session-1", "block-1", 0, CommandTiming{})
 }
 
 func TestOutputHandler_StartOutputLoop_ContextCancellation(t *testing.T) {
@@ -346,7 +372,7 @@ func TestOutputHandler_MultipleEvents(t *testing.T) {
 	// 发送多个事件
 	handler.emitOutput("session-1", "block-1", []byte("output 1"))
 	handler.emitOutput("session-1", "block-2", []byte("output 2"))
-	handler.emitCommandEnd("session-1", "block-2", 0)
+	handler.emitCommandEnd("session-1", "block-2", 0, CommandTiming{})
 	handler.emitError("session-1", "error message")
 
 	if len(emitter.events) != 4 {
@@ -33,6 +33,8 @@ type ProcessOptions struct {
 	SessionID string
 	Rows      uint16
 	Cols      uint16
+	Args      []string // 追加的 shell 启动参数，位于 hooks 集成参数之前
+	Env       []string // 追加到 shell 进程的环境变量
 }
 
 // Process 创建的进程信息
@@ -77,11 +79,11 @@ func (pm *ProcessManager) CreateProcess(opts *ProcessOptions) (*Process, error)
 	if useHooks && configPath != "" {
 		// 使用 hooks 模式
 		args, _ := pm.configGenerator.GetShellArgs(opts.ShellType, configPath)
-		cmd = exec.Command(opts.ShellPath, args...)
+		cmd = exec.Command(opts.ShellPath, append(append([]string(nil), opts.Args...), args...)...)
 		pm.logger.Info("终端使用hooks模式")
 	} else {
 		// 使用命令包装模式或默认模式
-		cmd = exec.Command(opts.ShellPath)
+		cmd = exec.Command(opts.ShellPath, opts.Args...)
 		pm.logger.Info("终端使用包装模式")
 	}
 
@@ -93,6 +95,7 @@ func (pm *ProcessManager) CreateProcess(opts *ProcessOptions) (*Process, error)
 		"COLORTERM=truecolor",
 		"BOXIFY_SESSION_ID="+opts.SessionID,
 	)
+	cmd.Env = append(cmd.Env, opts.Env...)
 
 	// 对于 zsh，设置 ZDOTDIR 环境变量让 shell 从临时目录加载配置
 	if useHooks && opts.ShellType == ShellTypeZsh && configPath != "" {
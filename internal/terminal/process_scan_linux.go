@@ -0,0 +1,64 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// scanSessionProcesses 扫描 /proc 查找会话 ID 为 sid 的存活进程（忽略僵尸进程）；
+// 第二个返回值为 false 表示无法读取 /proc
+func scanSessionProcesses(sid int) (bool, bool) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return false, false
+	}
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		if state, session, ok := parseProcStat(string(data)); ok && state != "Z" && session == sid {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// parseProcStat 从 /proc/<pid>/stat 中解析进程状态与会话 ID。
+// 进程名可能包含空格与括号，因此从最后一个 ')' 之后开始按字段切分：state ppid pgrp session ...
+func parseProcStat(stat string) (state string, session int, ok bool) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return "", 0, false
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 4 {
+		return "", 0, false
+	}
+	session, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return "", 0, false
+	}
+	return fields[0], session, true
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package terminal
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// unixSignals 信号名到信号值的映射
+var unixSignals = map[string]syscall.Signal{
+	"SIGINT":  syscall.SIGINT,
+	"SIGTSTP": syscall.SIGTSTP,
+	"SIGCONT": syscall.SIGCONT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// hangupProcess 向 shell 发送 SIGHUP 与 SIGTERM：交互式 bash/zsh 忽略 SIGTERM，
+// 收到 SIGHUP 后会转发给各作业并保存历史后退出。
+func hangupProcess(p *os.Process) error {
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
+
+// sessionHasProcesses 判断 shell 创建的会话（sid 即 shell 的 PID）中是否仍有存活进程
+func sessionHasProcesses(sid int) bool {
+	if found, ok := scanSessionProcesses(sid); ok {
+		return found
+	}
+	// 无法枚举进程时只检查与 shell 同一进程组的进程
+	err := syscall.Kill(-sid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// foregroundProcessGroup 通过 TIOCGPGRP 读取 PTY 的前台进程组。
+// 使用 SyscallConn 访问描述符，避免 Fd() 将 PTY 切换为阻塞模式
+func foregroundProcessGroup(ptyFile *os.File) (int, error) {
+	conn, err := ptyFile.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var pgid int
+	var ioctlErr error
+	if err := conn.Control(func(fd uintptr) {
+		pgid, ioctlErr = unix.IoctlGetInt(int(fd), unix.TIOCGPGRP)
+	}); err != nil {
+		return 0, err
+	}
+	return pgid, ioctlErr
+}
+
+// signalProcessGroup 向整个进程组发送信号
+func signalProcessGroup(pgid int, name string) error {
+	sig, ok := unixSignals[name]
+	if !ok || pgid <= 0 {
+		return errSignalUnsupported
+	}
+	return syscall.Kill(-pgid, sig)
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package terminal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// startSessionProcess 以独立会话启动进程，模拟 PTY 下 shell 的进程关系
+func startSessionProcess(t *testing.T, script string) *Session {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start process: %v", err)
+	}
+	t.Cleanup(func() { syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) })
+	// 等待 sh 安装 trap 或启动子进程
+	time.Sleep(100 * time.Millisecond)
+	return NewSession(context.Background(), "graceful", nil, cmd, ShellTypeBash, false, testLogger)
+}
+
+func TestSession_Terminate_Graceful(t *testing.T) {
+	session := startSessionProcess(t, "exec sleep 30")
+
+	report := session.Terminate(2 * time.Second)
+	if !report.Graceful || report.Killed {
+		t.Errorf("expected graceful exit, got %+v", report)
+	}
+	if report.OrphanedChildren {
+		t.Errorf("expected no orphaned children, got %+v", report)
+	}
+	if err := session.WaitProcess(); err == nil {
+		t.Error("WaitProcess should report the signal exit")
+	}
+}
+
+func TestSession_Terminate_EscalatesToKill(t *testing.T) {
+	session := startSessionProcess(t, "trap '' HUP TERM; while :; do sleep 0.05; done")
+
+	report := session.Terminate(200 * time.Millisecond)
+	if report.Graceful || !report.Killed {
+		t.Errorf("expected SIGKILL escalation, got %+v", report)
+	}
+	if report.Elapsed < 200*time.Millisecond {
+		t.Errorf("expected to wait for grace period, elapsed %v", report.Elapsed)
+	}
+}
+
+func TestSession_Terminate_ReportsOrphans(t *testing.T) {
+	session := startSessionProcess(t, "trap 'exit 0' HUP; sleep 30 & wait")
+
+	report := session.Terminate(2 * time.Second)
+	if !report.Graceful {
+		t.Errorf("expected graceful exit, got %+v", report)
+	}
+	if !report.OrphanedChildren {
+		t.Errorf("expected background sleep to be reported as orphaned, got %+v", report)
+	}
+}
+
+func TestSessionManager_WatchExit(t *testing.T) {
+	sm := NewSessionManager()
+	session := startSessionProcess(t, "sleep 0.2; exit 3")
+	sm.Add(session)
+
+	done := make(chan *CloseReport, 1)
+	sm.WatchExit(session, nil, func(s *Session, report *CloseReport) { done <- report })
+
+	select {
+	case report := <-done:
+		if !report.Graceful || report.Killed {
+			t.Errorf("expected graceful report for self-exited shell, got %+v", report)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("WatchExit callback not called")
+	}
+	if code, ok := session.ExitCode(); !ok || code != 3 {
+		t.Errorf("ExitCode() = %d, %v, want 3, true", code, ok)
+	}
+	if sm.Count() != 0 {
+		t.Errorf("exited session should be removed, count = %d", sm.Count())
+	}
+}
+
+func TestSessionManager_WatchExit_SkipsClosedSession(t *testing.T) {
+	sm := NewSessionManager()
+	sm.SetCloseGracePeriod(time.Second)
+	session := startSessionProcess(t, "exec sleep 30")
+	sm.Add(session)
+
+	called := make(chan struct{}, 1)
+	sm.WatchExit(session, nil, func(*Session, *CloseReport) { called <- struct{}{} })
+	if _, err := sm.CloseSession(session.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-called:
+		t.Error("onExit should not be called for sessions closed via CloseSession")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSession_SendSignal_ForegroundGroup(t *testing.T) {
+	// set -m 开启作业控制，sleep 在独立进程组中成为前台进程组
+	cmd := exec.Command("sh", "-c", "set -m; sleep 30; echo done; sleep 30")
+	ptyFile, err := pty.Start(cmd)
+	if err != nil {
+		t.Fatalf("start pty: %v", err)
+	}
+	defer ptyFile.Close()
+	t.Cleanup(func() { syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) })
+	go io.Copy(io.Discard, ptyFile)
+	session := NewSession(context.Background(), "signal", ptyFile, cmd, ShellTypeBash, false, testLogger)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		pgid, err := foregroundProcessGroup(ptyFile)
+		if err == nil && pgid != cmd.Process.Pid {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Skip("shell does not support job control in this environment")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	pgid, err := session.SendSignal("kill")
+	if err != nil {
+		t.Fatalf("SendSignal() error = %v", err)
+	}
+	if pgid == cmd.Process.Pid {
+		t.Fatalf("signal should go to the foreground job, not the shell")
+	}
+	// shell 存活并继续执行下一条命令，说明只有前台作业被终止
+	deadline = time.Now().Add(2 * time.Second)
+	for {
+		next, err := foregroundProcessGroup(ptyFile)
+		if err == nil && next != cmd.Process.Pid && next != pgid {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("shell did not continue with the next job")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSession_SendSignal_ShellForeground(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30")
+	ptyFile, err := pty.Start(cmd)
+	if err != nil {
+		t.Fatalf("start pty: %v", err)
+	}
+	defer ptyFile.Close()
+	t.Cleanup(func() { syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) })
+	session := NewSession(context.Background(), "signal", ptyFile, cmd, ShellTypeBash, false, testLogger)
+
+	if _, err := session.SendSignal("SIGKILL"); !errors.Is(err, ErrNoForegroundProcess) {
+		t.Fatalf("SendSignal(SIGKILL) to shell = %v, want ErrNoForegroundProcess", err)
+	}
+	if _, err := session.SendSignal("SIGHUP"); err == nil {
+		t.Fatal("unsupported signal should be rejected")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package terminal
+
+import "os"
+
+// hangupProcess Windows 下没有可供 shell 处理的退出信号，直接走强制终止
+func hangupProcess(_ *os.Process) error {
+	return errGracefulUnsupported
+}
+
+// sessionHasProcesses Windows 下不检测残留子进程
+func sessionHasProcesses(_ int) bool {
+	return false
+}
+
+// foregroundProcessGroup Windows 伪终端没有前台进程组
+func foregroundProcessGroup(_ *os.File) (int, error) {
+	return 0, errSignalUnsupported
+}
+
+// signalProcessGroup Windows 下不支持向进程组发送 POSIX 信号
+func signalProcessGroup(_ int, _ string) error {
+	return errSignalUnsupported
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/chenyang-zz/boxify/internal/types"
+)
+
+// DefaultScrollbackLimit 每个会话保留的回滚文本上限（字节）
+const DefaultScrollbackLimit = 1 << 20
+
+// MaxScrollbackMatches 单次搜索返回的最大匹配数
+const MaxScrollbackMatches = 1000
+
+// Scrollback 会话输出的回滚缓冲区
+// 写入时去除 ANSI 控制序列与回车，只保留可搜索的纯文本；超过上限时按整行丢弃最旧内容
+type Scrollback struct {
+	mu           sync.RWMutex
+	buf          []byte
+	limit        int
+	droppedBytes int64 // 已丢弃的字节数
+	droppedLines int64 // 已丢弃的行数
+	plain        ansiStripper
+}
+
+// NewScrollback 创建回滚缓冲区，limit <= 0 时使用默认上限
+func NewScrollback(limit int) *Scrollback {
+	if limit <= 0 {
+		limit = DefaultScrollbackLimit
+	}
+	return &Scrollback{limit: limit}
+}
+
+// Write 追加一段终端输出
+func (s *Scrollback) Write(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf = s.plain.appendPlain(s.buf, p)
+	s.trim()
+}
+
+// trim 超过上限时从头部丢弃整行
+func (s *Scrollback) trim() {
+	over := len(s.buf) - s.limit
+	if over <= 0 {
+		return
+	}
+	cut := over
+	if i := bytes.IndexByte(s.buf[over:], '\n'); i >= 0 {
+		cut = over + i + 1
+	}
+	s.droppedLines += int64(bytes.Count(s.buf[:cut], []byte{'\n'}))
+	s.droppedBytes += int64(cut)
+	s.buf = append(s.buf[:0:0], s.buf[cut:]...)
+}
+
+// Len 返回缓冲区当前保留的字节数
+func (s *Scrollback) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.buf)
+}
+
+// Search 在回滚缓冲区中逐行查找 pattern，regex=false 时按字面匹配
+// 匹配数超过 MaxScrollbackMatches 时截断并返回 truncated=true
+func (s *Scrollback) Search(pattern string, regex bool) ([]types.TerminalScrollbackMatch, bool, error) {
+	if pattern == "" {
+		return nil, false, fmt.Errorf("搜索内容不能为空")
+	}
+	expr := pattern
+	if !regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, false, fmt.Errorf("正则表达式无效: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := []types.TerminalScrollbackMatch{}
+	offset := s.droppedBytes
+	line := s.droppedLines
+	rest := s.buf
+	for len(rest) > 0 {
+		end := bytes.IndexByte(rest, '\n')
+		next := end + 1
+		if end < 0 {
+			end, next = len(rest), len(rest)
+		}
+		text := rest[:end]
+		for _, loc := range re.FindAllIndex(text, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			if len(matches) >= MaxScrollbackMatches {
+				return matches, true, nil
+			}
+			matches = append(matches, types.TerminalScrollbackMatch{
+				Offset: offset + int64(loc[0]),
+				Line:   line,
+				Column: utf8.RuneCount(text[:loc[0]]),
+				Length: utf8.RuneCount(text[loc[0]:loc[1]]),
+				Text:   string(text),
+			})
+		}
+		offset += int64(next)
+		line++
+		rest = rest[next:]
+	}
+	return matches, false, nil
+}
+
+// escape 序列解析状态
+const (
+	escNone = iota
+	escStart
+	escCSI
+	escOSC
+	escOSCEnd
+)
+
+// ansiStripper 去除终端输出中的 ANSI 控制序列、回车与其他控制字符，只保留换行与制表符
+// 转义序列状态跨调用保持，输出被任意切分时结果不变
+type ansiStripper struct {
+	state int
+}
+
+// appendPlain 将 p 去除控制序列后追加到 dst
+func (a *ansiStripper) appendPlain(dst, p []byte) []byte {
+	for _, c := range p {
+		switch a.state {
+		case escStart:
+			switch c {
+			case '[':
+				a.state = escCSI
+			case ']':
+				a.state = escOSC
+			default:
+				a.state = escNone
+			}
+			continue
+		case escCSI:
+			// CSI 以 0x40-0x7E 范围内的字节结束
+			if c >= 0x40 && c <= 0x7e {
+				a.state = escNone
+			}
+			continue
+		case escOSC:
+			// OSC 以 BEL 或 ESC \ 结束
+			if c == 0x07 {
+				a.state = escNone
+			} else if c == 0x1b {
+				a.state = escOSCEnd
+			}
+			continue
+		case escOSCEnd:
+			a.state = escNone
+			continue
+		}
+
+		switch {
+		case c == 0x1b:
+			a.state = escStart
+		case c == '\n' || c == '\t' || c >= 0x20 && c != 0x7f:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
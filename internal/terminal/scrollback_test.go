@@ -0,0 +1,81 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import "testing"
+
+func TestScrollbackStripsControlSequences(t *testing.T) {
+	sb := NewScrollback(0)
+	sb.Write([]byte("\x1b[31mred\x1b[0m text\r\n\x1b]0;ti"))
+	sb.Write([]byte("tle\x07next \x1b["))
+	sb.Write([]byte("1mline"))
+
+	matches, truncated, err := sb.Search("line", false)
+	if err != nil || truncated || len(matches) != 1 {
+		t.Fatalf("Search() = %+v, %v, %v", matches, truncated, err)
+	}
+	if m := matches[0]; m.Line != 1 || m.Column != 5 || m.Offset != 14 || m.Text != "next line" {
+		t.Fatalf("匹配位置错误: %+v", m)
+	}
+}
+
+func TestScrollbackSearchRegexAndLiteral(t *testing.T) {
+	sb := NewScrollback(0)
+	sb.Write([]byte("error: a.b\n日志 error 42\nok\n"))
+
+	matches, _, err := sb.Search(`error\s+\d+`, true)
+	if err != nil || len(matches) != 1 || matches[0].Line != 1 || matches[0].Column != 3 || matches[0].Length != 8 {
+		t.Fatalf("正则匹配错误: %+v %v", matches, err)
+	}
+	if matches, _, _ := sb.Search("a.b", false); len(matches) != 1 || matches[0].Column != 7 {
+		t.Fatalf("字面匹配不应解释元字符: %+v", matches)
+	}
+	if matches, _, _ := sb.Search("a.b", false); matches[0].Text != "error: a.b" {
+		t.Fatalf("应返回整行: %+v", matches)
+	}
+	if _, _, err := sb.Search("(", true); err == nil {
+		t.Fatal("无效正则应返回错误")
+	}
+	if _, _, err := sb.Search("", false); err == nil {
+		t.Fatal("空搜索内容应返回错误")
+	}
+}
+
+func TestScrollbackTrimKeepsAbsolutePositions(t *testing.T) {
+	sb := NewScrollback(12)
+	sb.Write([]byte("first\nsecond\nthird\n"))
+
+	if sb.Len() > 12 {
+		t.Fatalf("缓冲区超过上限: %d", sb.Len())
+	}
+	if matches, _, _ := sb.Search("first", false); len(matches) != 0 {
+		t.Fatalf("最旧的行应被丢弃: %+v", matches)
+	}
+	matches, _, _ := sb.Search("third", false)
+	if len(matches) != 1 || matches[0].Line != 2 || matches[0].Offset != 13 {
+		t.Fatalf("裁剪后行号与偏移应保持不变: %+v", matches)
+	}
+}
+
+func TestScrollbackSearchTruncates(t *testing.T) {
+	sb := NewScrollback(0)
+	for i := 0; i < MaxScrollbackMatches+5; i++ {
+		sb.Write([]byte("x\n"))
+	}
+	matches, truncated, _ := sb.Search("x", false)
+	if !truncated || len(matches) != MaxScrollbackMatches {
+		t.Fatalf("应截断为 %d 条: %d %v", MaxScrollbackMatches, len(matches), truncated)
+	}
+}
@@ -20,7 +20,11 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+	"github.com/chenyang-zz/boxify/internal/termprofile"
 )
 
 // TerminalConfig 终端配置
@@ -31,6 +35,41 @@ type TerminalConfig struct {
 	Cols           uint16    `json:"cols,omitempty"`           // 终端列数
 	WorkPath       string    `json:"workPath,omitempty"`       // 工作路径
 	InitialCommand string    `json:"initialCommand,omitempty"` // 初始命令
+
+	Args    []string          `json:"args,omitempty"`    // 追加的 shell 启动参数
+	Env     map[string]string `json:"env,omitempty"`     // 追加的环境变量，与配置方案同名时覆盖方案中的值
+	Profile string            `json:"profile,omitempty"` // 终端配置方案 ID 或名称，未填写字段使用方案中的值
+
+	// DBConsole 不为空时会话作为数据库控制台启动，自动进入 mysql/psql 或内置控制台
+	DBConsole *connection.ConnectionConfig `json:"dbConsole,omitempty"`
+}
+
+// ApplyProfile 以配置方案补全终端配置：config 中已填写的 shell、工作路径、初始命令优先，
+// 启动参数为方案参数在前、config 参数在后，环境变量同名时 config 覆盖方案
+func ApplyProfile(config TerminalConfig, profile *termprofile.Profile) TerminalConfig {
+	if profile == nil {
+		return config
+	}
+	if (config.Shell == "" || config.Shell == ShellTypeAuto) && profile.Shell != "" {
+		config.Shell = ShellType(profile.Shell)
+	}
+	if config.WorkPath == "" {
+		config.WorkPath = profile.WorkPath
+	}
+	if config.InitialCommand == "" {
+		config.InitialCommand = profile.InitialCommand
+	}
+	config.Args = append(append([]string(nil), profile.Args...), config.Args...)
+
+	env := make(map[string]string, len(profile.Env)+len(config.Env))
+	for k, v := range profile.Env {
+		env[k] = v
+	}
+	for k, v := range config.Env {
+		env[k] = v
+	}
+	config.Env = env
+	return config
 }
 
 // Session 终端会话
@@ -47,6 +86,9 @@ type Session struct {
 	initialDone  chan struct{}
 	initialState sync.RWMutex
 	initialOnce  sync.Once
+	waitOnce     sync.Once     // 保证 Cmd.Wait 只调用一次
+	exited       chan struct{} // 进程退出后关闭
+	waitErr      error         // Cmd.Wait 的返回值
 
 	// Shell Hooks 相关字段
 	filter     *MarkerFilter   // 输出过滤器
@@ -54,32 +96,42 @@ type Session struct {
 	shellType  ShellType       // shell 类型
 	useHooks   bool            // 是否使用 hooks 模式
 	configPath string          // 临时配置文件路径
+	secretDir  string          // 数据库控制台凭据目录，会话关闭时删除
 	workPath   string          // 当前工作路径
+	scrollback *Scrollback     // 输出回滚缓冲区，供终端内搜索
+	blocks     *BlockOutputs   // 按命令块保存的输出
 	logger     *slog.Logger
+
+	lastActivity atomic.Int64 // 最近一次输入或输出的时间（Unix 纳秒）
+	idleWarned   atomic.Bool  // 本轮空闲是否已发出警告，有新活动时重置
 }
 
 // NewSession 创建新的终端会话
 func NewSession(ctx context.Context, id string, pty *os.File, cmd *exec.Cmd, shellType ShellType, useHooks bool, logger *slog.Logger) *Session {
 	sessionCtx, sessionCancel := context.WithCancel(ctx)
 
-	return &Session{
-		ID:        id,
-		Pty:       pty,
-		Cmd:       cmd,
-		CreatedAt: time.Now(),
-		ctx:       sessionCtx,
-		cancel:    sessionCancel,
-		filter:    NewMarkerFilter(logger),
-		wrapper:   NewCommandWrapper(shellType, testLogger),
-		shellType: shellType,
-		useHooks:  useHooks,
-		logger:    logger,
+	session := &Session{
+		ID:         id,
+		Pty:        pty,
+		Cmd:        cmd,
+		CreatedAt:  time.Now(),
+		ctx:        sessionCtx,
+		cancel:     sessionCancel,
+		filter:     NewMarkerFilter(logger),
+		wrapper:    NewCommandWrapper(shellType, testLogger),
+		shellType:  shellType,
+		useHooks:   useHooks,
+		scrollback: NewScrollback(DefaultScrollbackLimit),
+		blocks:     NewBlockOutputs(DefaultBlockOutputLimit, DefaultBlockTotalLimit),
+		logger:     logger,
 		initialDone: func() chan struct{} {
 			done := make(chan struct{})
 			close(done)
 			return done
 		}(),
 	}
+	session.Touch()
+	return session
 }
 
 // SetLogger 设置日志器
@@ -104,6 +156,16 @@ func (s *Session) Filter() *MarkerFilter {
 	return s.filter
 }
 
+// Scrollback 返回输出回滚缓冲区
+func (s *Session) Scrollback() *Scrollback {
+	return s.scrollback
+}
+
+// Blocks 返回命令块输出存储
+func (s *Session) Blocks() *BlockOutputs {
+	return s.blocks
+}
+
 // Wrapper 返回命令包装器
 func (s *Session) Wrapper() *CommandWrapper {
 	return s.wrapper
@@ -129,6 +191,16 @@ func (s *Session) SetConfigPath(path string) {
 	s.configPath = path
 }
 
+// SecretDir 返回数据库控制台凭据目录
+func (s *Session) SecretDir() string {
+	return s.secretDir
+}
+
+// SetSecretDir 设置数据库控制台凭据目录
+func (s *Session) SetSecretDir(dir string) {
+	s.secretDir = dir
+}
+
 // CurrentBlock 返回当前活动的 block ID
 func (s *Session) CurrentBlock() string {
 	s.blockMutex.RLock()
@@ -221,10 +293,11 @@ func (s *Session) KillProcess() error {
 	return nil
 }
 
-// WaitProcess 等待进程结束
+// WaitProcess 等待进程结束，可重复调用
 func (s *Session) WaitProcess() error {
-	if s.Cmd != nil {
-		return s.Cmd.Wait()
+	if s.Cmd == nil || s.Cmd.Process == nil {
+		return nil
 	}
-	return nil
+	<-s.exitedChan()
+	return s.waitErr
 }
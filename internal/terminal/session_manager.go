@@ -15,22 +15,33 @@
 package terminal
 
 import (
+	"os"
 	"sync"
+	"time"
 )
 
 // SessionManager 会话管理器
 type SessionManager struct {
-	sessions map[string]*Session
-	mu       sync.RWMutex
+	sessions   map[string]*Session
+	mu         sync.RWMutex
+	closeGrace time.Duration // 关闭会话时等待 shell 自行退出的时长
 }
 
 // NewSessionManager 创建会话管理器
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
-		sessions: make(map[string]*Session),
+		sessions:   make(map[string]*Session),
+		closeGrace: DefaultCloseGracePeriod,
 	}
 }
 
+// SetCloseGracePeriod 设置关闭会话的宽限期，<= 0 时关闭会话直接强制终止进程
+func (sm *SessionManager) SetCloseGracePeriod(grace time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.closeGrace = grace
+}
+
 // Get 获取会话
 func (sm *SessionManager) Get(sessionID string) (*Session, bool) {
 	sm.mu.RLock()
@@ -57,52 +68,99 @@ func (sm *SessionManager) Remove(sessionID string) (*Session, bool) {
 	return session, ok
 }
 
-// CloseSession 关闭指定会话
-func (sm *SessionManager) CloseSession(sessionID string, configGenerator *ShellConfigGenerator) error {
+// CloseSession 关闭指定会话，会话不存在时返回 nil 报告。
+// 会话先从管理器移除，宽限期内的等待不阻塞其他会话的操作。
+func (sm *SessionManager) CloseSession(sessionID string, configGenerator *ShellConfigGenerator) (*CloseReport, error) {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
 	session, ok := sm.sessions[sessionID]
-	if !ok {
-		return nil
+	if ok {
+		delete(sm.sessions, sessionID)
 	}
+	grace := sm.closeGrace
+	sm.mu.Unlock()
 
-	sm.closeSessionUnsafe(session, configGenerator)
-	delete(sm.sessions, sessionID)
-
-	return nil
+	if !ok {
+		return nil, nil
+	}
+	return sm.closeSession(session, grace, configGenerator), nil
 }
 
-// CloseAll 关闭所有会话
-func (sm *SessionManager) CloseAll(configGenerator *ShellConfigGenerator) {
+// CloseAll 并行关闭所有会话，返回按会话 ID 索引的关闭结果
+func (sm *SessionManager) CloseAll(configGenerator *ShellConfigGenerator) map[string]*CloseReport {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	for _, session := range sm.sessions {
-		sm.closeSessionUnsafe(session, configGenerator)
+	sessions := sm.sessions
+	sm.sessions = make(map[string]*Session)
+	grace := sm.closeGrace
+	sm.mu.Unlock()
+
+	reports := make(map[string]*CloseReport, len(sessions))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for id, session := range sessions {
+		wg.Add(1)
+		go func(id string, session *Session) {
+			defer wg.Done()
+			report := sm.closeSession(session, grace, configGenerator)
+			mu.Lock()
+			reports[id] = report
+			mu.Unlock()
+		}(id, session)
 	}
+	wg.Wait()
+	return reports
+}
 
-	// 清空 map
-	sm.sessions = make(map[string]*Session)
+// WatchExit 等待会话进程自行退出（如用户输入 exit）。进程退出时若会话仍在管理器中，
+// 则移除会话、释放资源并回调 onExit；经 CloseSession/CloseAll 关闭的会话不会回调。
+func (sm *SessionManager) WatchExit(session *Session, configGenerator *ShellConfigGenerator, onExit func(session *Session, report *CloseReport)) {
+	exited := session.Exited()
+	if exited == nil {
+		return
+	}
+	go func() {
+		<-exited
+
+		sm.mu.Lock()
+		current, ok := sm.sessions[session.ID]
+		owned := ok && current == session
+		if owned {
+			delete(sm.sessions, session.ID)
+		}
+		sm.mu.Unlock()
+		if !owned {
+			return
+		}
+
+		report := sm.closeSession(session, 0, configGenerator)
+		if onExit != nil {
+			onExit(session, report)
+		}
+	}()
 }
 
-// closeSessionUnsafe 内部方法：关闭会话（不加锁）
-func (sm *SessionManager) closeSessionUnsafe(session *Session, configGenerator *ShellConfigGenerator) {
+// closeSession 内部方法：优雅结束进程后释放会话资源（不加锁）
+func (sm *SessionManager) closeSession(session *Session, grace time.Duration, configGenerator *ShellConfigGenerator) *CloseReport {
+	// 先通知读取循环退出，进程退出导致的 PTY 读取错误不再上报
+	session.Cancel()
+
+	// 发送退出信号，超时后强制终止并等待
+	report := session.Terminate(grace)
+
 	// 关闭会话资源
 	session.Close()
 
-	// 终止进程并等待
-	if err := session.KillProcess(); err != nil {
-		// 忽略错误，进程可能已结束
-	}
-	if err := session.WaitProcess(); err != nil {
-		// 忽略错误
-	}
-
 	// 清理临时配置文件
 	if session.ConfigPath() != "" && configGenerator != nil {
 		configGenerator.Cleanup(session.ConfigPath())
 	}
+
+	// 删除数据库控制台凭据文件
+	if session.SecretDir() != "" {
+		_ = os.RemoveAll(session.SecretDir())
+	}
+	return report
 }
 
 // Count 获取会话数量
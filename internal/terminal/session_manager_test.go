@@ -189,10 +189,13 @@ func TestSessionManager_CloseSession(t *testing.T) {
 	sm.Add(session)
 
 	// 关闭会话
-	err := sm.CloseSession("test-1", nil)
+	report, err := sm.CloseSession("test-1", nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
+	if report == nil || !report.Graceful {
+		t.Errorf("expected graceful report for session without process, got %+v", report)
+	}
 
 	// 验证会话被移除
 	if sm.Count() != 0 {
@@ -200,9 +203,9 @@ func TestSessionManager_CloseSession(t *testing.T) {
 	}
 
 	// 关闭不存在的会话应该返回 nil
-	err = sm.CloseSession("nonexistent", nil)
-	if err != nil {
-		t.Errorf("expected nil for nonexistent session, got %v", err)
+	report, err = sm.CloseSession("nonexistent", nil)
+	if err != nil || report != nil {
+		t.Errorf("expected nil for nonexistent session, got %+v, %v", report, err)
 	}
 }
 
@@ -215,7 +218,9 @@ func TestSessionManager_CloseAll(t *testing.T) {
 	}
 
 	// 关闭所有
-	sm.CloseAll(nil)
+	if reports := sm.CloseAll(nil); len(reports) != 5 {
+		t.Errorf("expected 5 close reports, got %d", len(reports))
+	}
 
 	// 验证所有会话被移除
 	if sm.Count() != 0 {
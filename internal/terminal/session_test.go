@@ -18,8 +18,11 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/chenyang-zz/boxify/internal/termprofile"
 )
 
 func TestConfig(t *testing.T) {
@@ -278,3 +281,32 @@ func TestSession_WorkPath(t *testing.T) {
 		t.Errorf("expected work path %s, got %s", testPath, session.WorkPath())
 	}
 }
+
+func TestApplyProfile(t *testing.T) {
+	profile := &termprofile.Profile{
+		Shell:          "bash",
+		Args:           []string{"--login"},
+		Env:            map[string]string{"APP_ENV": "dev", "REGION": "cn"},
+		WorkPath:       "/srv/a",
+		InitialCommand: "source .venv/bin/activate",
+	}
+
+	got := ApplyProfile(TerminalConfig{ID: "s1", Shell: ShellTypeAuto, Args: []string{"-x"}, Env: map[string]string{"APP_ENV": "test"}}, profile)
+	if got.Shell != ShellTypeBash || got.WorkPath != "/srv/a" || got.InitialCommand != profile.InitialCommand {
+		t.Fatalf("未填写字段应使用方案值: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Args, []string{"--login", "-x"}) {
+		t.Fatalf("Args = %v", got.Args)
+	}
+	if !reflect.DeepEqual(got.Env, map[string]string{"APP_ENV": "test", "REGION": "cn"}) {
+		t.Fatalf("Env = %v", got.Env)
+	}
+
+	explicit := ApplyProfile(TerminalConfig{Shell: ShellTypeZsh, WorkPath: "/tmp", InitialCommand: "ls"}, profile)
+	if explicit.Shell != ShellTypeZsh || explicit.WorkPath != "/tmp" || explicit.InitialCommand != "ls" {
+		t.Fatalf("已填写字段应优先: %+v", explicit)
+	}
+	if unchanged := ApplyProfile(TerminalConfig{ID: "s2"}, nil); unchanged.ID != "s2" || unchanged.Env != nil {
+		t.Fatalf("无方案时应原样返回: %+v", unchanged)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SupportedSignals 可由前端发送给前台进程组的信号
+var SupportedSignals = []string{"SIGINT", "SIGTSTP", "SIGCONT", "SIGQUIT", "SIGTERM", "SIGKILL"}
+
+var (
+	// ErrNoForegroundProcess 前台进程组就是 shell 本身，没有正在运行的命令
+	ErrNoForegroundProcess = errors.New("前台没有正在运行的命令")
+	// errSignalUnsupported 当前平台不支持向进程组发送信号
+	errSignalUnsupported = errors.New("当前平台不支持发送信号")
+)
+
+// NormalizeSignal 规范化信号名：忽略大小写，可省略 SIG 前缀
+func NormalizeSignal(name string) (string, error) {
+	sig := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+	for _, s := range SupportedSignals {
+		if s == sig {
+			return sig, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的信号: %s", name)
+}
+
+// SendSignal 向 PTY 的前台进程组发送信号，返回接收信号的进程组 ID。
+// 与写入 Ctrl+C 等控制字符不同，信号直接由内核投递，对关闭了终端中断键或处于 raw 模式的命令同样有效。
+// 前台为 shell 本身时，除 SIGINT 外的信号会终止或挂起 shell，因此返回 ErrNoForegroundProcess。
+func (s *Session) SendSignal(name string) (int, error) {
+	sig, err := NormalizeSignal(name)
+	if err != nil {
+		return 0, err
+	}
+	if s.Pty == nil || s.Cmd == nil || s.Cmd.Process == nil {
+		return 0, errors.New("会话没有运行中的进程")
+	}
+
+	pgid, err := foregroundProcessGroup(s.Pty)
+	if err != nil {
+		return 0, fmt.Errorf("获取前台进程组失败: %w", err)
+	}
+	if pgid == s.Cmd.Process.Pid && sig != "SIGINT" {
+		return pgid, ErrNoForegroundProcess
+	}
+	if err := signalProcessGroup(pgid, sig); err != nil {
+		return pgid, fmt.Errorf("发送信号 %s 失败: %w", sig, err)
+	}
+	return pgid, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import "testing"
+
+func TestNormalizeSignal(t *testing.T) {
+	tests := map[string]string{"SIGINT": "SIGINT", "int": "SIGINT", " sigkill ": "SIGKILL", "Tstp": "SIGTSTP"}
+	for in, want := range tests {
+		got, err := NormalizeSignal(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeSignal(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "SIGHUP", "9"} {
+		if _, err := NormalizeSignal(in); err == nil {
+			t.Errorf("NormalizeSignal(%q) should fail", in)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chenyang-zz/boxify/internal/termprofile"
 	"github.com/creack/pty"
 )
 
@@ -79,6 +80,14 @@ func (v *Validator) ValidateBasicConfig(config TerminalConfig) *ValidationResult
 		}
 	}
 
+	// 验证环境变量名
+	if err := termprofile.ValidateEnv(config.Env); err != nil {
+		return &ValidationResult{
+			Valid:   false,
+			Message: err.Error(),
+		}
+	}
+
 	// 验证工作路径
 	workPath := v.GetWorkPath(config)
 	if config.WorkPath != "" {
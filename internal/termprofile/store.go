@@ -0,0 +1,284 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package termprofile 管理终端配置方案：命名的 shell、启动参数、环境变量与工作目录组合，
+// 用于“项目 A 的终端”这类快捷启动；方案保存在用户配置目录下的 JSON 文件中。
+package termprofile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrProfileNotFound 终端配置方案不存在。
+var ErrProfileNotFound = errors.New("终端配置方案不存在")
+
+// Profile 是一个终端配置方案，未填写的字段在启动时使用终端默认值。
+type Profile struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Shell          string            `json:"shell,omitempty"`          // shell 类型，空或 auto 表示自动检测
+	Args           []string          `json:"args,omitempty"`           // 追加的 shell 启动参数
+	Env            map[string]string `json:"env,omitempty"`            // 追加的环境变量
+	WorkPath       string            `json:"workPath,omitempty"`       // 工作目录
+	InitialCommand string            `json:"initialCommand,omitempty"` // 初始命令
+	Default        bool              `json:"default"`                  // 未指定方案时使用的默认方案，最多一个
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+// Store 保存终端配置方案，可并发使用。
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	logger   *slog.Logger
+	profiles map[string]*Profile
+	now      func() time.Time
+}
+
+// DefaultPath 返回默认的方案文件路径。
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "terminal-profiles.json")
+	}
+	return filepath.Join(configDir, "Boxify", "terminal-profiles.json")
+}
+
+// NewStore 创建方案存储，path 为空时使用默认路径；需调用 Load 读取已保存的方案。
+func NewStore(path string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultPath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{
+		path:     path,
+		logger:   logger.With("module", "termprofile"),
+		profiles: make(map[string]*Profile),
+		now:      time.Now,
+	}
+}
+
+// Load 读取方案文件，文件不存在时视为空。
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取终端配置方案失败：%w", err)
+	}
+	var list []*Profile
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析终端配置方案失败：%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles = make(map[string]*Profile, len(list))
+	for _, p := range list {
+		if p != nil && p.ID != "" {
+			s.profiles[p.ID] = p
+		}
+	}
+	return nil
+}
+
+// List 返回全部方案，按名称排序。
+func (s *Store) List() []*Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		list = append(list, clone(p))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Get 按 ID 或名称查找方案，名称忽略大小写。
+func (s *Store) Get(idOrName string) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.profiles[idOrName]; ok {
+		return clone(p), nil
+	}
+	for _, p := range s.profiles {
+		if strings.EqualFold(p.Name, strings.TrimSpace(idOrName)) {
+			return clone(p), nil
+		}
+	}
+	return nil, ErrProfileNotFound
+}
+
+// Default 返回默认方案，没有时返回 nil。
+func (s *Store) Default() *Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.profiles {
+		if p.Default {
+			return clone(p)
+		}
+	}
+	return nil
+}
+
+// Save 创建或更新方案（ID 为空时创建）；设为默认时取消其他方案的默认标记。
+func (s *Store) Save(profile *Profile) (*Profile, error) {
+	if profile == nil {
+		return nil, errors.New("终端配置方案不能为空")
+	}
+	saved := clone(profile)
+	saved.Name = strings.TrimSpace(saved.Name)
+	if err := validate(saved); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.profiles {
+		if p.ID != saved.ID && strings.EqualFold(p.Name, saved.Name) {
+			return nil, fmt.Errorf("终端配置方案名称已存在: %s", saved.Name)
+		}
+	}
+
+	now := s.now()
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+	} else {
+		old, ok := s.profiles[saved.ID]
+		if !ok {
+			return nil, ErrProfileNotFound
+		}
+		saved.CreatedAt = old.CreatedAt
+	}
+	saved.UpdatedAt = now
+	if saved.Default {
+		for _, p := range s.profiles {
+			p.Default = false
+		}
+	}
+	s.profiles[saved.ID] = saved
+	if err := s.persistLocked(); err != nil {
+		return nil, err
+	}
+	return clone(saved), nil
+}
+
+// Delete 删除方案。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[id]; !ok {
+		return ErrProfileNotFound
+	}
+	delete(s.profiles, id)
+	return s.persistLocked()
+}
+
+// ValidateEnv 校验环境变量名：非空，且不含 = 与空字符。
+func ValidateEnv(env map[string]string) error {
+	for k, v := range env {
+		if strings.TrimSpace(k) == "" || strings.ContainsAny(k, "=\x00") {
+			return fmt.Errorf("环境变量名无效: %q", k)
+		}
+		if strings.ContainsRune(v, 0) {
+			return fmt.Errorf("环境变量 %s 的值不能包含空字符", k)
+		}
+	}
+	return nil
+}
+
+// EnvList 将环境变量转换为 KEY=VALUE 列表，按名称排序保证结果稳定。
+func EnvList(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]string, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, k+"="+env[k])
+	}
+	return list
+}
+
+// validate 校验方案字段。
+func validate(p *Profile) error {
+	if p.Name == "" {
+		return errors.New("终端配置方案名称不能为空")
+	}
+	for _, arg := range p.Args {
+		if strings.ContainsRune(arg, 0) {
+			return errors.New("启动参数不能包含空字符")
+		}
+	}
+	return ValidateEnv(p.Env)
+}
+
+// clone 返回不共享切片与映射的副本。
+func clone(p *Profile) *Profile {
+	c := *p
+	c.Args = append([]string(nil), p.Args...)
+	if p.Env != nil {
+		c.Env = make(map[string]string, len(p.Env))
+		for k, v := range p.Env {
+			c.Env[k] = v
+		}
+	}
+	return &c
+}
+
+// persistLocked 将方案写入文件，先写临时文件再替换，调用方需持有锁。
+func (s *Store) persistLocked() error {
+	list := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化终端配置方案失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入终端配置方案失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入终端配置方案失败：%w", err)
+	}
+	s.logger.Debug("终端配置方案已保存", "count", len(list))
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termprofile
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoreSaveLoadDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s := NewStore(path, nil)
+
+	a, err := s.Save(&Profile{Name: " Project A ", Shell: "bash", Args: []string{"--login"}, Env: map[string]string{"APP_ENV": "dev"}, WorkPath: "/srv/a", Default: true})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if a.ID == "" || a.Name != "Project A" || a.CreatedAt.IsZero() {
+		t.Fatalf("新建方案字段错误: %+v", a)
+	}
+	b, err := s.Save(&Profile{Name: "B", Default: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(&Profile{Name: "project a"}); err == nil {
+		t.Fatal("重名方案应返回错误")
+	}
+	if _, err := s.Save(&Profile{ID: "missing", Name: "C"}); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("更新不存在的方案应返回 ErrProfileNotFound, got %v", err)
+	}
+
+	reloaded := NewStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if d := reloaded.Default(); d == nil || d.ID != b.ID {
+		t.Fatalf("默认方案应只有最后设置的一个: %+v", d)
+	}
+	got, err := reloaded.Get("PROJECT A")
+	if err != nil || !reflect.DeepEqual(got.Env, a.Env) || !reflect.DeepEqual(got.Args, a.Args) || got.Default {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	got.Env["APP_ENV"] = "mutated"
+	if again, _ := reloaded.Get(a.ID); again.Env["APP_ENV"] != "dev" {
+		t.Fatal("Get 应返回副本")
+	}
+
+	if err := reloaded.Delete(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if list := reloaded.List(); len(list) != 1 || list[0].ID != b.ID {
+		t.Fatalf("List() = %+v", list)
+	}
+	if err := reloaded.Delete(a.ID); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("重复删除应返回 ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestValidateEnvAndEnvList(t *testing.T) {
+	if err := ValidateEnv(map[string]string{"A=B": "x"}); err == nil {
+		t.Fatal("含 = 的变量名应返回错误")
+	}
+	if err := ValidateEnv(map[string]string{" ": "x"}); err == nil {
+		t.Fatal("空变量名应返回错误")
+	}
+	if _, err := NewStore(filepath.Join(t.TempDir(), "p.json"), nil).Save(&Profile{Name: "x", Env: map[string]string{"K": "a\x00b"}}); err == nil {
+		t.Fatal("含空字符的值应返回错误")
+	}
+	if got := EnvList(map[string]string{"B": "2", "A": "1=1"}); !reflect.DeepEqual(got, []string{"A=1=1", "B=2"}) {
+		t.Fatalf("EnvList() = %v", got)
+	}
+}
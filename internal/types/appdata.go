@@ -0,0 +1,41 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/appdata"
+
+// AppDataExportFile 导出的应用数据归档信息。
+type AppDataExportFile struct {
+	Path            string `json:"path"`
+	Size            int64  `json:"size"`
+	Connections     int    `json:"connections"`
+	SavedQueries    int    `json:"savedQueries"`
+	IncludesSecrets bool   `json:"includesSecrets"`
+}
+
+// AppDataExportResult 导出应用数据结果。
+type AppDataExportResult struct {
+	BaseResult
+	Data *AppDataExportFile `json:"data,omitempty"`
+}
+
+// AppDataImportResult 导入应用数据结果。连接配置与查询由前端合并保存；
+// SettingsApplied 表示归档中的应用设置已覆盖当前设置。
+type AppDataImportResult struct {
+	BaseResult
+	Data            *appdata.Data `json:"data,omitempty"`
+	Path            string        `json:"path,omitempty"`
+	SettingsApplied bool          `json:"settingsApplied"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/appmenu"
+
+// AppMenuResult 应用菜单栏定义结果，Data 为当前生效的定义（含动态状态）。
+type AppMenuResult struct {
+	BaseResult
+	Data []appmenu.Item `json:"data"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/audit"
+
+// AuditLogResult 审计日志查询结果。
+type AuditLogResult struct {
+	BaseResult
+	Data *audit.Page `json:"data,omitempty"`
+}
+
+// AuditExportResult 审计日志导出结果。
+type AuditExportResult struct {
+	BaseResult
+	File  string `json:"file,omitempty"`  // 导出文件路径
+	Count int    `json:"count,omitempty"` // 导出条数
+}
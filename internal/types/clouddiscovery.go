@@ -0,0 +1,35 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/clouddiscovery"
+
+// CloudAccountResult 云账号结果（密钥字段已清空）
+type CloudAccountResult struct {
+	BaseResult
+	Data *clouddiscovery.Account `json:"data,omitempty"`
+}
+
+// CloudAccountListResult 云账号列表结果（密钥字段已清空）
+type CloudAccountListResult struct {
+	BaseResult
+	Data []*clouddiscovery.Account `json:"data,omitempty"`
+}
+
+// CloudInstanceListResult 云数据库实例发现结果
+type CloudInstanceListResult struct {
+	BaseResult
+	Data []*clouddiscovery.Instance `json:"data,omitempty"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/syncstate"
+
+// SyncStateResult 共享状态结果，键不存在时 Data 为空。
+type SyncStateResult struct {
+	BaseResult
+	Data *syncstate.Entry `json:"data,omitempty"`
+}
+
+// SyncStateUpdateResult 共享状态写入结果，包含冲突检测信息。
+type SyncStateUpdateResult struct {
+	BaseResult
+	Data *syncstate.Update `json:"data,omitempty"`
+}
+
+// SyncStateListResult 共享状态列表结果。
+type SyncStateListResult struct {
+	BaseResult
+	Data []syncstate.Entry `json:"data"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/drafts"
+
+// DraftResult 单个草稿结果。
+type DraftResult struct {
+	BaseResult
+	Data *drafts.Draft `json:"data,omitempty"`
+}
+
+// DraftRecoveryResult 启动时可恢复草稿结果。
+type DraftRecoveryResult struct {
+	BaseResult
+	Data *drafts.Recovery `json:"data,omitempty"`
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// EventSubscription 窗口对事件的订阅记录。
+type EventSubscription struct {
+	Window  string `json:"window"`  // 窗口名称
+	SinceMs int64  `json:"sinceMs"` // 订阅时间（Unix 毫秒）
+}
+
+// EventBusEventStat 单个事件的投递统计。
+type EventBusEventStat struct {
+	Event        string               `json:"event"`        // 事件名称
+	Tracked      bool                 `json:"tracked"`      // 是否曾被订阅（未跟踪的事件直接透传）
+	Subscribers  []*EventSubscription `json:"subscribers"`  // 当前订阅窗口
+	Pending      int                  `json:"pending"`      // 等待补发的缓冲条数
+	Emitted      int64                `json:"emitted"`      // 累计发送次数
+	Delivered    int64                `json:"delivered"`    // 累计投递次数（含补发）
+	Buffered     int64                `json:"buffered"`     // 累计缓冲次数
+	Replayed     int64                `json:"replayed"`     // 累计补发次数
+	Dropped      int64                `json:"dropped"`      // 累计丢弃次数（溢出/过期/未缓冲）
+	LastEmitAtMs int64                `json:"lastEmitAtMs"` // 最近一次发送时间（Unix 毫秒）
+}
+
+// EventBusDeadLetter 未能投递到订阅者的事件记录。
+type EventBusDeadLetter struct {
+	Event  string `json:"event"`  // 事件名称
+	Reason string `json:"reason"` // 原因：overflow、expired、no_buffer
+	AtMs   int64  `json:"atMs"`   // 发生时间（Unix 毫秒）
+}
+
+// EventBusStats 事件总线状态快照。
+type EventBusStats struct {
+	BufferEnabled       bool                  `json:"bufferEnabled"`       // 无订阅者时是否缓冲
+	MaxBufferedPerEvent int                   `json:"maxBufferedPerEvent"` // 每个事件最大缓冲条数
+	BufferTTLMs         int64                 `json:"bufferTtlMs"`         // 缓冲存活时间（毫秒）
+	Events              []*EventBusEventStat  `json:"events"`              // 各事件统计
+	DeadLetters         []*EventBusDeadLetter `json:"deadLetters"`         // 最近的死信记录
+}
+
+// EventBusStatsResult 事件总线状态查询结果。
+type EventBusStatsResult struct {
+	BaseResult
+	Data *EventBusStats `json:"data,omitempty"`
+}
+
+// EventSubscribeData 订阅结果。
+type EventSubscribeData struct {
+	Replayed int `json:"replayed"` // 订阅时补发的缓冲事件条数
+}
+
+// EventSubscribeResult 订阅事件结果。
+type EventSubscribeResult struct {
+	BaseResult
+	Data *EventSubscribeData `json:"data,omitempty"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/jobs"
+
+// JobResult 后台任务结果。
+type JobResult struct {
+	BaseResult
+	Data *jobs.Job `json:"data,omitempty"`
+}
+
+// JobListResult 后台任务列表结果。
+type JobListResult struct {
+	BaseResult
+	Data []jobs.Job `json:"data,omitempty"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/knownhosts"
+
+// KnownHostResult 受信任主机密钥结果
+type KnownHostResult struct {
+	BaseResult
+	Data *knownhosts.Entry `json:"data,omitempty"`
+}
+
+// KnownHostListResult 受信任主机密钥列表结果
+type KnownHostListResult struct {
+	BaseResult
+	Data []knownhosts.Entry `json:"data"`
+}
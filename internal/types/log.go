@@ -0,0 +1,44 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/logger"
+
+// LogConfig 当前日志配置。
+type LogConfig struct {
+	Level           string            `json:"level"`           // 全局日志级别
+	SubsystemLevels map[string]string `json:"subsystemLevels"` // 单独设置了级别的子系统
+	Subsystems      []string          `json:"subsystems"`      // 可单独设置级别的子系统
+	JSON            bool              `json:"json"`            // 是否使用 JSON 输出格式
+	FilePath        string            `json:"filePath"`        // 日志文件路径，未开启文件输出时为空
+}
+
+// LogConfigResult 日志配置结果。
+type LogConfigResult struct {
+	BaseResult
+	Data *LogConfig `json:"data,omitempty"`
+}
+
+// LogEntriesResult 最近日志结果，供日志查看面板使用。
+type LogEntriesResult struct {
+	BaseResult
+	Data []logger.Record `json:"data"`
+}
+
+// LogRotateResult 日志轮转结果。
+type LogRotateResult struct {
+	BaseResult
+	Data string `json:"data,omitempty"` // 轮转后的旧日志路径
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/recents"
+
+// RecentsResult 启动页最近使用汇总结果。
+type RecentsResult struct {
+	BaseResult
+	Data *recents.Overview `json:"data,omitempty"`
+}
+
+// RecentListResult 单类最近使用记录列表结果。
+type RecentListResult struct {
+	BaseResult
+	Data []recents.Item `json:"data"`
+}
+
+// RecentItemResult 单条最近使用记录结果。
+type RecentItemResult struct {
+	BaseResult
+	Data *recents.Item `json:"data,omitempty"`
+}
+
+// RecentsChangedEvent 最近使用记录变化事件，广播到全部窗口以刷新启动页。
+type RecentsChangedEvent struct {
+	Kind recents.Kind `json:"kind"` // 变化的记录类型，为空表示全部类型
+}
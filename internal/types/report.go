@@ -0,0 +1,32 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// ReportFile 生成的报表。
+type ReportFile struct {
+	Path           string `json:"path,omitempty"`    // 报表文件路径，预览时为空
+	Format         string `json:"format"`            // 报表格式：md 或 html
+	Content        string `json:"content,omitempty"` // 报表内容，仅预览时返回
+	Size           int64  `json:"size"`
+	Sections       int    `json:"sections"`             // 报表包含的查询数
+	FailedSections int    `json:"failedSections"`       // 执行失败的查询数
+	TransferID     string `json:"transferId,omitempty"` // 分块传输流 ID（预览内容过大时 Content 为空，内容通过 report-preview:chunk 分块下发）
+}
+
+// ReportResult 生成报表结果。
+type ReportResult struct {
+	BaseResult
+	Data *ReportFile `json:"data,omitempty"`
+}
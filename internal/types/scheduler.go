@@ -0,0 +1,35 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/scheduler"
+
+// ScheduledTaskResult 定时任务结果。
+type ScheduledTaskResult struct {
+	BaseResult
+	Data *scheduler.Task `json:"data,omitempty"`
+}
+
+// ScheduledTaskListResult 定时任务列表结果。
+type ScheduledTaskListResult struct {
+	BaseResult
+	Data []*scheduler.Task `json:"data,omitempty"`
+}
+
+// ScheduledRunListResult 定时任务执行记录结果。
+type ScheduledRunListResult struct {
+	BaseResult
+	Data []scheduler.Run `json:"data,omitempty"`
+}
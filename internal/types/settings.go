@@ -0,0 +1,33 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/chenyang-zz/boxify/internal/settings"
+	"github.com/chenyang-zz/boxify/internal/validate"
+)
+
+// SettingsResult 应用设置结果，校验失败时 ValidationErrors 携带字段级错误。
+type SettingsResult struct {
+	BaseResult
+	Data             *settings.Settings    `json:"data,omitempty"`
+	ValidationErrors []validate.FieldError `json:"validationErrors,omitempty"`
+}
+
+// SettingsChangedEvent 应用设置变更事件，广播到全部窗口。
+type SettingsChangedEvent struct {
+	Settings settings.Settings `json:"settings"` // 变更后的完整设置
+	Changed  []string          `json:"changed"`  // 发生变化的顶层字段：theme、logLevel、editor、query、confirm
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/chenyang-zz/boxify/internal/supportbundle"
+
+// SupportBundlePreviewResult 诊断包内容预览结果。
+type SupportBundlePreviewResult struct {
+	BaseResult
+	Data *supportbundle.Bundle `json:"data,omitempty"`
+}
+
+// SupportBundleFile 已生成的诊断包文件。
+type SupportBundleFile struct {
+	Path string `json:"path"` // 保存路径
+	Size int64  `json:"size"` // 文件大小（字节）
+}
+
+// SupportBundleFileResult 诊断包生成结果。
+type SupportBundleFileResult struct {
+	BaseResult
+	Data *SupportBundleFile `json:"data,omitempty"`
+}
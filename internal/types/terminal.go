@@ -14,6 +14,13 @@
 
 package types
 
+import (
+	"time"
+
+	"github.com/chenyang-zz/boxify/internal/cmdhistory"
+	"github.com/chenyang-zz/boxify/internal/termprofile"
+)
+
 // TerminalCreateResult 终端创建结果
 type TerminalCreateResult struct {
 	BaseResult
@@ -24,6 +31,14 @@ type TerminalCreateResult struct {
 type TerminalEnvironmentInfo struct {
 	WorkPath  string     `json:"workPath,omitempty"`  // 当前工作路径
 	PythonEnv *PythonEnv `json:"pythonEnv,omitempty"` // Python 环境信息
+
+	DBConsole *TerminalDBConsoleInfo `json:"dbConsole,omitempty"` // 数据库控制台信息，仅控制台会话返回
+}
+
+// TerminalDBConsoleInfo 数据库控制台会话信息
+type TerminalDBConsoleInfo struct {
+	Client  string `json:"client"`  // 使用的客户端：mysql、mariadb、psql 或 internal（内置控制台）
+	BlockID string `json:"blockId"` // 启动控制台命令所在的 block ID
 }
 
 // PythonEnv Python 环境信息
@@ -86,9 +101,97 @@ type TerminalExecutableCommand struct {
 	Path string `json:"path"` // 命令绝对路径
 }
 
+// TerminalScrollbackSearchResult 终端回滚搜索结果
+type TerminalScrollbackSearchResult struct {
+	BaseResult
+	Data *TerminalScrollbackSearchData `json:"data,omitempty"` // 搜索数据
+}
+
+// TerminalScrollbackSearchData 终端回滚搜索数据
+type TerminalScrollbackSearchData struct {
+	Matches   []TerminalScrollbackMatch `json:"matches"`   // 匹配列表，按输出顺序排列
+	Truncated bool                      `json:"truncated"` // 匹配数超过上限被截断
+}
+
+// TerminalScrollbackMatch 回滚缓冲区中的一处匹配
+// Offset 与 Line 从会话开始累计，缓冲区裁剪后依然稳定；Column 与 Length 以字符计
+type TerminalScrollbackMatch struct {
+	Offset int64  `json:"offset"` // 匹配起点在纯文本输出流中的字节偏移
+	Line   int64  `json:"line"`   // 匹配所在行号（从 0 开始）
+	Column int    `json:"column"` // 匹配在行内的起始列
+	Length int    `json:"length"` // 匹配长度
+	Text   string `json:"text"`   // 匹配所在整行文本
+}
+
+// TerminalBlockOutputResult 命令块输出结果
+type TerminalBlockOutputResult struct {
+	BaseResult
+	Data *TerminalBlockOutput `json:"data,omitempty"`
+}
+
+// TerminalBlockOutput 命令块保存的输出
+type TerminalBlockOutput struct {
+	BlockID   string     `json:"blockId"`            // 命令块 ID
+	Command   string     `json:"command,omitempty"`  // 命令文本
+	Output    string     `json:"output"`             // base64 编码的原始输出（含 ANSI 序列），用于重新渲染
+	Text      string     `json:"text"`               // 去除控制序列后的纯文本，用于复制与保存
+	Truncated bool       `json:"truncated"`          // 输出超过上限，只保留了末尾部分
+	ExitCode  *int       `json:"exitCode,omitempty"` // 退出码，命令未结束时为空
+	StartedAt time.Time  `json:"startedAt"`          // 开始时间
+	EndedAt   *time.Time `json:"endedAt,omitempty"`  // 结束时间，命令未结束时为空
+}
+
 // TerminalInteractionModeChangedEvent 终端交互模式切换事件。
 type TerminalInteractionModeChangedEvent struct {
 	SessionID     string `json:"sessionId"`     // 会话 ID
 	InInteractive bool   `json:"inInteractive"` // 是否处于交互模式
 	ChangedAtUnix int64  `json:"changedAtUnix"` // 事件时间（Unix 毫秒）
 }
+
+// 终端会话关闭原因
+const (
+	TerminalCloseReasonClosed = "closed" // 前端主动关闭
+	TerminalCloseReasonExit   = "exit"   // shell 进程自行退出
+	TerminalCloseReasonIdle   = "idle"   // 超过空闲时间被自动关闭
+)
+
+// TerminalClosedEvent 终端会话关闭事件。
+type TerminalClosedEvent struct {
+	SessionID        string `json:"sessionId"`          // 会话 ID
+	Reason           string `json:"reason"`             // 关闭原因：closed、exit、idle
+	ExitCode         *int   `json:"exitCode,omitempty"` // shell 退出码，被信号终止或未知时为空
+	OrphanedChildren bool   `json:"orphanedChildren"`   // shell 退出后是否仍有子进程存活
+	ClosedAtUnix     int64  `json:"closedAtUnix"`       // 事件时间（Unix 毫秒）
+}
+
+// TerminalIdleWarningEvent 终端会话即将因空闲被关闭的警告事件。
+type TerminalIdleWarningEvent struct {
+	SessionID      string `json:"sessionId"`      // 会话 ID
+	IdleSeconds    int64  `json:"idleSeconds"`    // 已空闲秒数
+	CloseInSeconds int64  `json:"closeInSeconds"` // 距离自动关闭的秒数
+}
+
+// TerminalOutputTruncatedEvent 终端输出超过速率上限、部分输出未发送给前端的事件。
+type TerminalOutputTruncatedEvent struct {
+	SessionID    string `json:"sessionId"`    // 会话 ID
+	BlockID      string `json:"blockId"`      // 命令块 ID
+	DroppedBytes int64  `json:"droppedBytes"` // 未发送的字节数
+}
+
+// TerminalProfileResult 终端配置方案结果
+type TerminalProfileResult struct {
+	BaseResult
+	Data *termprofile.Profile `json:"data,omitempty"`
+}
+
+// TerminalProfileListResult 终端配置方案列表结果
+type TerminalProfileListResult struct {
+	BaseResult
+	Data []*termprofile.Profile `json:"data,omitempty"`
+}
+
+// TerminalCommandHistoryResult 终端命令历史查询结果
+type TerminalCommandHistoryResult struct {
+	BaseResult
+	Data []cmdhistory.Item `json:"data,omitempty"` // 按命令去重后的历史条目
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// TransferStreamStat 分块传输流的运行状态。
+type TransferStreamStat struct {
+	StreamID    string `json:"streamId"`    // 传输流 ID
+	Event       string `json:"event"`       // 分块事件名称
+	SentChunks  int64  `json:"sentChunks"`  // 已发送分块数
+	AckedChunks int64  `json:"ackedChunks"` // 已确认分块数（累计确认）
+	InFlight    int64  `json:"inFlight"`    // 在途未确认分块数
+	SentBytes   int64  `json:"sentBytes"`   // 已发送原始字节数
+	FlowControl bool   `json:"flowControl"` // 消费端是否已启用确认流控
+	Degraded    bool   `json:"degraded"`    // 是否因确认超时降级为无流控
+	CreatedAtMs int64  `json:"createdAtMs"` // 创建时间（Unix 毫秒）
+	LastAckAtMs int64  `json:"lastAckAtMs"` // 最近一次确认时间（Unix 毫秒）
+	WaitCount   int64  `json:"waitCount"`   // 因窗口已满而等待的次数
+	WaitTotalMs int64  `json:"waitTotalMs"` // 累计等待时长（毫秒）
+}
+
+// TransferEndEvent 分块传输结束事件。
+type TransferEndEvent struct {
+	StreamID    string `json:"streamId"`    // 传输流 ID
+	Event       string `json:"event"`       // 分块事件名称
+	TotalChunks int64  `json:"totalChunks"` // 总分块数
+	TotalBytes  int64  `json:"totalBytes"`  // 总原始字节数
+	Cancelled   bool   `json:"cancelled"`   // 是否被取消
+}
+
+// TransferStatsResult 分块传输状态查询结果。
+type TransferStatsResult struct {
+	BaseResult
+	Data []*TransferStreamStat `json:"data,omitempty"` // 活跃传输流列表
+}
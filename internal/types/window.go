@@ -0,0 +1,48 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// 窗口上下文类型。
+const (
+	WindowContextTable    = "table"    // 连接中打开的数据表
+	WindowContextTerminal = "terminal" // 终端会话
+)
+
+// WindowContext 可在窗口间移交的标签页上下文，同一时刻只属于一个窗口。
+type WindowContext struct {
+	ID           string                 `json:"id"`
+	Kind         string                 `json:"kind"` // table、terminal
+	Title        string                 `json:"title,omitempty"`
+	Owner        string                 `json:"owner"`                  // 持有上下文的窗口名称
+	ConnectionID string                 `json:"connectionId,omitempty"` // table：前端保存的连接 ID
+	Database     string                 `json:"database,omitempty"`
+	Schema       string                 `json:"schema,omitempty"`
+	Table        string                 `json:"table,omitempty"`
+	SessionID    string                 `json:"sessionId,omitempty"` // terminal：终端会话 ID
+	Data         map[string]interface{} `json:"data,omitempty"`      // 前端自定义的标签页状态（筛选、排序、滚动位置等）
+	Version      uint64                 `json:"version"`             // 共享状态版本，移交时作为 baseVersion 传回
+}
+
+// WindowContextResult 窗口上下文结果。
+type WindowContextResult struct {
+	BaseResult
+	Data *WindowContext `json:"data,omitempty"`
+}
+
+// WindowContextListResult 窗口上下文列表结果。
+type WindowContextListResult struct {
+	BaseResult
+	Data []*WindowContext `json:"data"`
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ToInt64 将查询结果中的整数、浮点数或数字文本转换为 int64，浮点数截断小数部分，无法转换时返回 0。
+func ToInt64(v interface{}) int64 {
+	switch x := v.(type) {
+	case int:
+		return int64(x)
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case int64:
+		return x
+	case uint:
+		return int64(x)
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		return int64(x)
+	case float32:
+		return int64(x)
+	case float64:
+		return int64(x)
+	case []byte:
+		return parseInt64(string(x))
+	case string:
+		return parseInt64(x)
+	default:
+		return 0
+	}
+}
+
+// ToFloat64 将查询结果中的整数、浮点数或数字文本转换为 float64，无法转换时返回 0。
+func ToFloat64(v interface{}) float64 {
+	switch x := v.(type) {
+	case float32:
+		return float64(x)
+	case float64:
+		return x
+	case []byte:
+		return parseFloat64(string(x))
+	case string:
+		return parseFloat64(x)
+	default:
+		return float64(ToInt64(v))
+	}
+}
+
+// parseInt64 解析整数文本，带小数的文本（如 DECIMAL 列）按浮点数解析后截断。
+func parseInt64(s string) int64 {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	return int64(parseFloat64(s))
+}
+
+// parseFloat64 解析数字文本，无法解析时返回 0。
+func parseFloat64(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "testing"
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want int64
+	}{
+		{int32(7), 7},
+		{uint64(42), 42},
+		{float64(3.9), 3},
+		{"123", 123},
+		{[]byte(" 456 "), 456},
+		{"12.5", 12},
+		{"abc", 0},
+		{nil, 0},
+		{true, 0},
+	}
+	for _, tt := range tests {
+		if got := ToInt64(tt.in); got != tt.want {
+			t.Errorf("ToInt64(%#v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want float64
+	}{
+		{float32(1.5), 1.5},
+		{int64(3), 3},
+		{uint8(4), 4},
+		{"2.25", 2.25},
+		{[]byte("1e3"), 1000},
+		{"abc", 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := ToFloat64(tt.in); got != tt.want {
+			t.Errorf("ToFloat64(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+// MaxIdentifierLen 标识符最大长度（允许 schema.table 形式，单段上限由数据库自身校验）。
+const MaxIdentifierLen = 129
+
+// 校验错误码。
+const (
+	CodeRequired      = "required"       // 必填项为空
+	CodeInvalid       = "invalid"        // 格式不合法
+	CodeTooLong       = "too_long"       // 超出长度限制
+	CodeOutOfRange    = "out_of_range"   // 数值超出范围
+	CodeNotAllowed    = "not_allowed"    // 不在允许取值范围内
+	CodePathTraversal = "path_traversal" // 路径包含越级访问
+)
+
+// FieldError 单个字段的校验错误。
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名（与前端参数名一致）
+	Code    string `json:"code"`    // 错误码
+	Message string `json:"message"` // 中文错误描述
+}
+
+// Error 参数校验错误，包含一个或多个字段错误。
+type Error struct {
+	Fields []FieldError
+}
+
+// Error 实现 error 接口，合并所有字段错误描述。
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Message)
+	}
+	return "参数校验失败：" + strings.Join(msgs, "；")
+}
+
+// AsError 判断错误是否为参数校验错误。
+func AsError(err error) (*Error, bool) {
+	var ve *Error
+	if errors.As(err, &ve) {
+		return ve, true
+	}
+	return nil, false
+}
+
+// Validator 链式参数校验器，收集所有字段错误后统一返回。
+type Validator struct {
+	errs []FieldError
+}
+
+// New 创建参数校验器。
+func New() *Validator {
+	return &Validator{}
+}
+
+// Check 在条件不成立时记录字段错误。
+func (v *Validator) Check(ok bool, field, code, message string) *Validator {
+	if !ok {
+		v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+	}
+	return v
+}
+
+// Required 校验字符串非空。
+func (v *Validator) Required(field, value string) *Validator {
+	return v.Check(strings.TrimSpace(value) != "", field, CodeRequired, fmt.Sprintf("%s 不能为空", field))
+}
+
+// Identifier 校验必填标识符（库名、表名、列名）：非空、长度受限且不含控制字符。
+func (v *Validator) Identifier(field, value string) *Validator {
+	if strings.TrimSpace(value) == "" {
+		return v.Check(false, field, CodeRequired, fmt.Sprintf("%s 不能为空", field))
+	}
+	return v.OptionalIdentifier(field, value)
+}
+
+// OptionalIdentifier 校验可选标识符，为空时跳过。
+func (v *Validator) OptionalIdentifier(field, value string) *Validator {
+	if value == "" {
+		return v
+	}
+	if utf8.RuneCountInString(value) > MaxIdentifierLen {
+		return v.Check(false, field, CodeTooLong, fmt.Sprintf("%s 长度不能超过 %d 个字符", field, MaxIdentifierLen))
+	}
+	if !utf8.ValidString(value) || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return v.Check(false, field, CodeInvalid, fmt.Sprintf("%s 包含非法字符", field))
+	}
+	return v
+}
+
+// Identifiers 校验标识符列表中的每一项。
+func (v *Validator) Identifiers(field string, values []string) *Validator {
+	for i, value := range values {
+		v.Identifier(fmt.Sprintf("%s[%d]", field, i), value)
+	}
+	return v
+}
+
+// Range 校验整数取值范围（闭区间）。
+func (v *Validator) Range(field string, value, min, max int) *Validator {
+	return v.Check(value >= min && value <= max, field, CodeOutOfRange, fmt.Sprintf("%s 取值需在 %d 到 %d 之间", field, min, max))
+}
+
+// PageSize 校验分页大小：1 到 max。
+func (v *Validator) PageSize(field string, value, max int) *Validator {
+	return v.Range(field, value, 1, max)
+}
+
+// OneOf 校验取值在允许列表内（忽略大小写）。
+func (v *Validator) OneOf(field, value string, allowed ...string) *Validator {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	for _, a := range allowed {
+		if normalized == strings.ToLower(a) {
+			return v
+		}
+	}
+	return v.Check(false, field, CodeNotAllowed, fmt.Sprintf("%s 仅支持: %s", field, strings.Join(allowed, ", ")))
+}
+
+// SafePath 校验文件路径：非空、不含控制字符且不包含 ".." 越级片段。
+func (v *Validator) SafePath(field, path string) *Validator {
+	if strings.TrimSpace(path) == "" {
+		return v.Check(false, field, CodeRequired, fmt.Sprintf("%s 不能为空", field))
+	}
+	if strings.IndexFunc(path, unicode.IsControl) >= 0 {
+		return v.Check(false, field, CodeInvalid, fmt.Sprintf("%s 包含非法字符", field))
+	}
+	return v.Check(!hasTraversal(path), field, CodePathTraversal, fmt.Sprintf("%s 不能包含 '..' 路径片段", field))
+}
+
+// PathWithin 校验相对路径拼接到 baseDir 后仍位于 baseDir 之内。
+func (v *Validator) PathWithin(field, baseDir, rel string) *Validator {
+	if strings.TrimSpace(rel) == "" {
+		return v.Check(false, field, CodeRequired, fmt.Sprintf("%s 不能为空", field))
+	}
+	base := filepath.Clean(baseDir)
+	target := filepath.Clean(filepath.Join(base, rel))
+	inside := target == base || strings.HasPrefix(target, base+string(filepath.Separator))
+	return v.Check(inside && !filepath.IsAbs(rel), field, CodePathTraversal, fmt.Sprintf("%s 超出允许的目录范围", field))
+}
+
+// Route 校验只读查询路由，空值表示按主机策略。
+func (v *Validator) Route(field, route string) *Validator {
+	if route == "" {
+		return v
+	}
+	return v.OneOf(field, strings.ToLower(route), connection.RoutePrimary, connection.RouteReplica)
+}
+
+// ConnectionConfig 校验连接配置的基础字段。
+func (v *Validator) ConnectionConfig(field string, config *connection.ConnectionConfig) *Validator {
+	if config == nil {
+		return v.Check(false, field, CodeRequired, fmt.Sprintf("%s 不能为空", field))
+	}
+	switch config.Type {
+	case connection.ConnectionTypeSQLite:
+	case connection.ConnectionTypeCustom:
+		v.Required(field+".driver", config.Driver)
+	default:
+		v.Required(field+".host", config.Host)
+		v.Range(field+".port", config.Port, 0, 65535)
+	}
+	v.Check(config.Timeout >= 0, field+".timeout", CodeOutOfRange, fmt.Sprintf("%s.timeout 不能为负数", field))
+	for i, h := range config.Hosts {
+		name := fmt.Sprintf("%s.hosts[%d]", field, i)
+		v.Required(name+".host", h.Host)
+		v.Range(name+".port", h.Port, 0, 65535)
+	}
+	if config.HostPolicy != "" {
+		v.OneOf(field+".hostPolicy", strings.ToLower(config.HostPolicy), connection.HostPolicyFirstAvailable, connection.HostPolicyRoundRobin)
+	}
+	v.Route(field+".readRoute", config.ReadRoute)
+	if config.TLS != nil && strings.TrimSpace(config.TLS.Mode) != "" {
+		v.OneOf(field+".tls.mode", strings.ToLower(config.TLS.Mode), connection.TLSModeDisable, connection.TLSModePreferred,
+			connection.TLSModeRequired, connection.TLSModeVerifyCA, connection.TLSModeVerifyFull)
+	}
+	if config.Auth != nil {
+		v.OneOf(field+".auth.method", config.AuthMethod(), connection.AuthMethodPassword,
+			connection.AuthMethodKerberos, connection.AuthMethodLDAP, connection.AuthMethodPAM)
+	}
+	if config.UseSSH {
+		if config.SSH == nil {
+			return v.Check(false, field+".ssh", CodeRequired, fmt.Sprintf("%s.ssh 不能为空", field))
+		}
+		v.Required(field+".ssh.host", config.SSH.Host)
+		v.Range(field+".ssh.port", config.SSH.Port, 0, 65535)
+		v.Proxy(field+".ssh.proxy", config.SSH.Proxy)
+	} else {
+		v.Proxy(field+".proxy", config.Proxy)
+	}
+	return v
+}
+
+// Proxy 校验代理配置，nil 或类型为空表示不使用代理。
+func (v *Validator) Proxy(field string, proxy *connection.ProxyConfig) *Validator {
+	if proxy == nil || strings.TrimSpace(proxy.Type) == "" {
+		return v
+	}
+	v.OneOf(field+".type", proxy.Type, connection.ProxyTypeSOCKS5, connection.ProxyTypeHTTP)
+	v.Required(field+".host", proxy.Host)
+	return v.Range(field+".port", proxy.Port, 1, 65535)
+}
+
+// Err 返回收集到的校验错误，无错误时返回 nil。
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &Error{Fields: append([]FieldError(nil), v.errs...)}
+}
+
+// hasTraversal 判断路径是否包含 ".." 片段（同时兼容 / 与 \ 分隔符）。
+func hasTraversal(path string) bool {
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenyang-zz/boxify/internal/connection"
+)
+
+func TestValidatorCollectsFieldErrors(t *testing.T) {
+	err := New().
+		Identifier("tableName", "").
+		Identifier("dbName", "bad\x00name").
+		OptionalIdentifier("schema", strings.Repeat("a", MaxIdentifierLen+1)).
+		PageSize("pageSize", 0, 1000).
+		OneOf("format", "exe", "csv", "json").
+		Err()
+
+	ve, ok := AsError(err)
+	if !ok {
+		t.Fatalf("期望校验错误，得到 %v", err)
+	}
+	want := []string{CodeRequired, CodeInvalid, CodeTooLong, CodeOutOfRange, CodeNotAllowed}
+	if len(ve.Fields) != len(want) {
+		t.Fatalf("期望 %d 个字段错误，得到 %d: %+v", len(want), len(ve.Fields), ve.Fields)
+	}
+	for i, code := range want {
+		if ve.Fields[i].Code != code {
+			t.Errorf("字段 %s 期望错误码 %s，得到 %s", ve.Fields[i].Field, code, ve.Fields[i].Code)
+		}
+	}
+}
+
+func TestValidatorPasses(t *testing.T) {
+	err := New().
+		Identifier("tableName", "public.users").
+		OptionalIdentifier("dbName", "").
+		PageSize("pageSize", 100, 1000).
+		OneOf("format", "XLSX", "csv", "xlsx").
+		SafePath("path", "/tmp/export/users.csv").
+		Err()
+	if err != nil {
+		t.Fatalf("期望校验通过，得到 %v", err)
+	}
+}
+
+func TestSafePath(t *testing.T) {
+	cases := map[string]bool{
+		"/home/user/a.csv":     true,
+		"~/Documents":          true,
+		"/home/../etc/passwd":  false,
+		`C:\data\..\windows`:   false,
+		"":                     false,
+		"/tmp/file..name.json": true,
+	}
+	for path, ok := range cases {
+		err := New().SafePath("path", path).Err()
+		if (err == nil) != ok {
+			t.Errorf("路径 %q 期望通过=%v，得到错误 %v", path, ok, err)
+		}
+	}
+}
+
+func TestPathWithin(t *testing.T) {
+	if err := New().PathWithin("file", "/data", "sub/a.txt").Err(); err != nil {
+		t.Errorf("期望通过，得到 %v", err)
+	}
+	for _, rel := range []string{"../a.txt", "sub/../../a.txt", "/etc/passwd"} {
+		if err := New().PathWithin("file", "/data", rel).Err(); err == nil {
+			t.Errorf("路径 %q 应被拒绝", rel)
+		}
+	}
+}
+
+func TestConnectionConfig(t *testing.T) {
+	err := New().ConnectionConfig("config", nil).Err()
+	if err == nil {
+		t.Fatal("空配置应校验失败")
+	}
+
+	cfg := &connection.ConnectionConfig{Type: connection.ConnectionTypeMySQL, Host: "127.0.0.1", Port: 70000}
+	ve, ok := AsError(New().ConnectionConfig("config", cfg).Err())
+	if !ok || len(ve.Fields) != 1 || ve.Fields[0].Field != "config.port" {
+		t.Fatalf("期望端口校验失败，得到 %+v", ve)
+	}
+
+	cfg = &connection.ConnectionConfig{Type: connection.ConnectionTypeSQLite}
+	if err := New().ConnectionConfig("config", cfg).Err(); err != nil {
+		t.Errorf("SQLite 配置不需要主机，得到 %v", err)
+	}
+}
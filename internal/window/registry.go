@@ -38,15 +38,40 @@ type WindowRegistry struct {
 	mu      sync.RWMutex
 	app     *application.App
 	logger  *slog.Logger
+
+	states  *StateStore            // 按窗口名称保存的位置、大小与所在屏幕
+	pending map[string]WindowState // 应用启动前创建、待屏幕信息就绪后恢复位置的窗口
+	started bool                   // 应用已启动，屏幕信息可用
+
+	onClosed []func(name string) // 分离窗口关闭后的回调
 }
 
-// NewWindowRegistry 创建窗口注册表
+// NewWindowRegistry 创建窗口注册表，应用启动后恢复启动前创建的窗口位置，退出时保存全部窗口状态
 func NewWindowRegistry(app *application.App, logger *slog.Logger) *WindowRegistry {
-	return &WindowRegistry{
+	wr := &WindowRegistry{
 		windows: make(map[string]*WindowEntry),
 		app:     app,
 		logger:  logger,
+		states:  NewStateStore("", logger),
+		pending: make(map[string]WindowState),
 	}
+	app.Event.OnApplicationEvent(events.Common.ApplicationStarted, func(*application.ApplicationEvent) {
+		wr.restorePending()
+	})
+	app.OnShutdown(wr.saveAllStates)
+	return wr
+}
+
+// OnDetachedClosed 注册分离窗口关闭后的回调，参数为窗口名称
+func (wr *WindowRegistry) OnDetachedClosed(fn func(name string)) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.onClosed = append(wr.onClosed, fn)
+}
+
+// States 获取窗口状态存储
+func (wr *WindowRegistry) States() *StateStore {
+	return wr.states
 }
 
 // Register 注册窗口并设置生命周期钩子
@@ -139,6 +164,8 @@ func (wr *WindowRegistry) setupLifecycleHooks(entry *WindowEntry) {
 			slog.String("name", config.Window.Name),
 			slog.String("type", config.Type))
 
+		wr.saveState(entry)
+
 		switch ParseWindowType(config.Type) {
 		case WindowTypeMain, WindowTypeSingleton:
 			// 主窗口和单例窗口：隐藏而非关闭
@@ -157,6 +184,17 @@ func (wr *WindowRegistry) setupLifecycleHooks(entry *WindowEntry) {
 			// 注销窗口
 			wr.Unregister(config.Window.Name)
 			// 不调用 Cancel()，允许关闭
+
+		case WindowTypeDetached:
+			// 分离窗口：注销并允许关闭，由回调收回窗口持有的上下文
+			wr.Unregister(config.Window.Name)
+			wr.emitWindowEvent("window:closed", entry.Config)
+			wr.mu.RLock()
+			callbacks := append([]func(string){}, wr.onClosed...)
+			wr.mu.RUnlock()
+			for _, fn := range callbacks {
+				fn(config.Window.Name)
+			}
 		}
 
 	})
@@ -180,16 +218,105 @@ func (wr *WindowRegistry) createWindow(config *config.PageConfig) *application.W
 	opts.URL = config.Window.URL
 	opts.BackgroundType = application.BackgroundTypeTranslucent
 
+	restored := wr.applySavedState(config, &opts)
+
 	window := wr.app.Window.NewWithOptions(opts)
 
 	// 居中
-	if config.Center {
+	if config.Center && !restored {
 		window.Center()
 	}
 
 	return window
 }
 
+// applySavedState 按保存的状态设置窗口选项，返回是否已恢复位置。
+// 应用启动前屏幕信息不可用，只恢复大小与最大化，位置在启动后按当前屏幕校正再恢复。调用方需持有锁。
+func (wr *WindowRegistry) applySavedState(config *config.PageConfig, opts *application.WebviewWindowOptions) bool {
+	if !persistState(config.Type) {
+		return false
+	}
+	state, ok := wr.states.Get(config.Window.Name)
+	if !ok {
+		return false
+	}
+	if state.Maximized {
+		opts.StartState = application.WindowStateMaximised
+	}
+	if !wr.started {
+		opts.Width, opts.Height = state.Width, state.Height
+		wr.pending[config.Window.Name] = state
+		return false
+	}
+	state = fitToScreens(state, wr.app.Screen.GetAll())
+	opts.Width, opts.Height = state.Width, state.Height
+	opts.InitialPosition = application.WindowXY
+	opts.X, opts.Y = state.X, state.Y
+	return true
+}
+
+// restorePending 应用启动后按当前屏幕恢复启动前创建的窗口位置
+func (wr *WindowRegistry) restorePending() {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.started = true
+	screens := wr.app.Screen.GetAll()
+	for name, state := range wr.pending {
+		entry, ok := wr.windows[name]
+		if !ok {
+			continue
+		}
+		fitted := fitToScreens(state, screens)
+		if fitted.Maximized {
+			// 先还原到普通大小的位置，保证最大化发生在原屏幕上
+			entry.Window.UnMaximise()
+		}
+		entry.Window.SetBounds(application.Rect{X: fitted.X, Y: fitted.Y, Width: fitted.Width, Height: fitted.Height})
+		if fitted.Maximized {
+			entry.Window.Maximise()
+		}
+		if fitted.ScreenID != state.ScreenID {
+			wr.logger.Info("保存的屏幕已断开，窗口移至主屏幕", "name", name, "screen", state.ScreenName)
+		}
+	}
+	wr.pending = make(map[string]WindowState)
+}
+
+// persistState 判断窗口类型是否按名称保存状态：模态与分离窗口名称不固定，不保存
+func persistState(windowType string) bool {
+	t := ParseWindowType(windowType)
+	return t != WindowTypeModal && t != WindowTypeDetached
+}
+
+// saveState 保存窗口当前状态，模态与分离窗口不保存
+func (wr *WindowRegistry) saveState(entry *WindowEntry) {
+	if !persistState(entry.Config.Type) || !entry.Window.IsVisible() {
+		return
+	}
+	name := entry.Config.Window.Name
+	prev, hasPrev := wr.states.Get(name)
+	state, ok := captureState(entry.Window, prev, hasPrev)
+	if !ok {
+		return
+	}
+	if err := wr.states.Put(name, state); err != nil {
+		wr.logger.Warn("保存窗口状态失败", "name", name, "error", err)
+	}
+}
+
+// saveAllStates 保存全部可见窗口的状态
+func (wr *WindowRegistry) saveAllStates() {
+	wr.mu.RLock()
+	entries := make([]*WindowEntry, 0, len(wr.windows))
+	for _, entry := range wr.windows {
+		entries = append(entries, entry)
+	}
+	wr.mu.RUnlock()
+	for _, entry := range entries {
+		wr.saveState(entry)
+	}
+}
+
 // GetAllWindowNames 获取所有窗口名称
 func (wr *WindowRegistry) GetAllWindowNames() map[string]bool {
 	wr.mu.RLock()
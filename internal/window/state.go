@@ -0,0 +1,228 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// minVisibleSize 窗口标题栏在屏幕内至少可见的宽高，低于该值视为不在屏幕上。
+const minVisibleSize = 64
+
+// WindowState 窗口位置、大小与所在屏幕。最大化时 X/Y/Width/Height 保存最大化前的位置与大小。
+type WindowState struct {
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Maximized  bool   `json:"maximized,omitempty"`
+	ScreenID   string `json:"screenId,omitempty"`   // 所在屏幕 ID
+	ScreenName string `json:"screenName,omitempty"` // 所在屏幕名称，屏幕 ID 在重新插拔后可能变化，作为备选匹配
+}
+
+// StateStore 按窗口名称保存窗口状态，可并发使用。
+type StateStore struct {
+	mu     sync.Mutex
+	path   string
+	logger *slog.Logger
+	states map[string]WindowState
+}
+
+// DefaultStatePath 返回默认的窗口状态文件路径。
+func DefaultStatePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "window-state.json")
+	}
+	return filepath.Join(configDir, "Boxify", "window-state.json")
+}
+
+// NewStateStore 创建窗口状态存储，path 为空时使用默认路径。
+func NewStateStore(path string, logger *slog.Logger) *StateStore {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultStatePath()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StateStore{path: path, logger: logger, states: make(map[string]WindowState)}
+}
+
+// Load 读取窗口状态文件，文件不存在时为空。
+func (s *StateStore) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取窗口状态失败：%w", err)
+	}
+	var states map[string]WindowState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return fmt.Errorf("解析窗口状态失败：%w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = make(map[string]WindowState, len(states))
+	for name, state := range states {
+		if state.Width > 0 && state.Height > 0 {
+			s.states[name] = state
+		}
+	}
+	return nil
+}
+
+// Get 返回窗口的保存状态。
+func (s *StateStore) Get(name string) (WindowState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	return state, ok
+}
+
+// Put 更新窗口状态并写入文件。
+func (s *StateStore) Put(name string, state WindowState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[name] = state
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化窗口状态失败：%w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建配置目录失败：%w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入窗口状态失败：%w", err)
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("写入窗口状态失败：%w", err)
+	}
+	return nil
+}
+
+// captureState 读取窗口当前状态；最大化时保留 prev 中最大化前的位置与大小，最小化或全屏时返回 false。
+func captureState(window *application.WebviewWindow, prev WindowState, hasPrev bool) (WindowState, bool) {
+	if window.IsMinimised() || window.IsFullscreen() {
+		return WindowState{}, false
+	}
+	state := WindowState{Maximized: window.IsMaximised()}
+	if state.Maximized && hasPrev {
+		state.X, state.Y, state.Width, state.Height = prev.X, prev.Y, prev.Width, prev.Height
+	} else {
+		bounds := window.Bounds()
+		state.X, state.Y, state.Width, state.Height = bounds.X, bounds.Y, bounds.Width, bounds.Height
+	}
+	if state.Width <= 0 || state.Height <= 0 {
+		return WindowState{}, false
+	}
+	if screen, err := window.GetScreen(); err == nil && screen != nil {
+		state.ScreenID, state.ScreenName = screen.ID, screen.Name
+	}
+	return state, true
+}
+
+// fitToScreens 按当前连接的屏幕校正保存的窗口状态：
+// 原屏幕仍在且窗口可见时原样恢复；原屏幕仍在但窗口超出时移回该屏幕；
+// 原屏幕已断开或无法匹配时居中到主屏幕。大小不超过目标屏幕的工作区。screens 为空时原样返回。
+func fitToScreens(state WindowState, screens []*application.Screen) WindowState {
+	if len(screens) == 0 {
+		return state
+	}
+	target := findScreen(state, screens)
+	if target != nil && visibleOn(state, target.WorkArea) {
+		return state
+	}
+
+	relocate := target == nil
+	if target == nil {
+		target = primaryScreen(screens)
+	}
+	area := target.WorkArea
+	if area.Width <= 0 || area.Height <= 0 {
+		area = target.Bounds
+	}
+	state.Width = min(state.Width, area.Width)
+	state.Height = min(state.Height, area.Height)
+	if relocate {
+		state.X = area.X + (area.Width-state.Width)/2
+		state.Y = area.Y + (area.Height-state.Height)/2
+	} else {
+		state.X = clamp(state.X, area.X, area.X+area.Width-state.Width)
+		state.Y = clamp(state.Y, area.Y, area.Y+area.Height-state.Height)
+	}
+	state.ScreenID, state.ScreenName = target.ID, target.Name
+	return state
+}
+
+// findScreen 按 ID、名称依次匹配保存时所在的屏幕；未记录屏幕时按窗口位置匹配。
+func findScreen(state WindowState, screens []*application.Screen) *application.Screen {
+	if state.ScreenID != "" || state.ScreenName != "" {
+		for _, s := range screens {
+			if state.ScreenID != "" && s.ID == state.ScreenID {
+				return s
+			}
+		}
+		for _, s := range screens {
+			if state.ScreenName != "" && s.Name == state.ScreenName {
+				return s
+			}
+		}
+		return nil
+	}
+	for _, s := range screens {
+		if visibleOn(state, s.Bounds) {
+			return s
+		}
+	}
+	return nil
+}
+
+// primaryScreen 返回主屏幕，未标记时返回第一个屏幕。
+func primaryScreen(screens []*application.Screen) *application.Screen {
+	for _, s := range screens {
+		if s.IsPrimary {
+			return s
+		}
+	}
+	return screens[0]
+}
+
+// visibleOn 判断窗口能否被拖动：顶部标题栏位于区域内，且水平方向有足够的重叠。
+func visibleOn(state WindowState, area application.Rect) bool {
+	w := min(state.X+state.Width, area.X+area.Width) - max(state.X, area.X)
+	titleVisible := state.Y >= area.Y && state.Y <= area.Y+area.Height-minVisibleSize
+	return titleVisible && w >= min(minVisibleSize, state.Width)
+}
+
+// clamp 将 v 限制在 [lo, hi]，hi < lo 时返回 lo。
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func testScreens() []*application.Screen {
+	return []*application.Screen{
+		{ID: "1", Name: "Built-in", IsPrimary: true,
+			Bounds: application.Rect{Width: 1440, Height: 900}, WorkArea: application.Rect{Y: 25, Width: 1440, Height: 875}},
+		{ID: "2", Name: "DELL U2720Q",
+			Bounds: application.Rect{X: 1440, Width: 2560, Height: 1440}, WorkArea: application.Rect{X: 1440, Width: 2560, Height: 1440}},
+	}
+}
+
+func TestFitToScreens(t *testing.T) {
+	tests := []struct {
+		name  string
+		state WindowState
+		want  WindowState
+	}{
+		{
+			name:  "原屏幕可见时原样恢复",
+			state: WindowState{X: 1600, Y: 100, Width: 1200, Height: 800, ScreenID: "2", ScreenName: "DELL U2720Q"},
+			want:  WindowState{X: 1600, Y: 100, Width: 1200, Height: 800, ScreenID: "2", ScreenName: "DELL U2720Q"},
+		},
+		{
+			name:  "屏幕 ID 变化时按名称匹配",
+			state: WindowState{X: 1600, Y: 100, Width: 1200, Height: 800, ScreenID: "7", ScreenName: "DELL U2720Q"},
+			want:  WindowState{X: 1600, Y: 100, Width: 1200, Height: 800, ScreenID: "7", ScreenName: "DELL U2720Q"},
+		},
+		{
+			name:  "超出原屏幕时移回屏幕内",
+			state: WindowState{X: 3900, Y: -300, Width: 1200, Height: 800, ScreenID: "2", ScreenName: "DELL U2720Q"},
+			want:  WindowState{X: 2800, Y: 0, Width: 1200, Height: 800, ScreenID: "2", ScreenName: "DELL U2720Q"},
+		},
+		{
+			name:  "原屏幕断开时居中到主屏幕并缩小到工作区",
+			state: WindowState{X: 5000, Y: 100, Width: 2000, Height: 1000, Maximized: true, ScreenID: "3", ScreenName: "LG"},
+			want:  WindowState{X: 0, Y: 25, Width: 1440, Height: 875, Maximized: true, ScreenID: "1", ScreenName: "Built-in"},
+		},
+		{
+			name:  "未记录屏幕且不在任何屏幕上",
+			state: WindowState{X: -3000, Y: 0, Width: 800, Height: 600},
+			want:  WindowState{X: 320, Y: 162, Width: 800, Height: 600, ScreenID: "1", ScreenName: "Built-in"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fitToScreens(tt.state, testScreens()); got != tt.want {
+				t.Fatalf("fitToScreens() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	state := WindowState{X: 5000, Y: 5000, Width: 800, Height: 600}
+	if got := fitToScreens(state, nil); got != state {
+		t.Fatalf("没有屏幕信息时应原样返回: %+v", got)
+	}
+}
+
+func TestStateStorePersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window-state.json")
+	s := NewStateStore(path, nil)
+	if err := s.Load(); err != nil {
+		t.Fatalf("文件不存在时 Load() 不应报错: %v", err)
+	}
+	want := WindowState{X: 10, Y: 20, Width: 1200, Height: 800, Maximized: true, ScreenID: "2", ScreenName: "DELL"}
+	if err := s.Put("main", want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := s.Put("broken", WindowState{}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewStateStore(path, nil)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, ok := reloaded.Get("main"); !ok || got != want {
+		t.Fatalf("Get() = %+v, %v", got, ok)
+	}
+	if _, ok := reloaded.Get("broken"); ok {
+		t.Fatal("大小无效的状态应被忽略")
+	}
+}
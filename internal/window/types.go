@@ -26,6 +26,9 @@ const (
 
 	// WindowTypeModal 模态窗口 - 阻塞父窗口的对话框
 	WindowTypeModal
+
+	// WindowTypeDetached 分离窗口 - 以页面配置为模板可多开的窗口，如从主窗口拖出的标签页，关闭即销毁
+	WindowTypeDetached
 )
 
 // ParseWindowType 解析窗口类型字符串
@@ -37,6 +40,8 @@ func ParseWindowType(typeStr string) WindowType {
 		return WindowTypeSingleton
 	case "modal":
 		return WindowTypeModal
+	case "detached":
+		return WindowTypeDetached
 	default:
 		return WindowTypeSingleton
 	}
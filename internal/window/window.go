@@ -16,11 +16,11 @@ package window
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io/fs"
 	"log/slog"
-	"os"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/chenyang-zz/boxify/internal/auth"
@@ -41,9 +41,17 @@ type AppManager struct {
 
 func InitApplication(assets fs.FS) *AppManager {
 
+	// 初始化全局 logger，应用日志同样经由它输出，便于保留最近日志用于诊断
+	logger.Init(logger.Level())
+	defaultLogger := logger.GetDefaultLogger()
+	if err := logger.EnableFile(""); err != nil {
+		defaultLogger.Warn("开启日志文件输出失败", "error", err)
+	}
+
 	// 创建临时应用以获取环境信息
 	app := application.New(application.Options{
 		Name:     "Boxify",
+		Logger:   defaultLogger,
 		LogLevel: slog.LevelInfo,
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
@@ -57,14 +65,10 @@ func InitApplication(assets fs.FS) *AppManager {
 	}
 	ctx := context.WithValue(context.Background(), "buildType", buildType)
 
-	// 初始化全局 logger
-	logger.Init(slog.LevelInfo)
-	defaultLogger := logger.GetDefaultLogger()
-
 	am := &AppManager{
 		app:       app,
 		ctx:       ctx,
-		logger:    defaultLogger,
+		logger:    logger.For(logger.SubsystemWindow),
 		authStore: auth.NewAuthStateStore("", defaultLogger),
 	}
 
@@ -81,12 +85,12 @@ func InitApplication(assets fs.FS) *AppManager {
 	}
 	am.pageConfig = pageConfig
 
+	// 加载保存的窗口状态，需在创建窗口前完成
+	am.LoadLayout()
+
 	// 根据登录状态创建启动窗口
 	am.CreateStartupWindowFromConfig()
 
-	// 加载保存的布局
-	am.LoadLayout()
-
 	return am
 }
 
@@ -138,6 +142,41 @@ func (am *AppManager) OpenPage(pageId string) error {
 	return nil
 }
 
+// OpenDetachedPage 以页面配置为模板打开名为 name 的分离窗口（可多开，关闭即销毁），query 追加到页面 URL
+func (am *AppManager) OpenDetachedPage(pageId, name, title string, query url.Values) error {
+	pageConfig := am.pageConfig.GetPageConfig(pageId)
+	if pageConfig == nil || pageConfig.Window == nil {
+		return fmt.Errorf("页面不存在: %s", pageId)
+	}
+	if name == "" || am.registry.Get(name) != nil {
+		return fmt.Errorf("分离窗口名称无效或已存在: %s", name)
+	}
+
+	detached := *pageConfig
+	opts := *pageConfig.Window
+	detached.Window = &opts
+	detached.Type = "detached"
+	detached.IsMain = false
+	detached.Parent = ""
+	opts.Name = name
+	if title != "" {
+		detached.Title = title
+		opts.Title = title
+	}
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(opts.URL, "?") {
+			sep = "&"
+		}
+		opts.URL += sep + query.Encode()
+	}
+
+	if am.registry.Register(&detached) == nil {
+		return fmt.Errorf("创建分离窗口失败: %s", pageId)
+	}
+	return nil
+}
+
 // ClosePage 关闭页面
 func (am *AppManager) ClosePage(pageId string) error {
 	pageConfig := am.pageConfig.GetPageConfig(pageId)
@@ -191,52 +230,19 @@ func generateModalName() string {
 	return fmt.Sprintf("modal-%d", time.Now().UnixNano())
 }
 
-// SaveLayout 保存窗口布局
-func (am *AppManager) SaveLayout() {
-	layout := make(map[string]WindowState)
-
-	// 使用注册表的方法
-	for name := range am.registry.GetAllWindowNames() {
-		if window := am.registry.Get(name); window != nil {
-			x, y := window.Position()
-			width, height := window.Size()
-
-			layout[name] = WindowState{
-				X:      x,
-				Y:      y,
-				Width:  width,
-				Height: height,
-			}
-		}
-	}
+// NewDetachedWindowName 生成分离窗口唯一名称
+func NewDetachedWindowName() string {
+	return fmt.Sprintf("detached-%d", time.Now().UnixNano())
+}
 
-	data, _ := json.Marshal(layout)
-	os.WriteFile("layout.json", data, 0644)
+// SaveLayout 保存全部可见窗口的位置、大小、最大化状态与所在屏幕
+func (am *AppManager) SaveLayout() {
+	am.registry.saveAllStates()
 }
 
-// LoadLayout 加载窗口布局
+// LoadLayout 加载保存的窗口状态，之后创建的窗口按名称恢复；需在创建窗口前调用
 func (am *AppManager) LoadLayout() {
-	data, err := os.ReadFile("layout.json")
-	if err != nil {
-		return
+	if err := am.registry.States().Load(); err != nil {
+		am.logger.Warn("加载窗口状态失败，使用页面配置中的大小", "error", err)
 	}
-
-	var layout map[string]WindowState
-	if err := json.Unmarshal(data, &layout); err != nil {
-		return
-	}
-
-	for name, state := range layout {
-		if window := am.registry.Get(name); window != nil {
-			window.SetPosition(state.X, state.Y)
-			window.SetSize(state.Width, state.Height)
-		}
-	}
-}
-
-type WindowState struct {
-	X      int `json:"x"`
-	Y      int `json:"y"`
-	Width  int `json:"width"`
-	Height int `json:"height"`
 }
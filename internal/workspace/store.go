@@ -0,0 +1,394 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workspace 保存命名的工作区：使用的连接、打开的库表、编辑器标签页（含内容）、
+// 终端会话信息与界面布局，重新打开应用时据此恢复现场。每个工作区单独保存为一个 JSON 文件。
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTabContentBytes 单个编辑器标签页保存的内容上限。
+	MaxTabContentBytes = 2 << 20
+	// MaxLayoutBytes 界面布局数据的大小上限。
+	MaxLayoutBytes = 256 << 10
+)
+
+// ErrWorkspaceNotFound 工作区不存在。
+var ErrWorkspaceNotFound = errors.New("工作区不存在")
+
+// DatabaseRef 是一个打开的数据库（或 PostgreSQL 模式）。
+type DatabaseRef struct {
+	ConnectionID string `json:"connectionId"`     // 前端保存的连接 ID
+	Database     string `json:"database"`         // 数据库名
+	Schema       string `json:"schema,omitempty"` // 模式名，仅支持模式的数据库使用
+}
+
+// TableRef 是一个打开的数据表。
+type TableRef struct {
+	DatabaseRef
+	Table string `json:"table"`
+}
+
+// EditorTab 是一个编辑器标签页。
+type EditorTab struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Kind         string `json:"kind,omitempty"`         // 标签页类型，如 sql、table、ddl，由前端定义
+	ConnectionID string `json:"connectionId,omitempty"` // 关联的连接 ID
+	Database     string `json:"database,omitempty"`     // 关联的数据库
+	Content      string `json:"content,omitempty"`      // 编辑器内容
+	FilePath     string `json:"filePath,omitempty"`     // 关联的本地文件
+	Pinned       bool   `json:"pinned,omitempty"`
+}
+
+// TerminalSession 是终端会话的元数据，恢复时按这些参数重新创建终端（不保存输出）。
+type TerminalSession struct {
+	Title        string `json:"title,omitempty"`
+	Shell        string `json:"shell,omitempty"`        // shell 类型
+	Profile      string `json:"profile,omitempty"`      // 终端配置方案 ID 或名称
+	WorkPath     string `json:"workPath,omitempty"`     // 工作目录
+	ConnectionID string `json:"connectionId,omitempty"` // 数据库控制台会话关联的连接 ID
+}
+
+// Workspace 是一个命名的工作区。
+type Workspace struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Connections   []string          `json:"connections,omitempty"`   // 使用的连接 ID（连接配置由前端保存，此处不含密码）
+	OpenDatabases []DatabaseRef     `json:"openDatabases,omitempty"` // 展开的数据库
+	OpenTables    []TableRef        `json:"openTables,omitempty"`    // 打开的数据表
+	EditorTabs    []EditorTab       `json:"editorTabs,omitempty"`    // 编辑器标签页，按显示顺序
+	ActiveTabID   string            `json:"activeTabId,omitempty"`   // 当前激活的标签页
+	Terminals     []TerminalSession `json:"terminals,omitempty"`     // 终端会话
+	Layout        json.RawMessage   `json:"layout,omitempty"`        // 界面布局（面板尺寸等），由前端定义格式
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	OpenedAt      time.Time         `json:"openedAt"` // 最近一次加载时间，从未加载时为零值
+}
+
+// Summary 是工作区列表中的摘要信息，不含标签页内容与布局。
+type Summary struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Connections int       `json:"connections"`
+	EditorTabs  int       `json:"editorTabs"`
+	Terminals   int       `json:"terminals"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	OpenedAt    time.Time `json:"openedAt"`
+}
+
+// Store 以目录保存工作区，每个工作区一个文件，可并发使用。
+type Store struct {
+	mu     sync.Mutex
+	dir    string
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// DefaultDir 返回默认的工作区目录。
+func DefaultDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil || configDir == "" {
+		return filepath.Join(".", "workspaces")
+	}
+	return filepath.Join(configDir, "Boxify", "workspaces")
+}
+
+// NewStore 创建工作区存储，dir 为空时使用默认目录。
+func NewStore(dir string, logger *slog.Logger) *Store {
+	if strings.TrimSpace(dir) == "" {
+		dir = DefaultDir()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Store{dir: dir, logger: logger.With("module", "workspace"), now: time.Now}
+}
+
+// List 返回全部工作区摘要，最近打开或更新的在前；跳过无法解析的文件。
+func (s *Store) List() ([]Summary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Summary, 0, len(all))
+	for _, ws := range all {
+		list = append(list, summarize(ws))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := lastTouched(list[i].OpenedAt, list[i].UpdatedAt), lastTouched(list[j].OpenedAt, list[j].UpdatedAt)
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Get 按 ID 或名称（忽略大小写）读取工作区。
+func (s *Store) Get(idOrName string) (*Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findLocked(idOrName)
+}
+
+// Open 读取工作区并记录打开时间，供 Last 在下次启动时恢复。
+func (s *Store) Open(idOrName string) (*Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ws, err := s.findLocked(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	ws.OpenedAt = s.now()
+	if err := s.writeLocked(ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// Last 返回最近打开的工作区，没有时返回 nil。
+func (s *Store) Last() (*Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	var last *Workspace
+	for _, ws := range all {
+		if ws.OpenedAt.IsZero() {
+			continue
+		}
+		if last == nil || ws.OpenedAt.After(last.OpenedAt) {
+			last = ws
+		}
+	}
+	return last, nil
+}
+
+// Save 创建或更新工作区（ID 为空时创建），名称不能与其他工作区重复。
+func (s *Store) Save(ws *Workspace) (*Workspace, error) {
+	if ws == nil {
+		return nil, errors.New("工作区不能为空")
+	}
+	saved := *ws
+	saved.Name = strings.TrimSpace(saved.Name)
+	if err := validate(&saved); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	var old *Workspace
+	for _, w := range all {
+		if w.ID == saved.ID {
+			old = w
+		} else if strings.EqualFold(w.Name, saved.Name) {
+			return nil, fmt.Errorf("工作区名称已存在: %s", saved.Name)
+		}
+	}
+
+	now := s.now()
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+	} else {
+		if old == nil {
+			return nil, ErrWorkspaceNotFound
+		}
+		saved.CreatedAt = old.CreatedAt
+		if saved.OpenedAt.IsZero() {
+			saved.OpenedAt = old.OpenedAt
+		}
+	}
+	saved.UpdatedAt = now
+	if err := s.writeLocked(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// Delete 删除工作区。
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, err := s.pathFor(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrWorkspaceNotFound
+		}
+		return fmt.Errorf("删除工作区失败：%w", err)
+	}
+	return nil
+}
+
+// findLocked 按 ID 或名称查找工作区；调用方需持有锁。
+func (s *Store) findLocked(idOrName string) (*Workspace, error) {
+	if path, err := s.pathFor(idOrName); err == nil {
+		ws, err := readFile(path)
+		if err == nil {
+			return ws, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	for _, ws := range all {
+		if strings.EqualFold(ws.Name, strings.TrimSpace(idOrName)) {
+			return ws, nil
+		}
+	}
+	return nil, ErrWorkspaceNotFound
+}
+
+// readAllLocked 读取目录下全部工作区；调用方需持有锁。
+func (s *Store) readAllLocked() ([]*Workspace, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取工作区目录失败：%w", err)
+	}
+	var list []*Workspace
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		ws, err := readFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			s.logger.Warn("跳过无法读取的工作区文件", "file", entry.Name(), "error", err)
+			continue
+		}
+		list = append(list, ws)
+	}
+	return list, nil
+}
+
+// writeLocked 写入工作区文件，先写临时文件再替换；调用方需持有锁。
+func (s *Store) writeLocked(ws *Workspace) error {
+	path, err := s.pathFor(ws.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化工作区失败：%w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("创建工作区目录失败：%w", err)
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("写入工作区失败：%w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("写入工作区失败：%w", err)
+	}
+	s.logger.Debug("工作区已保存", "id", ws.ID, "name", ws.Name)
+	return nil
+}
+
+// pathFor 返回工作区文件路径；ID 只能是 uuid，避免路径穿越。
+func (s *Store) pathFor(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrWorkspaceNotFound
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// readFile 读取并解析单个工作区文件。
+func readFile(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ws Workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("解析工作区失败：%w", err)
+	}
+	if ws.ID == "" {
+		return nil, errors.New("工作区文件缺少 ID")
+	}
+	return &ws, nil
+}
+
+// validate 校验工作区字段。
+func validate(ws *Workspace) error {
+	if ws.Name == "" {
+		return errors.New("工作区名称不能为空")
+	}
+	for _, tab := range ws.EditorTabs {
+		if len(tab.Content) > MaxTabContentBytes {
+			return fmt.Errorf("标签页 %q 的内容超过 %d 字节上限", tab.Title, MaxTabContentBytes)
+		}
+	}
+	if len(ws.Layout) > MaxLayoutBytes {
+		return fmt.Errorf("界面布局数据超过 %d 字节上限", MaxLayoutBytes)
+	}
+	if len(ws.Layout) > 0 && !json.Valid(ws.Layout) {
+		return errors.New("界面布局数据不是有效的 JSON")
+	}
+	return nil
+}
+
+// summarize 生成工作区摘要。
+func summarize(ws *Workspace) Summary {
+	return Summary{
+		ID:          ws.ID,
+		Name:        ws.Name,
+		Connections: len(ws.Connections),
+		EditorTabs:  len(ws.EditorTabs),
+		Terminals:   len(ws.Terminals),
+		CreatedAt:   ws.CreatedAt,
+		UpdatedAt:   ws.UpdatedAt,
+		OpenedAt:    ws.OpenedAt,
+	}
+}
+
+// lastTouched 返回打开时间与更新时间中较晚的一个。
+func lastTouched(opened, updated time.Time) time.Time {
+	if opened.After(updated) {
+		return opened
+	}
+	return updated
+}
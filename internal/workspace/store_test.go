@@ -0,0 +1,134 @@
+// Copyright 2026 chenyang
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreSaveOpenList(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir, nil)
+	clock := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+
+	a, err := s.Save(&Workspace{
+		Name:        " Project A ",
+		Connections: []string{"conn-1"},
+		OpenTables:  []TableRef{{DatabaseRef: DatabaseRef{ConnectionID: "conn-1", Database: "shop"}, Table: "orders"}},
+		EditorTabs:  []EditorTab{{ID: "t1", Title: "query.sql", Kind: "sql", Content: "select 1"}},
+		ActiveTabID: "t1",
+		Terminals:   []TerminalSession{{Shell: "bash", WorkPath: "/srv/a"}},
+		Layout:      json.RawMessage(`{"sidebar":240}`),
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if a.ID == "" || a.Name != "Project A" || a.CreatedAt.IsZero() {
+		t.Fatalf("新建工作区字段错误: %+v", a)
+	}
+	b, err := s.Save(&Workspace{Name: "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(&Workspace{Name: "project a"}); err == nil {
+		t.Fatal("重名工作区应返回错误")
+	}
+
+	if last, err := s.Last(); err != nil || last != nil {
+		t.Fatalf("未打开过工作区时 Last() = %+v, %v", last, err)
+	}
+	opened, err := NewStore(dir, nil).Open("PROJECT A")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	var layout bytes.Buffer
+	if err := json.Compact(&layout, opened.Layout); err != nil {
+		t.Fatal(err)
+	}
+	if opened.EditorTabs[0].Content != "select 1" || opened.OpenTables[0].Table != "orders" || layout.String() != `{"sidebar":240}` {
+		t.Fatalf("工作区内容未完整保存: %+v", opened)
+	}
+	if last, _ := s.Last(); last == nil || last.ID != a.ID {
+		t.Fatalf("Last() 应返回最近打开的工作区: %+v", last)
+	}
+
+	// 更新时保留创建与打开时间
+	a.Name = "Project A2"
+	updated, err := s.Save(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(a.CreatedAt) || updated.OpenedAt.IsZero() {
+		t.Fatalf("更新应保留创建与打开时间: %+v", updated)
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].ID != a.ID || list[0].EditorTabs != 1 || list[1].ID != b.ID {
+		t.Fatalf("List() = %+v", list)
+	}
+
+	if err := s.Delete(b.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(b.ID); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("删除后 Get 应返回 ErrWorkspaceNotFound, got %v", err)
+	}
+	if err := s.Delete("../etc/passwd"); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("非法 ID 应返回 ErrWorkspaceNotFound, got %v", err)
+	}
+}
+
+func TestStoreValidate(t *testing.T) {
+	s := NewStore(t.TempDir(), nil)
+	if _, err := s.Save(&Workspace{Name: " "}); err == nil {
+		t.Fatal("空名称应返回错误")
+	}
+	big := strings.Repeat("x", MaxTabContentBytes+1)
+	if _, err := s.Save(&Workspace{Name: "big", EditorTabs: []EditorTab{{Title: "big.sql", Content: big}}}); err == nil {
+		t.Fatal("超出上限的标签页内容应返回错误")
+	}
+	if _, err := s.Save(&Workspace{Name: "layout", Layout: json.RawMessage(`{`)}); err == nil {
+		t.Fatal("无效的布局 JSON 应返回错误")
+	}
+	if _, err := s.Save(&Workspace{ID: "6f1c2a0e-0000-4000-8000-000000000000", Name: "missing"}); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("更新不存在的工作区应返回 ErrWorkspaceNotFound, got %v", err)
+	}
+}
+
+func TestStoreSkipsCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir, nil)
+	if _, err := s.Save(&Workspace{Name: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := s.List()
+	if err != nil || len(list) != 1 || list[0].Name != "ok" {
+		t.Fatalf("List() = %+v, %v", list, err)
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	clawchat "github.com/chenyang-zz/boxify/internal/claw/chat"
+	"github.com/chenyang-zz/boxify/internal/dbconsole"
 	"github.com/chenyang-zz/boxify/internal/events"
 	"github.com/chenyang-zz/boxify/internal/service"
 	boxtypes "github.com/chenyang-zz/boxify/internal/types"
@@ -23,6 +25,10 @@ func init() {
 	// 初始数据事件
 	application.RegisterEvent[service.InitialDataEntry]("initial-data:received")
 
+	// 分块传输事件
+	application.RegisterEvent[boxtypes.TransferEndEvent](string(events.EventTypeTransferEnd))
+	application.RegisterEvent[map[string]interface{}](string(events.EventTypeInitialDataChunk))
+
 	// 认证事件
 	application.RegisterEvent[service.AuthOAuthCompletedEvent]("auth:oauth-completed")
 
@@ -35,18 +41,32 @@ func init() {
 	application.RegisterEvent[map[string]interface{}]("terminal:command_end")
 	application.RegisterEvent[map[string]interface{}]("terminal:pwd_update")
 	application.RegisterEvent[boxtypes.TerminalInteractionModeChangedEvent](string(events.EventTypeTerminalInteractionModeChanged))
+	application.RegisterEvent[boxtypes.TerminalClosedEvent](string(events.EventTypeTerminalClosed))
+	application.RegisterEvent[boxtypes.TerminalIdleWarningEvent](string(events.EventTypeTerminalIdleWarning))
+	application.RegisterEvent[boxtypes.TerminalOutputTruncatedEvent](string(events.EventTypeTerminalOutputTruncated))
 
 	// git事件
 	application.RegisterEvent[boxtypes.GitStatusChangedEvent](string(events.EventTypeGitStatusChanged))
 
 	// claw事件
 	application.RegisterEvent[clawchat.ChatEvent](string(events.EventTypeClawChatEvent))
+
+	// 设置事件
+	application.RegisterEvent[boxtypes.SettingsChangedEvent](string(events.EventTypeSettingsChanged))
+
+	// 最近使用事件
+	application.RegisterEvent[boxtypes.RecentsChangedEvent](string(events.EventTypeRecentsChanged))
 }
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	// 终端数据库控制台以子命令方式复用本程序，不启动窗口
+	if len(os.Args) > 1 && os.Args[1] == dbconsole.Subcommand {
+		os.Exit(dbconsole.Main(os.Args[2:]))
+	}
+
 	// 创建应用（logger 在 InitApplication 内部初始化）
 	am := window.InitApplication(assets)
 
@@ -58,6 +78,10 @@ func main() {
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewTypeExportService(deps))
 		},
+		// 通知服务先于定时任务、导入导出等发送通知的服务启动、后于它们关闭，否则启动与关闭阶段的通知会因没有发送端被丢弃
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewNotificationService(deps))
+		},
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewDatabaseService(deps))
 		},
@@ -76,9 +100,36 @@ func main() {
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewMenuService(deps))
 		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewAppMenuService(deps))
+		},
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewTerminalService(deps))
 		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewTerminalProfileService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewWorkspaceService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewSettingsService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewRecentsService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewCloudDiscoveryService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewSSHHostKeyService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewDraftService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewAppDataService(deps))
+		},
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewFilesystemService(deps))
 		},
@@ -88,6 +139,36 @@ func main() {
 		func(app *application.App) application.Service {
 			return application.NewService(service.NewClawService(deps))
 		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewTransferService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewEventBusService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewSupportService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewLogService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewMetricsService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewDataTransferService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewJobService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewSchedulerService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewAuditService(deps))
+		},
+		func(app *application.App) application.Service {
+			return application.NewService(service.NewReportService(deps))
+		},
 	}
 
 	am.RegisterService(services...)